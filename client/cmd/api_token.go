@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var (
+	apiTokenName   string
+	apiTokenScopes []string
+)
+
+var apiTokenCmd = &cobra.Command{
+	Use:   "api-token",
+	Short: "Manage the tokens of the monitoring API",
+	Long: "Monitoring agents read the status and events from the monitoring API of the daemon, enabled with: netbird service install --monitoring-addr.\n" +
+		"Every request to the monitoring API has to present a token with the scope of the request. The scopes are:\n" +
+		"  status:read  the status, peers, routes and statistics\n" +
+		"  events:read  the system events\n" +
+		"Present a token with --api-token or NB_API_TOKEN, e.g.: netbird status --daemon-addr tcp://127.0.0.1:41731 --api-token <token>",
+}
+
+var apiTokenCreateCmd = &cobra.Command{
+	Use:     "create",
+	Short:   "Create a token, it is only shown once",
+	Example: "  netbird api-token create --name prometheus --scope status:read,events:read",
+	Args:    cobra.NoArgs,
+	RunE:    apiTokenCreate,
+}
+
+var apiTokenListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the tokens",
+	Example: "  netbird api-token list",
+	Args:    cobra.NoArgs,
+	RunE:    apiTokenList,
+}
+
+var apiTokenRevokeCmd = &cobra.Command{
+	Use:     "revoke id|name",
+	Short:   "Revoke a token",
+	Example: "  netbird api-token revoke prometheus",
+	Args:    cobra.ExactArgs(1),
+	RunE:    apiTokenRevoke,
+}
+
+func init() {
+	apiTokenCreateCmd.Flags().StringVar(&apiTokenName, "name", "", "Name of the token, e.g. the monitoring agent using it")
+	apiTokenCreateCmd.Flags().StringSliceVar(&apiTokenScopes, "scope", []string{"status:read"}, "Scopes of the token: status:read, events:read")
+}
+
+func apiTokenCreate(cmd *cobra.Command, _ []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.CreateAPIToken(cmd.Context(), &proto.CreateAPITokenRequest{
+		Name:   apiTokenName,
+		Scopes: apiTokenScopes,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create API token: %v", status.Convert(err).Message())
+	}
+
+	cmd.Printf("Created API token %s with the scopes %s. It isn't shown again:\n\n%s\n",
+		resp.GetToken().GetId(), strings.Join(resp.GetToken().GetScopes(), ", "), resp.GetSecret())
+	return nil
+}
+
+func apiTokenList(cmd *cobra.Command, _ []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.ListAPITokens(cmd.Context(), &proto.ListAPITokensRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to list API tokens: %v", status.Convert(err).Message())
+	}
+
+	if len(resp.GetTokens()) == 0 {
+		cmd.Println("No API tokens.")
+		return nil
+	}
+
+	cmd.Println("API tokens:")
+	for _, token := range resp.GetTokens() {
+		name := token.GetName()
+		if name == "" {
+			name = "-"
+		}
+		cmd.Printf("\n  - ID: %s\n    Name: %s\n    Scopes: %s\n    Created: %s\n",
+			token.GetId(), name, strings.Join(token.GetScopes(), ", "), token.GetCreatedAt().AsTime().Local().Format(time.RFC3339))
+	}
+	return nil
+}
+
+func apiTokenRevoke(cmd *cobra.Command, args []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.RevokeAPIToken(cmd.Context(), &proto.RevokeAPITokenRequest{Token: args[0]})
+	if err != nil {
+		return fmt.Errorf("failed to revoke API token: %v", status.Convert(err).Message())
+	}
+
+	cmd.Printf("Revoked API token %s\n", resp.GetToken().GetId())
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/netbirdio/netbird/client/internal"
 	"github.com/netbirdio/netbird/client/internal/debug"
+	"github.com/netbirdio/netbird/client/internal/logging"
 	"github.com/netbirdio/netbird/client/internal/peer"
 	"github.com/netbirdio/netbird/client/internal/profilemanager"
 	"github.com/netbirdio/netbird/client/proto"
@@ -28,6 +29,7 @@ var (
 	systemInfoFlag      bool
 	uploadBundleFlag    bool
 	uploadBundleURLFlag string
+	logSubsystemFlag    string
 )
 
 var debugCmd = &cobra.Command{
@@ -51,9 +53,10 @@ var logCmd = &cobra.Command{
 }
 
 var logLevelCmd = &cobra.Command{
-	Use:   "level <level>",
+	Use:   "level [level]",
 	Short: "Set the logging level for this session",
 	Long: `Sets the logging level for the current session. This setting is temporary and will revert to the default on daemon restart.
+Without a level the current levels are shown.
 Available log levels are:
   panic:   for panic level, highest level of severity
   fatal:   for fatal level errors that cause the program to exit
@@ -61,9 +64,13 @@ Available log levels are:
   warn:    for warning conditions
   info:    for informational messages
   debug:   for debug-level messages
-  trace:   for trace-level messages, which include more fine-grained information than debug`,
-	Args: cobra.ExactArgs(1),
-	RunE: setLogLevel,
+  trace:   for trace-level messages, which include more fine-grained information than debug
+
+With --subsystem the level applies to a single subsystem only: dns, routes, firewall, ice or relay.
+The level reset makes the subsystem follow the global level again.`,
+	Example: "  netbird debug log level debug\n  netbird debug log level trace --subsystem ice\n  netbird debug log level reset --subsystem ice",
+	Args:    cobra.RangeArgs(0, 1),
+	RunE:    setLogLevel,
 }
 
 var forCmd = &cobra.Command{
@@ -134,20 +141,50 @@ func setLogLevel(cmd *cobra.Command, args []string) error {
 	}()
 
 	client := proto.NewDaemonServiceClient(conn)
-	level := server.ParseLogLevel(args[0])
-	if level == proto.LogLevel_UNKNOWN {
-		//nolint
-		return fmt.Errorf("unknown log level: %s. Available levels are: panic, fatal, error, warn, info, debug, trace\n", args[0])
+	if len(args) == 0 {
+		return showLogLevels(cmd, client)
 	}
 
-	_, err = client.SetLogLevel(cmd.Context(), &proto.SetLogLevelRequest{
-		Level: level,
-	})
-	if err != nil {
+	req := &proto.SetLogLevelRequest{Subsystem: logSubsystemFlag}
+	if logSubsystemFlag != "" && strings.EqualFold(args[0], "reset") {
+		req.ResetSubsystem = true
+	} else {
+		req.Level = server.ParseLogLevel(args[0])
+		if req.Level == proto.LogLevel_UNKNOWN {
+			//nolint
+			return fmt.Errorf("unknown log level: %s. Available levels are: panic, fatal, error, warn, info, debug, trace\n", args[0])
+		}
+	}
+
+	if _, err = client.SetLogLevel(cmd.Context(), req); err != nil {
 		return fmt.Errorf("failed to set log level: %v", status.Convert(err).Message())
 	}
 
-	cmd.Println("Log level set successfully to", args[0])
+	switch {
+	case req.ResetSubsystem:
+		cmd.Printf("Log level of %s follows the global level\n", logSubsystemFlag)
+	case logSubsystemFlag != "":
+		cmd.Printf("Log level of %s set successfully to %s\n", logSubsystemFlag, args[0])
+	default:
+		cmd.Println("Log level set successfully to", args[0])
+	}
+	return nil
+}
+
+func showLogLevels(cmd *cobra.Command, client proto.DaemonServiceClient) error {
+	resp, err := client.GetLogLevel(cmd.Context(), &proto.GetLogLevelRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get log level: %v", status.Convert(err).Message())
+	}
+
+	cmd.Printf("Log level: %s\n", strings.ToLower(resp.GetLevel().String()))
+	for _, subsystem := range logging.Subsystems {
+		level, ok := resp.GetSubsystemLevels()[string(subsystem)]
+		if !ok {
+			continue
+		}
+		cmd.Printf("  %s: %s\n", subsystem, strings.ToLower(level.String()))
+	}
 	return nil
 }
 
@@ -304,7 +341,7 @@ func setSyncResponsePersistence(cmd *cobra.Command, args []string) error {
 
 func getStatusOutput(cmd *cobra.Command, anon bool) string {
 	var statusOutputString string
-	statusResp, err := getStatus(cmd.Context(), true)
+	statusResp, err := getStatus(cmd.Context(), true, nil)
 	if err != nil {
 		cmd.PrintErrf("Failed to get status: %v\n", err)
 	} else {
@@ -400,6 +437,8 @@ func init() {
 	debugBundleCmd.Flags().BoolVarP(&uploadBundleFlag, "upload-bundle", "U", false, "Uploads the debug bundle to a server")
 	debugBundleCmd.Flags().StringVar(&uploadBundleURLFlag, "upload-bundle-url", types.DefaultBundleURL, "Service URL to get an URL to upload the debug bundle")
 
+	logLevelCmd.Flags().StringVar(&logSubsystemFlag, "subsystem", "", "Sets the level of a single subsystem: dns, routes, firewall, ice or relay")
+
 	forCmd.Flags().Uint32VarP(&logFileCount, "log-file-count", "C", 1, "Number of rotated log files to include in debug bundle")
 	forCmd.Flags().BoolVarP(&systemInfoFlag, "system-info", "S", true, "Adds system information to the debug bundle")
 	forCmd.Flags().BoolVarP(&uploadBundleFlag, "upload-bundle", "U", false, "Uploads the debug bundle to a server")
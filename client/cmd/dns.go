@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var dnsCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Manage the NetBird DNS resolver",
+}
+
+var dnsCacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the cache of upstream DNS answers",
+	Long: "Upstream DNS answers are cached for the TTL of their records, NXDOMAIN and NODATA answers for the negative TTL of the zone.\n" +
+		"The cache is dropped whenever the DNS configuration changes.",
+}
+
+var dnsCacheStatsCmd = &cobra.Command{
+	Use:     "stats",
+	Short:   "Show the DNS cache counters",
+	Example: "  netbird dns cache stats",
+	Args:    cobra.NoArgs,
+	RunE:    dnsCacheStats,
+}
+
+var dnsCacheFlushCmd = &cobra.Command{
+	Use:     "flush",
+	Short:   "Drop the cached DNS answers",
+	Example: "  netbird dns cache flush",
+	Args:    cobra.NoArgs,
+	RunE:    dnsCacheFlush,
+}
+
+var dnsForwarderCmd = &cobra.Command{
+	Use:   "forwarder",
+	Short: "Inspect the DNS forwarder of the domain routes of this routing peer",
+	Long: "Routing peers forward the DNS queries for the domains they route. The answers are cached for the TTL handed out to the clients,\n" +
+		"NXDOMAIN and NODATA answers for up to 30 seconds. Expired answers are served when the upstream resolver fails.",
+}
+
+var dnsForwarderStatsCmd = &cobra.Command{
+	Use:     "stats",
+	Short:   "Show the DNS forwarder cache and upstream counters",
+	Example: "  netbird dns forwarder stats",
+	Args:    cobra.NoArgs,
+	RunE:    dnsForwarderStats,
+}
+
+func dnsCacheStats(cmd *cobra.Command, _ []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.GetDNSCacheStats(cmd.Context(), &proto.GetDNSCacheStatsRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get DNS cache stats: %v", status.Convert(err).Message())
+	}
+
+	cmd.Printf("Entries: %d\nHits:    %d\nMisses:  %d\n", resp.GetEntries(), resp.GetHits(), resp.GetMisses())
+	return nil
+}
+
+func dnsCacheFlush(cmd *cobra.Command, _ []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.FlushDNSCache(cmd.Context(), &proto.FlushDNSCacheRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to flush DNS cache: %v", status.Convert(err).Message())
+	}
+
+	cmd.Printf("Flushed %d cached DNS answers\n", resp.GetFlushed())
+	return nil
+}
+
+func dnsForwarderStats(cmd *cobra.Command, _ []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.GetDNSForwarderStats(cmd.Context(), &proto.GetDNSForwarderStatsRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get DNS forwarder stats: %v", status.Convert(err).Message())
+	}
+
+	var hitRatio float64
+	if total := resp.GetCacheHits() + resp.GetCacheMisses(); total > 0 {
+		hitRatio = float64(resp.GetCacheHits()) / float64(total) * 100
+	}
+
+	cmd.Printf("Cache entries:      %d\n", resp.GetCacheEntries())
+	cmd.Printf("Cache hits:         %d (%d negative)\n", resp.GetCacheHits(), resp.GetNegativeHits())
+	cmd.Printf("Cache misses:       %d\n", resp.GetCacheMisses())
+	cmd.Printf("Hit ratio:          %.1f%%\n", hitRatio)
+	cmd.Printf("Stale answers:      %d\n", resp.GetStaleAnswers())
+	cmd.Printf("Upstream queries:   %d (%d failed)\n", resp.GetUpstreamQueries(), resp.GetUpstreamFailures())
+	cmd.Printf("Upstream latency:   %s avg, %s max\n",
+		resp.GetUpstreamLatency().AsDuration().Round(time.Microsecond), resp.GetUpstreamLatencyMax().AsDuration().Round(time.Microsecond))
+	return nil
+}
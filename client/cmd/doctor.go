@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Detect common connectivity issues",
+	Long: "Checks the running client and the host for well-known failure patterns, like systemd-resolved conflicts, " +
+		"Docker dropping routed traffic, MTU blackholes, double NAT, an expired relay token or a broken nftables module, " +
+		"and prints the detected issues with steps to fix them.",
+	Example: "  netbird doctor",
+	Args:    cobra.NoArgs,
+	RunE:    doctor,
+}
+
+func doctor(cmd *cobra.Command, _ []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.Diagnose(cmd.Context(), &proto.DiagnoseRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to diagnose: %v", status.Convert(err).Message())
+	}
+
+	if len(resp.GetIssues()) == 0 {
+		cmd.Println("No issues detected.")
+		return nil
+	}
+
+	cmd.Printf("Detected %d issue(s):\n", len(resp.GetIssues()))
+	for _, issue := range resp.GetIssues() {
+		cmd.Printf("\n[%s] %s\n  %s\n", strings.ToUpper(issue.GetSeverity()), issue.GetTitle(), issue.GetDetails())
+		if len(issue.GetRemediation()) > 0 {
+			cmd.Println("  How to fix:")
+		}
+		for _, step := range issue.GetRemediation() {
+			cmd.Printf("    - %s\n", step)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var effectiveConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show the configuration the daemon runs with",
+	Long: "Show the resolved engine configuration and the feature flags of management, and whether each value is\n" +
+		"the default, set by the config or received from management.",
+	Example: "  netbird debug config",
+	Args:    cobra.NoArgs,
+	RunE:    effectiveConfig,
+}
+
+func init() {
+	debugCmd.AddCommand(effectiveConfigCmd)
+}
+
+func effectiveConfig(cmd *cobra.Command, _ []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.GetEffectiveConfig(cmd.Context(), &proto.GetEffectiveConfigRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get effective config: %v", status.Convert(err).Message())
+	}
+
+	cmd.Printf("Daemon version: %s\n\n", resp.GetVersion())
+	printEffectiveSettings(cmd, "Settings", resp.GetSettings())
+	cmd.Println()
+	printEffectiveSettings(cmd, "Features", resp.GetFeatures())
+	return nil
+}
+
+func printEffectiveSettings(cmd *cobra.Command, title string, settings []*proto.EffectiveSetting) {
+	cmd.Printf("%s:\n", title)
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	for _, setting := range settings {
+		_, _ = fmt.Fprintf(w, "  %s\t%s\t(%s)\n", setting.GetName(), setting.GetValue(), setting.GetSource())
+	}
+	_ = w.Flush()
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netbirdio/netbird/client/internal/daemonerr"
+)
+
+// The exit codes of the CLI per error reason, stable for automation. Errors without a known reason exit with 1.
+const (
+	ExitCodeError             = 1
+	ExitCodeDaemonUnreachable = 3
+	ExitCodePermissionDenied  = 4
+	ExitCodeAuthRequired      = 5
+	ExitCodeEngineStarting    = 6
+)
+
+const (
+	errorFormatText = "text"
+	errorFormatJSON = "json"
+)
+
+var exitCodes = map[daemonerr.Reason]int{
+	daemonerr.ReasonDaemonUnreachable: ExitCodeDaemonUnreachable,
+	daemonerr.ReasonPermissionDenied:  ExitCodePermissionDenied,
+	daemonerr.ReasonAuthRequired:      ExitCodeAuthRequired,
+	daemonerr.ReasonEngineStarting:    ExitCodeEngineStarting,
+}
+
+// ExitCode returns the exit code of the error
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if code, ok := exitCodes[daemonerr.ReasonOf(err)]; ok {
+		return code
+	}
+	return ExitCodeError
+}
+
+// errorOutput is the machine-readable form of an error
+type errorOutput struct {
+	Error    string `json:"error"`
+	Reason   string `json:"reason"`
+	ExitCode int    `json:"exitCode"`
+}
+
+// printError prints the error of the command to stderr, as JSON if requested with --error-format or --json
+func printError(cmd *cobra.Command, err error) {
+	if errorFormat != errorFormatJSON && !jsonFlag {
+		cmd.PrintErrln(cmd.ErrPrefix(), err.Error())
+		return
+	}
+
+	out, jsonErr := json.Marshal(errorOutput{
+		Error:    err.Error(),
+		Reason:   string(daemonerr.ReasonOf(err)),
+		ExitCode: ExitCode(err),
+	})
+	if jsonErr != nil {
+		cmd.PrintErrln(cmd.ErrPrefix(), err.Error())
+		return
+	}
+	cmd.PrintErrln(string(out))
+}
+
+// daemonDialError returns the error of a failed connection to the daemon with its reason. A daemon socket the user
+// may not access needs sudo, everything else means the daemon isn't reachable.
+func daemonDialError(addr string, err error) error {
+	if daemonSocketDenied(addr) {
+		return daemonerr.New(daemonerr.ReasonPermissionDenied, err)
+	}
+	return daemonerr.New(daemonerr.ReasonDaemonUnreachable, err)
+}
+
+func daemonSocketDenied(addr string) bool {
+	path, ok := strings.CutPrefix(addr, "unix://")
+	if !ok {
+		return false
+	}
+
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return errors.Is(err, os.ErrPermission)
+	}
+	_ = conn.Close()
+	return false
+}
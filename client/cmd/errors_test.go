@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"github.com/netbirdio/netbird/client/internal/daemonerr"
+)
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, 0, ExitCode(nil))
+	assert.Equal(t, ExitCodeError, ExitCode(errors.New("boom")))
+	assert.Equal(t, ExitCodeAuthRequired, ExitCode(fmt.Errorf("daemon up failed: %w",
+		daemonerr.Status(codes.FailedPrecondition, daemonerr.ReasonAuthRequired, "login"))))
+	assert.Equal(t, ExitCodeEngineStarting, ExitCode(daemonerr.Status(codes.DeadlineExceeded, daemonerr.ReasonEngineStarting, "starting")))
+	assert.Equal(t, ExitCodeDaemonUnreachable, ExitCode(daemonerr.New(daemonerr.ReasonDaemonUnreachable, errors.New("dial"))))
+}
+
+func TestDaemonDialError(t *testing.T) {
+	addr := "unix://" + filepath.Join(t.TempDir(), "missing.sock")
+	err := daemonDialError(addr, errors.New("context deadline exceeded"))
+	assert.Equal(t, daemonerr.ReasonDaemonUnreachable, daemonerr.ReasonOf(err))
+	assert.EqualError(t, err, "context deadline exceeded")
+}
+
+func TestPrintErrorJSON(t *testing.T) {
+	errorFormat = errorFormatJSON
+	t.Cleanup(func() { errorFormat = errorFormatText })
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetErr(&out)
+	printError(cmd, fmt.Errorf("up: %w", daemonerr.New(daemonerr.ReasonAuthRequired, errors.New("login required"))))
+
+	var printed errorOutput
+	require.NoError(t, json.Unmarshal(out.Bytes(), &printed))
+	assert.Equal(t, errorOutput{Error: "up: login required", Reason: "AUTH_REQUIRED", ExitCode: ExitCodeAuthRequired}, printed)
+}
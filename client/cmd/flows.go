@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var (
+	flowsPeer     string
+	flowsPort     uint16
+	flowsProtocol string
+	flowsSince    time.Duration
+	flowsLimit    uint32
+)
+
+var flowsCmd = &cobra.Command{
+	Use:   "flows",
+	Short: "Show the recent traffic flows",
+	Long: "Show the traffic flows the daemon recorded recently, newest first. The flows are recorded locally\n" +
+		"while the traffic events are enabled, regardless of the sampling and the filter of the flow collection.",
+	Example: `  netbird debug flows
+  netbird debug flows --peer peer-a.netbird.cloud --protocol tcp --port 22
+  netbird debug flows --since 5m --limit 20`,
+	Args: cobra.NoArgs,
+	RunE: showFlows,
+}
+
+func init() {
+	debugCmd.AddCommand(flowsCmd)
+
+	flowsCmd.Flags().StringVar(&flowsPeer, "peer", "", "Show the flows of a peer by FQDN, NetBird IP or public key, or of any IP address")
+	flowsCmd.Flags().Uint16Var(&flowsPort, "port", 0, "Show the flows from or to the port")
+	flowsCmd.Flags().StringVar(&flowsProtocol, "protocol", "", "Show the flows of the protocol (tcp, udp, icmp, sctp or a number)")
+	flowsCmd.Flags().DurationVar(&flowsSince, "since", 0, "Show the flows of the last duration, e.g. 10m")
+	flowsCmd.Flags().Uint32Var(&flowsLimit, "limit", 100, "Maximum number of flows to show, 0 shows all")
+}
+
+func showFlows(cmd *cobra.Command, _ []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	req := &proto.QueryFlowsRequest{
+		Peer:     flowsPeer,
+		Port:     uint32(flowsPort),
+		Protocol: flowsProtocol,
+		Limit:    flowsLimit,
+	}
+	if flowsSince > 0 {
+		req.Since = timestamppb.New(time.Now().Add(-flowsSince))
+	}
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.QueryFlows(cmd.Context(), req)
+	if err != nil {
+		return fmt.Errorf("failed to query flows: %v", status.Convert(err).Message())
+	}
+
+	if len(resp.GetFlows()) == 0 {
+		cmd.Println("No flows recorded.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TIME\tTYPE\tDIRECTION\tPROTOCOL\tSOURCE\tDESTINATION\tRX\tTX\tDNS")
+	for _, flow := range resp.GetFlows() {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%d\t%d\t%s\n",
+			flow.GetTimestamp().AsTime().Local().Format("15:04:05"),
+			flow.GetType(),
+			flow.GetDirection(),
+			flow.GetProtocol(),
+			flowEndpoint(flow.GetSourceIp(), flow.GetSourcePort()),
+			flowEndpoint(flow.GetDestIp(), flow.GetDestPort()),
+			flow.GetRxBytes(),
+			flow.GetTxBytes(),
+			flow.GetDnsQuery(),
+		)
+	}
+	return w.Flush()
+}
+
+func flowEndpoint(ip string, port uint32) string {
+	if port == 0 {
+		return ip
+	}
+	return net.JoinHostPort(ip, strconv.FormatUint(uint64(port), 10))
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc/status"
@@ -71,7 +72,35 @@ func printForwardingRules(cmd *cobra.Command, rules []*proto.ForwardingRule) {
 			cmd.Printf("\nTranslated peer: %s\n", rule.GetTranslatedHostname())
 		}
 
-		cmd.Printf("  Local %s/%s to %s:%s\n", rule.GetProtocol(), dPort, rule.GetTranslatedAddress(), tPort)
+		cmd.Printf("  Local %s/%s to %s%s\n", rule.GetProtocol(), dPort, forwardingRuleTargets(rule, tPort), forwardingRuleState(rule))
+	}
+}
+
+// forwardingRuleTargets lists the translated addresses with the translated port, the connections to several are
+// balanced round-robin
+func forwardingRuleTargets(rule *proto.ForwardingRule, tPort string) string {
+	targets := rule.GetTranslatedAddress() + ":" + tPort
+	if len(rule.GetTranslatedAddresses()) == 0 {
+		return targets
+	}
+
+	for _, addr := range rule.GetTranslatedAddresses() {
+		targets += ", " + addr + ":" + tPort
+	}
+	return targets + " (round-robin)"
+}
+
+// forwardingRuleState describes the draining and the health state of a rule, it's empty if there is none
+func forwardingRuleState(rule *proto.ForwardingRule) string {
+	switch {
+	case rule.GetDraining():
+		return fmt.Sprintf(" (draining until %s)", rule.GetDrainDeadline().AsTime().Local().Format(time.TimeOnly))
+	case rule.GetHealth() == "":
+		return ""
+	case rule.GetHealthError() != "":
+		return fmt.Sprintf(" (%s: %s)", rule.GetHealth(), rule.GetHealthError())
+	default:
+		return fmt.Sprintf(" (%s)", rule.GetHealth())
 	}
 }
 
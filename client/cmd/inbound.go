@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var inboundDuration time.Duration
+
+var inboundCmd = &cobra.Command{
+	Use:   "inbound",
+	Short: "Allow or deny the first inbound connections of peers",
+	Long: "With InboundPrompts enabled in the config, the first inbound connection of a peer to a local port raises a prompt.\n" +
+		"The connections pass while the prompt is unanswered, denying it closes the port for the peer for a while.\n" +
+		"The prompts are shown as notifications by the UI, too.",
+}
+
+var inboundListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the pending and the decided prompts",
+	Example: "  netbird inbound list",
+	Args:    cobra.NoArgs,
+	RunE:    inboundList,
+}
+
+var inboundAllowCmd = &cobra.Command{
+	Use:     "allow id",
+	Short:   "Allow the connections of a prompt",
+	Example: "  netbird inbound allow 3fa85f64 --duration 8h",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return inboundRespond(cmd, args[0], true)
+	},
+}
+
+var inboundDenyCmd = &cobra.Command{
+	Use:     "deny id",
+	Short:   "Close the port of a prompt for its peer",
+	Example: "  netbird inbound deny 3fa85f64",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return inboundRespond(cmd, args[0], false)
+	},
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{inboundAllowCmd, inboundDenyCmd} {
+		cmd.Flags().DurationVar(&inboundDuration, "duration", time.Hour, "How long the decision lasts, the next connection prompts again afterwards")
+	}
+}
+
+func inboundList(cmd *cobra.Command, _ []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.ListInboundPrompts(cmd.Context(), &proto.ListInboundPromptsRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to list inbound prompts: %v", status.Convert(err).Message())
+	}
+
+	if len(resp.GetPrompts()) == 0 {
+		cmd.Println("No inbound prompts.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tPEER\tPORT\tSINCE\tDECISION\tEXPIRES")
+	for _, prompt := range resp.GetPrompts() {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%d/%s\t%s\t%s\t%s\n",
+			prompt.GetId(),
+			inboundPeerName(prompt),
+			prompt.GetPort(),
+			prompt.GetProtocol(),
+			prompt.GetCreated().AsTime().Local().Format("15:04:05"),
+			prompt.GetDecision(),
+			prompt.GetExpires().AsTime().Local().Format("15:04:05"),
+		)
+	}
+	return w.Flush()
+}
+
+func inboundRespond(cmd *cobra.Command, id string, allow bool) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.RespondInboundPrompt(cmd.Context(), &proto.RespondInboundPromptRequest{
+		Id:       id,
+		Allow:    allow,
+		Duration: durationpb.New(inboundDuration),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to respond to inbound prompt: %v", status.Convert(err).Message())
+	}
+
+	prompt := resp.GetPrompt()
+	cmd.Printf("Connections from %s to port %d/%s %s until %s\n",
+		inboundPeerName(prompt),
+		prompt.GetPort(),
+		prompt.GetProtocol(),
+		prompt.GetDecision(),
+		prompt.GetExpires().AsTime().Local().Format(time.DateTime),
+	)
+	return nil
+}
+
+func inboundPeerName(prompt *proto.InboundPrompt) string {
+	if prompt.GetPeerFqdn() == "" {
+		return prompt.GetPeerIp()
+	}
+	return fmt.Sprintf("%s (%s)", prompt.GetPeerFqdn(), prompt.GetPeerIp())
+}
@@ -55,7 +55,7 @@ var loginCmd = &cobra.Command{
 			return fmt.Errorf("get active profile: %v", err)
 		}
 
-		providedSetupKey, err := getSetupKey()
+		providedSetupKey, err := getSetupKey(cmd.Context())
 		if err != nil {
 			return err
 		}
@@ -82,7 +82,7 @@ func doDaemonLogin(ctx context.Context, cmd *cobra.Command, providedSetupKey str
 	conn, err := DialClientGRPCServer(ctx, daemonAddr)
 	if err != nil {
 		//nolint
-		return fmt.Errorf("failed to connect to daemon error: %v\n"+
+		return fmt.Errorf("failed to connect to daemon error: %w\n"+
 			"If the daemon is not running please run: "+
 			"\nnetbird service install \nnetbird service start\n", err)
 	}
@@ -208,7 +208,7 @@ func switchProfile(ctx context.Context, profileName string, username string) err
 	conn, err := DialClientGRPCServer(ctx, daemonAddr)
 	if err != nil {
 		//nolint
-		return fmt.Errorf("failed to connect to daemon error: %v\n"+
+		return fmt.Errorf("failed to connect to daemon error: %w\n"+
 			"If the daemon is not running please run: "+
 			"\nnetbird service install \nnetbird service start\n", err)
 	}
@@ -26,7 +26,7 @@ var logoutCmd = &cobra.Command{
 
 		conn, err := DialClientGRPCServer(ctx, daemonAddr)
 		if err != nil {
-			return fmt.Errorf("connect to daemon: %v", err)
+			return fmt.Errorf("connect to daemon: %w", err)
 		}
 		defer conn.Close()
 
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var maintenanceCmd = &cobra.Command{
+	Use:   "maintenance [on|off]",
+	Short: "Announce maintenance of this peer",
+	Long: "Announce to the connected peers that this peer is about to go down for maintenance, or that it is back in service.\n" +
+		"Peers routing traffic through this peer move to other routing peers of the same network, if available, before it goes down.\n" +
+		"The mode is kept until it is turned off or the daemon restarts.",
+	Example: "  netbird maintenance on\n  netbird maintenance off",
+	Args:    cobra.ExactArgs(1),
+	RunE:    setMaintenance,
+}
+
+func setMaintenance(cmd *cobra.Command, args []string) error {
+	mode := strings.ToLower(args[0])
+	if mode != "on" && mode != "off" {
+		return fmt.Errorf("invalid maintenance value: %s. Use 'on' or 'off'", args[0])
+	}
+
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	if _, err := client.SetMaintenance(cmd.Context(), &proto.SetMaintenanceRequest{Enabled: mode == "on"}); err != nil {
+		return fmt.Errorf("failed to set maintenance mode: %v", status.Convert(err).Message())
+	}
+
+	cmd.Printf("Maintenance mode set to: %s\n", mode)
+	return nil
+}
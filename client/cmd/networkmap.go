@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var (
+	networkMapPeerFilter    string
+	networkMapRouteFilter   string
+	networkMapSectionFilter string
+)
+
+var networkMapCmd = &cobra.Command{
+	Use:   "network-map",
+	Short: "Show the latest network map received from management",
+	Long: `Prints the latest network map persisted by the daemon as JSON. Requires sync response persistence to be enabled with 'netbird debug persistence on'.
+Available sections are: all, map, routes, dns, acl`,
+	Example: `
+  netbird debug network-map
+  netbird debug network-map --peer peer-a.netbird.cloud --section acl
+  netbird debug network-map --route 10.0.0.0/24 --section routes`,
+	Args: cobra.NoArgs,
+	RunE: showNetworkMap,
+}
+
+func init() {
+	debugCmd.AddCommand(networkMapCmd)
+
+	networkMapCmd.Flags().StringVar(&networkMapPeerFilter, "peer", "", "Filter by peer public key, FQDN or IP")
+	networkMapCmd.Flags().StringVar(&networkMapRouteFilter, "route", "", "Filter by route ID, network ID, network or domain")
+	networkMapCmd.Flags().StringVar(&networkMapSectionFilter, "section", "all", "Section to print (all, map, routes, dns, acl)")
+}
+
+func showNetworkMap(cmd *cobra.Command, _ []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.GetNetworkMap(cmd.Context(), &proto.GetNetworkMapRequest{
+		Peer:  networkMapPeerFilter,
+		Route: networkMapRouteFilter,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get network map: %v", status.Convert(err).Message())
+	}
+
+	switch networkMapSectionFilter {
+	case "map":
+		cmd.Println(resp.GetNetworkMap())
+	case "routes":
+		cmd.Println(resp.GetRoutes())
+	case "dns":
+		cmd.Println(resp.GetDnsConfig())
+	case "acl":
+		cmd.Println(resp.GetFirewallRules())
+	case "all":
+		cmd.Printf("{\n\"serial\": %d,\n\"networkMap\": %s,\n\"routes\": %s,\n\"dnsConfig\": %s,\n\"firewallRules\": %s\n}\n",
+			resp.GetSerial(), resp.GetNetworkMap(), resp.GetRoutes(), resp.GetDnsConfig(), resp.GetFirewallRules())
+	default:
+		return fmt.Errorf("unknown section %q, use one of: all, map, routes, dns, acl", networkMapSectionFilter)
+	}
+
+	return nil
+}
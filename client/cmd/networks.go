@@ -46,6 +46,17 @@ var routesDeselectCmd = &cobra.Command{
 	RunE:    networksDeselect,
 }
 
+var exitNodeCmd = &cobra.Command{
+	Use:   "exit-node [peer|auto]",
+	Short: "Show or set the preferred exit node",
+	Long: "Pin a routing peer by public key, FQDN or IP as the preferred exit node or use 'auto' to pick the best available one.\n" +
+		"A pinned exit node is used whenever it is reachable, otherwise the client fails over to the next best exit node.\n" +
+		"Without arguments the current policy and the active exit node are shown.",
+	Example: "  netbird networks exit-node\n  netbird networks exit-node exit-1.netbird.cloud\n  netbird networks exit-node auto",
+	Args:    cobra.MaximumNArgs(1),
+	RunE:    exitNode,
+}
+
 func init() {
 	routesSelectCmd.PersistentFlags().BoolVarP(&appendFlag, "append", "a", false, "Append to current network selection instead of replacing")
 }
@@ -171,3 +182,47 @@ func networksDeselect(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func exitNode(cmd *cobra.Command, args []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := proto.NewDaemonServiceClient(conn)
+
+	if len(args) == 1 {
+		if _, err := client.SetExitNode(cmd.Context(), &proto.SetExitNodeRequest{Peer: args[0]}); err != nil {
+			return fmt.Errorf("failed to set exit node: %v", status.Convert(err).Message())
+		}
+		cmd.Println("Exit node selection updated successfully.")
+		return nil
+	}
+
+	resp, err := client.GetExitNode(cmd.Context(), &proto.GetExitNodeRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get exit node: %v", status.Convert(err).Message())
+	}
+
+	if resp.GetPinnedPeer() == "" {
+		cmd.Println("Mode: auto")
+	} else {
+		cmd.Printf("Mode: pinned\nPinned exit node: %s\n", peerDisplayName(resp.GetPinnedPeerFqdn(), resp.GetPinnedPeer()))
+	}
+
+	if resp.GetActivePeer() == "" {
+		cmd.Println("Active exit node: -")
+	} else {
+		cmd.Printf("Active exit node: %s\n", peerDisplayName(resp.GetActivePeerFqdn(), resp.GetActivePeer()))
+	}
+
+	return nil
+}
+
+func peerDisplayName(fqdn, pubKey string) string {
+	if fqdn == "" {
+		return pubKey
+	}
+	return fmt.Sprintf("%s (%s)", fqdn, pubKey)
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var peerRelayOff bool
+
+var peerCmd = &cobra.Command{
+	Use:   "peer",
+	Short: "Troubleshoot the connection to a peer",
+	Long:  "Troubleshoot the connection to a peer. The peer is a peer FQDN, short name, NetBird IP or public key.",
+}
+
+var peerReconnectCmd = &cobra.Command{
+	Use:     "reconnect <peer>",
+	Short:   "Tear down the connection to a peer and connect again",
+	Example: "  netbird peer reconnect nas",
+	Args:    cobra.ExactArgs(1),
+	RunE:    peerReconnect,
+}
+
+var peerRelayCmd = &cobra.Command{
+	Use:   "relay <peer>",
+	Short: "Keep the connection to a peer on the relay",
+	Long: "Keep the connection to a peer on the relay instead of the peer-to-peer connection, e.g. to rule out a broken direct path.\n" +
+		"The connection stays on the relay until --off is set or the connection is closed.",
+	Example: "  netbird peer relay nas\n  netbird peer relay nas --off",
+	Args:    cobra.ExactArgs(1),
+	RunE:    peerRelay,
+}
+
+var peerCandidatesCmd = &cobra.Command{
+	Use:     "candidates <peer>",
+	Short:   "Show the selected ICE candidate pair of the connection to a peer and the previous ones",
+	Example: "  netbird peer candidates nas",
+	Args:    cobra.ExactArgs(1),
+	RunE:    peerCandidates,
+}
+
+func init() {
+	peerRelayCmd.Flags().BoolVar(&peerRelayOff, "off", false, "Release the connection to the peer-to-peer connection again")
+}
+
+func peerReconnect(cmd *cobra.Command, args []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.ReconnectPeer(cmd.Context(), &proto.ReconnectPeerRequest{Peer: args[0]})
+	if err != nil {
+		return fmt.Errorf("failed to reconnect %s: %v", args[0], status.Convert(err).Message())
+	}
+
+	cmd.Printf("Reconnecting to %s\n", resp.GetFqdn())
+	return nil
+}
+
+func peerRelay(cmd *cobra.Command, args []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.ForceRelayPeer(cmd.Context(), &proto.ForceRelayPeerRequest{Peer: args[0], Enabled: !peerRelayOff})
+	if err != nil {
+		return fmt.Errorf("failed to force relay for %s: %v", args[0], status.Convert(err).Message())
+	}
+
+	if peerRelayOff {
+		cmd.Printf("Connection to %s may use peer-to-peer again\n", resp.GetFqdn())
+		return nil
+	}
+	cmd.Printf("Connection to %s is kept on the relay\n", resp.GetFqdn())
+	return nil
+}
+
+func peerCandidates(cmd *cobra.Command, args []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.GetPeerCandidates(cmd.Context(), &proto.GetPeerCandidatesRequest{Peer: args[0]})
+	if err != nil {
+		return fmt.Errorf("failed to get candidates of %s: %v", args[0], status.Convert(err).Message())
+	}
+
+	cmd.Print(parsePeerCandidates(resp))
+	return nil
+}
+
+func parsePeerCandidates(resp *proto.GetPeerCandidatesResponse) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Peer: %s\n", resp.GetFqdn())
+	if resp.GetRelayForced() {
+		b.WriteString("Relay: forced\n")
+	}
+
+	if current := resp.GetCurrent(); current != nil {
+		fmt.Fprintf(&b, "Current: %s\n", formatCandidatePair(current))
+	} else {
+		b.WriteString("Current: -\n")
+	}
+
+	if len(resp.GetHistory()) == 0 {
+		return b.String()
+	}
+	b.WriteString("History:\n")
+	for i := len(resp.GetHistory()) - 1; i >= 0; i-- {
+		pair := resp.GetHistory()[i]
+		fmt.Fprintf(&b, "  %s, until %s\n", formatCandidatePair(pair), pair.GetClosedAt().AsTime().Local().Format(time.DateTime))
+	}
+	return b.String()
+}
+
+func formatCandidatePair(pair *proto.CandidatePair) string {
+	s := fmt.Sprintf("%s/%s <-> %s/%s since %s",
+		pair.GetLocalType(), pair.GetLocalEndpoint(),
+		pair.GetRemoteType(), pair.GetRemoteEndpoint(),
+		pair.GetSelectedAt().AsTime().Local().Format(time.DateTime),
+	)
+	if pair.GetRelayed() {
+		s += " (TURN)"
+	}
+	return s
+}
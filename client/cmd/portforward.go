@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var portForwardCmd = &cobra.Command{
+	Use:   "port-forward",
+	Short: "Manage local port forwards to peers",
+	Long: "Forward local TCP ports to peers through the tunnel, similar to kubectl port-forward.\n" +
+		"Port forwards require the client to run in netstack mode and are kept until they are removed or the daemon restarts.",
+}
+
+var portForwardAddCmd = &cobra.Command{
+	Use:   "add [listen-address|port] target-address",
+	Short: "Forward a local port to a peer",
+	Long: "Listen on a local address and forward the connections to a peer IP or FQDN and port.\n" +
+		"A port without a host is bound to localhost.",
+	Example: "  netbird port-forward add 8080 peer-a.netbird.cloud:80\n  netbird port-forward add 0.0.0.0:5432 100.64.0.10:5432",
+	Args:    cobra.ExactArgs(2),
+	RunE:    portForwardAdd,
+}
+
+var portForwardRemoveCmd = &cobra.Command{
+	Use:     "remove listen-address",
+	Aliases: []string{"rm"},
+	Short:   "Remove a port forward",
+	Example: "  netbird port-forward remove 127.0.0.1:8080",
+	Args:    cobra.ExactArgs(1),
+	RunE:    portForwardRemove,
+}
+
+var portForwardListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List port forwards",
+	Example: "  netbird port-forward list",
+	Args:    cobra.NoArgs,
+	RunE:    portForwardList,
+}
+
+func portForwardAdd(cmd *cobra.Command, args []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.AddPortForward(cmd.Context(), &proto.AddPortForwardRequest{
+		ListenAddress: args[0],
+		TargetAddress: args[1],
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add port forward: %v", status.Convert(err).Message())
+	}
+
+	cmd.Printf("Forwarding %s to %s\n", resp.GetForward().GetListenAddress(), resp.GetForward().GetTargetAddress())
+	return nil
+}
+
+func portForwardRemove(cmd *cobra.Command, args []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	if _, err := client.RemovePortForward(cmd.Context(), &proto.RemovePortForwardRequest{ListenAddress: args[0]}); err != nil {
+		return fmt.Errorf("failed to remove port forward: %v", status.Convert(err).Message())
+	}
+
+	cmd.Printf("Removed port forward %s\n", args[0])
+	return nil
+}
+
+func portForwardList(cmd *cobra.Command, _ []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.ListPortForwards(cmd.Context(), &proto.ListPortForwardsRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to list port forwards: %v", status.Convert(err).Message())
+	}
+
+	if len(resp.GetForwards()) == 0 {
+		cmd.Println("No port forwards.")
+		return nil
+	}
+
+	cmd.Println("Port forwards:")
+	for _, f := range resp.GetForwards() {
+		cmd.Printf("\n  - Listen address: %s\n    Target: %s\n    Connections: %d active, %d total\n",
+			f.GetListenAddress(), f.GetTargetAddress(), f.GetActiveConnections(), f.GetTotalConnections())
+	}
+	return nil
+}
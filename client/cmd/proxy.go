@@ -0,0 +1,22 @@
+package cmd
+
+// Flag constants for the proxies exposing the tunnel in netstack mode
+const (
+	socks5ProxyAddressFlag = "socks5-proxy-address"
+	httpProxyAddressFlag   = "http-proxy-address"
+)
+
+var (
+	socks5ProxyAddress string
+	httpProxyAddress   string
+)
+
+func init() {
+	upCmd.PersistentFlags().StringVar(&socks5ProxyAddress, socks5ProxyAddressFlag, "",
+		"Listen address of the SOCKS5 proxy routing traffic through the tunnel, e.g. 127.0.0.1:1080. Requires netstack mode. "+
+			`An empty string "" restores the default listener on the port of NB_SOCKS5_LISTENER_PORT, 0.0.0.0:1080 if unset.`)
+
+	upCmd.PersistentFlags().StringVar(&httpProxyAddress, httpProxyAddressFlag, "",
+		"Listen address of an HTTP CONNECT proxy routing traffic through the tunnel, e.g. 127.0.0.1:3128. Requires netstack mode. "+
+			`An empty string "" disables the proxy.`)
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var relayListRefresh bool
+
+var relayCmd = &cobra.Command{
+	Use:   "relay",
+	Short: "Inspect and pin the home relay server",
+	Long: "Inspect and pin the home relay server. The client connects to the relay server with the lowest latency, " +
+		"the peers reach it through its relay server when no peer-to-peer connection is possible.",
+}
+
+var relayListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List the relay servers with their round trip time",
+	Example: "  netbird relay list\n  netbird relay list --refresh",
+	Args:    cobra.NoArgs,
+	RunE:    relayList,
+}
+
+var relayPinCmd = &cobra.Command{
+	Use:   "pin <url>",
+	Short: "Pin the home relay server",
+	Long: "Pin the home relay server to one of the relay servers instead of the one with the lowest latency.\n" +
+		"The relayed connections are reestablished through the pinned server. The pin is kept until the client restarts, " +
+		"set PreferredRelay in the config file to keep it.",
+	Example: "  netbird relay pin rels://relay.example.com:443",
+	Args:    cobra.ExactArgs(1),
+	RunE:    relayPin,
+}
+
+var relayUnpinCmd = &cobra.Command{
+	Use:   "unpin",
+	Short: "Select the home relay server with the lowest latency again",
+	Args:  cobra.NoArgs,
+	RunE:  relayUnpin,
+}
+
+func init() {
+	relayListCmd.Flags().BoolVar(&relayListRefresh, "refresh", false, "Measure the round trip times again")
+}
+
+func relayList(cmd *cobra.Command, _ []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.GetRelayCandidates(cmd.Context(), &proto.GetRelayCandidatesRequest{Refresh: relayListRefresh})
+	if err != nil {
+		return fmt.Errorf("failed to get the relay servers: %v", status.Convert(err).Message())
+	}
+
+	cmd.Print(parseRelayCandidates(resp))
+	return nil
+}
+
+func relayPin(cmd *cobra.Command, args []string) error {
+	return setPreferredRelay(cmd, args[0])
+}
+
+func relayUnpin(cmd *cobra.Command, _ []string) error {
+	return setPreferredRelay(cmd, "")
+}
+
+func setPreferredRelay(cmd *cobra.Command, url string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	if _, err := client.SetPreferredRelay(cmd.Context(), &proto.SetPreferredRelayRequest{Url: url}); err != nil {
+		return fmt.Errorf("failed to set the preferred relay server: %v", status.Convert(err).Message())
+	}
+
+	if url == "" {
+		cmd.Println("The relay server with the lowest latency is selected")
+		return nil
+	}
+	cmd.Printf("Pinned the home relay server to %s\n", url)
+	return nil
+}
+
+func parseRelayCandidates(resp *proto.GetRelayCandidatesResponse) string {
+	if len(resp.GetCandidates()) == 0 {
+		return "No relay servers available\n"
+	}
+
+	var b strings.Builder
+	for _, candidate := range resp.GetCandidates() {
+		var marks []string
+		if candidate.GetHome() {
+			marks = append(marks, "home")
+		}
+		if candidate.GetPreferred() {
+			if candidate.GetUrl() == resp.GetPinned() {
+				marks = append(marks, "pinned")
+			} else {
+				marks = append(marks, "preferred")
+			}
+		}
+
+		rtt := "-"
+		switch {
+		case candidate.GetError() != "":
+			rtt = "error: " + candidate.GetError()
+		case candidate.GetRtt() != nil:
+			rtt = candidate.GetRtt().AsDuration().String()
+		}
+
+		fmt.Fprintf(&b, "%s: %s", candidate.GetUrl(), rtt)
+		if len(marks) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(marks, ", "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
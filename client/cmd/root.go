@@ -23,6 +23,7 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/netbirdio/netbird/client/internal/profilemanager"
+	"github.com/netbirdio/netbird/client/internal/secrets"
 )
 
 const (
@@ -38,6 +39,8 @@ const (
 	extraIFaceBlackListFlag  = "extra-iface-blacklist"
 	dnsRouteIntervalFlag     = "dns-router-interval"
 	enableLazyConnectionFlag = "enable-lazy-connection"
+	enableICEMDNSFlag        = "enable-ice-mdns"
+	icePolicyFlag            = "ice-policy"
 	mtuFlag                  = "mtu"
 )
 
@@ -52,6 +55,7 @@ var (
 	oldDefaultLogFileDir    string
 	oldDefaultLogFile       string
 	logFiles                []string
+	logFormat               string
 	daemonAddr              string
 	managementURL           string
 	adminURL                string
@@ -71,15 +75,22 @@ var (
 	anonymizeFlag           bool
 	dnsRouteInterval        time.Duration
 	lazyConnEnabled         bool
+	iceMulticastDNS         bool
+	icePolicy               string
 	mtu                     uint16
 	profilesDisabled        bool
 	updateSettingsDisabled  bool
+	monitoringAddr          string
+	apiToken                string
+	errorFormat             string
 
 	rootCmd = &cobra.Command{
 		Use:          "netbird",
 		Short:        "",
 		Long:         "",
 		SilenceUsage: true,
+		// the errors are printed by Execute, in the requested format
+		SilenceErrors: true,
 	}
 )
 
@@ -88,7 +99,11 @@ func Execute() error {
 	if isUpdateBinary() {
 		return updateCmd.Execute()
 	}
-	return rootCmd.Execute()
+	cmd, err := rootCmd.ExecuteC()
+	if err != nil {
+		printError(cmd, err)
+	}
+	return err
 }
 
 func init() {
@@ -121,14 +136,20 @@ func init() {
 	}
 
 	rootCmd.PersistentFlags().StringVar(&daemonAddr, "daemon-addr", defaultDaemonAddr, "Daemon service address to serve CLI requests [unix|tcp]://[path|host:port]")
+	rootCmd.PersistentFlags().StringVar(&errorFormat, "error-format", errorFormatText, "format of the errors printed to stderr, text or json. "+
+		"Errors exit with a code per reason: 3 daemon unreachable, 4 needs sudo, 5 login required, 6 engine starting")
+	rootCmd.PersistentFlags().StringVar(&apiToken, "api-token", "", "API token presented to the monitoring API of the daemon, see: netbird api-token")
 	rootCmd.PersistentFlags().StringVarP(&managementURL, "management-url", "m", "", fmt.Sprintf("Management Service URL [http|https]://[host]:[port] (default \"%s\")", profilemanager.DefaultManagementURL))
 	rootCmd.PersistentFlags().StringVar(&adminURL, "admin-url", "", fmt.Sprintf("Admin Panel URL [http|https]://[host]:[port] (default \"%s\")", profilemanager.DefaultAdminURL))
 	rootCmd.PersistentFlags().StringVarP(&logLevel, "log-level", "l", "info", "sets NetBird log level")
 	rootCmd.PersistentFlags().StringSliceVar(&logFiles, "log-file", []string{defaultLogFile}, "sets NetBird log paths written to simultaneously. If `console` is specified the log will be output to stdout. If `syslog` is specified the log will be sent to syslog daemon. You can pass the flag multiple times or separate entries by `,` character")
-	rootCmd.PersistentFlags().StringVarP(&setupKey, "setup-key", "k", "", "Setup key obtained from the Management Service Dashboard (used to register peer)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "", "sets NetBird log format, text or json. The json format suits log shippers")
+	rootCmd.PersistentFlags().StringVarP(&setupKey, "setup-key", "k", "", "Setup key obtained from the Management Service Dashboard (used to register peer). "+
+		"Accepts a reference to an external secret: file:<path>, env:<name>, exec:<command> or vault:<path>#<field>")
 	rootCmd.PersistentFlags().StringVar(&setupKeyPath, "setup-key-file", "", "The path to a setup key obtained from the Management Service Dashboard (used to register peer) This is ignored if the setup-key flag is provided.")
 	rootCmd.MarkFlagsMutuallyExclusive("setup-key", "setup-key-file")
-	rootCmd.PersistentFlags().StringVar(&preSharedKey, preSharedKeyFlag, "", "Sets WireGuard PreSharedKey property. If set, then only peers that have the same key can communicate.")
+	rootCmd.PersistentFlags().StringVar(&preSharedKey, preSharedKeyFlag, "", "Sets WireGuard PreSharedKey property. If set, then only peers that have the same key can communicate. "+
+		"Accepts a reference to an external secret: file:<path>, env:<name> or vault:<path>#<field>, which is resolved on every connect")
 	rootCmd.PersistentFlags().StringVarP(&hostName, "hostname", "n", "", "Sets a custom hostname for the device")
 	rootCmd.PersistentFlags().BoolVarP(&anonymizeFlag, "anonymize", "A", false, "anonymize IP addresses and non-netbird.io domains in logs and status output")
 	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", defaultConfigPath, "Overrides the default profile file location")
@@ -144,12 +165,35 @@ func init() {
 	rootCmd.AddCommand(forwardingRulesCmd)
 	rootCmd.AddCommand(debugCmd)
 	rootCmd.AddCommand(profileCmd)
+	rootCmd.AddCommand(maintenanceCmd)
+	rootCmd.AddCommand(portForwardCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(wakeCmd)
+	rootCmd.AddCommand(dnsCmd)
+	rootCmd.AddCommand(peerCmd)
+	rootCmd.AddCommand(relayCmd)
+	rootCmd.AddCommand(apiTokenCmd)
+	rootCmd.AddCommand(inboundCmd)
 
 	networksCMD.AddCommand(routesListCmd)
 	networksCMD.AddCommand(routesSelectCmd, routesDeselectCmd)
+	networksCMD.AddCommand(exitNodeCmd)
+	networksCMD.AddCommand(routeRulesCmd)
+	networksCMD.AddCommand(routeMetricsCmd)
 
 	forwardingRulesCmd.AddCommand(forwardingRulesListCmd)
 
+	dnsCmd.AddCommand(dnsCacheCmd, dnsForwarderCmd)
+	dnsCacheCmd.AddCommand(dnsCacheStatsCmd, dnsCacheFlushCmd)
+	dnsForwarderCmd.AddCommand(dnsForwarderStatsCmd)
+
+	peerCmd.AddCommand(peerReconnectCmd, peerRelayCmd, peerCandidatesCmd)
+	relayCmd.AddCommand(relayListCmd, relayPinCmd, relayUnpinCmd)
+
+	portForwardCmd.AddCommand(portForwardAddCmd, portForwardRemoveCmd, portForwardListCmd)
+	apiTokenCmd.AddCommand(apiTokenCreateCmd, apiTokenListCmd, apiTokenRevokeCmd)
+	inboundCmd.AddCommand(inboundListCmd, inboundAllowCmd, inboundDenyCmd)
+
 	debugCmd.AddCommand(debugBundleCmd)
 	debugCmd.AddCommand(logCmd)
 	logCmd.AddCommand(logLevelCmd)
@@ -178,6 +222,8 @@ func init() {
 	upCmd.PersistentFlags().BoolVar(&rosenpassEnabled, enableRosenpassFlag, false, "[Experimental] Enable Rosenpass feature. If enabled, the connection will be post-quantum secured via Rosenpass.")
 	upCmd.PersistentFlags().BoolVar(&rosenpassPermissive, rosenpassPermissiveFlag, false, "[Experimental] Enable Rosenpass in permissive mode to allow this peer to accept WireGuard connections without requiring Rosenpass functionality from peers that do not have Rosenpass enabled.")
 	upCmd.PersistentFlags().BoolVar(&autoConnectDisabled, disableAutoConnectFlag, false, "Disables auto-connect feature. If enabled, then the client won't connect automatically when the service starts.")
+	upCmd.PersistentFlags().BoolVar(&iceMulticastDNS, enableICEMDNSFlag, false, "Hide the local IPs of the host candidates from peers behind random mDNS names. Direct LAN connections then require peers on the same network to resolve the names via mDNS.")
+	upCmd.PersistentFlags().StringVar(&icePolicy, icePolicyFlag, "", "Restricts the ICE candidate types used to connect to peers. Possible values: all, relay-only, no-relay, host-only. Management can override it per peer.")
 	upCmd.PersistentFlags().BoolVar(&lazyConnEnabled, enableLazyConnectionFlag, false, "[Experimental] Enable the lazy connection feature. If enabled, the client will establish connections on-demand. Note: this setting may be overridden by management configuration.")
 
 }
@@ -234,12 +280,32 @@ func DialClientGRPCServer(ctx context.Context, addr string) (*grpc.ClientConn, e
 	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
 	defer cancel()
 
-	return grpc.DialContext(
-		ctx,
-		strings.TrimPrefix(addr, "tcp://"),
+	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithBlock(),
-	)
+	}
+	if apiToken != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(apiTokenCredentials(apiToken)))
+	}
+
+	conn, err := grpc.DialContext(ctx, strings.TrimPrefix(addr, "tcp://"), opts...)
+	if err != nil {
+		return nil, daemonDialError(addr, err)
+	}
+	return conn, nil
+}
+
+// apiTokenCredentials presents an API token to the monitoring API of the daemon
+type apiTokenCredentials string
+
+func (c apiTokenCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(c)}, nil
+}
+
+// RequireTransportSecurity allows the token on the unencrypted daemon connection, the monitoring API is expected to
+// listen on a local address
+func (c apiTokenCredentials) RequireTransportSecurity() bool {
+	return false
 }
 
 // WithBackOff execute function in backoff cycle.
@@ -260,11 +326,18 @@ var CLIBackOffSettings = &backoff.ExponentialBackOff{
 	Clock:               backoff.SystemClock,
 }
 
-func getSetupKey() (string, error) {
+// getSetupKey returns the setup key from the flags. The key may refer to an external secret, which is resolved
+// by the CLI, so the daemon never runs secret commands on behalf of the user.
+func getSetupKey(ctx context.Context) (string, error) {
 	if setupKeyPath != "" && setupKey == "" {
 		return getSetupKeyFromFile(setupKeyPath)
 	}
-	return setupKey, nil
+
+	key, err := secrets.Resolve(ctx, setupKey)
+	if err != nil {
+		return "", fmt.Errorf("resolve setup key: %w", err)
+	}
+	return key, nil
 }
 
 func getSetupKeyFromFile(setupKeyPath string) (string, error) {
@@ -391,7 +464,7 @@ func getClient(cmd *cobra.Command) (*grpc.ClientConn, error) {
 	conn, err := DialClientGRPCServer(cmd.Context(), daemonAddr)
 	if err != nil {
 		//nolint
-		return nil, fmt.Errorf("failed to connect to daemon error: %v\n"+
+		return nil, fmt.Errorf("failed to connect to daemon error: %w\n"+
 			"If the daemon is not running please run: "+
 			"\nnetbird service install \nnetbird service start\n", err)
 	}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var routeMetricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Show how long programming the routes and route firewall rules takes",
+	Long: "Show the latency of the route syscalls and how long applying the routes and route firewall rules\n" +
+		"of each network map took, to measure the cost of large route sets.",
+	Example: "  netbird networks metrics",
+	Args:    cobra.NoArgs,
+	RunE:    routeMetrics,
+}
+
+func routeMetrics(cmd *cobra.Command, _ []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.GetRouteMetrics(cmd.Context(), &proto.GetRouteMetricsRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get route metrics: %v", status.Convert(err).Message())
+	}
+
+	if last := resp.GetLastUpdate(); last != nil {
+		cmd.Printf("Last network map (serial %d, %s):\n", last.GetSerial(), last.GetTime().AsTime().Local().Format("2006-01-02 15:04:05"))
+		cmd.Printf("  Routes: %d client, %d server in %s\n", last.GetClientRoutes(), last.GetServerRoutes(), last.GetRoutesDuration().AsDuration())
+		cmd.Printf("  Route firewall rules: %d in %s\n", last.GetFirewallRules(), last.GetFirewallDuration().AsDuration())
+	} else {
+		cmd.Println("No network map applied yet.")
+	}
+
+	printLatencyHistogram(cmd, "Network map routes", resp.GetRoutes())
+	printLatencyHistogram(cmd, "Network map route firewall rules", resp.GetFirewall())
+	printLatencyHistogram(cmd, fmt.Sprintf("Route add syscalls (%s)", resp.GetOs()), resp.GetRouteAdd())
+	printLatencyHistogram(cmd, fmt.Sprintf("Route remove syscalls (%s)", resp.GetOs()), resp.GetRouteRemove())
+	return nil
+}
+
+func printLatencyHistogram(cmd *cobra.Command, name string, h *proto.LatencyHistogram) {
+	cmd.Printf("\n%s: %d\n", name, h.GetCount())
+	if h.GetCount() == 0 {
+		return
+	}
+	cmd.Printf("  Average: %s, Max: %s\n", h.GetSum().AsDuration()/time.Duration(h.GetCount()), h.GetMax().AsDuration())
+	for _, bucket := range h.GetBuckets() {
+		if bucket.GetCount() == 0 {
+			continue
+		}
+		bound := "+Inf"
+		if bucket.GetUpperBound() != nil {
+			bound = bucket.GetUpperBound().AsDuration().String()
+		}
+		cmd.Printf("  <= %s: %d\n", bound, bucket.GetCount())
+	}
+}
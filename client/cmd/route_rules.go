@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var routeRulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Show the counters of the route firewall rules",
+	Long: "Show how many packets and bytes matched each firewall rule of the networks this peer routes.\n" +
+		"Audit rules let their traffic through, their counters tell what the rule would drop once enforced.",
+	Example: "  netbird networks rules",
+	Args:    cobra.NoArgs,
+	RunE:    routeRules,
+}
+
+func routeRules(cmd *cobra.Command, _ []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.GetRouteRuleStats(cmd.Context(), &proto.GetRouteRuleStatsRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get route rule stats: %v", status.Convert(err).Message())
+	}
+
+	if len(resp.GetRules()) == 0 {
+		cmd.Println("No route firewall rules available.")
+		return nil
+	}
+
+	for _, rule := range resp.GetRules() {
+		policy := "-"
+		if len(rule.GetPolicyID()) > 0 {
+			policy = string(rule.GetPolicyID())
+		}
+		cmd.Printf("\n  - Rule: %s\n    Policy: %s\n    Action: %s\n    Packets: %d\n    Bytes: %d\n",
+			rule.GetRuleID(), policy, rule.GetAction(), rule.GetPackets(), rule.GetBytes())
+	}
+	return nil
+}
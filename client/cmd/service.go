@@ -25,6 +25,8 @@ var serviceCmd = &cobra.Command{
 var (
 	serviceName    string
 	serviceEnvVars []string
+
+	systemdHardening bool
 )
 
 type program struct {
@@ -33,6 +35,8 @@ type program struct {
 	serv             *grpc.Server
 	serverInstance   *server.Server
 	serverInstanceMu sync.Mutex
+	// monitoringServ serves the monitoring API, protected by serverInstanceMu
+	monitoringServ *grpc.Server
 }
 
 func init() {
@@ -43,6 +47,7 @@ func init() {
 
 	serviceCmd.AddCommand(runCmd, startCmd, stopCmd, restartCmd, svcStatusCmd, installCmd, uninstallCmd, reconfigureCmd)
 	serviceCmd.PersistentFlags().BoolVar(&profilesDisabled, "disable-profiles", false, "Disables profiles feature. If enabled, the client will not be able to change or edit any profile. To persist this setting, use: netbird service install --disable-profiles")
+	serviceCmd.PersistentFlags().StringVar(&monitoringAddr, "monitoring-addr", "", "Serves the read-only monitoring API that requires API tokens on the address [unix|tcp]://[path|host:port], e.g. tcp://127.0.0.1:41731. Tokens are managed with: netbird api-token")
 	serviceCmd.PersistentFlags().BoolVar(&updateSettingsDisabled, "disable-update-settings", false, "Disables update settings feature. If enabled, the client will not be able to change or edit any settings. To persist this setting, use: netbird service install --disable-update-settings")
 
 	rootCmd.PersistentFlags().StringVarP(&serviceName, "service", "s", defaultServiceName, "Netbird system service name")
@@ -53,6 +58,11 @@ func init() {
 	installCmd.Flags().StringSliceVar(&serviceEnvVars, "service-env", nil, serviceEnvDesc)
 	reconfigureCmd.Flags().StringSliceVar(&serviceEnvVars, "service-env", nil, serviceEnvDesc)
 
+	systemdHardeningDesc := `Applies the systemd sandboxing of the packaged unit to the service (Linux only). ` +
+		`The sandbox restricts the daemon's access to the system, e.g. SSH sessions can't switch to other users`
+	installCmd.Flags().BoolVar(&systemdHardening, "systemd-hardening", false, systemdHardeningDesc)
+	reconfigureCmd.Flags().BoolVar(&systemdHardening, "systemd-hardening", false, systemdHardeningDesc)
+
 	rootCmd.AddCommand(serviceCmd)
 }
 
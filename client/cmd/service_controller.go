@@ -16,6 +16,7 @@ import (
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 
+	"github.com/netbirdio/netbird/client/internal/sdnotify"
 	"github.com/netbirdio/netbird/client/proto"
 	"github.com/netbirdio/netbird/client/server"
 	"github.com/netbirdio/netbird/client/system"
@@ -66,9 +67,13 @@ func (p *program) Start(svc service.Service) error {
 			log.Fatalf("failed to start daemon: %v", err)
 		}
 		proto.RegisterDaemonServiceServer(p.serv, serverInstance)
+		serverInstance.NotifyServiceManager(p.ctx)
 
 		p.serverInstanceMu.Lock()
 		p.serverInstance = serverInstance
+		if monitoringAddr != "" {
+			p.monitoringServ = startMonitoringServer(serverInstance, monitoringAddr)
+		}
 		p.serverInstanceMu.Unlock()
 
 		log.Printf("started daemon server: %v", split[1])
@@ -80,6 +85,10 @@ func (p *program) Start(svc service.Service) error {
 }
 
 func (p *program) Stop(srv service.Service) error {
+	if _, err := sdnotify.Notify(sdnotify.StateStopping); err != nil {
+		log.Debugf("failed to notify systemd about stopping: %v", err)
+	}
+
 	p.serverInstanceMu.Lock()
 	if p.serverInstance != nil {
 		in := new(proto.DownRequest)
@@ -88,6 +97,9 @@ func (p *program) Stop(srv service.Service) error {
 			log.Errorf("failed to stop daemon: %v", err)
 		}
 	}
+	if p.monitoringServ != nil {
+		p.monitoringServ.Stop()
+	}
 	p.serverInstanceMu.Unlock()
 
 	p.cancel()
@@ -101,6 +113,49 @@ func (p *program) Stop(srv service.Service) error {
 	return nil
 }
 
+// startMonitoringServer serves the read-only monitoring API of the daemon, every request has to present an API token.
+// It returns nil if the address can't be served, the daemon keeps running without the monitoring API.
+func startMonitoringServer(serverInstance *server.Server, addr string) *grpc.Server {
+	network, address, ok := strings.Cut(addr, "://")
+	if !ok || (network != "unix" && network != "tcp") {
+		log.Errorf("unsupported monitoring address protocol: %s", addr)
+		return nil
+	}
+
+	if network == "unix" {
+		if stat, err := os.Stat(address); err == nil && !stat.IsDir() {
+			if err := os.Remove(address); err != nil {
+				log.Debugf("remove monitoring socket file: %v", err)
+			}
+		}
+	}
+
+	listen, err := net.Listen(network, address)
+	if err != nil {
+		log.Errorf("failed to listen on the monitoring address %s: %v", addr, err)
+		return nil
+	}
+	if network == "unix" {
+		// the API tokens protect the socket
+		if err := os.Chmod(address, 0666); err != nil {
+			log.Errorf("failed setting monitoring socket permissions: %v", err)
+			_ = listen.Close()
+			return nil
+		}
+	}
+
+	serv := grpc.NewServer(serverInstance.MonitoringServerOptions()...)
+	proto.RegisterDaemonServiceServer(serv, serverInstance)
+
+	log.Infof("started monitoring API server: %s", addr)
+	go func() {
+		if err := serv.Serve(listen); err != nil {
+			log.Errorf("failed to serve monitoring API requests: %v", err)
+		}
+	}()
+	return serv
+}
+
 // Common setup for service control commands
 func setupServiceControlCommand(cmd *cobra.Command, ctx context.Context, cancel context.CancelFunc) (service.Service, error) {
 	SetFlagsFromEnvVars(rootCmd)
@@ -112,7 +167,7 @@ func setupServiceControlCommand(cmd *cobra.Command, ctx context.Context, cancel
 		return nil, err
 	}
 
-	if err := util.InitLog(logLevel, logFiles...); err != nil {
+	if err := util.InitLogWithFormat(logLevel, logFormat, logFiles...); err != nil {
 		return nil, fmt.Errorf("init log: %w", err)
 	}
 
@@ -15,6 +15,7 @@ import (
 	"github.com/kardianos/service"
 	"github.com/spf13/cobra"
 
+	"github.com/netbirdio/netbird/client/internal/winfw"
 	"github.com/netbirdio/netbird/util"
 )
 
@@ -51,6 +52,10 @@ func buildServiceArguments() []string {
 		args = append(args, "--log-file", logFile)
 	}
 
+	if logFormat != "" {
+		args = append(args, "--log-format", logFormat)
+	}
+
 	if profilesDisabled {
 		args = append(args, "--disable-profiles")
 	}
@@ -59,11 +64,19 @@ func buildServiceArguments() []string {
 		args = append(args, "--disable-update-settings")
 	}
 
+	if monitoringAddr != "" {
+		args = append(args, "--monitoring-addr", monitoringAddr)
+	}
+
 	return args
 }
 
 // Configure platform-specific service settings
 func configurePlatformSpecificSettings(svcConfig *service.Config) error {
+	for key, value := range serviceManagerOptions(runtime.GOOS, systemdHardening) {
+		svcConfig.Option[key] = value
+	}
+
 	if runtime.GOOS == "linux" {
 		// Respected only by systemd systems
 		svcConfig.Dependencies = []string{"After=network.target syslog.target"}
@@ -89,10 +102,6 @@ func configurePlatformSpecificSettings(svcConfig *service.Config) error {
 		}
 	}
 
-	if runtime.GOOS == "windows" {
-		svcConfig.Option["OnFailure"] = "restart"
-	}
-
 	return nil
 }
 
@@ -172,6 +181,10 @@ var uninstallCmd = &cobra.Command{
 			}
 		}
 
+		if err := winfw.Remove(); err != nil {
+			log.Warnf("failed to remove windows firewall rules: %v", err)
+		}
+
 		cmd.Println("NetBird service has been uninstalled")
 		return nil
 	},
@@ -0,0 +1,160 @@
+//go:build !ios && !android
+
+package cmd
+
+import (
+	"github.com/kardianos/service"
+)
+
+const (
+	// serviceRestartPolicy restarts the daemon after crashes but not after a clean stop
+	serviceRestartPolicy = "on-failure"
+	// windowsRecoveryDelay is how long the service control manager waits before restarting a failed service
+	windowsRecoveryDelay = "5s"
+	// windowsRecoveryResetPeriod is the time in seconds without failures after which the failure count is reset
+	windowsRecoveryResetPeriod = 24 * 60 * 60
+)
+
+// systemdServiceSection is the NetBird unit up to the sandboxing directives. It is rendered by the service library
+// which provides the cmd and cmdEscape template functions.
+const systemdServiceSection = `[Unit]
+Description={{.Description}}
+Documentation=https://netbird.io/docs
+ConditionFileIsExecutable={{.Path|cmdEscape}}
+{{range $i, $dep := .Dependencies}}
+{{$dep}} {{end}}
+StartLimitIntervalSec=60
+StartLimitBurst=10
+
+[Service]
+# the daemon reports ready once the first network map is applied or it waits for a login, and pings the watchdog
+# while the engine health probes complete
+Type=notify
+NotifyAccess=main
+TimeoutStartSec=300
+WatchdogSec=90
+ExecStart={{.Path|cmdEscape}}{{range .Arguments}} {{.|cmd}}{{end}}
+{{if .ChRoot}}RootDirectory={{.ChRoot|cmd}}{{end}}
+{{if .WorkingDirectory}}WorkingDirectory={{.WorkingDirectory|cmdEscape}}{{end}}
+{{if .UserName}}User={{.UserName}}{{end}}
+{{if and .LogOutput .HasOutputFileSupport -}}
+StandardOutput=file:{{.LogDirectory}}/{{.Name}}.out
+StandardError=file:{{.LogDirectory}}/{{.Name}}.err
+{{- end}}
+{{if gt .LimitNOFILE -1 }}LimitNOFILE={{.LimitNOFILE}}{{end}}
+{{if .Restart}}Restart={{.Restart}}{{end}}
+RestartSec=5
+TimeoutStopSec=10
+EnvironmentFile=-/etc/sysconfig/{{.Name}}
+
+{{range $k, $v := .EnvVars -}}
+Environment={{$k}}={{$v}}
+{{end -}}
+`
+
+// systemdSandboxSection matches the sandboxing of the packaged netbird@.service unit
+const systemdSandboxSection = `
+# sandboxing
+LockPersonality=yes
+MemoryDenyWriteExecute=yes
+NoNewPrivileges=yes
+PrivateMounts=yes
+PrivateTmp=yes
+ProtectClock=yes
+ProtectControlGroups=yes
+ProtectHome=yes
+ProtectHostname=yes
+ProtectKernelLogs=yes
+# needed to load wg module for kernel-mode WireGuard
+ProtectKernelModules=no
+ProtectKernelTunables=no
+ProtectSystem=yes
+RemoveIPC=yes
+RestrictNamespaces=yes
+RestrictRealtime=yes
+RestrictSUIDSGID=yes
+`
+
+const systemdInstallSection = `
+[Install]
+WantedBy=multi-user.target
+`
+
+// launchdPlist is the NetBird launch daemon. It is rendered by the service library which provides the bool and
+// html template functions. ExitTimeOut gives the daemon time to remove its routes and DNS settings on shutdown.
+const launchdPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Disabled</key>
+	<false/>
+	{{- if .EnvVars}}
+	<key>EnvironmentVariables</key>
+	<dict>
+		{{- range $k, $v := .EnvVars}}
+		<key>{{html $k}}</key>
+		<string>{{html $v}}</string>
+		{{- end}}
+	</dict>
+	{{- end}}
+	<key>ExitTimeOut</key>
+	<integer>30</integer>
+	<key>KeepAlive</key>
+	<{{bool .KeepAlive}}/>
+	<key>Label</key>
+	<string>{{html .Name}}</string>
+	<key>ProcessType</key>
+	<string>Adaptive</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>{{html .Path}}</string>
+		{{- range .Config.Arguments}}
+		<string>{{html .}}</string>
+		{{- end}}
+	</array>
+	<key>RunAtLoad</key>
+	<{{bool .RunAtLoad}}/>
+	{{- if .StandardErrorPath}}
+	<key>StandardErrorPath</key>
+	<string>{{html .StandardErrorPath}}</string>
+	{{- end}}
+	{{- if .StandardOutPath}}
+	<key>StandardOutPath</key>
+	<string>{{html .StandardOutPath}}</string>
+	{{- end}}
+	<key>ThrottleInterval</key>
+	<integer>5</integer>
+</dict>
+</plist>
+`
+
+// systemdUnit returns the systemd unit template, with the sandboxing directives if hardening is enabled
+func systemdUnit(hardening bool) string {
+	unit := systemdServiceSection
+	if hardening {
+		unit += systemdSandboxSection
+	}
+	return unit + systemdInstallSection
+}
+
+// serviceManagerOptions returns the service library options that generate the native service definition of the
+// platform, so the service behaves the same regardless of how it was installed
+func serviceManagerOptions(goos string, hardening bool) service.KeyValue {
+	options := make(service.KeyValue)
+
+	switch goos {
+	case "linux":
+		options["SystemdScript"] = systemdUnit(hardening)
+		options["Restart"] = serviceRestartPolicy
+	case "darwin":
+		options["LaunchdConfig"] = launchdPlist
+		options["KeepAlive"] = true
+		options["RunAtLoad"] = true
+	case "windows":
+		options["OnFailure"] = "restart"
+		options["OnFailureDelayDuration"] = windowsRecoveryDelay
+		options["OnFailureResetPeriod"] = windowsRecoveryResetPeriod
+	}
+
+	return options
+}
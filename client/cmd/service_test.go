@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
 	"testing"
+	"text/template"
 	"time"
 
 	"github.com/kardianos/service"
@@ -261,3 +263,70 @@ func TestServiceConfigWithEnvVars(t *testing.T) {
 		assert.Equal(t, "test-service", cfg.EnvVars["SYSTEMD_UNIT"])
 	}
 }
+
+// TestSystemdUnit tests the generated systemd unit with and without sandboxing
+func TestSystemdUnit(t *testing.T) {
+	funcs := template.FuncMap{
+		"cmd":       func(s string) string { return s },
+		"cmdEscape": func(s string) string { return s },
+	}
+	data := struct {
+		*service.Config
+		Path                 string
+		HasOutputFileSupport bool
+		LimitNOFILE          int
+		Restart              string
+		LogOutput            bool
+		LogDirectory         string
+	}{
+		Config: &service.Config{
+			Name:         "netbird",
+			Description:  "NetBird mesh network client",
+			Arguments:    []string{"service", "run"},
+			Dependencies: []string{"After=network.target syslog.target"},
+			EnvVars:      map[string]string{"NB_LOG_LEVEL": "debug"},
+		},
+		Path:        "/usr/bin/netbird",
+		LimitNOFILE: -1,
+		Restart:     serviceRestartPolicy,
+	}
+
+	for _, hardening := range []bool{false, true} {
+		tmpl, err := template.New("").Funcs(funcs).Parse(systemdUnit(hardening))
+		require.NoError(t, err)
+
+		var unit strings.Builder
+		require.NoError(t, tmpl.Execute(&unit, data))
+
+		assert.Contains(t, unit.String(), "ExecStart=/usr/bin/netbird service run")
+		assert.Contains(t, unit.String(), "Restart=on-failure")
+		assert.Contains(t, unit.String(), "Type=notify")
+		assert.Contains(t, unit.String(), "WatchdogSec=")
+		assert.Contains(t, unit.String(), "Environment=NB_LOG_LEVEL=debug")
+		assert.True(t, strings.HasSuffix(unit.String(), "[Install]\nWantedBy=multi-user.target\n"))
+		assert.Equal(t, hardening, strings.Contains(unit.String(), "ProtectSystem=yes"))
+	}
+}
+
+// TestServiceManagerOptions tests the native service options of every platform
+func TestServiceManagerOptions(t *testing.T) {
+	linux := serviceManagerOptions("linux", true)
+	assert.Equal(t, systemdUnit(true), linux["SystemdScript"])
+	assert.Equal(t, serviceRestartPolicy, linux["Restart"])
+
+	darwin := serviceManagerOptions("darwin", false)
+	assert.Equal(t, launchdPlist, darwin["LaunchdConfig"])
+	assert.Equal(t, true, darwin["KeepAlive"])
+	assert.Equal(t, true, darwin["RunAtLoad"])
+
+	windows := serviceManagerOptions("windows", false)
+	assert.Equal(t, "restart", windows["OnFailure"])
+	assert.Equal(t, windowsRecoveryDelay, windows["OnFailureDelayDuration"])
+	assert.Equal(t, windowsRecoveryResetPeriod, windows["OnFailureResetPeriod"])
+
+	assert.Empty(t, serviceManagerOptions("freebsd", true))
+
+	funcs := template.FuncMap{"bool": func(v bool) string { return map[bool]string{true: "true", false: "false"}[v] }}
+	_, err := template.New("").Funcs(funcs).Parse(launchdPlist)
+	require.NoError(t, err)
+}
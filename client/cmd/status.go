@@ -8,9 +8,11 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
 	"google.golang.org/grpc/status"
 
 	"github.com/netbirdio/netbird/client/internal"
+	"github.com/netbirdio/netbird/client/internal/daemonerr"
 	"github.com/netbirdio/netbird/client/internal/profilemanager"
 	"github.com/netbirdio/netbird/client/proto"
 	nbstatus "github.com/netbirdio/netbird/client/status"
@@ -22,12 +24,14 @@ var (
 	ipv4Flag             bool
 	jsonFlag             bool
 	yamlFlag             bool
+	controlPlaneFlag     bool
 	ipsFilter            []string
 	prefixNamesFilter    []string
 	statusFilter         string
 	ipsFilterMap         map[string]struct{}
 	prefixNamesFilterMap map[string]struct{}
 	connectionTypeFilter string
+	probePeersFlag       bool
 )
 
 var statusCmd = &cobra.Command{
@@ -44,10 +48,12 @@ func init() {
 	statusCmd.PersistentFlags().BoolVar(&jsonFlag, "json", false, "display detailed status information in json format")
 	statusCmd.PersistentFlags().BoolVar(&yamlFlag, "yaml", false, "display detailed status information in yaml format")
 	statusCmd.PersistentFlags().BoolVar(&ipv4Flag, "ipv4", false, "display only NetBird IPv4 of this peer, e.g., --ipv4 will output 100.64.0.33")
-	statusCmd.MarkFlagsMutuallyExclusive("detail", "json", "yaml", "ipv4")
+	statusCmd.PersistentFlags().BoolVar(&controlPlaneFlag, "control-plane", false, "display the health of the management, signal, relay and flow connections")
+	statusCmd.MarkFlagsMutuallyExclusive("detail", "json", "yaml", "ipv4", "control-plane")
 	statusCmd.PersistentFlags().StringSliceVar(&ipsFilter, "filter-by-ips", []string{}, "filters the detailed output by a list of one or more IPs, e.g., --filter-by-ips 100.64.0.100,100.64.0.200")
 	statusCmd.PersistentFlags().StringSliceVar(&prefixNamesFilter, "filter-by-names", []string{}, "filters the detailed output by a list of one or more peer FQDN or hostnames, e.g., --filter-by-names peer-a,peer-b.netbird.cloud")
 	statusCmd.PersistentFlags().StringVar(&statusFilter, "filter-by-status", "", "filters the detailed output by connection status(idle|connecting|connected), e.g., --filter-by-status connected")
+	statusCmd.PersistentFlags().BoolVar(&probePeersFlag, "probe-peers", false, "send echo probes through the tunnel to the connected peers and show their latency and loss")
 	statusCmd.PersistentFlags().StringVar(&connectionTypeFilter, "filter-by-connection-type", "", "filters the detailed output by connection type (P2P|Relayed), e.g., --filter-by-connection-type P2P")
 }
 
@@ -68,7 +74,8 @@ func statusFunc(cmd *cobra.Command, args []string) error {
 
 	ctx := internal.CtxInitState(cmd.Context())
 
-	resp, err := getStatus(ctx, false)
+	// probe the relays to show their current health in the control plane view
+	resp, err := getStatus(ctx, controlPlaneFlag, peerListOptions())
 	if err != nil {
 		return err
 	}
@@ -77,6 +84,10 @@ func statusFunc(cmd *cobra.Command, args []string) error {
 
 	if status == string(internal.StatusNeedsLogin) || status == string(internal.StatusLoginFailed) ||
 		status == string(internal.StatusSessionExpired) {
+		loginErr := daemonerr.New(daemonerr.ReasonAuthRequired, fmt.Errorf("login required, daemon status: %s", status))
+		if jsonFlag {
+			return loginErr
+		}
 		cmd.Printf("Daemon status: %s\n\n"+
 			"Run UP command to log in with SSO (interactive login):\n\n"+
 			" netbird up \n\n"+
@@ -85,7 +96,7 @@ func statusFunc(cmd *cobra.Command, args []string) error {
 			"More info: https://docs.netbird.io/how-to/register-machines-using-setup-keys\n\n",
 			resp.GetStatus(),
 		)
-		return nil
+		return loginErr
 	}
 
 	if ipv4Flag {
@@ -100,8 +111,17 @@ func statusFunc(cmd *cobra.Command, args []string) error {
 	}
 
 	var outputInformationHolder = nbstatus.ConvertToStatusOutputOverview(resp, anonymizeFlag, statusFilter, prefixNamesFilter, prefixNamesFilterMap, ipsFilterMap, connectionTypeFilter, profName)
+	if probePeersFlag {
+		probes, err := probePeers(ctx)
+		if err != nil {
+			return err
+		}
+		nbstatus.AttachPeerProbes(&outputInformationHolder, probes)
+	}
 	var statusOutputString string
 	switch {
+	case controlPlaneFlag:
+		statusOutputString = nbstatus.ParseControlPlaneSummary(outputInformationHolder)
 	case detailFlag:
 		statusOutputString = nbstatus.ParseToFullDetailSummary(outputInformationHolder)
 	case jsonFlag:
@@ -121,24 +141,43 @@ func statusFunc(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func getStatus(ctx context.Context, shouldRunProbes bool) (*proto.StatusResponse, error) {
+func getStatus(ctx context.Context, shouldRunProbes bool, peerListOptions *proto.PeerListOptions) (*proto.StatusResponse, error) {
 	conn, err := DialClientGRPCServer(ctx, daemonAddr)
 	if err != nil {
 		//nolint
-		return nil, fmt.Errorf("failed to connect to daemon error: %v\n"+
+		return nil, fmt.Errorf("failed to connect to daemon error: %w\n"+
 			"If the daemon is not running please run: "+
 			"\nnetbird service install \nnetbird service start\n", err)
 	}
 	defer conn.Close()
 
-	resp, err := proto.NewDaemonServiceClient(conn).Status(ctx, &proto.StatusRequest{GetFullPeerStatus: true, ShouldRunProbes: shouldRunProbes})
+	resp, err := proto.NewDaemonServiceClient(conn).Status(ctx, &proto.StatusRequest{
+		GetFullPeerStatus: true,
+		ShouldRunProbes:   shouldRunProbes,
+		PeerListOptions:   peerListOptions,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("status failed: %v", status.Convert(err).Message())
+		return nil, daemonerr.New(daemonerr.ReasonOf(err), fmt.Errorf("status failed: %v", status.Convert(err).Message()))
 	}
 
 	return resp, nil
 }
 
+// probePeers asks the daemon to probe the connected peers through the tunnel
+func probePeers(ctx context.Context) ([]*proto.PeerProbe, error) {
+	conn, err := DialClientGRPCServer(ctx, daemonAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to daemon error: %w", err)
+	}
+	defer conn.Close()
+
+	resp, err := proto.NewDaemonServiceClient(conn).ProbePeers(ctx, &proto.ProbePeersRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("probe peers failed: %v", status.Convert(err).Message())
+	}
+	return resp.GetPeers(), nil
+}
+
 func parseFilters() error {
 	switch strings.ToLower(statusFilter) {
 	case "", "idle", "connecting", "connected":
@@ -167,6 +206,10 @@ func parseFilters() error {
 		enableDetailFlagWhenFilterFlag()
 	}
 
+	if probePeersFlag {
+		enableDetailFlagWhenFilterFlag()
+	}
+
 	switch strings.ToLower(connectionTypeFilter) {
 	case "", "p2p", "relayed":
 		if strings.ToLower(connectionTypeFilter) != "" {
@@ -179,6 +222,24 @@ func parseFilters() error {
 	return nil
 }
 
+// peerListOptions passes the peer filters to the daemon, so it only sends the matching peers.
+// The filters are applied by the CLI as well, for daemons that don't support them.
+func peerListOptions() *proto.PeerListOptions {
+	if statusFilter == "" && len(ipsFilter) == 0 && len(prefixNamesFilter) == 0 && connectionTypeFilter == "" {
+		return nil
+	}
+
+	opts := &proto.PeerListOptions{
+		Ips:            ipsFilter,
+		Names:          maps.Keys(prefixNamesFilterMap),
+		ConnectionType: connectionTypeFilter,
+	}
+	if statusFilter != "" {
+		opts.Statuses = []string{statusFilter}
+	}
+	return opts
+}
+
 func enableDetailFlagWhenFilterFlag() {
 	if !detailFlag && !jsonFlag && !yamlFlag {
 		detailFlag = true
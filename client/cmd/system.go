@@ -8,6 +8,8 @@ const (
 	disableFirewallFlag     = "disable-firewall"
 	blockLANAccessFlag      = "block-lan-access"
 	blockInboundFlag        = "block-inbound"
+	killSwitchFlag          = "kill-switch"
+	firewallBackendFlag     = "firewall-backend"
 )
 
 var (
@@ -17,6 +19,8 @@ var (
 	disableFirewall     bool
 	blockLANAccess      bool
 	blockInbound        bool
+	killSwitch          bool
+	firewallBackend     string
 )
 
 func init() {
@@ -39,4 +43,12 @@ func init() {
 	upCmd.PersistentFlags().BoolVar(&blockInbound, blockInboundFlag, false,
 		"Block inbound connections. If enabled, the client will not allow any inbound connections to the local machine nor routed networks.\n"+
 			"This overrides any policies received from the management service.")
+
+	upCmd.PersistentFlags().BoolVar(&killSwitch, killSwitchFlag, false,
+		"Block all traffic that doesn't go through the tunnel, except what is needed to connect to NetBird. "+
+			"The block stays in place while the client is down, until the kill switch is disabled.")
+
+	upCmd.PersistentFlags().StringVar(&firewallBackend, firewallBackendFlag, "",
+		"Select the firewall implementation. Possible values: auto, iptables, nftables, userspace, none. "+
+			"Default is auto, which detects the firewall of the system. The status shows which backend is in use and why.")
 }
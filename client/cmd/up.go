@@ -173,7 +173,7 @@ func runInForegroundMode(ctx context.Context, cmd *cobra.Command, activeProf *pr
 		return fmt.Errorf("setup config: %v", err)
 	}
 
-	providedSetupKey, err := getSetupKey()
+	providedSetupKey, err := getSetupKey(ctx)
 	if err != nil {
 		return err
 	}
@@ -217,7 +217,7 @@ func runInDaemonMode(ctx context.Context, cmd *cobra.Command, pm *profilemanager
 	conn, err := DialClientGRPCServer(ctx, daemonAddr)
 	if err != nil {
 		//nolint
-		return fmt.Errorf("failed to connect to daemon error: %v\n"+
+		return fmt.Errorf("failed to connect to daemon error: %w\n"+
 			"If the daemon is not running please run: "+
 			"\nnetbird service install \nnetbird service start\n", err)
 	}
@@ -235,7 +235,7 @@ func runInDaemonMode(ctx context.Context, cmd *cobra.Command, pm *profilemanager
 		WaitForReady: func() *bool { b := true; return &b }(),
 	})
 	if err != nil {
-		return fmt.Errorf("unable to get daemon status: %v", err)
+		return fmt.Errorf("unable to get daemon status: %w", err)
 	}
 
 	if status.Status == string(internal.StatusConnected) {
@@ -266,7 +266,7 @@ func runInDaemonMode(ctx context.Context, cmd *cobra.Command, pm *profilemanager
 	}
 
 	if err := doDaemonUp(ctx, cmd, client, pm, activeProf, customDNSAddressConverted, username.Username); err != nil {
-		return fmt.Errorf("daemon up failed: %v", err)
+		return fmt.Errorf("daemon up failed: %w", err)
 	}
 	cmd.Println("Connected")
 	return nil
@@ -274,7 +274,7 @@ func runInDaemonMode(ctx context.Context, cmd *cobra.Command, pm *profilemanager
 
 func doDaemonUp(ctx context.Context, cmd *cobra.Command, client proto.DaemonServiceClient, pm *profilemanager.ProfileManager, activeProf *profilemanager.Profile, customDNSAddressConverted []byte, username string) error {
 
-	providedSetupKey, err := getSetupKey()
+	providedSetupKey, err := getSetupKey(ctx)
 	if err != nil {
 		return fmt.Errorf("get setup key: %v", err)
 	}
@@ -314,7 +314,7 @@ func doDaemonUp(ctx context.Context, cmd *cobra.Command, client proto.DaemonServ
 	}
 
 	if loginErr != nil {
-		return fmt.Errorf("login failed: %v", loginErr)
+		return fmt.Errorf("login failed: %w", loginErr)
 	}
 
 	if loginResp.NeedsSSOLogin {
@@ -327,7 +327,7 @@ func doDaemonUp(ctx context.Context, cmd *cobra.Command, client proto.DaemonServ
 		ProfileName: &activeProf.Name,
 		Username:    &username,
 	}); err != nil {
-		return fmt.Errorf("call service up method: %v", err)
+		return fmt.Errorf("call service up method: %w", err)
 	}
 
 	return nil
@@ -430,10 +430,34 @@ func setupSetConfigReq(customDNSAddressConverted []byte, cmd *cobra.Command, pro
 		req.BlockInbound = &blockInbound
 	}
 
+	if cmd.Flag(killSwitchFlag).Changed {
+		req.KillSwitch = &killSwitch
+	}
+
+	if cmd.Flag(socks5ProxyAddressFlag).Changed {
+		req.Socks5ProxyAddress = &socks5ProxyAddress
+	}
+
+	if cmd.Flag(httpProxyAddressFlag).Changed {
+		req.HttpProxyAddress = &httpProxyAddress
+	}
+
 	if cmd.Flag(enableLazyConnectionFlag).Changed {
 		req.LazyConnectionEnabled = &lazyConnEnabled
 	}
 
+	if cmd.Flag(enableICEMDNSFlag).Changed {
+		req.IceMulticastDNS = &iceMulticastDNS
+	}
+
+	if cmd.Flag(icePolicyFlag).Changed {
+		req.IcePolicy = &icePolicy
+	}
+
+	if cmd.Flag(firewallBackendFlag).Changed {
+		req.FirewallBackend = &firewallBackend
+	}
+
 	return &req
 }
 
@@ -547,9 +571,33 @@ func setupConfig(customDNSAddressConverted []byte, cmd *cobra.Command, configFil
 		ic.BlockInbound = &blockInbound
 	}
 
+	if cmd.Flag(killSwitchFlag).Changed {
+		ic.KillSwitch = &killSwitch
+	}
+
+	if cmd.Flag(socks5ProxyAddressFlag).Changed {
+		ic.SOCKS5ProxyAddress = &socks5ProxyAddress
+	}
+
+	if cmd.Flag(httpProxyAddressFlag).Changed {
+		ic.HTTPProxyAddress = &httpProxyAddress
+	}
+
 	if cmd.Flag(enableLazyConnectionFlag).Changed {
 		ic.LazyConnectionEnabled = &lazyConnEnabled
 	}
+
+	if cmd.Flag(enableICEMDNSFlag).Changed {
+		ic.ICEMulticastDNS = &iceMulticastDNS
+	}
+
+	if cmd.Flag(icePolicyFlag).Changed {
+		ic.ICEPolicy = &icePolicy
+	}
+
+	if cmd.Flag(firewallBackendFlag).Changed {
+		ic.FirewallBackend = &firewallBackend
+	}
 	return &ic, nil
 }
 
@@ -661,9 +709,33 @@ func setupLoginRequest(providedSetupKey string, customDNSAddressConverted []byte
 		loginRequest.BlockInbound = &blockInbound
 	}
 
+	if cmd.Flag(killSwitchFlag).Changed {
+		loginRequest.KillSwitch = &killSwitch
+	}
+
+	if cmd.Flag(socks5ProxyAddressFlag).Changed {
+		loginRequest.Socks5ProxyAddress = &socks5ProxyAddress
+	}
+
+	if cmd.Flag(httpProxyAddressFlag).Changed {
+		loginRequest.HttpProxyAddress = &httpProxyAddress
+	}
+
 	if cmd.Flag(enableLazyConnectionFlag).Changed {
 		loginRequest.LazyConnectionEnabled = &lazyConnEnabled
 	}
+
+	if cmd.Flag(enableICEMDNSFlag).Changed {
+		loginRequest.IceMulticastDNS = &iceMulticastDNS
+	}
+
+	if cmd.Flag(icePolicyFlag).Changed {
+		loginRequest.IcePolicy = &icePolicy
+	}
+
+	if cmd.Flag(firewallBackendFlag).Changed {
+		loginRequest.FirewallBackend = &firewallBackend
+	}
 	return &loginRequest, nil
 }
 
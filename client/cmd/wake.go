@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var (
+	wakeMAC string
+	wakeVia string
+)
+
+var wakeCmd = &cobra.Command{
+	Use:   "wake <peer>",
+	Short: "Wake up a sleeping machine with Wake-on-LAN",
+	Long: "Ask a routing peer on the site of a sleeping machine to send a Wake-on-LAN magic packet to its local network.\n" +
+		"The hardware address and the address in the local network come from management or the WakeOnLAN entries of the config.\n" +
+		"The routing peer is the peer routing a network that contains the local address of the machine, unless it is set with --via.",
+	Example: "  netbird wake desktop\n  netbird wake nas --mac 00:11:22:33:44:55 --via office-router",
+	Args:    cobra.ExactArgs(1),
+	RunE:    wake,
+}
+
+func init() {
+	wakeCmd.Flags().StringVar(&wakeMAC, "mac", "", "Hardware address of the machine, overrides the configured one")
+	wakeCmd.Flags().StringVar(&wakeVia, "via", "", "Routing peer that sends the magic packet")
+}
+
+func wake(cmd *cobra.Command, args []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.Wake(cmd.Context(), &proto.WakeRequest{
+		Target:     args[0],
+		MacAddress: wakeMAC,
+		Via:        wakeVia,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to wake %s: %v", args[0], status.Convert(err).Message())
+	}
+
+	cmd.Printf("Sent wake request for %s (%s) via %s\n", resp.GetTarget(), resp.GetMacAddress(), resp.GetRelay())
+	return nil
+}
@@ -0,0 +1,52 @@
+package firewall
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var errUserspaceUnavailable = errors.New("the userspace firewall requires the userspace WireGuard implementation")
+
+// Backend names a firewall implementation
+type Backend string
+
+const (
+	// BackendAuto detects the firewall implementation from the system
+	BackendAuto Backend = "auto"
+	// BackendIPTables manages the rules with iptables
+	BackendIPTables Backend = "iptables"
+	// BackendNFTables manages the rules with nftables
+	BackendNFTables Backend = "nftables"
+	// BackendUserspace filters the packets in the userspace WireGuard device
+	BackendUserspace Backend = "userspace"
+	// BackendNone runs without a firewall, the traffic of the peers isn't filtered
+	BackendNone Backend = "none"
+)
+
+// ParseBackend returns the backend with the given name, an empty name selects BackendAuto
+func ParseBackend(name string) (Backend, error) {
+	switch backend := Backend(strings.ToLower(strings.TrimSpace(name))); backend {
+	case "":
+		return BackendAuto, nil
+	case BackendAuto, BackendIPTables, BackendNFTables, BackendUserspace, BackendNone:
+		return backend, nil
+	default:
+		return "", fmt.Errorf("unknown firewall backend %q, valid values are auto, iptables, nftables, userspace and none", name)
+	}
+}
+
+// Selection tells which firewall backend is in use and why it was chosen
+type Selection struct {
+	Backend Backend
+	// Native is the kernel firewall the userspace firewall uses for the routed traffic, if any
+	Native Backend
+	Reason string
+}
+
+func (s Selection) String() string {
+	if s.Native != "" {
+		return fmt.Sprintf("%s with %s (%s)", s.Backend, s.Native, s.Reason)
+	}
+	return fmt.Sprintf("%s (%s)", s.Backend, s.Reason)
+}
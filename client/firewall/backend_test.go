@@ -0,0 +1,32 @@
+package firewall
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBackend(t *testing.T) {
+	for name, expected := range map[string]Backend{
+		"":           BackendAuto,
+		"auto":       BackendAuto,
+		"iptables":   BackendIPTables,
+		" NFTables ": BackendNFTables,
+		"userspace":  BackendUserspace,
+		"none":       BackendNone,
+	} {
+		backend, err := ParseBackend(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, expected, backend, name)
+	}
+
+	_, err := ParseBackend("pf")
+	assert.Error(t, err)
+}
+
+func TestSelection_String(t *testing.T) {
+	assert.Equal(t, "nftables (configured)", Selection{Backend: BackendNFTables, Reason: "configured"}.String())
+	assert.Equal(t, "userspace with iptables (userspace WireGuard)",
+		Selection{Backend: BackendUserspace, Native: BackendIPTables, Reason: "userspace WireGuard"}.String())
+}
@@ -14,20 +14,34 @@ import (
 	"github.com/netbirdio/netbird/client/internal/statemanager"
 )
 
-// NewFirewall creates a firewall manager instance
-func NewFirewall(iface IFaceMapper, _ *statemanager.Manager, flowLogger nftypes.FlowLogger, disableServerRoutes bool, mtu uint16) (firewall.Manager, error) {
+// NewFirewall creates a firewall manager instance. Only the userspace firewall is available on this OS.
+func NewFirewall(iface IFaceMapper, _ *statemanager.Manager, flowLogger nftypes.FlowLogger, disableServerRoutes bool, mtu uint16, backend Backend) (firewall.Manager, Selection, error) {
+	reason := "configured"
+	switch backend {
+	case BackendNone:
+		return nil, Selection{Backend: BackendNone, Reason: reason}, nil
+	case BackendIPTables, BackendNFTables:
+		return nil, Selection{Backend: BackendNone, Reason: fmt.Sprintf("configured %s is unavailable on %s", backend, runtime.GOOS)},
+			fmt.Errorf("%s firewall is not supported on %s", backend, runtime.GOOS)
+	case "", BackendAuto:
+		reason = fmt.Sprintf("auto-detected: the only firewall on %s", runtime.GOOS)
+	}
+
 	if !iface.IsUserspaceBind() {
-		return nil, fmt.Errorf("not implemented for this OS: %s", runtime.GOOS)
+		if backend == BackendUserspace {
+			return nil, Selection{Backend: BackendNone, Reason: "userspace firewall requires userspace WireGuard"}, errUserspaceUnavailable
+		}
+		return nil, Selection{Backend: BackendNone, Reason: "kernel WireGuard"}, fmt.Errorf("not implemented for this OS: %s", runtime.GOOS)
 	}
 
 	// use userspace packet filtering firewall
 	fm, err := uspfilter.Create(iface, disableServerRoutes, flowLogger, mtu)
 	if err != nil {
-		return nil, err
+		return nil, Selection{Backend: BackendNone, Reason: reason}, err
 	}
 	err = fm.AllowNetbird()
 	if err != nil {
 		log.Warnf("failed to allow netbird interface traffic: %v", err)
 	}
-	return fm, nil
+	return fm, Selection{Backend: BackendUserspace, Reason: reason}, nil
 }
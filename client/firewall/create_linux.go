@@ -34,25 +34,88 @@ const SKIP_NFTABLES_ENV = "NB_SKIP_NFTABLES_CHECK"
 // FWType is the type for the firewall type
 type FWType int
 
-func NewFirewall(iface IFaceMapper, stateManager *statemanager.Manager, flowLogger nftypes.FlowLogger, disableServerRoutes bool, mtu uint16) (firewall.Manager, error) {
+func (t FWType) backend() Backend {
+	switch t {
+	case IPTABLES:
+		return BackendIPTables
+	case NFTABLES:
+		return BackendNFTables
+	default:
+		return BackendNone
+	}
+}
+
+// NewFirewall creates the firewall manager of the given backend. BackendAuto detects the kernel firewall, the userspace
+// firewall filters the packets whenever the WireGuard interface runs in userspace and uses the kernel firewall for the
+// routed traffic. The selection tells which backend was chosen and why, also when creating it failed.
+func NewFirewall(iface IFaceMapper, stateManager *statemanager.Manager, flowLogger nftypes.FlowLogger, disableServerRoutes bool, mtu uint16, backend Backend) (firewall.Manager, Selection, error) {
+	if backend == "" {
+		backend = BackendAuto
+	}
+
+	switch backend {
+	case BackendNone:
+		return nil, Selection{Backend: BackendNone, Reason: "configured"}, nil
+	case BackendUserspace:
+		if !iface.IsUserspaceBind() {
+			return nil, Selection{Backend: BackendNone, Reason: "userspace firewall requires userspace WireGuard"}, errUserspaceUnavailable
+		}
+		fm, err := createUserspaceFirewall(iface, nil, disableServerRoutes, flowLogger, mtu)
+		return fm, Selection{Backend: BackendUserspace, Reason: "configured"}, err
+	}
+
 	// on the linux system we try to user nftables or iptables
 	// in any case, because we need to allow netbird interface traffic
 	// so we use AllowNetbird traffic from these firewall managers
 	// for the userspace packet filtering firewall
-	fm, err := createNativeFirewall(iface, stateManager, disableServerRoutes, mtu)
+	fwType, reason := selectNative(backend)
+	fm, err := createNativeFirewall(iface, stateManager, fwType, mtu)
 
 	if !iface.IsUserspaceBind() {
-		return fm, err
+		if err != nil {
+			return nil, Selection{Backend: BackendNone, Reason: reason}, err
+		}
+		return fm, Selection{Backend: fwType.backend(), Reason: reason}, nil
 	}
 
+	selection := Selection{Backend: BackendUserspace, Reason: "userspace WireGuard"}
 	if err != nil {
 		log.Warnf("failed to create native firewall: %v. Proceeding with userspace", err)
+		selection.Reason = fmt.Sprintf("userspace WireGuard, no kernel firewall: %s", reason)
+	} else {
+		selection.Native = fwType.backend()
+		selection.Reason = fmt.Sprintf("userspace WireGuard, %s", reason)
+	}
+
+	fm, err = createUserspaceFirewall(iface, fm, disableServerRoutes, flowLogger, mtu)
+	if err != nil {
+		return nil, Selection{Backend: BackendNone, Reason: selection.Reason}, err
+	}
+	return fm, selection, nil
+}
+
+// selectNative returns the kernel firewall for the backend and why it was chosen. Configured backends are probed, so
+// a missing one fails instead of falling back to the other.
+func selectNative(backend Backend) (FWType, string) {
+	switch backend {
+	case BackendIPTables:
+		if err := probeIPTables(); err != nil {
+			return UNKNOWN, fmt.Sprintf("configured iptables is unavailable: %v", err)
+		}
+		return IPTABLES, "configured"
+	case BackendNFTables:
+		if err := probeNFTables(); err != nil {
+			return UNKNOWN, fmt.Sprintf("configured nftables is unavailable: %v", err)
+		}
+		return NFTABLES, "configured"
+	default:
+		fwType, reason := check()
+		return fwType, "auto-detected: " + reason
 	}
-	return createUserspaceFirewall(iface, fm, disableServerRoutes, flowLogger, mtu)
 }
 
-func createNativeFirewall(iface IFaceMapper, stateManager *statemanager.Manager, routes bool, mtu uint16) (firewall.Manager, error) {
-	fm, err := createFW(iface, mtu)
+func createNativeFirewall(iface IFaceMapper, stateManager *statemanager.Manager, fwType FWType, mtu uint16) (firewall.Manager, error) {
+	fm, err := createFW(iface, fwType, mtu)
 	if err != nil {
 		return nil, fmt.Errorf("create firewall: %s", err)
 	}
@@ -64,8 +127,8 @@ func createNativeFirewall(iface IFaceMapper, stateManager *statemanager.Manager,
 	return fm, nil
 }
 
-func createFW(iface IFaceMapper, mtu uint16) (firewall.Manager, error) {
-	switch check() {
+func createFW(iface IFaceMapper, fwType FWType, mtu uint16) (firewall.Manager, error) {
+	switch fwType {
 	case IPTABLES:
 		log.Info("creating an iptables firewall manager")
 		return nbiptables.Create(iface, mtu)
@@ -96,8 +159,9 @@ func createUserspaceFirewall(iface IFaceMapper, fm firewall.Manager, disableServ
 	return fm, nil
 }
 
-// check returns the firewall type based on common lib checks. It returns UNKNOWN if no firewall is found.
-func check() FWType {
+// check returns the firewall type based on common lib checks and why it was chosen. It returns UNKNOWN if no firewall
+// is found.
+func check() (FWType, string) {
 	useIPTABLES := false
 	var iptablesChains []string
 	ip, err := iptables.NewWithProtocol(iptables.ProtocolIPv4)
@@ -105,7 +169,7 @@ func check() FWType {
 		major, minor, _ := ip.GetIptablesVersion()
 		// use iptables when its version is lower than 1.8.0 which doesn't work well with our nftables manager
 		if major < 1 || (major == 1 && minor < 8) {
-			return IPTABLES
+			return IPTABLES, fmt.Sprintf("iptables %d.%d is older than 1.8", major, minor)
 		}
 
 		useIPTABLES = true
@@ -117,17 +181,24 @@ func check() FWType {
 		}
 	}
 
+	if os.Getenv(SKIP_NFTABLES_ENV) == "true" {
+		if useIPTABLES {
+			return IPTABLES, fmt.Sprintf("nftables check skipped by %s", SKIP_NFTABLES_ENV)
+		}
+		return UNKNOWN, fmt.Sprintf("iptables is unavailable and the nftables check is skipped by %s", SKIP_NFTABLES_ENV)
+	}
+
 	nf := nftables.Conn{}
-	if chains, err := nf.ListChains(); err == nil && os.Getenv(SKIP_NFTABLES_ENV) != "true" {
+	if chains, err := nf.ListChains(); err == nil {
 		if !useIPTABLES {
-			return NFTABLES
+			return NFTABLES, "iptables is unavailable"
 		}
 
 		// search for chains where table is filter
 		// if we find one, we assume that nftables manager can be used with iptables
 		for _, chain := range chains {
 			if chain.Table.Name == "filter" {
-				return NFTABLES
+				return NFTABLES, "the filter table has nftables chains"
 			}
 		}
 
@@ -139,24 +210,43 @@ func check() FWType {
 		nbTablesList, err := nf.ListTables()
 		switch {
 		case err == nil && len(iptablesChains) > 0:
-			return IPTABLES
+			return IPTABLES, "the iptables filter chains are not managed by nftables"
 		case err == nil && len(nbTablesList) != 1:
-			return NFTABLES
+			return NFTABLES, "nftables is available"
 		case err == nil && len(nbTablesList) == 1 && nbTablesList[0].Name == "filter":
-			return IPTABLES
+			return IPTABLES, "the only nftables table is the filter table of iptables"
 		case err != nil:
 			log.Errorf("failed to list nftables tables on fw manager discovery: %s", err)
 		}
 	}
 
 	if useIPTABLES {
-		return IPTABLES
+		return IPTABLES, "nftables is unavailable"
 	}
 
-	return UNKNOWN
+	return UNKNOWN, "neither iptables nor nftables is available"
 }
 
 func isIptablesClientAvailable(client *iptables.IPTables) bool {
 	_, err := client.ListChains("filter")
 	return err == nil
 }
+
+func probeIPTables() error {
+	ip, err := iptables.NewWithProtocol(iptables.ProtocolIPv4)
+	if err != nil {
+		return err
+	}
+	if _, err := ip.ListChains("filter"); err != nil {
+		return fmt.Errorf("list filter chains: %w", err)
+	}
+	return nil
+}
+
+func probeNFTables() error {
+	nf := nftables.Conn{}
+	if _, err := nf.ListChains(); err != nil {
+		return fmt.Errorf("list chains: %w", err)
+	}
+	return nil
+}
@@ -405,10 +405,13 @@ func filterRuleSpecs(ip net.IP, protocol string, sPort, dPort *firewall.Port, ac
 }
 
 func actionToStr(action firewall.Action) string {
-	if action == firewall.ActionAccept {
+	switch action {
+	case firewall.ActionAccept, firewall.ActionAudit:
+		// audit rules accept the traffic, their counters tell what the rule would drop
 		return "ACCEPT"
+	default:
+		return "DROP"
 	}
-	return "DROP"
 }
 
 func transformIPsetName(ipsetName string, sPort, dPort *firewall.Port, action firewall.Action) string {
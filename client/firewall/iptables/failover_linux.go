@@ -0,0 +1,70 @@
+package iptables
+
+import (
+	"fmt"
+	"net/netip"
+
+	log "github.com/sirupsen/logrus"
+
+	firewall "github.com/netbirdio/netbird/client/firewall/manager"
+	nbid "github.com/netbirdio/netbird/client/internal/acl/id"
+)
+
+const failoverPrefix = "failover-"
+
+// AllowRouteFailover accepts traffic from the network to the peer regardless of the connection state
+func (m *Manager) AllowRouteFailover(peer netip.Addr, network netip.Prefix) (firewall.Rule, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.router.AddRouteFailover(peer, network)
+}
+
+// RemoveRouteFailover removes a rule added by AllowRouteFailover
+func (m *Manager) RemoveRouteFailover(rule firewall.Rule) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.router.DeleteRouteFailover(rule)
+}
+
+// AddRouteFailover inserts an accept rule for the network and the peer in front of the
+// established rule of the outbound routing chain
+func (r *router) AddRouteFailover(peer netip.Addr, network netip.Prefix) (firewall.Rule, error) {
+	if !peer.Is4() || !network.Addr().Is4() {
+		return nil, fmt.Errorf("failover rule for %s to %s: only ipv4 is supported", network, peer)
+	}
+
+	ruleKey := fmt.Sprintf("%s%s-%s", failoverPrefix, peer, network.Masked())
+	if _, exists := r.rules[ruleKey]; exists {
+		return nbid.RuleID(ruleKey), nil
+	}
+
+	rule := []string{"-s", network.Masked().String(), "-d", peer.String(), "-j", "ACCEPT"}
+	if err := r.iptablesClient.Insert(tableFilter, chainRTFWDOUT, 1, rule...); err != nil {
+		return nil, fmt.Errorf("add failover rule: %w", err)
+	}
+	r.rules[ruleKey] = rule
+
+	r.updateState()
+	return nbid.RuleID(ruleKey), nil
+}
+
+// DeleteRouteFailover removes a failover rule
+func (r *router) DeleteRouteFailover(rule firewall.Rule) error {
+	ruleKey := rule.ID()
+
+	spec, exists := r.rules[ruleKey]
+	if !exists {
+		log.Debugf("failover rule %s not found", ruleKey)
+		return nil
+	}
+
+	if err := r.iptablesClient.DeleteIfExists(tableFilter, chainRTFWDOUT, spec...); err != nil {
+		return fmt.Errorf("delete failover rule: %w", err)
+	}
+	delete(r.rules, ruleKey)
+
+	r.updateState()
+	return nil
+}
@@ -0,0 +1,158 @@
+package iptables
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/coreos/go-iptables/iptables"
+	"github.com/hashicorp/go-multierror"
+	log "github.com/sirupsen/logrus"
+
+	nberrors "github.com/netbirdio/netbird/client/errors"
+	firewall "github.com/netbirdio/netbird/client/firewall/manager"
+)
+
+const (
+	// chainNameKillSwitch is not touched by Close, so the kill switch survives engine restarts
+	chainNameKillSwitch = "NETBIRD-KILLSWITCH"
+	chainOutput         = "OUTPUT"
+)
+
+// EnableKillSwitch drops all egress traffic except through the netbird interface and the given rules.
+// The drop rule is installed first, so replacing the rules never lets traffic pass.
+func (m *Manager) EnableKillSwitch(rules []firewall.KillSwitchRule) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("invalid kill switch rule %s: %w", rule, err)
+		}
+	}
+
+	var merr *multierror.Error
+	for _, proto := range []iptables.Protocol{iptables.ProtocolIPv4, iptables.ProtocolIPv6} {
+		if err := m.enableKillSwitch(proto, rules); err != nil {
+			merr = multierror.Append(merr, err)
+		}
+	}
+
+	if err := nberrors.FormatErrorOrNil(merr); err != nil {
+		return err
+	}
+
+	log.Infof("kill switch enabled with %d exceptions", len(rules))
+	return nil
+}
+
+// DisableKillSwitch removes the kill switch chains
+func (m *Manager) DisableKillSwitch() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var merr *multierror.Error
+	for _, proto := range []iptables.Protocol{iptables.ProtocolIPv4, iptables.ProtocolIPv6} {
+		if err := disableKillSwitch(proto); err != nil {
+			merr = multierror.Append(merr, err)
+		}
+	}
+
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+func (m *Manager) enableKillSwitch(proto iptables.Protocol, rules []firewall.KillSwitchRule) error {
+	ipt, err := iptables.NewWithProtocol(proto)
+	if err != nil {
+		return fmt.Errorf("init iptables for protocol %v: %w", proto, err)
+	}
+
+	exists, err := ipt.ChainExists(tableFilter, chainNameKillSwitch)
+	if err != nil {
+		return fmt.Errorf("check chain %s: %w", chainNameKillSwitch, err)
+	}
+
+	if !exists {
+		if err := ipt.NewChain(tableFilter, chainNameKillSwitch); err != nil {
+			return fmt.Errorf("create chain %s: %w", chainNameKillSwitch, err)
+		}
+	}
+
+	// drop first: the chain is only emptied after the new drop rule is in place
+	oldRules, err := ipt.List(tableFilter, chainNameKillSwitch)
+	if err != nil {
+		return fmt.Errorf("list chain %s: %w", chainNameKillSwitch, err)
+	}
+	if err := ipt.Insert(tableFilter, chainNameKillSwitch, 1, "-j", "DROP"); err != nil {
+		return fmt.Errorf("add drop rule: %w", err)
+	}
+	// the first entry of the listing is the chain definition
+	for i := 1; i < len(oldRules); i++ {
+		if err := ipt.Delete(tableFilter, chainNameKillSwitch, "2"); err != nil {
+			return fmt.Errorf("remove old rule: %w", err)
+		}
+	}
+
+	accepts := [][]string{
+		{"-o", "lo", "-j", "ACCEPT"},
+		{"-o", m.wgIface.Name(), "-j", "ACCEPT"},
+	}
+	for _, rule := range rules {
+		if rule.Destination.IsValid() && rule.Destination.Addr().Is4() != (proto == iptables.ProtocolIPv4) {
+			continue
+		}
+		accepts = append(accepts, killSwitchRuleSpec(rule))
+	}
+
+	for i, spec := range accepts {
+		if err := ipt.Insert(tableFilter, chainNameKillSwitch, i+1, spec...); err != nil {
+			return fmt.Errorf("add rule %v: %w", spec, err)
+		}
+	}
+
+	if err := ipt.InsertUnique(tableFilter, chainOutput, 1, "-j", chainNameKillSwitch); err != nil {
+		return fmt.Errorf("add jump rule: %w", err)
+	}
+
+	return nil
+}
+
+func disableKillSwitch(proto iptables.Protocol) error {
+	ipt, err := iptables.NewWithProtocol(proto)
+	if err != nil {
+		return fmt.Errorf("init iptables for protocol %v: %w", proto, err)
+	}
+
+	if err := ipt.DeleteIfExists(tableFilter, chainOutput, "-j", chainNameKillSwitch); err != nil {
+		return fmt.Errorf("remove jump rule: %w", err)
+	}
+
+	exists, err := ipt.ChainExists(tableFilter, chainNameKillSwitch)
+	if err != nil {
+		return fmt.Errorf("check chain %s: %w", chainNameKillSwitch, err)
+	}
+	if !exists {
+		return nil
+	}
+
+	if err := ipt.ClearAndDeleteChain(tableFilter, chainNameKillSwitch); err != nil {
+		return fmt.Errorf("remove chain %s: %w", chainNameKillSwitch, err)
+	}
+	return nil
+}
+
+func killSwitchRuleSpec(rule firewall.KillSwitchRule) []string {
+	var spec []string
+	if rule.Destination.IsValid() {
+		spec = append(spec, "-d", rule.Destination.String())
+	}
+	if rule.Protocol == firewall.ProtocolTCP || rule.Protocol == firewall.ProtocolUDP {
+		spec = append(spec, "-p", string(rule.Protocol))
+	}
+	if rule.SrcPort != 0 {
+		spec = append(spec, "--sport", strconv.Itoa(int(rule.SrcPort)))
+	}
+	if rule.DstPort != 0 {
+		spec = append(spec, "--dport", strconv.Itoa(int(rule.DstPort)))
+	}
+	return append(spec, "-j", "ACCEPT")
+}
@@ -0,0 +1,191 @@
+package iptables
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	ipset "github.com/lrh3321/ipset-go"
+	log "github.com/sirupsen/logrus"
+
+	firewall "github.com/netbirdio/netbird/client/firewall/manager"
+)
+
+const (
+	chainRTRPF        = "NETBIRD-RT-RPF"
+	jumpRPF           = "jump-rpf"
+	setNameRPFOverlay = "netbird-rpf-overlay"
+	setNameRPFLocal   = "netbird-rpf-local"
+	// rpfSetSuffix names the sets the new networks are filled into before they are swapped in
+	rpfSetSuffix = "-new"
+)
+
+// SetReversePathFilter drops the routed traffic with sources outside the overlay and local networks
+func (m *Manager) SetReversePathFilter(overlay, local []netip.Prefix) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.router.SetReversePathFilter(overlay, local)
+}
+
+// RemoveReversePathFilter removes the reverse path filter
+func (m *Manager) RemoveReversePathFilter() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.router.RemoveReversePathFilter()
+}
+
+// ReversePathStats returns the counters of the packets dropped by the reverse path filter
+func (m *Manager) ReversePathStats() ([]firewall.RouteRuleStats, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.router.ReversePathStats()
+}
+
+// SetReversePathFilter fills the ipsets of the networks and installs the filter chain in front of the FORWARD
+// chain. The ipsets of an installed filter are replaced by swapping in the new ones, so the sources that stay
+// valid are never dropped.
+func (r *router) SetReversePathFilter(overlay, local []netip.Prefix) error {
+	for name, networks := range map[string][]netip.Prefix{setNameRPFOverlay: overlay, setNameRPFLocal: local} {
+		if err := r.replaceRPFSet(name, firewall.ReversePathNetworks(networks)); err != nil {
+			return err
+		}
+	}
+
+	if _, exists := r.rules[jumpRPF]; exists {
+		log.Debugf("updated reverse path filter with overlay networks %v and local networks %v", overlay, local)
+		return nil
+	}
+
+	if err := r.iptablesClient.NewChain(tableFilter, chainRTRPF); err != nil {
+		return fmt.Errorf("create chain %s: %w", chainRTRPF, err)
+	}
+
+	intf := r.wgIface.Name()
+	rules := [][]string{
+		{"-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED", "-j", "RETURN"},
+		{"-i", intf, "-m", "set", "!", matchSet, setNameRPFOverlay, "src",
+			"-m", "comment", "--comment", firewall.ReversePathOverlayRuleID, "-j", "DROP"},
+		{"!", "-i", intf, "-o", intf, "-m", "set", "!", matchSet, setNameRPFLocal, "src",
+			"-m", "comment", "--comment", firewall.ReversePathLocalRuleID, "-j", "DROP"},
+	}
+	for _, rule := range rules {
+		if err := r.iptablesClient.Append(tableFilter, chainRTRPF, rule...); err != nil {
+			return fmt.Errorf("add reverse path rule: %w", err)
+		}
+	}
+
+	jumpRule := []string{"-j", chainRTRPF}
+	if err := r.iptablesClient.Insert(tableFilter, chainFORWARD, 1, jumpRule...); err != nil {
+		return fmt.Errorf("add reverse path jump rule: %w", err)
+	}
+	r.rules[jumpRPF] = jumpRule
+
+	r.updateState()
+	log.Infof("reverse path filter enabled for %d overlay and %d local networks", len(overlay), len(local))
+	return nil
+}
+
+// RemoveReversePathFilter removes the filter chain and its ipsets
+func (r *router) RemoveReversePathFilter() error {
+	if err := r.removeReversePathFilter(); err != nil {
+		return err
+	}
+
+	r.updateState()
+	return nil
+}
+
+func (r *router) removeReversePathFilter() error {
+	if rule, exists := r.rules[jumpRPF]; exists {
+		if err := r.iptablesClient.DeleteIfExists(tableFilter, chainFORWARD, rule...); err != nil {
+			return fmt.Errorf("remove reverse path jump rule: %w", err)
+		}
+		delete(r.rules, jumpRPF)
+	}
+
+	exists, err := r.iptablesClient.ChainExists(tableFilter, chainRTRPF)
+	if err != nil {
+		return fmt.Errorf("check chain %s: %w", chainRTRPF, err)
+	}
+	if exists {
+		if err := r.iptablesClient.ClearAndDeleteChain(tableFilter, chainRTRPF); err != nil {
+			return fmt.Errorf("remove chain %s: %w", chainRTRPF, err)
+		}
+	}
+
+	for _, name := range []string{setNameRPFOverlay, setNameRPFLocal} {
+		if err := ipset.Destroy(name); err != nil && !errors.Is(err, ipset.ErrSetNotExist) {
+			log.Warnf("failed to destroy ipset %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// ReversePathStats reads the counters of the drop rules of the filter chain
+func (r *router) ReversePathStats() ([]firewall.RouteRuleStats, error) {
+	if _, exists := r.rules[jumpRPF]; !exists {
+		return nil, nil
+	}
+
+	rules, err := r.iptablesClient.StructuredStats(tableFilter, chainRTRPF)
+	if err != nil {
+		return nil, fmt.Errorf("get stats of %s: %w", chainRTRPF, err)
+	}
+
+	var stats []firewall.RouteRuleStats
+	for _, rule := range rules {
+		for _, id := range []string{firewall.ReversePathOverlayRuleID, firewall.ReversePathLocalRuleID} {
+			if strings.Contains(rule.Options, "/* "+id+" */") {
+				stats = append(stats, firewall.RouteRuleStats{
+					RuleID:  id,
+					Action:  firewall.ActionDrop,
+					Packets: rule.Packets,
+					Bytes:   rule.Bytes,
+				})
+			}
+		}
+	}
+	return stats, nil
+}
+
+// replaceRPFSet fills a new ipset with the networks and swaps it in for the one of the filter
+func (r *router) replaceRPFSet(name string, networks []netip.Prefix) error {
+	newName := name + rpfSetSuffix
+	if err := r.createIPSet(newName); err != nil {
+		return err
+	}
+	defer func() {
+		if err := ipset.Destroy(newName); err != nil && !errors.Is(err, ipset.ErrSetNotExist) {
+			log.Warnf("failed to destroy ipset %s: %v", newName, err)
+		}
+	}()
+
+	for _, network := range networks {
+		// hash:net sets don't take /0, the two halves of the address space stand in for it
+		halves := []netip.Prefix{network}
+		if network.Bits() == 0 {
+			halves = []netip.Prefix{netip.MustParsePrefix("0.0.0.0/1"), netip.MustParsePrefix("128.0.0.0/1")}
+		}
+		for _, prefix := range halves {
+			if err := r.addPrefixToIPSet(newName, prefix); err != nil {
+				return err
+			}
+		}
+	}
+
+	// the first time the set of the filter doesn't exist yet, the new one is renamed into place
+	if _, err := ipset.List(name); errors.Is(err, ipset.ErrSetNotExist) {
+		if err := ipset.Rename(newName, name); err != nil {
+			return fmt.Errorf("rename ipset %s: %w", newName, err)
+		}
+		return nil
+	}
+	if err := ipset.Swap(newName, name); err != nil {
+		return fmt.Errorf("swap ipset %s: %w", name, err)
+	}
+	return nil
+}
@@ -165,8 +165,8 @@ func (r *router) AddRouteFiltering(
 		return nil, fmt.Errorf("generate route rule spec: %w", err)
 	}
 
-	// Insert DROP rules at the beginning, append ACCEPT rules at the end
-	if action == firewall.ActionDrop {
+	// Insert DROP and AUDIT rules at the beginning, append ACCEPT rules at the end
+	if action == firewall.ActionDrop || action == firewall.ActionAudit {
 		// after the established rule
 		err = r.iptablesClient.Insert(tableFilter, chainRTFWDIN, 2, rule...)
 	} else {
@@ -371,6 +371,10 @@ func (r *router) Reset() error {
 		merr = multierror.Append(merr, err)
 	}
 
+	if err := r.removeReversePathFilter(); err != nil {
+		merr = multierror.Append(merr, err)
+	}
+
 	if err := r.cleanupDataPlaneMark(); err != nil {
 		merr = multierror.Append(merr, err)
 	}
@@ -397,6 +401,7 @@ func (r *router) cleanUpDefaultForwardRules() error {
 		{chainRTNAT, tableNat},
 		{chainRTRDR, tableNat},
 		{chainRTMSSCLAMP, tableMangle},
+		{chainRTRPF, tableFilter},
 	} {
 		ok, err := r.iptablesClient.ChainExists(chainInfo.table, chainInfo.chain)
 		if err != nil {
@@ -597,7 +602,7 @@ func (r *router) addJumpRules() error {
 }
 
 func (r *router) cleanJumpRules() error {
-	for _, ruleKey := range []string{jumpNatPost, jumpManglePre, jumpNatPre, jumpMSSClamp} {
+	for _, ruleKey := range []string{jumpNatPost, jumpManglePre, jumpNatPre, jumpMSSClamp, jumpRPF} {
 		if rule, exists := r.rules[ruleKey]; exists {
 			var table, chain string
 			switch ruleKey {
@@ -613,6 +618,9 @@ func (r *router) cleanJumpRules() error {
 			case jumpMSSClamp:
 				table = tableMangle
 				chain = chainFORWARD
+			case jumpRPF:
+				table = tableFilter
+				chain = chainFORWARD
 			default:
 				return fmt.Errorf("unknown jump rule: %s", ruleKey)
 			}
@@ -734,66 +742,76 @@ func (r *router) AddDNATRule(rule firewall.ForwardRule) (firewall.Rule, error) {
 		return rule, nil
 	}
 
-	toDestination := rule.TranslatedAddress.String()
+	var translatedPort string
 	switch {
 	case len(rule.TranslatedPort.Values) == 0:
 		// no translated port, use original port
 	case len(rule.TranslatedPort.Values) == 1:
-		toDestination += fmt.Sprintf(":%d", rule.TranslatedPort.Values[0])
+		translatedPort = fmt.Sprintf(":%d", rule.TranslatedPort.Values[0])
 	case rule.TranslatedPort.IsRange && len(rule.TranslatedPort.Values) == 2:
 		// need the "/originalport" suffix to avoid dnat port randomization
-		toDestination += fmt.Sprintf(":%d-%d/%d", rule.TranslatedPort.Values[0], rule.TranslatedPort.Values[1], rule.DestinationPort.Values[0])
+		translatedPort = fmt.Sprintf(":%d-%d/%d", rule.TranslatedPort.Values[0], rule.TranslatedPort.Values[1], rule.DestinationPort.Values[0])
 	default:
 		return nil, fmt.Errorf("invalid translated port: %v", rule.TranslatedPort)
 	}
 
 	proto := strings.ToLower(string(rule.Protocol))
 
-	rules := make(map[string]ruleInfo, 3)
+	backends := rule.Backends()
+	rules := make(map[string]ruleInfo, 3*len(backends))
+	// the DNAT rules of the backends are appended in order, the round-robin depends on it
+	keys := make([]string, 0, 3*len(backends))
 
-	// DNAT rule
-	dnatRule := []string{
-		"!", "-i", r.wgIface.Name(),
-		"-p", proto,
-		"-j", "DNAT",
-		"--to-destination", toDestination,
-	}
-	dnatRule = append(dnatRule, applyPort("--dport", &rule.DestinationPort)...)
-	rules[ruleKey+dnatSuffix] = ruleInfo{
-		table: tableNat,
-		chain: chainRTRDR,
-		rule:  dnatRule,
-	}
+	for i, backend := range backends {
+		backendKey := backendRuleKey(ruleKey, i)
 
-	// SNAT rule
-	snatRule := []string{
-		"-o", r.wgIface.Name(),
-		"-p", proto,
-		"-d", rule.TranslatedAddress.String(),
-		"-j", "MASQUERADE",
-	}
-	snatRule = append(snatRule, applyPort("--dport", &rule.TranslatedPort)...)
-	rules[ruleKey+snatSuffix] = ruleInfo{
-		table: tableNat,
-		chain: chainRTNAT,
-		rule:  snatRule,
-	}
+		// DNAT rule
+		dnatRule := []string{
+			"!", "-i", r.wgIface.Name(),
+			"-p", proto,
+		}
+		dnatRule = append(dnatRule, applyPort("--dport", &rule.DestinationPort)...)
+		dnatRule = append(dnatRule, balanceArgs(i, len(backends))...)
+		dnatRule = append(dnatRule, "-j", "DNAT", "--to-destination", backend.String()+translatedPort)
+		rules[backendKey+dnatSuffix] = ruleInfo{
+			table: tableNat,
+			chain: chainRTRDR,
+			rule:  dnatRule,
+		}
 
-	// Forward filtering rule, if fwd policy is DROP
-	forwardRule := []string{
-		"-o", r.wgIface.Name(),
-		"-p", proto,
-		"-d", rule.TranslatedAddress.String(),
-		"-j", "ACCEPT",
-	}
-	forwardRule = append(forwardRule, applyPort("--dport", &rule.TranslatedPort)...)
-	rules[ruleKey+fwdSuffix] = ruleInfo{
-		table: tableFilter,
-		chain: chainRTFWDOUT,
-		rule:  forwardRule,
+		// SNAT rule
+		snatRule := []string{
+			"-o", r.wgIface.Name(),
+			"-p", proto,
+			"-d", backend.String(),
+			"-j", "MASQUERADE",
+		}
+		snatRule = append(snatRule, applyPort("--dport", &rule.TranslatedPort)...)
+		rules[backendKey+snatSuffix] = ruleInfo{
+			table: tableNat,
+			chain: chainRTNAT,
+			rule:  snatRule,
+		}
+
+		// Forward filtering rule, if fwd policy is DROP
+		forwardRule := []string{
+			"-o", r.wgIface.Name(),
+			"-p", proto,
+			"-d", backend.String(),
+			"-j", "ACCEPT",
+		}
+		forwardRule = append(forwardRule, applyPort("--dport", &rule.TranslatedPort)...)
+		rules[backendKey+fwdSuffix] = ruleInfo{
+			table: tableFilter,
+			chain: chainRTFWDOUT,
+			rule:  forwardRule,
+		}
+
+		keys = append(keys, backendKey+dnatSuffix, backendKey+snatSuffix, backendKey+fwdSuffix)
 	}
 
-	for key, ruleInfo := range rules {
+	for _, key := range keys {
+		ruleInfo := rules[key]
 		if err := r.iptablesClient.Append(ruleInfo.table, ruleInfo.chain, ruleInfo.rule...); err != nil {
 			if rollbackErr := r.rollbackRules(rules); rollbackErr != nil {
 				log.Errorf("rollback failed: %v", rollbackErr)
@@ -807,6 +825,24 @@ func (r *router) AddDNATRule(rule firewall.ForwardRule) (firewall.Rule, error) {
 	return rule, nil
 }
 
+// backendRuleKey is the key of the rules of the backend at index, the rules of the first backend use the key of the
+// forward rule
+func backendRuleKey(ruleKey string, index int) string {
+	if index == 0 {
+		return ruleKey
+	}
+	return fmt.Sprintf("%s;%d", ruleKey, index)
+}
+
+// balanceArgs spread the new connections round-robin across the backends. The rule of the backend at index takes
+// every (backends-index)th connection reaching it, the rule of the last backend takes the rest.
+func balanceArgs(index, backends int) []string {
+	if index >= backends-1 {
+		return nil
+	}
+	return []string{"-m", "statistic", "--mode", "nth", "--every", strconv.Itoa(backends - index), "--packet", "0"}
+}
+
 func (r *router) rollbackRules(rules map[string]ruleInfo) error {
 	var merr *multierror.Error
 	for key, ruleInfo := range rules {
@@ -830,29 +866,42 @@ func (r *router) DeleteDNATRule(rule firewall.Rule) error {
 	ruleKey := rule.ID()
 
 	var merr *multierror.Error
-	if dnatRule, exists := r.rules[ruleKey+dnatSuffix]; exists {
+	for i := 0; ; i++ {
+		backendKey := backendRuleKey(ruleKey, i)
+		if _, exists := r.rules[backendKey+dnatSuffix]; !exists {
+			break
+		}
+		merr = multierror.Append(merr, r.deleteBackendRules(backendKey)...)
+	}
+
+	r.updateState()
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+// deleteBackendRules deletes the DNAT, SNAT and forward rules of a backend of a forward rule
+func (r *router) deleteBackendRules(backendKey string) []error {
+	var errs []error
+	if dnatRule, exists := r.rules[backendKey+dnatSuffix]; exists {
 		if err := r.iptablesClient.Delete(tableNat, chainRTRDR, dnatRule...); err != nil {
-			merr = multierror.Append(merr, fmt.Errorf("delete DNAT rule: %w", err))
+			errs = append(errs, fmt.Errorf("delete DNAT rule: %w", err))
 		}
-		delete(r.rules, ruleKey+dnatSuffix)
+		delete(r.rules, backendKey+dnatSuffix)
 	}
 
-	if snatRule, exists := r.rules[ruleKey+snatSuffix]; exists {
+	if snatRule, exists := r.rules[backendKey+snatSuffix]; exists {
 		if err := r.iptablesClient.Delete(tableNat, chainRTNAT, snatRule...); err != nil {
-			merr = multierror.Append(merr, fmt.Errorf("delete SNAT rule: %w", err))
+			errs = append(errs, fmt.Errorf("delete SNAT rule: %w", err))
 		}
-		delete(r.rules, ruleKey+snatSuffix)
+		delete(r.rules, backendKey+snatSuffix)
 	}
 
-	if fwdRule, exists := r.rules[ruleKey+fwdSuffix]; exists {
-		if err := r.iptablesClient.Delete(tableFilter, chainRTFWDIN, fwdRule...); err != nil {
-			merr = multierror.Append(merr, fmt.Errorf("delete forward rule: %w", err))
+	if fwdRule, exists := r.rules[backendKey+fwdSuffix]; exists {
+		if err := r.iptablesClient.Delete(tableFilter, chainRTFWDOUT, fwdRule...); err != nil {
+			errs = append(errs, fmt.Errorf("delete forward rule: %w", err))
 		}
-		delete(r.rules, ruleKey+fwdSuffix)
+		delete(r.rules, backendKey+fwdSuffix)
 	}
-
-	r.updateState()
-	return nberrors.FormatErrorOrNil(merr)
+	return errs
 }
 
 func (r *router) genRouteRuleSpec(params routeFilteringRuleParams, sources []netip.Prefix) ([]string, error) {
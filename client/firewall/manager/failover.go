@@ -0,0 +1,18 @@
+package manager
+
+import (
+	"net/netip"
+)
+
+// RouteFailover is implemented by firewall managers that can accept routed connections that were established
+// through another routing peer of the same network.
+// After a failover the connection tracking of this routing peer has no entries for these connections, so the
+// first packets from the network towards the peer would be dropped by rules that only accept established traffic.
+type RouteFailover interface {
+	// AllowRouteFailover accepts traffic from the network to the peer regardless of the connection state.
+	// The rule is meant to be short-lived, until the connection tracking picked up the existing connections.
+	AllowRouteFailover(peer netip.Addr, network netip.Prefix) (Rule, error)
+
+	// RemoveRouteFailover removes a rule added by AllowRouteFailover
+	RemoveRouteFailover(rule Rule) error
+}
@@ -47,6 +47,8 @@ func (a Action) String() string {
 		return "accept"
 	case ActionDrop:
 		return "drop"
+	case ActionAudit:
+		return "audit"
 	default:
 		return "unknown"
 	}
@@ -57,6 +59,9 @@ const (
 	ActionAccept Action = iota
 	// ActionDrop is the action to drop a packet
 	ActionDrop
+	// ActionAudit accepts a packet a drop rule would match and reports it, so new rules can be tried before they
+	// are enforced. It is only supported for route rules, which evaluate it before the accept rules.
+	ActionAudit
 )
 
 // Network is a rule destination, either a set or a prefix
@@ -190,3 +190,21 @@ func TestMergeIPRanges(t *testing.T) {
 		})
 	}
 }
+
+func TestReversePathNetworks(t *testing.T) {
+	networks := manager.ReversePathNetworks([]netip.Prefix{
+		netip.MustParsePrefix("100.64.0.5/32"),
+		netip.MustParsePrefix("192.168.1.7/24"),
+		netip.MustParsePrefix("fd00::/64"),
+		netip.MustParsePrefix("100.64.0.0/16"),
+		netip.MustParsePrefix("192.168.1.0/25"),
+	})
+
+	expected := []netip.Prefix{
+		netip.MustParsePrefix("100.64.0.0/16"),
+		netip.MustParsePrefix("192.168.1.0/24"),
+	}
+	if !reflect.DeepEqual(networks, expected) {
+		t.Errorf("ReversePathNetworks() = %v, want %v", networks, expected)
+	}
+}
@@ -3,25 +3,109 @@ package manager
 import (
 	"fmt"
 	"net/netip"
+	"strings"
+	"time"
 )
 
+// HealthCheckType is the probe of a forward rule health check
+type HealthCheckType int
+
+const (
+	// HealthCheckNone disables the health check
+	HealthCheckNone HealthCheckType = iota
+	// HealthCheckTCP connects to the translated port
+	HealthCheckTCP
+	// HealthCheckHTTP expects a 2xx or 3xx response from the translated port
+	HealthCheckHTTP
+)
+
+func (t HealthCheckType) String() string {
+	switch t {
+	case HealthCheckTCP:
+		return "tcp"
+	case HealthCheckHTTP:
+		return "http"
+	default:
+		return "none"
+	}
+}
+
+// ForwardHealthCheck configures the health check of the translated address of a forward rule
+type ForwardHealthCheck struct {
+	Type HealthCheckType
+	// Path of the HTTP request
+	Path     string
+	Interval time.Duration
+	Timeout  time.Duration
+	// UnhealthyThreshold is the number of consecutive failed checks after which the translated address is down
+	UnhealthyThreshold int
+}
+
 // ForwardRule todo figure out better place to this to avoid circular imports
 type ForwardRule struct {
 	Protocol          Protocol
 	DestinationPort   Port
 	TranslatedAddress netip.Addr
-	TranslatedPort    Port
+	// TranslatedAddresses are additional backends, the new connections are balanced round-robin across
+	// TranslatedAddress and them
+	TranslatedAddresses []netip.Addr
+	// TranslatedPort is a single port, a range of the size of the destination port range or empty to keep the port
+	TranslatedPort Port
+
+	// HealthCheck and DrainTimeout are handled by the ingress gateway, they aren't part of the firewall rule
+	HealthCheck ForwardHealthCheck
+	// DrainTimeout keeps a removed rule in place for the established connections
+	DrainTimeout time.Duration
 }
 
 func (r ForwardRule) ID() string {
 	id := fmt.Sprintf("%s;%s;%s;%s",
 		r.Protocol,
 		r.DestinationPort.String(),
-		r.TranslatedAddress.String(),
+		r.backendsString(),
 		r.TranslatedPort.String())
 	return id
 }
 
 func (r ForwardRule) String() string {
-	return fmt.Sprintf("protocol: %s, destinationPort: %s, translatedAddress: %s, translatedPort: %s", r.Protocol, r.DestinationPort.String(), r.TranslatedAddress.String(), r.TranslatedPort.String())
+	return fmt.Sprintf("protocol: %s, destinationPort: %s, translatedAddress: %s, translatedPort: %s", r.Protocol, r.DestinationPort.String(), r.backendsString(), r.TranslatedPort.String())
+}
+
+// Backends returns TranslatedAddress followed by the additional TranslatedAddresses
+func (r ForwardRule) Backends() []netip.Addr {
+	backends := make([]netip.Addr, 0, len(r.TranslatedAddresses)+1)
+	backends = append(backends, r.TranslatedAddress)
+	return append(backends, r.TranslatedAddresses...)
+}
+
+// Validate checks that the translated port range maps the destination port range one to one
+func (r ForwardRule) Validate() error {
+	if !r.TranslatedPort.IsRange {
+		return nil
+	}
+	if len(r.TranslatedPort.Values) != 2 || len(r.DestinationPort.Values) != 2 || !r.DestinationPort.IsRange {
+		return fmt.Errorf("translated port range %s requires a destination port range", r.TranslatedPort.String())
+	}
+
+	dstSize := int(r.DestinationPort.Values[1]) - int(r.DestinationPort.Values[0])
+	translatedSize := int(r.TranslatedPort.Values[1]) - int(r.TranslatedPort.Values[0])
+	if dstSize < 0 || translatedSize < 0 {
+		return fmt.Errorf("invalid port range: %s to %s", r.DestinationPort.String(), r.TranslatedPort.String())
+	}
+	if dstSize != translatedSize {
+		return fmt.Errorf("destination port range %s and translated port range %s differ in size", r.DestinationPort.String(), r.TranslatedPort.String())
+	}
+	return nil
+}
+
+func (r ForwardRule) backendsString() string {
+	if len(r.TranslatedAddresses) == 0 {
+		return r.TranslatedAddress.String()
+	}
+
+	backends := make([]string, 0, len(r.TranslatedAddresses)+1)
+	for _, addr := range r.Backends() {
+		backends = append(backends, addr.String())
+	}
+	return strings.Join(backends, ",")
 }
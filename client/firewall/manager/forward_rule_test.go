@@ -0,0 +1,86 @@
+package manager_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/client/firewall/manager"
+)
+
+func TestForwardRule_Backends(t *testing.T) {
+	rule := manager.ForwardRule{
+		Protocol:          manager.ProtocolTCP,
+		DestinationPort:   manager.Port{Values: []uint16{443}},
+		TranslatedAddress: netip.MustParseAddr("100.64.0.9"),
+		TranslatedPort:    manager.Port{Values: []uint16{8443}},
+	}
+	assert.Equal(t, []netip.Addr{netip.MustParseAddr("100.64.0.9")}, rule.Backends())
+	single := rule.ID()
+
+	rule.TranslatedAddresses = []netip.Addr{netip.MustParseAddr("100.64.0.10")}
+	assert.Equal(t, []netip.Addr{netip.MustParseAddr("100.64.0.9"), netip.MustParseAddr("100.64.0.10")}, rule.Backends())
+	assert.NotEqual(t, single, rule.ID(), "adding a backend must change the rule")
+	assert.Equal(t, "tcp;443;100.64.0.9,100.64.0.10;8443", rule.ID())
+}
+
+func TestForwardRule_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		dstPort    manager.Port
+		transPort  manager.Port
+		wantErrMsg string
+	}{
+		{
+			name:      "single port",
+			dstPort:   manager.Port{Values: []uint16{80}},
+			transPort: manager.Port{Values: []uint16{8080}},
+		},
+		{
+			name:      "range to single port",
+			dstPort:   manager.Port{IsRange: true, Values: []uint16{8000, 8010}},
+			transPort: manager.Port{Values: []uint16{80}},
+		},
+		{
+			name:      "range to shifted range",
+			dstPort:   manager.Port{IsRange: true, Values: []uint16{8000, 8010}},
+			transPort: manager.Port{IsRange: true, Values: []uint16{9000, 9010}},
+		},
+		{
+			name:       "ranges of different size",
+			dstPort:    manager.Port{IsRange: true, Values: []uint16{8000, 8010}},
+			transPort:  manager.Port{IsRange: true, Values: []uint16{9000, 9020}},
+			wantErrMsg: "differ in size",
+		},
+		{
+			name:       "single port to range",
+			dstPort:    manager.Port{Values: []uint16{80}},
+			transPort:  manager.Port{IsRange: true, Values: []uint16{9000, 9010}},
+			wantErrMsg: "requires a destination port range",
+		},
+		{
+			name:       "inverted range",
+			dstPort:    manager.Port{IsRange: true, Values: []uint16{8010, 8000}},
+			transPort:  manager.Port{IsRange: true, Values: []uint16{9000, 9010}},
+			wantErrMsg: "invalid port range",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := manager.ForwardRule{
+				Protocol:          manager.ProtocolTCP,
+				DestinationPort:   tt.dstPort,
+				TranslatedAddress: netip.MustParseAddr("100.64.0.9"),
+				TranslatedPort:    tt.transPort,
+			}
+			err := rule.Validate()
+			if tt.wantErrMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package manager
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+// KillSwitchRule describes egress traffic that stays allowed while the kill switch is active
+type KillSwitchRule struct {
+	// Destination limits the rule to a network, an invalid prefix matches any destination
+	Destination netip.Prefix `json:"destination"`
+	// Protocol is either tcp or udp if ports are set, otherwise all protocols are matched
+	Protocol Protocol `json:"protocol"`
+	SrcPort  uint16   `json:"src_port,omitempty"`
+	DstPort  uint16   `json:"dst_port,omitempty"`
+}
+
+// String returns the string representation of the rule
+func (r KillSwitchRule) String() string {
+	dst := "any"
+	if r.Destination.IsValid() {
+		dst = r.Destination.String()
+	}
+	return fmt.Sprintf("%s %s sport %d dport %d", r.Protocol, dst, r.SrcPort, r.DstPort)
+}
+
+// Validate checks that the rule can be expressed by the firewall managers
+func (r KillSwitchRule) Validate() error {
+	if r.SrcPort == 0 && r.DstPort == 0 {
+		return nil
+	}
+	if r.Protocol != ProtocolTCP && r.Protocol != ProtocolUDP {
+		return fmt.Errorf("ports require tcp or udp protocol, got %q", r.Protocol)
+	}
+	return nil
+}
+
+// KillSwitch is implemented by firewall managers that can block all egress traffic outside the tunnel
+type KillSwitch interface {
+	// EnableKillSwitch drops all egress traffic that neither leaves through the netbird interface nor
+	// matches one of the rules. Calling it again replaces the rules atomically.
+	// The kill switch outlives Close, so no traffic leaks while the tunnel is down.
+	EnableKillSwitch(rules []KillSwitchRule) error
+
+	// DisableKillSwitch removes the kill switch, it is a no-op if the kill switch isn't installed
+	DisableKillSwitch() error
+}
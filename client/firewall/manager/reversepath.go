@@ -0,0 +1,49 @@
+package manager
+
+import (
+	"cmp"
+	"net/netip"
+	"slices"
+)
+
+const (
+	// ReversePathOverlayRuleID is the rule ID of the counters of the packets from the overlay with a spoofed source
+	ReversePathOverlayRuleID = "reverse-path-overlay"
+	// ReversePathLocalRuleID is the rule ID of the counters of the packets from the local networks with a spoofed source
+	ReversePathLocalRuleID = "reverse-path-local"
+)
+
+// ReversePathFilter is implemented by firewall managers that can drop routed traffic with spoofed sources on
+// routing peers, a strict reverse path check (uRPF) for the traffic passing the peer.
+// Only IPv4 traffic is checked, like the route rules. Replies of established connections and related ICMP errors
+// are not checked.
+type ReversePathFilter interface {
+	// SetReversePathFilter drops the traffic forwarded from the netbird interface whose source isn't within the
+	// overlay networks, the AllowedIPs of the peers, and the traffic forwarded from other interfaces to the netbird
+	// interface whose source isn't within the local networks, the networks routed by this peer.
+	// Calling it again replaces the networks.
+	SetReversePathFilter(overlay, local []netip.Prefix) error
+
+	// RemoveReversePathFilter removes the filter, it is a no-op if the filter isn't installed
+	RemoveReversePathFilter() error
+
+	// ReversePathStats returns the counters of the dropped packets by ReversePathOverlayRuleID and
+	// ReversePathLocalRuleID, empty if the filter isn't installed
+	ReversePathStats() ([]RouteRuleStats, error)
+}
+
+// ReversePathNetworks returns the IPv4 networks of a reverse path filter sorted and merged, as the firewall sets
+// take them
+func ReversePathNetworks(prefixes []netip.Prefix) []netip.Prefix {
+	var networks []netip.Prefix
+	for _, prefix := range prefixes {
+		if prefix.Addr().Is4() {
+			networks = append(networks, prefix.Masked())
+		}
+	}
+	// the wider of the networks of the same address goes first, so it absorbs the narrower ones
+	slices.SortFunc(networks, func(a, b netip.Prefix) int {
+		return cmp.Or(a.Addr().Compare(b.Addr()), cmp.Compare(a.Bits(), b.Bits()))
+	})
+	return MergeIPRanges(networks)
+}
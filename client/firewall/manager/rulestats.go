@@ -0,0 +1,19 @@
+package manager
+
+// RouteRuleStats are the counters of the traffic that matched a route rule
+type RouteRuleStats struct {
+	// RuleID is the ID of the rule returned by AddRouteFiltering
+	RuleID string
+	// PolicyID is the ID of the management policy the rule belongs to, if the firewall keeps it
+	PolicyID []byte
+	Action   Action
+	Packets  uint64
+	Bytes    uint64
+}
+
+// RouteRuleCounter is implemented by firewall managers that count the traffic matching their route rules.
+// With stateful filtering only the packets that are evaluated against the rules are counted, usually the first
+// packets of a connection.
+type RouteRuleCounter interface {
+	RouteRuleStats() ([]RouteRuleStats, error)
+}
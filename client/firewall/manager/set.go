@@ -72,3 +72,14 @@ func NewDomainSet(domains domain.List) Set {
 
 	return set
 }
+
+// SetSize is the number of elements of a firewall set
+type SetSize struct {
+	Name     string
+	Elements int
+}
+
+// SetSizeReporter is implemented by firewall managers that match peer addresses and route networks with sets
+type SetSizeReporter interface {
+	SetSizes() ([]SetSize, error)
+}
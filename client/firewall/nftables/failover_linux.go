@@ -0,0 +1,202 @@
+package nftables
+
+import (
+	"bytes"
+	"fmt"
+	"net/netip"
+
+	"github.com/coreos/go-iptables/iptables"
+	"github.com/google/nftables"
+	"github.com/google/nftables/expr"
+	"github.com/hashicorp/go-multierror"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+
+	nberrors "github.com/netbirdio/netbird/client/errors"
+	firewall "github.com/netbirdio/netbird/client/firewall/manager"
+	nbid "github.com/netbirdio/netbird/client/internal/acl/id"
+)
+
+const failoverPrefix = "failover-"
+
+type failoverRule struct {
+	peer    netip.Addr
+	network netip.Prefix
+}
+
+// iptablesSpec returns the rule for the filter table if it is managed with iptables
+func (f failoverRule) iptablesSpec(intf string) []string {
+	return []string{"-o", intf, "-s", f.network.String(), "-d", f.peer.String(), "-j", "ACCEPT"}
+}
+
+// AllowRouteFailover accepts traffic from the network to the peer regardless of the connection state
+func (m *Manager) AllowRouteFailover(peer netip.Addr, network netip.Prefix) (firewall.Rule, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.router.AddRouteFailover(peer, network)
+}
+
+// RemoveRouteFailover removes a rule added by AllowRouteFailover
+func (m *Manager) RemoveRouteFailover(rule firewall.Rule) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.router.DeleteRouteFailover(rule)
+}
+
+// AddRouteFailover inserts accept rules for the traffic from the network to the peer into the chains that
+// would otherwise only accept established traffic towards our interface: the FORWARD chain of the filter table
+// and the forward chains of external tables. Our own chains don't filter traffic leaving through the interface.
+func (r *router) AddRouteFailover(peer netip.Addr, network netip.Prefix) (firewall.Rule, error) {
+	if !peer.Is4() || !network.Addr().Is4() {
+		return nil, fmt.Errorf("failover rule for %s to %s: only ipv4 is supported", network, peer)
+	}
+
+	rule := failoverRule{peer: peer, network: network.Masked()}
+	ruleKey := fmt.Sprintf("%s%s-%s", failoverPrefix, rule.peer, rule.network)
+	if _, exists := r.failovers[ruleKey]; exists {
+		return nbid.RuleID(ruleKey), nil
+	}
+
+	var merr *multierror.Error
+	if err := r.insertFailoverFilterTable(rule, ruleKey); err != nil {
+		merr = multierror.Append(merr, fmt.Errorf("filter table: %w", err))
+	}
+
+	for _, chain := range r.findExternalChains() {
+		if chain.Hooknum == nil || *chain.Hooknum != *nftables.ChainHookForward {
+			continue
+		}
+		r.insertFailoverRule(chain, rule, ruleKey)
+	}
+	if err := r.conn.Flush(); err != nil {
+		merr = multierror.Append(merr, fmt.Errorf("flush external chain rules: %w", err))
+	}
+
+	// keep the rule even on errors, so the rules that were added are removed again
+	r.failovers[ruleKey] = rule
+	if err := nberrors.FormatErrorOrNil(merr); err != nil {
+		return nbid.RuleID(ruleKey), fmt.Errorf("add failover rule: %w", err)
+	}
+	return nbid.RuleID(ruleKey), nil
+}
+
+// DeleteRouteFailover removes the failover rule from all chains
+func (r *router) DeleteRouteFailover(rule firewall.Rule) error {
+	ruleKey := rule.ID()
+
+	failover, exists := r.failovers[ruleKey]
+	if !exists {
+		log.Debugf("failover rule %s not found", ruleKey)
+		return nil
+	}
+	delete(r.failovers, ruleKey)
+
+	var merr *multierror.Error
+	if err := r.removeFailoverFilterTable(failover, ruleKey); err != nil {
+		merr = multierror.Append(merr, fmt.Errorf("filter table: %w", err))
+	}
+
+	for _, chain := range r.findExternalChains() {
+		if err := r.removeFailoverFromChain(chain, ruleKey); err != nil {
+			merr = multierror.Append(merr, err)
+		}
+	}
+	if err := r.conn.Flush(); err != nil {
+		merr = multierror.Append(merr, fmt.Errorf("flush external chain rules: %w", err))
+	}
+
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+// removeRouteFailovers removes all failover rules
+func (r *router) removeRouteFailovers() error {
+	var merr *multierror.Error
+	for ruleKey := range r.failovers {
+		if err := r.DeleteRouteFailover(nbid.RuleID(ruleKey)); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("remove %s: %w", ruleKey, err))
+		}
+	}
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+func (r *router) insertFailoverFilterTable(rule failoverRule, ruleKey string) error {
+	if r.filterTable == nil {
+		return nil
+	}
+
+	ipt, err := iptables.New()
+	if err != nil {
+		r.insertFailoverRule(r.filterForwardChain(), rule, ruleKey)
+		return nil
+	}
+	return ipt.Insert(tableNameFilter, chainNameForward, 1, rule.iptablesSpec(r.wgIface.Name())...)
+}
+
+func (r *router) removeFailoverFilterTable(rule failoverRule, ruleKey string) error {
+	if r.filterTable == nil {
+		return nil
+	}
+
+	ipt, err := iptables.New()
+	if err != nil {
+		return r.removeFailoverFromChain(r.filterForwardChain(), ruleKey)
+	}
+	return ipt.DeleteIfExists(tableNameFilter, chainNameForward, rule.iptablesSpec(r.wgIface.Name())...)
+}
+
+func (r *router) filterForwardChain() *nftables.Chain {
+	return &nftables.Chain{
+		Name:     chainNameForward,
+		Table:    r.filterTable,
+		Type:     nftables.ChainTypeFilter,
+		Hooknum:  nftables.ChainHookForward,
+		Priority: nftables.ChainPriorityFilter,
+	}
+}
+
+func (r *router) insertFailoverRule(chain *nftables.Chain, rule failoverRule, ruleKey string) {
+	exprs := []expr.Any{
+		&expr.Meta{Key: expr.MetaKeyOIFNAME, Register: 1},
+		&expr.Cmp{
+			Op:       expr.CmpOpEq,
+			Register: 1,
+			Data:     ifname(r.wgIface.Name()),
+		},
+		// external chains may be in the inet family
+		&expr.Meta{Key: expr.MetaKeyNFPROTO, Register: 1},
+		&expr.Cmp{
+			Op:       expr.CmpOpEq,
+			Register: 1,
+			Data:     []byte{unix.NFPROTO_IPV4},
+		},
+	}
+	exprs = append(exprs, applyPrefix(rule.network, true)...)
+	exprs = append(exprs, applyPrefix(netip.PrefixFrom(rule.peer, 32), false)...)
+	exprs = append(exprs, &expr.Counter{}, &expr.Verdict{Kind: expr.VerdictAccept})
+
+	r.conn.InsertRule(&nftables.Rule{
+		Table:    chain.Table,
+		Chain:    chain,
+		Exprs:    exprs,
+		UserData: []byte(ruleKey),
+	})
+}
+
+func (r *router) removeFailoverFromChain(chain *nftables.Chain, ruleKey string) error {
+	rules, err := r.conn.GetRules(chain.Table, chain)
+	if err != nil {
+		return fmt.Errorf("get rules from %s/%s: %w", chain.Table.Name, chain.Name, err)
+	}
+
+	for _, rule := range rules {
+		if !bytes.Equal(rule.UserData, []byte(ruleKey)) {
+			continue
+		}
+		if err := r.conn.DelRule(rule); err != nil {
+			return fmt.Errorf("delete rule from %s/%s: %w", chain.Table.Name, chain.Name, err)
+		}
+	}
+	return nil
+}
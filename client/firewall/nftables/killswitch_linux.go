@@ -0,0 +1,192 @@
+package nftables
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/google/nftables"
+	"github.com/google/nftables/binaryutil"
+	"github.com/google/nftables/expr"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+
+	firewall "github.com/netbirdio/netbird/client/firewall/manager"
+)
+
+const (
+	// tableNameKillSwitch is kept separate from the work table, so the kill switch survives Close
+	tableNameKillSwitch = "netbird-killswitch"
+	chainNameKillSwitch = "output"
+)
+
+// EnableKillSwitch drops all egress traffic except through the netbird interface and the given rules.
+// The table is replaced within a single transaction, so there is no window without the drop policy.
+func (m *Manager) EnableKillSwitch(rules []firewall.KillSwitchRule) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("invalid kill switch rule %s: %w", rule, err)
+		}
+	}
+
+	if err := m.deleteKillSwitchTable(); err != nil {
+		return err
+	}
+
+	table := m.rConn.AddTable(&nftables.Table{Name: tableNameKillSwitch, Family: nftables.TableFamilyINet})
+	policy := nftables.ChainPolicyDrop
+	chain := m.rConn.AddChain(&nftables.Chain{
+		Name:     chainNameKillSwitch,
+		Table:    table,
+		Type:     nftables.ChainTypeFilter,
+		Hooknum:  nftables.ChainHookOutput,
+		Priority: nftables.ChainPriorityFilter,
+		Policy:   &policy,
+	})
+
+	for _, intf := range []string{"lo", m.wgIface.Name()} {
+		m.rConn.AddRule(&nftables.Rule{
+			Table: table,
+			Chain: chain,
+			Exprs: []expr.Any{
+				&expr.Meta{Key: expr.MetaKeyOIFNAME, Register: 1},
+				&expr.Cmp{
+					Op:       expr.CmpOpEq,
+					Register: 1,
+					Data:     ifname(intf),
+				},
+				&expr.Verdict{Kind: expr.VerdictAccept},
+			},
+		})
+	}
+
+	for _, rule := range rules {
+		m.rConn.AddRule(&nftables.Rule{
+			Table: table,
+			Chain: chain,
+			Exprs: append(killSwitchRuleExprs(rule), &expr.Counter{}, &expr.Verdict{Kind: expr.VerdictAccept}),
+		})
+	}
+
+	if err := m.rConn.Flush(); err != nil {
+		return fmt.Errorf(flushError, err)
+	}
+
+	log.Infof("kill switch enabled with %d exceptions", len(rules))
+	return nil
+}
+
+// DisableKillSwitch removes the kill switch table
+func (m *Manager) DisableKillSwitch() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if err := m.deleteKillSwitchTable(); err != nil {
+		return err
+	}
+
+	if err := m.rConn.Flush(); err != nil {
+		return fmt.Errorf(flushError, err)
+	}
+
+	return nil
+}
+
+// deleteKillSwitchTable queues the deletion of the kill switch table if it exists
+func (m *Manager) deleteKillSwitchTable() error {
+	tables, err := m.rConn.ListTablesOfFamily(nftables.TableFamilyINet)
+	if err != nil {
+		return fmt.Errorf("list tables: %w", err)
+	}
+
+	for _, t := range tables {
+		if t.Name == tableNameKillSwitch {
+			m.rConn.DelTable(t)
+		}
+	}
+	return nil
+}
+
+func killSwitchRuleExprs(rule firewall.KillSwitchRule) []expr.Any {
+	var exprs []expr.Any
+
+	if rule.Destination.IsValid() {
+		nfproto, offset, addrLen := byte(unix.NFPROTO_IPV4), uint32(16), uint32(4)
+		if rule.Destination.Addr().Is6() {
+			nfproto, offset, addrLen = unix.NFPROTO_IPV6, 24, 16
+		}
+
+		exprs = append(exprs,
+			&expr.Meta{Key: expr.MetaKeyNFPROTO, Register: 1},
+			&expr.Cmp{
+				Op:       expr.CmpOpEq,
+				Register: 1,
+				Data:     []byte{nfproto},
+			},
+			&expr.Payload{
+				DestRegister: 1,
+				Base:         expr.PayloadBaseNetworkHeader,
+				Offset:       offset,
+				Len:          addrLen,
+			},
+			&expr.Bitwise{
+				SourceRegister: 1,
+				DestRegister:   1,
+				Len:            addrLen,
+				Mask:           net.CIDRMask(rule.Destination.Bits(), int(addrLen)*8),
+				Xor:            make([]byte, addrLen),
+			},
+			&expr.Cmp{
+				Op:       expr.CmpOpEq,
+				Register: 1,
+				Data:     rule.Destination.Masked().Addr().AsSlice(),
+			},
+		)
+	}
+
+	var protoNum byte
+	switch rule.Protocol {
+	case firewall.ProtocolTCP:
+		protoNum = unix.IPPROTO_TCP
+	case firewall.ProtocolUDP:
+		protoNum = unix.IPPROTO_UDP
+	default:
+		return exprs
+	}
+
+	exprs = append(exprs,
+		&expr.Meta{Key: expr.MetaKeyL4PROTO, Register: 1},
+		&expr.Cmp{
+			Op:       expr.CmpOpEq,
+			Register: 1,
+			Data:     []byte{protoNum},
+		},
+	)
+
+	if rule.SrcPort != 0 {
+		exprs = append(exprs, killSwitchPortExprs(rule.SrcPort, 0)...)
+	}
+	if rule.DstPort != 0 {
+		exprs = append(exprs, killSwitchPortExprs(rule.DstPort, 2)...)
+	}
+
+	return exprs
+}
+
+func killSwitchPortExprs(port uint16, offset uint32) []expr.Any {
+	return []expr.Any{
+		&expr.Payload{
+			DestRegister: 1,
+			Base:         expr.PayloadBaseTransportHeader,
+			Offset:       offset,
+			Len:          2,
+		},
+		&expr.Cmp{
+			Op:       expr.CmpOpEq,
+			Register: 1,
+			Data:     binaryutil.BigEndian.PutUint16(port),
+		},
+	}
+}
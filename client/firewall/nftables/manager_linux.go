@@ -176,6 +176,43 @@ func (m *Manager) DeleteRouteRule(rule firewall.Rule) error {
 	return m.router.DeleteRouteRule(rule)
 }
 
+// SetSizes returns the number of elements of the sets in the NetBird table
+func (m *Manager) SetSizes() ([]firewall.SetSize, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	sets, err := m.rConn.GetSets(m.router.workTable)
+	if err != nil {
+		return nil, fmt.Errorf("list sets: %w", err)
+	}
+
+	sizes := make([]firewall.SetSize, 0, len(sets))
+	for _, set := range sets {
+		elements, err := m.rConn.GetSetElements(set)
+		if err != nil {
+			return nil, fmt.Errorf("list elements of set %s: %w", set.Name, err)
+		}
+
+		size := firewall.SetSize{Name: set.Name}
+		for _, element := range elements {
+			// interval sets hold a start and an end element per prefix
+			if !element.IntervalEnd {
+				size.Elements++
+			}
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
+
+// RouteRuleStats returns the counters of the route filtering rules
+func (m *Manager) RouteRuleStats() ([]firewall.RouteRuleStats, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.router.RouteRuleStats()
+}
+
 func (m *Manager) IsServerRouteSupported() bool {
 	return true
 }
@@ -494,3 +494,48 @@ func compareExprsIgnoringCounters(t *testing.T, got, want []expr.Any) {
 		require.Equal(t, got[i], want[i], "expression mismatch at index %d", i)
 	}
 }
+
+func TestNftablesManagerRouteDestinationSet(t *testing.T) {
+	if check() != NFTABLES {
+		t.Skip("nftables not supported on this system")
+	}
+
+	manager, err := Create(ifaceMock, iface.DefaultMTU)
+	require.NoError(t, err, "failed to create manager")
+	require.NoError(t, manager.Init(nil))
+
+	t.Cleanup(func() {
+		require.NoError(t, manager.Close(nil), "failed to reset manager state")
+	})
+
+	// more destinations than fit into a single netlink message
+	prefixes := make([]netip.Prefix, 0, 4000)
+	for i := 0; i < cap(prefixes); i++ {
+		prefixes = append(prefixes, netip.PrefixFrom(netip.AddrFrom4([4]byte{10, byte(i >> 8), byte(i), 0}), 24))
+	}
+	set := fw.NewPrefixSet(prefixes)
+
+	_, err = manager.AddRouteFiltering(
+		nil,
+		[]netip.Prefix{netip.MustParsePrefix("100.64.0.0/10")},
+		fw.Network{Set: set},
+		fw.ProtocolTCP,
+		nil,
+		&fw.Port{Values: []uint16{443}},
+		fw.ActionAccept,
+	)
+	require.NoError(t, err, "failed to add route filtering rule")
+	require.NoError(t, manager.UpdateSet(set, prefixes), "failed to fill the destination set")
+
+	sizes, err := manager.SetSizes()
+	require.NoError(t, err)
+
+	var found bool
+	for _, size := range sizes {
+		if size.Name == set.HashedName() {
+			found = true
+			require.Equal(t, len(prefixes), size.Elements)
+		}
+	}
+	require.True(t, found, "destination set not reported")
+}
@@ -0,0 +1,262 @@
+package nftables
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+
+	"github.com/google/nftables"
+	"github.com/google/nftables/expr"
+	log "github.com/sirupsen/logrus"
+
+	firewall "github.com/netbirdio/netbird/client/firewall/manager"
+)
+
+const (
+	chainNameReversePath = "netbird-rt-rpf"
+	setNameRPFOverlay    = "netbird-rpf-overlay"
+	setNameRPFLocal      = "netbird-rpf-local"
+)
+
+// reversePathFilter is the chain and the source sets of the reverse path filter
+type reversePathFilter struct {
+	chain   *nftables.Chain
+	overlay *nftables.Set
+	local   *nftables.Set
+	// the merged prefixes of the sets, to update them with the difference only
+	overlayPrefixes []netip.Prefix
+	localPrefixes   []netip.Prefix
+}
+
+// SetReversePathFilter drops the routed traffic with sources outside the overlay and local networks
+func (m *Manager) SetReversePathFilter(overlay, local []netip.Prefix) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.router.SetReversePathFilter(overlay, local)
+}
+
+// RemoveReversePathFilter removes the reverse path filter
+func (m *Manager) RemoveReversePathFilter() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.router.RemoveReversePathFilter()
+}
+
+// ReversePathStats returns the counters of the packets dropped by the reverse path filter
+func (m *Manager) ReversePathStats() ([]firewall.RouteRuleStats, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.router.ReversePathStats()
+}
+
+// SetReversePathFilter installs the reverse path filter chain in front of the forward filter chains, or updates
+// the source sets of the installed one. The sets are updated with their difference in a single transaction, so the
+// sources that stay valid are never dropped.
+func (r *router) SetReversePathFilter(overlay, local []netip.Prefix) error {
+	overlay = firewall.ReversePathNetworks(overlay)
+	local = firewall.ReversePathNetworks(local)
+
+	if r.reversePath == nil {
+		if err := r.createReversePathFilter(overlay, local); err != nil {
+			return fmt.Errorf("create reverse path filter: %w", err)
+		}
+		log.Infof("reverse path filter enabled for %d overlay and %d local networks", len(overlay), len(local))
+		return nil
+	}
+
+	rpf := r.reversePath
+	if err := updateIntervalSet(r.conn, rpf.overlay, rpf.overlayPrefixes, overlay); err != nil {
+		return err
+	}
+	if err := updateIntervalSet(r.conn, rpf.local, rpf.localPrefixes, local); err != nil {
+		return err
+	}
+	if err := r.conn.Flush(); err != nil {
+		return fmt.Errorf("update reverse path sets: %w", err)
+	}
+	rpf.overlayPrefixes = overlay
+	rpf.localPrefixes = local
+
+	log.Debugf("updated reverse path filter with overlay networks %v and local networks %v", overlay, local)
+	return nil
+}
+
+func (r *router) createReversePathFilter(overlay, local []netip.Prefix) error {
+	overlaySet := &nftables.Set{
+		Name:     setNameRPFOverlay,
+		Table:    r.workTable,
+		Interval: true,
+		KeyType:  nftables.TypeIPAddr,
+	}
+	if err := r.conn.AddSet(overlaySet, convertPrefixesToSet(overlay)); err != nil {
+		return fmt.Errorf("add set %s: %w", setNameRPFOverlay, err)
+	}
+
+	localSet := &nftables.Set{
+		Name:     setNameRPFLocal,
+		Table:    r.workTable,
+		Interval: true,
+		KeyType:  nftables.TypeIPAddr,
+	}
+	if err := r.conn.AddSet(localSet, convertPrefixesToSet(local)); err != nil {
+		return fmt.Errorf("add set %s: %w", setNameRPFLocal, err)
+	}
+
+	// ahead of the forward filter chains, a drop is final in any of them
+	prio := *nftables.ChainPriorityFilter - 1
+	policy := nftables.ChainPolicyAccept
+	chain := r.conn.AddChain(&nftables.Chain{
+		Name:     chainNameReversePath,
+		Table:    r.workTable,
+		Hooknum:  nftables.ChainHookForward,
+		Priority: &prio,
+		Type:     nftables.ChainTypeFilter,
+		Policy:   &policy,
+	})
+
+	r.conn.AddRule(&nftables.Rule{
+		Table: r.workTable,
+		Chain: chain,
+		Exprs: getEstablishedExprs(1),
+	})
+
+	wgIface := ifname(r.wgIface.Name())
+	overlayExprs := []expr.Any{
+		&expr.Meta{Key: expr.MetaKeyIIFNAME, Register: 1},
+		&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: wgIface},
+	}
+	r.conn.AddRule(&nftables.Rule{
+		Table:    r.workTable,
+		Chain:    chain,
+		Exprs:    append(overlayExprs, reversePathDropExprs(overlaySet)...),
+		UserData: []byte(firewall.ReversePathOverlayRuleID),
+	})
+
+	localExprs := []expr.Any{
+		&expr.Meta{Key: expr.MetaKeyIIFNAME, Register: 1},
+		&expr.Cmp{Op: expr.CmpOpNeq, Register: 1, Data: wgIface},
+		&expr.Meta{Key: expr.MetaKeyOIFNAME, Register: 1},
+		&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: wgIface},
+	}
+	r.conn.AddRule(&nftables.Rule{
+		Table:    r.workTable,
+		Chain:    chain,
+		Exprs:    append(localExprs, reversePathDropExprs(localSet)...),
+		UserData: []byte(firewall.ReversePathLocalRuleID),
+	})
+
+	if err := r.conn.Flush(); err != nil {
+		return fmt.Errorf(flushError, err)
+	}
+
+	r.reversePath = &reversePathFilter{
+		chain:           chain,
+		overlay:         overlaySet,
+		local:           localSet,
+		overlayPrefixes: overlay,
+		localPrefixes:   local,
+	}
+	return nil
+}
+
+// RemoveReversePathFilter deletes the chain and the sets of the reverse path filter
+func (r *router) RemoveReversePathFilter() error {
+	rpf := r.reversePath
+	if rpf == nil {
+		return nil
+	}
+
+	r.conn.DelChain(rpf.chain)
+	r.conn.DelSet(rpf.overlay)
+	r.conn.DelSet(rpf.local)
+	if err := r.conn.Flush(); err != nil {
+		return fmt.Errorf("remove reverse path filter: %w", err)
+	}
+	r.reversePath = nil
+
+	log.Info("reverse path filter disabled")
+	return nil
+}
+
+// ReversePathStats reads the counters of the drop rules of the reverse path filter
+func (r *router) ReversePathStats() ([]firewall.RouteRuleStats, error) {
+	if r.reversePath == nil {
+		return nil, nil
+	}
+
+	rules, err := r.conn.GetRules(r.workTable, r.reversePath.chain)
+	if err != nil {
+		return nil, fmt.Errorf("get rules from %s: %w", chainNameReversePath, err)
+	}
+
+	var stats []firewall.RouteRuleStats
+	for _, rule := range rules {
+		id := string(rule.UserData)
+		if id != firewall.ReversePathOverlayRuleID && id != firewall.ReversePathLocalRuleID {
+			continue
+		}
+
+		ruleStats := firewall.RouteRuleStats{RuleID: id, Action: firewall.ActionDrop}
+		for _, e := range rule.Exprs {
+			if counter, ok := e.(*expr.Counter); ok {
+				ruleStats.Packets = counter.Packets
+				ruleStats.Bytes = counter.Bytes
+				break
+			}
+		}
+		stats = append(stats, ruleStats)
+	}
+	return stats, nil
+}
+
+// reversePathDropExprs drops the packets with a source outside the set
+func reversePathDropExprs(set *nftables.Set) []expr.Any {
+	return []expr.Any{
+		&expr.Payload{
+			DestRegister: 1,
+			Base:         expr.PayloadBaseNetworkHeader,
+			Offset:       12,
+			Len:          4,
+		},
+		&expr.Lookup{
+			SourceRegister: 1,
+			SetName:        set.Name,
+			SetID:          set.ID,
+			Invert:         true,
+		},
+		&expr.Counter{},
+		&expr.Verdict{Kind: expr.VerdictDrop},
+	}
+}
+
+// updateIntervalSet queues the removal of the stale and the addition of the new prefixes of an interval set. The prefixes
+// have to be merged, the removed intervals must match the ones in the set.
+func updateIntervalSet(conn *nftables.Conn, set *nftables.Set, current, desired []netip.Prefix) error {
+	var removed, added []netip.Prefix
+	for _, prefix := range current {
+		if !slices.Contains(desired, prefix) {
+			removed = append(removed, prefix)
+		}
+	}
+	for _, prefix := range desired {
+		if !slices.Contains(current, prefix) {
+			added = append(added, prefix)
+		}
+	}
+
+	// removals first, the new intervals may overlap the stale ones
+	if len(removed) > 0 {
+		if err := conn.SetDeleteElements(set, convertPrefixesToSet(removed)); err != nil {
+			return fmt.Errorf("remove prefixes from set %s: %w", set.Name, err)
+		}
+	}
+	if len(added) > 0 {
+		if err := conn.SetAddElements(set, convertPrefixesToSet(added)); err != nil {
+			return fmt.Errorf("add prefixes to set %s: %w", set.Name, err)
+		}
+	}
+	return nil
+}
@@ -76,6 +76,12 @@ type router struct {
 	ipFwdState       *ipfwdstate.IPForwardingState
 	legacyManagement bool
 	mtu              uint16
+	// failovers holds the failover rules by their key, they live outside the work table
+	failovers map[string]failoverRule
+	// routeRuleActions holds the action of the route filtering rules by their key, audit rules look like accept rules in the kernel
+	routeRuleActions map[string]firewall.Action
+	// reversePath is the reverse path filter, nil if it isn't installed. It lives in the work table.
+	reversePath *reversePathFilter
 }
 
 func newRouter(workTable *nftables.Table, wgIface iFaceMapper, mtu uint16) (*router, error) {
@@ -87,6 +93,9 @@ func newRouter(workTable *nftables.Table, wgIface iFaceMapper, mtu uint16) (*rou
 		wgIface:    wgIface,
 		ipFwdState: ipfwdstate.NewIPForwardingState(),
 		mtu:        mtu,
+		failovers:  make(map[string]failoverRule),
+
+		routeRuleActions: make(map[string]firewall.Action),
 	}
 
 	r.ipsetCounter = refcounter.New(
@@ -125,6 +134,7 @@ func (r *router) init(workTable *nftables.Table) error {
 func (r *router) Reset() error {
 	// clear without deleting the ipsets, the nf table will be deleted by the caller
 	r.ipsetCounter.Clear()
+	r.reversePath = nil
 
 	var merr *multierror.Error
 
@@ -136,6 +146,10 @@ func (r *router) Reset() error {
 		merr = multierror.Append(merr, fmt.Errorf("remove filter prerouting rules: %w", err))
 	}
 
+	if err := r.removeRouteFailovers(); err != nil {
+		merr = multierror.Append(merr, fmt.Errorf("remove failover rules: %w", err))
+	}
+
 	return nberrors.FormatErrorOrNil(merr)
 }
 
@@ -424,11 +438,12 @@ func (r *router) AddRouteFiltering(
 
 	exprs = append(exprs, &expr.Counter{})
 
+	// audit rules accept the traffic, their counter tells what the rule would drop
 	var verdict expr.VerdictKind
-	if action == firewall.ActionAccept {
-		verdict = expr.VerdictAccept
-	} else {
+	if action == firewall.ActionDrop {
 		verdict = expr.VerdictDrop
+	} else {
+		verdict = expr.VerdictAccept
 	}
 	exprs = append(exprs, &expr.Verdict{Kind: verdict})
 
@@ -439,8 +454,8 @@ func (r *router) AddRouteFiltering(
 		UserData: []byte(ruleKey),
 	}
 
-	// Insert DROP rules at the beginning, append ACCEPT rules at the end
-	if action == firewall.ActionDrop {
+	// Insert DROP and AUDIT rules at the beginning, append ACCEPT rules at the end
+	if action == firewall.ActionDrop || action == firewall.ActionAudit {
 		// TODO: Insert after the established rule
 		rule = r.conn.InsertRule(rule)
 	} else {
@@ -452,6 +467,7 @@ func (r *router) AddRouteFiltering(
 	}
 
 	r.rules[string(ruleKey)] = rule
+	r.routeRuleActions[string(ruleKey)] = action
 
 	log.Debugf("added route rule: sources=%v, destination=%v, proto=%v, sPort=%v, dPort=%v, action=%v", sources, destination, proto, sPort, dPort, action)
 
@@ -498,9 +514,46 @@ func (r *router) DeleteRouteRule(rule firewall.Rule) error {
 		return fmt.Errorf("decrement set counter: %w", err)
 	}
 
+	delete(r.routeRuleActions, ruleKey)
+
 	return nil
 }
 
+// RouteRuleStats reads the counters of the route filtering rules
+func (r *router) RouteRuleStats() ([]firewall.RouteRuleStats, error) {
+	chain := r.chains[chainNameRoutingFw]
+	if chain == nil {
+		return nil, nil
+	}
+
+	rules, err := r.conn.GetRules(r.workTable, chain)
+	if err != nil {
+		return nil, fmt.Errorf("get rules from %s: %w", chainNameRoutingFw, err)
+	}
+
+	var stats []firewall.RouteRuleStats
+	for _, rule := range rules {
+		action, ok := r.routeRuleActions[string(rule.UserData)]
+		if !ok {
+			continue
+		}
+
+		ruleStats := firewall.RouteRuleStats{
+			RuleID: string(rule.UserData),
+			Action: action,
+		}
+		for _, e := range rule.Exprs {
+			if counter, ok := e.(*expr.Counter); ok {
+				ruleStats.Packets = counter.Packets
+				ruleStats.Bytes = counter.Bytes
+				break
+			}
+		}
+		stats = append(stats, ruleStats)
+	}
+	return stats, nil
+}
+
 func (r *router) createIpSet(setName string, input setInput) (*nftables.Set, error) {
 	// overlapping prefixes will result in an error, so we need to merge them
 	prefixes := firewall.MergeIPRanges(input.prefixes)
@@ -561,12 +614,13 @@ func convertPrefixesToSet(prefixes []netip.Prefix) []nftables.SetElement {
 		firstIP := prefix.Addr()
 		lastIP := calculateLastIP(prefix).Next()
 
-		elements = append(elements,
-			// the nft tool also adds a line like this, see https://github.com/google/nftables/issues/247
-			// nftables.SetElement{Key: []byte{0, 0, 0, 0}, IntervalEnd: true},
-			nftables.SetElement{Key: firstIP.AsSlice()},
-			nftables.SetElement{Key: lastIP.AsSlice(), IntervalEnd: true},
-		)
+		// the nft tool also adds a line like this, see https://github.com/google/nftables/issues/247
+		// nftables.SetElement{Key: []byte{0, 0, 0, 0}, IntervalEnd: true},
+		elements = append(elements, nftables.SetElement{Key: firstIP.AsSlice()})
+		// a prefix ending with 255.255.255.255 has no address past it, its interval is left open
+		if lastIP.IsValid() {
+			elements = append(elements, nftables.SetElement{Key: lastIP.AsSlice(), IntervalEnd: true})
+		}
 	}
 	return elements
 }
@@ -1405,11 +1459,15 @@ func (r *router) AddDNATRule(rule firewall.ForwardRule) (firewall.Rule, error) {
 		return nil, fmt.Errorf("convert protocol to number: %w", err)
 	}
 
-	if err := r.addDnatRedirect(rule, protoNum, ruleKey); err != nil {
-		return nil, err
-	}
+	backends := rule.Backends()
+	for i, backend := range backends {
+		backendKey := backendRuleKey(ruleKey, i)
+		if err := r.addDnatRedirect(rule, backend, balanceExprs(i, len(backends)), protoNum, backendKey); err != nil {
+			return nil, err
+		}
 
-	r.addDnatMasq(rule, protoNum, ruleKey)
+		r.addDnatMasq(rule, backend, protoNum, backendKey)
+	}
 
 	// Unlike iptables, there's no point in adding "out" rules in the forward chain here as our policy is ACCEPT.
 	// To overcome DROP policies in other chains, we'd have to add rules to the chains there.
@@ -1423,7 +1481,36 @@ func (r *router) AddDNATRule(rule firewall.ForwardRule) (firewall.Rule, error) {
 	return &rule, nil
 }
 
-func (r *router) addDnatRedirect(rule firewall.ForwardRule, protoNum uint8, ruleKey string) error {
+// backendRuleKey is the key of the rules of the backend at index, the rules of the first backend use the key of the
+// forward rule
+func backendRuleKey(ruleKey string, index int) string {
+	if index == 0 {
+		return ruleKey
+	}
+	return fmt.Sprintf("%s;%d", ruleKey, index)
+}
+
+// balanceExprs spread the new connections round-robin across the backends. The rule of the backend at index takes
+// every (backends-index)th connection reaching it, the rule of the last backend takes the rest.
+func balanceExprs(index, backends int) []expr.Any {
+	if index >= backends-1 {
+		return nil
+	}
+	return []expr.Any{
+		&expr.Numgen{
+			Register: 1,
+			Modulus:  uint32(backends - index),
+			Type:     unix.NFT_NG_INCREMENTAL,
+		},
+		&expr.Cmp{
+			Op:       expr.CmpOpEq,
+			Register: 1,
+			Data:     binaryutil.NativeEndian.PutUint32(0),
+		},
+	}
+}
+
+func (r *router) addDnatRedirect(rule firewall.ForwardRule, backend netip.Addr, balance []expr.Any, protoNum uint8, ruleKey string) error {
 	dnatExprs := []expr.Any{
 		&expr.Meta{Key: expr.MetaKeyIIFNAME, Register: 1},
 		&expr.Cmp{
@@ -1445,16 +1532,17 @@ func (r *router) addDnatRedirect(rule firewall.ForwardRule, protoNum uint8, rule
 		},
 	}
 	dnatExprs = append(dnatExprs, applyPort(&rule.DestinationPort, false)...)
+	dnatExprs = append(dnatExprs, balance...)
 
 	// shifted translated port is not supported in nftables, so we hand this over to xtables
 	if rule.TranslatedPort.IsRange && len(rule.TranslatedPort.Values) == 2 {
 		if rule.TranslatedPort.Values[0] != rule.DestinationPort.Values[0] ||
 			rule.TranslatedPort.Values[1] != rule.DestinationPort.Values[1] {
-			return r.addXTablesRedirect(dnatExprs, ruleKey, rule)
+			return r.addXTablesRedirect(dnatExprs, ruleKey, rule, backend)
 		}
 	}
 
-	additionalExprs, regProtoMin, regProtoMax, err := r.handleTranslatedPort(rule)
+	additionalExprs, regProtoMin, regProtoMax, err := r.handleTranslatedPort(rule, backend)
 	if err != nil {
 		return err
 	}
@@ -1482,24 +1570,24 @@ func (r *router) addDnatRedirect(rule firewall.ForwardRule, protoNum uint8, rule
 	return nil
 }
 
-func (r *router) handleTranslatedPort(rule firewall.ForwardRule) ([]expr.Any, uint32, uint32, error) {
+func (r *router) handleTranslatedPort(rule firewall.ForwardRule, backend netip.Addr) ([]expr.Any, uint32, uint32, error) {
 	switch {
 	case rule.TranslatedPort.IsRange && len(rule.TranslatedPort.Values) == 2:
-		return r.handlePortRange(rule)
+		return r.handlePortRange(rule, backend)
 	case len(rule.TranslatedPort.Values) == 0:
-		return r.handleAddressOnly(rule)
+		return r.handleAddressOnly(backend)
 	case len(rule.TranslatedPort.Values) == 1:
-		return r.handleSinglePort(rule)
+		return r.handleSinglePort(rule, backend)
 	default:
 		return nil, 0, 0, fmt.Errorf("invalid translated port: %v", rule.TranslatedPort)
 	}
 }
 
-func (r *router) handlePortRange(rule firewall.ForwardRule) ([]expr.Any, uint32, uint32, error) {
+func (r *router) handlePortRange(rule firewall.ForwardRule, backend netip.Addr) ([]expr.Any, uint32, uint32, error) {
 	exprs := []expr.Any{
 		&expr.Immediate{
 			Register: 1,
-			Data:     rule.TranslatedAddress.AsSlice(),
+			Data:     backend.AsSlice(),
 		},
 		&expr.Immediate{
 			Register: 2,
@@ -1513,21 +1601,21 @@ func (r *router) handlePortRange(rule firewall.ForwardRule) ([]expr.Any, uint32,
 	return exprs, 2, 3, nil
 }
 
-func (r *router) handleAddressOnly(rule firewall.ForwardRule) ([]expr.Any, uint32, uint32, error) {
+func (r *router) handleAddressOnly(backend netip.Addr) ([]expr.Any, uint32, uint32, error) {
 	exprs := []expr.Any{
 		&expr.Immediate{
 			Register: 1,
-			Data:     rule.TranslatedAddress.AsSlice(),
+			Data:     backend.AsSlice(),
 		},
 	}
 	return exprs, 0, 0, nil
 }
 
-func (r *router) handleSinglePort(rule firewall.ForwardRule) ([]expr.Any, uint32, uint32, error) {
+func (r *router) handleSinglePort(rule firewall.ForwardRule, backend netip.Addr) ([]expr.Any, uint32, uint32, error) {
 	exprs := []expr.Any{
 		&expr.Immediate{
 			Register: 1,
-			Data:     rule.TranslatedAddress.AsSlice(),
+			Data:     backend.AsSlice(),
 		},
 		&expr.Immediate{
 			Register: 2,
@@ -1537,7 +1625,7 @@ func (r *router) handleSinglePort(rule firewall.ForwardRule) ([]expr.Any, uint32
 	return exprs, 2, 0, nil
 }
 
-func (r *router) addXTablesRedirect(dnatExprs []expr.Any, ruleKey string, rule firewall.ForwardRule) error {
+func (r *router) addXTablesRedirect(dnatExprs []expr.Any, ruleKey string, rule firewall.ForwardRule, backend netip.Addr) error {
 	dnatExprs = append(dnatExprs,
 		&expr.Counter{},
 		&expr.Target{
@@ -1546,8 +1634,8 @@ func (r *router) addXTablesRedirect(dnatExprs []expr.Any, ruleKey string, rule f
 			Info: &xt.NatRange2{
 				NatRange: xt.NatRange{
 					Flags:   uint(xt.NatRangeMapIPs | xt.NatRangeProtoSpecified | xt.NatRangeProtoOffset),
-					MinIP:   rule.TranslatedAddress.AsSlice(),
-					MaxIP:   rule.TranslatedAddress.AsSlice(),
+					MinIP:   backend.AsSlice(),
+					MaxIP:   backend.AsSlice(),
 					MinPort: rule.TranslatedPort.Values[0],
 					MaxPort: rule.TranslatedPort.Values[1],
 				},
@@ -1577,7 +1665,7 @@ func (r *router) addXTablesRedirect(dnatExprs []expr.Any, ruleKey string, rule f
 	return nil
 }
 
-func (r *router) addDnatMasq(rule firewall.ForwardRule, protoNum uint8, ruleKey string) {
+func (r *router) addDnatMasq(rule firewall.ForwardRule, backend netip.Addr, protoNum uint8, ruleKey string) {
 	masqExprs := []expr.Any{
 		&expr.Meta{Key: expr.MetaKeyOIFNAME, Register: 1},
 		&expr.Cmp{
@@ -1600,7 +1688,7 @@ func (r *router) addDnatMasq(rule firewall.ForwardRule, protoNum uint8, ruleKey
 		&expr.Cmp{
 			Op:       expr.CmpOpEq,
 			Register: 1,
-			Data:     rule.TranslatedAddress.AsSlice(),
+			Data:     backend.AsSlice(),
 		},
 	}
 
@@ -1629,16 +1717,26 @@ func (r *router) DeleteDNATRule(rule firewall.Rule) error {
 	}
 
 	var merr *multierror.Error
-	if dnatRule, exists := r.rules[ruleKey+dnatSuffix]; exists {
-		if err := r.conn.DelRule(dnatRule); err != nil {
-			merr = multierror.Append(merr, fmt.Errorf("delete dnat rule: %w", err))
+	var keys []string
+	for i := 0; ; i++ {
+		backendKey := backendRuleKey(ruleKey, i)
+		dnatRule, dnatExists := r.rules[backendKey+dnatSuffix]
+		masqRule, masqExists := r.rules[backendKey+snatSuffix]
+		if !dnatExists && !masqExists {
+			break
 		}
-	}
 
-	if masqRule, exists := r.rules[ruleKey+snatSuffix]; exists {
-		if err := r.conn.DelRule(masqRule); err != nil {
-			merr = multierror.Append(merr, fmt.Errorf("delete snat rule: %w", err))
+		if dnatExists {
+			if err := r.conn.DelRule(dnatRule); err != nil {
+				merr = multierror.Append(merr, fmt.Errorf("delete dnat rule: %w", err))
+			}
 		}
+		if masqExists {
+			if err := r.conn.DelRule(masqRule); err != nil {
+				merr = multierror.Append(merr, fmt.Errorf("delete snat rule: %w", err))
+			}
+		}
+		keys = append(keys, backendKey+dnatSuffix, backendKey+snatSuffix)
 	}
 
 	if err := r.conn.Flush(); err != nil {
@@ -1646,8 +1744,9 @@ func (r *router) DeleteDNATRule(rule firewall.Rule) error {
 	}
 
 	if merr == nil {
-		delete(r.rules, ruleKey+dnatSuffix)
-		delete(r.rules, ruleKey+snatSuffix)
+		for _, key := range keys {
+			delete(r.rules, key)
+		}
 	}
 
 	return nberrors.FormatErrorOrNil(merr)
@@ -1659,13 +1758,18 @@ func (r *router) UpdateSet(set firewall.Set, prefixes []netip.Prefix) error {
 		return fmt.Errorf("get set %s: %w", set.HashedName(), err)
 	}
 
+	// large sets are filled in batches, a single netlink message fails with too many elements
 	elements := convertPrefixesToSet(prefixes)
-	if err := r.conn.SetAddElements(nfset, elements); err != nil {
-		return fmt.Errorf("add elements to set %s: %w", set.HashedName(), err)
-	}
+	maxElements := maxPrefixesSet * 2
+	for start := 0; start < len(elements); start += maxElements {
+		batch := elements[start:min(start+maxElements, len(elements))]
+		if err := r.conn.SetAddElements(nfset, batch); err != nil {
+			return fmt.Errorf("add elements to set %s: %w", set.HashedName(), err)
+		}
 
-	if err := r.conn.Flush(); err != nil {
-		return fmt.Errorf(flushError, err)
+		if err := r.conn.Flush(); err != nil {
+			return fmt.Errorf(flushError, err)
+		}
 	}
 
 	log.Debugf("updated set %s with prefixes %v", set.HashedName(), prefixes)
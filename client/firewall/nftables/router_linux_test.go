@@ -546,6 +546,174 @@ func verifyRule(t *testing.T, rule *nftables.Rule, sources []netip.Prefix, desti
 	assert.True(t, containsAction(rule.Exprs, action), "Rule should contain correct action: %s", action)
 }
 
+func TestRouter_RouteFailover(t *testing.T) {
+	if check() != NFTABLES {
+		t.Skip("nftables not supported on this system")
+	}
+
+	workTable, err := createWorkTable()
+	require.NoError(t, err, "Failed to create work table")
+	defer deleteWorkTable()
+
+	// an external table, e.g. of a container runtime, dropping forwarded traffic
+	conn := &nftables.Conn{}
+	policy := nftables.ChainPolicyDrop
+	externalTable := conn.AddTable(&nftables.Table{Name: "nb-test-external", Family: nftables.TableFamilyINet})
+	externalChain := conn.AddChain(&nftables.Chain{
+		Name:     "forward",
+		Table:    externalTable,
+		Type:     nftables.ChainTypeFilter,
+		Hooknum:  nftables.ChainHookForward,
+		Priority: nftables.ChainPriorityFilter,
+		Policy:   &policy,
+	})
+	require.NoError(t, conn.Flush())
+	defer func() {
+		conn.DelTable(externalTable)
+		require.NoError(t, conn.Flush())
+	}()
+
+	r, err := newRouter(workTable, ifaceMock, iface.DefaultMTU)
+	require.NoError(t, err, "Failed to create router")
+	require.NoError(t, r.init(workTable))
+	defer func(r *router) {
+		require.NoError(t, r.Reset(), "Failed to reset rules")
+	}(r)
+
+	peer := netip.MustParseAddr("100.64.0.10")
+	network := netip.MustParsePrefix("192.168.1.0/24")
+
+	_, err = r.AddRouteFailover(peer, netip.MustParsePrefix("fd00::/64"))
+	assert.Error(t, err, "ipv6 networks aren't supported")
+
+	rule, err := r.AddRouteFailover(peer, network)
+	require.NoError(t, err)
+
+	findRule := func() *nftables.Rule {
+		rules, err := conn.GetRules(externalTable, externalChain)
+		require.NoError(t, err)
+		for _, nftRule := range rules {
+			if string(nftRule.UserData) == rule.ID() {
+				return nftRule
+			}
+		}
+		return nil
+	}
+
+	nftRule := findRule()
+	require.NotNil(t, nftRule, "failover rule should be inserted into the external chain")
+	assert.True(t, containsCIDRMatcher(nftRule.Exprs, network, true), "rule should match the network as source")
+	assert.True(t, containsCIDRMatcher(nftRule.Exprs, netip.PrefixFrom(peer, 32), false), "rule should match the peer as destination")
+	assert.True(t, containsAction(nftRule.Exprs, firewall.ActionAccept), "rule should accept")
+
+	require.NoError(t, r.DeleteRouteFailover(rule))
+	assert.Nil(t, findRule(), "failover rule should be removed from the external chain")
+	assert.Empty(t, r.failovers)
+}
+
+func TestRouter_ReversePathFilter(t *testing.T) {
+	if check() != NFTABLES {
+		t.Skip("nftables not supported on this system")
+	}
+
+	workTable, err := createWorkTable()
+	require.NoError(t, err, "Failed to create work table")
+	defer deleteWorkTable()
+
+	r, err := newRouter(workTable, ifaceMock, iface.DefaultMTU)
+	require.NoError(t, err, "Failed to create router")
+	require.NoError(t, r.init(workTable))
+	defer func(r *router) {
+		require.NoError(t, r.Reset(), "Failed to reset rules")
+	}(r)
+
+	setPrefixes := func(set *nftables.Set) []netip.Addr {
+		elements, err := r.conn.GetSetElements(set)
+		require.NoError(t, err)
+		var starts []netip.Addr
+		for _, element := range elements {
+			if !element.IntervalEnd {
+				addr, _ := netip.AddrFromSlice(element.Key)
+				starts = append(starts, addr)
+			}
+		}
+		return starts
+	}
+
+	overlay := []netip.Prefix{netip.MustParsePrefix("100.64.0.0/16"), netip.MustParsePrefix("10.1.0.0/24")}
+	require.NoError(t, r.SetReversePathFilter(overlay, []netip.Prefix{netip.MustParsePrefix("192.168.1.0/24")}))
+	require.NotNil(t, r.reversePath)
+	assert.ElementsMatch(t, []netip.Addr{netip.MustParseAddr("100.64.0.0"), netip.MustParseAddr("10.1.0.0")}, setPrefixes(r.reversePath.overlay))
+	assert.ElementsMatch(t, []netip.Addr{netip.MustParseAddr("192.168.1.0")}, setPrefixes(r.reversePath.local))
+
+	// an exit node routes every network, the interval of 0.0.0.0/0 is left open
+	require.NoError(t, r.SetReversePathFilter(overlay[:1], []netip.Prefix{netip.MustParsePrefix("0.0.0.0/0")}))
+	assert.ElementsMatch(t, []netip.Addr{netip.MustParseAddr("100.64.0.0")}, setPrefixes(r.reversePath.overlay))
+	assert.ElementsMatch(t, []netip.Addr{netip.MustParseAddr("0.0.0.0")}, setPrefixes(r.reversePath.local))
+
+	stats, err := r.ReversePathStats()
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	assert.Equal(t, firewall.ReversePathOverlayRuleID, stats[0].RuleID)
+	assert.Equal(t, firewall.ReversePathLocalRuleID, stats[1].RuleID)
+
+	require.NoError(t, r.RemoveReversePathFilter())
+	assert.Nil(t, r.reversePath)
+	stats, err = r.ReversePathStats()
+	require.NoError(t, err)
+	assert.Empty(t, stats)
+}
+
+func TestRouter_AddDNATRuleBackends(t *testing.T) {
+	if check() != NFTABLES {
+		t.Skip("nftables not supported on this system")
+	}
+
+	workTable, err := createWorkTable()
+	require.NoError(t, err, "Failed to create work table")
+	defer deleteWorkTable()
+
+	r, err := newRouter(workTable, ifaceMock, iface.DefaultMTU)
+	require.NoError(t, err, "Failed to create router")
+	require.NoError(t, r.init(workTable))
+	defer func(r *router) {
+		require.NoError(t, r.Reset(), "Failed to reset rules")
+	}(r)
+
+	rule := firewall.ForwardRule{
+		Protocol:            firewall.ProtocolTCP,
+		DestinationPort:     firewall.Port{Values: []uint16{443}},
+		TranslatedAddress:   netip.MustParseAddr("100.64.0.9"),
+		TranslatedAddresses: []netip.Addr{netip.MustParseAddr("100.64.0.10")},
+		TranslatedPort:      firewall.Port{Values: []uint16{8443}},
+	}
+
+	added, err := r.AddDNATRule(rule)
+	require.NoError(t, err)
+
+	ruleKey := rule.ID()
+	for _, key := range []string{ruleKey, ruleKey + ";1"} {
+		dnatRule, exists := r.rules[key+dnatSuffix]
+		require.True(t, exists, "dnat rule of backend %s should exist", key)
+		_, exists = r.rules[key+snatSuffix]
+		require.True(t, exists, "snat rule of backend %s should exist", key)
+
+		var balanced bool
+		for _, e := range dnatRule.Exprs {
+			if _, ok := e.(*expr.Numgen); ok {
+				balanced = true
+			}
+		}
+		assert.Equal(t, key == ruleKey, balanced, "only the first backend takes every nth connection")
+	}
+
+	require.NoError(t, r.DeleteDNATRule(added))
+	for _, key := range []string{ruleKey, ruleKey + ";1"} {
+		assert.NotContains(t, r.rules, key+dnatSuffix)
+		assert.NotContains(t, r.rules, key+snatSuffix)
+	}
+}
+
 func containsSetLookup(exprs []expr.Any) bool {
 	for _, e := range exprs {
 		if _, ok := e.(*expr.Lookup); ok {
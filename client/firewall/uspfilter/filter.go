@@ -64,6 +64,9 @@ const (
 )
 
 var errNatNotSupported = errors.New("nat not supported with userspace firewall")
+var errKillSwitchUnsupported = errors.New("kill switch requires a native firewall")
+var errRouteFailoverUnsupported = errors.New("route failover rules require the native router")
+var errRouteRuleStatsUnsupported = errors.New("the native firewall doesn't count route rules")
 
 // RuleSet is a set of rules grouped by a string key
 type RuleSet map[string]PeerRule
@@ -72,17 +75,25 @@ type RouteRules []*RouteRule
 
 func (r RouteRules) Sort() {
 	slices.SortStableFunc(r, func(a, b *RouteRule) int {
-		// Deny rules come first
-		if a.action == firewall.ActionDrop && b.action != firewall.ActionDrop {
-			return -1
-		}
-		if a.action != firewall.ActionDrop && b.action == firewall.ActionDrop {
-			return 1
+		// Deny rules come first, then the audit rules, so they see the traffic the accept rules would let through
+		if rankA, rankB := routeActionRank(a.action), routeActionRank(b.action); rankA != rankB {
+			return rankA - rankB
 		}
 		return strings.Compare(a.id, b.id)
 	})
 }
 
+func routeActionRank(action firewall.Action) int {
+	switch action {
+	case firewall.ActionDrop:
+		return 0
+	case firewall.ActionAudit:
+		return 1
+	default:
+		return 2
+	}
+}
+
 // Manager userspace firewall manager
 type Manager struct {
 	outgoingRules     map[netip.Addr]RuleSet
@@ -132,6 +143,14 @@ type Manager struct {
 	netstackServices     map[serviceKey]struct{}
 	netstackServiceMutex sync.RWMutex
 
+	// outboundLimiter drops outbound packets exceeding a bandwidth limit
+	outboundLimiter atomic.Pointer[func(dst netip.Addr, size int) bool]
+
+	// rpfOverlay are the sources the reverse path filter accepts for routed packets, nil if it isn't installed
+	rpfOverlay atomic.Pointer[[]netip.Prefix]
+	rpfPackets atomic.Uint64
+	rpfBytes   atomic.Uint64
+
 	mtu             uint16
 	mssClampValue   uint16
 	mssClampEnabled bool
@@ -567,6 +586,43 @@ func (m *Manager) SetLegacyManagement(isLegacy bool) error {
 	return m.nativeFirewall.SetLegacyManagement(isLegacy)
 }
 
+// EnableKillSwitch delegates to the native firewall, the userspace filter only sees tunnel traffic
+func (m *Manager) EnableKillSwitch(rules []firewall.KillSwitchRule) error {
+	killSwitch, ok := m.nativeFirewall.(firewall.KillSwitch)
+	if !ok {
+		return errKillSwitchUnsupported
+	}
+	return killSwitch.EnableKillSwitch(rules)
+}
+
+// DisableKillSwitch delegates to the native firewall
+func (m *Manager) DisableKillSwitch() error {
+	killSwitch, ok := m.nativeFirewall.(firewall.KillSwitch)
+	if !ok {
+		return nil
+	}
+	return killSwitch.DisableKillSwitch()
+}
+
+// AllowRouteFailover delegates to the native firewall if it routes the traffic.
+// The userspace forwarder terminates the connections, so they can't be taken over from another routing peer.
+func (m *Manager) AllowRouteFailover(peer netip.Addr, network netip.Prefix) (firewall.Rule, error) {
+	failover, ok := m.nativeFirewall.(firewall.RouteFailover)
+	if !ok || !m.nativeRouter.Load() {
+		return nil, errRouteFailoverUnsupported
+	}
+	return failover.AllowRouteFailover(peer, network)
+}
+
+// RemoveRouteFailover delegates to the native firewall
+func (m *Manager) RemoveRouteFailover(rule firewall.Rule) error {
+	failover, ok := m.nativeFirewall.(firewall.RouteFailover)
+	if !ok {
+		return nil
+	}
+	return failover.RemoveRouteFailover(rule)
+}
+
 // Flush doesn't need to be implemented for this manager
 func (m *Manager) Flush() error { return nil }
 
@@ -662,6 +718,11 @@ func (m *Manager) filterOutbound(packetData []byte, size int) bool {
 		}
 	}
 
+	if limiter := m.outboundLimiter.Load(); limiter != nil && !(*limiter)(dstIP, size) {
+		m.logger.Trace2("Dropping outbound packet to %s exceeding the bandwidth limit, size %d", dstIP, size)
+		return true
+	}
+
 	m.trackOutbound(d, srcIP, dstIP, packetData, size)
 	m.translateOutboundDNAT(packetData, d)
 
@@ -996,10 +1057,27 @@ func (m *Manager) handleRoutedTraffic(d *decoder, srcIP, dstIP netip.Addr, packe
 		return false
 	}
 
+	if m.reversePathDrops(srcIP, size) {
+		m.logger.Trace2("Dropping routed packet (spoofed source): src=%s dst=%s", srcIP, dstIP)
+		return true
+	}
+
 	protoLayer := d.decoded[1]
 	srcPort, dstPort := getPortsFromPacket(d)
 
-	ruleID, pass := m.routeACLsPass(srcIP, dstIP, protoLayer, srcPort, dstPort)
+	var ruleID []byte
+	pass := false
+	if rule := m.matchRouteRule(srcIP, dstIP, protoLayer, srcPort, dstPort); rule != nil {
+		rule.packets.Add(1)
+		rule.bytes.Add(uint64(size))
+		ruleID = rule.mgmtId
+		pass = rule.action != firewall.ActionDrop
+
+		if rule.action == firewall.ActionAudit {
+			m.auditRoutedPacket(d, rule, srcIP, dstIP, srcPort, dstPort, size)
+		}
+	}
+
 	if !pass {
 		proto := getProtocolFromPacket(d)
 
@@ -1239,15 +1317,100 @@ func validateRule(ip netip.Addr, packetData []byte, rules map[string]PeerRule, d
 
 // routeACLsPass returns true if the packet is allowed by the route ACLs
 func (m *Manager) routeACLsPass(srcIP, dstIP netip.Addr, protoLayer gopacket.LayerType, srcPort, dstPort uint16) ([]byte, bool) {
+	rule := m.matchRouteRule(srcIP, dstIP, protoLayer, srcPort, dstPort)
+	if rule == nil {
+		return nil, false
+	}
+	return rule.mgmtId, rule.action != firewall.ActionDrop
+}
+
+// matchRouteRule returns the first route rule that matches the packet, nil if none does
+func (m *Manager) matchRouteRule(srcIP, dstIP netip.Addr, protoLayer gopacket.LayerType, srcPort, dstPort uint16) *RouteRule {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
 	for _, rule := range m.routeRules {
 		if matches := m.ruleMatches(rule, srcIP, dstIP, protoLayer, srcPort, dstPort); matches {
-			return rule.mgmtId, rule.action == firewall.ActionAccept
+			return rule
+		}
+	}
+	return nil
+}
+
+// auditRoutedPacket reports a routed packet that passed because of an audit rule
+func (m *Manager) auditRoutedPacket(d *decoder, rule *RouteRule, srcIP, dstIP netip.Addr, srcPort, dstPort uint16, size int) {
+	proto := getProtocolFromPacket(d)
+
+	m.logger.Trace6("Auditing routed packet: rule_id=%s proto=%v src=%s:%d dst=%s:%d",
+		rule.mgmtId, proto, srcIP, srcPort, dstIP, dstPort)
+
+	m.flowLogger.StoreEvent(nftypes.EventFields{
+		FlowID:     uuid.New(),
+		Type:       nftypes.TypeAudit,
+		RuleID:     rule.mgmtId,
+		Direction:  nftypes.Ingress,
+		Protocol:   proto,
+		SourceIP:   srcIP,
+		DestIP:     dstIP,
+		SourcePort: srcPort,
+		DestPort:   dstPort,
+		RxPackets:  1,
+		RxBytes:    uint64(size),
+	})
+}
+
+// SetSizes returns the number of destinations of the route rule sets, or the sizes of the native router's sets
+func (m *Manager) SetSizes() ([]firewall.SetSize, error) {
+	if m.nativeRouter.Load() && m.nativeFirewall != nil {
+		reporter, ok := m.nativeFirewall.(firewall.SetSizeReporter)
+		if !ok {
+			return nil, nil
+		}
+		return reporter.SetSizes()
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	seen := make(map[firewall.Set]struct{})
+	var sizes []firewall.SetSize
+	for _, rule := range m.routeRules {
+		if rule.dstSet == (firewall.Set{}) {
+			continue
+		}
+		if _, ok := seen[rule.dstSet]; ok {
+			continue
 		}
+		seen[rule.dstSet] = struct{}{}
+		sizes = append(sizes, firewall.SetSize{Name: rule.dstSet.HashedName(), Elements: len(rule.destinations)})
+	}
+	return sizes, nil
+}
+
+// RouteRuleStats returns the counters of the route rules
+func (m *Manager) RouteRuleStats() ([]firewall.RouteRuleStats, error) {
+	if m.nativeRouter.Load() && m.nativeFirewall != nil {
+		counter, ok := m.nativeFirewall.(firewall.RouteRuleCounter)
+		if !ok {
+			return nil, errRouteRuleStatsUnsupported
+		}
+		return counter.RouteRuleStats()
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	stats := make([]firewall.RouteRuleStats, 0, len(m.routeRules))
+	for _, rule := range m.routeRules {
+		stats = append(stats, firewall.RouteRuleStats{
+			RuleID:   rule.id,
+			PolicyID: rule.mgmtId,
+			Action:   rule.action,
+			Packets:  rule.packets.Load(),
+			Bytes:    rule.bytes.Load(),
+		})
 	}
-	return nil, false
+	return stats, nil
 }
 
 func (m *Manager) ruleMatches(rule *RouteRule, srcAddr, dstAddr netip.Addr, protoLayer gopacket.LayerType, srcPort, dstPort uint16) bool {
@@ -1345,6 +1508,16 @@ func (m *Manager) RemovePacketHook(hookID string) error {
 	return fmt.Errorf("hook with given id not found")
 }
 
+// SetOutboundLimiter sets a function deciding whether an outbound packet fits into the bandwidth limits.
+// Packets it rejects are dropped. A nil limiter removes the limits.
+func (m *Manager) SetOutboundLimiter(allow func(dst netip.Addr, size int) bool) {
+	if allow == nil {
+		m.outboundLimiter.Store(nil)
+		return
+	}
+	m.outboundLimiter.Store(&allow)
+}
+
 // SetLogLevel sets the log level for the firewall manager
 func (m *Manager) SetLogLevel(level log.Level) {
 	if m.logger != nil {
@@ -1498,3 +1498,86 @@ func TestRouteACLSet(t *testing.T) {
 	_, isAllowed = manager.routeACLsPass(srcIP, dstIP, protoToLayer(fw.ProtocolTCP, layers.LayerTypeIPv4), 12345, 80)
 	require.True(t, isAllowed, "After set update, traffic to the added network should be allowed")
 }
+
+func TestRouteACLAudit(t *testing.T) {
+	manager := setupRoutedManager(t, "10.10.0.100/16")
+
+	sources := []netip.Prefix{netip.MustParsePrefix("100.10.0.0/16")}
+	dest := fw.Network{Prefix: netip.MustParsePrefix("192.168.1.0/24")}
+
+	acceptRule, err := manager.AddRouteFiltering([]byte("accept"), sources, dest, fw.ProtocolTCP, nil, nil, fw.ActionAccept)
+	require.NoError(t, err)
+	auditRule, err := manager.AddRouteFiltering([]byte("audit"), sources, dest, fw.ProtocolTCP, nil, &fw.Port{Values: []uint16{80}}, fw.ActionAudit)
+	require.NoError(t, err)
+	dropRule, err := manager.AddRouteFiltering([]byte("drop"), sources, dest, fw.ProtocolTCP, nil, &fw.Port{Values: []uint16{22}}, fw.ActionDrop)
+	require.NoError(t, err)
+
+	srcIP := netip.MustParseAddr("100.10.0.1")
+	dstIP := netip.MustParseAddr("192.168.1.100")
+	tcp := protoToLayer(fw.ProtocolTCP, layers.LayerTypeIPv4)
+
+	// the drop rule is evaluated before the audit rule
+	rule := manager.matchRouteRule(srcIP, dstIP, tcp, 12345, 22)
+	require.NotNil(t, rule)
+	require.Equal(t, dropRule.ID(), rule.ID())
+
+	// the audit rule is evaluated before the accept rule and lets the traffic through
+	rule = manager.matchRouteRule(srcIP, dstIP, tcp, 12345, 80)
+	require.NotNil(t, rule)
+	require.Equal(t, auditRule.ID(), rule.ID())
+	ruleID, pass := manager.routeACLsPass(srcIP, dstIP, tcp, 12345, 80)
+	require.True(t, pass)
+	require.Equal(t, []byte("audit"), ruleID)
+
+	rule = manager.matchRouteRule(srcIP, dstIP, tcp, 12345, 443)
+	require.NotNil(t, rule)
+	require.Equal(t, acceptRule.ID(), rule.ID())
+
+	rule = manager.matchRouteRule(srcIP, dstIP, tcp, 12345, 80)
+	rule.packets.Add(1)
+	rule.bytes.Add(100)
+
+	stats, err := manager.RouteRuleStats()
+	require.NoError(t, err)
+	require.Len(t, stats, len(manager.routeRules))
+	for _, s := range stats {
+		if s.RuleID != auditRule.ID() {
+			require.Zero(t, s.Packets)
+			continue
+		}
+		require.Equal(t, fw.ActionAudit, s.Action)
+		require.Equal(t, []byte("audit"), s.PolicyID)
+		require.Equal(t, uint64(1), s.Packets)
+		require.Equal(t, uint64(100), s.Bytes)
+	}
+}
+
+func TestReversePathFilter(t *testing.T) {
+	manager := setupRoutedManager(t, "10.10.0.100/16")
+
+	spoofed := netip.MustParseAddr("192.168.5.1")
+	require.False(t, manager.reversePathDrops(spoofed, 100), "no source is dropped without the filter")
+
+	require.NoError(t, manager.SetReversePathFilter(
+		[]netip.Prefix{netip.MustParsePrefix("10.10.0.0/16"), netip.MustParsePrefix("172.16.0.0/24")},
+		[]netip.Prefix{netip.MustParsePrefix("192.168.1.0/24")},
+	))
+
+	require.False(t, manager.reversePathDrops(netip.MustParseAddr("10.10.0.1"), 100))
+	require.False(t, manager.reversePathDrops(netip.MustParseAddr("172.16.0.9"), 100), "sources routed by a peer pass")
+	require.True(t, manager.reversePathDrops(spoofed, 100))
+	require.False(t, manager.reversePathDrops(netip.MustParseAddr("fd00::1"), 100), "ipv6 isn't checked")
+
+	stats, err := manager.ReversePathStats()
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	require.Equal(t, fw.ReversePathOverlayRuleID, stats[0].RuleID)
+	require.Equal(t, uint64(1), stats[0].Packets)
+	require.Equal(t, uint64(100), stats[0].Bytes)
+
+	require.NoError(t, manager.RemoveReversePathFilter())
+	require.False(t, manager.reversePathDrops(spoofed, 100))
+	stats, err = manager.ReversePathStats()
+	require.NoError(t, err)
+	require.Empty(t, stats)
+}
@@ -40,6 +40,7 @@ type Forwarder struct {
 	stack            *stack.Stack
 	endpoint         *endpoint
 	udpForwarder     *udpForwarder
+	tcpProxy         *tcpProxy
 	ctx              context.Context
 	cancel           context.CancelFunc
 	ip               tcpip.Address
@@ -111,6 +112,7 @@ func New(iface common.IFaceMapper, logger *nblog.Logger, flowLogger nftypes.Flow
 		stack:         s,
 		endpoint:      endpoint,
 		udpForwarder:  newUDPForwarder(mtu, logger, flowLogger),
+		tcpProxy:      newTCPProxyFromEnv(logger),
 		ctx:           ctx,
 		cancel:        cancel,
 		netstack:      netstack,
@@ -0,0 +1,206 @@
+package forwarder
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/firewall/uspfilter/conntrack"
+	nblog "github.com/netbirdio/netbird/client/firewall/uspfilter/log"
+)
+
+const (
+	// EnvTCPIdleTimeout sets how long a forwarded TCP connection may go without data in either direction before it
+	// is closed, as a Go duration. Zero disables the timeout.
+	EnvTCPIdleTimeout = "NB_FORWARDER_TCP_IDLE_TIMEOUT"
+	// EnvTCPBufferSize sets the size of the copy buffer of each direction of a forwarded TCP connection in bytes
+	EnvTCPBufferSize = "NB_FORWARDER_TCP_BUFFER_SIZE"
+
+	// defaultTCPIdleTimeout matches the conntrack timeout of established connections, so idle replication or
+	// control connections live as long as they would through the native firewall
+	defaultTCPIdleTimeout = conntrack.DefaultTCPTimeout
+	defaultTCPBufferSize  = 32 * 1024
+	minTCPBufferSize      = 4 * 1024
+	maxTCPBufferSize      = 4 * 1024 * 1024
+)
+
+// tcpProxy copies the data between the two sides of a forwarded TCP connection
+type tcpProxy struct {
+	logger      *nblog.Logger
+	idleTimeout time.Duration
+	bufPool     sync.Pool
+}
+
+// tcpRelayResult is what a relay transferred and why it ended
+type tcpRelayResult struct {
+	bytesInToOut int64
+	bytesOutToIn int64
+	errInToOut   error
+	errOutToIn   error
+	// idle is set if the connection was closed by the idle timeout
+	idle bool
+}
+
+func newTCPProxy(logger *nblog.Logger, idleTimeout time.Duration, bufferSize int) *tcpProxy {
+	p := &tcpProxy{logger: logger, idleTimeout: idleTimeout}
+	p.bufPool.New = func() any {
+		b := make([]byte, bufferSize)
+		return &b
+	}
+	return p
+}
+
+// newTCPProxyFromEnv returns the proxy with the idle timeout and buffer size of the environment
+func newTCPProxyFromEnv(logger *nblog.Logger) *tcpProxy {
+	idleTimeout := defaultTCPIdleTimeout
+	if val := os.Getenv(EnvTCPIdleTimeout); val != "" {
+		timeout, err := time.ParseDuration(val)
+		if err != nil || timeout < 0 {
+			log.Warnf("ignoring invalid %s %q, using %s", EnvTCPIdleTimeout, val, idleTimeout)
+		} else {
+			idleTimeout = timeout
+		}
+	}
+
+	bufferSize := defaultTCPBufferSize
+	if val := os.Getenv(EnvTCPBufferSize); val != "" {
+		size, err := strconv.Atoi(val)
+		if err != nil || size < minTCPBufferSize || size > maxTCPBufferSize {
+			log.Warnf("ignoring invalid %s %q, expected %d to %d bytes, using %d", EnvTCPBufferSize, val, minTCPBufferSize, maxTCPBufferSize, bufferSize)
+		} else {
+			bufferSize = size
+		}
+	}
+
+	return newTCPProxy(logger, idleTimeout, bufferSize)
+}
+
+// relay copies the data between the connections until both directions ended, an error aborts the connection or it
+// went idle, and closes both connections. A direction that ends with EOF half-closes its destination, so protocols
+// that shut down one direction and keep reading, like FTP transfers or database replication, keep working.
+func (p *tcpProxy) relay(ctx context.Context, inConn, outConn net.Conn) tcpRelayResult {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var res tcpRelayResult
+	var lastActivity atomic.Int64
+	lastActivity.Store(time.Now().UnixNano())
+
+	var idle atomic.Bool
+	if p.idleTimeout > 0 {
+		go func() {
+			if p.watchIdle(ctx, &lastActivity) {
+				idle.Store(true)
+				cancel()
+			}
+		}()
+	}
+
+	var closeOnce sync.Once
+	closeConns := func() {
+		closeOnce.Do(func() {
+			if err := inConn.Close(); err != nil && !isClosedError(err) {
+				p.logger.Debug1("forwarder: inConn close error: %v", err)
+			}
+			if err := outConn.Close(); err != nil && !isClosedError(err) {
+				p.logger.Debug1("forwarder: outConn close error: %v", err)
+			}
+		})
+	}
+	go func() {
+		<-ctx.Done()
+		closeConns()
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		res.bytesInToOut, res.errInToOut = p.copy(outConn, inConn, &lastActivity)
+		p.endDirection(outConn, res.errInToOut, cancel)
+	}()
+	go func() {
+		defer wg.Done()
+		res.bytesOutToIn, res.errOutToIn = p.copy(inConn, outConn, &lastActivity)
+		p.endDirection(inConn, res.errOutToIn, cancel)
+	}()
+	wg.Wait()
+
+	cancel()
+	closeConns()
+	res.idle = idle.Load()
+	return res
+}
+
+// copy copies from src to dst until EOF, which is reported as nil error, and records the time of each read
+func (p *tcpProxy) copy(dst, src net.Conn, lastActivity *atomic.Int64) (int64, error) {
+	bufp := p.bufPool.Get().(*[]byte)
+	defer p.bufPool.Put(bufp)
+	buf := *bufp
+
+	var written int64
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			lastActivity.Store(time.Now().UnixNano())
+			wn, werr := dst.Write(buf[:n])
+			written += int64(wn)
+			if werr != nil {
+				return written, werr
+			}
+			if wn != n {
+				return written, io.ErrShortWrite
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return written, nil
+		}
+		if err != nil {
+			return written, err
+		}
+	}
+}
+
+// watchIdle reports true once the connection went without data for the idle timeout, false if ctx is done first
+func (p *tcpProxy) watchIdle(ctx context.Context, lastActivity *atomic.Int64) bool {
+	ticker := time.NewTicker(max(p.idleTimeout/10, 10*time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+			if time.Since(time.Unix(0, lastActivity.Load())) > p.idleTimeout {
+				return true
+			}
+		}
+	}
+}
+
+// endDirection propagates the end of a direction to its destination. EOF half-closes the destination and leaves the
+// other direction running, an error or a destination that can't be half-closed aborts the connection.
+func (p *tcpProxy) endDirection(dst net.Conn, err error, cancel context.CancelFunc) {
+	if err != nil {
+		cancel()
+		return
+	}
+
+	cw, ok := dst.(interface{ CloseWrite() error })
+	if !ok {
+		cancel()
+		return
+	}
+	if err := cw.CloseWrite(); err != nil {
+		p.logger.Debug1("forwarder: half-close error: %v", err)
+		cancel()
+	}
+}
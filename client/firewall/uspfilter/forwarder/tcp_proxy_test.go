@@ -0,0 +1,123 @@
+package forwarder
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nblog "github.com/netbirdio/netbird/client/firewall/uspfilter/log"
+)
+
+// tcpPair returns the two ends of a loopback TCP connection
+func tcpPair(t *testing.T) (*net.TCPConn, *net.TCPConn) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			close(accepted)
+			return
+		}
+		accepted <- conn
+	}()
+
+	dialed, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+	server, ok := <-accepted
+	require.True(t, ok)
+
+	t.Cleanup(func() {
+		_ = dialed.Close()
+		_ = server.Close()
+	})
+	return dialed.(*net.TCPConn), server.(*net.TCPConn)
+}
+
+func startRelay(t *testing.T, proxy *tcpProxy) (client, server *net.TCPConn, done <-chan tcpRelayResult) {
+	t.Helper()
+
+	client, in := tcpPair(t)
+	out, server := tcpPair(t)
+
+	results := make(chan tcpRelayResult, 1)
+	go func() {
+		results <- proxy.relay(context.Background(), in, out)
+	}()
+	return client, server, results
+}
+
+func TestTCPProxyHalfClose(t *testing.T) {
+	proxy := newTCPProxy(nblog.NewFromLogrus(log.StandardLogger()), time.Minute, minTCPBufferSize)
+	client, server, done := startRelay(t, proxy)
+
+	_, err := client.Write([]byte("request"))
+	require.NoError(t, err)
+	require.NoError(t, client.CloseWrite())
+
+	// the server sees the end of the request and still answers over the other direction
+	request, err := io.ReadAll(server)
+	require.NoError(t, err)
+	assert.Equal(t, "request", string(request))
+
+	_, err = server.Write([]byte("response"))
+	require.NoError(t, err)
+	require.NoError(t, server.Close())
+
+	response, err := io.ReadAll(client)
+	require.NoError(t, err)
+	assert.Equal(t, "response", string(response))
+
+	select {
+	case res := <-done:
+		assert.Equal(t, int64(len("request")), res.bytesInToOut)
+		assert.Equal(t, int64(len("response")), res.bytesOutToIn)
+		assert.False(t, res.idle)
+	case <-time.After(5 * time.Second):
+		t.Fatal("relay didn't end after both directions closed")
+	}
+}
+
+func TestTCPProxyIdleTimeout(t *testing.T) {
+	proxy := newTCPProxy(nblog.NewFromLogrus(log.StandardLogger()), 200*time.Millisecond, minTCPBufferSize)
+	client, _, done := startRelay(t, proxy)
+
+	_, err := client.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	select {
+	case res := <-done:
+		assert.True(t, res.idle)
+	case <-time.After(5 * time.Second):
+		t.Fatal("idle connection wasn't closed")
+	}
+
+	_, err = io.ReadAll(client)
+	assert.NoError(t, err, "the client sees the closed connection")
+}
+
+func TestNewTCPProxyFromEnv(t *testing.T) {
+	logger := nblog.NewFromLogrus(log.StandardLogger())
+
+	t.Setenv(EnvTCPIdleTimeout, "10m")
+	t.Setenv(EnvTCPBufferSize, "65536")
+	proxy := newTCPProxyFromEnv(logger)
+	assert.Equal(t, 10*time.Minute, proxy.idleTimeout)
+	assert.Len(t, *proxy.bufPool.Get().(*[]byte), 65536)
+
+	t.Setenv(EnvTCPIdleTimeout, "soon")
+	t.Setenv(EnvTCPBufferSize, "1")
+	proxy = newTCPProxyFromEnv(logger)
+	assert.Equal(t, defaultTCPIdleTimeout, proxy.idleTimeout)
+	assert.Len(t, *proxy.bufPool.Get().(*[]byte), defaultTCPBufferSize)
+}
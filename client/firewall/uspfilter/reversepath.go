@@ -0,0 +1,77 @@
+package uspfilter
+
+import (
+	"errors"
+	"net/netip"
+
+	firewall "github.com/netbirdio/netbird/client/firewall/manager"
+)
+
+var errReversePathUnsupported = errors.New("the native firewall doesn't filter reverse paths")
+
+// SetReversePathFilter delegates to the native firewall if it routes the traffic. Otherwise the routed packets
+// from the overlay are checked against the overlay networks. The local networks aren't checked, the userspace
+// forwarder terminates the connections and only passes the replies to the connections it established.
+func (m *Manager) SetReversePathFilter(overlay, local []netip.Prefix) error {
+	if m.nativeRouter.Load() {
+		rpf, ok := m.nativeFirewall.(firewall.ReversePathFilter)
+		if !ok {
+			return errReversePathUnsupported
+		}
+		return rpf.SetReversePathFilter(overlay, local)
+	}
+
+	networks := firewall.ReversePathNetworks(overlay)
+	m.rpfOverlay.Store(&networks)
+	return nil
+}
+
+// RemoveReversePathFilter removes the filter of the userspace router and of the native firewall
+func (m *Manager) RemoveReversePathFilter() error {
+	m.rpfOverlay.Store(nil)
+
+	if rpf, ok := m.nativeFirewall.(firewall.ReversePathFilter); ok {
+		return rpf.RemoveReversePathFilter()
+	}
+	return nil
+}
+
+// ReversePathStats returns the counters of the packets dropped by the reverse path filter
+func (m *Manager) ReversePathStats() ([]firewall.RouteRuleStats, error) {
+	if m.nativeRouter.Load() {
+		rpf, ok := m.nativeFirewall.(firewall.ReversePathFilter)
+		if !ok {
+			return nil, errReversePathUnsupported
+		}
+		return rpf.ReversePathStats()
+	}
+
+	if m.rpfOverlay.Load() == nil {
+		return nil, nil
+	}
+	return []firewall.RouteRuleStats{{
+		RuleID:  firewall.ReversePathOverlayRuleID,
+		Action:  firewall.ActionDrop,
+		Packets: m.rpfPackets.Load(),
+		Bytes:   m.rpfBytes.Load(),
+	}}, nil
+}
+
+// reversePathDrops reports whether a routed packet from the overlay is dropped for a source outside the overlay
+// networks. Only IPv4 packets are checked, like by the native firewalls.
+func (m *Manager) reversePathDrops(srcIP netip.Addr, size int) bool {
+	networks := m.rpfOverlay.Load()
+	if networks == nil || !srcIP.Is4() {
+		return false
+	}
+
+	for _, network := range *networks {
+		if network.Contains(srcIP) {
+			return false
+		}
+	}
+
+	m.rpfPackets.Add(1)
+	m.rpfBytes.Add(uint64(size))
+	return true
+}
@@ -2,6 +2,7 @@ package uspfilter
 
 import (
 	"net/netip"
+	"sync/atomic"
 
 	"github.com/google/gopacket"
 
@@ -38,6 +39,10 @@ type RouteRule struct {
 	srcPort      *firewall.Port
 	dstPort      *firewall.Port
 	action       firewall.Action
+
+	// packets and bytes count the traffic that matched the rule
+	packets atomic.Uint64
+	bytes   atomic.Uint64
 }
 
 // ID returns the rule id
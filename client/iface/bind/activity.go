@@ -18,6 +18,14 @@ const (
 type PeerRecord struct {
 	Address      netip.AddrPort
 	LastActivity atomic.Int64 // UnixNano timestamp
+	Packets      atomic.Uint64
+	Bytes        atomic.Uint64
+}
+
+// ActivityCounters are the transport packets and bytes received from a peer
+type ActivityCounters struct {
+	Packets uint64
+	Bytes   uint64
 }
 
 type ActivityRecorder struct {
@@ -46,6 +54,21 @@ func (r *ActivityRecorder) GetLastActivities() map[string]monotime.Time {
 	return activities
 }
 
+// GetActivityCounters returns a snapshot of the received transport packets and bytes per peer
+func (r *ActivityRecorder) GetActivityCounters() map[string]ActivityCounters {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counters := make(map[string]ActivityCounters, len(r.peers))
+	for key, record := range r.peers {
+		counters[key] = ActivityCounters{
+			Packets: record.Packets.Load(),
+			Bytes:   record.Bytes.Load(),
+		}
+	}
+	return counters
+}
+
 // UpsertAddress adds or updates the address for a publicKey
 func (r *ActivityRecorder) UpsertAddress(publicKey string, address netip.AddrPort) {
 	r.mu.Lock()
@@ -76,8 +99,8 @@ func (r *ActivityRecorder) Remove(publicKey string) {
 	}
 }
 
-// record updates LastActivity for the given address using atomic store
-func (r *ActivityRecorder) record(address netip.AddrPort) {
+// record counts the packet and updates LastActivity for the given address using atomic store
+func (r *ActivityRecorder) record(address netip.AddrPort, size int) {
 	r.mu.RLock()
 	record, ok := r.addrToPeer[address]
 	r.mu.RUnlock()
@@ -86,6 +109,9 @@ func (r *ActivityRecorder) record(address netip.AddrPort) {
 		return
 	}
 
+	record.Packets.Add(1)
+	record.Bytes.Add(uint64(size))
+
 	now := int64(monotime.Now())
 	last := record.LastActivity.Load()
 	if now-last < saveFrequency {
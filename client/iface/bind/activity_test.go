@@ -23,3 +23,18 @@ func TestActivityRecorder_GetLastActivities(t *testing.T) {
 		t.Fatalf("Expected activity for peer %s to be recent, but got %v", peer, p)
 	}
 }
+
+func TestActivityRecorder_GetActivityCounters(t *testing.T) {
+	peer := "peer1"
+	addr := netip.MustParseAddrPort("192.168.0.5:51820")
+	ar := NewActivityRecorder()
+	ar.UpsertAddress(peer, addr)
+
+	ar.record(addr, 100)
+	ar.record(addr, 50)
+
+	counters := ar.GetActivityCounters()[peer]
+	if counters.Packets != 2 || counters.Bytes != 150 {
+		t.Fatalf("Expected 2 packets and 150 bytes for peer %s, but got %+v", peer, counters)
+	}
+}
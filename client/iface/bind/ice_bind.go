@@ -62,7 +62,9 @@ type ICEBind struct {
 	mtu          uint16
 
 	endpoints   map[netip.Addr]net.Conn
+	pacers      map[netip.Addr]*relayPacer
 	endpointsMu sync.Mutex
+	pacing      bool
 	recvChan    chan recvMessage
 	// every time when Close() is called (i.e. BindUpdate()) we need to close exit from the receiveRelayed and create a
 	// new closed channel. With the closedChanMu we can safely close the channel and create a new one
@@ -84,6 +86,8 @@ func NewICEBind(transportNet transport.Net, filterFn udpmux.FilterFn, address wg
 		address:          address,
 		mtu:              mtu,
 		endpoints:        make(map[netip.Addr]net.Conn),
+		pacers:           make(map[netip.Addr]*relayPacer),
+		pacing:           relayPacingEnabled(),
 		recvChan:         make(chan recvMessage, 1),
 		closedChan:       make(chan struct{}),
 		closed:           true,
@@ -138,8 +142,14 @@ func (s *ICEBind) GetICEMux() (*udpmux.UniversalUDPMuxDefault, error) {
 
 func (b *ICEBind) SetEndpoint(fakeIP netip.Addr, conn net.Conn) {
 	b.endpointsMu.Lock()
+	defer b.endpointsMu.Unlock()
+
 	b.endpoints[fakeIP] = conn
-	b.endpointsMu.Unlock()
+
+	delete(b.pacers, fakeIP)
+	if statsConn, ok := conn.(pathStatsConn); ok && b.pacing {
+		b.pacers[fakeIP] = newRelayPacer(statsConn)
+	}
 }
 
 func (b *ICEBind) RemoveEndpoint(fakeIP netip.Addr) {
@@ -147,6 +157,7 @@ func (b *ICEBind) RemoveEndpoint(fakeIP netip.Addr) {
 	defer b.endpointsMu.Unlock()
 
 	delete(b.endpoints, fakeIP)
+	delete(b.pacers, fakeIP)
 }
 
 func (b *ICEBind) ReceiveFromEndpoint(ctx context.Context, ep *Endpoint, buf []byte) {
@@ -162,12 +173,16 @@ func (b *ICEBind) ReceiveFromEndpoint(ctx context.Context, ep *Endpoint, buf []b
 func (b *ICEBind) Send(bufs [][]byte, ep wgConn.Endpoint) error {
 	b.endpointsMu.Lock()
 	conn, ok := b.endpoints[ep.DstIP()]
+	pacer := b.pacers[ep.DstIP()]
 	b.endpointsMu.Unlock()
 	if !ok {
 		return b.StdNetBind.Send(bufs, ep)
 	}
 
 	for _, buf := range bufs {
+		if pacer != nil {
+			pacer.wait(len(buf))
+		}
 		if _, err := conn.Write(buf); err != nil {
 			return err
 		}
@@ -237,7 +252,7 @@ func (s *ICEBind) createIPv4ReceiverFn(pc *ipv4.PacketConn, conn *net.UDPConn, r
 			addrPort := msg.Addr.(*net.UDPAddr).AddrPort()
 
 			if isTransportPkg(msg.Buffers, msg.N) {
-				s.activityRecorder.record(addrPort)
+				s.activityRecorder.record(addrPort, msg.N)
 			}
 
 			ep := &wgConn.StdNetEndpoint{AddrPort: addrPort} // TODO: remove allocation
@@ -301,7 +316,7 @@ func (c *ICEBind) receiveRelayed(buffs [][]byte, sizes []int, eps []wgConn.Endpo
 
 		if isTransportPkg(buffs, sizes[0]) {
 			if ep, ok := eps[0].(*Endpoint); ok {
-				c.activityRecorder.record(ep.AddrPort)
+				c.activityRecorder.record(ep.AddrPort, sizes[0])
 			}
 		}
 
@@ -0,0 +1,144 @@
+//go:build !js
+
+package bind
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+
+	relayNet "github.com/netbirdio/netbird/shared/relay/client/dialer/net"
+)
+
+const (
+	// EnvRelayPacing enables the pacing of the WireGuard transport sends over relayed connections
+	EnvRelayPacing = "NB_RELAY_PACING"
+
+	pacerUpdateInterval = time.Second
+	// pacerTargetQueueDelay is the queuing delay tolerated on the relay path before the send rate is reduced
+	pacerTargetQueueDelay = 10 * time.Millisecond
+	pacerLossThreshold    = 0.02
+	pacerDecrease         = 0.85
+	pacerIncrease         = 1.1
+	// pacerMinRate keeps the tunnel usable on a congested path, in bytes/s
+	pacerMinRate = 64 * 1024
+	// pacerBurst is the traffic sent back to back, in bytes. It fits a few full sized packets.
+	pacerBurst = 16 * 1024
+)
+
+func relayPacingEnabled() bool {
+	return strings.EqualFold(os.Getenv(EnvRelayPacing), "true")
+}
+
+// pathStatsConn is implemented by the relay connections that measure their network path
+type pathStatsConn interface {
+	PathStats() (relayNet.PathStats, bool)
+}
+
+// relayPacer paces the sends over a relayed connection to keep the queues on the relay path short, so interactive
+// traffic isn't stuck behind a bulk transfer. It starts unpaced. When the RTT rises above the base RTT of the path or
+// packets get lost, the rate is reduced below the measured throughput, and raised again while the path stays clear.
+// Once the sender uses much less than the allowed rate the pacing is lifted.
+type relayPacer struct {
+	conn pathStatsConn
+
+	mu         sync.Mutex
+	limiter    *rate.Limiter
+	lastUpdate time.Time
+	lastStats  relayNet.PathStats
+	sentBytes  uint64
+}
+
+func newRelayPacer(conn pathStatsConn) *relayPacer {
+	p := &relayPacer{
+		conn:       conn,
+		limiter:    rate.NewLimiter(rate.Inf, pacerBurst),
+		lastUpdate: time.Now(),
+	}
+	if stats, ok := conn.PathStats(); ok {
+		p.lastStats = stats
+	}
+	return p
+}
+
+// wait blocks until a packet of the given size may be sent
+func (p *relayPacer) wait(size int) {
+	now := time.Now()
+
+	p.mu.Lock()
+	p.sentBytes += uint64(size)
+	if now.Sub(p.lastUpdate) >= pacerUpdateInterval {
+		p.update(now)
+	}
+	p.mu.Unlock()
+
+	r := p.limiter.ReserveN(now, size)
+	if !r.OK() {
+		// larger than the burst, never delay it forever
+		return
+	}
+	if delay := r.DelayFrom(now); delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// update adjusts the rate to the path measurements since the last update. It must be called with the lock held.
+func (p *relayPacer) update(now time.Time) {
+	elapsed := now.Sub(p.lastUpdate)
+	throughput := float64(p.sentBytes) / elapsed.Seconds()
+	p.lastUpdate = now
+	p.sentBytes = 0
+
+	stats, ok := p.conn.PathStats()
+	if !ok {
+		return
+	}
+	last := p.lastStats
+	p.lastStats = stats
+
+	current := p.limiter.Limit()
+	next := current
+	switch {
+	case congested(last, stats):
+		if current == rate.Inf || float64(current) > throughput {
+			current = rate.Limit(throughput)
+		}
+		next = max(current*pacerDecrease, pacerMinRate)
+	case current == rate.Inf:
+		return
+	case throughput < float64(current)/2:
+		next = rate.Inf
+	case throughput >= float64(current)*0.8:
+		next = current * pacerIncrease
+	}
+
+	if next == p.limiter.Limit() {
+		return
+	}
+
+	if next == rate.Inf {
+		log.Debugf("relay path is clear, stop pacing")
+	} else {
+		log.Tracef("relay path pacing at %.0f kbit/s, rtt %s, min rtt %s", float64(next)*8/1000, stats.SmoothedRTT, stats.MinRTT)
+	}
+	p.limiter.SetLimitAt(now, next)
+}
+
+func congested(last, stats relayNet.PathStats) bool {
+	if stats.MinRTT > 0 && stats.SmoothedRTT-stats.MinRTT > max(pacerTargetQueueDelay, stats.MinRTT/2) {
+		return true
+	}
+
+	// the counters restart with a new relay server connection
+	if stats.PacketsSent <= last.PacketsSent || stats.PacketsLost < last.PacketsLost {
+		return false
+	}
+
+	sent := stats.PacketsSent - last.PacketsSent
+	lost := stats.PacketsLost - last.PacketsLost
+	return float64(lost)/float64(sent) > pacerLossThreshold
+}
@@ -0,0 +1,82 @@
+//go:build !js
+
+package bind
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+
+	relayNet "github.com/netbirdio/netbird/shared/relay/client/dialer/net"
+)
+
+type fakeStatsConn struct {
+	stats relayNet.PathStats
+}
+
+func (f *fakeStatsConn) PathStats() (relayNet.PathStats, bool) {
+	return f.stats, true
+}
+
+// sendInterval simulates the given throughput, in bytes/s, for one update interval and updates the pacer
+func sendInterval(p *relayPacer, throughput float64) {
+	p.sentBytes = uint64(throughput * pacerUpdateInterval.Seconds())
+	p.update(p.lastUpdate.Add(pacerUpdateInterval))
+}
+
+func TestRelayPacer_QueuingDelay(t *testing.T) {
+	conn := &fakeStatsConn{stats: relayNet.PathStats{SmoothedRTT: 20 * time.Millisecond, MinRTT: 20 * time.Millisecond}}
+	p := newRelayPacer(conn)
+
+	sendInterval(p, 1_000_000)
+	assert.Equal(t, rate.Inf, p.limiter.Limit(), "a clear path should not be paced")
+
+	conn.stats.SmoothedRTT = 80 * time.Millisecond
+	sendInterval(p, 1_000_000)
+	assert.InDelta(t, 1_000_000*pacerDecrease, float64(p.limiter.Limit()), 1, "the rate should drop below the throughput")
+
+	sendInterval(p, 850_000)
+	assert.InDelta(t, 1_000_000*pacerDecrease*pacerDecrease, float64(p.limiter.Limit()), 1)
+
+	conn.stats.SmoothedRTT = 21 * time.Millisecond
+	limit := p.limiter.Limit()
+	sendInterval(p, float64(limit))
+	assert.InDelta(t, float64(limit)*pacerIncrease, float64(p.limiter.Limit()), 1, "the rate should recover on a clear path")
+
+	sendInterval(p, 1000)
+	assert.Equal(t, rate.Inf, p.limiter.Limit(), "the pacing should be lifted when the sender is idle")
+}
+
+func TestRelayPacer_Loss(t *testing.T) {
+	conn := &fakeStatsConn{stats: relayNet.PathStats{SmoothedRTT: 20 * time.Millisecond, MinRTT: 20 * time.Millisecond}}
+	p := newRelayPacer(conn)
+
+	conn.stats.PacketsSent = 1000
+	conn.stats.PacketsLost = 10
+	sendInterval(p, 1_000_000)
+	assert.Equal(t, rate.Inf, p.limiter.Limit(), "loss below the threshold should be tolerated")
+
+	conn.stats.PacketsSent = 2000
+	conn.stats.PacketsLost = 110
+	sendInterval(p, 1_000_000)
+	assert.InDelta(t, 1_000_000*pacerDecrease, float64(p.limiter.Limit()), 1)
+}
+
+func TestRelayPacer_MinRate(t *testing.T) {
+	conn := &fakeStatsConn{stats: relayNet.PathStats{SmoothedRTT: 500 * time.Millisecond, MinRTT: 20 * time.Millisecond}}
+	p := newRelayPacer(conn)
+
+	for i := 0; i < 50; i++ {
+		sendInterval(p, 100_000)
+	}
+	assert.Equal(t, rate.Limit(pacerMinRate), p.limiter.Limit())
+}
+
+func TestRelayPacer_CountersRestart(t *testing.T) {
+	assert.False(t, congested(
+		relayNet.PathStats{PacketsSent: 1000, PacketsLost: 100},
+		relayNet.PathStats{PacketsSent: 10, PacketsLost: 5},
+	))
+}
@@ -12,6 +12,7 @@ import (
 	"golang.zx2c4.com/wireguard/wgctrl"
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
 
+	"github.com/netbirdio/netbird/client/iface/bind"
 	"github.com/netbirdio/netbird/monotime"
 )
 
@@ -320,3 +321,7 @@ func (c *KernelConfigurer) GetStats() (map[string]WGStats, error) {
 func (c *KernelConfigurer) LastActivities() map[string]monotime.Time {
 	return nil
 }
+
+func (c *KernelConfigurer) ActivityCounters() map[string]bind.ActivityCounters {
+	return nil
+}
@@ -25,13 +25,13 @@ type Bind interface {
 }
 
 type TunNetstackDevice struct {
-	name          string
-	address       wgaddr.Address
-	port          int
-	key           string
-	mtu           uint16
-	listenAddress string
-	bind          Bind
+	name        string
+	address     wgaddr.Address
+	port        int
+	key         string
+	mtu         uint16
+	proxyConfig nbnetstack.ProxyConfig
+	bind        Bind
 
 	device         *device.Device
 	filteredDevice *FilteredDevice
@@ -42,15 +42,15 @@ type TunNetstackDevice struct {
 	net *netstack.Net
 }
 
-func NewNetstackDevice(name string, address wgaddr.Address, wgPort int, key string, mtu uint16, bind Bind, listenAddress string) *TunNetstackDevice {
+func NewNetstackDevice(name string, address wgaddr.Address, wgPort int, key string, mtu uint16, bind Bind, proxyConfig nbnetstack.ProxyConfig) *TunNetstackDevice {
 	return &TunNetstackDevice{
-		name:          name,
-		address:       address,
-		port:          wgPort,
-		key:           key,
-		mtu:           mtu,
-		listenAddress: listenAddress,
-		bind:          bind,
+		name:        name,
+		address:     address,
+		port:        wgPort,
+		key:         key,
+		mtu:         mtu,
+		proxyConfig: proxyConfig,
+		bind:        bind,
 	}
 }
 
@@ -64,7 +64,7 @@ func (t *TunNetstackDevice) create() (WGConfigurer, error) {
 	}
 
 	log.Debugf("netstack using address: %s", t.address.IP)
-	t.nsTun = nbnetstack.NewNetStackTun(t.listenAddress, t.address.IP, dnsAddr, int(t.mtu))
+	t.nsTun = nbnetstack.NewNetStackTun(t.proxyConfig, t.address.IP, dnsAddr, int(t.mtu))
 	log.Debugf("netstack using dns address: %s", dnsAddr)
 	tunIface, net, err := t.nsTun.Create()
 	if err != nil {
@@ -15,7 +15,7 @@ func TestNewNetstackDevice(t *testing.T) {
 	wgAddress, _ := wgaddr.ParseWGAddress("1.2.3.4/24")
 
 	relayBind := bind.NewRelayBindJS()
-	nsTun := NewNetstackDevice("wtx", wgAddress, 1234, privateKey.String(), 1500, relayBind, netstack.ListenAddr())
+	nsTun := NewNetstackDevice("wtx", wgAddress, 1234, privateKey.String(), 1500, relayBind, netstack.ProxyConfig{SOCKS5Address: netstack.ListenAddr()})
 
 	cfgr, err := nsTun.Create()
 	if err != nil {
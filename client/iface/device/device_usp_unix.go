@@ -48,7 +48,7 @@ func (t *USPDevice) Create() (WGConfigurer, error) {
 	tunIface, err := tun.CreateTUN(t.name, int(t.mtu))
 	if err != nil {
 		log.Debugf("failed to create tun interface (%s, %d): %s", t.name, int(t.mtu), err)
-		return nil, fmt.Errorf("error creating tun device: %s", err)
+		return nil, fmt.Errorf("error creating tun device: %w", err)
 	}
 	t.filteredDevice = newDeviceFilter(tunIface)
 
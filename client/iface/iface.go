@@ -16,6 +16,7 @@ import (
 	wgdevice "golang.zx2c4.com/wireguard/device"
 
 	"github.com/netbirdio/netbird/client/errors"
+	"github.com/netbirdio/netbird/client/iface/bind"
 	"github.com/netbirdio/netbird/client/iface/configurer"
 	"github.com/netbirdio/netbird/client/iface/device"
 	"github.com/netbirdio/netbird/client/iface/udpmux"
@@ -63,6 +64,10 @@ type WGIFaceOpts struct {
 	TransportNet transport.Net
 	FilterFn     udpmux.FilterFn
 	DisableDNS   bool
+	// SOCKS5ProxyAddress overrides the listen address of the SOCKS5 proxy in netstack mode
+	SOCKS5ProxyAddress string
+	// HTTPProxyAddress is the listen address of the HTTP CONNECT proxy in netstack mode, empty disables it
+	HTTPProxyAddress string
 }
 
 // WGIface represents an interface instance
@@ -289,6 +294,18 @@ func (w *WGIface) LastActivities() map[string]monotime.Time {
 
 }
 
+// ActivityCounters returns the transport packets and bytes received per peer, nil if they are not tracked
+func (w *WGIface) ActivityCounters() map[string]bind.ActivityCounters {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.configurer == nil {
+		return nil
+	}
+
+	return w.configurer.ActivityCounters()
+}
+
 func (w *WGIface) FullStats() (*configurer.Stats, error) {
 	if w.configurer == nil {
 		return nil, ErrIfaceNotFound
@@ -30,3 +30,8 @@ func (d *NSDialer) Dial(ctx context.Context, network, addr string) (net.Conn, er
 	}
 	return conn, err
 }
+
+// LookupContextHost resolves the host through the DNS of the netstack
+func (d *NSDialer) LookupContextHost(ctx context.Context, host string) ([]string, error) {
+	return d.net.LookupContextHost(ctx, host)
+}
@@ -6,15 +6,29 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"sync/atomic"
 
 	log "github.com/sirupsen/logrus"
 )
 
 const EnvUseNetstackMode = "NB_USE_NETSTACK_MODE"
 
+var fallback atomic.Bool
+
 // IsEnabled todo: move these function to cmd layer
 func IsEnabled() bool {
-	return os.Getenv(EnvUseNetstackMode) == "true"
+	return os.Getenv(EnvUseNetstackMode) == "true" || fallback.Load()
+}
+
+// EnableFallback switches the process to netstack mode, e.g. after the tunnel device could not be created.
+// The mode stays enabled until the process exits.
+func EnableFallback() {
+	fallback.Store(true)
+}
+
+// IsFallback reports whether netstack mode was enabled by EnableFallback
+func IsFallback() bool {
+	return fallback.Load()
 }
 
 func ListenAddr() string {
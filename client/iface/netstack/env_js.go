@@ -7,6 +7,14 @@ func IsEnabled() bool {
 	return true
 }
 
+// EnableFallback is a no-op for js, netstack is always enabled
+func EnableFallback() {}
+
+// IsFallback always returns false for js since netstack is the regular mode
+func IsFallback() bool {
+	return false
+}
+
 func ListenAddr() string {
 	return ""
 }
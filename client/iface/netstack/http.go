@@ -0,0 +1,150 @@
+package netstack
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	httpDialTimeout       = 30 * time.Second
+	httpReadHeaderTimeout = 10 * time.Second
+)
+
+// HTTPProxy tunnels HTTP CONNECT requests through the netstack. Plain HTTP proxy requests are not supported,
+// clients are expected to use CONNECT for all targets, which curl and most HTTP libraries do for https.
+type HTTPProxy struct {
+	dialer Dialer
+
+	mu     sync.Mutex
+	server *http.Server
+}
+
+// NewHTTPConnect returns an HTTP CONNECT proxy dialing with the dialer
+func NewHTTPConnect(dialer Dialer) *HTTPProxy {
+	return &HTTPProxy{
+		dialer: dialer,
+	}
+}
+
+func (p *HTTPProxy) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Errorf("failed to create listener for http proxy: %s", err)
+		return err
+	}
+	return p.Serve(listener)
+}
+
+// Serve serves the proxy on the listener until the proxy is closed
+func (p *HTTPProxy) Serve(listener net.Listener) error {
+	server := &http.Server{
+		Handler:           p,
+		ReadHeaderTimeout: httpReadHeaderTimeout,
+	}
+
+	p.mu.Lock()
+	p.server = server
+	p.mu.Unlock()
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (p *HTTPProxy) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.server == nil {
+		return nil
+	}
+	return p.server.Close()
+}
+
+func (p *HTTPProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		w.Header().Set("Allow", http.MethodConnect)
+		http.Error(w, "only CONNECT is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, _, err := net.SplitHostPort(r.Host); err != nil {
+		http.Error(w, "invalid target address", http.StatusBadRequest)
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "hijacking not supported", http.StatusInternalServerError)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), httpDialTimeout)
+	defer cancel()
+
+	upstream, err := p.dialer.Dial(ctx, "tcp", r.Host)
+	if err != nil {
+		log.Debugf("http proxy failed to dial %s: %v", r.Host, err)
+		http.Error(w, "failed to reach target", http.StatusBadGateway)
+		return
+	}
+
+	conn, buf, err := hijacker.Hijack()
+	if err != nil {
+		log.Debugf("http proxy failed to hijack connection: %v", err)
+		closeConn(upstream)
+		return
+	}
+
+	if _, err := conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n")); err != nil {
+		closeConn(conn)
+		closeConn(upstream)
+		return
+	}
+
+	// the client might have sent data right after the request, it is buffered by the server
+	if n := buf.Reader.Buffered(); n > 0 {
+		data, _ := buf.Reader.Peek(n)
+		if _, err := upstream.Write(data); err != nil {
+			closeConn(conn)
+			closeConn(upstream)
+			return
+		}
+	}
+
+	relay(conn, upstream)
+}
+
+// relay copies data in both directions until either side is done
+func relay(a, b net.Conn) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	cp := func(dst, src net.Conn) {
+		defer wg.Done()
+		if _, err := io.Copy(dst, src); err != nil {
+			log.Tracef("http proxy relay ended: %v", err)
+		}
+		// unblock the copy in the other direction
+		closeConn(dst)
+		closeConn(src)
+	}
+
+	go cp(a, b)
+	go cp(b, a)
+	wg.Wait()
+}
+
+func closeConn(conn net.Conn) {
+	if err := conn.Close(); err != nil {
+		log.Tracef("failed to close connection: %v", err)
+	}
+}
@@ -1,6 +1,8 @@
 package netstack
 
 import (
+	"context"
+	"fmt"
 	"net"
 
 	"github.com/things-go/go-socks5"
@@ -12,6 +14,31 @@ const (
 	DefaultSocks5Port = 1080
 )
 
+// ProxyConfig holds the listen addresses of the proxies exposing the netstack
+type ProxyConfig struct {
+	// SOCKS5Address is the listen address of the SOCKS5 proxy
+	SOCKS5Address string
+	// HTTPAddress is the listen address of the HTTP CONNECT proxy, empty disables it
+	HTTPAddress string
+}
+
+// NewProxyConfig returns the proxy config with the configured addresses. The SOCKS5 proxy listens on ListenAddr
+// unless an address is configured.
+func NewProxyConfig(socks5Address, httpAddress string) ProxyConfig {
+	if socks5Address == "" {
+		socks5Address = ListenAddr()
+	}
+	return ProxyConfig{
+		SOCKS5Address: socks5Address,
+		HTTPAddress:   httpAddress,
+	}
+}
+
+// Resolver resolves names, implemented by the netstack to resolve NetBird names through its DNS
+type Resolver interface {
+	LookupContextHost(ctx context.Context, host string) ([]string, error)
+}
+
 // Proxy todo close server
 type Proxy struct {
 	server *socks5.Server
@@ -20,13 +47,15 @@ type Proxy struct {
 	closed   bool
 }
 
+// NewSocks5 returns a SOCKS5 proxy dialing with the dialer. Names are resolved with the dialer if it is a Resolver.
 func NewSocks5(dialer Dialer) (*Proxy, error) {
-	server := socks5.NewServer(
-		socks5.WithDial(dialer.Dial),
-	)
+	opts := []socks5.Option{socks5.WithDial(dialer.Dial)}
+	if r, ok := dialer.(Resolver); ok {
+		opts = append(opts, socks5.WithResolver(&nameResolver{resolver: r}))
+	}
 
 	return &Proxy{
-		server: server,
+		server: socks5.NewServer(opts...),
 	}, nil
 }
 
@@ -36,6 +65,11 @@ func (s *Proxy) ListenAndServe(addr string) error {
 		log.Errorf("failed to create listener for socks5 proxy: %s", err)
 		return err
 	}
+	return s.Serve(listener)
+}
+
+// Serve serves the proxy on the listener until the proxy is closed
+func (s *Proxy) Serve(listener net.Listener) error {
 	s.listener = listener
 
 	for {
@@ -63,3 +97,24 @@ func (s *Proxy) Close() error {
 	s.closed = true
 	return s.listener.Close()
 }
+
+// nameResolver resolves the names of SOCKS5 requests, so NetBird names can be used with the proxy
+type nameResolver struct {
+	resolver Resolver
+}
+
+func (r *nameResolver) Resolve(ctx context.Context, name string) (context.Context, net.IP, error) {
+	addrs, err := r.resolver.LookupContextHost(ctx, name)
+	if err != nil {
+		return ctx, nil, err
+	}
+	if len(addrs) == 0 {
+		return ctx, nil, fmt.Errorf("no addresses found for %s", name)
+	}
+
+	ip := net.ParseIP(addrs[0])
+	if ip == nil {
+		return ctx, nil, fmt.Errorf("invalid address %s for %s", addrs[0], name)
+	}
+	return ctx, ip, nil
+}
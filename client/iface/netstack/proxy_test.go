@@ -0,0 +1,148 @@
+package netstack
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/proxy"
+)
+
+// fakeDialer dials the host network and resolves all names to localhost
+type fakeDialer struct {
+	dialer net.Dialer
+}
+
+func (f *fakeDialer) Dial(ctx context.Context, network, address string) (net.Conn, error) {
+	return f.dialer.DialContext(ctx, network, address)
+}
+
+func (f *fakeDialer) LookupContextHost(context.Context, string) ([]string, error) {
+	return []string{"127.0.0.1"}, nil
+}
+
+func startEchoServer(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+// serve runs the proxy on a local listener and returns its address
+func serve(t *testing.T, p interface {
+	Serve(net.Listener) error
+	Close() error
+}) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() { done <- p.Serve(listener) }()
+	t.Cleanup(func() {
+		assert.NoError(t, p.Close())
+		assert.NoError(t, <-done)
+	})
+
+	return listener.Addr().String()
+}
+
+func assertEcho(t *testing.T, conn net.Conn) {
+	t.Helper()
+
+	_, err := conn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+}
+
+func TestNewProxyConfig(t *testing.T) {
+	t.Setenv("NB_SOCKS5_LISTENER_PORT", "")
+
+	assert.Equal(t, ProxyConfig{SOCKS5Address: "0.0.0.0:1080"}, NewProxyConfig("", ""))
+	assert.Equal(t, ProxyConfig{SOCKS5Address: "127.0.0.1:1081", HTTPAddress: "127.0.0.1:3128"},
+		NewProxyConfig("127.0.0.1:1081", "127.0.0.1:3128"))
+}
+
+func TestHTTPConnect(t *testing.T) {
+	echoAddr := startEchoServer(t)
+	addr := serve(t, NewHTTPConnect(&fakeDialer{}))
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = fmt.Fprintf(conn, "CONNECT %s HTTP/1.1\r\nHost: %s\r\n\r\n", echoAddr, echoAddr)
+	require.NoError(t, err)
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assertEcho(t, &bufferedConn{Conn: conn, reader: reader})
+}
+
+func TestHTTPRejectsPlainRequests(t *testing.T) {
+	addr := serve(t, NewHTTPConnect(&fakeDialer{}))
+
+	resp, err := http.Get("http://" + addr + "/")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestSOCKS5(t *testing.T) {
+	echoAddr := startEchoServer(t)
+	_, port, err := net.SplitHostPort(echoAddr)
+	require.NoError(t, err)
+
+	socks5, err := NewSocks5(&fakeDialer{})
+	require.NoError(t, err)
+	addr := serve(t, socks5)
+
+	dialer, err := proxy.SOCKS5("tcp", addr, nil, proxy.Direct)
+	require.NoError(t, err)
+
+	// the name is resolved through the netstack resolver
+	conn, err := dialer.Dial("tcp", net.JoinHostPort("peer.netbird.cloud", port))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	assertEcho(t, conn)
+}
+
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
@@ -13,21 +13,22 @@ import (
 const EnvSkipProxy = "NB_NETSTACK_SKIP_PROXY"
 
 type NetStackTun struct { //nolint:revive
-	address       netip.Addr
-	dnsAddress    netip.Addr
-	mtu           int
-	listenAddress string
+	address     netip.Addr
+	dnsAddress  netip.Addr
+	mtu         int
+	proxyConfig ProxyConfig
 
-	proxy  *Proxy
-	tundev tun.Device
+	proxy     *Proxy
+	httpProxy *HTTPProxy
+	tundev    tun.Device
 }
 
-func NewNetStackTun(listenAddress string, address netip.Addr, dnsAddress netip.Addr, mtu int) *NetStackTun {
+func NewNetStackTun(proxyConfig ProxyConfig, address netip.Addr, dnsAddress netip.Addr, mtu int) *NetStackTun {
 	return &NetStackTun{
-		address:       address,
-		dnsAddress:    dnsAddress,
-		mtu:           mtu,
-		listenAddress: listenAddress,
+		address:     address,
+		dnsAddress:  dnsAddress,
+		mtu:         mtu,
+		proxyConfig: proxyConfig,
 	}
 }
 
@@ -53,18 +54,31 @@ func (t *NetStackTun) Create() (tun.Device, *netstack.Net, error) {
 	}
 
 	dialer := NewNSDialer(tunNet)
-	t.proxy, err = NewSocks5(dialer)
-	if err != nil {
-		_ = t.tundev.Close()
-		return nil, nil, err
-	}
-
-	go func() {
-		err := t.proxy.ListenAndServe(t.listenAddress)
+	if t.proxyConfig.SOCKS5Address != "" {
+		t.proxy, err = NewSocks5(dialer)
 		if err != nil {
-			log.Errorf("error in socks5 proxy serving: %s", err)
+			_ = t.tundev.Close()
+			return nil, nil, err
 		}
-	}()
+
+		go func() {
+			err := t.proxy.ListenAndServe(t.proxyConfig.SOCKS5Address)
+			if err != nil {
+				log.Errorf("error in socks5 proxy serving: %s", err)
+			}
+		}()
+	}
+
+	if t.proxyConfig.HTTPAddress != "" {
+		t.httpProxy = NewHTTPConnect(dialer)
+
+		go func() {
+			err := t.httpProxy.ListenAndServe(t.proxyConfig.HTTPAddress)
+			if err != nil {
+				log.Errorf("error in http proxy serving: %s", err)
+			}
+		}()
+	}
 
 	return nsTunDev, tunNet, nil
 }
@@ -79,6 +93,13 @@ func (t *NetStackTun) Close() error {
 		}
 	}
 
+	if t.httpProxy != nil {
+		if pErr := t.httpProxy.Close(); pErr != nil {
+			log.Errorf("failed to close http proxy: %s", pErr)
+			err = pErr
+		}
+	}
+
 	if t.tundev != nil {
 		dErr := t.tundev.Close()
 		if dErr != nil {
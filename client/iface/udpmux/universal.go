@@ -322,6 +322,18 @@ func (m *UniversalUDPMuxDefault) GetXORMappedAddr(serverAddr net.Addr, deadline
 	}
 }
 
+// ResetXORMappedAddrs drops the cached server reflexive addresses, so the next candidate gathering asks the STUN
+// servers again. It is used after a network change, when the cached addresses belong to the previous network.
+func (m *UniversalUDPMuxDefault) ResetXORMappedAddrs() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for addr, mappedAddr := range m.xorMappedMap {
+		mappedAddr.closeWaiters()
+		delete(m.xorMappedMap, addr)
+	}
+}
+
 // sendSTUN sends a STUN request via UDP conn.
 //
 // The returned channel is closed when the STUN response has been received.
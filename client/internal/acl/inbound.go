@@ -0,0 +1,143 @@
+package acl
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+// ParseInboundException parses a service that stays reachable while the peer blocks inbound connections.
+// The format is <protocol>[/<port>[-<port>]][@<peer IP>][ <days> <hh:mm>-<hh:mm>], e.g. icmp, tcp/22@100.64.0.10,
+// udp/5000-5010 or tcp/3389 mon-fri 09:00-17:00. Without a peer IP all peers may connect, without a schedule the
+// service is reachable all the time. The schedule is in the local time zone.
+func ParseInboundException(exception string) (*mgmProto.FirewallRule, error) {
+	rule := &mgmProto.FirewallRule{
+		PeerIP:    "0.0.0.0",
+		Direction: mgmProto.RuleDirection_IN,
+		Action:    mgmProto.RuleAction_ACCEPT,
+	}
+
+	fields := strings.Fields(exception)
+	switch len(fields) {
+	case 1:
+	case 3:
+		schedule, err := parseScheduleWindow(fields[1], fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("inbound exception %q: %w", exception, err)
+		}
+		rule.Schedule = schedule
+	default:
+		return nil, fmt.Errorf("invalid inbound exception %q", exception)
+	}
+
+	spec, source, hasSource := strings.Cut(fields[0], "@")
+	if hasSource {
+		addr, err := netip.ParseAddr(source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer IP in inbound exception %q: %w", exception, err)
+		}
+		rule.PeerIP = addr.String()
+	}
+
+	protocol, ports, hasPorts := strings.Cut(spec, "/")
+	switch strings.ToLower(protocol) {
+	case "tcp":
+		rule.Protocol = mgmProto.RuleProtocol_TCP
+	case "udp":
+		rule.Protocol = mgmProto.RuleProtocol_UDP
+	case "icmp":
+		rule.Protocol = mgmProto.RuleProtocol_ICMP
+	case "all":
+		rule.Protocol = mgmProto.RuleProtocol_ALL
+	default:
+		return nil, fmt.Errorf("invalid protocol in inbound exception %q, valid values are tcp, udp, icmp and all", exception)
+	}
+
+	if !hasPorts {
+		return rule, nil
+	}
+	if rule.Protocol != mgmProto.RuleProtocol_TCP && rule.Protocol != mgmProto.RuleProtocol_UDP {
+		return nil, fmt.Errorf("inbound exception %q: ports are only supported for tcp and udp", exception)
+	}
+
+	portInfo, err := parsePortInfo(ports)
+	if err != nil {
+		return nil, fmt.Errorf("inbound exception %q: %w", exception, err)
+	}
+	rule.PortInfo = portInfo
+	return rule, nil
+}
+
+func parsePortInfo(ports string) (*mgmProto.PortInfo, error) {
+	start, end, isRange := strings.Cut(ports, "-")
+	first, err := parsePort(start)
+	if err != nil {
+		return nil, err
+	}
+	if !isRange {
+		return &mgmProto.PortInfo{PortSelection: &mgmProto.PortInfo_Port{Port: uint32(first)}}, nil
+	}
+
+	last, err := parsePort(end)
+	if err != nil {
+		return nil, err
+	}
+	if last < first {
+		return nil, fmt.Errorf("invalid port range %s", ports)
+	}
+	return &mgmProto.PortInfo{PortSelection: &mgmProto.PortInfo_Range_{
+		Range: &mgmProto.PortInfo_Range{Start: uint32(first), End: uint32(last)},
+	}}, nil
+}
+
+func parsePort(port string) (uint16, error) {
+	value, err := strconv.ParseUint(port, 10, 16)
+	if err != nil || value == 0 {
+		return 0, fmt.Errorf("invalid port %q", port)
+	}
+	return uint16(value), nil
+}
+
+// InboundExceptionRules returns the inbound accept rules of the local and the management exceptions. Other rules
+// management sends as exceptions are ignored, they can't open more than inbound services.
+func InboundExceptionRules(local, fromManagement []*mgmProto.FirewallRule) []*mgmProto.FirewallRule {
+	rules := make([]*mgmProto.FirewallRule, 0, len(local)+len(fromManagement))
+	rules = append(rules, local...)
+	for _, rule := range fromManagement {
+		if rule.GetDirection() != mgmProto.RuleDirection_IN || rule.GetAction() != mgmProto.RuleAction_ACCEPT {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// AllowsInboundTCP reports whether one of the rules accepts inbound TCP connections to the port from at least one peer
+func AllowsInboundTCP(rules []*mgmProto.FirewallRule, port uint16) bool {
+	for _, rule := range rules {
+		if rule.GetDirection() != mgmProto.RuleDirection_IN || rule.GetAction() != mgmProto.RuleAction_ACCEPT {
+			continue
+		}
+		if rule.GetProtocol() != mgmProto.RuleProtocol_TCP && rule.GetProtocol() != mgmProto.RuleProtocol_ALL {
+			continue
+		}
+
+		switch {
+		case portInfoEmpty(rule.GetPortInfo()):
+			if rule.GetPort() == "" || rule.GetPort() == strconv.Itoa(int(port)) {
+				return true
+			}
+		case rule.GetPortInfo().GetRange() != nil:
+			r := rule.GetPortInfo().GetRange()
+			if uint32(port) >= r.GetStart() && uint32(port) <= r.GetEnd() {
+				return true
+			}
+		case rule.GetPortInfo().GetPort() == uint32(port):
+			return true
+		}
+	}
+	return false
+}
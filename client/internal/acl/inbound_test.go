@@ -0,0 +1,132 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+func TestParseInboundException(t *testing.T) {
+	tests := []struct {
+		exception string
+		protocol  mgmProto.RuleProtocol
+		peerIP    string
+		portInfo  *mgmProto.PortInfo
+		window    *mgmProto.ScheduleWindow
+		wantErr   bool
+	}{
+		{exception: "icmp", protocol: mgmProto.RuleProtocol_ICMP, peerIP: "0.0.0.0"},
+		{exception: "all@100.64.0.10", protocol: mgmProto.RuleProtocol_ALL, peerIP: "100.64.0.10"},
+		{
+			exception: "tcp/22@100.64.0.10",
+			protocol:  mgmProto.RuleProtocol_TCP,
+			peerIP:    "100.64.0.10",
+			portInfo:  &mgmProto.PortInfo{PortSelection: &mgmProto.PortInfo_Port{Port: 22}},
+		},
+		{
+			exception: "UDP/5000-5010",
+			protocol:  mgmProto.RuleProtocol_UDP,
+			peerIP:    "0.0.0.0",
+			portInfo: &mgmProto.PortInfo{PortSelection: &mgmProto.PortInfo_Range_{
+				Range: &mgmProto.PortInfo_Range{Start: 5000, End: 5010},
+			}},
+		},
+		{
+			exception: "tcp/3389@100.64.0.10 mon-fri 09:00-17:30",
+			protocol:  mgmProto.RuleProtocol_TCP,
+			peerIP:    "100.64.0.10",
+			portInfo:  &mgmProto.PortInfo{PortSelection: &mgmProto.PortInfo_Port{Port: 3389}},
+			window:    &mgmProto.ScheduleWindow{Weekdays: []uint32{1, 2, 3, 4, 5}, StartMinute: 540, EndMinute: 1050},
+		},
+		{
+			exception: "all sat,sun 22:00-06:00",
+			protocol:  mgmProto.RuleProtocol_ALL,
+			peerIP:    "0.0.0.0",
+			window:    &mgmProto.ScheduleWindow{Weekdays: []uint32{6, 0}, StartMinute: 1320, EndMinute: 360},
+		},
+		{
+			exception: "icmp daily 00:00-24:00",
+			protocol:  mgmProto.RuleProtocol_ICMP,
+			peerIP:    "0.0.0.0",
+			window:    &mgmProto.ScheduleWindow{StartMinute: 0, EndMinute: 1440},
+		},
+		{exception: "sctp", wantErr: true},
+		{exception: "tcp/22 mon-fri", wantErr: true},
+		{exception: "tcp/22 monday 09:00-17:00", wantErr: true},
+		{exception: "tcp/22 daily 09:00-09:00", wantErr: true},
+		{exception: "tcp/22 daily 9-17", wantErr: true},
+		{exception: "icmp/8", wantErr: true},
+		{exception: "tcp/0", wantErr: true},
+		{exception: "tcp/70000", wantErr: true},
+		{exception: "udp/10-5", wantErr: true},
+		{exception: "tcp/22@admin", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.exception, func(t *testing.T) {
+			rule, err := ParseInboundException(tt.exception)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.protocol, rule.Protocol)
+			assert.Equal(t, tt.peerIP, rule.PeerIP)
+			assert.Equal(t, mgmProto.RuleDirection_IN, rule.Direction)
+			assert.Equal(t, mgmProto.RuleAction_ACCEPT, rule.Action)
+			assert.Equal(t, tt.portInfo.GetPort(), rule.GetPortInfo().GetPort())
+			assert.Equal(t, tt.portInfo.GetRange().GetStart(), rule.GetPortInfo().GetRange().GetStart())
+			assert.Equal(t, tt.portInfo.GetRange().GetEnd(), rule.GetPortInfo().GetRange().GetEnd())
+			if tt.window == nil {
+				assert.Nil(t, rule.GetSchedule())
+				return
+			}
+			require.Len(t, rule.GetSchedule().GetWindows(), 1)
+			window := rule.GetSchedule().GetWindows()[0]
+			assert.Equal(t, "Local", rule.GetSchedule().GetTimeZone())
+			assert.Equal(t, tt.window.GetWeekdays(), window.GetWeekdays())
+			assert.Equal(t, tt.window.GetStartMinute(), window.GetStartMinute())
+			assert.Equal(t, tt.window.GetEndMinute(), window.GetEndMinute())
+		})
+	}
+}
+
+func TestInboundExceptionRules(t *testing.T) {
+	local, err := ParseInboundException("tcp/22")
+	require.NoError(t, err)
+
+	fromManagement := []*mgmProto.FirewallRule{
+		{PeerIP: "100.64.0.10", Direction: mgmProto.RuleDirection_IN, Action: mgmProto.RuleAction_ACCEPT, Protocol: mgmProto.RuleProtocol_UDP, Port: "53"},
+		{PeerIP: "100.64.0.11", Direction: mgmProto.RuleDirection_OUT, Action: mgmProto.RuleAction_ACCEPT, Protocol: mgmProto.RuleProtocol_ALL},
+		{PeerIP: "100.64.0.12", Direction: mgmProto.RuleDirection_IN, Action: mgmProto.RuleAction_DROP, Protocol: mgmProto.RuleProtocol_ALL},
+	}
+
+	rules := InboundExceptionRules([]*mgmProto.FirewallRule{local}, fromManagement)
+	require.Len(t, rules, 2)
+	assert.Equal(t, local, rules[0])
+	assert.Equal(t, fromManagement[0], rules[1])
+}
+
+func TestAllowsInboundTCP(t *testing.T) {
+	parse := func(exception string) *mgmProto.FirewallRule {
+		rule, err := ParseInboundException(exception)
+		require.NoError(t, err)
+		return rule
+	}
+
+	assert.True(t, AllowsInboundTCP([]*mgmProto.FirewallRule{parse("tcp/22@100.64.0.10")}, 22))
+	assert.True(t, AllowsInboundTCP([]*mgmProto.FirewallRule{parse("tcp/20-30")}, 22))
+	assert.True(t, AllowsInboundTCP([]*mgmProto.FirewallRule{parse("tcp")}, 22))
+	assert.True(t, AllowsInboundTCP([]*mgmProto.FirewallRule{parse("all")}, 22))
+	assert.True(t, AllowsInboundTCP([]*mgmProto.FirewallRule{
+		{PeerIP: "0.0.0.0", Direction: mgmProto.RuleDirection_IN, Action: mgmProto.RuleAction_ACCEPT, Protocol: mgmProto.RuleProtocol_TCP, Port: "22"},
+	}, 22))
+
+	assert.False(t, AllowsInboundTCP(nil, 22))
+	assert.False(t, AllowsInboundTCP([]*mgmProto.FirewallRule{parse("udp/22"), parse("icmp")}, 22))
+	assert.False(t, AllowsInboundTCP([]*mgmProto.FirewallRule{parse("tcp/23-30")}, 22))
+}
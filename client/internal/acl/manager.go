@@ -26,6 +26,8 @@ var ErrSourceRangesEmpty = errors.New("sources range is empty")
 // Manager is a ACL rules manager
 type Manager interface {
 	ApplyFiltering(networkMap *mgmProto.NetworkMap, dnsRouteFeatureFlag bool)
+	SetClockSkew(skew time.Duration)
+	Stop()
 }
 
 // DefaultManager uses firewall manager to handle
@@ -35,6 +37,20 @@ type DefaultManager struct {
 	peerRulesPairs map[id.RuleID][]firewall.Rule
 	routeRules     map[id.RuleID]struct{}
 	mutex          sync.Mutex
+
+	// blockInbound replaces the peer rules of management with the inbound exceptions
+	blockInbound bool
+	// localInboundExceptions are the inbound exceptions configured on this peer
+	localInboundExceptions []*mgmProto.FirewallRule
+
+	// networkMap and dnsRouteFeatureFlag are applied again when a rule schedule changes its state
+	networkMap          *mgmProto.NetworkMap
+	dnsRouteFeatureFlag bool
+	scheduleTimer       *time.Timer
+	nextScheduleChange  time.Time
+	// clockUntrusted is set while the clock differs more than MaxClockSkew from the clock of management
+	clockUntrusted bool
+	now            func() time.Time
 }
 
 func NewDefaultManager(fm firewall.Manager) *DefaultManager {
@@ -42,9 +58,20 @@ func NewDefaultManager(fm firewall.Manager) *DefaultManager {
 		firewall:       fm,
 		peerRulesPairs: make(map[id.RuleID][]firewall.Rule),
 		routeRules:     make(map[id.RuleID]struct{}),
+		now:            time.Now,
 	}
 }
 
+// BlockInbound blocks the inbound connections from peers, except for the given local exceptions and the exceptions
+// management sends. The peer and route rules of management are no longer applied.
+func (d *DefaultManager) BlockInbound(exceptions []*mgmProto.FirewallRule) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.blockInbound = true
+	d.localInboundExceptions = exceptions
+}
+
 // ApplyFiltering firewall rules to the local firewall manager processed by ACL policy.
 //
 // If allowByDefault is true it appends allow ALL traffic rules to input and output chains.
@@ -57,6 +84,51 @@ func (d *DefaultManager) ApplyFiltering(networkMap *mgmProto.NetworkMap, dnsRout
 		return
 	}
 
+	d.networkMap = networkMap
+	d.dnsRouteFeatureFlag = dnsRouteFeatureFlag
+	d.applyFiltering()
+}
+
+// SetClockSkew sets the difference of the clock to the clock of management. While it exceeds MaxClockSkew the
+// scheduled accept rules are removed and the scheduled drop rules are applied, regardless of their windows.
+func (d *DefaultManager) SetClockSkew(skew time.Duration) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	untrusted := skew.Abs() > MaxClockSkew
+	if untrusted == d.clockUntrusted {
+		return
+	}
+	d.clockUntrusted = untrusted
+
+	if untrusted {
+		log.Warnf("the clock differs %s from the clock of management, not trusting it to enforce rule schedules",
+			skew.Round(time.Second))
+	} else {
+		log.Infof("the clock is in sync with management again, enforcing rule schedules")
+	}
+
+	if d.networkMap != nil && d.firewall != nil {
+		d.applyFiltering()
+	}
+}
+
+// Stop stops applying the rules on schedule changes
+func (d *DefaultManager) Stop() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.scheduleTimer != nil {
+		d.scheduleTimer.Stop()
+		d.scheduleTimer = nil
+	}
+	d.networkMap = nil
+}
+
+func (d *DefaultManager) applyFiltering() {
+	networkMap := d.networkMap
+	d.nextScheduleChange = time.Time{}
+
 	start := time.Now()
 	defer func() {
 		total := 0
@@ -68,15 +140,91 @@ func (d *DefaultManager) ApplyFiltering(networkMap *mgmProto.NetworkMap, dnsRout
 			time.Since(start), total)
 	}()
 
-	d.applyPeerACLs(networkMap)
+	if d.blockInbound {
+		d.applyPeerRules(InboundExceptionRules(d.localInboundExceptions, networkMap.GetInboundExceptions()))
+	} else {
+		d.applyPeerACLs(networkMap)
 
-	if err := d.applyRouteACLs(networkMap.RoutesFirewallRules, dnsRouteFeatureFlag); err != nil {
-		log.Errorf("Failed to apply route ACLs: %v", err)
+		if err := d.applyRouteACLs(networkMap.RoutesFirewallRules, d.dnsRouteFeatureFlag); err != nil {
+			log.Errorf("Failed to apply route ACLs: %v", err)
+		}
 	}
 
 	if err := d.firewall.Flush(); err != nil {
 		log.Error("failed to flush firewall rules: ", err)
 	}
+
+	d.resetScheduleTimer()
+}
+
+// resetScheduleTimer applies the rules again at the next change of a rule schedule, without management
+func (d *DefaultManager) resetScheduleTimer() {
+	if d.scheduleTimer != nil {
+		d.scheduleTimer.Stop()
+		d.scheduleTimer = nil
+	}
+	if d.nextScheduleChange.IsZero() {
+		return
+	}
+
+	log.Debugf("applying the firewall rules again at the next schedule change at %s", d.nextScheduleChange.Format(time.RFC3339))
+	d.scheduleTimer = time.AfterFunc(d.nextScheduleChange.Sub(d.now()), func() {
+		d.mutex.Lock()
+		defer d.mutex.Unlock()
+
+		if d.networkMap == nil {
+			return
+		}
+		d.applyFiltering()
+	})
+}
+
+// activeRules returns the rules with an active schedule. Without a trusted clock the scheduled accept rules are left
+// out and the scheduled drop rules are kept, the same applies to rules with an invalid schedule.
+func activeRules[R scheduledRule](d *DefaultManager, rules []R) []R {
+	scheduled := false
+	for _, rule := range rules {
+		if rule.GetSchedule() != nil {
+			scheduled = true
+			break
+		}
+	}
+	if !scheduled {
+		return rules
+	}
+
+	now := d.now()
+	active := make([]R, 0, len(rules))
+	for _, rule := range rules {
+		if rule.GetSchedule() == nil {
+			active = append(active, rule)
+			continue
+		}
+
+		if d.clockUntrusted {
+			if rule.GetAction() == mgmProto.RuleAction_DROP {
+				active = append(active, rule)
+			}
+			continue
+		}
+
+		isActive, next, err := scheduleState(rule.GetSchedule(), now)
+		if err != nil {
+			log.Errorf("invalid schedule of firewall rule %+v: %v", rule, err)
+			isActive = rule.GetAction() == mgmProto.RuleAction_DROP
+		}
+		if isActive {
+			active = append(active, rule)
+		}
+		if !next.IsZero() && (d.nextScheduleChange.IsZero() || next.Before(d.nextScheduleChange)) {
+			d.nextScheduleChange = next
+		}
+	}
+
+	if skipped := len(rules) - len(active); skipped > 0 {
+		log.Debugf("skipping %d firewall rules outside of their schedule", skipped)
+	}
+	return active
 }
 
 func (d *DefaultManager) applyPeerACLs(networkMap *mgmProto.NetworkMap) {
@@ -102,6 +250,12 @@ func (d *DefaultManager) applyPeerACLs(networkMap *mgmProto.NetworkMap) {
 		)
 	}
 
+	d.applyPeerRules(rules)
+}
+
+func (d *DefaultManager) applyPeerRules(rules []*mgmProto.FirewallRule) {
+	rules = activeRules(d, rules)
+
 	newRulePairs := make(map[id.RuleID][]firewall.Rule)
 	ipsetByRuleSelectors := make(map[string]string)
 
@@ -145,6 +299,16 @@ func (d *DefaultManager) applyRouteACLs(rules []*mgmProto.RouteFirewallRule, dyn
 	newRouteRules := make(map[id.RuleID]struct{}, len(rules))
 	var merr *multierror.Error
 
+	groups, rules := groupRouteRules(activeRules(d, rules))
+	for _, group := range groups {
+		id, err := d.applyRouteACLGroup(group)
+		if err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("add route rule group: %w", err))
+			continue
+		}
+		newRouteRules[id] = struct{}{}
+	}
+
 	// Apply new rules - firewall manager will return existing rule ID if already present
 	for _, rule := range rules {
 		id, err := d.applyRouteACL(rule, dynamicResolver)
@@ -174,17 +338,9 @@ func (d *DefaultManager) applyRouteACLs(rules []*mgmProto.RouteFirewallRule, dyn
 }
 
 func (d *DefaultManager) applyRouteACL(rule *mgmProto.RouteFirewallRule, dynamicResolver bool) (id.RuleID, error) {
-	if len(rule.SourceRanges) == 0 {
-		return "", ErrSourceRangesEmpty
-	}
-
-	var sources []netip.Prefix
-	for _, sourceRange := range rule.SourceRanges {
-		source, err := netip.ParsePrefix(sourceRange)
-		if err != nil {
-			return "", fmt.Errorf("parse source range: %w", err)
-		}
-		sources = append(sources, source)
+	sources, err := parseSourceRanges(rule)
+	if err != nil {
+		return "", err
 	}
 
 	destination, err := determineDestination(rule, dynamicResolver, sources)
@@ -192,6 +348,11 @@ func (d *DefaultManager) applyRouteACL(rule *mgmProto.RouteFirewallRule, dynamic
 		return "", fmt.Errorf("determine destination: %w", err)
 	}
 
+	return d.addRouteRule(rule, sources, destination)
+}
+
+// addRouteRule adds the rule for the given sources and destination to the firewall
+func (d *DefaultManager) addRouteRule(rule *mgmProto.RouteFirewallRule, sources []netip.Prefix, destination firewall.Network) (id.RuleID, error) {
 	protocol, err := convertToFirewallProtocol(rule.Protocol)
 	if err != nil {
 		return "", fmt.Errorf("invalid protocol: %w", err)
@@ -212,6 +373,22 @@ func (d *DefaultManager) applyRouteACL(rule *mgmProto.RouteFirewallRule, dynamic
 	return id.RuleID(addedRule.ID()), nil
 }
 
+func parseSourceRanges(rule *mgmProto.RouteFirewallRule) ([]netip.Prefix, error) {
+	if len(rule.SourceRanges) == 0 {
+		return nil, ErrSourceRangesEmpty
+	}
+
+	var sources []netip.Prefix
+	for _, sourceRange := range rule.SourceRanges {
+		source, err := netip.ParsePrefix(sourceRange)
+		if err != nil {
+			return nil, fmt.Errorf("parse source range: %w", err)
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
 func (d *DefaultManager) protoRuleToFirewallRule(
 	r *mgmProto.FirewallRule,
 	ipsetName string,
@@ -230,6 +407,9 @@ func (d *DefaultManager) protoRuleToFirewallRule(
 	if err != nil {
 		return "", nil, fmt.Errorf("skipping firewall rule: %s", err)
 	}
+	if action == firewall.ActionAudit {
+		return "", nil, fmt.Errorf("skipping firewall rule: audit action is only supported for route rules")
+	}
 
 	var port *firewall.Port
 	if !portInfoEmpty(r.PortInfo) {
@@ -380,6 +560,8 @@ func convertFirewallAction(action mgmProto.RuleAction) (firewall.Action, error)
 		return firewall.ActionAccept, nil
 	case mgmProto.RuleAction_DROP:
 		return firewall.ActionDrop, nil
+	case mgmProto.RuleAction_AUDIT:
+		return firewall.ActionAudit, nil
 	default:
 		return firewall.ActionDrop, fmt.Errorf("invalid action type: %d", action)
 	}
@@ -53,7 +53,7 @@ func TestDefaultManager(t *testing.T) {
 	}).AnyTimes()
 	ifaceMock.EXPECT().GetWGDevice().Return(nil).AnyTimes()
 
-	fw, err := firewall.NewFirewall(ifaceMock, nil, flowLogger, false, iface.DefaultMTU)
+	fw, _, err := firewall.NewFirewall(ifaceMock, nil, flowLogger, false, iface.DefaultMTU, firewall.BackendAuto)
 	require.NoError(t, err)
 	defer func() {
 		err = fw.Close(nil)
@@ -171,7 +171,7 @@ func TestDefaultManagerStateless(t *testing.T) {
 	}).AnyTimes()
 	ifaceMock.EXPECT().GetWGDevice().Return(nil).AnyTimes()
 
-	fw, err := firewall.NewFirewall(ifaceMock, nil, flowLogger, false, iface.DefaultMTU)
+	fw, _, err := firewall.NewFirewall(ifaceMock, nil, flowLogger, false, iface.DefaultMTU, firewall.BackendAuto)
 	require.NoError(t, err)
 	defer func() {
 		err = fw.Close(nil)
@@ -189,6 +189,74 @@ func TestDefaultManagerStateless(t *testing.T) {
 	})
 }
 
+func TestDefaultManagerBlockInbound(t *testing.T) {
+	networkMap := &mgmProto.NetworkMap{
+		FirewallRules: []*mgmProto.FirewallRule{
+			{
+				PeerIP:    "10.93.0.1",
+				Direction: mgmProto.RuleDirection_IN,
+				Action:    mgmProto.RuleAction_ACCEPT,
+				Protocol:  mgmProto.RuleProtocol_ALL,
+			},
+		},
+		InboundExceptions: []*mgmProto.FirewallRule{
+			{
+				PeerIP:    "10.93.0.2",
+				Direction: mgmProto.RuleDirection_IN,
+				Action:    mgmProto.RuleAction_ACCEPT,
+				Protocol:  mgmProto.RuleProtocol_TCP,
+				Port:      "22",
+			},
+			{
+				PeerIP:    "10.93.0.3",
+				Direction: mgmProto.RuleDirection_OUT,
+				Action:    mgmProto.RuleAction_ACCEPT,
+				Protocol:  mgmProto.RuleProtocol_ALL,
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ifaceMock := mocks.NewMockIFaceMapper(ctrl)
+	ifaceMock.EXPECT().IsUserspaceBind().Return(true).AnyTimes()
+	ifaceMock.EXPECT().SetFilter(gomock.Any())
+	network := netip.MustParsePrefix("172.0.0.1/32")
+
+	ifaceMock.EXPECT().Name().Return("lo").AnyTimes()
+	ifaceMock.EXPECT().Address().Return(wgaddr.Address{
+		IP:      network.Addr(),
+		Network: network,
+	}).AnyTimes()
+	ifaceMock.EXPECT().GetWGDevice().Return(nil).AnyTimes()
+
+	fw, _, err := firewall.NewFirewall(ifaceMock, nil, flowLogger, false, iface.DefaultMTU, firewall.BackendAuto)
+	require.NoError(t, err)
+	defer func() {
+		err = fw.Close(nil)
+		require.NoError(t, err)
+	}()
+
+	icmp, err := ParseInboundException("icmp")
+	require.NoError(t, err)
+
+	acl := NewDefaultManager(fw)
+	acl.BlockInbound([]*mgmProto.FirewallRule{icmp})
+
+	t.Run("only inbound exceptions are applied", func(t *testing.T) {
+		acl.ApplyFiltering(networkMap, false)
+		assert.Equal(t, 2, len(acl.peerRulesPairs))
+	})
+
+	t.Run("no allow-all rule for empty rules", func(t *testing.T) {
+		networkMap.FirewallRules = nil
+		networkMap.InboundExceptions = nil
+		acl.ApplyFiltering(networkMap, false)
+		assert.Equal(t, 1, len(acl.peerRulesPairs))
+	})
+}
+
 func TestPortInfoEmpty(t *testing.T) {
 	tests := []struct {
 		name     string
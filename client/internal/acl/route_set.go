@@ -0,0 +1,105 @@
+package acl
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	firewall "github.com/netbirdio/netbird/client/firewall/manager"
+	"github.com/netbirdio/netbird/client/internal/acl/id"
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+// routeSetMinDestinations is the number of destinations from which route rules that differ only in their
+// destination are applied as a single rule matching a set of the destinations instead of one rule per destination
+const routeSetMinDestinations = 2
+
+// groupRouteRules collects the static IPv4 route rules that differ only in their destination. It returns the groups
+// that are large enough to be applied with a destination set and the rules that are applied one by one.
+// Audit rules aren't grouped so their counters stay per rule.
+func groupRouteRules(rules []*mgmProto.RouteFirewallRule) ([][]*mgmProto.RouteFirewallRule, []*mgmProto.RouteFirewallRule) {
+	bySelector := make(map[string][]*mgmProto.RouteFirewallRule)
+	var selectors []string
+	var single []*mgmProto.RouteFirewallRule
+
+	for _, rule := range rules {
+		if !isGroupableRouteRule(rule) {
+			single = append(single, rule)
+			continue
+		}
+
+		selector := getRouteRuleGroupingSelector(rule)
+		if _, ok := bySelector[selector]; !ok {
+			selectors = append(selectors, selector)
+		}
+		bySelector[selector] = append(bySelector[selector], rule)
+	}
+
+	var groups [][]*mgmProto.RouteFirewallRule
+	for _, selector := range selectors {
+		group := bySelector[selector]
+		if len(group) < routeSetMinDestinations {
+			single = append(single, group...)
+			continue
+		}
+		groups = append(groups, group)
+	}
+	return groups, single
+}
+
+func isGroupableRouteRule(rule *mgmProto.RouteFirewallRule) bool {
+	if rule.IsDynamic || len(rule.SourceRanges) == 0 || rule.Action == mgmProto.RuleAction_AUDIT {
+		return false
+	}
+
+	prefix, err := netip.ParsePrefix(rule.Destination)
+	return err == nil && prefix.Addr().Is4()
+}
+
+func getRouteRuleGroupingSelector(rule *mgmProto.RouteFirewallRule) string {
+	sources := slices.Clone(rule.SourceRanges)
+	slices.Sort(sources)
+	return fmt.Sprintf("%x:%s:%v:%v:%v:%v", rule.PolicyID, strings.Join(sources, ","), rule.Action, rule.Protocol, rule.CustomProtocol, rule.PortInfo)
+}
+
+// applyRouteACLGroup adds a single rule for the group that matches the destinations of all rules with a set
+func (d *DefaultManager) applyRouteACLGroup(group []*mgmProto.RouteFirewallRule) (id.RuleID, error) {
+	sources, err := parseSourceRanges(group[0])
+	if err != nil {
+		return "", err
+	}
+
+	destinations := make([]netip.Prefix, 0, len(group))
+	for _, rule := range group {
+		prefix, err := netip.ParsePrefix(rule.Destination)
+		if err != nil {
+			return "", fmt.Errorf("parse destination: %w", err)
+		}
+		destinations = append(destinations, prefix.Masked())
+	}
+	// overlapping prefixes can't be added to an interval set
+	firewall.SortPrefixes(destinations)
+	destinations = firewall.MergeIPRanges(destinations)
+	set := firewall.NewPrefixSet(destinations)
+
+	ruleID, err := d.addRouteRule(group[0], sources, firewall.Network{Set: set})
+	if err != nil {
+		return "", err
+	}
+
+	// the set of an existing rule already holds the destinations, its name is derived from them
+	if _, exists := d.routeRules[ruleID]; exists {
+		return ruleID, nil
+	}
+
+	if err := d.firewall.UpdateSet(set, destinations); err != nil {
+		if delErr := d.firewall.DeleteRouteRule(ruleID); delErr != nil {
+			log.Errorf("failed to delete route rule %s after a failed set update: %v", ruleID, delErr)
+		}
+		return "", fmt.Errorf("update set %s: %w", set.HashedName(), err)
+	}
+	return ruleID, nil
+}
@@ -0,0 +1,95 @@
+package acl
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/firewall"
+	"github.com/netbirdio/netbird/client/iface"
+	"github.com/netbirdio/netbird/client/iface/wgaddr"
+	"github.com/netbirdio/netbird/client/internal/acl/mocks"
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+func routeRule(policy, destination string, sources ...string) *mgmProto.RouteFirewallRule {
+	return &mgmProto.RouteFirewallRule{
+		PolicyID:     []byte(policy),
+		SourceRanges: sources,
+		Destination:  destination,
+		Action:       mgmProto.RuleAction_ACCEPT,
+		Protocol:     mgmProto.RuleProtocol_TCP,
+		PortInfo:     &mgmProto.PortInfo{PortSelection: &mgmProto.PortInfo_Port{Port: 443}},
+	}
+}
+
+func TestGroupRouteRules(t *testing.T) {
+	audit := routeRule("policy1", "10.0.3.0/24", "100.64.0.0/10")
+	audit.Action = mgmProto.RuleAction_AUDIT
+
+	dynamic := routeRule("policy1", "0.0.0.0/0", "100.64.0.0/10")
+	dynamic.IsDynamic = true
+	dynamic.Domains = []string{"example.com"}
+
+	rules := []*mgmProto.RouteFirewallRule{
+		routeRule("policy1", "10.0.1.0/24", "100.64.0.0/10", "100.100.0.0/16"),
+		routeRule("policy1", "10.0.2.0/24", "100.100.0.0/16", "100.64.0.0/10"),
+		routeRule("policy2", "10.0.4.0/24", "100.64.0.0/10"),
+		routeRule("policy1", "fd00::/64", "100.64.0.0/10", "100.100.0.0/16"),
+		audit,
+		dynamic,
+	}
+
+	groups, single := groupRouteRules(rules)
+	require.Len(t, groups, 1, "rules differing only in the destination should be grouped")
+	assert.ElementsMatch(t, rules[:2], groups[0])
+	assert.ElementsMatch(t, rules[2:], single)
+}
+
+func TestDefaultManagerRouteSets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ifaceMock := mocks.NewMockIFaceMapper(ctrl)
+	ifaceMock.EXPECT().IsUserspaceBind().Return(true).AnyTimes()
+	ifaceMock.EXPECT().SetFilter(gomock.Any())
+	network := netip.MustParsePrefix("172.0.0.1/32")
+
+	ifaceMock.EXPECT().Name().Return("lo").AnyTimes()
+	ifaceMock.EXPECT().Address().Return(wgaddr.Address{
+		IP:      network.Addr(),
+		Network: network,
+	}).AnyTimes()
+	ifaceMock.EXPECT().GetWGDevice().Return(nil).AnyTimes()
+
+	fw, _, err := firewall.NewFirewall(ifaceMock, nil, flowLogger, false, iface.DefaultMTU, firewall.BackendAuto)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, fw.Close(nil))
+	}()
+
+	acl := NewDefaultManager(fw)
+
+	networkMap := &mgmProto.NetworkMap{
+		RoutesFirewallRules: []*mgmProto.RouteFirewallRule{
+			routeRule("policy1", "10.0.1.0/24", "100.64.0.0/10"),
+			routeRule("policy1", "10.0.2.0/24", "100.64.0.0/10"),
+			routeRule("policy1", "10.0.2.128/25", "100.64.0.0/10"),
+			routeRule("policy2", "10.0.4.0/24", "100.64.0.0/10"),
+		},
+	}
+
+	acl.ApplyFiltering(networkMap, false)
+	assert.Len(t, acl.routeRules, 2, "the destinations of policy1 should share a single rule")
+
+	// applying the same rules again doesn't leave duplicates behind
+	acl.ApplyFiltering(networkMap, false)
+	assert.Len(t, acl.routeRules, 2)
+
+	networkMap.RoutesFirewallRules = networkMap.RoutesFirewallRules[2:]
+	acl.ApplyFiltering(networkMap, false)
+	assert.Len(t, acl.routeRules, 2, "a group that shrank below the set size should fall back to a single rule")
+}
@@ -0,0 +1,197 @@
+package acl
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+const (
+	// MaxClockSkew is the difference between the clocks of the peer and management above which the peer no longer
+	// trusts its clock to enforce rule schedules
+	MaxClockSkew = 5 * time.Minute
+
+	minutesPerDay = 24 * 60
+)
+
+// scheduledRule is a firewall or route firewall rule of management
+type scheduledRule interface {
+	GetSchedule() *mgmProto.RuleSchedule
+	GetAction() mgmProto.RuleAction
+}
+
+// scheduleState returns whether the schedule is active at now and the next time its state may change. The next
+// time is zero if the state doesn't change anymore.
+func scheduleState(schedule *mgmProto.RuleSchedule, now time.Time) (bool, time.Time, error) {
+	if schedule == nil {
+		return true, time.Time{}, nil
+	}
+
+	loc, err := time.LoadLocation(schedule.GetTimeZone())
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("invalid time zone %q: %w", schedule.GetTimeZone(), err)
+	}
+	now = now.In(loc)
+
+	var next time.Time
+	addTransition := func(t time.Time) {
+		if t.After(now) && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+
+	active := true
+	if notBefore := schedule.GetNotBefore(); notBefore != nil {
+		start := notBefore.AsTime()
+		active = !now.Before(start)
+		addTransition(start)
+	}
+	if notAfter := schedule.GetNotAfter(); notAfter != nil {
+		end := notAfter.AsTime()
+		if !now.Before(end) {
+			// expired for good
+			return false, time.Time{}, nil
+		}
+		addTransition(end)
+	}
+
+	if len(schedule.GetWindows()) == 0 {
+		return active, next, nil
+	}
+
+	inWindow := false
+	for _, window := range schedule.GetWindows() {
+		if err := validateWindow(window); err != nil {
+			return false, time.Time{}, err
+		}
+
+		// a window starting on the day before may still be open, the windows of the next week bound the next change
+		for day := -1; day <= 7; day++ {
+			year, month, dayOfMonth := now.Year(), now.Month(), now.Day()+day
+			if !windowOnWeekday(window, time.Date(year, month, dayOfMonth, 0, 0, 0, 0, loc).Weekday()) {
+				continue
+			}
+
+			// the bounds are wall clock times, adding minutes to midnight would be off by an hour on DST changes
+			startMinute, endMinute := int(window.GetStartMinute()), int(window.GetEndMinute())
+			endDay := dayOfMonth
+			if endMinute <= startMinute {
+				endDay++
+			}
+			start := time.Date(year, month, dayOfMonth, startMinute/60, startMinute%60, 0, 0, loc)
+			end := time.Date(year, month, endDay, endMinute/60, endMinute%60, 0, 0, loc)
+
+			if !now.Before(start) && now.Before(end) {
+				inWindow = true
+			}
+			addTransition(start)
+			addTransition(end)
+		}
+	}
+
+	return active && inWindow, next, nil
+}
+
+func validateWindow(window *mgmProto.ScheduleWindow) error {
+	if window.GetStartMinute() >= minutesPerDay || window.GetEndMinute() > minutesPerDay {
+		return fmt.Errorf("invalid schedule window %s", formatWindow(window))
+	}
+	if window.GetStartMinute() == window.GetEndMinute() {
+		return fmt.Errorf("empty schedule window %s", formatWindow(window))
+	}
+	for _, weekday := range window.GetWeekdays() {
+		if weekday > uint32(time.Saturday) {
+			return fmt.Errorf("invalid weekday %d in schedule window", weekday)
+		}
+	}
+	return nil
+}
+
+func windowOnWeekday(window *mgmProto.ScheduleWindow, weekday time.Weekday) bool {
+	if len(window.GetWeekdays()) == 0 {
+		return true
+	}
+	for _, day := range window.GetWeekdays() {
+		if time.Weekday(day) == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+func formatWindow(window *mgmProto.ScheduleWindow) string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d",
+		window.GetStartMinute()/60, window.GetStartMinute()%60,
+		window.GetEndMinute()/60, window.GetEndMinute()%60)
+}
+
+// parseScheduleWindow parses the days and the hours of a window in the local time zone, e.g. mon-fri 09:00-17:00,
+// sat,sun 10:00-14:00 or daily 22:00-06:00
+func parseScheduleWindow(days, hours string) (*mgmProto.RuleSchedule, error) {
+	window := &mgmProto.ScheduleWindow{}
+
+	if !strings.EqualFold(days, "daily") {
+		for _, part := range strings.Split(days, ",") {
+			first, last, isRange := strings.Cut(part, "-")
+			start, err := parseWeekday(first)
+			if err != nil {
+				return nil, err
+			}
+			end := start
+			if isRange {
+				if end, err = parseWeekday(last); err != nil {
+					return nil, err
+				}
+			}
+			// ranges may wrap around the week, e.g. fri-mon
+			for day := start; ; day = (day + 1) % 7 {
+				window.Weekdays = append(window.Weekdays, uint32(day))
+				if day == end {
+					break
+				}
+			}
+		}
+	}
+
+	from, to, ok := strings.Cut(hours, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid hours %q, the format is hh:mm-hh:mm", hours)
+	}
+	start, err := parseMinuteOfDay(from)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseMinuteOfDay(to)
+	if err != nil {
+		return nil, err
+	}
+	window.StartMinute = start
+	window.EndMinute = end
+
+	if err := validateWindow(window); err != nil {
+		return nil, err
+	}
+	return &mgmProto.RuleSchedule{Windows: []*mgmProto.ScheduleWindow{window}, TimeZone: "Local"}, nil
+}
+
+func parseWeekday(day string) (time.Weekday, error) {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if strings.EqualFold(day, weekday.String()[:3]) {
+			return weekday, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid weekday %q, valid values are mon, tue, wed, thu, fri, sat and sun", day)
+}
+
+func parseMinuteOfDay(value string) (uint32, error) {
+	if value == "24:00" {
+		return minutesPerDay, nil
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, the format is hh:mm", value)
+	}
+	return uint32(t.Hour()*60 + t.Minute()), nil
+}
@@ -0,0 +1,228 @@
+package acl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+func TestScheduleState(t *testing.T) {
+	businessHours := &mgmProto.ScheduleWindow{Weekdays: []uint32{1, 2, 3, 4, 5}, StartMinute: 9 * 60, EndMinute: 17 * 60}
+	nightShift := &mgmProto.ScheduleWindow{StartMinute: 22 * 60, EndMinute: 6 * 60}
+
+	// Wednesday
+	day := time.Date(2026, time.October, 14, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		schedule   *mgmProto.RuleSchedule
+		now        time.Time
+		wantActive bool
+		wantNext   time.Time
+		wantErr    bool
+	}{
+		{
+			name:       "no schedule",
+			now:        day,
+			wantActive: true,
+		},
+		{
+			name:       "within business hours",
+			schedule:   &mgmProto.RuleSchedule{Windows: []*mgmProto.ScheduleWindow{businessHours}},
+			now:        day.Add(10 * time.Hour),
+			wantActive: true,
+			wantNext:   day.Add(17 * time.Hour),
+		},
+		{
+			name:       "after business hours",
+			schedule:   &mgmProto.RuleSchedule{Windows: []*mgmProto.ScheduleWindow{businessHours}},
+			now:        day.Add(18 * time.Hour),
+			wantActive: false,
+			wantNext:   day.Add(33 * time.Hour),
+		},
+		{
+			name:       "weekend",
+			schedule:   &mgmProto.RuleSchedule{Windows: []*mgmProto.ScheduleWindow{businessHours}},
+			now:        day.AddDate(0, 0, 3).Add(10 * time.Hour),
+			wantActive: false,
+			wantNext:   day.AddDate(0, 0, 5).Add(9 * time.Hour),
+		},
+		{
+			name:       "window of the day before continues after midnight",
+			schedule:   &mgmProto.RuleSchedule{Windows: []*mgmProto.ScheduleWindow{nightShift}},
+			now:        day.Add(2 * time.Hour),
+			wantActive: true,
+			wantNext:   day.Add(6 * time.Hour),
+		},
+		{
+			name: "windows in a time zone",
+			schedule: &mgmProto.RuleSchedule{
+				Windows:  []*mgmProto.ScheduleWindow{businessHours},
+				TimeZone: "America/New_York",
+			},
+			// 08:00 in New York
+			now:        day.Add(12 * time.Hour),
+			wantActive: false,
+			wantNext:   day.Add(13 * time.Hour),
+		},
+		{
+			name: "not active yet",
+			schedule: &mgmProto.RuleSchedule{
+				NotBefore: timestamppb.New(day.AddDate(0, 0, 1)),
+			},
+			now:        day,
+			wantActive: false,
+			wantNext:   day.AddDate(0, 0, 1),
+		},
+		{
+			name: "expires",
+			schedule: &mgmProto.RuleSchedule{
+				Windows:  []*mgmProto.ScheduleWindow{businessHours},
+				NotAfter: timestamppb.New(day.Add(12 * time.Hour)),
+			},
+			now:        day.Add(10 * time.Hour),
+			wantActive: true,
+			wantNext:   day.Add(12 * time.Hour),
+		},
+		{
+			name: "expired",
+			schedule: &mgmProto.RuleSchedule{
+				Windows:  []*mgmProto.ScheduleWindow{businessHours},
+				NotAfter: timestamppb.New(day.Add(12 * time.Hour)),
+			},
+			now:        day.Add(13 * time.Hour),
+			wantActive: false,
+		},
+		{
+			name:     "invalid time zone",
+			schedule: &mgmProto.RuleSchedule{TimeZone: "Mars/Olympus_Mons"},
+			now:      day,
+			wantErr:  true,
+		},
+		{
+			name: "invalid window",
+			schedule: &mgmProto.RuleSchedule{Windows: []*mgmProto.ScheduleWindow{
+				{StartMinute: 1440, EndMinute: 60},
+			}},
+			now:     day,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			active, next, err := scheduleState(tt.schedule, tt.now)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantActive, active)
+			assert.True(t, tt.wantNext.Equal(next), "next change at %s, want %s", next, tt.wantNext)
+		})
+	}
+}
+
+func TestScheduleState_DST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	businessHours := &mgmProto.RuleSchedule{
+		Windows:  []*mgmProto.ScheduleWindow{{StartMinute: 9 * 60, EndMinute: 17 * 60}},
+		TimeZone: "Europe/Berlin",
+	}
+	nightShift := &mgmProto.RuleSchedule{
+		Windows:  []*mgmProto.ScheduleWindow{{StartMinute: 22 * 60, EndMinute: 6 * 60}},
+		TimeZone: "Europe/Berlin",
+	}
+
+	tests := []struct {
+		name       string
+		schedule   *mgmProto.RuleSchedule
+		now        time.Time
+		wantActive bool
+		wantNext   time.Time
+	}{
+		{
+			name:       "before the window on the day clocks go back",
+			schedule:   businessHours,
+			now:        time.Date(2026, time.October, 25, 8, 30, 0, 0, berlin),
+			wantActive: false,
+			wantNext:   time.Date(2026, time.October, 25, 9, 0, 0, 0, berlin),
+		},
+		{
+			name:       "end of the window on the day clocks go forward",
+			schedule:   businessHours,
+			now:        time.Date(2026, time.March, 29, 16, 30, 0, 0, berlin),
+			wantActive: true,
+			wantNext:   time.Date(2026, time.March, 29, 17, 0, 0, 0, berlin),
+		},
+		{
+			name:       "window across midnight when clocks go forward",
+			schedule:   nightShift,
+			now:        time.Date(2026, time.March, 29, 5, 30, 0, 0, berlin),
+			wantActive: true,
+			wantNext:   time.Date(2026, time.March, 29, 6, 0, 0, 0, berlin),
+		},
+		{
+			name:       "window across midnight when clocks go back",
+			schedule:   nightShift,
+			now:        time.Date(2026, time.October, 25, 6, 30, 0, 0, berlin),
+			wantActive: false,
+			wantNext:   time.Date(2026, time.October, 25, 22, 0, 0, 0, berlin),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			active, next, err := scheduleState(tt.schedule, tt.now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantActive, active)
+			assert.True(t, tt.wantNext.Equal(next), "next change at %s, want %s", next, tt.wantNext)
+		})
+	}
+}
+
+func TestActiveRules(t *testing.T) {
+	now := time.Date(2026, time.October, 14, 18, 0, 0, 0, time.UTC)
+	businessHours := &mgmProto.RuleSchedule{Windows: []*mgmProto.ScheduleWindow{{StartMinute: 9 * 60, EndMinute: 17 * 60}}}
+
+	always := &mgmProto.FirewallRule{PeerIP: "100.64.0.1", Action: mgmProto.RuleAction_ACCEPT}
+	scheduledAccept := &mgmProto.FirewallRule{PeerIP: "100.64.0.2", Action: mgmProto.RuleAction_ACCEPT, Schedule: businessHours}
+	scheduledDrop := &mgmProto.FirewallRule{PeerIP: "100.64.0.3", Action: mgmProto.RuleAction_DROP, Schedule: businessHours}
+	rules := []*mgmProto.FirewallRule{always, scheduledAccept, scheduledDrop}
+
+	d := NewDefaultManager(nil)
+	d.now = func() time.Time { return now }
+
+	assert.Equal(t, []*mgmProto.FirewallRule{always}, activeRules(d, rules), "both scheduled rules are outside of their window")
+	assert.Equal(t, now.Add(15*time.Hour), d.nextScheduleChange)
+
+	d.now = func() time.Time { return now.Add(-8 * time.Hour) }
+	assert.Equal(t, rules, activeRules(d, rules))
+
+	// without a trusted clock the scheduled rules can't open anything
+	d.clockUntrusted = true
+	assert.Equal(t, []*mgmProto.FirewallRule{always, scheduledDrop}, activeRules(d, rules))
+}
+
+func TestDefaultManager_SetClockSkew(t *testing.T) {
+	d := NewDefaultManager(nil)
+
+	d.SetClockSkew(-time.Minute)
+	assert.False(t, d.clockUntrusted)
+
+	d.SetClockSkew(MaxClockSkew + time.Second)
+	assert.True(t, d.clockUntrusted)
+
+	d.SetClockSkew(-MaxClockSkew - time.Second)
+	assert.True(t, d.clockUntrusted)
+
+	d.SetClockSkew(0)
+	assert.False(t, d.clockUntrusted)
+}
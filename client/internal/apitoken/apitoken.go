@@ -0,0 +1,236 @@
+// Package apitoken manages the tokens of the monitoring API of the daemon. A token grants read access to the scopes
+// it was created with. Only the SHA-256 hash of a token is stored, the token itself is shown once on creation.
+package apitoken
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/netbirdio/netbird/util"
+)
+
+// Scope is a permission granted by a token
+type Scope string
+
+const (
+	// ScopeStatusRead allows reading the status, the peers and the statistics of the client
+	ScopeStatusRead Scope = "status:read"
+	// ScopeEventsRead allows reading and subscribing to the system events
+	ScopeEventsRead Scope = "events:read"
+
+	// tokenPrefix makes the tokens recognizable, e.g. by secret scanners
+	tokenPrefix = "nbt_"
+	idLength    = 8
+	secretBytes = 32
+)
+
+// Scopes are the known scopes
+var Scopes = []Scope{ScopeStatusRead, ScopeEventsRead}
+
+var (
+	// ErrInvalidToken is returned for unknown and malformed tokens
+	ErrInvalidToken = errors.New("invalid API token")
+	// ErrNotFound is returned when revoking an unknown token
+	ErrNotFound = errors.New("API token not found")
+)
+
+// ParseScope returns the scope of the name
+func ParseScope(name string) (Scope, error) {
+	scope := Scope(strings.ToLower(strings.TrimSpace(name)))
+	if !slices.Contains(Scopes, scope) {
+		return "", fmt.Errorf("unknown scope %q, expected one of %v", name, Scopes)
+	}
+	return scope, nil
+}
+
+// Token is a stored API token
+type Token struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Hash      string    `json:"hash"`
+	Scopes    []Scope   `json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// HasScope reports whether the token grants the scope
+func (t Token) HasScope(scope Scope) bool {
+	return slices.Contains(t.Scopes, scope)
+}
+
+type tokenFile struct {
+	Tokens []Token `json:"tokens"`
+}
+
+// Store keeps the tokens in a JSON file readable by root only
+type Store struct {
+	path string
+
+	mu     sync.Mutex
+	tokens []Token
+	loaded bool
+}
+
+// NewStore returns the store of the file, the file is read on first use
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Create generates a token with the scopes and returns it with its stored form
+func (s *Store) Create(ctx context.Context, name string, scopes []Scope) (string, Token, error) {
+	if len(scopes) == 0 {
+		return "", Token{}, errors.New("at least one scope is required")
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(Scopes, scope) {
+			return "", Token{}, fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return "", Token{}, err
+	}
+
+	id, err := randomString(idLength / 2)
+	if err != nil {
+		return "", Token{}, err
+	}
+	secret := make([]byte, secretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", Token{}, fmt.Errorf("generate token: %w", err)
+	}
+	plain := tokenPrefix + id + "_" + base64.RawURLEncoding.EncodeToString(secret)
+
+	token := Token{
+		ID:        id,
+		Name:      name,
+		Hash:      hashToken(plain),
+		Scopes:    slices.Compact(slices.Sorted(slices.Values(scopes))),
+		CreatedAt: time.Now().UTC(),
+	}
+	tokens := append(slices.Clone(s.tokens), token)
+	if err := s.save(ctx, tokens); err != nil {
+		return "", Token{}, err
+	}
+	return plain, token, nil
+}
+
+// List returns the stored tokens
+func (s *Store) List() ([]Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return slices.Clone(s.tokens), nil
+}
+
+// Revoke deletes the token with the ID or name
+func (s *Store) Revoke(ctx context.Context, idOrName string) (Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return Token{}, err
+	}
+
+	i := slices.IndexFunc(s.tokens, func(t Token) bool {
+		return t.ID == idOrName || (t.Name != "" && t.Name == idOrName)
+	})
+	if i < 0 {
+		return Token{}, fmt.Errorf("%w: %s", ErrNotFound, idOrName)
+	}
+
+	revoked := s.tokens[i]
+	if err := s.save(ctx, slices.Delete(slices.Clone(s.tokens), i, i+1)); err != nil {
+		return Token{}, err
+	}
+	return revoked, nil
+}
+
+// Authenticate returns the stored token of the presented one
+func (s *Store) Authenticate(plain string) (Token, error) {
+	id, ok := tokenID(plain)
+	if !ok {
+		return Token{}, ErrInvalidToken
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return Token{}, err
+	}
+
+	hash := hashToken(plain)
+	for _, token := range s.tokens {
+		if token.ID == id && subtle.ConstantTimeCompare([]byte(token.Hash), []byte(hash)) == 1 {
+			return token, nil
+		}
+	}
+	return Token{}, ErrInvalidToken
+}
+
+// load reads the file once. The caller must hold the lock.
+func (s *Store) load() error {
+	if s.loaded {
+		return nil
+	}
+
+	var file tokenFile
+	if _, err := util.ReadJson(s.path, &file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("read API tokens: %w", err)
+	}
+	s.tokens = file.Tokens
+	s.loaded = true
+	return nil
+}
+
+// save writes the tokens and keeps them on success. The caller must hold the lock.
+func (s *Store) save(ctx context.Context, tokens []Token) error {
+	if err := util.WriteJsonWithRestrictedPermission(ctx, s.path, tokenFile{Tokens: tokens}); err != nil {
+		return fmt.Errorf("write API tokens: %w", err)
+	}
+	s.tokens = tokens
+	return nil
+}
+
+// tokenID returns the ID part of a token
+func tokenID(plain string) (string, bool) {
+	rest, ok := strings.CutPrefix(plain, tokenPrefix)
+	if !ok {
+		return "", false
+	}
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(id) != idLength || secret == "" {
+		return "", false
+	}
+	return id, true
+}
+
+func hashToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate token id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
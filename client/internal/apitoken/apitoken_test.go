@@ -0,0 +1,79 @@
+package apitoken
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "api_tokens.json")
+	store := NewStore(path)
+
+	plain, token, err := store.Create(ctx, "prometheus", []Scope{ScopeStatusRead, ScopeStatusRead})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(plain, tokenPrefix+token.ID+"_"))
+	assert.Equal(t, []Scope{ScopeStatusRead}, token.Scopes)
+	assert.NotContains(t, token.Hash, plain, "the token itself must not be stored")
+
+	authenticated, err := store.Authenticate(plain)
+	require.NoError(t, err)
+	assert.Equal(t, token.ID, authenticated.ID)
+	assert.True(t, authenticated.HasScope(ScopeStatusRead))
+	assert.False(t, authenticated.HasScope(ScopeEventsRead))
+
+	// the tokens are persisted
+	reloaded := NewStore(path)
+	tokens, err := reloaded.List()
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, "prometheus", tokens[0].Name)
+	_, err = reloaded.Authenticate(plain)
+	require.NoError(t, err)
+
+	revoked, err := reloaded.Revoke(ctx, "prometheus")
+	require.NoError(t, err)
+	assert.Equal(t, token.ID, revoked.ID)
+	_, err = reloaded.Authenticate(plain)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = reloaded.Revoke(ctx, token.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestStore_Authenticate_Invalid(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "api_tokens.json"))
+	plain, token, err := store.Create(context.Background(), "", []Scope{ScopeEventsRead})
+	require.NoError(t, err)
+
+	for _, presented := range []string{
+		"",
+		"nbt_",
+		plain[:len(plain)-1],
+		strings.TrimPrefix(plain, tokenPrefix),
+		tokenPrefix + token.ID + "_forged",
+		tokenPrefix + "00000000" + strings.TrimPrefix(plain, tokenPrefix+token.ID),
+	} {
+		_, err := store.Authenticate(presented)
+		assert.ErrorIs(t, err, ErrInvalidToken, presented)
+	}
+}
+
+func TestStore_Create_Scopes(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "api_tokens.json"))
+
+	_, _, err := store.Create(context.Background(), "none", nil)
+	assert.Error(t, err)
+	_, _, err = store.Create(context.Background(), "admin", []Scope{"config:write"})
+	assert.Error(t, err)
+
+	_, err = ParseScope(" Events:Read ")
+	assert.NoError(t, err)
+	_, err = ParseScope("config:write")
+	assert.Error(t, err)
+}
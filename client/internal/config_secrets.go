@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/profilemanager"
+	"github.com/netbirdio/netbird/client/internal/secrets"
+	"github.com/netbirdio/netbird/client/ssh"
+)
+
+// secretsReloadInterval is how often secrets from external providers are resolved again to pick up rotations
+const secretsReloadInterval = 5 * time.Minute
+
+// configSecrets holds the resolved secrets of the config, which may refer to external secret providers
+type configSecrets struct {
+	preSharedKey string
+	sshKey       string
+}
+
+// hasSecretReferences reports whether any of the config secrets is kept by an external provider
+func hasSecretReferences(config *profilemanager.Config) bool {
+	return secrets.IsReference(config.PreSharedKey) || secrets.IsReference(config.SSHKey)
+}
+
+func resolveConfigSecrets(ctx context.Context, config *profilemanager.Config) (configSecrets, error) {
+	preSharedKey, err := secrets.Resolve(ctx, config.PreSharedKey)
+	if err != nil {
+		return configSecrets{}, fmt.Errorf("resolve pre-shared key: %w", err)
+	}
+
+	sshKey, err := secrets.Resolve(ctx, config.SSHKey)
+	if err != nil {
+		return configSecrets{}, fmt.Errorf("resolve ssh key: %w", err)
+	}
+
+	return configSecrets{preSharedKey: preSharedKey, sshKey: sshKey}, nil
+}
+
+// publicSSHKey returns the public key of the configured SSH key
+func publicSSHKey(ctx context.Context, config *profilemanager.Config) ([]byte, error) {
+	sshKey, err := secrets.Resolve(ctx, config.SSHKey)
+	if err != nil {
+		return nil, fmt.Errorf("resolve ssh key: %w", err)
+	}
+	return ssh.GeneratePublicKey([]byte(sshKey))
+}
+
+// watchConfigSecrets periodically resolves the external secrets and calls restart once they changed,
+// so the engine picks up rotated secrets without a manual reconnect.
+func watchConfigSecrets(ctx context.Context, config *profilemanager.Config, current configSecrets, restart func()) {
+	ticker := time.NewTicker(secretsReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			resolved, err := resolveConfigSecrets(ctx, config)
+			if err != nil {
+				log.Warnf("failed to reload secrets, keeping the current ones: %v", err)
+				continue
+			}
+			if resolved == current {
+				continue
+			}
+
+			log.Infof("secrets from external providers changed, restarting engine")
+			restart()
+			return
+		}
+	}
+}
@@ -23,13 +23,14 @@ import (
 // - Maintaining a list of excluded peers that should always have permanent connections
 // - Handling connection establishment based on peer signaling
 //
-// The implementation is not thread-safe; it is protected by engine.syncMsgMux.
+// The implementation is not thread-safe; it is protected by engine.peerConnMux.
 type ConnMgr struct {
 	peerStore        *peerstore.Store
 	statusRecorder   *peer.Status
 	iface            lazyconn.WGIface
 	enabledLocally   bool
 	rosenpassEnabled bool
+	inactivity       lazyconn.InactivityConfig
 
 	lazyConnMgr *manager.Manager
 
@@ -44,6 +45,7 @@ func NewConnMgr(engineConfig *EngineConfig, statusRecorder *peer.Status, peerSto
 		statusRecorder:   statusRecorder,
 		iface:            iface,
 		rosenpassEnabled: engineConfig.RosenpassEnabled,
+		inactivity:       engineConfig.LazyConnInactivity,
 	}
 	if engineConfig.LazyConnectionEnabled || lazyconn.IsLazyConnEnabledByEnv() {
 		e.enabledLocally = true
@@ -138,6 +140,7 @@ func (e *ConnMgr) SetExcludeList(ctx context.Context, peerIDs map[string]bool) {
 			AllowedIPs: peerConn.WgConfig().AllowedIps,
 			PeerConnID: peerConn.ConnID(),
 			Log:        peerConn.Log,
+			Inactivity: peerConn.LazyInactivity(),
 		}
 		excludedPeers = append(excludedPeers, lazyPeerCfg)
 	}
@@ -170,7 +173,7 @@ func (e *ConnMgr) AddPeerConn(ctx context.Context, peerKey string, conn *peer.Co
 		return
 	}
 
-	if !lazyconn.IsSupported(conn.AgentVersionString()) {
+	if !conn.SupportsLazyConnection() {
 		conn.Log.Warnf("peer does not support lazy connection (%s), open permanent connection", conn.AgentVersionString())
 		if err := conn.Open(ctx); err != nil {
 			conn.Log.Errorf("failed to open connection: %v", err)
@@ -183,6 +186,7 @@ func (e *ConnMgr) AddPeerConn(ctx context.Context, peerKey string, conn *peer.Co
 		AllowedIPs: conn.WgConfig().AllowedIps,
 		PeerConnID: conn.ConnID(),
 		Log:        conn.Log,
+		Inactivity: conn.LazyInactivity(),
 	}
 	excluded, err := e.lazyConnMgr.AddPeer(lazyPeerCfg)
 	if err != nil {
@@ -254,8 +258,16 @@ func (e *ConnMgr) Close() {
 }
 
 func (e *ConnMgr) initLazyManager(engineCtx context.Context) {
+	inactivity := e.inactivity
+	if threshold := inactivityThresholdEnv(); threshold != nil {
+		inactivity.Threshold = *threshold
+	}
+
 	cfg := manager.Config{
-		InactivityThreshold: inactivityThresholdEnv(),
+		Inactivity: inactivity,
+	}
+	if budget := e.statusRecorder.ResourceBudget(); budget != nil {
+		cfg.Budget = budget
 	}
 	e.lazyConnMgr = manager.NewManager(cfg, engineCtx, e.peerStore, e.iface)
 
@@ -284,6 +296,7 @@ func (e *ConnMgr) addPeersToLazyConnManager() error {
 			AllowedIPs: peerConn.WgConfig().AllowedIps,
 			PeerConnID: peerConn.ConnID(),
 			Log:        peerConn.Log,
+			Inactivity: peerConn.LazyInactivity(),
 		}
 		lazyPeerCfgs = append(lazyPeerCfgs, lazyPeerCfg)
 	}
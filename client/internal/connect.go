@@ -8,7 +8,6 @@ import (
 	"net/netip"
 	"runtime"
 	"runtime/debug"
-	"strings"
 	"sync"
 	"time"
 
@@ -18,11 +17,15 @@ import (
 	"google.golang.org/grpc/codes"
 	gstatus "google.golang.org/grpc/status"
 
+	"github.com/netbirdio/netbird/client/firewall"
 	"github.com/netbirdio/netbird/client/iface"
 	"github.com/netbirdio/netbird/client/iface/device"
+	"github.com/netbirdio/netbird/client/internal/acl"
 	"github.com/netbirdio/netbird/client/internal/dns"
+	"github.com/netbirdio/netbird/client/internal/eventbus"
 	"github.com/netbirdio/netbird/client/internal/listener"
 	"github.com/netbirdio/netbird/client/internal/peer"
+	icemaker "github.com/netbirdio/netbird/client/internal/peer/ice"
 	"github.com/netbirdio/netbird/client/internal/profilemanager"
 	"github.com/netbirdio/netbird/client/internal/statemanager"
 	"github.com/netbirdio/netbird/client/internal/stdnet"
@@ -30,13 +33,12 @@ import (
 	"github.com/netbirdio/netbird/client/internal/updatemanager/installer"
 	nbnet "github.com/netbirdio/netbird/client/net"
 	cProto "github.com/netbirdio/netbird/client/proto"
-	"github.com/netbirdio/netbird/client/ssh"
 	sshconfig "github.com/netbirdio/netbird/client/ssh/config"
+	sshserver "github.com/netbirdio/netbird/client/ssh/server"
 	"github.com/netbirdio/netbird/client/system"
 	mgm "github.com/netbirdio/netbird/shared/management/client"
 	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
 	"github.com/netbirdio/netbird/shared/relay/auth/hmac"
-	relayClient "github.com/netbirdio/netbird/shared/relay/client"
 	signal "github.com/netbirdio/netbird/shared/signal/client"
 	"github.com/netbirdio/netbird/util"
 	"github.com/netbirdio/netbird/version"
@@ -52,6 +54,14 @@ type ConnectClient struct {
 	engineMutex sync.Mutex
 
 	persistSyncResponse bool
+
+	// eventBus outlives the engines, so the subscribers keep their subscription across reconnects
+	eventBus *eventbus.Bus
+
+	// mgmResume and signalResume outlive the connections to management and Signal, so the connections of a warm
+	// restart resume the sessions of the replaced ones
+	mgmResume    *mgm.SessionResume
+	signalResume *signal.SessionResume
 }
 
 func NewConnectClient(
@@ -67,9 +77,17 @@ func NewConnectClient(
 		statusRecorder:      statusRecorder,
 		doInitialAutoUpdate: doInitalAutoUpdate,
 		engineMutex:         sync.Mutex{},
+		eventBus:            newEventBus(statusRecorder),
+		mgmResume:           mgm.NewSessionResume(),
+		signalResume:        signal.NewSessionResume(),
 	}
 }
 
+// EventBus returns the bus of the structured engine events
+func (c *ConnectClient) EventBus() *eventbus.Bus {
+	return c.eventBus
+}
+
 // Run with main logic.
 func (c *ConnectClient) Run(runningChan chan struct{}) error {
 	return c.run(MobileDependency{}, runningChan)
@@ -135,6 +153,10 @@ func (c *ConnectClient) run(mobileDependency MobileDependency, runningChan chan
 
 	nbnet.Init()
 
+	if err := applyProxy(c.config); err != nil {
+		return err
+	}
+
 	backOff := &backoff.ExponentialBackOff{
 		InitialInterval:     time.Second,
 		RandomizationFactor: 1,
@@ -165,11 +187,6 @@ func (c *ConnectClient) run(mobileDependency MobileDependency, runningChan chan
 		mgmTlsEnabled = true
 	}
 
-	publicSSHKey, err := ssh.GeneratePublicKey([]byte(c.config.SSHKey))
-	if err != nil {
-		return err
-	}
-
 	var path string
 	if runtime.GOOS == "ios" || runtime.GOOS == "android" {
 		// On mobile, use the provided state file path directly
@@ -186,6 +203,7 @@ func (c *ConnectClient) run(mobileDependency MobileDependency, runningChan chan
 	}
 	stateManager := statemanager.New(path)
 	stateManager.RegisterState(&sshconfig.ShutdownState{})
+	stateManager.RegisterState(&NetworkMapCacheState{})
 
 	updateManager, err := updatemanager.NewManager(c.statusRecorder, stateManager)
 	if err == nil {
@@ -197,6 +215,14 @@ func (c *ConnectClient) run(mobileDependency MobileDependency, runningChan chan
 		}
 	}
 
+	builder := &engineBuilder{
+		client:        c,
+		key:           myPrivateKey,
+		mgmTlsEnabled: mgmTlsEnabled,
+		mobileDep:     mobileDependency,
+		stateManager:  stateManager,
+	}
+
 	defer c.statusRecorder.ClientStop()
 	operation := func() error {
 		// if context cancelled we not start new backoff cycle
@@ -206,128 +232,24 @@ func (c *ConnectClient) run(mobileDependency MobileDependency, runningChan chan
 
 		state.Set(StatusConnecting)
 
-		engineCtx, cancel := context.WithCancel(c.ctx)
-		defer func() {
-			_, err := state.Status()
-			c.statusRecorder.MarkManagementDisconnected(err)
-			c.statusRecorder.CleanLocalPeerState()
-			cancel()
-		}()
-
-		log.Debugf("connecting to the Management service %s", c.config.ManagementURL.Host)
-		mgmClient, err := mgm.NewClient(engineCtx, c.config.ManagementURL.Host, myPrivateKey, mgmTlsEnabled)
+		session, err := builder.openSession(nil)
 		if err != nil {
-			return wrapErr(gstatus.Errorf(codes.FailedPrecondition, "failed connecting to Management Service : %s", err))
-		}
-		mgmNotifier := statusRecorderToMgmConnStateNotifier(c.statusRecorder)
-		mgmClient.SetConnStateListener(mgmNotifier)
-
-		log.Debugf("connected to the Management service %s", c.config.ManagementURL.Host)
-		defer func() {
-			if err = mgmClient.Close(); err != nil {
-				log.Warnf("failed to close the Management service client %v", err)
-			}
-		}()
-
-		// connect (just a connection, no stream yet) and login to Management Service to get an initial global Netbird config
-		loginResp, err := loginToManagement(engineCtx, mgmClient, publicSSHKey, c.config)
-		if err != nil {
-			log.Debug(err)
-			if s, ok := gstatus.FromError(err); ok && (s.Code() == codes.PermissionDenied) {
-				state.Set(StatusNeedsLogin)
-				_ = c.Stop()
-				return backoff.Permanent(wrapErr(err)) // unrecoverable error
-			}
-			return wrapErr(err)
-		}
-		c.statusRecorder.MarkManagementConnected()
-
-		localPeerState := peer.LocalPeerState{
-			IP:              loginResp.GetPeerConfig().GetAddress(),
-			PubKey:          myPrivateKey.PublicKey().String(),
-			KernelInterface: device.WireGuardModuleIsLoaded(),
-			FQDN:            loginResp.GetPeerConfig().GetFqdn(),
+			return err
 		}
-		c.statusRecorder.UpdateLocalPeerState(localPeerState)
-
-		signalURL := fmt.Sprintf("%s://%s",
-			strings.ToLower(loginResp.GetNetbirdConfig().GetSignal().GetProtocol().String()),
-			loginResp.GetNetbirdConfig().GetSignal().GetUri(),
-		)
-
-		c.statusRecorder.UpdateSignalAddress(signalURL)
-
-		c.statusRecorder.MarkSignalDisconnected(nil)
 		defer func() {
 			_, err := state.Status()
+			session.close()
 			c.statusRecorder.MarkSignalDisconnected(err)
+			c.statusRecorder.MarkManagementDisconnected(err)
+			c.statusRecorder.CleanLocalPeerState()
 		}()
 
-		// with the global Netbird config in hand connect (just a connection, no stream yet) Signal
-		signalClient, err := connectToSignal(engineCtx, loginResp.GetNetbirdConfig(), myPrivateKey)
-		if err != nil {
-			log.Error(err)
-			return wrapErr(err)
-		}
-		defer func() {
-			err = signalClient.Close()
-			if err != nil {
-				log.Warnf("failed closing Signal service client %v", err)
-			}
-		}()
-
-		signalNotifier := statusRecorderToSignalConnStateNotifier(c.statusRecorder)
-		signalClient.SetConnStateListener(signalNotifier)
-
-		c.statusRecorder.MarkSignalConnected()
-
-		relayURLs, token := parseRelayInfo(loginResp)
-		peerConfig := loginResp.GetPeerConfig()
-
-		engineConfig, err := createEngineConfig(myPrivateKey, c.config, peerConfig)
+		engine, err := builder.startEngine(session)
 		if err != nil {
-			log.Error(err)
-			return wrapErr(err)
-		}
-
-		relayManager := relayClient.NewManager(engineCtx, relayURLs, myPrivateKey.PublicKey().String(), engineConfig.MTU)
-		c.statusRecorder.SetRelayMgr(relayManager)
-		if len(relayURLs) > 0 {
-			if token != nil {
-				if err := relayManager.UpdateToken(token); err != nil {
-					log.Errorf("failed to update token: %s", err)
-					return wrapErr(err)
-				}
-			}
-			log.Infof("connecting to the Relay service(s): %s", strings.Join(relayURLs, ", "))
-			if err = relayManager.Serve(); err != nil {
-				log.Error(err)
-			}
-		}
-
-		checks := loginResp.GetChecks()
-
-		c.engineMutex.Lock()
-		engine := NewEngine(engineCtx, cancel, signalClient, mgmClient, relayManager, engineConfig, mobileDependency, c.statusRecorder, checks, stateManager)
-		engine.SetSyncResponsePersistence(c.persistSyncResponse)
-		c.engine = engine
-		c.engineMutex.Unlock()
-
-		if err := engine.Start(loginResp.GetNetbirdConfig(), c.config.ManagementURL); err != nil {
-			log.Errorf("error while starting Netbird Connection Engine: %s", err)
 			return wrapErr(err)
 		}
 
-		if loginResp.PeerConfig != nil && loginResp.PeerConfig.AutoUpdate != nil {
-			// AutoUpdate will be true when the user click on "Connect" menu on the UI
-			if c.doInitialAutoUpdate {
-				log.Infof("start engine by ui, run auto-update check")
-				c.engine.InitialUpdateHandling(loginResp.PeerConfig.AutoUpdate)
-				c.doInitialAutoUpdate = false
-			}
-		}
-
-		log.Infof("Netbird engine started, the IP is: %s", peerConfig.GetAddress())
+		log.Infof("Netbird engine started, the IP is: %s", session.loginResp.GetPeerConfig().GetAddress())
 		state.Set(StatusConnected)
 
 		if runningChan != nil {
@@ -335,21 +257,18 @@ func (c *ConnectClient) run(mobileDependency MobileDependency, runningChan chan
 			runningChan = nil
 		}
 
-		<-engineCtx.Done()
-
-		c.engineMutex.Lock()
-		c.engine = nil
-		c.engineMutex.Unlock()
-
-		// todo: consider to remove this condition. Is not thread safe.
-		// We should always call Stop(), but we need to verify that it is idempotent
-		if engine.wgInterface != nil {
-			log.Infof("ensuring %s is removed, Netbird engine context cancelled", engine.wgInterface.Name())
-
-			if err := engine.Stop(); err != nil {
-				log.Errorf("Failed to stop engine: %v", err)
+		for session.ctx.Err() == nil {
+			select {
+			case <-session.ctx.Done():
+			case <-engine.warmRestartRequests():
+				if err := builder.warmRestart(session, engine); err != nil {
+					log.Warnf("failed to restart the engine warm, restarting the client: %v", err)
+					engine.triggerClientRestart()
+				}
 			}
 		}
+
+		c.stopEngine(engine)
 		c.statusRecorder.ClientTeardown()
 
 		backOff.Reset()
@@ -376,6 +295,23 @@ func (c *ConnectClient) run(mobileDependency MobileDependency, runningChan chan
 	return nil
 }
 
+// stopEngine stops the engine
+func (c *ConnectClient) stopEngine(engine *Engine) {
+	c.engineMutex.Lock()
+	c.engine = nil
+	c.engineMutex.Unlock()
+
+	// todo: consider to remove this condition. Is not thread safe.
+	// We should always call Stop(), but we need to verify that it is idempotent
+	if engine.wgInterface != nil {
+		log.Infof("ensuring %s is removed, Netbird engine context cancelled", engine.wgInterface.Name())
+
+		if err := engine.Stop(); err != nil {
+			log.Errorf("Failed to stop engine: %v", err)
+		}
+	}
+}
+
 func parseRelayInfo(loginResp *mgmProto.LoginResponse) ([]string, *hmac.Token) {
 	relayCfg := loginResp.GetNetbirdConfig().GetRelay()
 	if relayCfg == nil {
@@ -459,7 +395,7 @@ func (c *ConnectClient) SetSyncResponsePersistence(enabled bool) {
 }
 
 // createEngineConfig converts configuration received from Management Service to EngineConfig
-func createEngineConfig(key wgtypes.Key, config *profilemanager.Config, peerConfig *mgmProto.PeerConfig) (*EngineConfig, error) {
+func createEngineConfig(key wgtypes.Key, config *profilemanager.Config, cfgSecrets configSecrets, peerConfig *mgmProto.PeerConfig) (*EngineConfig, error) {
 	nm := false
 	if config.NetworkMonitor != nil {
 		nm = *config.NetworkMonitor
@@ -472,7 +408,8 @@ func createEngineConfig(key wgtypes.Key, config *profilemanager.Config, peerConf
 		WgPrivateKey:                  key,
 		WgPort:                        config.WgPort,
 		NetworkMonitor:                nm,
-		SSHKey:                        []byte(config.SSHKey),
+		NetworkMonitorDebounce:        config.NetworkMonitorDebounce,
+		SSHKey:                        []byte(cfgSecrets.sshKey),
 		NATExternalIPs:                config.NATExternalIPs,
 		CustomDNSAddress:              config.CustomDNSAddress,
 		RosenpassEnabled:              config.RosenpassEnabled,
@@ -483,25 +420,107 @@ func createEngineConfig(key wgtypes.Key, config *profilemanager.Config, peerConf
 		EnableSSHLocalPortForwarding:  config.EnableSSHLocalPortForwarding,
 		EnableSSHRemotePortForwarding: config.EnableSSHRemotePortForwarding,
 		DisableSSHAuth:                config.DisableSSHAuth,
+		SSHRecordingDir:               config.SSHRecordingDir,
+		SSHPeerRules:                  toSSHPeerRules(config.SSHPeerRules),
 		DNSRouteInterval:              config.DNSRouteInterval,
 
 		DisableClientRoutes: config.DisableClientRoutes,
 		DisableServerRoutes: config.DisableServerRoutes || config.BlockInbound,
 		DisableDNS:          config.DisableDNS,
 		DisableFirewall:     config.DisableFirewall,
+		FirewallBackend:     firewall.Backend(config.FirewallBackend),
 		BlockLANAccess:      config.BlockLANAccess,
 		BlockInbound:        config.BlockInbound,
+		KillSwitch:          config.KillSwitch,
+		SOCKS5ProxyAddress:  config.SOCKS5ProxyAddress,
+		HTTPProxyAddress:    config.HTTPProxyAddress,
 
 		LazyConnectionEnabled: config.LazyConnectionEnabled,
+		LazyConnInactivity:    config.LazyConnInactivity,
+		ICEMulticastDNS:       config.ICEMulticastDNS,
+		ICEPolicy:             icemaker.Policy(config.ICEPolicy),
 
 		MTU: selectMTU(config.MTU, peerConfig.Mtu),
+
+		WgKeepAlive:        config.WgKeepAlive,
+		PeerWgKeepAlive:    config.PeerWgKeepAlive,
+		WgHandshakeTimeout: config.WgHandshakeTimeout,
+
+		WgHandshakeRetryInterval: config.WgHandshakeRetryInterval,
+		WgResponderDelay:         config.WgResponderDelay,
+
+		Plugins:        config.Plugins,
+		Hooks:          config.Hooks,
+		TrafficShaping: config.TrafficShaping,
+		WakeOnLAN:      config.WakeOnLAN,
+
+		RouteFailoverGrace:   config.RouteFailoverGrace,
+		DNSQueryLog:          config.DNSQueryLog,
+		DiagnosticEndpoints:  config.DiagnosticEndpoints,
+		MDNSResponder:        config.MDNSResponder,
+		ExitNodeFailsafe:     config.ExitNodeFailsafe,
+		RouteDisconnectGrace: config.RouteDisconnectGrace,
+		ExitNodeExclusions:   config.ExitNodeExclusions,
+		CriticalEndpoints:    config.CriticalEndpoints,
+		DomainRoutes:         config.DomainRoutes,
+
+		DisableNetstackFallback: config.DisableNetstackFallback,
+		InventoryReporting:      config.InventoryReporting,
+		InventoryInterval:       config.InventoryInterval,
+		PreferredRelay:          config.PreferredRelay,
+		ProxyURL:                config.ProxyURL,
+		BondedPeers:             config.BondedPeers,
+		PathMTUProbing:          config.PathMTUProbing,
+		PathMTUProbeInterval:    config.PathMTUProbeInterval,
+		DNSCollectionFilter:     config.DNSCollectionFilter,
+		ReversePathFilter:       config.ReversePathFilter,
+		DNSPublisher:            config.DNSPublisher,
+		InboundPrompts:          config.InboundPrompts,
+	}
+
+	for pubKey, bond := range config.BondedPeers {
+		if err := bond.Validate(); err != nil {
+			return nil, fmt.Errorf("bond to peer %s: %w", pubKey, err)
+		}
+	}
+
+	if config.DNSCollectionFilter != nil {
+		if err := config.DNSCollectionFilter.Validate(); err != nil {
+			return nil, fmt.Errorf("DNS collection filter: %w", err)
+		}
+	}
+
+	if config.DNSPublisher != nil {
+		if err := config.DNSPublisher.Validate(); err != nil {
+			return nil, fmt.Errorf("DNS publisher: %w", err)
+		}
 	}
 
-	if config.PreSharedKey != "" {
-		preSharedKey, err := wgtypes.ParseKey(config.PreSharedKey)
+	dnsListenAddresses, err := dns.ParseListenAddresses(config.DNSListenAddresses)
+	if err != nil {
+		return nil, fmt.Errorf("DNS listen addresses: %w", err)
+	}
+	engineConf.DNSListenAddresses = dnsListenAddresses
+
+	turnTransports, err := icemaker.ParseTURNTransports(config.TURNTransports)
+	if err != nil {
+		return nil, err
+	}
+	engineConf.TURNTransports = turnTransports
+
+	for _, exception := range config.InboundExceptions {
+		rule, err := acl.ParseInboundException(exception)
 		if err != nil {
 			return nil, err
 		}
+		engineConf.InboundExceptions = append(engineConf.InboundExceptions, rule)
+	}
+
+	if cfgSecrets.preSharedKey != "" {
+		preSharedKey, err := wgtypes.ParseKey(cfgSecrets.preSharedKey)
+		if err != nil {
+			return nil, fmt.Errorf("parse pre-shared key: %w", err)
+		}
 		engineConf.PreSharedKey = &preSharedKey
 	}
 
@@ -631,3 +650,16 @@ func closeConnWithLog(conn *net.UDPConn) {
 		log.Warnf("closing the testing port %d took %s. Usually it is safe to ignore, but continuous warnings may indicate a problem.", conn.LocalAddr().(*net.UDPAddr).Port, time.Since(startClosing))
 	}
 }
+
+func toSSHPeerRules(rules []profilemanager.SSHPeerRule) []sshserver.PeerRule {
+	var peerRules []sshserver.PeerRule
+	for _, rule := range rules {
+		peerRules = append(peerRules, sshserver.PeerRule{
+			Peers:        rule.Peers,
+			Users:        rule.Users,
+			ForceCommand: rule.ForceCommand,
+			DenyShell:    rule.DenyShell,
+		})
+	}
+	return peerRules
+}
@@ -0,0 +1,83 @@
+// Package daemonerr defines the stable reasons of the errors returned by the daemon RPCs and the CLI. The reason
+// travels as ErrorInfo detail of the gRPC status, so the CLI and other automation can branch on it instead of
+// parsing error messages.
+package daemonerr
+
+import (
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+)
+
+// Reason is the stable reason of an error
+type Reason string
+
+const (
+	// ReasonUnknown is any error without a known reason
+	ReasonUnknown Reason = "UNKNOWN"
+	// ReasonAuthRequired means the peer has to log in, interactively or with a setup key
+	ReasonAuthRequired Reason = "AUTH_REQUIRED"
+	// ReasonDaemonUnreachable means the CLI couldn't connect to the daemon, it is most likely not running
+	ReasonDaemonUnreachable Reason = "DAEMON_UNREACHABLE"
+	// ReasonPermissionDenied means the daemon socket isn't accessible by the user, the command needs sudo
+	ReasonPermissionDenied Reason = "PERMISSION_DENIED"
+	// ReasonEngineStarting means the engine is still starting, the command can be retried
+	ReasonEngineStarting Reason = "ENGINE_STARTING"
+
+	// Domain is the domain of the ErrorInfo details with a reason
+	Domain = "daemon.netbird.io"
+)
+
+// Error is an error with a reason, used for the errors that don't come from a daemon RPC
+type Error struct {
+	Reason Reason
+	Err    error
+}
+
+// New returns the error with the reason
+func New(reason Reason, err error) error {
+	return &Error{Reason: reason, Err: err}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Status returns a gRPC status error that carries the reason as ErrorInfo detail
+func Status(code codes.Code, reason Reason, msg string) error {
+	st := gstatus.New(code, msg)
+	withReason, err := st.WithDetails(&errdetails.ErrorInfo{Reason: string(reason), Domain: Domain})
+	if err != nil {
+		return st.Err()
+	}
+	return withReason.Err()
+}
+
+// ReasonOf returns the reason of the error, from an Error in the chain or from the details of a gRPC status
+func ReasonOf(err error) Reason {
+	if err == nil {
+		return ""
+	}
+
+	var reasonErr *Error
+	if errors.As(err, &reasonErr) {
+		return reasonErr.Reason
+	}
+
+	st, ok := gstatus.FromError(err)
+	if !ok {
+		return ReasonUnknown
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetDomain() == Domain {
+			return Reason(info.GetReason())
+		}
+	}
+	return ReasonUnknown
+}
@@ -0,0 +1,43 @@
+package daemonerr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+)
+
+func TestReasonOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Reason
+	}{
+		{name: "nil", err: nil, want: ""},
+		{name: "plain", err: errors.New("boom"), want: ReasonUnknown},
+		{name: "status without reason", err: gstatus.Error(codes.Internal, "boom"), want: ReasonUnknown},
+		{name: "status with reason", err: Status(codes.FailedPrecondition, ReasonAuthRequired, "login"), want: ReasonAuthRequired},
+		{name: "wrapped status", err: fmt.Errorf("up: %w", Status(codes.FailedPrecondition, ReasonEngineStarting, "starting")), want: ReasonEngineStarting},
+		{name: "error", err: New(ReasonDaemonUnreachable, errors.New("dial")), want: ReasonDaemonUnreachable},
+		{name: "wrapped error", err: fmt.Errorf("connect: %w", New(ReasonPermissionDenied, errors.New("dial"))), want: ReasonPermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ReasonOf(tt.err))
+		})
+	}
+}
+
+func TestStatusRoundTrip(t *testing.T) {
+	err := Status(codes.PermissionDenied, ReasonAuthRequired, "peer login expired")
+
+	// the status is sent over the wire as its proto form
+	received := gstatus.FromProto(gstatus.Convert(err).Proto()).Err()
+	assert.Equal(t, ReasonAuthRequired, ReasonOf(received))
+	assert.Equal(t, codes.PermissionDenied, gstatus.Code(received))
+	assert.Equal(t, "peer login expired", gstatus.Convert(received).Message())
+}
@@ -25,6 +25,7 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 
 	"github.com/netbirdio/netbird/client/anonymize"
+	"github.com/netbirdio/netbird/client/internal/eventbus"
 	"github.com/netbirdio/netbird/client/internal/peer"
 	"github.com/netbirdio/netbird/client/internal/profilemanager"
 	"github.com/netbirdio/netbird/client/internal/updatemanager/installer"
@@ -48,6 +49,7 @@ nftables.txt: Anonymized nftables rules with packet counters, if --system-info f
 resolv.conf: DNS resolver configuration from /etc/resolv.conf (Unix systems only), if --system-info flag was provided.
 scutil_dns.txt: DNS configuration from scutil --dns (macOS only), if --system-info flag was provided.
 resolved_domains.txt: Anonymized resolved domain IP addresses from the status recorder.
+engine_events.txt: Anonymized latest events of the engine, e.g. route, DNS and firewall changes.
 config.txt: Anonymized configuration information of the NetBird client.
 network_map.json: Anonymized sync response containing peer configurations, routes, DNS settings, and firewall rules.
 state.json: Anonymized client state dump containing netbird states for the active profile.
@@ -224,6 +226,7 @@ type BundleGenerator struct {
 	statusRecorder *peer.Status
 	syncResponse   *mgmProto.SyncResponse
 	logFile        string
+	eventHistory   *eventbus.History
 
 	anonymize         bool
 	clientStatus      string
@@ -245,6 +248,7 @@ type GeneratorDependencies struct {
 	StatusRecorder *peer.Status
 	SyncResponse   *mgmProto.SyncResponse
 	LogFile        string
+	EventHistory   *eventbus.History
 }
 
 func NewBundleGenerator(deps GeneratorDependencies, cfg BundleConfig) *BundleGenerator {
@@ -261,6 +265,7 @@ func NewBundleGenerator(deps GeneratorDependencies, cfg BundleConfig) *BundleGen
 		statusRecorder: deps.StatusRecorder,
 		syncResponse:   deps.SyncResponse,
 		logFile:        deps.LogFile,
+		eventHistory:   deps.EventHistory,
 
 		anonymize:         cfg.Anonymize,
 		clientStatus:      cfg.ClientStatus,
@@ -324,6 +329,10 @@ func (g *BundleGenerator) createArchive() error {
 		log.Errorf("failed to add resolved domains to debug bundle: %v", err)
 	}
 
+	if err := g.addEngineEvents(); err != nil {
+		log.Errorf("failed to add engine events to debug bundle: %v", err)
+	}
+
 	if g.includeSystemInfo {
 		g.addSystemInfo()
 	}
@@ -485,6 +494,10 @@ func (g *BundleGenerator) addCommonConfigFields(configContent *strings.Builder)
 	configContent.WriteString(fmt.Sprintf("DisableFirewall: %v\n", g.internalConfig.DisableFirewall))
 	configContent.WriteString(fmt.Sprintf("BlockLANAccess: %v\n", g.internalConfig.BlockLANAccess))
 	configContent.WriteString(fmt.Sprintf("BlockInbound: %v\n", g.internalConfig.BlockInbound))
+	configContent.WriteString(fmt.Sprintf("KillSwitch: %v\n", g.internalConfig.KillSwitch))
+	configContent.WriteString(fmt.Sprintf("FirewallBackend: %s\n", g.internalConfig.FirewallBackend))
+	configContent.WriteString(fmt.Sprintf("SOCKS5ProxyAddress: %s\n", g.internalConfig.SOCKS5ProxyAddress))
+	configContent.WriteString(fmt.Sprintf("HTTPProxyAddress: %s\n", g.internalConfig.HTTPProxyAddress))
 
 	if g.internalConfig.DisableNotifications != nil {
 		configContent.WriteString(fmt.Sprintf("DisableNotifications: %v\n", *g.internalConfig.DisableNotifications))
@@ -504,6 +517,8 @@ func (g *BundleGenerator) addCommonConfigFields(configContent *strings.Builder)
 	}
 
 	configContent.WriteString(fmt.Sprintf("LazyConnectionEnabled: %v\n", g.internalConfig.LazyConnectionEnabled))
+	configContent.WriteString(fmt.Sprintf("ICEMulticastDNS: %v\n", g.internalConfig.ICEMulticastDNS))
+	configContent.WriteString(fmt.Sprintf("ICEPolicy: %s\n", g.internalConfig.ICEPolicy))
 }
 
 func (g *BundleGenerator) addProf() (err error) {
@@ -583,6 +598,21 @@ func (g *BundleGenerator) addResolvedDomains() error {
 	return nil
 }
 
+func (g *BundleGenerator) addEngineEvents() error {
+	records := g.eventHistory.Records()
+	if len(records) == 0 {
+		log.Debugf("skipping engine events in debug bundle: no events")
+		return nil
+	}
+
+	eventsContent := formatEngineEvents(records, g.anonymize, g.anonymizer)
+	if err := g.addFileToZip(strings.NewReader(eventsContent), "engine_events.txt"); err != nil {
+		return fmt.Errorf("add engine events file to zip: %w", err)
+	}
+
+	return nil
+}
+
 func (g *BundleGenerator) addSyncResponse() error {
 	if g.syncResponse == nil {
 		log.Debugf("skipping empty sync response in debug bundle")
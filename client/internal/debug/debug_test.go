@@ -5,11 +5,13 @@ import (
 	"net"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/netbirdio/netbird/client/anonymize"
+	"github.com/netbirdio/netbird/client/internal/eventbus"
 	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
 )
 
@@ -541,3 +543,18 @@ Chain OUTPUT (policy ACCEPT 0 packets, 0 bytes)
 	assert.Contains(t, anonNftables, "chain input {")
 	assert.Contains(t, anonNftables, "type filter hook input priority filter; policy accept;")
 }
+
+func TestFormatEngineEvents(t *testing.T) {
+	records := []eventbus.Record{
+		{Time: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Event: eventbus.RouteChanged{Network: "10.0.0.0/24", Peer: "peer1"}},
+		{Time: time.Date(2025, 1, 2, 3, 4, 6, 0, time.UTC), Event: eventbus.NetworkMapUpdated{Serial: 7}},
+	}
+
+	content := formatEngineEvents(records, false, nil)
+	assert.Equal(t, "2025-01-02T03:04:05Z [route] eventbus.RouteChanged {Network:10.0.0.0/24 ResourceID: Peer:peer1 Removed:false}\n"+
+		"2025-01-02T03:04:06Z [network-map] eventbus.NetworkMapUpdated {Serial:7}\n", content)
+
+	records = []eventbus.Record{{Event: eventbus.RouteChanged{Network: "203.0.113.0/24", Peer: "peer1"}}}
+	anonymized := formatEngineEvents(records, true, anonymize.NewAnonymizer(anonymize.DefaultAddresses()))
+	assert.NotContains(t, anonymized, "203.0.113.0", "public addresses are anonymized")
+}
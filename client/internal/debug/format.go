@@ -6,8 +6,10 @@ import (
 	"net/netip"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/netbirdio/netbird/client/anonymize"
+	"github.com/netbirdio/netbird/client/internal/eventbus"
 	"github.com/netbirdio/netbird/client/internal/peer"
 	"github.com/netbirdio/netbird/client/internal/routemanager/systemops"
 	"github.com/netbirdio/netbird/shared/management/domain"
@@ -204,3 +206,17 @@ func formatTable(title string, headers []string, rows [][]string) string {
 
 	return builder.String()
 }
+
+// formatEngineEvents lists the engine events oldest first, one event per line
+func formatEngineEvents(records []eventbus.Record, anonymize bool, anonymizer *anonymize.Anonymizer) string {
+	var builder strings.Builder
+	for _, record := range records {
+		line := fmt.Sprintf("%s [%s] %T %+v", record.Time.UTC().Format(time.RFC3339Nano), record.Event.Topic(), record.Event, record.Event)
+		if anonymize {
+			line = anonymizer.AnonymizeString(line)
+		}
+		builder.WriteString(line)
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
@@ -0,0 +1,278 @@
+// Package diagsrv serves diagnostic endpoints on the NetBird address of the peer. Remote peers use them to verify
+// end-to-end connectivity: a TCP and UDP echo port and an HTTP /whoami endpoint that reports who the request came
+// from and over which path. The endpoints are only reachable if the access control policies allow their ports.
+package diagsrv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	log "github.com/sirupsen/logrus"
+	"golang.zx2c4.com/wireguard/tun/netstack"
+
+	nberrors "github.com/netbirdio/netbird/client/errors"
+	"github.com/netbirdio/netbird/client/iface/wgaddr"
+	"github.com/netbirdio/netbird/client/internal/peer"
+)
+
+const (
+	// EchoPort is the TCP and UDP port that returns everything it receives
+	EchoPort = 22007
+	// HTTPPort is the TCP port of the HTTP endpoints
+	HTTPPort = 22080
+
+	// maxEchoConns limits the concurrent TCP echo connections
+	maxEchoConns = 16
+	// echoIdleTimeout closes TCP echo connections that didn't send anything for this long
+	echoIdleTimeout = 30 * time.Second
+	// maxDatagramSize is the largest UDP datagram that is echoed
+	maxDatagramSize = 65535
+)
+
+type wgIface interface {
+	GetNet() *netstack.Net
+	Address() wgaddr.Address
+}
+
+// Server serves the diagnostic endpoints
+type Server struct {
+	wgIface        wgIface
+	statusRecorder *peer.Status
+	echoPort       uint16
+	httpPort       uint16
+
+	mu           sync.Mutex
+	echoListener net.Listener
+	echoConn     net.PacketConn
+	httpServer   *http.Server
+	wg           sync.WaitGroup
+
+	connsMu   sync.Mutex
+	echoConns map[net.Conn]struct{}
+}
+
+// New returns a server for the NetBird address of the interface
+func New(wgIface wgIface, statusRecorder *peer.Status) *Server {
+	return &Server{
+		wgIface:        wgIface,
+		statusRecorder: statusRecorder,
+		echoPort:       EchoPort,
+		httpPort:       HTTPPort,
+		echoConns:      make(map[net.Conn]struct{}),
+	}
+}
+
+// Start listens on the echo and HTTP ports of the NetBird address
+func (s *Server) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.httpServer != nil {
+		return nil
+	}
+
+	ip := s.wgIface.Address().IP
+	netstackNet := s.wgIface.GetNet()
+
+	echoListener, err := listenTCP(netstackNet, netip.AddrPortFrom(ip, s.echoPort))
+	if err != nil {
+		return fmt.Errorf("listen TCP echo: %w", err)
+	}
+
+	echoConn, err := listenUDP(netstackNet, netip.AddrPortFrom(ip, s.echoPort))
+	if err != nil {
+		closeWithLog(echoListener, "TCP echo listener")
+		return fmt.Errorf("listen UDP echo: %w", err)
+	}
+
+	httpListener, err := listenTCP(netstackNet, netip.AddrPortFrom(ip, s.httpPort))
+	if err != nil {
+		closeWithLog(echoListener, "TCP echo listener")
+		closeWithLog(echoConn, "UDP echo socket")
+		return fmt.Errorf("listen HTTP: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/whoami", s.handleWhoAmI)
+	mux.HandleFunc("/whoami/", s.handleWhoAmI)
+
+	httpServer := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       time.Minute,
+	}
+	s.echoListener = echoListener
+	s.echoConn = echoConn
+	s.httpServer = httpServer
+
+	s.wg.Add(3)
+	go s.serveTCPEcho(echoListener)
+	go s.serveUDPEcho(echoConn)
+	go func() {
+		defer s.wg.Done()
+		if err := httpServer.Serve(httpListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("diagnostic HTTP endpoint stopped: %v", err)
+		}
+	}()
+
+	log.Infof("diagnostic endpoints listening on %s: echo TCP/UDP %d, HTTP %d", ip, s.echoPort, s.httpPort)
+	return nil
+}
+
+// Stop closes the listeners and waits for the open connections to finish
+func (s *Server) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.httpServer == nil {
+		return nil
+	}
+
+	var merr *multierror.Error
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		merr = multierror.Append(merr, fmt.Errorf("shutdown HTTP endpoint: %w", err))
+	}
+	if err := s.echoListener.Close(); err != nil {
+		merr = multierror.Append(merr, fmt.Errorf("close TCP echo listener: %w", err))
+	}
+	if err := s.echoConn.Close(); err != nil {
+		merr = multierror.Append(merr, fmt.Errorf("close UDP echo socket: %w", err))
+	}
+
+	s.connsMu.Lock()
+	for conn := range s.echoConns {
+		closeWithLog(conn, "TCP echo connection")
+	}
+	s.connsMu.Unlock()
+
+	s.wg.Wait()
+
+	s.httpServer = nil
+	s.echoListener = nil
+	s.echoConn = nil
+
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+func (s *Server) serveTCPEcho(listener net.Listener) {
+	defer s.wg.Done()
+
+	var conns sync.WaitGroup
+	defer conns.Wait()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Errorf("diagnostic TCP echo stopped: %v", err)
+			}
+			return
+		}
+
+		if !s.trackEchoConn(conn) {
+			log.Debugf("rejecting TCP echo connection from %s, too many open connections", conn.RemoteAddr())
+			closeWithLog(conn, "TCP echo connection")
+			continue
+		}
+
+		conns.Add(1)
+		go func() {
+			defer conns.Done()
+			defer s.untrackEchoConn(conn)
+			echo(conn)
+		}()
+	}
+}
+
+func (s *Server) trackEchoConn(conn net.Conn) bool {
+	s.connsMu.Lock()
+	defer s.connsMu.Unlock()
+
+	if len(s.echoConns) >= maxEchoConns {
+		return false
+	}
+	s.echoConns[conn] = struct{}{}
+	return true
+}
+
+func (s *Server) untrackEchoConn(conn net.Conn) {
+	s.connsMu.Lock()
+	defer s.connsMu.Unlock()
+
+	delete(s.echoConns, conn)
+}
+
+// echo returns everything it reads from the connection until it is closed or idle
+func echo(conn net.Conn) {
+	defer closeWithLog(conn, "TCP echo connection")
+
+	buf := make([]byte, 32*1024)
+	for {
+		if err := conn.SetDeadline(time.Now().Add(echoIdleTimeout)); err != nil {
+			return
+		}
+
+		n, err := conn.Read(buf)
+		if n > 0 {
+			if _, err := conn.Write(buf[:n]); err != nil {
+				return
+			}
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				log.Tracef("TCP echo connection from %s: %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+	}
+}
+
+func (s *Server) serveUDPEcho(conn net.PacketConn) {
+	defer s.wg.Done()
+
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Errorf("diagnostic UDP echo stopped: %v", err)
+			}
+			return
+		}
+
+		if _, err := conn.WriteTo(buf[:n], addr); err != nil {
+			log.Tracef("UDP echo to %s: %v", addr, err)
+		}
+	}
+}
+
+func listenTCP(netstackNet *netstack.Net, addr netip.AddrPort) (net.Listener, error) {
+	if netstackNet != nil {
+		return netstackNet.ListenTCPAddrPort(addr)
+	}
+	return net.ListenTCP("tcp", net.TCPAddrFromAddrPort(addr))
+}
+
+func listenUDP(netstackNet *netstack.Net, addr netip.AddrPort) (net.PacketConn, error) {
+	if netstackNet != nil {
+		return netstackNet.ListenUDPAddrPort(addr)
+	}
+	return net.ListenUDP("udp", net.UDPAddrFromAddrPort(addr))
+}
+
+func closeWithLog(c io.Closer, name string) {
+	if err := c.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+		log.Debugf("close %s: %v", name, err)
+	}
+}
@@ -0,0 +1,117 @@
+package diagsrv
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.zx2c4.com/wireguard/tun/netstack"
+
+	"github.com/netbirdio/netbird/client/iface/wgaddr"
+	"github.com/netbirdio/netbird/client/internal/peer"
+)
+
+type mockIface struct {
+	addr wgaddr.Address
+}
+
+func (m *mockIface) GetNet() *netstack.Net   { return nil }
+func (m *mockIface) Address() wgaddr.Address { return m.addr }
+
+func newTestServer(t *testing.T, recorder *peer.Status) *Server {
+	t.Helper()
+
+	addr, err := wgaddr.ParseWGAddress("127.0.0.1/8")
+	require.NoError(t, err)
+
+	server := New(&mockIface{addr: addr}, recorder)
+	server.echoPort = 0
+	server.httpPort = 0
+	require.NoError(t, server.Start())
+	t.Cleanup(func() {
+		assert.NoError(t, server.Stop())
+	})
+	return server
+}
+
+func TestServer_Echo(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	tcpConn, err := net.DialTimeout("tcp", server.echoListener.Addr().String(), time.Second)
+	require.NoError(t, err)
+	defer tcpConn.Close()
+	require.NoError(t, tcpConn.SetDeadline(time.Now().Add(5*time.Second)))
+
+	_, err = tcpConn.Write([]byte("ping"))
+	require.NoError(t, err)
+	buf := make([]byte, 4)
+	_, err = tcpConn.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+
+	udpConn, err := net.Dial("udp", server.echoConn.LocalAddr().String())
+	require.NoError(t, err)
+	defer udpConn.Close()
+	require.NoError(t, udpConn.SetDeadline(time.Now().Add(5*time.Second)))
+
+	_, err = udpConn.Write([]byte("pong"))
+	require.NoError(t, err)
+	n, err := udpConn.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "pong", string(buf[:n]))
+}
+
+func TestServer_WhoAmI(t *testing.T) {
+	recorder := peer.NewRecorder("https://mgm")
+	recorder.UpdateLocalPeerState(peer.LocalPeerState{IP: "100.64.0.1/16", FQDN: "local.netbird.cloud"})
+	require.NoError(t, recorder.AddPeer("remote-key", "remote.netbird.cloud", "100.64.0.2"))
+	require.NoError(t, recorder.UpdatePeerRelayedState(peer.State{
+		PubKey:             "remote-key",
+		ConnStatus:         peer.StatusConnected,
+		Relayed:            true,
+		RelayServerAddress: "rels://relay.netbird.io:443",
+	}))
+
+	server := New(&mockIface{}, recorder)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		expectPeer bool
+	}{
+		{name: "peer", remoteAddr: "100.64.0.2:50000", expectPeer: true},
+		{name: "routed network", remoteAddr: "192.168.1.10:50000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "http://local.netbird.cloud/whoami/some/path", nil)
+			req.RemoteAddr = tt.remoteAddr
+			rec := httptest.NewRecorder()
+
+			server.handleWhoAmI(rec, req)
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			var resp WhoAmI
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, tt.remoteAddr, resp.RemoteAddr)
+			assert.Equal(t, "/whoami/some/path", resp.Path)
+			assert.Equal(t, "local.netbird.cloud", resp.LocalFQDN)
+
+			if !tt.expectPeer {
+				assert.Nil(t, resp.Peer)
+				return
+			}
+			require.NotNil(t, resp.Peer)
+			assert.Equal(t, "remote.netbird.cloud", resp.Peer.FQDN)
+			assert.Equal(t, "remote-key", resp.Peer.PubKey)
+			assert.True(t, resp.Peer.Relayed)
+			assert.Equal(t, "rels://relay.netbird.io:443", resp.Peer.RelayServer)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package diagsrv
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/netip"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// WhoAmI is the answer of the /whoami endpoint
+type WhoAmI struct {
+	// RemoteAddr is the source address and port of the request as seen by this peer
+	RemoteAddr string `json:"remoteAddr"`
+	// Peer is the remote peer the request came from, nil if the source isn't a peer address, e.g. a routed network
+	Peer *PeerInfo `json:"peer,omitempty"`
+	// LocalAddr is the address the request was sent to
+	LocalAddr string `json:"localAddr"`
+	// LocalFQDN is the FQDN of this peer
+	LocalFQDN string `json:"localFqdn,omitempty"`
+	Method    string `json:"method"`
+	Host      string `json:"host"`
+	Path      string `json:"path"`
+}
+
+// PeerInfo identifies the remote peer and the path the connection to it takes
+type PeerInfo struct {
+	FQDN       string `json:"fqdn"`
+	PubKey     string `json:"pubKey"`
+	ConnStatus string `json:"connStatus"`
+	Relayed    bool   `json:"relayed"`
+	// RelayServer is the relay the connection goes through if it is relayed
+	RelayServer string `json:"relayServer,omitempty"`
+	// LocalCandidate and RemoteCandidate are the ICE candidates of a direct connection, e.g. "host 192.168.1.2:51820"
+	LocalCandidate  string `json:"localCandidate,omitempty"`
+	RemoteCandidate string `json:"remoteCandidate,omitempty"`
+	Latency         string `json:"latency,omitempty"`
+}
+
+func (s *Server) handleWhoAmI(w http.ResponseWriter, r *http.Request) {
+	resp := WhoAmI{
+		RemoteAddr: r.RemoteAddr,
+		Method:     r.Method,
+		Host:       r.Host,
+		Path:       r.URL.Path,
+	}
+
+	if localAddr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+		resp.LocalAddr = localAddr.String()
+	}
+
+	if s.statusRecorder != nil {
+		resp.LocalFQDN = s.statusRecorder.GetLocalPeerState().FQDN
+		resp.Peer = s.peerInfo(r.RemoteAddr)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Debugf("write whoami response to %s: %v", r.RemoteAddr, err)
+	}
+}
+
+func (s *Server) peerInfo(remoteAddr string) *PeerInfo {
+	addrPort, err := netip.ParseAddrPort(remoteAddr)
+	if err != nil {
+		return nil
+	}
+
+	state, ok := s.statusRecorder.PeerStateByIP(addrPort.Addr().Unmap().String())
+	if !ok {
+		return nil
+	}
+
+	info := &PeerInfo{
+		FQDN:       state.FQDN,
+		PubKey:     state.PubKey,
+		ConnStatus: state.ConnStatus.String(),
+		Relayed:    state.Relayed,
+	}
+	if state.Relayed {
+		info.RelayServer = state.RelayServerAddress
+	} else {
+		info.LocalCandidate = candidate(state.LocalIceCandidateType, state.LocalIceCandidateEndpoint)
+		info.RemoteCandidate = candidate(state.RemoteIceCandidateType, state.RemoteIceCandidateEndpoint)
+	}
+	if state.Latency > 0 {
+		info.Latency = state.Latency.String()
+	}
+	return info
+}
+
+func candidate(candidateType, endpoint string) string {
+	switch {
+	case candidateType == "":
+		return endpoint
+	case endpoint == "":
+		return candidateType
+	default:
+		return candidateType + " " + endpoint
+	}
+}
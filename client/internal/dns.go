@@ -10,8 +10,11 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	nbdns "github.com/netbirdio/netbird/dns"
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
 )
 
+const offlinePeerRecordTTL = 300
+
 func createPTRRecord(aRecord nbdns.SimpleRecord, prefix netip.Prefix) (nbdns.SimpleRecord, bool) {
 	ip, err := netip.ParseAddr(aRecord.RData)
 	if err != nil {
@@ -117,3 +120,63 @@ func addReverseZone(config *nbdns.Config, network netip.Prefix) {
 	config.CustomZones = append(config.CustomZones, reverseZone)
 	log.Debugf("added reverse DNS zone: %s with %d records", zoneName, len(records))
 }
+
+// addOfflinePeerRecords adds A records for offline peers that are missing from the peer zones,
+// so their names keep resolving until they come online
+func addOfflinePeerRecords(config *nbdns.Config, offlinePeers []*mgmProto.RemotePeerConfig) {
+	for _, offlinePeer := range offlinePeers {
+		fqdn := offlinePeer.GetFqdn()
+		if fqdn == "" {
+			continue
+		}
+
+		allowedIPs := offlinePeer.GetAllowedIps()
+		if len(allowedIPs) == 0 {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(allowedIPs[0])
+		if err != nil || !prefix.Addr().Is4() {
+			continue
+		}
+
+		zone := findPeerZone(config, fqdn)
+		if zone == nil || hasRecord(zone, fqdn) {
+			continue
+		}
+
+		zone.Records = append(zone.Records, nbdns.SimpleRecord{
+			Name:  fqdn,
+			Type:  int(dns.TypeA),
+			Class: nbdns.DefaultClass,
+			TTL:   offlinePeerRecordTTL,
+			RData: prefix.Addr().String(),
+		})
+		log.Debugf("added DNS record for offline peer %s", fqdn)
+	}
+}
+
+// findPeerZone returns the authoritative zone the given fqdn belongs to
+func findPeerZone(config *nbdns.Config, fqdn string) *nbdns.CustomZone {
+	name := dns.Fqdn(strings.ToLower(fqdn))
+	for i := range config.CustomZones {
+		zone := &config.CustomZones[i]
+		if zone.NonAuthoritative {
+			continue
+		}
+		if dns.IsSubDomain(dns.Fqdn(strings.ToLower(zone.Domain)), name) {
+			return zone
+		}
+	}
+	return nil
+}
+
+// hasRecord checks if the zone already holds a record for the given fqdn
+func hasRecord(zone *nbdns.CustomZone, fqdn string) bool {
+	name := dns.Fqdn(fqdn)
+	for _, record := range zone.Records {
+		if strings.EqualFold(dns.Fqdn(record.Name), name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+package dns
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	// maxCacheEntries limits the memory the cache takes, expired entries are evicted first when it is full
+	maxCacheEntries = 10000
+	// maxCacheTTL caps the TTL of cached answers, so record changes show up eventually even with long TTLs
+	maxCacheTTL = time.Hour
+	// maxNegativeCacheTTL caps the TTL of cached NXDOMAIN and NODATA answers, RFC 2308 recommends up to 3 hours
+	maxNegativeCacheTTL = 5 * time.Minute
+)
+
+// CacheStats are the counters of the upstream answer cache
+type CacheStats struct {
+	Entries int
+	Hits    uint64
+	Misses  uint64
+}
+
+type cacheKey struct {
+	name  string
+	qtype uint16
+	class uint16
+	// answers with and without DNSSEC records differ
+	dnssec bool
+}
+
+type cacheEntry struct {
+	msg     *dns.Msg
+	stored  time.Time
+	expires time.Time
+}
+
+// responseCache keeps the upstream answers for the TTL of their records
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[cacheKey]*cacheEntry
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{
+		entries: make(map[cacheKey]*cacheEntry),
+	}
+}
+
+func newCacheKey(r *dns.Msg) cacheKey {
+	q := r.Question[0]
+	key := cacheKey{
+		name:  strings.ToLower(q.Name),
+		qtype: q.Qtype,
+		class: q.Qclass,
+	}
+	if opt := r.IsEdns0(); opt != nil {
+		key.dnssec = opt.Do()
+	}
+	return key
+}
+
+// get returns a cached answer for the request with the TTLs reduced by the time it was cached, nil if there is none
+func (c *responseCache) get(r *dns.Msg) *dns.Msg {
+	if len(r.Question) == 0 {
+		return nil
+	}
+
+	key := newCacheKey(r)
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && !now.Before(entry.expires) {
+		delete(c.entries, key)
+		ok = false
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		c.misses.Add(1)
+		return nil
+	}
+	c.hits.Add(1)
+
+	resp := entry.msg.Copy()
+	resp.Id = r.Id
+	resp.Question = r.Question
+	elapsed := uint32(now.Sub(entry.stored) / time.Second)
+	for _, rrs := range [][]dns.RR{resp.Answer, resp.Ns, resp.Extra} {
+		for _, rr := range rrs {
+			if rr.Header().Rrtype == dns.TypeOPT {
+				continue
+			}
+			if rr.Header().Ttl > elapsed {
+				rr.Header().Ttl -= elapsed
+			} else {
+				rr.Header().Ttl = 0
+			}
+		}
+	}
+	return resp
+}
+
+// set caches the upstream answer to the request for the TTL of its records
+func (c *responseCache) set(r *dns.Msg, resp *dns.Msg) {
+	if len(r.Question) == 0 {
+		return
+	}
+
+	ttl := cacheTTL(resp)
+	if ttl <= 0 {
+		return
+	}
+
+	now := time.Now()
+	entry := &cacheEntry{
+		msg:     resp.Copy(),
+		stored:  now,
+		expires: now.Add(ttl),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= maxCacheEntries {
+		c.evict(now)
+	}
+	c.entries[newCacheKey(r)] = entry
+}
+
+// evict removes the expired entries, or an arbitrary one if none expired. Callers must hold the lock.
+func (c *responseCache) evict(now time.Time) {
+	for key, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+	if len(c.entries) < maxCacheEntries {
+		return
+	}
+	for key := range c.entries {
+		delete(c.entries, key)
+		return
+	}
+}
+
+// flush removes all cached answers and returns how many were removed
+func (c *responseCache) flush() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := len(c.entries)
+	clear(c.entries)
+	return n
+}
+
+func (c *responseCache) stats() CacheStats {
+	c.mu.Lock()
+	entries := len(c.entries)
+	c.mu.Unlock()
+
+	return CacheStats{
+		Entries: entries,
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+	}
+}
+
+// cacheTTL returns how long the answer can be cached: the lowest TTL of the answer records, or for NXDOMAIN and
+// NODATA answers the negative TTL of the SOA record as described in RFC 2308. Zero means the answer isn't cached.
+func cacheTTL(resp *dns.Msg) time.Duration {
+	if resp.Truncated {
+		return 0
+	}
+
+	switch {
+	case resp.Rcode == dns.RcodeSuccess && len(resp.Answer) > 0:
+		return min(minTTL(resp.Answer), maxCacheTTL)
+	case resp.Rcode == dns.RcodeSuccess || resp.Rcode == dns.RcodeNameError:
+		for _, rr := range resp.Ns {
+			if soa, ok := rr.(*dns.SOA); ok {
+				ttl := time.Duration(min(soa.Hdr.Ttl, soa.Minttl)) * time.Second
+				return min(ttl, maxNegativeCacheTTL)
+			}
+		}
+		return 0
+	default:
+		return 0
+	}
+}
+
+func minTTL(rrs []dns.RR) time.Duration {
+	lowest := uint32(0)
+	for i, rr := range rrs {
+		if i == 0 || rr.Header().Ttl < lowest {
+			lowest = rr.Header().Ttl
+		}
+	}
+	return time.Duration(lowest) * time.Second
+}
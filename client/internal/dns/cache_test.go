@@ -0,0 +1,112 @@
+package dns
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCacheTestAnswer(r *dns.Msg, ttl uint32) *dns.Msg {
+	resp := new(dns.Msg)
+	resp.SetReply(r)
+	resp.Answer = []dns.RR{
+		&dns.A{
+			Hdr: dns.RR_Header{Name: r.Question[0].Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: ttl},
+			A:   net.ParseIP("192.0.2.1"),
+		},
+	}
+	return resp
+}
+
+func TestResponseCache_TTL(t *testing.T) {
+	cache := newResponseCache()
+
+	r := new(dns.Msg)
+	r.SetQuestion("Example.com.", dns.TypeA)
+	cache.set(r, newCacheTestAnswer(r, 60))
+
+	entry := cache.entries[newCacheKey(r)]
+	require.NotNil(t, entry)
+	entry.stored = entry.stored.Add(-10 * time.Second)
+
+	lookup := new(dns.Msg)
+	lookup.SetQuestion("example.com.", dns.TypeA)
+	resp := cache.get(lookup)
+	require.NotNil(t, resp, "names are matched case-insensitively")
+	assert.Equal(t, lookup.Id, resp.Id)
+	assert.Equal(t, uint32(50), resp.Answer[0].Header().Ttl, "the TTL is reduced by the time the answer was cached")
+
+	entry.expires = time.Now()
+	assert.Nil(t, cache.get(lookup), "expired answers aren't returned")
+
+	stats := cache.stats()
+	assert.Equal(t, CacheStats{Entries: 0, Hits: 1, Misses: 1}, stats)
+}
+
+func TestResponseCache_Negative(t *testing.T) {
+	cache := newResponseCache()
+
+	r := new(dns.Msg)
+	r.SetQuestion("missing.example.com.", dns.TypeA)
+	resp := new(dns.Msg)
+	resp.SetRcode(r, dns.RcodeNameError)
+	resp.Ns = []dns.RR{
+		&dns.SOA{
+			Hdr:    dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 3600},
+			Ns:     "ns.example.com.",
+			Mbox:   "admin.example.com.",
+			Minttl: 30,
+		},
+	}
+
+	assert.Equal(t, 30*time.Second, cacheTTL(resp), "the lower of the SOA TTL and minimum is used")
+	cache.set(r, resp)
+	cached := cache.get(r)
+	require.NotNil(t, cached)
+	assert.Equal(t, dns.RcodeNameError, cached.Rcode)
+}
+
+func TestResponseCache_NotCached(t *testing.T) {
+	r := new(dns.Msg)
+	r.SetQuestion("example.com.", dns.TypeA)
+
+	servfail := new(dns.Msg)
+	servfail.SetRcode(r, dns.RcodeServerFailure)
+
+	truncated := newCacheTestAnswer(r, 60)
+	truncated.Truncated = true
+
+	nodataWithoutSOA := new(dns.Msg)
+	nodataWithoutSOA.SetReply(r)
+
+	for name, resp := range map[string]*dns.Msg{
+		"servfail":           servfail,
+		"truncated":          truncated,
+		"zero ttl":           newCacheTestAnswer(r, 0),
+		"nodata without soa": nodataWithoutSOA,
+	} {
+		t.Run(name, func(t *testing.T) {
+			cache := newResponseCache()
+			cache.set(r, resp)
+			assert.Nil(t, cache.get(r))
+		})
+	}
+}
+
+func TestResponseCache_Flush(t *testing.T) {
+	cache := newResponseCache()
+
+	for _, name := range []string{"a.example.com.", "b.example.com."} {
+		r := new(dns.Msg)
+		r.SetQuestion(name, dns.TypeA)
+		cache.set(r, newCacheTestAnswer(r, 60))
+	}
+
+	assert.Equal(t, 2, cache.stats().Entries)
+	assert.Equal(t, 2, cache.flush())
+	assert.Equal(t, 0, cache.stats().Entries)
+}
@@ -0,0 +1,125 @@
+package dns
+
+import (
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+const (
+	serviceRetryInitialInterval = time.Second
+	serviceRetryMaxInterval     = time.Minute
+)
+
+// serviceWithFailureHandler is implemented by services that can stop on their own after they started listening
+type serviceWithFailureHandler interface {
+	service
+	setFailureHandler(func(error))
+}
+
+// onServiceFailure is called by the service when it stopped without being asked to
+func (s *DefaultServer) onServiceFailure(err error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.activateFallback(err)
+}
+
+// activateFallback hands DNS back to the resolvers the host used before NetBird took over, so the host isn't left
+// without working DNS while the NetBird DNS service is down. The service is retried in the background and the
+// NetBird DNS configuration is applied again once it is back. Callers must hold s.mux.
+func (s *DefaultServer) activateFallback(err error) {
+	if s.ctx.Err() != nil || s.fallbackActive {
+		return
+	}
+	s.fallbackActive = true
+
+	log.Warnf("NetBird DNS service is unavailable, restoring the previous system resolvers: %v", err)
+
+	if !s.isUsingNoopHostManager() {
+		if err := s.hostManager.restoreHostDNS(); err != nil {
+			log.Errorf("failed to restore host DNS settings: %v", err)
+		}
+		// the host config has to be applied again once the service is back
+		s.currentConfigHash = ^uint64(0)
+	}
+
+	if s.statusRecorder != nil {
+		s.statusRecorder.PublishEvent(
+			proto.SystemEvent_WARNING,
+			proto.SystemEvent_DNS,
+			"DNS service unavailable, using the previous system resolvers",
+			"NetBird DNS is unavailable. The previous system DNS servers are used until it recovers, NetBird domains won't resolve meanwhile.",
+			map[string]string{"error": err.Error()},
+		)
+	}
+
+	s.shutdownWg.Add(1)
+	go func() {
+		defer s.shutdownWg.Done()
+		s.retryService()
+	}()
+}
+
+// retryService restarts the DNS service with an exponential backoff until it runs again, the fallback is
+// deactivated or the server stops
+func (s *DefaultServer) retryService() {
+	bo := backoff.WithContext(&backoff.ExponentialBackOff{
+		InitialInterval:     serviceRetryInitialInterval,
+		RandomizationFactor: 0.5,
+		Multiplier:          2,
+		MaxInterval:         serviceRetryMaxInterval,
+		MaxElapsedTime:      0,
+		Stop:                backoff.Stop,
+		Clock:               backoff.SystemClock,
+	}, s.ctx)
+
+	operation := func() error {
+		s.mux.Lock()
+		defer s.mux.Unlock()
+
+		if !s.fallbackActive || s.ctx.Err() != nil {
+			return nil
+		}
+
+		if err := s.enableDNS(); err != nil {
+			log.Debugf("NetBird DNS service is still unavailable: %v", err)
+			return err
+		}
+
+		s.deactivateFallback()
+		return nil
+	}
+
+	if err := backoff.Retry(operation, bo); err != nil {
+		log.Debugf("stopped retrying the DNS service: %v", err)
+	}
+}
+
+// deactivateFallback applies the NetBird DNS configuration to the host again. Callers must hold s.mux.
+func (s *DefaultServer) deactivateFallback() {
+	s.fallbackActive = false
+
+	log.Infof("NetBird DNS service is running again, applying the DNS configuration")
+
+	// the service might listen on another address than before it failed
+	s.currentConfig.ServerIP = s.service.RuntimeIP()
+	s.currentConfig.ServerPort = s.service.RuntimePort()
+	if s.currentConfig.ServerPort != DefaultPort && !s.hostManager.supportCustomPort() {
+		s.currentConfig.RouteAll = false
+	}
+	s.applyHostConfig()
+
+	if s.statusRecorder != nil {
+		s.statusRecorder.PublishEvent(
+			proto.SystemEvent_INFO,
+			proto.SystemEvent_DNS,
+			"DNS service recovered",
+			"NetBird DNS is available again.",
+			nil,
+		)
+	}
+}
@@ -0,0 +1,80 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/internal/dns/local"
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/statemanager"
+)
+
+type failingService struct {
+	mockService
+	failures int32
+	attempts atomic.Int32
+}
+
+func (f *failingService) Listen() error {
+	if f.attempts.Add(1) <= f.failures {
+		return errors.New("address already in use")
+	}
+	return nil
+}
+
+func TestDefaultServer_Fallback(t *testing.T) {
+	var restored, applied atomic.Int32
+	hostManager := &mockHostConfigurator{
+		applyDNSConfigFunc: func(HostDNSConfig, *statemanager.Manager) error {
+			applied.Add(1)
+			return nil
+		},
+		restoreHostDNSFunc: func() error {
+			restored.Add(1)
+			return nil
+		},
+		supportCustomPortFunc: func() bool { return true },
+	}
+
+	svc := &failingService{failures: 1}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	server := &DefaultServer{
+		ctx:               ctx,
+		ctxCancel:         cancel,
+		handlerChain:      NewHandlerChain(),
+		hostManager:       hostManager,
+		localResolver:     local.NewResolver(),
+		service:           svc,
+		statusRecorder:    peer.NewRecorder("test"),
+		currentConfigHash: 1,
+	}
+	defer func() {
+		cancel()
+		server.shutdownWg.Wait()
+	}()
+
+	server.onServiceFailure(errors.New("listener crashed"))
+	assert.Equal(t, int32(1), restored.Load(), "the previous system resolvers are restored")
+
+	server.mux.Lock()
+	assert.True(t, server.fallbackActive)
+	server.applyHostConfig()
+	server.mux.Unlock()
+	assert.Equal(t, int32(0), applied.Load(), "the host config isn't applied while the service is down")
+
+	require.Eventually(t, func() bool {
+		server.mux.Lock()
+		defer server.mux.Unlock()
+		return !server.fallbackActive
+	}, 10*time.Second, 50*time.Millisecond, "the service is retried until it runs again")
+
+	assert.Equal(t, int32(1), applied.Load(), "the host config is applied again once the service is back")
+	assert.Equal(t, int32(2), svc.attempts.Load(), "the service was retried after the failed attempt")
+}
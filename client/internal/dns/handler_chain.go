@@ -2,25 +2,32 @@ package dns
 
 import (
 	"fmt"
+	"net"
+	"net/netip"
 	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/miekg/dns"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netbirdio/netbird/client/internal/dns/resutil"
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
 )
 
 const (
 	PriorityMgmtCache = 150
 	PriorityLocal     = 100
-	PriorityDNSRoute  = 75
-	PriorityUpstream  = 50
-	PriorityDefault   = 1
-	PriorityFallback  = -100
+	// PriorityDomainRule is the priority of the locally configured domain routes, they observe the answers of the
+	// handlers after them
+	PriorityDomainRule = 90
+	PriorityDNSRoute   = 75
+	PriorityUpstream   = 50
+	PriorityDefault    = 1
+	PriorityFallback   = -100
 )
 
 type SubdomainMatcher interface {
@@ -28,6 +35,14 @@ type SubdomainMatcher interface {
 	MatchSubdomains() bool
 }
 
+// ResponseObserver is a handler that passes the queries on and sees the answer of the handler that answered them.
+// ObserveResponse is called before the answer is written, so the observer can act on it before the client does.
+// Only the first observer in the chain sees the answer.
+type ResponseObserver interface {
+	dns.Handler
+	ObserveResponse(m *dns.Msg)
+}
+
 type HandlerEntry struct {
 	Handler         dns.Handler
 	Priority        int
@@ -41,6 +56,8 @@ type HandlerEntry struct {
 type HandlerChain struct {
 	mu       sync.RWMutex
 	handlers []HandlerEntry
+	// queryLogger receives the answered queries as flow events, nil disables the query log
+	queryLogger nftypes.FlowLogger
 }
 
 // ResponseWriterChain wraps a dns.ResponseWriter to track if handler wants to continue chain
@@ -51,6 +68,8 @@ type ResponseWriterChain struct {
 	shouldContinue bool
 	response       *dns.Msg
 	meta           map[string]string
+	// observer sees the answer before it is written
+	observer ResponseObserver
 }
 
 // RequestID returns the request ID for tracing
@@ -73,6 +92,9 @@ func (w *ResponseWriterChain) WriteMsg(m *dns.Msg) error {
 		return nil
 	}
 	w.response = m
+	if w.observer != nil {
+		w.observer.ObserveResponse(m)
+	}
 	return w.ResponseWriter.WriteMsg(m)
 }
 
@@ -207,6 +229,8 @@ func (c *HandlerChain) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 	handlers := slices.Clone(c.handlers)
 	c.mu.RUnlock()
 
+	var observer ResponseObserver
+
 	// Try handlers in priority order
 	for _, entry := range handlers {
 		if !c.isHandlerMatch(qname, entry) {
@@ -226,11 +250,15 @@ func (c *HandlerChain) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 			ResponseWriter: w,
 			origPattern:    entry.OrigPattern,
 			requestID:      requestID,
+			observer:       observer,
 		}
 		entry.Handler.ServeDNS(chainWriter, r)
 
 		// If handler wants to continue, try next handler
 		if chainWriter.shouldContinue {
+			if o, ok := entry.Handler.(ResponseObserver); ok && observer == nil {
+				observer = o
+			}
 			if entry.Priority != PriorityMgmtCache {
 				logger.Tracef("handler requested continue for domain=%s", qname)
 			}
@@ -238,6 +266,7 @@ func (c *HandlerChain) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 		}
 
 		c.logResponse(logger, chainWriter, qname, startTime)
+		c.logQuery(w, chainWriter)
 		return
 	}
 
@@ -266,6 +295,52 @@ func (c *HandlerChain) logResponse(logger *log.Entry, cw *ResponseWriterChain, q
 		meta, time.Since(startTime))
 }
 
+// logQuery sends the answered query to the query log
+func (c *HandlerChain) logQuery(w dns.ResponseWriter, cw *ResponseWriterChain) {
+	if c.queryLogger == nil || cw.response == nil || len(cw.response.Question) == 0 {
+		return
+	}
+
+	question := cw.response.Question[0]
+	event := nftypes.EventFields{
+		FlowID:    uuid.New(),
+		Type:      nftypes.TypeEnd,
+		Direction: nftypes.Ingress,
+		Protocol:  nftypes.UDP,
+		DNSQuery: &nftypes.DNSQuery{
+			Name:   strings.ToLower(question.Name),
+			Type:   question.Qtype,
+			Rcode:  cw.response.Rcode,
+			Cached: cw.meta["cache"] == "hit",
+		},
+	}
+
+	if remote, ok := addrPortOf(w.RemoteAddr()); ok {
+		event.SourceIP = remote.Addr().Unmap()
+		event.SourcePort = remote.Port()
+	}
+	if local, ok := addrPortOf(w.LocalAddr()); ok {
+		event.DestIP = local.Addr().Unmap()
+		event.DestPort = local.Port()
+	}
+	if _, ok := w.RemoteAddr().(*net.TCPAddr); ok {
+		event.Protocol = nftypes.TCP
+	}
+
+	c.queryLogger.StoreEvent(event)
+}
+
+func addrPortOf(addr net.Addr) (netip.AddrPort, bool) {
+	switch a := addr.(type) {
+	case *net.UDPAddr:
+		return a.AddrPort(), true
+	case *net.TCPAddr:
+		return a.AddrPort(), true
+	default:
+		return netip.AddrPort{}, false
+	}
+}
+
 func (c *HandlerChain) isHandlerMatch(qname string, entry HandlerEntry) bool {
 	switch {
 	case entry.Pattern == ".":
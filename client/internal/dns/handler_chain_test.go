@@ -325,6 +325,58 @@ func TestHandlerChain_ServeDNS_ChainContinuation(t *testing.T) {
 	handler3.AssertExpectations(t)
 }
 
+type observingHandler struct {
+	observed []*dns.Msg
+}
+
+func (h *observingHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	resp := new(dns.Msg)
+	resp.SetRcode(r, dns.RcodeNameError)
+	resp.MsgHdr.Zero = true
+	_ = w.WriteMsg(resp)
+}
+
+func (h *observingHandler) ObserveResponse(m *dns.Msg) {
+	h.observed = append(h.observed, m)
+}
+
+// TestHandlerChain_ServeDNS_ResponseObserver tests that the first continuing observer sees the final answer
+func TestHandlerChain_ServeDNS_ResponseObserver(t *testing.T) {
+	chain := nbdns.NewHandlerChain()
+
+	specific := &observingHandler{}
+	general := &observingHandler{}
+	upstream := &nbdns.MockHandler{}
+
+	chain.AddHandler("sub.example.com.", specific, nbdns.PriorityDomainRule)
+	chain.AddHandler("*.example.com.", general, nbdns.PriorityDomainRule)
+	chain.AddHandler("sub.example.com.", upstream, nbdns.PriorityUpstream)
+
+	r := new(dns.Msg)
+	r.SetQuestion("sub.example.com.", dns.TypeA)
+
+	answer := new(dns.Msg)
+	answer.SetReply(r)
+	upstream.On("ServeDNS", mock.Anything, r).Run(func(args mock.Arguments) {
+		w := args.Get(0).(*nbdns.ResponseWriterChain)
+		assert.NoError(t, w.WriteMsg(answer))
+	}).Once()
+
+	var written *dns.Msg
+	w := &test.MockResponseWriter{WriteMsgFunc: func(m *dns.Msg) error {
+		// the observer acts on the answer before the client gets it
+		assert.Len(t, specific.observed, 1)
+		written = m
+		return nil
+	}}
+	chain.ServeDNS(w, r)
+
+	upstream.AssertExpectations(t)
+	assert.Equal(t, []*dns.Msg{answer}, specific.observed)
+	assert.Empty(t, general.observed)
+	assert.Equal(t, answer, written)
+}
+
 func TestHandlerChain_PriorityDeregistration(t *testing.T) {
 	tests := []struct {
 		name string
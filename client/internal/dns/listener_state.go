@@ -0,0 +1,49 @@
+package dns
+
+import (
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// serviceWithListenState is a service that reports the addresses it listens on
+type serviceWithListenState interface {
+	listenState() ([]netip.AddrPort, []string)
+}
+
+// reportListener updates the DNS listener of the status and publishes an event when the configured addresses
+// fall back to other ones
+func (s *DefaultServer) reportListener() {
+	svc, ok := s.service.(serviceWithListenState)
+	if !ok {
+		return
+	}
+
+	addrs, fallbacks := svc.listenState()
+	if s.statusRecorder != nil {
+		s.statusRecorder.UpdateDNSListener(peer.DNSListenerState{
+			Addresses: addrs,
+			Fallbacks: fallbacks,
+		})
+	}
+
+	if slices.Equal(fallbacks, s.listenerFallbacks) {
+		return
+	}
+	s.listenerFallbacks = fallbacks
+	if len(fallbacks) == 0 || s.statusRecorder == nil {
+		return
+	}
+
+	s.statusRecorder.PublishDedupEvent(
+		"dns-listener-fallback/"+strings.Join(fallbacks, ";"),
+		proto.SystemEvent_WARNING,
+		proto.SystemEvent_DNS,
+		"DNS listen address unavailable",
+		"Some configured DNS listen addresses are in use, the resolver listens on other ones. Run 'netbird status' for details.",
+		map[string]string{"fallbacks": strings.Join(fallbacks, "\n")},
+	)
+}
@@ -32,8 +32,12 @@ type Resolver struct {
 	records map[dns.Question][]dns.RR
 	domains map[domain.Domain]struct{}
 	// zones maps zone domain -> NonAuthoritative (true = non-authoritative, user-created zone)
-	zones    map[domain.Domain]bool
-	resolver resolver
+	zones map[domain.Domain]bool
+	// absentPeers maps the names of peers management doesn't let connect to the reason
+	absentPeers map[domain.Domain]string
+	// delegations are the domains within the zones that are handled by nameserver groups
+	delegations map[domain.Domain]struct{}
+	resolver    resolver
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -42,11 +46,13 @@ type Resolver struct {
 func NewResolver() *Resolver {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Resolver{
-		records: make(map[dns.Question][]dns.RR),
-		domains: make(map[domain.Domain]struct{}),
-		zones:   make(map[domain.Domain]bool),
-		ctx:     ctx,
-		cancel:  cancel,
+		records:     make(map[dns.Question][]dns.RR),
+		domains:     make(map[domain.Domain]struct{}),
+		zones:       make(map[domain.Domain]bool),
+		absentPeers: make(map[domain.Domain]string),
+		delegations: make(map[domain.Domain]struct{}),
+		ctx:         ctx,
+		cancel:      cancel,
 	}
 }
 
@@ -70,6 +76,8 @@ func (d *Resolver) Stop() {
 	maps.Clear(d.records)
 	maps.Clear(d.domains)
 	maps.Clear(d.zones)
+	maps.Clear(d.absentPeers)
+	maps.Clear(d.delegations)
 }
 
 // ID returns the unique handler ID
@@ -104,11 +112,38 @@ func (d *Resolver) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 		return
 	}
 
+	d.addAbsenceReason(replyMessage, r, question.Name)
+
 	if err := w.WriteMsg(replyMessage); err != nil {
 		logger.Warnf("failed to write the local resolver response: %v", err)
 	}
 }
 
+// addAbsenceReason attaches an extended DNS error with the reason to answers for peers management doesn't let
+// connect, so the querier can tell a blocked peer from an unreachable one. It requires EDNS in the request.
+func (d *Resolver) addAbsenceReason(reply, request *dns.Msg, qname string) {
+	if request.IsEdns0() == nil {
+		return
+	}
+
+	d.mu.RLock()
+	reason, ok := d.absentPeers[domain.Domain(qname)]
+	d.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	opt := reply.IsEdns0()
+	if opt == nil {
+		reply.SetEdns0(request.IsEdns0().UDPSize(), false)
+		opt = reply.IsEdns0()
+	}
+	opt.Option = append(opt.Option, &dns.EDNS0_EDE{
+		InfoCode:  dns.ExtendedErrorCodeBlocked,
+		ExtraText: "peer blocked by management: " + reason,
+	})
+}
+
 // determineRcode returns the appropriate DNS response code.
 // Per RFC 6604, CNAME chains should return the rcode of the final target resolution,
 // even if CNAME records are included in the answer.
@@ -144,12 +179,34 @@ func (d *Resolver) findZone(qname string) (nonAuthoritative bool, found bool) {
 	}
 }
 
+// isDelegated checks if the name is at or below a domain delegated to a nameserver group. Caller must hold the lock.
+func (d *Resolver) isDelegated(qname string) bool {
+	if len(d.delegations) == 0 {
+		return false
+	}
+
+	qname = strings.ToLower(dns.Fqdn(qname))
+	for {
+		if _, ok := d.delegations[domain.Domain(qname)]; ok {
+			return true
+		}
+		idx := strings.Index(qname, ".")
+		if idx == -1 || idx == len(qname)-1 {
+			return false
+		}
+		qname = qname[idx+1:]
+	}
+}
+
 // shouldFallthrough checks if the query should fallthrough to the next handler.
-// Returns true if the queried name belongs to a non-authoritative zone.
+// Returns true if the queried name belongs to a non-authoritative zone or is delegated to a nameserver group.
 func (d *Resolver) shouldFallthrough(qname string) bool {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
 
+	if d.isDelegated(qname) {
+		return true
+	}
 	nonAuth, found := d.findZone(qname)
 	return found && nonAuth
 }
@@ -175,11 +232,15 @@ func (d *Resolver) hasRecordsForDomain(domainName domain.Domain) bool {
 // isInManagedZone checks if the given name falls within any of our managed zones.
 // This is used to avoid unnecessary external resolution for CNAME targets that
 // are within zones we manage - if we don't have a record for it, it doesn't exist.
+// Names delegated to nameserver groups are resolved externally.
 // Caller must NOT hold the lock.
 func (d *Resolver) isInManagedZone(name string) bool {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
 
+	if d.isDelegated(name) {
+		return false
+	}
 	_, found := d.findZone(name)
 	return found
 }
@@ -401,6 +462,29 @@ func (d *Resolver) Update(customZones []nbdns.CustomZone) {
 	}
 }
 
+// UpdateAbsentPeers replaces the peers management doesn't let connect, keyed by their name
+func (d *Resolver) UpdateAbsentPeers(peers map[string]string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	maps.Clear(d.absentPeers)
+	for name, reason := range peers {
+		d.absentPeers[domain.Domain(strings.ToLower(dns.Fqdn(name)))] = reason
+	}
+}
+
+// UpdateDelegations replaces the domains within the zones that are handled by nameserver groups. The resolver
+// passes the queries for names at or below them it has no records for on to the next handler.
+func (d *Resolver) UpdateDelegations(domains []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	maps.Clear(d.delegations)
+	for _, name := range domains {
+		d.delegations[domain.Domain(strings.ToLower(dns.Fqdn(name)))] = struct{}{}
+	}
+}
+
 // RegisterRecord stores a new record by appending it to any existing list
 func (d *Resolver) RegisterRecord(record nbdns.SimpleRecord) error {
 	d.mu.Lock()
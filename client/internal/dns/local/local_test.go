@@ -1187,6 +1187,39 @@ func TestLocalResolver_Fallthrough(t *testing.T) {
 	}
 }
 
+// TestLocalResolver_Delegation verifies that names delegated to nameserver groups fall through
+// even within authoritative zones, while the records of the zone are still answered
+func TestLocalResolver_Delegation(t *testing.T) {
+	resolver := NewResolver()
+	resolver.Update([]nbdns.CustomZone{{
+		Domain: "corp.example.",
+		Records: []nbdns.SimpleRecord{
+			{Name: "app.corp.example.", Type: int(dns.TypeA), Class: nbdns.DefaultClass, TTL: 300, RData: "10.0.0.1"},
+		},
+	}})
+	resolver.UpdateDelegations([]string{"Legacy.Corp.Example"})
+
+	assert.True(t, resolver.shouldFallthrough("legacy.corp.example."), "delegated domain should fall through")
+	assert.True(t, resolver.shouldFallthrough("host.legacy.corp.example."), "names below the delegation should fall through")
+	assert.False(t, resolver.shouldFallthrough("other.corp.example."), "other names of the zone should not fall through")
+	assert.False(t, resolver.isInManagedZone("host.legacy.corp.example."), "delegated names should resolve externally")
+	assert.True(t, resolver.isInManagedZone("other.corp.example."))
+
+	var responseMSG *dns.Msg
+	responseWriter := &test.MockResponseWriter{
+		WriteMsgFunc: func(m *dns.Msg) error {
+			responseMSG = m
+			return nil
+		},
+	}
+	resolver.ServeDNS(responseWriter, new(dns.Msg).SetQuestion("app.corp.example.", dns.TypeA))
+	require.NotNil(t, responseMSG)
+	assert.Len(t, responseMSG.Answer, 1)
+
+	resolver.UpdateDelegations(nil)
+	assert.False(t, resolver.shouldFallthrough("host.legacy.corp.example."), "removed delegation should not fall through")
+}
+
 // TestLocalResolver_AuthoritativeFlag tests the AA flag behavior
 func TestLocalResolver_AuthoritativeFlag(t *testing.T) {
 	t.Run("direct record lookup is authoritative", func(t *testing.T) {
@@ -1419,3 +1452,42 @@ func BenchmarkIsInManagedZone_ManyZones(b *testing.B) {
 		resolver.isInManagedZone(qname)
 	}
 }
+
+func TestLocalResolver_AbsentPeers(t *testing.T) {
+	resolver := NewResolver()
+	resolver.Update([]nbdns.CustomZone{{
+		Domain: "netbird.cloud.",
+		Records: []nbdns.SimpleRecord{
+			{Name: "blocked.netbird.cloud.", Type: int(dns.TypeA), Class: nbdns.DefaultClass, TTL: 300, RData: "100.64.0.1"},
+			{Name: "peer.netbird.cloud.", Type: int(dns.TypeA), Class: nbdns.DefaultClass, TTL: 300, RData: "100.64.0.2"},
+		},
+	}})
+	resolver.UpdateAbsentPeers(map[string]string{"Blocked.netbird.cloud": "posture check failed"})
+
+	query := func(name string, edns bool) *dns.Msg {
+		msg := new(dns.Msg).SetQuestion(name, dns.TypeA)
+		if edns {
+			msg.SetEdns0(dns.DefaultMsgSize, false)
+		}
+		var resp *dns.Msg
+		resolver.ServeDNS(&test.MockResponseWriter{WriteMsgFunc: func(m *dns.Msg) error { resp = m; return nil }}, msg)
+		require.NotNil(t, resp)
+		return resp
+	}
+
+	resp := query("blocked.netbird.cloud.", true)
+	require.Len(t, resp.Answer, 1, "the record of the absent peer is still answered")
+	opt := resp.IsEdns0()
+	require.NotNil(t, opt)
+	require.Len(t, opt.Option, 1)
+	ede, ok := opt.Option[0].(*dns.EDNS0_EDE)
+	require.True(t, ok)
+	assert.Equal(t, dns.ExtendedErrorCodeBlocked, ede.InfoCode)
+	assert.Contains(t, ede.ExtraText, "posture check failed")
+
+	assert.Nil(t, query("blocked.netbird.cloud.", false).IsEdns0(), "no EDNS in the answer without EDNS in the request")
+	assert.Nil(t, query("peer.netbird.cloud.", true).IsEdns0(), "other peers carry no reason")
+
+	resolver.UpdateAbsentPeers(nil)
+	assert.Nil(t, query("blocked.netbird.cloud.", true).IsEdns0())
+}
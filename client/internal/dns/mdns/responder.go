@@ -0,0 +1,346 @@
+// Package mdns answers mDNS and LLMNR queries for the peers of the NetBird network on the NetBird interface.
+// Peers are announced by the first label of their FQDN: "peer1.netbird.cloud" answers to "peer1.local" over mDNS
+// and to "peer1" over LLMNR, so devices and tools that prefer link-local name resolution find the peers without
+// changes to the system DNS settings. Only IPv4 multicast is served.
+package mdns
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/miekg/dns"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/ipv4"
+
+	nberrors "github.com/netbirdio/netbird/client/errors"
+	nbdns "github.com/netbirdio/netbird/dns"
+)
+
+const (
+	mdnsPort  = 5353
+	llmnrPort = 5355
+
+	localDomain = "local."
+
+	// mdnsTTL is the TTL RFC 6762 recommends for host name records
+	mdnsTTL = 120
+	// legacyUnicastTTL is the TTL of answers to mDNS queries of plain DNS resolvers, RFC 6762 section 6.7
+	legacyUnicastTTL = 10
+	// llmnrTTL is the default TTL of RFC 4795
+	llmnrTTL = 30
+
+	// cacheFlushBit marks mDNS records as the unique answer for the name, RFC 6762 section 10.2
+	cacheFlushBit = 1 << 15
+	// unicastResponseBit is set in the class of mDNS questions that ask for a unicast response
+	unicastResponseBit = 1 << 15
+
+	maxPacketSize = 9000
+)
+
+var (
+	mdnsGroup  = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: mdnsPort}
+	llmnrGroup = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 252), Port: llmnrPort}
+)
+
+type protocol int
+
+const (
+	protoMDNS protocol = iota
+	protoLLMNR
+)
+
+func (p protocol) String() string {
+	if p == protoLLMNR {
+		return "LLMNR"
+	}
+	return "mDNS"
+}
+
+// Responder answers the multicast name queries that arrive on the NetBird interface
+type Responder struct {
+	ifaceName string
+	network   netip.Prefix
+
+	mu    sync.RWMutex
+	hosts map[string][]netip.Addr
+
+	connsMu sync.Mutex
+	conns   []*net.UDPConn
+	wg      sync.WaitGroup
+}
+
+// NewResponder returns a responder for the interface with the given name. Queries from outside of the network are
+// ignored.
+func NewResponder(ifaceName string, network netip.Prefix) *Responder {
+	return &Responder{
+		ifaceName: ifaceName,
+		network:   network,
+		hosts:     make(map[string][]netip.Addr),
+	}
+}
+
+// Start joins the mDNS and LLMNR multicast groups on the interface
+func (r *Responder) Start() error {
+	r.connsMu.Lock()
+	defer r.connsMu.Unlock()
+
+	if len(r.conns) > 0 {
+		return nil
+	}
+
+	iface, err := net.InterfaceByName(r.ifaceName)
+	if err != nil {
+		return fmt.Errorf("get interface %s: %w", r.ifaceName, err)
+	}
+
+	for _, group := range []struct {
+		addr  *net.UDPAddr
+		proto protocol
+	}{
+		{mdnsGroup, protoMDNS},
+		{llmnrGroup, protoLLMNR},
+	} {
+		conn, err := listenGroup(iface, group.addr)
+		if err != nil {
+			r.closeConns()
+			return fmt.Errorf("listen %s: %w", group.proto, err)
+		}
+		r.conns = append(r.conns, conn)
+
+		r.wg.Add(1)
+		go r.serve(conn, group.proto)
+	}
+
+	log.Infof("mDNS and LLMNR responder listening on interface %s", r.ifaceName)
+	return nil
+}
+
+// Stop leaves the multicast groups
+func (r *Responder) Stop() error {
+	r.connsMu.Lock()
+	defer r.connsMu.Unlock()
+
+	err := r.closeConns()
+	r.wg.Wait()
+	return err
+}
+
+func (r *Responder) closeConns() error {
+	var merr *multierror.Error
+	for _, conn := range r.conns {
+		if err := conn.Close(); err != nil {
+			merr = multierror.Append(merr, err)
+		}
+	}
+	r.conns = nil
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+// Update replaces the announced hosts with the A and AAAA records of the authoritative zones
+func (r *Responder) Update(zones []nbdns.CustomZone) {
+	hosts := make(map[string][]netip.Addr)
+	for _, zone := range zones {
+		if zone.NonAuthoritative {
+			continue
+		}
+
+		zoneSuffix := "." + strings.ToLower(dns.Fqdn(zone.Domain))
+		for _, record := range zone.Records {
+			if record.Type != int(dns.TypeA) && record.Type != int(dns.TypeAAAA) {
+				continue
+			}
+
+			label, ok := strings.CutSuffix(strings.ToLower(dns.Fqdn(record.Name)), zoneSuffix)
+			if !ok || label == "" || strings.Contains(label, ".") {
+				continue
+			}
+
+			addr, err := netip.ParseAddr(record.RData)
+			if err != nil {
+				log.Debugf("skipping record %s for mDNS: %v", record.Name, err)
+				continue
+			}
+			hosts[label] = append(hosts[label], addr.Unmap())
+		}
+	}
+
+	r.mu.Lock()
+	r.hosts = hosts
+	r.mu.Unlock()
+}
+
+func listenGroup(iface *net.Interface, group *net.UDPAddr) (*net.UDPConn, error) {
+	conn, err := net.ListenMulticastUDP("udp4", iface, group)
+	if err != nil {
+		return nil, err
+	}
+
+	// answers to the group have to leave through the NetBird interface, not the default one
+	pc := ipv4.NewPacketConn(conn)
+	if err := pc.SetMulticastInterface(iface); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("set multicast interface: %w", err)
+	}
+	if err := pc.SetMulticastTTL(255); err != nil {
+		log.Debugf("set multicast TTL for %s: %v", group, err)
+	}
+	return conn, nil
+}
+
+func (r *Responder) serve(conn *net.UDPConn, proto protocol) {
+	defer r.wg.Done()
+
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, src, err := conn.ReadFromUDPAddrPort(buf)
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Errorf("%s responder stopped: %v", proto, err)
+			}
+			return
+		}
+
+		// the socket is bound to the group address and receives the queries of all interfaces that joined it
+		if !r.network.Contains(src.Addr().Unmap()) {
+			continue
+		}
+
+		query := new(dns.Msg)
+		if err := query.Unpack(buf[:n]); err != nil {
+			log.Tracef("invalid %s query from %s: %v", proto, src, err)
+			continue
+		}
+
+		reply, multicast := r.answer(query, proto, src.Port())
+		if reply == nil {
+			continue
+		}
+
+		data, err := reply.Pack()
+		if err != nil {
+			log.Debugf("pack %s answer: %v", proto, err)
+			continue
+		}
+
+		dst := net.UDPAddrFromAddrPort(src)
+		if multicast {
+			dst = mdnsGroup
+		}
+		if _, err := conn.WriteToUDP(data, dst); err != nil {
+			log.Debugf("send %s answer to %s: %v", proto, dst, err)
+		}
+	}
+}
+
+// answer returns the reply to the query and whether it is sent to the multicast group, nil if the query isn't
+// for a known host
+func (r *Responder) answer(query *dns.Msg, proto protocol, srcPort uint16) (*dns.Msg, bool) {
+	if query.Response || query.Opcode != dns.OpcodeQuery || len(query.Question) == 0 {
+		return nil, false
+	}
+
+	// RFC 6762 section 6.7: queries not sent from port 5353 come from plain DNS resolvers and expect a unicast
+	// answer like a regular DNS server would give
+	legacyUnicast := proto == protoMDNS && srcPort != mdnsPort
+	multicast := proto == protoMDNS && !legacyUnicast
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var answers []dns.RR
+	var questions []dns.Question
+	for _, q := range query.Question {
+		if proto == protoMDNS && q.Qclass&unicastResponseBit != 0 {
+			multicast = false
+		}
+
+		label, ok := hostLabel(q.Name, proto)
+		if !ok {
+			continue
+		}
+		addrs, ok := r.hosts[label]
+		if !ok {
+			continue
+		}
+
+		rrs := records(q, addrs, ttl(proto, legacyUnicast))
+		if len(rrs) == 0 {
+			continue
+		}
+		answers = append(answers, rrs...)
+		questions = append(questions, dns.Question{Name: q.Name, Qtype: q.Qtype, Qclass: q.Qclass &^ unicastResponseBit})
+	}
+
+	if len(answers) == 0 {
+		return nil, false
+	}
+
+	reply := new(dns.Msg)
+	reply.Response = true
+	reply.Authoritative = true
+	reply.Answer = answers
+	if proto == protoMDNS && !legacyUnicast {
+		if multicast {
+			// multicast answers carry the cache flush bit, no question and no ID, RFC 6762 section 18
+			for _, rr := range answers {
+				rr.Header().Class |= cacheFlushBit
+			}
+		}
+		return reply, multicast
+	}
+
+	reply.Id = query.Id
+	reply.Question = questions
+	return reply, false
+}
+
+// hostLabel returns the host label of the queried name if it is a name the protocol resolves
+func hostLabel(name string, proto protocol) (string, bool) {
+	name = strings.ToLower(dns.Fqdn(name))
+
+	if proto == protoMDNS {
+		var ok bool
+		if name, ok = strings.CutSuffix(name, "."+localDomain); !ok {
+			return "", false
+		}
+	} else {
+		name = strings.TrimSuffix(name, ".")
+	}
+
+	if name == "" || strings.Contains(name, ".") {
+		return "", false
+	}
+	return name, true
+}
+
+func records(q dns.Question, addrs []netip.Addr, ttl uint32) []dns.RR {
+	var rrs []dns.RR
+	for _, addr := range addrs {
+		hdr := dns.RR_Header{Name: q.Name, Class: dns.ClassINET, Ttl: ttl}
+		switch {
+		case addr.Is4() && (q.Qtype == dns.TypeA || q.Qtype == dns.TypeANY):
+			hdr.Rrtype = dns.TypeA
+			rrs = append(rrs, &dns.A{Hdr: hdr, A: addr.AsSlice()})
+		case addr.Is6() && (q.Qtype == dns.TypeAAAA || q.Qtype == dns.TypeANY):
+			hdr.Rrtype = dns.TypeAAAA
+			rrs = append(rrs, &dns.AAAA{Hdr: hdr, AAAA: addr.AsSlice()})
+		}
+	}
+	return rrs
+}
+
+func ttl(proto protocol, legacyUnicast bool) uint32 {
+	switch {
+	case proto == protoLLMNR:
+		return llmnrTTL
+	case legacyUnicast:
+		return legacyUnicastTTL
+	default:
+		return mdnsTTL
+	}
+}
@@ -0,0 +1,155 @@
+package mdns
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nbdns "github.com/netbirdio/netbird/dns"
+)
+
+func newTestResponder() *Responder {
+	r := NewResponder("wt0", netip.MustParsePrefix("100.64.0.0/10"))
+	r.Update([]nbdns.CustomZone{
+		{
+			Domain: "netbird.cloud",
+			Records: []nbdns.SimpleRecord{
+				{Name: "peer1.netbird.cloud", Type: int(dns.TypeA), Class: nbdns.DefaultClass, TTL: 300, RData: "100.64.0.2"},
+				{Name: "peer1.netbird.cloud", Type: int(dns.TypeAAAA), Class: nbdns.DefaultClass, TTL: 300, RData: "fd00::2"},
+				{Name: "nested.peer2.netbird.cloud", Type: int(dns.TypeA), Class: nbdns.DefaultClass, TTL: 300, RData: "100.64.0.3"},
+			},
+		},
+		{
+			Domain:           "custom.zone",
+			NonAuthoritative: true,
+			Records: []nbdns.SimpleRecord{
+				{Name: "host.custom.zone", Type: int(dns.TypeA), Class: nbdns.DefaultClass, TTL: 300, RData: "10.0.0.1"},
+			},
+		},
+	})
+	return r
+}
+
+func TestResponder_Answer(t *testing.T) {
+	r := newTestResponder()
+
+	tests := []struct {
+		name          string
+		qname         string
+		qtype         uint16
+		qclass        uint16
+		proto         protocol
+		srcPort       uint16
+		expectAnswers []string
+		expectMcast   bool
+	}{
+		{
+			name:          "mDNS A",
+			qname:         "peer1.local.",
+			qtype:         dns.TypeA,
+			proto:         protoMDNS,
+			srcPort:       mdnsPort,
+			expectAnswers: []string{"100.64.0.2"},
+			expectMcast:   true,
+		},
+		{
+			name:          "mDNS unicast response requested",
+			qname:         "PEER1.local.",
+			qtype:         dns.TypeAAAA,
+			qclass:        unicastResponseBit,
+			proto:         protoMDNS,
+			srcPort:       mdnsPort,
+			expectAnswers: []string{"fd00::2"},
+		},
+		{
+			name:          "mDNS legacy unicast",
+			qname:         "peer1.local.",
+			qtype:         dns.TypeANY,
+			proto:         protoMDNS,
+			srcPort:       40000,
+			expectAnswers: []string{"100.64.0.2", "fd00::2"},
+		},
+		{
+			name:          "LLMNR",
+			qname:         "peer1.",
+			qtype:         dns.TypeA,
+			proto:         protoLLMNR,
+			srcPort:       40000,
+			expectAnswers: []string{"100.64.0.2"},
+		},
+		{
+			name:    "mDNS name outside of local",
+			qname:   "peer1.netbird.cloud.",
+			qtype:   dns.TypeA,
+			proto:   protoMDNS,
+			srcPort: mdnsPort,
+		},
+		{
+			name:    "multi label name",
+			qname:   "nested.peer2.local.",
+			qtype:   dns.TypeA,
+			proto:   protoMDNS,
+			srcPort: mdnsPort,
+		},
+		{
+			name:    "non-authoritative zone",
+			qname:   "host.",
+			qtype:   dns.TypeA,
+			proto:   protoLLMNR,
+			srcPort: 40000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := new(dns.Msg)
+			query.Id = 1234
+			query.Question = []dns.Question{{Name: tt.qname, Qtype: tt.qtype, Qclass: dns.ClassINET | tt.qclass}}
+
+			reply, multicast := r.answer(query, tt.proto, tt.srcPort)
+			if len(tt.expectAnswers) == 0 {
+				assert.Nil(t, reply)
+				return
+			}
+			require.NotNil(t, reply)
+			assert.Equal(t, tt.expectMcast, multicast)
+			assert.True(t, reply.Response)
+			assert.True(t, reply.Authoritative)
+
+			var answers []string
+			for _, rr := range reply.Answer {
+				assert.Equal(t, tt.qname, rr.Header().Name)
+				assert.Equal(t, multicast, rr.Header().Class&cacheFlushBit != 0)
+				switch rec := rr.(type) {
+				case *dns.A:
+					answers = append(answers, rec.A.String())
+				case *dns.AAAA:
+					answers = append(answers, rec.AAAA.String())
+				}
+			}
+			assert.ElementsMatch(t, tt.expectAnswers, answers)
+
+			if multicast {
+				assert.Zero(t, reply.Id)
+				assert.Empty(t, reply.Question)
+			} else if tt.proto == protoLLMNR || tt.srcPort != mdnsPort {
+				assert.Equal(t, query.Id, reply.Id)
+				assert.Len(t, reply.Question, 1)
+			}
+		})
+	}
+}
+
+func TestResponder_IgnoresResponses(t *testing.T) {
+	r := newTestResponder()
+
+	msg := new(dns.Msg)
+	msg.Response = true
+	msg.Question = []dns.Question{{Name: "peer1.local.", Qtype: dns.TypeA, Qclass: dns.ClassINET}}
+
+	reply, _ := r.answer(msg, protoMDNS, mdnsPort)
+	assert.Nil(t, reply)
+}
@@ -66,6 +66,20 @@ func (m *MockServer) UpdateDNSServer(serial uint64, update nbdns.Config) error {
 	return fmt.Errorf("method UpdateDNSServer is not implemented")
 }
 
+// CacheStats mock implementation of CacheStats from Server interface
+func (m *MockServer) CacheStats() CacheStats {
+	return CacheStats{}
+}
+
+// FlushCache mock implementation of FlushCache from Server interface
+func (m *MockServer) FlushCache() int {
+	return 0
+}
+
+// UpdateAbsentPeers mock implementation of UpdateAbsentPeers from Server interface
+func (m *MockServer) UpdateAbsentPeers(map[string]string) {
+}
+
 func (m *MockServer) SearchDomains() []string {
 	return make([]string, 0)
 }
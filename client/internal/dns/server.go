@@ -18,9 +18,12 @@ import (
 	"github.com/netbirdio/netbird/client/iface/netstack"
 	dnsconfig "github.com/netbirdio/netbird/client/internal/dns/config"
 	"github.com/netbirdio/netbird/client/internal/dns/local"
+	"github.com/netbirdio/netbird/client/internal/dns/mdns"
 	"github.com/netbirdio/netbird/client/internal/dns/mgmt"
 	"github.com/netbirdio/netbird/client/internal/dns/types"
+	"github.com/netbirdio/netbird/client/internal/eventbus"
 	"github.com/netbirdio/netbird/client/internal/listener"
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
 	"github.com/netbirdio/netbird/client/internal/peer"
 	"github.com/netbirdio/netbird/client/internal/statemanager"
 	nbdns "github.com/netbirdio/netbird/dns"
@@ -50,6 +53,9 @@ type Server interface {
 	ProbeAvailability()
 	UpdateServerConfig(domains dnsconfig.ServerDomains) error
 	PopulateManagementDomain(mgmtURL *url.URL) error
+	UpdateAbsentPeers(peers map[string]string)
+	CacheStats() CacheStats
+	FlushCache() int
 }
 
 type nsGroupsByDomain struct {
@@ -85,6 +91,16 @@ type DefaultServer struct {
 	extraDomains       map[domain.Domain]int
 
 	mgmtCacheResolver *mgmt.Resolver
+	// responseCache is shared by the upstream resolvers
+	responseCache *responseCache
+	// fallbackActive is set while the DNS service is down and the host uses its previous resolvers
+	fallbackActive bool
+	// mdnsResponder answers mDNS and LLMNR queries for the peers on the NetBird interface, nil if disabled
+	mdnsResponder *mdns.Responder
+	// zoneConflicts are the last reported overlaps of the custom zones
+	zoneConflicts []string
+	// listenerFallbacks are the last reported fallbacks of the listen addresses
+	listenerFallbacks []string
 
 	// permanent related properties
 	permanent      bool
@@ -96,6 +112,8 @@ type DefaultServer struct {
 
 	statusRecorder *peer.Status
 	stateManager   *statemanager.Manager
+	// eventBus receives the states of the nameserver groups
+	eventBus *eventbus.Bus
 }
 
 type handlerWithStop interface {
@@ -120,6 +138,12 @@ type DefaultServerConfig struct {
 	StatusRecorder *peer.Status
 	StateManager   *statemanager.Manager
 	DisableSys     bool
+	// QueryLogger receives every answered query as a flow event, nil disables the query log
+	QueryLogger nftypes.FlowLogger
+	// MDNSResponder answers mDNS and LLMNR queries for the peer names on the NetBird interface
+	MDNSResponder bool
+	// ExtraAddresses are the additional addresses the resolver listens on next to the one the system resolver uses
+	ExtraAddresses []netip.AddrPort
 }
 
 // NewDefaultServer returns a new dns server
@@ -136,11 +160,18 @@ func NewDefaultServer(ctx context.Context, config DefaultServerConfig) (*Default
 	var dnsService service
 	if config.WgInterface.IsUserspaceBind() {
 		dnsService = NewServiceViaMemory(config.WgInterface)
+		if len(config.ExtraAddresses) > 0 {
+			log.Warnf("the DNS resolver runs in memory, ignoring the additional listen addresses %v", config.ExtraAddresses)
+		}
 	} else {
-		dnsService = newServiceViaListener(config.WgInterface, addrPort)
+		dnsService = newServiceViaListener(config.WgInterface, addrPort, config.ExtraAddresses)
 	}
 
 	server := newDefaultServer(ctx, config.WgInterface, dnsService, config.StatusRecorder, config.StateManager, config.DisableSys)
+	server.handlerChain.queryLogger = config.QueryLogger
+	if config.MDNSResponder {
+		server.mdnsResponder = mdns.NewResponder(config.WgInterface.Name(), config.WgInterface.Address().Network)
+	}
 	return server, nil
 }
 
@@ -208,17 +239,27 @@ func newDefaultServer(
 		hostsDNSHolder:    newHostsDNSHolder(),
 		hostManager:       &noopHostConfigurator{},
 		mgmtCacheResolver: mgmtCacheResolver,
+		responseCache:     newResponseCache(),
 		currentConfigHash: ^uint64(0), // Initialize to max uint64 to ensure first config is always applied
 	}
 
 	// register with root zone, handler chain takes care of the routing
 	dnsService.RegisterMux(".", handlerChain)
 
+	if svc, ok := dnsService.(serviceWithFailureHandler); ok {
+		svc.setFailureHandler(defaultServer.onServiceFailure)
+	}
+
 	return defaultServer
 }
 
 // RegisterHandler registers a handler for the given domains with the given priority.
 // Any previously registered handler for the same domain and priority will be replaced.
+// SetEventBus sets the bus the server publishes its events to, it must be called before Initialize
+func (s *DefaultServer) SetEventBus(bus *eventbus.Bus) {
+	s.eventBus = bus
+}
+
 func (s *DefaultServer) RegisterHandler(domains domain.List, handler dns.Handler, priority int) {
 	s.mux.Lock()
 	defer s.mux.Unlock()
@@ -290,10 +331,13 @@ func (s *DefaultServer) Initialize() (err error) {
 		if err != nil {
 			return fmt.Errorf("service listen: %w", err)
 		}
+		s.reportListener()
 	}
 
 	s.stateManager.RegisterState(&ShutdownState{})
 
+	s.startMDNSResponder()
+
 	// Keep using noop host manager if dns off requested or running in netstack mode.
 	// Netstack mode currently doesn't have a way to receive DNS requests.
 	// TODO: Use listener on localhost in netstack mode when running as root.
@@ -318,6 +362,18 @@ func (s *DefaultServer) DnsIP() netip.Addr {
 	return s.service.RuntimeIP()
 }
 
+// OriginalNameservers returns the nameservers the host used before its DNS was pointed at this server, if the host
+// manager keeps them
+func (s *DefaultServer) OriginalNameservers() []netip.Addr {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if hostMgrWithNS, ok := s.hostManager.(hostManagerWithOriginalNS); ok {
+		return hostMgrWithNS.getOriginalNameservers()
+	}
+	return nil
+}
+
 // Stop stops the server
 func (s *DefaultServer) Stop() {
 	s.ctxCancel()
@@ -330,11 +386,38 @@ func (s *DefaultServer) Stop() {
 		log.Errorf("failed to disable DNS: %v", err)
 	}
 
+	if s.mdnsResponder != nil {
+		if err := s.mdnsResponder.Stop(); err != nil {
+			log.Errorf("failed to stop mDNS responder: %v", err)
+		}
+	}
+
 	maps.Clear(s.extraDomains)
 }
 
+// startMDNSResponder starts answering the multicast name queries. Netstack mode has no interface to join the
+// multicast groups on.
+func (s *DefaultServer) startMDNSResponder() {
+	if s.mdnsResponder == nil {
+		return
+	}
+	if netstack.IsEnabled() {
+		log.Info("mDNS responder is not supported in netstack mode")
+		return
+	}
+	if err := s.mdnsResponder.Start(); err != nil {
+		log.Errorf("failed to start mDNS responder: %v", err)
+	}
+}
+
 func (s *DefaultServer) disableDNS() error {
 	defer s.service.Stop()
+	if s.statusRecorder != nil {
+		s.statusRecorder.UpdateDNSListener(peer.DNSListenerState{})
+	}
+
+	// the service is stopped on purpose, stop retrying it
+	s.fallbackActive = false
 
 	if s.isUsingNoopHostManager() {
 		return nil
@@ -411,6 +494,9 @@ func (s *DefaultServer) UpdateDNSServer(serial uint64, update nbdns.Config) erro
 		return nil
 	}
 
+	// answers may come from other upstreams with the new configuration
+	s.responseCache.flush()
+
 	if err := s.applyConfiguration(update); err != nil {
 		return fmt.Errorf("apply configuration: %w", err)
 	}
@@ -421,6 +507,24 @@ func (s *DefaultServer) UpdateDNSServer(serial uint64, update nbdns.Config) erro
 	return nil
 }
 
+// CacheStats returns the counters of the upstream answer cache
+func (s *DefaultServer) CacheStats() CacheStats {
+	return s.responseCache.stats()
+}
+
+// FlushCache removes all cached upstream answers and returns how many were removed
+func (s *DefaultServer) FlushCache() int {
+	n := s.responseCache.flush()
+	log.Infof("flushed %d cached DNS answers", n)
+	return n
+}
+
+// UpdateAbsentPeers sets the peers management doesn't let connect, keyed by their name. Local answers for them
+// carry the reason.
+func (s *DefaultServer) UpdateAbsentPeers(peers map[string]string) {
+	s.localResolver.UpdateAbsentPeers(peers)
+}
+
 func (s *DefaultServer) SearchDomains() []string {
 	var searchDomains []string
 
@@ -478,6 +582,7 @@ func (s *DefaultServer) applyConfiguration(update nbdns.Config) error {
 	if update.ServiceEnable {
 		if err := s.enableDNS(); err != nil {
 			log.Errorf("failed to enable DNS: %v", err)
+			s.activateFallback(err)
 		}
 	} else if !s.permanent {
 		if err := s.disableDNS(); err != nil {
@@ -498,7 +603,14 @@ func (s *DefaultServer) applyConfiguration(update nbdns.Config) error {
 
 	s.updateMux(muxUpdates)
 
-	s.localResolver.Update(localZones)
+	layout := layoutZones(localZones, update.NameServerGroups)
+	s.reportZoneConflicts(layout.conflicts)
+
+	s.localResolver.UpdateDelegations(layout.delegations)
+	s.localResolver.Update(layout.zones)
+	if s.mdnsResponder != nil {
+		s.mdnsResponder.Update(layout.zones)
+	}
 
 	s.currentConfig = dnsConfigToHostDNSConfig(update, s.service.RuntimeIP(), s.service.RuntimePort())
 
@@ -536,6 +648,7 @@ func (s *DefaultServer) enableDNS() error {
 	if err := s.service.Listen(); err != nil {
 		return fmt.Errorf("start DNS service: %w", err)
 	}
+	s.reportListener()
 
 	if !s.isUsingNoopHostManager() {
 		return nil
@@ -566,6 +679,12 @@ func (s *DefaultServer) applyHostConfig() {
 		return
 	}
 
+	// keep the previous system resolvers until the DNS service is back
+	if s.fallbackActive {
+		log.Debugf("not applying host config while the DNS service is unavailable")
+		return
+	}
+
 	config := s.currentConfig
 
 	existingDomains := make(map[string]struct{})
@@ -642,6 +761,7 @@ func (s *DefaultServer) registerFallback(config HostDNSConfig) {
 		log.Errorf("failed to create upstream resolver for original nameservers: %v", err)
 		return
 	}
+	handler.cache = s.responseCache
 
 	for _, ns := range originalNameservers {
 		if ns == config.ServerIP {
@@ -753,6 +873,7 @@ func (s *DefaultServer) createHandlersForDomainGroup(domainGroup nsGroupsByDomai
 		if err != nil {
 			return nil, fmt.Errorf("create upstream resolver: %v", err)
 		}
+		handler.cache = s.responseCache
 
 		for _, ns := range nsGroup.NameServers {
 			if ns.NSType != nbdns.UDPNameServerType {
@@ -937,6 +1058,7 @@ func (s *DefaultServer) addHostRootZone() {
 		log.Errorf("unable to create a new upstream resolver, error: %v", err)
 		return
 	}
+	handler.cache = s.responseCache
 
 	handler.upstreamServers = maps.Keys(hostDNSServers)
 	handler.deactivate = func(error) {}
@@ -964,7 +1086,7 @@ func (s *DefaultServer) updateNSGroupStates(groups []*nbdns.NameServerGroup) {
 		}
 		states = append(states, state)
 	}
-	s.statusRecorder.UpdateDNSStates(states)
+	s.eventBus.Publish(eventbus.DNSStatesChanged{States: states})
 }
 
 func (s *DefaultServer) updateNSState(nsGroup *nbdns.NameServerGroup, err error, enabled bool) {
@@ -977,7 +1099,7 @@ func (s *DefaultServer) updateNSState(nsGroup *nbdns.NameServerGroup, err error,
 			break
 		}
 	}
-	s.statusRecorder.UpdateDNSStates(states)
+	s.eventBus.Publish(eventbus.DNSStatesChanged{States: states})
 }
 
 func generateGroupKey(nsGroup *nbdns.NameServerGroup) string {
@@ -6,6 +6,8 @@ import (
 	"net"
 	"net/netip"
 	"runtime"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -26,34 +28,58 @@ var (
 )
 
 type serviceViaListener struct {
-	wgInterface       WGIface
-	dnsMux            *dns.ServeMux
-	customAddr        *netip.AddrPort
+	wgInterface WGIface
+	dnsMux      *dns.ServeMux
+	customAddr  *netip.AddrPort
+	// extraAddrs are the additional addresses to listen on, served by extraServers
+	extraAddrs   []netip.AddrPort
+	extraServers []*dns.Server
+	// fallbacks describe the configured addresses that couldn't be bound and what was bound instead
+	fallbacks         []string
 	server            *dns.Server
 	listenIP          netip.Addr
 	listenPort        uint16
 	listenerIsRunning bool
 	listenerFlagLock  sync.Mutex
 	ebpfService       ebpfMgr.Manager
+	// onFailure is called when the listener stops without being asked to
+	onFailure func(error)
 }
 
-func newServiceViaListener(wgIface WGIface, customAddr *netip.AddrPort) *serviceViaListener {
+func newServiceViaListener(wgIface WGIface, customAddr *netip.AddrPort, extraAddrs []netip.AddrPort) *serviceViaListener {
 	mux := dns.NewServeMux()
 
 	s := &serviceViaListener{
 		wgInterface: wgIface,
 		dnsMux:      mux,
 		customAddr:  customAddr,
-		server: &dns.Server{
-			Net:     "udp",
-			Handler: mux,
-			UDPSize: 65535,
-		},
+		extraAddrs:  extraAddrs,
 	}
 
 	return s
 }
 
+// ParseListenAddresses parses DNS listen addresses given as ip or ip:port, the port is 53 if omitted
+func ParseListenAddresses(addrs []string) ([]netip.AddrPort, error) {
+	var parsed []netip.AddrPort
+	for _, addr := range addrs {
+		if ip, err := netip.ParseAddr(strings.Trim(addr, "[]")); err == nil {
+			parsed = append(parsed, netip.AddrPortFrom(ip.Unmap(), DefaultPort))
+			continue
+		}
+
+		addrPort, err := netip.ParseAddrPort(addr)
+		if err != nil {
+			return nil, fmt.Errorf("parse DNS listen address %q: %w", addr, err)
+		}
+		if addrPort.Port() == 0 {
+			return nil, fmt.Errorf("parse DNS listen address %q: port 0 is not allowed", addr)
+		}
+		parsed = append(parsed, netip.AddrPortFrom(addrPort.Addr().Unmap(), addrPort.Port()))
+	}
+	return parsed, nil
+}
+
 func (s *serviceViaListener) Listen() error {
 	s.listenerFlagLock.Lock()
 	defer s.listenerFlagLock.Unlock()
@@ -62,26 +88,159 @@ func (s *serviceViaListener) Listen() error {
 		return nil
 	}
 
+	s.fallbacks = nil
+
+	// bind before serving, so a failed bind is reported to the caller
+	conn, err := s.listenPrimary()
+	if err != nil {
+		return err
+	}
+	addr := fmt.Sprintf("%s:%d", s.listenIP, s.listenPort)
+
+	// a server can't be started again once it stopped, every listen gets a new one
+	server := &dns.Server{
+		Addr:       addr,
+		Net:        "udp",
+		Handler:    s.dnsMux,
+		UDPSize:    65535,
+		PacketConn: conn,
+	}
+	s.server = server
+	s.listenerIsRunning = true
+
+	log.Debugf("starting dns on %s", addr)
+	go func() {
+		err := server.ActivateAndServe()
+		if !s.serverStopped(server) || err == nil {
+			return
+		}
+
+		log.Errorf("dns server running on %s returned an error: %v", addr, err)
+		if s.onFailure != nil {
+			s.onFailure(err)
+		}
+	}()
+
+	s.listenExtra()
+
+	return nil
+}
+
+// listenPrimary binds the address the system resolver uses. A custom address that can't be bound falls back to the
+// automatically selected one. Callers must hold listenerFlagLock.
+func (s *serviceViaListener) listenPrimary() (net.PacketConn, error) {
 	var err error
 	s.listenIP, s.listenPort, err = s.evalListenAddress()
 	if err != nil {
 		log.Errorf("failed to eval runtime address: %s", err)
-		return fmt.Errorf("eval listen address: %w", err)
+		return nil, fmt.Errorf("eval listen address: %w", err)
 	}
 	s.listenIP = s.listenIP.Unmap()
-	s.server.Addr = fmt.Sprintf("%s:%d", s.listenIP, s.listenPort)
-	log.Debugf("starting dns on %s", s.server.Addr)
-	go func() {
-		s.setListenerStatus(true)
-		defer s.setListenerStatus(false)
 
-		err := s.server.ListenAndServe()
+	addr := netip.AddrPortFrom(s.listenIP, s.listenPort)
+	conn, err := net.ListenPacket("udp", addr.String())
+	if err == nil {
+		return conn, nil
+	}
+	s.freeEBPF()
+	if s.customAddr == nil {
+		return nil, fmt.Errorf("listen on %s: %w", addr, err)
+	}
+
+	log.Warnf("failed to listen on the custom DNS address %s, selecting another one: %v", addr, err)
+	s.listenIP, s.listenPort, err = s.evalAutoListenAddress()
+	if err != nil {
+		return nil, fmt.Errorf("eval fallback listen address: %w", err)
+	}
+	s.listenIP = s.listenIP.Unmap()
+
+	fallback := netip.AddrPortFrom(s.listenIP, s.listenPort)
+	conn, err = net.ListenPacket("udp", fallback.String())
+	if err != nil {
+		s.freeEBPF()
+		return nil, fmt.Errorf("listen on %s: %w", fallback, err)
+	}
+	s.fallbacks = append(s.fallbacks, fmt.Sprintf("custom address %s is unavailable, listening on %s", addr, fallback))
+	return conn, nil
+}
+
+// listenExtra binds the additional addresses, an address whose port is taken falls back to the custom port. The
+// addresses that can't be bound are reported as fallbacks. Callers must hold listenerFlagLock.
+func (s *serviceViaListener) listenExtra() {
+	primary := netip.AddrPortFrom(s.listenIP, s.listenPort)
+	for _, addr := range s.extraAddrs {
+		if addr == primary {
+			continue
+		}
+
+		bound := addr
+		conn, err := net.ListenPacket("udp", addr.String())
+		if err != nil && addr.Port() != customPort {
+			fallback := netip.AddrPortFrom(addr.Addr(), customPort)
+			if fallbackConn, fallbackErr := net.ListenPacket("udp", fallback.String()); fallbackErr == nil {
+				log.Warnf("failed to listen on the DNS address %s, listening on %s: %v", addr, fallback, err)
+				s.fallbacks = append(s.fallbacks, fmt.Sprintf("%s is unavailable, listening on %s", addr, fallback))
+				conn, bound, err = fallbackConn, fallback, nil
+			}
+		}
 		if err != nil {
-			log.Errorf("dns server running with %d port returned an error: %v. Will not retry", s.listenPort, err)
+			log.Warnf("failed to listen on the DNS address %s: %v", addr, err)
+			s.fallbacks = append(s.fallbacks, fmt.Sprintf("%s is unavailable: %v", addr, err))
+			continue
 		}
-	}()
 
-	return nil
+		server := &dns.Server{
+			Addr:       bound.String(),
+			Net:        "udp",
+			Handler:    s.dnsMux,
+			UDPSize:    65535,
+			PacketConn: conn,
+		}
+		s.extraServers = append(s.extraServers, server)
+
+		log.Debugf("starting dns on additional address %s", bound)
+		go func() {
+			if err := server.ActivateAndServe(); err != nil {
+				log.Warnf("dns server running on additional address %s returned an error: %v", bound, err)
+			}
+		}()
+	}
+}
+
+// listenState returns the addresses the service listens on, the one of the system resolver first, and the
+// fallbacks from the configured addresses
+func (s *serviceViaListener) listenState() ([]netip.AddrPort, []string) {
+	s.listenerFlagLock.Lock()
+	defer s.listenerFlagLock.Unlock()
+
+	if !s.listenerIsRunning {
+		return nil, nil
+	}
+
+	addrs := []netip.AddrPort{netip.AddrPortFrom(s.listenIP, s.listenPort)}
+	for _, server := range s.extraServers {
+		addrs = append(addrs, netip.MustParseAddrPort(server.Addr))
+	}
+	return addrs, slices.Clone(s.fallbacks)
+}
+
+// serverStopped marks the listener as not running if the server is still the current one. It reports false if
+// the server was stopped or replaced in the meantime.
+func (s *serviceViaListener) serverStopped(server *dns.Server) bool {
+	s.listenerFlagLock.Lock()
+	defer s.listenerFlagLock.Unlock()
+
+	if s.server != server || !s.listenerIsRunning {
+		return false
+	}
+	s.listenerIsRunning = false
+	s.freeEBPF()
+	return true
+}
+
+// setFailureHandler sets the function called when the listener stops without being asked to
+func (s *serviceViaListener) setFailureHandler(onFailure func(error)) {
+	s.onFailure = onFailure
 }
 
 func (s *serviceViaListener) Stop() {
@@ -95,17 +254,32 @@ func (s *serviceViaListener) Stop() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	err := s.server.ShutdownContext(ctx)
-	if err != nil {
-		log.Errorf("stopping dns server listener returned an error: %v", err)
-	}
+	s.listenerIsRunning = false
 
-	if s.ebpfService != nil {
-		err = s.ebpfService.FreeDNSFwd()
-		if err != nil {
-			log.Errorf("stopping traffic forwarder returned an error: %v", err)
+	for _, server := range append([]*dns.Server{s.server}, s.extraServers...) {
+		if err := server.ShutdownContext(ctx); err != nil {
+			log.Errorf("stopping dns server listener on %s returned an error: %v", server.Addr, err)
+			// the server might not have started serving yet, closing the socket makes it return right away
+			if err := server.PacketConn.Close(); err != nil {
+				log.Debugf("closing dns server socket: %v", err)
+			}
 		}
 	}
+	s.extraServers = nil
+
+	s.freeEBPF()
+}
+
+// freeEBPF unloads the eBPF DNS forwarder if it is in use. Callers must hold listenerFlagLock.
+func (s *serviceViaListener) freeEBPF() {
+	if s.ebpfService == nil {
+		return
+	}
+
+	if err := s.ebpfService.FreeDNSFwd(); err != nil {
+		log.Errorf("stopping traffic forwarder returned an error: %v", err)
+	}
+	s.ebpfService = nil
 }
 
 func (s *serviceViaListener) RegisterMux(pattern string, handler dns.Handler) {
@@ -132,13 +306,6 @@ func (s *serviceViaListener) RuntimeIP() netip.Addr {
 	return s.listenIP
 }
 
-func (s *serviceViaListener) setListenerStatus(running bool) {
-	s.listenerFlagLock.Lock()
-	defer s.listenerFlagLock.Unlock()
-
-	s.listenerIsRunning = running
-}
-
 // evalListenAddress figure out the listen address for the DNS server
 // first check the 53 port availability on WG interface or lo, if not success
 // pick a random port on WG interface for eBPF, if not success
@@ -148,6 +315,11 @@ func (s *serviceViaListener) evalListenAddress() (netip.Addr, uint16, error) {
 		return s.customAddr.Addr(), s.customAddr.Port(), nil
 	}
 
+	return s.evalAutoListenAddress()
+}
+
+// evalAutoListenAddress selects the listen address without the custom address
+func (s *serviceViaListener) evalAutoListenAddress() (netip.Addr, uint16, error) {
 	ip, ok := s.testFreePort(DefaultPort)
 	if ok {
 		return ip, DefaultPort, nil
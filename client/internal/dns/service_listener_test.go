@@ -0,0 +1,79 @@
+package dns
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseListenAddresses(t *testing.T) {
+	addrs, err := ParseListenAddresses([]string{"127.0.0.53", "10.0.0.1:5353", "::1", "[fd00::1]:53", "[::ffff:127.0.0.1]:54"})
+	require.NoError(t, err)
+	assert.Equal(t, []netip.AddrPort{
+		netip.MustParseAddrPort("127.0.0.53:53"),
+		netip.MustParseAddrPort("10.0.0.1:5353"),
+		netip.MustParseAddrPort("[::1]:53"),
+		netip.MustParseAddrPort("[fd00::1]:53"),
+		netip.MustParseAddrPort("127.0.0.1:54"),
+	}, addrs)
+
+	for _, invalid := range []string{"localhost", "10.0.0.1:0", "10.0.0.1:port", "10.0.0.1:70000"} {
+		_, err := ParseListenAddresses([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
+
+func freeUDPAddr(t *testing.T) netip.AddrPort {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).AddrPort()
+}
+
+func TestServiceViaListener_ExtraAddresses(t *testing.T) {
+	primary := freeUDPAddr(t)
+	extra := freeUDPAddr(t)
+
+	taken, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer taken.Close()
+	takenAddr := taken.LocalAddr().(*net.UDPAddr).AddrPort()
+
+	svc := newServiceViaListener(&mocWGIface{}, &primary, []netip.AddrPort{primary, extra, takenAddr})
+	svc.RegisterMux(".", dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		resp := &dns.Msg{}
+		resp.SetRcode(r, dns.RcodeNameError)
+		_ = w.WriteMsg(resp)
+	}))
+	require.NoError(t, svc.Listen())
+	defer svc.Stop()
+
+	addrs, fallbacks := svc.listenState()
+	require.GreaterOrEqual(t, len(addrs), 2)
+	assert.Equal(t, primary, addrs[0], "the address of the system resolver comes first")
+	assert.Equal(t, extra, addrs[1])
+	assert.NotContains(t, addrs, takenAddr)
+	require.Len(t, fallbacks, 1)
+	assert.Contains(t, fallbacks[0], takenAddr.String())
+
+	msg := &dns.Msg{}
+	msg.SetQuestion("example.com.", dns.TypeA)
+	resp, err := dns.Exchange(msg, extra.String())
+	require.NoError(t, err)
+	assert.Equal(t, dns.RcodeNameError, resp.Rcode)
+
+	svc.Stop()
+	addrs, fallbacks = svc.listenState()
+	assert.Empty(t, addrs)
+	assert.Empty(t, fallbacks)
+
+	conn, err := net.ListenPacket("udp", extra.String())
+	require.NoError(t, err, "the additional address is released on stop")
+	require.NoError(t, conn.Close())
+}
@@ -68,6 +68,8 @@ type upstreamResolverBase struct {
 	deactivate     func(error)
 	reactivate     func()
 	statusRecorder *peer.Status
+	// cache holds the upstream answers for the TTL of their records, nil disables caching
+	cache *responseCache
 }
 
 func newUpstreamResolverBase(ctx context.Context, statusRecorder *peer.Status, domain string) *upstreamResolverBase {
@@ -122,6 +124,10 @@ func (u *upstreamResolverBase) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 		return
 	}
 
+	if u.writeCachedResponse(w, r, logger) {
+		return
+	}
+
 	if u.tryUpstreamServers(w, r, logger) {
 		return
 	}
@@ -129,6 +135,24 @@ func (u *upstreamResolverBase) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 	u.writeErrorResponse(w, r, logger)
 }
 
+// writeCachedResponse answers the request from the cache, it returns false if there is no cached answer
+func (u *upstreamResolverBase) writeCachedResponse(w dns.ResponseWriter, r *dns.Msg, logger *log.Entry) bool {
+	if u.cache == nil {
+		return false
+	}
+
+	resp := u.cache.get(r)
+	if resp == nil {
+		return false
+	}
+
+	resutil.SetMeta(w, "cache", "hit")
+	if err := w.WriteMsg(resp); err != nil {
+		logger.Errorf("failed to write cached DNS response for question domain=%s: %s", r.Question[0].Name, err)
+	}
+	return true
+}
+
 func (u *upstreamResolverBase) prepareRequest(r *dns.Msg) {
 	if r.Extra == nil {
 		r.MsgHdr.AuthenticatedData = true
@@ -179,7 +203,11 @@ func (u *upstreamResolverBase) queryUpstream(w dns.ResponseWriter, r *dns.Msg, u
 		return false
 	}
 
-	return u.writeSuccessResponse(w, rm, upstream, r.Question[0].Name, t, logger)
+	written := u.writeSuccessResponse(w, rm, upstream, r.Question[0].Name, t, logger)
+	if u.cache != nil {
+		u.cache.set(r, rm)
+	}
+	return written
 }
 
 func (u *upstreamResolverBase) handleUpstreamError(err error, upstream netip.AddrPort, domain string, startTime time.Time, timeout time.Duration, logger *log.Entry) {
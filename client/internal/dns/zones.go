@@ -0,0 +1,150 @@
+package dns
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/miekg/dns"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/proto"
+	nbdns "github.com/netbirdio/netbird/dns"
+)
+
+// zoneLayout is the outcome of resolving the overlaps of the custom zones and the nameserver groups
+type zoneLayout struct {
+	zones []nbdns.CustomZone
+	// delegations are the domains within custom zones that are handled by nameserver groups, the local resolver
+	// passes the names it has no records for on to them
+	delegations []string
+	// conflicts describe the overlaps that dropped records or changed the authority of a zone
+	conflicts []string
+}
+
+// layoutZones makes the handling of overlapping custom zones independent of their order:
+//   - zones of the same domain are merged, the zone is authoritative if any of them is
+//   - a name belongs to the longest zone containing it, the records of outer zones within a nested zone are dropped
+//   - the domains of nameserver groups within a zone are delegated to the groups, the records of the zone at or below
+//     a delegated domain are dropped. A group of the zone domain itself gets the names the zone has no records for.
+func layoutZones(customZones []nbdns.CustomZone, nsGroups []*nbdns.NameServerGroup) zoneLayout {
+	var layout zoneLayout
+
+	merged := make(map[string]*nbdns.CustomZone)
+	var domains []string
+	for _, zone := range customZones {
+		zoneDomain := normalizeZone(zone.Domain)
+		existing, ok := merged[zoneDomain]
+		if !ok {
+			zone.Records = append([]nbdns.SimpleRecord(nil), zone.Records...)
+			merged[zoneDomain] = &zone
+			domains = append(domains, zoneDomain)
+			continue
+		}
+
+		if existing.NonAuthoritative != zone.NonAuthoritative {
+			layout.conflicts = append(layout.conflicts,
+				"zone "+zoneDomain+" is received as authoritative and non-authoritative, it is handled as authoritative")
+		}
+		existing.NonAuthoritative = existing.NonAuthoritative && zone.NonAuthoritative
+		existing.SearchDomainDisabled = existing.SearchDomainDisabled && zone.SearchDomainDisabled
+		existing.Records = append(existing.Records, zone.Records...)
+	}
+	sort.Strings(domains)
+
+	layout.delegations = zoneDelegations(domains, nsGroups)
+
+	for _, zoneDomain := range domains {
+		zone := merged[zoneDomain]
+		records := zone.Records[:0]
+		for _, record := range zone.Records {
+			if reason := shadowedRecord(record, zoneDomain, domains, layout.delegations); reason != "" {
+				layout.conflicts = append(layout.conflicts, "record "+record.String()+" of zone "+zoneDomain+" "+reason)
+				continue
+			}
+			records = append(records, record)
+		}
+		zone.Records = records
+		layout.zones = append(layout.zones, *zone)
+	}
+
+	return layout
+}
+
+// zoneDelegations returns the sorted domains of the nameserver groups that are equal to or within a custom zone
+func zoneDelegations(zoneDomains []string, nsGroups []*nbdns.NameServerGroup) []string {
+	delegated := make(map[string]struct{})
+	for _, nsGroup := range nsGroups {
+		for _, nsDomain := range nsGroup.Domains {
+			nsDomain = normalizeZone(nsDomain)
+			if nsDomain == nbdns.RootZone {
+				continue
+			}
+			for _, zoneDomain := range zoneDomains {
+				if dns.IsSubDomain(zoneDomain, nsDomain) {
+					delegated[nsDomain] = struct{}{}
+					break
+				}
+			}
+		}
+	}
+
+	delegations := make([]string, 0, len(delegated))
+	for nsDomain := range delegated {
+		delegations = append(delegations, nsDomain)
+	}
+	sort.Strings(delegations)
+	return delegations
+}
+
+// shadowedRecord returns why a record of a zone isn't served by it, or an empty string if it is
+func shadowedRecord(record nbdns.SimpleRecord, zoneDomain string, zoneDomains, delegations []string) string {
+	name := normalizeZone(record.Name)
+	if !dns.IsSubDomain(zoneDomain, name) {
+		// records outside of their zone are kept as before, they are only reachable through another zone
+		return ""
+	}
+
+	for _, other := range zoneDomains {
+		if other != zoneDomain && dns.IsSubDomain(zoneDomain, other) && dns.IsSubDomain(other, name) {
+			return "is shadowed by the nested zone " + other
+		}
+	}
+	for _, delegation := range delegations {
+		if delegation != zoneDomain && dns.IsSubDomain(zoneDomain, delegation) && dns.IsSubDomain(delegation, name) {
+			return "is shadowed by the delegation of " + delegation + " to a nameserver group"
+		}
+	}
+	return ""
+}
+
+// reportZoneConflicts logs the conflicts of the custom zones and publishes them as an event when they changed
+func (s *DefaultServer) reportZoneConflicts(conflicts []string) {
+	if slices.Equal(conflicts, s.zoneConflicts) {
+		return
+	}
+	s.zoneConflicts = conflicts
+	if len(conflicts) == 0 {
+		return
+	}
+
+	for _, conflict := range conflicts {
+		log.Warnf("DNS zone conflict: %s", conflict)
+	}
+
+	if s.statusRecorder == nil {
+		return
+	}
+	s.statusRecorder.PublishDedupEvent(
+		"dns-zone-conflicts/"+strings.Join(conflicts, ";"),
+		proto.SystemEvent_WARNING,
+		proto.SystemEvent_DNS,
+		"Overlapping DNS zones",
+		"Some DNS zones received from management overlap. Records shadowed by nested zones or nameserver groups are not served.",
+		map[string]string{"conflicts": strings.Join(conflicts, "\n")},
+	)
+}
+
+func normalizeZone(name string) string {
+	return strings.ToLower(dns.Fqdn(name))
+}
@@ -0,0 +1,78 @@
+package dns
+
+import (
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nbdns "github.com/netbirdio/netbird/dns"
+)
+
+func zoneRecord(name string) nbdns.SimpleRecord {
+	return nbdns.SimpleRecord{Name: name, Type: int(dns.TypeA), Class: nbdns.DefaultClass, TTL: 300, RData: "10.0.0.1"}
+}
+
+func TestLayoutZones(t *testing.T) {
+	zones := []nbdns.CustomZone{
+		{
+			Domain: "corp.example",
+			Records: []nbdns.SimpleRecord{
+				zoneRecord("app.corp.example."),
+				zoneRecord("db.dev.corp.example."),
+				zoneRecord("host.legacy.corp.example."),
+			},
+		},
+		{
+			Domain:           "dev.corp.example",
+			NonAuthoritative: true,
+			Records:          []nbdns.SimpleRecord{zoneRecord("api.dev.corp.example.")},
+		},
+		{
+			Domain:  "Dev.Corp.Example.",
+			Records: []nbdns.SimpleRecord{zoneRecord("web.dev.corp.example.")},
+		},
+	}
+	nsGroups := []*nbdns.NameServerGroup{
+		{Domains: []string{"legacy.corp.example", "other.example"}},
+		{Primary: true, Domains: []string{nbdns.RootZone}},
+	}
+
+	layout := layoutZones(zones, nsGroups)
+
+	require.Len(t, layout.zones, 2)
+	assert.Equal(t, "corp.example", layout.zones[0].Domain)
+	assert.Equal(t, []nbdns.SimpleRecord{zoneRecord("app.corp.example.")}, layout.zones[0].Records)
+
+	assert.Equal(t, "dev.corp.example", layout.zones[1].Domain)
+	assert.False(t, layout.zones[1].NonAuthoritative, "merged zone should be authoritative if any part is")
+	assert.Equal(t, []nbdns.SimpleRecord{zoneRecord("api.dev.corp.example."), zoneRecord("web.dev.corp.example.")},
+		layout.zones[1].Records)
+
+	assert.Equal(t, []string{"legacy.corp.example."}, layout.delegations)
+	assert.Len(t, layout.conflicts, 3)
+
+	// the layout doesn't depend on the order of the zones
+	reversed := []nbdns.CustomZone{zones[2], zones[1], zones[0]}
+	reversedLayout := layoutZones(reversed, nsGroups)
+	require.Len(t, reversedLayout.zones, 2)
+	assert.False(t, reversedLayout.zones[1].NonAuthoritative)
+	assert.Equal(t, layout.zones[0].Records, reversedLayout.zones[0].Records)
+	assert.Equal(t, layout.delegations, reversedLayout.delegations)
+}
+
+func TestLayoutZones_ZoneDelegatedAsWhole(t *testing.T) {
+	zones := []nbdns.CustomZone{{
+		Domain:  "corp.example",
+		Records: []nbdns.SimpleRecord{zoneRecord("app.corp.example.")},
+	}}
+	nsGroups := []*nbdns.NameServerGroup{{Domains: []string{"corp.example"}}}
+
+	layout := layoutZones(zones, nsGroups)
+
+	require.Len(t, layout.zones, 1)
+	assert.Len(t, layout.zones[0].Records, 1, "the records of a zone delegated as a whole should be kept")
+	assert.Equal(t, []string{"corp.example."}, layout.delegations)
+	assert.Empty(t, layout.conflicts)
+}
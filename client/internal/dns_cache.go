@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"errors"
+
+	"github.com/netbirdio/netbird/client/internal/dns"
+	"github.com/netbirdio/netbird/client/internal/dnsfwd"
+)
+
+var (
+	errDNSServerNotRunning    = errors.New("DNS server is not running")
+	errDNSForwarderNotRunning = errors.New("DNS forwarder is not running, this peer doesn't route domains")
+)
+
+// DNSCacheStats returns the counters of the cache of upstream DNS answers
+func (e *Engine) DNSCacheStats() (dns.CacheStats, error) {
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+
+	if e.dnsServer == nil {
+		return dns.CacheStats{}, errDNSServerNotRunning
+	}
+	return e.dnsServer.CacheStats(), nil
+}
+
+// FlushDNSCache drops the cached upstream DNS answers and returns how many were dropped
+func (e *Engine) FlushDNSCache() (int, error) {
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+
+	if e.dnsServer == nil {
+		return 0, errDNSServerNotRunning
+	}
+	return e.dnsServer.FlushCache(), nil
+}
+
+// DNSForwarderStats returns the counters of the cache and the upstream queries of the DNS forwarder
+func (e *Engine) DNSForwarderStats() (dnsfwd.Stats, error) {
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+
+	if e.dnsForwardMgr == nil {
+		return dnsfwd.Stats{}, errDNSForwarderNotRunning
+	}
+	stats, ok := e.dnsForwardMgr.Stats()
+	if !ok {
+		return dnsfwd.Stats{}, errDNSForwarderNotRunning
+	}
+	return stats, nil
+}
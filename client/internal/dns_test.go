@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nbdns "github.com/netbirdio/netbird/dns"
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+func TestAddOfflinePeerRecords(t *testing.T) {
+	config := nbdns.Config{
+		CustomZones: []nbdns.CustomZone{
+			{
+				Domain:           "example.com.",
+				NonAuthoritative: true,
+			},
+			{
+				Domain: "netbird.cloud.",
+				Records: []nbdns.SimpleRecord{
+					{
+						Name:  "online.netbird.cloud",
+						Type:  int(dns.TypeA),
+						Class: nbdns.DefaultClass,
+						TTL:   300,
+						RData: "100.64.0.2",
+					},
+				},
+			},
+		},
+	}
+
+	offlinePeers := []*mgmProto.RemotePeerConfig{
+		{WgPubKey: "existing", Fqdn: "Online.netbird.cloud", AllowedIps: []string{"100.64.0.2/32"}},
+		{WgPubKey: "offline", Fqdn: "offline.netbird.cloud", AllowedIps: []string{"100.64.0.3/32"}},
+		{WgPubKey: "foreign", Fqdn: "peer.example.com", AllowedIps: []string{"100.64.0.4/32"}},
+		{WgPubKey: "no-fqdn", AllowedIps: []string{"100.64.0.5/32"}},
+		{WgPubKey: "invalid", Fqdn: "invalid.netbird.cloud", AllowedIps: []string{"invalid"}},
+	}
+
+	addOfflinePeerRecords(&config, offlinePeers)
+
+	assert.Empty(t, config.CustomZones[0].Records, "non-authoritative zones should not be touched")
+
+	records := config.CustomZones[1].Records
+	require.Len(t, records, 2)
+	assert.Equal(t, nbdns.SimpleRecord{
+		Name:  "offline.netbird.cloud",
+		Type:  int(dns.TypeA),
+		Class: nbdns.DefaultClass,
+		TTL:   offlinePeerRecordTTL,
+		RData: "100.64.0.3",
+	}, records[1])
+}
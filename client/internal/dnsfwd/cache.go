@@ -1,73 +1,176 @@
 package dnsfwd
 
 import (
+	"container/list"
 	"net/netip"
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/miekg/dns"
 )
 
-type cache struct {
-	mu      sync.RWMutex
-	records map[string]*cacheEntry
+const (
+	// maxCacheEntries bounds the memory of the cache, the least recently used entries are evicted first
+	maxCacheEntries = 10000
+	// maxNegativeCacheTTL caps how long NXDOMAIN and NODATA answers are cached, the resolver doesn't expose the
+	// negative TTL of the zone
+	maxNegativeCacheTTL = 30 * time.Second
+)
+
+type cacheKey struct {
+	domain string
+	qtype  uint16
 }
 
 type cacheEntry struct {
-	ip4Addrs []netip.Addr
-	ip6Addrs []netip.Addr
+	key   cacheKey
+	addrs []netip.Addr
+	// rcode is the answer code, NXDOMAIN or NOERROR without addresses for negative answers
+	rcode   int
+	expires time.Time
 }
 
-func newCache() *cache {
+// cachedAnswer is a fresh answer from the cache
+type cachedAnswer struct {
+	addrs []netip.Addr
+	rcode int
+	// ttl is the remaining lifetime of the answer in seconds
+	ttl uint32
+}
+
+// cache keeps the resolved answers in LRU order. Fresh answers are served without asking the upstream, expired
+// ones are kept until they are evicted to be served when the upstream fails.
+type cache struct {
+	mu      sync.Mutex
+	entries map[cacheKey]*list.Element
+	lru     *list.List
+	// ttl is how long the answers with addresses are fresh, negativeTTL the one of the answers without
+	ttl         time.Duration
+	negativeTTL time.Duration
+}
+
+func newCache(ttl time.Duration) *cache {
 	return &cache{
-		records: make(map[string]*cacheEntry),
+		entries:     make(map[cacheKey]*list.Element),
+		lru:         list.New(),
+		ttl:         ttl,
+		negativeTTL: min(ttl, maxNegativeCacheTTL),
 	}
 }
 
+// get returns the cached addresses of the domain regardless of their age, negative answers have none
 func (c *cache) get(domain string, reqType uint16) ([]netip.Addr, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	entry, exists := c.records[normalizeDomain(domain)]
-	if !exists {
+	entry, ok := c.lookup(cacheKey{domain: normalizeDomain(domain), qtype: reqType})
+	if !ok {
 		return nil, false
 	}
+	return slices.Clone(entry.addrs), true
+}
 
-	switch reqType {
-	case dns.TypeA:
-		return slices.Clone(entry.ip4Addrs), true
-	case dns.TypeAAAA:
-		return slices.Clone(entry.ip6Addrs), true
-	default:
-		return nil, false
+// getFresh returns the cached answer of the domain if it didn't expire yet
+func (c *cache) getFresh(domain string, reqType uint16, now time.Time) (cachedAnswer, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.lookup(cacheKey{domain: normalizeDomain(domain), qtype: reqType})
+	if !ok || !now.Before(entry.expires) {
+		return cachedAnswer{}, false
 	}
 
+	return cachedAnswer{
+		addrs: slices.Clone(entry.addrs),
+		rcode: entry.rcode,
+		ttl:   uint32(max(entry.expires.Sub(now)/time.Second, 1)),
+	}, true
+}
+
+// lookup returns the entry of the key and marks it as recently used. Callers must hold the lock.
+func (c *cache) lookup(key cacheKey) (*cacheEntry, bool) {
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*cacheEntry), true
 }
 
+// set caches the addresses of the domain, no addresses cache a NODATA answer
 func (c *cache) set(domain string, reqType uint16, addrs []netip.Addr) {
+	c.store(domain, reqType, slices.Clone(addrs), dns.RcodeSuccess)
+}
+
+// setNegative caches an NXDOMAIN or NODATA answer of the domain
+func (c *cache) setNegative(domain string, reqType uint16, rcode int) {
+	c.store(domain, reqType, nil, rcode)
+}
+
+func (c *cache) store(domain string, reqType uint16, addrs []netip.Addr, rcode int) {
+	ttl := c.ttl
+	if len(addrs) == 0 {
+		ttl = c.negativeTTL
+	}
+
+	key := cacheKey{domain: normalizeDomain(domain), qtype: reqType}
+	entry := &cacheEntry{
+		key:     key,
+		addrs:   addrs,
+		rcode:   rcode,
+		expires: time.Now().Add(ttl),
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	norm := normalizeDomain(domain)
-	entry, exists := c.records[norm]
-	if !exists {
-		entry = &cacheEntry{}
-		c.records[norm] = entry
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return
 	}
 
-	switch reqType {
-	case dns.TypeA:
-		entry.ip4Addrs = slices.Clone(addrs)
-	case dns.TypeAAAA:
-		entry.ip6Addrs = slices.Clone(addrs)
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > maxCacheEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
 	}
 }
 
-// unset removes cached entries for the given domain and request type.
+// unset removes cached entries for the given domain.
 func (c *cache) unset(domain string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	delete(c.records, normalizeDomain(domain))
+
+	norm := normalizeDomain(domain)
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		key := cacheKey{domain: norm, qtype: qtype}
+		if elem, ok := c.entries[key]; ok {
+			c.lru.Remove(elem)
+			delete(c.entries, key)
+		}
+	}
+}
+
+// flush removes all cached answers and returns how many were removed
+func (c *cache) flush() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := c.lru.Len()
+	clear(c.entries)
+	c.lru.Init()
+	return n
+}
+
+func (c *cache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
 }
 
 // normalizeDomain converts an input domain into a canonical form used as cache key:
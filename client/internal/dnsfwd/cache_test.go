@@ -1,8 +1,14 @@
 package dnsfwd
 
 import (
+	"fmt"
 	"net/netip"
 	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func mustAddr(t *testing.T, s string) netip.Addr {
@@ -15,7 +21,7 @@ func mustAddr(t *testing.T, s string) netip.Addr {
 }
 
 func TestCacheNormalization(t *testing.T) {
-	c := newCache()
+	c := newCache(time.Minute)
 
 	// Mixed case, without trailing dot
 	domainInput := "ExAmPlE.CoM"
@@ -34,7 +40,7 @@ func TestCacheNormalization(t *testing.T) {
 }
 
 func TestCacheSeparateTypes(t *testing.T) {
-	c := newCache()
+	c := newCache(time.Minute)
 
 	domain := "test.local"
 	ipv4 := []netip.Addr{mustAddr(t, "10.0.0.1")}
@@ -55,7 +61,7 @@ func TestCacheSeparateTypes(t *testing.T) {
 }
 
 func TestCacheCloneOnGetAndSet(t *testing.T) {
-	c := newCache()
+	c := newCache(time.Minute)
 	domain := "clone.test"
 
 	src := []netip.Addr{mustAddr(t, "8.8.8.8")}
@@ -78,8 +84,63 @@ func TestCacheCloneOnGetAndSet(t *testing.T) {
 }
 
 func TestCacheMiss(t *testing.T) {
-	c := newCache()
+	c := newCache(time.Minute)
 	if got, ok := c.get("missing.example", 1); ok || got != nil {
 		t.Fatalf("expected cache miss, got=%v ok=%v", got, ok)
 	}
 }
+
+func TestCacheFreshness(t *testing.T) {
+	c := newCache(time.Minute)
+	now := time.Now()
+
+	c.set("fresh.test", dns.TypeA, []netip.Addr{mustAddr(t, "10.0.0.1")})
+	answer, ok := c.getFresh("fresh.test", dns.TypeA, now)
+	require.True(t, ok)
+	assert.Equal(t, dns.RcodeSuccess, answer.rcode)
+	assert.Equal(t, []netip.Addr{mustAddr(t, "10.0.0.1")}, answer.addrs)
+	assert.LessOrEqual(t, answer.ttl, uint32(60))
+
+	_, ok = c.getFresh("fresh.test", dns.TypeA, now.Add(2*time.Minute))
+	assert.False(t, ok, "expired answer should not be fresh")
+
+	addrs, ok := c.get("fresh.test", dns.TypeA)
+	assert.True(t, ok, "expired answer should stay available for upstream failures")
+	assert.Len(t, addrs, 1)
+}
+
+func TestCacheNegative(t *testing.T) {
+	c := newCache(5 * time.Minute)
+	now := time.Now()
+
+	c.setNegative("missing.test", dns.TypeA, dns.RcodeNameError)
+	answer, ok := c.getFresh("missing.test", dns.TypeA, now)
+	require.True(t, ok)
+	assert.Equal(t, dns.RcodeNameError, answer.rcode)
+	assert.Empty(t, answer.addrs)
+
+	_, ok = c.getFresh("missing.test", dns.TypeA, now.Add(maxNegativeCacheTTL+time.Second))
+	assert.False(t, ok, "negative answers should expire after the negative TTL")
+}
+
+func TestCacheLRUEviction(t *testing.T) {
+	c := newCache(time.Minute)
+	addrs := []netip.Addr{mustAddr(t, "10.0.0.1")}
+
+	for i := range maxCacheEntries {
+		c.set(fmt.Sprintf("host%d.test", i), dns.TypeA, addrs)
+	}
+	// the first entry was used recently, so the second one is the oldest
+	_, ok := c.get("host0.test", dns.TypeA)
+	require.True(t, ok)
+
+	c.set("new.test", dns.TypeA, addrs)
+	assert.Equal(t, maxCacheEntries, c.len())
+
+	_, ok = c.get("host0.test", dns.TypeA)
+	assert.True(t, ok, "recently used entry should be kept")
+	_, ok = c.get("host1.test", dns.TypeA)
+	assert.False(t, ok, "least recently used entry should be evicted")
+	_, ok = c.get("new.test", dns.TypeA)
+	assert.True(t, ok)
+}
@@ -49,6 +49,7 @@ type DNSForwarder struct {
 	firewall   firewaller
 	resolver   resolver
 	cache      *cache
+	stats      forwarderStats
 
 	wgIface wgIface
 }
@@ -61,7 +62,7 @@ func NewDNSForwarder(listenAddress netip.AddrPort, ttl uint32, firewall firewall
 		firewall:       firewall,
 		statusRecorder: statusRecorder,
 		resolver:       net.DefaultResolver,
-		cache:          newCache(),
+		cache:          newCache(time.Duration(ttl) * time.Second),
 		wgIface:        wgIface,
 	}
 }
@@ -220,10 +221,23 @@ func (f *DNSForwarder) handleDNSQuery(logger *log.Entry, w dns.ResponseWriter, q
 		return nil
 	}
 
+	if answer, ok := f.cache.getFresh(domain, question.Qtype, time.Now()); ok {
+		f.stats.cacheHit(len(answer.addrs) == 0)
+		resp.Rcode = answer.rcode
+		if len(answer.addrs) > 0 {
+			f.updateInternalState(answer.addrs, mostSpecificResId, matchingEntries)
+			resp.Answer = append(resp.Answer, resutil.IPsToRRs(domain, answer.addrs, answer.ttl)...)
+		}
+		return resp
+	}
+	f.stats.cacheMisses.Add(1)
+
 	ctx, cancel := context.WithTimeout(context.Background(), upstreamTimeout)
 	defer cancel()
 
+	start := time.Now()
 	result := resutil.LookupIP(ctx, f.resolver, network, domain, question.Qtype)
+	f.stats.upstreamQuery(time.Since(start), !isAnswer(result))
 	if result.Err != nil {
 		f.handleDNSError(ctx, logger, w, question, resp, domain, result)
 		return nil
@@ -341,8 +355,8 @@ func (f *DNSForwarder) handleDNSError(
 	resp.Rcode = result.Rcode
 
 	// NotFound: cache negative result and respond
-	if result.Rcode == dns.RcodeNameError || result.Rcode == dns.RcodeSuccess {
-		f.cache.set(domain, question.Qtype, nil)
+	if isAnswer(result) {
+		f.cache.setNegative(domain, question.Qtype, result.Rcode)
 		if writeErr := w.WriteMsg(resp); writeErr != nil {
 			logger.Errorf("failed to write failure DNS response: %v", writeErr)
 		}
@@ -353,6 +367,7 @@ func (f *DNSForwarder) handleDNSError(
 	if ips, ok := f.cache.get(domain, qType); ok {
 		if len(ips) > 0 {
 			logger.Debugf("serving cached DNS response after upstream failure: domain=%s type=%s", domain, qTypeName)
+			f.stats.staleAnswers.Add(1)
 			resp.Answer = append(resp.Answer, resutil.IPsToRRs(domain, ips, f.ttl)...)
 			resp.Rcode = dns.RcodeSuccess
 			if writeErr := w.WriteMsg(resp); writeErr != nil {
@@ -362,8 +377,10 @@ func (f *DNSForwarder) handleDNSError(
 		}
 
 		// Cached negative result - re-verify NXDOMAIN vs NODATA
+		start := time.Now()
 		verifyResult := resutil.LookupIP(ctx, f.resolver, resutil.NetworkForQtype(qType), domain, qType)
-		if verifyResult.Rcode == dns.RcodeNameError || verifyResult.Rcode == dns.RcodeSuccess {
+		f.stats.upstreamQuery(time.Since(start), !isAnswer(verifyResult))
+		if isAnswer(verifyResult) {
 			resp.Rcode = verifyResult.Rcode
 			if writeErr := w.WriteMsg(resp); writeErr != nil {
 				logger.Errorf("failed to write failure DNS response: %v", writeErr)
@@ -386,6 +403,18 @@ func (f *DNSForwarder) handleDNSError(
 	}
 }
 
+// Stats returns the counters of the cache and the upstream queries
+func (f *DNSForwarder) Stats() Stats {
+	stats := f.stats.snapshot()
+	stats.CacheEntries = f.cache.len()
+	return stats
+}
+
+// isAnswer reports whether the upstream answered the lookup, possibly with NXDOMAIN or NODATA
+func isAnswer(result resutil.LookupResult) bool {
+	return result.Rcode == dns.RcodeNameError || result.Rcode == dns.RcodeSuccess
+}
+
 // getMatchingEntries retrieves the resource IDs for a given domain.
 // It returns the most specific match and all matching resource IDs.
 func (f *DNSForwarder) getMatchingEntries(domain string) (route.ResID, []*ForwarderEntry) {
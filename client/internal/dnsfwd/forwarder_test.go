@@ -681,6 +681,7 @@ func TestDNSForwarder_ServeFromCacheOnUpstreamFailure(t *testing.T) {
 	require.Len(t, resp1.Answer, 1)
 
 	// Second query: serve from cache after upstream failure
+	expireCache(forwarder)
 	q2 := &dns.Msg{}
 	q2.SetQuestion(dns.Fqdn("example.com"), dns.TypeA)
 	var writtenResp *dns.Msg
@@ -725,6 +726,7 @@ func TestDNSForwarder_CacheNormalizationCasingAndDot(t *testing.T) {
 	mockResolver.On("LookupNetIP", mock.Anything, "ip4", strings.ToLower("EXAMPLE.COM")).
 		Return([]netip.Addr{}, &net.DNSError{Err: "temporary failure"}).Once()
 
+	expireCache(forwarder)
 	q2 := &dns.Msg{}
 	q2.SetQuestion("EXAMPLE.COM", dns.TypeA)
 	var writtenResp *dns.Msg
@@ -738,6 +740,69 @@ func TestDNSForwarder_CacheNormalizationCasingAndDot(t *testing.T) {
 	mockResolver.AssertExpectations(t)
 }
 
+// Verifies that fresh answers, including negative ones, are served from the cache without asking the upstream.
+func TestDNSForwarder_ServeFromFreshCache(t *testing.T) {
+	mockResolver := &MockResolver{}
+	forwarder := NewDNSForwarder(netip.MustParseAddrPort("127.0.0.1:0"), 300, nil, &peer.Status{}, nil)
+	forwarder.resolver = mockResolver
+
+	d, err := domain.FromString("*.example.com")
+	require.NoError(t, err)
+	forwarder.UpdateDomains([]*ForwarderEntry{{Domain: d, ResID: "res-fresh"}})
+
+	mockResolver.On("LookupNetIP", mock.Anything, "ip4", "app.example.com.").
+		Return([]netip.Addr{netip.MustParseAddr("1.2.3.4")}, nil).Once()
+	mockResolver.On("LookupNetIP", mock.Anything, "ip4", "missing.example.com.").
+		Return([]netip.Addr{}, &net.DNSError{IsNotFound: true, Name: "missing.example.com"}).Once()
+	mockResolver.On("LookupNetIP", mock.Anything, "ip6", "missing.example.com.").
+		Return([]netip.Addr{}, &net.DNSError{IsNotFound: true, Name: "missing.example.com"}).Once()
+
+	query := func(name string) *dns.Msg {
+		q := &dns.Msg{}
+		q.SetQuestion(name, dns.TypeA)
+		var written *dns.Msg
+		w := &test.MockResponseWriter{WriteMsgFunc: func(m *dns.Msg) error { written = m; return nil }}
+		if resp := forwarder.handleDNSQuery(log.NewEntry(log.StandardLogger()), w, q); resp != nil {
+			return resp
+		}
+		return written
+	}
+
+	for range 3 {
+		resp := query("app.example.com.")
+		require.NotNil(t, resp)
+		require.Equal(t, dns.RcodeSuccess, resp.Rcode)
+		require.Len(t, resp.Answer, 1)
+		assert.LessOrEqual(t, resp.Answer[0].Header().Ttl, uint32(300))
+
+		resp = query("missing.example.com.")
+		require.NotNil(t, resp)
+		require.Equal(t, dns.RcodeNameError, resp.Rcode)
+		require.Empty(t, resp.Answer)
+	}
+
+	mockResolver.AssertExpectations(t)
+
+	stats := forwarder.Stats()
+	assert.Equal(t, 2, stats.CacheEntries)
+	assert.Equal(t, uint64(4), stats.CacheHits)
+	assert.Equal(t, uint64(2), stats.NegativeHits)
+	assert.Equal(t, uint64(2), stats.CacheMisses)
+	assert.Equal(t, uint64(2), stats.UpstreamQueries)
+	assert.Zero(t, stats.UpstreamFailures)
+	assert.InDelta(t, 4.0/6.0, stats.HitRatio(), 0.001)
+}
+
+// expireCache marks the cached answers as expired, so the next queries go to the upstream
+func expireCache(f *DNSForwarder) {
+	f.cache.mu.Lock()
+	defer f.cache.mu.Unlock()
+
+	for _, elem := range f.cache.entries {
+		elem.Value.(*cacheEntry).expires = time.Time{}
+	}
+}
+
 func TestDNSForwarder_MultipleOverlappingPatterns(t *testing.T) {
 	// Test complex overlapping pattern scenarios
 	mockFirewall := &MockFirewall{}
@@ -891,6 +956,7 @@ func TestDNSForwarder_NodataVsNxdomain(t *testing.T) {
 			mockResolver.Calls = nil
 			mockFirewall.ExpectedCalls = nil
 			mockFirewall.Calls = nil
+			forwarder.cache.flush()
 
 			tt.setupMocks()
 
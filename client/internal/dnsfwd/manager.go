@@ -117,6 +117,14 @@ func (m *Manager) UpdateDomains(entries []*ForwarderEntry) {
 	m.dnsForwarder.UpdateDomains(entries)
 }
 
+// Stats returns the counters of the cache and the upstream queries of the forwarder, false if it isn't running
+func (m *Manager) Stats() (Stats, bool) {
+	if m.dnsForwarder == nil {
+		return Stats{}, false
+	}
+	return m.dnsForwarder.Stats(), true
+}
+
 func (m *Manager) Stop(ctx context.Context) error {
 	if m.dnsForwarder == nil {
 		return nil
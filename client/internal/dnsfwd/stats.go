@@ -0,0 +1,81 @@
+package dnsfwd
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats are the counters of the DNS forwarder since it started
+type Stats struct {
+	CacheEntries int
+	// CacheHits are the queries answered from fresh cache entries, NegativeHits the ones of them without addresses
+	CacheHits    uint64
+	NegativeHits uint64
+	CacheMisses  uint64
+	// StaleAnswers are the queries answered from expired cache entries because the upstream failed
+	StaleAnswers     uint64
+	UpstreamQueries  uint64
+	UpstreamFailures uint64
+	// UpstreamLatency is the average duration of the upstream queries, UpstreamLatencyMax the longest
+	UpstreamLatency    time.Duration
+	UpstreamLatencyMax time.Duration
+}
+
+// HitRatio returns the share of the queries answered from fresh cache entries
+func (s Stats) HitRatio() float64 {
+	total := s.CacheHits + s.CacheMisses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.CacheHits) / float64(total)
+}
+
+type forwarderStats struct {
+	cacheHits        atomic.Uint64
+	negativeHits     atomic.Uint64
+	cacheMisses      atomic.Uint64
+	staleAnswers     atomic.Uint64
+	upstreamQueries  atomic.Uint64
+	upstreamFailures atomic.Uint64
+	// the durations of the upstream queries in nanoseconds
+	upstreamLatencySum atomic.Int64
+	upstreamLatencyMax atomic.Int64
+}
+
+func (s *forwarderStats) cacheHit(negative bool) {
+	s.cacheHits.Add(1)
+	if negative {
+		s.negativeHits.Add(1)
+	}
+}
+
+func (s *forwarderStats) upstreamQuery(latency time.Duration, failed bool) {
+	s.upstreamQueries.Add(1)
+	if failed {
+		s.upstreamFailures.Add(1)
+	}
+
+	s.upstreamLatencySum.Add(int64(latency))
+	for {
+		current := s.upstreamLatencyMax.Load()
+		if int64(latency) <= current || s.upstreamLatencyMax.CompareAndSwap(current, int64(latency)) {
+			return
+		}
+	}
+}
+
+func (s *forwarderStats) snapshot() Stats {
+	stats := Stats{
+		CacheHits:          s.cacheHits.Load(),
+		NegativeHits:       s.negativeHits.Load(),
+		CacheMisses:        s.cacheMisses.Load(),
+		StaleAnswers:       s.staleAnswers.Load(),
+		UpstreamQueries:    s.upstreamQueries.Load(),
+		UpstreamFailures:   s.upstreamFailures.Load(),
+		UpstreamLatencyMax: time.Duration(s.upstreamLatencyMax.Load()),
+	}
+	if stats.UpstreamQueries > 0 {
+		stats.UpstreamLatency = time.Duration(s.upstreamLatencySum.Load() / int64(stats.UpstreamQueries))
+	}
+	return stats
+}
@@ -0,0 +1,214 @@
+package dnspublish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/libdns/libdns"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	cloudflareAPI = "https://api.cloudflare.com/client/v4"
+	// cloudflareMinTTL is the lowest TTL Cloudflare accepts besides automatic
+	cloudflareMinTTL = 60
+)
+
+// cloudflareProvider updates the zone through the DNS records API of Cloudflare
+type cloudflareProvider struct {
+	baseURL    string
+	apiToken   string
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	zoneID string
+}
+
+type cloudflareRecord struct {
+	ID      string `json:"id,omitempty"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	TTL     int    `json:"ttl"`
+	Proxied bool   `json:"proxied"`
+}
+
+type cloudflareResponse struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+	Result json.RawMessage `json:"result"`
+}
+
+func newCloudflareProvider(settings map[string]string) *cloudflareProvider {
+	return &cloudflareProvider{
+		baseURL:    cloudflareAPI,
+		apiToken:   settings["api_token"],
+		zoneID:     settings["zone_id"],
+		httpClient: &http.Client{},
+	}
+}
+
+// SetRecords updates the existing record of each name and type or creates it. Further records of the name and type
+// are deleted.
+func (p *cloudflareProvider) SetRecords(ctx context.Context, zone string, recs []libdns.Record) ([]libdns.Record, error) {
+	zoneID, err := p.getZoneID(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rec := range recs {
+		record := toCloudflareRecord(zone, rec)
+		existing, err := p.listRecords(ctx, zoneID, record)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(existing) == 0 {
+			if err := p.do(ctx, http.MethodPost, "/zones/"+zoneID+"/dns_records", record, nil); err != nil {
+				return nil, fmt.Errorf("create record %s: %w", record.Name, err)
+			}
+			continue
+		}
+
+		if err := p.do(ctx, http.MethodPut, "/zones/"+zoneID+"/dns_records/"+existing[0].ID, record, nil); err != nil {
+			return nil, fmt.Errorf("update record %s: %w", record.Name, err)
+		}
+		for _, stale := range existing[1:] {
+			if err := p.do(ctx, http.MethodDelete, "/zones/"+zoneID+"/dns_records/"+stale.ID, nil, nil); err != nil {
+				return nil, fmt.Errorf("delete record %s: %w", record.Name, err)
+			}
+		}
+	}
+	return recs, nil
+}
+
+// DeleteRecords deletes the records with the name, type and value, records that don't exist are ignored
+func (p *cloudflareProvider) DeleteRecords(ctx context.Context, zone string, recs []libdns.Record) ([]libdns.Record, error) {
+	zoneID, err := p.getZoneID(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rec := range recs {
+		record := toCloudflareRecord(zone, rec)
+		existing, err := p.listRecords(ctx, zoneID, record)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range existing {
+			if r.Content != record.Content {
+				continue
+			}
+			if err := p.do(ctx, http.MethodDelete, "/zones/"+zoneID+"/dns_records/"+r.ID, nil, nil); err != nil {
+				return nil, fmt.Errorf("delete record %s: %w", record.Name, err)
+			}
+		}
+	}
+	return recs, nil
+}
+
+// getZoneID returns the configured zone ID or looks it up by the zone name
+func (p *cloudflareProvider) getZoneID(ctx context.Context, zone string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.zoneID != "" {
+		return p.zoneID, nil
+	}
+
+	name := strings.TrimSuffix(zone, ".")
+	var zones []struct {
+		ID string `json:"id"`
+	}
+	if err := p.do(ctx, http.MethodGet, "/zones?name="+url.QueryEscape(name), nil, &zones); err != nil {
+		return "", fmt.Errorf("look up zone %s: %w", name, err)
+	}
+	if len(zones) == 0 {
+		return "", fmt.Errorf("zone %s not found", name)
+	}
+
+	p.zoneID = zones[0].ID
+	log.Debugf("found the Cloudflare zone ID %s of %s", p.zoneID, name)
+	return p.zoneID, nil
+}
+
+func (p *cloudflareProvider) listRecords(ctx context.Context, zoneID string, record cloudflareRecord) ([]cloudflareRecord, error) {
+	query := url.Values{"type": {record.Type}, "name": {record.Name}}
+	var records []cloudflareRecord
+	if err := p.do(ctx, http.MethodGet, "/zones/"+zoneID+"/dns_records?"+query.Encode(), nil, &records); err != nil {
+		return nil, fmt.Errorf("list records %s: %w", record.Name, err)
+	}
+	return records, nil
+}
+
+// do sends the request to the API and decodes the result into out if set
+func (p *cloudflareProvider) do(ctx context.Context, method, path string, body, out any) error {
+	var reqBody io.Reader = http.NoBody
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshal request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.apiToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Debugf("failed to close response body: %v", err)
+		}
+	}()
+
+	var apiResp cloudflareResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return fmt.Errorf("decode response (%s): %w", resp.Status, err)
+	}
+	if !apiResp.Success {
+		var msgs []string
+		for _, e := range apiResp.Errors {
+			msgs = append(msgs, fmt.Sprintf("%d: %s", e.Code, e.Message))
+		}
+		if len(msgs) == 0 {
+			return errors.New(resp.Status)
+		}
+		return fmt.Errorf("%s: %s", resp.Status, strings.Join(msgs, ", "))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(apiResp.Result, out); err != nil {
+		return fmt.Errorf("decode result: %w", err)
+	}
+	return nil
+}
+
+func toCloudflareRecord(zone string, rec libdns.Record) cloudflareRecord {
+	return cloudflareRecord{
+		Type:    rec.Type,
+		Name:    rec.Name + "." + strings.TrimSuffix(zone, "."),
+		Content: rec.Value,
+		TTL:     max(int(rec.TTL.Seconds()), cloudflareMinTTL),
+	}
+}
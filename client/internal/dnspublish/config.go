@@ -0,0 +1,72 @@
+package dnspublish
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	ProviderRFC2136    = "rfc2136"
+	ProviderRoute53    = "route53"
+	ProviderCloudflare = "cloudflare"
+
+	// defaultTTL is the TTL of the published records if the config doesn't set one
+	defaultTTL = 5 * time.Minute
+)
+
+// Config configures the publisher mirroring the peer names into an external DNS zone. A peer with the FQDN
+// peer-a.netbird.cloud is published as peer-a.<Zone> with its NetBird IP.
+type Config struct {
+	// Provider is the DNS provider of the zone: rfc2136, route53 or cloudflare
+	Provider string
+	// Zone is the zone the records are published in, e.g. netbird.corp.example
+	Zone string
+	// TTL is the TTL of the published records. Zero means default (5m).
+	TTL time.Duration
+	// Settings are the settings of the provider:
+	//   rfc2136: server (host:port), tsig_key, tsig_secret, tsig_algorithm (hmac-sha256 if empty)
+	//   route53: region, profile, access_key_id, secret_access_key, hosted_zone_id, the environment if empty
+	//   cloudflare: api_token, zone_id (looked up by the zone name if empty)
+	Settings map[string]string
+}
+
+// Validate checks the provider, the zone and the TTL of the config
+func (c Config) Validate() error {
+	switch c.Provider {
+	case ProviderRFC2136:
+		if c.Settings["server"] == "" {
+			return fmt.Errorf("%s provider requires the server setting", c.Provider)
+		}
+	case ProviderRoute53:
+	case ProviderCloudflare:
+		if c.Settings["api_token"] == "" {
+			return fmt.Errorf("%s provider requires the api_token setting", c.Provider)
+		}
+	default:
+		return fmt.Errorf("unsupported DNS provider %q, use one of: %s, %s, %s",
+			c.Provider, ProviderRFC2136, ProviderRoute53, ProviderCloudflare)
+	}
+
+	if _, ok := dns.IsDomainName(c.Zone); !ok || strings.Trim(c.Zone, ".") == "" {
+		return fmt.Errorf("invalid zone %q", c.Zone)
+	}
+	if c.TTL < 0 {
+		return fmt.Errorf("invalid TTL %s", c.TTL)
+	}
+	return nil
+}
+
+// zone returns the zone as FQDN with the trailing dot
+func (c Config) zone() string {
+	return dns.Fqdn(strings.ToLower(c.Zone))
+}
+
+func (c Config) ttl() time.Duration {
+	if c.TTL > 0 {
+		return c.TTL
+	}
+	return defaultTTL
+}
@@ -0,0 +1,313 @@
+// Package dnspublish mirrors the names of the NetBird peers into an external DNS zone, like external-dns does for
+// Kubernetes services, so the peers resolve in the corporate DNS as well.
+package dnspublish
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libdns/libdns"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+const (
+	// retryInterval is how often the changes that failed are retried and the records are reconciled with the peers
+	retryInterval = 30 * time.Second
+	// maxAttempts is how often a change is tried before it is dropped, e.g. deleting a record that doesn't exist
+	maxAttempts = 5
+	// maxPending limits the changes queued while the provider is unreachable, further changes are dropped
+	maxPending = 4096
+	// updateTimeout limits a single update of the zone
+	updateTimeout = 30 * time.Second
+)
+
+// Provider updates the records of a zone, the libdns providers implement it
+type Provider interface {
+	libdns.RecordSetter
+	libdns.RecordDeleter
+}
+
+// change publishes or removes the record of a peer
+type change struct {
+	remove   bool
+	record   libdns.Record
+	attempts int
+}
+
+func (c change) String() string {
+	action := "publish"
+	if c.remove {
+		action = "remove"
+	}
+	return fmt.Sprintf("%s %s %s %s", action, c.record.Name, c.record.Type, c.record.Value)
+}
+
+// Publisher publishes the records of the peers added to the network and removes the records of the removed peers.
+// The changes are applied in order, the records stay in place while the engine is down. Changes missed while
+// events were dropped are caught up by reconciling the published records with the peers periodically.
+type Publisher struct {
+	config         Config
+	provider       Provider
+	statusRecorder *peer.Status
+
+	sub    *peer.EventSubscription
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	pending []change
+	// published are the records published by this publisher, keyed by name
+	published map[string]libdns.Record
+	notify    chan struct{}
+}
+
+// NewPublisher returns a publisher for the provider of the config
+func NewPublisher(config Config, statusRecorder *peer.Status) (*Publisher, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	provider, err := newProvider(config)
+	if err != nil {
+		return nil, fmt.Errorf("create %s provider: %w", config.Provider, err)
+	}
+
+	return newPublisher(config, provider, statusRecorder), nil
+}
+
+func newPublisher(config Config, provider Provider, statusRecorder *peer.Status) *Publisher {
+	return &Publisher{
+		config:         config,
+		provider:       provider,
+		statusRecorder: statusRecorder,
+		published:      make(map[string]libdns.Record),
+		notify:         make(chan struct{}, 1),
+	}
+}
+
+func newProvider(config Config) (Provider, error) {
+	switch config.Provider {
+	case ProviderRFC2136:
+		return newRFC2136Provider(config.Settings), nil
+	case ProviderRoute53:
+		return newRoute53Provider(config.Settings), nil
+	case ProviderCloudflare:
+		return newCloudflareProvider(config.Settings), nil
+	default:
+		return nil, fmt.Errorf("unsupported provider %q", config.Provider)
+	}
+}
+
+// Start publishes the records of the known peers and follows the peers joining and leaving the network
+func (p *Publisher) Start(ctx context.Context) {
+	ctx, p.cancel = context.WithCancel(ctx)
+	p.sub = p.statusRecorder.SubscribeToEvents(peer.EventFilter{Categories: []proto.SystemEvent_Category{proto.SystemEvent_CONNECTIVITY}})
+
+	p.reconcile()
+	p.signal()
+
+	p.wg.Add(2)
+	go func() {
+		defer p.wg.Done()
+		p.receive(ctx)
+	}()
+	go func() {
+		defer p.wg.Done()
+		p.run(ctx)
+	}()
+
+	log.Infof("publishing the peer names to the %s zone %s", p.config.Provider, p.config.zone())
+}
+
+// Close stops publishing. The pending changes are dropped, the published records are kept.
+func (p *Publisher) Close() {
+	if p.sub == nil {
+		return
+	}
+
+	p.statusRecorder.UnsubscribeFromEvents(p.sub)
+	p.cancel()
+	p.wg.Wait()
+	p.sub = nil
+}
+
+func (p *Publisher) receive(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-p.sub.Events():
+			if !ok {
+				return
+			}
+			meta := e.GetMetadata()
+			switch meta[peer.EventTypeKey] {
+			case peer.EventPeerAdded:
+				p.enqueue(false, meta["fqdn"], meta["ip"])
+			case peer.EventPeerRemoved:
+				p.enqueue(true, meta["fqdn"], meta["ip"])
+			}
+		}
+	}
+}
+
+// enqueue queues the change of the record of the peer, peers without a name or address are skipped
+func (p *Publisher) enqueue(remove bool, fqdn, ip string) {
+	record, ok := p.recordOf(fqdn, ip)
+	if !ok {
+		return
+	}
+
+	p.mu.Lock()
+	p.enqueueLocked(change{remove: remove, record: record})
+	p.mu.Unlock()
+	p.signal()
+}
+
+// enqueueLocked queues the change, the caller must hold mu
+func (p *Publisher) enqueueLocked(c change) {
+	if len(p.pending) >= maxPending {
+		log.Warnf("DNS publisher queue full, dropping: %s", c)
+		return
+	}
+	p.pending = append(p.pending, c)
+}
+
+func (p *Publisher) signal() {
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+}
+
+// recordOf returns the record of the peer: the first label of its FQDN in the zone of the config
+func (p *Publisher) recordOf(fqdn, ip string) (libdns.Record, bool) {
+	label, _, _ := strings.Cut(strings.ToLower(fqdn), ".")
+	addr, err := netip.ParseAddr(ip)
+	if label == "" || err != nil {
+		return libdns.Record{}, false
+	}
+
+	recordType := "A"
+	if addr.Unmap().Is6() {
+		recordType = "AAAA"
+	}
+	return libdns.Record{
+		Type:  recordType,
+		Name:  label,
+		Value: addr.Unmap().String(),
+		TTL:   p.config.ttl(),
+	}, true
+}
+
+func (p *Publisher) run(ctx context.Context) {
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.notify:
+		case <-ticker.C:
+			p.reconcile()
+		}
+		p.flush(ctx)
+	}
+}
+
+// flush applies the pending changes in order. It stops at the first failure, so a later change of the same
+// record can't be overtaken by the retry of an earlier one.
+func (p *Publisher) flush(ctx context.Context) {
+	for ctx.Err() == nil {
+		p.mu.Lock()
+		if len(p.pending) == 0 {
+			p.mu.Unlock()
+			return
+		}
+		c := p.pending[0]
+		p.mu.Unlock()
+
+		err := p.apply(ctx, c)
+
+		p.mu.Lock()
+		retry := false
+		switch {
+		case err == nil:
+			log.Debugf("DNS publisher: %s", c)
+			p.pending = p.pending[1:]
+			p.updatePublished(c)
+		case c.attempts+1 >= maxAttempts:
+			log.Errorf("DNS publisher: giving up to %s after %d attempts: %v", c, maxAttempts, err)
+			p.pending = p.pending[1:]
+		default:
+			log.Warnf("DNS publisher: failed to %s, retrying in %s: %v", c, retryInterval, err)
+			p.pending[0].attempts++
+			retry = true
+		}
+		p.mu.Unlock()
+
+		if retry {
+			return
+		}
+	}
+}
+
+// updatePublished records the applied change, the caller must hold mu
+func (p *Publisher) updatePublished(c change) {
+	if !c.remove {
+		p.published[c.record.Name] = c.record
+		return
+	}
+	if published, ok := p.published[c.record.Name]; ok && published.Value == c.record.Value {
+		delete(p.published, c.record.Name)
+	}
+}
+
+// reconcile queues the changes that bring the published records in line with the peers, e.g. after events were
+// dropped. It waits until the queued changes are applied.
+func (p *Publisher) reconcile() {
+	desired := make(map[string]libdns.Record)
+	for _, state := range p.statusRecorder.GetFullStatus().Peers {
+		if record, ok := p.recordOf(state.FQDN, state.IP); ok {
+			desired[record.Name] = record
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.pending) > 0 {
+		return
+	}
+	for name, record := range desired {
+		if published, ok := p.published[name]; !ok || published.Value != record.Value {
+			p.enqueueLocked(change{record: record})
+		}
+	}
+	for name, published := range p.published {
+		if _, ok := desired[name]; !ok {
+			p.enqueueLocked(change{remove: true, record: published})
+		}
+	}
+}
+
+func (p *Publisher) apply(ctx context.Context, c change) error {
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	var err error
+	if c.remove {
+		_, err = p.provider.DeleteRecords(ctx, p.config.zone(), []libdns.Record{c.record})
+	} else {
+		_, err = p.provider.SetRecords(ctx, p.config.zone(), []libdns.Record{c.record})
+	}
+	return err
+}
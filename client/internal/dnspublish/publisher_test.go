@@ -0,0 +1,140 @@
+package dnspublish
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+)
+
+type fakeProvider struct {
+	mu      sync.Mutex
+	records map[string]string
+	fail    bool
+}
+
+func (f *fakeProvider) SetRecords(_ context.Context, _ string, recs []libdns.Record) ([]libdns.Record, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fail {
+		return nil, errors.New("unreachable")
+	}
+	for _, rec := range recs {
+		f.records[rec.Name] = rec.Value
+	}
+	return recs, nil
+}
+
+func (f *fakeProvider) DeleteRecords(_ context.Context, _ string, recs []libdns.Record) ([]libdns.Record, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fail {
+		return nil, errors.New("unreachable")
+	}
+	for _, rec := range recs {
+		if f.records[rec.Name] == rec.Value {
+			delete(f.records, rec.Name)
+		}
+	}
+	return recs, nil
+}
+
+func (f *fakeProvider) snapshot() map[string]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	records := make(map[string]string, len(f.records))
+	for name, value := range f.records {
+		records[name] = value
+	}
+	return records
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "rfc2136", cfg: Config{Provider: ProviderRFC2136, Zone: "corp.example", Settings: map[string]string{"server": "10.0.0.53"}}},
+		{name: "route53", cfg: Config{Provider: ProviderRoute53, Zone: "corp.example"}},
+		{name: "no zone", cfg: Config{Provider: ProviderRoute53}, wantErr: true},
+		{name: "unsupported provider", cfg: Config{Provider: "bind", Zone: "corp.example"}, wantErr: true},
+		{name: "negative ttl", cfg: Config{Provider: ProviderRoute53, Zone: "corp.example", TTL: -time.Second}, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPublisher_FollowsPeers(t *testing.T) {
+	recorder := peer.NewRecorder("https://mgm")
+	require.NoError(t, recorder.AddPeer("key-a", "peer-a.netbird.cloud", "100.64.0.1"))
+
+	provider := &fakeProvider{records: make(map[string]string)}
+	publisher := newPublisher(Config{Provider: ProviderRFC2136, Zone: "corp.example"}, provider, recorder)
+	publisher.Start(context.Background())
+	defer publisher.Close()
+
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual(map[string]string{"peer-a": "100.64.0.1"}, provider.snapshot())
+	}, time.Second, 10*time.Millisecond, "known peers are published on start")
+
+	require.NoError(t, recorder.AddPeer("key-b", "peer-b.netbird.cloud", "100.64.0.2"))
+	require.NoError(t, recorder.RemovePeer("key-a"))
+
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual(map[string]string{"peer-b": "100.64.0.2"}, provider.snapshot())
+	}, time.Second, 10*time.Millisecond, "added peers are published and removed peers are deleted")
+}
+
+func TestPublisher_RetriesInOrder(t *testing.T) {
+	recorder := peer.NewRecorder("https://mgm")
+	provider := &fakeProvider{records: make(map[string]string), fail: true}
+	publisher := newPublisher(Config{Provider: ProviderRFC2136, Zone: "corp.example"}, provider, recorder)
+
+	publisher.enqueue(false, "peer-a.netbird.cloud", "100.64.0.1")
+	publisher.enqueue(true, "peer-a.netbird.cloud", "100.64.0.1")
+	publisher.flush(context.Background())
+
+	require.Len(t, publisher.pending, 2, "a failed change blocks the later ones")
+	assert.Equal(t, 1, publisher.pending[0].attempts)
+
+	provider.mu.Lock()
+	provider.fail = false
+	provider.mu.Unlock()
+	publisher.flush(context.Background())
+
+	assert.Empty(t, publisher.pending)
+	assert.Empty(t, provider.snapshot())
+	assert.Empty(t, publisher.published)
+}
+
+func TestPublisher_RecordOf(t *testing.T) {
+	publisher := newPublisher(Config{Provider: ProviderRoute53, Zone: "corp.example"}, nil, nil)
+
+	record, ok := publisher.recordOf("Peer-A.netbird.cloud", "fd00::1")
+	require.True(t, ok)
+	assert.Equal(t, "peer-a", record.Name)
+	assert.Equal(t, "AAAA", record.Type)
+	assert.Equal(t, defaultTTL, record.TTL)
+
+	_, ok = publisher.recordOf("", "100.64.0.1")
+	assert.False(t, ok, "peers without a name are skipped")
+	_, ok = publisher.recordOf("peer-a.netbird.cloud", "")
+	assert.False(t, ok, "peers without an address are skipped")
+}
@@ -0,0 +1,124 @@
+package dnspublish
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/miekg/dns"
+)
+
+// tsigFudge is the allowed clock skew of the signed updates
+const tsigFudge = 300
+
+// rfc2136Provider updates the zone with dynamic DNS updates (RFC 2136), signed with TSIG (RFC 8945) if a key is set
+type rfc2136Provider struct {
+	server        string
+	tsigKey       string
+	tsigSecret    string
+	tsigAlgorithm string
+}
+
+func newRFC2136Provider(settings map[string]string) *rfc2136Provider {
+	server := settings["server"]
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	algorithm := settings["tsig_algorithm"]
+	if algorithm == "" {
+		algorithm = dns.HmacSHA256
+	}
+
+	p := &rfc2136Provider{
+		server:        server,
+		tsigSecret:    settings["tsig_secret"],
+		tsigAlgorithm: dns.Fqdn(strings.ToLower(algorithm)),
+	}
+	if key := settings["tsig_key"]; key != "" {
+		p.tsigKey = dns.Fqdn(strings.ToLower(key))
+	}
+	return p
+}
+
+// SetRecords replaces the record sets of the records
+func (p *rfc2136Provider) SetRecords(ctx context.Context, zone string, recs []libdns.Record) ([]libdns.Record, error) {
+	msg := new(dns.Msg)
+	msg.SetUpdate(zone)
+	for _, rec := range recs {
+		rr, err := toRR(zone, rec)
+		if err != nil {
+			return nil, err
+		}
+		msg.RemoveRRset([]dns.RR{rr})
+		msg.Insert([]dns.RR{rr})
+	}
+
+	if err := p.exchange(ctx, msg); err != nil {
+		return nil, err
+	}
+	return recs, nil
+}
+
+// DeleteRecords deletes the records, records that don't exist are ignored by the server
+func (p *rfc2136Provider) DeleteRecords(ctx context.Context, zone string, recs []libdns.Record) ([]libdns.Record, error) {
+	msg := new(dns.Msg)
+	msg.SetUpdate(zone)
+	for _, rec := range recs {
+		rr, err := toRR(zone, rec)
+		if err != nil {
+			return nil, err
+		}
+		msg.Remove([]dns.RR{rr})
+	}
+
+	if err := p.exchange(ctx, msg); err != nil {
+		return nil, err
+	}
+	return recs, nil
+}
+
+func (p *rfc2136Provider) exchange(ctx context.Context, msg *dns.Msg) error {
+	client := &dns.Client{Net: "tcp"}
+	if p.tsigKey != "" {
+		client.TsigSecret = map[string]string{p.tsigKey: p.tsigSecret}
+		msg.SetTsig(p.tsigKey, p.tsigAlgorithm, tsigFudge, time.Now().Unix())
+	}
+
+	resp, _, err := client.ExchangeContext(ctx, msg, p.server)
+	if err != nil {
+		return fmt.Errorf("send update to %s: %w", p.server, err)
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("update refused by %s: %s", p.server, dns.RcodeToString[resp.Rcode])
+	}
+	return nil
+}
+
+// toRR returns the address record in the zone
+func toRR(zone string, rec libdns.Record) (dns.RR, error) {
+	addr, err := netip.ParseAddr(rec.Value)
+	if err != nil {
+		return nil, fmt.Errorf("record %s: %w", rec.Name, err)
+	}
+
+	hdr := dns.RR_Header{
+		Name:  dns.Fqdn(rec.Name + "." + strings.TrimSuffix(zone, ".")),
+		Class: dns.ClassINET,
+		Ttl:   uint32(rec.TTL.Seconds()),
+	}
+	switch rec.Type {
+	case "A":
+		hdr.Rrtype = dns.TypeA
+		return &dns.A{Hdr: hdr, A: addr.AsSlice()}, nil
+	case "AAAA":
+		hdr.Rrtype = dns.TypeAAAA
+		return &dns.AAAA{Hdr: hdr, AAAA: addr.AsSlice()}, nil
+	default:
+		return nil, fmt.Errorf("record %s: unsupported type %s", rec.Name, rec.Type)
+	}
+}
@@ -0,0 +1,16 @@
+package dnspublish
+
+import (
+	"github.com/libdns/route53"
+)
+
+// newRoute53Provider returns the Route 53 provider, the unset settings are taken from the AWS environment
+func newRoute53Provider(settings map[string]string) *route53.Provider {
+	return &route53.Provider{
+		Region:          settings["region"],
+		Profile:         settings["profile"],
+		AccessKeyId:     settings["access_key_id"],
+		SecretAccessKey: settings["secret_access_key"],
+		HostedZoneID:    settings["hosted_zone_id"],
+	}
+}
@@ -0,0 +1,52 @@
+package internal
+
+import (
+	"time"
+
+	"github.com/netbirdio/netbird/client/internal/doctor"
+	"github.com/netbirdio/netbird/client/internal/peer"
+)
+
+// Diagnose checks the engine and the host for well-known failure patterns
+func (e *Engine) Diagnose() []doctor.Issue {
+	state := doctor.State{
+		Time: time.Now(),
+		MTU:  e.config.MTU,
+	}
+
+	e.syncMsgMux.Lock()
+	if e.wgInterface != nil {
+		state.InterfaceName = e.wgInterface.Name()
+	}
+	if !e.config.DisableDNS && e.dnsServer != nil {
+		state.DNSEnabled = true
+		state.DNSAddress = e.dnsServer.DnsIP()
+	}
+	if e.routeManager != nil {
+		state.ServerRoutes = e.routeManager.ServerRoutesCount()
+	}
+	e.syncMsgMux.Unlock()
+
+	if e.relayManager != nil {
+		if expiresAt, ok := e.relayManager.TokenExpiresAt(); ok {
+			state.RelayTokenExpiresAt = expiresAt
+		}
+	}
+
+	for _, p := range e.statusRecorder.GetFullStatus().Peers {
+		state.Peers = append(state.Peers, doctor.PeerState{
+			FQDN:                   p.FQDN,
+			Connected:              p.ConnStatus == peer.StatusConnected,
+			Relayed:                p.Relayed,
+			LocalCandidateType:     p.LocalIceCandidateType,
+			LocalCandidateEndpoint: p.LocalIceCandidateEndpoint,
+			LastHandshake:          p.LastWireguardHandshake,
+			BytesTx:                p.BytesTx,
+			BytesRx:                p.BytesRx,
+		})
+	}
+
+	state.System = doctor.CollectSystem(state.InterfaceName)
+
+	return doctor.Diagnose(state)
+}
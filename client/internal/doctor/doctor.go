@@ -0,0 +1,297 @@
+// Package doctor detects well-known failure patterns of the client from the state of the engine and the host,
+// and suggests how to fix them.
+package doctor
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Severity of an issue
+type Severity string
+
+const (
+	// SeverityWarning is an issue that degrades connectivity or might break it
+	SeverityWarning Severity = "warning"
+	// SeverityError is an issue that breaks connectivity
+	SeverityError Severity = "error"
+)
+
+const (
+	// recentHandshake is the age of a WireGuard handshake below which the tunnel is considered up
+	recentHandshake = 3 * time.Minute
+	// blackholeMinTx is the amount of sent bytes above which a missing response points to dropped packets
+	blackholeMinTx = 1 << 20
+	// minMTU is the IPv6 minimum MTU that passes any path
+	minMTU = 1280
+)
+
+var (
+	resolvedStubAddr = netip.AddrFrom4([4]byte{127, 0, 0, 53})
+	sharedAddrSpace  = netip.MustParsePrefix("100.64.0.0/10")
+)
+
+// Issue is a detected problem with the steps to fix it
+type Issue struct {
+	ID          string
+	Severity    Severity
+	Title       string
+	Details     string
+	Remediation []string
+}
+
+// PeerState is the connection state of a remote peer
+type PeerState struct {
+	FQDN                   string
+	Connected              bool
+	Relayed                bool
+	LocalCandidateType     string
+	LocalCandidateEndpoint string
+	LastHandshake          time.Time
+	BytesTx                int64
+	BytesRx                int64
+}
+
+// State is the input of the checks, collected from the engine and the host
+type State struct {
+	Time          time.Time
+	InterfaceName string
+	MTU           uint16
+
+	// DNSEnabled is set if the client manages the host DNS configuration
+	DNSEnabled bool
+	DNSAddress netip.Addr
+
+	// ServerRoutes is the number of networks this peer routes for other peers
+	ServerRoutes int
+
+	Peers []PeerState
+
+	// RelayTokenExpiresAt is zero if no relay token was received
+	RelayTokenExpiresAt time.Time
+
+	System System
+}
+
+// System holds facts about the host
+type System struct {
+	ResolvConfNameservers []netip.Addr
+	ResolvedRunning       bool
+
+	DockerRunning bool
+	// ForwardPolicyDrop is set if the policy of the iptables FORWARD chain drops packets
+	ForwardPolicyDrop bool
+	// ForwardAcceptsInterface is set if the iptables FORWARD chain has rules for the WireGuard interface
+	ForwardAcceptsInterface bool
+
+	// NftablesError is the error of accessing nftables, empty if it works
+	NftablesError string
+	// IptablesAvailable is set if iptables can be used as fallback
+	IptablesAvailable bool
+}
+
+type check func(State) *Issue
+
+var checks = []check{
+	checkResolvedConflict,
+	checkDockerForward,
+	checkMTUBlackhole,
+	checkDoubleNAT,
+	checkRelayToken,
+	checkNftables,
+}
+
+// Diagnose runs all checks against the state and returns the detected issues
+func Diagnose(state State) []Issue {
+	if state.Time.IsZero() {
+		state.Time = time.Now()
+	}
+
+	var issues []Issue
+	for _, c := range checks {
+		if issue := c(state); issue != nil {
+			issues = append(issues, *issue)
+		}
+	}
+	return issues
+}
+
+func checkResolvedConflict(s State) *Issue {
+	ns := s.System.ResolvConfNameservers
+	if !s.DNSEnabled || !s.System.ResolvedRunning || len(ns) == 0 {
+		return nil
+	}
+	if slices.Contains(ns, resolvedStubAddr) || slices.Contains(ns, s.DNSAddress) {
+		return nil
+	}
+
+	return &Issue{
+		ID:       "systemd-resolved-conflict",
+		Severity: SeverityError,
+		Title:    "/etc/resolv.conf bypasses systemd-resolved",
+		Details: fmt.Sprintf("systemd-resolved is running but /etc/resolv.conf points to %s instead of its stub resolver. "+
+			"The DNS configuration for the NetBird interface is ignored and peer names don't resolve.", joinAddrs(ns)),
+		Remediation: []string{
+			"Link /etc/resolv.conf to the stub resolver: ln -sf /run/systemd/resolve/stub-resolv.conf /etc/resolv.conf",
+			"Or disable systemd-resolved and restart NetBird, so that it manages /etc/resolv.conf directly",
+		},
+	}
+}
+
+func checkDockerForward(s State) *Issue {
+	sys := s.System
+	if s.ServerRoutes == 0 || !sys.DockerRunning || !sys.ForwardPolicyDrop || sys.ForwardAcceptsInterface {
+		return nil
+	}
+
+	return &Issue{
+		ID:       "docker-iptables",
+		Severity: SeverityError,
+		Title:    "Docker drops routed traffic",
+		Details: fmt.Sprintf("Docker set the policy of the iptables FORWARD chain to DROP and the rules for %s are missing, "+
+			"e.g. because Docker was restarted after NetBird. Traffic to the %d routed networks of this peer is dropped.",
+			s.InterfaceName, s.ServerRoutes),
+		Remediation: []string{
+			"Restart NetBird to restore its rules: netbird down && netbird up",
+			fmt.Sprintf("Allow the traffic permanently: iptables -I DOCKER-USER -i %s -j ACCEPT", s.InterfaceName),
+			"Start NetBird after Docker, e.g. with After=docker.service in the systemd unit",
+		},
+	}
+}
+
+func checkMTUBlackhole(s State) *Issue {
+	if s.MTU <= minMTU {
+		return nil
+	}
+
+	var affected []string
+	for _, p := range s.Peers {
+		if !p.Connected || s.Time.Sub(p.LastHandshake) > recentHandshake {
+			continue
+		}
+		if p.BytesTx > blackholeMinTx && p.BytesRx*100 < p.BytesTx {
+			affected = append(affected, p.FQDN)
+		}
+	}
+	if len(affected) == 0 {
+		return nil
+	}
+
+	return &Issue{
+		ID:       "mtu-blackhole",
+		Severity: SeverityWarning,
+		Title:    "Possible MTU blackhole",
+		Details: fmt.Sprintf("WireGuard handshakes succeed but almost nothing is received from %s. "+
+			"Large packets are likely dropped on the path because the interface MTU %d is too large.",
+			strings.Join(affected, ", "), s.MTU),
+		Remediation: []string{
+			fmt.Sprintf("Lower the MTU: netbird up --mtu %d", minMTU),
+			"Allow ICMP \"fragmentation needed\" messages on the firewalls along the path",
+		},
+	}
+}
+
+func checkDoubleNAT(s State) *Issue {
+	ports := make(map[netip.Addr]map[uint16]struct{})
+	var cgnat netip.Addr
+	for _, p := range s.Peers {
+		if !p.Connected || (p.LocalCandidateType != "srflx" && p.LocalCandidateType != "prflx") {
+			continue
+		}
+		ap, err := netip.ParseAddrPort(p.LocalCandidateEndpoint)
+		if err != nil {
+			continue
+		}
+		addr := ap.Addr().Unmap()
+		if sharedAddrSpace.Contains(addr) {
+			cgnat = addr
+		}
+		if ports[addr] == nil {
+			ports[addr] = make(map[uint16]struct{})
+		}
+		ports[addr][ap.Port()] = struct{}{}
+	}
+
+	var details string
+	switch {
+	case cgnat.IsValid():
+		details = fmt.Sprintf("The public address %s is in the carrier-grade NAT range, the ISP translates it a second time.", cgnat)
+	default:
+		for addr, p := range ports {
+			if len(p) > 1 {
+				details = fmt.Sprintf("The NAT maps the WireGuard port to %d different ports of %s depending on the peer. "+
+					"This happens behind symmetric or chained NATs.", len(p), addr)
+				break
+			}
+		}
+	}
+	if details == "" {
+		return nil
+	}
+
+	return &Issue{
+		ID:       "double-nat",
+		Severity: SeverityWarning,
+		Title:    "Double or symmetric NAT",
+		Details:  details + " Direct connections often fail and peers fall back to the relay.",
+		Remediation: []string{
+			"Put the ISP modem into bridge mode or this host into the DMZ of the outer router",
+			"Forward the WireGuard port to this host and announce it with --external-ip-map",
+			"Enable UPnP or NAT-PMP on the router",
+		},
+	}
+}
+
+func checkRelayToken(s State) *Issue {
+	if s.RelayTokenExpiresAt.IsZero() || s.Time.Before(s.RelayTokenExpiresAt) {
+		return nil
+	}
+
+	return &Issue{
+		ID:       "relay-token-expired",
+		Severity: SeverityError,
+		Title:    "Relay token expired",
+		Details: fmt.Sprintf("The relay token expired %s ago. It is refreshed by the management service, "+
+			"so the management connection is likely broken and new relayed connections fail.",
+			s.Time.Sub(s.RelayTokenExpiresAt).Round(time.Second)),
+		Remediation: []string{
+			"Check the management connection: netbird status -d",
+			"Reconnect to refresh the token: netbird down && netbird up",
+		},
+	}
+}
+
+func checkNftables(s State) *Issue {
+	if s.System.NftablesError == "" {
+		return nil
+	}
+
+	issue := &Issue{
+		ID:       "nftables-unavailable",
+		Severity: SeverityWarning,
+		Title:    "nftables is not usable",
+		Details:  fmt.Sprintf("Accessing nftables failed: %s. The nf_tables kernel module is likely missing or broken.", s.System.NftablesError),
+		Remediation: []string{
+			"Load the module: modprobe nf_tables",
+			"Install the modules package of the running kernel, e.g. linux-modules-extra, and reboot after kernel upgrades",
+			"Use iptables instead by setting NB_SKIP_NFTABLES_CHECK=true",
+		},
+	}
+	if !s.System.IptablesAvailable {
+		issue.Severity = SeverityError
+		issue.Details += " iptables isn't available either, so no firewall rules can be applied."
+		issue.Remediation = issue.Remediation[:2]
+	}
+	return issue
+}
+
+func joinAddrs(addrs []netip.Addr) string {
+	s := make([]string, 0, len(addrs))
+	for _, a := range addrs {
+		s = append(s, a.String())
+	}
+	return strings.Join(s, ", ")
+}
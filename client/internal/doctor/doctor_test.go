@@ -0,0 +1,164 @@
+package doctor
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func issueIDs(issues []Issue) []string {
+	ids := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		ids = append(ids, issue.ID)
+	}
+	return ids
+}
+
+func TestDiagnose_Healthy(t *testing.T) {
+	state := State{
+		Time:          now,
+		InterfaceName: "wt0",
+		MTU:           1280,
+		DNSEnabled:    true,
+		DNSAddress:    netip.MustParseAddr("100.64.0.1"),
+		ServerRoutes:  1,
+		Peers: []PeerState{{
+			FQDN:                   "peer-a.netbird.cloud",
+			Connected:              true,
+			LocalCandidateType:     "srflx",
+			LocalCandidateEndpoint: "203.0.113.10:51820",
+			LastHandshake:          now.Add(-time.Minute),
+			BytesTx:                10 << 20,
+			BytesRx:                8 << 20,
+		}},
+		RelayTokenExpiresAt: now.Add(time.Hour),
+		System: System{
+			ResolvConfNameservers:   []netip.Addr{netip.MustParseAddr("127.0.0.53")},
+			ResolvedRunning:         true,
+			DockerRunning:           true,
+			ForwardPolicyDrop:       true,
+			ForwardAcceptsInterface: true,
+			IptablesAvailable:       true,
+		},
+	}
+
+	assert.Empty(t, Diagnose(state))
+}
+
+func TestCheckResolvedConflict(t *testing.T) {
+	state := State{
+		DNSEnabled: true,
+		DNSAddress: netip.MustParseAddr("100.64.0.1"),
+		System: System{
+			ResolvConfNameservers: []netip.Addr{netip.MustParseAddr("192.168.1.1")},
+			ResolvedRunning:       true,
+		},
+	}
+	require.NotNil(t, checkResolvedConflict(state))
+
+	state.System.ResolvedRunning = false
+	assert.Nil(t, checkResolvedConflict(state), "resolved isn't running")
+
+	state.System.ResolvedRunning = true
+	state.System.ResolvConfNameservers = []netip.Addr{state.DNSAddress}
+	assert.Nil(t, checkResolvedConflict(state), "resolv.conf points to the NetBird resolver")
+
+	state.DNSEnabled = false
+	state.System.ResolvConfNameservers = []netip.Addr{netip.MustParseAddr("192.168.1.1")}
+	assert.Nil(t, checkResolvedConflict(state), "DNS is disabled")
+}
+
+func TestCheckDockerForward(t *testing.T) {
+	state := State{
+		InterfaceName: "wt0",
+		ServerRoutes:  2,
+		System: System{
+			DockerRunning:     true,
+			ForwardPolicyDrop: true,
+		},
+	}
+	issue := checkDockerForward(state)
+	require.NotNil(t, issue)
+	assert.Contains(t, issue.Remediation[1], "-i wt0")
+
+	state.System.ForwardAcceptsInterface = true
+	assert.Nil(t, checkDockerForward(state), "forward rules are in place")
+
+	state.System.ForwardAcceptsInterface = false
+	state.ServerRoutes = 0
+	assert.Nil(t, checkDockerForward(state), "not a routing peer")
+}
+
+func TestCheckMTUBlackhole(t *testing.T) {
+	state := State{
+		Time: now,
+		MTU:  1420,
+		Peers: []PeerState{
+			{FQDN: "stuck", Connected: true, LastHandshake: now.Add(-time.Minute), BytesTx: 5 << 20, BytesRx: 1024},
+			{FQDN: "fine", Connected: true, LastHandshake: now.Add(-time.Minute), BytesTx: 5 << 20, BytesRx: 4 << 20},
+			{FQDN: "stale", Connected: true, LastHandshake: now.Add(-time.Hour), BytesTx: 5 << 20, BytesRx: 1024},
+		},
+	}
+	issue := checkMTUBlackhole(state)
+	require.NotNil(t, issue)
+	assert.Contains(t, issue.Details, "stuck")
+	assert.NotContains(t, issue.Details, "fine")
+	assert.NotContains(t, issue.Details, "stale")
+
+	state.MTU = 1280
+	assert.Nil(t, checkMTUBlackhole(state), "MTU is already minimal")
+}
+
+func TestCheckDoubleNAT(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints []string
+		want      bool
+	}{
+		{name: "single mapping", endpoints: []string{"203.0.113.10:51820", "203.0.113.10:51820"}},
+		{name: "endpoint dependent mapping", endpoints: []string{"203.0.113.10:40001", "203.0.113.10:40002"}, want: true},
+		{name: "carrier grade nat", endpoints: []string{"100.72.1.2:51820"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := State{}
+			for _, ep := range tt.endpoints {
+				state.Peers = append(state.Peers, PeerState{Connected: true, LocalCandidateType: "srflx", LocalCandidateEndpoint: ep})
+			}
+			assert.Equal(t, tt.want, checkDoubleNAT(state) != nil)
+		})
+	}
+}
+
+func TestCheckRelayToken(t *testing.T) {
+	assert.Nil(t, checkRelayToken(State{Time: now}), "no token received")
+	assert.Nil(t, checkRelayToken(State{Time: now, RelayTokenExpiresAt: now.Add(time.Minute)}))
+	assert.NotNil(t, checkRelayToken(State{Time: now, RelayTokenExpiresAt: now.Add(-time.Minute)}))
+}
+
+func TestCheckNftables(t *testing.T) {
+	assert.Nil(t, checkNftables(State{}))
+
+	issue := checkNftables(State{System: System{NftablesError: "protocol not supported", IptablesAvailable: true}})
+	require.NotNil(t, issue)
+	assert.Equal(t, SeverityWarning, issue.Severity)
+
+	issue = checkNftables(State{System: System{NftablesError: "protocol not supported"}})
+	require.NotNil(t, issue)
+	assert.Equal(t, SeverityError, issue.Severity)
+}
+
+func TestDiagnose_ReportsAllIssues(t *testing.T) {
+	state := State{
+		Time:                now,
+		RelayTokenExpiresAt: now.Add(-time.Hour),
+		System:              System{NftablesError: "protocol not supported", IptablesAvailable: true},
+	}
+	assert.Equal(t, []string{"relay-token-expired", "nftables-unavailable"}, issueIDs(Diagnose(state)))
+}
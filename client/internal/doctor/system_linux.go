@@ -0,0 +1,110 @@
+//go:build linux && !android
+
+package doctor
+
+import (
+	"bufio"
+	"net"
+	"net/netip"
+	"os"
+	"strings"
+
+	"github.com/coreos/go-iptables/iptables"
+	"github.com/google/nftables"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	resolvConfPath   = "/etc/resolv.conf"
+	resolvedRunDir   = "/run/systemd/resolve"
+	dockerInterface  = "docker0"
+	dockerUserChain  = "DOCKER-USER"
+	netbirdChainName = "NETBIRD-"
+	skipNftablesEnv  = "NB_SKIP_NFTABLES_CHECK"
+)
+
+// CollectSystem gathers the host facts used by the checks
+func CollectSystem(ifName string) System {
+	var sys System
+
+	sys.ResolvConfNameservers = resolvConfNameservers(resolvConfPath)
+	if _, err := os.Stat(resolvedRunDir); err == nil {
+		sys.ResolvedRunning = true
+	}
+
+	if _, err := net.InterfaceByName(dockerInterface); err == nil {
+		sys.DockerRunning = true
+	}
+
+	if ipt, err := iptables.NewWithProtocol(iptables.ProtocolIPv4); err == nil {
+		collectIptables(ipt, ifName, &sys)
+	} else {
+		log.Debugf("doctor: iptables not available: %v", err)
+	}
+
+	if os.Getenv(skipNftablesEnv) != "true" {
+		conn := &nftables.Conn{}
+		if _, err := conn.ListTables(); err != nil {
+			sys.NftablesError = err.Error()
+		}
+	}
+
+	return sys
+}
+
+func collectIptables(ipt *iptables.IPTables, ifName string, sys *System) {
+	chains, err := ipt.ListChains("filter")
+	if err != nil {
+		log.Debugf("doctor: list iptables chains: %v", err)
+		return
+	}
+	sys.IptablesAvailable = true
+
+	for _, chain := range chains {
+		if chain == dockerUserChain {
+			sys.DockerRunning = true
+		}
+	}
+
+	rules, err := ipt.List("filter", "FORWARD")
+	if err != nil {
+		log.Debugf("doctor: list iptables forward rules: %v", err)
+		return
+	}
+
+	for _, rule := range rules {
+		switch {
+		case rule == "-P FORWARD DROP":
+			sys.ForwardPolicyDrop = true
+		case strings.Contains(rule, "-i "+ifName+" "), strings.Contains(rule, "-o "+ifName+" "),
+			strings.Contains(rule, netbirdChainName):
+			sys.ForwardAcceptsInterface = true
+		}
+	}
+}
+
+func resolvConfNameservers(path string) []netip.Addr {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Debugf("doctor: open %s: %v", path, err)
+		return nil
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Debugf("doctor: close %s: %v", path, err)
+		}
+	}()
+
+	var servers []netip.Addr
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "nameserver" {
+			continue
+		}
+		if addr, err := netip.ParseAddr(fields[1]); err == nil {
+			servers = append(servers, addr)
+		}
+	}
+	return servers
+}
@@ -0,0 +1,8 @@
+//go:build !linux || android
+
+package doctor
+
+// CollectSystem gathers the host facts used by the checks. The checks of host facts only apply to Linux.
+func CollectSystem(string) System {
+	return System{}
+}
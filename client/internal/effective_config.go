@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/netbirdio/netbird/client/iface"
+	"github.com/netbirdio/netbird/client/internal/lazyconn"
+)
+
+// ConfigSource is where an effective setting of the engine came from
+type ConfigSource string
+
+const (
+	ConfigSourceDefault    ConfigSource = "default"
+	ConfigSourceConfig     ConfigSource = "config"
+	ConfigSourceManagement ConfigSource = "management"
+)
+
+// EffectiveSetting is a value the running engine uses
+type EffectiveSetting struct {
+	Name   string
+	Value  string
+	Source ConfigSource
+}
+
+// EffectiveConfig is the resolved configuration of the running engine. The secrets of the config are left out.
+type EffectiveConfig struct {
+	Settings []EffectiveSetting
+	// Features are the feature flags management can turn on for the peer
+	Features []EffectiveSetting
+}
+
+// EffectiveConfig returns the configuration the engine runs with and where each value came from. Values that differ
+// from the default are reported as set by the config, which includes the command line flags and the environment.
+func (e *Engine) EffectiveConfig() EffectiveConfig {
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+
+	c := e.config
+	networkMap := e.syncCache.GetNetworkMap()
+	peerConfig := networkMap.GetPeerConfig()
+
+	mtuSource := localSource(c.MTU != iface.DefaultMTU)
+	if peerMTU := peerConfig.GetMtu(); peerMTU > 0 && uint16(peerMTU) == c.MTU && c.MTU != iface.DefaultMTU {
+		mtuSource = ConfigSourceManagement
+	}
+
+	settings := []EffectiveSetting{
+		{Name: "WgIfaceName", Value: c.WgIfaceName, Source: localSource(c.WgIfaceName != iface.WgInterfaceDefault)},
+		{Name: "WgPort", Value: fmt.Sprint(c.WgPort), Source: localSource(c.WgPort != iface.DefaultWgPort)},
+		{Name: "WgAddr", Value: c.WgAddr, Source: ConfigSourceManagement},
+		{Name: "MTU", Value: fmt.Sprint(c.MTU), Source: mtuSource},
+		newEffectiveSetting("NetworkMonitor", c.NetworkMonitor),
+		newEffectiveSetting("NetworkMonitorDebounce", c.NetworkMonitorDebounce),
+		newEffectiveSetting("IFaceBlackList", c.IFaceBlackList),
+		newEffectiveSetting("DisableIPv6Discovery", c.DisableIPv6Discovery),
+		newEffectiveSetting("PreSharedKey", c.PreSharedKey != nil),
+		newEffectiveSetting("UDPMuxPort", c.UDPMuxPort),
+		newEffectiveSetting("UDPMuxSrflxPort", c.UDPMuxSrflxPort),
+		newEffectiveSetting("NATExternalIPs", c.NATExternalIPs),
+		newEffectiveSetting("CustomDNSAddress", c.CustomDNSAddress),
+		newEffectiveSetting("RosenpassEnabled", c.RosenpassEnabled),
+		newEffectiveSetting("RosenpassPermissive", c.RosenpassPermissive),
+		newEffectiveSetting("ServerSSHAllowed", c.ServerSSHAllowed),
+		newEffectiveSetting("EnableSSHRoot", boolValue(c.EnableSSHRoot)),
+		newEffectiveSetting("EnableSSHSFTP", boolValue(c.EnableSSHSFTP)),
+		newEffectiveSetting("EnableSSHLocalPortForwarding", boolValue(c.EnableSSHLocalPortForwarding)),
+		newEffectiveSetting("EnableSSHRemotePortForwarding", boolValue(c.EnableSSHRemotePortForwarding)),
+		newEffectiveSetting("DisableSSHAuth", boolValue(c.DisableSSHAuth)),
+		newEffectiveSetting("DNSRouteInterval", c.DNSRouteInterval),
+		newEffectiveSetting("DisableClientRoutes", c.DisableClientRoutes),
+		newEffectiveSetting("DisableServerRoutes", c.DisableServerRoutes),
+		newEffectiveSetting("DisableDNS", c.DisableDNS),
+		newEffectiveSetting("DisableFirewall", c.DisableFirewall),
+		newEffectiveSetting("BlockLANAccess", c.BlockLANAccess),
+		newEffectiveSetting("BlockInbound", c.BlockInbound),
+		newEffectiveSetting("KillSwitch", c.KillSwitch),
+		newEffectiveSetting("FirewallBackend", c.FirewallBackend),
+		newEffectiveSetting("ICEMulticastDNS", c.ICEMulticastDNS),
+		newEffectiveSetting("ICEPolicy", c.ICEPolicy),
+		newEffectiveSetting("WgKeepAlive", c.WgKeepAlive),
+		newEffectiveSetting("WgHandshakeTimeout", c.WgHandshakeTimeout),
+		newEffectiveSetting("WgHandshakeRetryInterval", c.WgHandshakeRetryInterval),
+		newEffectiveSetting("WgResponderDelay", c.WgResponderDelay),
+		newEffectiveSetting("RouteFailoverGrace", c.RouteFailoverGrace),
+		newEffectiveSetting("RouteDisconnectGrace", c.RouteDisconnectGrace),
+		newEffectiveSetting("ExitNodeFailsafe", c.ExitNodeFailsafe),
+		newEffectiveSetting("ExitNodeExclusions", c.ExitNodeExclusions),
+		newEffectiveSetting("CriticalEndpoints", c.CriticalEndpoints),
+		newEffectiveSetting("DNSQueryLog", c.DNSQueryLog),
+		newEffectiveSetting("DiagnosticEndpoints", c.DiagnosticEndpoints),
+		newEffectiveSetting("MDNSResponder", c.MDNSResponder),
+		newEffectiveSetting("DisableNetstackFallback", c.DisableNetstackFallback),
+		newEffectiveSetting("InventoryReporting", c.InventoryReporting),
+		newEffectiveSetting("InventoryInterval", c.InventoryInterval),
+		newEffectiveSetting("PreferredRelay", c.PreferredRelay),
+		newEffectiveSetting("TURNTransports", c.TURNTransports),
+		newEffectiveSetting("PathMTUProbing", c.PathMTUProbing),
+		newEffectiveSetting("PathMTUProbeInterval", c.PathMTUProbeInterval),
+		newEffectiveSetting("ReversePathFilter", c.ReversePathFilter),
+		newEffectiveSetting("DNSListenAddresses", c.DNSListenAddresses),
+	}
+
+	lazySource := ConfigSourceDefault
+	switch {
+	case c.LazyConnectionEnabled || lazyconn.IsLazyConnEnabledByEnv():
+		lazySource = ConfigSourceConfig
+	case peerConfig != nil:
+		lazySource = ConfigSourceManagement
+	}
+	lazyEnabled := e.connMgr != nil && e.connMgr.isStartedWithLazyMgr()
+
+	dnsRouteSource := ConfigSourceDefault
+	if peerConfig != nil {
+		dnsRouteSource = ConfigSourceManagement
+	}
+
+	flow := e.syncCache.GetNetbirdConfig().GetFlow()
+	flowSource := ConfigSourceDefault
+	if flow != nil {
+		flowSource = ConfigSourceManagement
+	}
+
+	features := []EffectiveSetting{
+		{Name: "LazyConnection", Value: fmt.Sprint(lazyEnabled), Source: lazySource},
+		{Name: "DNSRoutes", Value: fmt.Sprint(peerConfig.GetRoutingPeerDnsResolutionEnabled()), Source: dnsRouteSource},
+		{Name: "Flow", Value: fmt.Sprint(flow.GetEnabled()), Source: flowSource},
+	}
+
+	return EffectiveConfig{Settings: settings, Features: features}
+}
+
+// newEffectiveSetting returns the setting with the source derived from the value, zero values are the defaults
+func newEffectiveSetting[T any](name string, value T) EffectiveSetting {
+	formatted := formatSettingValue(value)
+	var zero T
+	return EffectiveSetting{
+		Name:   name,
+		Value:  formatted,
+		Source: localSource(formatted != formatSettingValue(zero)),
+	}
+}
+
+func formatSettingValue(value any) string {
+	switch v := value.(type) {
+	case []string:
+		return strings.Join(v, ",")
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func localSource(set bool) ConfigSource {
+	if set {
+		return ConfigSourceConfig
+	}
+	return ConfigSourceDefault
+}
+
+// boolValue returns the value of an optional flag, false if unset
+func boolValue(value *bool) bool {
+	return value != nil && *value
+}
@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/client/iface"
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+func TestEngine_EffectiveConfig(t *testing.T) {
+	e := &Engine{
+		syncMsgMux: &sync.Mutex{},
+		config: &EngineConfig{
+			WgIfaceName:          iface.WgInterfaceDefault,
+			WgPort:               51821,
+			WgAddr:               "100.64.0.1/16",
+			MTU:                  1400,
+			RouteDisconnectGrace: time.Minute,
+			ExitNodeExclusions:   []string{"10.0.0.0/8", "bank.example"},
+		},
+	}
+
+	settings := func(cfg EffectiveConfig) map[string]EffectiveSetting {
+		byName := make(map[string]EffectiveSetting)
+		for _, s := range append(cfg.Settings, cfg.Features...) {
+			byName[s.Name] = s
+		}
+		return byName
+	}
+
+	byName := settings(e.EffectiveConfig())
+	assert.Equal(t, EffectiveSetting{Name: "WgIfaceName", Value: iface.WgInterfaceDefault, Source: ConfigSourceDefault}, byName["WgIfaceName"])
+	assert.Equal(t, EffectiveSetting{Name: "WgPort", Value: "51821", Source: ConfigSourceConfig}, byName["WgPort"])
+	assert.Equal(t, EffectiveSetting{Name: "MTU", Value: "1400", Source: ConfigSourceConfig}, byName["MTU"])
+	assert.Equal(t, EffectiveSetting{Name: "RouteDisconnectGrace", Value: "1m0s", Source: ConfigSourceConfig}, byName["RouteDisconnectGrace"])
+	assert.Equal(t, EffectiveSetting{Name: "ExitNodeExclusions", Value: "10.0.0.0/8,bank.example", Source: ConfigSourceConfig}, byName["ExitNodeExclusions"])
+	assert.Equal(t, ConfigSourceDefault, byName["KillSwitch"].Source)
+	assert.Equal(t, EffectiveSetting{Name: "DNSRoutes", Value: "false", Source: ConfigSourceDefault}, byName["DNSRoutes"])
+	assert.Equal(t, EffectiveSetting{Name: "Flow", Value: "false", Source: ConfigSourceDefault}, byName["Flow"])
+
+	e.syncCache = &mgmProto.SyncResponse{
+		NetbirdConfig: &mgmProto.NetbirdConfig{Flow: &mgmProto.FlowConfig{Enabled: true}},
+		NetworkMap: &mgmProto.NetworkMap{
+			PeerConfig: &mgmProto.PeerConfig{Mtu: 1400, RoutingPeerDnsResolutionEnabled: true},
+		},
+	}
+
+	byName = settings(e.EffectiveConfig())
+	assert.Equal(t, ConfigSourceManagement, byName["MTU"].Source, "the MTU of the peer config applies")
+	assert.Equal(t, EffectiveSetting{Name: "DNSRoutes", Value: "true", Source: ConfigSourceManagement}, byName["DNSRoutes"])
+	assert.Equal(t, EffectiveSetting{Name: "Flow", Value: "true", Source: ConfigSourceManagement}, byName["Flow"])
+	assert.Equal(t, EffectiveSetting{Name: "LazyConnection", Value: "false", Source: ConfigSourceManagement}, byName["LazyConnection"])
+}
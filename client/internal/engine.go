@@ -4,7 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math/rand"
+	"math"
 	"net"
 	"net/netip"
 	"net/url"
@@ -14,6 +14,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -31,10 +32,17 @@ import (
 	"github.com/netbirdio/netbird/client/iface/device"
 	"github.com/netbirdio/netbird/client/iface/udpmux"
 	"github.com/netbirdio/netbird/client/internal/acl"
+	"github.com/netbirdio/netbird/client/internal/diagsrv"
 	"github.com/netbirdio/netbird/client/internal/dns"
 	dnsconfig "github.com/netbirdio/netbird/client/internal/dns/config"
 	"github.com/netbirdio/netbird/client/internal/dnsfwd"
+	"github.com/netbirdio/netbird/client/internal/dnspublish"
+	"github.com/netbirdio/netbird/client/internal/eventbus"
+	"github.com/netbirdio/netbird/client/internal/hooks"
+	"github.com/netbirdio/netbird/client/internal/inboundprompt"
 	"github.com/netbirdio/netbird/client/internal/ingressgw"
+	"github.com/netbirdio/netbird/client/internal/lazyconn"
+	"github.com/netbirdio/netbird/client/internal/logging"
 	"github.com/netbirdio/netbird/client/internal/netflow"
 	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
 	"github.com/netbirdio/netbird/client/internal/networkmonitor"
@@ -42,13 +50,20 @@ import (
 	"github.com/netbirdio/netbird/client/internal/peer/guard"
 	icemaker "github.com/netbirdio/netbird/client/internal/peer/ice"
 	"github.com/netbirdio/netbird/client/internal/peerstore"
+	"github.com/netbirdio/netbird/client/internal/plugin"
 	"github.com/netbirdio/netbird/client/internal/relay"
 	"github.com/netbirdio/netbird/client/internal/rosenpass"
 	"github.com/netbirdio/netbird/client/internal/routemanager"
+	"github.com/netbirdio/netbird/client/internal/routemanager/metrics"
 	"github.com/netbirdio/netbird/client/internal/routemanager/systemops"
+	"github.com/netbirdio/netbird/client/internal/shaping"
 	"github.com/netbirdio/netbird/client/internal/statemanager"
 	"github.com/netbirdio/netbird/client/internal/updatemanager"
+	"github.com/netbirdio/netbird/client/internal/winfw"
+	"github.com/netbirdio/netbird/client/internal/wol"
+	"github.com/netbirdio/netbird/client/net/proxy"
 	cProto "github.com/netbirdio/netbird/client/proto"
+	sshserver "github.com/netbirdio/netbird/client/ssh/server"
 	"github.com/netbirdio/netbird/shared/management/domain"
 	semaphoregroup "github.com/netbirdio/netbird/util/semaphore-group"
 
@@ -56,6 +71,7 @@ import (
 	nbdns "github.com/netbirdio/netbird/dns"
 	"github.com/netbirdio/netbird/route"
 	mgm "github.com/netbirdio/netbird/shared/management/client"
+	"github.com/netbirdio/netbird/shared/management/networkmap"
 	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
 	auth "github.com/netbirdio/netbird/shared/relay/auth/hmac"
 	relayClient "github.com/netbirdio/netbird/shared/relay/client"
@@ -64,19 +80,21 @@ import (
 	"github.com/netbirdio/netbird/util"
 )
 
-// PeerConnectionTimeoutMax is a timeout of an initial connection attempt to a remote peer.
-// E.g. this peer will wait PeerConnectionTimeoutMax for the remote peer to respond,
-// if not successful then it will retry the connection attempt.
-// Todo pass timeout at EnginConfig
 const (
-	PeerConnectionTimeoutMax = 45000 // ms
-	PeerConnectionTimeoutMin = 30000 // ms
-	connInitLimit            = 200
-	disableAutoUpdate        = "disabled"
+	connInitLimit     = 200
+	disableAutoUpdate = "disabled"
 )
 
 var ErrResetConnection = fmt.Errorf("reset connection")
 
+var (
+	dnsLog      = logging.Logger(logging.DNS)
+	routesLog   = logging.Logger(logging.Routes)
+	firewallLog = logging.Logger(logging.Firewall)
+	iceLog      = logging.Logger(logging.ICE)
+	relayLog    = logging.Logger(logging.Relay)
+)
+
 // EngineConfig is a config for the Engine
 type EngineConfig struct {
 	WgPort      int
@@ -90,6 +108,8 @@ type EngineConfig struct {
 
 	// NetworkMonitor is a flag to enable network monitoring
 	NetworkMonitor bool
+	// NetworkMonitorDebounce is the quiet time after network events before they are evaluated, zero means default
+	NetworkMonitorDebounce time.Duration
 
 	// IFaceBlackList is a list of network interfaces to ignore when discovering connection candidates (ICE related)
 	IFaceBlackList       []string
@@ -119,6 +139,10 @@ type EngineConfig struct {
 	EnableSSHLocalPortForwarding  *bool
 	EnableSSHRemotePortForwarding *bool
 	DisableSSHAuth                *bool
+	// SSHRecordingDir stores the output of the recorded SSH sessions, empty disables the file recording
+	SSHRecordingDir string
+	// SSHPeerRules map the peers to the local users of the SSH server, they replace the peer rules of management
+	SSHPeerRules []sshserver.PeerRule
 
 	DNSRouteInterval time.Duration
 
@@ -128,10 +152,100 @@ type EngineConfig struct {
 	DisableFirewall     bool
 	BlockLANAccess      bool
 	BlockInbound        bool
+	// KillSwitch drops all traffic that doesn't go through the tunnel, the rules stay in place while the engine is down
+	KillSwitch bool
+	// SOCKS5ProxyAddress overrides the listen address of the SOCKS5 proxy exposing the tunnel in netstack mode
+	SOCKS5ProxyAddress string
+	// HTTPProxyAddress is the listen address of the HTTP CONNECT proxy exposing the tunnel in netstack mode, empty
+	// disables it
+	HTTPProxyAddress string
+	// FirewallBackend selects the firewall implementation, empty means auto-detection
+	FirewallBackend firewall.Backend
+	// InboundExceptions are the local inbound rules that stay in effect while BlockInbound is set
+	InboundExceptions []*mgmProto.FirewallRule
 
 	LazyConnectionEnabled bool
 
+	// ICEMulticastDNS hides the host candidate IPs behind mDNS names
+	ICEMulticastDNS bool
+	// ICEPolicy restricts the ICE candidate types, management can override it per peer
+	ICEPolicy icemaker.Policy
+
+	// LazyConnInactivity configures when lazy connections are idle, management can override it per peer
+	LazyConnInactivity lazyconn.InactivityConfig
+
 	MTU uint16
+
+	// WgKeepAlive is the persistent keepalive interval programmed for every peer. Zero means default.
+	WgKeepAlive time.Duration
+	// PeerWgKeepAlive overrides WgKeepAlive for specific peers, keyed by the peer's WireGuard public key
+	PeerWgKeepAlive map[string]time.Duration
+	// WgHandshakeTimeout is the allowed WireGuard handshake delay before a connection is considered broken. Zero means default.
+	WgHandshakeTimeout time.Duration
+	// WgHandshakeRetryInterval is the initial interval between the connection offers to a peer that isn't connected. Zero means default.
+	WgHandshakeRetryInterval time.Duration
+	// WgResponderDelay is how long the responding peer waits for the handshake of the initiator before it
+	// configures the remote endpoint itself. Zero means default.
+	WgResponderDelay time.Duration
+	// ConnBackoff is the connection timeout and offer retry policy of the peer connections. Zero values mean default.
+	ConnBackoff peer.BackoffPolicy
+
+	// Plugins are the external extensions the engine connects to
+	Plugins []plugin.Config
+	// Hooks are the commands and webhooks run on lifecycle events
+	Hooks []hooks.Config
+	// TrafficShaping are the bandwidth limits of the traffic to peers and routes
+	TrafficShaping []shaping.Rule
+	// WakeOnLAN are the locally configured machines to wake up, keyed by peer FQDN, NetBird IP or any name
+	WakeOnLAN map[string]wol.Target
+	// RouteFailoverGrace is how long failed over connections are accepted as a routing peer, zero disables it
+	RouteFailoverGrace time.Duration
+	// DNSQueryLog sends the answered DNS queries to the flow manager
+	DNSQueryLog bool
+	// DiagnosticEndpoints serves the echo and whoami endpoints on the NetBird address
+	DiagnosticEndpoints bool
+	// MDNSResponder answers mDNS and LLMNR queries for the peer names on the NetBird interface
+	MDNSResponder bool
+	// ExitNodeFailsafe is how long an exit node may be unreachable before its default route is removed, zero disables it
+	ExitNodeFailsafe time.Duration
+	// RouteDisconnectGrace is how long the routes of a disconnected routing peer stay installed, zero disables it
+	RouteDisconnectGrace time.Duration
+	// ExitNodeExclusions are the CIDRs and domains routed via the local connection while an exit node is used
+	ExitNodeExclusions []string
+	// CriticalEndpoints are the addresses and CIDRs that bypass exit nodes and the LAN block, empty uses
+	// routemanager.DefaultCriticalEndpoints
+	CriticalEndpoints []string
+	// DomainRoutes route the resolved addresses of the domains via a routing peer or the local connection
+	DomainRoutes map[string]string
+	// DisableNetstackFallback fails the start instead of falling back to netstack mode when the tunnel device
+	// can't be created for lack of permissions
+	DisableNetstackFallback bool
+	// InventoryReporting reports the device inventory with the meta, collected every InventoryInterval
+	InventoryReporting bool
+	InventoryInterval  time.Duration
+	// PreferredRelay pins the home relay server, empty selects the one with the lowest latency
+	PreferredRelay string
+	// TURNTransports is the order of preference of the transports to reach the TURN servers over, UDP first,
+	// then TCP and TLS on port 443 if empty
+	TURNTransports []icemaker.TURNTransport
+	// ProxyURL is the proxy the connections to the management, signal and relay servers go through, the proxy of the
+	// environment if empty
+	ProxyURL string
+	// BondedPeers are the peers the connections are bonded to over two uplinks, keyed by the peer's WireGuard public key
+	BondedPeers map[string]peer.BondConfig
+	// PathMTUProbing measures the path MTU to the directly connected peers every PathMTUProbeInterval
+	PathMTUProbing       bool
+	PathMTUProbeInterval time.Duration
+	// DNSCollectionFilter scopes and anonymizes the DNS collection of the traffic events
+	DNSCollectionFilter *nftypes.DNSFilter
+	// ReversePathFilter drops the routed traffic with spoofed sources while this peer routes networks
+	ReversePathFilter bool
+	// DNSListenAddresses are additional addresses the local DNS resolver listens on
+	DNSListenAddresses []netip.AddrPort
+	// DNSPublisher mirrors the peer names into an external DNS zone, nil disables it
+	DNSPublisher *dnspublish.Config
+	// InboundPrompts asks the user about the first inbound connections of the peers to local ports
+	InboundPrompts bool
 }
 
 // Engine is a mechanism responsible for reacting on Signal and Management stream events and managing connections to the remote peers.
@@ -151,6 +265,10 @@ type Engine struct {
 
 	// syncMsgMux is used to guarantee sequential Management Service message processing
 	syncMsgMux *sync.Mutex
+	// peerConnMux serializes the signal messages with the changes of the peer connections and the connection manager.
+	// The holders of syncMsgMux take it only around those changes, so applying a large network map doesn't delay the
+	// offers, answers and candidates of the peers. It is always taken after syncMsgMux.
+	peerConnMux sync.Mutex
 
 	config    *EngineConfig
 	mobileDep MobileDependency
@@ -160,6 +278,9 @@ type Engine struct {
 	// TURNs is a list of STUN servers used by ICE
 	TURNs    []*stun.URI
 	stunTurn icemaker.StunTurn
+	// announcedTURNs are the TURNs as management sent them, TURNs holds them with the selected transports
+	announcedTURNs []*stun.URI
+	turnTransports *turnTransportSelection
 
 	clientCtx    context.Context
 	clientCancel context.CancelFunc
@@ -167,16 +288,28 @@ type Engine struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 
+	// sessionCtx is the context of the streams from management and Signal, a warm restart replaces it with the
+	// context of the new connections
+	sessionCtx    context.Context
+	sessionCancel context.CancelFunc
+
 	wgInterface WGIface
 
 	udpMux *udpmux.UniversalUDPMuxDefault
 
 	// networkSerial is the latest CurrentSerial (state ID) of the network sent by the Management service
 	networkSerial uint64
+	// inboundExceptions are the inbound rules management keeps in effect while inbound connections are blocked
+	inboundExceptions []*mgmProto.FirewallRule
 
 	networkMonitor *networkmonitor.NetworkMonitor
 
 	sshServer sshServer
+	// sshServerFeatures are the optional features applied to the running SSH server
+	sshServerFeatures sshFeatures
+
+	// winfwConfig is the last applied Windows Defender Firewall config
+	winfwConfig winfw.Config
 
 	statusRecorder *peer.Status
 
@@ -193,7 +326,13 @@ type Engine struct {
 
 	relayManager *relayClient.Manager
 	stateManager *statemanager.Manager
-	srWatcher    *guard.SRWatcher
+	peerStats    *peerStats
+	// peerConnCache holds the last connections of the peers that succeeded, they prime the new connections
+	peerConnCache *peerConnCache
+	srWatcher     *guard.SRWatcher
+
+	// wakeHints are the Wake-on-LAN hints from management, keyed by peer public key
+	wakeHints map[string]wol.Target
 
 	// Sync response persistence
 	persistSyncResponse bool
@@ -201,16 +340,64 @@ type Engine struct {
 	connSemaphore       *semaphoregroup.SemaphoreGroup
 	flowManager         nftypes.FlowManager
 
+	// peerWorkers bounds the workers applying the peers of a network map. It is separate from connSemaphore, a
+	// worker waiting for peerConnMux must not hold the slot Conn.Open waits for under that lock.
+	peerWorkers *semaphoregroup.SemaphoreGroup
+
+	// resourceBudget accounts and limits the resources of the peer connections
+	resourceBudget *peer.ResourceBudget
+
+	// syncCache is the latest network map and Netbird config, persisted as NetworkMapCacheState
+	syncCache *mgmProto.SyncResponse
+	// mgmSynced is set once a network map was received from management
+	mgmSynced bool
+	// offlineCatalogue is set while the engine runs from the cached network map
+	offlineCatalogue bool
+	// warmRestarts carries the warm restart requests to the connect client, nil if it doesn't handle them
+	warmRestarts chan struct{}
+	// lanBlockRules drop the routed traffic to the local networks if BlockLANAccess is set, keyed by the network. The
+	// rule is nil if the firewall couldn't add it.
+	lanBlockRules map[netip.Prefix]firewallManager.Rule
+	// networkMapApplied is set once a network map was applied, received from management or cached
+	networkMapApplied atomic.Bool
+	// lastResume is when the engine last recovered the peers after a resume from suspend
+	lastResume time.Time
+
+	// roles are the roles of the peer reported to management and in the status
+	roles system.Roles
+	// inventory collects the device inventory reported with the meta, nil if the reporting is disabled
+	inventory *system.InventoryCollector
+	// relaySelection steers the home relay server to the pinned or the lowest latency one
+	relaySelection *relaySelection
+
 	// auto-update
 	updateManager *updatemanager.Manager
 
 	// WireGuard interface monitor
 	wgIfaceMonitor *WGIfaceMonitor
 
+	pluginMgr    *plugin.Manager
+	hooksMgr     *hooks.Manager
+	dnsPublisher *dnspublish.Publisher
+	shaper       shaping.Shaper
+	diagServer   *diagsrv.Server
+
+	// inboundPrompts asks about the first inbound connections, nil if InboundPrompts is disabled
+	inboundPrompts *inboundprompt.Manager
+
 	// shutdownWg tracks all long-running goroutines to ensure clean shutdown
 	shutdownWg sync.WaitGroup
 
 	probeStunTurn *relay.StunTurnProbe
+
+	// routeMetrics records how long programming the routes and route firewall rules takes
+	routeMetrics *metrics.Recorder
+
+	// eventBus carries the events of the engine components to the status recorder, the flow manager and the
+	// external subscribers
+	eventBus *eventbus.Bus
+	// removeFlowHandler unsubscribes the flow manager from the event bus
+	removeFlowHandler func()
 }
 
 // Peer is an instance of the Connection Peer
@@ -243,9 +430,24 @@ func NewEngine(clientCtx context.Context, clientCancel context.CancelFunc, signa
 		stateManager:   stateManager,
 		checks:         checks,
 		connSemaphore:  semaphoregroup.NewSemaphoreGroup(connInitLimit),
+		peerWorkers:    semaphoregroup.NewSemaphoreGroup(connInitLimit),
+		resourceBudget: peer.NewResourceBudget(peer.ResourceLimitsFromEnv()),
 		probeStunTurn:  relay.NewStunTurnProbe(relay.DefaultCacheTTL),
+		routeMetrics:   metrics.NewRecorder(),
+		roles:          system.Roles{EphemeralCI: system.IsCIRunner()},
+		eventBus:       newEventBus(statusRecorder),
+	}
+	engine.turnTransports = newTURNTransportSelection(config.TURNTransports, engine.probeStunTurn.ProbeTURN)
+	if relayManager != nil {
+		engine.relaySelection = newRelaySelection(relayManager, config.PreferredRelay)
+	}
+	if config.InventoryReporting {
+		engine.inventory = system.NewInventoryCollector(config.InventoryInterval)
 	}
 
+	engine.signaler.SetMaintenance(statusRecorder.GetMaintenance())
+	statusRecorder.SetResourceBudget(engine.resourceBudget)
+
 	log.Infof("I am: %s", config.WgPrivateKey.PublicKey().String())
 	return engine
 }
@@ -259,7 +461,9 @@ func (e *Engine) Stop() error {
 	e.syncMsgMux.Lock()
 
 	if e.connMgr != nil {
+		e.peerConnMux.Lock()
 		e.connMgr.Close()
+		e.peerConnMux.Unlock()
 	}
 
 	// stopping network monitor first to avoid starting the engine again
@@ -272,6 +476,10 @@ func (e *Engine) Stop() error {
 		log.Warnf("failed to stop SSH server: %v", err)
 	}
 
+	if err := e.stopDiagnosticEndpoints(); err != nil {
+		log.Warnf("failed to stop diagnostic endpoints: %v", err)
+	}
+
 	e.cleanupSSHConfig()
 
 	if e.ingressGatewayMgr != nil {
@@ -281,6 +489,8 @@ func (e *Engine) Stop() error {
 		e.ingressGatewayMgr = nil
 	}
 
+	e.stopInboundPrompts()
+
 	if e.srWatcher != nil {
 		e.srWatcher.Close()
 	}
@@ -289,11 +499,27 @@ func (e *Engine) Stop() error {
 		e.updateManager.Stop()
 	}
 
+	if e.pluginMgr != nil {
+		e.pluginMgr.Close()
+		e.pluginMgr = nil
+	}
+
+	// closing the DNS publisher before the peers are removed, the published records outlive the engine
+	if e.dnsPublisher != nil {
+		e.dnsPublisher.Close()
+		e.dnsPublisher = nil
+	}
+
 	log.Info("cleaning up status recorder states")
 	e.statusRecorder.ReplaceOfflinePeers([]peer.State{})
-	e.statusRecorder.UpdateDNSStates([]peer.NSGroupState{})
+	e.eventBus.Publish(eventbus.DNSStatesChanged{States: []peer.NSGroupState{}})
 	e.statusRecorder.UpdateRelayStates([]relay.ProbeResult{})
 
+	// the counters of the session are gone with the peers
+	e.collectPeerStats()
+	// the last connections prime the ones of the next engine
+	e.collectPeerConnCache()
+
 	if err := e.removeAllPeers(); err != nil {
 		log.Errorf("failed to remove all peers: %s", err)
 	}
@@ -308,8 +534,21 @@ func (e *Engine) Stop() error {
 	// so dbus and friends don't complain because of a missing interface
 	e.stopDNSServer()
 
+	e.closeShaper()
+
+	e.statusRecorder.PublishLifecycleEvent(peer.EventEngineDown, cProto.SystemEvent_INFO, cProto.SystemEvent_SYSTEM, "Engine stopped", "", nil)
+
+	// closing the hooks after the peers and routes are gone, so they see the corresponding events
+	if e.hooksMgr != nil {
+		e.hooksMgr.Close()
+		e.hooksMgr = nil
+	}
+
 	if e.cancel != nil {
+		// a signal message that is being handled finishes before the context is canceled
+		e.peerConnMux.Lock()
 		e.cancel()
+		e.peerConnMux.Unlock()
 	}
 
 	e.close()
@@ -384,10 +623,23 @@ func (e *Engine) Start(netbirdConfig *mgmProto.NetbirdConfig, mgmtURL *url.URL)
 	e.syncMsgMux.Lock()
 	defer e.syncMsgMux.Unlock()
 
+	err := e.start(netbirdConfig, mgmtURL)
+	if !e.fallbackToNetstack(err) {
+		return err
+	}
+	return e.start(netbirdConfig, mgmtURL)
+}
+
+// start brings up the engine, the caller must hold syncMsgMux
+func (e *Engine) start(netbirdConfig *mgmProto.NetbirdConfig, mgmtURL *url.URL) error {
 	if err := iface.ValidateMTU(e.config.MTU); err != nil {
 		return fmt.Errorf("invalid MTU configuration: %w", err)
 	}
 
+	if err := proxy.SetURL(e.config.ProxyURL); err != nil {
+		return fmt.Errorf("set proxy: %w", err)
+	}
+
 	if e.cancel != nil {
 		e.cancel()
 	}
@@ -404,6 +656,12 @@ func (e *Engine) Start(netbirdConfig *mgmProto.NetbirdConfig, mgmtURL *url.URL)
 	// start flow manager right after interface creation
 	publicKey := e.config.WgPrivateKey.PublicKey()
 	e.flowManager = netflow.NewManager(e.wgInterface, publicKey[:], e.statusRecorder)
+	if e.config.DNSCollectionFilter != nil {
+		e.flowManager.SetDNSFilter(*e.config.DNSCollectionFilter)
+	}
+	e.removeFlowHandler = e.eventBus.Handle(flowEventHandler(e.flowManager))
+	e.startInboundPrompts()
+	e.eventBus.Publish(eventbus.RolesChanged{Roles: e.roles})
 
 	if e.config.RosenpassEnabled {
 		log.Infof("rosenpass is enabled")
@@ -421,6 +679,8 @@ func (e *Engine) Start(netbirdConfig *mgmProto.NetbirdConfig, mgmtURL *url.URL)
 		}
 	}
 	e.stateManager.Start()
+	e.loadPeerStats()
+	e.loadPeerConnCache()
 
 	initialRoutes, dnsConfig, dnsFeatureFlag, err := e.readInitialSettings()
 	if err != nil {
@@ -437,26 +697,34 @@ func (e *Engine) Start(netbirdConfig *mgmProto.NetbirdConfig, mgmtURL *url.URL)
 
 	// Populate DNS cache with NetbirdConfig and management URL for early resolution
 	if err := e.PopulateNetbirdConfig(netbirdConfig, mgmtURL); err != nil {
-		log.Warnf("failed to populate DNS cache: %v", err)
+		dnsLog.Warnf("failed to populate DNS cache: %v", err)
 	}
 
 	e.routeManager = routemanager.NewManager(routemanager.ManagerConfig{
-		Context:             e.ctx,
-		PublicKey:           e.config.WgPrivateKey.PublicKey().String(),
-		DNSRouteInterval:    e.config.DNSRouteInterval,
-		WGInterface:         e.wgInterface,
-		StatusRecorder:      e.statusRecorder,
-		RelayManager:        e.relayManager,
-		InitialRoutes:       initialRoutes,
-		StateManager:        e.stateManager,
-		DNSServer:           dnsServer,
-		DNSFeatureFlag:      dnsFeatureFlag,
-		PeerStore:           e.peerStore,
-		DisableClientRoutes: e.config.DisableClientRoutes,
-		DisableServerRoutes: e.config.DisableServerRoutes,
+		Context:               e.ctx,
+		PublicKey:             e.config.WgPrivateKey.PublicKey().String(),
+		DNSRouteInterval:      e.config.DNSRouteInterval,
+		WGInterface:           e.wgInterface,
+		StatusRecorder:        e.statusRecorder,
+		RelayManager:          e.relayManager,
+		InitialRoutes:         initialRoutes,
+		StateManager:          e.stateManager,
+		DNSServer:             dnsServer,
+		DNSFeatureFlag:        dnsFeatureFlag,
+		PeerStore:             e.peerStore,
+		DisableClientRoutes:   e.config.DisableClientRoutes,
+		DisableServerRoutes:   e.config.DisableServerRoutes,
+		RouteFailoverListener: e.notifyRouteFailover,
+		ExitNodeFailsafe:      e.config.ExitNodeFailsafe,
+		RouteDisconnectGrace:  e.config.RouteDisconnectGrace,
+		ExitNodeExclusions:    e.config.ExitNodeExclusions,
+		CriticalEndpoints:     e.config.CriticalEndpoints,
+		DomainRoutes:          e.config.DomainRoutes,
+		Metrics:               e.routeMetrics,
+		EventBus:              e.eventBus,
 	})
 	if err := e.routeManager.Init(); err != nil {
-		log.Errorf("Failed to initialize route manager: %s", err)
+		routesLog.Errorf("Failed to initialize route manager: %s", err)
 	}
 
 	e.routeManager.SetRouteChangeListener(e.mobileDep.NetworkChangeListener)
@@ -464,7 +732,7 @@ func (e *Engine) Start(netbirdConfig *mgmProto.NetbirdConfig, mgmtURL *url.URL)
 	if err = e.wgInterfaceCreate(); err != nil {
 		log.Errorf("failed creating tunnel interface %s: [%s]", e.config.WgIfaceName, err.Error())
 		e.close()
-		return fmt.Errorf("create wg interface: %w", err)
+		return fmt.Errorf("%w: %w", errCreateWgIface, err)
 	}
 
 	if err := e.createFirewall(); err != nil {
@@ -472,6 +740,8 @@ func (e *Engine) Start(netbirdConfig *mgmProto.NetbirdConfig, mgmtURL *url.URL)
 		return err
 	}
 
+	e.updateWindowsFirewall()
+
 	e.udpMux, err = e.wgInterface.Up()
 	if err != nil {
 		log.Errorf("failed to pull up wgInterface [%s]: %s", e.wgInterface.Name(), err.Error())
@@ -479,9 +749,14 @@ func (e *Engine) Start(netbirdConfig *mgmProto.NetbirdConfig, mgmtURL *url.URL)
 		return fmt.Errorf("up wg interface: %w", err)
 	}
 
-	// if inbound conns are blocked there is no need to create the ACL manager
-	if e.firewall != nil && !e.config.BlockInbound {
-		e.acl = acl.NewDefaultManager(e.firewall)
+	if e.firewall != nil {
+		aclManager := acl.NewDefaultManager(e.firewall)
+		// if inbound conns are blocked only the inbound exceptions are applied
+		if e.config.BlockInbound {
+			aclManager.BlockInbound(e.config.InboundExceptions)
+		}
+		e.acl = aclManager
+		e.restoreFilteringFromCache()
 	}
 
 	err = e.dnsServer.Initialize()
@@ -498,8 +773,47 @@ func (e *Engine) Start(netbirdConfig *mgmProto.NetbirdConfig, mgmtURL *url.URL)
 	e.srWatcher = guard.NewSRWatcher(e.signal, e.relayManager, e.mobileDep.IFaceDiscover, iceCfg)
 	e.srWatcher.Start()
 
-	e.receiveSignalEvents()
-	e.receiveManagementEvents()
+	if len(e.config.Plugins) > 0 {
+		e.pluginMgr = plugin.NewManager(e.config.Plugins, e.statusRecorder)
+		e.pluginMgr.Start(e.ctx)
+	}
+
+	if len(e.config.Hooks) > 0 {
+		e.hooksMgr = hooks.NewManager(e.config.Hooks, e.statusRecorder)
+		e.hooksMgr.Start()
+	}
+
+	if e.config.DNSPublisher != nil {
+		publisher, err := dnspublish.NewPublisher(*e.config.DNSPublisher, e.statusRecorder)
+		if err != nil {
+			log.Warnf("failed to start the DNS publisher: %v", err)
+		} else {
+			e.dnsPublisher = publisher
+			e.dnsPublisher.Start(e.ctx)
+		}
+	}
+
+	if e.config.DiagnosticEndpoints {
+		if err := e.startDiagnosticEndpoints(); err != nil {
+			log.Warnf("failed to start diagnostic endpoints: %v", err)
+		}
+	}
+
+	e.sessionCtx, e.sessionCancel = context.WithCancel(e.ctx)
+	e.receiveSignalEvents(e.sessionCtx, e.signal)
+	e.signal.WaitStreamConnected()
+	e.receiveManagementEvents(e.sessionCtx, e.mgmClient)
+	e.shutdownWg.Add(1)
+	go func() {
+		defer e.shutdownWg.Done()
+		e.bootFromNetworkMapCache()
+	}()
+	e.startPeerStats()
+	e.startPeerConnCache()
+	e.startInventoryReporting()
+	e.startPathMTUProbing()
+	e.startLANBlockRefresh()
+	e.startResumeDetection()
 
 	// starting network monitor at the very last to avoid disruptions
 	e.startNetworkMonitor()
@@ -519,6 +833,18 @@ func (e *Engine) Start(netbirdConfig *mgmProto.NetbirdConfig, mgmtURL *url.URL)
 		}
 	}()
 
+	e.statusRecorder.PublishLifecycleEvent(
+		peer.EventEngineUp,
+		cProto.SystemEvent_INFO,
+		cProto.SystemEvent_SYSTEM,
+		"Engine started",
+		"",
+		map[string]string{
+			"interface": e.wgInterface.Name(),
+			"ip":        e.wgInterface.Address().String(),
+		},
+	)
+
 	return nil
 }
 
@@ -531,21 +857,32 @@ func (e *Engine) InitialUpdateHandling(autoUpdateSettings *mgmProto.AutoUpdateSe
 
 func (e *Engine) createFirewall() error {
 	if e.config.DisableFirewall {
-		log.Infof("firewall is disabled")
+		firewallLog.Infof("firewall is disabled")
+		e.updateFirewallBackend(firewall.Selection{Backend: firewall.BackendNone, Reason: "firewall is disabled"})
 		return nil
 	}
 
 	var err error
-	e.firewall, err = firewall.NewFirewall(e.wgInterface, e.stateManager, e.flowManager.GetLogger(), e.config.DisableServerRoutes, e.config.MTU)
-	if err != nil || e.firewall == nil {
-		log.Errorf("failed creating firewall manager: %s", err)
+	var selection firewall.Selection
+	e.firewall, selection, err = firewall.NewFirewall(e.wgInterface, e.stateManager, e.flowManager.GetLogger(), e.config.DisableServerRoutes, e.config.MTU, e.config.FirewallBackend)
+	e.updateFirewallBackend(selection)
+	if err != nil {
+		firewallLog.Errorf("failed creating firewall manager: %s", err)
 		return nil
 	}
+	if e.firewall == nil {
+		firewallLog.Infof("running without a firewall: %s", selection)
+		return nil
+	}
+	firewallLog.Infof("using firewall backend %s", selection)
+	e.firewall.SetLogLevel(logging.SubsystemLevel(logging.Firewall))
 
 	if err := e.initFirewall(); err != nil {
 		return err
 	}
 
+	e.updateKillSwitch()
+
 	return nil
 }
 
@@ -555,9 +892,10 @@ func (e *Engine) initFirewall() error {
 		return fmt.Errorf("set firewall: %w", err)
 	}
 
-	if e.config.BlockLANAccess {
-		e.blockLanAccess()
-	}
+	// the rules of a previous firewall are gone with it
+	e.lanBlockRules = nil
+	e.updateLANBlock()
+	e.setInboundPromptsFirewall()
 
 	if e.rpManager == nil || !e.config.RosenpassEnabled {
 		return nil
@@ -585,41 +923,6 @@ func (e *Engine) initFirewall() error {
 	return nil
 }
 
-func (e *Engine) blockLanAccess() {
-	if e.config.BlockInbound {
-		// no need to set up extra deny rules if inbound is already blocked in general
-		return
-	}
-
-	var merr *multierror.Error
-
-	// TODO: keep this updated
-	toBlock, err := getInterfacePrefixes()
-	if err != nil {
-		merr = multierror.Append(merr, fmt.Errorf("get local addresses: %w", err))
-	}
-
-	log.Infof("blocking route LAN access for networks: %v", toBlock)
-	v4 := netip.PrefixFrom(netip.IPv4Unspecified(), 0)
-	for _, network := range toBlock {
-		if _, err := e.firewall.AddRouteFiltering(
-			nil,
-			[]netip.Prefix{v4},
-			firewallManager.Network{Prefix: network},
-			firewallManager.ProtocolALL,
-			nil,
-			nil,
-			firewallManager.ActionDrop,
-		); err != nil {
-			merr = multierror.Append(merr, fmt.Errorf("add fw rule for network %s: %w", network, err))
-		}
-	}
-
-	if merr != nil {
-		log.Warnf("encountered errors blocking IPs to block LAN access: %v", nberrors.FormatErrorOrNil(merr))
-	}
-}
-
 // modifyPeers updates peers that have been modified (e.g. IP address has been changed).
 // It closes the existing connection, removes it from the peerConns map, and creates a new one.
 func (e *Engine) modifyPeers(peersUpdate []*mgmProto.RemotePeerConfig) error {
@@ -638,6 +941,21 @@ func (e *Engine) modifyPeers(peersUpdate []*mgmProto.RemotePeerConfig) error {
 			continue
 		}
 
+		if currentPeer.ICEPolicy() != e.peerICEPolicy(p) {
+			modified = append(modified, p)
+			continue
+		}
+
+		if !inactivityConfigEqual(currentPeer.LazyInactivity(), peerLazyInactivity(p)) {
+			modified = append(modified, p)
+			continue
+		}
+
+		if !preSharedKeyEqual(currentPeer.WgConfig().PreSharedKey, e.peerPreSharedKey(p)) {
+			modified = append(modified, p)
+			continue
+		}
+
 		allowedIPs, ok := e.peerStore.AllowedIPs(peerPubKey)
 		if !ok {
 			continue
@@ -653,20 +971,15 @@ func (e *Engine) modifyPeers(peersUpdate []*mgmProto.RemotePeerConfig) error {
 	}
 
 	// second, close all modified connections and remove them from the state map
+	modifiedKeys := make([]string, 0, len(modified))
 	for _, p := range modified {
-		err := e.removePeer(p.GetWgPubKey())
-		if err != nil {
-			return err
-		}
+		modifiedKeys = append(modifiedKeys, p.GetWgPubKey())
 	}
-	// third, add the peer connections again
-	for _, p := range modified {
-		err := e.addNewPeer(p)
-		if err != nil {
-			return err
-		}
+	if err := e.runPeerWorkers(modifiedKeys, e.removePeer); err != nil {
+		return err
 	}
-	return nil
+	// third, add the peer connections again
+	return e.addNewPeers(modified)
 }
 
 // removePeers finds and removes peers that do not exist anymore in the network map received from the Management Service.
@@ -679,32 +992,62 @@ func (e *Engine) removePeers(peersUpdate []*mgmProto.RemotePeerConfig) error {
 
 	toRemove := util.SliceDiff(e.peerStore.PeersPubKey(), newPeers)
 
-	for _, p := range toRemove {
-		err := e.removePeer(p)
-		if err != nil {
+	return e.runPeerWorkers(toRemove, func(peerKey string) error {
+		if err := e.removePeer(peerKey); err != nil {
 			return err
 		}
-		log.Infof("removed peer %s", p)
-	}
-	return nil
+		log.Infof("removed peer %s", peerKey)
+		return nil
+	})
 }
 
 func (e *Engine) removeAllPeers() error {
 	log.Debugf("removing all peer connections")
-	for _, p := range e.peerStore.PeersPubKey() {
-		err := e.removePeer(p)
-		if err != nil {
-			return err
+	return e.runPeerWorkers(e.peerStore.PeersPubKey(), e.removePeer)
+}
+
+// runPeerWorkers calls work for the peers concurrently and aggregates the errors. Every worker holds a peerWorkers
+// slot, so large network maps are applied with at most connInitLimit workers.
+func (e *Engine) runPeerWorkers(peerKeys []string, work func(peerKey string) error) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		merr *multierror.Error
+	)
+
+	for _, peerKey := range peerKeys {
+		// not bound to the engine context, the peers are also removed on shutdown
+		if err := e.peerWorkers.Add(context.Background()); err != nil {
+			mu.Lock()
+			merr = multierror.Append(merr, fmt.Errorf("wait for peer worker: %w", err))
+			mu.Unlock()
+			break
 		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer e.peerWorkers.Done()
+
+			if err := work(peerKey); err != nil {
+				mu.Lock()
+				merr = multierror.Append(merr, fmt.Errorf("peer %s: %w", peerKey, err))
+				mu.Unlock()
+			}
+		}()
 	}
-	return nil
+	wg.Wait()
+
+	return nberrors.FormatErrorOrNil(merr)
 }
 
 // removePeer closes an existing peer connection and removes a peer
 func (e *Engine) removePeer(peerKey string) error {
 	log.Debugf("removing peer from engine %s", peerKey)
 
+	e.peerConnMux.Lock()
 	e.connMgr.RemovePeerConn(peerKey)
+	e.peerConnMux.Unlock()
 
 	err := e.statusRecorder.RemovePeer(peerKey)
 	if err != nil {
@@ -722,7 +1065,7 @@ func (e *Engine) PopulateNetbirdConfig(netbirdConfig *mgmProto.NetbirdConfig, mg
 	// Populate management URL if provided
 	if mgmtURL != nil {
 		if err := e.dnsServer.PopulateManagementDomain(mgmtURL); err != nil {
-			log.Warnf("failed to populate DNS cache with management URL: %v", err)
+			dnsLog.Warnf("failed to populate DNS cache with management URL: %v", err)
 		}
 	}
 
@@ -781,6 +1124,44 @@ func (e *Engine) handleSync(update *mgmProto.SyncResponse) error {
 		return e.ctx.Err()
 	}
 
+	if serverTime := update.GetServerTime(); serverTime != nil && e.acl != nil {
+		e.acl.SetClockSkew(time.Since(serverTime.AsTime()))
+	}
+
+	var changes *networkMapChanges
+	if update.GetNetworkMap().GetDelta() {
+		nm, err := networkmap.ApplyDelta(e.syncCache.GetNetworkMap(), update.GetNetworkMap())
+		if err != nil {
+			return fmt.Errorf("%w: %w", mgm.ErrNetworkMapResync, err)
+		}
+		log.Debugf("applying network map delta %d -> %d with %d changed and %d removed peers",
+			update.GetNetworkMap().GetBaseSerial(), nm.GetSerial(),
+			len(update.GetNetworkMap().GetRemotePeers())+len(update.GetNetworkMap().GetOfflinePeers()),
+			len(update.GetNetworkMap().GetRemovedPeers()))
+		changes = deltaChanges(e.syncCache.GetNetworkMap(), update.GetNetworkMap())
+		update = &mgmProto.SyncResponse{
+			NetbirdConfig: update.GetNetbirdConfig(),
+			NetworkMap:    nm,
+			Checks:        update.GetChecks(),
+		}
+	}
+
+	if update.GetNetworkMap() != nil {
+		e.leaveOfflineCatalogue()
+	}
+
+	if err := e.applySyncResponse(update, changes); err != nil {
+		return err
+	}
+
+	e.cacheSyncResponse(update)
+	return nil
+}
+
+// applySyncResponse applies an update received from management or read from the network map cache. The changes
+// limit the update to the parts a delta touched, nil applies the full network map.
+// The caller must hold syncMsgMux.
+func (e *Engine) applySyncResponse(update *mgmProto.SyncResponse, changes *networkMapChanges) error {
 	if update.NetworkMap != nil && update.NetworkMap.PeerConfig != nil {
 		e.handleAutoUpdateVersion(update.NetworkMap.PeerConfig.AutoUpdate, false)
 	}
@@ -797,10 +1178,7 @@ func (e *Engine) handleSync(update *mgmProto.SyncResponse) error {
 			return fmt.Errorf("update STUNs: %w", err)
 		}
 
-		var stunTurn []*stun.URI
-		stunTurn = append(stunTurn, e.STUNs...)
-		stunTurn = append(stunTurn, e.TURNs...)
-		e.stunTurn.Store(stunTurn)
+		e.storeStunTurn()
 
 		err = e.handleRelayUpdate(wCfg.GetRelay())
 		if err != nil {
@@ -813,7 +1191,7 @@ func (e *Engine) handleSync(update *mgmProto.SyncResponse) error {
 		}
 
 		if err := e.PopulateNetbirdConfig(wCfg, nil); err != nil {
-			log.Warnf("Failed to update DNS server config: %v", err)
+			dnsLog.Warnf("Failed to update DNS server config: %v", err)
 		}
 
 		// todo update signal
@@ -834,16 +1212,31 @@ func (e *Engine) handleSync(update *mgmProto.SyncResponse) error {
 		log.Debugf("sync response persisted with serial %d", nm.GetSerial())
 	}
 
+	// let plugins veto and inject elements within their policy limits. Their elements aren't part of the delta, a
+	// map they changed is applied in full.
+	if filtered := e.pluginMgr.ApplyNetworkMap(e.ctx, nm); filtered != nm {
+		nm = filtered
+		changes = nil
+	}
+
 	// only apply new changes and ignore old ones
-	if err := e.updateNetworkMap(nm); err != nil {
+	if err := e.applyNetworkMap(nm, changes); err != nil {
 		return err
 	}
 
-	e.statusRecorder.PublishEvent(cProto.SystemEvent_INFO, cProto.SystemEvent_SYSTEM, "Network map updated", "", nil)
+	e.updateTrafficShaping()
+
+	e.eventBus.Publish(eventbus.NetworkMapUpdated{Serial: nm.GetSerial()})
+	e.networkMapApplied.Store(true)
 
 	return nil
 }
 
+// NetworkMapApplied reports whether the engine applied a network map, the interface and DNS are set up by then
+func (e *Engine) NetworkMapApplied() bool {
+	return e.networkMapApplied.Load()
+}
+
 func (e *Engine) handleRelayUpdate(update *mgmProto.RelayConfig) error {
 	if update != nil {
 		// when we receive token we expect valid address list too
@@ -863,6 +1256,12 @@ func (e *Engine) handleRelayUpdate(update *mgmProto.RelayConfig) error {
 	} else {
 		e.relayManager.UpdateServerURLs(nil)
 	}
+	e.relaySelection.updateServers(e.ctx, &e.shutdownWg, update.GetUrls())
+
+	// the relay servers are exceptions of the kill switch
+	if e.config.KillSwitch {
+		e.updateKillSwitch()
+	}
 
 	return nil
 }
@@ -883,6 +1282,17 @@ func toFlowLoggerConfig(config *mgmProto.FlowConfig) (*nftypes.FlowConfig, error
 	if config.GetInterval() == nil {
 		return nil, errors.New("flow interval is nil")
 	}
+
+	sampling, err := toFlowSampling(config.GetSampling())
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := toFlowFilter(config.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+
 	return &nftypes.FlowConfig{
 		Enabled:            config.GetEnabled(),
 		Counters:           config.GetCounters(),
@@ -892,9 +1302,71 @@ func toFlowLoggerConfig(config *mgmProto.FlowConfig) (*nftypes.FlowConfig, error
 		Interval:           config.GetInterval().AsDuration(),
 		DNSCollection:      config.GetDnsCollection(),
 		ExitNodeCollection: config.GetExitNodeCollection(),
+		Sampling:           sampling,
+		Filter:             filter,
 	}, nil
 }
 
+func toFlowSampling(config map[string]*mgmProto.FlowSampling) (map[nftypes.DeviceClass]nftypes.FlowSampling, error) {
+	sampling := make(map[nftypes.DeviceClass]nftypes.FlowSampling, len(config))
+	for class, s := range config {
+		if s.GetIpv4PrefixLength() > 32 || s.GetIpv6PrefixLength() > 128 {
+			return nil, fmt.Errorf("invalid flow aggregation prefix lengths /%d and /%d for %s",
+				s.GetIpv4PrefixLength(), s.GetIpv6PrefixLength(), class)
+		}
+		if s.GetPortRangeSize() > math.MaxUint16 {
+			return nil, fmt.Errorf("invalid flow aggregation port range size %d for %s", s.GetPortRangeSize(), class)
+		}
+
+		sampling[nftypes.DeviceClass(class)] = nftypes.FlowSampling{
+			Rate:             s.GetRate(),
+			IPv4PrefixLength: uint8(s.GetIpv4PrefixLength()),
+			IPv6PrefixLength: uint8(s.GetIpv6PrefixLength()),
+			PortRangeSize:    uint16(s.GetPortRangeSize()),
+			MaxFlows:         s.GetMaxFlows(),
+		}
+	}
+	return sampling, nil
+}
+
+func toFlowFilter(config *mgmProto.FlowFilter) (nftypes.FlowFilter, error) {
+	filter := nftypes.FlowFilter{
+		RoutedOnly:         config.GetRoutedOnly(),
+		IngressGatewayOnly: config.GetIngressGatewayOnly(),
+	}
+
+	for _, entry := range config.GetExcludedPeerPorts() {
+		portRange, err := parseFlowPortRange(entry)
+		if err != nil {
+			return nftypes.FlowFilter{}, fmt.Errorf("invalid excluded flow ports %q: %w", entry, err)
+		}
+		filter.ExcludedPeerPorts = append(filter.ExcludedPeerPorts, portRange)
+	}
+
+	for _, entry := range config.GetPeerPrefixes() {
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nftypes.FlowFilter{}, fmt.Errorf("invalid flow peer prefix %q: %w", entry, err)
+		}
+		filter.PeerPrefixes = append(filter.PeerPrefixes, prefix.Masked())
+	}
+
+	return filter, nil
+}
+
+// flowDeviceClass classifies this peer by the routes it serves, exit nodes generate the most flows
+func flowDeviceClass(serverRoutes map[route.ID]*route.Route) nftypes.DeviceClass {
+	if len(serverRoutes) == 0 {
+		return nftypes.DeviceClassClient
+	}
+	for _, r := range serverRoutes {
+		if !r.IsDynamic() && r.Network.Bits() == 0 {
+			return nftypes.DeviceClassExitNode
+		}
+	}
+	return nftypes.DeviceClassRoutingPeer
+}
+
 // updateChecksIfNew updates checks if there are changes and sync new meta with management
 func (e *Engine) updateChecksIfNew(checks []*mgmProto.Checks) error {
 	// if checks are equal, we skip the update
@@ -903,7 +1375,12 @@ func (e *Engine) updateChecksIfNew(checks []*mgmProto.Checks) error {
 	}
 	e.checks = checks
 
-	info, err := system.GetInfoWithChecks(e.ctx, checks)
+	return e.syncMeta()
+}
+
+// syncMeta sends the system info with the current checks, flags and roles to management
+func (e *Engine) syncMeta() error {
+	info, err := system.GetInfoWithChecks(e.ctx, e.checks)
 	if err != nil {
 		log.Warnf("failed to get system info with checks: %v", err)
 		info = system.GetInfo(e.ctx)
@@ -925,6 +1402,8 @@ func (e *Engine) updateChecksIfNew(checks []*mgmProto.Checks) error {
 		e.config.EnableSSHRemotePortForwarding,
 		e.config.DisableSSHAuth,
 	)
+	info.Roles = e.roles
+	info.Inventory = e.inventory.Cached()
 
 	if err := e.mgmClient.SyncMeta(info); err != nil {
 		log.Errorf("could not sync meta: error %s", err)
@@ -962,8 +1441,8 @@ func (e *Engine) updateConfig(conf *mgmProto.PeerConfig) error {
 }
 
 // receiveManagementEvents connects to the Management Service event stream to receive updates from the management service
-// E.g. when a new peer has been registered and we are allowed to connect to it.
-func (e *Engine) receiveManagementEvents() {
+// E.g. when a new peer has been registered and we are allowed to connect to it. The stream is closed with the context.
+func (e *Engine) receiveManagementEvents(ctx context.Context, mgmClient mgm.Client) {
 	e.shutdownWg.Add(1)
 	go func() {
 		defer e.shutdownWg.Done()
@@ -989,14 +1468,35 @@ func (e *Engine) receiveManagementEvents() {
 			e.config.EnableSSHRemotePortForwarding,
 			e.config.DisableSSHAuth,
 		)
+		info.Roles = e.roles
+		info.Inventory = e.inventory.Get(e.ctx)
+
+		for {
+			err = mgmClient.Sync(ctx, info, func(update *mgmProto.SyncResponse) error {
+				// the stream of the replaced connection doesn't apply updates anymore
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				return e.handleSync(update)
+			})
+			if err == nil || ctx.Err() != nil {
+				break
+			}
+			if !e.keepRunningOffline(err) {
+				// happens if management is unavailable for a long time.
+				// We want to cancel the operation of the whole client
+				_ = CtxGetState(e.ctx).Wrap(ErrResetConnection)
+				e.clientCancel()
+				return
+			}
 
-		err = e.mgmClient.Sync(e.ctx, info, e.handleSync)
-		if err != nil {
-			// happens if management is unavailable for a long time.
-			// We want to cancel the operation of the whole client
-			_ = CtxGetState(e.ctx).Wrap(ErrResetConnection)
-			e.clientCancel()
-			return
+			log.Warnf("management sync failed, running from the cached network map and retrying in %s: %v",
+				managementSyncRetryInterval, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(managementSyncRetryInterval):
+			}
 		}
 		log.Debugf("stopped receiving updates from Management Service")
 	}()
@@ -1008,7 +1508,7 @@ func (e *Engine) updateSTUNs(stuns []*mgmProto.HostConfig) error {
 		return nil
 	}
 	var newSTUNs []*stun.URI
-	log.Debugf("got STUNs update from Management Service, updating")
+	iceLog.Debugf("got STUNs update from Management Service, updating")
 	for _, s := range stuns {
 		url, err := stun.ParseURI(s.Uri)
 		if err != nil {
@@ -1026,7 +1526,7 @@ func (e *Engine) updateTURNs(turns []*mgmProto.ProtectedHostConfig) error {
 		return nil
 	}
 	var newTURNs []*stun.URI
-	log.Debugf("got TURNs update from Management Service, updating")
+	iceLog.Debugf("got TURNs update from Management Service, updating")
 	for _, turn := range turns {
 		url, err := stun.ParseURI(turn.HostConfig.Uri)
 		if err != nil {
@@ -1036,12 +1536,21 @@ func (e *Engine) updateTURNs(turns []*mgmProto.ProtectedHostConfig) error {
 		url.Password = turn.Password
 		newTURNs = append(newTURNs, url)
 	}
-	e.TURNs = newTURNs
+	e.announcedTURNs = newTURNs
+	e.applyTURNTransports(newTURNs)
 
 	return nil
 }
 
 func (e *Engine) updateNetworkMap(networkMap *mgmProto.NetworkMap) error {
+	return e.applyNetworkMap(networkMap, nil)
+}
+
+// applyNetworkMap applies the network map, limited to the changed parts if changes is set
+func (e *Engine) applyNetworkMap(networkMap *mgmProto.NetworkMap, changes *networkMapChanges) error {
+	// the SSH server setup in updateConfig depends on the inbound exceptions
+	e.inboundExceptions = networkMap.GetInboundExceptions()
+
 	// intentionally leave it before checking serial because for now it can happen that peer IP changed but serial didn't
 	if networkMap.GetPeerConfig() != nil {
 		err := e.updateConfig(networkMap.GetPeerConfig())
@@ -1056,7 +1565,10 @@ func (e *Engine) updateNetworkMap(networkMap *mgmProto.NetworkMap) error {
 		return nil
 	}
 
-	if err := e.connMgr.UpdatedRemoteFeatureFlag(e.ctx, networkMap.GetPeerConfig().GetLazyConnectionEnabled()); err != nil {
+	e.peerConnMux.Lock()
+	err := e.connMgr.UpdatedRemoteFeatureFlag(e.ctx, networkMap.GetPeerConfig().GetLazyConnectionEnabled())
+	e.peerConnMux.Unlock()
+	if err != nil {
 		log.Errorf("failed to update lazy connection feature flag: %v", err)
 	}
 
@@ -1082,9 +1594,10 @@ func (e *Engine) updateNetworkMap(networkMap *mgmProto.NetworkMap) error {
 	}
 
 	dnsConfig := toDNSConfig(protoDNSConfig, e.wgInterface.Address().Network)
+	addOfflinePeerRecords(&dnsConfig, networkMap.GetOfflinePeers())
 
 	if err := e.dnsServer.UpdateDNSServer(serial, dnsConfig); err != nil {
-		log.Errorf("failed to update dns server, err: %v", err)
+		dnsLog.Errorf("failed to update dns server, err: %v", err)
 	}
 
 	e.routeManager.SetDNSForwarderPort(dnsConfig.ForwarderPort)
@@ -1093,23 +1606,45 @@ func (e *Engine) updateNetworkMap(networkMap *mgmProto.NetworkMap) error {
 	routes := toRoutes(networkMap.GetRoutes())
 	serverRoutes, clientRoutes := e.routeManager.ClassifyRoutes(routes)
 
-	// lazy mgr needs to be aware of which routes are available before they are applied
-	if e.connMgr != nil {
-		e.connMgr.UpdateRouteHAMap(clientRoutes)
-		log.Debugf("updated lazy connection manager with %d HA groups", len(clientRoutes))
+	dnsRouteFeatureFlag := toDNSFeatureFlag(networkMap)
+	mapUpdate := metrics.NetworkMapUpdate{
+		Serial:        serial,
+		Time:          time.Now(),
+		ClientRoutes:  countHARoutes(clientRoutes),
+		ServerRoutes:  len(serverRoutes),
+		FirewallRules: len(networkMap.GetRoutesFirewallRules()),
 	}
 
-	dnsRouteFeatureFlag := toDNSFeatureFlag(networkMap)
-	if err := e.routeManager.UpdateRoutes(serial, serverRoutes, clientRoutes, dnsRouteFeatureFlag); err != nil {
-		log.Errorf("failed to update routes: %v", err)
+	if changes.routesChanged() {
+		// lazy mgr needs to be aware of which routes are available before they are applied
+		if e.connMgr != nil {
+			e.peerConnMux.Lock()
+			e.connMgr.UpdateRouteHAMap(clientRoutes)
+			e.peerConnMux.Unlock()
+			log.Debugf("updated lazy connection manager with %d HA groups", len(clientRoutes))
+		}
+
+		routesStart := time.Now()
+		if err := e.routeManager.UpdateRoutes(serial, serverRoutes, clientRoutes, dnsRouteFeatureFlag); err != nil {
+			routesLog.Errorf("failed to update routes: %v", err)
+		}
+		mapUpdate.RoutesDuration = time.Since(routesStart)
 	}
 
-	if e.acl != nil {
+	if e.acl != nil && changes.rulesChanged() {
+		firewallStart := time.Now()
 		e.acl.ApplyFiltering(networkMap, dnsRouteFeatureFlag)
+		mapUpdate.FirewallDuration = time.Since(firewallStart)
+		e.updateFirewallSetStats()
 	}
+	e.routeMetrics.ObserveNetworkMap(mapUpdate)
+	routesLog.Debugf("applied %d client and %d server routes in %s, %d route firewall rules in %s",
+		mapUpdate.ClientRoutes, mapUpdate.ServerRoutes, mapUpdate.RoutesDuration, mapUpdate.FirewallRules, mapUpdate.FirewallDuration)
 
-	fwdEntries := toRouteDomains(e.config.WgPrivateKey.PublicKey().String(), routes)
-	e.updateDNSForwarder(dnsRouteFeatureFlag, fwdEntries)
+	if changes.routesChanged() {
+		fwdEntries := toRouteDomains(e.config.WgPrivateKey.PublicKey().String(), routes)
+		e.updateDNSForwarder(dnsRouteFeatureFlag, fwdEntries)
+	}
 
 	// Ingress forward rules
 	forwardingRules, err := e.updateForwardRules(networkMap.GetForwardingRules())
@@ -1117,9 +1652,12 @@ func (e *Engine) updateNetworkMap(networkMap *mgmProto.NetworkMap) error {
 		log.Errorf("failed to update forward rules, err: %v", err)
 	}
 
+	e.updateRolesIfNew(detectRoles(serverRoutes, forwardingRules))
+
 	log.Debugf("got peers update from Management Service, total peers to connect to = %d", len(networkMap.GetRemotePeers()))
 
 	e.updateOfflinePeers(networkMap.GetOfflinePeers())
+	e.updateWakeHints(slices.Concat(networkMap.GetRemotePeers(), networkMap.GetOfflinePeers()))
 
 	// Filter out own peer from the remote peers list
 	localPubKey := e.config.WgPrivateKey.PublicKey().String()
@@ -1130,6 +1668,8 @@ func (e *Engine) updateNetworkMap(networkMap *mgmProto.NetworkMap) error {
 		}
 	}
 
+	e.updateReversePathFilter(serverRoutes, remotePeers)
+
 	// cleanup request, most likely our peer has been deleted
 	if networkMap.GetRemotePeersIsEmpty() {
 		err := e.removeAllPeers()
@@ -1138,27 +1678,18 @@ func (e *Engine) updateNetworkMap(networkMap *mgmProto.NetworkMap) error {
 			return err
 		}
 	} else {
-		err := e.removePeers(remotePeers)
-		if err != nil {
-			return err
-		}
-
-		err = e.modifyPeers(remotePeers)
-		if err != nil {
-			return err
-		}
-
-		err = e.addNewPeers(remotePeers)
-		if err != nil {
+		if err := e.updatePeers(remotePeers, changes); err != nil {
 			return err
 		}
 
 		e.statusRecorder.FinishPeerListModifications()
 
-		e.updatePeerSSHHostKeys(remotePeers)
+		if changes.peersChanged() {
+			e.updatePeerSSHHostKeys(remotePeers)
 
-		if err := e.updateSSHClientConfig(remotePeers); err != nil {
-			log.Warnf("failed to update SSH client config: %v", err)
+			if err := e.updateSSHClientConfig(remotePeers); err != nil {
+				log.Warnf("failed to update SSH client config: %v", err)
+			}
 		}
 
 		e.updateSSHServerAuth(networkMap.GetSshAuth())
@@ -1166,7 +1697,9 @@ func (e *Engine) updateNetworkMap(networkMap *mgmProto.NetworkMap) error {
 
 	// must set the exclude list after the peers are added. Without it the manager can not figure out the peers parameters from the store
 	excludedLazyPeers := e.toExcludedLazyPeers(forwardingRules, remotePeers)
+	e.peerConnMux.Lock()
 	e.connMgr.SetExcludeList(e.ctx, excludedLazyPeers)
+	e.peerConnMux.Unlock()
 
 	e.networkSerial = serial
 
@@ -1195,7 +1728,7 @@ func toRoutes(protoRoutes []*mgmProto.Route) []*route.Route {
 		if len(protoRoute.Domains) == 0 {
 			var err error
 			if prefix, err = netip.ParsePrefix(protoRoute.Network); err != nil {
-				log.Errorf("Failed to parse prefix %s: %v", protoRoute.Network, err)
+				routesLog.Errorf("Failed to parse prefix %s: %v", protoRoute.Network, err)
 				continue
 			}
 		}
@@ -1301,67 +1834,132 @@ func toDNSConfig(protoDNSConfig *mgmProto.DNSConfig, network netip.Prefix) nbdns
 
 func (e *Engine) updateOfflinePeers(offlinePeers []*mgmProto.RemotePeerConfig) {
 	replacement := make([]peer.State, len(offlinePeers))
+	absentPeers := make(map[string]string)
 	for i, offlinePeer := range offlinePeers {
 		log.Debugf("added offline peer %s", offlinePeer.Fqdn)
+		allowedIPs := parseAllowedIPs(offlinePeer.GetAllowedIps())
+		var ip string
+		if len(allowedIPs) > 0 {
+			ip = allowedIPs[0].Addr().String()
+		}
 		replacement[i] = peer.State{
-			IP:               strings.Join(offlinePeer.GetAllowedIps(), ","),
+			IP:               ip,
 			PubKey:           offlinePeer.GetWgPubKey(),
 			FQDN:             offlinePeer.GetFqdn(),
+			AllowedIPs:       allowedIPs,
+			Offline:          true,
+			OfflineReason:    peerOfflineReason(offlinePeer),
 			ConnStatus:       peer.StatusIdle,
 			ConnStatusUpdate: time.Now(),
 			Mux:              new(sync.RWMutex),
 		}
+		if reason := replacement[i].OfflineReason; reason != peer.OfflineReasonNone && offlinePeer.GetFqdn() != "" {
+			absentPeers[offlinePeer.GetFqdn()] = string(reason)
+		}
 	}
 	e.statusRecorder.ReplaceOfflinePeers(replacement)
+
+	if e.dnsServer != nil {
+		e.dnsServer.UpdateAbsentPeers(absentPeers)
+	}
+}
+
+// parseAllowedIPs parses the allowed IPs of a peer, skipping invalid entries
+func parseAllowedIPs(allowedIPs []string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(allowedIPs))
+	for _, ipString := range allowedIPs {
+		prefix, err := netip.ParsePrefix(ipString)
+		if err != nil {
+			log.Warnf("failed to parse allowed IP %s: %v", ipString, err)
+			continue
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes
 }
 
-// addNewPeers adds peers that were not know before but arrived from the Management service with the update
+// addNewPeers adds peers that were not know before but arrived from the Management service with the update.
+// The connections are created concurrently and handed to the connection manager once all workers are done.
 func (e *Engine) addNewPeers(peersUpdate []*mgmProto.RemotePeerConfig) error {
+	configs := make(map[string]*mgmProto.RemotePeerConfig, len(peersUpdate))
+	newPeers := make([]string, 0, len(peersUpdate))
 	for _, p := range peersUpdate {
-		err := e.addNewPeer(p)
+		peerKey := p.GetWgPubKey()
+		if _, ok := configs[peerKey]; ok {
+			continue
+		}
+		if _, ok := e.peerStore.PeerConn(peerKey); ok {
+			continue
+		}
+		configs[peerKey] = p
+		newPeers = append(newPeers, peerKey)
+	}
+
+	var mu sync.Mutex
+	conns := make(map[string]*peer.Conn, len(newPeers))
+	err := e.runPeerWorkers(newPeers, func(peerKey string) error {
+		conn, err := e.newPeerConn(configs[peerKey])
 		if err != nil {
 			return err
 		}
+		mu.Lock()
+		conns[peerKey] = conn
+		mu.Unlock()
+		return nil
+	})
+
+	var merr *multierror.Error
+	if err != nil {
+		merr = multierror.Append(merr, err)
 	}
-	return nil
+	for _, peerKey := range newPeers {
+		conn, ok := conns[peerKey]
+		if !ok {
+			continue
+		}
+		// locked per peer, opening a connection may wait for a connSemaphore slot
+		e.peerConnMux.Lock()
+		exists := e.connMgr.AddPeerConn(e.ctx, peerKey, conn)
+		e.peerConnMux.Unlock()
+		if exists {
+			conn.Close(false)
+			merr = multierror.Append(merr, fmt.Errorf("peer already exists: %s", peerKey))
+		}
+	}
+
+	return nberrors.FormatErrorOrNil(merr)
 }
 
-// addNewPeer add peer if connection doesn't exist
-func (e *Engine) addNewPeer(peerConfig *mgmProto.RemotePeerConfig) error {
+// newPeerConn creates the connection of a new peer and adds the peer to the status recorder
+func (e *Engine) newPeerConn(peerConfig *mgmProto.RemotePeerConfig) (*peer.Conn, error) {
 	peerKey := peerConfig.GetWgPubKey()
 	peerIPs := make([]netip.Prefix, 0, len(peerConfig.GetAllowedIps()))
-	if _, ok := e.peerStore.PeerConn(peerKey); ok {
-		return nil
-	}
 
 	for _, ipString := range peerConfig.GetAllowedIps() {
 		allowedNetIP, err := netip.ParsePrefix(ipString)
 		if err != nil {
 			log.Errorf("failed to parse allowedIPS: %v", err)
-			return err
+			return nil, err
 		}
 		peerIPs = append(peerIPs, allowedNetIP)
 	}
 
-	conn, err := e.createPeerConn(peerKey, peerIPs, peerConfig.AgentVersion)
+	conn, err := e.createPeerConn(peerKey, peerIPs, peerConfig)
 	if err != nil {
-		return fmt.Errorf("create peer connection: %w", err)
+		return nil, fmt.Errorf("create peer connection: %w", err)
 	}
 
 	err = e.statusRecorder.AddPeer(peerKey, peerConfig.Fqdn, peerIPs[0].Addr().String())
 	if err != nil {
 		log.Warnf("error adding peer %s to status recorder, got error: %v", peerKey, err)
+	} else if err := e.statusRecorder.UpdatePeerAllowedIPs(peerKey, peerIPs); err != nil {
+		log.Warnf("error updating allowed IPs of peer %s, got error: %v", peerKey, err)
 	}
 
-	if exists := e.connMgr.AddPeerConn(e.ctx, peerKey, conn); exists {
-		conn.Close(false)
-		return fmt.Errorf("peer already exists: %s", peerKey)
-	}
-
-	return nil
+	return conn, nil
 }
 
-func (e *Engine) createPeerConn(pubKey string, allowedIPs []netip.Prefix, agentVersion string) (*peer.Conn, error) {
+func (e *Engine) createPeerConn(pubKey string, allowedIPs []netip.Prefix, peerConfig *mgmProto.RemotePeerConfig) (*peer.Conn, error) {
 	log.Debugf("creating peer connection %s", pubKey)
 
 	wgConfig := peer.WgConfig{
@@ -1369,16 +1967,19 @@ func (e *Engine) createPeerConn(pubKey string, allowedIPs []netip.Prefix, agentV
 		WgListenPort: e.config.WgPort,
 		WgInterface:  e.wgInterface,
 		AllowedIps:   allowedIPs,
-		PreSharedKey: e.config.PreSharedKey,
+		PreSharedKey: e.peerPreSharedKey(peerConfig),
+
+		PersistentKeepalive:    e.peerKeepAlive(pubKey),
+		HandshakeTimeout:       e.config.WgHandshakeTimeout,
+		HandshakeRetryInterval: e.config.WgHandshakeRetryInterval,
+		ResponderDelay:         e.config.WgResponderDelay,
 	}
 
-	// randomize connection timeout
-	timeout := time.Duration(rand.Intn(PeerConnectionTimeoutMax-PeerConnectionTimeoutMin)+PeerConnectionTimeoutMin) * time.Millisecond
 	config := peer.ConnConfig{
 		Key:          pubKey,
 		LocalKey:     e.config.WgPrivateKey.PublicKey().String(),
-		AgentVersion: agentVersion,
-		Timeout:      timeout,
+		AgentVersion: peerConfig.GetAgentVersion(),
+		Backoff:      e.config.ConnBackoff,
 		WgConfig:     wgConfig,
 		LocalWgPort:  e.config.WgPort,
 		RosenpassConfig: peer.RosenpassConfig{
@@ -1386,8 +1987,14 @@ func (e *Engine) createPeerConn(pubKey string, allowedIPs []netip.Prefix, agentV
 			Addr:           e.getRosenpassAddr(),
 			PermissiveMode: e.config.RosenpassPermissive,
 		},
-		ICEConfig: e.createICEConfig(),
+		ICEConfig:      e.createICEConfig(),
+		LazyInactivity: peerLazyInactivity(peerConfig),
+	}
+	if bond, ok := e.config.BondedPeers[pubKey]; ok {
+		config.Bond = &bond
 	}
+	config.Priming = e.peerConnPriming(pubKey)
+	config.ICEConfig.Policy = e.peerICEPolicy(peerConfig)
 
 	serviceDependencies := peer.ServiceDependencies{
 		StatusRecorder: e.statusRecorder,
@@ -1396,6 +2003,7 @@ func (e *Engine) createPeerConn(pubKey string, allowedIPs []netip.Prefix, agentV
 		RelayManager:   e.relayManager,
 		SrWatcher:      e.srWatcher,
 		Semaphore:      e.connSemaphore,
+		Budget:         e.resourceBudget,
 	}
 	peerConn, err := peer.NewConn(config, serviceDependencies)
 	if err != nil {
@@ -1410,15 +2018,102 @@ func (e *Engine) createPeerConn(pubKey string, allowedIPs []netip.Prefix, agentV
 	return peerConn, nil
 }
 
-// receiveSignalEvents connects to the Signal Service event stream to negotiate connection with remote peers
-func (e *Engine) receiveSignalEvents() {
+// peerICEPolicy returns the ICE policy management set for the peer, falling back to the local setting
+func (e *Engine) peerICEPolicy(peerConfig *mgmProto.RemotePeerConfig) icemaker.Policy {
+	switch peerConfig.GetIcePolicy() {
+	case mgmProto.RemotePeerConfig_ALL:
+		return icemaker.PolicyAll
+	case mgmProto.RemotePeerConfig_RELAY_ONLY:
+		return icemaker.PolicyRelayOnly
+	case mgmProto.RemotePeerConfig_NO_RELAY:
+		return icemaker.PolicyNoRelay
+	case mgmProto.RemotePeerConfig_HOST_ONLY:
+		return icemaker.PolicyHostOnly
+	default:
+		return e.config.ICEPolicy
+	}
+}
+
+// peerOfflineReason returns why management doesn't let the offline peer connect
+func peerOfflineReason(peerConfig *mgmProto.RemotePeerConfig) peer.OfflineReason {
+	switch peerConfig.GetOfflineReason() {
+	case mgmProto.RemotePeerConfig_LOGIN_EXPIRED:
+		return peer.OfflineReasonLoginExpired
+	case mgmProto.RemotePeerConfig_POSTURE_CHECK_FAILED:
+		return peer.OfflineReasonPostureCheckFailed
+	case mgmProto.RemotePeerConfig_DISABLED:
+		return peer.OfflineReasonDisabled
+	default:
+		return peer.OfflineReasonNone
+	}
+}
+
+// peerLazyInactivity returns the lazy connection idle detection management set for the peer, nil if not set
+func peerLazyInactivity(peerConfig *mgmProto.RemotePeerConfig) *lazyconn.InactivityConfig {
+	lc := peerConfig.GetLazyConnection()
+	if lc == nil {
+		return nil
+	}
+
+	cfg := &lazyconn.InactivityConfig{
+		Threshold:  lc.GetInactivityThreshold().AsDuration(),
+		MinPackets: lc.GetMinPackets(),
+		MinBytes:   lc.GetMinBytes(),
+	}
+	if *cfg == (lazyconn.InactivityConfig{}) {
+		return nil
+	}
+	return cfg
+}
+
+func inactivityConfigEqual(a, b *lazyconn.InactivityConfig) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// peerPreSharedKey returns the pre-shared key management set for the peer, falling back to the local setting
+func (e *Engine) peerPreSharedKey(peerConfig *mgmProto.RemotePeerConfig) *wgtypes.Key {
+	encoded := peerConfig.GetPreSharedKey()
+	if encoded == "" {
+		return e.config.PreSharedKey
+	}
+
+	key, err := wgtypes.ParseKey(encoded)
+	if err != nil {
+		log.Errorf("invalid pre-shared key of peer %s from management, using the local one: %v", peerConfig.GetWgPubKey(), err)
+		return e.config.PreSharedKey
+	}
+	return &key
+}
+
+func preSharedKeyEqual(a, b *wgtypes.Key) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// peerKeepAlive returns the keepalive interval configured for the peer, falling back to the global setting
+func (e *Engine) peerKeepAlive(pubKey string) time.Duration {
+	if keepAlive, ok := e.config.PeerWgKeepAlive[pubKey]; ok && keepAlive > 0 {
+		return keepAlive
+	}
+	return e.config.WgKeepAlive
+}
+
+// receiveSignalEvents connects to the Signal Service event stream to negotiate connection with remote peers. The
+// stream is closed with the context.
+func (e *Engine) receiveSignalEvents(ctx context.Context, signalClient signal.Client) {
 	e.shutdownWg.Add(1)
 	go func() {
 		defer e.shutdownWg.Done()
 		// connect to a stream of messages coming from the signal server
-		err := e.signal.Receive(e.ctx, func(msg *sProto.Message) error {
-			e.syncMsgMux.Lock()
-			defer e.syncMsgMux.Unlock()
+		err := signalClient.Receive(ctx, func(msg *sProto.Message) error {
+			// not syncMsgMux, the messages must not wait for a network map update to be applied
+			e.peerConnMux.Lock()
+			defer e.peerConnMux.Unlock()
 
 			// Check context INSIDE lock to ensure atomicity with shutdown
 			if e.ctx.Err() != nil {
@@ -1431,7 +2126,7 @@ func (e *Engine) receiveSignalEvents() {
 			}
 
 			msgType := msg.GetBody().GetType()
-			if msgType != sProto.Body_GO_IDLE {
+			if msgType != sProto.Body_GO_IDLE && msgType != sProto.Body_MAINTENANCE && msgType != sProto.Body_WAKE {
 				e.connMgr.ActivatePeer(e.ctx, conn)
 			}
 
@@ -1442,6 +2137,8 @@ func (e *Engine) receiveSignalEvents() {
 					return err
 				}
 
+				e.updatePeerMaintenance(msg.Key, msg.GetBody().GetMaintenance())
+
 				if msg.Body.Type == sProto.Body_OFFER {
 					conn.OnRemoteOffer(*offerAnswer)
 				} else {
@@ -1454,15 +2151,25 @@ func (e *Engine) receiveSignalEvents() {
 					return err
 				}
 
-				go conn.OnRemoteCandidate(candidate, e.routeManager.GetClientRoutes())
+				if msg.GetBody().GetUplink() > 0 {
+					go conn.OnRemoteBondCandidate(candidate, e.routeManager.GetClientRoutes())
+				} else {
+					go conn.OnRemoteCandidate(candidate, e.routeManager.GetClientRoutes())
+				}
 			case sProto.Body_MODE:
 			case sProto.Body_GO_IDLE:
 				e.connMgr.DeactivatePeer(conn)
+			case sProto.Body_MAINTENANCE:
+				e.updatePeerMaintenance(msg.Key, msg.GetBody().GetMaintenance())
+			case sProto.Body_WAKE:
+				e.handleWake(msg.Key, msg.GetBody().GetWakeOnLan())
+			case sProto.Body_ROUTE_FAILOVER:
+				e.handleRouteFailover(msg.Key, msg.GetBody().GetRouteFailover())
 			}
 
 			return nil
 		})
-		if err != nil {
+		if err != nil && ctx.Err() == nil {
 			// happens if signal is unavailable for a long time.
 			// We want to cancel the operation of the whole client
 			_ = CtxGetState(e.ctx).Wrap(ErrResetConnection)
@@ -1470,8 +2177,6 @@ func (e *Engine) receiveSignalEvents() {
 			return
 		}
 	}()
-
-	e.signal.WaitStreamConnected()
 }
 
 func (e *Engine) parseNATExternalIPMappings() []string {
@@ -1536,16 +2241,25 @@ func (e *Engine) close() {
 		e.statusRecorder.SetWgIface(nil)
 	}
 
+	if e.acl != nil {
+		e.acl.Stop()
+	}
+
 	if e.firewall != nil {
 		err := e.firewall.Close(e.stateManager)
 		if err != nil {
-			log.Warnf("failed to reset firewall: %s", err)
+			firewallLog.Warnf("failed to reset firewall: %s", err)
 		}
 	}
 
 	if e.rpManager != nil {
 		_ = e.rpManager.Close()
 	}
+
+	if e.removeFlowHandler != nil {
+		e.removeFlowHandler()
+		e.removeFlowHandler = nil
+	}
 }
 
 func (e *Engine) readInitialSettings() ([]*route.Route, *nbdns.Config, bool, error) {
@@ -1598,6 +2312,9 @@ func (e *Engine) newWgIface() (*iface.WGIface, error) {
 		TransportNet: transportNet,
 		FilterFn:     e.addrViaRoutes,
 		DisableDNS:   e.config.DisableDNS,
+
+		SOCKS5ProxyAddress: e.config.SOCKS5ProxyAddress,
+		HTTPProxyAddress:   e.config.HTTPProxyAddress,
 	}
 
 	switch runtime.GOOS {
@@ -1645,25 +2362,36 @@ func (e *Engine) newDnsServer(dnsConfig *nbdns.Config) (dns.Server, error) {
 			e.statusRecorder,
 			e.config.DisableDNS,
 		)
+		dnsServer.SetEventBus(e.eventBus)
 		go e.mobileDep.DnsReadyListener.OnReady()
 		return dnsServer, nil
 
 	case "ios":
 		dnsServer := dns.NewDefaultServerIos(e.ctx, e.wgInterface, e.mobileDep.DnsManager, e.statusRecorder, e.config.DisableDNS)
+		dnsServer.SetEventBus(e.eventBus)
 		return dnsServer, nil
 
 	default:
 
+		var queryLogger nftypes.FlowLogger
+		if e.config.DNSQueryLog {
+			queryLogger = e.flowManager.GetLogger()
+		}
+
 		dnsServer, err := dns.NewDefaultServer(e.ctx, dns.DefaultServerConfig{
 			WgInterface:    e.wgInterface,
 			CustomAddress:  e.config.CustomDNSAddress,
 			StatusRecorder: e.statusRecorder,
 			StateManager:   e.stateManager,
 			DisableSys:     e.config.DisableDNS,
+			QueryLogger:    queryLogger,
+			MDNSResponder:  e.config.MDNSResponder,
+			ExtraAddresses: e.config.DNSListenAddresses,
 		})
 		if err != nil {
 			return nil, err
 		}
+		dnsServer.SetEventBus(e.eventBus)
 
 		return dnsServer, nil
 	}
@@ -1745,6 +2473,7 @@ func (e *Engine) RunHealthProbes(waitForResult bool) bool {
 				log.Debugf("failed to update wg stats for peer %s: %s", key, err)
 			}
 		}
+		e.updatePeerStats(stats)
 	}
 
 	e.syncMsgMux.Unlock()
@@ -1763,7 +2492,7 @@ func (e *Engine) RunHealthProbes(waitForResult bool) bool {
 			break
 		}
 	}
-	log.Debugf("relay health check: healthy=%t", relayHealthy)
+	relayLog.Debugf("relay health check: healthy=%t", relayHealthy)
 
 	allHealthy := signalHealthy && managementHealthy && relayHealthy
 	log.Debugf("all health checks completed: healthy=%t", allHealthy)
@@ -1794,21 +2523,40 @@ func (e *Engine) startNetworkMonitor() {
 		return
 	}
 
-	e.networkMonitor = networkmonitor.New()
+	// route events on the blacklisted interfaces, like our own or container bridges, don't affect the connectivity
+	e.networkMonitor = networkmonitor.New(networkmonitor.Config{
+		Debounce:          e.config.NetworkMonitorDebounce,
+		IgnoredInterfaces: e.config.IFaceBlackList,
+	})
 	e.shutdownWg.Add(1)
 	go func() {
 		defer e.shutdownWg.Done()
-		if err := e.networkMonitor.Listen(e.ctx); err != nil {
-			if errors.Is(err, context.Canceled) {
-				log.Infof("network monitor stopped")
+		for {
+			if err := e.networkMonitor.Listen(e.ctx); err != nil {
+				if errors.Is(err, context.Canceled) {
+					log.Infof("network monitor stopped")
+					return
+				}
+				log.Errorf("network monitor error: %v", err)
 				return
 			}
-			log.Errorf("network monitor error: %v", err)
-			return
-		}
 
-		log.Infof("Network monitor: detected network change, triggering client restart")
-		e.triggerClientRestart()
+			if e.softReconnect() {
+				if e.ctx.Err() != nil {
+					return
+				}
+				log.Infof("Network monitor: detected network change, restarted ICE on all peer connections")
+				continue
+			}
+
+			if e.warmRestarts == nil {
+				log.Infof("Network monitor: detected network change, triggering client restart")
+				e.triggerClientRestart()
+				return
+			}
+			log.Infof("Network monitor: detected network change, renewing the connections to the NetBird services")
+			e.requestWarmRestart()
+		}
 	}()
 }
 
@@ -1839,7 +2587,13 @@ func (e *Engine) stopDNSServer() {
 		nsGroupStates[i].Enabled = false
 		nsGroupStates[i].Error = err
 	}
-	e.statusRecorder.UpdateDNSStates(nsGroupStates)
+	e.eventBus.Publish(eventbus.DNSStatesChanged{States: nsGroupStates})
+}
+
+// SetEventBus replaces the event bus of the engine, it must be called before Start. The bus must keep the status
+// recorder up to date, like the one of the ConnectClient.
+func (e *Engine) SetEventBus(bus *eventbus.Bus) {
+	e.eventBus = bus
 }
 
 // SetSyncResponsePersistence enables or disables sync response persistence
@@ -1858,6 +2612,56 @@ func (e *Engine) SetSyncResponsePersistence(enabled bool) {
 	}
 }
 
+// SetMaintenance enables or disables the maintenance mode of this peer and announces it to the active peers,
+// so they can move their routes to other routing peers before this one goes down.
+// Idle peers learn about the maintenance mode with the next offer or answer.
+func (e *Engine) SetMaintenance(enabled bool) error {
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+
+	e.signaler.SetMaintenance(enabled)
+	log.Infof("maintenance mode is set to %t", enabled)
+
+	var merr *multierror.Error
+	for _, pubKey := range e.peerStore.PeersPubKey() {
+		if state, err := e.statusRecorder.GetPeer(pubKey); err == nil && state.ConnStatus == peer.StatusIdle {
+			continue
+		}
+
+		if err := e.signaler.SignalMaintenance(pubKey); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("signal maintenance to %s: %w", pubKey, err))
+		}
+	}
+
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+// updatePeerMaintenance records the maintenance state announced by a remote peer
+func (e *Engine) updatePeerMaintenance(pubKey string, maintenance bool) {
+	changed, err := e.statusRecorder.UpdatePeerMaintenance(pubKey, maintenance)
+	if err != nil {
+		log.Debugf("failed to update maintenance state of peer %s: %v", pubKey, err)
+		return
+	}
+	if !changed {
+		return
+	}
+
+	message := "Peer left maintenance mode"
+	if maintenance {
+		message = "Peer entered maintenance mode"
+	}
+	log.Infof("%s: %s", message, pubKey)
+
+	e.statusRecorder.PublishEvent(
+		cProto.SystemEvent_INFO,
+		cProto.SystemEvent_NETWORK,
+		message,
+		"",
+		map[string]string{"peer": pubKey},
+	)
+}
+
 // GetLatestSyncResponse returns the stored sync response if persistence is enabled
 func (e *Engine) GetLatestSyncResponse() (*mgmProto.SyncResponse, error) {
 	e.syncMsgMux.Lock()
@@ -1922,7 +2726,7 @@ func (e *Engine) updateDNSForwarder(
 			e.dnsForwardMgr.UpdateDomains(fwdEntries)
 		}
 	} else if e.dnsForwardMgr != nil {
-		log.Infof("disable domain router service")
+		dnsLog.Infof("disable domain router service")
 		e.stopDNSForwarder()
 	}
 }
@@ -1931,12 +2735,12 @@ func (e *Engine) startDNSForwarder(fwdEntries []*dnsfwd.ForwarderEntry) {
 	e.dnsForwardMgr = dnsfwd.NewManager(e.firewall, e.statusRecorder, e.wgInterface)
 
 	if err := e.dnsForwardMgr.Start(fwdEntries); err != nil {
-		log.Errorf("failed to start DNS forward: %v", err)
+		dnsLog.Errorf("failed to start DNS forward: %v", err)
 		e.dnsForwardMgr = nil
 		return
 	}
 
-	log.Infof("started domain router service with %d entries", len(fwdEntries))
+	dnsLog.Infof("started domain router service with %d entries", len(fwdEntries))
 }
 
 func (e *Engine) stopDNSForwarder() {
@@ -1945,7 +2749,7 @@ func (e *Engine) stopDNSForwarder() {
 	}
 
 	if err := e.dnsForwardMgr.Stop(context.Background()); err != nil {
-		log.Errorf("failed to stop DNS forward: %v", err)
+		dnsLog.Errorf("failed to stop DNS forward: %v", err)
 	}
 
 	e.dnsForwardMgr = nil
@@ -1979,23 +2783,24 @@ func (e *Engine) Address() (netip.Addr, error) {
 
 func (e *Engine) updateForwardRules(rules []*mgmProto.ForwardingRule) ([]firewallManager.ForwardRule, error) {
 	if e.firewall == nil {
-		log.Warn("firewall is disabled, not updating forwarding rules")
+		firewallLog.Warn("firewall is disabled, not updating forwarding rules")
 		return nil, nil
 	}
 
 	if len(rules) == 0 {
+		if e.flowManager != nil {
+			e.flowManager.SetIngressForwards(nil)
+		}
 		if e.ingressGatewayMgr == nil {
 			return nil, nil
 		}
 
-		err := e.ingressGatewayMgr.Close()
-		e.ingressGatewayMgr = nil
-		e.statusRecorder.SetIngressGwMgr(nil)
-		return nil, err
+		// the manager is kept for the draining rules, it's closed with the engine
+		return nil, e.ingressGatewayMgr.Update(nil)
 	}
 
 	if e.ingressGatewayMgr == nil {
-		mgr := ingressgw.NewManager(e.firewall)
+		mgr := ingressgw.NewManager(e.firewall, e.statusRecorder)
 		e.ingressGatewayMgr = mgr
 		e.statusRecorder.SetIngressGwMgr(mgr)
 	}
@@ -2021,6 +2826,12 @@ func (e *Engine) updateForwardRules(rules []*mgmProto.ForwardingRule) ([]firewal
 			continue
 		}
 
+		translateIPs, err := convertTranslatedAddresses(translateIP, rule.GetTranslatedAddresses())
+		if err != nil {
+			merr = multierror.Append(merr, err)
+			continue
+		}
+
 		translatePort, err := convertPortInfo(rule.GetTranslatedPort())
 		if err != nil {
 			merr = multierror.Append(merr, fmt.Errorf("invalid translate port '%v': %w", rule.GetTranslatedPort(), err))
@@ -2028,10 +2839,17 @@ func (e *Engine) updateForwardRules(rules []*mgmProto.ForwardingRule) ([]firewal
 		}
 
 		forwardRule := firewallManager.ForwardRule{
-			Protocol:          proto,
-			DestinationPort:   *dstPortInfo,
-			TranslatedAddress: translateIP,
-			TranslatedPort:    *translatePort,
+			Protocol:            proto,
+			DestinationPort:     *dstPortInfo,
+			TranslatedAddress:   translateIP,
+			TranslatedAddresses: translateIPs,
+			TranslatedPort:      *translatePort,
+			HealthCheck:         convertForwardingHealthCheck(rule.GetHealthCheck()),
+			DrainTimeout:        rule.GetDrainTimeout().AsDuration(),
+		}
+		if err := forwardRule.Validate(); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("invalid forwarding rule '%s': %w", forwardRule, err))
+			continue
 		}
 
 		forwardingRules = append(forwardingRules, forwardRule)
@@ -2042,20 +2860,25 @@ func (e *Engine) updateForwardRules(rules []*mgmProto.ForwardingRule) ([]firewal
 		log.Errorf("failed to update forwarding rules: %v", err)
 	}
 
+	if e.flowManager != nil {
+		e.flowManager.SetIngressForwards(toIngressForwards(forwardingRules))
+	}
+
 	return forwardingRules, nberrors.FormatErrorOrNil(merr)
 }
 
 func (e *Engine) toExcludedLazyPeers(rules []firewallManager.ForwardRule, peers []*mgmProto.RemotePeerConfig) map[string]bool {
 	excludedPeers := make(map[string]bool)
 	for _, r := range rules {
-		ip := r.TranslatedAddress
-		for _, p := range peers {
-			for _, allowedIP := range p.GetAllowedIps() {
-				if allowedIP != ip.String() {
-					continue
+		for _, ip := range r.Backends() {
+			for _, p := range peers {
+				for _, allowedIP := range p.GetAllowedIps() {
+					if allowedIP != ip.String() {
+						continue
+					}
+					log.Infof("exclude forwarder peer from lazy connection: %s", p.GetWgPubKey())
+					excludedPeers[p.GetWgPubKey()] = true
 				}
-				log.Infof("exclude forwarder peer from lazy connection: %s", p.GetWgPubKey())
-				excludedPeers[p.GetWgPubKey()] = true
 			}
 		}
 	}
@@ -2084,48 +2907,6 @@ func isChecksEqual(checks1, checks2 []*mgmProto.Checks) bool {
 	return slices.Equal(n1, n2)
 }
 
-func getInterfacePrefixes() ([]netip.Prefix, error) {
-	ifaces, err := net.Interfaces()
-	if err != nil {
-		return nil, fmt.Errorf("get interfaces: %w", err)
-	}
-
-	var prefixes []netip.Prefix
-	var merr *multierror.Error
-
-	for _, iface := range ifaces {
-		addrs, err := iface.Addrs()
-		if err != nil {
-			merr = multierror.Append(merr, fmt.Errorf("get addresses for interface %s: %w", iface.Name, err))
-			continue
-		}
-		for _, addr := range addrs {
-			ipNet, ok := addr.(*net.IPNet)
-			if !ok {
-				merr = multierror.Append(merr, fmt.Errorf("cast address to IPNet: %v", addr))
-				continue
-			}
-			addr, ok := netip.AddrFromSlice(ipNet.IP)
-			if !ok {
-				merr = multierror.Append(merr, fmt.Errorf("cast IPNet to netip.Addr: %v", ipNet.IP))
-				continue
-			}
-			ones, _ := ipNet.Mask.Size()
-			prefix := netip.PrefixFrom(addr.Unmap(), ones).Masked()
-			ip := prefix.Addr()
-
-			// TODO: add IPv6
-			if !ip.Is4() || ip.IsLoopback() || ip.IsMulticast() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
-				continue
-			}
-
-			prefixes = append(prefixes, prefix)
-		}
-	}
-
-	return prefixes, nberrors.FormatErrorOrNil(merr)
-}
-
 // compareNetIPLists compares a list of netip.Prefix with a list of strings.
 // return true if both lists are equal, false otherwise.
 func compareNetIPLists(list1 []netip.Prefix, list2 []string) bool {
@@ -2172,6 +2953,33 @@ func createFile(path string) error {
 	return file.Close()
 }
 
+// convertToBondOffer returns the ICE session on the secondary uplink of a bonded connection, nil if there is none
+func convertToBondOffer(bond *sProto.Bond) *peer.BondOffer {
+	if bond == nil {
+		return nil
+	}
+
+	sessionID, err := peer.ICESessionIDFromBytes(bond.GetSessionId())
+	if err != nil {
+		log.Warnf("invalid bond session ID in message: %v", err)
+		return nil
+	}
+
+	mode := peer.BondModeStandby
+	if bond.GetSpray() {
+		mode = peer.BondModeSpray
+	}
+	return &peer.BondOffer{
+		IceCredentials: peer.IceCredentials{
+			UFrag: bond.GetUFrag(),
+			Pwd:   bond.GetPwd(),
+		},
+		SessionID:   &sessionID,
+		Mode:        mode,
+		SprayWeight: int(bond.GetSprayWeight()),
+	}
+}
+
 func convertToOfferAnswer(msg *sProto.Message) (*peer.OfferAnswer, error) {
 	remoteCred, err := signal.UnMarshalCredential(msg)
 	if err != nil {
@@ -2209,6 +3017,8 @@ func convertToOfferAnswer(msg *sProto.Message) (*peer.OfferAnswer, error) {
 		RosenpassAddr:   rosenpassAddr,
 		RelaySrvAddress: msg.GetBody().GetRelayServerAddress(),
 		SessionID:       sessionID,
+		Features:        peer.NewFeatures(msg.GetBody().GetFeaturesSupported()...),
+		Bond:            convertToBondOffer(msg.GetBody().GetBond()),
 	}
 	return &offerAnswer, nil
 }
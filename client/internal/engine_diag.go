@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/diagsrv"
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+)
+
+// startDiagnosticEndpoints serves the echo and whoami endpoints on the NetBird address. They are reachable from
+// the peers the access control policies allow to connect to their ports.
+func (e *Engine) startDiagnosticEndpoints() error {
+	server := diagsrv.New(e.wgInterface, e.statusRecorder)
+	if err := server.Start(); err != nil {
+		return fmt.Errorf("start diagnostic endpoints: %w", err)
+	}
+	e.diagServer = server
+
+	if e.wgInterface.GetNet() != nil {
+		if registrar, ok := e.firewall.(interface {
+			RegisterNetstackService(protocol nftypes.Protocol, port uint16)
+		}); ok {
+			registrar.RegisterNetstackService(nftypes.TCP, diagsrv.EchoPort)
+			registrar.RegisterNetstackService(nftypes.UDP, diagsrv.EchoPort)
+			registrar.RegisterNetstackService(nftypes.TCP, diagsrv.HTTPPort)
+			log.Debugf("registered diagnostic endpoints with netstack for TCP/UDP:%d and TCP:%d", diagsrv.EchoPort, diagsrv.HTTPPort)
+		}
+	}
+
+	return nil
+}
+
+func (e *Engine) stopDiagnosticEndpoints() error {
+	if e.diagServer == nil {
+		return nil
+	}
+
+	if e.wgInterface.GetNet() != nil {
+		if registrar, ok := e.firewall.(interface {
+			UnregisterNetstackService(protocol nftypes.Protocol, port uint16)
+		}); ok {
+			registrar.UnregisterNetstackService(nftypes.TCP, diagsrv.EchoPort)
+			registrar.UnregisterNetstackService(nftypes.UDP, diagsrv.EchoPort)
+			registrar.UnregisterNetstackService(nftypes.TCP, diagsrv.HTTPPort)
+		}
+	}
+
+	err := e.diagServer.Stop()
+	e.diagServer = nil
+	if err != nil {
+		return fmt.Errorf("stop diagnostic endpoints: %w", err)
+	}
+	return nil
+}
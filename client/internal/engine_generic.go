@@ -15,5 +15,7 @@ func (e *Engine) createICEConfig() icemaker.Config {
 		UDPMux:               e.udpMux.SingleSocketUDPMux,
 		UDPMuxSrflx:          e.udpMux,
 		NATExternalIPs:       e.parseNATExternalIPMappings(),
+		MulticastDNS:         e.config.ICEMulticastDNS,
+		Policy:               e.config.ICEPolicy,
 	}
 }
@@ -0,0 +1,272 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cenkalti/backoff/v4"
+	log "github.com/sirupsen/logrus"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/iface/device"
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/statemanager"
+	"github.com/netbirdio/netbird/client/ssh"
+	mgm "github.com/netbirdio/netbird/shared/management/client"
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+	relayClient "github.com/netbirdio/netbird/shared/relay/client"
+	signal "github.com/netbirdio/netbird/shared/signal/client"
+)
+
+// engineSession are the connections to the NetBird services and the configuration an engine runs with
+type engineSession struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	// connCancel closes the context of the connections to management and Signal if they were adopted from a warm
+	// session, their context is the session context otherwise
+	connCancel context.CancelFunc
+
+	configSecrets configSecrets
+	mgmClient     mgm.Client
+	signalClient  signal.Client
+	relayManager  *relayClient.Manager
+	loginResp     *mgmProto.LoginResponse
+	engineConfig  *EngineConfig
+}
+
+// close closes the connections of the session and cancels its context
+func (s *engineSession) close() {
+	s.closeConns()
+	s.cancel()
+}
+
+// closeConns closes the connections to management and Signal
+func (s *engineSession) closeConns() {
+	if s.signalClient != nil {
+		if err := s.signalClient.Close(); err != nil {
+			log.Warnf("failed closing Signal service client %v", err)
+		}
+	}
+	if s.mgmClient != nil {
+		if err := s.mgmClient.Close(); err != nil {
+			log.Warnf("failed to close the Management service client %v", err)
+		}
+	}
+	if s.connCancel != nil {
+		s.connCancel()
+	}
+}
+
+// adopt replaces the connections to management and Signal with the ones of the warm session and closes the
+// replaced ones. The engine and the relay connections of the session stay.
+func (s *engineSession) adopt(warm *engineSession) {
+	// the streams of the replaced connections end after the swap, their disconnect would override the state of the
+	// adopted ones
+	if c, ok := s.mgmClient.(*mgm.GrpcClient); ok {
+		c.SetConnStateListener(nil)
+	}
+	if c, ok := s.signalClient.(*signal.GrpcClient); ok {
+		c.SetConnStateListener(nil)
+	}
+	s.closeConns()
+	s.mgmClient = warm.mgmClient
+	s.signalClient = warm.signalClient
+	s.loginResp = warm.loginResp
+	s.connCancel = warm.cancel
+}
+
+// engineBuilder opens the sessions and builds the engines of a client run
+type engineBuilder struct {
+	client        *ConnectClient
+	key           wgtypes.Key
+	mgmTlsEnabled bool
+	mobileDep     MobileDependency
+	stateManager  *statemanager.Manager
+}
+
+// openSession logs in to management and connects to Signal and Relay. A warm session renews the connections to
+// management and Signal of the running session, it is opened while the engine keeps running and doesn't touch the
+// status until reportSession is called on the swap. The relay connections and the engine config of the running
+// session stay.
+func (b *engineBuilder) openSession(running *engineSession) (session *engineSession, err error) {
+	c := b.client
+	state := CtxGetState(c.ctx)
+	wrapErr := state.Wrap
+	warm := running != nil
+
+	parent := c.ctx
+	if warm {
+		parent = running.ctx
+	}
+	ctx, cancel := context.WithCancel(parent)
+	session = &engineSession{ctx: ctx, cancel: cancel}
+	defer func() {
+		if err == nil {
+			return
+		}
+		session.close()
+		if warm {
+			return
+		}
+		_, stateErr := state.Status()
+		if session.loginResp != nil {
+			c.statusRecorder.MarkSignalDisconnected(stateErr)
+		}
+		c.statusRecorder.MarkManagementDisconnected(stateErr)
+		c.statusRecorder.CleanLocalPeerState()
+	}()
+
+	// secrets are resolved on every attempt, so rotated secrets are picked up on reconnect
+	session.configSecrets, err = resolveConfigSecrets(ctx, c.config)
+	if err != nil {
+		return session, wrapErr(err)
+	}
+
+	publicSSHKey, err := ssh.GeneratePublicKey([]byte(session.configSecrets.sshKey))
+	if err != nil {
+		return session, backoff.Permanent(wrapErr(err))
+	}
+
+	if !warm {
+		// a new engine holds no network map the Sync session could be resumed with
+		c.mgmResume.Reset()
+	}
+
+	log.Debugf("connecting to the Management service %s", c.config.ManagementURL.Host)
+	session.mgmClient, err = c.newManagementClient(ctx, b.key, b.mgmTlsEnabled)
+	if err != nil {
+		return session, wrapErr(gstatus.Errorf(codes.FailedPrecondition, "failed connecting to Management Service : %s", err))
+	}
+	log.Debugf("connected to the Management service %s", c.config.ManagementURL.Host)
+
+	loginResp, err := b.login(session, running, publicSSHKey)
+	if err != nil {
+		log.Debug(err)
+		if s, ok := gstatus.FromError(err); ok && (s.Code() == codes.PermissionDenied) {
+			state.Set(StatusNeedsLogin)
+			_ = c.Stop()
+			return session, backoff.Permanent(wrapErr(err)) // unrecoverable error
+		}
+		return session, wrapErr(err)
+	}
+	session.loginResp = loginResp
+	if !warm {
+		c.statusRecorder.MarkManagementConnected()
+		c.statusRecorder.UpdateLocalPeerState(b.localPeerState(session))
+		c.statusRecorder.UpdateSignalAddress(sessionSignalURL(session))
+		c.statusRecorder.MarkSignalDisconnected(nil)
+	}
+
+	// with the global Netbird config in hand connect (just a connection, no stream yet) Signal
+	session.signalClient, err = c.newSignalClient(ctx, loginResp.GetNetbirdConfig(), b.key)
+	if err != nil {
+		log.Error(err)
+		return session, wrapErr(err)
+	}
+	if warm {
+		return session, nil
+	}
+	c.statusRecorder.MarkSignalConnected()
+
+	relayURLs, token := parseRelayInfo(loginResp)
+
+	session.engineConfig, err = createEngineConfig(b.key, c.config, session.configSecrets, loginResp.GetPeerConfig())
+	if err != nil {
+		log.Error(err)
+		return session, wrapErr(err)
+	}
+
+	session.relayManager = relayClient.NewManager(ctx, relayURLs, b.key.PublicKey().String(), session.engineConfig.MTU)
+	c.statusRecorder.SetRelayMgr(session.relayManager)
+	session.relayManager.SetPreferredServer(session.engineConfig.PreferredRelay)
+	if len(relayURLs) > 0 {
+		if token != nil {
+			if err := session.relayManager.UpdateToken(token); err != nil {
+				log.Errorf("failed to update token: %s", err)
+				return session, wrapErr(err)
+			}
+		}
+		log.Infof("connecting to the Relay service(s): %s", strings.Join(relayURLs, ", "))
+		if err = session.relayManager.Serve(); err != nil {
+			log.Error(err)
+		}
+	}
+
+	return session, nil
+}
+
+// login logs in to management to get the global Netbird config. A warm session resuming the Sync session of the
+// running engine skips the login, the engine keeps its network map and the login of the running session stays valid
+// as long as the secrets it was sent with didn't change. A Sync stream that can't resume receives the full map.
+func (b *engineBuilder) login(session, running *engineSession, publicSSHKey []byte) (*mgmProto.LoginResponse, error) {
+	c := b.client
+	if running != nil && running.loginResp != nil && session.configSecrets == running.configSecrets && c.mgmResume.Resumable() {
+		log.Debugf("resuming the Management service session, skipping the login")
+		return running.loginResp, nil
+	}
+
+	// connect (just a connection, no stream yet) and login to Management Service to get an initial global Netbird config
+	return loginToManagement(session.ctx, session.mgmClient, publicSSHKey, c.config)
+}
+
+// reportSession publishes the connections of the session once it adopted the ones of a warm session
+func (b *engineBuilder) reportSession(session *engineSession) {
+	c := b.client
+	c.statusRecorder.MarkManagementConnected()
+	c.statusRecorder.UpdateLocalPeerState(b.localPeerState(session))
+	c.statusRecorder.UpdateSignalAddress(sessionSignalURL(session))
+	c.statusRecorder.MarkSignalConnected()
+}
+
+func (b *engineBuilder) localPeerState(session *engineSession) peer.LocalPeerState {
+	return peer.LocalPeerState{
+		IP:              session.loginResp.GetPeerConfig().GetAddress(),
+		PubKey:          b.key.PublicKey().String(),
+		KernelInterface: device.WireGuardModuleIsLoaded(),
+		FQDN:            session.loginResp.GetPeerConfig().GetFqdn(),
+	}
+}
+
+func sessionSignalURL(session *engineSession) string {
+	return fmt.Sprintf("%s://%s",
+		strings.ToLower(session.loginResp.GetNetbirdConfig().GetSignal().GetProtocol().String()),
+		session.loginResp.GetNetbirdConfig().GetSignal().GetUri(),
+	)
+}
+
+// startEngine builds the engine of the session and starts it
+func (b *engineBuilder) startEngine(session *engineSession) (*Engine, error) {
+	c := b.client
+	loginResp := session.loginResp
+
+	c.engineMutex.Lock()
+	engine := NewEngine(session.ctx, session.cancel, session.signalClient, session.mgmClient, session.relayManager, session.engineConfig, b.mobileDep, c.statusRecorder, loginResp.GetChecks(), b.stateManager)
+	engine.SetSyncResponsePersistence(c.persistSyncResponse)
+	engine.SetEventBus(c.eventBus)
+	engine.enableWarmRestart()
+	c.engine = engine
+	c.engineMutex.Unlock()
+
+	if err := engine.Start(loginResp.GetNetbirdConfig(), c.config.ManagementURL); err != nil {
+		log.Errorf("error while starting Netbird Connection Engine: %s", err)
+		return engine, err
+	}
+
+	if loginResp.PeerConfig != nil && loginResp.PeerConfig.AutoUpdate != nil {
+		// AutoUpdate will be true when the user click on "Connect" menu on the UI
+		if c.doInitialAutoUpdate {
+			log.Infof("start engine by ui, run auto-update check")
+			engine.InitialUpdateHandling(loginResp.PeerConfig.AutoUpdate)
+			c.doInitialAutoUpdate = false
+		}
+	}
+
+	if hasSecretReferences(c.config) {
+		go watchConfigSecrets(session.ctx, c.config, session.configSecrets, engine.triggerClientRestart)
+	}
+
+	return engine, nil
+}
@@ -7,9 +7,11 @@ import (
 	"net/netip"
 	"strings"
 
+	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 
 	firewallManager "github.com/netbirdio/netbird/client/firewall/manager"
+	"github.com/netbirdio/netbird/client/internal/acl"
 	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
 	sshauth "github.com/netbirdio/netbird/client/ssh/auth"
 	sshconfig "github.com/netbirdio/netbird/client/ssh/config"
@@ -23,6 +25,17 @@ type sshServer interface {
 	Stop() error
 	GetStatus() (bool, []sshserver.SessionInfo)
 	UpdateSSHAuth(config *sshauth.Config)
+	UpdatePolicy(policy *sshserver.Policy)
+	SetAllowSFTP(allow bool)
+	SetAllowLocalPortForwarding(allow bool)
+	SetAllowRemotePortForwarding(allow bool)
+}
+
+// sshFeatures are the optional features of the SSH server that management can enable
+type sshFeatures struct {
+	sftp                 bool
+	localPortForwarding  bool
+	remotePortForwarding bool
 }
 
 func (e *Engine) setupSSHPortRedirection() error {
@@ -44,7 +57,7 @@ func (e *Engine) setupSSHPortRedirection() error {
 }
 
 func (e *Engine) updateSSH(sshConf *mgmProto.SSHConfig) error {
-	if e.config.BlockInbound {
+	if e.config.BlockInbound && !e.sshInboundAllowed() {
 		log.Info("SSH server is disabled because inbound connections are blocked")
 		return e.stopSSHServer()
 	}
@@ -61,14 +74,19 @@ func (e *Engine) updateSSH(sshConf *mgmProto.SSHConfig) error {
 		return e.stopSSHServer()
 	}
 
+	policy := e.withLocalSSHPeerRules(toSSHPolicy(sshConf.GetPolicy()))
 	if e.sshServer != nil {
 		log.Debug("SSH server is already running")
+		e.sshServer.UpdatePolicy(policy)
+		if features := e.toSSHFeatures(sshConf); features != e.sshServerFeatures {
+			e.applySSHFeatures(e.sshServer, features)
+		}
 		return nil
 	}
 
 	if e.config.DisableSSHAuth != nil && *e.config.DisableSSHAuth {
 		log.Info("starting SSH server without JWT authentication (authentication disabled by config)")
-		return e.startSSHServer(nil)
+		return e.startSSHServer(nil, policy, e.toSSHFeatures(sshConf))
 	}
 
 	if protoJWT := sshConf.GetJwtConfig(); protoJWT != nil {
@@ -79,12 +97,102 @@ func (e *Engine) updateSSH(sshConf *mgmProto.SSHConfig) error {
 			MaxTokenAge:  protoJWT.GetMaxTokenAge(),
 		}
 
-		return e.startSSHServer(jwtConfig)
+		return e.startSSHServer(jwtConfig, policy, e.toSSHFeatures(sshConf))
 	}
 
 	return errors.New("SSH server requires valid JWT configuration")
 }
 
+// sshInboundAllowed reports whether the inbound exceptions keep the SSH server reachable by some peers
+func (e *Engine) sshInboundAllowed() bool {
+	rules := acl.InboundExceptionRules(e.config.InboundExceptions, e.inboundExceptions)
+	return acl.AllowsInboundTCP(rules, 22) || acl.AllowsInboundTCP(rules, 22022)
+}
+
+// toSSHPolicy converts the SSH policy of management. Invalid peer entries match no peer, so a policy with only
+// invalid entries denies all peers instead of allowing them.
+func toSSHPolicy(protoPolicy *mgmProto.SSHPolicy) *sshserver.Policy {
+	if protoPolicy == nil {
+		return nil
+	}
+
+	policy := &sshserver.Policy{
+		AllowedUsers:    protoPolicy.GetAllowedUsers(),
+		AllowedCommands: protoPolicy.GetAllowedCommands(),
+		RecordSessions:  protoPolicy.GetRecordSessions(),
+	}
+	policy.AllowedPeers = toSSHPeerPrefixes(protoPolicy.GetAllowedPeers())
+	for _, rule := range protoPolicy.GetPeerRules() {
+		policy.PeerRules = append(policy.PeerRules, sshserver.PeerRule{
+			Peers:        toSSHPeerPrefixes(rule.GetPeers()),
+			Users:        rule.GetUsers(),
+			ForceCommand: rule.GetForceCommand(),
+			DenyShell:    rule.GetDenyShell(),
+		})
+	}
+	return policy
+}
+
+// toSSHPeerPrefixes parses the NetBird IPs or networks of the peers, invalid entries are kept as the invalid
+// prefix matching no peer
+func toSSHPeerPrefixes(peers []string) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for _, peer := range peers {
+		prefix, err := netip.ParsePrefix(peer)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(peer)
+			if addrErr != nil {
+				log.Warnf("invalid peer %q in the SSH policy: %v", peer, err)
+			} else {
+				prefix = netip.PrefixFrom(addr, addr.BitLen())
+			}
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes
+}
+
+// withLocalSSHPeerRules replaces the peer rules of the policy with the rules of the local config, if set
+func (e *Engine) withLocalSSHPeerRules(policy *sshserver.Policy) *sshserver.Policy {
+	if len(e.config.SSHPeerRules) == 0 {
+		return policy
+	}
+	if policy == nil {
+		policy = &sshserver.Policy{}
+	}
+	policy.PeerRules = e.config.SSHPeerRules
+	return policy
+}
+
+// storeSSHSessionEvent sends the start and the end of the recorded SSH sessions to the flow logger
+func (e *Engine) storeSSHSessionEvent(event sshserver.SessionEvent) {
+	if e.flowManager == nil {
+		return
+	}
+
+	eventType := nftypes.TypeStart
+	if event.End {
+		eventType = nftypes.TypeEnd
+	}
+
+	e.flowManager.GetLogger().StoreEvent(nftypes.EventFields{
+		FlowID:     uuid.NewSHA1(uuid.NameSpaceOID, []byte(event.ID)),
+		Type:       eventType,
+		Direction:  nftypes.Ingress,
+		Protocol:   nftypes.TCP,
+		SourceIP:   event.RemoteAddr.Addr(),
+		SourcePort: event.RemoteAddr.Port(),
+		DestIP:     event.LocalAddr.Addr(),
+		DestPort:   event.LocalAddr.Port(),
+		SSHSession: &nftypes.SSHSession{
+			User:      event.Username,
+			JWTUser:   event.JWTUsername,
+			Command:   event.Command,
+			Recording: event.File,
+		},
+	})
+}
+
 // updateSSHClientConfig updates the SSH client configuration with peer information
 func (e *Engine) updateSSHClientConfig(remotePeers []*mgmProto.RemotePeerConfig) error {
 	peerInfo := e.extractPeerSSHInfo(remotePeers)
@@ -219,7 +327,7 @@ func (e *Engine) cleanupSSHConfig() {
 }
 
 // startSSHServer initializes and starts the SSH server with proper configuration.
-func (e *Engine) startSSHServer(jwtConfig *sshserver.JWTConfig) error {
+func (e *Engine) startSSHServer(jwtConfig *sshserver.JWTConfig, policy *sshserver.Policy, features sshFeatures) error {
 	if e.wgInterface == nil {
 		return errors.New("wg interface not initialized")
 	}
@@ -227,8 +335,13 @@ func (e *Engine) startSSHServer(jwtConfig *sshserver.JWTConfig) error {
 	serverConfig := &sshserver.Config{
 		HostKeyPEM: e.config.SSHKey,
 		JWT:        jwtConfig,
+		Recording: &sshserver.RecordingConfig{
+			Dir:    e.config.SSHRecordingDir,
+			Events: e.storeSSHSessionEvent,
+		},
 	}
 	server := sshserver.New(serverConfig)
+	server.UpdatePolicy(policy)
 
 	wgAddr := e.wgInterface.Address()
 	server.SetNetworkValidation(wgAddr)
@@ -241,6 +354,7 @@ func (e *Engine) startSSHServer(jwtConfig *sshserver.JWTConfig) error {
 	}
 
 	e.configureSSHServer(server)
+	e.applySSHFeatures(server, features)
 
 	if err := server.Start(e.ctx, listenAddr); err != nil {
 		return fmt.Errorf("start SSH server: %w", err)
@@ -261,6 +375,8 @@ func (e *Engine) startSSHServer(jwtConfig *sshserver.JWTConfig) error {
 		log.Warnf("failed to setup SSH port redirection: %v", err)
 	}
 
+	e.updateWindowsFirewall()
+
 	return nil
 }
 
@@ -273,30 +389,34 @@ func (e *Engine) configureSSHServer(server *sshserver.Server) {
 		server.SetAllowRootLogin(false)
 		log.Info("SSH root login disabled (default)")
 	}
+}
 
-	if e.config.EnableSSHSFTP != nil && *e.config.EnableSSHSFTP {
-		server.SetAllowSFTP(true)
-		log.Info("SSH SFTP subsystem enabled")
-	} else {
-		server.SetAllowSFTP(false)
-		log.Info("SSH SFTP subsystem disabled (default)")
+// toSSHFeatures combines the local and the management settings of the optional SSH server features.
+// A feature set in the local config ignores management, unset features follow management.
+func (e *Engine) toSSHFeatures(sshConf *mgmProto.SSHConfig) sshFeatures {
+	return sshFeatures{
+		sftp:                 sshFeatureEnabled(e.config.EnableSSHSFTP, sshConf.GetSftpEnabled()),
+		localPortForwarding:  sshFeatureEnabled(e.config.EnableSSHLocalPortForwarding, sshConf.GetLocalPortForwardingEnabled()),
+		remotePortForwarding: sshFeatureEnabled(e.config.EnableSSHRemotePortForwarding, sshConf.GetRemotePortForwardingEnabled()),
 	}
+}
 
-	if e.config.EnableSSHLocalPortForwarding != nil && *e.config.EnableSSHLocalPortForwarding {
-		server.SetAllowLocalPortForwarding(true)
-		log.Info("SSH local port forwarding enabled")
-	} else {
-		server.SetAllowLocalPortForwarding(false)
-		log.Info("SSH local port forwarding disabled (default)")
+func sshFeatureEnabled(local *bool, fromManagement bool) bool {
+	if local != nil {
+		return *local
 	}
+	return fromManagement
+}
 
-	if e.config.EnableSSHRemotePortForwarding != nil && *e.config.EnableSSHRemotePortForwarding {
-		server.SetAllowRemotePortForwarding(true)
-		log.Info("SSH remote port forwarding enabled")
-	} else {
-		server.SetAllowRemotePortForwarding(false)
-		log.Info("SSH remote port forwarding disabled (default)")
-	}
+// applySSHFeatures enables or disables the optional features of the SSH server
+func (e *Engine) applySSHFeatures(server sshServer, features sshFeatures) {
+	server.SetAllowSFTP(features.sftp)
+	server.SetAllowLocalPortForwarding(features.localPortForwarding)
+	server.SetAllowRemotePortForwarding(features.remotePortForwarding)
+	e.sshServerFeatures = features
+
+	log.Infof("SSH server features: sftp=%v, local port forwarding=%v, remote port forwarding=%v",
+		features.sftp, features.localPortForwarding, features.remotePortForwarding)
 }
 
 func (e *Engine) cleanupSSHPortRedirection() error {
@@ -338,6 +458,7 @@ func (e *Engine) stopSSHServer() error {
 	log.Info("stopping SSH server")
 	err := e.sshServer.Stop()
 	e.sshServer = nil
+	e.updateWindowsFirewall()
 	if err != nil {
 		return fmt.Errorf("stop: %w", err)
 	}
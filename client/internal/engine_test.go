@@ -38,6 +38,7 @@ import (
 	"github.com/netbirdio/netbird/management/server/groups"
 
 	"github.com/netbirdio/netbird/client/iface"
+	"github.com/netbirdio/netbird/client/iface/bind"
 	"github.com/netbirdio/netbird/client/iface/configurer"
 	"github.com/netbirdio/netbird/client/iface/device"
 	"github.com/netbirdio/netbird/client/iface/udpmux"
@@ -69,6 +70,7 @@ import (
 	"github.com/netbirdio/netbird/shared/signal/proto"
 	signalServer "github.com/netbirdio/netbird/signal/server"
 	"github.com/netbirdio/netbird/util"
+	semaphoregroup "github.com/netbirdio/netbird/util/semaphore-group"
 )
 
 var (
@@ -213,6 +215,10 @@ func (m *MockWGIface) LastActivities() map[string]monotime.Time {
 	return nil
 }
 
+func (m *MockWGIface) ActivityCounters() map[string]bind.ActivityCounters {
+	return nil
+}
+
 func TestMain(m *testing.M) {
 	_ = util.InitLog("debug", util.LogConsole)
 	code := m.Run()
@@ -370,6 +376,28 @@ func TestEngine_SSHUpdateLogic(t *testing.T) {
 	assert.Nil(t, engine.sshServer)
 }
 
+func TestEngine_SSHFeatures(t *testing.T) {
+	enabled, disabled := true, false
+	engine := &Engine{
+		config: &EngineConfig{
+			EnableSSHSFTP:                &disabled,
+			EnableSSHLocalPortForwarding: &enabled,
+		},
+	}
+
+	features := engine.toSSHFeatures(&mgmtProto.SSHConfig{
+		SftpEnabled:                 true,
+		LocalPortForwardingEnabled:  false,
+		RemotePortForwardingEnabled: true,
+	})
+	assert.False(t, features.sftp, "local config should take precedence over management")
+	assert.True(t, features.localPortForwarding, "local config should take precedence over management")
+	assert.True(t, features.remotePortForwarding, "unset local config should follow management")
+
+	features = engine.toSSHFeatures(nil)
+	assert.False(t, features.remotePortForwarding, "features should be disabled without management config")
+}
+
 func TestEngine_SSHServerConsistency(t *testing.T) {
 
 	t.Run("server set only on successful creation", func(t *testing.T) {
@@ -1168,6 +1196,70 @@ loop:
 	}
 }
 
+func TestEngine_RunPeerWorkers(t *testing.T) {
+	const limit = 4
+	e := &Engine{peerWorkers: semaphoregroup.NewSemaphoreGroup(limit)}
+
+	peerKeys := make([]string, 50)
+	for i := range peerKeys {
+		peerKeys[i] = fmt.Sprintf("peer-%d", i)
+	}
+
+	var (
+		mu        sync.Mutex
+		running   int
+		maxActive int
+		done      []string
+	)
+	err := e.runPeerWorkers(peerKeys, func(peerKey string) error {
+		mu.Lock()
+		running++
+		maxActive = max(maxActive, running)
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		running--
+		done = append(done, peerKey)
+		mu.Unlock()
+
+		if peerKey == "peer-3" || peerKey == "peer-7" {
+			return fmt.Errorf("failed")
+		}
+		return nil
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "peer peer-3")
+	assert.Contains(t, err.Error(), "peer peer-7")
+	assert.ElementsMatch(t, peerKeys, done, "a failing peer doesn't stop the others")
+	assert.LessOrEqual(t, maxActive, limit)
+	assert.Greater(t, maxActive, 1, "peers are processed concurrently")
+}
+
+func TestEngine_RunPeerWorkers_ConnSemaphoreTaken(t *testing.T) {
+	e := &Engine{
+		connSemaphore: semaphoregroup.NewSemaphoreGroup(1),
+		peerWorkers:   semaphoregroup.NewSemaphoreGroup(4),
+	}
+	// a connection opening under peerConnMux holds the only slot
+	require.NoError(t, e.connSemaphore.Add(context.Background()))
+	defer e.connSemaphore.Done()
+
+	done := make(chan error)
+	go func() {
+		done <- e.runPeerWorkers([]string{"peer-a", "peer-b"}, func(string) error { return nil })
+	}()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the peer workers wait for the connection slots")
+	}
+}
+
 func Test_ParseNATExternalIPMappings(t *testing.T) {
 	ifaceList, err := net.Interfaces()
 	if err != nil {
@@ -1665,3 +1757,27 @@ func getPeers(e *Engine) int {
 
 	return len(e.peerStore.PeersPubKey())
 }
+
+func TestEngine_PeerPreSharedKey(t *testing.T) {
+	localKey, err := wgtypes.GenerateKey()
+	require.NoError(t, err)
+	peerKey, err := wgtypes.GenerateKey()
+	require.NoError(t, err)
+
+	engine := &Engine{config: &EngineConfig{PreSharedKey: &localKey}}
+
+	got := engine.peerPreSharedKey(&mgmtProto.RemotePeerConfig{})
+	assert.Equal(t, localKey, *got, "the local key is used without a peer key")
+
+	got = engine.peerPreSharedKey(&mgmtProto.RemotePeerConfig{PreSharedKey: peerKey.String()})
+	assert.Equal(t, peerKey, *got, "the peer key takes precedence")
+
+	got = engine.peerPreSharedKey(&mgmtProto.RemotePeerConfig{PreSharedKey: "invalid"})
+	assert.Equal(t, localKey, *got, "an invalid peer key falls back to the local key")
+
+	assert.True(t, preSharedKeyEqual(nil, nil))
+	assert.False(t, preSharedKeyEqual(&localKey, nil))
+	assert.False(t, preSharedKeyEqual(&localKey, &peerKey))
+	peerKeyCopy := peerKey
+	assert.True(t, preSharedKeyEqual(&peerKey, &peerKeyCopy))
+}
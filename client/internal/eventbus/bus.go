@@ -0,0 +1,198 @@
+package eventbus
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+const defaultBufferSize = 64
+
+// Topic names the kind of an event
+type Topic string
+
+// Event is a structured event of the engine or one of its components
+type Event interface {
+	Topic() Topic
+}
+
+// Handler handles the events synchronously on the goroutine of the publisher
+type Handler func(Event)
+
+// Policy decides what happens to the events a subscriber can't keep up with
+type Policy int
+
+const (
+	// PolicyDrop drops the events that don't fit into the buffer of the subscription, the publishers never wait
+	PolicyDrop Policy = iota
+	// PolicyBlock makes the publishers wait until the subscription has room for the event or is closed
+	PolicyBlock
+)
+
+// SubscribeOptions configures a subscription
+type SubscribeOptions struct {
+	// Buffer is the number of events the subscription queues. Zero means default.
+	Buffer int
+	// Policy decides what happens once the buffer is full
+	Policy Policy
+	// Topics limits the subscription to the given topics, empty subscribes to all topics
+	Topics []Topic
+}
+
+type handlerEntry struct {
+	id      uint64
+	handler Handler
+}
+
+// Bus delivers the events of the engine components to the handlers and the subscriptions. A nil Bus drops all events.
+type Bus struct {
+	mu       sync.RWMutex
+	nextID   uint64
+	handlers []handlerEntry
+	subs     map[uint64]*Subscription
+}
+
+// New returns an empty Bus
+func New() *Bus {
+	return &Bus{
+		subs: make(map[uint64]*Subscription),
+	}
+}
+
+// Handle registers a handler that runs on the goroutine of the publisher before Publish returns. The handlers are
+// meant for the internal subscribers whose state must be current once the publisher continues, they must neither
+// block nor publish. The returned function removes the handler.
+func (b *Bus) Handle(handler Handler) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	id := b.nextID
+	b.handlers = append(b.handlers, handlerEntry{id: id, handler: handler})
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		for i, entry := range b.handlers {
+			if entry.id == id {
+				b.handlers = append(b.handlers[:i:i], b.handlers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Subscribe returns a subscription that receives the events through a buffered channel. The subscription must be
+// closed once it is no longer read.
+func (b *Bus) Subscribe(opts SubscribeOptions) *Subscription {
+	size := opts.Buffer
+	if size <= 0 {
+		size = defaultBufferSize
+	}
+
+	sub := &Subscription{
+		bus:    b,
+		events: make(chan Event, size),
+		done:   make(chan struct{}),
+		policy: opts.Policy,
+	}
+	if len(opts.Topics) > 0 {
+		sub.topics = make(map[Topic]struct{}, len(opts.Topics))
+		for _, topic := range opts.Topics {
+			sub.topics[topic] = struct{}{}
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	sub.id = b.nextID
+	b.subs[sub.id] = sub
+	return sub
+}
+
+// Publish runs the handlers and queues the event for the subscriptions, in the order they registered
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	handlers := make([]Handler, 0, len(b.handlers))
+	for _, entry := range b.handlers {
+		handlers = append(handlers, entry.handler)
+	}
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+
+	// the read lock keeps the subscriptions from closing their channel while the event is queued
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, sub := range b.subs {
+		sub.deliver(event)
+	}
+}
+
+// Subscription receives the events of a Bus
+type Subscription struct {
+	bus       *Bus
+	id        uint64
+	events    chan Event
+	done      chan struct{}
+	closeOnce sync.Once
+	policy    Policy
+	topics    map[Topic]struct{}
+	dropped   atomic.Uint64
+}
+
+// Events returns the channel of the events, it is closed once the subscription is closed
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Dropped returns the number of events the subscription dropped because its buffer was full
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close stops the subscription and closes the events channel
+func (s *Subscription) Close() {
+	s.closeOnce.Do(func() {
+		// releases the publishers blocked on this subscription before the bus lock is taken
+		close(s.done)
+
+		s.bus.mu.Lock()
+		defer s.bus.mu.Unlock()
+
+		delete(s.bus.subs, s.id)
+		close(s.events)
+	})
+}
+
+func (s *Subscription) deliver(event Event) {
+	if s.topics != nil {
+		if _, ok := s.topics[event.Topic()]; !ok {
+			return
+		}
+	}
+
+	if s.policy == PolicyBlock {
+		select {
+		case s.events <- event:
+		case <-s.done:
+		}
+		return
+	}
+
+	select {
+	case s.events <- event:
+	case <-s.done:
+	default:
+		s.dropped.Add(1)
+	}
+}
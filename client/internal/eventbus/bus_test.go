@@ -0,0 +1,126 @@
+package eventbus
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBus_Handle(t *testing.T) {
+	bus := New()
+
+	var got []Event
+	remove := bus.Handle(func(event Event) {
+		got = append(got, event)
+	})
+
+	bus.Publish(NetworkMapUpdated{Serial: 1})
+	bus.Publish(RouteChanged{Network: "10.0.0.0/24", Peer: "peer"})
+	assert.Equal(t, []Event{NetworkMapUpdated{Serial: 1}, RouteChanged{Network: "10.0.0.0/24", Peer: "peer"}}, got,
+		"the handlers run before Publish returns, in publish order")
+
+	remove()
+	bus.Publish(NetworkMapUpdated{Serial: 2})
+	assert.Len(t, got, 2, "removed handlers get no events")
+}
+
+func TestBus_NilPublish(t *testing.T) {
+	var bus *Bus
+	assert.NotPanics(t, func() { bus.Publish(NetworkMapUpdated{}) })
+}
+
+func TestSubscription_Topics(t *testing.T) {
+	bus := New()
+	sub := bus.Subscribe(SubscribeOptions{Topics: []Topic{TopicRoute}})
+	defer sub.Close()
+
+	bus.Publish(NetworkMapUpdated{Serial: 1})
+	bus.Publish(RouteChanged{Network: "10.0.0.0/24"})
+
+	select {
+	case event := <-sub.Events():
+		assert.Equal(t, RouteChanged{Network: "10.0.0.0/24"}, event)
+	default:
+		t.Fatal("expected the route event")
+	}
+	assert.Empty(t, sub.Events(), "events of other topics are not delivered")
+}
+
+func TestSubscription_Drop(t *testing.T) {
+	bus := New()
+	sub := bus.Subscribe(SubscribeOptions{Buffer: 2})
+	defer sub.Close()
+
+	for i := uint64(0); i < 5; i++ {
+		bus.Publish(NetworkMapUpdated{Serial: i})
+	}
+
+	assert.Equal(t, uint64(3), sub.Dropped())
+	assert.Equal(t, NetworkMapUpdated{Serial: 0}, <-sub.Events())
+	assert.Equal(t, NetworkMapUpdated{Serial: 1}, <-sub.Events())
+}
+
+func TestSubscription_Block(t *testing.T) {
+	bus := New()
+	sub := bus.Subscribe(SubscribeOptions{Buffer: 1, Policy: PolicyBlock})
+
+	bus.Publish(NetworkMapUpdated{Serial: 1})
+
+	published := make(chan struct{})
+	go func() {
+		bus.Publish(NetworkMapUpdated{Serial: 2})
+		close(published)
+	}()
+
+	select {
+	case <-published:
+		t.Fatal("the publisher should wait for room in the buffer")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	assert.Equal(t, NetworkMapUpdated{Serial: 1}, <-sub.Events())
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("the publisher should continue once the buffer has room")
+	}
+	assert.Zero(t, sub.Dropped())
+
+	// a closed subscription releases the blocked publishers
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		bus.Publish(NetworkMapUpdated{Serial: 3})
+	}()
+	time.Sleep(10 * time.Millisecond)
+	sub.Close()
+	wg.Wait()
+
+	var remaining []Event
+	for event := range sub.Events() {
+		remaining = append(remaining, event)
+	}
+	assert.Equal(t, []Event{NetworkMapUpdated{Serial: 2}}, remaining, "the queued events stay readable after close")
+}
+
+func TestHistory(t *testing.T) {
+	history := NewHistory(3)
+	assert.Empty(t, history.Records())
+
+	for i := uint64(1); i <= 4; i++ {
+		history.Record(NetworkMapUpdated{Serial: i})
+	}
+
+	records := history.Records()
+	require.Len(t, records, 3)
+	for i, record := range records {
+		assert.Equal(t, NetworkMapUpdated{Serial: uint64(i + 2)}, record.Event, "the oldest events are replaced first")
+	}
+
+	var nilHistory *History
+	assert.Nil(t, nilHistory.Records())
+}
@@ -0,0 +1,67 @@
+package eventbus
+
+import (
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/system"
+	"github.com/netbirdio/netbird/route"
+)
+
+const (
+	TopicNetworkMap Topic = "network-map"
+	TopicRoute      Topic = "route"
+	TopicDNS        Topic = "dns"
+	TopicFirewall   Topic = "firewall"
+	TopicRoles      Topic = "roles"
+)
+
+// NetworkMapUpdated is published by the engine once a network map is applied
+type NetworkMapUpdated struct {
+	Serial uint64
+}
+
+func (NetworkMapUpdated) Topic() Topic { return TopicNetworkMap }
+
+// RouteChanged is published by the route manager when a network is routed through a peer or stops being routed
+type RouteChanged struct {
+	// Network is the prefix or the domains of the route
+	Network    string
+	ResourceID route.ResID
+	// Peer is the routing peer of a client route, empty for the routes the local peer serves
+	Peer    string
+	Removed bool
+}
+
+func (RouteChanged) Topic() Topic { return TopicRoute }
+
+// IsLocal reports whether the local peer serves the route
+func (e RouteChanged) IsLocal() bool {
+	return e.Peer == ""
+}
+
+// DNSStatesChanged is published by the DNS server when the state of the nameserver groups changed
+type DNSStatesChanged struct {
+	States []peer.NSGroupState
+}
+
+func (DNSStatesChanged) Topic() Topic { return TopicDNS }
+
+// FirewallBackendChanged is published by the engine once it selected the firewall backend
+type FirewallBackendChanged struct {
+	State peer.FirewallBackendState
+}
+
+func (FirewallBackendChanged) Topic() Topic { return TopicFirewall }
+
+// FirewallSetsChanged is published by the engine when the sizes of the firewall sets were read
+type FirewallSetsChanged struct {
+	Sets []peer.FirewallSetState
+}
+
+func (FirewallSetsChanged) Topic() Topic { return TopicFirewall }
+
+// RolesChanged is published by the engine when the roles of the local peer changed
+type RolesChanged struct {
+	Roles system.Roles
+}
+
+func (RolesChanged) Topic() Topic { return TopicRoles }
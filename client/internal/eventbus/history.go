@@ -0,0 +1,58 @@
+package eventbus
+
+import (
+	"sync"
+	"time"
+)
+
+// Record is an event with the time it was published
+type Record struct {
+	Time  time.Time
+	Event Event
+}
+
+// History keeps the latest events of one or more buses, e.g. for the debug bundle
+type History struct {
+	mu      sync.Mutex
+	records []Record
+	next    int
+	full    bool
+}
+
+// NewHistory returns a History that keeps the given number of events
+func NewHistory(size int) *History {
+	return &History{
+		records: make([]Record, size),
+	}
+}
+
+// Record stores the event, replacing the oldest one once the history is full. It is a Handler.
+func (h *History) Record(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.records) == 0 {
+		return
+	}
+
+	h.records[h.next] = Record{Time: time.Now(), Event: event}
+	h.next = (h.next + 1) % len(h.records)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// Records returns the stored events, oldest first
+func (h *History) Records() []Record {
+	if h == nil {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.full {
+		return append([]Record(nil), h.records[:h.next]...)
+	}
+	return append(append([]Record(nil), h.records[h.next:]...), h.records[:h.next]...)
+}
@@ -0,0 +1,75 @@
+package internal
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/eventbus"
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+	"github.com/netbirdio/netbird/client/internal/peer"
+	cProto "github.com/netbirdio/netbird/client/proto"
+	"github.com/netbirdio/netbird/client/system"
+)
+
+// newEventBus returns an event bus that keeps the status recorder up to date with the events of the engine
+func newEventBus(statusRecorder *peer.Status) *eventbus.Bus {
+	bus := eventbus.New()
+	bus.Handle(statusEventHandler(statusRecorder))
+	return bus
+}
+
+// statusEventHandler applies the events of the engine and its components to the status recorder
+func statusEventHandler(statusRecorder *peer.Status) eventbus.Handler {
+	return func(event eventbus.Event) {
+		switch ev := event.(type) {
+		case eventbus.NetworkMapUpdated:
+			statusRecorder.PublishEvent(cProto.SystemEvent_INFO, cProto.SystemEvent_SYSTEM, "Network map updated", "", nil)
+		case eventbus.RouteChanged:
+			applyRouteChange(statusRecorder, ev)
+		case eventbus.DNSStatesChanged:
+			statusRecorder.UpdateDNSStates(ev.States)
+		case eventbus.FirewallBackendChanged:
+			statusRecorder.UpdateFirewallBackend(ev.State)
+		case eventbus.FirewallSetsChanged:
+			statusRecorder.UpdateFirewallSets(ev.Sets)
+		case eventbus.RolesChanged:
+			statusRecorder.UpdateLocalPeerRoles(ev.Roles.Names())
+		}
+	}
+}
+
+func applyRouteChange(statusRecorder *peer.Status, ev eventbus.RouteChanged) {
+	switch {
+	case ev.IsLocal() && ev.Removed:
+		statusRecorder.RemoveLocalPeerStateRoute(ev.Network)
+	case ev.IsLocal():
+		statusRecorder.AddLocalPeerStateRoute(ev.Network, ev.ResourceID)
+	case ev.Removed:
+		if err := statusRecorder.RemovePeerStateRoute(ev.Peer, ev.Network); err != nil {
+			log.Warnf("Failed to update peer state: %v", err)
+		}
+	default:
+		if err := statusRecorder.AddPeerStateRoute(ev.Peer, ev.Network, ev.ResourceID); err != nil {
+			log.Warnf("Failed to update peer state: %v", err)
+		}
+	}
+}
+
+// flowEventHandler selects the flow sampling of the device class the roles of the peer belong to
+func flowEventHandler(flowManager nftypes.FlowManager) eventbus.Handler {
+	return func(event eventbus.Event) {
+		if ev, ok := event.(eventbus.RolesChanged); ok {
+			flowManager.SetDeviceClass(rolesDeviceClass(ev.Roles))
+		}
+	}
+}
+
+func rolesDeviceClass(roles system.Roles) nftypes.DeviceClass {
+	switch {
+	case roles.ExitNode:
+		return nftypes.DeviceClassExitNode
+	case roles.RoutingPeer:
+		return nftypes.DeviceClassRoutingPeer
+	default:
+		return nftypes.DeviceClassClient
+	}
+}
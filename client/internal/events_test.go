@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/internal/eventbus"
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/system"
+)
+
+func TestStatusEventHandler(t *testing.T) {
+	recorder := peer.NewRecorder("https://api.netbird.io:443")
+	require.NoError(t, recorder.AddPeer("peer1", "peer1.netbird.cloud", "100.64.0.2"))
+	bus := newEventBus(recorder)
+
+	bus.Publish(eventbus.RouteChanged{Network: "10.0.0.0/24", ResourceID: "res1", Peer: "peer1"})
+	bus.Publish(eventbus.RouteChanged{Network: "192.168.0.0/24", ResourceID: "res2"})
+
+	state, err := recorder.GetPeer("peer1")
+	require.NoError(t, err)
+	assert.Contains(t, state.GetRoutes(), "10.0.0.0/24")
+	assert.Contains(t, recorder.GetLocalPeerState().Routes, "192.168.0.0/24")
+
+	bus.Publish(eventbus.RouteChanged{Network: "10.0.0.0/24", Peer: "peer1", Removed: true})
+	bus.Publish(eventbus.RouteChanged{Network: "192.168.0.0/24", Removed: true})
+
+	state, err = recorder.GetPeer("peer1")
+	require.NoError(t, err)
+	assert.NotContains(t, state.GetRoutes(), "10.0.0.0/24")
+	assert.NotContains(t, recorder.GetLocalPeerState().Routes, "192.168.0.0/24")
+
+	states := []peer.NSGroupState{{ID: "ns1", Enabled: false, Error: errors.New("unreachable")}}
+	bus.Publish(eventbus.DNSStatesChanged{States: states})
+	assert.Equal(t, states, recorder.GetDNSStates())
+
+	backend := peer.FirewallBackendState{Backend: "nftables", Reason: "default"}
+	bus.Publish(eventbus.FirewallBackendChanged{State: backend})
+	assert.Equal(t, backend, recorder.GetFirewallBackend())
+
+	sets := []peer.FirewallSetState{{Name: "nb-peers", Elements: 3}}
+	bus.Publish(eventbus.FirewallSetsChanged{Sets: sets})
+	assert.Equal(t, sets, recorder.GetFirewallSets())
+
+	bus.Publish(eventbus.RolesChanged{Roles: system.Roles{ExitNode: true, RoutingPeer: true}})
+	assert.Equal(t, []string{system.RoleRoutingPeer, system.RoleExitNode}, recorder.GetLocalPeerState().Roles)
+}
+
+func TestRolesDeviceClass(t *testing.T) {
+	assert.Equal(t, nftypes.DeviceClassClient, rolesDeviceClass(system.Roles{IngressGateway: true}))
+	assert.Equal(t, nftypes.DeviceClassRoutingPeer, rolesDeviceClass(system.Roles{RoutingPeer: true}))
+	assert.Equal(t, nftypes.DeviceClassExitNode, rolesDeviceClass(system.Roles{RoutingPeer: true, ExitNode: true}))
+}
@@ -0,0 +1,16 @@
+package internal
+
+import (
+	"github.com/netbirdio/netbird/client/firewall"
+	"github.com/netbirdio/netbird/client/internal/eventbus"
+	"github.com/netbirdio/netbird/client/internal/peer"
+)
+
+// updateFirewallBackend publishes which firewall backend the engine uses and why
+func (e *Engine) updateFirewallBackend(selection firewall.Selection) {
+	e.eventBus.Publish(eventbus.FirewallBackendChanged{State: peer.FirewallBackendState{
+		Backend: string(selection.Backend),
+		Native:  string(selection.Native),
+		Reason:  selection.Reason,
+	}})
+}
@@ -0,0 +1,29 @@
+package internal
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	firewallManager "github.com/netbirdio/netbird/client/firewall/manager"
+	"github.com/netbirdio/netbird/client/internal/eventbus"
+	"github.com/netbirdio/netbird/client/internal/peer"
+)
+
+// updateFirewallSetStats publishes the sizes of the firewall sets
+func (e *Engine) updateFirewallSetStats() {
+	reporter, ok := e.firewall.(firewallManager.SetSizeReporter)
+	if !ok {
+		return
+	}
+
+	sizes, err := reporter.SetSizes()
+	if err != nil {
+		log.Warnf("failed to read the firewall set sizes: %v", err)
+		return
+	}
+
+	sets := make([]peer.FirewallSetState, 0, len(sizes))
+	for _, size := range sizes {
+		sets = append(sets, peer.FirewallSetState{Name: size.Name, Elements: size.Elements})
+	}
+	e.eventBus.Publish(eventbus.FirewallSetsChanged{Sets: sets})
+}
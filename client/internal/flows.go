@@ -0,0 +1,22 @@
+package internal
+
+import (
+	"errors"
+
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+)
+
+var errFlowManagerNotRunning = errors.New("flow manager is not running")
+
+// QueryFlows returns the recently recorded traffic flows selected by the query, newest first. The flows are only
+// recorded while the traffic events are enabled.
+func (e *Engine) QueryFlows(query nftypes.EventQuery) ([]*nftypes.Event, error) {
+	e.syncMsgMux.Lock()
+	flowManager := e.flowManager
+	e.syncMsgMux.Unlock()
+
+	if flowManager == nil {
+		return nil, errFlowManagerNotRunning
+	}
+	return flowManager.GetLogger().QueryEvents(query), nil
+}
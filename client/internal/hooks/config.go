@@ -0,0 +1,74 @@
+package hooks
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// defaultTimeout limits hooks that don't configure a timeout
+const defaultTimeout = 10 * time.Second
+
+// Config describes a hook that runs on lifecycle events. Either Command or URL must be set.
+type Config struct {
+	// Name is used for logging only
+	Name string
+	// Events are the lifecycle event types the hook runs on, like peer_connected. All events match if empty.
+	Events []string
+
+	// Command is executed with the event as JSON on stdin and as NB_EVENT environment variables
+	Command []string
+	// URL is the webhook the event is POSTed to as JSON
+	URL string
+	// Headers are added to webhook requests, e.g. for authentication
+	Headers map[string]string
+
+	// Timeout limits a single run of the hook. Zero means default (10s).
+	Timeout time.Duration
+}
+
+// Validate checks that the hook has exactly one valid target
+func (c Config) Validate() error {
+	switch {
+	case len(c.Command) > 0 && c.URL != "":
+		return fmt.Errorf("hook %s: command and url are mutually exclusive", c)
+	case len(c.Command) > 0:
+		return nil
+	case c.URL != "":
+		u, err := url.Parse(c.URL)
+		if err != nil {
+			return fmt.Errorf("hook %s: invalid url: %w", c, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("hook %s: unsupported url scheme %q", c, u.Scheme)
+		}
+		return nil
+	default:
+		return fmt.Errorf("hook %s: either command or url is required", c)
+	}
+}
+
+func (c Config) matches(eventType string) bool {
+	return len(c.Events) == 0 || slices.Contains(c.Events, eventType)
+}
+
+func (c Config) timeout() time.Duration {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return defaultTimeout
+}
+
+func (c Config) String() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	if c.URL != "" {
+		return c.URL
+	}
+	if len(c.Command) > 0 {
+		return c.Command[0]
+	}
+	return "unnamed"
+}
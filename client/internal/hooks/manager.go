@@ -0,0 +1,223 @@
+// Package hooks runs user configured commands and webhooks on lifecycle events of the engine, like peers connecting
+// or routes being added, so users can integrate the client with firewalls or monitoring.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// queueSize is the number of events buffered while hooks are running. Further events are dropped.
+const queueSize = 256
+
+var envKeyReplacer = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// Event is the payload passed to hooks
+type Event struct {
+	Event     string            `json:"event"`
+	Message   string            `json:"message"`
+	Severity  string            `json:"severity"`
+	Category  string            `json:"category"`
+	Metadata  map[string]string `json:"metadata"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// Manager runs the configured hooks on the lifecycle events published by the status recorder.
+// Hooks run one at a time in the order of the events.
+type Manager struct {
+	configs        []Config
+	statusRecorder *peer.Status
+	httpClient     *http.Client
+
+	sub   *peer.EventSubscription
+	queue chan Event
+	wg    sync.WaitGroup
+}
+
+// NewManager returns a hook manager for the valid configs. Invalid configs are logged and skipped.
+func NewManager(configs []Config, statusRecorder *peer.Status) *Manager {
+	var valid []Config
+	for _, cfg := range configs {
+		if err := cfg.Validate(); err != nil {
+			log.Warnf("skipping hook: %v", err)
+			continue
+		}
+		valid = append(valid, cfg)
+	}
+
+	return &Manager{
+		configs:        valid,
+		statusRecorder: statusRecorder,
+		httpClient:     &http.Client{},
+	}
+}
+
+// Start subscribes to the lifecycle events
+func (m *Manager) Start() {
+	if len(m.configs) == 0 {
+		return
+	}
+
+	m.sub = m.statusRecorder.SubscribeToEvents(peer.EventFilter{})
+	m.queue = make(chan Event, queueSize)
+
+	m.wg.Add(2)
+	go func() {
+		defer m.wg.Done()
+		defer close(m.queue)
+		m.receive()
+	}()
+	go func() {
+		defer m.wg.Done()
+		for event := range m.queue {
+			m.run(event)
+		}
+	}()
+}
+
+// Close unsubscribes from the events and waits until the pending events are handled, so hooks still see the
+// events published while the engine shuts down.
+func (m *Manager) Close() {
+	if m.sub == nil {
+		return
+	}
+
+	m.statusRecorder.UnsubscribeFromEvents(m.sub)
+	m.wg.Wait()
+	m.sub = nil
+}
+
+func (m *Manager) receive() {
+	for e := range m.sub.Events() {
+		eventType := e.GetMetadata()[peer.EventTypeKey]
+		if eventType == "" {
+			continue
+		}
+
+		event := toEvent(eventType, e)
+		select {
+		case m.queue <- event:
+		default:
+			log.Warnf("hook queue full, dropping %s event", eventType)
+		}
+	}
+}
+
+func (m *Manager) run(event Event) {
+	for _, cfg := range m.configs {
+		if !cfg.matches(event.Event) {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.timeout())
+		var err error
+		if cfg.URL != "" {
+			err = m.post(ctx, cfg, event)
+		} else {
+			err = runCommand(ctx, cfg, event)
+		}
+		cancel()
+
+		if err != nil {
+			log.Warnf("hook %s failed on %s event: %v", cfg, event.Event, err)
+			continue
+		}
+		log.Debugf("hook %s ran on %s event", cfg, event.Event)
+	}
+}
+
+func (m *Manager) post(ctx context.Context, cfg Config, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Debugf("failed to close response body: %v", err)
+		}
+	}()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return nil
+}
+
+func runCommand(ctx context.Context, cfg Config, event Event) error {
+	input, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, cfg.Command[0], cfg.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Env = append(os.Environ(), eventEnv(event)...)
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// eventEnv returns the event as environment variables: NB_EVENT holds the type and every metadata entry is
+// passed as NB_EVENT_<KEY>
+func eventEnv(event Event) []string {
+	env := []string{
+		"NB_EVENT=" + event.Event,
+		"NB_EVENT_MESSAGE=" + event.Message,
+	}
+	for k, v := range event.Metadata {
+		key := envKeyReplacer.ReplaceAllString(strings.ToUpper(k), "_")
+		env = append(env, "NB_EVENT_"+key+"="+v)
+	}
+	return env
+}
+
+func toEvent(eventType string, e *proto.SystemEvent) Event {
+	metadata := make(map[string]string, len(e.GetMetadata()))
+	for k, v := range e.GetMetadata() {
+		if k == peer.EventTypeKey {
+			continue
+		}
+		metadata[k] = v
+	}
+
+	return Event{
+		Event:     eventType,
+		Message:   e.GetMessage(),
+		Severity:  e.GetSeverity().String(),
+		Category:  e.GetCategory().String(),
+		Metadata:  metadata,
+		Timestamp: e.GetTimestamp().AsTime(),
+	}
+}
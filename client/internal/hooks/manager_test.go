@@ -0,0 +1,119 @@
+package hooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "command", cfg: Config{Command: []string{"/bin/true"}}},
+		{name: "webhook", cfg: Config{URL: "https://example.com/hook"}},
+		{name: "no target", cfg: Config{}, wantErr: true},
+		{name: "both targets", cfg: Config{Command: []string{"/bin/true"}, URL: "https://example.com"}, wantErr: true},
+		{name: "unsupported scheme", cfg: Config{URL: "ftp://example.com"}, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestManager_Webhook(t *testing.T) {
+	var mu sync.Mutex
+	var received []Event
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("Authorization"))
+
+		var event Event
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+
+		mu.Lock()
+		received = append(received, event)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	recorder := peer.NewRecorder("https://mgm")
+	m := NewManager([]Config{{
+		URL:     server.URL,
+		Events:  []string{peer.EventPeerConnected},
+		Headers: map[string]string{"Authorization": "secret"},
+	}}, recorder)
+	m.Start()
+
+	// regular events and filtered lifecycle events don't trigger the hook
+	recorder.PublishEvent(proto.SystemEvent_INFO, proto.SystemEvent_SYSTEM, "Network map updated", "", nil)
+	recorder.PublishLifecycleEvent(peer.EventPeerDisconnected, proto.SystemEvent_INFO, proto.SystemEvent_CONNECTIVITY, "Peer disconnected", "", nil)
+	recorder.PublishLifecycleEvent(peer.EventPeerConnected, proto.SystemEvent_INFO, proto.SystemEvent_CONNECTIVITY, "Peer connected", "", map[string]string{"peer": "key"})
+
+	// pending events are handled on close
+	m.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 1)
+	assert.Equal(t, peer.EventPeerConnected, received[0].Event)
+	assert.Equal(t, map[string]string{"peer": "key"}, received[0].Metadata)
+}
+
+func TestManager_Command(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a posix shell")
+	}
+
+	out := filepath.Join(t.TempDir(), "out")
+
+	recorder := peer.NewRecorder("https://mgm")
+	m := NewManager([]Config{{
+		Command: []string{"/bin/sh", "-c", `echo "$NB_EVENT $NB_EVENT_NETWORK" > "$0"`, out},
+		Timeout: 5 * time.Second,
+	}}, recorder)
+	m.Start()
+
+	recorder.PublishLifecycleEvent(peer.EventRouteAdded, proto.SystemEvent_INFO, proto.SystemEvent_NETWORK, "Route added", "", map[string]string{"network": "10.0.0.0/8"})
+	m.Close()
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "route_added 10.0.0.0/8\n", string(data))
+}
+
+func TestEventEnv(t *testing.T) {
+	env := eventEnv(Event{
+		Event:    peer.EventPeerConnected,
+		Message:  "Peer connected",
+		Metadata: map[string]string{"peer-key": "abc"},
+	})
+
+	assert.ElementsMatch(t, []string{
+		"NB_EVENT=peer_connected",
+		"NB_EVENT_MESSAGE=Peer connected",
+		"NB_EVENT_PEER_KEY=abc",
+	}, env)
+}
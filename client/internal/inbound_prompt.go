@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/inboundprompt"
+)
+
+var errInboundPromptsDisabled = errors.New("inbound prompts are disabled")
+
+// startInboundPrompts subscribes the inbound prompts to the flows of the new flow manager
+func (e *Engine) startInboundPrompts() {
+	if !e.config.InboundPrompts {
+		return
+	}
+
+	e.inboundPrompts = inboundprompt.NewManager(e.statusRecorder, e.wgInterface.Address())
+	e.flowManager.SetFlowListener(e.inboundPrompts.OnFlow)
+	log.Infof("inbound connection prompts are enabled")
+}
+
+// setInboundPromptsFirewall closes the denied ports again on a new firewall
+func (e *Engine) setInboundPromptsFirewall() {
+	if e.inboundPrompts == nil {
+		return
+	}
+	e.inboundPrompts.SetFirewall(e.firewall)
+}
+
+func (e *Engine) stopInboundPrompts() {
+	if e.inboundPrompts == nil {
+		return
+	}
+
+	if e.flowManager != nil {
+		e.flowManager.SetFlowListener(nil)
+	}
+	e.inboundPrompts.Close()
+	e.inboundPrompts = nil
+}
+
+// InboundPrompts returns the pending and the decided inbound prompts, newest first
+func (e *Engine) InboundPrompts() ([]inboundprompt.Prompt, error) {
+	e.syncMsgMux.Lock()
+	prompts := e.inboundPrompts
+	e.syncMsgMux.Unlock()
+
+	if prompts == nil {
+		return nil, errInboundPromptsDisabled
+	}
+	return prompts.Prompts(), nil
+}
+
+// RespondInboundPrompt allows or denies the connections of the prompt for the duration, zero is the default duration
+func (e *Engine) RespondInboundPrompt(id string, allow bool, duration time.Duration) (inboundprompt.Prompt, error) {
+	e.syncMsgMux.Lock()
+	prompts := e.inboundPrompts
+	e.syncMsgMux.Unlock()
+
+	if prompts == nil {
+		return inboundprompt.Prompt{}, errInboundPromptsDisabled
+	}
+	return prompts.Respond(id, allow, duration)
+}
@@ -0,0 +1,351 @@
+// Package inboundprompt asks the user about the first inbound connections of the peers to local ports, for
+// workstations on networks whose access policies allow more than the user wants to expose. The traffic passes while a
+// prompt waits for an answer, a denied port is closed for the peer until the decision expires.
+package inboundprompt
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	firewall "github.com/netbirdio/netbird/client/firewall/manager"
+	"github.com/netbirdio/netbird/client/iface/wgaddr"
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+const (
+	// DefaultDecisionDuration is how long a decision lasts without an explicit duration, and how long an unanswered
+	// prompt waits
+	DefaultDecisionDuration = time.Hour
+	// maxPending caps the unanswered prompts, the flows of further ports pass without a prompt
+	maxPending = 32
+)
+
+// ErrPromptNotFound is returned when answering a prompt that doesn't exist or expired
+var ErrPromptNotFound = errors.New("inbound prompt not found")
+
+// Decision is the answer to a prompt
+type Decision int
+
+const (
+	DecisionPending Decision = iota
+	DecisionAllowed
+	DecisionDenied
+)
+
+func (d Decision) String() string {
+	switch d {
+	case DecisionAllowed:
+		return "allowed"
+	case DecisionDenied:
+		return "denied"
+	default:
+		return "pending"
+	}
+}
+
+// Prompt is the first inbound connection of a peer to a local port
+type Prompt struct {
+	ID       string
+	PeerIP   netip.Addr
+	PeerFQDN string
+	Protocol nftypes.Protocol
+	Port     uint16
+	Created  time.Time
+	Decision Decision
+	// Expires is when the decision ends or the unanswered prompt is dropped, the next connection prompts again
+	Expires time.Time
+}
+
+// Target is the local port of the prompt, e.g. 22/tcp
+func (p Prompt) Target() string {
+	return fmt.Sprintf("%d/%s", p.Port, strings.ToLower(p.Protocol.String()))
+}
+
+// Firewall closes the denied ports
+type Firewall interface {
+	AddPeerFiltering(id []byte, ip net.IP, proto firewall.Protocol, sPort *firewall.Port, dPort *firewall.Port, action firewall.Action, ipsetName string) ([]firewall.Rule, error)
+	DeletePeerRule(rule firewall.Rule) error
+	Flush() error
+}
+
+// Status resolves the peer names and publishes the prompts
+type Status interface {
+	PeerByIP(ip string) (string, bool)
+	PublishLifecycleEvent(eventType string, severity proto.SystemEvent_Severity, category proto.SystemEvent_Category, msg string, userMsg string, metadata map[string]string)
+}
+
+type flowKey struct {
+	peer     netip.Addr
+	protocol nftypes.Protocol
+	port     uint16
+}
+
+type entry struct {
+	Prompt
+	key   flowKey
+	timer *time.Timer
+	rules []firewall.Rule
+}
+
+// Manager prompts for the first inbound connections and applies the answers
+type Manager struct {
+	status Status
+	local  wgaddr.Address
+
+	mu       sync.Mutex
+	firewall Firewall
+	entries  map[flowKey]*entry
+	byID     map[string]*entry
+	closed   bool
+}
+
+// NewManager creates the manager of the prompts for the connections to the local address of the interface
+func NewManager(status Status, local wgaddr.Address) *Manager {
+	return &Manager{
+		status:  status,
+		local:   local,
+		entries: make(map[flowKey]*entry),
+		byID:    make(map[string]*entry),
+	}
+}
+
+// SetFirewall sets the firewall closing the denied ports. The denied ports are closed again on a new firewall.
+func (m *Manager) SetFirewall(fw Firewall) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.firewall = fw
+	for _, e := range m.entries {
+		// the rules are gone with the previous firewall
+		e.rules = nil
+		if e.Decision != DecisionDenied || fw == nil {
+			continue
+		}
+		if err := m.deny(e); err != nil {
+			log.Errorf("failed to close port %s for %s again: %v", e.Target(), e.PeerIP, err)
+		}
+	}
+}
+
+// OnFlow prompts for the first inbound connection of a peer to a local port. It is the listener of the flow events.
+func (m *Manager) OnFlow(event nftypes.EventFields) {
+	if event.Type != nftypes.TypeStart || event.Direction != nftypes.Ingress {
+		return
+	}
+	if event.Protocol != nftypes.TCP && event.Protocol != nftypes.UDP {
+		return
+	}
+	if event.DestIP != m.local.IP || event.SourceIP == m.local.IP || !m.local.Network.Contains(event.SourceIP) {
+		return
+	}
+
+	key := flowKey{peer: event.SourceIP, protocol: event.Protocol, port: event.DestPort}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return
+	}
+	if _, ok := m.entries[key]; ok {
+		return
+	}
+	if m.pending() >= maxPending {
+		log.Debugf("too many unanswered inbound prompts, not prompting for %s to port %d/%s", key.peer, key.port, key.protocol)
+		return
+	}
+
+	now := time.Now()
+	e := &entry{
+		Prompt: Prompt{
+			ID:       newPromptID(),
+			PeerIP:   key.peer,
+			Protocol: key.protocol,
+			Port:     key.port,
+			Created:  now,
+			Decision: DecisionPending,
+			Expires:  now.Add(DefaultDecisionDuration),
+		},
+		key: key,
+	}
+	e.timer = time.AfterFunc(DefaultDecisionDuration, func() {
+		m.expire(e)
+	})
+	m.entries[key] = e
+	m.byID[e.ID] = e
+
+	// the peer lookup and the subscribers are kept off the packet path
+	go m.publish(e)
+}
+
+// Respond applies the answer to the prompt for the duration, zero is DefaultDecisionDuration. A decided prompt can
+// be answered again.
+func (m *Manager) Respond(id string, allow bool, duration time.Duration) (Prompt, error) {
+	if duration <= 0 {
+		duration = DefaultDecisionDuration
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.byID[id]
+	if !ok {
+		return Prompt{}, ErrPromptNotFound
+	}
+
+	if err := m.deleteRules(e); err != nil {
+		return Prompt{}, fmt.Errorf("open port: %w", err)
+	}
+
+	e.Decision = DecisionAllowed
+	if !allow {
+		if err := m.deny(e); err != nil {
+			return Prompt{}, fmt.Errorf("close port: %w", err)
+		}
+		e.Decision = DecisionDenied
+	}
+
+	e.Expires = time.Now().Add(duration)
+	e.timer.Reset(duration)
+	log.Infof("inbound connections from %s to port %s %s for %s", e.PeerIP, e.Target(), e.Decision, duration)
+	return e.Prompt, nil
+}
+
+// Prompts returns the pending and the decided prompts, the newest first
+func (m *Manager) Prompts() []Prompt {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prompts := make([]Prompt, 0, len(m.entries))
+	for _, e := range m.entries {
+		prompts = append(prompts, e.Prompt)
+	}
+	slices.SortFunc(prompts, func(a, b Prompt) int {
+		return b.Created.Compare(a.Created)
+	})
+	return prompts
+}
+
+// Close opens the denied ports and drops the prompts
+func (m *Manager) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.closed = true
+	for _, e := range m.entries {
+		e.timer.Stop()
+		if err := m.deleteRules(e); err != nil {
+			log.Warnf("failed to open port %s for %s: %v", e.Target(), e.PeerIP, err)
+		}
+	}
+	m.entries = make(map[flowKey]*entry)
+	m.byID = make(map[string]*entry)
+}
+
+func (m *Manager) expire(e *entry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// the manager was closed meanwhile
+	if m.entries[e.key] != e {
+		return
+	}
+	delete(m.entries, e.key)
+	delete(m.byID, e.ID)
+
+	if err := m.deleteRules(e); err != nil {
+		log.Errorf("failed to open port %s for %s after the decision expired: %v", e.Target(), e.PeerIP, err)
+	}
+}
+
+// deny closes the port of the prompt for its peer, callers must hold the lock
+func (m *Manager) deny(e *entry) error {
+	if m.firewall == nil {
+		return errors.New("no firewall")
+	}
+
+	protocol := firewall.ProtocolTCP
+	if e.Protocol == nftypes.UDP {
+		protocol = firewall.ProtocolUDP
+	}
+	port := &firewall.Port{Values: []uint16{e.Port}}
+
+	rules, err := m.firewall.AddPeerFiltering(nil, e.PeerIP.AsSlice(), protocol, nil, port, firewall.ActionDrop, "")
+	if err != nil {
+		return err
+	}
+	e.rules = rules
+	return m.firewall.Flush()
+}
+
+// deleteRules opens the port of the prompt again, callers must hold the lock
+func (m *Manager) deleteRules(e *entry) error {
+	if len(e.rules) == 0 || m.firewall == nil {
+		e.rules = nil
+		return nil
+	}
+
+	for _, rule := range e.rules {
+		if err := m.firewall.DeletePeerRule(rule); err != nil {
+			return err
+		}
+	}
+	e.rules = nil
+	return m.firewall.Flush()
+}
+
+// pending counts the unanswered prompts, callers must hold the lock
+func (m *Manager) pending() int {
+	var n int
+	for _, e := range m.entries {
+		if e.Decision == DecisionPending {
+			n++
+		}
+	}
+	return n
+}
+
+func (m *Manager) publish(e *entry) {
+	name := e.PeerIP.String()
+	if fqdn, ok := m.status.PeerByIP(name); ok && fqdn != "" {
+		name = fqdn
+
+		m.mu.Lock()
+		e.PeerFQDN = fqdn
+		m.mu.Unlock()
+	}
+
+	m.status.PublishLifecycleEvent(
+		peer.EventInboundPrompt,
+		proto.SystemEvent_INFO,
+		proto.SystemEvent_NETWORK,
+		"New inbound connection",
+		fmt.Sprintf("%s connects to port %s of this device. Allow or deny it with: netbird inbound allow|deny %s", name, e.Target(), e.ID),
+		map[string]string{
+			"prompt_id": e.ID,
+			"peer":      name,
+			"peer_ip":   e.PeerIP.String(),
+			"target":    e.Target(),
+		},
+	)
+}
+
+// newPromptID returns a short random ID that is easy to type
+func newPromptID() string {
+	b := make([]byte, 4)
+	// never fails, see crypto/rand.Read
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,234 @@
+package inboundprompt
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	firewall "github.com/netbirdio/netbird/client/firewall/manager"
+	"github.com/netbirdio/netbird/client/iface/wgaddr"
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+type mockRule string
+
+func (r mockRule) ID() string {
+	return string(r)
+}
+
+type mockFirewall struct {
+	mu    sync.Mutex
+	next  int
+	rules map[string]string
+}
+
+func newMockFirewall() *mockFirewall {
+	return &mockFirewall{rules: make(map[string]string)}
+}
+
+func (f *mockFirewall) AddPeerFiltering(_ []byte, ip net.IP, proto firewall.Protocol, _ *firewall.Port, dPort *firewall.Port, action firewall.Action, _ string) ([]firewall.Rule, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.next++
+	id := fmt.Sprintf("rule%d", f.next)
+	f.rules[id] = fmt.Sprintf("%s %s %d %d", ip, proto, dPort.Values[0], action)
+	return []firewall.Rule{mockRule(id)}, nil
+}
+
+func (f *mockFirewall) DeletePeerRule(rule firewall.Rule) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.rules, rule.ID())
+	return nil
+}
+
+func (f *mockFirewall) Flush() error {
+	return nil
+}
+
+func (f *mockFirewall) ruleSet() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var rules []string
+	for _, rule := range f.rules {
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+type mockStatus struct {
+	events chan map[string]string
+}
+
+func (s *mockStatus) PeerByIP(ip string) (string, bool) {
+	if ip == "100.64.0.2" {
+		return "peer-a.netbird.cloud", true
+	}
+	return "", false
+}
+
+func (s *mockStatus) PublishLifecycleEvent(_ string, _ proto.SystemEvent_Severity, _ proto.SystemEvent_Category, _ string, _ string, metadata map[string]string) {
+	s.events <- metadata
+}
+
+func newTestManager(t *testing.T) (*Manager, *mockStatus) {
+	t.Helper()
+
+	local, err := wgaddr.ParseWGAddress("100.64.0.1/16")
+	require.NoError(t, err)
+
+	status := &mockStatus{events: make(chan map[string]string, maxPending+1)}
+	m := NewManager(status, local)
+	t.Cleanup(m.Close)
+	return m, status
+}
+
+func inboundFlow(src string, protocol nftypes.Protocol, port uint16) nftypes.EventFields {
+	return nftypes.EventFields{
+		Type:      nftypes.TypeStart,
+		Direction: nftypes.Ingress,
+		Protocol:  protocol,
+		SourceIP:  netip.MustParseAddr(src),
+		DestIP:    netip.MustParseAddr("100.64.0.1"),
+		DestPort:  port,
+	}
+}
+
+func waitEvent(t *testing.T, status *mockStatus) map[string]string {
+	t.Helper()
+
+	select {
+	case metadata := <-status.events:
+		return metadata
+	case <-time.After(time.Second):
+		t.Fatal("no inbound prompt published")
+		return nil
+	}
+}
+
+func TestManager_OnFlow(t *testing.T) {
+	m, status := newTestManager(t)
+
+	m.OnFlow(inboundFlow("100.64.0.2", nftypes.TCP, 22))
+	metadata := waitEvent(t, status)
+	assert.Equal(t, "peer-a.netbird.cloud", metadata["peer"])
+	assert.Equal(t, "22/tcp", metadata["target"])
+
+	// the next connections of the peer to the port don't prompt again
+	m.OnFlow(inboundFlow("100.64.0.2", nftypes.TCP, 22))
+
+	ignored := []nftypes.EventFields{
+		func() nftypes.EventFields {
+			e := inboundFlow("100.64.0.3", nftypes.TCP, 22)
+			e.Direction = nftypes.Egress
+			return e
+		}(),
+		func() nftypes.EventFields {
+			e := inboundFlow("100.64.0.3", nftypes.TCP, 22)
+			e.Type = nftypes.TypeEnd
+			return e
+		}(),
+		func() nftypes.EventFields {
+			e := inboundFlow("100.64.0.3", nftypes.TCP, 22)
+			e.DestIP = netip.MustParseAddr("10.0.0.1")
+			return e
+		}(),
+		inboundFlow("100.64.0.3", nftypes.ICMP, 0),
+		inboundFlow("192.168.1.10", nftypes.TCP, 22),
+	}
+	for _, event := range ignored {
+		m.OnFlow(event)
+	}
+
+	prompts := m.Prompts()
+	require.Len(t, prompts, 1)
+	assert.Equal(t, DecisionPending, prompts[0].Decision)
+	assert.Equal(t, "peer-a.netbird.cloud", prompts[0].PeerFQDN)
+	assert.Equal(t, netip.MustParseAddr("100.64.0.2"), prompts[0].PeerIP)
+}
+
+func TestManager_Respond(t *testing.T) {
+	m, status := newTestManager(t)
+	fw := newMockFirewall()
+	m.SetFirewall(fw)
+
+	m.OnFlow(inboundFlow("100.64.0.2", nftypes.UDP, 5353))
+	id := waitEvent(t, status)["prompt_id"]
+
+	_, err := m.Respond("unknown", false, 0)
+	assert.ErrorIs(t, err, ErrPromptNotFound)
+
+	prompt, err := m.Respond(id, false, 0)
+	require.NoError(t, err)
+	assert.Equal(t, DecisionDenied, prompt.Decision)
+	assert.WithinDuration(t, time.Now().Add(DefaultDecisionDuration), prompt.Expires, time.Second)
+	assert.Equal(t, []string{"100.64.0.2 udp 5353 1"}, fw.ruleSet())
+
+	// changing the decision opens the port again
+	prompt, err = m.Respond(id, true, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, DecisionAllowed, prompt.Decision)
+	assert.Empty(t, fw.ruleSet())
+}
+
+func TestManager_Expire(t *testing.T) {
+	m, status := newTestManager(t)
+	fw := newMockFirewall()
+	m.SetFirewall(fw)
+
+	m.OnFlow(inboundFlow("100.64.0.2", nftypes.TCP, 3389))
+	id := waitEvent(t, status)["prompt_id"]
+
+	_, err := m.Respond(id, false, 50*time.Millisecond)
+	require.NoError(t, err)
+	require.Len(t, fw.ruleSet(), 1)
+
+	require.Eventually(t, func() bool {
+		return len(m.Prompts()) == 0
+	}, time.Second, 10*time.Millisecond)
+	assert.Empty(t, fw.ruleSet(), "the port is opened once the decision expired")
+
+	// the next connection prompts again
+	m.OnFlow(inboundFlow("100.64.0.2", nftypes.TCP, 3389))
+	assert.NotEqual(t, id, waitEvent(t, status)["prompt_id"])
+}
+
+func TestManager_SetFirewall(t *testing.T) {
+	m, status := newTestManager(t)
+	m.SetFirewall(newMockFirewall())
+
+	m.OnFlow(inboundFlow("100.64.0.2", nftypes.TCP, 22))
+	m.OnFlow(inboundFlow("100.64.0.2", nftypes.TCP, 80))
+	first := waitEvent(t, status)["prompt_id"]
+	waitEvent(t, status)
+
+	_, err := m.Respond(first, false, 0)
+	require.NoError(t, err)
+
+	// a new firewall starts without the rules, the denied port is closed again
+	fw := newMockFirewall()
+	m.SetFirewall(fw)
+	assert.Len(t, fw.ruleSet(), 1)
+
+	m.Close()
+	assert.Empty(t, fw.ruleSet(), "closing the manager opens the denied ports")
+}
+
+func TestManager_MaxPending(t *testing.T) {
+	m, _ := newTestManager(t)
+
+	for port := uint16(1); port <= maxPending+1; port++ {
+		m.OnFlow(inboundFlow("100.64.0.2", nftypes.TCP, port))
+	}
+	assert.Len(t, m.Prompts(), maxPending)
+}
@@ -0,0 +1,256 @@
+package ingressgw
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	firewall "github.com/netbirdio/netbird/client/firewall/manager"
+)
+
+const (
+	defaultHealthCheckInterval = 10 * time.Second
+	defaultHealthCheckTimeout  = 5 * time.Second
+	defaultUnhealthyThreshold  = 3
+)
+
+// HealthStatus is the health of the translated address of a forward rule
+type HealthStatus int
+
+const (
+	// HealthUnknown is the status until the first check passed or the threshold of failed checks is reached
+	HealthUnknown HealthStatus = iota
+	HealthUp
+	HealthDown
+)
+
+func (s HealthStatus) String() string {
+	switch s {
+	case HealthUp:
+		return "up"
+	case HealthDown:
+		return "down"
+	default:
+		return "unknown"
+	}
+}
+
+// BackendHealth is the result of the health checks of a translated address
+type BackendHealth struct {
+	Status    HealthStatus
+	LastCheck time.Time
+	// LastError is the error of the last failed check, it's cleared once a check passes
+	LastError string
+}
+
+type dialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// healthChecker periodically probes a translated address of a forward rule
+type healthChecker struct {
+	rule     firewall.ForwardRule
+	backend  netip.Addr
+	check    firewall.ForwardHealthCheck
+	dial     dialFunc
+	onChange func(rule firewall.ForwardRule, backend netip.Addr, health BackendHealth)
+
+	mu       sync.Mutex
+	health   BackendHealth
+	failures int
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newHealthChecker(rule firewall.ForwardRule, backend netip.Addr, dial dialFunc, onChange func(firewall.ForwardRule, netip.Addr, BackendHealth)) *healthChecker {
+	check := rule.HealthCheck
+	if check.Interval <= 0 {
+		check.Interval = defaultHealthCheckInterval
+	}
+	if check.Timeout <= 0 {
+		check.Timeout = defaultHealthCheckTimeout
+	}
+	if check.UnhealthyThreshold <= 0 {
+		check.UnhealthyThreshold = defaultUnhealthyThreshold
+	}
+	if check.Path == "" {
+		check.Path = "/"
+	}
+
+	return &healthChecker{
+		rule:     rule,
+		backend:  backend,
+		check:    check,
+		dial:     dial,
+		onChange: onChange,
+	}
+}
+
+func (c *healthChecker) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.done = make(chan struct{})
+	go c.run(ctx)
+}
+
+func (c *healthChecker) stop() {
+	c.cancel()
+	<-c.done
+}
+
+// Health returns the result of the last checks
+func (c *healthChecker) Health() BackendHealth {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.health
+}
+
+func (c *healthChecker) run(ctx context.Context) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.check.Interval)
+	defer ticker.Stop()
+
+	for {
+		err := c.probe(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		c.record(err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *healthChecker) probe(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.check.Timeout)
+	defer cancel()
+
+	address := c.address()
+	switch c.check.Type {
+	case firewall.HealthCheckHTTP:
+		return c.probeHTTP(ctx, address)
+	default:
+		conn, err := c.dial(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+		if err := conn.Close(); err != nil {
+			log.Debugf("failed to close health check connection to %s: %v", address, err)
+		}
+		return nil
+	}
+}
+
+func (c *healthChecker) probeHTTP(ctx context.Context, address string) error {
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext:       c.dial,
+			DisableKeepAlives: true,
+		},
+		// a redirect proves the backend is up, it may point to a host not reachable from the gateway
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+address+c.check.Path, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Debugf("failed to close health check response body: %v", err)
+		}
+	}()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return errors.New(resp.Status)
+	}
+	return nil
+}
+
+// address is the checked translated address with the first translated port
+func (c *healthChecker) address() string {
+	var port uint16
+	if len(c.rule.TranslatedPort.Values) > 0 {
+		port = c.rule.TranslatedPort.Values[0]
+	}
+	return netip.AddrPortFrom(c.backend, port).String()
+}
+
+func (c *healthChecker) record(err error) {
+	c.mu.Lock()
+	previous := c.health.Status
+	c.health.LastCheck = time.Now()
+	if err == nil {
+		c.failures = 0
+		c.health.Status = HealthUp
+		c.health.LastError = ""
+	} else {
+		c.failures++
+		c.health.LastError = err.Error()
+		if c.failures >= c.check.UnhealthyThreshold {
+			c.health.Status = HealthDown
+		}
+	}
+	health, failures := c.health, c.failures
+	c.mu.Unlock()
+
+	if err != nil {
+		log.Debugf("health check of %s failed (%d/%d): %v", c.address(), failures, c.check.UnhealthyThreshold, err)
+	}
+
+	// the first passed check isn't a change worth reporting
+	if health.Status == previous || previous == HealthUnknown && health.Status == HealthUp {
+		return
+	}
+	c.onChange(c.rule, c.backend, health)
+}
+
+// ruleHealth is the health of the translated addresses of a rule: down if one is down, unknown if one wasn't checked
+// yet and up if all are up. The error names the translated address if the rule has several.
+func ruleHealth(checkers []*healthChecker) BackendHealth {
+	var health BackendHealth
+	for i, checker := range checkers {
+		backendHealth := checker.Health()
+		if i > 0 && healthRank(backendHealth.Status) <= healthRank(health.Status) {
+			continue
+		}
+
+		health = backendHealth
+		if len(checkers) > 1 && health.LastError != "" {
+			health.LastError = fmt.Sprintf("%s: %s", checker.backend, health.LastError)
+		}
+	}
+	return health
+}
+
+// healthRank orders the statuses by severity
+func healthRank(status HealthStatus) int {
+	switch status {
+	case HealthUp:
+		return 0
+	case HealthDown:
+		return 2
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,211 @@
+package ingressgw
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	firewall "github.com/netbirdio/netbird/client/firewall/manager"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+type mockPublisher struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (m *mockPublisher) PublishEvent(severity proto.SystemEvent_Severity, _ proto.SystemEvent_Category, msg string, _ string, _ map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, severity.String()+": "+msg)
+}
+
+func (m *mockPublisher) published() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.events...)
+}
+
+func healthCheckRule(t *testing.T, addr string, check firewall.ForwardHealthCheck) firewall.ForwardRule {
+	t.Helper()
+
+	addrPort := netip.MustParseAddrPort(addr)
+	port, err := firewall.NewPort(int(addrPort.Port()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	check.Interval = 10 * time.Millisecond
+	check.Timeout = time.Second
+	check.UnhealthyThreshold = 2
+	return firewall.ForwardRule{
+		Protocol:          firewall.ProtocolTCP,
+		DestinationPort:   *port,
+		TranslatedAddress: addrPort.Addr(),
+		TranslatedPort:    *port,
+		HealthCheck:       check,
+	}
+}
+
+func waitForHealth(t *testing.T, mgr *Manager, status HealthStatus) BackendHealth {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		states := mgr.RuleStates()
+		if len(states) == 1 && states[0].Health.Status == status {
+			return states[0].Health
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("translated address did not become %s: %v", status, states)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestManager_HealthCheckTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	publisher := &mockPublisher{}
+	mgr := NewManager(&MockDNATFirewall{}, publisher)
+	defer mgr.Close()
+
+	rule := healthCheckRule(t, listener.Addr().String(), firewall.ForwardHealthCheck{Type: firewall.HealthCheckTCP})
+	if err := mgr.Update([]firewall.ForwardRule{rule}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	health := waitForHealth(t, mgr, HealthUp)
+	if health.LastCheck.IsZero() || health.LastError != "" {
+		t.Errorf("unexpected health: %+v", health)
+	}
+	if events := publisher.published(); len(events) != 0 {
+		t.Errorf("the first passed check must not be published: %v", events)
+	}
+
+	_ = listener.Close()
+
+	health = waitForHealth(t, mgr, HealthDown)
+	if health.LastError == "" {
+		t.Errorf("the error of the failed check must be set")
+	}
+	events := publisher.published()
+	if len(events) != 1 || events[0] != "WARNING: Forwarding backend down" {
+		t.Errorf("unexpected events: %v", events)
+	}
+}
+
+func TestManager_HealthCheckHTTP(t *testing.T) {
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" || !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	publisher := &mockPublisher{}
+	mgr := NewManager(&MockDNATFirewall{}, publisher)
+	defer mgr.Close()
+
+	rule := healthCheckRule(t, server.Listener.Addr().String(), firewall.ForwardHealthCheck{Type: firewall.HealthCheckHTTP, Path: "/healthz"})
+	if err := mgr.Update([]firewall.ForwardRule{rule}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	health := waitForHealth(t, mgr, HealthDown)
+	if health.LastError != "503 Service Unavailable" {
+		t.Errorf("unexpected error: %s", health.LastError)
+	}
+
+	healthy.Store(true)
+
+	waitForHealth(t, mgr, HealthUp)
+	events := publisher.published()
+	if len(events) != 2 || events[0] != "WARNING: Forwarding backend down" || events[1] != "INFO: Forwarding backend up" {
+		t.Errorf("unexpected events: %v", events)
+	}
+}
+
+func TestManager_HealthCheckStoppedOnRemoval(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+	}))
+	defer server.Close()
+
+	mgr := NewManager(&MockDNATFirewall{}, nil)
+	rule := healthCheckRule(t, server.Listener.Addr().String(), firewall.ForwardHealthCheck{Type: firewall.HealthCheckHTTP})
+	if err := mgr.Update([]firewall.ForwardRule{rule}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	waitForHealth(t, mgr, HealthUp)
+
+	if err := mgr.Update(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stopped := requests.Load()
+	time.Sleep(50 * time.Millisecond)
+
+	if requests.Load() != stopped {
+		t.Errorf("health check must stop with the rule")
+	}
+}
+
+func TestManager_HealthCheckBackends(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	publisher := &mockPublisher{}
+	mgr := NewManager(&MockDNATFirewall{}, publisher)
+	defer mgr.Close()
+
+	// the second translated address doesn't listen on the port
+	rule := healthCheckRule(t, listener.Addr().String(), firewall.ForwardHealthCheck{Type: firewall.HealthCheckTCP})
+	rule.TranslatedAddresses = []netip.Addr{netip.MustParseAddr("127.0.0.2")}
+	if err := mgr.Update([]firewall.ForwardRule{rule}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	health := waitForHealth(t, mgr, HealthDown)
+	if !strings.HasPrefix(health.LastError, "127.0.0.2: ") {
+		t.Errorf("the error must name the translated address that is down: %s", health.LastError)
+	}
+	events := publisher.published()
+	if len(events) != 1 || events[0] != "WARNING: Forwarding backend down" {
+		t.Errorf("unexpected events: %v", events)
+	}
+}
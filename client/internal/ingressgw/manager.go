@@ -2,13 +2,17 @@ package ingressgw
 
 import (
 	"fmt"
+	"net"
+	"net/netip"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	log "github.com/sirupsen/logrus"
 
 	nberrors "github.com/netbirdio/netbird/client/errors"
 	firewall "github.com/netbirdio/netbird/client/firewall/manager"
+	"github.com/netbirdio/netbird/client/proto"
 )
 
 type DNATFirewall interface {
@@ -16,22 +20,53 @@ type DNATFirewall interface {
 	DeleteDNATRule(rule firewall.Rule) error
 }
 
+// EventPublisher is notified when the translated address of a forward rule goes down or recovers
+type EventPublisher interface {
+	PublishEvent(severity proto.SystemEvent_Severity, category proto.SystemEvent_Category, msg string, userMsg string, metadata map[string]string)
+}
+
 type RulePair struct {
 	firewall.ForwardRule
 	firewall.Rule
 }
 
+// drainingRule is a removed rule kept in place until its drain timeout passed
+type drainingRule struct {
+	RulePair
+	deadline time.Time
+	timer    *time.Timer
+}
+
+// RuleState is a forward rule with the health of its translated addresses
+type RuleState struct {
+	firewall.ForwardRule
+	// Health is only checked for rules with a health check, it's down if one of the translated addresses is down
+	Health   BackendHealth
+	Draining bool
+	// DrainDeadline is the time a draining rule is removed at
+	DrainDeadline time.Time
+}
+
 type Manager struct {
 	dnatFirewall DNATFirewall
+	publisher    EventPublisher
+	dial         dialFunc
 
-	rules   map[string]RulePair // keys is the ID of the ForwardRule
-	rulesMu sync.Mutex
+	rules    map[string]RulePair // keys is the ID of the ForwardRule
+	checkers map[string][]*healthChecker
+	draining map[string]*drainingRule
+	rulesMu  sync.Mutex
 }
 
-func NewManager(dnatFirewall DNATFirewall) *Manager {
+// NewManager creates the manager of the forward rules. The publisher is optional.
+func NewManager(dnatFirewall DNATFirewall, publisher EventPublisher) *Manager {
 	return &Manager{
 		dnatFirewall: dnatFirewall,
+		publisher:    publisher,
+		dial:         (&net.Dialer{}).DialContext,
 		rules:        make(map[string]RulePair),
+		checkers:     make(map[string][]*healthChecker),
+		draining:     make(map[string]*drainingRule),
 	}
 }
 
@@ -49,8 +84,18 @@ func (h *Manager) Update(forwardRules []firewall.ForwardRule) error {
 	// Process new/updated rules
 	for _, fwdRule := range forwardRules {
 		id := fwdRule.ID()
-		if _, ok := h.rules[id]; ok {
+		if rulePair, ok := h.rules[id]; ok {
 			delete(toDelete, id)
+			h.updateRule(id, rulePair, fwdRule)
+			continue
+		}
+
+		if drained, ok := h.draining[id]; ok {
+			drained.timer.Stop()
+			delete(h.draining, id)
+			log.Infof("forward rule has been restored while draining '%s'", fwdRule)
+			h.rules[id] = RulePair{ForwardRule: fwdRule, Rule: drained.Rule}
+			h.startHealthCheck(id, fwdRule)
 			continue
 		}
 
@@ -64,33 +109,161 @@ func (h *Manager) Update(forwardRules []firewall.ForwardRule) error {
 			ForwardRule: fwdRule,
 			Rule:        rule,
 		}
+		h.startHealthCheck(id, fwdRule)
 	}
 
 	// Remove deleted rules
 	for id, rulePair := range toDelete {
+		h.stopHealthCheck(id)
+		delete(h.rules, id)
+
+		if rulePair.DrainTimeout > 0 {
+			h.drain(id, rulePair)
+			continue
+		}
+
 		if err := h.dnatFirewall.DeleteDNATRule(rulePair.Rule); err != nil {
 			mErr = multierror.Append(mErr, fmt.Errorf("failed to delete forward rule '%s': %v", rulePair.ForwardRule.String(), err))
 		}
 		log.Infof("forward rule has been deleted '%s'", rulePair.ForwardRule)
-		delete(h.rules, id)
 	}
 
 	return nberrors.FormatErrorOrNil(mErr)
 }
 
+// updateRule applies the health check and drain settings of an existing rule
+func (h *Manager) updateRule(id string, rulePair RulePair, fwdRule firewall.ForwardRule) {
+	h.rules[id] = RulePair{ForwardRule: fwdRule, Rule: rulePair.Rule}
+	if rulePair.HealthCheck == fwdRule.HealthCheck {
+		return
+	}
+
+	h.stopHealthCheck(id)
+	h.startHealthCheck(id, fwdRule)
+}
+
+// drain keeps the DNAT rule of a removed forward rule, so the established connections can finish
+func (h *Manager) drain(id string, rulePair RulePair) {
+	drained := &drainingRule{
+		RulePair: rulePair,
+		deadline: time.Now().Add(rulePair.DrainTimeout),
+	}
+	drained.timer = time.AfterFunc(rulePair.DrainTimeout, func() {
+		h.finishDrain(id, drained)
+	})
+	h.draining[id] = drained
+	log.Infof("forward rule is draining for %s '%s'", rulePair.DrainTimeout, rulePair.ForwardRule)
+}
+
+func (h *Manager) finishDrain(id string, drained *drainingRule) {
+	h.rulesMu.Lock()
+	defer h.rulesMu.Unlock()
+
+	// the rule was restored or the manager closed meanwhile
+	if h.draining[id] != drained {
+		return
+	}
+	delete(h.draining, id)
+
+	if err := h.dnatFirewall.DeleteDNATRule(drained.Rule); err != nil {
+		log.Errorf("failed to delete drained forward rule '%s': %v", drained.ForwardRule, err)
+		return
+	}
+	log.Infof("forward rule has been deleted after draining '%s'", drained.ForwardRule)
+}
+
+func (h *Manager) startHealthCheck(id string, fwdRule firewall.ForwardRule) {
+	if fwdRule.HealthCheck.Type == firewall.HealthCheckNone {
+		return
+	}
+
+	// the translated addresses are checked separately, a rule balancing across several is partially up
+	backends := fwdRule.Backends()
+	checkers := make([]*healthChecker, 0, len(backends))
+	for _, backend := range backends {
+		checker := newHealthChecker(fwdRule, backend, h.dial, h.publishHealth)
+		checker.start()
+		checkers = append(checkers, checker)
+	}
+	h.checkers[id] = checkers
+}
+
+func (h *Manager) stopHealthCheck(id string) {
+	checkers, ok := h.checkers[id]
+	if !ok {
+		return
+	}
+	for _, checker := range checkers {
+		checker.stop()
+	}
+	delete(h.checkers, id)
+}
+
+func (h *Manager) publishHealth(rule firewall.ForwardRule, backend netip.Addr, health BackendHealth) {
+	address := backend.String()
+	if health.Status == HealthDown {
+		log.Warnf("translated address of forward rule is down '%s': %s", rule, health.LastError)
+	} else {
+		log.Infof("translated address of forward rule is up again '%s'", rule)
+	}
+
+	if h.publisher == nil {
+		return
+	}
+
+	metadata := map[string]string{
+		"protocol":           string(rule.Protocol),
+		"destination_port":   rule.DestinationPort.String(),
+		"translated_address": address,
+		"translated_port":    rule.TranslatedPort.String(),
+		"health":             health.Status.String(),
+	}
+
+	if health.Status == HealthDown {
+		metadata["error"] = health.LastError
+		h.publisher.PublishEvent(
+			proto.SystemEvent_WARNING,
+			proto.SystemEvent_CONNECTIVITY,
+			"Forwarding backend down",
+			fmt.Sprintf("The translated address %s of the forwarded port %s/%s is down: %s", address, rule.Protocol, rule.DestinationPort.String(), health.LastError),
+			metadata,
+		)
+		return
+	}
+
+	h.publisher.PublishEvent(
+		proto.SystemEvent_INFO,
+		proto.SystemEvent_CONNECTIVITY,
+		"Forwarding backend up",
+		fmt.Sprintf("The translated address %s of the forwarded port %s/%s is up again", address, rule.Protocol, rule.DestinationPort.String()),
+		metadata,
+	)
+}
+
 func (h *Manager) Close() error {
 	h.rulesMu.Lock()
 	defer h.rulesMu.Unlock()
 
-	log.Infof("clean up all (%d) forward rules", len(h.rules))
+	for id := range h.checkers {
+		h.stopHealthCheck(id)
+	}
+
+	log.Infof("clean up all (%d) forward rules and (%d) draining rules", len(h.rules), len(h.draining))
 	var mErr *multierror.Error
 	for _, rule := range h.rules {
 		if err := h.dnatFirewall.DeleteDNATRule(rule.Rule); err != nil {
 			mErr = multierror.Append(mErr, fmt.Errorf("failed to delete forward rule '%s': %v", rule, err))
 		}
 	}
+	for _, drained := range h.draining {
+		drained.timer.Stop()
+		if err := h.dnatFirewall.DeleteDNATRule(drained.Rule); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("failed to delete draining forward rule '%s': %v", drained.ForwardRule, err))
+		}
+	}
 
 	h.rules = make(map[string]RulePair)
+	h.draining = make(map[string]*drainingRule)
 	return nberrors.FormatErrorOrNil(mErr)
 }
 
@@ -105,3 +278,27 @@ func (h *Manager) Rules() []firewall.ForwardRule {
 
 	return rules
 }
+
+// RuleStates returns the active and the draining rules with the health of their translated addresses
+func (h *Manager) RuleStates() []RuleState {
+	h.rulesMu.Lock()
+	defer h.rulesMu.Unlock()
+
+	states := make([]RuleState, 0, len(h.rules)+len(h.draining))
+	for id, rulePair := range h.rules {
+		state := RuleState{ForwardRule: rulePair.ForwardRule}
+		if checkers, ok := h.checkers[id]; ok {
+			state.Health = ruleHealth(checkers)
+		}
+		states = append(states, state)
+	}
+	for _, drained := range h.draining {
+		states = append(states, RuleState{
+			ForwardRule:   drained.ForwardRule,
+			Draining:      true,
+			DrainDeadline: drained.deadline,
+		})
+	}
+
+	return states
+}
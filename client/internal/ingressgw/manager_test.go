@@ -3,7 +3,9 @@ package ingressgw
 import (
 	"fmt"
 	"net/netip"
+	"sync"
 	"testing"
+	"time"
 
 	firewall "github.com/netbirdio/netbird/client/firewall/manager"
 )
@@ -23,6 +25,10 @@ func (m *MocFwRule) ID() string {
 
 type MockDNATFirewall struct {
 	throwError bool
+
+	mu      sync.Mutex
+	added   int
+	deleted int
 }
 
 func (m *MockDNATFirewall) AddDNATRule(fwdRule firewall.ForwardRule) (firewall.Rule, error) {
@@ -30,6 +36,10 @@ func (m *MockDNATFirewall) AddDNATRule(fwdRule firewall.ForwardRule) (firewall.R
 		return nil, fmt.Errorf("moc error")
 	}
 
+	m.mu.Lock()
+	m.added++
+	m.mu.Unlock()
+
 	fwRule := &MocFwRule{
 		id: fwdRule.ID(),
 	}
@@ -40,16 +50,26 @@ func (m *MockDNATFirewall) DeleteDNATRule(rule firewall.Rule) error {
 	if m.throwError {
 		return fmt.Errorf("moc error")
 	}
+
+	m.mu.Lock()
+	m.deleted++
+	m.mu.Unlock()
 	return nil
 }
 
+func (m *MockDNATFirewall) counts() (int, int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.added, m.deleted
+}
+
 func (m *MockDNATFirewall) forceToThrowErrors() {
 	m.throwError = true
 }
 
 func TestManager_AddRule(t *testing.T) {
 	fw := &MockDNATFirewall{}
-	mgr := NewManager(fw)
+	mgr := NewManager(fw, nil)
 
 	port, _ := firewall.NewPort(8080)
 
@@ -79,7 +99,7 @@ func TestManager_AddRule(t *testing.T) {
 
 func TestManager_UpdateRule(t *testing.T) {
 	fw := &MockDNATFirewall{}
-	mgr := NewManager(fw)
+	mgr := NewManager(fw, nil)
 
 	port, _ := firewall.NewPort(8080)
 	ruleTCP := firewall.ForwardRule{
@@ -128,7 +148,7 @@ func TestManager_UpdateRule(t *testing.T) {
 
 func TestManager_ExtendRules(t *testing.T) {
 	fw := &MockDNATFirewall{}
-	mgr := NewManager(fw)
+	mgr := NewManager(fw, nil)
 
 	port, _ := firewall.NewPort(8080)
 	ruleTCP := firewall.ForwardRule{
@@ -161,7 +181,7 @@ func TestManager_ExtendRules(t *testing.T) {
 
 func TestManager_UnderlingError(t *testing.T) {
 	fw := &MockDNATFirewall{}
-	mgr := NewManager(fw)
+	mgr := NewManager(fw, nil)
 
 	port, _ := firewall.NewPort(8080)
 	ruleTCP := firewall.ForwardRule{
@@ -196,7 +216,7 @@ func TestManager_UnderlingError(t *testing.T) {
 
 func TestManager_Cleanup(t *testing.T) {
 	fw := &MockDNATFirewall{}
-	mgr := NewManager(fw)
+	mgr := NewManager(fw, nil)
 
 	port, _ := firewall.NewPort(8080)
 	ruleTCP := firewall.ForwardRule{
@@ -225,7 +245,7 @@ func TestManager_DeleteBrokenRule(t *testing.T) {
 
 	// force to throw errors when Add DNAT Rule
 	fw.forceToThrowErrors()
-	mgr := NewManager(fw)
+	mgr := NewManager(fw, nil)
 
 	port, _ := firewall.NewPort(8080)
 	ruleTCP := firewall.ForwardRule{
@@ -256,7 +276,7 @@ func TestManager_DeleteBrokenRule(t *testing.T) {
 
 func TestManager_Close(t *testing.T) {
 	fw := &MockDNATFirewall{}
-	mgr := NewManager(fw)
+	mgr := NewManager(fw, nil)
 
 	port, _ := firewall.NewPort(8080)
 	ruleTCP := firewall.ForwardRule{
@@ -279,3 +299,127 @@ func TestManager_Close(t *testing.T) {
 		t.Errorf("unexpected rules count: %d", len(rules))
 	}
 }
+
+func TestManager_DrainRule(t *testing.T) {
+	fw := &MockDNATFirewall{}
+	mgr := NewManager(fw, nil)
+
+	port, _ := firewall.NewPort(8080)
+	ruleTCP := firewall.ForwardRule{
+		Protocol:          firewall.ProtocolTCP,
+		DestinationPort:   *port,
+		TranslatedAddress: netip.MustParseAddr("172.16.254.1"),
+		TranslatedPort:    *port,
+		DrainTimeout:      100 * time.Millisecond,
+	}
+
+	if err := mgr.Update([]firewall.ForwardRule{ruleTCP}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := mgr.Update(nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if rules := mgr.Rules(); len(rules) != 0 {
+		t.Errorf("unexpected rules count: %d", len(rules))
+	}
+
+	states := mgr.RuleStates()
+	if len(states) != 1 || !states[0].Draining {
+		t.Fatalf("expected a draining rule, got: %v", states)
+	}
+
+	if _, deleted := fw.counts(); deleted != 0 {
+		t.Errorf("draining rule must not be deleted yet")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, deleted := fw.counts(); deleted == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("drained rule has not been deleted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if states := mgr.RuleStates(); len(states) != 0 {
+		t.Errorf("unexpected rule states count: %d", len(states))
+	}
+}
+
+func TestManager_RestoreDrainingRule(t *testing.T) {
+	fw := &MockDNATFirewall{}
+	mgr := NewManager(fw, nil)
+
+	port, _ := firewall.NewPort(8080)
+	ruleTCP := firewall.ForwardRule{
+		Protocol:          firewall.ProtocolTCP,
+		DestinationPort:   *port,
+		TranslatedAddress: netip.MustParseAddr("172.16.254.1"),
+		TranslatedPort:    *port,
+		DrainTimeout:      50 * time.Millisecond,
+	}
+
+	if err := mgr.Update([]firewall.ForwardRule{ruleTCP}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := mgr.Update(nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := mgr.Update([]firewall.ForwardRule{ruleTCP}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	added, deleted := fw.counts()
+	if added != 1 || deleted != 0 {
+		t.Errorf("restored rule must be reused, added: %d, deleted: %d", added, deleted)
+	}
+
+	if rules := mgr.Rules(); len(rules) != 1 {
+		t.Errorf("unexpected rules count: %d", len(rules))
+	}
+
+	if err := mgr.Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, deleted := fw.counts(); deleted != 1 {
+		t.Errorf("unexpected deleted count: %d", deleted)
+	}
+}
+
+func TestManager_CloseDrainingRule(t *testing.T) {
+	fw := &MockDNATFirewall{}
+	mgr := NewManager(fw, nil)
+
+	port, _ := firewall.NewPort(8080)
+	ruleTCP := firewall.ForwardRule{
+		Protocol:          firewall.ProtocolTCP,
+		DestinationPort:   *port,
+		TranslatedAddress: netip.MustParseAddr("172.16.254.1"),
+		TranslatedPort:    *port,
+		DrainTimeout:      time.Hour,
+	}
+
+	if err := mgr.Update([]firewall.ForwardRule{ruleTCP}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := mgr.Update(nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := mgr.Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if _, deleted := fw.counts(); deleted != 1 {
+		t.Errorf("draining rule must be deleted on close, deleted: %d", deleted)
+	}
+	if states := mgr.RuleStates(); len(states) != 0 {
+		t.Errorf("unexpected rule states count: %d", len(states))
+	}
+}
@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// startInventoryReporting collects the device inventory every interval and reports it to management if it changed
+func (e *Engine) startInventoryReporting() {
+	if e.inventory == nil {
+		return
+	}
+
+	e.shutdownWg.Add(1)
+	go func() {
+		defer e.shutdownWg.Done()
+
+		ticker := time.NewTicker(e.inventory.Interval())
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-e.ctx.Done():
+				return
+			case <-ticker.C:
+				// collecting may take a while, so it is done before taking the lock
+				if !e.inventory.Refresh(e.ctx) {
+					continue
+				}
+				e.reportInventory()
+			}
+		}
+	}()
+}
+
+func (e *Engine) reportInventory() {
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+
+	// running from the cached network map, the inventory is sent with the next Sync
+	if e.offlineCatalogue || e.ctx.Err() != nil {
+		return
+	}
+	if err := e.syncMeta(); err != nil {
+		log.Warnf("failed to report the device inventory to management: %v", err)
+	}
+}
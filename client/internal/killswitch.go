@@ -0,0 +1,254 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	firewallManager "github.com/netbirdio/netbird/client/firewall/manager"
+)
+
+const (
+	killSwitchResolveTimeout = 5 * time.Second
+
+	dnsServerPort    = 53
+	dhcpv4ServerPort = 67
+	dhcpv6ServerPort = 547
+)
+
+// KillSwitchState keeps the kill switch rules and the resolved endpoint addresses across restarts.
+// It deliberately doesn't implement Cleanup, the rules have to stay installed while the client is down.
+type KillSwitchState struct {
+	Rules []firewallManager.KillSwitchRule `json:"rules"`
+	// Addresses caches the resolved control plane endpoints, they are used if DNS can't be reached.
+	Addresses map[string][]netip.Addr `json:"addresses"`
+	// Resolvers caches the system resolvers, they are used if none are found, e.g. while the host DNS points at
+	// NetBird after an unclean shutdown.
+	Resolvers []netip.Addr `json:"resolvers"`
+}
+
+func (s *KillSwitchState) Name() string {
+	return "kill_switch_state"
+}
+
+// updateKillSwitch installs or removes the kill switch according to the engine config.
+// The exceptions are rebuilt from the current management, signal, relay, STUN and TURN endpoints and the system
+// resolvers, so endpoints missing from the address cache can still be resolved while the switch is active.
+func (e *Engine) updateKillSwitch() {
+	ks, ok := e.firewall.(firewallManager.KillSwitch)
+	if !ok {
+		if e.config.KillSwitch {
+			log.Warnf("kill switch is not supported by the firewall manager")
+		}
+		return
+	}
+
+	state := &KillSwitchState{}
+	e.stateManager.RegisterState(state)
+	if err := e.stateManager.LoadState(state); err != nil {
+		log.Warnf("failed to load kill switch state: %v", err)
+	}
+	if existing, ok := e.stateManager.GetState(state).(*KillSwitchState); ok && existing != nil {
+		state = existing
+	}
+
+	if !e.config.KillSwitch {
+		if e.stateManager.GetState(state) == nil {
+			return
+		}
+		if err := ks.DisableKillSwitch(); err != nil {
+			log.Errorf("failed to disable kill switch: %v", err)
+			return
+		}
+		if err := e.stateManager.DeleteState(state); err != nil {
+			log.Errorf("failed to delete kill switch state: %v", err)
+		}
+		log.Infof("kill switch disabled")
+		return
+	}
+
+	addresses := e.resolveKillSwitchEndpoints(state.Addresses)
+	resolvers := e.killSwitchResolvers(state.Resolvers)
+	rules := e.killSwitchRules(addresses, resolvers)
+
+	if err := ks.EnableKillSwitch(rules); err != nil {
+		log.Errorf("failed to enable kill switch: %v", err)
+		return
+	}
+
+	if err := e.stateManager.UpdateState(&KillSwitchState{Rules: rules, Addresses: addresses, Resolvers: resolvers}); err != nil {
+		log.Errorf("failed to update kill switch state: %v", err)
+	}
+}
+
+// killSwitchEndpoints returns the host:port of the management, signal, relay, STUN and TURN servers
+func (e *Engine) killSwitchEndpoints() []string {
+	urls := []string{
+		e.statusRecorder.GetManagementState().URL,
+		e.statusRecorder.GetSignalState().URL,
+	}
+	if e.relayManager != nil {
+		urls = append(urls, e.relayManager.ServerURLs()...)
+	}
+	// the relay candidates of ICE are allocated outside the tunnel
+	for _, uri := range append(slices.Clone(e.STUNs), e.TURNs...) {
+		urls = append(urls, net.JoinHostPort(uri.Host, strconv.Itoa(uri.Port)))
+	}
+
+	var endpoints []string
+	for _, u := range urls {
+		if u == "" {
+			continue
+		}
+		endpoint, err := parseKillSwitchEndpoint(u)
+		if err != nil {
+			log.Warnf("skipping kill switch exception for %s: %v", u, err)
+			continue
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints
+}
+
+// resolveKillSwitchEndpoints resolves the endpoints, falling back to the cached addresses.
+// With the kill switch active the resolver might not be reachable, so the cache is what keeps the
+// control plane connections working across restarts.
+func (e *Engine) resolveKillSwitchEndpoints(cached map[string][]netip.Addr) map[string][]netip.Addr {
+	addresses := make(map[string][]netip.Addr)
+	for _, endpoint := range e.killSwitchEndpoints() {
+		host, _, err := net.SplitHostPort(endpoint)
+		if err != nil {
+			continue
+		}
+
+		if addr, err := netip.ParseAddr(host); err == nil {
+			addresses[endpoint] = []netip.Addr{addr.Unmap()}
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(e.ctx, killSwitchResolveTimeout)
+		ips, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+		cancel()
+		if err != nil || len(ips) == 0 {
+			if prev, ok := cached[endpoint]; ok {
+				log.Debugf("using cached addresses for kill switch exception %s: %v", endpoint, err)
+				addresses[endpoint] = prev
+				continue
+			}
+			log.Warnf("failed to resolve %s for the kill switch, the endpoint won't be reachable: %v", endpoint, err)
+			continue
+		}
+
+		for _, ip := range ips {
+			addresses[endpoint] = append(addresses[endpoint], ip.Unmap())
+		}
+	}
+	return addresses
+}
+
+// killSwitchResolvers returns the system resolvers, including the ones the host used before its DNS was pointed at
+// NetBird, falling back to the cached ones. Resolvers on loopback aren't affected by the kill switch.
+func (e *Engine) killSwitchResolvers(cached []netip.Addr) []netip.Addr {
+	candidates := systemResolvers()
+	if dnsServer, ok := e.dnsServer.(interface{ OriginalNameservers() []netip.Addr }); ok {
+		candidates = append(candidates, dnsServer.OriginalNameservers()...)
+	}
+
+	var resolvers []netip.Addr
+	for _, addr := range candidates {
+		addr = addr.Unmap()
+		if !addr.IsValid() || addr.IsLoopback() || addr.IsUnspecified() || slices.Contains(resolvers, addr) {
+			continue
+		}
+		resolvers = append(resolvers, addr)
+	}
+
+	if len(resolvers) == 0 && len(cached) > 0 {
+		log.Debugf("no system resolvers found, using the cached ones for the kill switch")
+		return cached
+	}
+	return resolvers
+}
+
+// killSwitchRules returns the egress traffic that is allowed outside the tunnel:
+// WireGuard, DHCP, DNS to the system resolvers and the control plane endpoints
+func (e *Engine) killSwitchRules(addresses map[string][]netip.Addr, resolvers []netip.Addr) []firewallManager.KillSwitchRule {
+	rules := []firewallManager.KillSwitchRule{
+		{Protocol: firewallManager.ProtocolUDP, SrcPort: uint16(e.config.WgPort)},
+		{Protocol: firewallManager.ProtocolUDP, DstPort: dhcpv4ServerPort},
+		{Protocol: firewallManager.ProtocolUDP, DstPort: dhcpv6ServerPort},
+	}
+
+	for _, addr := range resolvers {
+		for _, proto := range []firewallManager.Protocol{firewallManager.ProtocolUDP, firewallManager.ProtocolTCP} {
+			rules = append(rules, firewallManager.KillSwitchRule{
+				Destination: netip.PrefixFrom(addr, addr.BitLen()),
+				Protocol:    proto,
+				DstPort:     dnsServerPort,
+			})
+		}
+	}
+
+	for endpoint, addrs := range addresses {
+		_, portStr, err := net.SplitHostPort(endpoint)
+		if err != nil {
+			continue
+		}
+		port, err := strconv.ParseUint(portStr, 10, 16)
+		if err != nil {
+			continue
+		}
+
+		for _, addr := range addrs {
+			// relays might be served over QUIC, so udp is allowed along with tcp
+			for _, proto := range []firewallManager.Protocol{firewallManager.ProtocolTCP, firewallManager.ProtocolUDP} {
+				rules = append(rules, firewallManager.KillSwitchRule{
+					Destination: netip.PrefixFrom(addr, addr.BitLen()),
+					Protocol:    proto,
+					DstPort:     uint16(port),
+				})
+			}
+		}
+	}
+	return rules
+}
+
+// parseKillSwitchEndpoint converts a server URL or host:port into host:port, filling in the default port of the scheme
+func parseKillSwitchEndpoint(rawURL string) (string, error) {
+	if !strings.Contains(rawURL, "://") {
+		if _, _, err := net.SplitHostPort(rawURL); err != nil {
+			return "", fmt.Errorf("parse address: %w", err)
+		}
+		return rawURL, nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("parse url: %w", err)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("missing host")
+	}
+
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "https", "rels", "wss":
+			port = "443"
+		case "http", "rel", "ws":
+			port = "80"
+		default:
+			return "", fmt.Errorf("unknown scheme %q", u.Scheme)
+		}
+	}
+
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
@@ -0,0 +1,53 @@
+//go:build !windows
+
+package internal
+
+import (
+	"bufio"
+	"net/netip"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// resolvConfPaths are the resolver configurations read for the system resolvers. The one of systemd-resolved lists
+// the upstream servers behind its local stub.
+var resolvConfPaths = []string{"/etc/resolv.conf", "/run/systemd/resolve/resolv.conf"}
+
+// systemResolvers returns the nameservers configured on the host
+func systemResolvers() []netip.Addr {
+	var servers []netip.Addr
+	for _, path := range resolvConfPaths {
+		servers = append(servers, resolvConfNameservers(path)...)
+	}
+	return servers
+}
+
+func resolvConfNameservers(path string) []netip.Addr {
+	file, err := os.Open(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Debugf("failed to open %s: %v", path, err)
+		}
+		return nil
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Debugf("failed to close %s: %v", path, err)
+		}
+	}()
+
+	var servers []netip.Addr
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "nameserver" {
+			continue
+		}
+		if addr, err := netip.ParseAddr(fields[1]); err == nil {
+			servers = append(servers, addr)
+		}
+	}
+	return servers
+}
@@ -0,0 +1,38 @@
+//go:build !windows
+
+package internal
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolvConfNameservers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resolv.conf")
+	content := "# generated\nnameserver 192.0.2.53\nnameserver 2001:db8::53\nsearch netbird.cloud\nnameserver invalid\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	assert.Equal(t, []netip.Addr{netip.MustParseAddr("192.0.2.53"), netip.MustParseAddr("2001:db8::53")}, resolvConfNameservers(path))
+	assert.Empty(t, resolvConfNameservers(filepath.Join(t.TempDir(), "missing")))
+}
+
+func TestKillSwitchResolvers_Cached(t *testing.T) {
+	orig := resolvConfPaths
+	t.Cleanup(func() { resolvConfPaths = orig })
+
+	path := filepath.Join(t.TempDir(), "resolv.conf")
+	require.NoError(t, os.WriteFile(path, []byte("nameserver 127.0.0.53\n"), 0o600))
+	resolvConfPaths = []string{path}
+
+	e := &Engine{}
+	cached := []netip.Addr{netip.MustParseAddr("192.0.2.53")}
+	assert.Equal(t, cached, e.killSwitchResolvers(cached), "loopback resolvers are left out")
+
+	require.NoError(t, os.WriteFile(path, []byte("nameserver 198.51.100.53\nnameserver 198.51.100.53\n"), 0o600))
+	assert.Equal(t, []netip.Addr{netip.MustParseAddr("198.51.100.53")}, e.killSwitchResolvers(cached))
+}
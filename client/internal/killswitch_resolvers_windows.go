@@ -0,0 +1,42 @@
+package internal
+
+import (
+	"errors"
+	"net/netip"
+	"unsafe"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/windows"
+)
+
+// systemResolvers returns the DNS servers of the network adapters that are up
+func systemResolvers() []netip.Addr {
+	size := uint32(15 * 1024)
+	var adapters *windows.IpAdapterAddresses
+	for {
+		buf := make([]byte, size)
+		adapters = (*windows.IpAdapterAddresses)(unsafe.Pointer(&buf[0]))
+		err := windows.GetAdaptersAddresses(windows.AF_UNSPEC,
+			windows.GAA_FLAG_SKIP_UNICAST|windows.GAA_FLAG_SKIP_ANYCAST|windows.GAA_FLAG_SKIP_MULTICAST, 0, adapters, &size)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, windows.ERROR_BUFFER_OVERFLOW) {
+			log.Debugf("failed to get the adapter addresses: %v", err)
+			return nil
+		}
+	}
+
+	var servers []netip.Addr
+	for adapter := adapters; adapter != nil; adapter = adapter.Next {
+		if adapter.OperStatus != windows.IfOperStatusUp {
+			continue
+		}
+		for server := adapter.FirstDnsServerAddress; server != nil; server = server.Next {
+			if addr, ok := netip.AddrFromSlice(server.Address.IP()); ok {
+				servers = append(servers, addr.Unmap())
+			}
+		}
+	}
+	return servers
+}
@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/pion/stun/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	firewallManager "github.com/netbirdio/netbird/client/firewall/manager"
+	"github.com/netbirdio/netbird/client/internal/peer"
+)
+
+func TestParseKillSwitchEndpoint(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "https with port", input: "https://api.netbird.io:443", expected: "api.netbird.io:443"},
+		{name: "https default port", input: "https://api.netbird.io", expected: "api.netbird.io:443"},
+		{name: "http default port", input: "http://signal.local", expected: "signal.local:80"},
+		{name: "rels default port", input: "rels://relay.netbird.io", expected: "relay.netbird.io:443"},
+		{name: "rel custom port", input: "rel://10.0.0.1:8080", expected: "10.0.0.1:8080"},
+		{name: "ipv6 host", input: "rels://[2001:db8::1]", expected: "[2001:db8::1]:443"},
+		{name: "host and port", input: "signal.netbird.io:10000", expected: "signal.netbird.io:10000"},
+		{name: "missing port", input: "signal.netbird.io", wantErr: true},
+		{name: "unknown scheme", input: "ftp://files.netbird.io", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			endpoint, err := parseKillSwitchEndpoint(tc.input)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, endpoint)
+		})
+	}
+}
+
+func TestKillSwitchRules(t *testing.T) {
+	e := &Engine{config: &EngineConfig{WgPort: 51820}}
+
+	rules := e.killSwitchRules(map[string][]netip.Addr{
+		"api.netbird.io:443": {netip.MustParseAddr("192.0.2.1"), netip.MustParseAddr("2001:db8::1")},
+	}, []netip.Addr{netip.MustParseAddr("198.51.100.53")})
+
+	assert.ElementsMatch(t, []firewallManager.KillSwitchRule{
+		{Protocol: firewallManager.ProtocolUDP, SrcPort: 51820},
+		{Protocol: firewallManager.ProtocolUDP, DstPort: dhcpv4ServerPort},
+		{Protocol: firewallManager.ProtocolUDP, DstPort: dhcpv6ServerPort},
+		{Destination: netip.MustParsePrefix("198.51.100.53/32"), Protocol: firewallManager.ProtocolUDP, DstPort: dnsServerPort},
+		{Destination: netip.MustParsePrefix("198.51.100.53/32"), Protocol: firewallManager.ProtocolTCP, DstPort: dnsServerPort},
+		{Destination: netip.MustParsePrefix("192.0.2.1/32"), Protocol: firewallManager.ProtocolTCP, DstPort: 443},
+		{Destination: netip.MustParsePrefix("192.0.2.1/32"), Protocol: firewallManager.ProtocolUDP, DstPort: 443},
+		{Destination: netip.MustParsePrefix("2001:db8::1/128"), Protocol: firewallManager.ProtocolTCP, DstPort: 443},
+		{Destination: netip.MustParsePrefix("2001:db8::1/128"), Protocol: firewallManager.ProtocolUDP, DstPort: 443},
+	}, rules)
+
+	for _, rule := range rules {
+		assert.NoError(t, rule.Validate())
+	}
+}
+
+func TestKillSwitchEndpoints_StunTurn(t *testing.T) {
+	e := &Engine{
+		statusRecorder: peer.NewRecorder("https://api.netbird.io:443"),
+		STUNs:          []*stun.URI{{Scheme: stun.SchemeTypeSTUN, Host: "stun.netbird.io", Port: 3478}},
+		TURNs:          []*stun.URI{{Scheme: stun.SchemeTypeTURNS, Host: "turn.netbird.io", Port: 5349, Proto: stun.ProtoTypeTCP}},
+	}
+
+	assert.Subset(t, e.killSwitchEndpoints(), []string{"stun.netbird.io:3478", "turn.netbird.io:5349"})
+}
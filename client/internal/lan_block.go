@@ -0,0 +1,227 @@
+package internal
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	log "github.com/sirupsen/logrus"
+
+	nberrors "github.com/netbirdio/netbird/client/errors"
+	firewallManager "github.com/netbirdio/netbird/client/firewall/manager"
+	"github.com/netbirdio/netbird/client/internal/routemanager"
+)
+
+// lanBlockRefreshInterval is how often the local networks are checked for changes. The network monitor only reports
+// default route changes, a network appearing on another interface has to be found by polling.
+const lanBlockRefreshInterval = 30 * time.Second
+
+// updateLANBlock drops the routed traffic to the IPv4 and IPv6 networks of the local interfaces. The rules of the
+// networks that disappeared are removed, so the block follows the interfaces. The caller must hold syncMsgMux.
+func (e *Engine) updateLANBlock() {
+	if !e.config.BlockLANAccess || e.config.BlockInbound || e.firewall == nil {
+		// no need to set up extra deny rules if inbound is already blocked in general
+		return
+	}
+
+	var merr *multierror.Error
+
+	networks, err := getInterfacePrefixes()
+	if err != nil {
+		merr = multierror.Append(merr, fmt.Errorf("get local addresses: %w", err))
+	}
+	// the route manager reports the invalid entries
+	critical, _ := routemanager.ParseCriticalEndpoints(e.config.CriticalEndpoints)
+	networks = excludePrefixes(networks, critical)
+
+	if e.lanBlockRules == nil {
+		e.lanBlockRules = make(map[netip.Prefix]firewallManager.Rule)
+	}
+
+	for network, rule := range e.lanBlockRules {
+		if slices.Contains(networks, network) {
+			continue
+		}
+		if rule == nil {
+			delete(e.lanBlockRules, network)
+			continue
+		}
+		if err := e.firewall.DeleteRouteRule(rule); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("delete fw rule for network %s: %w", network, err))
+			continue
+		}
+		delete(e.lanBlockRules, network)
+		routesLog.Infof("unblocked route LAN access for the removed network %s", network)
+	}
+
+	var added []netip.Prefix
+	for _, network := range networks {
+		if _, ok := e.lanBlockRules[network]; ok {
+			continue
+		}
+
+		source := netip.PrefixFrom(netip.IPv4Unspecified(), 0)
+		if network.Addr().Is6() {
+			source = netip.PrefixFrom(netip.IPv6Unspecified(), 0)
+		}
+		rule, err := e.firewall.AddRouteFiltering(
+			nil,
+			[]netip.Prefix{source},
+			firewallManager.Network{Prefix: network},
+			firewallManager.ProtocolALL,
+			nil,
+			nil,
+			firewallManager.ActionDrop,
+		)
+		// a failed network is kept without a rule, it isn't retried and logged on every refresh
+		e.lanBlockRules[network] = rule
+		if err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("add fw rule for network %s: %w", network, err))
+			continue
+		}
+		added = append(added, network)
+	}
+
+	if len(added) > 0 {
+		routesLog.Infof("blocking route LAN access for networks: %v", added)
+	}
+	if merr != nil {
+		log.Warnf("encountered errors blocking IPs to block LAN access: %v", nberrors.FormatErrorOrNil(merr))
+	}
+}
+
+// startLANBlockRefresh periodically updates the LAN block to the networks of the local interfaces
+func (e *Engine) startLANBlockRefresh() {
+	if !e.config.BlockLANAccess || e.config.BlockInbound {
+		return
+	}
+
+	e.shutdownWg.Add(1)
+	go func() {
+		defer e.shutdownWg.Done()
+
+		ticker := time.NewTicker(lanBlockRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-e.ctx.Done():
+				return
+			case <-ticker.C:
+				e.syncMsgMux.Lock()
+				if e.ctx.Err() == nil {
+					e.updateLANBlock()
+				}
+				e.syncMsgMux.Unlock()
+			}
+		}
+	}()
+}
+
+// excludePrefixes splits the networks so that none of the resulting prefixes overlaps the excluded ones
+func excludePrefixes(networks []netip.Prefix, excluded []netip.Prefix) []netip.Prefix {
+	for _, exclude := range excluded {
+		var remaining []netip.Prefix
+		for _, network := range networks {
+			remaining = append(remaining, excludePrefix(network, exclude)...)
+		}
+		networks = remaining
+	}
+	return networks
+}
+
+// excludePrefix returns the prefixes covering the network without the excluded prefix. The network is halved until
+// the half containing the excluded prefix is the excluded prefix itself, the other halves are kept.
+func excludePrefix(network, exclude netip.Prefix) []netip.Prefix {
+	if !network.Overlaps(exclude) {
+		return []netip.Prefix{network}
+	}
+	if exclude.Bits() <= network.Bits() {
+		return nil
+	}
+
+	var remaining []netip.Prefix
+	for current := network; current.Bits() < exclude.Bits(); {
+		lower := netip.PrefixFrom(current.Addr(), current.Bits()+1)
+		upper := netip.PrefixFrom(setBit(current.Addr(), current.Bits()), current.Bits()+1)
+		if lower.Contains(exclude.Addr()) {
+			remaining = append(remaining, upper)
+			current = lower
+		} else {
+			remaining = append(remaining, lower)
+			current = upper
+		}
+	}
+	return remaining
+}
+
+// setBit returns the address with the bit at the position set, counted from the most significant bit
+func setBit(addr netip.Addr, bit int) netip.Addr {
+	b := addr.AsSlice()
+	b[bit/8] |= 0x80 >> (bit % 8)
+	result, _ := netip.AddrFromSlice(b)
+	return result
+}
+
+// getInterfacePrefixes returns the IPv4 and IPv6 networks of the local interfaces, without the loopback, multicast
+// and link-local ones
+func getInterfacePrefixes() ([]netip.Prefix, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("get interfaces: %w", err)
+	}
+
+	var prefixes []netip.Prefix
+	var merr *multierror.Error
+
+	for _, iface := range ifaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("get addresses for interface %s: %w", iface.Name, err))
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				merr = multierror.Append(merr, fmt.Errorf("cast address to IPNet: %v", addr))
+				continue
+			}
+			prefix, ok := lanPrefix(ipNet)
+			if !ok {
+				continue
+			}
+			if !slices.Contains(prefixes, prefix) {
+				prefixes = append(prefixes, prefix)
+			}
+		}
+	}
+
+	return prefixes, nberrors.FormatErrorOrNil(merr)
+}
+
+// lanPrefix returns the network of an interface address, false if it isn't a network to block
+func lanPrefix(ipNet *net.IPNet) (netip.Prefix, bool) {
+	addr, ok := netip.AddrFromSlice(ipNet.IP)
+	if !ok {
+		return netip.Prefix{}, false
+	}
+	addr = addr.Unmap()
+
+	ones, bits := ipNet.Mask.Size()
+	if addr.Is4() && bits == net.IPv6len*8 {
+		ones -= 96
+	}
+	prefix, err := addr.Prefix(ones)
+	if err != nil {
+		return netip.Prefix{}, false
+	}
+
+	ip := prefix.Addr()
+	if ip.IsUnspecified() || ip.IsLoopback() || ip.IsMulticast() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+		return netip.Prefix{}, false
+	}
+	return prefix, true
+}
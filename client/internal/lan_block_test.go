@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLANPrefix(t *testing.T) {
+	tests := []struct {
+		name  string
+		ipNet *net.IPNet
+		want  netip.Prefix
+		ok    bool
+	}{
+		{name: "ipv4", ipNet: &net.IPNet{IP: net.ParseIP("192.168.1.10").To4(), Mask: net.CIDRMask(24, 32)}, want: netip.MustParsePrefix("192.168.1.0/24"), ok: true},
+		{name: "ipv4 with ipv6 mask", ipNet: &net.IPNet{IP: net.ParseIP("10.1.2.3"), Mask: net.CIDRMask(112, 128)}, want: netip.MustParsePrefix("10.1.0.0/16"), ok: true},
+		{name: "ipv6 global", ipNet: &net.IPNet{IP: net.ParseIP("2001:db8:1::10"), Mask: net.CIDRMask(64, 128)}, want: netip.MustParsePrefix("2001:db8:1::/64"), ok: true},
+		{name: "ipv6 unique local", ipNet: &net.IPNet{IP: net.ParseIP("fd00:1::10"), Mask: net.CIDRMask(64, 128)}, want: netip.MustParsePrefix("fd00:1::/64"), ok: true},
+		{name: "ipv4 loopback", ipNet: &net.IPNet{IP: net.ParseIP("127.0.0.1").To4(), Mask: net.CIDRMask(8, 32)}},
+		{name: "ipv6 loopback", ipNet: &net.IPNet{IP: net.ParseIP("::1"), Mask: net.CIDRMask(128, 128)}},
+		{name: "ipv4 link-local", ipNet: &net.IPNet{IP: net.ParseIP("169.254.1.1").To4(), Mask: net.CIDRMask(16, 32)}},
+		{name: "ipv6 link-local", ipNet: &net.IPNet{IP: net.ParseIP("fe80::1"), Mask: net.CIDRMask(64, 128)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefix, ok := lanPrefix(tt.ipNet)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.want, prefix)
+			}
+		})
+	}
+}
+
+func TestExcludePrefixes(t *testing.T) {
+	p := netip.MustParsePrefix
+
+	tests := []struct {
+		name     string
+		networks []netip.Prefix
+		excluded []netip.Prefix
+		want     []netip.Prefix
+	}{
+		{
+			name:     "no overlap",
+			networks: []netip.Prefix{p("192.168.1.0/24")},
+			excluded: []netip.Prefix{p("169.254.169.254/32")},
+			want:     []netip.Prefix{p("192.168.1.0/24")},
+		},
+		{
+			name:     "host in network",
+			networks: []netip.Prefix{p("10.0.0.0/29")},
+			excluded: []netip.Prefix{p("10.0.0.2/32")},
+			want:     []netip.Prefix{p("10.0.0.4/30"), p("10.0.0.0/31"), p("10.0.0.3/32")},
+		},
+		{
+			name:     "excluded covers network",
+			networks: []netip.Prefix{p("10.0.0.0/24"), p("192.168.1.0/24")},
+			excluded: []netip.Prefix{p("10.0.0.0/8")},
+			want:     []netip.Prefix{p("192.168.1.0/24")},
+		},
+		{
+			name:     "ipv6",
+			networks: []netip.Prefix{p("fd00:ec2::/126")},
+			excluded: []netip.Prefix{p("fd00:ec2::1/128")},
+			want:     []netip.Prefix{p("fd00:ec2::2/127"), p("fd00:ec2::/128")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, excludePrefixes(tt.networks, tt.excluded))
+		})
+	}
+}
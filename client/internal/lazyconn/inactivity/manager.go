@@ -7,6 +7,7 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/netbirdio/netbird/client/iface/bind"
 	"github.com/netbirdio/netbird/client/internal/lazyconn"
 	"github.com/netbirdio/netbird/monotime"
 )
@@ -20,29 +21,41 @@ const (
 
 type WgInterface interface {
 	LastActivities() map[string]monotime.Time
+	ActivityCounters() map[string]bind.ActivityCounters
 }
 
 type Manager struct {
 	inactivePeersChan chan map[string]struct{}
 
-	iface               WgInterface
-	interestedPeers     map[string]*lazyconn.PeerConfig
-	inactivityThreshold time.Duration
+	iface           WgInterface
+	interestedPeers map[string]*lazyconn.PeerConfig
+	config          lazyconn.InactivityConfig
+
+	// trafficActivity tracks the activity of peers with traffic thresholds
+	trafficActivity map[string]*peerActivity
+}
+
+type peerActivity struct {
+	counters   bind.ActivityCounters
+	lastActive monotime.Time
 }
 
-func NewManager(iface WgInterface, configuredThreshold *time.Duration) *Manager {
-	inactivityThreshold, err := validateInactivityThreshold(configuredThreshold)
+func NewManager(iface WgInterface, config lazyconn.InactivityConfig) *Manager {
+	inactivityThreshold, err := validateInactivityThreshold(config.Threshold)
 	if err != nil {
 		inactivityThreshold = DefaultInactivityThreshold
 		log.Warnf("invalid inactivity threshold configured: %v, using default: %v", err, DefaultInactivityThreshold)
 	}
+	config.Threshold = inactivityThreshold
 
-	log.Infof("inactivity threshold configured: %v", inactivityThreshold)
+	log.Infof("inactivity threshold configured: %v, min packets per interval: %d, min bytes per interval: %d",
+		config.Threshold, config.MinPackets, config.MinBytes)
 	return &Manager{
-		inactivePeersChan:   make(chan map[string]struct{}, 1),
-		iface:               iface,
-		interestedPeers:     make(map[string]*lazyconn.PeerConfig),
-		inactivityThreshold: inactivityThreshold,
+		inactivePeersChan: make(chan map[string]struct{}, 1),
+		iface:             iface,
+		interestedPeers:   make(map[string]*lazyconn.PeerConfig),
+		config:            config,
+		trafficActivity:   make(map[string]*peerActivity),
 	}
 }
 
@@ -80,6 +93,7 @@ func (m *Manager) RemovePeer(peer string) {
 
 	pi.Log.Debugf("remove peer from inactivity manager")
 	delete(m.interestedPeers, peer)
+	delete(m.trafficActivity, peer)
 }
 
 func (m *Manager) Start(ctx context.Context) {
@@ -122,10 +136,12 @@ func (m *Manager) notifyInactivePeers(ctx context.Context, inactivePeers map[str
 
 func (m *Manager) checkStats() (map[string]struct{}, error) {
 	lastActivities := m.iface.LastActivities()
+	var counters map[string]bind.ActivityCounters
 
 	idlePeers := make(map[string]struct{})
 
 	checkTime := time.Now()
+	now := monotime.Now()
 	for peerID, peerCfg := range m.interestedPeers {
 		lastActive, ok := lastActivities[peerID]
 		if !ok {
@@ -134,8 +150,16 @@ func (m *Manager) checkStats() (map[string]struct{}, error) {
 			continue
 		}
 
+		cfg := m.peerConfig(peerCfg)
+		if cfg.MinPackets > 0 || cfg.MinBytes > 0 {
+			if counters == nil {
+				counters = m.iface.ActivityCounters()
+			}
+			lastActive = m.trackTrafficActivity(peerID, counters[peerID], cfg, now)
+		}
+
 		since := monotime.Since(lastActive)
-		if since > m.inactivityThreshold {
+		if since > cfg.Threshold {
 			peerCfg.Log.Infof("peer is inactive since time: %s", checkTime.Add(-since).String())
 			idlePeers[peerID] = struct{}{}
 		}
@@ -144,12 +168,45 @@ func (m *Manager) checkStats() (map[string]struct{}, error) {
 	return idlePeers, nil
 }
 
-func validateInactivityThreshold(configuredThreshold *time.Duration) (time.Duration, error) {
-	if configuredThreshold == nil {
+// peerConfig returns the idle detection config of the peer, with the overrides of the peer applied
+func (m *Manager) peerConfig(peerCfg *lazyconn.PeerConfig) lazyconn.InactivityConfig {
+	cfg := m.config.Override(peerCfg.Inactivity)
+	if cfg.Threshold < MinimumInactivityThreshold {
+		cfg.Threshold = m.config.Threshold
+	}
+	return cfg
+}
+
+// trackTrafficActivity returns the last time the peer exceeded the traffic thresholds within a check interval
+func (m *Manager) trackTrafficActivity(peerID string, counters bind.ActivityCounters, cfg lazyconn.InactivityConfig, now monotime.Time) monotime.Time {
+	pa, ok := m.trafficActivity[peerID]
+	if !ok {
+		// the first sample is the baseline for the next interval
+		pa = &peerActivity{counters: counters, lastActive: now}
+		m.trafficActivity[peerID] = pa
+		return pa.lastActive
+	}
+
+	packets := counters.Packets - pa.counters.Packets
+	bytes := counters.Bytes - pa.counters.Bytes
+	if counters.Packets < pa.counters.Packets || counters.Bytes < pa.counters.Bytes {
+		// the counters were reset, e.g. the peer was re-added to WireGuard
+		packets, bytes = counters.Packets, counters.Bytes
+	}
+	pa.counters = counters
+
+	if packets >= cfg.MinPackets && bytes >= cfg.MinBytes {
+		pa.lastActive = now
+	}
+	return pa.lastActive
+}
+
+func validateInactivityThreshold(configuredThreshold time.Duration) (time.Duration, error) {
+	if configuredThreshold == 0 {
 		return DefaultInactivityThreshold, nil
 	}
-	if *configuredThreshold < MinimumInactivityThreshold {
-		return 0, fmt.Errorf("configured inactivity threshold %v is too low, using %v", *configuredThreshold, MinimumInactivityThreshold)
+	if configuredThreshold < MinimumInactivityThreshold {
+		return 0, fmt.Errorf("configured inactivity threshold %v is too low, using %v", configuredThreshold, MinimumInactivityThreshold)
 	}
-	return *configuredThreshold, nil
+	return configuredThreshold, nil
 }
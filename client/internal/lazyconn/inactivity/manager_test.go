@@ -8,18 +8,24 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/netbirdio/netbird/client/iface/bind"
 	"github.com/netbirdio/netbird/client/internal/lazyconn"
 	"github.com/netbirdio/netbird/monotime"
 )
 
 type mockWgInterface struct {
 	lastActivities map[string]monotime.Time
+	counters       map[string]bind.ActivityCounters
 }
 
 func (m *mockWgInterface) LastActivities() map[string]monotime.Time {
 	return m.lastActivities
 }
 
+func (m *mockWgInterface) ActivityCounters() map[string]bind.ActivityCounters {
+	return m.counters
+}
+
 func TestPeerTriggersInactivity(t *testing.T) {
 	peerID := "peer1"
 
@@ -40,7 +46,7 @@ func TestPeerTriggersInactivity(t *testing.T) {
 		Log:       peerLog,
 	}
 
-	manager := NewManager(wgMock, nil)
+	manager := NewManager(wgMock, lazyconn.InactivityConfig{})
 	manager.AddPeer(peerCfg)
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -81,7 +87,7 @@ func TestPeerTriggersActivity(t *testing.T) {
 		Log:       peerLog,
 	}
 
-	manager := NewManager(wgMock, nil)
+	manager := NewManager(wgMock, lazyconn.InactivityConfig{})
 	manager.AddPeer(peerCfg)
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -102,6 +108,55 @@ func TestPeerTriggersActivity(t *testing.T) {
 	}
 }
 
+func TestPeerTrafficBelowThresholdIsInactive(t *testing.T) {
+	peerID := "peer1"
+
+	// the peer sends keepalives, so the last activity is always recent
+	wgMock := &mockWgInterface{
+		lastActivities: map[string]monotime.Time{peerID: monotime.Now()},
+		counters:       map[string]bind.ActivityCounters{peerID: {Packets: 100, Bytes: 10000}},
+	}
+
+	peerCfg := &lazyconn.PeerConfig{
+		PublicKey:  peerID,
+		Log:        log.WithField("peer", peerID),
+		Inactivity: &lazyconn.InactivityConfig{Threshold: 2 * time.Minute},
+	}
+
+	manager := NewManager(wgMock, lazyconn.InactivityConfig{MinPackets: 10})
+	manager.AddPeer(peerCfg)
+
+	idle, err := manager.checkStats()
+	assert.NoError(t, err)
+	assert.Empty(t, idle, "first sample is the baseline")
+
+	stale := monotime.Time(int64(monotime.Now()) - int64(3*time.Minute))
+
+	// below the packet threshold for longer than the peer threshold
+	manager.trafficActivity[peerID].lastActive = stale
+	wgMock.counters[peerID] = bind.ActivityCounters{Packets: 105, Bytes: 10500}
+	idle, err = manager.checkStats()
+	assert.NoError(t, err)
+	assert.Contains(t, idle, peerID)
+
+	// enough traffic within the interval
+	manager.trafficActivity[peerID].lastActive = stale
+	wgMock.counters[peerID] = bind.ActivityCounters{Packets: 200, Bytes: 20000}
+	idle, err = manager.checkStats()
+	assert.NoError(t, err)
+	assert.Empty(t, idle)
+}
+
+func TestInactivityConfigOverride(t *testing.T) {
+	cfg := lazyconn.InactivityConfig{Threshold: 15 * time.Minute, MinPackets: 10, MinBytes: 1000}
+
+	assert.Equal(t, cfg, cfg.Override(nil))
+	assert.Equal(t,
+		lazyconn.InactivityConfig{Threshold: 5 * time.Minute, MinPackets: 10, MinBytes: 500},
+		cfg.Override(&lazyconn.InactivityConfig{Threshold: 5 * time.Minute, MinBytes: 500}),
+	)
+}
+
 // fakeTickerMock implements Ticker interface for testing
 type fakeTickerMock struct {
 	CChan chan time.Time
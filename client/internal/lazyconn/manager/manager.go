@@ -28,8 +28,16 @@ type managedPeer struct {
 	expectedWatcher watcherType
 }
 
+// ActivationBudget decides whether the resources allow a new peer connection
+type ActivationBudget interface {
+	AllowActivation() error
+}
+
 type Config struct {
-	InactivityThreshold *time.Duration
+	// Inactivity configures the idle detection, peers can override it
+	Inactivity lazyconn.InactivityConfig
+	// Budget denies activations over the resource limits, optional
+	Budget ActivationBudget
 }
 
 // Manager manages lazy connections
@@ -43,6 +51,7 @@ type Manager struct {
 	engineCtx           context.Context
 	peerStore           *peerstore.Store
 	inactivityThreshold time.Duration
+	budget              ActivationBudget
 
 	managedPeers         map[string]*lazyconn.PeerConfig
 	managedPeersByConnID map[peerid.ConnID]*managedPeer
@@ -68,6 +77,7 @@ func NewManager(config Config, engineCtx context.Context, peerStore *peerstore.S
 		engineCtx:            engineCtx,
 		peerStore:            peerStore,
 		inactivityThreshold:  inactivity.DefaultInactivityThreshold,
+		budget:               config.Budget,
 		managedPeers:         make(map[string]*lazyconn.PeerConfig),
 		managedPeersByConnID: make(map[peerid.ConnID]*managedPeer),
 		excludes:             make(map[string]lazyconn.PeerConfig),
@@ -77,7 +87,7 @@ func NewManager(config Config, engineCtx context.Context, peerStore *peerstore.S
 	}
 
 	if wgIface.IsUserspaceBind() {
-		m.inactivityManager = inactivity.NewManager(wgIface, config.InactivityThreshold)
+		m.inactivityManager = inactivity.NewManager(wgIface, config.Inactivity)
 	} else {
 		log.Warnf("inactivity manager not supported for kernel mode, wait for remote peer to close the connection")
 	}
@@ -317,12 +327,19 @@ func (m *Manager) getPeerForActivation(peerID string) (*lazyconn.PeerConfig, *ma
 }
 
 // activateSinglePeer activates a single peer
-// return true if the peer was activated, false if it was already active
+// return true if the peer was activated, false if it was already active or the resource budget denied it
 func (m *Manager) activateSinglePeer(cfg *lazyconn.PeerConfig, mp *managedPeer) bool {
 	if mp.expectedWatcher == watcherInactivity {
 		return false
 	}
 
+	if m.budget != nil {
+		if err := m.budget.AllowActivation(); err != nil {
+			cfg.Log.Warnf("deny activation of the lazy connection: %v", err)
+			return false
+		}
+	}
+
 	mp.expectedWatcher = watcherInactivity
 	m.activityManager.RemovePeer(cfg.Log, cfg.PeerConnID)
 	m.inactivityManager.AddPeer(cfg)
@@ -532,6 +549,10 @@ func (m *Manager) onPeerActivity(peerConnID peerid.ConnID) {
 	mp.peerCfg.Log.Infof("detected peer activity")
 
 	if !m.activateSinglePeer(mp.peerCfg, mp) {
+		// the activation was denied, the activity listener is gone with the detected traffic
+		if err := m.activityManager.MonitorPeerActivity(*mp.peerCfg); err != nil {
+			mp.peerCfg.Log.Errorf("failed to create activity monitor: %v", err)
+		}
 		return
 	}
 
@@ -567,6 +588,13 @@ func (m *Manager) onPeerInactivityTimedOut(peerIDs map[string]struct{}) {
 			continue
 		}
 
+		if conn, ok := m.peerStore.PeerConn(mp.peerCfg.PublicKey); ok && !conn.SupportsLazyConnection() {
+			// the remote peer announced that it can't be connected lazily, it would reconnect right away
+			mp.peerCfg.Log.Infof("remote peer doesn't support lazy connections, keep the connection open")
+			m.inactivityManager.RemovePeer(mp.peerCfg.PublicKey)
+			continue
+		}
+
 		mp.peerCfg.Log.Infof("connection timed out")
 
 		// this is blocking operation, potentially can be optimized
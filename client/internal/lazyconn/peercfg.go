@@ -2,6 +2,7 @@ package lazyconn
 
 import (
 	"net/netip"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -13,4 +14,34 @@ type PeerConfig struct {
 	AllowedIPs []netip.Prefix
 	PeerConnID id.ConnID
 	Log        *log.Entry
+	// Inactivity overrides the idle detection of the inactivity manager for the peer
+	Inactivity *InactivityConfig
+}
+
+// InactivityConfig configures when a lazy connection is idle, so it is closed and the remote peer is sent GO_IDLE.
+// Zero values keep the defaults.
+type InactivityConfig struct {
+	// Threshold is how long the connection has to be idle before it is closed
+	Threshold time.Duration
+	// MinPackets and MinBytes are the transport packets and bytes received per check interval that count as activity.
+	// Less traffic, e.g. the keepalives of an application, counts as idle. Zero counts any traffic.
+	MinPackets uint64
+	MinBytes   uint64
+}
+
+// Override returns the config with the non-zero fields of the override applied
+func (c InactivityConfig) Override(override *InactivityConfig) InactivityConfig {
+	if override == nil {
+		return c
+	}
+	if override.Threshold > 0 {
+		c.Threshold = override.Threshold
+	}
+	if override.MinPackets > 0 {
+		c.MinPackets = override.MinPackets
+	}
+	if override.MinBytes > 0 {
+		c.MinBytes = override.MinBytes
+	}
+	return c
 }
@@ -0,0 +1,56 @@
+package logging
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+// Install wraps the formatter and the hooks of the logger with the level filter. It has to be called again after
+// the formatter or the hooks were replaced, e.g. by util.InitLog.
+func Install(logger *log.Logger) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	install(logger)
+	installed = true
+}
+
+func install(logger *log.Logger) {
+	if _, ok := logger.Formatter.(*filterFormatter); !ok {
+		logger.Formatter = &filterFormatter{Formatter: logger.Formatter}
+	}
+
+	hooks := make(log.LevelHooks)
+	for level, levelHooks := range logger.Hooks {
+		for _, hook := range levelHooks {
+			if _, ok := hook.(*filterHook); !ok {
+				hook = &filterHook{Hook: hook}
+			}
+			hooks[level] = append(hooks[level], hook)
+		}
+	}
+	logger.ReplaceHooks(hooks)
+}
+
+// filterFormatter drops the entries below the level of their subsystem, nothing is written for them
+type filterFormatter struct {
+	log.Formatter
+}
+
+func (f *filterFormatter) Format(entry *log.Entry) ([]byte, error) {
+	if !enabled(entry) {
+		return nil, nil
+	}
+	return f.Formatter.Format(entry)
+}
+
+// filterHook keeps the hooks, like the syslog one, from seeing the dropped entries
+type filterHook struct {
+	log.Hook
+}
+
+func (h *filterHook) Fire(entry *log.Entry) error {
+	if !enabled(entry) {
+		return nil
+	}
+	return h.Hook.Fire(entry)
+}
@@ -0,0 +1,189 @@
+// Package logging adds per subsystem log levels on top of the standard logrus logger. The level of a subsystem can
+// be raised or lowered at runtime without touching the rest of the log output.
+//
+// Entries are attributed to a subsystem by the subsystem field set by Logger, or else by the package they are logged
+// from, so the existing logrus calls in the subsystem packages are covered as well.
+package logging
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SubsystemKey is the entry field holding the subsystem
+const SubsystemKey = "subsystem"
+
+// Subsystem is a part of the client with its own log level
+type Subsystem string
+
+const (
+	DNS      Subsystem = "dns"
+	Routes   Subsystem = "routes"
+	Firewall Subsystem = "firewall"
+	ICE      Subsystem = "ice"
+	Relay    Subsystem = "relay"
+)
+
+// Subsystems are all subsystems with their own log level
+var Subsystems = []Subsystem{DNS, Routes, Firewall, ICE, Relay}
+
+// sources maps source paths to subsystems, the first match wins
+var sources = []struct {
+	path      string
+	subsystem Subsystem
+}{
+	{"/client/internal/dns/", DNS},
+	{"/client/internal/dnsfwd/", DNS},
+	{"/client/internal/routemanager/", Routes},
+	{"/client/internal/routeselector/", Routes},
+	{"/client/firewall/", Firewall},
+	{"/client/internal/acl/", Firewall},
+	{"/client/internal/peer/ice/", ICE},
+	{"/client/internal/peer/worker_ice.go", ICE},
+	{"/client/iface/udpmux/", ICE},
+	{"/client/internal/peer/worker_relay.go", Relay},
+	{"/client/internal/relay/", Relay},
+	{"/shared/relay/", Relay},
+}
+
+// ParseSubsystem returns the subsystem with the given name
+func ParseSubsystem(name string) (Subsystem, error) {
+	for _, s := range Subsystems {
+		if strings.EqualFold(string(s), name) {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("unknown subsystem %q", name)
+}
+
+// Logger returns a logger for the subsystem
+func Logger(s Subsystem) *log.Entry {
+	return log.WithField(SubsystemKey, s)
+}
+
+type levels struct {
+	global     log.Level
+	subsystems map[Subsystem]log.Level
+}
+
+var (
+	mu        sync.Mutex
+	installed bool
+	current   atomic.Pointer[levels]
+)
+
+// SetLevel sets the level of the entries not overridden by a subsystem level
+func SetLevel(level log.Level) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	l := load()
+	update(&levels{global: level, subsystems: l.subsystems})
+}
+
+// Level returns the level of the entries not overridden by a subsystem level
+func Level() log.Level {
+	return load().global
+}
+
+// SetSubsystemLevel overrides the level of the subsystem
+func SetSubsystemLevel(s Subsystem, level log.Level) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	l := load()
+	subsystems := maps.Clone(l.subsystems)
+	if subsystems == nil {
+		subsystems = make(map[Subsystem]log.Level)
+	}
+	subsystems[s] = level
+	update(&levels{global: l.global, subsystems: subsystems})
+}
+
+// ResetSubsystemLevel removes the level override of the subsystem, it follows the global level again
+func ResetSubsystemLevel(s Subsystem) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	l := load()
+	subsystems := maps.Clone(l.subsystems)
+	delete(subsystems, s)
+	update(&levels{global: l.global, subsystems: subsystems})
+}
+
+// SubsystemLevel returns the level of the subsystem, the global level if it isn't overridden
+func SubsystemLevel(s Subsystem) log.Level {
+	l := load()
+	if level, ok := l.subsystems[s]; ok {
+		return level
+	}
+	return l.global
+}
+
+// SubsystemLevels returns the subsystem level overrides
+func SubsystemLevels() map[Subsystem]log.Level {
+	return maps.Clone(load().subsystems)
+}
+
+func load() *levels {
+	if l := current.Load(); l != nil {
+		return l
+	}
+	return &levels{global: log.GetLevel()}
+}
+
+// update stores the levels and sets the logger to the most verbose one, the filter drops the entries the other
+// levels don't allow. It must be called with the lock held.
+func update(l *levels) {
+	current.Store(l)
+
+	logger := log.StandardLogger()
+	if !installed {
+		install(logger)
+		installed = true
+	}
+
+	maxLevel := l.global
+	for _, level := range l.subsystems {
+		maxLevel = max(maxLevel, level)
+	}
+	logger.SetLevel(maxLevel)
+}
+
+func enabled(entry *log.Entry) bool {
+	l := current.Load()
+	if l == nil {
+		return true
+	}
+
+	level := l.global
+	if s, ok := subsystemOf(entry); ok {
+		if subsystemLevel, ok := l.subsystems[s]; ok {
+			level = subsystemLevel
+		}
+	}
+	return entry.Level <= level
+}
+
+func subsystemOf(entry *log.Entry) (Subsystem, bool) {
+	if s, ok := entry.Data[SubsystemKey].(Subsystem); ok {
+		return s, true
+	}
+
+	if entry.Caller == nil {
+		return "", false
+	}
+
+	file := entry.Caller.File
+	for _, src := range sources {
+		if strings.Contains(file, src.path) {
+			return src.subsystem, true
+		}
+	}
+	return "", false
+}
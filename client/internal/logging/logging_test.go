@@ -0,0 +1,141 @@
+package logging
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupLogger(t *testing.T, level log.Level) *bytes.Buffer {
+	t.Helper()
+
+	logger := log.StandardLogger()
+	out, formatter, hooks, oldLevel, reportCaller := logger.Out, logger.Formatter, logger.Hooks, logger.GetLevel(), logger.ReportCaller
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		current.Store(nil)
+		installed = false
+		logger.SetOutput(out)
+		logger.SetFormatter(formatter)
+		logger.ReplaceHooks(hooks)
+		logger.SetLevel(oldLevel)
+		logger.SetReportCaller(reportCaller)
+	})
+
+	buf := &bytes.Buffer{}
+	logger.SetOutput(buf)
+	logger.SetFormatter(&log.TextFormatter{DisableTimestamp: true})
+	logger.ReplaceHooks(make(log.LevelHooks))
+	SetLevel(level)
+	return buf
+}
+
+func TestSubsystemLevel_MoreVerbose(t *testing.T) {
+	buf := setupLogger(t, log.InfoLevel)
+	SetSubsystemLevel(DNS, log.DebugLevel)
+
+	assert.Equal(t, log.DebugLevel, log.GetLevel(), "the logger should allow the most verbose level")
+	assert.Equal(t, log.InfoLevel, Level())
+
+	Logger(DNS).Debug("dns debug")
+	Logger(Routes).Debug("routes debug")
+	log.Debug("global debug")
+	log.Info("global info")
+
+	assert.Contains(t, buf.String(), "dns debug")
+	assert.NotContains(t, buf.String(), "routes debug")
+	assert.NotContains(t, buf.String(), "global debug")
+	assert.Contains(t, buf.String(), "global info")
+}
+
+func TestSubsystemLevel_LessVerbose(t *testing.T) {
+	buf := setupLogger(t, log.DebugLevel)
+	SetSubsystemLevel(ICE, log.WarnLevel)
+
+	Logger(ICE).Info("ice info")
+	Logger(ICE).Warn("ice warning")
+	Logger(Relay).Info("relay info")
+
+	assert.NotContains(t, buf.String(), "ice info")
+	assert.Contains(t, buf.String(), "ice warning")
+	assert.Contains(t, buf.String(), "relay info")
+}
+
+func TestResetSubsystemLevel(t *testing.T) {
+	buf := setupLogger(t, log.InfoLevel)
+	SetSubsystemLevel(Firewall, log.TraceLevel)
+	require.Equal(t, map[Subsystem]log.Level{Firewall: log.TraceLevel}, SubsystemLevels())
+
+	ResetSubsystemLevel(Firewall)
+	assert.Empty(t, SubsystemLevels())
+	assert.Equal(t, log.InfoLevel, SubsystemLevel(Firewall))
+	assert.Equal(t, log.InfoLevel, log.GetLevel())
+
+	Logger(Firewall).Debug("firewall debug")
+	assert.Empty(t, buf.String())
+}
+
+func TestHooksAreFiltered(t *testing.T) {
+	setupLogger(t, log.InfoLevel)
+
+	hook := &countingHook{}
+	log.AddHook(hook)
+	Install(log.StandardLogger())
+
+	SetSubsystemLevel(DNS, log.DebugLevel)
+	log.Debug("global debug")
+	Logger(DNS).Debug("dns debug")
+
+	assert.Equal(t, 1, hook.fired)
+}
+
+func TestSubsystemFromSource(t *testing.T) {
+	tests := []struct {
+		file string
+		want Subsystem
+		ok   bool
+	}{
+		{file: "/src/netbird/client/internal/dns/server.go", want: DNS, ok: true},
+		{file: "/src/netbird/client/internal/routemanager/client/client.go", want: Routes, ok: true},
+		{file: "/src/netbird/client/firewall/nftables/manager_linux.go", want: Firewall, ok: true},
+		{file: "/src/netbird/client/internal/peer/worker_ice.go", want: ICE, ok: true},
+		{file: "/src/netbird/shared/relay/client/client.go", want: Relay, ok: true},
+		{file: "/src/netbird/client/internal/engine.go", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			s, ok := subsystemOf(&log.Entry{Data: log.Fields{}, Caller: &runtime.Frame{File: tt.file}})
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, s)
+		})
+	}
+}
+
+func TestParseSubsystem(t *testing.T) {
+	s, err := ParseSubsystem("DNS")
+	require.NoError(t, err)
+	assert.Equal(t, DNS, s)
+
+	_, err = ParseSubsystem("unknown")
+	assert.Error(t, err)
+}
+
+type countingHook struct {
+	fired int
+}
+
+func (h *countingHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (h *countingHook) Fire(*log.Entry) error {
+	h.fired++
+	return nil
+}
@@ -11,7 +11,6 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/netbirdio/netbird/client/internal/profilemanager"
-	"github.com/netbirdio/netbird/client/ssh"
 	"github.com/netbirdio/netbird/client/system"
 	mgm "github.com/netbirdio/netbird/shared/management/client"
 	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
@@ -19,6 +18,15 @@ import (
 
 // IsLoginRequired check that the server is support SSO or not
 func IsLoginRequired(ctx context.Context, config *profilemanager.Config) (bool, error) {
+	// the simulated management accepts any peer
+	if config.ManagementSimulationFile != "" {
+		return false, nil
+	}
+
+	if err := applyProxy(config); err != nil {
+		return false, err
+	}
+
 	mgmURL := config.ManagementURL
 	mgmClient, err := getMgmClient(ctx, config.PrivateKey, mgmURL)
 	if err != nil {
@@ -35,7 +43,7 @@ func IsLoginRequired(ctx context.Context, config *profilemanager.Config) (bool,
 	}()
 	log.Debugf("connected to the Management service %s", mgmURL.String())
 
-	pubSSHKey, err := ssh.GeneratePublicKey([]byte(config.SSHKey))
+	pubSSHKey, err := publicSSHKey(ctx, config)
 	if err != nil {
 		return false, err
 	}
@@ -49,6 +57,15 @@ func IsLoginRequired(ctx context.Context, config *profilemanager.Config) (bool,
 
 // Login or register the client
 func Login(ctx context.Context, config *profilemanager.Config, setupKey string, jwtToken string) error {
+	if config.ManagementSimulationFile != "" {
+		log.Infof("skipping the login, the Management service is simulated with %s", config.ManagementSimulationFile)
+		return nil
+	}
+
+	if err := applyProxy(config); err != nil {
+		return err
+	}
+
 	mgmClient, err := getMgmClient(ctx, config.PrivateKey, config.ManagementURL)
 	if err != nil {
 		return err
@@ -64,7 +81,7 @@ func Login(ctx context.Context, config *profilemanager.Config, setupKey string,
 	}()
 	log.Debugf("connected to the Management service %s", config.ManagementURL.String())
 
-	pubSSHKey, err := ssh.GeneratePublicKey([]byte(config.SSHKey))
+	pubSSHKey, err := publicSSHKey(ctx, config)
 	if err != nil {
 		return err
 	}
@@ -3,10 +3,15 @@ package internal
 import (
 	"errors"
 	"fmt"
+	"math"
 	"net"
 	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
 
 	firewallManager "github.com/netbirdio/netbird/client/firewall/manager"
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
 	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
 )
 
@@ -56,3 +61,127 @@ func convertToIP(rawIP []byte) (netip.Addr, error) {
 
 	return netip.AddrFrom16([16]byte(rawIP)), nil
 }
+
+// convertTranslatedAddresses converts the additional translated addresses of a forwarding rule, the duplicates of
+// the translated address and of each other are dropped
+func convertTranslatedAddresses(translated netip.Addr, rawIPs [][]byte) ([]netip.Addr, error) {
+	if len(rawIPs) == 0 {
+		return nil, nil
+	}
+
+	addrs := make([]netip.Addr, 0, len(rawIPs))
+	for _, rawIP := range rawIPs {
+		addr, err := convertToIP(rawIP)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert translated address '%s': %w", net.IP(rawIP), err)
+		}
+		if addr == translated || slices.Contains(addrs, addr) {
+			continue
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
+// convertForwardingHealthCheck converts the health check of a forwarding rule, a nil check disables it
+func convertForwardingHealthCheck(check *mgmProto.ForwardingHealthCheck) firewallManager.ForwardHealthCheck {
+	if check == nil {
+		return firewallManager.ForwardHealthCheck{}
+	}
+
+	healthCheck := firewallManager.ForwardHealthCheck{
+		Type:               firewallManager.HealthCheckTCP,
+		Path:               check.GetPath(),
+		Interval:           check.GetInterval().AsDuration(),
+		Timeout:            check.GetTimeout().AsDuration(),
+		UnhealthyThreshold: int(check.GetUnhealthyThreshold()),
+	}
+	if check.GetType() == mgmProto.ForwardingHealthCheck_HTTP {
+		healthCheck.Type = firewallManager.HealthCheckHTTP
+	}
+	if healthCheck.Path != "" && !strings.HasPrefix(healthCheck.Path, "/") {
+		healthCheck.Path = "/" + healthCheck.Path
+	}
+	return healthCheck
+}
+
+// parseFlowPortRange parses a protocol with an optional port or port range, e.g. "udp/5353", "udp/137-138" or "icmp"
+func parseFlowPortRange(entry string) (nftypes.PortRange, error) {
+	protoName, ports, hasPorts := strings.Cut(strings.ToLower(strings.TrimSpace(entry)), "/")
+
+	portRange := nftypes.PortRange{Start: 0, End: math.MaxUint16}
+	switch protoName {
+	case "tcp":
+		portRange.Protocol = nftypes.TCP
+	case "udp":
+		portRange.Protocol = nftypes.UDP
+	case "sctp":
+		portRange.Protocol = nftypes.SCTP
+	case "icmp":
+		if hasPorts {
+			return nftypes.PortRange{}, errors.New("icmp has no ports")
+		}
+		portRange.Protocol = nftypes.ICMP
+	default:
+		return nftypes.PortRange{}, fmt.Errorf("unsupported protocol %q", protoName)
+	}
+
+	if !hasPorts {
+		return portRange, nil
+	}
+
+	startPort, endPort, isRange := strings.Cut(ports, "-")
+	start, err := strconv.ParseUint(startPort, 10, 16)
+	if err != nil {
+		return nftypes.PortRange{}, fmt.Errorf("invalid port %q", startPort)
+	}
+	end := start
+	if isRange {
+		if end, err = strconv.ParseUint(endPort, 10, 16); err != nil {
+			return nftypes.PortRange{}, fmt.Errorf("invalid port %q", endPort)
+		}
+	}
+	if start > end {
+		return nftypes.PortRange{}, fmt.Errorf("port range start %d is after its end %d", start, end)
+	}
+
+	portRange.Start = uint16(start)
+	portRange.End = uint16(end)
+	return portRange, nil
+}
+
+// toIngressForwards converts the forwarding rules into the ingress forwards the flow filter matches on
+func toIngressForwards(rules []firewallManager.ForwardRule) []nftypes.IngressForward {
+	forwards := make([]nftypes.IngressForward, 0, len(rules))
+	for _, rule := range rules {
+		// forwards of all protocols match any protocol
+		protocol := nftypes.ProtocolUnknown
+		switch rule.Protocol {
+		case firewallManager.ProtocolTCP:
+			protocol = nftypes.TCP
+		case firewallManager.ProtocolUDP:
+			protocol = nftypes.UDP
+		}
+
+		for _, backend := range rule.Backends() {
+			forwards = append(forwards, nftypes.IngressForward{
+				Ports:             toFlowPortRanges(protocol, rule.DestinationPort),
+				TranslatedAddress: backend,
+				TranslatedPorts:   toFlowPortRanges(protocol, rule.TranslatedPort),
+			})
+		}
+	}
+	return forwards
+}
+
+func toFlowPortRanges(protocol nftypes.Protocol, port firewallManager.Port) []nftypes.PortRange {
+	if port.IsRange && len(port.Values) == 2 {
+		return []nftypes.PortRange{{Protocol: protocol, Start: port.Values[0], End: port.Values[1]}}
+	}
+
+	ranges := make([]nftypes.PortRange, 0, len(port.Values))
+	for _, value := range port.Values {
+		ranges = append(ranges, nftypes.PortRange{Protocol: protocol, Start: value, End: value})
+	}
+	return ranges
+}
@@ -0,0 +1,126 @@
+package internal
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	firewallManager "github.com/netbirdio/netbird/client/firewall/manager"
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+func TestParseFlowPortRange(t *testing.T) {
+	tests := []struct {
+		entry   string
+		want    nftypes.PortRange
+		wantErr bool
+	}{
+		{entry: "udp/5353", want: nftypes.PortRange{Protocol: nftypes.UDP, Start: 5353, End: 5353}},
+		{entry: "UDP/137-138", want: nftypes.PortRange{Protocol: nftypes.UDP, Start: 137, End: 138}},
+		{entry: "tcp", want: nftypes.PortRange{Protocol: nftypes.TCP, Start: 0, End: 65535}},
+		{entry: "icmp", want: nftypes.PortRange{Protocol: nftypes.ICMP, Start: 0, End: 65535}},
+		{entry: "icmp/8", wantErr: true},
+		{entry: "gre", wantErr: true},
+		{entry: "udp/70000", wantErr: true},
+		{entry: "udp/138-137", wantErr: true},
+		{entry: "udp/", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.entry, func(t *testing.T) {
+			got, err := parseFlowPortRange(tt.entry)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestToIngressForwards(t *testing.T) {
+	forwards := toIngressForwards([]firewallManager.ForwardRule{
+		{
+			Protocol:          firewallManager.ProtocolTCP,
+			DestinationPort:   firewallManager.Port{IsRange: true, Values: []uint16{8000, 8010}},
+			TranslatedAddress: netip.MustParseAddr("100.64.0.9"),
+			TranslatedPort:    firewallManager.Port{IsRange: true, Values: []uint16{9000, 9010}},
+		},
+		{
+			Protocol:          firewallManager.ProtocolALL,
+			DestinationPort:   firewallManager.Port{Values: []uint16{53}},
+			TranslatedAddress: netip.MustParseAddr("100.64.0.10"),
+			TranslatedPort:    firewallManager.Port{Values: []uint16{5353}},
+		},
+	})
+
+	require.Len(t, forwards, 2)
+	assert.Equal(t, []nftypes.PortRange{{Protocol: nftypes.TCP, Start: 8000, End: 8010}}, forwards[0].Ports)
+	assert.Equal(t, []nftypes.PortRange{{Protocol: nftypes.TCP, Start: 9000, End: 9010}}, forwards[0].TranslatedPorts)
+	assert.Equal(t, netip.MustParseAddr("100.64.0.9"), forwards[0].TranslatedAddress)
+	assert.Equal(t, []nftypes.PortRange{{Protocol: nftypes.ProtocolUnknown, Start: 53, End: 53}}, forwards[1].Ports)
+}
+
+func TestConvertForwardingHealthCheck(t *testing.T) {
+	assert.Equal(t, firewallManager.ForwardHealthCheck{}, convertForwardingHealthCheck(nil), "nil check must disable the health check")
+
+	assert.Equal(t, firewallManager.ForwardHealthCheck{Type: firewallManager.HealthCheckTCP}, convertForwardingHealthCheck(&mgmProto.ForwardingHealthCheck{}))
+
+	got := convertForwardingHealthCheck(&mgmProto.ForwardingHealthCheck{
+		Type:               mgmProto.ForwardingHealthCheck_HTTP,
+		Path:               "healthz",
+		Interval:           durationpb.New(30 * time.Second),
+		Timeout:            durationpb.New(2 * time.Second),
+		UnhealthyThreshold: 5,
+	})
+	assert.Equal(t, firewallManager.ForwardHealthCheck{
+		Type:               firewallManager.HealthCheckHTTP,
+		Path:               "/healthz",
+		Interval:           30 * time.Second,
+		Timeout:            2 * time.Second,
+		UnhealthyThreshold: 5,
+	}, got)
+}
+
+func TestConvertTranslatedAddresses(t *testing.T) {
+	translated := netip.MustParseAddr("100.64.0.9")
+
+	addrs, err := convertTranslatedAddresses(translated, nil)
+	require.NoError(t, err)
+	assert.Nil(t, addrs)
+
+	addrs, err = convertTranslatedAddresses(translated, [][]byte{
+		{100, 64, 0, 10},
+		{100, 64, 0, 9},
+		{100, 64, 0, 11},
+		{100, 64, 0, 10},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []netip.Addr{netip.MustParseAddr("100.64.0.10"), netip.MustParseAddr("100.64.0.11")}, addrs, "duplicates must be dropped")
+
+	_, err = convertTranslatedAddresses(translated, [][]byte{{100, 64, 0}})
+	assert.Error(t, err)
+}
+
+func TestToIngressForwards_Backends(t *testing.T) {
+	forwards := toIngressForwards([]firewallManager.ForwardRule{
+		{
+			Protocol:            firewallManager.ProtocolTCP,
+			DestinationPort:     firewallManager.Port{Values: []uint16{443}},
+			TranslatedAddress:   netip.MustParseAddr("100.64.0.9"),
+			TranslatedAddresses: []netip.Addr{netip.MustParseAddr("100.64.0.10")},
+			TranslatedPort:      firewallManager.Port{Values: []uint16{8443}},
+		},
+	})
+
+	require.Len(t, forwards, 2)
+	assert.Equal(t, netip.MustParseAddr("100.64.0.9"), forwards[0].TranslatedAddress)
+	assert.Equal(t, netip.MustParseAddr("100.64.0.10"), forwards[1].TranslatedAddress)
+	assert.Equal(t, forwards[0].Ports, forwards[1].Ports)
+}
@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+
+	"github.com/netbirdio/netbird/client/internal/mgmtsim"
+	mgm "github.com/netbirdio/netbird/shared/management/client"
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+	signal "github.com/netbirdio/netbird/shared/signal/client"
+)
+
+// newManagementClient connects to management, or loads the simulated management from the file if one is configured
+func (c *ConnectClient) newManagementClient(ctx context.Context, key wgtypes.Key, tlsEnabled bool) (mgm.Client, error) {
+	if path := c.config.ManagementSimulationFile; path != "" {
+		log.Warnf("simulating the Management service with %s, the client is not connected to management", path)
+		return mgmtsim.NewClient(path)
+	}
+
+	mgmClient, err := mgm.NewClient(ctx, c.config.ManagementURL.Host, key, tlsEnabled)
+	if err != nil {
+		return nil, err
+	}
+	mgmClient.SetConnStateListener(statusRecorderToMgmConnStateNotifier(c.statusRecorder))
+	mgmClient.SetSessionResume(c.mgmResume)
+	return mgmClient, nil
+}
+
+// newSignalClient connects to signal. The simulated management may leave signal unconfigured, the messages to the
+// peers are dropped then.
+func (c *ConnectClient) newSignalClient(ctx context.Context, wtConfig *mgmProto.NetbirdConfig, key wgtypes.Key) (signal.Client, error) {
+	if c.config.ManagementSimulationFile != "" && wtConfig.GetSignal().GetUri() == "" {
+		log.Warnf("no Signal service in the simulated management, peer connections are not established")
+		return mgmtsim.NewSignalClient(), nil
+	}
+
+	signalClient, err := connectToSignal(ctx, wtConfig, key)
+	if err != nil {
+		return nil, err
+	}
+	signalClient.SetConnStateListener(statusRecorderToSignalConnStateNotifier(c.statusRecorder))
+	signalClient.SetSessionResume(c.signalResume)
+	return signalClient, nil
+}
@@ -0,0 +1,189 @@
+// Package mgmtsim simulates the management service with network maps loaded from a local file, so the routing, DNS
+// and ACL behavior of the client can be tested without a management server.
+//
+// The file holds a SyncResponse in its JSON mapping, or the same structure in YAML if the file name ends with .yaml
+// or .yml. The fields are named like in management.proto, testdata/networkmap.yaml is an example. The file is
+// watched for changes, every change is sent to the engine like a network map update.
+package mgmtsim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/system"
+	mgm "github.com/netbirdio/netbird/shared/management/client"
+	"github.com/netbirdio/netbird/shared/management/domain"
+	"github.com/netbirdio/netbird/shared/management/proto"
+)
+
+var _ mgm.Client = (*Client)(nil)
+
+// Client is a management client that serves the network maps from a file
+type Client struct {
+	path      string
+	serverKey wgtypes.Key
+
+	mu       sync.Mutex
+	current  *proto.SyncResponse
+	revision uint64
+	closed   bool
+}
+
+// NewClient loads the simulated management state from the file
+func NewClient(path string) (*Client, error) {
+	serverKey, err := wgtypes.GeneratePrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("generate server key: %w", err)
+	}
+
+	c := &Client{
+		path:      path,
+		serverKey: serverKey.PublicKey(),
+	}
+	if err := c.reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Close stops serving the network maps
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	return nil
+}
+
+// Sync sends the network map of the file and every change of it to the handler until the context is done
+func (c *Client) Sync(ctx context.Context, _ *system.Info, msgHandler func(msg *proto.SyncResponse) error) error {
+	updates := make(chan struct{}, 1)
+	w, err := newWatcher(c.path, func() {
+		select {
+		case updates <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		log.Warnf("failed to watch %s, changes are not applied: %v", c.path, err)
+	} else {
+		defer w.close()
+	}
+
+	if err := msgHandler(c.syncResponse()); err != nil {
+		return fmt.Errorf("handle simulated sync: %w", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-updates:
+			if err := c.reload(); err != nil {
+				log.Errorf("failed to reload the simulated network map, keeping the previous one: %v", err)
+				continue
+			}
+			log.Infof("applying the simulated network map from %s", c.path)
+			if err := msgHandler(c.syncResponse()); err != nil {
+				log.Errorf("failed to apply the simulated network map: %v", err)
+			}
+		}
+	}
+}
+
+// GetServerPublicKey returns a key generated for the simulation
+func (c *Client) GetServerPublicKey() (*wgtypes.Key, error) {
+	return &c.serverKey, nil
+}
+
+// Register returns the peer and the Netbird config of the file, the setup key isn't checked
+func (c *Client) Register(_ wgtypes.Key, _ string, _ string, _ *system.Info, _ []byte, _ domain.List) (*proto.LoginResponse, error) {
+	return c.loginResponse(), nil
+}
+
+// Login returns the peer and the Netbird config of the file
+func (c *Client) Login(_ wgtypes.Key, _ *system.Info, _ []byte, _ domain.List) (*proto.LoginResponse, error) {
+	return c.loginResponse(), nil
+}
+
+func (c *Client) GetDeviceAuthorizationFlow(wgtypes.Key) (*proto.DeviceAuthorizationFlow, error) {
+	return nil, gstatus.Error(codes.NotFound, "no device authorization flow in the simulated management")
+}
+
+func (c *Client) GetPKCEAuthorizationFlow(wgtypes.Key) (*proto.PKCEAuthorizationFlow, error) {
+	return nil, gstatus.Error(codes.NotFound, "no PKCE authorization flow in the simulated management")
+}
+
+// GetNetworkMap returns the network map of the file
+func (c *Client) GetNetworkMap(*system.Info) (*proto.NetworkMap, error) {
+	return c.syncResponse().GetNetworkMap(), nil
+}
+
+// IsHealthy reports whether the client is still open
+func (c *Client) IsHealthy() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return !c.closed
+}
+
+// SyncMeta discards the meta, there is no management to report it to
+func (c *Client) SyncMeta(*system.Info) error {
+	return nil
+}
+
+// Logout does nothing in the simulation
+func (c *Client) Logout() error {
+	return nil
+}
+
+// reload loads the file again, the network map serial is the number of loads so every change is applied
+func (c *Client) reload() error {
+	resp, err := loadSyncResponse(c.path)
+	if err != nil {
+		return err
+	}
+	// the peer config may be set at the top or in the network map, like management sends it
+	if resp.PeerConfig == nil {
+		resp.PeerConfig = resp.GetNetworkMap().GetPeerConfig()
+	}
+	if resp.NetworkMap != nil && resp.NetworkMap.PeerConfig == nil {
+		resp.NetworkMap.PeerConfig = resp.PeerConfig
+	}
+	if resp.GetPeerConfig().GetAddress() == "" {
+		return errors.New("peerConfig.address is required")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.revision++
+	if resp.NetworkMap != nil {
+		resp.NetworkMap.Serial = c.revision
+	}
+	c.current = resp
+	return nil
+}
+
+func (c *Client) syncResponse() *proto.SyncResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.current
+}
+
+func (c *Client) loginResponse() *proto.LoginResponse {
+	resp := c.syncResponse()
+	return &proto.LoginResponse{
+		NetbirdConfig: resp.GetNetbirdConfig(),
+		PeerConfig:    resp.GetPeerConfig(),
+		Checks:        resp.GetChecks(),
+	}
+}
@@ -0,0 +1,88 @@
+package mgmtsim
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/shared/management/proto"
+)
+
+func TestLoadSyncResponse_YAML(t *testing.T) {
+	resp, err := loadSyncResponse("testdata/networkmap.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, "100.64.0.10/16", resp.GetPeerConfig().GetAddress())
+	require.Len(t, resp.GetNetworkMap().GetRemotePeers(), 1)
+	assert.Equal(t, []string{"100.64.0.20/32"}, resp.GetNetworkMap().GetRemotePeers()[0].GetAllowedIps())
+	require.Len(t, resp.GetNetworkMap().GetRoutes(), 1)
+	assert.Equal(t, "10.10.0.0/16", resp.GetNetworkMap().GetRoutes()[0].GetNetwork())
+	assert.True(t, resp.GetNetworkMap().GetDNSConfig().GetServiceEnable())
+	require.Len(t, resp.GetNetworkMap().GetFirewallRules(), 1)
+	assert.Equal(t, proto.RuleAction_ACCEPT, resp.GetNetworkMap().GetFirewallRules()[0].GetAction())
+}
+
+func TestNewClient(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "networkmap.json")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"NetworkMap": {}}`), 0o600))
+	_, err := NewClient(path)
+	assert.ErrorContains(t, err, "peerConfig.address is required")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"NetworkMap": {"peerConfig": {"address": "100.64.0.10/16"}}}`), 0o600))
+	client, err := NewClient(path)
+	require.NoError(t, err)
+
+	login, err := client.Login(client.serverKey, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "100.64.0.10/16", login.GetPeerConfig().GetAddress(), "the peer config of the network map is used for the login")
+
+	nm, err := client.GetNetworkMap(nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), nm.GetSerial())
+}
+
+func TestClient_SyncAppliesChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "networkmap.json")
+	write := func(fqdn string) {
+		data := `{"peerConfig": {"address": "100.64.0.10/16", "fqdn": "` + fqdn + `"}, "NetworkMap": {}}`
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+	}
+	write("first.netbird.selfhosted")
+
+	client, err := NewClient(path)
+	require.NoError(t, err)
+
+	updates := make(chan *proto.SyncResponse, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Sync(t.Context(), nil, func(msg *proto.SyncResponse) error {
+			updates <- msg
+			return nil
+		})
+	}()
+
+	select {
+	case msg := <-updates:
+		assert.Equal(t, "first.netbird.selfhosted", msg.GetPeerConfig().GetFqdn())
+	case <-time.After(5 * time.Second):
+		t.Fatal("no initial sync")
+	}
+
+	// a broken file keeps the previous network map
+	require.NoError(t, os.WriteFile(path, []byte(`{`), 0o600))
+	time.Sleep(2 * reloadDelay)
+	write("second.netbird.selfhosted")
+
+	select {
+	case msg := <-updates:
+		assert.Equal(t, "second.netbird.selfhosted", msg.GetPeerConfig().GetFqdn())
+		assert.Greater(t, msg.GetNetworkMap().GetSerial(), uint64(1))
+	case <-time.After(5 * time.Second):
+		t.Fatal("the change was not applied")
+	}
+}
@@ -0,0 +1,99 @@
+package mgmtsim
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"google.golang.org/protobuf/encoding/protojson"
+	"gopkg.in/yaml.v3"
+
+	"github.com/netbirdio/netbird/shared/management/proto"
+)
+
+// reloadDelay collects the events of an editor saving the file into a single reload
+const reloadDelay = 200 * time.Millisecond
+
+// loadSyncResponse reads a SyncResponse from a JSON or YAML file
+func loadSyncResponse(path string) (*proto.SyncResponse, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+	}
+
+	resp := &proto.SyncResponse{}
+	if err := protojson.Unmarshal(data, resp); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return resp, nil
+}
+
+// yamlToJSON converts YAML to JSON, so the YAML file uses the JSON mapping of the protobuf messages
+func yamlToJSON(data []byte) ([]byte, error) {
+	var v any
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		v = map[string]any{}
+	}
+	return json.Marshal(v)
+}
+
+// watcher calls onChange after the file was written, created or replaced
+type watcher struct {
+	inotify *fsnotify.Watcher
+	wg      sync.WaitGroup
+}
+
+// newWatcher watches the directory of the file, editors commonly replace the file instead of writing it
+func newWatcher(path string, onChange func()) (*watcher, error) {
+	inotify, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("create watcher: %w", err)
+	}
+	if err := inotify.Add(filepath.Dir(path)); err != nil {
+		_ = inotify.Close()
+		return nil, fmt.Errorf("watch %s: %w", filepath.Dir(path), err)
+	}
+
+	w := &watcher{inotify: inotify}
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		var timer *time.Timer
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+		for event := range inotify.Events {
+			if filepath.Clean(event.Name) != filepath.Clean(path) || !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+				continue
+			}
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(reloadDelay, onChange)
+		}
+	}()
+	return w, nil
+}
+
+func (w *watcher) close() {
+	_ = w.inotify.Close()
+	w.wg.Wait()
+}
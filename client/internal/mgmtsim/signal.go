@@ -0,0 +1,57 @@
+package mgmtsim
+
+import (
+	"context"
+
+	signal "github.com/netbirdio/netbird/shared/signal/client"
+	"github.com/netbirdio/netbird/shared/signal/proto"
+)
+
+var _ signal.Client = (*SignalClient)(nil)
+
+// SignalClient stands in for the signal service when the simulated Netbird config has none. The offers to the
+// peers are dropped, so only the local behavior of the client is simulated, not the peer connections.
+type SignalClient struct{}
+
+// NewSignalClient returns a signal client that drops the messages
+func NewSignalClient() *SignalClient {
+	return &SignalClient{}
+}
+
+func (s *SignalClient) Close() error {
+	return nil
+}
+
+func (s *SignalClient) StreamConnected() bool {
+	return true
+}
+
+func (s *SignalClient) GetStatus() signal.Status {
+	return signal.StreamConnected
+}
+
+// Receive blocks until the context is done, no messages arrive
+func (s *SignalClient) Receive(ctx context.Context, _ func(msg *proto.Message) error) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (s *SignalClient) Ready() bool {
+	return true
+}
+
+func (s *SignalClient) IsHealthy() bool {
+	return true
+}
+
+func (s *SignalClient) WaitStreamConnected() {}
+
+func (s *SignalClient) SendToStream(*proto.EncryptedMessage) error {
+	return nil
+}
+
+func (s *SignalClient) Send(*proto.Message) error {
+	return nil
+}
+
+func (s *SignalClient) SetOnReconnectedListener(func()) {}
@@ -0,0 +1,102 @@
+//go:build linux && !android
+
+package bpftrack
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/link"
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+const filterPriority = 1
+
+// attach hooks the programs to the interface. TCX links are used on kernels supporting them (6.6+), older kernels
+// get direct-action filters on a clsact qdisc. The returned function detaches the programs again.
+func attach(ifIndex int, objs *flowObjects) (func() error, error) {
+	detach, err := attachTCX(ifIndex, objs)
+	if err == nil {
+		return detach, nil
+	}
+	log.Debugf("failed to attach eBPF flow programs with tcx, falling back to clsact: %v", err)
+
+	return attachClsact(ifIndex, objs)
+}
+
+func attachTCX(ifIndex int, objs *flowObjects) (func() error, error) {
+	ingress, err := link.AttachTCX(link.TCXOptions{
+		Interface: ifIndex,
+		Program:   objs.ingress,
+		Attach:    ebpf.AttachTCXIngress,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("attach ingress: %w", err)
+	}
+
+	egress, err := link.AttachTCX(link.TCXOptions{
+		Interface: ifIndex,
+		Program:   objs.egress,
+		Attach:    ebpf.AttachTCXEgress,
+	})
+	if err != nil {
+		if err := ingress.Close(); err != nil {
+			log.Debugf("failed to detach ingress program: %v", err)
+		}
+		return nil, fmt.Errorf("attach egress: %w", err)
+	}
+
+	return func() error {
+		return errors.Join(ingress.Close(), egress.Close())
+	}, nil
+}
+
+func attachClsact(ifIndex int, objs *flowObjects) (func() error, error) {
+	qdisc := &netlink.GenericQdisc{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: ifIndex,
+			Handle:    netlink.MakeHandle(0xffff, 0),
+			Parent:    netlink.HANDLE_CLSACT,
+		},
+		QdiscType: "clsact",
+	}
+	if err := netlink.QdiscAdd(qdisc); err != nil && !errors.Is(err, unix.EEXIST) {
+		return nil, fmt.Errorf("add clsact qdisc: %w", err)
+	}
+
+	ingress := clsactFilter(ifIndex, netlink.HANDLE_MIN_INGRESS, objs.ingress, "nb_flows_in")
+	if err := netlink.FilterReplace(ingress); err != nil {
+		return nil, fmt.Errorf("add ingress filter: %w", err)
+	}
+
+	egress := clsactFilter(ifIndex, netlink.HANDLE_MIN_EGRESS, objs.egress, "nb_flows_out")
+	if err := netlink.FilterReplace(egress); err != nil {
+		if err := netlink.FilterDel(ingress); err != nil {
+			log.Debugf("failed to delete ingress filter: %v", err)
+		}
+		return nil, fmt.Errorf("add egress filter: %w", err)
+	}
+
+	// the qdisc stays, other filters may use it and it's removed with the interface
+	return func() error {
+		return errors.Join(netlink.FilterDel(ingress), netlink.FilterDel(egress))
+	}, nil
+}
+
+func clsactFilter(ifIndex int, parent uint32, prog *ebpf.Program, name string) *netlink.BpfFilter {
+	return &netlink.BpfFilter{
+		FilterAttrs: netlink.FilterAttrs{
+			LinkIndex: ifIndex,
+			Parent:    parent,
+			Handle:    1,
+			Protocol:  unix.ETH_P_ALL,
+			Priority:  filterPriority,
+		},
+		Fd:           prog.FD(),
+		Name:         name,
+		DirectAction: true,
+	}
+}
@@ -0,0 +1,271 @@
+//go:build linux && !android
+
+// Package bpftrack accounts the flows on the WireGuard interface with eBPF programs attached to its TC hooks.
+// The kernel counts the packets of each flow in a map, userspace only reads the map periodically, so the cost
+// doesn't grow with the packet rate like with per-packet processing in Go.
+package bpftrack
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/rlimit"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+)
+
+const (
+	pollInterval = 5 * time.Second
+
+	// tcpIdleTimeout ends TCP flows without packets, a later packet starts a new flow
+	tcpIdleTimeout = 5 * time.Minute
+	// idleTimeout ends the flows of the other protocols without packets, like the UDP timeout of conntrack
+	idleTimeout = 30 * time.Second
+	// closeLinger keeps closed TCP flows until the last ACKs passed, so they don't start a new flow
+	closeLinger = 10 * time.Second
+)
+
+// trackedFlow is a flow the start event was stored for
+type trackedFlow struct {
+	id    uuid.UUID
+	last  flowValue
+	polls uint64
+}
+
+// BPFTrack stores the start and the end of the flows counted by the eBPF programs
+type BPFTrack struct {
+	flowLogger nftypes.FlowLogger
+	iface      nftypes.IFaceMapper
+	maxFlows   uint32
+
+	mux     sync.Mutex
+	objs    *flowObjects
+	detach  func() error
+	cancel  context.CancelFunc
+	done    chan struct{}
+	tracked map[flowKey]*trackedFlow
+	polls   uint64
+}
+
+// New creates a tracker for the flows on the WireGuard interface
+func New(flowLogger nftypes.FlowLogger, iface nftypes.IFaceMapper) *BPFTrack {
+	return &BPFTrack{
+		flowLogger: flowLogger,
+		iface:      iface,
+		maxFlows:   defaultMaxFlows,
+		tracked:    make(map[flowKey]*trackedFlow),
+	}
+}
+
+// Start loads the programs and attaches them to the interface. It fails if the kernel doesn't support eBPF or the
+// process lacks the privileges. The packets are always counted, enableCounters is ignored. This method is idempotent.
+func (t *BPFTrack) Start(_ bool) error {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.objs != nil {
+		return nil
+	}
+
+	iface, err := net.InterfaceByName(t.iface.Name())
+	if err != nil {
+		return fmt.Errorf("get interface %s: %w", t.iface.Name(), err)
+	}
+
+	if err := rlimit.RemoveMemlock(); err != nil {
+		return fmt.Errorf("remove memlock limit: %w", err)
+	}
+
+	objs, err := loadFlowObjects(t.maxFlows)
+	if err != nil {
+		return err
+	}
+
+	detach, err := attach(iface.Index, objs)
+	if err != nil {
+		if err := objs.Close(); err != nil {
+			log.Debugf("failed to close eBPF flow objects: %v", err)
+		}
+		return fmt.Errorf("attach to %s: %w", iface.Name, err)
+	}
+
+	t.objs = objs
+	t.detach = detach
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+	t.done = make(chan struct{})
+	go t.pollRoutine(ctx, t.done)
+
+	log.Infof("started eBPF flow capture on %s", iface.Name)
+	return nil
+}
+
+// Stop detaches the programs, the flows in progress are ended. This method is idempotent.
+func (t *BPFTrack) Stop() {
+	if err := t.Close(); err != nil {
+		log.Warnf("failed to stop eBPF flow capture: %v", err)
+	}
+}
+
+// Close detaches the programs and releases the flow table
+func (t *BPFTrack) Close() error {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.objs == nil {
+		return nil
+	}
+
+	t.cancel()
+	<-t.done
+
+	var errs []error
+	if err := t.detach(); err != nil {
+		errs = append(errs, fmt.Errorf("detach: %w", err))
+	}
+
+	// end the flows with their final counters
+	t.poll(monotonicNow())
+	for key, flow := range t.tracked {
+		t.storeEvent(nftypes.TypeEnd, key, flow)
+	}
+	clear(t.tracked)
+
+	if err := t.objs.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("close objects: %w", err))
+	}
+	t.objs = nil
+
+	log.Info("stopped eBPF flow capture")
+	return errors.Join(errs...)
+}
+
+func (t *BPFTrack) pollRoutine(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.poll(monotonicNow())
+		}
+	}
+}
+
+// poll stores the start of the new flows and the end of the idle and closed flows. Flows evicted from the full
+// table end with their last known counters.
+func (t *BPFTrack) poll(now uint64) {
+	t.polls++
+
+	var (
+		key   flowKey
+		value flowValue
+		ended []flowKey
+	)
+	iter := t.objs.flows.Iterate()
+	for iter.Next(&key, &value) {
+		flow, ok := t.tracked[key]
+		if !ok {
+			flow = &trackedFlow{id: uuid.New(), last: value}
+			t.tracked[key] = flow
+			t.storeEvent(nftypes.TypeStart, key, flow)
+		}
+		flow.last = value
+		flow.polls = t.polls
+
+		if flowEnded(key, value, now) {
+			ended = append(ended, key)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		log.Debugf("failed to iterate eBPF flows: %v", err)
+		return
+	}
+
+	for _, key := range ended {
+		if err := t.objs.flows.Delete(&key); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			log.Debugf("failed to delete eBPF flow: %v", err)
+		}
+	}
+
+	for key, flow := range t.tracked {
+		if flow.polls == t.polls && !flowEnded(key, flow.last, now) {
+			continue
+		}
+		t.storeEvent(nftypes.TypeEnd, key, flow)
+		delete(t.tracked, key)
+	}
+}
+
+func flowEnded(key flowKey, value flowValue, now uint64) bool {
+	timeout := idleTimeout
+	switch {
+	case value.Closed != 0:
+		timeout = closeLinger
+	case key.Proto == uint8(nftypes.TCP):
+		timeout = tcpIdleTimeout
+	}
+	return now > value.LastSeen && now-value.LastSeen > uint64(timeout)
+}
+
+func (t *BPFTrack) storeEvent(typ nftypes.Type, key flowKey, flow *trackedFlow) {
+	remote := netip.AddrFrom16(key.Remote).Unmap()
+	local := netip.AddrFrom16(key.Local).Unmap()
+	remotePort := binary.BigEndian.Uint16(key.RemotePort[:])
+	localPort := binary.BigEndian.Uint16(key.LocalPort[:])
+
+	fields := nftypes.EventFields{
+		FlowID:    flow.id,
+		Type:      typ,
+		Direction: nftypes.Ingress,
+		Protocol:  nftypes.Protocol(key.Proto),
+		SourceIP:  remote,
+		DestIP:    local,
+		RxPackets: flow.last.RxPackets,
+		TxPackets: flow.last.TxPackets,
+		RxBytes:   flow.last.RxBytes,
+		TxBytes:   flow.last.TxBytes,
+	}
+	if flow.last.Initiator == initiatorEgress {
+		fields.Direction = nftypes.Egress
+		fields.SourceIP, fields.DestIP = local, remote
+		remotePort, localPort = localPort, remotePort
+	}
+
+	switch fields.Protocol {
+	case nftypes.ICMP, nftypes.ICMPv6:
+		fields.ICMPType = key.RemotePort[0]
+		fields.ICMPCode = key.RemotePort[1]
+	default:
+		fields.SourcePort = remotePort
+		fields.DestPort = localPort
+	}
+
+	log.Tracef("eBPF flow event type %d, %s %s: %s:%d → %s:%d", typ, fields.Direction, fields.Protocol,
+		fields.SourceIP, fields.SourcePort, fields.DestIP, fields.DestPort)
+	t.flowLogger.StoreEvent(fields)
+}
+
+// monotonicNow returns the time of the clock of bpf_ktime_get_ns
+func monotonicNow() uint64 {
+	var ts unix.Timespec
+	if err := unix.ClockGettime(unix.CLOCK_MONOTONIC, &ts); err != nil {
+		return 0
+	}
+	return uint64(ts.Nano())
+}
@@ -0,0 +1,264 @@
+//go:build linux && !android
+
+package bpftrack
+
+import (
+	"encoding/binary"
+	"net"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/rlimit"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+)
+
+var (
+	localV4  = netip.MustParseAddr("100.64.0.1")
+	remoteV4 = netip.MustParseAddr("100.64.0.2")
+	localV6  = netip.MustParseAddr("fd00::1")
+	remoteV6 = netip.MustParseAddr("fd00::2")
+)
+
+type eventRecorder struct {
+	nftypes.FlowLogger
+	mu     sync.Mutex
+	events []nftypes.EventFields
+}
+
+func (r *eventRecorder) StoreEvent(event nftypes.EventFields) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func loadTestObjects(t *testing.T) *flowObjects {
+	t.Helper()
+
+	if err := rlimit.RemoveMemlock(); err != nil {
+		t.Skipf("eBPF not available: %v", err)
+	}
+	objs, err := loadFlowObjects(64)
+	if err != nil {
+		t.Skipf("eBPF not available: %v", err)
+	}
+	t.Cleanup(func() {
+		require.NoError(t, objs.Close())
+	})
+	return objs
+}
+
+func runProgram(t *testing.T, prog *ebpf.Program, frame []byte) {
+	t.Helper()
+
+	ret, err := prog.Run(&ebpf.RunOptions{Data: frame})
+	if err != nil {
+		t.Skipf("running eBPF programs not supported: %v", err)
+	}
+	assert.Equal(t, int32(tcActUnspec), int32(ret), "packet must pass")
+}
+
+func buildFrame(t *testing.T, src, dst netip.Addr, l4 ...gopacket.SerializableLayer) []byte {
+	t.Helper()
+
+	eth := &layers.Ethernet{
+		SrcMAC:       net.HardwareAddr{0, 0, 0, 0, 0, 1},
+		DstMAC:       net.HardwareAddr{0, 0, 0, 0, 0, 2},
+		EthernetType: layers.EthernetTypeIPv4,
+	}
+
+	var network gopacket.NetworkLayer
+	var ipLayer gopacket.SerializableLayer
+	if src.Is4() {
+		ip := &layers.IPv4{Version: 4, TTL: 64, SrcIP: src.AsSlice(), DstIP: dst.AsSlice()}
+		network, ipLayer = ip, ip
+	} else {
+		eth.EthernetType = layers.EthernetTypeIPv6
+		ip := &layers.IPv6{Version: 6, HopLimit: 64, SrcIP: src.AsSlice(), DstIP: dst.AsSlice()}
+		network, ipLayer = ip, ip
+	}
+
+	switch l := l4[0].(type) {
+	case *layers.TCP:
+		network.(*layers.IPv4).Protocol = layers.IPProtocolTCP
+		require.NoError(t, l.SetNetworkLayerForChecksum(network))
+	case *layers.UDP:
+		if ip, ok := network.(*layers.IPv6); ok {
+			ip.NextHeader = layers.IPProtocolUDP
+		} else {
+			network.(*layers.IPv4).Protocol = layers.IPProtocolUDP
+		}
+		require.NoError(t, l.SetNetworkLayerForChecksum(network))
+	case *layers.ICMPv4:
+		network.(*layers.IPv4).Protocol = layers.IPProtocolICMPv4
+	}
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{ComputeChecksums: true, FixLengths: true}
+	require.NoError(t, gopacket.SerializeLayers(buf, opts, append([]gopacket.SerializableLayer{eth, ipLayer}, l4...)...))
+	return buf.Bytes()
+}
+
+func testKey(remote, local netip.Addr, remotePort, localPort uint16, proto nftypes.Protocol) flowKey {
+	key := flowKey{
+		Remote: remote.As16(),
+		Local:  local.As16(),
+		Proto:  uint8(proto),
+	}
+	binary.BigEndian.PutUint16(key.RemotePort[:], remotePort)
+	binary.BigEndian.PutUint16(key.LocalPort[:], localPort)
+	return key
+}
+
+func lookupFlow(t *testing.T, objs *flowObjects, key flowKey) flowValue {
+	t.Helper()
+
+	var value flowValue
+	require.NoError(t, objs.flows.Lookup(&key, &value), "flow must be counted")
+	return value
+}
+
+func TestFlowPrograms_TCP(t *testing.T) {
+	objs := loadTestObjects(t)
+
+	syn := buildFrame(t, remoteV4, localV4, &layers.TCP{SrcPort: 40000, DstPort: 22, SYN: true}, gopacket.Payload(make([]byte, 100)))
+	synAck := buildFrame(t, localV4, remoteV4, &layers.TCP{SrcPort: 22, DstPort: 40000, SYN: true, ACK: true})
+	fin := buildFrame(t, remoteV4, localV4, &layers.TCP{SrcPort: 40000, DstPort: 22, FIN: true, ACK: true})
+
+	runProgram(t, objs.ingress, syn)
+	runProgram(t, objs.egress, synAck)
+
+	key := testKey(remoteV4, localV4, 40000, 22, nftypes.TCP)
+	value := lookupFlow(t, objs, key)
+	assert.Equal(t, uint64(1), value.RxPackets)
+	assert.Equal(t, uint64(1), value.TxPackets)
+	assert.NotZero(t, value.RxBytes)
+	assert.Greater(t, value.RxBytes, value.TxBytes, "payload must be counted")
+	assert.Equal(t, uint8(initiatorIngress), value.Initiator)
+	assert.Zero(t, value.Closed)
+	assert.NotZero(t, value.FirstSeen)
+
+	runProgram(t, objs.ingress, fin)
+
+	value = lookupFlow(t, objs, key)
+	assert.Equal(t, uint64(2), value.RxPackets)
+	assert.Equal(t, uint8(1), value.Closed, "FIN must close the flow")
+	assert.GreaterOrEqual(t, value.LastSeen, value.FirstSeen)
+}
+
+func TestFlowPrograms_UDPv6(t *testing.T) {
+	objs := loadTestObjects(t)
+
+	query := buildFrame(t, localV6, remoteV6, &layers.UDP{SrcPort: 50000, DstPort: 53}, gopacket.Payload("query"))
+	runProgram(t, objs.egress, query)
+
+	value := lookupFlow(t, objs, testKey(remoteV6, localV6, 53, 50000, nftypes.UDP))
+	assert.Equal(t, uint64(1), value.TxPackets)
+	assert.Zero(t, value.RxPackets)
+	assert.Equal(t, uint8(initiatorEgress), value.Initiator)
+}
+
+func TestFlowPrograms_ICMPEcho(t *testing.T) {
+	objs := loadTestObjects(t)
+
+	request := buildFrame(t, localV4, remoteV4, &layers.ICMPv4{TypeCode: layers.CreateICMPv4TypeCode(layers.ICMPv4TypeEchoRequest, 0), Id: 1, Seq: 1})
+	reply := buildFrame(t, remoteV4, localV4, &layers.ICMPv4{TypeCode: layers.CreateICMPv4TypeCode(layers.ICMPv4TypeEchoReply, 0), Id: 1, Seq: 1})
+
+	runProgram(t, objs.egress, request)
+	runProgram(t, objs.ingress, reply)
+
+	key := flowKey{Remote: remoteV4.As16(), Local: localV4.As16(), RemotePort: [2]byte{8, 0}, Proto: uint8(nftypes.ICMP)}
+	value := lookupFlow(t, objs, key)
+	assert.Equal(t, uint64(1), value.TxPackets)
+	assert.Equal(t, uint64(1), value.RxPackets, "reply must be counted in the flow of the request")
+	assert.Equal(t, uint8(initiatorEgress), value.Initiator)
+}
+
+func TestBPFTrack_Poll(t *testing.T) {
+	objs := loadTestObjects(t)
+	recorder := &eventRecorder{}
+	tracker := New(recorder, nil)
+	tracker.objs = objs
+
+	now := uint64(time.Hour)
+	tcpKey := testKey(remoteV4, localV4, 40000, 22, nftypes.TCP)
+	udpKey := testKey(remoteV6, localV6, 53, 50000, nftypes.UDP)
+	require.NoError(t, objs.flows.Put(&tcpKey, &flowValue{RxPackets: 3, RxBytes: 300, TxPackets: 2, TxBytes: 200, FirstSeen: now, LastSeen: now}))
+	require.NoError(t, objs.flows.Put(&udpKey, &flowValue{TxPackets: 1, TxBytes: 60, FirstSeen: now, LastSeen: now, Initiator: initiatorEgress}))
+
+	tracker.poll(now)
+	require.Len(t, recorder.events, 2, "both flows must start")
+	for _, event := range recorder.events {
+		assert.Equal(t, nftypes.TypeStart, event.Type)
+		switch event.Protocol {
+		case nftypes.TCP:
+			assert.Equal(t, nftypes.Ingress, event.Direction)
+			assert.Equal(t, remoteV4, event.SourceIP)
+			assert.Equal(t, uint16(40000), event.SourcePort)
+			assert.Equal(t, uint16(22), event.DestPort)
+			assert.Equal(t, uint64(300), event.RxBytes)
+		case nftypes.UDP:
+			assert.Equal(t, nftypes.Egress, event.Direction)
+			assert.Equal(t, localV6, event.SourceIP)
+			assert.Equal(t, remoteV6, event.DestIP)
+			assert.Equal(t, uint16(50000), event.SourcePort)
+			assert.Equal(t, uint16(53), event.DestPort)
+		}
+	}
+
+	// the UDP flow idles out, the TCP flow is still within its timeout
+	recorder.events = nil
+	tracker.poll(now + uint64(idleTimeout) + 1)
+	require.Len(t, recorder.events, 1)
+	assert.Equal(t, nftypes.TypeEnd, recorder.events[0].Type)
+	assert.Equal(t, nftypes.UDP, recorder.events[0].Protocol)
+	assert.Equal(t, uint64(60), recorder.events[0].TxBytes)
+
+	var value flowValue
+	assert.ErrorIs(t, objs.flows.Lookup(&udpKey, &value), ebpf.ErrKeyNotExist, "ended flow must be deleted")
+
+	// an evicted flow ends with its last counters
+	recorder.events = nil
+	require.NoError(t, objs.flows.Delete(&tcpKey))
+	tracker.poll(now + uint64(idleTimeout) + 2)
+	require.Len(t, recorder.events, 1)
+	assert.Equal(t, nftypes.TypeEnd, recorder.events[0].Type)
+	assert.Equal(t, nftypes.TCP, recorder.events[0].Protocol)
+	assert.Equal(t, uint64(3), recorder.events[0].RxPackets)
+	assert.Empty(t, tracker.tracked)
+}
+
+func TestFlowEnded(t *testing.T) {
+	tcp := flowKey{Proto: uint8(nftypes.TCP)}
+	udp := flowKey{Proto: uint8(nftypes.UDP)}
+	last := uint64(time.Hour)
+
+	tests := []struct {
+		name  string
+		key   flowKey
+		value flowValue
+		now   uint64
+		ended bool
+	}{
+		{"active tcp", tcp, flowValue{LastSeen: last}, last + uint64(time.Minute), false},
+		{"idle tcp", tcp, flowValue{LastSeen: last}, last + uint64(tcpIdleTimeout) + 1, true},
+		{"closed tcp lingering", tcp, flowValue{LastSeen: last, Closed: 1}, last + uint64(time.Second), false},
+		{"closed tcp", tcp, flowValue{LastSeen: last, Closed: 1}, last + uint64(closeLinger) + 1, true},
+		{"active udp", udp, flowValue{LastSeen: last}, last + uint64(time.Second), false},
+		{"idle udp", udp, flowValue{LastSeen: last}, last + uint64(idleTimeout) + 1, true},
+		{"seen after poll", udp, flowValue{LastSeen: last}, last - 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.ended, flowEnded(tt.key, tt.value, tt.now))
+		})
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux || android
+
+package bpftrack
+
+import nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+
+// New returns nil, eBPF flow capture is only available on Linux
+func New(flowLogger nftypes.FlowLogger, iface nftypes.IFaceMapper) nftypes.ConnTracker {
+	return nil
+}
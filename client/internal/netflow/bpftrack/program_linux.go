@@ -0,0 +1,344 @@
+//go:build linux && !android
+
+package bpftrack
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/asm"
+)
+
+// The programs are assembled here instead of compiled from C, so the client doesn't need a BPF toolchain to build
+// and no object file is embedded (see the note on the object files in client/internal/ebpf).
+//
+// Both programs parse the IP and transport header of each packet on the WireGuard interface and count it in the
+// entry of its flow. The key is oriented by the peer side, so both directions of a connection share an entry:
+// the ingress program counts received packets, the egress program sent ones.
+
+const (
+	// defaultMaxFlows is the size of the flow table. The least recently used flows are evicted if it's full.
+	defaultMaxFlows = 65536
+
+	initiatorIngress = 0
+	initiatorEgress  = 1
+
+	// bpfNoExist is BPF_NOEXIST, the update fails if the key exists
+	bpfNoExist = 1
+	// bpfHdrStartNet is BPF_HDR_START_NET, offsets are relative to the network header
+	bpfHdrStartNet = 1
+	// tcActUnspec lets the packet continue, it is TC_ACT_UNSPEC and TCX_NEXT
+	tcActUnspec = -1
+
+	tcpFlagsCloseMask = 0x05 // FIN | RST
+)
+
+// stack layout of the programs, offsets relative to the frame pointer
+const (
+	stackKey    = -40  // flowKey, 40 bytes
+	stackHeader = -80  // IP header, 40 bytes
+	stackL4     = -96  // transport header, 16 bytes
+	stackValue  = -152 // flowValue, 56 bytes
+
+	keyRemote     = stackKey
+	keyLocal      = stackKey + 16
+	keyRemotePort = stackKey + 32
+	keyLocalPort  = stackKey + 34
+	keyProto      = stackKey + 36
+
+	valueRxPackets = 0
+	valueRxBytes   = 8
+	valueTxPackets = 16
+	valueTxBytes   = 24
+	valueFirstSeen = 32
+	valueLastSeen  = 40
+	valueInitiator = 48
+	valueClosed    = 49
+)
+
+// flowKey identifies a flow from the view of this peer. IPv4 addresses are stored IPv4-mapped, ports in network
+// byte order. ICMP flows carry the type and the code in RemotePort, echo replies are counted as requests.
+type flowKey struct {
+	Remote     [16]byte
+	Local      [16]byte
+	RemotePort [2]byte
+	LocalPort  [2]byte
+	Proto      uint8
+	_          [3]byte
+}
+
+// flowValue holds the counters of a flow, the timestamps are CLOCK_MONOTONIC nanoseconds
+type flowValue struct {
+	RxPackets uint64
+	RxBytes   uint64
+	TxPackets uint64
+	TxBytes   uint64
+	FirstSeen uint64
+	LastSeen  uint64
+	// Initiator is the direction of the first packet of the flow
+	Initiator uint8
+	// Closed is set once a TCP FIN or RST was seen
+	Closed uint8
+	_      [6]byte
+}
+
+// flowObjects are the flow table and the programs counting into it
+type flowObjects struct {
+	flows   *ebpf.Map
+	ingress *ebpf.Program
+	egress  *ebpf.Program
+}
+
+func loadFlowObjects(maxFlows uint32) (*flowObjects, error) {
+	flows, err := ebpf.NewMap(&ebpf.MapSpec{
+		Name:       "nb_flows",
+		Type:       ebpf.LRUHash,
+		KeySize:    uint32(binary.Size(flowKey{})),
+		ValueSize:  uint32(binary.Size(flowValue{})),
+		MaxEntries: maxFlows,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create flow map: %w", err)
+	}
+
+	ingress, err := newFlowProgram("nb_flows_in", flows, initiatorIngress)
+	if err != nil {
+		_ = flows.Close()
+		return nil, fmt.Errorf("load ingress program: %w", err)
+	}
+
+	egress, err := newFlowProgram("nb_flows_out", flows, initiatorEgress)
+	if err != nil {
+		_ = ingress.Close()
+		_ = flows.Close()
+		return nil, fmt.Errorf("load egress program: %w", err)
+	}
+
+	return &flowObjects{flows: flows, ingress: ingress, egress: egress}, nil
+}
+
+func (o *flowObjects) Close() error {
+	var firstErr error
+	for _, closer := range []interface{ Close() error }{o.ingress, o.egress, o.flows} {
+		if err := closer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func newFlowProgram(name string, flows *ebpf.Map, direction int64) (*ebpf.Program, error) {
+	return ebpf.NewProgram(&ebpf.ProgramSpec{
+		Name:         name,
+		Type:         ebpf.SchedCLS,
+		License:      "GPL",
+		Instructions: flowInstructions(flows.FD(), direction),
+	})
+}
+
+// flowInstructions returns the program counting the packets of a direction.
+//
+// Registers: R6 skb, R7 packet length, R8 transport header offset (0 if there is none), R9 TCP close flags.
+func flowInstructions(flowsFD int, direction int64) asm.Instructions {
+	// the key is oriented by the peer side: received packets come from the remote end, sent packets go to it
+	srcAddr, dstAddr := int16(keyRemote), int16(keyLocal)
+	srcPort, dstPort := int16(keyRemotePort), int16(keyLocalPort)
+	packetsOff, bytesOff := int32(valueRxPackets), int32(valueRxBytes)
+	if direction == initiatorEgress {
+		srcAddr, dstAddr = dstAddr, srcAddr
+		srcPort, dstPort = dstPort, srcPort
+		packetsOff, bytesOff = valueTxPackets, valueTxBytes
+	}
+
+	insns := asm.Instructions{
+		asm.Mov.Reg(asm.R6, asm.R1),
+		asm.LoadMem(asm.R7, asm.R6, 0, asm.Word), // skb->len
+		asm.Mov.Imm(asm.R8, 0),
+		asm.Mov.Imm(asm.R9, 0),
+		asm.StoreImm(asm.R10, stackKey, 0, asm.DWord),
+		asm.StoreImm(asm.R10, stackKey+8, 0, asm.DWord),
+		asm.StoreImm(asm.R10, stackKey+16, 0, asm.DWord),
+		asm.StoreImm(asm.R10, stackKey+24, 0, asm.DWord),
+		asm.StoreImm(asm.R10, stackKey+32, 0, asm.DWord),
+	}
+
+	// IP version
+	insns = append(insns, loadBytesImm(0, stackHeader, 1, "pass")...)
+	insns = append(insns,
+		asm.LoadMem(asm.R0, asm.R10, stackHeader, asm.Byte),
+		asm.RSh.Imm(asm.R0, 4),
+		asm.JEq.Imm(asm.R0, 4, "ipv4"),
+		asm.JEq.Imm(asm.R0, 6, "ipv6"),
+		asm.Ja.Label("pass"),
+	)
+
+	// IPv4: the transport header follows the options, fragments but the first have none
+	ipv4 := loadBytesImm(0, stackHeader, 20, "pass")
+	ipv4[0] = ipv4[0].WithSymbol("ipv4")
+	insns = append(insns, ipv4...)
+	insns = append(insns,
+		asm.LoadMem(asm.R8, asm.R10, stackHeader, asm.Byte),
+		asm.And.Imm(asm.R8, 0x0f),
+		asm.LSh.Imm(asm.R8, 2),
+		asm.JLT.Imm(asm.R8, 20, "pass"),
+		asm.LoadMem(asm.R0, asm.R10, stackHeader+6, asm.Byte),
+		asm.And.Imm(asm.R0, 0x1f),
+		asm.LoadMem(asm.R1, asm.R10, stackHeader+7, asm.Byte),
+		asm.Or.Reg(asm.R0, asm.R1),
+		asm.JEq.Imm(asm.R0, 0, "ipv4_addrs"),
+		asm.Mov.Imm(asm.R8, 0),
+		asm.LoadMem(asm.R0, asm.R10, stackHeader+9, asm.Byte).WithSymbol("ipv4_addrs"),
+		asm.StoreMem(asm.R10, keyProto, asm.R0, asm.Byte),
+		asm.StoreImm(asm.R10, keyRemote+10, 0xffff, asm.Half),
+		asm.StoreImm(asm.R10, keyLocal+10, 0xffff, asm.Half),
+		asm.LoadMem(asm.R0, asm.R10, stackHeader+12, asm.Word),
+		asm.StoreMem(asm.R10, srcAddr+12, asm.R0, asm.Word),
+		asm.LoadMem(asm.R0, asm.R10, stackHeader+16, asm.Word),
+		asm.StoreMem(asm.R10, dstAddr+12, asm.R0, asm.Word),
+		asm.Ja.Label("l4"),
+	)
+
+	// IPv6: extension headers aren't followed, their flows are counted without ports
+	ipv6 := loadBytesImm(0, stackHeader, 40, "pass")
+	ipv6[0] = ipv6[0].WithSymbol("ipv6")
+	insns = append(insns, ipv6...)
+	insns = append(insns,
+		asm.Mov.Imm(asm.R8, 40),
+		asm.LoadMem(asm.R0, asm.R10, stackHeader+6, asm.Byte),
+		asm.StoreMem(asm.R10, keyProto, asm.R0, asm.Byte),
+		asm.LoadMem(asm.R0, asm.R10, stackHeader+8, asm.DWord),
+		asm.StoreMem(asm.R10, srcAddr, asm.R0, asm.DWord),
+		asm.LoadMem(asm.R0, asm.R10, stackHeader+16, asm.DWord),
+		asm.StoreMem(asm.R10, srcAddr+8, asm.R0, asm.DWord),
+		asm.LoadMem(asm.R0, asm.R10, stackHeader+24, asm.DWord),
+		asm.StoreMem(asm.R10, dstAddr, asm.R0, asm.DWord),
+		asm.LoadMem(asm.R0, asm.R10, stackHeader+32, asm.DWord),
+		asm.StoreMem(asm.R10, dstAddr+8, asm.R0, asm.DWord),
+	)
+
+	// transport header
+	insns = append(insns,
+		asm.JEq.Imm(asm.R8, 0, "lookup").WithSymbol("l4"),
+		asm.LoadMem(asm.R0, asm.R10, keyProto, asm.Byte),
+		asm.JEq.Imm(asm.R0, 6, "tcp"),
+		asm.JEq.Imm(asm.R0, 17, "ports"),
+		asm.JEq.Imm(asm.R0, 132, "ports"),
+		asm.JEq.Imm(asm.R0, 1, "icmp"),
+		asm.JEq.Imm(asm.R0, 58, "icmp"),
+		asm.Ja.Label("lookup"),
+	)
+
+	tcp := loadBytesReg(asm.R8, stackL4, 14, "lookup")
+	tcp[0] = tcp[0].WithSymbol("tcp")
+	insns = append(insns, tcp...)
+	insns = append(insns,
+		asm.LoadMem(asm.R9, asm.R10, stackL4+13, asm.Byte),
+		asm.And.Imm(asm.R9, tcpFlagsCloseMask),
+		asm.Ja.Label("copy_ports"),
+	)
+
+	ports := loadBytesReg(asm.R8, stackL4, 4, "lookup")
+	ports[0] = ports[0].WithSymbol("ports")
+	insns = append(insns, ports...)
+	insns = append(insns,
+		asm.LoadMem(asm.R0, asm.R10, stackL4, asm.Half).WithSymbol("copy_ports"),
+		asm.StoreMem(asm.R10, srcPort, asm.R0, asm.Half),
+		asm.LoadMem(asm.R0, asm.R10, stackL4+2, asm.Half),
+		asm.StoreMem(asm.R10, dstPort, asm.R0, asm.Half),
+		asm.Ja.Label("lookup"),
+	)
+
+	icmp := loadBytesReg(asm.R8, stackL4, 2, "lookup")
+	icmp[0] = icmp[0].WithSymbol("icmp")
+	insns = append(insns, icmp...)
+	insns = append(insns,
+		asm.LoadMem(asm.R0, asm.R10, stackL4, asm.Byte),
+		// echo replies belong to the flow of the request: ICMP 0 -> 8, ICMPv6 129 -> 128
+		asm.JNE.Imm(asm.R0, 0, "icmp_v6_reply"),
+		asm.Mov.Imm(asm.R0, 8),
+		asm.Ja.Label("icmp_store"),
+		asm.JNE.Imm(asm.R0, 129, "icmp_store").WithSymbol("icmp_v6_reply"),
+		asm.Mov.Imm(asm.R0, 128),
+		asm.StoreMem(asm.R10, keyRemotePort, asm.R0, asm.Byte).WithSymbol("icmp_store"),
+		asm.LoadMem(asm.R0, asm.R10, stackL4+1, asm.Byte),
+		asm.StoreMem(asm.R10, keyRemotePort+1, asm.R0, asm.Byte),
+	)
+
+	// count the packet in the existing entry
+	insns = append(insns,
+		asm.LoadMapPtr(asm.R1, flowsFD).WithSymbol("lookup"),
+		asm.Mov.Reg(asm.R2, asm.R10),
+		asm.Add.Imm(asm.R2, stackKey),
+		asm.FnMapLookupElem.Call(),
+		asm.JEq.Imm(asm.R0, 0, "create"),
+		asm.Mov.Imm(asm.R1, 1),
+		asm.Mov.Reg(asm.R2, asm.R0),
+		asm.Add.Imm(asm.R2, packetsOff),
+		asm.StoreXAdd(asm.R2, asm.R1, asm.DWord),
+		asm.Mov.Reg(asm.R2, asm.R0),
+		asm.Add.Imm(asm.R2, bytesOff),
+		asm.StoreXAdd(asm.R2, asm.R7, asm.DWord),
+		asm.JEq.Imm(asm.R9, 0, "touch"),
+		asm.StoreImm(asm.R0, valueClosed, 1, asm.Byte),
+		asm.Mov.Reg(asm.R8, asm.R0).WithSymbol("touch"),
+		asm.FnKtimeGetNs.Call(),
+		asm.StoreMem(asm.R8, valueLastSeen, asm.R0, asm.DWord),
+		asm.Ja.Label("pass"),
+	)
+
+	// or create the entry of a new flow
+	insns = append(insns,
+		asm.StoreImm(asm.R10, stackValue, 0, asm.DWord).WithSymbol("create"),
+		asm.StoreImm(asm.R10, stackValue+8, 0, asm.DWord),
+		asm.StoreImm(asm.R10, stackValue+16, 0, asm.DWord),
+		asm.StoreImm(asm.R10, stackValue+24, 0, asm.DWord),
+		asm.StoreImm(asm.R10, stackValue+48, 0, asm.DWord),
+		asm.StoreImm(asm.R10, stackValue+int16(packetsOff), 1, asm.DWord),
+		asm.StoreMem(asm.R10, stackValue+int16(bytesOff), asm.R7, asm.DWord),
+		asm.StoreImm(asm.R10, stackValue+valueInitiator, direction, asm.Byte),
+		asm.StoreMem(asm.R10, stackValue+valueClosed, asm.R9, asm.Byte),
+		asm.FnKtimeGetNs.Call(),
+		asm.StoreMem(asm.R10, stackValue+valueFirstSeen, asm.R0, asm.DWord),
+		asm.StoreMem(asm.R10, stackValue+valueLastSeen, asm.R0, asm.DWord),
+		asm.LoadMapPtr(asm.R1, flowsFD),
+		asm.Mov.Reg(asm.R2, asm.R10),
+		asm.Add.Imm(asm.R2, stackKey),
+		asm.Mov.Reg(asm.R3, asm.R10),
+		asm.Add.Imm(asm.R3, stackValue),
+		asm.Mov.Imm(asm.R4, bpfNoExist),
+		asm.FnMapUpdateElem.Call(),
+	)
+
+	return append(insns,
+		asm.Mov.Imm(asm.R0, tcActUnspec).WithSymbol("pass"),
+		asm.Return(),
+	)
+}
+
+// loadBytesImm copies n bytes at the offset of the network header to the stack, it jumps to fail if the packet is
+// too short
+func loadBytesImm(offset int32, to int16, n int32, fail string) asm.Instructions {
+	insns := asm.Instructions{asm.Mov.Imm(asm.R2, offset)}
+	insns = append(insns, loadBytesCall(to, n)...)
+	return append(insns, asm.JNE.Imm(asm.R0, 0, fail))
+}
+
+// loadBytesReg copies n bytes at the offset in the register, relative to the network header, to the stack
+func loadBytesReg(offset asm.Register, to int16, n int32, fail string) asm.Instructions {
+	insns := asm.Instructions{asm.Mov.Reg(asm.R2, offset)}
+	insns = append(insns, loadBytesCall(to, n)...)
+	return append(insns, asm.JNE.Imm(asm.R0, 0, fail))
+}
+
+// loadBytesCall calls bpf_skb_load_bytes_relative with the offset in R2
+func loadBytesCall(to int16, n int32) asm.Instructions {
+	return asm.Instructions{
+		asm.Mov.Reg(asm.R1, asm.R6),
+		asm.Mov.Reg(asm.R3, asm.R10),
+		asm.Add.Imm(asm.R3, int32(to)),
+		asm.Mov.Imm(asm.R4, n),
+		asm.Mov.Imm(asm.R5, bpfHdrStartNet),
+		asm.FnSkbLoadBytesRelative.Call(),
+	}
+}
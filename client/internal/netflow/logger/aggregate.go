@@ -0,0 +1,149 @@
+package logger
+
+import (
+	"encoding/binary"
+	"net/netip"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/netflow/types"
+)
+
+// aggregationInterval is how often aggregated flows are moved to the store
+const aggregationInterval = 10 * time.Second
+
+// sampled reports whether the flow is kept. The decision depends on the flow ID only,
+// so the start and end events of a flow are kept or dropped together.
+func sampled(flowID uuid.UUID, rate uint32) bool {
+	if rate <= 1 {
+		return true
+	}
+	return binary.BigEndian.Uint32(flowID[12:])%rate == 0
+}
+
+type aggregateKey struct {
+	flowType       types.Type
+	direction      types.Direction
+	protocol       types.Protocol
+	sourceIP       netip.Addr
+	destIP         netip.Addr
+	sourcePort     uint16
+	destPort       uint16
+	icmpType       uint8
+	icmpCode       uint8
+	ruleID         string
+	sourceResource string
+	destResource   string
+}
+
+// aggregator merges the events of an interval into flows of networks and port ranges
+type aggregator struct {
+	wgIfaceNet netip.Prefix
+	flows      map[aggregateKey]*types.Event
+	overflowed int
+}
+
+func newAggregator(wgIfaceNet netip.Prefix) *aggregator {
+	return &aggregator{
+		wgIfaceNet: wgIfaceNet,
+		flows:      make(map[aggregateKey]*types.Event),
+	}
+}
+
+// add merges the event into its aggregated flow
+func (a *aggregator) add(event *types.Event, sampling types.FlowSampling) {
+	key := aggregateKey{
+		flowType:       event.Type,
+		direction:      event.Direction,
+		protocol:       event.Protocol,
+		sourceIP:       a.maskAddr(event.SourceIP, sampling),
+		destIP:         a.maskAddr(event.DestIP, sampling),
+		sourcePort:     portRangeStart(event.SourcePort, sampling.PortRangeSize),
+		destPort:       portRangeStart(event.DestPort, sampling.PortRangeSize),
+		icmpType:       event.ICMPType,
+		icmpCode:       event.ICMPCode,
+		ruleID:         string(event.RuleID),
+		sourceResource: string(event.SourceResourceID),
+		destResource:   string(event.DestResourceID),
+	}
+
+	flow, ok := a.flows[key]
+	if !ok && sampling.MaxFlows > 0 && uint32(len(a.flows)) >= sampling.MaxFlows {
+		a.overflowed++
+		key = aggregateKey{flowType: event.Type, direction: event.Direction, protocol: event.Protocol}
+		flow, ok = a.flows[key]
+	}
+
+	if !ok {
+		flow = &types.Event{
+			ID:        uuid.New(),
+			Timestamp: event.Timestamp,
+			EventFields: types.EventFields{
+				FlowID:           uuid.New(),
+				Type:             key.flowType,
+				RuleID:           event.RuleID,
+				Direction:        key.direction,
+				Protocol:         key.protocol,
+				SourceIP:         key.sourceIP,
+				DestIP:           key.destIP,
+				SourceResourceID: event.SourceResourceID,
+				DestResourceID:   event.DestResourceID,
+				SourcePort:       key.sourcePort,
+				DestPort:         key.destPort,
+				ICMPType:         key.icmpType,
+				ICMPCode:         key.icmpCode,
+			},
+		}
+		a.flows[key] = flow
+	}
+
+	flow.RxPackets += event.RxPackets
+	flow.TxPackets += event.TxPackets
+	flow.RxBytes += event.RxBytes
+	flow.TxBytes += event.TxBytes
+}
+
+// flush returns the aggregated flows and starts a new interval
+func (a *aggregator) flush() []*types.Event {
+	if a.overflowed > 0 {
+		log.Debugf("merged %d flow events into overflow flows, the flow cap was reached", a.overflowed)
+		a.overflowed = 0
+	}
+
+	events := make([]*types.Event, 0, len(a.flows))
+	for _, flow := range a.flows {
+		events = append(events, flow)
+	}
+	clear(a.flows)
+	return events
+}
+
+// maskAddr reduces addresses outside the NetBird network to their aggregation network
+func (a *aggregator) maskAddr(addr netip.Addr, sampling types.FlowSampling) netip.Addr {
+	if !addr.IsValid() || a.wgIfaceNet.Contains(addr) {
+		return addr
+	}
+
+	bits := int(sampling.IPv4PrefixLength)
+	if addr.Is6() {
+		bits = int(sampling.IPv6PrefixLength)
+	}
+	if bits == 0 || bits >= addr.BitLen() {
+		return addr
+	}
+
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return addr
+	}
+	return prefix.Addr()
+}
+
+func portRangeStart(port, size uint16) uint16 {
+	if size <= 1 {
+		return port
+	}
+	return port - port%size
+}
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/internal/netflow/types"
+)
+
+func TestSampled(t *testing.T) {
+	flowID := uuid.New()
+	assert.True(t, sampled(flowID, 0), "zero keeps all flows")
+	assert.True(t, sampled(flowID, 1), "one keeps all flows")
+	assert.Equal(t, sampled(flowID, 10), sampled(flowID, 10), "the decision is stable per flow")
+
+	kept := 0
+	for i := 0; i < 10000; i++ {
+		if sampled(uuid.New(), 10) {
+			kept++
+		}
+	}
+	assert.InDelta(t, 1000, kept, 200)
+}
+
+func newTestEvent(src, dst string, srcPort, dstPort uint16, rxBytes uint64) *types.Event {
+	return &types.Event{
+		ID: uuid.New(),
+		EventFields: types.EventFields{
+			FlowID:     uuid.New(),
+			Type:       types.TypeEnd,
+			Direction:  types.Egress,
+			Protocol:   types.TCP,
+			SourceIP:   netip.MustParseAddr(src),
+			DestIP:     netip.MustParseAddr(dst),
+			SourcePort: srcPort,
+			DestPort:   dstPort,
+			RxBytes:    rxBytes,
+		},
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	agg := newAggregator(netip.MustParsePrefix("100.64.0.0/16"))
+	sampling := types.FlowSampling{IPv4PrefixLength: 24, PortRangeSize: 1024}
+
+	agg.add(newTestEvent("100.64.0.1", "203.0.113.10", 50000, 443, 100), sampling)
+	agg.add(newTestEvent("100.64.0.1", "203.0.113.20", 50100, 443, 200), sampling)
+	agg.add(newTestEvent("100.64.0.2", "203.0.113.30", 50200, 443, 300), sampling)
+	agg.add(newTestEvent("100.64.0.1", "198.51.100.1", 50300, 443, 400), sampling)
+
+	events := agg.flush()
+	require.Len(t, events, 3)
+
+	byDest := make(map[string]*types.Event)
+	for _, event := range events {
+		byDest[event.SourceIP.String()+"-"+event.DestIP.String()] = event
+	}
+
+	merged := byDest["100.64.0.1-203.0.113.0"]
+	require.NotNil(t, merged, "destinations are aggregated per /24, peer addresses are kept")
+	assert.Equal(t, uint64(300), merged.RxBytes)
+	assert.Equal(t, uint16(49152), merged.SourcePort, "ports carry the start of the range")
+	assert.Equal(t, uint16(0), merged.DestPort)
+
+	assert.NotNil(t, byDest["100.64.0.2-203.0.113.0"])
+	assert.NotNil(t, byDest["100.64.0.1-198.51.100.0"])
+
+	assert.Empty(t, agg.flush(), "flush starts a new interval")
+}
+
+func TestAggregator_MaxFlows(t *testing.T) {
+	agg := newAggregator(netip.MustParsePrefix("100.64.0.0/16"))
+	sampling := types.FlowSampling{MaxFlows: 2}
+
+	agg.add(newTestEvent("100.64.0.1", "203.0.113.1", 50000, 443, 1), sampling)
+	agg.add(newTestEvent("100.64.0.1", "203.0.113.2", 50000, 443, 2), sampling)
+	agg.add(newTestEvent("100.64.0.1", "203.0.113.3", 50000, 443, 4), sampling)
+	agg.add(newTestEvent("100.64.0.1", "203.0.113.4", 50000, 443, 8), sampling)
+
+	events := agg.flush()
+	require.Len(t, events, 3, "two flows and the overflow flow")
+
+	var overflow *types.Event
+	for _, event := range events {
+		if !event.DestIP.IsValid() {
+			overflow = event
+		}
+	}
+	require.NotNil(t, overflow)
+	assert.Equal(t, uint64(12), overflow.RxBytes)
+	assert.Equal(t, types.TCP, overflow.Protocol)
+}
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"net/netip"
+
+	"github.com/netbirdio/netbird/client/internal/netflow/types"
+	"github.com/netbirdio/netbird/dns"
+)
+
+var (
+	// pseudonymPrefix4 is the reserved 240.0.0.0/4 network the IPv4 pseudonyms are taken from
+	pseudonymPrefix4 = netip.MustParsePrefix("240.0.0.0/4")
+	// pseudonymPrefix6 is the discard-only 100::/64 network the IPv6 pseudonyms are taken from
+	pseudonymPrefix6 = netip.MustParsePrefix("100::/64")
+)
+
+// UpdateDNSFilter updates the filter of the DNS queries and the DNS traffic flows
+func (l *Logger) UpdateDNSFilter(filter types.DNSFilter) {
+	l.dnsFilter.Store(&filter)
+}
+
+// applyDNSFilter drops the DNS queries outside the domains of the client's rule and replaces the client address of
+// the DNS queries and flows with its pseudonym if the rule hashes the client IPs. It reports whether the event is
+// kept.
+func (l *Logger) applyDNSFilter(event *types.Event) bool {
+	filter := l.dnsFilter.Load()
+	if filter == nil {
+		return true
+	}
+
+	query := event.DNSQuery != nil
+	if !query && !isDNSFlow(&event.EventFields) {
+		return true
+	}
+
+	rule := filter.Rule(l.peerKeys(filter, event.SourceIP)...)
+	if query && !rule.MatchesDomain(event.DNSQuery.Name) {
+		return false
+	}
+	if rule.HashClientIPs {
+		event.SourceIP = l.pseudonym(event.SourceIP)
+	}
+	return true
+}
+
+// peerKeys returns the keys of the peer with the address a per-peer rule may be configured for
+func (l *Logger) peerKeys(filter *types.DNSFilter, addr netip.Addr) []string {
+	if len(filter.Peers) == 0 || !addr.IsValid() {
+		return nil
+	}
+
+	keys := []string{addr.String()}
+	if l.statusRecorder == nil {
+		return keys
+	}
+	if state, ok := l.statusRecorder.PeerStateByIP(addr.String()); ok {
+		keys = append(keys, state.PubKey, state.FQDN)
+	}
+	return keys
+}
+
+// pseudonym returns the address standing in for the client address, a keyed hash of it in a reserved network of the
+// same family. The key is generated when the logger is created, the pseudonyms change with a restart of the client.
+func (l *Logger) pseudonym(addr netip.Addr) netip.Addr {
+	if !addr.IsValid() {
+		return addr
+	}
+
+	mac := hmac.New(sha256.New, l.pseudonymKey)
+	mac.Write(addr.AsSlice())
+	sum := mac.Sum(nil)
+
+	if addr.Unmap().Is4() {
+		base := pseudonymPrefix4.Addr().As4()
+		return netip.AddrFrom4([4]byte{base[0] | sum[0]&0x0f, sum[1], sum[2], sum[3]})
+	}
+
+	ip := pseudonymPrefix6.Addr().As16()
+	copy(ip[8:], sum[:8])
+	return netip.AddrFrom16(ip)
+}
+
+// isDNSFlow reports whether the flow is DNS traffic, the traffic covered by the DNS collection setting
+func isDNSFlow(event *types.EventFields) bool {
+	return event.Protocol == types.UDP &&
+		(event.DestPort == 53 || event.DestPort == dns.ForwarderClientPort || event.DestPort == dns.ForwarderServerPort)
+}
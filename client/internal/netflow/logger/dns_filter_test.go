@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/client/internal/netflow/types"
+)
+
+func TestLogger_ApplyDNSFilter(t *testing.T) {
+	l := New(nil, netip.MustParsePrefix("100.64.0.0/16"))
+	client := netip.MustParseAddr("100.64.0.2")
+	query := func(name string) *types.Event {
+		return &types.Event{EventFields: types.EventFields{
+			SourceIP: client,
+			DNSQuery: &types.DNSQuery{Name: name},
+		}}
+	}
+
+	event := query("private.example.com")
+	assert.True(t, l.applyDNSFilter(event), "every query is collected without a filter")
+	assert.Equal(t, client, event.SourceIP)
+
+	l.UpdateDNSFilter(types.DNSFilter{
+		DNSFilterRule: types.DNSFilterRule{
+			ExcludeDomains: []string{"private.example.com"},
+			HashClientIPs:  true,
+		},
+		Peers: map[string]types.DNSFilterRule{"100.64.0.3": {}},
+	})
+
+	assert.False(t, l.applyDNSFilter(query("private.example.com")))
+
+	event = query("example.com")
+	assert.True(t, l.applyDNSFilter(event))
+	assert.True(t, pseudonymPrefix4.Contains(event.SourceIP), "the client address is replaced with its pseudonym")
+	assert.Equal(t, l.pseudonym(client), event.SourceIP, "the pseudonym of a client is stable")
+	assert.NotEqual(t, l.pseudonym(netip.MustParseAddr("100.64.0.4")), event.SourceIP)
+
+	flow := &types.Event{EventFields: types.EventFields{
+		Protocol: types.UDP,
+		SourceIP: client,
+		DestPort: 53,
+	}}
+	assert.True(t, l.applyDNSFilter(flow))
+	assert.Equal(t, event.SourceIP, flow.SourceIP, "the DNS flows carry the pseudonym of the queries")
+
+	other := &types.Event{EventFields: types.EventFields{
+		Protocol: types.TCP,
+		SourceIP: client,
+		DestPort: 443,
+	}}
+	assert.True(t, l.applyDNSFilter(other))
+	assert.Equal(t, client, other.SourceIP, "the flows other than DNS are left alone")
+
+	event = query("private.example.com")
+	event.SourceIP = netip.MustParseAddr("100.64.0.3")
+	assert.True(t, l.applyDNSFilter(event), "the rule of the peer replaces the global rule")
+	assert.Equal(t, netip.MustParseAddr("100.64.0.3"), event.SourceIP)
+
+	assert.True(t, pseudonymPrefix6.Contains(l.pseudonym(netip.MustParseAddr("fd00::1"))))
+}
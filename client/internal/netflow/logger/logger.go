@@ -2,6 +2,8 @@ package logger
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
 	"net/netip"
 	"sync"
 	"sync/atomic"
@@ -13,7 +15,6 @@ import (
 	"github.com/netbirdio/netbird/client/internal/netflow/store"
 	"github.com/netbirdio/netbird/client/internal/netflow/types"
 	"github.com/netbirdio/netbird/client/internal/peer"
-	"github.com/netbirdio/netbird/dns"
 )
 
 type rcvChan chan *types.EventFields
@@ -26,18 +27,37 @@ type Logger struct {
 	wgIfaceNet         netip.Prefix
 	dnsCollection      atomic.Bool
 	exitNodeCollection atomic.Bool
-	Store              types.Store
+	sampling           atomic.Pointer[types.FlowSampling]
+	filter             atomic.Pointer[types.FlowFilter]
+	dnsFilter          atomic.Pointer[types.DNSFilter]
+	listener           atomic.Pointer[types.FlowListener]
+	// pseudonymKey keys the hashes of the client addresses of the DNS collection
+	pseudonymKey []byte
+	// recent keeps the latest flows for the local queries
+	recent *store.Ring
+	Store  types.Store
 }
 
 func New(statusRecorder *peer.Status, wgIfaceIPNet netip.Prefix) *Logger {
+	pseudonymKey := make([]byte, sha256.Size)
+	if _, err := rand.Read(pseudonymKey); err != nil {
+		log.Errorf("failed to generate the key of the DNS client pseudonyms: %v", err)
+	}
+
 	return &Logger{
 		statusRecorder: statusRecorder,
 		wgIfaceNet:     wgIfaceIPNet,
+		pseudonymKey:   pseudonymKey,
+		recent:         store.NewRing(store.DefaultRingSize),
 		Store:          store.NewMemoryStore(),
 	}
 }
 
 func (l *Logger) StoreEvent(flowEvent types.EventFields) {
+	if listener := l.listener.Load(); listener != nil && !flowEvent.IsAuditLog() {
+		(*listener)(flowEvent)
+	}
+
 	if !l.enabled.Load() {
 		return
 	}
@@ -54,6 +74,15 @@ func (l *Logger) StoreEvent(flowEvent types.EventFields) {
 	}
 }
 
+// SetListener sets the listener of the traffic flow events, it is called even if the logger isn't enabled
+func (l *Logger) SetListener(listener types.FlowListener) {
+	if listener == nil {
+		l.listener.Store(nil)
+		return
+	}
+	l.listener.Store(&listener)
+}
+
 func (l *Logger) Enable() {
 	go l.startReceiver()
 }
@@ -72,12 +101,24 @@ func (l *Logger) startReceiver() {
 	l.rcvChan.Store(&c)
 	l.enabled.Store(true)
 
+	agg := newAggregator(l.wgIfaceNet)
+	ticker := time.NewTicker(aggregationInterval)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			log.Info("flow Memory store receiver stopped")
 			return
+		case <-ticker.C:
+			l.storeAggregated(agg)
 		case eventFields := <-c:
+			// the events of the audit logs are neither sampled nor aggregated
+			isAuditLog := eventFields.IsAuditLog()
+			if !isAuditLog && eventFields.Type == types.TypeEnd {
+				// the route counters cover every flow, they are taken before the sampling and the filter
+				l.statusRecorder.AddRouteTransfer(eventFields.SourceIP, eventFields.DestIP, eventFields.RxBytes, eventFields.TxBytes)
+			}
 			id := uuid.New()
 			event := types.Event{
 				ID:          id,
@@ -96,9 +137,31 @@ func (l *Logger) startReceiver() {
 				event.DestResourceID, isDestExitNode = l.statusRecorder.CheckRoutes(event.DestIP)
 			}
 
-			if l.shouldStore(eventFields, isSrcExitNode || isDestExitNode) {
-				l.Store.StoreEvent(&event)
+			if !l.applyDNSFilter(&event) {
+				continue
+			}
+
+			// the local queries see every flow, the sampling and the filter only scope what is sent
+			l.recent.Add(&event)
+
+			sampling := l.getSampling()
+			if !isAuditLog && !sampled(eventFields.FlowID, sampling.Rate) {
+				continue
+			}
+
+			if !l.shouldStore(eventFields, isSrcExitNode || isDestExitNode) {
+				continue
+			}
+
+			if !isAuditLog && !l.getFilter().Matches(eventFields, l.wgIfaceNet) {
+				continue
+			}
+
+			if !isAuditLog && sampling.Aggregates() {
+				agg.add(&event, sampling)
+				continue
 			}
+			l.Store.StoreEvent(&event)
 		}
 	}
 }
@@ -131,15 +194,56 @@ func (l *Logger) DeleteEvents(ids []uuid.UUID) {
 	l.Store.DeleteEvents(ids)
 }
 
+// QueryEvents returns the recently recorded flows selected by the query, newest first. The flows are recorded
+// before the sampling and the filter of the flow config.
+func (l *Logger) QueryEvents(query types.EventQuery) []*types.Event {
+	return l.recent.Query(query)
+}
+
 func (l *Logger) UpdateConfig(dnsCollection, exitNodeCollection bool) {
 	l.dnsCollection.Store(dnsCollection)
 	l.exitNodeCollection.Store(exitNodeCollection)
 }
 
+// UpdateSampling updates the sampling and aggregation of the events
+func (l *Logger) UpdateSampling(sampling types.FlowSampling) {
+	l.sampling.Store(&sampling)
+}
+
+func (l *Logger) getSampling() types.FlowSampling {
+	if sampling := l.sampling.Load(); sampling != nil {
+		return *sampling
+	}
+	return types.FlowSampling{}
+}
+
+// UpdateFilter updates the scope of the collected flows
+func (l *Logger) UpdateFilter(filter types.FlowFilter) {
+	l.filter.Store(&filter)
+}
+
+func (l *Logger) getFilter() types.FlowFilter {
+	if filter := l.filter.Load(); filter != nil {
+		return *filter
+	}
+	return types.FlowFilter{}
+}
+
+// storeAggregated moves the aggregated flows of the interval to the store
+func (l *Logger) storeAggregated(agg *aggregator) {
+	for _, event := range agg.flush() {
+		l.Store.StoreEvent(event)
+	}
+}
+
 func (l *Logger) shouldStore(event *types.EventFields, isExitNode bool) bool {
+	// the audit logs are enabled on their own, the dns collection setting covers the DNS traffic flows
+	if event.IsAuditLog() {
+		return true
+	}
+
 	// check dns collection
-	if !l.dnsCollection.Load() && event.Protocol == types.UDP &&
-		(event.DestPort == 53 || event.DestPort == dns.ForwarderClientPort || event.DestPort == dns.ForwarderServerPort) {
+	if !l.dnsCollection.Load() && isDNSFlow(event) {
 		return false
 	}
 
@@ -65,3 +65,27 @@ func TestStore(t *testing.T) {
 		t.Errorf("didn't match any event")
 	}
 }
+
+func TestQueryEvents(t *testing.T) {
+	logger := logger.New(nil, netip.Prefix{})
+	// flows that aren't sent are still recorded for the local queries
+	logger.UpdateSampling(types.FlowSampling{Rate: 1000})
+	logger.Enable()
+	defer logger.Close()
+
+	wait := func() { time.Sleep(10 * time.Millisecond) }
+	wait()
+	for i := 0; i < 10; i++ {
+		logger.StoreEvent(types.EventFields{FlowID: uuid.New(), Type: types.TypeStart, Protocol: types.TCP, DestPort: uint16(8000 + i)})
+	}
+	logger.StoreEvent(types.EventFields{FlowID: uuid.New(), Type: types.TypeStart, Protocol: types.UDP, DestPort: 53})
+	wait()
+
+	if got := len(logger.QueryEvents(types.EventQuery{Protocol: types.TCP})); got != 10 {
+		t.Errorf("expected 10 TCP flows, got %d", got)
+	}
+	events := logger.QueryEvents(types.EventQuery{Limit: 1})
+	if len(events) != 1 || events[0].DestPort != 53 {
+		t.Errorf("expected the newest flow first, got %v", events)
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"net/netip"
-	"runtime"
 	"sync"
 	"time"
 
@@ -13,7 +12,6 @@ import (
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
-	"github.com/netbirdio/netbird/client/internal/netflow/conntrack"
 	"github.com/netbirdio/netbird/client/internal/netflow/logger"
 	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
 	"github.com/netbirdio/netbird/client/internal/peer"
@@ -31,6 +29,12 @@ type Manager struct {
 	receiverClient *client.GRPCClient
 	publicKey      []byte
 	cancel         context.CancelFunc
+	deviceClass    nftypes.DeviceClass
+	statusRecorder *peer.Status
+	// ingressForwards are kept apart from the flow config, they change with the network map
+	ingressForwards []nftypes.IngressForward
+	// listening keeps the connection tracker running for the flow listener while the flows aren't collected
+	listening bool
 }
 
 // NewManager creates a new netflow manager
@@ -41,15 +45,12 @@ func NewManager(iface nftypes.IFaceMapper, publicKey []byte, statusRecorder *pee
 	}
 	flowLogger := logger.New(statusRecorder, prefix)
 
-	var ct nftypes.ConnTracker
-	if runtime.GOOS == "linux" && iface != nil && !iface.IsUserspaceBind() {
-		ct = conntrack.New(flowLogger, iface)
-	}
-
 	return &Manager{
-		logger:    flowLogger,
-		conntrack: ct,
-		publicKey: publicKey,
+		logger:         flowLogger,
+		conntrack:      newConnTracker(flowLogger, iface),
+		publicKey:      publicKey,
+		deviceClass:    nftypes.DeviceClassClient,
+		statusRecorder: statusRecorder,
 	}
 }
 
@@ -97,6 +98,10 @@ func (m *Manager) resetClient() error {
 	}
 	log.Infof("flow client configured to connect to %s", m.flowConfig.URL)
 
+	if m.statusRecorder != nil {
+		m.statusRecorder.UpdateFlowURL(m.flowConfig.URL)
+		flowClient.SetConnStateListener(m.statusRecorder)
+	}
 	m.receiverClient = flowClient
 
 	if m.cancel != nil {
@@ -125,12 +130,16 @@ func (m *Manager) disableFlow() error {
 		m.cancel()
 	}
 
-	if m.conntrack != nil {
+	if m.conntrack != nil && !m.listening {
 		m.conntrack.Stop()
 	}
 
 	m.logger.Close()
 
+	if m.statusRecorder != nil {
+		m.statusRecorder.UpdateFlowURL("")
+	}
+
 	if m.receiverClient == nil {
 		return nil
 	}
@@ -166,6 +175,8 @@ func (m *Manager) Update(update *nftypes.FlowConfig) error {
 	}
 
 	m.logger.UpdateConfig(update.DNSCollection, update.ExitNodeCollection)
+	m.updateSampling()
+	m.updateFilter()
 
 	changed := previous != nil && update.Enabled != previous.Enabled
 	if update.Enabled {
@@ -181,12 +192,83 @@ func (m *Manager) Update(update *nftypes.FlowConfig) error {
 	return m.disableFlow()
 }
 
+// SetDeviceClass selects the sampling settings of the device class, e.g. exit nodes generate far more flows than clients
+func (m *Manager) SetDeviceClass(class nftypes.DeviceClass) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.deviceClass == class {
+		return
+	}
+	log.Debugf("flow device class changed from %s to %s", m.deviceClass, class)
+	m.deviceClass = class
+	m.updateSampling()
+}
+
+// updateSampling applies the sampling settings of the current device class, callers must hold the lock
+func (m *Manager) updateSampling() {
+	var sampling nftypes.FlowSampling
+	if m.flowConfig != nil {
+		sampling = m.flowConfig.Sampling[m.deviceClass]
+	}
+	m.logger.UpdateSampling(sampling)
+}
+
+// SetIngressForwards sets the ingress port forwards of the peer, the ingress gateway filter collects their flows
+func (m *Manager) SetIngressForwards(forwards []nftypes.IngressForward) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	m.ingressForwards = forwards
+	m.updateFilter()
+}
+
+// SetDNSFilter sets the locally configured filter of the DNS collection. It applies on top of the DNS collection
+// setting of the flow config, which enables the collection of the DNS traffic flows.
+func (m *Manager) SetDNSFilter(filter nftypes.DNSFilter) {
+	m.logger.UpdateDNSFilter(filter)
+}
+
+// SetFlowListener sets the listener of the local flow events. The connection tracker keeps running for the listener
+// while the flows aren't collected.
+func (m *Manager) SetFlowListener(listener nftypes.FlowListener) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	m.logger.SetListener(listener)
+	m.listening = listener != nil
+
+	if m.conntrack == nil || (m.flowConfig != nil && m.flowConfig.Enabled) {
+		return
+	}
+	if !m.listening {
+		m.conntrack.Stop()
+		return
+	}
+	if err := m.conntrack.Start(false); err != nil {
+		log.Warnf("failed to start conntrack for the flow listener: %v", err)
+	}
+}
+
+// updateFilter applies the filter of the flow config with the current ingress forwards, callers must hold the lock
+func (m *Manager) updateFilter() {
+	var filter nftypes.FlowFilter
+	if m.flowConfig != nil {
+		filter = m.flowConfig.Filter
+	}
+	filter.IngressForwards = m.ingressForwards
+	m.logger.UpdateFilter(filter)
+}
+
 // Close cleans up all resources
 func (m *Manager) Close() {
 	m.mux.Lock()
 	if err := m.disableFlow(); err != nil {
 		log.Warnf("failed to disable flow manager: %v", err)
 	}
+	if m.conntrack != nil && m.listening {
+		m.conntrack.Stop()
+	}
 	m.mux.Unlock()
 
 	m.shutdownWg.Wait()
@@ -269,6 +351,24 @@ func toProtoEvent(publicKey []byte, event *nftypes.Event) *proto.FlowEvent {
 		},
 	}
 
+	if q := event.DNSQuery; q != nil {
+		protoEvent.FlowFields.DnsInfo = &proto.DNSInfo{
+			QueryName: q.Name,
+			QueryType: uint32(q.Type),
+			Rcode:     uint32(q.Rcode),
+			Cached:    q.Cached,
+		}
+	}
+
+	if session := event.SSHSession; session != nil {
+		protoEvent.FlowFields.SshInfo = &proto.SSHInfo{
+			User:      session.User,
+			JwtUser:   session.JWTUser,
+			Command:   session.Command,
+			Recording: session.Recording,
+		}
+	}
+
 	if event.Protocol == nftypes.ICMP {
 		protoEvent.FlowFields.ConnectionInfo = &proto.FlowFields_IcmpInfo{
 			IcmpInfo: &proto.ICMPInfo{
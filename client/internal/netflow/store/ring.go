@@ -0,0 +1,72 @@
+package store
+
+import (
+	"sync"
+
+	"github.com/netbirdio/netbird/client/internal/netflow/types"
+)
+
+// DefaultRingSize is the number of the recent events kept for the local queries
+const DefaultRingSize = 4096
+
+// Ring keeps the most recent events, the oldest are overwritten once it is full
+type Ring struct {
+	mux    sync.Mutex
+	events []*types.Event
+	next   int
+	full   bool
+}
+
+// NewRing returns a ring buffer of the size
+func NewRing(size int) *Ring {
+	return &Ring{events: make([]*types.Event, size)}
+}
+
+// Add records the event
+func (r *Ring) Add(event *types.Event) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if len(r.events) == 0 {
+		return
+	}
+	r.events[r.next] = event
+	r.next = (r.next + 1) % len(r.events)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Query returns the events selected by the query, newest first
+func (r *Ring) Query(query types.EventQuery) []*types.Event {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	count := r.next
+	if r.full {
+		count = len(r.events)
+	}
+
+	var events []*types.Event
+	for i := 1; i <= count; i++ {
+		event := r.events[(r.next-i+len(r.events))%len(r.events)]
+		if !query.Matches(event) {
+			continue
+		}
+		events = append(events, event)
+		if query.Limit > 0 && len(events) == query.Limit {
+			break
+		}
+	}
+	return events
+}
+
+// Reset drops the recorded events
+func (r *Ring) Reset() {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	clear(r.events)
+	r.next = 0
+	r.full = false
+}
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/client/internal/netflow/types"
+)
+
+func TestRing(t *testing.T) {
+	ring := NewRing(3)
+	assert.Empty(t, ring.Query(types.EventQuery{}))
+
+	start := time.Now()
+	peerA := netip.MustParseAddr("100.64.0.2")
+	peerB := netip.MustParseAddr("100.64.0.3")
+	for i, fields := range []types.EventFields{
+		{Protocol: types.TCP, SourceIP: peerA, DestPort: 22},
+		{Protocol: types.UDP, SourceIP: peerA, DestPort: 53},
+		{Protocol: types.TCP, SourceIP: peerB, DestPort: 443},
+		{Protocol: types.TCP, DestIP: peerA, SourcePort: 22},
+	} {
+		ring.Add(&types.Event{EventFields: fields, Timestamp: start.Add(time.Duration(i) * time.Second)})
+	}
+
+	all := ring.Query(types.EventQuery{})
+	assert.Len(t, all, 3, "the oldest event is overwritten")
+	assert.Equal(t, uint16(22), all[0].SourcePort, "newest first")
+	assert.Equal(t, uint16(53), all[2].DestPort)
+
+	assert.Len(t, ring.Query(types.EventQuery{Addrs: []netip.Addr{peerA}}), 2)
+	assert.Len(t, ring.Query(types.EventQuery{Port: 22}), 1)
+	assert.Len(t, ring.Query(types.EventQuery{Protocol: types.TCP}), 2)
+	assert.Len(t, ring.Query(types.EventQuery{Since: start.Add(2 * time.Second)}), 2)
+	assert.Len(t, ring.Query(types.EventQuery{Until: start.Add(2 * time.Second)}), 2)
+
+	limited := ring.Query(types.EventQuery{Limit: 1})
+	assert.Len(t, limited, 1)
+	assert.Equal(t, all[0], limited[0])
+
+	ring.Reset()
+	assert.Empty(t, ring.Query(types.EventQuery{}))
+}
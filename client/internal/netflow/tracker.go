@@ -0,0 +1,80 @@
+package netflow
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/netflow/bpftrack"
+	"github.com/netbirdio/netbird/client/internal/netflow/conntrack"
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+)
+
+// envEnableEBPFFlowCapture counts the flows of kernel WireGuard interfaces with eBPF programs instead of conntrack events
+const envEnableEBPFFlowCapture = "NB_ENABLE_EBPF_FLOW_CAPTURE"
+
+// newConnTracker returns the tracker for the flows of kernel WireGuard interfaces. The userspace firewall keeps its
+// own trackers for userspace interfaces, so flows are never counted twice.
+func newConnTracker(flowLogger nftypes.FlowLogger, iface nftypes.IFaceMapper) nftypes.ConnTracker {
+	if runtime.GOOS != "linux" || iface == nil || iface.IsUserspaceBind() {
+		return nil
+	}
+
+	var ct nftypes.ConnTracker = conntrack.New(flowLogger, iface)
+	if !isEBPFFlowCaptureEnabled() {
+		return ct
+	}
+
+	var bt nftypes.ConnTracker = bpftrack.New(flowLogger, iface)
+	if bt == nil {
+		return ct
+	}
+	return &fallbackTracker{primary: bt, fallback: ct, active: bt}
+}
+
+func isEBPFFlowCaptureEnabled() bool {
+	val := os.Getenv(envEnableEBPFFlowCapture)
+	if val == "" {
+		return false
+	}
+	enabled, err := strconv.ParseBool(val)
+	if err != nil {
+		log.Warnf("failed to parse %s: %v", envEnableEBPFFlowCapture, err)
+		return false
+	}
+	return enabled
+}
+
+// fallbackTracker starts the primary tracker and switches to the fallback for good once the primary fails to start,
+// e.g. if the kernel lacks eBPF support
+type fallbackTracker struct {
+	primary  nftypes.ConnTracker
+	fallback nftypes.ConnTracker
+	active   nftypes.ConnTracker
+}
+
+// Start starts the active tracker
+func (t *fallbackTracker) Start(enableCounters bool) error {
+	if t.active == t.primary {
+		err := t.primary.Start(enableCounters)
+		if err == nil {
+			return nil
+		}
+		log.Warnf("failed to start eBPF flow capture, falling back to conntrack: %v", err)
+		t.active = t.fallback
+	}
+	return t.active.Start(enableCounters)
+}
+
+// Stop stops the active tracker
+func (t *fallbackTracker) Stop() {
+	t.active.Stop()
+}
+
+// Close closes both trackers
+func (t *fallbackTracker) Close() error {
+	return errors.Join(t.primary.Close(), t.fallback.Close())
+}
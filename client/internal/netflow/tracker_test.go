@@ -0,0 +1,69 @@
+package netflow
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTracker struct {
+	startErr error
+	starts   int
+	stops    int
+	closed   bool
+}
+
+func (f *fakeTracker) Start(bool) error {
+	f.starts++
+	return f.startErr
+}
+
+func (f *fakeTracker) Stop() {
+	f.stops++
+}
+
+func (f *fakeTracker) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestFallbackTracker(t *testing.T) {
+	t.Run("primary starts", func(t *testing.T) {
+		primary, fallback := &fakeTracker{}, &fakeTracker{}
+		tracker := &fallbackTracker{primary: primary, fallback: fallback, active: primary}
+
+		require.NoError(t, tracker.Start(true))
+		tracker.Stop()
+
+		assert.Equal(t, 1, primary.starts)
+		assert.Equal(t, 1, primary.stops)
+		assert.Zero(t, fallback.starts)
+		assert.Zero(t, fallback.stops)
+	})
+
+	t.Run("primary fails", func(t *testing.T) {
+		primary, fallback := &fakeTracker{startErr: errors.New("no eBPF")}, &fakeTracker{}
+		tracker := &fallbackTracker{primary: primary, fallback: fallback, active: primary}
+
+		require.NoError(t, tracker.Start(true))
+		tracker.Stop()
+		require.NoError(t, tracker.Start(true))
+
+		assert.Equal(t, 1, primary.starts, "failed primary must not be retried")
+		assert.Zero(t, primary.stops)
+		assert.Equal(t, 2, fallback.starts)
+		assert.Equal(t, 1, fallback.stops)
+
+		require.NoError(t, tracker.Close())
+		assert.True(t, primary.closed)
+		assert.True(t, fallback.closed)
+	})
+}
+
+func TestNewConnTracker_UserspaceBind(t *testing.T) {
+	t.Setenv(envEnableEBPFFlowCapture, "true")
+	assert.Nil(t, newConnTracker(nil, &mockIFaceMapper{isUserspaceBind: true}))
+	assert.Nil(t, newConnTracker(nil, nil))
+}
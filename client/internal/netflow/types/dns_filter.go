@@ -0,0 +1,89 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/netbirdio/netbird/shared/management/domain"
+)
+
+// DNSFilterRule scopes and anonymizes the DNS collection of the queries of a client
+type DNSFilterRule struct {
+	// IncludeDomains collects only the queries for these domains and their subdomains, empty collects all queries
+	IncludeDomains []string
+	// ExcludeDomains leaves out the queries for these domains and their subdomains, it takes precedence over
+	// IncludeDomains
+	ExcludeDomains []string
+	// HashClientIPs replaces the address of the client in the DNS queries and DNS traffic flows with a keyed hash of
+	// it, so the clients can be told apart but not identified
+	HashClientIPs bool
+}
+
+// DNSFilter is the locally configured filter of the DNS collection, it applies to the DNS query log and the DNS
+// traffic flows. The domain filters only apply to the queries, the flows don't carry the domain.
+type DNSFilter struct {
+	DNSFilterRule
+	// Peers replaces the rule for the queries of peers, keyed by the WireGuard public key, the FQDN or the NetBird IP
+	// of the querying peer
+	Peers map[string]DNSFilterRule
+}
+
+// Validate checks the domains of the rules
+func (f DNSFilter) Validate() error {
+	if err := f.DNSFilterRule.validate(); err != nil {
+		return err
+	}
+	for peer, rule := range f.Peers {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("DNS filter of peer %s: %w", peer, err)
+		}
+	}
+	return nil
+}
+
+// Rule returns the rule of the first peer key with a rule of its own, or the global rule
+func (f DNSFilter) Rule(peerKeys ...string) DNSFilterRule {
+	for _, key := range peerKeys {
+		if rule, ok := f.Peers[key]; ok && key != "" {
+			return rule
+		}
+	}
+	return f.DNSFilterRule
+}
+
+// MatchesDomain reports whether the queries for the domain are collected
+func (r DNSFilterRule) MatchesDomain(name string) bool {
+	name = normalizeDomain(name)
+	if slices.ContainsFunc(r.ExcludeDomains, func(d string) bool { return inDomain(name, d) }) {
+		return false
+	}
+	return len(r.IncludeDomains) == 0 || slices.ContainsFunc(r.IncludeDomains, func(d string) bool { return inDomain(name, d) })
+}
+
+func (r DNSFilterRule) validate() error {
+	for _, d := range slices.Concat(r.IncludeDomains, r.ExcludeDomains) {
+		if normalizeDomain(strings.TrimPrefix(d, "*.")) == "" {
+			return fmt.Errorf("empty domain")
+		}
+		if _, err := domain.FromString(strings.TrimPrefix(d, "*.")); err != nil {
+			return fmt.Errorf("invalid domain %q: %w", d, err)
+		}
+	}
+	return nil
+}
+
+// inDomain reports whether the name is the domain or one of its subdomains, a leading wildcard label is ignored.
+// Internationalized domains are compared in their punycode form, as they are queried.
+func inDomain(name, d string) bool {
+	d = strings.TrimPrefix(d, "*.")
+	if ascii, err := domain.FromString(d); err == nil {
+		d = string(ascii)
+	}
+	d = normalizeDomain(d)
+	return name == d || strings.HasSuffix(name, "."+d)
+}
+
+func normalizeDomain(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".")
+}
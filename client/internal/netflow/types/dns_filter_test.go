@@ -0,0 +1,53 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDNSFilterRule_MatchesDomain(t *testing.T) {
+	rule := DNSFilterRule{
+		IncludeDomains: []string{"example.com", "*.corp.internal", "bücher.de"},
+		ExcludeDomains: []string{"private.example.com"},
+	}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"example.com", true},
+		{"WWW.Example.com.", true},
+		{"notexample.com", false},
+		{"private.example.com", false},
+		{"a.private.example.com", false},
+		{"host.corp.internal", true},
+		{"xn--bcher-kva.de", true},
+		{"example.org", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, rule.MatchesDomain(tt.name), tt.name)
+	}
+
+	assert.True(t, DNSFilterRule{}.MatchesDomain("example.org"), "an empty rule collects every query")
+}
+
+func TestDNSFilter_Rule(t *testing.T) {
+	peerRule := DNSFilterRule{HashClientIPs: true}
+	filter := DNSFilter{
+		DNSFilterRule: DNSFilterRule{IncludeDomains: []string{"example.com"}},
+		Peers:         map[string]DNSFilterRule{"peer.netbird.cloud": peerRule},
+	}
+
+	assert.Equal(t, peerRule, filter.Rule("100.64.0.2", "pubkey", "peer.netbird.cloud"))
+	assert.Equal(t, filter.DNSFilterRule, filter.Rule("100.64.0.3", "other", ""))
+	assert.Equal(t, filter.DNSFilterRule, filter.Rule())
+}
+
+func TestDNSFilter_Validate(t *testing.T) {
+	assert.NoError(t, DNSFilter{DNSFilterRule: DNSFilterRule{IncludeDomains: []string{"*.example.com"}}}.Validate())
+	assert.Error(t, DNSFilter{DNSFilterRule: DNSFilterRule{ExcludeDomains: []string{""}}}.Validate())
+	assert.Error(t, DNSFilter{Peers: map[string]DNSFilterRule{
+		"peer": {IncludeDomains: []string{"."}},
+	}}.Validate())
+}
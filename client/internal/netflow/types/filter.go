@@ -0,0 +1,93 @@
+package types
+
+import (
+	"net/netip"
+	"slices"
+)
+
+// FlowFilter scopes the collected traffic flows, the zero value collects all flows.
+// The events of the audit logs aren't filtered.
+type FlowFilter struct {
+	// RoutedOnly collects the flows with one end outside the NetBird network
+	RoutedOnly bool
+	// IngressGatewayOnly collects the flows of IngressForwards. With RoutedOnly set as well, the flows matching
+	// either are collected.
+	IngressGatewayOnly bool
+	// ExcludedPeerPorts leaves out the peer to peer flows from or to these ports
+	ExcludedPeerPorts []PortRange
+	// PeerPrefixes collects only flows with one end in these networks, empty collects the flows of all peers
+	PeerPrefixes []netip.Prefix
+	// IngressForwards are the port forwards of the peer, they are set from the forwarding rules and not from the
+	// flow config
+	IngressForwards []IngressForward
+}
+
+// PortRange is a range of ports of a protocol, Start equals End for single ports
+type PortRange struct {
+	// Protocol is the protocol of the ports, ProtocolUnknown matches all protocols
+	Protocol Protocol
+	Start    uint16
+	End      uint16
+}
+
+// Contains reports whether the port of the protocol is in the range
+func (r PortRange) Contains(protocol Protocol, port uint16) bool {
+	if r.Protocol != ProtocolUnknown && r.Protocol != protocol {
+		return false
+	}
+	return port >= r.Start && port <= r.End
+}
+
+// IngressForward is an ingress port forward of the peer
+type IngressForward struct {
+	// Ports are the forwarded ports on the address of the peer
+	Ports []PortRange
+	// TranslatedAddress is the address the traffic is forwarded to
+	TranslatedAddress netip.Addr
+	// TranslatedPorts are the ports the traffic is forwarded to
+	TranslatedPorts []PortRange
+}
+
+// Matches reports whether the flow is collected, peerNet is the NetBird network
+func (f FlowFilter) Matches(event *EventFields, peerNet netip.Prefix) bool {
+	srcPeer := peerNet.Contains(event.SourceIP)
+	dstPeer := peerNet.Contains(event.DestIP)
+	peerToPeer := srcPeer && dstPeer
+
+	if f.RoutedOnly || f.IngressGatewayOnly {
+		routed := f.RoutedOnly && !peerToPeer
+		ingress := f.IngressGatewayOnly && f.isIngressFlow(event, peerNet)
+		if !routed && !ingress {
+			return false
+		}
+	}
+
+	if peerToPeer && slices.ContainsFunc(f.ExcludedPeerPorts, func(r PortRange) bool {
+		return r.Contains(event.Protocol, event.SourcePort) || r.Contains(event.Protocol, event.DestPort)
+	}) {
+		return false
+	}
+
+	if len(f.PeerPrefixes) > 0 && !slices.ContainsFunc(f.PeerPrefixes, func(prefix netip.Prefix) bool {
+		return prefix.Contains(event.SourceIP) || prefix.Contains(event.DestIP)
+	}) {
+		return false
+	}
+
+	return true
+}
+
+// isIngressFlow reports whether the flow is destined to a forwarded port of the peer or to a forward target.
+// Depending on the firewall the flow is seen before or after the translation.
+func (f FlowFilter) isIngressFlow(event *EventFields, peerNet netip.Prefix) bool {
+	return slices.ContainsFunc(f.IngressForwards, func(forward IngressForward) bool {
+		if peerNet.Contains(event.DestIP) && slices.ContainsFunc(forward.Ports, func(r PortRange) bool {
+			return r.Contains(event.Protocol, event.DestPort)
+		}) {
+			return true
+		}
+		return event.DestIP == forward.TranslatedAddress && slices.ContainsFunc(forward.TranslatedPorts, func(r PortRange) bool {
+			return r.Contains(event.Protocol, event.DestPort)
+		})
+	})
+}
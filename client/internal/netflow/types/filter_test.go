@@ -0,0 +1,92 @@
+package types
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlowFilter_Matches(t *testing.T) {
+	peerNet := netip.MustParsePrefix("100.64.0.0/16")
+	flow := func(protocol Protocol, src, dst string, srcPort, dstPort uint16) *EventFields {
+		return &EventFields{
+			Protocol:   protocol,
+			SourceIP:   netip.MustParseAddr(src),
+			DestIP:     netip.MustParseAddr(dst),
+			SourcePort: srcPort,
+			DestPort:   dstPort,
+		}
+	}
+
+	ingress := []IngressForward{{
+		Ports:             []PortRange{{Protocol: TCP, Start: 8080, End: 8080}},
+		TranslatedAddress: netip.MustParseAddr("100.64.0.9"),
+		TranslatedPorts:   []PortRange{{Protocol: TCP, Start: 80, End: 80}},
+	}}
+
+	peerToPeer := flow(TCP, "100.64.0.1", "100.64.0.2", 50000, 22)
+	routed := flow(TCP, "100.64.0.1", "10.0.0.5", 50000, 443)
+	mdns := flow(UDP, "100.64.0.1", "100.64.0.2", 5353, 5353)
+	ping := flow(ICMP, "100.64.0.1", "100.64.0.2", 0, 0)
+	ingressFlow := flow(TCP, "203.0.113.1", "100.64.0.1", 40000, 8080)
+	translatedFlow := flow(TCP, "203.0.113.1", "100.64.0.9", 40000, 80)
+
+	tests := []struct {
+		name   string
+		filter FlowFilter
+		event  *EventFields
+		want   bool
+	}{
+		{name: "no filter", event: peerToPeer, want: true},
+		{name: "routed only drops peer to peer", filter: FlowFilter{RoutedOnly: true}, event: peerToPeer, want: false},
+		{name: "routed only keeps routed", filter: FlowFilter{RoutedOnly: true}, event: routed, want: true},
+		{name: "ingress only drops routed", filter: FlowFilter{IngressGatewayOnly: true, IngressForwards: ingress}, event: routed, want: false},
+		{name: "ingress only keeps forwarded port", filter: FlowFilter{IngressGatewayOnly: true, IngressForwards: ingress}, event: ingressFlow, want: true},
+		{name: "ingress only keeps translated flow", filter: FlowFilter{IngressGatewayOnly: true, IngressForwards: ingress}, event: translatedFlow, want: true},
+		{name: "ingress only without forwards", filter: FlowFilter{IngressGatewayOnly: true}, event: ingressFlow, want: false},
+		{name: "routed or ingress", filter: FlowFilter{RoutedOnly: true, IngressGatewayOnly: true, IngressForwards: ingress}, event: routed, want: true},
+		{
+			name:   "excluded peer port",
+			filter: FlowFilter{ExcludedPeerPorts: []PortRange{{Protocol: UDP, Start: 5353, End: 5353}}},
+			event:  mdns,
+			want:   false,
+		},
+		{
+			name:   "excluded port of another protocol",
+			filter: FlowFilter{ExcludedPeerPorts: []PortRange{{Protocol: TCP, Start: 5353, End: 5353}}},
+			event:  mdns,
+			want:   true,
+		},
+		{
+			name:   "excluded protocol",
+			filter: FlowFilter{ExcludedPeerPorts: []PortRange{{Protocol: ICMP, Start: 0, End: 65535}}},
+			event:  ping,
+			want:   false,
+		},
+		{
+			name:   "excluded ports keep routed flows",
+			filter: FlowFilter{ExcludedPeerPorts: []PortRange{{Protocol: TCP, Start: 443, End: 443}}},
+			event:  routed,
+			want:   true,
+		},
+		{
+			name:   "peer prefix match",
+			filter: FlowFilter{PeerPrefixes: []netip.Prefix{netip.MustParsePrefix("100.64.0.2/32")}},
+			event:  peerToPeer,
+			want:   true,
+		},
+		{
+			name:   "peer prefix mismatch",
+			filter: FlowFilter{PeerPrefixes: []netip.Prefix{netip.MustParsePrefix("100.64.1.0/24")}},
+			event:  peerToPeer,
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Matches(tt.event, peerNet))
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package types
+
+import (
+	"net/netip"
+	"slices"
+	"time"
+)
+
+// EventQuery selects the recently recorded flow events, the zero value selects all events
+type EventQuery struct {
+	// Addrs selects the events from or to any of the addresses
+	Addrs []netip.Addr
+	// Port selects the events from or to the port
+	Port uint16
+	// Protocol selects the events of the protocol, ProtocolUnknown selects all protocols
+	Protocol Protocol
+	// Since and Until limit the time range of the events, zero values leave the range open
+	Since time.Time
+	Until time.Time
+	// Limit is the maximum number of the newest events returned, zero returns all
+	Limit int
+}
+
+// Matches reports whether the query selects the event
+func (q EventQuery) Matches(event *Event) bool {
+	if len(q.Addrs) > 0 && !slices.Contains(q.Addrs, event.SourceIP) && !slices.Contains(q.Addrs, event.DestIP) {
+		return false
+	}
+	if q.Port != 0 && event.SourcePort != q.Port && event.DestPort != q.Port {
+		return false
+	}
+	if q.Protocol != ProtocolUnknown && event.Protocol != q.Protocol {
+		return false
+	}
+	if !q.Since.IsZero() && event.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && event.Timestamp.After(q.Until) {
+		return false
+	}
+	return true
+}
@@ -19,6 +19,7 @@ const (
 	ICMP            = Protocol(1)
 	TCP             = Protocol(6)
 	UDP             = Protocol(17)
+	ICMPv6          = Protocol(58)
 	SCTP            = Protocol(132)
 )
 
@@ -30,6 +31,8 @@ func (p Protocol) String() string {
 		return "TCP"
 	case 17:
 		return "UDP"
+	case 58:
+		return "ICMPv6"
 	case 132:
 		return "SCTP"
 	default:
@@ -44,6 +47,8 @@ const (
 	TypeStart
 	TypeEnd
 	TypeDrop
+	// TypeAudit is traffic that matched an audit rule, it was let through but would be dropped once enforced
+	TypeAudit
 )
 
 type Direction int
@@ -89,6 +94,33 @@ type EventFields struct {
 	TxPackets        uint64
 	RxBytes          uint64
 	TxBytes          uint64
+	// DNSQuery is set for the events of the DNS query log
+	DNSQuery *DNSQuery
+	// SSHSession is set for the events of the SSH session recording
+	SSHSession *SSHSession
+}
+
+// IsAuditLog reports whether the event belongs to the DNS query log or the SSH session recording
+func (e *EventFields) IsAuditLog() bool {
+	return e.DNSQuery != nil || e.SSHSession != nil
+}
+
+// DNSQuery is a query answered by the DNS server of the client
+type DNSQuery struct {
+	Name   string
+	Type   uint16
+	Rcode  int
+	Cached bool
+}
+
+// SSHSession is a session on the embedded SSH server
+type SSHSession struct {
+	User    string
+	JWTUser string
+	// Command is empty for interactive shells
+	Command string
+	// Recording is the path of the output recording, empty if the output isn't recorded
+	Recording string
 }
 
 type FlowConfig struct {
@@ -100,6 +132,40 @@ type FlowConfig struct {
 	TokenSignature     string
 	DNSCollection      bool
 	ExitNodeCollection bool
+	// Sampling holds the sampling settings per device class
+	Sampling map[DeviceClass]FlowSampling
+	// Filter scopes the collected flows
+	Filter FlowFilter
+}
+
+// DeviceClass groups peers by the flow volume they generate
+type DeviceClass string
+
+const (
+	DeviceClassClient      DeviceClass = "client"
+	DeviceClassRoutingPeer DeviceClass = "routing-peer"
+	DeviceClassExitNode    DeviceClass = "exit-node"
+)
+
+// FlowSampling reduces the number of flow events a peer sends
+type FlowSampling struct {
+	// Rate keeps one of Rate flows, zero and one keep all flows. All events of a flow are kept or dropped together.
+	Rate uint32
+	// IPv4PrefixLength aggregates the IPv4 addresses outside the NetBird network into networks of this size,
+	// zero keeps the addresses
+	IPv4PrefixLength uint8
+	// IPv6PrefixLength is the IPv6 counterpart of IPv4PrefixLength
+	IPv6PrefixLength uint8
+	// PortRangeSize aggregates the ports into ranges of this size, the events carry the first port of the range
+	PortRangeSize uint16
+	// MaxFlows caps the distinct flows per aggregation interval, further flows are merged into one overflow
+	// flow per type, direction and protocol. Zero means no cap.
+	MaxFlows uint32
+}
+
+// Aggregates reports whether events are merged into aggregated flows
+func (s FlowSampling) Aggregates() bool {
+	return s.IPv4PrefixLength > 0 || s.IPv6PrefixLength > 0 || s.PortRangeSize > 1 || s.MaxFlows > 0
 }
 
 type FlowManager interface {
@@ -109,8 +175,20 @@ type FlowManager interface {
 	Close()
 	// GetLogger returns a flow logger
 	GetLogger() FlowLogger
+	// SetDeviceClass selects the sampling settings of the device class
+	SetDeviceClass(class DeviceClass)
+	// SetIngressForwards sets the ingress port forwards whose flows are the ingress gateway flows
+	SetIngressForwards(forwards []IngressForward)
+	// SetDNSFilter sets the locally configured filter of the DNS collection
+	SetDNSFilter(filter DNSFilter)
+	// SetFlowListener sets the listener of the local flow events, nil removes it
+	SetFlowListener(listener FlowListener)
 }
 
+// FlowListener is called with every traffic flow event, whether the flows are collected or not. It is called on the
+// packet path and must not block.
+type FlowListener func(event EventFields)
+
 type FlowLogger interface {
 	// StoreEvent stores a flow event
 	StoreEvent(flowEvent EventFields)
@@ -118,12 +196,22 @@ type FlowLogger interface {
 	GetEvents() []*Event
 	// DeleteEvents deletes events from the store
 	DeleteEvents([]uuid.UUID)
+	// QueryEvents returns the recently recorded events selected by the query, newest first
+	QueryEvents(query EventQuery) []*Event
 	// Close closes the logger
 	Close()
 	// Enable enables the flow logger receiver
 	Enable()
 	// UpdateConfig updates the flow manager configuration
 	UpdateConfig(dnsCollection, exitNodeCollection bool)
+	// UpdateSampling updates the sampling and aggregation of the events
+	UpdateSampling(sampling FlowSampling)
+	// UpdateFilter updates the scope of the collected flows
+	UpdateFilter(filter FlowFilter)
+	// UpdateDNSFilter updates the filter of the DNS queries and the DNS traffic flows
+	UpdateDNSFilter(filter DNSFilter)
+	// SetListener sets the listener of the traffic flow events, nil removes it
+	SetListener(listener FlowListener)
 }
 
 type Store interface {
@@ -0,0 +1,216 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	cProto "github.com/netbirdio/netbird/client/proto"
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+const (
+	// networkMapCacheDelay is how long the engine waits for the first network map from management before it
+	// boots from the cached one
+	networkMapCacheDelay = 20 * time.Second
+	// managementSyncRetryInterval is the pause between Sync attempts while the engine runs from the cached map
+	managementSyncRetryInterval = 30 * time.Second
+)
+
+var errNetworkMapCacheMismatch = errors.New("cached network map belongs to another peer configuration")
+
+// NetworkMapCacheState keeps the latest network map and Netbird config received from management, so the engine
+// can bring up the peer connections while the management Sync stream can't be established.
+// It deliberately doesn't implement Cleanup, the cache has to survive restarts.
+type NetworkMapCacheState struct {
+	// PeerKey and ManagementURL tie the cache to the peer it was received for, the state file is shared by profiles
+	PeerKey       string `json:"peer_key"`
+	ManagementURL string `json:"management_url"`
+	// SyncResponse is the protojson encoded network map and Netbird config
+	SyncResponse json.RawMessage `json:"sync_response"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+func (s *NetworkMapCacheState) Name() string {
+	return "network_map_cache_state"
+}
+
+// cacheSyncResponse merges the update into the cached sync response and persists it with the next state save.
+// The cached network map is also the base of the network map deltas. The caller must hold syncMsgMux.
+func (e *Engine) cacheSyncResponse(update *mgmProto.SyncResponse) {
+	if e.syncCache == nil {
+		e.syncCache = &mgmProto.SyncResponse{}
+	}
+	if update.GetNetbirdConfig() != nil {
+		e.syncCache.NetbirdConfig = update.GetNetbirdConfig()
+	}
+	if update.GetNetworkMap() == nil {
+		return
+	}
+	e.syncCache.NetworkMap = update.GetNetworkMap()
+	e.syncCache.Checks = update.GetChecks()
+
+	if e.stateManager == nil {
+		return
+	}
+
+	data, err := protojson.Marshal(e.syncCache)
+	if err != nil {
+		log.Warnf("failed to encode the network map cache: %v", err)
+		return
+	}
+
+	state := &NetworkMapCacheState{
+		PeerKey:       e.config.WgPrivateKey.PublicKey().String(),
+		ManagementURL: e.statusRecorder.GetManagementState().URL,
+		SyncResponse:  data,
+		UpdatedAt:     time.Now().UTC(),
+	}
+	if err := e.stateManager.UpdateState(state); err != nil {
+		log.Warnf("failed to update the network map cache: %v", err)
+	}
+}
+
+// loadSyncResponseCache returns the cached sync response if it was received for the running peer configuration
+func (e *Engine) loadSyncResponseCache() (*mgmProto.SyncResponse, time.Time, error) {
+	state := &NetworkMapCacheState{}
+	if err := e.stateManager.LoadState(state); err != nil {
+		return nil, time.Time{}, fmt.Errorf("load state: %w", err)
+	}
+	cached, ok := e.stateManager.GetState(state).(*NetworkMapCacheState)
+	if !ok || cached == nil || len(cached.SyncResponse) == 0 {
+		return nil, time.Time{}, nil
+	}
+
+	if cached.PeerKey != e.config.WgPrivateKey.PublicKey().String() ||
+		cached.ManagementURL != e.statusRecorder.GetManagementState().URL {
+		return nil, time.Time{}, errNetworkMapCacheMismatch
+	}
+
+	update := &mgmProto.SyncResponse{}
+	if err := protojson.Unmarshal(cached.SyncResponse, update); err != nil {
+		return nil, time.Time{}, fmt.Errorf("decode sync response: %w", err)
+	}
+
+	// a map received before an address change would program stale routes and peers
+	if update.GetNetworkMap().GetPeerConfig().GetAddress() != e.config.WgAddr {
+		return nil, time.Time{}, errNetworkMapCacheMismatch
+	}
+
+	return update, cached.UpdatedAt, nil
+}
+
+// restoreFilteringFromCache applies the firewall rules of the cached network map right after the firewall was
+// created, so the flows permitted before a restart keep working while management is unreachable (fail-static).
+// The first network map received from management replaces them. The caller must hold syncMsgMux.
+func (e *Engine) restoreFilteringFromCache() {
+	if e.acl == nil || e.stateManager == nil {
+		return
+	}
+
+	update, updatedAt, err := e.loadSyncResponseCache()
+	if err != nil {
+		log.Debugf("not restoring the cached firewall rules: %v", err)
+		return
+	}
+	networkMap := update.GetNetworkMap()
+	if networkMap == nil {
+		return
+	}
+
+	e.acl.ApplyFiltering(networkMap, toDNSFeatureFlag(networkMap))
+	log.Infof("restored the firewall rules of the network map cached at %s", updatedAt.Format(time.RFC3339))
+}
+
+// bootFromNetworkMapCache applies the cached network map if management hasn't sent one within the delay. The peer
+// connections are established in the offline catalogue mode until management is reachable again.
+func (e *Engine) bootFromNetworkMapCache() {
+	select {
+	case <-e.ctx.Done():
+		return
+	case <-time.After(networkMapCacheDelay):
+	}
+
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+
+	if e.ctx.Err() != nil || e.mgmSynced || e.offlineCatalogue {
+		return
+	}
+
+	update, updatedAt, err := e.loadSyncResponseCache()
+	if err != nil {
+		log.Warnf("not using the cached network map: %v", err)
+		return
+	}
+	if update == nil {
+		log.Debugf("no cached network map, waiting for management")
+		return
+	}
+
+	log.Warnf("no network map received from management within %s, running from the network map cached at %s",
+		networkMapCacheDelay, updatedAt.Format(time.RFC3339))
+
+	if err := e.runFromSyncResponse(update); err != nil {
+		log.Errorf("failed to apply the cached network map: %v", err)
+		return
+	}
+
+	e.statusRecorder.PublishEvent(
+		cProto.SystemEvent_WARNING,
+		cProto.SystemEvent_NETWORK,
+		"Running from the cached network map",
+		"Management is unreachable, peers are connected with the last known network map until it is reachable again.",
+		map[string]string{"serial": fmt.Sprint(update.GetNetworkMap().GetSerial()), "cached_at": updatedAt.Format(time.RFC3339)},
+	)
+}
+
+// runFromSyncResponse applies a network map that wasn't received by this engine from management. The engine runs
+// in the offline catalogue mode until management sends the first network map. The caller must hold syncMsgMux.
+func (e *Engine) runFromSyncResponse(update *mgmProto.SyncResponse) error {
+	e.offlineCatalogue = true
+	// keep the cache as the base for the Netbird config updates received before the next network map
+	e.syncCache = proto.Clone(update).(*mgmProto.SyncResponse)
+
+	return e.applySyncResponse(update, nil)
+}
+
+// leaveOfflineCatalogue prepares the engine for the first network map received from management. A map received
+// after running from the cache is always applied, even if management's serial is lower than the cached one.
+// The caller must hold syncMsgMux.
+func (e *Engine) leaveOfflineCatalogue() {
+	e.mgmSynced = true
+	if !e.offlineCatalogue {
+		return
+	}
+
+	e.offlineCatalogue = false
+	e.networkSerial = 0
+	log.Infof("management is reachable, reconciling the cached network map")
+	e.statusRecorder.PublishEvent(
+		cProto.SystemEvent_INFO,
+		cProto.SystemEvent_NETWORK,
+		"Network map reconciled with management",
+		"",
+		nil,
+	)
+}
+
+// keepRunningOffline reports whether the engine keeps running from the cached network map after the Sync stream
+// failed. Denied permissions still cancel the client, the peer has to log in again.
+func (e *Engine) keepRunningOffline(err error) bool {
+	if s, ok := gstatus.FromError(err); ok && s.Code() == codes.PermissionDenied {
+		return false
+	}
+
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+	return e.offlineCatalogue
+}
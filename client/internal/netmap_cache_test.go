@@ -0,0 +1,174 @@
+package internal
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/statemanager"
+	mgm "github.com/netbirdio/netbird/shared/management/client"
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+func newNetworkMapCacheEngine(t *testing.T, statePath string, key wgtypes.Key, wgAddr string) *Engine {
+	t.Helper()
+	stateManager := statemanager.New(statePath)
+	stateManager.RegisterState(&NetworkMapCacheState{})
+	return &Engine{
+		config:         &EngineConfig{WgPrivateKey: key, WgAddr: wgAddr},
+		statusRecorder: peer.NewRecorder("https://api.netbird.io:443"),
+		stateManager:   stateManager,
+	}
+}
+
+func TestNetworkMapCache(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	key, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+
+	e := newNetworkMapCacheEngine(t, statePath, key, "100.64.0.1/16")
+	e.cacheSyncResponse(&mgmProto.SyncResponse{
+		NetbirdConfig: &mgmProto.NetbirdConfig{Signal: &mgmProto.HostConfig{Uri: "signal.netbird.io:443"}},
+	})
+	e.cacheSyncResponse(&mgmProto.SyncResponse{
+		NetworkMap: &mgmProto.NetworkMap{
+			Serial:      7,
+			PeerConfig:  &mgmProto.PeerConfig{Address: "100.64.0.1/16"},
+			RemotePeers: []*mgmProto.RemotePeerConfig{{WgPubKey: "peer-a", AllowedIps: []string{"100.64.0.2/32"}}},
+		},
+	})
+	require.NoError(t, e.stateManager.PersistState(context.Background()))
+
+	t.Run("restored after restart", func(t *testing.T) {
+		restarted := newNetworkMapCacheEngine(t, statePath, key, "100.64.0.1/16")
+		update, updatedAt, err := restarted.loadSyncResponseCache()
+		require.NoError(t, err)
+		require.NotNil(t, update)
+		assert.False(t, updatedAt.IsZero())
+		assert.Equal(t, uint64(7), update.GetNetworkMap().GetSerial())
+		assert.Equal(t, "peer-a", update.GetNetworkMap().GetRemotePeers()[0].GetWgPubKey())
+		assert.Equal(t, "signal.netbird.io:443", update.GetNetbirdConfig().GetSignal().GetUri())
+	})
+
+	t.Run("ignored for another peer", func(t *testing.T) {
+		otherKey, err := wgtypes.GeneratePrivateKey()
+		require.NoError(t, err)
+
+		other := newNetworkMapCacheEngine(t, statePath, otherKey, "100.64.0.1/16")
+		_, _, err = other.loadSyncResponseCache()
+		assert.ErrorIs(t, err, errNetworkMapCacheMismatch)
+	})
+
+	t.Run("ignored after an address change", func(t *testing.T) {
+		moved := newNetworkMapCacheEngine(t, statePath, key, "100.64.0.9/16")
+		_, _, err := moved.loadSyncResponseCache()
+		assert.ErrorIs(t, err, errNetworkMapCacheMismatch)
+	})
+
+	t.Run("no cache", func(t *testing.T) {
+		empty := newNetworkMapCacheEngine(t, filepath.Join(t.TempDir(), "state.json"), key, "100.64.0.1/16")
+		update, _, err := empty.loadSyncResponseCache()
+		require.NoError(t, err)
+		assert.Nil(t, update)
+	})
+}
+
+type recordingACLManager struct {
+	applied []*mgmProto.NetworkMap
+}
+
+func (m *recordingACLManager) ApplyFiltering(networkMap *mgmProto.NetworkMap, _ bool) {
+	m.applied = append(m.applied, networkMap)
+}
+
+func (m *recordingACLManager) SetClockSkew(time.Duration) {}
+
+func (m *recordingACLManager) Stop() {}
+
+func TestRestoreFilteringFromCache(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	key, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+
+	e := newNetworkMapCacheEngine(t, statePath, key, "100.64.0.1/16")
+	aclManager := &recordingACLManager{}
+	e.acl = aclManager
+
+	e.restoreFilteringFromCache()
+	assert.Empty(t, aclManager.applied, "nothing is applied without a cache")
+
+	e.cacheSyncResponse(&mgmProto.SyncResponse{
+		NetworkMap: &mgmProto.NetworkMap{
+			Serial:     3,
+			PeerConfig: &mgmProto.PeerConfig{Address: "100.64.0.1/16"},
+			FirewallRules: []*mgmProto.FirewallRule{
+				{PeerIP: "100.64.0.2", Direction: mgmProto.RuleDirection_IN, Action: mgmProto.RuleAction_ACCEPT, Protocol: mgmProto.RuleProtocol_TCP, Port: "22"},
+			},
+		},
+	})
+	require.NoError(t, e.stateManager.PersistState(context.Background()))
+
+	restarted := newNetworkMapCacheEngine(t, statePath, key, "100.64.0.1/16")
+	restartedACL := &recordingACLManager{}
+	restarted.acl = restartedACL
+
+	restarted.restoreFilteringFromCache()
+	require.Len(t, restartedACL.applied, 1)
+	assert.Equal(t, uint64(3), restartedACL.applied[0].GetSerial())
+	assert.Equal(t, "22", restartedACL.applied[0].GetFirewallRules()[0].GetPort())
+
+	other, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+	otherPeer := newNetworkMapCacheEngine(t, statePath, other, "100.64.0.1/16")
+	otherACL := &recordingACLManager{}
+	otherPeer.acl = otherACL
+
+	otherPeer.restoreFilteringFromCache()
+	assert.Empty(t, otherACL.applied, "rules cached for another peer are not restored")
+}
+
+func TestLeaveOfflineCatalogue(t *testing.T) {
+	e := &Engine{statusRecorder: peer.NewRecorder("https://api.netbird.io:443"), networkSerial: 42}
+
+	e.leaveOfflineCatalogue()
+	assert.True(t, e.mgmSynced)
+	assert.Equal(t, uint64(42), e.networkSerial, "serial is kept if the engine didn't run from the cache")
+
+	e.offlineCatalogue = true
+	e.leaveOfflineCatalogue()
+	assert.False(t, e.offlineCatalogue)
+	assert.Zero(t, e.networkSerial, "the first map from management has to replace the cached one")
+}
+
+func TestKeepRunningOffline(t *testing.T) {
+	e := &Engine{syncMsgMux: &sync.Mutex{}}
+	unavailable := gstatus.Error(codes.Unavailable, "management is down")
+	denied := gstatus.Error(codes.PermissionDenied, "peer login expired")
+
+	assert.False(t, e.keepRunningOffline(unavailable), "without the cache the client is restarted")
+
+	e.offlineCatalogue = true
+	assert.True(t, e.keepRunningOffline(unavailable))
+	assert.False(t, e.keepRunningOffline(denied), "denied permissions always cancel the client")
+}
+
+func TestHandleSync_NetworkMapDeltaResync(t *testing.T) {
+	e := &Engine{
+		ctx:        context.Background(),
+		syncMsgMux: &sync.Mutex{},
+		syncCache:  &mgmProto.SyncResponse{NetworkMap: &mgmProto.NetworkMap{Serial: 3}},
+	}
+
+	err := e.handleSync(&mgmProto.SyncResponse{NetworkMap: &mgmProto.NetworkMap{Serial: 5, Delta: true, BaseSerial: 4}})
+	assert.ErrorIs(t, err, mgm.ErrNetworkMapResync, "a delta for another base needs a full map")
+	assert.Equal(t, uint64(3), e.syncCache.GetNetworkMap().GetSerial())
+}
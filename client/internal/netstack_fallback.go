@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/iface/netstack"
+	nbnet "github.com/netbirdio/netbird/client/net"
+	cProto "github.com/netbirdio/netbird/client/proto"
+)
+
+var errCreateWgIface = errors.New("create wg interface")
+
+// fallbackToNetstack switches the process to netstack mode if the start failed because the tunnel device couldn't
+// be created for lack of permissions, as it is common in containers. It tears down what the failed start set up
+// and reports whether the engine should be started again. The caller must hold syncMsgMux.
+func (e *Engine) fallbackToNetstack(err error) bool {
+	if !canFallbackToNetstack(err) {
+		return false
+	}
+	if e.config.DisableNetstackFallback {
+		log.Warnf("tunnel device creation is not permitted and the netstack fallback is disabled")
+		return false
+	}
+
+	reason := fmt.Sprintf("tunnel device creation not permitted: %v", err)
+	log.Warnf("%s, falling back to netstack mode", reason)
+
+	e.abortStart()
+
+	netstack.EnableFallback()
+	// the socket marks and exclusion routes depend on the mode
+	nbnet.Init()
+
+	e.statusRecorder.UpdateNetstackFallback(reason)
+	e.statusRecorder.PublishEvent(
+		cProto.SystemEvent_WARNING,
+		cProto.SystemEvent_SYSTEM,
+		"Running in netstack mode",
+		"The tunnel device could not be created for lack of permissions. NetBird runs in userspace netstack mode, "+
+			"the peers are reachable through the SOCKS5 proxy only and the host's routes and DNS are left untouched.",
+		map[string]string{"error": err.Error()},
+	)
+	return true
+}
+
+// canFallbackToNetstack reports whether err is a permission failure to create the tunnel device on a platform that
+// supports netstack mode
+func canFallbackToNetstack(err error) bool {
+	if !errors.Is(err, errCreateWgIface) || !errors.Is(err, os.ErrPermission) {
+		return false
+	}
+	if netstack.IsEnabled() {
+		return false
+	}
+
+	switch runtime.GOOS {
+	case "android", "ios":
+		return false
+	default:
+		return true
+	}
+}
+
+// abortStart stops the components a start failing on the tunnel device creation had set up, the interface itself
+// is closed already
+func (e *Engine) abortStart() {
+	if e.routeManager != nil {
+		e.routeManager.Stop(e.stateManager)
+		e.routeManager = nil
+	}
+
+	e.stopDNSServer()
+
+	if e.flowManager != nil {
+		e.flowManager.Close()
+		e.flowManager = nil
+	}
+
+	stateCtx, stateCancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer stateCancel()
+	if err := e.stateManager.Stop(stateCtx); err != nil {
+		log.Errorf("failed to stop state manager: %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/client/iface/netstack"
+	"github.com/netbirdio/netbird/client/internal/peer"
+)
+
+func TestCanFallbackToNetstack(t *testing.T) {
+	if netstack.IsEnabled() || runtime.GOOS == "android" || runtime.GOOS == "ios" {
+		t.Skip("netstack fallback is not available")
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "no error", err: nil},
+		{name: "permission denied", err: fmt.Errorf("%w: link add: %w", errCreateWgIface, syscall.EPERM), want: true},
+		{name: "access denied", err: fmt.Errorf("%w: %w", errCreateWgIface, syscall.EACCES), want: true},
+		{name: "other create error", err: fmt.Errorf("%w: %w", errCreateWgIface, syscall.EBUSY)},
+		{name: "permission denied elsewhere", err: fmt.Errorf("create firewall: %w", syscall.EPERM)},
+		{name: "unrelated", err: errors.New("boom")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, canFallbackToNetstack(tt.err))
+		})
+	}
+}
+
+func TestFallbackToNetstack_Disabled(t *testing.T) {
+	recorder := peer.NewRecorder("https://api.netbird.io:443")
+	e := &Engine{statusRecorder: recorder, config: &EngineConfig{DisableNetstackFallback: true}}
+
+	err := fmt.Errorf("%w: %w", errCreateWgIface, syscall.EPERM)
+	assert.False(t, e.fallbackToNetstack(err))
+	assert.Empty(t, recorder.GetNetstackFallback())
+	assert.False(t, netstack.IsFallback())
+}
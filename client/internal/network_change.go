@@ -0,0 +1,78 @@
+package internal
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	nbnet "github.com/netbirdio/netbird/client/net"
+)
+
+// softReconnect adapts the engine to a network change without recreating it. The WireGuard interface and the
+// WireGuard sessions are kept, the cached server reflexive addresses are dropped and every peer connection
+// restarts ICE, so the peers find new candidate pairs on the new network.
+// It reports false if the engine has to be restarted instead.
+func (e *Engine) softReconnect() bool {
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+
+	if e.ctx.Err() != nil {
+		// the engine is stopping, nothing to restart
+		return true
+	}
+
+	if e.exitNodeRouted() {
+		log.Infof("Network monitor: exit node routes depend on the previous default next hop, restart is required")
+		return false
+	}
+
+	// the host routes of the NetBird services must follow a changed default next hop
+	if e.routeManager != nil {
+		if err := e.routeManager.ProtectEndpoints(); err != nil {
+			log.Warnf("Network monitor: failed to update the host routes of the NetBird services: %v", err)
+		}
+	}
+
+	// the change might have brought up or removed local networks
+	e.updateLANBlock()
+
+	e.restartPeersICE()
+	return true
+}
+
+// restartPeersICE restarts ICE on all peer connections, so they gather the candidates of the changed network. The
+// caller must hold syncMsgMux.
+func (e *Engine) restartPeersICE() {
+	if e.udpMux != nil {
+		e.udpMux.ResetXORMappedAddrs()
+	}
+
+	for _, key := range e.peerStore.PeersPubKey() {
+		conn, ok := e.peerStore.PeerConn(key)
+		if !ok {
+			continue
+		}
+		conn.RestartICE()
+	}
+}
+
+// exitNodeRouted reports whether a selected exit node route is in use while the routes to the NetBird services and
+// peer endpoints go through exclusion routes pinned to the default next hop. A changed next hop breaks those routes.
+func (e *Engine) exitNodeRouted() bool {
+	if nbnet.AdvancedRouting() || e.routeManager == nil {
+		return false
+	}
+
+	clientRoutes := e.routeManager.GetClientRoutes()
+	if len(clientRoutes) == 0 {
+		return false
+	}
+
+	selected := e.routeManager.GetRouteSelector().FilterSelectedExitNodes(clientRoutes)
+	for _, routes := range selected {
+		for _, r := range routes {
+			if r.Network.Bits() == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"slices"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+// networkMapChanges are the parts of the network map a delta from management touched. The engine applies only
+// these parts, the untouched peers, routes and rules are left as they are. A nil value stands for a full network
+// map, everything is reconciled then.
+type networkMapChanges struct {
+	// peers are the added and changed online peers
+	peers []*mgmProto.RemotePeerConfig
+	// removedPeers are the keys of the removed peers and of the peers that went offline
+	removedPeers []string
+	peerConfig   bool
+	routes       bool
+	rules        bool
+}
+
+// deltaChanges returns the changes the delta makes to the base network map
+func deltaChanges(base, delta *mgmProto.NetworkMap) *networkMapChanges {
+	changes := &networkMapChanges{
+		peers:        delta.GetRemotePeers(),
+		removedPeers: slices.Clone(delta.GetRemovedPeers()),
+		peerConfig:   !proto.Equal(base.GetPeerConfig(), delta.GetPeerConfig()),
+		routes:       len(delta.GetRoutes()) > 0 || len(delta.GetRemovedRoutes()) > 0,
+		rules: len(delta.GetFirewallRules()) > 0 || delta.GetFirewallRulesIsEmpty() ||
+			len(delta.GetRoutesFirewallRules()) > 0 || delta.GetRoutesFirewallRulesIsEmpty() ||
+			!slices.EqualFunc(base.GetInboundExceptions(), delta.GetInboundExceptions(), func(a, b *mgmProto.FirewallRule) bool {
+				return proto.Equal(a, b)
+			}),
+	}
+	for _, p := range delta.GetOfflinePeers() {
+		changes.removedPeers = append(changes.removedPeers, p.GetWgPubKey())
+	}
+	return changes
+}
+
+func (c *networkMapChanges) peersChanged() bool {
+	return c == nil || len(c.peers) > 0 || len(c.removedPeers) > 0
+}
+
+// routesChanged reports whether the routes have to be applied, the peer config carries the DNS route flag
+func (c *networkMapChanges) routesChanged() bool {
+	return c == nil || c.routes || c.peerConfig
+}
+
+// rulesChanged reports whether the firewall rules have to be applied
+func (c *networkMapChanges) rulesChanged() bool {
+	return c == nil || c.rules || c.peerConfig
+}
+
+// updatePeers removes, modifies and adds the peer connections. With the changes of a delta only the touched peers
+// are looked at, otherwise the connections are reconciled with the full list of remote peers.
+func (e *Engine) updatePeers(remotePeers []*mgmProto.RemotePeerConfig, changes *networkMapChanges) error {
+	if changes == nil {
+		if err := e.removePeers(remotePeers); err != nil {
+			return err
+		}
+		if err := e.modifyPeers(remotePeers); err != nil {
+			return err
+		}
+		return e.addNewPeers(remotePeers)
+	}
+
+	toRemove := slices.DeleteFunc(slices.Clone(changes.removedPeers), func(peerKey string) bool {
+		_, ok := e.peerStore.PeerConn(peerKey)
+		return !ok
+	})
+	err := e.runPeerWorkers(toRemove, func(peerKey string) error {
+		if err := e.removePeer(peerKey); err != nil {
+			return err
+		}
+		log.Infof("removed peer %s", peerKey)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	localPubKey := e.config.WgPrivateKey.PublicKey().String()
+	changed := slices.DeleteFunc(slices.Clone(changes.peers), func(p *mgmProto.RemotePeerConfig) bool {
+		return p.GetWgPubKey() == localPubKey
+	})
+	if err := e.modifyPeers(changed); err != nil {
+		return err
+	}
+	return e.addNewPeers(changed)
+}
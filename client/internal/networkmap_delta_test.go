@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+func TestDeltaChanges(t *testing.T) {
+	base := &mgmProto.NetworkMap{
+		Serial:            1,
+		PeerConfig:        &mgmProto.PeerConfig{Address: "100.64.0.10/16"},
+		InboundExceptions: []*mgmProto.FirewallRule{{PeerIP: "100.64.0.1"}},
+	}
+
+	tests := []struct {
+		name     string
+		delta    *mgmProto.NetworkMap
+		expected *networkMapChanges
+	}{
+		{
+			name: "peers only",
+			delta: &mgmProto.NetworkMap{
+				PeerConfig:        &mgmProto.PeerConfig{Address: "100.64.0.10/16"},
+				InboundExceptions: []*mgmProto.FirewallRule{{PeerIP: "100.64.0.1"}},
+				RemotePeers:       []*mgmProto.RemotePeerConfig{{WgPubKey: "keyA"}},
+				RemovedPeers:      []string{"keyB"},
+				OfflinePeers:      []*mgmProto.RemotePeerConfig{{WgPubKey: "keyC"}},
+			},
+			expected: &networkMapChanges{
+				peers:        []*mgmProto.RemotePeerConfig{{WgPubKey: "keyA"}},
+				removedPeers: []string{"keyB", "keyC"},
+			},
+		},
+		{
+			name: "routes and rules",
+			delta: &mgmProto.NetworkMap{
+				PeerConfig:           &mgmProto.PeerConfig{Address: "100.64.0.10/16"},
+				InboundExceptions:    []*mgmProto.FirewallRule{{PeerIP: "100.64.0.1"}},
+				RemovedRoutes:        []string{"r1"},
+				FirewallRulesIsEmpty: true,
+			},
+			expected: &networkMapChanges{
+				removedPeers: []string{},
+				routes:       true,
+				rules:        true,
+			},
+		},
+		{
+			name: "peer config and inbound exceptions",
+			delta: &mgmProto.NetworkMap{
+				PeerConfig: &mgmProto.PeerConfig{Address: "100.64.0.10/16", RoutingPeerDnsResolutionEnabled: true},
+			},
+			expected: &networkMapChanges{
+				removedPeers: []string{},
+				peerConfig:   true,
+				rules:        true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := deltaChanges(base, tt.delta)
+			assert.Len(t, changes.peers, len(tt.expected.peers))
+			assert.ElementsMatch(t, tt.expected.removedPeers, changes.removedPeers)
+			assert.Equal(t, tt.expected.peerConfig, changes.peerConfig, "peer config")
+			assert.Equal(t, tt.expected.routes, changes.routes, "routes")
+			assert.Equal(t, tt.expected.rules, changes.rules, "rules")
+		})
+	}
+}
+
+func TestNetworkMapChanges_FullMap(t *testing.T) {
+	var changes *networkMapChanges
+	assert.True(t, changes.peersChanged())
+	assert.True(t, changes.routesChanged())
+	assert.True(t, changes.rulesChanged())
+
+	changes = &networkMapChanges{}
+	assert.False(t, changes.peersChanged())
+	assert.False(t, changes.routesChanged())
+	assert.False(t, changes.rulesChanged())
+
+	changes.peerConfig = true
+	assert.True(t, changes.routesChanged())
+	assert.True(t, changes.rulesChanged())
+}
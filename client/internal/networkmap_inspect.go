@@ -0,0 +1,194 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+// NetworkMapFilter narrows an inspected network map down to matching peers and routes.
+// Empty fields match everything.
+type NetworkMapFilter struct {
+	// Peer matches remote peers by WireGuard public key, FQDN or IP address
+	Peer string
+	// Route matches routes by ID, network ID, network or domain
+	Route string
+}
+
+// NetworkMapInspection holds the JSON encoded sections of an inspected network map
+type NetworkMapInspection struct {
+	Serial        uint64
+	NetworkMap    string
+	Routes        string
+	DNSConfig     string
+	FirewallRules string
+}
+
+type firewallRulesInspection struct {
+	PeerRules  []json.RawMessage `json:"peerRules"`
+	RouteRules []json.RawMessage `json:"routeRules"`
+}
+
+// InspectNetworkMap applies the filter to the network map and returns its sections encoded as JSON
+func InspectNetworkMap(networkMap *mgmProto.NetworkMap, filter NetworkMapFilter) (*NetworkMapInspection, error) {
+	nm := &mgmProto.NetworkMap{
+		Serial:          networkMap.GetSerial(),
+		PeerConfig:      networkMap.GetPeerConfig(),
+		DNSConfig:       networkMap.GetDNSConfig(),
+		ForwardingRules: networkMap.GetForwardingRules(),
+		SshAuth:         networkMap.GetSshAuth(),
+	}
+
+	// the slices are cloned before filtering, DeleteFunc modifies them in place and they belong to the engine
+	var peerIPs []string
+	skipPeer := func(p *mgmProto.RemotePeerConfig) bool {
+		if !peerMatches(p, filter.Peer) {
+			return true
+		}
+		peerIPs = append(peerIPs, p.GetAllowedIps()...)
+		return false
+	}
+	nm.RemotePeers = slices.DeleteFunc(slices.Clone(networkMap.GetRemotePeers()), skipPeer)
+	nm.OfflinePeers = slices.DeleteFunc(slices.Clone(networkMap.GetOfflinePeers()), skipPeer)
+	nm.Routes = slices.DeleteFunc(slices.Clone(networkMap.GetRoutes()), func(r *mgmProto.Route) bool {
+		return !routeMatches(r, filter.Route)
+	})
+
+	nm.FirewallRules = networkMap.GetFirewallRules()
+	if filter.Peer != "" {
+		nm.FirewallRules = slices.DeleteFunc(slices.Clone(nm.FirewallRules), func(r *mgmProto.FirewallRule) bool {
+			return !ruleMatchesPeerIPs(r.GetPeerIP(), peerIPs)
+		})
+	}
+
+	nm.RoutesFirewallRules = networkMap.GetRoutesFirewallRules()
+	if filter.Route != "" {
+		nm.RoutesFirewallRules = slices.DeleteFunc(slices.Clone(nm.RoutesFirewallRules), func(r *mgmProto.RouteFirewallRule) bool {
+			return !slices.ContainsFunc(nm.Routes, func(rt *mgmProto.Route) bool {
+				return r.GetRouteID() == rt.GetID() || r.GetDestination() == rt.GetNetwork()
+			})
+		})
+	}
+
+	marshaler := protojson.MarshalOptions{Multiline: true, Indent: "  "}
+
+	nmJSON, err := marshaler.Marshal(nm)
+	if err != nil {
+		return nil, fmt.Errorf("marshal network map: %w", err)
+	}
+
+	routesJSON, err := json.MarshalIndent(toRoutes(nm.GetRoutes()), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal routes: %w", err)
+	}
+
+	var network netip.Prefix
+	if addr := nm.GetPeerConfig().GetAddress(); addr != "" {
+		if network, err = netip.ParsePrefix(addr); err != nil {
+			return nil, fmt.Errorf("parse peer address %s: %w", addr, err)
+		}
+	}
+	dnsConfig := nm.GetDNSConfig()
+	if dnsConfig == nil {
+		dnsConfig = &mgmProto.DNSConfig{}
+	}
+	dnsJSON, err := json.MarshalIndent(toDNSConfig(dnsConfig, network.Masked()), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal DNS config: %w", err)
+	}
+
+	rules := firewallRulesInspection{
+		PeerRules:  make([]json.RawMessage, 0, len(nm.FirewallRules)),
+		RouteRules: make([]json.RawMessage, 0, len(nm.RoutesFirewallRules)),
+	}
+	for _, r := range nm.FirewallRules {
+		raw, err := protojson.Marshal(r)
+		if err != nil {
+			return nil, fmt.Errorf("marshal firewall rule: %w", err)
+		}
+		rules.PeerRules = append(rules.PeerRules, raw)
+	}
+	for _, r := range nm.RoutesFirewallRules {
+		raw, err := protojson.Marshal(r)
+		if err != nil {
+			return nil, fmt.Errorf("marshal route firewall rule: %w", err)
+		}
+		rules.RouteRules = append(rules.RouteRules, raw)
+	}
+	rulesJSON, err := json.MarshalIndent(rules, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal firewall rules: %w", err)
+	}
+
+	return &NetworkMapInspection{
+		Serial:        nm.GetSerial(),
+		NetworkMap:    string(nmJSON),
+		Routes:        string(routesJSON),
+		DNSConfig:     string(dnsJSON),
+		FirewallRules: string(rulesJSON),
+	}, nil
+}
+
+// ruleMatchesPeerIPs reports whether a firewall rule applies to one of the peer IPs. Rules for 0.0.0.0 or without a
+// peer IP apply to every peer.
+func ruleMatchesPeerIPs(ruleIP string, peerIPs []string) bool {
+	if ruleIP == "" || ruleIP == "0.0.0.0" {
+		return len(peerIPs) > 0
+	}
+	return slices.ContainsFunc(peerIPs, func(ip string) bool {
+		return prefixContains(ip, ruleIP)
+	})
+}
+
+func peerMatches(p *mgmProto.RemotePeerConfig, filter string) bool {
+	if filter == "" {
+		return true
+	}
+
+	if p.GetWgPubKey() == filter || strings.EqualFold(p.GetFqdn(), filter) {
+		return true
+	}
+
+	// allow matching by the short name of the peer
+	if host, _, ok := strings.Cut(p.GetFqdn(), "."); ok && strings.EqualFold(host, filter) {
+		return true
+	}
+
+	return slices.ContainsFunc(p.GetAllowedIps(), func(allowedIP string) bool {
+		return prefixContains(allowedIP, filter)
+	})
+}
+
+func routeMatches(r *mgmProto.Route, filter string) bool {
+	if filter == "" {
+		return true
+	}
+
+	if r.GetID() == filter || r.GetNetID() == filter || r.GetNetwork() == filter {
+		return true
+	}
+
+	return slices.ContainsFunc(r.GetDomains(), func(d string) bool { return strings.EqualFold(d, filter) })
+}
+
+// prefixContains reports whether the address or prefix in addr is contained in the prefix
+func prefixContains(prefix, addr string) bool {
+	p, err := netip.ParsePrefix(prefix)
+	if err != nil {
+		return false
+	}
+
+	if a, err := netip.ParseAddr(addr); err == nil {
+		return p.Contains(a)
+	}
+	if ap, err := netip.ParsePrefix(addr); err == nil {
+		return p.Contains(ap.Addr())
+	}
+	return false
+}
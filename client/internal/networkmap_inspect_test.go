@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+func TestInspectNetworkMap(t *testing.T) {
+	networkMap := &mgmProto.NetworkMap{
+		Serial:     5,
+		PeerConfig: &mgmProto.PeerConfig{Address: "100.64.0.10/16"},
+		RemotePeers: []*mgmProto.RemotePeerConfig{
+			{WgPubKey: "keyA", Fqdn: "peer-a.netbird.cloud", AllowedIps: []string{"100.64.0.1/32"}},
+			{WgPubKey: "keyB", Fqdn: "peer-b.netbird.cloud", AllowedIps: []string{"100.64.0.2/32"}},
+		},
+		Routes: []*mgmProto.Route{
+			{ID: "r1", NetID: "office", Network: "10.0.0.0/24", Peer: "keyA", NetworkType: 1},
+			{ID: "r2", NetID: "lab", Network: "10.1.0.0/24", Peer: "keyB", NetworkType: 1},
+		},
+		FirewallRules: []*mgmProto.FirewallRule{
+			{PeerIP: "100.64.0.1"},
+			{PeerIP: "100.64.0.2"},
+			{PeerIP: "0.0.0.0"},
+		},
+		RoutesFirewallRules: []*mgmProto.RouteFirewallRule{
+			{RouteID: "r1", Destination: "10.0.0.0/24"},
+			{RouteID: "r2", Destination: "10.1.0.0/24"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		filter        NetworkMapFilter
+		expectedPeers []string
+		expectedRoute []string
+		peerRules     int
+		routeRules    int
+	}{
+		{
+			name:          "no filter",
+			expectedPeers: []string{"keyA", "keyB"},
+			expectedRoute: []string{"r1", "r2"},
+			peerRules:     3,
+			routeRules:    2,
+		},
+		{
+			name:          "peer by short name",
+			filter:        NetworkMapFilter{Peer: "peer-a"},
+			expectedPeers: []string{"keyA"},
+			expectedRoute: []string{"r1", "r2"},
+			peerRules:     2,
+			routeRules:    2,
+		},
+		{
+			name:          "peer by ip and route by net id",
+			filter:        NetworkMapFilter{Peer: "100.64.0.2", Route: "office"},
+			expectedPeers: []string{"keyB"},
+			expectedRoute: []string{"r1"},
+			peerRules:     2,
+			routeRules:    1,
+		},
+		{
+			name:          "unknown peer",
+			filter:        NetworkMapFilter{Peer: "peer-c"},
+			expectedRoute: []string{"r1", "r2"},
+			peerRules:     0,
+			routeRules:    2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			inspection, err := InspectNetworkMap(networkMap, tc.filter)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(5), inspection.Serial)
+
+			nm := &mgmProto.NetworkMap{}
+			require.NoError(t, protojson.Unmarshal([]byte(inspection.NetworkMap), nm))
+
+			var peers []string
+			for _, p := range nm.GetRemotePeers() {
+				peers = append(peers, p.GetWgPubKey())
+			}
+			assert.Equal(t, tc.expectedPeers, peers)
+
+			var routes []string
+			for _, r := range nm.GetRoutes() {
+				routes = append(routes, r.GetID())
+			}
+			assert.Equal(t, tc.expectedRoute, routes)
+
+			var rules firewallRulesInspection
+			require.NoError(t, json.Unmarshal([]byte(inspection.FirewallRules), &rules))
+			assert.Len(t, rules.PeerRules, tc.peerRules)
+			assert.Len(t, rules.RouteRules, tc.routeRules)
+
+			// the inspected map is left untouched
+			assert.Len(t, networkMap.GetRemotePeers(), 2)
+			assert.Len(t, networkMap.GetFirewallRules(), 3)
+		})
+	}
+}
@@ -13,10 +13,10 @@ import (
 	"github.com/netbirdio/netbird/client/internal/routemanager/systemops"
 )
 
-func checkChange(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop) error {
+func checkChange(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop, ignored func(string) bool) (change, error) {
 	fd, err := prepareFd()
 	if err != nil {
-		return fmt.Errorf("open routing socket: %v", err)
+		return changeRoute, fmt.Errorf("open routing socket: %v", err)
 	}
 
 	defer func() {
@@ -26,5 +26,5 @@ func checkChange(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop) er
 		}
 	}()
 
-	return routeCheck(ctx, fd, nexthopv4, nexthopv6)
+	return changeRoute, routeCheck(ctx, fd, nexthopv4, nexthopv6, ignored)
 }
@@ -20,7 +20,7 @@ func prepareFd() (int, error) {
 	return unix.Socket(syscall.AF_ROUTE, syscall.SOCK_RAW, syscall.AF_UNSPEC)
 }
 
-func routeCheck(ctx context.Context, fd int, nexthopv4, nexthopv6 systemops.Nexthop) error {
+func routeCheck(ctx context.Context, fd int, nexthopv4, nexthopv6 systemops.Nexthop, ignored func(string) bool) error {
 	for {
 		select {
 		case <-ctx.Done():
@@ -57,6 +57,10 @@ func routeCheck(ctx context.Context, fd int, nexthopv4, nexthopv6 systemops.Next
 				intf := "<nil>"
 				if route.Interface != nil {
 					intf = route.Interface.Name
+					if ignored(intf) {
+						log.Debugf("Network monitor: ignoring default route event on interface %s", intf)
+						continue
+					}
 				}
 				switch msg.Type {
 				case unix.RTM_ADD:
@@ -18,10 +18,10 @@ import (
 
 // todo: refactor to not use static functions
 
-func checkChange(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop) error {
+func checkChange(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop, ignored func(string) bool) (change, error) {
 	fd, err := prepareFd()
 	if err != nil {
-		return fmt.Errorf("open routing socket: %v", err)
+		return changeRoute, fmt.Errorf("open routing socket: %v", err)
 	}
 
 	defer func() {
@@ -34,7 +34,7 @@ func checkChange(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop) er
 
 	routeChanged := make(chan struct{})
 	go func() {
-		_ = routeCheck(ctx, fd, nexthopv4, nexthopv6)
+		_ = routeCheck(ctx, fd, nexthopv4, nexthopv6, ignored)
 		close(routeChanged)
 	}()
 
@@ -46,19 +46,19 @@ func checkChange(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop) er
 
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return changeRoute, ctx.Err()
 	case <-routeChanged:
 		if ctx.Err() != nil {
-			return ctx.Err()
+			return changeRoute, ctx.Err()
 		}
 		log.Infof("route change detected")
-		return nil
+		return changeRoute, nil
 	case <-wakeUp:
 		if ctx.Err() != nil {
-			return ctx.Err()
+			return changeRoute, ctx.Err()
 		}
 		log.Infof("wakeup detected")
-		return nil
+		return changeWakeUp, nil
 	}
 }
 
@@ -6,7 +6,8 @@ import (
 	"github.com/netbirdio/netbird/client/internal/routemanager/systemops"
 )
 
-func checkChange(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop) error {
+func checkChange(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop, ignored func(string) bool) (change, error) {
 	// No-op for WASM - network changes don't apply
-	return nil
+	<-ctx.Done()
+	return changeRoute, ctx.Err()
 }
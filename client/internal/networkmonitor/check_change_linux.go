@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"syscall"
 
 	log "github.com/sirupsen/logrus"
@@ -14,9 +15,9 @@ import (
 	"github.com/netbirdio/netbird/client/internal/routemanager/systemops"
 )
 
-func checkChange(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop) error {
+func checkChange(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop, ignored func(string) bool) (change, error) {
 	if nexthopv4.Intf == nil && nexthopv6.Intf == nil {
-		return errors.New("no interfaces available")
+		return changeRoute, errors.New("no interfaces available")
 	}
 
 	done := make(chan struct{})
@@ -24,29 +25,33 @@ func checkChange(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop) er
 
 	routeChan := make(chan netlink.RouteUpdate)
 	if err := netlink.RouteSubscribe(routeChan, done); err != nil {
-		return fmt.Errorf("subscribe to route updates: %v", err)
+		return changeRoute, fmt.Errorf("subscribe to route updates: %v", err)
 	}
 
 	log.Info("Network monitor: started")
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return changeRoute, ctx.Err()
 		// handle route changes
 		case route := <-routeChan:
 			// default route and main table
 			if route.Dst != nil || route.Table != syscall.RT_TABLE_MAIN {
 				continue
 			}
+			if intf, err := net.InterfaceByIndex(route.LinkIndex); err == nil && ignored(intf.Name) {
+				log.Debugf("Network monitor: ignoring default route event on interface %s", intf.Name)
+				continue
+			}
 			switch route.Type {
 			// triggered on added/replaced routes
 			case syscall.RTM_NEWROUTE:
 				log.Infof("Network monitor: default route changed: via %s, interface %d", route.Gw, route.LinkIndex)
-				return nil
+				return changeRoute, nil
 			case syscall.RTM_DELROUTE:
 				if nexthopv4.Intf != nil && route.Gw.Equal(nexthopv4.IP.AsSlice()) || nexthopv6.Intf != nil && route.Gw.Equal(nexthopv6.IP.AsSlice()) {
 					log.Infof("Network monitor: default route removed: via %s, interface %d", route.Gw, route.LinkIndex)
-					return nil
+					return changeRoute, nil
 				}
 			}
 		}
@@ -10,10 +10,10 @@ import (
 	"github.com/netbirdio/netbird/client/internal/routemanager/systemops"
 )
 
-func checkChange(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop) error {
+func checkChange(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop, ignored func(string) bool) (change, error) {
 	routeMonitor, err := systemops.NewRouteMonitor(ctx)
 	if err != nil {
-		return fmt.Errorf("create route monitor: %w", err)
+		return changeRoute, fmt.Errorf("create route monitor: %w", err)
 	}
 	defer func() {
 		if err := routeMonitor.Stop(); err != nil {
@@ -24,14 +24,18 @@ func checkChange(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop) er
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return changeRoute, ctx.Err()
 		case route := <-routeMonitor.RouteUpdates():
 			if route.Destination.Bits() != 0 {
 				continue
 			}
+			if intf := route.NextHop.Intf; intf != nil && ignored(intf.Name) {
+				log.Debugf("Network monitor: ignoring default route event on interface %s", intf.Name)
+				continue
+			}
 
 			if routeChanged(route, nexthopv4, nexthopv6) {
-				return nil
+				return changeRoute, nil
 			}
 		}
 	}
@@ -0,0 +1,35 @@
+package networkmonitor
+
+import (
+	"strings"
+	"time"
+)
+
+// change is the kind of a detected network change
+type change int
+
+const (
+	// changeRoute is a default route event, the engine is restarted only if the default next hops changed
+	changeRoute change = iota
+	// changeWakeUp is a wakeup of the system, the engine is always restarted
+	changeWakeUp
+)
+
+// Config configures the network monitor
+type Config struct {
+	// Debounce is the quiet time after the last event before the change is evaluated. Zero means 2 seconds.
+	Debounce time.Duration
+	// IgnoredInterfaces are the interface name prefixes whose default route events are ignored, e.g. the
+	// interface blacklist of the client
+	IgnoredInterfaces []string
+}
+
+// ignored reports whether the events of the interface are ignored
+func (c Config) ignored(name string) bool {
+	for _, prefix := range c.IgnoredInterfaces {
+		if prefix != "" && strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -9,6 +9,7 @@ import (
 	"net/netip"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -21,24 +22,34 @@ const (
 	debounceTime = 2 * time.Second
 )
 
-var checkChangeFn = checkChange
+var (
+	checkChangeFn = checkChange
+	nextHopsFn    = getNextHops
+)
 
 // NetworkMonitor watches for changes in network configuration.
 type NetworkMonitor struct {
-	cancel context.CancelFunc
-	wg     sync.WaitGroup
-	mu     sync.Mutex
+	config    Config
+	cancel    context.CancelFunc
+	listening atomic.Bool
+	wg        sync.WaitGroup
+	mu        sync.Mutex
 }
 
 // New creates a new network monitor.
-func New() *NetworkMonitor {
-	return &NetworkMonitor{}
+func New(config Config) *NetworkMonitor {
+	if config.Debounce <= 0 {
+		config.Debounce = debounceTime
+	}
+	return &NetworkMonitor{config: config}
 }
 
-// Listen begins monitoring network changes. When a change is detected, this function will return without error.
+// Listen begins monitoring network changes. When the default next hops changed, this function will return without error.
+// Route events that leave the default next hops as they were, e.g. a default route that was removed and added again,
+// are ignored. Listen can be called again after it returned to watch for the next change.
 func (nw *NetworkMonitor) Listen(ctx context.Context) (err error) {
 	nw.mu.Lock()
-	if nw.cancel != nil {
+	if !nw.listening.CompareAndSwap(false, true) {
 		nw.mu.Unlock()
 		return errors.New("network monitor already started")
 	}
@@ -49,22 +60,21 @@ func (nw *NetworkMonitor) Listen(ctx context.Context) (err error) {
 	nw.mu.Unlock()
 
 	defer nw.wg.Done()
+	defer nw.listening.Store(false)
 
 	var nexthop4, nexthop6 systemops.Nexthop
 
 	operation := func() error {
-		var errv4, errv6 error
-		nexthop4, errv4 = systemops.GetNextHop(netip.IPv4Unspecified())
-		nexthop6, errv6 = systemops.GetNextHop(netip.IPv6Unspecified())
-
-		if errv4 != nil && errv6 != nil {
-			return errors.New("failed to get default next hops")
+		var err error
+		nexthop4, nexthop6, err = nextHopsFn()
+		if err != nil {
+			return err
 		}
 
-		if errv4 == nil {
+		if nexthop4.Intf != nil {
 			log.Debugf("Network monitor: IPv4 default route: %s, interface: %s", nexthop4.IP, nexthop4.Intf.Name)
 		}
-		if errv6 == nil {
+		if nexthop6.Intf != nil {
 			log.Debugf("Network monitor: IPv6 default route: %s, interface: %s", nexthop6.IP, nexthop6.Intf.Name)
 		}
 
@@ -86,7 +96,8 @@ func (nw *NetworkMonitor) Listen(ctx context.Context) (err error) {
 	}()
 
 	event := make(chan struct{}, 1)
-	go nw.checkChanges(ctx, event, nexthop4, nexthop6)
+	var wokeUp atomic.Bool
+	go nw.checkChanges(ctx, event, &wokeUp, nexthop4, nexthop6)
 
 	log.Infof("start watching for network changes")
 	// debounce changes
@@ -94,10 +105,20 @@ func (nw *NetworkMonitor) Listen(ctx context.Context) (err error) {
 	timer.Stop()
 	for {
 		select {
-		case <-event:
-			timer.Reset(debounceTime)
+		case _, ok := <-event:
+			if !ok {
+				timer.Stop()
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				return errors.New("stopped checking for changes")
+			}
+			timer.Reset(nw.config.Debounce)
 		case <-timer.C:
-			return nil
+			if wokeUp.Load() || nextHopsChanged(nexthop4, nexthop6) {
+				return nil
+			}
+			log.Infof("Network monitor: default routes are unchanged after route events, keep watching")
 		case <-ctx.Done():
 			timer.Stop()
 			return ctx.Err()
@@ -118,15 +139,19 @@ func (nw *NetworkMonitor) Stop() {
 	nw.wg.Wait()
 }
 
-func (nw *NetworkMonitor) checkChanges(ctx context.Context, event chan struct{}, nexthop4 systemops.Nexthop, nexthop6 systemops.Nexthop) {
+func (nw *NetworkMonitor) checkChanges(ctx context.Context, event chan struct{}, wokeUp *atomic.Bool, nexthop4 systemops.Nexthop, nexthop6 systemops.Nexthop) {
 	defer close(event)
 	for {
-		if err := checkChangeFn(ctx, nexthop4, nexthop6); err != nil {
+		c, err := checkChangeFn(ctx, nexthop4, nexthop6, nw.config.ignored)
+		if err != nil {
 			if !errors.Is(err, context.Canceled) {
 				log.Errorf("Network monitor: failed to check for changes: %v", err)
 			}
 			return
 		}
+		if c == changeWakeUp {
+			wokeUp.Store(true)
+		}
 		// prevent blocking
 		select {
 		case event <- struct{}{}:
@@ -134,3 +159,33 @@ func (nw *NetworkMonitor) checkChanges(ctx context.Context, event chan struct{},
 		}
 	}
 }
+
+// getNextHops returns the default next hops, it fails only if neither IPv4 nor IPv6 has one
+func getNextHops() (systemops.Nexthop, systemops.Nexthop, error) {
+	nexthop4, errv4 := systemops.GetNextHop(netip.IPv4Unspecified())
+	nexthop6, errv6 := systemops.GetNextHop(netip.IPv6Unspecified())
+	if errv4 != nil && errv6 != nil {
+		return systemops.Nexthop{}, systemops.Nexthop{}, errors.New("failed to get default next hops")
+	}
+	return nexthop4, nexthop6, nil
+}
+
+// nextHopsChanged compares the current default next hops with the ones at the start of the monitor.
+// A failed lookup counts as change, the connectivity is gone.
+func nextHopsChanged(nexthop4, nexthop6 systemops.Nexthop) bool {
+	current4, current6, err := nextHopsFn()
+	if err != nil {
+		log.Infof("Network monitor: default routes are gone: %v", err)
+		return true
+	}
+
+	if !current4.Equal(nexthop4) {
+		log.Infof("Network monitor: IPv4 default next hop changed from %s to %s", nexthop4, current4)
+		return true
+	}
+	if !current6.Equal(nexthop6) {
+		log.Infof("Network monitor: IPv6 default next hop changed from %s to %s", nexthop6, current6)
+		return true
+	}
+	return false
+}
@@ -11,7 +11,7 @@ type NetworkMonitor struct {
 }
 
 // New creates a new network monitor.
-func New() *NetworkMonitor {
+func New(Config) *NetworkMonitor {
 	return &NetworkMonitor{}
 }
 
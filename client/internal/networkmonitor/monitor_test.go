@@ -3,9 +3,13 @@ package networkmonitor
 import (
 	"context"
 	"errors"
+	"net/netip"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/netbirdio/netbird/client/internal/routemanager/systemops"
 )
 
@@ -13,23 +17,39 @@ type MocMultiEvent struct {
 	counter int
 }
 
-func (m *MocMultiEvent) checkChange(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop) error {
+func (m *MocMultiEvent) checkChange(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop, _ func(string) bool) (change, error) {
 	if m.counter == 0 {
 		<-ctx.Done()
-		return ctx.Err()
+		return changeRoute, ctx.Err()
 	}
 
 	time.Sleep(1 * time.Second)
 	m.counter--
-	return nil
+	return changeRoute, nil
+}
+
+// mockNextHops returns a next hop that changes after the first call if changing is set
+func mockNextHops(t *testing.T, changing bool) {
+	t.Helper()
+
+	var calls atomic.Int32
+	nextHopsFn = func() (systemops.Nexthop, systemops.Nexthop, error) {
+		ip := netip.MustParseAddr("192.168.1.1")
+		if calls.Add(1) > 1 && changing {
+			ip = netip.MustParseAddr("192.168.2.1")
+		}
+		return systemops.Nexthop{IP: ip}, systemops.Nexthop{}, nil
+	}
+	t.Cleanup(func() { nextHopsFn = getNextHops })
 }
 
 func TestNetworkMonitor_Close(t *testing.T) {
-	checkChangeFn = func(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop) error {
+	mockNextHops(t, true)
+	checkChangeFn = func(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop, _ func(string) bool) (change, error) {
 		<-ctx.Done()
-		return ctx.Err()
+		return changeRoute, ctx.Err()
 	}
-	nw := New()
+	nw := New(Config{})
 
 	var resErr error
 	done := make(chan struct{})
@@ -48,17 +68,18 @@ func TestNetworkMonitor_Close(t *testing.T) {
 }
 
 func TestNetworkMonitor_Event(t *testing.T) {
-	checkChangeFn = func(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop) error {
+	mockNextHops(t, true)
+	checkChangeFn = func(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop, _ func(string) bool) (change, error) {
 		timeout, cancel := context.WithTimeout(ctx, 3*time.Second)
 		defer cancel()
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return changeRoute, ctx.Err()
 		case <-timeout.Done():
-			return nil
+			return changeRoute, nil
 		}
 	}
-	nw := New()
+	nw := New(Config{})
 	defer nw.Stop()
 
 	var resErr error
@@ -77,9 +98,10 @@ func TestNetworkMonitor_Event(t *testing.T) {
 func TestNetworkMonitor_MultiEvent(t *testing.T) {
 	eventsRepeated := 3
 	me := &MocMultiEvent{counter: eventsRepeated}
+	mockNextHops(t, true)
 	checkChangeFn = me.checkChange
 
-	nw := New()
+	nw := New(Config{})
 	defer nw.Stop()
 
 	done := make(chan struct{})
@@ -97,3 +119,74 @@ func TestNetworkMonitor_MultiEvent(t *testing.T) {
 		t.Errorf("unexpected duration: %v", time.Since(started))
 	}
 }
+
+func TestNetworkMonitor_UnchangedNextHops(t *testing.T) {
+	mockNextHops(t, false)
+	var events atomic.Int32
+	checkChangeFn = func(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop, _ func(string) bool) (change, error) {
+		if events.Add(1) > 2 {
+			<-ctx.Done()
+			return changeRoute, ctx.Err()
+		}
+		return changeRoute, nil
+	}
+
+	nw := New(Config{Debounce: 100 * time.Millisecond})
+
+	done := make(chan error)
+	go func() {
+		done <- nw.Listen(context.Background())
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("monitor returned although the next hops didn't change: %v", err)
+	case <-time.After(time.Second):
+	}
+
+	nw.Stop()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestNetworkMonitor_WakeUp(t *testing.T) {
+	mockNextHops(t, false)
+	var events atomic.Int32
+	checkChangeFn = func(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop, _ func(string) bool) (change, error) {
+		if events.Add(1) > 1 {
+			<-ctx.Done()
+			return changeRoute, ctx.Err()
+		}
+		return changeWakeUp, nil
+	}
+
+	nw := New(Config{Debounce: 100 * time.Millisecond})
+	defer nw.Stop()
+
+	assert.NoError(t, nw.Listen(context.Background()), "a wakeup restarts even if the next hops are the same")
+}
+
+func TestNetworkMonitor_ListenAgain(t *testing.T) {
+	mockNextHops(t, false)
+	var events atomic.Int32
+	checkChangeFn = func(ctx context.Context, nexthopv4, nexthopv6 systemops.Nexthop, _ func(string) bool) (change, error) {
+		if events.Add(1)%2 == 0 {
+			<-ctx.Done()
+			return changeRoute, ctx.Err()
+		}
+		return changeWakeUp, nil
+	}
+
+	nw := New(Config{Debounce: 100 * time.Millisecond})
+	defer nw.Stop()
+
+	assert.NoError(t, nw.Listen(context.Background()))
+	assert.NoError(t, nw.Listen(context.Background()), "the monitor watches again after a change was reported")
+}
+
+func TestConfig_Ignored(t *testing.T) {
+	config := Config{IgnoredInterfaces: []string{"wt", "docker", ""}}
+
+	assert.True(t, config.ignored("wt0"))
+	assert.True(t, config.ignored("docker0"))
+	assert.False(t, config.ignored("eth0"))
+}
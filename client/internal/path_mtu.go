@@ -0,0 +1,33 @@
+package internal
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/iface/netstack"
+	"github.com/netbirdio/netbird/client/internal/pmtu"
+)
+
+// startPathMTUProbing measures the path MTU to the directly connected peers until the engine stops. The echo
+// requests are sent by the kernel, so the tunnel has to be a kernel or userspace tun device.
+func (e *Engine) startPathMTUProbing() {
+	if !e.config.PathMTUProbing {
+		return
+	}
+	if netstack.IsEnabled() {
+		log.Infof("path MTU probing isn't supported in netstack mode")
+		return
+	}
+
+	pinger, err := pmtu.NewICMPPinger(e.wgInterface.Address().IP)
+	if err != nil {
+		log.Warnf("path MTU probing is disabled: %v", err)
+		return
+	}
+	prober := pmtu.NewProber(pinger, e.statusRecorder, e.config.MTU, e.config.PathMTUProbeInterval)
+
+	e.shutdownWg.Add(1)
+	go func() {
+		defer e.shutdownWg.Done()
+		prober.Run(e.ctx)
+	}()
+}
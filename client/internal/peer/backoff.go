@@ -0,0 +1,51 @@
+package peer
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	defaultConnTimeoutMin = 30 * time.Second
+	defaultConnTimeoutMax = 45 * time.Second
+	defaultRetryJitter    = 0.1
+)
+
+// BackoffPolicy controls how long a peer connection waits for the remote peer before it sends a new offer and
+// how often it retries while the connection is not established. Zero values mean default.
+type BackoffPolicy struct {
+	// InitialTimeout is the shortest connection timeout, the timeout caps the interval between the offers
+	InitialTimeout time.Duration
+	// MaxTimeout is the longest connection timeout. Every connection picks a random timeout between
+	// InitialTimeout and MaxTimeout, so the peers don't retry in lockstep.
+	MaxTimeout time.Duration
+	// Jitter randomizes the intervals between the offers by this factor, e.g. 0.1 varies them by 10%
+	Jitter float64
+	// MaxRetries is the number of offers sent before the connection idles until the next relay, ICE or network
+	// change. Zero retries without limit.
+	MaxRetries int
+}
+
+// connTimeout picks the connection timeout between InitialTimeout and MaxTimeout
+func (p BackoffPolicy) connTimeout() time.Duration {
+	minTimeout := p.InitialTimeout
+	if minTimeout <= 0 {
+		minTimeout = defaultConnTimeoutMin
+	}
+	maxTimeout := p.MaxTimeout
+	if maxTimeout <= 0 {
+		maxTimeout = defaultConnTimeoutMax
+	}
+	if maxTimeout <= minTimeout {
+		return minTimeout
+	}
+	return minTimeout + rand.N(maxTimeout-minTimeout)
+}
+
+// jitter returns the randomization factor of the intervals between the offers
+func (p BackoffPolicy) jitter() float64 {
+	if p.Jitter <= 0 || p.Jitter >= 1 {
+		return defaultRetryJitter
+	}
+	return p.Jitter
+}
@@ -0,0 +1,39 @@
+package peer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoffPolicy_ConnTimeout(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		timeout := BackoffPolicy{}.connTimeout()
+		assert.GreaterOrEqual(t, timeout, defaultConnTimeoutMin)
+		assert.Less(t, timeout, defaultConnTimeoutMax)
+	}
+
+	p := BackoffPolicy{InitialTimeout: 5 * time.Second, MaxTimeout: 10 * time.Second}
+	for i := 0; i < 100; i++ {
+		timeout := p.connTimeout()
+		assert.GreaterOrEqual(t, timeout, 5*time.Second)
+		assert.Less(t, timeout, 10*time.Second)
+	}
+
+	p = BackoffPolicy{InitialTimeout: time.Minute, MaxTimeout: time.Second}
+	assert.Equal(t, time.Minute, p.connTimeout(), "max below the initial timeout falls back to the initial timeout")
+
+	p = BackoffPolicy{InitialTimeout: time.Second}
+	for i := 0; i < 100; i++ {
+		timeout := p.connTimeout()
+		assert.GreaterOrEqual(t, timeout, time.Second)
+		assert.Less(t, timeout, defaultConnTimeoutMax)
+	}
+}
+
+func TestBackoffPolicy_Jitter(t *testing.T) {
+	assert.Equal(t, defaultRetryJitter, BackoffPolicy{}.jitter())
+	assert.Equal(t, 0.5, BackoffPolicy{Jitter: 0.5}.jitter())
+	assert.Equal(t, defaultRetryJitter, BackoffPolicy{Jitter: 1.5}.jitter(), "factors of 1 or more are ignored")
+}
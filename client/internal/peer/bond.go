@@ -0,0 +1,281 @@
+package peer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// BondMode selects how a bonded connection uses the secondary uplink
+type BondMode string
+
+const (
+	// BondModeStandby keeps the secondary uplink as a hot standby, the packets move over to it once the primary
+	// uplink fails
+	BondModeStandby BondMode = "standby"
+	// BondModeSpray sends a share of the packets over the secondary uplink, so the loss of one uplink loses fewer
+	// packets
+	BondModeSpray BondMode = "spray"
+
+	defaultSprayWeight = 50
+)
+
+const (
+	uplinkPrimary   = 0
+	uplinkSecondary = 1
+
+	bondReadBufferSize = 65535
+	bondReadQueueSize  = 64
+)
+
+var errNoBondPath = errors.New("no uplink of the bonded connection is connected")
+
+// BondConfig bonds the connection to a peer over two uplinks of a multi-homed host. An ICE session is established
+// over each uplink, the connection continues over the secondary uplink when the primary one fails.
+type BondConfig struct {
+	// Mode is standby or spray, standby if empty
+	Mode BondMode `json:"mode,omitempty"`
+	// Interface is the network interface of the secondary uplink, the primary ICE session doesn't use it
+	Interface string `json:"interface"`
+	// SprayWeight is the percentage of the packets sent over the secondary uplink in spray mode, 50 if zero
+	SprayWeight int `json:"sprayWeight,omitempty"`
+}
+
+// Validate checks the mode and the spray weight of the bond
+func (c BondConfig) Validate() error {
+	switch c.Mode {
+	case "", BondModeStandby, BondModeSpray:
+	default:
+		return fmt.Errorf("unknown bond mode %q, supported modes are: %s, %s", c.Mode, BondModeStandby, BondModeSpray)
+	}
+	if c.SprayWeight < 0 || c.SprayWeight > 100 {
+		return fmt.Errorf("bond spray weight %d is not a percentage", c.SprayWeight)
+	}
+	return nil
+}
+
+func (c BondConfig) mode() BondMode {
+	if c.Mode == "" {
+		return BondModeStandby
+	}
+	return c.Mode
+}
+
+func (c BondConfig) sprayWeight() int {
+	if c.SprayWeight == 0 {
+		return defaultSprayWeight
+	}
+	return c.SprayWeight
+}
+
+// BondOffer is the ICE session on the secondary uplink of a bonded connection, it is sent with the offers and answers
+type BondOffer struct {
+	IceCredentials IceCredentials
+	// SessionID is the session of the ICE agent on the secondary uplink
+	SessionID *ICESessionID
+	Mode      BondMode
+	// SprayWeight is the percentage of the packets sent over the secondary uplink in spray mode
+	SprayWeight int
+}
+
+// offerAnswer returns the offer or answer for the ICE worker of the secondary uplink
+func (b *BondOffer) offerAnswer(remote *OfferAnswer) *OfferAnswer {
+	bondOffer := *remote
+	bondOffer.IceCredentials = b.IceCredentials
+	bondOffer.SessionID = b.SessionID
+	bondOffer.Bond = nil
+	return &bondOffer
+}
+
+type bondPacket struct {
+	buf *[]byte
+	n   int
+}
+
+var bondBufPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, bondReadBufferSize)
+		return &buf
+	},
+}
+
+// bondedConn is a net.Conn over the ICE connections of the two uplinks of a bonded connection. The packets of both
+// uplinks are read, the packets are written to the primary uplink in standby mode and to both by their weight in
+// spray mode. If the uplink to write to isn't connected, the other one is used.
+type bondedConn struct {
+	mode        BondMode
+	sprayWeight int
+
+	mu     sync.Mutex
+	paths  [2]net.Conn
+	credit int
+
+	packets   chan bondPacket
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newBondedConn(config BondConfig) *bondedConn {
+	return &bondedConn{
+		mode:        config.mode(),
+		sprayWeight: config.sprayWeight(),
+		packets:     make(chan bondPacket, bondReadQueueSize),
+		closed:      make(chan struct{}),
+	}
+}
+
+// setPath sets the connection of the uplink, nil if the uplink is disconnected. The packets of the connection are
+// read until it is closed.
+func (c *bondedConn) setPath(uplink int, conn net.Conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.paths[uplink] == conn {
+		return
+	}
+	c.paths[uplink] = conn
+	if conn != nil {
+		go c.readPath(conn)
+	}
+}
+
+// hasPath reports whether any uplink is connected
+func (c *bondedConn) hasPath() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.paths[uplinkPrimary] != nil || c.paths[uplinkSecondary] != nil
+}
+
+func (c *bondedConn) readPath(conn net.Conn) {
+	for {
+		buf := bondBufPool.Get().(*[]byte)
+		n, err := conn.Read(*buf)
+		if err != nil {
+			bondBufPool.Put(buf)
+			return
+		}
+
+		select {
+		case c.packets <- bondPacket{buf: buf, n: n}:
+		case <-c.closed:
+			bondBufPool.Put(buf)
+			return
+		}
+	}
+}
+
+func (c *bondedConn) Read(b []byte) (int, error) {
+	select {
+	case p := <-c.packets:
+		n := copy(b, (*p.buf)[:p.n])
+		bondBufPool.Put(p.buf)
+		return n, nil
+	case <-c.closed:
+		return 0, net.ErrClosed
+	}
+}
+
+func (c *bondedConn) Write(b []byte) (int, error) {
+	select {
+	case <-c.closed:
+		return 0, net.ErrClosed
+	default:
+	}
+
+	var lastErr error = errNoBondPath
+	for _, conn := range c.writeOrder() {
+		if conn == nil {
+			continue
+		}
+		n, err := conn.Write(b)
+		if err == nil {
+			return n, nil
+		}
+		lastErr = err
+	}
+	return 0, lastErr
+}
+
+// writeOrder returns the connections to try for the next packet. In spray mode the secondary uplink goes first for
+// its weight of the packets.
+func (c *bondedConn) writeOrder() [2]net.Conn {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	primary, secondary := c.paths[uplinkPrimary], c.paths[uplinkSecondary]
+	if c.mode != BondModeSpray || primary == nil || secondary == nil {
+		return [2]net.Conn{primary, secondary}
+	}
+
+	c.credit += c.sprayWeight
+	if c.credit >= 100 {
+		c.credit -= 100
+		return [2]net.Conn{secondary, primary}
+	}
+	return [2]net.Conn{primary, secondary}
+}
+
+// Close stops reading the uplinks and closes their connections
+func (c *bondedConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+
+	c.mu.Lock()
+	paths := c.paths
+	c.paths = [2]net.Conn{}
+	c.mu.Unlock()
+
+	var errs []error
+	for _, conn := range paths {
+		if conn == nil {
+			continue
+		}
+		if err := conn.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *bondedConn) LocalAddr() net.Addr {
+	if conn := c.activePath(); conn != nil {
+		return conn.LocalAddr()
+	}
+	return &net.UDPAddr{}
+}
+
+func (c *bondedConn) RemoteAddr() net.Addr {
+	if conn := c.activePath(); conn != nil {
+		return conn.RemoteAddr()
+	}
+	return &net.UDPAddr{}
+}
+
+// SetDeadline is not supported, the proxy stops reading by closing the connection
+func (c *bondedConn) SetDeadline(time.Time) error {
+	return nil
+}
+
+// SetReadDeadline is not supported, the proxy stops reading by closing the connection
+func (c *bondedConn) SetReadDeadline(time.Time) error {
+	return nil
+}
+
+// SetWriteDeadline is not supported, the writes to the uplinks don't block
+func (c *bondedConn) SetWriteDeadline(time.Time) error {
+	return nil
+}
+
+func (c *bondedConn) activePath() net.Conn {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.paths[uplinkPrimary] != nil {
+		return c.paths[uplinkPrimary]
+	}
+	return c.paths[uplinkSecondary]
+}
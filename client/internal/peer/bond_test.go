@@ -0,0 +1,160 @@
+package peer
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePathConn is an uplink connection recording the written packets
+type fakePathConn struct {
+	net.Conn
+	mu       sync.Mutex
+	written  int
+	writeErr error
+	incoming chan []byte
+	closed   chan struct{}
+	once     sync.Once
+}
+
+func newFakePathConn() *fakePathConn {
+	return &fakePathConn{incoming: make(chan []byte, 8), closed: make(chan struct{})}
+}
+
+func (c *fakePathConn) Read(b []byte) (int, error) {
+	select {
+	case p := <-c.incoming:
+		return copy(b, p), nil
+	case <-c.closed:
+		return 0, net.ErrClosed
+	}
+}
+
+func (c *fakePathConn) Write(b []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.writeErr != nil {
+		return 0, c.writeErr
+	}
+	c.written++
+	return len(b), nil
+}
+
+func (c *fakePathConn) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return nil
+}
+
+func (c *fakePathConn) writes() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.written
+}
+
+func TestBondConfig_Validate(t *testing.T) {
+	assert.NoError(t, BondConfig{Interface: "wwan1"}.Validate())
+	assert.NoError(t, BondConfig{Mode: BondModeSpray, SprayWeight: 30}.Validate())
+	assert.Error(t, BondConfig{Mode: "round-robin"}.Validate())
+	assert.Error(t, BondConfig{Mode: BondModeSpray, SprayWeight: 101}.Validate())
+
+	assert.Equal(t, BondModeStandby, BondConfig{}.mode())
+	assert.Equal(t, defaultSprayWeight, BondConfig{}.sprayWeight())
+}
+
+func TestBondedConn_Standby(t *testing.T) {
+	bonded := newBondedConn(BondConfig{})
+	defer bonded.Close()
+
+	primary, secondary := newFakePathConn(), newFakePathConn()
+	bonded.setPath(uplinkPrimary, primary)
+	bonded.setPath(uplinkSecondary, secondary)
+
+	for i := 0; i < 10; i++ {
+		_, err := bonded.Write([]byte("packet"))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 10, primary.writes())
+	assert.Equal(t, 0, secondary.writes())
+
+	primary.mu.Lock()
+	primary.writeErr = errors.New("uplink down")
+	primary.mu.Unlock()
+	_, err := bonded.Write([]byte("packet"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, secondary.writes(), "a failed write goes over the other uplink")
+
+	bonded.setPath(uplinkPrimary, nil)
+	_, err = bonded.Write([]byte("packet"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, secondary.writes())
+	assert.True(t, bonded.hasPath())
+
+	bonded.setPath(uplinkSecondary, nil)
+	assert.False(t, bonded.hasPath())
+	_, err = bonded.Write([]byte("packet"))
+	assert.ErrorIs(t, err, errNoBondPath)
+}
+
+func TestBondedConn_Spray(t *testing.T) {
+	bonded := newBondedConn(BondConfig{Mode: BondModeSpray, SprayWeight: 25})
+	defer bonded.Close()
+
+	primary, secondary := newFakePathConn(), newFakePathConn()
+	bonded.setPath(uplinkPrimary, primary)
+	bonded.setPath(uplinkSecondary, secondary)
+
+	for i := 0; i < 100; i++ {
+		_, err := bonded.Write([]byte("packet"))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 75, primary.writes())
+	assert.Equal(t, 25, secondary.writes())
+}
+
+func TestBondedConn_ReadsBothUplinks(t *testing.T) {
+	bonded := newBondedConn(BondConfig{})
+
+	primary, secondary := newFakePathConn(), newFakePathConn()
+	bonded.setPath(uplinkPrimary, primary)
+	bonded.setPath(uplinkSecondary, secondary)
+
+	primary.incoming <- []byte("from primary")
+	secondary.incoming <- []byte("from secondary")
+
+	received := map[string]bool{}
+	buf := make([]byte, 64)
+	for i := 0; i < 2; i++ {
+		n, err := bonded.Read(buf)
+		require.NoError(t, err)
+		received[string(buf[:n])] = true
+	}
+	assert.Equal(t, map[string]bool{"from primary": true, "from secondary": true}, received)
+
+	readErr := make(chan error, 1)
+	go func() {
+		_, err := bonded.Read(buf)
+		readErr <- err
+	}()
+
+	require.NoError(t, bonded.Close())
+	select {
+	case err := <-readErr:
+		assert.ErrorIs(t, err, net.ErrClosed)
+	case <-time.After(time.Second):
+		t.Fatal("read didn't return after close")
+	}
+
+	for _, path := range []*fakePathConn{primary, secondary} {
+		select {
+		case <-path.closed:
+		default:
+			t.Fatal("the uplink connection wasn't closed")
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package peer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/netbirdio/netbird/client/iface"
+	"github.com/netbirdio/netbird/client/iface/bufsize"
+	"github.com/netbirdio/netbird/client/iface/wgproxy"
+)
+
+const (
+	// proxyBufferBytes estimates the buffers of a local WireGuard proxy, one read buffer in each direction
+	proxyBufferBytes = 2 * (iface.DefaultMTU + bufsize.WGBufferOverhead)
+	// bondBufferBytes is the read queue of a bonded connection on top of its proxy
+	bondBufferBytes = bondReadQueueSize * bondReadBufferSize
+)
+
+// ErrResourceBudgetExceeded is returned when a peer connection can't be activated because of the resource limits
+var ErrResourceBudgetExceeded = errors.New("peer resource budget exceeded")
+
+// ResourceLimits are the hard caps of the resources used by all peer connections together. Zero means unlimited.
+type ResourceLimits struct {
+	MaxGoroutines  int
+	MaxICEAgents   int
+	MaxBufferBytes int64
+}
+
+// ResourceUsage is the resources used by peer connections
+type ResourceUsage struct {
+	Goroutines  int
+	ICEAgents   int
+	BufferBytes int64
+}
+
+// ResourceBudgetState is the aggregated usage of the peer connections with the limits
+type ResourceBudgetState struct {
+	ResourceUsage
+	Limits ResourceLimits
+	// Peers is the number of open peer connections
+	Peers int
+	// DeniedActivations is the number of lazy connection activations denied because of the limits
+	DeniedActivations int64
+	// DeniedICEAgents is the number of ICE attempts skipped because of the limits, the connection stayed on relay
+	DeniedICEAgents int64
+}
+
+// peerResources counts the resources of a single peer connection
+type peerResources struct {
+	goroutines  atomic.Int64
+	iceAgents   atomic.Int64
+	bufferBytes atomic.Int64
+}
+
+func (r *peerResources) usage() ResourceUsage {
+	return ResourceUsage{
+		Goroutines:  int(r.goroutines.Load()),
+		ICEAgents:   int(r.iceAgents.Load()),
+		BufferBytes: r.bufferBytes.Load(),
+	}
+}
+
+// ResourceBudget accounts the goroutines, the ICE agents and the buffers of the peer connections. Reaching a limit
+// doesn't break the established connections: new lazy activations are denied and new connections skip ICE and stay on
+// relay until resources are released.
+type ResourceBudget struct {
+	limits ResourceLimits
+	total  peerResources
+
+	deniedActivations atomic.Int64
+	deniedICEAgents   atomic.Int64
+
+	mu    sync.Mutex
+	peers map[string]*peerResources
+}
+
+func NewResourceBudget(limits ResourceLimits) *ResourceBudget {
+	return &ResourceBudget{
+		limits: limits,
+		peers:  make(map[string]*peerResources),
+	}
+}
+
+// Limits returns the configured limits
+func (b *ResourceBudget) Limits() ResourceLimits {
+	return b.limits
+}
+
+// Usage returns the resources used by all peer connections
+func (b *ResourceBudget) Usage() ResourceUsage {
+	return b.total.usage()
+}
+
+// PeerUsage returns the resources used by the connection of the peer
+func (b *ResourceBudget) PeerUsage(peerKey string) (ResourceUsage, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	res, ok := b.peers[peerKey]
+	if !ok {
+		return ResourceUsage{}, false
+	}
+	return res.usage(), true
+}
+
+// State returns the aggregated usage with the limits
+func (b *ResourceBudget) State() ResourceBudgetState {
+	b.mu.Lock()
+	peers := len(b.peers)
+	b.mu.Unlock()
+
+	return ResourceBudgetState{
+		ResourceUsage:     b.Usage(),
+		Limits:            b.limits,
+		Peers:             peers,
+		DeniedActivations: b.deniedActivations.Load(),
+		DeniedICEAgents:   b.deniedICEAgents.Load(),
+	}
+}
+
+// AllowActivation checks whether a new peer connection fits in the goroutine and buffer limits
+func (b *ResourceBudget) AllowActivation() error {
+	usage := b.Usage()
+	var err error
+	switch {
+	case b.limits.MaxGoroutines > 0 && usage.Goroutines >= b.limits.MaxGoroutines:
+		err = fmt.Errorf("%w: %d of %d goroutines in use", ErrResourceBudgetExceeded, usage.Goroutines, b.limits.MaxGoroutines)
+	case b.limits.MaxBufferBytes > 0 && usage.BufferBytes >= b.limits.MaxBufferBytes:
+		err = fmt.Errorf("%w: %d of %d buffer bytes in use", ErrResourceBudgetExceeded, usage.BufferBytes, b.limits.MaxBufferBytes)
+	default:
+		return nil
+	}
+
+	b.deniedActivations.Add(1)
+	return err
+}
+
+func (b *ResourceBudget) register(peerKey string) *peerResources {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	res, ok := b.peers[peerKey]
+	if !ok {
+		res = &peerResources{}
+		b.peers[peerKey] = res
+	}
+	return res
+}
+
+// unregister drops the peer from the aggregates. The resources still held by res keep being accounted in the totals
+// until they are released.
+func (b *ResourceBudget) unregister(peerKey string, res *peerResources) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.peers[peerKey] == res {
+		delete(b.peers, peerKey)
+	}
+}
+
+// goTracked runs f in a goroutine accounted to the peer
+func (b *ResourceBudget) goTracked(res *peerResources, f func()) {
+	res.goroutines.Add(1)
+	b.total.goroutines.Add(1)
+	go func() {
+		defer func() {
+			res.goroutines.Add(-1)
+			b.total.goroutines.Add(-1)
+		}()
+		f()
+	}()
+}
+
+// acquireICEAgent reserves an ICE agent for the peer. It returns false when the ICE agent limit is reached.
+func (b *ResourceBudget) acquireICEAgent(res *peerResources) bool {
+	if n := b.total.iceAgents.Add(1); b.limits.MaxICEAgents > 0 && n > int64(b.limits.MaxICEAgents) {
+		b.total.iceAgents.Add(-1)
+		b.deniedICEAgents.Add(1)
+		return false
+	}
+	res.iceAgents.Add(1)
+	return true
+}
+
+func (b *ResourceBudget) releaseICEAgent(res *peerResources) {
+	res.iceAgents.Add(-1)
+	b.total.iceAgents.Add(-1)
+}
+
+// allocBuffers accounts buffers of the peer, negative size releases them
+func (b *ResourceBudget) allocBuffers(res *peerResources, size int64) {
+	res.bufferBytes.Add(size)
+	b.total.bufferBytes.Add(size)
+}
+
+// accountedProxy releases the accounted buffers of the proxy once it is closed
+type accountedProxy struct {
+	wgproxy.Proxy
+	once    sync.Once
+	release func()
+}
+
+func newAccountedProxy(proxy wgproxy.Proxy, budget *ResourceBudget, res *peerResources, bufferBytes int64) *accountedProxy {
+	budget.allocBuffers(res, bufferBytes)
+	return &accountedProxy{
+		Proxy: proxy,
+		release: func() {
+			budget.allocBuffers(res, -bufferBytes)
+		},
+	}
+}
+
+func (p *accountedProxy) CloseConn() error {
+	p.once.Do(p.release)
+	return p.Proxy.CloseConn()
+}
@@ -0,0 +1,103 @@
+package peer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/iface/wgproxy"
+)
+
+type closeCountingProxy struct {
+	wgproxy.Proxy
+	closed int
+}
+
+func (p *closeCountingProxy) CloseConn() error {
+	p.closed++
+	return nil
+}
+
+func TestResourceBudget_Goroutines(t *testing.T) {
+	budget := NewResourceBudget(ResourceLimits{MaxGoroutines: 2})
+	res := budget.register("peer1")
+
+	release := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		budget.goTracked(res, func() {
+			<-release
+		})
+	}
+
+	usage, ok := budget.PeerUsage("peer1")
+	require.True(t, ok)
+	assert.Equal(t, 2, usage.Goroutines)
+	assert.ErrorIs(t, budget.AllowActivation(), ErrResourceBudgetExceeded)
+	assert.Equal(t, int64(1), budget.State().DeniedActivations)
+
+	close(release)
+	require.Eventually(t, func() bool {
+		return budget.Usage().Goroutines == 0
+	}, time.Second, 10*time.Millisecond)
+	assert.NoError(t, budget.AllowActivation())
+}
+
+func TestResourceBudget_ICEAgents(t *testing.T) {
+	budget := NewResourceBudget(ResourceLimits{MaxICEAgents: 1})
+	res1 := budget.register("peer1")
+	res2 := budget.register("peer2")
+
+	require.True(t, budget.acquireICEAgent(res1))
+	assert.False(t, budget.acquireICEAgent(res2), "the second agent is over the limit")
+	assert.NoError(t, budget.AllowActivation(), "the ICE agent limit keeps the connections on relay only")
+
+	budget.releaseICEAgent(res1)
+	assert.True(t, budget.acquireICEAgent(res2))
+
+	state := budget.State()
+	assert.Equal(t, 1, state.ICEAgents)
+	assert.Equal(t, 2, state.Peers)
+	assert.Equal(t, int64(1), state.DeniedICEAgents)
+}
+
+func TestResourceBudget_Buffers(t *testing.T) {
+	budget := NewResourceBudget(ResourceLimits{MaxBufferBytes: proxyBufferBytes})
+	res := budget.register("peer1")
+
+	inner := &closeCountingProxy{}
+	proxy := newAccountedProxy(inner, budget, res, proxyBufferBytes)
+	assert.Equal(t, int64(proxyBufferBytes), budget.Usage().BufferBytes)
+	err := budget.AllowActivation()
+	assert.ErrorIs(t, err, ErrResourceBudgetExceeded)
+	assert.Contains(t, err.Error(), "buffer bytes in use")
+
+	require.NoError(t, proxy.CloseConn())
+	require.NoError(t, proxy.CloseConn())
+	assert.Equal(t, 2, inner.closed)
+	assert.Equal(t, int64(0), budget.Usage().BufferBytes, "the buffers are released once")
+}
+
+func TestResourceBudget_Unregister(t *testing.T) {
+	budget := NewResourceBudget(ResourceLimits{})
+	res := budget.register("peer1")
+	budget.allocBuffers(res, 100)
+
+	budget.unregister("peer1", res)
+	_, ok := budget.PeerUsage("peer1")
+	assert.False(t, ok)
+	assert.Equal(t, int64(100), budget.Usage().BufferBytes, "the totals keep the resources until they are released")
+
+	budget.allocBuffers(res, -100)
+	assert.Equal(t, ResourceUsage{}, budget.Usage())
+}
+
+func TestResourceLimitsFromEnv(t *testing.T) {
+	t.Setenv(EnvKeyNBPeerMaxGoroutines, "5000")
+	t.Setenv(EnvKeyNBPeerMaxICEAgents, "invalid")
+	t.Setenv(EnvKeyNBPeerMaxBufferBytes, "1073741824")
+
+	limits := ResourceLimitsFromEnv()
+	assert.Equal(t, ResourceLimits{MaxGoroutines: 5000, MaxBufferBytes: 1 << 30}, limits)
+}
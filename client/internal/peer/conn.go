@@ -8,6 +8,7 @@ import (
 	"net/netip"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pion/ice/v4"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/netbirdio/netbird/client/iface/configurer"
 	"github.com/netbirdio/netbird/client/iface/wgproxy"
+	"github.com/netbirdio/netbird/client/internal/lazyconn"
 	"github.com/netbirdio/netbird/client/internal/peer/conntype"
 	"github.com/netbirdio/netbird/client/internal/peer/dispatcher"
 	"github.com/netbirdio/netbird/client/internal/peer/guard"
@@ -25,6 +27,7 @@ import (
 	"github.com/netbirdio/netbird/client/internal/stdnet"
 	"github.com/netbirdio/netbird/route"
 	relayClient "github.com/netbirdio/netbird/shared/relay/client"
+	signal "github.com/netbirdio/netbird/shared/signal/client"
 	semaphoregroup "github.com/netbirdio/netbird/util/semaphore-group"
 )
 
@@ -36,6 +39,8 @@ type ServiceDependencies struct {
 	SrWatcher          *guard.SRWatcher
 	Semaphore          *semaphoregroup.SemaphoreGroup
 	PeerConnDispatcher *dispatcher.ConnectionDispatcher
+	// Budget accounts the resources of the connections, unlimited if nil
+	Budget *ResourceBudget
 }
 
 type WgConfig struct {
@@ -44,6 +49,34 @@ type WgConfig struct {
 	WgInterface  WGIface
 	AllowedIps   []netip.Prefix
 	PreSharedKey *wgtypes.Key
+
+	// PersistentKeepalive overrides the default WireGuard keepalive interval for this peer. Zero means default.
+	PersistentKeepalive time.Duration
+	// HandshakeTimeout overrides the allowed delay of the WireGuard handshake before the connection
+	// is considered broken. Zero means default.
+	HandshakeTimeout time.Duration
+	// HandshakeRetryInterval overrides the initial interval between the connection offers sent
+	// while the connection is not established. Zero means default.
+	HandshakeRetryInterval time.Duration
+	// ResponderDelay overrides how long the responding side waits for the handshake of the initiator
+	// before it configures the remote endpoint itself. Zero means default.
+	ResponderDelay time.Duration
+}
+
+// keepAlive returns the persistent keepalive interval that should be programmed for the peer
+func (c WgConfig) keepAlive() time.Duration {
+	if c.PersistentKeepalive > 0 {
+		return c.PersistentKeepalive
+	}
+	return defaultWgKeepAlive
+}
+
+// responderDelay returns how long the responding side waits before it configures the remote endpoint
+func (c WgConfig) responderDelay() time.Duration {
+	if c.ResponderDelay > 0 {
+		return c.ResponderDelay
+	}
+	return fallbackDelay
 }
 
 type RosenpassConfig struct {
@@ -64,7 +97,8 @@ type ConnConfig struct {
 
 	AgentVersion string
 
-	Timeout time.Duration
+	// Backoff controls the connection timeout and the offer retries
+	Backoff BackoffPolicy
 
 	WgConfig WgConfig
 
@@ -74,6 +108,22 @@ type ConnConfig struct {
 
 	// ICEConfig ICE protocol configuration
 	ICEConfig icemaker.Config
+
+	// LazyInactivity overrides the idle detection of the lazy connection, nil keeps the local settings
+	LazyInactivity *lazyconn.InactivityConfig
+
+	// Bond bonds the connection over two uplinks, nil bonds it only if the remote peer offers a bond
+	Bond *BondConfig
+
+	// Priming is the last connection to the peer that succeeded, nil if it isn't known
+	Priming *ConnPriming
+}
+
+// connBond is the bond of a connection, the local one or the one the remote peer offered
+type connBond struct {
+	config BondConfig
+	// worker is the ICE worker of the secondary uplink
+	worker *WorkerICE
 }
 
 type Conn struct {
@@ -95,6 +145,16 @@ type Conn struct {
 	statusICE           *worker.AtomicWorkerStatus
 	currentConnPriority conntype.ConnPriority
 	opened              bool // this flag is used to prevent close in case of not opened connection
+	// iceActiveSince is when the ICE connection became the active one, zero if it isn't active
+	iceActiveSince time.Time
+	// lastStaleRecovery is when the handshake watchdog last recovered the connection
+	lastStaleRecovery time.Time
+	// relayForced keeps the connection on the relay, ICE isn't used while it is set
+	relayForced bool
+	// candidatePairs are the ICE candidate pairs the connection selected, the most recent last
+	candidatePairs []CandidatePair
+	// relayServer is the relay server instance of the last relayed connection
+	relayServer string
 
 	workerICE   *WorkerICE
 	workerRelay *WorkerRelay
@@ -103,14 +163,28 @@ type Conn struct {
 	// used to store the remote Rosenpass key for Relayed connection in case of connection update from ice
 	rosenpassRemoteKey []byte
 
+	// remoteFeatures are the features announced by the remote peer, nil until it announced any
+	remoteFeatures atomic.Pointer[Features]
+
 	wgProxyICE   wgproxy.Proxy
 	wgProxyRelay wgproxy.Proxy
 	handshaker   *Handshaker
 
+	// bond is the bond of the connection over two uplinks, nil if the connection isn't bonded
+	bond atomic.Pointer[connBond]
+	// bonded carries the ICE connections of the uplinks while wgProxyICE proxies it, nil otherwise
+	bonded *bondedConn
+	// bondPaths are the connected ICE connections of the uplinks of the bond
+	bondPaths [2]net.Conn
+
 	guard     *guard.Guard
 	semaphore *semaphoregroup.SemaphoreGroup
 	wg        sync.WaitGroup
 
+	budget *ResourceBudget
+	// resources are the resources of the connection accounted in the budget, set while the connection is open
+	resources *peerResources
+
 	// debug purpose
 	dumpState *stateDump
 
@@ -126,6 +200,11 @@ func NewConn(config ConnConfig, services ServiceDependencies) (*Conn, error) {
 
 	connLog := log.WithField("peer", config.Key)
 
+	budget := services.Budget
+	if budget == nil {
+		budget = NewResourceBudget(ResourceLimits{})
+	}
+
 	var conn = &Conn{
 		Log:             connLog,
 		config:          config,
@@ -135,6 +214,7 @@ func NewConn(config ConnConfig, services ServiceDependencies) (*Conn, error) {
 		relayManager:    services.RelayManager,
 		srWatcher:       services.SrWatcher,
 		semaphore:       services.Semaphore,
+		budget:          budget,
 		statusRelay:     worker.NewAtomicStatus(),
 		statusICE:       worker.NewAtomicStatus(),
 		dumpState:       newStateDump(config.Key, connLog, services.StatusRecorder),
@@ -161,6 +241,7 @@ func (conn *Conn) Open(engineCtx context.Context) error {
 	}
 
 	conn.ctx, conn.ctxCancel = context.WithCancel(engineCtx)
+	conn.resources = conn.budget.register(conn.config.Key)
 
 	conn.workerRelay = NewWorkerRelay(conn.ctx, conn.Log, isController(conn.config), conn.config, conn, conn.relayManager, conn.dumpState)
 
@@ -172,21 +253,45 @@ func (conn *Conn) Open(engineCtx context.Context) error {
 	}
 	conn.workerICE = workerICE
 
+	if conn.config.Bond != nil && !isForceRelayed() {
+		if _, err := conn.enableBond(*conn.config.Bond); err != nil {
+			conn.Log.Errorf("failed to bond the connection, using a single uplink: %v", err)
+		}
+	}
+
 	conn.handshaker = NewHandshaker(conn.Log, conn.config, conn.signaler, conn.workerICE, conn.workerRelay)
 
 	conn.handshaker.AddRelayListener(conn.workerRelay.OnNewOffer)
 	if !isForceRelayed() {
 		conn.handshaker.AddICEListener(conn.workerICE.OnNewOffer)
+		conn.handshaker.AddBondListener(conn.onRemoteBond, conn.localBondOffer)
 	}
 
-	conn.guard = guard.NewGuard(conn.Log, conn.isConnectedOnAllWay, conn.config.Timeout, conn.srWatcher)
+	conn.guard = guard.NewGuard(conn.Log, conn.isConnectedOnAllWay, conn.config.Backoff.connTimeout(), conn.srWatcher)
+	conn.guard.SetRetryInterval(conn.config.WgConfig.HandshakeRetryInterval)
+	conn.guard.SetRetryLimits(conn.config.Backoff.jitter(), conn.config.Backoff.MaxRetries)
 
 	conn.wg.Add(1)
-	go func() {
+	conn.budget.goTracked(conn.resources, func() {
 		defer conn.wg.Done()
 		conn.handshaker.Listen(conn.ctx)
-	}()
-	go conn.dumpState.Start(conn.ctx)
+	})
+	ctx := conn.ctx
+	conn.budget.goTracked(conn.resources, func() {
+		conn.dumpState.Start(ctx)
+	})
+
+	conn.wg.Add(1)
+	conn.budget.goTracked(conn.resources, func() {
+		defer conn.wg.Done()
+		conn.watchHalfOpen(conn.ctx)
+	})
+
+	conn.wg.Add(1)
+	conn.budget.goTracked(conn.resources, func() {
+		defer conn.wg.Done()
+		conn.watchHandshake(conn.ctx)
+	})
 
 	peerState := State{
 		PubKey:           conn.config.Key,
@@ -199,14 +304,14 @@ func (conn *Conn) Open(engineCtx context.Context) error {
 	}
 
 	conn.wg.Add(1)
-	go func() {
+	conn.budget.goTracked(conn.resources, func() {
 		defer conn.wg.Done()
 
 		conn.waitInitialRandomSleepTime(conn.ctx)
 		conn.semaphore.Done()
 
 		conn.guard.Start(conn.ctx, conn.onGuardEvent)
-	}()
+	})
 	conn.opened = true
 	return nil
 }
@@ -234,6 +339,9 @@ func (conn *Conn) Close(signalToRemote bool) {
 	conn.workerRelay.DisableWgWatcher()
 	conn.workerRelay.CloseConn()
 	conn.workerICE.Close()
+	if b := conn.bond.Swap(nil); b != nil {
+		b.worker.Close()
+	}
 
 	if conn.wgProxyRelay != nil {
 		err := conn.wgProxyRelay.CloseConn()
@@ -250,6 +358,8 @@ func (conn *Conn) Close(signalToRemote bool) {
 		}
 		conn.wgProxyICE = nil
 	}
+	conn.bonded = nil
+	conn.bondPaths = [2]net.Conn{}
 
 	if err := conn.endpointUpdater.RemoveWgPeer(); err != nil {
 		conn.Log.Errorf("failed to remove wg endpoint: %v", err)
@@ -260,16 +370,49 @@ func (conn *Conn) Close(signalToRemote bool) {
 	}
 
 	conn.setStatusToDisconnected()
+	conn.iceActiveSince = time.Time{}
+	conn.closeCandidatePair(time.Now())
+	conn.relayForced = false
 	conn.opened = false
 	conn.wg.Wait()
+	conn.budget.unregister(conn.config.Key, conn.resources)
 	conn.Log.Infof("peer connection closed")
 }
 
+// RestartICE renews the ICE session and sends a new offer to the remote peer. It is used after a local network
+// change, when the selected candidate pair may use an address that is gone. The relayed connection and the
+// WireGuard peer are kept, the traffic moves over once the new ICE connection is ready.
+func (conn *Conn) RestartICE() {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if !conn.opened || isForceRelayed() {
+		return
+	}
+
+	if err := conn.workerICE.RenewSession(); err != nil {
+		conn.Log.Errorf("failed to renew ICE session: %v", err)
+		return
+	}
+	if b := conn.bond.Load(); b != nil {
+		if err := b.worker.RenewSession(); err != nil {
+			conn.Log.Errorf("failed to renew ICE session of the secondary uplink: %v", err)
+		}
+	}
+
+	conn.Log.Debugf("restarting ICE after a network change")
+	conn.dumpState.SendOffer()
+	if err := conn.handshaker.SendOffer(); err != nil {
+		conn.Log.Errorf("failed to send offer: %v", err)
+	}
+}
+
 // OnRemoteAnswer handles an offer from the remote peer and returns true if the message was accepted, false otherwise
 // doesn't block, discards the message if connection wasn't ready
 func (conn *Conn) OnRemoteAnswer(answer OfferAnswer) {
 	conn.dumpState.RemoteAnswer()
 	conn.Log.Infof("OnRemoteAnswer, priority: %s, status ICE: %s, status relay: %s", conn.currentConnPriority, conn.statusICE, conn.statusRelay)
+	conn.setRemoteFeatures(answer.Features)
 	conn.handshaker.OnRemoteAnswer(answer)
 }
 
@@ -279,6 +422,14 @@ func (conn *Conn) OnRemoteCandidate(candidate ice.Candidate, haRoutes route.HAMa
 	conn.workerICE.OnRemoteCandidate(candidate, haRoutes)
 }
 
+// OnRemoteBondCandidate handles a candidate of the ICE session on the secondary uplink of a bonded connection
+func (conn *Conn) OnRemoteBondCandidate(candidate ice.Candidate, haRoutes route.HAMap) {
+	conn.dumpState.RemoteCandidate()
+	if b := conn.bond.Load(); b != nil {
+		b.worker.OnRemoteCandidate(candidate, haRoutes)
+	}
+}
+
 // SetOnConnected sets a handler function to be triggered by Conn when a new connection to a remote peer established
 func (conn *Conn) SetOnConnected(handler func(remoteWireGuardKey string, remoteRosenpassPubKey []byte, wireGuardIP string, remoteRosenpassAddr string)) {
 	conn.onConnected = handler
@@ -292,9 +443,40 @@ func (conn *Conn) SetOnDisconnected(handler func(remotePeer string)) {
 func (conn *Conn) OnRemoteOffer(offer OfferAnswer) {
 	conn.dumpState.RemoteOffer()
 	conn.Log.Infof("OnRemoteOffer, on status ICE: %s, status Relay: %s", conn.statusICE, conn.statusRelay)
+	conn.setRemoteFeatures(offer.Features)
 	conn.handshaker.OnRemoteOffer(offer)
 }
 
+// RemoteFeatures returns the features announced by the remote peer. It returns false if the peer didn't announce
+// any yet, e.g. because no offer was received or it runs a version without feature negotiation.
+func (conn *Conn) RemoteFeatures() (Features, bool) {
+	f := conn.remoteFeatures.Load()
+	if f == nil {
+		return 0, false
+	}
+	return *f, true
+}
+
+// SupportsLazyConnection reports whether the remote peer can be connected lazily. The agent version is only
+// used until the peer announced its features.
+func (conn *Conn) SupportsLazyConnection() bool {
+	if f, ok := conn.RemoteFeatures(); ok {
+		return f.Has(signal.FeatureLazyConnection)
+	}
+	return lazyconn.IsSupported(conn.AgentVersionString())
+}
+
+func (conn *Conn) setRemoteFeatures(f Features) {
+	// peers without feature negotiation don't announce anything
+	if f == 0 {
+		return
+	}
+
+	if old := conn.remoteFeatures.Swap(&f); old == nil || *old != f {
+		conn.Log.Infof("remote peer features: %s", f)
+	}
+}
+
 // WgConfig returns the WireGuard config
 func (conn *Conn) WgConfig() WgConfig {
 	return conn.config.WgConfig
@@ -339,6 +521,13 @@ func (conn *Conn) onICEConnectionIsReady(priority conntype.ConnPriority, iceConn
 		return
 	}
 
+	if conn.relayForced && conn.wgProxyRelay != nil {
+		conn.Log.Infof("relay is forced, do not upgrade connection to ICE")
+		conn.statusICE.SetConnected()
+		conn.updateIceState(iceConnInfo)
+		return
+	}
+
 	conn.Log.Infof("set ICE to active connection")
 	conn.dumpState.P2PConnected()
 
@@ -347,9 +536,16 @@ func (conn *Conn) onICEConnectionIsReady(priority conntype.ConnPriority, iceConn
 		wgProxy wgproxy.Proxy
 		err     error
 	)
-	if iceConnInfo.RelayedOnLocal {
+	if conn.bond.Load() != nil {
+		wgProxy, err = conn.attachBondPath(uplinkPrimary, iceConnInfo.RemoteConn)
+		if err != nil {
+			conn.Log.Errorf("failed to add bonded net.Conn to local proxy: %v", err)
+			return
+		}
+		ep = wgProxy.EndpointAddr()
+	} else if iceConnInfo.RelayedOnLocal {
 		conn.dumpState.NewLocalProxy()
-		wgProxy, err = conn.newProxy(iceConnInfo.RemoteConn)
+		wgProxy, err = conn.newProxy(iceConnInfo.RemoteConn, proxyBufferBytes)
 		if err != nil {
 			conn.Log.Errorf("failed to add turn net.Conn to local proxy: %v", err)
 			return
@@ -381,6 +577,7 @@ func (conn *Conn) onICEConnectionIsReady(priority conntype.ConnPriority, iceConn
 	presharedKey := conn.presharedKey(iceConnInfo.RosenpassPubKey)
 	if err = conn.endpointUpdater.ConfigureWGEndpoint(ep, presharedKey); err != nil {
 		conn.handleConfigurationFailure(err, wgProxy)
+		conn.bonded = nil
 		return
 	}
 	wgConfigWorkaround()
@@ -391,6 +588,8 @@ func (conn *Conn) onICEConnectionIsReady(priority conntype.ConnPriority, iceConn
 	}
 
 	conn.currentConnPriority = priority
+	conn.iceActiveSince = time.Now()
+	conn.recordCandidatePair(iceConnInfo, conn.iceActiveSince)
 	conn.statusICE.SetConnected()
 	conn.updateIceState(iceConnInfo)
 	conn.doOnConnected(iceConnInfo.RosenpassPubKey, iceConnInfo.RosenpassAddr)
@@ -404,16 +603,37 @@ func (conn *Conn) onICEStateDisconnected() {
 		return
 	}
 
+	conn.bondPaths[uplinkPrimary] = nil
+	if conn.bonded != nil {
+		conn.bonded.setPath(uplinkPrimary, nil)
+		if conn.bonded.hasPath() && conn.isICEActive() {
+			conn.Log.Infof("ICE disconnected on the primary uplink, the connection continues over the secondary uplink")
+			return
+		}
+	}
+
+	conn.iceDisconnected()
+}
+
+// iceDisconnected switches the connection back to the relay, or resets it if the relay isn't connected. The caller
+// must hold mu.
+func (conn *Conn) iceDisconnected() {
 	conn.Log.Tracef("ICE connection state changed to disconnected")
+	conn.iceActiveSince = time.Time{}
+	conn.closeCandidatePair(time.Now())
 
 	if conn.wgProxyICE != nil {
 		if err := conn.wgProxyICE.CloseConn(); err != nil {
 			conn.Log.Warnf("failed to close deprecated wg proxy conn: %v", err)
 		}
 	}
+	conn.bonded = nil
 
 	// switch back to relay connection
-	if conn.isReadyToUpgrade() {
+	if conn.currentConnPriority == conntype.Relay {
+		// ICE wasn't in use, e.g. because the relay is forced
+		conn.Log.Infof("ICE disconnected, Relay stays the active connection")
+	} else if conn.isReadyToUpgrade() {
 		conn.Log.Infof("ICE disconnected, set Relay to active connection")
 		conn.dumpState.SwitchToRelay()
 		conn.wgProxyRelay.Work()
@@ -424,10 +644,10 @@ func (conn *Conn) onICEStateDisconnected() {
 		}
 
 		conn.wgWatcherWg.Add(1)
-		go func() {
+		conn.budget.goTracked(conn.resources, func() {
 			defer conn.wgWatcherWg.Done()
 			conn.workerRelay.EnableWgWatcher(conn.ctx)
-		}()
+		})
 		conn.wgProxyRelay.Work()
 		conn.currentConnPriority = conntype.Relay
 	} else {
@@ -457,6 +677,134 @@ func (conn *Conn) onICEStateDisconnected() {
 	}
 }
 
+// enableBond creates the ICE worker of the secondary uplink and makes the ICE connections carry the WireGuard traffic
+// through the bonded connection
+func (conn *Conn) enableBond(config BondConfig) (*connBond, error) {
+	bondConfig := conn.config
+	bondConfig.ICEConfig.BindInterface = config.Interface
+
+	worker, err := newBondWorkerICE(conn.ctx, conn.Log.WithField("uplink", "secondary"), bondConfig, conn, conn.signaler, conn.iFaceDiscover, conn.statusRecorder, conn.workerRelay.RelayIsSupportedLocally())
+	if err != nil {
+		return nil, fmt.Errorf("create ICE worker of the secondary uplink: %w", err)
+	}
+	conn.workerICE.setBonded(config.Interface)
+
+	b := &connBond{config: config, worker: worker}
+	conn.bond.Store(b)
+	conn.Log.Infof("bonding the connection over two uplinks, mode %s", config.mode())
+	return b, nil
+}
+
+// onRemoteBond handles the bond the remote peer offers. The connection is bonded if it isn't yet, the secondary
+// uplink is the one of the remote peer then.
+func (conn *Conn) onRemoteBond(remoteOfferAnswer *OfferAnswer) {
+	remoteBond := remoteOfferAnswer.Bond
+	if remoteBond == nil || conn.ctx.Err() != nil {
+		return
+	}
+
+	b := conn.bond.Load()
+	if b == nil {
+		var err error
+		b, err = conn.enableBond(BondConfig{Mode: remoteBond.Mode, SprayWeight: remoteBond.SprayWeight})
+		if err != nil {
+			conn.Log.Errorf("failed to bond the connection offered by the remote peer: %v", err)
+			return
+		}
+	}
+	b.worker.OnNewOffer(remoteBond.offerAnswer(remoteOfferAnswer))
+}
+
+// localBondOffer returns the ICE session on the secondary uplink for the offers and answers, nil if the connection
+// isn't bonded
+func (conn *Conn) localBondOffer() *BondOffer {
+	b := conn.bond.Load()
+	if b == nil {
+		return nil
+	}
+
+	uFrag, pwd := b.worker.GetLocalUserCredentials()
+	sessionID := b.worker.SessionID()
+	return &BondOffer{
+		IceCredentials: IceCredentials{UFrag: uFrag, Pwd: pwd},
+		SessionID:      &sessionID,
+		Mode:           b.config.mode(),
+		SprayWeight:    b.config.sprayWeight(),
+	}
+}
+
+// attachBondPath attaches the ICE connection of the uplink to the bonded connection and returns the proxy of the
+// bonded connection. The proxy is created if the bonded connection isn't proxied yet. The caller must hold mu.
+func (conn *Conn) attachBondPath(uplink int, remoteConn net.Conn) (wgproxy.Proxy, error) {
+	conn.bondPaths[uplink] = remoteConn
+	if conn.bonded != nil {
+		conn.bonded.setPath(uplink, remoteConn)
+		return conn.wgProxyICE, nil
+	}
+
+	bonded := newBondedConn(conn.bond.Load().config)
+	wgProxy, err := conn.newProxy(bonded, proxyBufferBytes+bondBufferBytes)
+	if err != nil {
+		return nil, err
+	}
+	conn.dumpState.NewLocalProxy()
+
+	for u, path := range conn.bondPaths {
+		if path != nil {
+			bonded.setPath(u, path)
+		}
+	}
+	conn.bonded = bonded
+	conn.wgProxyICE = wgProxy
+	return wgProxy, nil
+}
+
+// onBondPathReady attaches the ICE connection of the secondary uplink. It is a standby of the primary uplink, the
+// bonded connection is only proxied once the primary uplink is connected.
+func (conn *Conn) onBondPathReady(iceConnInfo ICEConnInfo) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if conn.ctx.Err() != nil {
+		return
+	}
+
+	if remoteConnNil(conn.Log, iceConnInfo.RemoteConn) {
+		conn.Log.Errorf("remote ICE connection of the secondary uplink is nil")
+		return
+	}
+
+	conn.Log.Infof("ICE connected on the secondary uplink, local %s %s, remote %s %s", iceConnInfo.LocalIceCandidateType,
+		iceConnInfo.LocalIceCandidateEndpoint, iceConnInfo.RemoteIceCandidateType, iceConnInfo.RemoteIceCandidateEndpoint)
+	conn.bondPaths[uplinkSecondary] = iceConnInfo.RemoteConn
+	if conn.bonded != nil {
+		conn.bonded.setPath(uplinkSecondary, iceConnInfo.RemoteConn)
+	}
+}
+
+// onBondPathDisconnected detaches the ICE connection of the secondary uplink. If the primary uplink failed before,
+// the connection falls back like on a disconnected ICE connection.
+func (conn *Conn) onBondPathDisconnected() {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if conn.ctx.Err() != nil {
+		return
+	}
+
+	conn.Log.Infof("ICE disconnected on the secondary uplink")
+	conn.bondPaths[uplinkSecondary] = nil
+	if conn.bonded == nil {
+		return
+	}
+
+	conn.bonded.setPath(uplinkSecondary, nil)
+	if conn.bonded.hasPath() {
+		return
+	}
+	conn.iceDisconnected()
+}
+
 func (conn *Conn) onRelayConnectionIsReady(rci RelayConnInfo) {
 	conn.mu.Lock()
 	defer conn.mu.Unlock()
@@ -471,7 +819,7 @@ func (conn *Conn) onRelayConnectionIsReady(rci RelayConnInfo) {
 	conn.dumpState.RelayConnected()
 	conn.Log.Debugf("Relay connection has been established, setup the WireGuard")
 
-	wgProxy, err := conn.newProxy(rci.relayedConn)
+	wgProxy, err := conn.newProxy(rci.relayedConn, proxyBufferBytes)
 	if err != nil {
 		conn.Log.Errorf("failed to add relayed net.Conn to local proxy: %v", err)
 		return
@@ -486,7 +834,8 @@ func (conn *Conn) onRelayConnectionIsReady(rci RelayConnInfo) {
 		conn.Log.Debugf("do not switch to relay because current priority is: %s", conn.currentConnPriority.String())
 		conn.setRelayedProxy(wgProxy)
 		conn.statusRelay.SetConnected()
-		conn.updateRelayStatus(rci.relayedConn.RemoteAddr().String(), rci.rosenpassPubKey)
+		conn.relayServer = rci.relayedConn.RemoteAddr().String()
+		conn.updateRelayStatus(conn.relayServer, rci.rosenpassPubKey)
 		return
 	}
 
@@ -501,17 +850,18 @@ func (conn *Conn) onRelayConnectionIsReady(rci RelayConnInfo) {
 	}
 
 	conn.wgWatcherWg.Add(1)
-	go func() {
+	conn.budget.goTracked(conn.resources, func() {
 		defer conn.wgWatcherWg.Done()
 		conn.workerRelay.EnableWgWatcher(conn.ctx)
-	}()
+	})
 
 	wgConfigWorkaround()
 	conn.rosenpassRemoteKey = rci.rosenpassPubKey
 	conn.currentConnPriority = conntype.Relay
 	conn.statusRelay.SetConnected()
 	conn.setRelayedProxy(wgProxy)
-	conn.updateRelayStatus(rci.relayedConn.RemoteAddr().String(), rci.rosenpassPubKey)
+	conn.relayServer = rci.relayedConn.RemoteAddr().String()
+	conn.updateRelayStatus(conn.relayServer, rci.rosenpassPubKey)
 	conn.Log.Infof("start to communicate with peer via relay")
 	conn.doOnConnected(rci.rosenpassPubKey, rci.rosenpassAddr)
 }
@@ -669,7 +1019,7 @@ func (conn *Conn) isConnectedOnAllWay() (connected bool) {
 		}
 	}()
 
-	if runtime.GOOS != "js" && conn.statusICE.Get() == worker.StatusDisconnected && !conn.workerICE.InProgress() {
+	if runtime.GOOS != "js" && !conn.isRelayOnly() && conn.statusICE.Get() == worker.StatusDisconnected && !conn.workerICE.InProgress() {
 		return false
 	}
 
@@ -682,7 +1032,23 @@ func (conn *Conn) isConnectedOnAllWay() (connected bool) {
 	return true
 }
 
-func (conn *Conn) newProxy(remoteConn net.Conn) (wgproxy.Proxy, error) {
+// isRelayOnly reports whether the ICE policy leaves the connection to the relay service
+func (conn *Conn) isRelayOnly() bool {
+	return conn.config.ICEConfig.Policy == icemaker.PolicyRelayOnly && conn.workerRelay.IsRelayConnectionSupportedWithPeer()
+}
+
+// ICEPolicy returns the ICE policy of the connection
+func (conn *Conn) ICEPolicy() icemaker.Policy {
+	return conn.config.ICEConfig.Policy
+}
+
+// LazyInactivity returns the lazy connection idle detection override of the connection
+func (conn *Conn) LazyInactivity() *lazyconn.InactivityConfig {
+	return conn.config.LazyInactivity
+}
+
+// newProxy creates the local proxy of the remote connection and accounts its buffers until the proxy is closed
+func (conn *Conn) newProxy(remoteConn net.Conn, bufferBytes int64) (wgproxy.Proxy, error) {
 	conn.Log.Debugf("setup proxied WireGuard connection")
 	udpAddr := &net.UDPAddr{
 		IP:   conn.config.WgConfig.AllowedIps[0].Addr().AsSlice(),
@@ -694,7 +1060,7 @@ func (conn *Conn) newProxy(remoteConn net.Conn) (wgproxy.Proxy, error) {
 		conn.Log.Errorf("failed to add turn net.Conn to local proxy: %v", err)
 		return nil, err
 	}
-	return wgProxy, nil
+	return newAccountedProxy(wgProxy, conn.budget, conn.resources, bufferBytes), nil
 }
 
 func (conn *Conn) isReadyToUpgrade() bool {
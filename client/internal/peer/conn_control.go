@@ -0,0 +1,126 @@
+package peer
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+const candidatePairHistorySize = 10
+
+var (
+	// ErrConnNotOpened is returned by the connection controls if the connection is closed, e.g. idle in lazy mode
+	ErrConnNotOpened = errors.New("peer connection is not open")
+	// ErrNoRelayedConn is returned if the connection is forced to the relay without a relayed connection
+	ErrNoRelayedConn = errors.New("no relayed connection to the peer")
+)
+
+// CandidatePair is an ICE candidate pair the connection selected
+type CandidatePair struct {
+	LocalType      string
+	LocalEndpoint  string
+	RemoteType     string
+	RemoteEndpoint string
+	// Relayed is set if the pair goes through a TURN server
+	Relayed    bool
+	SelectedAt time.Time
+	// ClosedAt is when the pair stopped being used, zero while it is in use
+	ClosedAt time.Time
+}
+
+// Reconnect closes the connection and opens it again, it renews the ICE and relayed connections and the WireGuard
+// endpoint of the peer
+func (conn *Conn) Reconnect(engineCtx context.Context) error {
+	conn.mu.Lock()
+	opened := conn.opened
+	conn.mu.Unlock()
+
+	if !opened {
+		return ErrConnNotOpened
+	}
+
+	conn.Log.Infof("reconnecting on request")
+	conn.Close(false)
+	return conn.Open(engineCtx)
+}
+
+// ForceRelay keeps the connection on the relay while enabled, ICE keeps connecting but the traffic isn't moved over.
+// Disabling it restarts ICE to upgrade the connection again. The setting lasts until the connection is closed.
+func (conn *Conn) ForceRelay(enabled bool) error {
+	conn.mu.Lock()
+	if !conn.opened {
+		conn.mu.Unlock()
+		return ErrConnNotOpened
+	}
+	if enabled && conn.wgProxyRelay == nil {
+		conn.mu.Unlock()
+		return ErrNoRelayedConn
+	}
+	if conn.relayForced == enabled {
+		conn.mu.Unlock()
+		return nil
+	}
+	conn.relayForced = enabled
+	iceActive := conn.isICEActive()
+	conn.mu.Unlock()
+
+	if !enabled {
+		conn.Log.Infof("relay is no longer forced, restarting ICE")
+		conn.RestartICE()
+		return nil
+	}
+
+	conn.Log.Infof("relay is forced")
+	if iceActive {
+		// closing the agent reports the ICE connection as disconnected, which switches to the relay
+		conn.workerICE.Close()
+	}
+	return nil
+}
+
+// RelayForced reports whether the connection is kept on the relay
+func (conn *Conn) RelayForced() bool {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	return conn.relayForced
+}
+
+// CandidatePairs returns the candidate pair the connection uses, nil if ICE isn't active, and the previously
+// selected ones, the most recent last
+func (conn *Conn) CandidatePairs() (*CandidatePair, []CandidatePair) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	pairs := slices.Clone(conn.candidatePairs)
+	if len(pairs) == 0 || !pairs[len(pairs)-1].ClosedAt.IsZero() || !conn.isICEActive() {
+		return nil, pairs
+	}
+
+	current := pairs[len(pairs)-1]
+	return &current, pairs[:len(pairs)-1]
+}
+
+// recordCandidatePair records the pair of the ICE connection that became the active one, the caller must hold mu
+func (conn *Conn) recordCandidatePair(info ICEConnInfo, now time.Time) {
+	conn.closeCandidatePair(now)
+
+	conn.candidatePairs = append(conn.candidatePairs, CandidatePair{
+		LocalType:      info.LocalIceCandidateType,
+		LocalEndpoint:  info.LocalIceCandidateEndpoint,
+		RemoteType:     info.RemoteIceCandidateType,
+		RemoteEndpoint: info.RemoteIceCandidateEndpoint,
+		Relayed:        info.Relayed,
+		SelectedAt:     now,
+	})
+	if len(conn.candidatePairs) > candidatePairHistorySize {
+		conn.candidatePairs = slices.Delete(conn.candidatePairs, 0, len(conn.candidatePairs)-candidatePairHistorySize)
+	}
+}
+
+// closeCandidatePair marks the last pair as no longer used, the caller must hold mu
+func (conn *Conn) closeCandidatePair(now time.Time) {
+	if n := len(conn.candidatePairs); n > 0 && conn.candidatePairs[n-1].ClosedAt.IsZero() {
+		conn.candidatePairs[n-1].ClosedAt = now
+	}
+}
@@ -0,0 +1,60 @@
+package peer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/internal/peer/conntype"
+	"github.com/netbirdio/netbird/client/internal/peer/worker"
+)
+
+func TestConn_CandidatePairs(t *testing.T) {
+	conn := &Conn{statusICE: worker.NewAtomicStatus()}
+
+	current, history := conn.CandidatePairs()
+	assert.Nil(t, current)
+	assert.Empty(t, history)
+
+	start := time.Now()
+	for i := 0; i < candidatePairHistorySize+2; i++ {
+		conn.recordCandidatePair(ICEConnInfo{
+			LocalIceCandidateType:      "host",
+			LocalIceCandidateEndpoint:  fmt.Sprintf("10.0.0.1:%d", 51820+i),
+			RemoteIceCandidateType:     "srflx",
+			RemoteIceCandidateEndpoint: "198.51.100.1:51820",
+		}, start.Add(time.Duration(i)*time.Second))
+	}
+	conn.currentConnPriority = conntype.ICEP2P
+	conn.statusICE.SetConnected()
+
+	current, history = conn.CandidatePairs()
+	require.NotNil(t, current)
+	assert.Equal(t, fmt.Sprintf("10.0.0.1:%d", 51820+candidatePairHistorySize+1), current.LocalEndpoint)
+	assert.True(t, current.ClosedAt.IsZero())
+	require.Len(t, history, candidatePairHistorySize-1)
+	assert.Equal(t, "10.0.0.1:51822", history[0].LocalEndpoint)
+	for _, pair := range history {
+		assert.Equal(t, pair.SelectedAt.Add(time.Second), pair.ClosedAt, "a pair is closed when the next one is selected")
+	}
+
+	closedAt := start.Add(time.Minute)
+	conn.closeCandidatePair(closedAt)
+	conn.statusICE.SetDisconnected()
+
+	current, history = conn.CandidatePairs()
+	assert.Nil(t, current)
+	require.Len(t, history, candidatePairHistorySize)
+	assert.Equal(t, closedAt, history[len(history)-1].ClosedAt)
+}
+
+func TestConn_ControlsNotOpened(t *testing.T) {
+	conn := &Conn{}
+
+	assert.ErrorIs(t, conn.ForceRelay(true), ErrConnNotOpened)
+	assert.ErrorIs(t, conn.Reconnect(t.Context()), ErrConnNotOpened)
+	assert.False(t, conn.RelayForced())
+}
@@ -23,7 +23,7 @@ var testDispatcher = dispatcher.NewConnectionDispatcher()
 var connConf = ConnConfig{
 	Key:         "LLHf3Ma6z6mdLbriAJbqhX7+nM/B71lgw2+91q3LfhU=",
 	LocalKey:    "RRHf3Ma6z6mdLbriAJbqhX7+nM/B71lgw2+91q3LfhU=",
-	Timeout:     time.Second,
+	Backoff:     BackoffPolicy{InitialTimeout: time.Second, MaxTimeout: time.Second},
 	LocalWgPort: 51820,
 	ICEConfig: ice.Config{
 		InterfaceBlackList: nil,
@@ -284,3 +284,19 @@ func TestConn_presharedKey(t *testing.T) {
 		})
 	}
 }
+
+func TestWgConfig_keepAlive(t *testing.T) {
+	cfg := WgConfig{}
+	assert.Equal(t, defaultWgKeepAlive, cfg.keepAlive(), "unset keepalive should fall back to the default")
+
+	cfg.PersistentKeepalive = 10 * time.Second
+	assert.Equal(t, 10*time.Second, cfg.keepAlive(), "configured keepalive should be used")
+}
+
+func TestWgConfig_responderDelay(t *testing.T) {
+	cfg := WgConfig{}
+	assert.Equal(t, fallbackDelay, cfg.responderDelay(), "unset responder delay should fall back to the default")
+
+	cfg.ResponderDelay = 15 * time.Second
+	assert.Equal(t, 15*time.Second, cfg.responderDelay(), "configured responder delay should be used")
+}
@@ -79,7 +79,7 @@ func (e *EndpointUpdater) waitForCloseTheDelayedUpdate() {
 // scheduleDelayedUpdate waits for the fallback period before updating the endpoint
 func (e *EndpointUpdater) scheduleDelayedUpdate(ctx context.Context, addr *net.UDPAddr, presharedKey *wgtypes.Key) {
 	defer e.updateWg.Done()
-	t := time.NewTimer(fallbackDelay)
+	t := time.NewTimer(e.wgConfig.responderDelay())
 	defer t.Stop()
 
 	select {
@@ -96,7 +96,7 @@ func (e *EndpointUpdater) updateWireGuardPeer(endpoint *net.UDPAddr, presharedKe
 	return e.wgConfig.WgInterface.UpdatePeer(
 		e.wgConfig.RemoteKey,
 		e.wgConfig.AllowedIps,
-		defaultWgKeepAlive,
+		e.wgConfig.keepAlive(),
 		endpoint,
 		presharedKey,
 	)
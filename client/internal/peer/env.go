@@ -3,11 +3,18 @@ package peer
 import (
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
+
+	log "github.com/sirupsen/logrus"
 )
 
 const (
 	EnvKeyNBForceRelay = "NB_FORCE_RELAY"
+
+	EnvKeyNBPeerMaxGoroutines  = "NB_PEER_MAX_GOROUTINES"
+	EnvKeyNBPeerMaxICEAgents   = "NB_PEER_MAX_ICE_AGENTS"
+	EnvKeyNBPeerMaxBufferBytes = "NB_PEER_MAX_BUFFER_BYTES"
 )
 
 func isForceRelayed() bool {
@@ -16,3 +23,28 @@ func isForceRelayed() bool {
 	}
 	return strings.EqualFold(os.Getenv(EnvKeyNBForceRelay), "true")
 }
+
+// ResourceLimitsFromEnv reads the resource limits of the peer connections, unset or invalid values are unlimited
+func ResourceLimitsFromEnv() ResourceLimits {
+	return ResourceLimits{
+		MaxGoroutines:  int(resourceLimitFromEnv(EnvKeyNBPeerMaxGoroutines)),
+		MaxICEAgents:   int(resourceLimitFromEnv(EnvKeyNBPeerMaxICEAgents)),
+		MaxBufferBytes: resourceLimitFromEnv(EnvKeyNBPeerMaxBufferBytes),
+	}
+}
+
+func resourceLimitFromEnv(key string) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0
+	}
+
+	limit, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || limit < 0 {
+		log.Warnf("invalid value %s set for %s, the resource is unlimited", value, key)
+		return 0
+	}
+
+	log.Infof("limit peer connections to %d by %s", limit, key)
+	return limit
+}
@@ -70,3 +70,25 @@ func NewConnectionAlreadyClosed(peer string) error {
 		peer: peer,
 	}
 }
+
+// ConnectionHalfOpenError is an error indicating that a part of a peer Conn has been stuck in an intermediate state
+// for longer than its lifetime
+type ConnectionHalfOpenError struct {
+	peer  string
+	State HalfOpenState
+	// Age is how long the part has been in the state
+	Age time.Duration
+}
+
+func (e *ConnectionHalfOpenError) Error() string {
+	return fmt.Sprintf("connection to peer %s has been half-open (%s) for %s", e.peer, e.State, e.Age.Round(time.Second))
+}
+
+// NewConnectionHalfOpenError creates a new ConnectionHalfOpenError error
+func NewConnectionHalfOpenError(peer string, state HalfOpenState, age time.Duration) error {
+	return &ConnectionHalfOpenError{
+		peer:  peer,
+		State: state,
+		Age:   age,
+	}
+}
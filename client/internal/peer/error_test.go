@@ -25,3 +25,9 @@ func TestNewConnectionAlreadyClosed(t *testing.T) {
 	err := NewConnectionAlreadyClosed("X")
 	assert.Equal(t, &ConnectionAlreadyClosedError{peer: "X"}, err)
 }
+
+func TestNewConnectionHalfOpenError(t *testing.T) {
+	err := NewConnectionHalfOpenError("X", HalfOpenRelayIdle, time.Minute)
+	assert.Equal(t, &ConnectionHalfOpenError{peer: "X", State: HalfOpenRelayIdle, Age: time.Minute}, err)
+	assert.Equal(t, "connection to peer X has been half-open (relay opened but idle) for 1m0s", err.Error())
+}
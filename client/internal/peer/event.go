@@ -0,0 +1,149 @@
+package peer
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/time/rate"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+const (
+	// eventDedupWindow is the time during which repeated events with the same dedup key are suppressed
+	eventDedupWindow = 30 * time.Second
+	// eventDedupRetention is how long the suppressed count of an event is kept after its last occurrence
+	eventDedupRetention = 10 * eventDedupWindow
+)
+
+// EventTypeKey is the metadata key holding the type of lifecycle events
+const EventTypeKey = "event"
+
+// Lifecycle event types, published with PublishLifecycleEvent
+const (
+	EventEngineUp         = "engine_up"
+	EventEngineDown       = "engine_down"
+	EventPeerAdded        = "peer_added"
+	EventPeerRemoved      = "peer_removed"
+	EventPeerConnected    = "peer_connected"
+	EventPeerDisconnected = "peer_disconnected"
+	EventRouteAdded       = "route_added"
+	EventRouteRemoved     = "route_removed"
+	EventExitNodeChanged  = "exit_node_changed"
+	EventInboundPrompt    = "inbound_prompt"
+)
+
+type eventRateLimit struct {
+	limit rate.Limit
+	burst int
+}
+
+// eventRateLimits caps the events per category. Categories without an entry are not limited.
+// Errors and critical events are never rate limited.
+var eventRateLimits = map[proto.SystemEvent_Category]eventRateLimit{
+	proto.SystemEvent_NETWORK:      {limit: rate.Every(time.Second), burst: 10},
+	proto.SystemEvent_DNS:          {limit: rate.Every(time.Second), burst: 10},
+	proto.SystemEvent_CONNECTIVITY: {limit: rate.Every(time.Second), burst: 10},
+	proto.SystemEvent_SYSTEM:       {limit: rate.Every(10 * time.Second), burst: 3},
+}
+
+// EventFilter selects the events a subscriber receives. The zero value matches all events.
+type EventFilter struct {
+	MinSeverity proto.SystemEvent_Severity
+	// Categories limits the events to the given categories, all categories match if empty
+	Categories []proto.SystemEvent_Category
+}
+
+// Match reports whether the event passes the filter
+func (f EventFilter) Match(event *proto.SystemEvent) bool {
+	if event.GetSeverity() < f.MinSeverity {
+		return false
+	}
+	return len(f.Categories) == 0 || slices.Contains(f.Categories, event.GetCategory())
+}
+
+type dedupEntry struct {
+	lastSent   time.Time
+	lastSeen   time.Time
+	suppressed uint32
+}
+
+// eventLimiter drops repeated events and enforces the per category rate limits
+type eventLimiter struct {
+	limiters map[proto.SystemEvent_Category]*rate.Limiter
+	seen     map[string]*dedupEntry
+	now      func() time.Time
+}
+
+func newEventLimiter() *eventLimiter {
+	limiters := make(map[proto.SystemEvent_Category]*rate.Limiter, len(eventRateLimits))
+	for category, l := range eventRateLimits {
+		limiters[category] = rate.NewLimiter(l.limit, l.burst)
+	}
+
+	return &eventLimiter{
+		limiters: limiters,
+		seen:     make(map[string]*dedupEntry),
+		now:      time.Now,
+	}
+}
+
+// allow reports whether the event should be published. If so, it returns the number of events with the same
+// key that were suppressed since the last published one.
+func (l *eventLimiter) allow(key string, severity proto.SystemEvent_Severity, category proto.SystemEvent_Category) (bool, uint32) {
+	now := l.now()
+	l.prune(now)
+
+	entry, ok := l.seen[key]
+	if !ok {
+		entry = &dedupEntry{}
+		l.seen[key] = entry
+	}
+	entry.lastSeen = now
+
+	if !entry.lastSent.IsZero() && now.Sub(entry.lastSent) < eventDedupWindow {
+		entry.suppressed++
+		return false, 0
+	}
+
+	if limiter, ok := l.limiters[category]; ok && severity < proto.SystemEvent_ERROR && !limiter.AllowN(now, 1) {
+		entry.suppressed++
+		return false, 0
+	}
+
+	suppressed := entry.suppressed
+	entry.lastSent = now
+	entry.suppressed = 0
+	return true, suppressed
+}
+
+// prune drops the entries of events that haven't occurred within the retention period
+func (l *eventLimiter) prune(now time.Time) {
+	for key, entry := range l.seen {
+		if now.Sub(entry.lastSeen) >= eventDedupRetention {
+			delete(l.seen, key)
+		}
+	}
+}
+
+// eventDedupKey derives the dedup key of an event that was published without an explicit one
+func eventDedupKey(severity proto.SystemEvent_Severity, category proto.SystemEvent_Category, msg string, metadata map[string]string) string {
+	var sb strings.Builder
+	sb.WriteString(severity.String())
+	sb.WriteString("/")
+	sb.WriteString(category.String())
+	sb.WriteString("/")
+	sb.WriteString(msg)
+
+	keys := maps.Keys(metadata)
+	slices.Sort(keys)
+	for _, k := range keys {
+		sb.WriteString("/")
+		sb.WriteString(k)
+		sb.WriteString("=")
+		sb.WriteString(metadata[k])
+	}
+	return sb.String()
+}
@@ -0,0 +1,86 @@
+package peer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+func TestEventLimiter_Dedup(t *testing.T) {
+	now := time.Now()
+	l := newEventLimiter()
+	l.now = func() time.Time { return now }
+
+	allowed, suppressed := l.allow("key", proto.SystemEvent_INFO, proto.SystemEvent_AUTHENTICATION)
+	assert.True(t, allowed)
+	assert.Zero(t, suppressed)
+
+	for i := 0; i < 3; i++ {
+		now = now.Add(time.Second)
+		allowed, _ = l.allow("key", proto.SystemEvent_INFO, proto.SystemEvent_AUTHENTICATION)
+		assert.False(t, allowed, "duplicate within the dedup window should be suppressed")
+	}
+
+	allowed, _ = l.allow("other", proto.SystemEvent_INFO, proto.SystemEvent_AUTHENTICATION)
+	assert.True(t, allowed, "events with a different key should not be suppressed")
+
+	now = now.Add(eventDedupWindow)
+	allowed, suppressed = l.allow("key", proto.SystemEvent_INFO, proto.SystemEvent_AUTHENTICATION)
+	assert.True(t, allowed)
+	assert.Equal(t, uint32(3), suppressed)
+}
+
+func TestEventLimiter_RateLimit(t *testing.T) {
+	now := time.Now()
+	l := newEventLimiter()
+	l.now = func() time.Time { return now }
+
+	burst := eventRateLimits[proto.SystemEvent_SYSTEM].burst
+	for i := 0; i < burst; i++ {
+		allowed, _ := l.allow(string(rune('a'+i)), proto.SystemEvent_INFO, proto.SystemEvent_SYSTEM)
+		assert.True(t, allowed, "events within the burst should pass")
+	}
+
+	allowed, _ := l.allow("limited", proto.SystemEvent_INFO, proto.SystemEvent_SYSTEM)
+	assert.False(t, allowed, "events over the burst should be rate limited")
+
+	allowed, _ = l.allow("error", proto.SystemEvent_ERROR, proto.SystemEvent_SYSTEM)
+	assert.True(t, allowed, "errors should bypass the rate limit")
+
+	allowed, _ = l.allow("network", proto.SystemEvent_INFO, proto.SystemEvent_NETWORK)
+	assert.True(t, allowed, "categories are limited independently")
+
+	now = now.Add(10 * time.Second)
+	allowed, suppressed := l.allow("limited", proto.SystemEvent_INFO, proto.SystemEvent_SYSTEM)
+	assert.True(t, allowed)
+	assert.Equal(t, uint32(1), suppressed)
+}
+
+func TestStatus_PublishEventFilter(t *testing.T) {
+	recorder := NewRecorder("https://mgm")
+
+	all := recorder.SubscribeToEvents(EventFilter{})
+	defer recorder.UnsubscribeFromEvents(all)
+	warnings := recorder.SubscribeToEvents(EventFilter{
+		MinSeverity: proto.SystemEvent_WARNING,
+		Categories:  []proto.SystemEvent_Category{proto.SystemEvent_DNS},
+	})
+	defer recorder.UnsubscribeFromEvents(warnings)
+
+	recorder.PublishEvent(proto.SystemEvent_INFO, proto.SystemEvent_DNS, "dns info", "", nil)
+	recorder.PublishEvent(proto.SystemEvent_WARNING, proto.SystemEvent_NETWORK, "network warning", "", nil)
+	recorder.PublishEvent(proto.SystemEvent_WARNING, proto.SystemEvent_DNS, "dns warning", "", nil)
+	recorder.PublishEvent(proto.SystemEvent_WARNING, proto.SystemEvent_DNS, "dns warning", "", nil)
+
+	require.Len(t, all.Events(), 3, "the duplicate should be dropped")
+	require.Len(t, warnings.Events(), 1)
+
+	event := <-warnings.Events()
+	assert.Equal(t, "dns warning", event.GetMessage())
+	assert.NotEmpty(t, event.GetDedupKey())
+	assert.Len(t, recorder.GetEventHistory(), 3)
+}
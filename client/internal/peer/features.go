@@ -0,0 +1,73 @@
+package peer
+
+import (
+	"math/bits"
+	"strconv"
+	"strings"
+
+	signal "github.com/netbirdio/netbird/shared/signal/client"
+)
+
+var featureNames = map[uint32]string{
+	signal.DirectCheck:           "direct-check",
+	signal.FeatureRosenpass:      "rosenpass",
+	signal.FeatureLazyConnection: "lazy",
+	signal.FeatureFlowSampling:   "flow-sampling",
+	signal.FeatureMultiPath:      "multi-path",
+	signal.FeatureTCPFallback:    "tcp-fallback",
+	signal.FeatureWakeOnLAN:      "wake-on-lan",
+	signal.FeatureRouteFailover:  "route-failover",
+}
+
+// Features is the set of capabilities a peer announces in its offers and answers, one bit per signal feature ID
+type Features uint64
+
+// NewFeatures returns the set of the given feature IDs. Unknown IDs above the bit range are ignored.
+func NewFeatures(ids ...uint32) Features {
+	var f Features
+	for _, id := range ids {
+		if id < 64 {
+			f |= 1 << id
+		}
+	}
+	return f
+}
+
+// Has reports whether the feature is in the set
+func (f Features) Has(id uint32) bool {
+	return id < 64 && f&(1<<id) != 0
+}
+
+// IDs returns the feature IDs of the set in ascending order
+func (f Features) IDs() []uint32 {
+	ids := make([]uint32, 0, bits.OnesCount64(uint64(f)))
+	for rest := uint64(f); rest != 0; rest &= rest - 1 {
+		ids = append(ids, uint32(bits.TrailingZeros64(rest)))
+	}
+	return ids
+}
+
+func (f Features) String() string {
+	if f == 0 {
+		return "none"
+	}
+
+	names := make([]string, 0, bits.OnesCount64(uint64(f)))
+	for _, id := range f.IDs() {
+		name, ok := featureNames[id]
+		if !ok {
+			name = strconv.FormatUint(uint64(id), 10)
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ",")
+}
+
+// localFeatures returns the features this client announces for the connection
+func localFeatures(config ConnConfig) Features {
+	f := NewFeatures(signal.FeatureLazyConnection, signal.FeatureWakeOnLAN, signal.FeatureRouteFailover, signal.FeatureMultiPath)
+	if config.RosenpassConfig.PubKey != nil {
+		f |= NewFeatures(signal.FeatureRosenpass)
+	}
+	return f
+}
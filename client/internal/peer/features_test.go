@@ -0,0 +1,50 @@
+package peer
+
+import (
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	signal "github.com/netbirdio/netbird/shared/signal/client"
+)
+
+func TestFeatures(t *testing.T) {
+	f := NewFeatures(signal.FeatureLazyConnection, signal.FeatureRosenpass, 100)
+
+	assert.True(t, f.Has(signal.FeatureLazyConnection))
+	assert.True(t, f.Has(signal.FeatureRosenpass))
+	assert.False(t, f.Has(signal.FeatureTCPFallback))
+	assert.False(t, f.Has(100), "IDs out of range are ignored")
+	assert.Equal(t, []uint32{signal.FeatureRosenpass, signal.FeatureLazyConnection}, f.IDs())
+	assert.Equal(t, "rosenpass,lazy", f.String())
+	assert.Equal(t, "none", Features(0).String())
+}
+
+func TestLocalFeatures(t *testing.T) {
+	f := localFeatures(ConnConfig{})
+	assert.True(t, f.Has(signal.FeatureLazyConnection))
+	assert.True(t, f.Has(signal.FeatureWakeOnLAN))
+	assert.True(t, f.Has(signal.FeatureRouteFailover))
+	assert.True(t, f.Has(signal.FeatureMultiPath))
+	assert.False(t, f.Has(signal.FeatureRosenpass))
+
+	f = localFeatures(ConnConfig{RosenpassConfig: RosenpassConfig{PubKey: []byte("key")}})
+	assert.True(t, f.Has(signal.FeatureRosenpass))
+}
+
+func TestConn_SupportsLazyConnection(t *testing.T) {
+	conn := &Conn{Log: log.WithField("peer", "test"), config: ConnConfig{AgentVersion: "0.40.0"}}
+	assert.False(t, conn.SupportsLazyConnection(), "falls back to the agent version")
+
+	conn.setRemoteFeatures(0)
+	_, known := conn.RemoteFeatures()
+	assert.False(t, known, "empty announcements are ignored")
+
+	conn.setRemoteFeatures(NewFeatures(signal.FeatureLazyConnection))
+	assert.True(t, conn.SupportsLazyConnection(), "announced features take precedence")
+
+	conn.config.AgentVersion = "0.50.0"
+	conn.setRemoteFeatures(NewFeatures(signal.FeatureRosenpass))
+	assert.False(t, conn.SupportsLazyConnection())
+}
@@ -8,6 +8,9 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// defaultJitter is the randomization factor of the retry intervals
+const defaultJitter = 0.1
+
 type isConnectedFunc func() bool
 
 // Guard is responsible for the reconnection logic.
@@ -25,6 +28,13 @@ type Guard struct {
 	srWatcher               *SRWatcher
 	relayedConnDisconnected chan struct{}
 	iCEConnDisconnected     chan struct{}
+
+	// retryInterval overrides the initial interval of the offer retries if set
+	retryInterval time.Duration
+	// jitter is the randomization factor of the retry intervals
+	jitter float64
+	// maxRetries limits the offers sent until the next connection change, zero is unlimited
+	maxRetries int
 }
 
 func NewGuard(log *log.Entry, isConnectedFn isConnectedFunc, timeout time.Duration, srWatcher *SRWatcher) *Guard {
@@ -35,9 +45,23 @@ func NewGuard(log *log.Entry, isConnectedFn isConnectedFunc, timeout time.Durati
 		srWatcher:               srWatcher,
 		relayedConnDisconnected: make(chan struct{}, 1),
 		iCEConnDisconnected:     make(chan struct{}, 1),
+		jitter:                  defaultJitter,
 	}
 }
 
+// SetRetryInterval sets the initial interval between the offers sent while the connection is not established.
+// The interval doubles with every retry up to the guard timeout. Zero restores the default.
+func (g *Guard) SetRetryInterval(interval time.Duration) {
+	g.retryInterval = interval
+}
+
+// SetRetryLimits sets the randomization factor of the retry intervals and the number of offers sent before the
+// guard idles until the next relay, ICE or network change. Zero maxRetries retries without limit.
+func (g *Guard) SetRetryLimits(jitter float64, maxRetries int) {
+	g.jitter = jitter
+	g.maxRetries = maxRetries
+}
+
 func (g *Guard) Start(ctx context.Context, eventCallback func()) {
 	g.log.Infof("starting guard for reconnection with MaxInterval: %s", g.timeout)
 	g.reconnectLoopWithRetry(ctx, eventCallback)
@@ -108,30 +132,35 @@ func (g *Guard) reconnectLoopWithRetry(ctx context.Context, callback func()) {
 
 // initialTicker give chance to the peer to establish the initial connection.
 func (g *Guard) initialTicker(ctx context.Context) *backoff.Ticker {
-	bo := backoff.WithContext(&backoff.ExponentialBackOff{
-		InitialInterval:     3 * time.Second,
-		RandomizationFactor: 0.1,
-		Multiplier:          2,
-		MaxInterval:         g.timeout,
-		Stop:                backoff.Stop,
-		Clock:               backoff.SystemClock,
-	}, ctx)
-
-	return backoff.NewTicker(bo)
+	return backoff.NewTicker(g.newBackOff(ctx, g.initialInterval(3*time.Second)))
 }
 
 func (g *Guard) prepareExponentTicker(ctx context.Context) *backoff.Ticker {
-	bo := backoff.WithContext(&backoff.ExponentialBackOff{
-		InitialInterval:     800 * time.Millisecond,
-		RandomizationFactor: 0.1,
+	ticker := backoff.NewTicker(g.newBackOff(ctx, g.initialInterval(800*time.Millisecond)))
+	<-ticker.C // consume the initial tick what is happening right after the ticker has been created
+
+	return ticker
+}
+
+// newBackOff returns the backoff of the offer retries, the ticker is closed once maxRetries is reached
+func (g *Guard) newBackOff(ctx context.Context, initialInterval time.Duration) backoff.BackOff {
+	var bo backoff.BackOff = &backoff.ExponentialBackOff{
+		InitialInterval:     initialInterval,
+		RandomizationFactor: g.jitter,
 		Multiplier:          2,
 		MaxInterval:         g.timeout,
 		Stop:                backoff.Stop,
 		Clock:               backoff.SystemClock,
-	}, ctx)
-
-	ticker := backoff.NewTicker(bo)
-	<-ticker.C // consume the initial tick what is happening right after the ticker has been created
+	}
+	if g.maxRetries > 0 {
+		bo = backoff.WithMaxRetries(bo, uint64(g.maxRetries))
+	}
+	return backoff.WithContext(bo, ctx)
+}
 
-	return ticker
+func (g *Guard) initialInterval(defaultInterval time.Duration) time.Duration {
+	if g.retryInterval > 0 {
+		return g.retryInterval
+	}
+	return defaultInterval
 }
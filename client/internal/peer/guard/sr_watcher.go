@@ -69,6 +69,18 @@ func (w *SRWatcher) Close() {
 	w.relayManager.SetOnReconnectedListener(nil)
 }
 
+// SetSignalClient replaces the watched Signal client
+func (w *SRWatcher) SetSignalClient(signalClient chNotifier) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.cancelIceMonitor != nil {
+		w.signalClient.SetOnReconnectedListener(nil)
+		signalClient.SetOnReconnectedListener(w.onReconnected)
+	}
+	w.signalClient = signalClient
+}
+
 func (w *SRWatcher) NewListener() chan struct{} {
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -86,7 +98,7 @@ func (w *SRWatcher) RemoveListener(listenerChan chan struct{}) {
 }
 
 func (w *SRWatcher) onICEChanged() {
-	if !w.signalClient.Ready() {
+	if !w.signalReady() {
 		return
 	}
 
@@ -95,7 +107,7 @@ func (w *SRWatcher) onICEChanged() {
 }
 
 func (w *SRWatcher) onReconnected() {
-	if !w.signalClient.Ready() {
+	if !w.signalReady() {
 		return
 	}
 	if !w.relayManager.Ready() {
@@ -106,6 +118,13 @@ func (w *SRWatcher) onReconnected() {
 	w.notify()
 }
 
+func (w *SRWatcher) signalReady() bool {
+	w.mu.Lock()
+	signalClient := w.signalClient
+	w.mu.Unlock()
+	return signalClient.Ready()
+}
+
 func (w *SRWatcher) notify() {
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -0,0 +1,161 @@
+package peer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/peer/worker"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// HalfOpenState is an intermediate state a part of a peer connection may get stuck in. Such connections hold
+// sockets and show up in the status without passing any traffic.
+type HalfOpenState string
+
+const (
+	// HalfOpenICENoHandshake is an active ICE connection without a WireGuard handshake since it was set up
+	HalfOpenICENoHandshake HalfOpenState = "ICE connected without WireGuard handshake"
+	// HalfOpenRelayIdle is a relayed connection that was opened but never set up for WireGuard
+	HalfOpenRelayIdle HalfOpenState = "relay opened but idle"
+)
+
+var (
+	// halfOpenLifetime bounds how long a part of the connection may stay half-open. It matches the period the
+	// WireGuard watcher of the relayed connection allows for a handshake.
+	halfOpenLifetime      = wgHandshakePeriod + wgHandshakeOvertime
+	halfOpenCheckInterval = 30 * time.Second
+)
+
+// halfOpenSnapshot holds the state of the connection parts that may get stuck half-open
+type halfOpenSnapshot struct {
+	// iceActiveSince is when the ICE connection became the active one, zero if it isn't active
+	iceActiveSince time.Time
+	lastHandshake  time.Time
+	// relayOpenedAt is when the relayed connection was opened, zero if none is open
+	relayOpenedAt time.Time
+	relayInUse    bool
+}
+
+// check returns the parts of the connection that have been half-open for longer than the lifetime
+func (s halfOpenSnapshot) check(peerKey string, now time.Time, lifetime time.Duration) []*ConnectionHalfOpenError {
+	var stuck []*ConnectionHalfOpenError
+
+	if !s.iceActiveSince.IsZero() && s.lastHandshake.Before(s.iceActiveSince) {
+		if age := now.Sub(s.iceActiveSince); age > lifetime {
+			stuck = append(stuck, &ConnectionHalfOpenError{peer: peerKey, State: HalfOpenICENoHandshake, Age: age})
+		}
+	}
+
+	if !s.relayOpenedAt.IsZero() && !s.relayInUse {
+		if age := now.Sub(s.relayOpenedAt); age > lifetime {
+			stuck = append(stuck, &ConnectionHalfOpenError{peer: peerKey, State: HalfOpenRelayIdle, Age: age})
+		}
+	}
+
+	return stuck
+}
+
+// watchHalfOpen periodically tears down the parts of the connection that are stuck half-open, the guard
+// reconnects them afterward
+func (conn *Conn) watchHalfOpen(ctx context.Context) {
+	ticker := time.NewTicker(halfOpenCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			conn.reapHalfOpen(time.Now())
+		}
+	}
+}
+
+func (conn *Conn) reapHalfOpen(now time.Time) {
+	conn.mu.Lock()
+	if conn.ctx.Err() != nil {
+		conn.mu.Unlock()
+		return
+	}
+	snapshot := halfOpenSnapshot{
+		iceActiveSince: conn.iceActiveSince,
+		relayOpenedAt:  conn.workerRelay.OpenedAt(),
+		relayInUse:     conn.statusRelay.Get() == worker.StatusConnected,
+	}
+	conn.mu.Unlock()
+
+	if !snapshot.iceActiveSince.IsZero() {
+		handshake, err := conn.lastWgHandshake()
+		if err != nil {
+			conn.Log.Debugf("failed to read WireGuard handshake: %v", err)
+			snapshot.iceActiveSince = time.Time{}
+		}
+		snapshot.lastHandshake = handshake
+	}
+
+	for _, stuck := range snapshot.check(conn.config.Key, now, conn.halfOpenLifetime()) {
+		switch stuck.State {
+		case HalfOpenICENoHandshake:
+			conn.mu.Lock()
+			renewed := !conn.iceActiveSince.Equal(snapshot.iceActiveSince)
+			conn.mu.Unlock()
+			if renewed {
+				continue
+			}
+			conn.reportHalfOpen(stuck)
+			// closing the agent reports the ICE connection as disconnected, which switches back to the relay
+			conn.workerICE.Close()
+		case HalfOpenRelayIdle:
+			if !conn.workerRelay.OpenedAt().Equal(snapshot.relayOpenedAt) {
+				continue
+			}
+			conn.reportHalfOpen(stuck)
+			conn.workerRelay.CloseConn()
+		}
+	}
+}
+
+func (conn *Conn) reportHalfOpen(stuck *ConnectionHalfOpenError) {
+	conn.Log.WithFields(log.Fields{
+		"state": stuck.State,
+		"age":   stuck.Age.Round(time.Second),
+	}).Warnf("closing half-open connection part: %v", stuck)
+
+	if conn.statusRecorder == nil {
+		return
+	}
+	conn.statusRecorder.PublishEvent(
+		proto.SystemEvent_WARNING,
+		proto.SystemEvent_CONNECTIVITY,
+		stuck.Error(),
+		"",
+		map[string]string{
+			"peer":  conn.config.Key,
+			"state": string(stuck.State),
+			"age":   stuck.Age.Round(time.Second).String(),
+		},
+	)
+}
+
+func (conn *Conn) lastWgHandshake() (time.Time, error) {
+	stats, err := conn.config.WgConfig.WgInterface.GetStats()
+	if err != nil {
+		return time.Time{}, err
+	}
+	stat, ok := stats[conn.config.Key]
+	if !ok {
+		return time.Time{}, errors.New("peer not found in WireGuard endpoints")
+	}
+	return stat.LastHandshake, nil
+}
+
+// halfOpenLifetime follows the handshake timeout override of the peer
+func (conn *Conn) halfOpenLifetime() time.Duration {
+	if conn.config.WgConfig.HandshakeTimeout > 0 {
+		return wgHandshakePeriod + conn.config.WgConfig.HandshakeTimeout
+	}
+	return halfOpenLifetime
+}
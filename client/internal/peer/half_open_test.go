@@ -0,0 +1,73 @@
+package peer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHalfOpenSnapshot_Check(t *testing.T) {
+	now := time.Now()
+	lifetime := time.Minute
+	expired := now.Add(-2 * time.Minute)
+	fresh := now.Add(-30 * time.Second)
+
+	tests := []struct {
+		name     string
+		snapshot halfOpenSnapshot
+		want     []HalfOpenState
+	}{
+		{
+			name:     "idle connection",
+			snapshot: halfOpenSnapshot{},
+		},
+		{
+			name:     "ICE without handshake within the lifetime",
+			snapshot: halfOpenSnapshot{iceActiveSince: fresh},
+		},
+		{
+			name:     "ICE without handshake",
+			snapshot: halfOpenSnapshot{iceActiveSince: expired},
+			want:     []HalfOpenState{HalfOpenICENoHandshake},
+		},
+		{
+			name:     "ICE with a handshake before it became active",
+			snapshot: halfOpenSnapshot{iceActiveSince: expired, lastHandshake: expired.Add(-time.Second)},
+			want:     []HalfOpenState{HalfOpenICENoHandshake},
+		},
+		{
+			name:     "ICE with handshake",
+			snapshot: halfOpenSnapshot{iceActiveSince: expired, lastHandshake: fresh},
+		},
+		{
+			name:     "relay opened within the lifetime",
+			snapshot: halfOpenSnapshot{relayOpenedAt: fresh},
+		},
+		{
+			name:     "relay in use",
+			snapshot: halfOpenSnapshot{relayOpenedAt: expired, relayInUse: true},
+		},
+		{
+			name:     "relay idle",
+			snapshot: halfOpenSnapshot{relayOpenedAt: expired},
+			want:     []HalfOpenState{HalfOpenRelayIdle},
+		},
+		{
+			name:     "both stuck",
+			snapshot: halfOpenSnapshot{iceActiveSince: expired, relayOpenedAt: expired},
+			want:     []HalfOpenState{HalfOpenICENoHandshake, HalfOpenRelayIdle},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var states []HalfOpenState
+			for _, stuck := range tt.snapshot.check("peer", now, lifetime) {
+				states = append(states, stuck.State)
+				assert.Greater(t, stuck.Age, lifetime)
+			}
+			assert.Equal(t, tt.want, states)
+		})
+	}
+}
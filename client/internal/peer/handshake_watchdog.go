@@ -0,0 +1,155 @@
+package peer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// StaleRecovery is what the handshake watchdog did about an ICE connection whose WireGuard handshake went stale
+type StaleRecovery string
+
+const (
+	// StaleRecoveryRelay switches the traffic to the relayed connection, the guard reconnects ICE afterward
+	StaleRecoveryRelay StaleRecovery = "relay fallback"
+	// StaleRecoveryICERestart renews the ICE session, the WireGuard peer is kept until the new connection is ready
+	StaleRecoveryICERestart StaleRecovery = "ICE restart"
+)
+
+var handshakeWatchdogInterval = 30 * time.Second
+
+// handshakeHealth holds the state of an ICE connection the handshake watchdog decides on
+type handshakeHealth struct {
+	// iceActiveSince is when the ICE connection became the active one, zero if it isn't active
+	iceActiveSince time.Time
+	lastHandshake  time.Time
+	// lastRecovery is when the watchdog last acted on the connection
+	lastRecovery time.Time
+}
+
+// stale returns the age of the last handshake if the active ICE connection stopped handshaking for longer than the
+// threshold. Connections that never handshaked since they became active are left to the half-open reaper, and a
+// recovery gets the threshold to take effect before the next one.
+func (h handshakeHealth) stale(now time.Time, threshold time.Duration) (time.Duration, bool) {
+	if h.iceActiveSince.IsZero() || h.lastHandshake.Before(h.iceActiveSince) {
+		return 0, false
+	}
+	if now.Sub(h.lastRecovery) <= threshold {
+		return 0, false
+	}
+
+	age := now.Sub(h.lastHandshake)
+	return age, age > threshold
+}
+
+// watchHandshake periodically checks the handshake age of the active ICE connection and recovers the connection of
+// this peer when it went stale, instead of waiting for the ICE agent or a full engine restart to notice a dead path
+func (conn *Conn) watchHandshake(ctx context.Context) {
+	ticker := time.NewTicker(handshakeWatchdogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			conn.checkHandshake(time.Now())
+		}
+	}
+}
+
+func (conn *Conn) checkHandshake(now time.Time) {
+	conn.mu.Lock()
+	if conn.ctx.Err() != nil || !conn.isICEActive() {
+		conn.mu.Unlock()
+		return
+	}
+	health := handshakeHealth{
+		iceActiveSince: conn.iceActiveSince,
+		lastRecovery:   conn.lastStaleRecovery,
+	}
+	conn.mu.Unlock()
+
+	handshake, err := conn.lastWgHandshake()
+	if err != nil {
+		conn.Log.Debugf("failed to read WireGuard handshake: %v", err)
+		return
+	}
+	health.lastHandshake = handshake
+
+	age, stale := health.stale(now, conn.halfOpenLifetime())
+	if !stale {
+		return
+	}
+
+	conn.mu.Lock()
+	if !conn.iceActiveSince.Equal(health.iceActiveSince) {
+		conn.mu.Unlock()
+		return
+	}
+	conn.lastStaleRecovery = now
+	recovery := StaleRecoveryICERestart
+	if conn.isReadyToUpgrade() {
+		recovery = StaleRecoveryRelay
+	}
+	conn.mu.Unlock()
+
+	conn.reportStaleHandshake(recovery, age)
+
+	switch recovery {
+	case StaleRecoveryRelay:
+		// closing the agent reports the ICE connection as disconnected, which switches to the relay
+		conn.workerICE.Close()
+	case StaleRecoveryICERestart:
+		conn.RestartICE()
+	}
+}
+
+func (conn *Conn) reportStaleHandshake(recovery StaleRecovery, age time.Duration) {
+	conn.Log.Warnf("WireGuard handshake is stale for %s, recovering the connection with %s", age.Round(time.Second), recovery)
+
+	if conn.statusRecorder == nil {
+		return
+	}
+	conn.statusRecorder.PublishEvent(
+		proto.SystemEvent_WARNING,
+		proto.SystemEvent_CONNECTIVITY,
+		fmt.Sprintf("WireGuard handshake with peer %s is stale, recovering with %s", conn.config.Key, recovery),
+		"",
+		map[string]string{
+			"peer":     conn.config.Key,
+			"recovery": string(recovery),
+			"age":      age.Round(time.Second).String(),
+		},
+	)
+}
+
+// RecoverAfterResume treats the connection as stale after the host resumed from suspend, without waiting for the
+// handshake to age. The remote peer and the NAT bindings likely dropped the ICE connection while the host slept, so
+// an active ICE connection falls back to the relay if one is ready, and ICE is restarted otherwise.
+func (conn *Conn) RecoverAfterResume() {
+	conn.mu.Lock()
+	if !conn.opened || conn.ctx.Err() != nil {
+		conn.mu.Unlock()
+		return
+	}
+	recovery := StaleRecoveryICERestart
+	if conn.isICEActive() {
+		conn.lastStaleRecovery = time.Now()
+		if conn.isReadyToUpgrade() {
+			recovery = StaleRecoveryRelay
+		}
+	}
+	conn.mu.Unlock()
+
+	conn.Log.Infof("host resumed from suspend, recovering the connection with %s", recovery)
+
+	switch recovery {
+	case StaleRecoveryRelay:
+		conn.workerICE.Close()
+	case StaleRecoveryICERestart:
+		conn.RestartICE()
+	}
+}
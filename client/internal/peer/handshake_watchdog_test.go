@@ -0,0 +1,69 @@
+package peer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandshakeHealth_Stale(t *testing.T) {
+	now := time.Now()
+	threshold := time.Minute
+	expired := now.Add(-2 * time.Minute)
+	fresh := now.Add(-30 * time.Second)
+
+	tests := []struct {
+		name    string
+		health  handshakeHealth
+		wantAge time.Duration
+		want    bool
+	}{
+		{
+			name:   "ICE not active",
+			health: handshakeHealth{lastHandshake: expired},
+		},
+		{
+			name:   "no handshake since ICE became active",
+			health: handshakeHealth{iceActiveSince: expired, lastHandshake: expired.Add(-time.Second)},
+		},
+		{
+			name:   "fresh handshake",
+			health: handshakeHealth{iceActiveSince: expired.Add(-time.Minute), lastHandshake: fresh},
+		},
+		{
+			name:    "stale handshake",
+			health:  handshakeHealth{iceActiveSince: expired.Add(-time.Minute), lastHandshake: expired},
+			wantAge: 2 * time.Minute,
+			want:    true,
+		},
+		{
+			name: "recovered recently",
+			health: handshakeHealth{
+				iceActiveSince: expired.Add(-time.Minute),
+				lastHandshake:  expired,
+				lastRecovery:   fresh,
+			},
+		},
+		{
+			name: "recovered before the threshold",
+			health: handshakeHealth{
+				iceActiveSince: expired.Add(-time.Minute),
+				lastHandshake:  expired,
+				lastRecovery:   expired,
+			},
+			wantAge: 2 * time.Minute,
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			age, stale := tt.health.stale(now, threshold)
+			assert.Equal(t, tt.want, stale)
+			if tt.want {
+				assert.Equal(t, tt.wantAge, age)
+			}
+		})
+	}
+}
@@ -41,6 +41,13 @@ type OfferAnswer struct {
 	RelaySrvAddress string
 	// SessionID is the unique identifier of the session, used to discard old messages
 	SessionID *ICESessionID
+
+	// Features are the capabilities of the remote peer when receiving this message.
+	// This value is the local features when sending the message
+	Features Features
+
+	// Bond is the ICE session on the secondary uplink of a bonded connection, nil if the connection isn't bonded
+	Bond *BondOffer
 }
 
 type Handshaker struct {
@@ -57,6 +64,10 @@ type Handshaker struct {
 	// the listener will always process the latest offer
 	relayListener *AsyncOfferListener
 	iceListener   func(remoteOfferAnswer *OfferAnswer)
+	// bondListener is called before the ICE listener, so the ICE agent of the primary uplink is created for the bond
+	bondListener func(remoteOfferAnswer *OfferAnswer)
+	// bondOffer returns the local ICE session on the secondary uplink, nil if the connection isn't bonded
+	bondOffer func() *BondOffer
 
 	// remoteOffersCh is a channel used to wait for remote credentials to proceed with the connection
 	remoteOffersCh chan OfferAnswer
@@ -84,15 +95,26 @@ func (h *Handshaker) AddICEListener(offer func(remoteOfferAnswer *OfferAnswer))
 	h.iceListener = offer
 }
 
+// AddBondListener sets the handler of the offers and answers of bonded connections and the source of the local
+// bond offer
+func (h *Handshaker) AddBondListener(offer func(remoteOfferAnswer *OfferAnswer), bondOffer func() *BondOffer) {
+	h.bondListener = offer
+	h.bondOffer = bondOffer
+}
+
 func (h *Handshaker) Listen(ctx context.Context) {
 	for {
 		select {
 		case remoteOfferAnswer := <-h.remoteOffersCh:
-			h.log.Infof("received offer, running version %s, remote WireGuard listen port %d, session id: %s", remoteOfferAnswer.Version, remoteOfferAnswer.WgListenPort, remoteOfferAnswer.SessionIDString())
+			h.log.Infof("received offer, running version %s, features %s, remote WireGuard listen port %d, session id: %s", remoteOfferAnswer.Version, remoteOfferAnswer.Features, remoteOfferAnswer.WgListenPort, remoteOfferAnswer.SessionIDString())
 			if h.relayListener != nil {
 				h.relayListener.Notify(&remoteOfferAnswer)
 			}
 
+			if h.bondListener != nil {
+				h.bondListener(&remoteOfferAnswer)
+			}
+
 			if h.iceListener != nil {
 				h.iceListener(&remoteOfferAnswer)
 			}
@@ -102,11 +124,15 @@ func (h *Handshaker) Listen(ctx context.Context) {
 				continue
 			}
 		case remoteOfferAnswer := <-h.remoteAnswerCh:
-			h.log.Infof("received answer, running version %s, remote WireGuard listen port %d, session id: %s", remoteOfferAnswer.Version, remoteOfferAnswer.WgListenPort, remoteOfferAnswer.SessionIDString())
+			h.log.Infof("received answer, running version %s, features %s, remote WireGuard listen port %d, session id: %s", remoteOfferAnswer.Version, remoteOfferAnswer.Features, remoteOfferAnswer.WgListenPort, remoteOfferAnswer.SessionIDString())
 			if h.relayListener != nil {
 				h.relayListener.Notify(&remoteOfferAnswer)
 			}
 
+			if h.bondListener != nil {
+				h.bondListener(&remoteOfferAnswer)
+			}
+
 			if h.iceListener != nil {
 				h.iceListener(&remoteOfferAnswer)
 			}
@@ -178,11 +204,16 @@ func (h *Handshaker) buildOfferAnswer() OfferAnswer {
 		RosenpassPubKey: h.config.RosenpassConfig.PubKey,
 		RosenpassAddr:   h.config.RosenpassConfig.Addr,
 		SessionID:       &sid,
+		Features:        localFeatures(h.config),
 	}
 
 	if addr, err := h.relay.RelayInstanceAddress(); err == nil {
 		answer.RelaySrvAddress = addr
 	}
 
+	if h.bondOffer != nil {
+		answer.Bond = h.bondOffer()
+	}
+
 	return answer
 }
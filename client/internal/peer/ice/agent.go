@@ -2,6 +2,7 @@ package ice
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -29,7 +30,13 @@ const (
 
 type ThreadSafeAgent struct {
 	*ice.Agent
-	once sync.Once
+	once    sync.Once
+	onClose func()
+}
+
+// SetOnClose sets the function called once the agent has been closed
+func (a *ThreadSafeAgent) SetOnClose(onClose func()) {
+	a.onClose = onClose
 }
 
 func (a *ThreadSafeAgent) Close() error {
@@ -46,6 +53,10 @@ func (a *ThreadSafeAgent) Close() error {
 			log.Warnf("ICE agent close timed out after %v, proceeding with cleanup", iceAgentCloseTimeout)
 			err = nil
 		}
+
+		if a.onClose != nil {
+			a.onClose()
+		}
 	})
 	return err
 }
@@ -61,16 +72,35 @@ func NewAgent(ctx context.Context, iFaceDiscover stdnet.ExternalIFaceDiscover, c
 		log.Errorf("failed to create pion's stdnet: %s", err)
 	}
 
+	interfaceFilter := stdnet.InterfaceFilter(config.InterfaceBlackList)
+	if config.BindInterface != "" {
+		if transportNet == nil {
+			return nil, fmt.Errorf("bind to interface %s: no stdnet", config.BindInterface)
+		}
+		if err := transportNet.SetBindInterface(config.BindInterface); err != nil {
+			return nil, fmt.Errorf("bind to interface %s: %w", config.BindInterface, err)
+		}
+		interfaceFilter = func(iface string) bool {
+			return iface == config.BindInterface
+		}
+	}
+
 	fac := logging.NewDefaultLoggerFactory()
 
 	//fac.Writer = log.StandardLogger().Writer()
 
+	mDNSMode := ice.MulticastDNSModeDisabled
+	if config.MulticastDNS {
+		// the agent generates a random name, so the host candidates can't be linked across sessions
+		mDNSMode = ice.MulticastDNSModeQueryAndGather
+	}
+
 	agentConfig := &ice.AgentConfig{
-		MulticastDNSMode:       ice.MulticastDNSModeDisabled,
+		MulticastDNSMode:       mDNSMode,
 		NetworkTypes:           []ice.NetworkType{ice.NetworkTypeUDP4, ice.NetworkTypeUDP6},
 		Urls:                   config.StunTurn.Load(),
 		CandidateTypes:         candidateTypes,
-		InterfaceFilter:        stdnet.InterfaceFilter(config.InterfaceBlackList),
+		InterfaceFilter:        interfaceFilter,
 		UDPMux:                 config.UDPMux,
 		UDPMuxSrflx:            config.UDPMuxSrflx,
 		NAT1To1IPs:             config.NATExternalIPs,
@@ -108,15 +138,3 @@ func GenerateICECredentials() (string, string, error) {
 	}
 	return ufrag, pwd, nil
 }
-
-func CandidateTypes() []ice.CandidateType {
-	if hasICEForceRelayConn() {
-		return []ice.CandidateType{ice.CandidateTypeRelay}
-	}
-
-	return []ice.CandidateType{ice.CandidateTypeHost, ice.CandidateTypeServerReflexive, ice.CandidateTypeRelay}
-}
-
-func CandidateTypesP2P() []ice.CandidateType {
-	return []ice.CandidateType{ice.CandidateTypeHost, ice.CandidateTypeServerReflexive}
-}
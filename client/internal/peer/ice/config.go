@@ -12,9 +12,19 @@ type Config struct {
 	// (e.g. if eth0 is in the list, host candidate of this interface won't be used)
 	InterfaceBlackList   []string
 	DisableIPv6Discovery bool
+	// BindInterface restricts the candidates to the interface and binds the sockets to it, empty uses all the
+	// interfaces that aren't blacklisted
+	BindInterface string
 
 	UDPMux      ice.UDPMux
 	UDPMuxSrflx ice.UniversalUDPMux
 
 	NATExternalIPs []string
+
+	// MulticastDNS hides the IPs of the local host candidates behind random mDNS names and resolves the mDNS
+	// candidates of peers
+	MulticastDNS bool
+
+	// Policy restricts the candidate types used for the connection
+	Policy Policy
 }
@@ -0,0 +1,66 @@
+package ice
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pion/ice/v4"
+)
+
+// Policy restricts the ICE candidates used for peer connections
+type Policy string
+
+const (
+	// PolicyAll uses all candidate types, the default
+	PolicyAll Policy = "all"
+	// PolicyRelayOnly never connects peers directly. The relay service is used, or TURN if it isn't available.
+	PolicyRelayOnly Policy = "relay-only"
+	// PolicyNoRelay disables TURN, peers connect directly or through the relay service
+	PolicyNoRelay Policy = "no-relay"
+	// PolicyHostOnly only uses the local interface addresses, e.g. for peers on the same LAN
+	PolicyHostOnly Policy = "host-only"
+)
+
+// Policies are the valid policies
+var Policies = []Policy{PolicyAll, PolicyRelayOnly, PolicyNoRelay, PolicyHostOnly}
+
+// ParsePolicy parses a policy, an empty string is the default policy
+func ParsePolicy(s string) (Policy, error) {
+	if s == "" {
+		return PolicyAll, nil
+	}
+
+	for _, p := range Policies {
+		if strings.EqualFold(string(p), s) {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("invalid ICE policy %q, valid policies are: all, relay-only, no-relay, host-only", s)
+}
+
+// CandidateTypes returns the candidate types gathered when the relay service can't be used for the peer
+func (p Policy) CandidateTypes() []ice.CandidateType {
+	switch {
+	case p == PolicyRelayOnly || hasICEForceRelayConn():
+		return []ice.CandidateType{ice.CandidateTypeRelay}
+	case p == PolicyNoRelay:
+		return []ice.CandidateType{ice.CandidateTypeHost, ice.CandidateTypeServerReflexive}
+	case p == PolicyHostOnly:
+		return []ice.CandidateType{ice.CandidateTypeHost}
+	default:
+		return []ice.CandidateType{ice.CandidateTypeHost, ice.CandidateTypeServerReflexive, ice.CandidateTypeRelay}
+	}
+}
+
+// CandidateTypesP2P returns the candidate types gathered when the relay service is used for the peer as well.
+// No types means that ICE isn't used at all.
+func (p Policy) CandidateTypesP2P() []ice.CandidateType {
+	switch p {
+	case PolicyRelayOnly:
+		return nil
+	case PolicyHostOnly:
+		return []ice.CandidateType{ice.CandidateTypeHost}
+	default:
+		return []ice.CandidateType{ice.CandidateTypeHost, ice.CandidateTypeServerReflexive}
+	}
+}
@@ -0,0 +1,59 @@
+package ice
+
+import (
+	"testing"
+
+	"github.com/pion/ice/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Policy
+		wantErr bool
+	}{
+		{input: "", want: PolicyAll},
+		{input: "all", want: PolicyAll},
+		{input: "relay-only", want: PolicyRelayOnly},
+		{input: "No-Relay", want: PolicyNoRelay},
+		{input: "host-only", want: PolicyHostOnly},
+		{input: "turn-only", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParsePolicy(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPolicy_CandidateTypes(t *testing.T) {
+	host, srflx, relay := ice.CandidateTypeHost, ice.CandidateTypeServerReflexive, ice.CandidateTypeRelay
+
+	tests := []struct {
+		policy  Policy
+		want    []ice.CandidateType
+		wantP2P []ice.CandidateType
+	}{
+		{policy: "", want: []ice.CandidateType{host, srflx, relay}, wantP2P: []ice.CandidateType{host, srflx}},
+		{policy: PolicyAll, want: []ice.CandidateType{host, srflx, relay}, wantP2P: []ice.CandidateType{host, srflx}},
+		{policy: PolicyRelayOnly, want: []ice.CandidateType{relay}, wantP2P: nil},
+		{policy: PolicyNoRelay, want: []ice.CandidateType{host, srflx}, wantP2P: []ice.CandidateType{host, srflx}},
+		{policy: PolicyHostOnly, want: []ice.CandidateType{host}, wantP2P: []ice.CandidateType{host}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.CandidateTypes())
+			assert.Equal(t, tt.wantP2P, tt.policy.CandidateTypesP2P())
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package ice
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pion/stun/v3"
+)
+
+// TURNTransport is a transport the TURN servers are reached over
+type TURNTransport string
+
+const (
+	TURNTransportUDP TURNTransport = "udp"
+	TURNTransportTCP TURNTransport = "tcp"
+	// TURNTransportTLS is TURN over TLS on port 443, it passes most firewalls that block UDP
+	TURNTransportTLS TURNTransport = "tls"
+)
+
+// turnsFallbackPort is the port of the TLS fallback for TURN servers management announced for UDP
+const turnsFallbackPort = 443
+
+// DefaultTURNTransports is the transport preference if none is configured
+var DefaultTURNTransports = []TURNTransport{TURNTransportUDP, TURNTransportTCP, TURNTransportTLS}
+
+// ParseTURNTransports parses the transport preference, most preferred first. No transports is the default preference.
+func ParseTURNTransports(values []string) ([]TURNTransport, error) {
+	if len(values) == 0 {
+		return DefaultTURNTransports, nil
+	}
+
+	transports := make([]TURNTransport, 0, len(values))
+	for _, value := range values {
+		transport := TURNTransport(strings.ToLower(strings.TrimSpace(value)))
+		switch transport {
+		case TURNTransportUDP, TURNTransportTCP, TURNTransportTLS:
+		default:
+			return nil, fmt.Errorf("invalid TURN transport %q, valid transports are: udp, tcp, tls", value)
+		}
+		if !slices.Contains(transports, transport) {
+			transports = append(transports, transport)
+		}
+	}
+	return transports, nil
+}
+
+// TURNTransportOf returns the transport of a TURN URI
+func TURNTransportOf(uri *stun.URI) TURNTransport {
+	switch {
+	case uri.Scheme == stun.SchemeTypeTURNS:
+		return TURNTransportTLS
+	case uri.Proto == stun.ProtoTypeTCP:
+		return TURNTransportTCP
+	default:
+		return TURNTransportUDP
+	}
+}
+
+// TURNTransportURIs returns the variants of a TURN over UDP URI for the transports, in their order. The TCP variant
+// uses the same port, the TLS variant port 443. Other URIs were announced for a transport on purpose and are
+// returned as they are.
+func TURNTransportURIs(uri *stun.URI, transports []TURNTransport) []*stun.URI {
+	if uri.Scheme != stun.SchemeTypeTURN || uri.Proto != stun.ProtoTypeUDP {
+		return []*stun.URI{uri}
+	}
+
+	uris := make([]*stun.URI, 0, len(transports))
+	for _, transport := range transports {
+		variant := *uri
+		switch transport {
+		case TURNTransportUDP:
+		case TURNTransportTCP:
+			variant.Proto = stun.ProtoTypeTCP
+		case TURNTransportTLS:
+			variant.Scheme = stun.SchemeTypeTURNS
+			variant.Proto = stun.ProtoTypeTCP
+			variant.Port = turnsFallbackPort
+		default:
+			continue
+		}
+		uris = append(uris, &variant)
+	}
+	if len(uris) == 0 {
+		return []*stun.URI{uri}
+	}
+	return uris
+}
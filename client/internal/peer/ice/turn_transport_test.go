@@ -0,0 +1,49 @@
+package ice
+
+import (
+	"testing"
+
+	"github.com/pion/stun/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTURNTransports(t *testing.T) {
+	transports, err := ParseTURNTransports(nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultTURNTransports, transports)
+
+	transports, err = ParseTURNTransports([]string{"TLS", " udp", "tls"})
+	require.NoError(t, err)
+	assert.Equal(t, []TURNTransport{TURNTransportTLS, TURNTransportUDP}, transports)
+
+	_, err = ParseTURNTransports([]string{"quic"})
+	assert.Error(t, err)
+}
+
+func TestTURNTransportURIs(t *testing.T) {
+	uri, err := stun.ParseURI("turn:turn.example.com:3478")
+	require.NoError(t, err)
+	uri.Username, uri.Password = "user", "secret"
+
+	uris := TURNTransportURIs(uri, DefaultTURNTransports)
+	require.Len(t, uris, 3)
+	assert.Equal(t, "turn:turn.example.com:3478?transport=udp", uris[0].String())
+	assert.Equal(t, "turn:turn.example.com:3478?transport=tcp", uris[1].String())
+	assert.Equal(t, "turns:turn.example.com:443?transport=tcp", uris[2].String())
+	for _, variant := range uris {
+		assert.Equal(t, "user", variant.Username)
+		assert.Equal(t, "secret", variant.Password)
+	}
+	assert.Equal(t, []TURNTransport{TURNTransportUDP, TURNTransportTCP, TURNTransportTLS},
+		[]TURNTransport{TURNTransportOf(uris[0]), TURNTransportOf(uris[1]), TURNTransportOf(uris[2])})
+	assert.Equal(t, "turn:turn.example.com:3478?transport=udp", uri.String(), "the original URI is not modified")
+
+	uris = TURNTransportURIs(uri, []TURNTransport{TURNTransportTLS})
+	require.Len(t, uris, 1)
+	assert.Equal(t, "turns:turn.example.com:443?transport=tcp", uris[0].String())
+
+	tcp, err := stun.ParseURI("turn:turn.example.com:3478?transport=tcp")
+	require.NoError(t, err)
+	assert.Equal(t, []*stun.URI{tcp}, TURNTransportURIs(tcp, DefaultTURNTransports), "explicit transports are kept")
+}
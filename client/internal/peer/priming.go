@@ -0,0 +1,88 @@
+package peer
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pion/ice/v4"
+
+	icemaker "github.com/netbirdio/netbird/client/internal/peer/ice"
+)
+
+// ConnPriming is the last connection to a peer that succeeded. It primes the next connection attempt, the remote
+// candidate is checked right away instead of after the remote candidates arrived through Signal.
+type ConnPriming struct {
+	// RemoteCandidateType and RemoteEndpoint are the remote candidate of the last direct ICE pair
+	RemoteCandidateType string `json:"remote_candidate_type,omitempty"`
+	RemoteEndpoint      string `json:"remote_endpoint,omitempty"`
+	// RelayServer is the relay server instance the peer was last reached through
+	RelayServer string    `json:"relay_server,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Priming returns the last direct ICE pair and relay server of the connection, false if it never connected
+func (conn *Conn) Priming() (ConnPriming, bool) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	priming := ConnPriming{RelayServer: conn.relayServer}
+	for i := len(conn.candidatePairs) - 1; i >= 0; i-- {
+		pair := conn.candidatePairs[i]
+		// the TURN allocation of a relayed pair doesn't outlive the connection
+		if pair.Relayed {
+			continue
+		}
+		priming.RemoteCandidateType = pair.RemoteType
+		priming.RemoteEndpoint = pair.RemoteEndpoint
+		break
+	}
+	return priming, priming.RemoteEndpoint != "" || priming.RelayServer != ""
+}
+
+// primeAgent adds the remote candidate of the last connection that succeeded to the first agent of the worker. The
+// caller must hold muxAgent.
+func (w *WorkerICE) primeAgent(agent *icemaker.ThreadSafeAgent) {
+	priming := w.priming
+	w.priming = nil
+	if priming == nil || priming.RemoteEndpoint == "" {
+		return
+	}
+
+	candidate, err := primingCandidate(priming.RemoteEndpoint)
+	if err != nil {
+		w.log.Debugf("not priming the ICE agent: %v", err)
+		return
+	}
+	if err := agent.AddRemoteCandidate(candidate); err != nil {
+		w.log.Debugf("failed to prime the ICE agent: %v", err)
+		return
+	}
+	w.log.Debugf("primed the ICE agent with the last remote %s candidate %s", priming.RemoteCandidateType, priming.RemoteEndpoint)
+}
+
+// primingCandidate returns the remote endpoint as a peer reflexive candidate, an address known from a previous
+// connectivity check
+func primingCandidate(endpoint string) (ice.Candidate, error) {
+	i := strings.LastIndex(endpoint, ":")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid endpoint %q", endpoint)
+	}
+	addr, err := netip.ParseAddr(strings.Trim(endpoint[:i], "[]"))
+	if err != nil {
+		return nil, fmt.Errorf("parse endpoint address: %w", err)
+	}
+	port, err := strconv.ParseUint(endpoint[i+1:], 10, 16)
+	if err != nil || port == 0 {
+		return nil, fmt.Errorf("invalid endpoint port %q", endpoint[i+1:])
+	}
+
+	return ice.NewCandidatePeerReflexive(&ice.CandidatePeerReflexiveConfig{
+		Network:   "udp",
+		Address:   addr.Unmap().String(),
+		Port:      int(port),
+		Component: 1,
+	})
+}
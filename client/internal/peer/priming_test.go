@@ -0,0 +1,52 @@
+package peer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/ice/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConn_Priming(t *testing.T) {
+	conn := &Conn{}
+
+	_, ok := conn.Priming()
+	assert.False(t, ok, "a connection that never connected doesn't prime")
+
+	now := time.Now()
+	conn.recordCandidatePair(ICEConnInfo{
+		RemoteIceCandidateType:     "srflx",
+		RemoteIceCandidateEndpoint: "198.51.100.1:51820",
+	}, now)
+	conn.recordCandidatePair(ICEConnInfo{
+		RemoteIceCandidateType:     "relay",
+		RemoteIceCandidateEndpoint: "203.0.113.1:3478",
+		Relayed:                    true,
+	}, now.Add(time.Second))
+	conn.relayServer = "rels://relay.netbird.io:443"
+
+	priming, ok := conn.Priming()
+	require.True(t, ok)
+	assert.Equal(t, "srflx", priming.RemoteCandidateType, "the relayed TURN pair is skipped")
+	assert.Equal(t, "198.51.100.1:51820", priming.RemoteEndpoint)
+	assert.Equal(t, "rels://relay.netbird.io:443", priming.RelayServer)
+}
+
+func TestPrimingCandidate(t *testing.T) {
+	candidate, err := primingCandidate("198.51.100.1:51820")
+	require.NoError(t, err)
+	assert.Equal(t, ice.CandidateTypePeerReflexive, candidate.Type())
+	assert.Equal(t, "198.51.100.1", candidate.Address())
+	assert.Equal(t, 51820, candidate.Port())
+
+	candidate, err = primingCandidate("2001:db8::1:51820")
+	require.NoError(t, err)
+	assert.Equal(t, "2001:db8::1", candidate.Address())
+
+	for _, endpoint := range []string{"", "198.51.100.1", "host.local:51820", "198.51.100.1:0", "198.51.100.1:70000"} {
+		_, err := primingCandidate(endpoint)
+		assert.Error(t, err, endpoint)
+	}
+}
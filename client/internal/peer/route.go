@@ -131,3 +131,25 @@ func (r *routeIDLookup) Lookup(ip netip.Addr) (route.ResID, bool) {
 
 	return resourceID, isExitNode
 }
+
+// LookupPrefix returns the most specific local or remote route prefix that contains the given IP address.
+// The local routes take precedence, like in Lookup.
+func (r *routeIDLookup) LookupPrefix(ip netip.Addr) (netip.Prefix, bool) {
+	r.localLock.RLock()
+	for _, entry := range r.localRoutes {
+		if entry.prefix.Contains(ip) {
+			r.localLock.RUnlock()
+			return entry.prefix, true
+		}
+	}
+	r.localLock.RUnlock()
+
+	r.remoteLock.RLock()
+	defer r.remoteLock.RUnlock()
+	for _, entry := range r.remoteRoutes {
+		if entry.prefix.Contains(ip) {
+			return entry.prefix, true
+		}
+	}
+	return netip.Prefix{}, false
+}
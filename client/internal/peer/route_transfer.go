@@ -0,0 +1,77 @@
+package peer
+
+import (
+	"net/netip"
+	"sync"
+)
+
+// RouteTransfer holds the transfer counters of the flows to and from a routed network
+type RouteTransfer struct {
+	BytesRx int64
+	BytesTx int64
+}
+
+// routeTransfers accumulates the transfer counters per routed network
+type routeTransfers struct {
+	mu       sync.Mutex
+	counters map[netip.Prefix]RouteTransfer
+}
+
+func (r *routeTransfers) add(prefix netip.Prefix, bytesRx, bytesTx uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.counters == nil {
+		r.counters = make(map[netip.Prefix]RouteTransfer)
+	}
+	counter := r.counters[prefix]
+	counter.BytesRx += int64(bytesRx)
+	counter.BytesTx += int64(bytesTx)
+	r.counters[prefix] = counter
+}
+
+func (r *routeTransfers) remove(prefix netip.Prefix) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.counters, prefix)
+}
+
+// snapshot returns the counters keyed by the network as it appears in the routes of the peer states
+func (r *routeTransfers) snapshot() map[string]RouteTransfer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.counters) == 0 {
+		return nil
+	}
+	snapshot := make(map[string]RouteTransfer, len(r.counters))
+	for prefix, counter := range r.counters {
+		snapshot[prefix.String()] = counter
+	}
+	return snapshot
+}
+
+// AddRouteTransfer attributes the transfer of a finished flow to the routed network of the remote end of the flow.
+// The destination is checked first, flows that don't touch a routed network are ignored.
+func (d *Status) AddRouteTransfer(srcIP, dstIP netip.Addr, bytesRx, bytesTx uint64) {
+	if d == nil || bytesRx == 0 && bytesTx == 0 {
+		return
+	}
+
+	prefix, ok := d.routeIDLookup.LookupPrefix(dstIP)
+	if !ok {
+		prefix, ok = d.routeIDLookup.LookupPrefix(srcIP)
+	}
+	if !ok {
+		return
+	}
+	d.routeTransfers.add(prefix, bytesRx, bytesTx)
+}
+
+// removeRouteTransfer drops the counters of a network that is no longer routed
+func (d *Status) removeRouteTransfer(route string) {
+	if prefix, err := netip.ParsePrefix(route); err == nil {
+		d.routeTransfers.remove(prefix)
+	}
+}
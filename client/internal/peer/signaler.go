@@ -1,6 +1,11 @@
 package peer
 
 import (
+	"net"
+	"net/netip"
+	"sync"
+	"sync/atomic"
+
 	"github.com/pion/ice/v4"
 	log "github.com/sirupsen/logrus"
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
@@ -11,7 +16,10 @@ import (
 
 type Signaler struct {
 	signal       signal.Client
+	signalMu     sync.RWMutex
 	wgPrivateKey wgtypes.Key
+	// maintenance is announced with every offer and answer so peers learn about it on (re)connection
+	maintenance atomic.Bool
 }
 
 func NewSignaler(signal signal.Client, wgPrivateKey wgtypes.Key) *Signaler {
@@ -21,6 +29,19 @@ func NewSignaler(signal signal.Client, wgPrivateKey wgtypes.Key) *Signaler {
 	}
 }
 
+// SetClient replaces the Signal client the messages are sent with
+func (s *Signaler) SetClient(client signal.Client) {
+	s.signalMu.Lock()
+	defer s.signalMu.Unlock()
+	s.signal = client
+}
+
+func (s *Signaler) signalClient() signal.Client {
+	s.signalMu.RLock()
+	defer s.signalMu.RUnlock()
+	return s.signal
+}
+
 func (s *Signaler) SignalOffer(offer OfferAnswer, remoteKey string) error {
 	return s.signalOfferAnswer(offer, remoteKey, sProto.Body_OFFER)
 }
@@ -30,18 +51,31 @@ func (s *Signaler) SignalAnswer(offer OfferAnswer, remoteKey string) error {
 }
 
 func (s *Signaler) SignalICECandidate(candidate ice.Candidate, remoteKey string) error {
-	return s.signal.Send(&sProto.Message{
+	return s.signalClient().Send(&sProto.Message{
+		Key:       s.wgPrivateKey.PublicKey().String(),
+		RemoteKey: remoteKey,
+		Body: &sProto.Body{
+			Type:    sProto.Body_CANDIDATE,
+			Payload: candidate.Marshal(),
+		},
+	})
+}
+
+// SignalBondCandidate signals a candidate of the ICE session on the secondary uplink of a bonded connection
+func (s *Signaler) SignalBondCandidate(candidate ice.Candidate, remoteKey string) error {
+	return s.signalClient().Send(&sProto.Message{
 		Key:       s.wgPrivateKey.PublicKey().String(),
 		RemoteKey: remoteKey,
 		Body: &sProto.Body{
 			Type:    sProto.Body_CANDIDATE,
 			Payload: candidate.Marshal(),
+			Uplink:  uplinkSecondary,
 		},
 	})
 }
 
 func (s *Signaler) Ready() bool {
-	return s.signal.Ready()
+	return s.signalClient().Ready()
 }
 
 // SignalOfferAnswer signals either an offer or an answer to remote peer
@@ -66,8 +100,23 @@ func (s *Signaler) signalOfferAnswer(offerAnswer OfferAnswer, remoteKey string,
 	if err != nil {
 		return err
 	}
+	msg.Body.Maintenance = s.maintenance.Load()
+	msg.Body.FeaturesSupported = offerAnswer.Features.IDs()
+	if bond := offerAnswer.Bond; bond != nil {
+		bondSessionID, err := bond.SessionID.Bytes()
+		if err != nil {
+			log.Warnf("failed to get bond session ID bytes: %v", err)
+		}
+		msg.Body.Bond = &sProto.Bond{
+			UFrag:       bond.IceCredentials.UFrag,
+			Pwd:         bond.IceCredentials.Pwd,
+			SessionId:   bondSessionID,
+			Spray:       bond.Mode == BondModeSpray,
+			SprayWeight: uint32(bond.SprayWeight),
+		}
+	}
 
-	if err = s.signal.Send(msg); err != nil {
+	if err = s.signalClient().Send(msg); err != nil {
 		return err
 	}
 
@@ -75,7 +124,7 @@ func (s *Signaler) signalOfferAnswer(offerAnswer OfferAnswer, remoteKey string,
 }
 
 func (s *Signaler) SignalIdle(remoteKey string) error {
-	return s.signal.Send(&sProto.Message{
+	return s.signalClient().Send(&sProto.Message{
 		Key:       s.wgPrivateKey.PublicKey().String(),
 		RemoteKey: remoteKey,
 		Body: &sProto.Body{
@@ -83,3 +132,56 @@ func (s *Signaler) SignalIdle(remoteKey string) error {
 		},
 	})
 }
+
+// SignalWake asks the remote peer to send a Wake-on-LAN magic packet for the hardware address to its local network
+func (s *Signaler) SignalWake(remoteKey string, mac net.HardwareAddr, lanAddr netip.Addr) error {
+	wake := &sProto.WakeOnLan{
+		MacAddress: mac,
+	}
+	if lanAddr.IsValid() {
+		wake.LanAddress = lanAddr.String()
+	}
+
+	return s.signalClient().Send(&sProto.Message{
+		Key:       s.wgPrivateKey.PublicKey().String(),
+		RemoteKey: remoteKey,
+		Body: &sProto.Body{
+			Type:      sProto.Body_WAKE,
+			WakeOnLan: wake,
+		},
+	})
+}
+
+// SignalRouteFailover tells the routing peer that the traffic for the networks moved over to it from another routing peer
+func (s *Signaler) SignalRouteFailover(remoteKey string, networks []netip.Prefix) error {
+	failover := &sProto.RouteFailover{}
+	for _, network := range networks {
+		failover.Networks = append(failover.Networks, network.String())
+	}
+
+	return s.signalClient().Send(&sProto.Message{
+		Key:       s.wgPrivateKey.PublicKey().String(),
+		RemoteKey: remoteKey,
+		Body: &sProto.Body{
+			Type:          sProto.Body_ROUTE_FAILOVER,
+			RouteFailover: failover,
+		},
+	})
+}
+
+// SetMaintenance sets the maintenance flag attached to the outgoing offers and answers
+func (s *Signaler) SetMaintenance(enabled bool) {
+	s.maintenance.Store(enabled)
+}
+
+// SignalMaintenance announces the current maintenance state to the remote peer
+func (s *Signaler) SignalMaintenance(remoteKey string) error {
+	return s.signalClient().Send(&sProto.Message{
+		Key:       s.wgPrivateKey.PublicKey().String(),
+		RemoteKey: remoteKey,
+		Body: &sProto.Body{
+			Type:        sProto.Body_MAINTENANCE,
+			Maintenance: s.maintenance.Load(),
+		},
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/netip"
 	"slices"
+	"strconv"
 	"sync"
 	"time"
 
@@ -43,11 +44,23 @@ type EventListener interface {
 
 // RouterState status for router peers. This contains relevant fields for route manager
 type RouterState struct {
-	Status  ConnStatus
-	Relayed bool
-	Latency time.Duration
+	Status      ConnStatus
+	Relayed     bool
+	Latency     time.Duration
+	Maintenance bool
 }
 
+// OfflineReason tells why management doesn't let an offline peer connect
+type OfflineReason string
+
+const (
+	// OfflineReasonNone is a peer that is offline without a reason given by management
+	OfflineReasonNone               OfflineReason = ""
+	OfflineReasonLoginExpired       OfflineReason = "login expired"
+	OfflineReasonPostureCheckFailed OfflineReason = "posture check failed"
+	OfflineReasonDisabled           OfflineReason = "disabled"
+)
+
 // State contains the latest state of a peer
 type State struct {
 	Mux                        *sync.RWMutex
@@ -68,9 +81,21 @@ type State struct {
 	Latency                    time.Duration
 	RosenpassEnabled           bool
 	SSHHostKey                 []byte
+	Maintenance                bool
+	AllowedIPs                 []netip.Prefix
+	Offline                    bool
+	OfflineReason              OfflineReason
+	Lifetime                   LifetimeStats
 	routes                     map[string]struct{}
 }
 
+// LifetimeStats are the transfer counters and the last connection time of a peer, accumulated across engine restarts
+type LifetimeStats struct {
+	BytesTx       int64     `json:"bytesTx"`
+	BytesRx       int64     `json:"bytesRx"`
+	LastConnected time.Time `json:"lastConnected"`
+}
+
 // AddRoute add a single route to routes map
 func (s *State) AddRoute(network string) {
 	s.Mux.Lock()
@@ -109,11 +134,14 @@ type LocalPeerState struct {
 	KernelInterface bool
 	FQDN            string
 	Routes          map[string]struct{}
+	// Roles are the roles the peer derived from its local state
+	Roles []string
 }
 
 // Clone returns a copy of the LocalPeerState
 func (l LocalPeerState) Clone() LocalPeerState {
 	l.Routes = maps.Clone(l.Routes)
+	l.Roles = slices.Clone(l.Roles)
 	return l
 }
 
@@ -122,6 +150,13 @@ type SignalState struct {
 	URL       string
 	Connected bool
 	Error     error
+	// Reconnects counts how often the signal stream was connected again after it had been disconnected
+	Reconnects int
+	// LastDisconnect is when the signal stream was disconnected the last time, zero if it never was
+	LastDisconnect       time.Time
+	LastDisconnectReason string
+	// Latency is the round trip time of the last message sent through signal
+	Latency time.Duration
 }
 
 // ManagementState contains the latest state of a management connection
@@ -131,6 +166,14 @@ type ManagementState struct {
 	Error     error
 }
 
+// FlowState contains the latest state of the connection to the flow receiver. The URL is empty if flow logging is
+// disabled.
+type FlowState struct {
+	URL       string
+	Connected bool
+	Error     error
+}
+
 // RosenpassState contains the latest state of the Rosenpass configuration
 type RosenpassState struct {
 	Enabled    bool
@@ -147,17 +190,50 @@ type NSGroupState struct {
 	Error   error
 }
 
+// FirewallSetState is the number of addresses or networks a set of the firewall holds
+type FirewallSetState struct {
+	Name     string
+	Elements int
+}
+
+// FirewallBackendState tells which firewall backend the engine uses and why it was chosen
+type FirewallBackendState struct {
+	Backend string
+	// Native is the kernel firewall the userspace firewall uses for the routed traffic, if any
+	Native string
+	Reason string
+}
+
+// DNSListenerState is where the local DNS resolver listens
+type DNSListenerState struct {
+	// Addresses are the bound addresses, the system resolver uses the first one
+	Addresses []netip.AddrPort
+	// Fallbacks describe the configured addresses that couldn't be bound and what was bound instead
+	Fallbacks []string
+}
+
 // FullStatus contains the full state held by the Status instance
 type FullStatus struct {
 	Peers                 []State
 	ManagementState       ManagementState
 	SignalState           SignalState
+	FlowState             FlowState
 	LocalPeerState        LocalPeerState
 	RosenpassState        RosenpassState
 	Relays                []relay.ProbeResult
 	NSGroupStates         []NSGroupState
 	NumOfForwardingRules  int
 	LazyConnectionEnabled bool
+	MaintenanceEnabled    bool
+	FirewallSets          []FirewallSetState
+	FirewallBackend       FirewallBackendState
+	// NetstackFallback is why the engine fell back to netstack mode, empty if it did not
+	NetstackFallback string
+	DNSListener      DNSListenerState
+	// RouteTransfers are the transfer counters of the routed networks, keyed by the network
+	RouteTransfers map[string]RouteTransfer
+	// Resources are the resources used by the peer connections, nil if they aren't accounted
+	Resources *ResourceBudgetState
 }
 
 type StatusChangeSubscription struct {
@@ -188,6 +264,12 @@ type Status struct {
 	changeNotify          map[string]map[string]*StatusChangeSubscription // map[peerID]map[subscriptionID]*StatusChangeSubscription
 	signalState           bool
 	signalError           error
+	signalConnectedOnce   bool
+	signalReconnects      int
+	signalLastDisconnect  time.Time
+	signalDisconnectCause string
+	signalLatency         time.Duration
+	flowState             FlowState
 	managementState       bool
 	managementError       error
 	relayStates           []relay.ProbeResult
@@ -201,6 +283,11 @@ type Status struct {
 	nsGroupStates         []NSGroupState
 	resolvedDomainsStates map[domain.Domain]ResolvedDomainInfo
 	lazyConnectionEnabled bool
+	maintenanceEnabled    bool
+	firewallSets          []FirewallSetState
+	firewallBackend       FirewallBackendState
+	netstackFallback      string
+	dnsListener           DNSListenerState
 
 	// To reduce the number of notification invocation this bool will be true when need to call the notification
 	// Some Peer actions mostly used by in a batch when the network map has been synchronized. In these type of events
@@ -210,13 +297,17 @@ type Status struct {
 	relayMgr *relayClient.Manager
 
 	eventMux     sync.RWMutex
-	eventStreams map[string]chan *proto.SystemEvent
+	eventStreams map[string]*EventSubscription
 	eventQueue   *EventQueue
+	eventLimiter *eventLimiter
 
 	ingressGwMgr *ingressgw.Manager
+	// resourceBudget accounts the resources of the peer connections
+	resourceBudget *ResourceBudget
 
-	routeIDLookup routeIDLookup
-	wgIface       WGIfaceStatus
+	routeIDLookup  routeIDLookup
+	routeTransfers routeTransfers
+	wgIface        WGIfaceStatus
 }
 
 // NewRecorder returns a new Status instance
@@ -224,8 +315,9 @@ func NewRecorder(mgmAddress string) *Status {
 	return &Status{
 		peers:                 make(map[string]State),
 		changeNotify:          make(map[string]map[string]*StatusChangeSubscription),
-		eventStreams:          make(map[string]chan *proto.SystemEvent),
+		eventStreams:          make(map[string]*EventSubscription),
 		eventQueue:            NewEventQueue(eventQueueSize),
+		eventLimiter:          newEventLimiter(),
 		offlinePeers:          make([]State, 0),
 		notifier:              newNotifier(),
 		mgmAddress:            mgmAddress,
@@ -245,6 +337,20 @@ func (d *Status) SetIngressGwMgr(ingressGwMgr *ingressgw.Manager) {
 	d.ingressGwMgr = ingressGwMgr
 }
 
+// SetResourceBudget sets the budget of the peer connection resources
+func (d *Status) SetResourceBudget(budget *ResourceBudget) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.resourceBudget = budget
+}
+
+// ResourceBudget returns the budget of the peer connection resources, nil if it isn't set
+func (d *Status) ResourceBudget() *ResourceBudget {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	return d.resourceBudget
+}
+
 // ReplaceOfflinePeers replaces
 func (d *Status) ReplaceOfflinePeers(replacement []State) {
 	d.mux.Lock()
@@ -273,6 +379,7 @@ func (d *Status) AddPeer(peerPubKey string, fqdn string, ip string) error {
 		Mux:        new(sync.RWMutex),
 	}
 	d.peerListChangedForNotification = true
+	d.publishPeerNameEvent(EventPeerAdded, peerPubKey, fqdn, ip)
 	return nil
 }
 
@@ -300,21 +407,52 @@ func (d *Status) PeerByIP(ip string) (string, bool) {
 	return "", false
 }
 
+// PeerStateByIP returns the state of the peer with the given NetBird IP
+func (d *Status) PeerStateByIP(ip string) (State, bool) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	for _, state := range d.peers {
+		if state.IP == ip {
+			return state, true
+		}
+	}
+	return State{}, false
+}
+
 // RemovePeer removes peer from Daemon status map
 func (d *Status) RemovePeer(peerPubKey string) error {
 	d.mux.Lock()
 	defer d.mux.Unlock()
 
-	_, ok := d.peers[peerPubKey]
+	state, ok := d.peers[peerPubKey]
 	if !ok {
 		return errors.New("no peer with to remove")
 	}
 
 	delete(d.peers, peerPubKey)
 	d.peerListChangedForNotification = true
+	d.publishPeerNameEvent(EventPeerRemoved, peerPubKey, state.FQDN, state.IP)
 	return nil
 }
 
+// publishPeerNameEvent publishes the lifecycle event of a peer name joining or leaving the network, e.g. for
+// mirroring the names into another DNS zone
+func (d *Status) publishPeerNameEvent(eventType, peerPubKey, fqdn, ip string) {
+	msg := "Peer added"
+	if eventType == EventPeerRemoved {
+		msg = "Peer removed"
+	}
+	d.PublishLifecycleEvent(
+		eventType,
+		proto.SystemEvent_INFO,
+		proto.SystemEvent_CONNECTIVITY,
+		msg,
+		"",
+		map[string]string{"peer": peerPubKey, "fqdn": fqdn, "ip": ip},
+	)
+}
+
 // UpdatePeerState updates peer status
 func (d *Status) UpdatePeerState(receivedState State) error {
 	d.mux.Lock()
@@ -343,6 +481,7 @@ func (d *Status) UpdatePeerState(receivedState State) error {
 
 	if hasConnStatusChanged(oldState, receivedState.ConnStatus) {
 		d.notifyPeerListChanged()
+		d.publishPeerConnEvent(peerState, oldState)
 	}
 
 	// when we close the connection we will not notify the router manager
@@ -369,6 +508,15 @@ func (d *Status) AddPeerStateRoute(peer string, route string, resourceId route.R
 		d.routeIDLookup.AddRemoteRouteID(resourceId, pref)
 	}
 
+	d.PublishLifecycleEvent(
+		EventRouteAdded,
+		proto.SystemEvent_INFO,
+		proto.SystemEvent_NETWORK,
+		"Route added",
+		"",
+		map[string]string{"network": route, "peer": peer, "fqdn": peerState.FQDN, "id": string(resourceId)},
+	)
+
 	// todo: consider to make sense of this notification or not
 	d.notifyPeerListChanged()
 	return nil
@@ -390,6 +538,16 @@ func (d *Status) RemovePeerStateRoute(peer string, route string) error {
 	if err == nil {
 		d.routeIDLookup.RemoveRemoteRouteID(pref)
 	}
+	d.removeRouteTransfer(route)
+
+	d.PublishLifecycleEvent(
+		EventRouteRemoved,
+		proto.SystemEvent_INFO,
+		proto.SystemEvent_NETWORK,
+		"Route removed",
+		"",
+		map[string]string{"network": route, "peer": peer, "fqdn": peerState.FQDN},
+	)
 
 	// todo: consider to make sense of this notification or not
 	d.notifyPeerListChanged()
@@ -431,6 +589,7 @@ func (d *Status) UpdatePeerICEState(receivedState State) error {
 
 	if hasConnStatusChanged(oldState, receivedState.ConnStatus) {
 		d.notifyPeerListChanged()
+		d.publishPeerConnEvent(peerState, oldState)
 	}
 
 	if hasStatusOrRelayedChange(oldState, receivedState.ConnStatus, oldIsRelayed, receivedState.Relayed) {
@@ -439,6 +598,28 @@ func (d *Status) UpdatePeerICEState(receivedState State) error {
 	return nil
 }
 
+// UpdatePeerMaintenance records the maintenance state announced by the remote peer
+// and notifies the route manager, so it can move routes away from the peer.
+func (d *Status) UpdatePeerMaintenance(pubKey string, maintenance bool) (bool, error) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	peerState, ok := d.peers[pubKey]
+	if !ok {
+		return false, errors.New("peer doesn't exist")
+	}
+
+	if peerState.Maintenance == maintenance {
+		return false, nil
+	}
+
+	peerState.Maintenance = maintenance
+	d.peers[pubKey] = peerState
+
+	d.notifyPeerStateChangeListeners(pubKey)
+	return true, nil
+}
+
 func (d *Status) UpdatePeerRelayedState(receivedState State) error {
 	d.mux.Lock()
 	defer d.mux.Unlock()
@@ -461,6 +642,7 @@ func (d *Status) UpdatePeerRelayedState(receivedState State) error {
 
 	if hasConnStatusChanged(oldState, receivedState.ConnStatus) {
 		d.notifyPeerListChanged()
+		d.publishPeerConnEvent(peerState, oldState)
 	}
 
 	if hasStatusOrRelayedChange(oldState, receivedState.ConnStatus, oldIsRelayed, receivedState.Relayed) {
@@ -490,6 +672,7 @@ func (d *Status) UpdatePeerRelayedStateToDisconnected(receivedState State) error
 
 	if hasConnStatusChanged(oldState, receivedState.ConnStatus) {
 		d.notifyPeerListChanged()
+		d.publishPeerConnEvent(peerState, oldState)
 	}
 
 	if hasStatusOrRelayedChange(oldState, receivedState.ConnStatus, oldIsRelayed, receivedState.Relayed) {
@@ -522,6 +705,7 @@ func (d *Status) UpdatePeerICEStateToDisconnected(receivedState State) error {
 
 	if hasConnStatusChanged(oldState, receivedState.ConnStatus) {
 		d.notifyPeerListChanged()
+		d.publishPeerConnEvent(peerState, oldState)
 	}
 
 	if hasStatusOrRelayedChange(oldState, receivedState.ConnStatus, oldIsRelayed, receivedState.Relayed) {
@@ -549,10 +733,53 @@ func (d *Status) UpdateWireGuardPeerState(pubKey string, wgStats configurer.WGSt
 	return nil
 }
 
+// UpdatePeerLifetimeStats updates the accumulated transfer counters and the last connection time of the peer
+func (d *Status) UpdatePeerLifetimeStats(pubKey string, stats LifetimeStats) error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	peerState, ok := d.peers[pubKey]
+	if !ok {
+		return errors.New("peer doesn't exist")
+	}
+
+	peerState.Lifetime = stats
+	d.peers[pubKey] = peerState
+
+	return nil
+}
+
 func hasStatusOrRelayedChange(oldConnStatus, newConnStatus ConnStatus, oldRelayed, newRelayed bool) bool {
 	return oldRelayed != newRelayed || hasConnStatusChanged(newConnStatus, oldConnStatus)
 }
 
+// publishPeerConnEvent publishes a lifecycle event when the peer connected or lost its connection
+func (d *Status) publishPeerConnEvent(state State, oldStatus ConnStatus) {
+	var eventType, msg string
+	switch {
+	case state.ConnStatus == StatusConnected:
+		eventType, msg = EventPeerConnected, "Peer connected"
+	case oldStatus == StatusConnected:
+		eventType, msg = EventPeerDisconnected, "Peer disconnected"
+	default:
+		return
+	}
+
+	d.PublishLifecycleEvent(
+		eventType,
+		proto.SystemEvent_INFO,
+		proto.SystemEvent_CONNECTIVITY,
+		msg,
+		"",
+		map[string]string{
+			"peer":    state.PubKey,
+			"fqdn":    state.FQDN,
+			"ip":      state.IP,
+			"relayed": strconv.FormatBool(state.Relayed),
+		},
+	)
+}
+
 func hasConnStatusChanged(oldStatus, newStatus ConnStatus) bool {
 	return newStatus != oldStatus
 }
@@ -567,9 +794,30 @@ func (d *Status) UpdatePeerFQDN(peerPubKey, fqdn string) error {
 		return errors.New("peer doesn't exist")
 	}
 
+	oldFQDN := peerState.FQDN
 	peerState.FQDN = fqdn
 	d.peers[peerPubKey] = peerState
 
+	if oldFQDN != fqdn {
+		d.publishPeerNameEvent(EventPeerRemoved, peerPubKey, oldFQDN, peerState.IP)
+		d.publishPeerNameEvent(EventPeerAdded, peerPubKey, fqdn, peerState.IP)
+	}
+	return nil
+}
+
+// UpdatePeerAllowedIPs updates peer's allowed IPs as received from management
+func (d *Status) UpdatePeerAllowedIPs(peerPubKey string, allowedIPs []netip.Prefix) error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	peerState, ok := d.peers[peerPubKey]
+	if !ok {
+		return errors.New("peer doesn't exist")
+	}
+
+	peerState.AllowedIPs = allowedIPs
+	d.peers[peerPubKey] = peerState
+
 	return nil
 }
 
@@ -659,6 +907,14 @@ func (d *Status) UpdateLocalPeerState(localPeerState LocalPeerState) {
 	d.notifyAddressChanged()
 }
 
+// UpdateLocalPeerRoles updates the roles of the local peer
+func (d *Status) UpdateLocalPeerRoles(roles []string) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	d.localPeer.Roles = roles
+}
+
 // AddLocalPeerStateRoute adds a route to the local peer state
 func (d *Status) AddLocalPeerStateRoute(route string, resourceId route.ResID) {
 	d.mux.Lock()
@@ -685,6 +941,7 @@ func (d *Status) RemoveLocalPeerStateRoute(route string) {
 	if err == nil {
 		d.routeIDLookup.RemoveLocalRouteID(pref)
 	}
+	d.removeRouteTransfer(route)
 
 	delete(d.localPeer.Routes, route)
 }
@@ -773,12 +1030,26 @@ func (d *Status) UpdateLazyConnection(enabled bool) {
 	d.lazyConnectionEnabled = enabled
 }
 
+// UpdateMaintenance sets whether the local peer announces maintenance to its peers
+func (d *Status) UpdateMaintenance(enabled bool) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.maintenanceEnabled = enabled
+}
+
 // MarkSignalDisconnected sets SignalState to disconnected
 func (d *Status) MarkSignalDisconnected(err error) {
 	d.mux.Lock()
 	defer d.mux.Unlock()
 	defer d.onConnectionChanged()
 
+	if d.signalState {
+		d.signalLastDisconnect = time.Now()
+		d.signalDisconnectCause = ""
+		if err != nil {
+			d.signalDisconnectCause = err.Error()
+		}
+	}
 	d.signalState = false
 	d.signalError = err
 }
@@ -789,10 +1060,52 @@ func (d *Status) MarkSignalConnected() {
 	defer d.mux.Unlock()
 	defer d.onConnectionChanged()
 
+	if !d.signalState && d.signalConnectedOnce {
+		d.signalReconnects++
+	}
+	d.signalConnectedOnce = true
 	d.signalState = true
 	d.signalError = nil
 }
 
+// UpdateSignalLatency records the round trip time of a message sent through signal
+func (d *Status) UpdateSignalLatency(latency time.Duration) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.signalLatency = latency
+}
+
+// UpdateFlowURL sets the address of the flow receiver and resets its connection state. An empty URL marks flow
+// logging as disabled.
+func (d *Status) UpdateFlowURL(url string) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.flowState = FlowState{URL: url}
+}
+
+// MarkFlowDisconnected sets FlowState to disconnected
+func (d *Status) MarkFlowDisconnected(err error) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.flowState.Connected = false
+	d.flowState.Error = err
+}
+
+// MarkFlowConnected sets FlowState to connected
+func (d *Status) MarkFlowConnected() {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.flowState.Connected = true
+	d.flowState.Error = nil
+}
+
+// GetFlowState returns the state of the connection to the flow receiver
+func (d *Status) GetFlowState() FlowState {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	return d.flowState
+}
+
 func (d *Status) UpdateRelayStates(relayResults []relay.ProbeResult) {
 	d.mux.Lock()
 	defer d.mux.Unlock()
@@ -851,6 +1164,69 @@ func (d *Status) GetLazyConnection() bool {
 	return d.lazyConnectionEnabled
 }
 
+// GetMaintenance returns whether the local peer is in maintenance mode
+func (d *Status) GetMaintenance() bool {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	return d.maintenanceEnabled
+}
+
+// UpdateFirewallSets records the sizes of the firewall sets
+func (d *Status) UpdateFirewallSets(sets []FirewallSetState) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.firewallSets = slices.Clone(sets)
+}
+
+// GetFirewallSets returns the sizes of the firewall sets
+func (d *Status) GetFirewallSets() []FirewallSetState {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	return slices.Clone(d.firewallSets)
+}
+
+// UpdateFirewallBackend records which firewall backend is in use
+func (d *Status) UpdateFirewallBackend(backend FirewallBackendState) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.firewallBackend = backend
+}
+
+// GetFirewallBackend returns which firewall backend is in use
+func (d *Status) GetFirewallBackend() FirewallBackendState {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	return d.firewallBackend
+}
+
+// UpdateNetstackFallback records why the engine fell back to netstack mode
+func (d *Status) UpdateNetstackFallback(reason string) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.netstackFallback = reason
+}
+
+// GetNetstackFallback returns why the engine fell back to netstack mode, empty if it did not
+func (d *Status) GetNetstackFallback() string {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	return d.netstackFallback
+}
+
+// UpdateDNSListener records where the local DNS resolver listens
+func (d *Status) UpdateDNSListener(state DNSListenerState) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.dnsListener = state
+}
+
+// GetDNSListener returns where the local DNS resolver listens
+func (d *Status) GetDNSListener() DNSListenerState {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	return d.dnsListener
+}
+
 func (d *Status) GetManagementState() ManagementState {
 	d.mux.Lock()
 	defer d.mux.Unlock()
@@ -898,9 +1274,13 @@ func (d *Status) GetSignalState() SignalState {
 	d.mux.Lock()
 	defer d.mux.Unlock()
 	return SignalState{
-		d.signalAddress,
-		d.signalState,
-		d.signalError,
+		URL:                  d.signalAddress,
+		Connected:            d.signalState,
+		Error:                d.signalError,
+		Reconnects:           d.signalReconnects,
+		LastDisconnect:       d.signalLastDisconnect,
+		LastDisconnectReason: d.signalDisconnectCause,
+		Latency:              d.signalLatency,
 	}
 }
 
@@ -945,6 +1325,18 @@ func (d *Status) ForwardingRules() []firewall.ForwardRule {
 	return d.ingressGwMgr.Rules()
 }
 
+// ForwardingRuleStates returns the forwarding rules including the draining ones, with the health of their translated
+// addresses
+func (d *Status) ForwardingRuleStates() []ingressgw.RuleState {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	if d.ingressGwMgr == nil {
+		return nil
+	}
+
+	return d.ingressGwMgr.RuleStates()
+}
+
 func (d *Status) GetDNSStates() []NSGroupState {
 	d.mux.Lock()
 	defer d.mux.Unlock()
@@ -964,17 +1356,28 @@ func (d *Status) GetFullStatus() FullStatus {
 	fullStatus := FullStatus{
 		ManagementState:       d.GetManagementState(),
 		SignalState:           d.GetSignalState(),
+		FlowState:             d.GetFlowState(),
 		Relays:                d.GetRelayStates(),
 		RosenpassState:        d.GetRosenpassState(),
 		NSGroupStates:         d.GetDNSStates(),
 		NumOfForwardingRules:  len(d.ForwardingRules()),
 		LazyConnectionEnabled: d.GetLazyConnection(),
+		MaintenanceEnabled:    d.GetMaintenance(),
+		FirewallSets:          d.GetFirewallSets(),
+		FirewallBackend:       d.GetFirewallBackend(),
+		NetstackFallback:      d.GetNetstackFallback(),
+		DNSListener:           d.GetDNSListener(),
+		RouteTransfers:        d.routeTransfers.snapshot(),
 	}
 
 	d.mux.Lock()
 	defer d.mux.Unlock()
 
 	fullStatus.LocalPeerState = d.localPeer
+	if d.resourceBudget != nil {
+		resources := d.resourceBudget.State()
+		fullStatus.Resources = &resources
+	}
 
 	for _, status := range d.peers {
 		fullStatus.Peers = append(fullStatus.Peers, status)
@@ -1030,9 +1433,10 @@ func (d *Status) notifyPeerStateChangeListeners(peerID string) {
 		}
 
 		routerPeers[pid] = RouterState{
-			Status:  s.ConnStatus,
-			Relayed: s.Relayed,
-			Latency: s.Latency,
+			Status:      s.ConnStatus,
+			Relayed:     s.Relayed,
+			Latency:     s.Latency,
+			Maintenance: s.Maintenance,
 		}
 	}
 
@@ -1056,7 +1460,8 @@ func (d *Status) numOfPeers() int {
 	return len(d.peers) + len(d.offlinePeers)
 }
 
-// PublishEvent adds an event to the queue and distributes it to all subscribers
+// PublishEvent adds an event to the queue and distributes it to all subscribers.
+// The event is deduplicated by its severity, category, message and metadata.
 func (d *Status) PublishEvent(
 	severity proto.SystemEvent_Severity,
 	category proto.SystemEvent_Category,
@@ -1064,7 +1469,34 @@ func (d *Status) PublishEvent(
 	userMsg string,
 	metadata map[string]string,
 ) {
-	event := &proto.SystemEvent{
+	d.PublishDedupEvent("", severity, category, msg, userMsg, metadata)
+}
+
+// PublishDedupEvent adds an event to the queue and distributes it to all subscribers.
+// Events with the same dedup key are published at most once per dedup window, and all events are subject to the
+// rate limit of their category. An empty key falls back to the key derived by PublishEvent.
+func (d *Status) PublishDedupEvent(
+	dedupKey string,
+	severity proto.SystemEvent_Severity,
+	category proto.SystemEvent_Category,
+	msg string,
+	userMsg string,
+	metadata map[string]string,
+) {
+	if dedupKey == "" {
+		dedupKey = eventDedupKey(severity, category, msg, metadata)
+	}
+
+	d.eventMux.Lock()
+	defer d.eventMux.Unlock()
+
+	allowed, suppressed := d.eventLimiter.allow(dedupKey, severity, category)
+	if !allowed {
+		log.Tracef("event suppressed: %s", dedupKey)
+		return
+	}
+
+	d.publishEvent(&proto.SystemEvent{
 		Id:          uuid.New().String(),
 		Severity:    severity,
 		Category:    category,
@@ -1072,16 +1504,53 @@ func (d *Status) PublishEvent(
 		UserMessage: userMsg,
 		Metadata:    metadata,
 		Timestamp:   timestamppb.Now(),
+		DedupKey:    dedupKey,
+		Suppressed:  suppressed,
+	})
+}
+
+// PublishLifecycleEvent publishes an event marking a state transition, like a peer connecting or the engine
+// going down. The event type is stored in the metadata under EventTypeKey. Lifecycle events bypass deduplication
+// and rate limits, consumers like hooks rely on seeing every transition.
+func (d *Status) PublishLifecycleEvent(
+	eventType string,
+	severity proto.SystemEvent_Severity,
+	category proto.SystemEvent_Category,
+	msg string,
+	userMsg string,
+	metadata map[string]string,
+) {
+	meta := make(map[string]string, len(metadata)+1)
+	for k, v := range metadata {
+		meta[k] = v
 	}
+	meta[EventTypeKey] = eventType
 
 	d.eventMux.Lock()
 	defer d.eventMux.Unlock()
 
+	d.publishEvent(&proto.SystemEvent{
+		Id:          uuid.New().String(),
+		Severity:    severity,
+		Category:    category,
+		Message:     msg,
+		UserMessage: userMsg,
+		Metadata:    meta,
+		Timestamp:   timestamppb.Now(),
+	})
+}
+
+// publishEvent adds the event to the queue and distributes it to the matching subscribers. The event mutex must be held.
+func (d *Status) publishEvent(event *proto.SystemEvent) {
 	d.eventQueue.Add(event)
 
-	for _, stream := range d.eventStreams {
+	for _, sub := range d.eventStreams {
+		if !sub.filter.Match(event) {
+			continue
+		}
+
 		select {
-		case stream <- event:
+		case sub.events <- event:
 		default:
 			log.Debugf("event stream buffer full, skipping event: %v", event)
 		}
@@ -1090,19 +1559,19 @@ func (d *Status) PublishEvent(
 	log.Debugf("event published: %v", event)
 }
 
-// SubscribeToEvents returns a new event subscription
-func (d *Status) SubscribeToEvents() *EventSubscription {
+// SubscribeToEvents returns a new event subscription that receives the events matching the filter
+func (d *Status) SubscribeToEvents(filter EventFilter) *EventSubscription {
 	d.eventMux.Lock()
 	defer d.eventMux.Unlock()
 
-	id := uuid.New().String()
-	stream := make(chan *proto.SystemEvent, 10)
-	d.eventStreams[id] = stream
-
-	return &EventSubscription{
-		id:     id,
-		events: stream,
+	sub := &EventSubscription{
+		id:     uuid.New().String(),
+		events: make(chan *proto.SystemEvent, 10),
+		filter: filter,
 	}
+	d.eventStreams[sub.id] = sub
+
+	return sub
 }
 
 // UnsubscribeFromEvents removes an event subscription
@@ -1115,7 +1584,7 @@ func (d *Status) UnsubscribeFromEvents(sub *EventSubscription) {
 	defer d.eventMux.Unlock()
 
 	if stream, exists := d.eventStreams[sub.id]; exists {
-		close(stream)
+		close(stream.events)
 		delete(d.eventStreams, sub.id)
 	}
 }
@@ -1176,6 +1645,7 @@ func (q *EventQueue) GetAll() []*proto.SystemEvent {
 type EventSubscription struct {
 	id     string
 	events chan *proto.SystemEvent
+	filter EventFilter
 }
 
 func (s *EventSubscription) Events() <-chan *proto.SystemEvent {
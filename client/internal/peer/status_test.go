@@ -3,11 +3,13 @@ package peer
 import (
 	"context"
 	"errors"
+	"net/netip"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAddPeer(t *testing.T) {
@@ -109,6 +111,51 @@ func TestGetPeerStateChangeNotifierLogic(t *testing.T) {
 	}
 }
 
+func TestUpdatePeerMaintenance(t *testing.T) {
+	key := "abc"
+	status := NewRecorder("https://mgm")
+	_ = status.AddPeer(key, "abc.netbird", "10.10.10.10")
+
+	sub := status.SubscribeToPeerStateChanges(context.Background(), key)
+
+	changed, err := status.UpdatePeerMaintenance(key, true)
+	assert.NoError(t, err, "shouldn't return error")
+	assert.True(t, changed, "maintenance state should have changed")
+
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+	select {
+	case states := <-sub.eventsChan:
+		assert.True(t, states[key].Maintenance, "router state should report maintenance")
+	case <-timeoutCtx.Done():
+		t.Errorf("timed out waiting for event")
+	}
+
+	changed, err = status.UpdatePeerMaintenance(key, true)
+	assert.NoError(t, err, "shouldn't return error")
+	assert.False(t, changed, "repeated announcement shouldn't change the state")
+
+	_, err = status.UpdatePeerMaintenance("unknown", true)
+	assert.Error(t, err, "unknown peer should return error")
+}
+
+func TestUpdatePeerLifetimeStats(t *testing.T) {
+	key := "abc"
+	status := NewRecorder("https://mgm")
+	_ = status.AddPeer(key, "abc.netbird", "10.10.10.10")
+
+	stats := LifetimeStats{BytesTx: 100, BytesRx: 200, LastConnected: time.Now()}
+	err := status.UpdatePeerLifetimeStats(key, stats)
+	assert.NoError(t, err, "shouldn't return error")
+
+	state, err := status.GetPeer(key)
+	assert.NoError(t, err)
+	assert.Equal(t, stats, state.Lifetime)
+
+	err = status.UpdatePeerLifetimeStats("unknown", stats)
+	assert.Error(t, err, "unknown peer should return error")
+}
+
 func TestRemovePeer(t *testing.T) {
 	key := "abc"
 	status := NewRecorder("https://mgm")
@@ -186,6 +233,47 @@ func TestUpdateSignalState(t *testing.T) {
 	}
 }
 
+func TestSignalReconnectTelemetry(t *testing.T) {
+	status := NewRecorder("https://mgm")
+	status.UpdateSignalAddress("https://signal")
+
+	status.MarkSignalDisconnected(nil)
+	status.MarkSignalConnected()
+	state := status.GetSignalState()
+	assert.Equal(t, 0, state.Reconnects, "the first connection is no reconnect")
+	assert.True(t, state.LastDisconnect.IsZero())
+
+	status.MarkSignalDisconnected(errors.New("stream closed"))
+	status.MarkSignalDisconnected(errors.New("unavailable"))
+	state = status.GetSignalState()
+	assert.False(t, state.LastDisconnect.IsZero())
+	assert.Equal(t, "stream closed", state.LastDisconnectReason, "only the transition to disconnected should be recorded")
+
+	status.MarkSignalConnected()
+	status.MarkSignalConnected()
+	status.UpdateSignalLatency(25 * time.Millisecond)
+	state = status.GetSignalState()
+	assert.Equal(t, 1, state.Reconnects)
+	assert.True(t, state.Connected)
+	assert.NoError(t, state.Error)
+	assert.Equal(t, 25*time.Millisecond, state.Latency)
+}
+
+func TestUpdateFlowState(t *testing.T) {
+	status := NewRecorder("https://mgm")
+	assert.Equal(t, FlowState{}, status.GetFlowState())
+
+	status.UpdateFlowURL("https://flow")
+	status.MarkFlowDisconnected(errors.New("unavailable"))
+	assert.Equal(t, FlowState{URL: "https://flow", Error: errors.New("unavailable")}, status.GetFlowState())
+
+	status.MarkFlowConnected()
+	assert.Equal(t, FlowState{URL: "https://flow", Connected: true}, status.GetFlowState())
+
+	status.UpdateFlowURL("")
+	assert.Equal(t, FlowState{}, status.GetFullStatus().FlowState)
+}
+
 func TestUpdateManagementState(t *testing.T) {
 	url := "https://management"
 	var tests = []struct {
@@ -247,3 +335,30 @@ func TestGetFullStatus(t *testing.T) {
 	assert.Equal(t, signalState, fullStatus.SignalState, "signal status should be equal")
 	assert.ElementsMatch(t, []State{peerState1, peerState2}, fullStatus.Peers, "peers states should match")
 }
+
+func TestAddRouteTransfer(t *testing.T) {
+	key := "abc"
+	status := NewRecorder("https://mgm")
+	require.NoError(t, status.AddPeer(key, "abc.netbird", "100.64.0.10"))
+	require.NoError(t, status.AddPeerStateRoute(key, "10.0.0.0/16", "res1"))
+	require.NoError(t, status.AddPeerStateRoute(key, "10.0.1.0/24", "res2"))
+	status.AddLocalPeerStateRoute("192.168.1.0/24", "res3")
+
+	status.AddRouteTransfer(netip.MustParseAddr("100.64.0.1"), netip.MustParseAddr("10.0.1.5"), 100, 200)
+	status.AddRouteTransfer(netip.MustParseAddr("100.64.0.1"), netip.MustParseAddr("10.0.2.5"), 10, 20)
+	status.AddRouteTransfer(netip.MustParseAddr("10.0.2.5"), netip.MustParseAddr("100.64.0.1"), 1, 2)
+	status.AddRouteTransfer(netip.MustParseAddr("100.64.0.20"), netip.MustParseAddr("192.168.1.1"), 5, 5)
+	status.AddRouteTransfer(netip.MustParseAddr("100.64.0.1"), netip.MustParseAddr("172.16.0.1"), 7, 7)
+
+	assert.Equal(t, map[string]RouteTransfer{
+		"10.0.1.0/24":    {BytesRx: 100, BytesTx: 200},
+		"10.0.0.0/16":    {BytesRx: 11, BytesTx: 22},
+		"192.168.1.0/24": {BytesRx: 5, BytesTx: 5},
+	}, status.GetFullStatus().RouteTransfers)
+
+	require.NoError(t, status.RemovePeerStateRoute(key, "10.0.1.0/24"))
+	status.RemoveLocalPeerStateRoute("192.168.1.0/24")
+	assert.Equal(t, map[string]RouteTransfer{
+		"10.0.0.0/16": {BytesRx: 11, BytesTx: 22},
+	}, status.GetFullStatus().RouteTransfers, "the counters of removed routes should be dropped")
+}
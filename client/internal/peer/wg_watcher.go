@@ -34,6 +34,9 @@ type WGWatcher struct {
 	ctxCancel   context.CancelFunc
 	ctxLock     sync.Mutex
 	enabledTime time.Time
+
+	// handshakeOvertime overrides the package level wgHandshakeOvertime if set
+	handshakeOvertime time.Duration
 }
 
 func NewWGWatcher(log *log.Entry, wgIfaceStater WGInterfaceStater, peerKey string, stateDump *stateDump) *WGWatcher {
@@ -45,6 +48,11 @@ func NewWGWatcher(log *log.Entry, wgIfaceStater WGInterfaceStater, peerKey strin
 	}
 }
 
+// SetHandshakeOvertime sets the allowed handshake delay for this watcher. Zero restores the default.
+func (w *WGWatcher) SetHandshakeOvertime(overtime time.Duration) {
+	w.handshakeOvertime = overtime
+}
+
 // EnableWgWatcher starts the WireGuard watcher. If it is already enabled, it will return immediately and do nothing.
 func (w *WGWatcher) EnableWgWatcher(parentCtx context.Context, onDisconnectedFn func()) {
 	w.log.Debugf("enable WireGuard watcher")
@@ -89,7 +97,7 @@ func (w *WGWatcher) DisableWgWatcher() {
 func (w *WGWatcher) periodicHandshakeCheck(ctx context.Context, ctxCancel context.CancelFunc, onDisconnectedFn func(), initialHandshake time.Time) {
 	w.log.Infof("WireGuard watcher started")
 
-	timer := time.NewTimer(wgHandshakeOvertime)
+	timer := time.NewTimer(w.overtime())
 	defer timer.Stop()
 	defer ctxCancel()
 
@@ -110,7 +118,7 @@ func (w *WGWatcher) periodicHandshakeCheck(ctx context.Context, ctxCancel contex
 
 			lastHandshake = *handshake
 
-			resetTime := time.Until(handshake.Add(checkPeriod))
+			resetTime := time.Until(handshake.Add(w.checkPeriod()))
 			timer.Reset(resetTime)
 			w.stateDump.WGcheckSuccess()
 
@@ -139,7 +147,7 @@ func (w *WGWatcher) handshakeCheck(lastHandshake time.Time) (*time.Time, bool) {
 	}
 
 	// in case if the machine is suspended, the handshake time will be in the past
-	if handshake.Add(checkPeriod).Before(time.Now()) {
+	if handshake.Add(w.checkPeriod()).Before(time.Now()) {
 		w.log.Warnf("WireGuard handshake timed out, closing relay connection: %v", handshake)
 		return nil, false
 	}
@@ -153,6 +161,20 @@ func (w *WGWatcher) handshakeCheck(lastHandshake time.Time) (*time.Time, bool) {
 	return &handshake, true
 }
 
+func (w *WGWatcher) overtime() time.Duration {
+	if w.handshakeOvertime > 0 {
+		return w.handshakeOvertime
+	}
+	return wgHandshakeOvertime
+}
+
+func (w *WGWatcher) checkPeriod() time.Duration {
+	if w.handshakeOvertime > 0 {
+		return wgHandshakePeriod + w.handshakeOvertime
+	}
+	return checkPeriod
+}
+
 func (w *WGWatcher) wgState() (time.Time, error) {
 	wgStates, err := w.wgIfaceStater.GetStats()
 	if err != nil {
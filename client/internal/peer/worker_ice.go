@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net"
 	"net/netip"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -40,6 +42,8 @@ type WorkerICE struct {
 	iFaceDiscover     stdnet.ExternalIFaceDiscover
 	statusRecorder    *Status
 	hasRelayOnLocally bool
+	budget            *ResourceBudget
+	resources         *peerResources
 
 	agent             *icemaker.ThreadSafeAgent
 	agentDialerCancel context.CancelFunc
@@ -59,6 +63,16 @@ type WorkerICE struct {
 
 	// we record the last known state of the ICE agent to avoid duplicate on disconnected events
 	lastKnownState ice.ConnectionState
+
+	// uplink is the uplink of a bonded connection the worker connects over, the secondary uplink has its own worker
+	uplink int
+	// bonded makes the agent carry the WireGuard traffic through a proxy instead of the UDP mux of the interface
+	bonded bool
+	// bondExclude is the interface of the secondary uplink, the worker of the primary uplink doesn't use it
+	bondExclude string
+
+	// priming is the last connection that succeeded, it primes the first agent only
+	priming *ConnPriming
 }
 
 func NewWorkerICE(ctx context.Context, log *log.Entry, config ConnConfig, conn *Conn, signaler *Signaler, ifaceDiscover stdnet.ExternalIFaceDiscover, statusRecorder *Status, hasRelayOnLocally bool) (*WorkerICE, error) {
@@ -76,8 +90,11 @@ func NewWorkerICE(ctx context.Context, log *log.Entry, config ConnConfig, conn *
 		iFaceDiscover:     ifaceDiscover,
 		statusRecorder:    statusRecorder,
 		hasRelayOnLocally: hasRelayOnLocally,
+		budget:            conn.budget,
+		resources:         conn.resources,
 		lastKnownState:    ice.ConnectionStateDisconnected,
 		sessionID:         sessionID,
+		priming:           config.Priming,
 	}
 
 	localUfrag, localPwd, err := icemaker.GenerateICECredentials()
@@ -89,8 +106,69 @@ func NewWorkerICE(ctx context.Context, log *log.Entry, config ConnConfig, conn *
 	return w, nil
 }
 
+// newBondWorkerICE creates the worker of the secondary uplink of a bonded connection
+func newBondWorkerICE(ctx context.Context, log *log.Entry, config ConnConfig, conn *Conn, signaler *Signaler, ifaceDiscover stdnet.ExternalIFaceDiscover, statusRecorder *Status, hasRelayOnLocally bool) (*WorkerICE, error) {
+	w, err := NewWorkerICE(ctx, log, config, conn, signaler, ifaceDiscover, statusRecorder, hasRelayOnLocally)
+	if err != nil {
+		return nil, err
+	}
+	w.uplink = uplinkSecondary
+	w.bonded = true
+	w.priming = nil
+	return w, nil
+}
+
+// setBonded makes the next ICE agents carry the WireGuard traffic of a bonded connection, without using the
+// interface of the secondary uplink
+func (w *WorkerICE) setBonded(excludeInterface string) {
+	w.muxAgent.Lock()
+	defer w.muxAgent.Unlock()
+
+	w.bonded = true
+	w.bondExclude = excludeInterface
+}
+
+func (w *WorkerICE) isBonded() bool {
+	w.muxAgent.Lock()
+	defer w.muxAgent.Unlock()
+
+	return w.bonded
+}
+
+// iceConfig returns the config of the next ICE agent. The connections of bonded agents are proxied to WireGuard, so
+// they can't share the UDP mux of the WireGuard interface. The caller must hold muxAgent.
+func (w *WorkerICE) iceConfig() icemaker.Config {
+	config := w.config.ICEConfig
+	if !w.bonded {
+		return config
+	}
+
+	config.UDPMux = nil
+	config.UDPMuxSrflx = nil
+	if w.bondExclude != "" {
+		config.InterfaceBlackList = append(slices.Clone(config.InterfaceBlackList), w.bondExclude)
+	}
+	return config
+}
+
+// candidateTypes returns the candidate types allowed by the ICE policy. If the relay service is available for the
+// peer, TURN isn't needed.
+func (w *WorkerICE) candidateTypes(remoteOfferAnswer *OfferAnswer) []ice.CandidateType {
+	if w.hasRelayOnLocally && remoteOfferAnswer.RelaySrvAddress != "" {
+		return w.config.ICEConfig.Policy.CandidateTypesP2P()
+	}
+	return w.config.ICEConfig.Policy.CandidateTypes()
+}
+
 func (w *WorkerICE) OnNewOffer(remoteOfferAnswer *OfferAnswer) {
 	w.log.Debugf("OnNewOffer for ICE, serial: %s", remoteOfferAnswer.SessionIDString())
+
+	preferredCandidateTypes := w.candidateTypes(remoteOfferAnswer)
+	if len(preferredCandidateTypes) == 0 {
+		w.log.Debugf("ICE policy %s leaves the connection to the relay service, skipping ICE", w.config.ICEConfig.Policy)
+		return
+	}
+
 	w.muxAgent.Lock()
 	defer w.muxAgent.Unlock()
 
@@ -118,32 +196,38 @@ func (w *WorkerICE) OnNewOffer(remoteOfferAnswer *OfferAnswer) {
 		w.agent = nil
 	}
 
-	var preferredCandidateTypes []ice.CandidateType
-	if w.hasRelayOnLocally && remoteOfferAnswer.RelaySrvAddress != "" {
-		preferredCandidateTypes = icemaker.CandidateTypesP2P()
-	} else {
-		preferredCandidateTypes = icemaker.CandidateTypes()
-	}
-
 	if remoteOfferAnswer.SessionID != nil {
 		w.log.Debugf("recreate ICE agent: %s / %s", w.sessionID, *remoteOfferAnswer.SessionID)
 	}
+	if !w.budget.acquireICEAgent(w.resources) {
+		w.log.Warnf("ICE agent limit of the peer connections reached, skipping ICE and staying on relay")
+		return
+	}
+
 	dialerCtx, dialerCancel := context.WithCancel(w.ctx)
 	agent, err := w.reCreateAgent(dialerCancel, preferredCandidateTypes)
 	if err != nil {
+		w.budget.releaseICEAgent(w.resources)
 		w.log.Errorf("failed to recreate ICE Agent: %s", err)
 		return
 	}
+	resources := w.resources
+	agent.SetOnClose(func() {
+		w.budget.releaseICEAgent(resources)
+	})
 	w.agent = agent
 	w.agentDialerCancel = dialerCancel
 	w.agentConnecting = true
+	w.primeAgent(agent)
 	if remoteOfferAnswer.SessionID != nil {
 		w.remoteSessionID = *remoteOfferAnswer.SessionID
 	} else {
 		w.remoteSessionID = ""
 	}
 
-	go w.connect(dialerCtx, agent, remoteOfferAnswer)
+	w.budget.goTracked(w.resources, func() {
+		w.connect(dialerCtx, agent, remoteOfferAnswer)
+	})
 }
 
 // OnRemoteCandidate Handles ICE connection Candidate provided by the remote peer.
@@ -209,7 +293,7 @@ func (w *WorkerICE) Close() {
 }
 
 func (w *WorkerICE) reCreateAgent(dialerCancel context.CancelFunc, candidates []ice.CandidateType) (*icemaker.ThreadSafeAgent, error) {
-	agent, err := icemaker.NewAgent(w.ctx, w.iFaceDiscover, w.config.ICEConfig, candidates, w.localUfrag, w.localPwd)
+	agent, err := icemaker.NewAgent(w.ctx, w.iFaceDiscover, w.iceConfig(), candidates, w.localUfrag, w.localPwd)
 	if err != nil {
 		return nil, fmt.Errorf("create agent: %w", err)
 	}
@@ -238,6 +322,21 @@ func (w *WorkerICE) SessionID() ICESessionID {
 	return w.sessionID
 }
 
+// RenewSession replaces the session ID. The next offer with it makes the remote peer recreate its ICE agent, and the
+// answer with the new remote session ID recreates ours, so both sides gather candidates again.
+func (w *WorkerICE) RenewSession() error {
+	sessionID, err := NewICESessionID()
+	if err != nil {
+		return fmt.Errorf("create session ID: %w", err)
+	}
+
+	w.muxAgent.Lock()
+	defer w.muxAgent.Unlock()
+
+	w.sessionID = sessionID
+	return nil
+}
+
 // will block until connection succeeded
 // but it won't release if ICE Agent went into Disconnected or Failed state,
 // so we have to cancel it with the provided context once agent detected a broken connection
@@ -269,7 +368,9 @@ func (w *WorkerICE) connect(ctx context.Context, agent *icemaker.ThreadSafeAgent
 		return
 	}
 
-	if !isRelayCandidate(pair.Local) {
+	// the punch needs the IP of the remote candidate, mDNS names are only resolved by the agent. Bonded connections
+	// are always proxied, the remote WireGuard port isn't used.
+	if !isRelayCandidate(pair.Local) && !isMulticastDNSCandidate(pair.Remote) && !w.isBonded() {
 		// dynamically set remote WireGuard port if other side specified a different one from the default one
 		remoteWgPort := iface.DefaultWgPort
 		if remoteOfferAnswer.WgListenPort != 0 {
@@ -277,7 +378,9 @@ func (w *WorkerICE) connect(ctx context.Context, agent *icemaker.ThreadSafeAgent
 		}
 
 		// To support old version's with direct mode we attempt to punch an additional role with the remote WireGuard port
-		go w.punchRemoteWGPort(pair, remoteWgPort)
+		w.budget.goTracked(w.resources, func() {
+			w.punchRemoteWGPort(pair, remoteWgPort)
+		})
 	}
 
 	ci := ICEConnInfo{
@@ -300,6 +403,10 @@ func (w *WorkerICE) connect(ctx context.Context, agent *icemaker.ThreadSafeAgent
 	w.muxAgent.Unlock()
 
 	// todo: the potential problem is a race between the onConnectionStateChange
+	if w.uplink == uplinkSecondary {
+		w.conn.onBondPathReady(ci)
+		return
+	}
 	w.conn.onICEConnectionIsReady(selectedPriority(pair), ci)
 }
 
@@ -361,18 +468,27 @@ func (w *WorkerICE) onICECandidate(candidate ice.Candidate) {
 
 	// TODO: reported port is incorrect for CandidateTypeHost, makes understanding ICE use via logs confusing as port is ignored
 	w.log.Debugf("discovered local candidate %s", candidate.String())
-	go func() {
-		err := w.signaler.SignalICECandidate(candidate, w.config.Key)
+	w.budget.goTracked(w.resources, func() {
+		signalCandidate := w.signaler.SignalICECandidate
+		if w.uplink == uplinkSecondary {
+			signalCandidate = w.signaler.SignalBondCandidate
+		}
+		err := signalCandidate(candidate, w.config.Key)
 		if err != nil {
 			w.log.Errorf("failed signaling candidate to the remote peer %s %s", w.config.Key, err)
 		}
-	}()
+	})
 }
 
 func (w *WorkerICE) onICESelectedCandidatePair(agent *icemaker.ThreadSafeAgent, c1, c2 ice.Candidate) {
 	w.log.Debugf("selected candidate pair [local <-> remote] -> [%s <-> %s], peer %s", c1.String(), c2.String(),
 		w.config.Key)
 
+	// the latency of the peer is the one of the primary uplink
+	if w.uplink == uplinkSecondary {
+		return
+	}
+
 	pairStat, ok := agent.GetSelectedCandidatePairStats()
 	if !ok {
 		w.log.Warnf("failed to get selected candidate pair stats")
@@ -401,7 +517,11 @@ func (w *WorkerICE) onConnectionStateChange(agent *icemaker.ThreadSafeAgent, dia
 
 			if w.lastKnownState == ice.ConnectionStateConnected {
 				w.lastKnownState = ice.ConnectionStateDisconnected
-				w.conn.onICEStateDisconnected()
+				if w.uplink == uplinkSecondary {
+					w.conn.onBondPathDisconnected()
+				} else {
+					w.conn.onICEStateDisconnected()
+				}
 			}
 		default:
 			return
@@ -462,6 +582,11 @@ func extraSrflxCandidate(candidate ice.Candidate) (*ice.CandidateServerReflexive
 }
 
 func candidateViaRoutes(candidate ice.Candidate, clientRoutes route.HAMap) bool {
+	// mDNS candidates are resolved by the agent, their address is only known once resolved
+	if isMulticastDNSCandidate(candidate) {
+		return false
+	}
+
 	addr, err := netip.ParseAddr(candidate.Address())
 	if err != nil {
 		log.Errorf("Failed to parse IP address %s: %v", candidate.Address(), err)
@@ -489,6 +614,11 @@ func candidateViaRoutes(candidate ice.Candidate, clientRoutes route.HAMap) bool
 	return false
 }
 
+// isMulticastDNSCandidate reports whether the candidate address is an mDNS name hiding the host IP
+func isMulticastDNSCandidate(candidate ice.Candidate) bool {
+	return candidate.Type() == ice.CandidateTypeHost && strings.HasSuffix(candidate.Address(), ".local")
+}
+
 func isRelayCandidate(candidate ice.Candidate) bool {
 	return candidate.Type() == ice.CandidateTypeRelay
 }
@@ -0,0 +1,38 @@
+package peer
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/pion/ice/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/route"
+)
+
+func TestCandidateViaRoutes_MulticastDNS(t *testing.T) {
+	routes := route.HAMap{
+		"lan": []*route.Route{{Network: netip.MustParsePrefix("192.168.1.0/24")}},
+	}
+
+	routed, err := ice.NewCandidateHost(&ice.CandidateHostConfig{
+		Network:   "udp",
+		Address:   "192.168.1.10",
+		Port:      51820,
+		Component: 1,
+	})
+	require.NoError(t, err)
+	assert.False(t, isMulticastDNSCandidate(routed))
+	assert.True(t, candidateViaRoutes(routed, routes), "candidate in a routed network should be ignored")
+
+	mDNS, err := ice.NewCandidateHost(&ice.CandidateHostConfig{
+		Network:   "udp",
+		Address:   "1b4e28ba-2fa1-11d2-883f-0016d3cca427.local",
+		Port:      51820,
+		Component: 1,
+	})
+	require.NoError(t, err)
+	assert.True(t, isMulticastDNSCandidate(mDNS))
+	assert.False(t, candidateViaRoutes(mDNS, routes), "mDNS candidates are resolved by the agent")
+}
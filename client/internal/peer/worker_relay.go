@@ -6,6 +6,7 @@ import (
 	"net"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -27,7 +28,9 @@ type WorkerRelay struct {
 	relayManager *relayClient.Manager
 
 	relayedConn net.Conn
-	relayLock   sync.Mutex
+	// relayedConnOpenedAt is when the relayed connection was opened, zero if it is closed
+	relayedConnOpenedAt time.Time
+	relayLock           sync.Mutex
 
 	relaySupportedOnRemotePeer atomic.Bool
 
@@ -44,6 +47,7 @@ func NewWorkerRelay(ctx context.Context, log *log.Entry, ctrl bool, config ConnC
 		relayManager: relayManager,
 		wgWatcher:    NewWGWatcher(log, config.WgConfig.WgInterface, config.Key, stateDump),
 	}
+	r.wgWatcher.SetHandshakeOvertime(config.WgConfig.HandshakeTimeout)
 	return r
 }
 
@@ -76,6 +80,7 @@ func (w *WorkerRelay) OnNewOffer(remoteOfferAnswer *OfferAnswer) {
 
 	w.relayLock.Lock()
 	w.relayedConn = relayedConn
+	w.relayedConnOpenedAt = time.Now()
 	w.relayLock.Unlock()
 
 	err = w.relayManager.AddCloseListener(srv, w.onRelayClientDisconnected)
@@ -101,6 +106,13 @@ func (w *WorkerRelay) DisableWgWatcher() {
 	w.wgWatcher.DisableWgWatcher()
 }
 
+// OpenedAt returns when the relayed connection was opened, zero if none is open
+func (w *WorkerRelay) OpenedAt() time.Time {
+	w.relayLock.Lock()
+	defer w.relayLock.Unlock()
+	return w.relayedConnOpenedAt
+}
+
 func (w *WorkerRelay) RelayInstanceAddress() (string, error) {
 	return w.relayManager.RelayInstanceAddress()
 }
@@ -123,11 +135,13 @@ func (w *WorkerRelay) CloseConn() {
 	if err := w.relayedConn.Close(); err != nil {
 		w.log.Warnf("failed to close relay connection: %v", err)
 	}
+	w.relayedConnOpenedAt = time.Time{}
 }
 
 func (w *WorkerRelay) onWGDisconnected() {
 	w.relayLock.Lock()
 	_ = w.relayedConn.Close()
+	w.relayedConnOpenedAt = time.Time{}
 	w.relayLock.Unlock()
 
 	w.conn.onRelayDisconnected()
@@ -148,6 +162,10 @@ func (w *WorkerRelay) preferredRelayServer(myRelayAddress, remoteRelayAddress st
 }
 
 func (w *WorkerRelay) onRelayClientDisconnected() {
+	w.relayLock.Lock()
+	w.relayedConnOpenedAt = time.Time{}
+	w.relayLock.Unlock()
+
 	w.wgWatcher.DisableWgWatcher()
 	go w.conn.onRelayDisconnected()
 }
@@ -0,0 +1,210 @@
+package internal
+
+import (
+	"maps"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+)
+
+const (
+	peerConnCacheInterval = time.Minute
+	// peerConnCacheRetention is the age after which the last connection of a peer doesn't prime a new one anymore
+	peerConnCacheRetention = 7 * 24 * time.Hour
+)
+
+// PeerConnCacheState keeps the last direct ICE candidate pair and relay server of the peers across restarts, so
+// reconnecting many peers after a resume doesn't wait for the full ICE gathering of each of them
+type PeerConnCacheState struct {
+	Peers map[string]peer.ConnPriming `json:"peers"`
+}
+
+func (s *PeerConnCacheState) Name() string {
+	return "peer_conn_cache_state"
+}
+
+// peerConnCache holds the last connection that succeeded of each peer
+type peerConnCache struct {
+	mu    sync.Mutex
+	peers map[string]peer.ConnPriming
+}
+
+func newPeerConnCache(peers map[string]peer.ConnPriming, now time.Time) *peerConnCache {
+	c := &peerConnCache{peers: make(map[string]peer.ConnPriming, len(peers))}
+	for key, priming := range peers {
+		if now.Sub(priming.UpdatedAt) > peerConnCacheRetention {
+			continue
+		}
+		c.peers[key] = priming
+	}
+	return c
+}
+
+// get returns the last connection of the peer, nil if it isn't known
+func (c *peerConnCache) get(pubKey string) *peer.ConnPriming {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	priming, ok := c.peers[pubKey]
+	if !ok {
+		return nil
+	}
+	return &priming
+}
+
+// update records the last connection of the peer, the parts it doesn't know are kept from the previous one. The
+// time is only updated on a change, a peer that stays disconnected doesn't renew its connection.
+func (c *peerConnCache) update(pubKey string, priming peer.ConnPriming, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	last := c.peers[pubKey]
+	if priming.RemoteEndpoint == "" {
+		priming.RemoteCandidateType = last.RemoteCandidateType
+		priming.RemoteEndpoint = last.RemoteEndpoint
+	}
+	if priming.RelayServer == "" {
+		priming.RelayServer = last.RelayServer
+	}
+	priming.UpdatedAt = last.UpdatedAt
+	if priming == last {
+		return
+	}
+	priming.UpdatedAt = now
+	c.peers[pubKey] = priming
+}
+
+// relayServers returns the relay servers the peers were last reached through
+func (c *peerConnCache) relayServers() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var servers []string
+	for _, priming := range c.peers {
+		if priming.RelayServer != "" && !slices.Contains(servers, priming.RelayServer) {
+			servers = append(servers, priming.RelayServer)
+		}
+	}
+	slices.Sort(servers)
+	return servers
+}
+
+func (c *peerConnCache) state() *PeerConnCacheState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &PeerConnCacheState{Peers: maps.Clone(c.peers)}
+}
+
+// loadPeerConnCache restores the persisted last connections of the peers
+func (e *Engine) loadPeerConnCache() {
+	state := &PeerConnCacheState{}
+	e.stateManager.RegisterState(state)
+	if err := e.stateManager.LoadState(state); err != nil {
+		log.Warnf("failed to load peer connection cache: %v", err)
+	}
+	if existing, ok := e.stateManager.GetState(state).(*PeerConnCacheState); ok && existing != nil {
+		state = existing
+	}
+
+	e.peerConnCache = newPeerConnCache(state.Peers, time.Now())
+}
+
+// startPeerConnCache connects to the relay servers the peers were last reached through and periodically records
+// the last connections of the peers, so they survive a crash of the client
+func (e *Engine) startPeerConnCache() {
+	e.shutdownWg.Add(1)
+	go func() {
+		defer e.shutdownWg.Done()
+
+		e.preconnectRelayServers()
+
+		ticker := time.NewTicker(peerConnCacheInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-e.ctx.Done():
+				return
+			case <-ticker.C:
+				e.syncMsgMux.Lock()
+				e.collectPeerConnCache()
+				e.syncMsgMux.Unlock()
+			}
+		}
+	}()
+}
+
+// preconnectRelayServers connects to the foreign relay servers of the cached peers, the relayed connections of the
+// peers are opened without waiting for the relay handshakes one by one
+func (e *Engine) preconnectRelayServers() {
+	if e.peerConnCache == nil || e.relayManager == nil {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, server := range e.peerConnCache.relayServers() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := e.relayManager.Preconnect(server); err != nil {
+				log.Debugf("failed to connect to the cached relay server %s: %v", server, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// collectPeerConnCache records the last connections of the peers. Callers must hold syncMsgMux.
+func (e *Engine) collectPeerConnCache() {
+	if e.peerConnCache == nil {
+		return
+	}
+
+	now := time.Now()
+	for _, key := range e.peerStore.PeersPubKey() {
+		conn, ok := e.peerStore.PeerConn(key)
+		if !ok {
+			continue
+		}
+		if priming, ok := conn.Priming(); ok {
+			e.peerConnCache.update(key, priming, now)
+		}
+	}
+
+	if err := e.stateManager.UpdateState(e.peerConnCache.state()); err != nil {
+		log.Errorf("failed to update peer connection cache state: %v", err)
+	}
+}
+
+// peerConnPriming returns the last connection of the peer, nil if it isn't known. A remote endpoint that is routed
+// through the tunnel now isn't used, the connectivity checks would loop through the tunnel.
+func (e *Engine) peerConnPriming(pubKey string) *peer.ConnPriming {
+	if e.peerConnCache == nil {
+		return nil
+	}
+	priming := e.peerConnCache.get(pubKey)
+	if priming == nil || priming.RemoteEndpoint == "" || e.routeManager == nil {
+		return priming
+	}
+
+	host := priming.RemoteEndpoint
+	if i := strings.LastIndex(host, ":"); i >= 0 {
+		host = host[:i]
+	}
+	addr, err := netip.ParseAddr(strings.Trim(host, "[]"))
+	if err != nil {
+		return priming
+	}
+	if routed, prefix, _ := e.addrViaRoutes(addr); routed {
+		log.Debugf("not priming the connection to peer %s, its last endpoint is routed through %s", pubKey, prefix)
+		priming.RemoteCandidateType = ""
+		priming.RemoteEndpoint = ""
+	}
+	return priming
+}
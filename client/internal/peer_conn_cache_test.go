@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+)
+
+func TestPeerConnCache(t *testing.T) {
+	now := time.Now()
+	cache := newPeerConnCache(map[string]peer.ConnPriming{
+		"fresh":   {RemoteCandidateType: "host", RemoteEndpoint: "192.168.1.2:51820", UpdatedAt: now.Add(-time.Hour)},
+		"expired": {RemoteCandidateType: "host", RemoteEndpoint: "192.168.1.3:51820", UpdatedAt: now.Add(-peerConnCacheRetention - time.Hour)},
+	}, now)
+
+	assert.NotNil(t, cache.get("fresh"))
+	assert.Nil(t, cache.get("expired"), "expired connections aren't used")
+
+	// a relayed connection keeps the last direct pair
+	cache.update("fresh", peer.ConnPriming{RelayServer: "rels://relay-b.netbird.io:443"}, now)
+	primed := cache.get("fresh")
+	require.NotNil(t, primed)
+	assert.Equal(t, "192.168.1.2:51820", primed.RemoteEndpoint)
+	assert.Equal(t, "rels://relay-b.netbird.io:443", primed.RelayServer)
+	assert.Equal(t, now, primed.UpdatedAt)
+
+	// an unchanged connection keeps its time, the retention counts from the last change
+	cache.update("fresh", peer.ConnPriming{RemoteCandidateType: "host", RemoteEndpoint: "192.168.1.2:51820"}, now.Add(time.Minute))
+	assert.Equal(t, now, cache.get("fresh").UpdatedAt)
+
+	cache.update("new", peer.ConnPriming{RelayServer: "rels://relay-a.netbird.io:443"}, now)
+	cache.update("other", peer.ConnPriming{RelayServer: "rels://relay-a.netbird.io:443"}, now)
+	assert.Equal(t, []string{"rels://relay-a.netbird.io:443", "rels://relay-b.netbird.io:443"}, cache.relayServers())
+
+	state := cache.state()
+	assert.Len(t, state.Peers, 3)
+}
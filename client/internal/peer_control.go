@@ -0,0 +1,84 @@
+package internal
+
+import (
+	"fmt"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+)
+
+// PeerCandidates are the ICE candidate pairs of the connection to a peer
+type PeerCandidates struct {
+	FQDN string
+	// Current is the pair the connection uses, nil if ICE isn't active
+	Current     *peer.CandidatePair
+	History     []peer.CandidatePair
+	RelayForced bool
+}
+
+// ReconnectPeer closes the connection to a peer and opens it again. The peer is a peer FQDN, NetBird IP or public
+// key. It returns the FQDN of the peer.
+func (e *Engine) ReconnectPeer(target string) (string, error) {
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+
+	conn, state, err := e.findPeerConn(target)
+	if err != nil {
+		return "", err
+	}
+
+	if err := conn.Reconnect(e.ctx); err != nil {
+		return "", fmt.Errorf("reconnect %s: %w", state.FQDN, err)
+	}
+	return state.FQDN, nil
+}
+
+// ForceRelayPeer keeps the connection to a peer on the relay, or releases it to ICE again. It returns the FQDN of
+// the peer.
+func (e *Engine) ForceRelayPeer(target string, enabled bool) (string, error) {
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+
+	conn, state, err := e.findPeerConn(target)
+	if err != nil {
+		return "", err
+	}
+
+	if err := conn.ForceRelay(enabled); err != nil {
+		return "", fmt.Errorf("force relay for %s: %w", state.FQDN, err)
+	}
+	return state.FQDN, nil
+}
+
+// GetPeerCandidates returns the selected ICE candidate pair of the connection to a peer and the previous ones
+func (e *Engine) GetPeerCandidates(target string) (*PeerCandidates, error) {
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+
+	conn, state, err := e.findPeerConn(target)
+	if err != nil {
+		return nil, err
+	}
+
+	current, history := conn.CandidatePairs()
+	return &PeerCandidates{
+		FQDN:        state.FQDN,
+		Current:     current,
+		History:     history,
+		RelayForced: conn.RelayForced(),
+	}, nil
+}
+
+// findPeerConn finds the connection to a peer by FQDN, short name, NetBird IP or public key. The caller must hold
+// syncMsgMux.
+func (e *Engine) findPeerConn(target string) (*peer.Conn, peer.State, error) {
+	state, ok := findPeerState(e.statusRecorder.GetFullStatus().Peers, target)
+	if !ok {
+		return nil, peer.State{}, fmt.Errorf("peer %s not found", target)
+	}
+
+	conn, ok := e.peerStore.PeerConn(state.PubKey)
+	if !ok {
+		return nil, peer.State{}, fmt.Errorf("no connection to peer %s", state.FQDN)
+	}
+	return conn, state, nil
+}
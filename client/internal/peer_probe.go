@@ -0,0 +1,197 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/iface/netstack"
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/pmtu"
+)
+
+const (
+	// DefaultPeerProbeCount is the number of echo probes sent to each peer if none is requested
+	DefaultPeerProbeCount = 3
+	// MaxPeerProbeCount is the highest number of echo probes sent to each peer
+	MaxPeerProbeCount = 20
+
+	// peerProbeSize is the IP packet size of the echo probes, small enough for every path
+	peerProbeSize = 64
+	// peerProbeConcurrency is the number of peers probed at the same time
+	peerProbeConcurrency = 16
+	// peerProbeInterval is the pause between the probes sent to a peer
+	peerProbeInterval = 100 * time.Millisecond
+)
+
+var errPeerNotConnected = errors.New("peer is not connected")
+
+// newPeerProbePinger returns the pinger of the echo probes sent from the local NetBird address
+var newPeerProbePinger = pmtu.NewICMPPinger
+
+// PeerProbeResult is the round trip time and loss of the echo probes sent to a peer through the tunnel
+type PeerProbeResult struct {
+	PubKey   string
+	FQDN     string
+	IP       string
+	Sent     int
+	Received int
+	// RTTMin, RTTAvg and RTTMax are the round trip times of the answered probes
+	RTTMin time.Duration
+	RTTAvg time.Duration
+	RTTMax time.Duration
+	// Error is why the peer wasn't probed, like a peer that isn't connected
+	Error string
+}
+
+// Loss returns the share of the probes that weren't answered
+func (r PeerProbeResult) Loss() float64 {
+	if r.Sent == 0 {
+		return 0
+	}
+	return float64(r.Sent-r.Received) / float64(r.Sent)
+}
+
+// ProbePeers sends count echo requests through the tunnel to each of the targets, or to all connected peers if
+// there are none, and returns the round trip times and loss sorted by FQDN. The targets are FQDNs, short names,
+// NetBird IPs or public keys. Unlike the ICE latency, the probes measure the whole path through WireGuard, peers
+// whose ACLs drop ICMP show a full loss.
+func (e *Engine) ProbePeers(ctx context.Context, targets []string, count int) ([]PeerProbeResult, error) {
+	if count <= 0 {
+		count = DefaultPeerProbeCount
+	}
+	count = min(count, MaxPeerProbeCount)
+
+	if netstack.IsEnabled() {
+		return nil, errors.New("probing peers isn't supported in netstack mode")
+	}
+
+	e.syncMsgMux.Lock()
+	wgIface := e.wgInterface
+	e.syncMsgMux.Unlock()
+	if wgIface == nil {
+		return nil, ErrEngineStopped
+	}
+
+	states, err := selectProbePeers(e.statusRecorder.GetFullStatus().Peers, targets)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]PeerProbeResult, len(states))
+	sem := make(chan struct{}, peerProbeConcurrency)
+	var wg sync.WaitGroup
+	for i, state := range states {
+		results[i] = PeerProbeResult{PubKey: state.PubKey, FQDN: state.FQDN, IP: state.IP}
+		if state.ConnStatus != peer.StatusConnected {
+			results[i].Error = errPeerNotConnected.Error()
+			continue
+		}
+		addr, err := netip.ParseAddr(state.IP)
+		if err != nil || !addr.Is4() {
+			results[i].Error = fmt.Sprintf("unsupported peer address %q", state.IP)
+			continue
+		}
+
+		wg.Add(1)
+		go func(result *PeerProbeResult) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				result.Error = ctx.Err().Error()
+				return
+			}
+
+			pinger, err := newPeerProbePinger(wgIface.Address().IP)
+			if err != nil {
+				result.Error = fmt.Sprintf("create pinger: %v", err)
+				return
+			}
+			defer func() {
+				if err := pinger.Close(); err != nil {
+					log.Debugf("failed to close the peer probe pinger: %v", err)
+				}
+			}()
+			probePeer(ctx, pinger, addr, count, result)
+		}(&results[i])
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].FQDN < results[j].FQDN
+	})
+	return results, nil
+}
+
+// selectProbePeers returns the states of the targets, or the ones of the connected peers if there are no targets
+func selectProbePeers(peers []peer.State, targets []string) ([]peer.State, error) {
+	if len(targets) == 0 {
+		var connected []peer.State
+		for _, state := range peers {
+			if state.ConnStatus == peer.StatusConnected {
+				connected = append(connected, state)
+			}
+		}
+		return connected, nil
+	}
+
+	selected := make([]peer.State, 0, len(targets))
+	seen := make(map[string]struct{}, len(targets))
+	for _, target := range targets {
+		state, ok := findPeerState(peers, target)
+		if !ok {
+			return nil, fmt.Errorf("peer %s not found", target)
+		}
+		if _, ok := seen[state.PubKey]; ok {
+			continue
+		}
+		seen[state.PubKey] = struct{}{}
+		selected = append(selected, state)
+	}
+	return selected, nil
+}
+
+// probePeer sends count echo requests to the address and records their round trip times in the result
+func probePeer(ctx context.Context, pinger pmtu.Pinger, addr netip.Addr, count int, result *PeerProbeResult) {
+	var total time.Duration
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(peerProbeInterval):
+			}
+		}
+
+		start := time.Now()
+		err := pinger.Ping(ctx, addr, peerProbeSize)
+		rtt := time.Since(start)
+		if ctx.Err() != nil {
+			return
+		}
+		result.Sent++
+		if err != nil {
+			log.Tracef("echo probe to peer %s failed: %v", result.FQDN, err)
+			continue
+		}
+
+		result.Received++
+		total += rtt
+		if result.RTTMin == 0 || rtt < result.RTTMin {
+			result.RTTMin = rtt
+		}
+		result.RTTMax = max(result.RTTMax, rtt)
+	}
+
+	if result.Received > 0 {
+		result.RTTAvg = total / time.Duration(result.Received)
+	}
+}
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+)
+
+// scriptedPinger answers the echo requests by the script, true is an answered one
+type scriptedPinger struct {
+	answers []bool
+	delay   time.Duration
+}
+
+func (p *scriptedPinger) Ping(_ context.Context, _ netip.Addr, _ int) error {
+	answered := p.answers[0]
+	p.answers = p.answers[1:]
+	if !answered {
+		return errors.New("timeout")
+	}
+	time.Sleep(p.delay)
+	return nil
+}
+
+func (p *scriptedPinger) Close() error {
+	return nil
+}
+
+func TestProbePeer(t *testing.T) {
+	pinger := &scriptedPinger{answers: []bool{true, false, true, true}, delay: time.Millisecond}
+
+	var result PeerProbeResult
+	probePeer(context.Background(), pinger, netip.MustParseAddr("100.64.0.2"), 4, &result)
+
+	assert.Equal(t, 4, result.Sent)
+	assert.Equal(t, 3, result.Received)
+	assert.InDelta(t, 0.25, result.Loss(), 0.001)
+	assert.GreaterOrEqual(t, result.RTTMin, time.Millisecond)
+	assert.LessOrEqual(t, result.RTTMin, result.RTTAvg)
+	assert.LessOrEqual(t, result.RTTAvg, result.RTTMax)
+}
+
+func TestProbePeer_NoReply(t *testing.T) {
+	pinger := &scriptedPinger{answers: []bool{false, false}}
+
+	var result PeerProbeResult
+	probePeer(context.Background(), pinger, netip.MustParseAddr("100.64.0.2"), 2, &result)
+
+	assert.Equal(t, 2, result.Sent)
+	assert.Zero(t, result.Received)
+	assert.Equal(t, 1.0, result.Loss())
+	assert.Zero(t, result.RTTAvg)
+}
+
+func TestSelectProbePeers(t *testing.T) {
+	peers := []peer.State{
+		{PubKey: "key-a", FQDN: "a.netbird.cloud", IP: "100.64.0.2", ConnStatus: peer.StatusConnected},
+		{PubKey: "key-b", FQDN: "b.netbird.cloud", IP: "100.64.0.3", ConnStatus: peer.StatusIdle},
+	}
+
+	selected, err := selectProbePeers(peers, nil)
+	require.NoError(t, err)
+	require.Len(t, selected, 1, "only the connected peers are probed by default")
+	assert.Equal(t, "key-a", selected[0].PubKey)
+
+	selected, err = selectProbePeers(peers, []string{"b", "100.64.0.3", "a.netbird.cloud"})
+	require.NoError(t, err)
+	require.Len(t, selected, 2)
+	assert.Equal(t, "key-b", selected[0].PubKey)
+	assert.Equal(t, "key-a", selected[1].PubKey)
+
+	_, err = selectProbePeers(peers, []string{"c"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,163 @@
+package internal
+
+import (
+	"maps"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/iface/configurer"
+	"github.com/netbirdio/netbird/client/internal/peer"
+)
+
+const (
+	peerStatsInterval = time.Minute
+	// peerStatsRetention is the time after which the stats of a peer that didn't connect anymore are dropped
+	peerStatsRetention = 90 * 24 * time.Hour
+)
+
+// PeerStatsState keeps the cumulative transfer counters and the last connection time of the peers across restarts
+type PeerStatsState struct {
+	Peers map[string]peer.LifetimeStats `json:"peers"`
+}
+
+func (s *PeerStatsState) Name() string {
+	return "peer_stats_state"
+}
+
+// peerStats accumulates the WireGuard transfer counters on top of the persisted totals.
+// The WireGuard counters start at zero whenever the interface or the peer is recreated,
+// so only the growth since the last sample is added.
+type peerStats struct {
+	mu     sync.Mutex
+	totals map[string]peer.LifetimeStats
+	// last holds the WireGuard counters of the previous sample
+	last map[string]configurer.WGStats
+}
+
+func newPeerStats(totals map[string]peer.LifetimeStats, now time.Time) *peerStats {
+	s := &peerStats{
+		totals: make(map[string]peer.LifetimeStats, len(totals)),
+		last:   make(map[string]configurer.WGStats),
+	}
+	for key, stats := range totals {
+		if now.Sub(stats.LastConnected) > peerStatsRetention {
+			continue
+		}
+		s.totals[key] = stats
+	}
+	return s
+}
+
+// add accounts the current WireGuard counters of the peer and returns its lifetime stats
+func (s *peerStats) add(pubKey string, wgStats configurer.WGStats, connected bool, now time.Time) peer.LifetimeStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	total := s.totals[pubKey]
+	last := s.last[pubKey]
+
+	total.BytesTx += counterDelta(last.TxBytes, wgStats.TxBytes)
+	total.BytesRx += counterDelta(last.RxBytes, wgStats.RxBytes)
+	if connected {
+		total.LastConnected = now
+	}
+
+	s.totals[pubKey] = total
+	s.last[pubKey] = wgStats
+	return total
+}
+
+func (s *peerStats) state() *PeerStatsState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &PeerStatsState{Peers: maps.Clone(s.totals)}
+}
+
+// counterDelta returns the growth of a counter, a smaller value means that the counter was reset
+func counterDelta(last, current int64) int64 {
+	if current < last {
+		return current
+	}
+	return current - last
+}
+
+// loadPeerStats restores the persisted peer stats
+func (e *Engine) loadPeerStats() {
+	state := &PeerStatsState{}
+	e.stateManager.RegisterState(state)
+	if err := e.stateManager.LoadState(state); err != nil {
+		log.Warnf("failed to load peer stats: %v", err)
+	}
+	if existing, ok := e.stateManager.GetState(state).(*PeerStatsState); ok && existing != nil {
+		state = existing
+	}
+
+	e.peerStats = newPeerStats(state.Peers, time.Now())
+}
+
+// startPeerStats periodically accumulates the peer stats, so they survive a crash of the client
+func (e *Engine) startPeerStats() {
+	e.shutdownWg.Add(1)
+	go func() {
+		defer e.shutdownWg.Done()
+
+		ticker := time.NewTicker(peerStatsInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-e.ctx.Done():
+				return
+			case <-ticker.C:
+				e.syncMsgMux.Lock()
+				e.collectPeerStats()
+				e.syncMsgMux.Unlock()
+			}
+		}
+	}()
+}
+
+// collectPeerStats reads the WireGuard counters and accumulates them. Callers must hold syncMsgMux.
+func (e *Engine) collectPeerStats() {
+	if e.wgInterface == nil || e.peerStats == nil {
+		return
+	}
+
+	stats, err := e.wgInterface.GetStats()
+	if err != nil {
+		log.Debugf("failed to get wireguard stats: %v", err)
+		return
+	}
+	e.updatePeerStats(stats)
+}
+
+// updatePeerStats accumulates the WireGuard counters, updates the status recorder and the persisted state
+func (e *Engine) updatePeerStats(stats map[string]configurer.WGStats) {
+	if e.peerStats == nil {
+		return
+	}
+
+	now := time.Now()
+	for _, key := range e.peerStore.PeersPubKey() {
+		wgStats, ok := stats[key]
+		if !ok {
+			continue
+		}
+
+		state, err := e.statusRecorder.GetPeer(key)
+		if err != nil {
+			continue
+		}
+
+		lifetime := e.peerStats.add(key, wgStats, state.ConnStatus == peer.StatusConnected, now)
+		if err := e.statusRecorder.UpdatePeerLifetimeStats(key, lifetime); err != nil {
+			log.Debugf("failed to update lifetime stats for peer %s: %s", key, err)
+		}
+	}
+
+	if err := e.stateManager.UpdateState(e.peerStats.state()); err != nil {
+		log.Errorf("failed to update peer stats state: %v", err)
+	}
+}
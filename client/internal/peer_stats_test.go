@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/client/iface/configurer"
+	"github.com/netbirdio/netbird/client/internal/peer"
+)
+
+func TestPeerStats_Accumulate(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	lastConnected := now.Add(-time.Hour)
+
+	s := newPeerStats(map[string]peer.LifetimeStats{
+		"peer-a": {BytesTx: 1000, BytesRx: 2000, LastConnected: lastConnected},
+	}, now)
+
+	stats := s.add("peer-a", configurer.WGStats{TxBytes: 100, RxBytes: 200}, false, now)
+	assert.Equal(t, peer.LifetimeStats{BytesTx: 1100, BytesRx: 2200, LastConnected: lastConnected}, stats)
+
+	stats = s.add("peer-a", configurer.WGStats{TxBytes: 150, RxBytes: 300}, true, now)
+	assert.Equal(t, peer.LifetimeStats{BytesTx: 1150, BytesRx: 2300, LastConnected: now}, stats)
+
+	// the WireGuard counters were reset, e.g. because the peer was recreated
+	stats = s.add("peer-a", configurer.WGStats{TxBytes: 10, RxBytes: 20}, true, now)
+	assert.Equal(t, peer.LifetimeStats{BytesTx: 1160, BytesRx: 2320, LastConnected: now}, stats)
+
+	stats = s.add("peer-b", configurer.WGStats{TxBytes: 5, RxBytes: 7}, false, now)
+	assert.Equal(t, peer.LifetimeStats{BytesTx: 5, BytesRx: 7}, stats)
+
+	assert.Len(t, s.state().Peers, 2)
+}
+
+func TestPeerStats_DropsStalePeers(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := newPeerStats(map[string]peer.LifetimeStats{
+		"recent": {BytesTx: 1, LastConnected: now.Add(-time.Hour)},
+		"stale":  {BytesTx: 1, LastConnected: now.Add(-peerStatsRetention - time.Hour)},
+	}, now)
+
+	peers := s.state().Peers
+	assert.Contains(t, peers, "recent")
+	assert.NotContains(t, peers, "stale")
+}
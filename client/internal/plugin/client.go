@@ -0,0 +1,76 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/netbirdio/netbird/client/internal/plugin/proto"
+	"github.com/netbirdio/netbird/version"
+)
+
+// client is a connection to a single plugin
+type client struct {
+	config       Config
+	name         string
+	capabilities []proto.Capability
+
+	conn *grpc.ClientConn
+	rpc  proto.PluginClient
+}
+
+func dial(ctx context.Context, config Config) (*client, error) {
+	conn, err := grpc.NewClient("unix://"+config.Socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("dial plugin socket %s: %w", config.Socket, err)
+	}
+
+	c := &client{
+		config: config,
+		name:   config.Name,
+		conn:   conn,
+		rpc:    proto.NewPluginClient(conn),
+	}
+
+	if err := c.handshake(ctx); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *client) handshake(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	resp, err := c.rpc.Handshake(ctx, &proto.HandshakeRequest{
+		ApiVersion:    APIVersion,
+		DaemonVersion: version.NetbirdVersion(),
+	})
+	if err != nil {
+		return fmt.Errorf("handshake: %w", err)
+	}
+
+	if resp.GetApiVersion() != APIVersion {
+		return fmt.Errorf("unsupported plugin API version %d, expected %d", resp.GetApiVersion(), APIVersion)
+	}
+
+	if resp.GetName() != "" {
+		c.name = resp.GetName()
+	}
+	c.capabilities = resp.GetCapabilities()
+
+	return nil
+}
+
+func (c *client) has(capability proto.Capability) bool {
+	return slices.Contains(c.capabilities, capability)
+}
+
+func (c *client) close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,29 @@
+package plugin
+
+import "net/netip"
+
+// Config describes an external plugin the daemon connects to and the limits the plugin has to respect
+type Config struct {
+	// Name is used for logging only, the plugin reports its own name during the handshake
+	Name string
+	// Socket is the path of the unix socket the plugin listens on
+	Socket string
+
+	// AllowVeto permits the plugin to drop peers and routes from the network map
+	AllowVeto bool
+	// AllowRouteInjection permits the plugin to add routes to the network map
+	AllowRouteInjection bool
+	// AllowDNSInjection permits the plugin to add DNS records to existing custom zones
+	AllowDNSInjection bool
+	// AllowedPrefixes restricts the networks of injected routes. Routes outside these prefixes are ignored.
+	AllowedPrefixes []netip.Prefix
+}
+
+func (c Config) allowsPrefix(prefix netip.Prefix) bool {
+	for _, allowed := range c.AllowedPrefixes {
+		if allowed.Bits() <= prefix.Bits() && allowed.Contains(prefix.Addr()) {
+			return true
+		}
+	}
+	return false
+}
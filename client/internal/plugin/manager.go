@@ -0,0 +1,325 @@
+package plugin
+
+import (
+	"context"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	gproto "google.golang.org/protobuf/proto"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/plugin/proto"
+	"github.com/netbirdio/netbird/route"
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+// APIVersion is the plugin API version implemented by the daemon. It is bumped on breaking changes.
+const APIVersion = 1
+
+// callTimeout limits every call to a plugin so a misbehaving plugin can not stall the engine
+const callTimeout = 2 * time.Second
+
+// Manager connects to the configured plugins, forwards engine events to them and lets them
+// adjust the network map within the limits of their configuration.
+type Manager struct {
+	configs        []Config
+	statusRecorder *peer.Status
+
+	mu      sync.Mutex
+	clients []*client
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func NewManager(configs []Config, statusRecorder *peer.Status) *Manager {
+	return &Manager{
+		configs:        configs,
+		statusRecorder: statusRecorder,
+	}
+}
+
+// Start connects to all configured plugins. Plugins that can not be reached are skipped.
+func (m *Manager) Start(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ctx, m.cancel = context.WithCancel(ctx)
+
+	for _, cfg := range m.configs {
+		c, err := dial(ctx, cfg)
+		if err != nil {
+			log.Warnf("failed to connect to plugin %s: %v", cfg.Socket, err)
+			continue
+		}
+		log.Infof("connected to plugin %s with capabilities %v", c.name, c.capabilities)
+		m.clients = append(m.clients, c)
+	}
+
+	if m.statusRecorder == nil || !slices.ContainsFunc(m.clients, func(c *client) bool { return c.has(proto.Capability_EVENTS) }) {
+		return
+	}
+
+	sub := m.statusRecorder.SubscribeToEvents(peer.EventFilter{})
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer m.statusRecorder.UnsubscribeFromEvents(sub)
+		m.forwardEvents(ctx, sub)
+	}()
+}
+
+// Close disconnects from all plugins
+func (m *Manager) Close() {
+	m.mu.Lock()
+	if m.cancel != nil {
+		m.cancel()
+	}
+	clients := m.clients
+	m.clients = nil
+	m.mu.Unlock()
+
+	m.wg.Wait()
+
+	for _, c := range clients {
+		if err := c.close(); err != nil {
+			log.Debugf("failed to close plugin %s connection: %v", c.name, err)
+		}
+	}
+}
+
+func (m *Manager) forwardEvents(ctx context.Context, sub *peer.EventSubscription) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			pluginEvent := &proto.Event{
+				Id:        event.GetId(),
+				Severity:  event.GetSeverity().String(),
+				Category:  event.GetCategory().String(),
+				Message:   event.GetMessage(),
+				Metadata:  event.GetMetadata(),
+				Timestamp: event.GetTimestamp(),
+			}
+			for _, c := range m.withCapability(proto.Capability_EVENTS) {
+				callCtx, cancel := context.WithTimeout(ctx, callTimeout)
+				if _, err := c.rpc.OnEvent(callCtx, pluginEvent); err != nil {
+					log.Debugf("failed to deliver event to plugin %s: %v", c.name, err)
+				}
+				cancel()
+			}
+		}
+	}
+}
+
+func (m *Manager) withCapability(capability proto.Capability) []*client {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var clients []*client
+	for _, c := range m.clients {
+		if c.has(capability) {
+			clients = append(clients, c)
+		}
+	}
+	return clients
+}
+
+// ApplyNetworkMap returns the network map with the plugin vetoes and injections applied.
+// The input is never modified. If no plugin is interested the input is returned as is.
+func (m *Manager) ApplyNetworkMap(ctx context.Context, networkMap *mgmProto.NetworkMap) *mgmProto.NetworkMap {
+	if m == nil || networkMap == nil {
+		return networkMap
+	}
+
+	filters := m.withCapability(proto.Capability_FILTER)
+	injectors := slices.Concat(m.withCapability(proto.Capability_INJECT_ROUTES), m.withCapability(proto.Capability_INJECT_DNS))
+	if len(filters) == 0 && len(injectors) == 0 {
+		return networkMap
+	}
+
+	nm, ok := gproto.Clone(networkMap).(*mgmProto.NetworkMap)
+	if !ok {
+		log.Errorf("failed to clone network map for plugins")
+		return networkMap
+	}
+
+	for _, c := range filters {
+		m.applyFilter(ctx, c, nm)
+	}
+
+	injected := make(map[*client]struct{}, len(injectors))
+	for _, c := range injectors {
+		if _, done := injected[c]; done {
+			continue
+		}
+		injected[c] = struct{}{}
+		m.applyInjections(ctx, c, nm)
+	}
+
+	return nm
+}
+
+func (m *Manager) applyFilter(ctx context.Context, c *client, nm *mgmProto.NetworkMap) {
+	if !c.config.AllowVeto {
+		log.Debugf("plugin %s is not allowed to veto network map elements", c.name)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	resp, err := c.rpc.FilterNetworkMap(ctx, toPluginNetworkMap(nm))
+	if err != nil {
+		log.Warnf("plugin %s failed to filter the network map: %v", c.name, err)
+		return
+	}
+
+	if vetoed := resp.GetVetoedPeers(); len(vetoed) > 0 {
+		log.Infof("plugin %s vetoed peers: %v", c.name, vetoed)
+		nm.RemotePeers = slices.DeleteFunc(nm.RemotePeers, func(p *mgmProto.RemotePeerConfig) bool {
+			return slices.Contains(vetoed, p.GetWgPubKey())
+		})
+	}
+
+	if vetoed := resp.GetVetoedRoutes(); len(vetoed) > 0 {
+		log.Infof("plugin %s vetoed routes: %v", c.name, vetoed)
+		nm.Routes = slices.DeleteFunc(nm.Routes, func(r *mgmProto.Route) bool {
+			return slices.Contains(vetoed, r.GetID())
+		})
+	}
+}
+
+func (m *Manager) applyInjections(ctx context.Context, c *client, nm *mgmProto.NetworkMap) {
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	resp, err := c.rpc.GetInjections(ctx, toPluginNetworkMap(nm))
+	if err != nil {
+		log.Warnf("plugin %s failed to return injections: %v", c.name, err)
+		return
+	}
+
+	if c.has(proto.Capability_INJECT_ROUTES) {
+		for _, r := range resp.GetRoutes() {
+			injectedRoute, ok := toInjectedRoute(c, nm, r)
+			if !ok {
+				continue
+			}
+			nm.Routes = append(nm.Routes, injectedRoute)
+		}
+	}
+
+	if c.has(proto.Capability_INJECT_DNS) {
+		for _, record := range resp.GetDnsRecords() {
+			injectDNSRecord(c, nm, record)
+		}
+	}
+}
+
+// toInjectedRoute validates a route returned by a plugin against the plugin policy and the network map
+func toInjectedRoute(c *client, nm *mgmProto.NetworkMap, r *proto.Route) (*mgmProto.Route, bool) {
+	if !c.config.AllowRouteInjection {
+		log.Debugf("plugin %s is not allowed to inject routes", c.name)
+		return nil, false
+	}
+
+	prefix, err := netip.ParsePrefix(r.GetNetwork())
+	if err != nil {
+		log.Warnf("plugin %s injected route with invalid network %q: %v", c.name, r.GetNetwork(), err)
+		return nil, false
+	}
+
+	if !c.config.allowsPrefix(prefix) {
+		log.Warnf("plugin %s injected route %s outside of the allowed prefixes", c.name, prefix)
+		return nil, false
+	}
+
+	// routes can only point to peers the management server has allowed us to connect to
+	if !slices.ContainsFunc(nm.GetRemotePeers(), func(p *mgmProto.RemotePeerConfig) bool {
+		return p.GetWgPubKey() == r.GetPeer()
+	}) {
+		log.Warnf("plugin %s injected route %s via unknown peer %s", c.name, prefix, r.GetPeer())
+		return nil, false
+	}
+
+	networkType := route.IPv4Network
+	if prefix.Addr().Is6() {
+		networkType = route.IPv6Network
+	}
+
+	netID := r.GetNetId()
+	if netID == "" {
+		netID = "plugin-" + prefix.String()
+	}
+
+	return &mgmProto.Route{
+		ID:          "plugin-" + c.name + "-" + prefix.String() + "-" + r.GetPeer(),
+		Network:     prefix.Masked().String(),
+		NetworkType: int64(networkType),
+		Peer:        r.GetPeer(),
+		Metric:      int64(r.GetMetric()),
+		NetID:       netID,
+	}, true
+}
+
+// injectDNSRecord adds the record to the custom zone that contains it. Records outside existing zones are dropped.
+func injectDNSRecord(c *client, nm *mgmProto.NetworkMap, record *proto.DNSRecord) {
+	if !c.config.AllowDNSInjection {
+		log.Debugf("plugin %s is not allowed to inject DNS records", c.name)
+		return
+	}
+
+	name := strings.ToLower(strings.TrimSuffix(record.GetName(), "."))
+	for _, zone := range nm.GetDNSConfig().GetCustomZones() {
+		zoneDomain := strings.ToLower(strings.TrimSuffix(zone.GetDomain(), "."))
+		if name != zoneDomain && !strings.HasSuffix(name, "."+zoneDomain) {
+			continue
+		}
+
+		zone.Records = append(zone.Records, &mgmProto.SimpleRecord{
+			Name:  name + ".",
+			Type:  int64(record.GetType()),
+			Class: "IN",
+			TTL:   int64(record.GetTtl()),
+			RData: record.GetRdata(),
+		})
+		return
+	}
+
+	log.Warnf("plugin %s injected DNS record %s outside of the known zones", c.name, name)
+}
+
+func toPluginNetworkMap(nm *mgmProto.NetworkMap) *proto.NetworkMap {
+	pluginMap := &proto.NetworkMap{
+		Serial: nm.GetSerial(),
+	}
+
+	for _, p := range nm.GetRemotePeers() {
+		pluginMap.Peers = append(pluginMap.Peers, &proto.Peer{
+			PubKey:     p.GetWgPubKey(),
+			Fqdn:       p.GetFqdn(),
+			AllowedIps: p.GetAllowedIps(),
+		})
+	}
+
+	for _, r := range nm.GetRoutes() {
+		pluginMap.Routes = append(pluginMap.Routes, &proto.Route{
+			Id:      r.GetID(),
+			NetId:   r.GetNetID(),
+			Network: r.GetNetwork(),
+			Domains: r.GetDomains(),
+			Peer:    r.GetPeer(),
+			Metric:  int32(r.GetMetric()),
+		})
+	}
+
+	return pluginMap
+}
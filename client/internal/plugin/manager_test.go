@@ -0,0 +1,153 @@
+package plugin
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/netbirdio/netbird/client/internal/plugin/proto"
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+type testPlugin struct {
+	proto.UnimplementedPluginServer
+	apiVersion uint32
+	filter     *proto.FilterResponse
+	injections *proto.InjectionsResponse
+}
+
+func (p *testPlugin) Handshake(context.Context, *proto.HandshakeRequest) (*proto.HandshakeResponse, error) {
+	return &proto.HandshakeResponse{
+		ApiVersion: p.apiVersion,
+		Name:       "test",
+		Capabilities: []proto.Capability{
+			proto.Capability_FILTER,
+			proto.Capability_INJECT_ROUTES,
+			proto.Capability_INJECT_DNS,
+		},
+	}, nil
+}
+
+func (p *testPlugin) FilterNetworkMap(context.Context, *proto.NetworkMap) (*proto.FilterResponse, error) {
+	return p.filter, nil
+}
+
+func (p *testPlugin) GetInjections(context.Context, *proto.NetworkMap) (*proto.InjectionsResponse, error) {
+	return p.injections, nil
+}
+
+func startTestPlugin(t *testing.T, p *testPlugin) string {
+	t.Helper()
+
+	socket := filepath.Join(t.TempDir(), "plugin.sock")
+	lis, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	srv := grpc.NewServer()
+	proto.RegisterPluginServer(srv, p)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	return socket
+}
+
+func testNetworkMap() *mgmProto.NetworkMap {
+	return &mgmProto.NetworkMap{
+		Serial: 1,
+		RemotePeers: []*mgmProto.RemotePeerConfig{
+			{WgPubKey: "peerA", AllowedIps: []string{"100.64.0.1/32"}},
+			{WgPubKey: "peerB", AllowedIps: []string{"100.64.0.2/32"}},
+		},
+		Routes: []*mgmProto.Route{
+			{ID: "route1", Network: "10.0.0.0/24", Peer: "peerA"},
+		},
+		DNSConfig: &mgmProto.DNSConfig{
+			CustomZones: []*mgmProto.CustomZone{{Domain: "netbird.cloud."}},
+		},
+	}
+}
+
+func TestManager_ApplyNetworkMap(t *testing.T) {
+	p := &testPlugin{
+		apiVersion: APIVersion,
+		filter: &proto.FilterResponse{
+			VetoedPeers:  []string{"peerB"},
+			VetoedRoutes: []string{"route1"},
+		},
+		injections: &proto.InjectionsResponse{
+			Routes: []*proto.Route{
+				{Network: "192.168.1.0/24", Peer: "peerA"},
+				{Network: "172.16.0.0/16", Peer: "peerA"},
+				{Network: "192.168.1.128/25", Peer: "unknown"},
+			},
+			DnsRecords: []*proto.DNSRecord{
+				{Name: "svc.netbird.cloud", Type: 1, Ttl: 300, Rdata: "100.64.0.1"},
+				{Name: "svc.example.com", Type: 1, Ttl: 300, Rdata: "100.64.0.1"},
+			},
+		},
+	}
+	socket := startTestPlugin(t, p)
+
+	mgr := NewManager([]Config{{
+		Socket:              socket,
+		AllowVeto:           true,
+		AllowRouteInjection: true,
+		AllowDNSInjection:   true,
+		AllowedPrefixes:     []netip.Prefix{netip.MustParsePrefix("192.168.0.0/16")},
+	}}, nil)
+	mgr.Start(context.Background())
+	defer mgr.Close()
+
+	original := testNetworkMap()
+	nm := mgr.ApplyNetworkMap(context.Background(), original)
+
+	require.Len(t, nm.GetRemotePeers(), 1)
+	assert.Equal(t, "peerA", nm.GetRemotePeers()[0].GetWgPubKey())
+
+	require.Len(t, nm.GetRoutes(), 1, "only the route inside the allowed prefixes via a known peer should be injected")
+	assert.Equal(t, "192.168.1.0/24", nm.GetRoutes()[0].GetNetwork())
+
+	records := nm.GetDNSConfig().GetCustomZones()[0].GetRecords()
+	require.Len(t, records, 1, "only records inside known zones should be injected")
+	assert.Equal(t, "svc.netbird.cloud.", records[0].GetName())
+
+	assert.Len(t, original.GetRemotePeers(), 2, "the original network map must not be modified")
+	assert.Len(t, original.GetRoutes(), 1, "the original network map must not be modified")
+}
+
+func TestManager_ApplyNetworkMapWithoutPermissions(t *testing.T) {
+	p := &testPlugin{
+		apiVersion: APIVersion,
+		filter:     &proto.FilterResponse{VetoedPeers: []string{"peerB"}},
+		injections: &proto.InjectionsResponse{
+			Routes: []*proto.Route{{Network: "192.168.1.0/24", Peer: "peerA"}},
+		},
+	}
+	socket := startTestPlugin(t, p)
+
+	mgr := NewManager([]Config{{Socket: socket}}, nil)
+	mgr.Start(context.Background())
+	defer mgr.Close()
+
+	nm := mgr.ApplyNetworkMap(context.Background(), testNetworkMap())
+	assert.Len(t, nm.GetRemotePeers(), 2)
+	assert.Len(t, nm.GetRoutes(), 1)
+}
+
+func TestManager_VersionMismatch(t *testing.T) {
+	socket := startTestPlugin(t, &testPlugin{apiVersion: APIVersion + 1})
+
+	mgr := NewManager([]Config{{Socket: socket, AllowVeto: true}}, nil)
+	mgr.Start(context.Background())
+	defer mgr.Close()
+
+	assert.Empty(t, mgr.withCapability(proto.Capability_FILTER), "plugin with incompatible API version must be skipped")
+}
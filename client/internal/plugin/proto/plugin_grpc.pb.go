@@ -0,0 +1,217 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// PluginClient is the client API for Plugin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PluginClient interface {
+	// Handshake negotiates the API version and returns the capabilities of the plugin
+	Handshake(ctx context.Context, in *HandshakeRequest, opts ...grpc.CallOption) (*HandshakeResponse, error)
+	// OnEvent delivers an engine event to the plugin
+	OnEvent(ctx context.Context, in *Event, opts ...grpc.CallOption) (*Empty, error)
+	// FilterNetworkMap lets the plugin veto peers and routes of a network map before it is applied
+	FilterNetworkMap(ctx context.Context, in *NetworkMap, opts ...grpc.CallOption) (*FilterResponse, error)
+	// GetInjections returns the routes and DNS records the plugin wants to add to the network map
+	GetInjections(ctx context.Context, in *NetworkMap, opts ...grpc.CallOption) (*InjectionsResponse, error)
+}
+
+type pluginClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPluginClient(cc grpc.ClientConnInterface) PluginClient {
+	return &pluginClient{cc}
+}
+
+func (c *pluginClient) Handshake(ctx context.Context, in *HandshakeRequest, opts ...grpc.CallOption) (*HandshakeResponse, error) {
+	out := new(HandshakeResponse)
+	err := c.cc.Invoke(ctx, "/plugin.Plugin/Handshake", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginClient) OnEvent(ctx context.Context, in *Event, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/plugin.Plugin/OnEvent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginClient) FilterNetworkMap(ctx context.Context, in *NetworkMap, opts ...grpc.CallOption) (*FilterResponse, error) {
+	out := new(FilterResponse)
+	err := c.cc.Invoke(ctx, "/plugin.Plugin/FilterNetworkMap", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginClient) GetInjections(ctx context.Context, in *NetworkMap, opts ...grpc.CallOption) (*InjectionsResponse, error) {
+	out := new(InjectionsResponse)
+	err := c.cc.Invoke(ctx, "/plugin.Plugin/GetInjections", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PluginServer is the server API for Plugin service.
+// All implementations must embed UnimplementedPluginServer
+// for forward compatibility
+type PluginServer interface {
+	// Handshake negotiates the API version and returns the capabilities of the plugin
+	Handshake(context.Context, *HandshakeRequest) (*HandshakeResponse, error)
+	// OnEvent delivers an engine event to the plugin
+	OnEvent(context.Context, *Event) (*Empty, error)
+	// FilterNetworkMap lets the plugin veto peers and routes of a network map before it is applied
+	FilterNetworkMap(context.Context, *NetworkMap) (*FilterResponse, error)
+	// GetInjections returns the routes and DNS records the plugin wants to add to the network map
+	GetInjections(context.Context, *NetworkMap) (*InjectionsResponse, error)
+	mustEmbedUnimplementedPluginServer()
+}
+
+// UnimplementedPluginServer must be embedded to have forward compatible implementations.
+type UnimplementedPluginServer struct {
+}
+
+func (UnimplementedPluginServer) Handshake(context.Context, *HandshakeRequest) (*HandshakeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Handshake not implemented")
+}
+func (UnimplementedPluginServer) OnEvent(context.Context, *Event) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OnEvent not implemented")
+}
+func (UnimplementedPluginServer) FilterNetworkMap(context.Context, *NetworkMap) (*FilterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FilterNetworkMap not implemented")
+}
+func (UnimplementedPluginServer) GetInjections(context.Context, *NetworkMap) (*InjectionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInjections not implemented")
+}
+func (UnimplementedPluginServer) mustEmbedUnimplementedPluginServer() {}
+
+// UnsafePluginServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PluginServer will
+// result in compilation errors.
+type UnsafePluginServer interface {
+	mustEmbedUnimplementedPluginServer()
+}
+
+func RegisterPluginServer(s grpc.ServiceRegistrar, srv PluginServer) {
+	s.RegisterService(&Plugin_ServiceDesc, srv)
+}
+
+func _Plugin_Handshake_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HandshakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServer).Handshake(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.Plugin/Handshake",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServer).Handshake(ctx, req.(*HandshakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Plugin_OnEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Event)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServer).OnEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.Plugin/OnEvent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServer).OnEvent(ctx, req.(*Event))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Plugin_FilterNetworkMap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkMap)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServer).FilterNetworkMap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.Plugin/FilterNetworkMap",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServer).FilterNetworkMap(ctx, req.(*NetworkMap))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Plugin_GetInjections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkMap)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServer).GetInjections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.Plugin/GetInjections",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServer).GetInjections(ctx, req.(*NetworkMap))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Plugin_ServiceDesc is the grpc.ServiceDesc for Plugin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Plugin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "plugin.Plugin",
+	HandlerType: (*PluginServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Handshake",
+			Handler:    _Plugin_Handshake_Handler,
+		},
+		{
+			MethodName: "OnEvent",
+			Handler:    _Plugin_OnEvent_Handler,
+		},
+		{
+			MethodName: "FilterNetworkMap",
+			Handler:    _Plugin_FilterNetworkMap_Handler,
+		},
+		{
+			MethodName: "GetInjections",
+			Handler:    _Plugin_GetInjections_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin.proto",
+}
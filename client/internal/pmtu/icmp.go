@@ -0,0 +1,96 @@
+package pmtu
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+)
+
+const (
+	protocolICMP = 1
+	// echoOverhead is the size of the IPv4 and ICMP headers of an echo request
+	echoOverhead = 28
+)
+
+// icmpPinger sends the echo requests over a privileged ICMP socket bound to the NetBird address, so they go
+// through the tunnel
+type icmpPinger struct {
+	mu   sync.Mutex
+	conn *icmp.PacketConn
+	id   int
+	seq  int
+	buf  []byte
+}
+
+// NewICMPPinger returns a pinger sending from the local NetBird address
+func NewICMPPinger(local netip.Addr) (Pinger, error) {
+	conn, err := icmp.ListenPacket("ip4:icmp", local.String())
+	if err != nil {
+		return nil, fmt.Errorf("listen icmp: %w", err)
+	}
+	return &icmpPinger{
+		conn: conn,
+		id:   os.Getpid() & 0xffff,
+		buf:  make([]byte, 65535),
+	}, nil
+}
+
+func (p *icmpPinger) Ping(ctx context.Context, dst netip.Addr, size int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if size < echoOverhead {
+		return fmt.Errorf("packet size %d is below the headers size", size)
+	}
+
+	p.seq = (p.seq + 1) & 0xffff
+	msg := icmp.Message{
+		Type: ipv4.ICMPTypeEcho,
+		Body: &icmp.Echo{ID: p.id, Seq: p.seq, Data: make([]byte, size-echoOverhead)},
+	}
+	data, err := msg.Marshal(nil)
+	if err != nil {
+		return fmt.Errorf("marshal echo request: %w", err)
+	}
+
+	deadline := time.Now().Add(probeTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := p.conn.SetReadDeadline(deadline); err != nil {
+		return fmt.Errorf("set read deadline: %w", err)
+	}
+
+	if _, err := p.conn.WriteTo(data, &net.IPAddr{IP: dst.AsSlice()}); err != nil {
+		return fmt.Errorf("send echo request: %w", err)
+	}
+
+	for {
+		n, from, err := p.conn.ReadFrom(p.buf)
+		if err != nil {
+			return fmt.Errorf("read echo reply: %w", err)
+		}
+		if addr, ok := from.(*net.IPAddr); !ok || !addr.IP.Equal(dst.AsSlice()) {
+			continue
+		}
+
+		reply, err := icmp.ParseMessage(protocolICMP, p.buf[:n])
+		if err != nil || reply.Type != ipv4.ICMPTypeEchoReply {
+			continue
+		}
+		if echo, ok := reply.Body.(*icmp.Echo); ok && echo.ID == p.id && echo.Seq == p.seq {
+			return nil
+		}
+	}
+}
+
+func (p *icmpPinger) Close() error {
+	return p.conn.Close()
+}
@@ -0,0 +1,177 @@
+// Package pmtu measures the path MTU to the peers. Echo requests up to the interface MTU are sent to the NetBird
+// address of the peers, the largest one answered is the path MTU. A path MTU below the interface MTU means the
+// encapsulated packets are dropped on the way, usually because their fragments are filtered.
+package pmtu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	cProto "github.com/netbirdio/netbird/client/proto"
+)
+
+const (
+	// DefaultInterval is the pause between the probes of all directly connected peers
+	DefaultInterval = 10 * time.Minute
+
+	// minProbeSize is the minimum IPv4 packet size every path has to carry
+	minProbeSize = 576
+	// probeGranularity is the precision of the measured path MTU
+	probeGranularity = 8
+	probeTimeout     = time.Second
+	probeAttempts    = 3
+)
+
+var errNoReply = errors.New("peer doesn't answer echo requests")
+
+// Pinger sends an echo request of the IP packet size to the address and waits for the reply
+type Pinger interface {
+	Ping(ctx context.Context, dst netip.Addr, size int) error
+	Close() error
+}
+
+type statusRecorder interface {
+	GetFullStatus() peer.FullStatus
+	PublishEvent(severity cProto.SystemEvent_Severity, category cProto.SystemEvent_Category, msg string, userMsg string, metadata map[string]string)
+}
+
+// Prober measures the path MTU to the directly connected peers and advises lowering the interface MTU through the
+// status recorder if a path doesn't carry packets of the interface MTU. Relayed connections aren't probed, the relay
+// protocol doesn't fragment.
+type Prober struct {
+	pinger   Pinger
+	recorder statusRecorder
+	mtu      uint16
+	interval time.Duration
+
+	mu sync.Mutex
+	// advised is the path MTU the user was last advised about, keyed by peer public key
+	advised map[string]uint16
+}
+
+// NewProber returns a prober for the interface MTU, probing every interval or DefaultInterval if zero
+func NewProber(pinger Pinger, recorder statusRecorder, mtu uint16, interval time.Duration) *Prober {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Prober{
+		pinger:   pinger,
+		recorder: recorder,
+		mtu:      mtu,
+		interval: interval,
+		advised:  make(map[string]uint16),
+	}
+}
+
+// Run probes the peers every interval until the context is done, then closes the pinger
+func (p *Prober) Run(ctx context.Context) {
+	defer func() {
+		if err := p.pinger.Close(); err != nil {
+			log.Debugf("failed to close the path MTU pinger: %v", err)
+		}
+	}()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.probePeers(ctx)
+		}
+	}
+}
+
+func (p *Prober) probePeers(ctx context.Context) {
+	for _, state := range p.recorder.GetFullStatus().Peers {
+		if state.ConnStatus != peer.StatusConnected || state.Relayed {
+			continue
+		}
+		addr, err := netip.ParseAddr(state.IP)
+		if err != nil || !addr.Is4() {
+			continue
+		}
+
+		pathMTU, err := p.Probe(ctx, addr)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Debugf("not measuring the path MTU to peer %s: %v", state.FQDN, err)
+			continue
+		}
+		log.Debugf("path MTU to peer %s is %d, the interface MTU is %d", state.FQDN, pathMTU, p.mtu)
+		p.advise(state, pathMTU)
+	}
+}
+
+// Probe returns the largest packet size up to the interface MTU that reaches the address and is answered
+func (p *Prober) Probe(ctx context.Context, addr netip.Addr) (uint16, error) {
+	if p.ping(ctx, addr, int(p.mtu)) {
+		return p.mtu, nil
+	}
+	if !p.ping(ctx, addr, minProbeSize) {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, errNoReply
+	}
+
+	// lo is answered, hi isn't
+	lo, hi := minProbeSize, int(p.mtu)
+	for hi-lo > probeGranularity {
+		mid := (lo + hi) / 2
+		if p.ping(ctx, addr, mid) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+	return uint16(lo), nil
+}
+
+// ping reports whether one of the attempts to ping with the size was answered
+func (p *Prober) ping(ctx context.Context, addr netip.Addr, size int) bool {
+	for i := 0; i < probeAttempts && ctx.Err() == nil; i++ {
+		if err := p.pinger.Ping(ctx, addr, size); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// advise publishes a warning once per measured path MTU below the interface MTU
+func (p *Prober) advise(state peer.State, pathMTU uint16) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if pathMTU >= p.mtu {
+		delete(p.advised, state.PubKey)
+		return
+	}
+	if p.advised[state.PubKey] == pathMTU {
+		return
+	}
+	p.advised[state.PubKey] = pathMTU
+
+	log.Warnf("packets larger than %d bytes don't reach peer %s, the interface MTU is %d", pathMTU, state.FQDN, p.mtu)
+	p.recorder.PublishEvent(
+		cProto.SystemEvent_WARNING,
+		cProto.SystemEvent_NETWORK,
+		"Path MTU to peer is below the interface MTU",
+		fmt.Sprintf("Packets larger than %d bytes don't reach %s, lower the MTU to %d to avoid stalled connections.", pathMTU, state.FQDN, pathMTU),
+		map[string]string{"peer": state.FQDN, "path_mtu": fmt.Sprint(pathMTU), "mtu": fmt.Sprint(p.mtu)},
+	)
+}
@@ -0,0 +1,92 @@
+package pmtu
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	cProto "github.com/netbirdio/netbird/client/proto"
+)
+
+// fakePinger answers the echo requests up to the path MTU
+type fakePinger struct {
+	pathMTU int
+	pings   int
+}
+
+func (p *fakePinger) Ping(_ context.Context, _ netip.Addr, size int) error {
+	p.pings++
+	if size > p.pathMTU {
+		return errors.New("timeout")
+	}
+	return nil
+}
+
+func (p *fakePinger) Close() error {
+	return nil
+}
+
+type fakeRecorder struct {
+	peers  []peer.State
+	events []map[string]string
+}
+
+func (r *fakeRecorder) GetFullStatus() peer.FullStatus {
+	return peer.FullStatus{Peers: r.peers}
+}
+
+func (r *fakeRecorder) PublishEvent(_ cProto.SystemEvent_Severity, _ cProto.SystemEvent_Category, _ string, _ string, metadata map[string]string) {
+	r.events = append(r.events, metadata)
+}
+
+func TestProber_Probe(t *testing.T) {
+	addr := netip.MustParseAddr("100.64.0.2")
+
+	pinger := &fakePinger{pathMTU: 1280}
+	prober := NewProber(pinger, &fakeRecorder{}, 1280, 0)
+	pathMTU, err := prober.Probe(context.Background(), addr)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(1280), pathMTU)
+	assert.Equal(t, 1, pinger.pings, "a path carrying the interface MTU takes a single probe")
+
+	pinger = &fakePinger{pathMTU: 1100}
+	prober = NewProber(pinger, &fakeRecorder{}, 1280, 0)
+	pathMTU, err = prober.Probe(context.Background(), addr)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, pathMTU, uint16(1100))
+	assert.Greater(t, pathMTU, uint16(1100-probeGranularity))
+
+	pinger = &fakePinger{pathMTU: 0}
+	prober = NewProber(pinger, &fakeRecorder{}, 1280, 0)
+	_, err = prober.Probe(context.Background(), addr)
+	assert.ErrorIs(t, err, errNoReply)
+}
+
+func TestProber_ProbePeers(t *testing.T) {
+	recorder := &fakeRecorder{peers: []peer.State{
+		{PubKey: "direct", FQDN: "direct.netbird.cloud", IP: "100.64.0.2", ConnStatus: peer.StatusConnected},
+		{PubKey: "relayed", FQDN: "relayed.netbird.cloud", IP: "100.64.0.3", ConnStatus: peer.StatusConnected, Relayed: true},
+		{PubKey: "idle", FQDN: "idle.netbird.cloud", IP: "100.64.0.4", ConnStatus: peer.StatusIdle},
+	}}
+	pinger := &fakePinger{pathMTU: 1200}
+	prober := NewProber(pinger, recorder, 1280, 0)
+
+	prober.probePeers(context.Background())
+	require.Len(t, recorder.events, 1, "only the directly connected peer is probed")
+	assert.Equal(t, "direct.netbird.cloud", recorder.events[0]["peer"])
+	assert.Equal(t, "1280", recorder.events[0]["mtu"])
+
+	prober.probePeers(context.Background())
+	assert.Len(t, recorder.events, 1, "an unchanged path MTU is advised once")
+
+	pinger.pathMTU = 1280
+	prober.probePeers(context.Background())
+	pinger.pathMTU = 1200
+	prober.probePeers(context.Background())
+	assert.Len(t, recorder.events, 2, "the advice is repeated once the path MTU dropped again")
+}
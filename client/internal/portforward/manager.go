@@ -0,0 +1,242 @@
+// Package portforward forwards local TCP listeners to peer addresses through the netstack, similar to
+// kubectl port-forward. It lets applications reach peers without a TUN device.
+package portforward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-multierror"
+	log "github.com/sirupsen/logrus"
+
+	nberrors "github.com/netbirdio/netbird/client/errors"
+)
+
+// ErrNotFound is returned when no forward listens on the given address
+var ErrNotFound = errors.New("port forward not found")
+
+// Dialer dials through the tunnel, implemented by *netstack.Net
+type Dialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// DialerFunc returns the dialer of the running engine. It is called for every connection, so forwards keep working
+// across engine restarts.
+type DialerFunc func() (Dialer, error)
+
+// Info describes a port forward
+type Info struct {
+	ListenAddress     string
+	TargetAddress     string
+	ActiveConnections uint64
+	TotalConnections  uint64
+}
+
+// Manager keeps track of the port forwards
+type Manager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	dialer DialerFunc
+
+	mu       sync.Mutex
+	forwards map[string]*forward
+}
+
+type forward struct {
+	listener net.Listener
+	target   string
+	active   atomic.Int64
+	total    atomic.Uint64
+	done     chan struct{}
+}
+
+// NewManager returns a port forward manager that dials through the given dialer
+func NewManager(ctx context.Context, dialer DialerFunc) *Manager {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Manager{
+		ctx:      ctx,
+		cancel:   cancel,
+		dialer:   dialer,
+		forwards: make(map[string]*forward),
+	}
+}
+
+// Add starts listening on the listen address and forwards connections to the target.
+// A listen address without a host is bound to localhost, so the forward isn't exposed to the network by accident.
+func (m *Manager) Add(listenAddr, target string) (Info, error) {
+	if _, _, err := net.SplitHostPort(target); err != nil {
+		return Info{}, fmt.Errorf("invalid target address %s: %w", target, err)
+	}
+
+	listenAddr, err := normalizeListenAddr(listenAddr)
+	if err != nil {
+		return Info{}, err
+	}
+
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return Info{}, fmt.Errorf("listen on %s: %w", listenAddr, err)
+	}
+
+	f := &forward{
+		listener: listener,
+		target:   target,
+		done:     make(chan struct{}),
+	}
+
+	m.mu.Lock()
+	m.forwards[listener.Addr().String()] = f
+	m.mu.Unlock()
+
+	go m.serve(f)
+
+	log.Infof("forwarding %s to %s", listener.Addr(), target)
+	return f.info(), nil
+}
+
+// Remove closes the listener of the forward. Established connections are kept until they are closed by either side.
+// The listen address is normalized like in Add, so a bare port removes the forward on localhost.
+func (m *Manager) Remove(listenAddr string) error {
+	listenAddr, err := normalizeListenAddr(listenAddr)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	f, ok := m.forwards[listenAddr]
+	delete(m.forwards, listenAddr)
+	m.mu.Unlock()
+
+	if !ok {
+		return ErrNotFound
+	}
+
+	if err := f.close(); err != nil {
+		return fmt.Errorf("close listener: %w", err)
+	}
+
+	log.Infof("stopped forwarding %s to %s", listenAddr, f.target)
+	return nil
+}
+
+// normalizeListenAddr binds a bare port or an address without a host to localhost and resolves the host, so the
+// address matches the one of the listener
+func normalizeListenAddr(listenAddr string) (string, error) {
+	if !strings.Contains(listenAddr, ":") {
+		listenAddr = net.JoinHostPort("127.0.0.1", listenAddr)
+	}
+
+	host, port, err := net.SplitHostPort(listenAddr)
+	if err != nil {
+		return "", fmt.Errorf("invalid listen address %s: %w", listenAddr, err)
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+
+	addr, err := net.ResolveTCPAddr("tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return "", fmt.Errorf("resolve listen address %s: %w", listenAddr, err)
+	}
+	return addr.String(), nil
+}
+
+// List returns the port forwards ordered by their listen address
+func (m *Manager) List() []Info {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	infos := make([]Info, 0, len(m.forwards))
+	for _, f := range m.forwards {
+		infos = append(infos, f.info())
+	}
+	slices.SortFunc(infos, func(a, b Info) int {
+		return strings.Compare(a.ListenAddress, b.ListenAddress)
+	})
+	return infos
+}
+
+// Close removes all forwards and closes their connections
+func (m *Manager) Close() error {
+	m.cancel()
+
+	m.mu.Lock()
+	forwards := m.forwards
+	m.forwards = make(map[string]*forward)
+	m.mu.Unlock()
+
+	var merr *multierror.Error
+	for addr, f := range forwards {
+		if err := f.close(); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("close listener %s: %w", addr, err))
+		}
+	}
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+func (m *Manager) serve(f *forward) {
+	defer close(f.done)
+
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Errorf("port forward %s stopped accepting connections: %v", f.listener.Addr(), err)
+			}
+			return
+		}
+
+		go m.handle(f, conn)
+	}
+}
+
+func (m *Manager) handle(f *forward, conn net.Conn) {
+	f.total.Add(1)
+	f.active.Add(1)
+	defer f.active.Add(-1)
+
+	dialer, err := m.dialer()
+	if err != nil {
+		log.Warnf("port forward %s can't reach %s: %v", f.listener.Addr(), f.target, err)
+		closeConn(conn)
+		return
+	}
+
+	upstream, err := dialer.DialContext(m.ctx, "tcp", f.target)
+	if err != nil {
+		log.Debugf("port forward %s failed to dial %s: %v", f.listener.Addr(), f.target, err)
+		closeConn(conn)
+		return
+	}
+
+	relay(m.ctx, conn, upstream)
+}
+
+func (f *forward) info() Info {
+	active := f.active.Load()
+	if active < 0 {
+		active = 0
+	}
+
+	return Info{
+		ListenAddress:     f.listener.Addr().String(),
+		TargetAddress:     f.target,
+		ActiveConnections: uint64(active),
+		TotalConnections:  f.total.Load(),
+	}
+}
+
+func (f *forward) close() error {
+	err := f.listener.Close()
+	<-f.done
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
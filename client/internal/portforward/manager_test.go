@@ -0,0 +1,151 @@
+package portforward
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startEchoServer(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func newTestManager(t *testing.T, dialer DialerFunc) *Manager {
+	t.Helper()
+
+	m := NewManager(context.Background(), dialer)
+	t.Cleanup(func() { assert.NoError(t, m.Close()) })
+	return m
+}
+
+func hostDialer() (Dialer, error) {
+	return &net.Dialer{}, nil
+}
+
+func TestManager_Forward(t *testing.T) {
+	echoAddr := startEchoServer(t)
+	m := newTestManager(t, hostDialer)
+
+	info, err := m.Add("127.0.0.1:0", echoAddr)
+	require.NoError(t, err)
+	assert.Equal(t, echoAddr, info.TargetAddress)
+
+	conn, err := net.Dial("tcp", info.ListenAddress)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+
+	forwards := m.List()
+	require.Len(t, forwards, 1)
+	assert.Equal(t, uint64(1), forwards[0].TotalConnections)
+	assert.Equal(t, uint64(1), forwards[0].ActiveConnections)
+}
+
+func TestManager_BarePortBindsLocalhost(t *testing.T) {
+	m := newTestManager(t, hostDialer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	require.NoError(t, listener.Close())
+
+	info, err := m.Add(port, "100.64.0.1:80")
+	require.NoError(t, err)
+	assert.Equal(t, net.JoinHostPort("127.0.0.1", port), info.ListenAddress)
+}
+
+func TestManager_RemoveBarePort(t *testing.T) {
+	m := newTestManager(t, hostDialer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	require.NoError(t, listener.Close())
+
+	_, err = m.Add(port, "100.64.0.1:80")
+	require.NoError(t, err)
+	require.NoError(t, m.Remove(port))
+	assert.Empty(t, m.List())
+
+	// an address without a host is bound to localhost, too
+	info, err := m.Add(":"+port, "100.64.0.1:80")
+	require.NoError(t, err)
+	assert.Equal(t, net.JoinHostPort("127.0.0.1", port), info.ListenAddress)
+	require.NoError(t, m.Remove(":"+port))
+	assert.Empty(t, m.List())
+}
+
+func TestManager_Remove(t *testing.T) {
+	m := newTestManager(t, hostDialer)
+
+	info, err := m.Add("127.0.0.1:0", "100.64.0.1:80")
+	require.NoError(t, err)
+
+	require.NoError(t, m.Remove(info.ListenAddress))
+	assert.Empty(t, m.List())
+
+	_, err = net.Dial("tcp", info.ListenAddress)
+	assert.Error(t, err, "listener should be closed")
+
+	assert.ErrorIs(t, m.Remove(info.ListenAddress), ErrNotFound)
+}
+
+func TestManager_InvalidTarget(t *testing.T) {
+	m := newTestManager(t, hostDialer)
+
+	_, err := m.Add("127.0.0.1:0", "100.64.0.1")
+	assert.Error(t, err)
+	assert.Empty(t, m.List())
+}
+
+func TestManager_DialerUnavailable(t *testing.T) {
+	m := newTestManager(t, func() (Dialer, error) {
+		return nil, errors.New("engine not running")
+	})
+
+	info, err := m.Add("127.0.0.1:0", "100.64.0.1:80")
+	require.NoError(t, err)
+
+	conn, err := net.Dial("tcp", info.ListenAddress)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// the connection is closed by the forward
+	_, err = conn.Read(make([]byte, 1))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, io.EOF) || strings.Contains(err.Error(), "reset"), err.Error())
+}
@@ -0,0 +1,46 @@
+package portforward
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// relay copies data in both directions until either side is done or the context is canceled
+func relay(ctx context.Context, a, b net.Conn) {
+	var once sync.Once
+	closeBoth := func() {
+		once.Do(func() {
+			closeConn(a)
+			closeConn(b)
+		})
+	}
+
+	stop := context.AfterFunc(ctx, closeBoth)
+	defer stop()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	cp := func(dst, src net.Conn) {
+		defer wg.Done()
+		if _, err := io.Copy(dst, src); err != nil {
+			log.Tracef("port forward relay ended: %v", err)
+		}
+		// unblock the copy in the other direction
+		closeBoth()
+	}
+
+	go cp(a, b)
+	go cp(b, a)
+	wg.Wait()
+}
+
+func closeConn(conn net.Conn) {
+	if err := conn.Close(); err != nil {
+		log.Tracef("failed to close connection: %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/profilemanager"
+	"github.com/netbirdio/netbird/client/net/proxy"
+)
+
+// applyProxy routes the connections to the management, signal and relay servers through the proxy of the config, or
+// the proxy of the environment if none is configured
+func applyProxy(config *profilemanager.Config) error {
+	if config.ProxyURL == "" {
+		return proxy.SetURL("")
+	}
+
+	proxyURL, err := proxy.Parse(config.ProxyURL)
+	if err != nil {
+		return fmt.Errorf("invalid proxy: %w", err)
+	}
+	if err := proxy.SetURL(config.ProxyURL); err != nil {
+		return fmt.Errorf("set proxy: %w", err)
+	}
+	log.Infof("connecting to the NetBird servers through proxy %s", proxyURL.Redacted())
+	return nil
+}
@@ -3,6 +3,7 @@ package relay
 import (
 	"context"
 	"crypto/sha256"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
@@ -249,7 +250,16 @@ func (p *StunTurnProbe) probeSTUN(ctx context.Context, uri *stun.URI) (addr stri
 	return addr, nil
 }
 
-// ProbeTURN tries allocating a session from the given TURN URI
+// ProbeTURN tries allocating a session from the given TURN URI, bypassing the cache
+func (p *StunTurnProbe) ProbeTURN(ctx context.Context, uri *stun.URI) error {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	_, err := p.probeTURN(ctx, uri)
+	return err
+}
+
+// probeTURN tries allocating a session from the given TURN URI
 func (p *StunTurnProbe) probeTURN(ctx context.Context, uri *stun.URI) (addr string, probeErr error) {
 	defer func() {
 		if probeErr != nil {
@@ -274,6 +284,15 @@ func (p *StunTurnProbe) probeTURN(ctx context.Context, uri *stun.URI) (addr stri
 			probeErr = fmt.Errorf("dial: %w", err)
 			return
 		}
+		if uri.Scheme == stun.SchemeTypeTURNS {
+			tlsConn := tls.Client(tcpConn, &tls.Config{ServerName: uri.Host})
+			if err := tlsConn.HandshakeContext(ctx); err != nil {
+				_ = tcpConn.Close()
+				probeErr = fmt.Errorf("tls handshake: %w", err)
+				return
+			}
+			tcpConn = tlsConn
+		}
 		conn = turn.NewSTUNConn(tcpConn)
 	default:
 		probeErr = fmt.Errorf("conn: unknown proto: %s", uri.Proto)
@@ -0,0 +1,269 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	relayClient "github.com/netbirdio/netbird/shared/relay/client"
+)
+
+const (
+	// relayMeasureInterval is how long the round trip times are reused while the relay servers don't change
+	relayMeasureInterval = 10 * time.Minute
+	relayMeasureTimeout  = 5 * time.Second
+	relaySwitchTimeout   = 30 * time.Second
+	// relaySwitchMargin is how much lower the round trip time of another relay server must be before the home relay
+	// server is switched, switching reconnects the relayed peers
+	relaySwitchMargin = 20 * time.Millisecond
+)
+
+// ErrUnknownRelay is returned when pinning a relay server management didn't send
+var ErrUnknownRelay = errors.New("unknown relay server")
+
+// RelayCandidate is a relay server the client may use as its home relay server
+type RelayCandidate struct {
+	URL string
+	// RTT is the round trip time to the server, zero if it wasn't measured or the measurement failed
+	RTT        time.Duration
+	Err        error
+	MeasuredAt time.Time
+	// Home is set for the server the client is connected to
+	Home bool
+	// Preferred is set for the pinned server, or the one with the lowest round trip time
+	Preferred bool
+}
+
+// relaySelection measures the round trip times to the relay servers and steers the home relay server to the pinned
+// or the lowest latency one. The peers connect through the home relay server of the peer that offers the connection.
+type relaySelection struct {
+	manager *relayClient.Manager
+	measure func(ctx context.Context, serverURL string) (time.Duration, error)
+
+	mu         sync.Mutex
+	pinned     string
+	urls       []string
+	candidates map[string]RelayCandidate
+	measuredAt time.Time
+	measuring  bool
+}
+
+func newRelaySelection(manager *relayClient.Manager, pinned string) *relaySelection {
+	return &relaySelection{
+		manager:    manager,
+		measure:    relayClient.MeasureRTT,
+		pinned:     pinned,
+		candidates: make(map[string]RelayCandidate),
+	}
+}
+
+// updateServers measures the relay servers again if they changed or the measurement is outdated, and selects the
+// home relay server once it's done
+func (r *relaySelection) updateServers(ctx context.Context, wg *sync.WaitGroup, urls []string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.measuring || (slices.Equal(r.urls, urls) && time.Since(r.measuredAt) < relayMeasureInterval) {
+		return
+	}
+	r.urls = slices.Clone(urls)
+	if len(urls) == 0 {
+		r.candidates = make(map[string]RelayCandidate)
+		return
+	}
+
+	r.measuring = true
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		r.measureAll(ctx)
+		r.selectServer(ctx)
+	}()
+}
+
+// measureAll measures the round trip time to all relay servers concurrently
+func (r *relaySelection) measureAll(ctx context.Context) {
+	r.mu.Lock()
+	urls := slices.Clone(r.urls)
+	r.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, relayMeasureTimeout)
+	defer cancel()
+
+	results := make([]RelayCandidate, len(urls))
+	var wg sync.WaitGroup
+	for i, url := range urls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rtt, err := r.measure(ctx, url)
+			results[i] = RelayCandidate{URL: url, RTT: rtt, Err: err, MeasuredAt: time.Now()}
+		}()
+	}
+	wg.Wait()
+
+	candidates := make(map[string]RelayCandidate, len(results))
+	for _, candidate := range results {
+		if candidate.Err != nil {
+			log.Debugf("failed to measure the round trip time to relay server %s: %v", candidate.URL, candidate.Err)
+		} else {
+			log.Debugf("round trip time to relay server %s: %s", candidate.URL, candidate.RTT)
+		}
+		candidates[candidate.URL] = candidate
+	}
+
+	r.mu.Lock()
+	r.candidates = candidates
+	r.measuredAt = time.Now()
+	r.measuring = false
+	r.mu.Unlock()
+}
+
+// selectServer makes the relay manager prefer the selected server and switches the home relay server to it, if it's
+// pinned or has a clearly lower round trip time
+func (r *relaySelection) selectServer(ctx context.Context) {
+	r.mu.Lock()
+	candidates := r.sortedCandidates()
+	pinned := r.pinned
+	r.mu.Unlock()
+
+	home, _ := r.manager.HomeServerURL()
+	preferred, switchHome := chooseRelay(candidates, pinned, home)
+	r.manager.SetPreferredServer(preferred)
+	if !switchHome {
+		return
+	}
+
+	log.Infof("switching the home relay server from %s to %s", home, preferred)
+	ctx, cancel := context.WithTimeout(ctx, relaySwitchTimeout)
+	defer cancel()
+	if err := r.manager.SwitchServer(ctx, preferred); err != nil {
+		log.Warnf("failed to switch the home relay server to %s: %v", preferred, err)
+	}
+}
+
+// pin pins the home relay server, an empty URL selects the lowest latency server again
+func (r *relaySelection) pin(ctx context.Context, wg *sync.WaitGroup, url string) error {
+	if url != "" && !slices.Contains(r.manager.ServerURLs(), url) {
+		return fmt.Errorf("%w: %s", ErrUnknownRelay, url)
+	}
+
+	r.mu.Lock()
+	r.pinned = url
+	r.mu.Unlock()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		r.selectServer(ctx)
+	}()
+	return nil
+}
+
+// list returns the relay servers with their latest measurement, the selected and the home server marked
+func (r *relaySelection) list() ([]RelayCandidate, string) {
+	r.mu.Lock()
+	candidates := r.sortedCandidates()
+	pinned := r.pinned
+	r.mu.Unlock()
+
+	home, _ := r.manager.HomeServerURL()
+	preferred, _ := chooseRelay(candidates, pinned, home)
+	for i := range candidates {
+		candidates[i].Home = candidates[i].URL == home
+		candidates[i].Preferred = candidates[i].URL == preferred
+	}
+	return candidates, pinned
+}
+
+// sortedCandidates returns the candidates in the order of the relay URLs, the caller must hold mu
+func (r *relaySelection) sortedCandidates() []RelayCandidate {
+	candidates := make([]RelayCandidate, 0, len(r.urls))
+	for _, url := range r.urls {
+		candidate, ok := r.candidates[url]
+		if !ok {
+			candidate = RelayCandidate{URL: url}
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
+// chooseRelay returns the pinned server if it's a candidate, otherwise the reachable one with the lowest round trip
+// time. It reports whether the home server should be switched to it.
+func chooseRelay(candidates []RelayCandidate, pinned, home string) (string, bool) {
+	var best, current *RelayCandidate
+	for i := range candidates {
+		candidate := &candidates[i]
+		if candidate.URL == home {
+			current = candidate
+		}
+		if candidate.URL == pinned {
+			return pinned, home != "" && home != pinned
+		}
+		if candidate.Err != nil || candidate.RTT == 0 {
+			continue
+		}
+		if best == nil || candidate.RTT < best.RTT {
+			best = candidate
+		}
+	}
+
+	if best == nil {
+		return "", false
+	}
+	if home == "" || home == best.URL {
+		return best.URL, false
+	}
+	// the home server is unknown or unreachable, or clearly slower
+	if current == nil || current.Err != nil || current.RTT == 0 || current.RTT-best.RTT > relaySwitchMargin {
+		return best.URL, true
+	}
+	return best.URL, false
+}
+
+// GetRelayCandidates returns the relay servers with their round trip time and the pinned server. Refresh measures
+// the round trip times again.
+func (e *Engine) GetRelayCandidates(refresh bool) ([]RelayCandidate, string, error) {
+	e.syncMsgMux.Lock()
+	selection := e.relaySelection
+	e.syncMsgMux.Unlock()
+
+	if selection == nil {
+		return nil, "", errors.New("relay is not enabled")
+	}
+
+	if refresh {
+		selection.mu.Lock()
+		measuring := selection.measuring
+		selection.measuring = true
+		selection.mu.Unlock()
+
+		if !measuring {
+			selection.measureAll(e.ctx)
+		}
+	}
+
+	candidates, pinned := selection.list()
+	return candidates, pinned, nil
+}
+
+// SetPreferredRelay pins the home relay server, an empty URL selects the lowest latency server again
+func (e *Engine) SetPreferredRelay(url string) error {
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+
+	if e.relaySelection == nil {
+		return errors.New("relay is not enabled")
+	}
+	return e.relaySelection.pin(e.ctx, &e.shutdownWg, url)
+}
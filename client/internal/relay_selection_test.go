@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChooseRelay(t *testing.T) {
+	const (
+		eu = "rels://eu.relay.example.com"
+		us = "rels://us.relay.example.com"
+		ap = "rels://ap.relay.example.com"
+	)
+	candidates := []RelayCandidate{
+		{URL: eu, RTT: 30 * time.Millisecond},
+		{URL: us, RTT: 15 * time.Millisecond},
+		{URL: ap, Err: errors.New("timeout")},
+	}
+
+	tests := []struct {
+		name          string
+		candidates    []RelayCandidate
+		pinned        string
+		home          string
+		wantPreferred string
+		wantSwitch    bool
+	}{
+		{name: "not connected", candidates: candidates, wantPreferred: us},
+		{name: "connected to the fastest", candidates: candidates, home: us, wantPreferred: us},
+		{name: "within the margin", candidates: candidates, home: eu, wantPreferred: us},
+		{
+			name: "clearly slower",
+			candidates: []RelayCandidate{
+				{URL: eu, RTT: 80 * time.Millisecond},
+				{URL: us, RTT: 15 * time.Millisecond},
+			},
+			home:          eu,
+			wantPreferred: us,
+			wantSwitch:    true,
+		},
+		{name: "home unreachable", candidates: candidates, home: ap, wantPreferred: us, wantSwitch: true},
+		{name: "home removed", candidates: candidates, home: "rels://old.relay.example.com", wantPreferred: us, wantSwitch: true},
+		{name: "pinned", candidates: candidates, pinned: ap, home: us, wantPreferred: ap, wantSwitch: true},
+		{name: "pinned is home", candidates: candidates, pinned: eu, home: eu, wantPreferred: eu},
+		{name: "pinned removed", candidates: candidates, pinned: "rels://old.relay.example.com", home: us, wantPreferred: us},
+		{name: "nothing measured", candidates: []RelayCandidate{{URL: eu}, {URL: us}}, home: eu},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preferred, switchHome := chooseRelay(tt.candidates, tt.pinned, tt.home)
+			assert.Equal(t, tt.wantPreferred, preferred)
+			assert.Equal(t, tt.wantSwitch, switchHome)
+		})
+	}
+}
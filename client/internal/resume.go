@@ -0,0 +1,106 @@
+package internal
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/sleep"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+const (
+	// resumeCheckInterval is how often the clocks are compared to detect a resume from suspend
+	resumeCheckInterval = 5 * time.Second
+	// resumeThreshold is the suspend time that counts as a sleep, well above the scheduling delays of a busy host
+	resumeThreshold = 20 * time.Second
+	// resumeDebounce folds the wake up reported by the OS and the one detected from the clocks into one recovery
+	resumeDebounce = time.Minute
+)
+
+// startResumeDetection recovers the peer connections when the host resumes from suspend. Without it, the peers stay
+// on the connections that died during the sleep until the ICE and handshake timeouts expire. The wake up is
+// detected from the clocks on every platform and from the OS power events where they are supported.
+func (e *Engine) startResumeDetection() {
+	onEvent := func(event sleep.EventType) {
+		if event == sleep.EventTypeWakeUp {
+			e.handleResume()
+		}
+	}
+
+	var detectors []interface{ Deregister() error }
+
+	clock := sleep.NewClockDetector(resumeCheckInterval, resumeThreshold)
+	if err := clock.Register(onEvent); err != nil {
+		log.Warnf("failed to start the resume detection: %v", err)
+	} else {
+		detectors = append(detectors, clock)
+	}
+
+	if power, err := sleep.New(); err != nil {
+		log.Debugf("OS power events aren't available for the resume detection: %v", err)
+	} else if err := power.Register(onEvent); err != nil {
+		log.Warnf("failed to register for OS power events: %v", err)
+	} else {
+		detectors = append(detectors, power)
+	}
+
+	e.shutdownWg.Add(1)
+	go func() {
+		defer e.shutdownWg.Done()
+		<-e.ctx.Done()
+		for _, d := range detectors {
+			if err := d.Deregister(); err != nil {
+				log.Debugf("failed to stop the resume detection: %v", err)
+			}
+		}
+	}()
+}
+
+// handleResume treats all peer connections as stale after a resume from suspend and recovers them right away, like
+// after a network change. The health probes run again so the status doesn't show the state from before the sleep.
+func (e *Engine) handleResume() {
+	e.syncMsgMux.Lock()
+	if e.ctx.Err() != nil {
+		e.syncMsgMux.Unlock()
+		return
+	}
+	if !e.lastResume.IsZero() && time.Since(e.lastResume) < resumeDebounce {
+		e.syncMsgMux.Unlock()
+		return
+	}
+	e.lastResume = time.Now()
+
+	log.Infof("host resumed from suspend, recovering the peer connections")
+
+	// the host might have woken up on another network
+	if e.routeManager != nil {
+		if err := e.routeManager.ProtectEndpoints(); err != nil {
+			log.Warnf("failed to update the host routes of the NetBird services after resume: %v", err)
+		}
+	}
+	e.updateLANBlock()
+
+	if e.udpMux != nil {
+		e.udpMux.ResetXORMappedAddrs()
+	}
+
+	for _, key := range e.peerStore.PeersPubKey() {
+		conn, ok := e.peerStore.PeerConn(key)
+		if !ok {
+			continue
+		}
+		conn.RecoverAfterResume()
+	}
+	e.syncMsgMux.Unlock()
+
+	e.statusRecorder.PublishEvent(
+		proto.SystemEvent_INFO,
+		proto.SystemEvent_CONNECTIVITY,
+		"Host resumed from suspend, recovering the peer connections",
+		"",
+		nil,
+	)
+
+	e.RunHealthProbes(false)
+}
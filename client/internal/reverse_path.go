@@ -0,0 +1,60 @@
+package internal
+
+import (
+	"net/netip"
+
+	log "github.com/sirupsen/logrus"
+
+	firewallManager "github.com/netbirdio/netbird/client/firewall/manager"
+	"github.com/netbirdio/netbird/route"
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+// updateReversePathFilter drops the routed traffic with spoofed sources while this peer routes networks. The
+// traffic from the overlay has to come from the AllowedIPs of the peers, the traffic into the overlay from the
+// networks this peer routes. The sources of dynamic routes can't be told in advance, they pass unchecked.
+func (e *Engine) updateReversePathFilter(serverRoutes map[route.ID]*route.Route, remotePeers []*mgmProto.RemotePeerConfig) {
+	if !e.config.ReversePathFilter || e.firewall == nil {
+		return
+	}
+
+	rpf, ok := e.firewall.(firewallManager.ReversePathFilter)
+	if !ok {
+		log.Warnf("the firewall doesn't support the reverse path filter")
+		return
+	}
+
+	if len(serverRoutes) == 0 {
+		if err := rpf.RemoveReversePathFilter(); err != nil {
+			log.Errorf("failed to remove the reverse path filter: %v", err)
+		}
+		return
+	}
+
+	overlay := []netip.Prefix{e.wgInterface.Address().Network}
+	for _, p := range remotePeers {
+		overlay = append(overlay, parseAllowedIPs(p.GetAllowedIps())...)
+	}
+
+	var local []netip.Prefix
+	for _, r := range serverRoutes {
+		if r.IsDynamic() {
+			local = []netip.Prefix{netip.PrefixFrom(netip.IPv4Unspecified(), 0)}
+			break
+		}
+		local = append(local, r.Network)
+	}
+
+	if err := rpf.SetReversePathFilter(overlay, local); err != nil {
+		log.Errorf("failed to update the reverse path filter: %v", err)
+	}
+}
+
+// reversePathStats returns the counters of the reverse path filter, empty if it isn't enabled
+func (e *Engine) reversePathStats() ([]firewallManager.RouteRuleStats, error) {
+	rpf, ok := e.firewall.(firewallManager.ReversePathFilter)
+	if !e.config.ReversePathFilter || !ok {
+		return nil, nil
+	}
+	return rpf.ReversePathStats()
+}
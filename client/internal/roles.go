@@ -0,0 +1,41 @@
+package internal
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	firewallManager "github.com/netbirdio/netbird/client/firewall/manager"
+	"github.com/netbirdio/netbird/client/internal/eventbus"
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+	"github.com/netbirdio/netbird/client/system"
+	"github.com/netbirdio/netbird/route"
+)
+
+// detectRoles derives the roles of the peer from the routes it serves and its ingress forwarding rules
+func detectRoles(serverRoutes map[route.ID]*route.Route, forwardingRules []firewallManager.ForwardRule) system.Roles {
+	class := flowDeviceClass(serverRoutes)
+	return system.Roles{
+		RoutingPeer:    class != nftypes.DeviceClassClient,
+		ExitNode:       class == nftypes.DeviceClassExitNode,
+		IngressGateway: len(forwardingRules) > 0,
+		EphemeralCI:    system.IsCIRunner(),
+	}
+}
+
+// updateRolesIfNew publishes changed roles and reports them to management. The caller must hold syncMsgMux.
+func (e *Engine) updateRolesIfNew(roles system.Roles) {
+	if roles == e.roles {
+		return
+	}
+	e.roles = roles
+
+	log.Infof("peer roles changed to %v", roles.Names())
+	e.eventBus.Publish(eventbus.RolesChanged{Roles: roles})
+
+	// running from the cached network map, the roles are sent with the next Sync
+	if e.offlineCatalogue {
+		return
+	}
+	if err := e.syncMeta(); err != nil {
+		log.Warnf("failed to report the peer roles to management: %v", err)
+	}
+}
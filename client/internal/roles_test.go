@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	firewallManager "github.com/netbirdio/netbird/client/firewall/manager"
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/system"
+	"github.com/netbirdio/netbird/route"
+)
+
+func TestDetectRoles(t *testing.T) {
+	ci := system.IsCIRunner()
+	lan := &route.Route{ID: "lan", Network: netip.MustParsePrefix("192.168.0.0/24")}
+	exit := &route.Route{ID: "exit", Network: netip.MustParsePrefix("0.0.0.0/0")}
+
+	assert.Equal(t, system.Roles{EphemeralCI: ci}, detectRoles(nil, nil))
+	assert.Equal(t, system.Roles{RoutingPeer: true, EphemeralCI: ci}, detectRoles(map[route.ID]*route.Route{lan.ID: lan}, nil))
+	assert.Equal(t, system.Roles{RoutingPeer: true, ExitNode: true, EphemeralCI: ci},
+		detectRoles(map[route.ID]*route.Route{lan.ID: lan, exit.ID: exit}, nil))
+	assert.Equal(t, system.Roles{IngressGateway: true, EphemeralCI: ci},
+		detectRoles(nil, []firewallManager.ForwardRule{{Protocol: firewallManager.ProtocolTCP}}))
+}
+
+func TestUpdateRolesIfNew(t *testing.T) {
+	recorder := peer.NewRecorder("https://api.netbird.io:443")
+	e := &Engine{statusRecorder: recorder, eventBus: newEventBus(recorder), offlineCatalogue: true}
+
+	e.updateRolesIfNew(system.Roles{RoutingPeer: true, IngressGateway: true})
+	assert.Equal(t, []string{system.RoleRoutingPeer, system.RoleIngressGateway}, e.statusRecorder.GetLocalPeerState().Roles)
+
+	e.updateRolesIfNew(system.Roles{})
+	assert.Empty(t, e.statusRecorder.GetLocalPeerState().Roles)
+}
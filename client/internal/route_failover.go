@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"net/netip"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/route"
+	signal "github.com/netbirdio/netbird/shared/signal/client"
+	sProto "github.com/netbirdio/netbird/shared/signal/proto"
+)
+
+// notifyRouteFailover tells the new routing peer of a network that our traffic moved over to it,
+// so it accepts the connections that were established through the previous routing peer.
+// It is called by the route watchers, which may run while the engine holds syncMsgMux.
+func (e *Engine) notifyRouteFailover(newRoute *route.Route) {
+	conn, ok := e.peerStore.PeerConn(newRoute.Peer)
+	if !ok {
+		return
+	}
+	if features, known := conn.RemoteFeatures(); known && !features.Has(signal.FeatureRouteFailover) {
+		log.Debugf("routing peer %s doesn't support route failover notices", newRoute.Peer)
+		return
+	}
+
+	network := newRoute.Network
+	go func() {
+		if err := e.signaler.SignalRouteFailover(newRoute.Peer, []netip.Prefix{network}); err != nil {
+			log.Warnf("failed to signal failover of %s to %s: %v", network, newRoute.Peer, err)
+			return
+		}
+		log.Debugf("signaled failover of %s to %s", network, newRoute.Peer)
+	}()
+}
+
+// handleRouteFailover accepts the failed over connections of a remote peer for the configured grace period
+func (e *Engine) handleRouteFailover(pubKey string, failover *sProto.RouteFailover) {
+	if e.config.RouteFailoverGrace <= 0 {
+		log.Debugf("ignoring route failover from %s, the failover grace period is disabled", pubKey)
+		return
+	}
+	if e.routeManager == nil || e.routeManager.ServerRoutesCount() == 0 {
+		log.Warnf("ignoring route failover from %s, this peer doesn't route any network", pubKey)
+		return
+	}
+
+	state, err := e.statusRecorder.GetPeer(pubKey)
+	if err != nil {
+		log.Warnf("ignoring route failover from unknown peer %s", pubKey)
+		return
+	}
+	peerIP, err := netip.ParseAddr(state.IP)
+	if err != nil {
+		log.Warnf("ignoring route failover from %s with invalid address %q: %v", pubKey, state.IP, err)
+		return
+	}
+
+	networks := make([]netip.Prefix, 0, len(failover.GetNetworks()))
+	for _, n := range failover.GetNetworks() {
+		network, err := netip.ParsePrefix(n)
+		if err != nil {
+			log.Warnf("ignoring invalid network %q in route failover from %s: %v", n, pubKey, err)
+			continue
+		}
+		networks = append(networks, network)
+	}
+	if len(networks) == 0 {
+		return
+	}
+
+	if err := e.routeManager.AllowRouteFailover(peerIP, networks, e.config.RouteFailoverGrace); err != nil {
+		log.Warnf("failed to accept failed over connections of %s: %v", state.FQDN, err)
+	}
+}
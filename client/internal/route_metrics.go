@@ -0,0 +1,26 @@
+package internal
+
+import (
+	"errors"
+
+	"github.com/netbirdio/netbird/client/internal/routemanager/metrics"
+	"github.com/netbirdio/netbird/route"
+)
+
+var errRouteMetricsUnavailable = errors.New("route metrics are not recorded")
+
+// RouteMetrics returns how long programming the routes and route firewall rules took
+func (e *Engine) RouteMetrics() (metrics.Snapshot, error) {
+	if e.routeMetrics == nil {
+		return metrics.Snapshot{}, errRouteMetricsUnavailable
+	}
+	return e.routeMetrics.Snapshot(), nil
+}
+
+func countHARoutes(haMap route.HAMap) int {
+	var count int
+	for _, routes := range haMap {
+		count += len(routes)
+	}
+	return count
+}
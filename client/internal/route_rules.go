@@ -0,0 +1,35 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+
+	firewallManager "github.com/netbirdio/netbird/client/firewall/manager"
+)
+
+var errRouteRuleStatsUnsupported = errors.New("the firewall doesn't count route rules")
+
+// RouteRuleStats returns the counters of the route firewall rules, followed by the ones of the reverse path filter
+func (e *Engine) RouteRuleStats() ([]firewallManager.RouteRuleStats, error) {
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+
+	rpfStats, err := e.reversePathStats()
+	if err != nil {
+		return nil, fmt.Errorf("reverse path filter: %w", err)
+	}
+
+	counter, ok := e.firewall.(firewallManager.RouteRuleCounter)
+	if !ok {
+		if len(rpfStats) > 0 {
+			return rpfStats, nil
+		}
+		return nil, errRouteRuleStatsUnsupported
+	}
+
+	stats, err := counter.RouteRuleStats()
+	if err != nil {
+		return nil, err
+	}
+	return append(stats, rpfStats...), nil
+}
@@ -9,12 +9,14 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	nbdns "github.com/netbirdio/netbird/client/internal/dns"
+	"github.com/netbirdio/netbird/client/internal/eventbus"
 	"github.com/netbirdio/netbird/client/internal/peer"
 	"github.com/netbirdio/netbird/client/internal/routemanager/common"
 	"github.com/netbirdio/netbird/client/internal/routemanager/dnsinterceptor"
 	"github.com/netbirdio/netbird/client/internal/routemanager/dynamic"
 	"github.com/netbirdio/netbird/client/internal/routemanager/iface"
 	"github.com/netbirdio/netbird/client/internal/routemanager/static"
+	"github.com/netbirdio/netbird/client/internal/routeselector"
 	"github.com/netbirdio/netbird/client/proto"
 	"github.com/netbirdio/netbird/route"
 )
@@ -33,12 +35,18 @@ const (
 	reasonPeerUpdate
 	reasonShutdown
 	reasonHA
+	reasonPolicy
 )
 
+// pinnedExitNodeBonus outweighs every other score component, so a pinned exit node wins as long as it is reachable.
+const pinnedExitNodeBonus = 1_000_000
+
 type routerPeerStatus struct {
-	status  peer.ConnStatus
-	relayed bool
-	latency time.Duration
+	status      peer.ConnStatus
+	relayed     bool
+	latency     time.Duration
+	maintenance bool
+	unhealthy   bool
 }
 
 type RoutesUpdate struct {
@@ -62,6 +70,17 @@ type WatcherConfig struct {
 	StatusRecorder   *peer.Status
 	Route            *route.Route
 	Handler          RouteHandler
+	RouteSelector    *routeselector.RouteSelector
+	// OnFailover is called when the traffic of the network moved from one routing peer to another
+	OnFailover func(newRoute *route.Route)
+	// ExitNodeFailsafe is how long the exit node of a default route may be unreachable before the route is
+	// removed, zero disables it. It requires a SuspendableHandler.
+	ExitNodeFailsafe time.Duration
+	// DisconnectGrace is how long the route of a disconnected routing peer stays installed before it fails over
+	// or is removed, zero disables it
+	DisconnectGrace time.Duration
+	// EventBus receives the route changes
+	EventBus *eventbus.Bus
 }
 
 // Watcher watches route and peer changes and updates allowed IPs accordingly.
@@ -75,11 +94,25 @@ type Watcher struct {
 	routes              map[route.ID]*route.Route
 	routeUpdate         chan RoutesUpdate
 	peerStateUpdate     chan map[string]peer.RouterState
+	policyUpdate        chan struct{}
 	routePeersNotifiers map[string]chan struct{} // map of peer key to channel for peer state changes
 	currentChosen       *route.Route
 	currentChosenStatus *routerPeerStatus
 	handler             RouteHandler
+	routeSelector       *routeselector.RouteSelector
 	updateSerial        uint64
+	dial                dialFunc
+	wgStats             wgStater
+	probePort           string
+	probeUpdate         chan []probeResult
+	peerHealth          map[string]*peerHealth
+	probing             bool
+	onFailover          func(newRoute *route.Route)
+	failsafe            *exitNodeFailsafe
+	disconnectGrace     time.Duration
+	// disconnectedSince is when the chosen routing peer disconnected, zero while it is connected
+	disconnectedSince time.Time
+	eventBus          *eventbus.Bus
 }
 
 func NewWatcher(config WatcherConfig) *Watcher {
@@ -94,8 +127,19 @@ func NewWatcher(config WatcherConfig) *Watcher {
 		routePeersNotifiers: make(map[string]chan struct{}),
 		routeUpdate:         make(chan RoutesUpdate),
 		peerStateUpdate:     make(chan map[string]peer.RouterState),
+		policyUpdate:        make(chan struct{}, 1),
 		handler:             config.Handler,
+		routeSelector:       config.RouteSelector,
 		currentChosenStatus: nil,
+		dial:                probeDialer(config.WGInterface),
+		wgStats:             probeStater(config.WGInterface),
+		probePort:           routeProbePort(),
+		probeUpdate:         make(chan []probeResult),
+		peerHealth:          make(map[string]*peerHealth),
+		onFailover:          config.OnFailover,
+		failsafe:            newExitNodeFailsafe(config.ExitNodeFailsafe, config.Handler),
+		disconnectGrace:     config.DisconnectGrace,
+		eventBus:            config.EventBus,
 	}
 	return client
 }
@@ -109,9 +153,11 @@ func (w *Watcher) getRouterPeerStatuses() map[route.ID]routerPeerStatus {
 			continue
 		}
 		routePeerStatuses[r.ID] = routerPeerStatus{
-			status:  peerStatus.ConnStatus,
-			relayed: peerStatus.Relayed,
-			latency: peerStatus.Latency,
+			status:      peerStatus.ConnStatus,
+			relayed:     peerStatus.Relayed,
+			latency:     peerStatus.Latency,
+			maintenance: peerStatus.Maintenance,
+			unhealthy:   w.isPeerUnhealthy(r.Peer),
 		}
 	}
 	return routePeerStatuses
@@ -126,14 +172,30 @@ func (w *Watcher) convertRouterPeerStatuses(states map[string]peer.RouterState)
 			continue
 		}
 		routePeerStatuses[r.ID] = routerPeerStatus{
-			status:  peerStatus.Status,
-			relayed: peerStatus.Relayed,
-			latency: peerStatus.Latency,
+			status:      peerStatus.Status,
+			relayed:     peerStatus.Relayed,
+			latency:     peerStatus.Latency,
+			maintenance: peerStatus.Maintenance,
+			unhealthy:   w.isPeerUnhealthy(r.Peer),
 		}
 	}
 	return routePeerStatuses
 }
 
+// hasConnectedRoutingPeer reports whether a routing peer other than the excluded one is connected and usable
+func (w *Watcher) hasConnectedRoutingPeer(routePeerStatuses map[route.ID]routerPeerStatus, excludedPeer string) bool {
+	for _, r := range w.routes {
+		if r.Peer == excludedPeer {
+			continue
+		}
+		status, found := routePeerStatuses[r.ID]
+		if found && status.status == peer.StatusConnected && !status.maintenance && !status.unhealthy {
+			return true
+		}
+	}
+	return false
+}
+
 // getBestRouteFromStatuses determines the most optimal route from the available routes
 // within a Watcher, taking into account peer connection status, route metrics, and
 // preference for non-relayed and direct connections.
@@ -144,6 +206,9 @@ func (w *Watcher) convertRouterPeerStatuses(states map[string]peer.RouterState)
 // * Metric: Routes with lower metrics (better) are prioritized.
 // * Non-relayed: Routes without relays are preferred.
 // * Latency: Routes with lower latency are prioritized.
+// * Pinned exit node: A reachable exit node route via the pinned peer always wins, otherwise the other rules apply.
+// * Maintenance: Peers that announced maintenance rank like idle peers and lose their pin, they are only used without alternatives.
+// * Liveness: Peers of an HA group without a recent WireGuard handshake, or failing the opt-in TCP probes, are demoted the same way.
 // * Allowed IPs: Idle peers can still receive allowed IPs to enable lazy connection triggering.
 // * we compare the current score + 10ms to the chosen score to avoid flapping between routes
 // * Stability: In case of equal scores, the currently active route (if any) is maintained.
@@ -161,6 +226,10 @@ func (w *Watcher) getBestRouteFromStatuses(routePeerStatuses map[route.ID]router
 
 	var chosenStatus routerPeerStatus
 
+	pinnedPeer := w.pinnedExitNode()
+	// idle also covers an offline peer, an idle pinned peer is only preferred while no other peer is connected
+	connectedAlternative := w.hasConnectedRoutingPeer(routePeerStatuses, pinnedPeer)
+
 	for _, r := range w.routes {
 		tempScore := float64(0)
 		peerStatus, found := routePeerStatuses[r.ID]
@@ -191,7 +260,7 @@ func (w *Watcher) getBestRouteFromStatuses(routePeerStatuses map[route.ID]router
 		tempScore += 1 - latency.Seconds()
 
 		// apply significant penalty for idle peers to ensure connected peers always take precedence
-		if peerStatus.status == peer.StatusConnected {
+		if peerStatus.status == peer.StatusConnected && !peerStatus.maintenance && !peerStatus.unhealthy {
 			tempScore += 100_000
 		}
 
@@ -199,6 +268,11 @@ func (w *Watcher) getBestRouteFromStatuses(routePeerStatuses map[route.ID]router
 			tempScore++
 		}
 
+		if pinnedPeer != "" && r.Peer == pinnedPeer && r.Network.Bits() == 0 && !peerStatus.maintenance && !peerStatus.unhealthy &&
+			(peerStatus.status == peer.StatusConnected || !connectedAlternative) {
+			tempScore += pinnedExitNodeBonus
+		}
+
 		if tempScore > chosenScore || (tempScore == chosenScore && chosen == "") {
 			chosen = r.ID
 			chosenStatus = peerStatus
@@ -252,6 +326,14 @@ func (w *Watcher) getBestRouteFromStatuses(routePeerStatuses map[route.ID]router
 	return chosen, chosenStatus
 }
 
+// pinnedExitNode returns the peer the user pinned for exit node routes, or an empty string in auto mode.
+func (w *Watcher) pinnedExitNode() string {
+	if w.routeSelector == nil {
+		return ""
+	}
+	return w.routeSelector.PinnedExitNode()
+}
+
 func (w *Watcher) watchPeerStatusChanges(ctx context.Context, peerKey string, peerStateUpdate chan map[string]peer.RouterState, closer chan struct{}) {
 	subscription := w.statusRecorder.SubscribeToPeerStateChanges(ctx, peerKey)
 	defer w.statusRecorder.UnsubscribePeerStateChanges(subscription)
@@ -287,18 +369,14 @@ func (w *Watcher) addAllowedIPs(route *route.Route) error {
 		return fmt.Errorf("add allowed IPs for peer %s: %w", route.Peer, err)
 	}
 
-	if err := w.statusRecorder.AddPeerStateRoute(route.Peer, w.handler.String(), route.GetResourceID()); err != nil {
-		log.Warnf("Failed to update peer state: %v", err)
-	}
+	w.eventBus.Publish(eventbus.RouteChanged{Network: w.handler.String(), ResourceID: route.GetResourceID(), Peer: route.Peer})
 
 	w.connectEvent(route)
 	return nil
 }
 
 func (w *Watcher) removeAllowedIPs(route *route.Route, rsn reason) error {
-	if err := w.statusRecorder.RemovePeerStateRoute(route.Peer, w.handler.String()); err != nil {
-		log.Warnf("Failed to update peer state: %v", err)
-	}
+	w.eventBus.Publish(eventbus.RouteChanged{Network: w.handler.String(), ResourceID: route.GetResourceID(), Peer: route.Peer, Removed: true})
 
 	if err := w.handler.RemoveAllowedIPs(); err != nil {
 		return fmt.Errorf("remove allowed IPs: %w", err)
@@ -328,6 +406,10 @@ func (w *Watcher) shouldSkipRecalculation(newChosenID route.ID, newStatus router
 }
 
 func (w *Watcher) recalculateRoutes(rsn reason, routerPeerStatuses map[route.ID]routerPeerStatus) error {
+	if w.holdDisconnectedRoute(rsn, routerPeerStatuses, time.Now()) {
+		return nil
+	}
+
 	newChosenID, newStatus := w.getBestRouteFromStatuses(routerPeerStatuses)
 
 	// If no route is chosen, remove the route from the peer
@@ -353,7 +435,11 @@ func (w *Watcher) recalculateRoutes(rsn reason, routerPeerStatuses map[route.ID]
 
 	// If the chosen route was assigned to a different peer, remove the allowed IPs first
 	if isNew := w.currentChosen == nil; !isNew {
-		if err := w.removeAllowedIPs(w.currentChosen, reasonHA); err != nil {
+		switchReason := reasonHA
+		if rsn == reasonPolicy {
+			switchReason = reasonPolicy
+		}
+		if err := w.removeAllowedIPs(w.currentChosen, switchReason); err != nil {
 			return fmt.Errorf("remove old: %w", err)
 		}
 	}
@@ -366,9 +452,14 @@ func (w *Watcher) recalculateRoutes(rsn reason, routerPeerStatuses map[route.ID]
 		w.connectEvent(newChosenRoute)
 	}
 
+	previous := w.currentChosen
 	w.currentChosen = newChosenRoute
 	w.currentChosenStatus = &newStatus
 
+	if previous != nil && previous.Peer != newChosenRoute.Peer && !newChosenRoute.IsDynamic() && w.onFailover != nil {
+		w.onFailover(newChosenRoute)
+	}
+
 	return nil
 }
 
@@ -387,12 +478,14 @@ func (w *Watcher) connectEvent(route *route.Route) {
 
 	meta := map[string]string{
 		"network": w.handler.String(),
+		"state":   "connected",
 	}
 	if route != nil {
 		meta["id"] = string(route.NetID)
 		meta["peer"] = route.Peer
 	}
-	w.statusRecorder.PublishEvent(
+	w.statusRecorder.PublishLifecycleEvent(
+		peer.EventExitNodeChanged,
 		proto.SystemEvent_INFO,
 		proto.SystemEvent_NETWORK,
 		"Default route added",
@@ -424,6 +517,7 @@ func (w *Watcher) disconnectEvent(route *route.Route, rsn reason) {
 		meta["peer"] = route.Peer
 	}
 	meta["network"] = w.handler.String()
+	meta["state"] = "disconnected"
 	switch rsn {
 	case reasonShutdown:
 		severity = proto.SystemEvent_INFO
@@ -440,13 +534,18 @@ func (w *Watcher) disconnectEvent(route *route.Route, rsn reason) {
 		severity = proto.SystemEvent_INFO
 		message = "Default route disconnected due to high availability change"
 		userMessage = "Exit node disconnected due to high availability change."
+	case reasonPolicy:
+		severity = proto.SystemEvent_INFO
+		message = "Default route changed due to exit node selection policy"
+		userMessage = "Exit node switched due to exit node selection change."
 	default:
 		severity = proto.SystemEvent_ERROR
 		message = "Default route disconnected for unknown reasons"
 		userMessage = "Exit node disconnected for unknown reasons."
 	}
 
-	w.statusRecorder.PublishEvent(
+	w.statusRecorder.PublishLifecycleEvent(
+		peer.EventExitNodeChanged,
 		severity,
 		proto.SystemEvent_NETWORK,
 		message,
@@ -464,6 +563,14 @@ func (w *Watcher) SendUpdate(update RoutesUpdate) {
 	}()
 }
 
+// TriggerRecalculation makes the watcher re-evaluate the chosen route, e.g. after the exit node policy changed.
+func (w *Watcher) TriggerRecalculation() {
+	select {
+	case w.policyUpdate <- struct{}{}:
+	default:
+	}
+}
+
 func (w *Watcher) classifyUpdate(update RoutesUpdate) bool {
 	isUpdateMapDifferent := false
 	updateMap := make(map[route.ID]*route.Route)
@@ -496,15 +603,54 @@ func (w *Watcher) classifyUpdate(update RoutesUpdate) bool {
 // Start is the main point of reacting on client network routing events.
 // All the processing related to the client network should be done here. Thread-safe.
 func (w *Watcher) Start() {
+	var probeTick <-chan time.Time
+	if !routeProbesDisabled() {
+		probeTicker := time.NewTicker(probeInterval)
+		defer probeTicker.Stop()
+		probeTick = probeTicker.C
+	}
+
+	var failsafeTick <-chan time.Time
+	if w.failsafe != nil {
+		failsafeTicker := time.NewTicker(failsafeCheckInterval)
+		defer failsafeTicker.Stop()
+		failsafeTick = failsafeTicker.C
+	}
+
+	var graceTick <-chan time.Time
+	if w.disconnectGrace > 0 {
+		graceTicker := time.NewTicker(graceCheckInterval)
+		defer graceTicker.Stop()
+		graceTick = graceTicker.C
+	}
+
 	for {
 		select {
 		case <-w.ctx.Done():
 			return
+		case <-probeTick:
+			w.startProbes()
+		case now := <-failsafeTick:
+			w.checkFailsafe(now)
+		case now := <-graceTick:
+			w.checkDisconnectGrace(now)
+		case results := <-w.probeUpdate:
+			w.probing = false
+			if !w.handleProbeResults(results) {
+				continue
+			}
+			if err := w.recalculateRoutes(reasonHA, w.getRouterPeerStatuses()); err != nil {
+				log.Errorf("Failed to recalculate routes for network [%v]: %v", w.handler, err)
+			}
 		case routersStates := <-w.peerStateUpdate:
 			routerPeerStatuses := w.convertRouterPeerStatuses(routersStates)
 			if err := w.recalculateRoutes(reasonPeerUpdate, routerPeerStatuses); err != nil {
 				log.Errorf("Failed to recalculate routes for network [%v]: %v", w.handler, err)
 			}
+		case <-w.policyUpdate:
+			if err := w.recalculateRoutes(reasonPolicy, w.getRouterPeerStatuses()); err != nil {
+				log.Errorf("Failed to recalculate routes for network [%v]: %v", w.handler, err)
+			}
 		case update := <-w.routeUpdate:
 			if update.UpdateSerial < w.updateSerial {
 				log.Warnf("Received a routes update with smaller serial number (%d -> %d), ignoring it", w.updateSerial, update.UpdateSerial)
@@ -541,6 +687,7 @@ func (w *Watcher) handleRouteUpdate(update RoutesUpdate) {
 func (w *Watcher) Stop() {
 	log.Debugf("Stopping watcher for network [%v]", w.handler)
 
+	w.stopFailsafe()
 	w.cancel()
 
 	if w.currentChosen == nil {
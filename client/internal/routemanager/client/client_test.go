@@ -9,6 +9,7 @@ import (
 	"github.com/netbirdio/netbird/client/internal/peer"
 	"github.com/netbirdio/netbird/client/internal/routemanager/common"
 	"github.com/netbirdio/netbird/client/internal/routemanager/static"
+	"github.com/netbirdio/netbird/client/internal/routeselector"
 	"github.com/netbirdio/netbird/route"
 )
 
@@ -828,3 +829,127 @@ func TestGetBestrouteFromStatuses(t *testing.T) {
 		})
 	}
 }
+
+func TestGetBestRouteFromStatuses_PinnedExitNode(t *testing.T) {
+	exitNode := netip.MustParsePrefix("0.0.0.0/0")
+	routes := map[route.ID]*route.Route{
+		"route1": {ID: "route1", Network: exitNode, Metric: route.MaxMetric, Peer: "peer1"},
+		"route2": {ID: "route2", Network: exitNode, Metric: route.MaxMetric, Peer: "peer2"},
+	}
+
+	testCases := []struct {
+		name            string
+		pinned          string
+		statuses        map[route.ID]routerPeerStatus
+		expectedRouteID route.ID
+	}{
+		{
+			name: "auto picks lowest latency",
+			statuses: map[route.ID]routerPeerStatus{
+				"route1": {status: peer.StatusConnected, latency: 15 * time.Millisecond},
+				"route2": {status: peer.StatusConnected, latency: 200 * time.Millisecond},
+			},
+			expectedRouteID: "route1",
+		},
+		{
+			name:   "pinned peer wins over lower latency",
+			pinned: "peer2",
+			statuses: map[route.ID]routerPeerStatus{
+				"route1": {status: peer.StatusConnected, latency: 15 * time.Millisecond},
+				"route2": {status: peer.StatusConnected, relayed: true, latency: 200 * time.Millisecond},
+			},
+			expectedRouteID: "route2",
+		},
+		{
+			name:   "pinned peer unreachable fails over",
+			pinned: "peer2",
+			statuses: map[route.ID]routerPeerStatus{
+				"route1": {status: peer.StatusConnected, latency: 15 * time.Millisecond},
+				"route2": {status: peer.StatusConnecting},
+			},
+			expectedRouteID: "route1",
+		},
+		{
+			name:   "idle pinned peer fails over to a connected peer",
+			pinned: "peer2",
+			statuses: map[route.ID]routerPeerStatus{
+				"route1": {status: peer.StatusConnected, latency: 15 * time.Millisecond},
+				"route2": {status: peer.StatusIdle},
+			},
+			expectedRouteID: "route1",
+		},
+		{
+			name:   "idle pinned peer wins over idle peers",
+			pinned: "peer2",
+			statuses: map[route.ID]routerPeerStatus{
+				"route1": {status: peer.StatusIdle},
+				"route2": {status: peer.StatusIdle},
+			},
+			expectedRouteID: "route2",
+		},
+		{
+			name: "peer in maintenance is avoided",
+			statuses: map[route.ID]routerPeerStatus{
+				"route1": {status: peer.StatusConnected, latency: 15 * time.Millisecond, maintenance: true},
+				"route2": {status: peer.StatusConnected, relayed: true, latency: 200 * time.Millisecond},
+			},
+			expectedRouteID: "route2",
+		},
+		{
+			name:   "pinned peer in maintenance is avoided",
+			pinned: "peer1",
+			statuses: map[route.ID]routerPeerStatus{
+				"route1": {status: peer.StatusConnected, latency: 15 * time.Millisecond, maintenance: true},
+				"route2": {status: peer.StatusConnected, latency: 200 * time.Millisecond},
+			},
+			expectedRouteID: "route2",
+		},
+		{
+			name: "peer in maintenance is used without alternatives",
+			statuses: map[route.ID]routerPeerStatus{
+				"route1": {status: peer.StatusConnected, latency: 15 * time.Millisecond, maintenance: true},
+				"route2": {status: peer.StatusConnecting},
+			},
+			expectedRouteID: "route1",
+		},
+		{
+			name:   "pinned peer failing liveness probes is avoided",
+			pinned: "peer1",
+			statuses: map[route.ID]routerPeerStatus{
+				"route1": {status: peer.StatusConnected, latency: 15 * time.Millisecond, unhealthy: true},
+				"route2": {status: peer.StatusConnected, relayed: true, latency: 200 * time.Millisecond},
+			},
+			expectedRouteID: "route2",
+		},
+		{
+			name:   "unknown pinned peer keeps auto",
+			pinned: "peer3",
+			statuses: map[route.ID]routerPeerStatus{
+				"route1": {status: peer.StatusConnected, latency: 200 * time.Millisecond},
+				"route2": {status: peer.StatusConnected, latency: 15 * time.Millisecond},
+			},
+			expectedRouteID: "route2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			selector := routeselector.NewRouteSelector()
+			selector.PinExitNode(tc.pinned)
+
+			params := common.HandlerParams{
+				Route: &route.Route{Network: exitNode},
+			}
+			client := &Watcher{
+				handler:       static.NewRoute(params),
+				routes:        routes,
+				routeSelector: selector,
+			}
+
+			chosenRoute, _ := client.getBestRouteFromStatuses(tc.statuses)
+			if chosenRoute != tc.expectedRouteID {
+				t.Errorf("expected routeID %s, got %s", tc.expectedRouteID, chosenRoute)
+			}
+		})
+	}
+}
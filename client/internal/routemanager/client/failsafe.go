@@ -0,0 +1,242 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/proto"
+	"github.com/netbirdio/netbird/route"
+)
+
+// failsafeCheckInterval is how often the watcher checks if the exit node is reachable
+const failsafeCheckInterval = time.Second
+
+// SuspendableHandler is a route handler whose system route can be taken down temporarily without the route
+// manager noticing. The exit node failsafe uses it to send the traffic through the local internet connection
+// while no exit node is reachable.
+type SuspendableHandler struct {
+	RouteHandler
+
+	mu        sync.Mutex
+	added     bool
+	suspended bool
+}
+
+// NewSuspendableHandler wraps the handler
+func NewSuspendableHandler(handler RouteHandler) *SuspendableHandler {
+	return &SuspendableHandler{RouteHandler: handler}
+}
+
+// AddRoute adds the system route unless it is suspended, it is added once resumed in that case
+func (h *SuspendableHandler) AddRoute(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.suspended {
+		if err := h.RouteHandler.AddRoute(ctx); err != nil {
+			return err
+		}
+	}
+	h.added = true
+	return nil
+}
+
+// RemoveRoute removes the system route if it isn't already removed by a suspension
+func (h *SuspendableHandler) RemoveRoute() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.added && !h.suspended {
+		if err := h.RouteHandler.RemoveRoute(); err != nil {
+			return err
+		}
+	}
+	h.added = false
+	return nil
+}
+
+// Suspend removes the system route until Resume is called
+func (h *SuspendableHandler) Suspend() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.suspended {
+		return nil
+	}
+	if h.added {
+		if err := h.RouteHandler.RemoveRoute(); err != nil {
+			return err
+		}
+	}
+	h.suspended = true
+	return nil
+}
+
+// Resume adds the system route back if the route manager still wants it
+func (h *SuspendableHandler) Resume(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.suspended {
+		return nil
+	}
+	if h.added {
+		if err := h.RouteHandler.AddRoute(ctx); err != nil {
+			return err
+		}
+	}
+	h.suspended = false
+	return nil
+}
+
+// exitNodeFailsafe tracks how long the exit node of a default route has been unreachable
+type exitNodeFailsafe struct {
+	timeout          time.Duration
+	handler          *SuspendableHandler
+	unreachableSince time.Time
+	active           bool
+}
+
+func newExitNodeFailsafe(timeout time.Duration, handler RouteHandler) *exitNodeFailsafe {
+	if timeout <= 0 {
+		return nil
+	}
+	suspendable, ok := handler.(*SuspendableHandler)
+	if !ok {
+		return nil
+	}
+	return &exitNodeFailsafe{
+		timeout: timeout,
+		handler: suspendable,
+	}
+}
+
+// failsafeEnabled reports whether the watcher removes the default route while the exit node is unreachable
+func (w *Watcher) failsafeEnabled() bool {
+	if w.failsafe == nil {
+		return false
+	}
+	for _, r := range w.routes {
+		if isDefaultRoute(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// exitNodeReachable reports whether the chosen routing peer can carry traffic. Idle peers count as reachable,
+// the traffic of the default route wakes them up.
+func (w *Watcher) exitNodeReachable() bool {
+	if w.currentChosen == nil {
+		return false
+	}
+
+	state, err := w.statusRecorder.GetPeer(w.currentChosen.Peer)
+	if err != nil {
+		return false
+	}
+
+	switch state.ConnStatus {
+	case peer.StatusConnected:
+		return !w.isPeerUnhealthy(w.currentChosen.Peer)
+	case peer.StatusIdle:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkFailsafe removes the default route once the exit node was unreachable for the failsafe timeout and
+// restores it when the exit node, or another one of the group, is reachable again
+func (w *Watcher) checkFailsafe(now time.Time) {
+	if !w.failsafeEnabled() {
+		return
+	}
+	fs := w.failsafe
+
+	if w.exitNodeReachable() {
+		fs.unreachableSince = time.Time{}
+		if !fs.active {
+			return
+		}
+
+		if err := fs.handler.Resume(w.ctx); err != nil {
+			log.Errorf("failed to restore the default route for network [%v]: %v", w.handler, err)
+			return
+		}
+		fs.active = false
+		log.Infof("exit node for network [%v] is reachable again, restored the default route", w.handler)
+		w.failsafeEvent(false)
+		return
+	}
+
+	if fs.unreachableSince.IsZero() {
+		fs.unreachableSince = now
+		return
+	}
+	if fs.active || now.Sub(fs.unreachableSince) < fs.timeout {
+		return
+	}
+
+	if err := fs.handler.Suspend(); err != nil {
+		log.Errorf("failed to remove the default route for network [%v]: %v", w.handler, err)
+		return
+	}
+	fs.active = true
+	log.Warnf("exit node for network [%v] is unreachable for %s, removed the default route", w.handler, now.Sub(fs.unreachableSince).Round(time.Second))
+	w.failsafeEvent(true)
+}
+
+func (w *Watcher) failsafeEvent(active bool) {
+	meta := map[string]string{
+		"network": w.handler.String(),
+	}
+	if w.currentChosen != nil {
+		meta["id"] = string(w.currentChosen.NetID)
+		meta["peer"] = w.currentChosen.Peer
+	}
+
+	if active {
+		meta["state"] = "failsafe"
+		w.statusRecorder.PublishLifecycleEvent(
+			peer.EventExitNodeChanged,
+			proto.SystemEvent_WARNING,
+			proto.SystemEvent_NETWORK,
+			fmt.Sprintf("Default route removed, the exit node was unreachable for %s", w.failsafe.timeout),
+			"Exit node unreachable. Your internet traffic uses your local connection until it recovers.",
+			meta,
+		)
+		return
+	}
+
+	meta["state"] = "restored"
+	w.statusRecorder.PublishLifecycleEvent(
+		peer.EventExitNodeChanged,
+		proto.SystemEvent_INFO,
+		proto.SystemEvent_NETWORK,
+		"Default route restored, the exit node is reachable again",
+		"Exit node recovered. Your internet traffic uses the exit node again.",
+		meta,
+	)
+}
+
+// stopFailsafe restores a default route the failsafe removed, so the route manager finds it as it left it
+func (w *Watcher) stopFailsafe() {
+	if w.failsafe == nil || !w.failsafe.active {
+		return
+	}
+	if err := w.failsafe.handler.Resume(w.ctx); err != nil {
+		log.Errorf("failed to restore the default route for network [%v]: %v", w.handler, err)
+	}
+	w.failsafe.active = false
+}
+
+// isDefaultRoute reports whether the route is a static default route, the only kind the failsafe handles
+func isDefaultRoute(r *route.Route) bool {
+	return r != nil && !r.IsDynamic() && r.Network.Bits() == 0
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/route"
+)
+
+type countingRouteHandler struct {
+	mockRouteHandler
+	routes int
+}
+
+func (h *countingRouteHandler) AddRoute(context.Context) error {
+	h.routes++
+	return nil
+}
+
+func (h *countingRouteHandler) RemoveRoute() error {
+	h.routes--
+	return nil
+}
+
+func TestSuspendableHandler(t *testing.T) {
+	inner := &countingRouteHandler{}
+	h := NewSuspendableHandler(inner)
+
+	require.NoError(t, h.AddRoute(context.Background()))
+	assert.Equal(t, 1, inner.routes)
+
+	require.NoError(t, h.Suspend())
+	assert.Equal(t, 0, inner.routes)
+
+	// the route manager removing the route while it is suspended must not remove it twice
+	require.NoError(t, h.RemoveRoute())
+	assert.Equal(t, 0, inner.routes)
+
+	require.NoError(t, h.Resume(context.Background()))
+	assert.Equal(t, 0, inner.routes, "route removed by the manager must not come back")
+
+	require.NoError(t, h.AddRoute(context.Background()))
+	require.NoError(t, h.Suspend())
+	require.NoError(t, h.Resume(context.Background()))
+	assert.Equal(t, 1, inner.routes)
+
+	require.NoError(t, h.RemoveRoute())
+	assert.Equal(t, 0, inner.routes)
+}
+
+func TestWatcher_CheckFailsafe(t *testing.T) {
+	recorder := peer.NewRecorder("https://mgm")
+	require.NoError(t, recorder.AddPeer("exit-node", "exit.netbird.cloud", "100.64.0.2"))
+
+	inner := &countingRouteHandler{mockRouteHandler: mockRouteHandler{network: "0.0.0.0/0"}}
+	handler := NewSuspendableHandler(inner)
+	require.NoError(t, handler.AddRoute(context.Background()))
+
+	exitRoute := &route.Route{ID: "exit", NetID: "exit", Network: netip.MustParsePrefix("0.0.0.0/0"), Peer: "exit-node"}
+	w := &Watcher{
+		ctx:            context.Background(),
+		statusRecorder: recorder,
+		handler:        handler,
+		routes:         map[route.ID]*route.Route{exitRoute.ID: exitRoute},
+		peerHealth:     make(map[string]*peerHealth),
+		currentChosen:  exitRoute,
+		failsafe:       newExitNodeFailsafe(30*time.Second, handler),
+	}
+	require.NotNil(t, w.failsafe)
+
+	setStatus := func(status peer.ConnStatus) {
+		require.NoError(t, recorder.UpdatePeerState(peer.State{PubKey: "exit-node", ConnStatus: status, ConnStatusUpdate: time.Now()}))
+	}
+
+	now := time.Now()
+	setStatus(peer.StatusConnecting)
+
+	w.checkFailsafe(now)
+	w.checkFailsafe(now.Add(29 * time.Second))
+	assert.Equal(t, 1, inner.routes, "route must stay within the failsafe timeout")
+	assert.False(t, w.failsafe.active)
+
+	w.checkFailsafe(now.Add(30 * time.Second))
+	assert.Equal(t, 0, inner.routes, "route must be removed after the failsafe timeout")
+	assert.True(t, w.failsafe.active)
+
+	setStatus(peer.StatusConnected)
+	w.checkFailsafe(now.Add(31 * time.Second))
+	assert.Equal(t, 1, inner.routes, "route must be restored once the exit node is reachable")
+	assert.False(t, w.failsafe.active)
+
+	// an exit node that is connected but fails its liveness probes is unreachable as well
+	w.peerHealth["exit-node"] = &peerHealth{unhealthy: true}
+	w.checkFailsafe(now.Add(40 * time.Second))
+	w.checkFailsafe(now.Add(70 * time.Second))
+	assert.True(t, w.failsafe.active)
+
+	w.stopFailsafe()
+	assert.Equal(t, 1, inner.routes, "stopping the watcher must hand the route back to the manager")
+}
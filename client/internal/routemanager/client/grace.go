@@ -0,0 +1,55 @@
+package client
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/route"
+)
+
+// graceCheckInterval is how often the watcher checks if the disconnect grace period of the chosen routing peer ended
+const graceCheckInterval = time.Second
+
+// holdDisconnectedRoute reports whether the route of the chosen routing peer stays installed although the peer
+// disconnected. During the grace period the route neither fails over nor is removed, so short relay hiccups don't
+// make the routes flap. The traffic keeps going to the WireGuard interface and is dropped there instead of leaking
+// through another route.
+func (w *Watcher) holdDisconnectedRoute(rsn reason, statuses map[route.ID]routerPeerStatus, now time.Time) bool {
+	if w.disconnectGrace <= 0 || w.currentChosen == nil || rsn != reasonPeerUpdate {
+		w.disconnectedSince = time.Time{}
+		return false
+	}
+
+	if status, ok := statuses[w.currentChosen.ID]; ok && status.status != peer.StatusConnecting {
+		w.disconnectedSince = time.Time{}
+		return false
+	}
+
+	if w.disconnectedSince.IsZero() {
+		w.disconnectedSince = now
+		log.Infof("routing peer %s for network [%v] disconnected, keeping its route for %s",
+			w.currentChosen.Peer, w.handler, w.disconnectGrace)
+	}
+
+	if now.Sub(w.disconnectedSince) < w.disconnectGrace {
+		return true
+	}
+
+	log.Infof("routing peer %s for network [%v] is disconnected for longer than %s, recalculating the route",
+		w.currentChosen.Peer, w.handler, w.disconnectGrace)
+	w.disconnectedSince = time.Time{}
+	return false
+}
+
+// checkDisconnectGrace recalculates the route once the grace period of the disconnected routing peer ended
+func (w *Watcher) checkDisconnectGrace(now time.Time) {
+	if w.disconnectedSince.IsZero() || now.Sub(w.disconnectedSince) < w.disconnectGrace {
+		return
+	}
+
+	if err := w.recalculateRoutes(reasonPeerUpdate, w.getRouterPeerStatuses()); err != nil {
+		log.Errorf("Failed to recalculate routes for network [%v]: %v", w.handler, err)
+	}
+}
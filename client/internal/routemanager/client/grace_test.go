@@ -0,0 +1,47 @@
+package client
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/route"
+)
+
+func TestWatcher_HoldDisconnectedRoute(t *testing.T) {
+	siteRoute := &route.Route{ID: "site", NetID: "site", Network: netip.MustParsePrefix("10.0.0.0/24"), Peer: "router"}
+	w := &Watcher{
+		handler:         &mockRouteHandler{network: "10.0.0.0/24"},
+		routes:          map[route.ID]*route.Route{siteRoute.ID: siteRoute},
+		currentChosen:   siteRoute,
+		disconnectGrace: 30 * time.Second,
+	}
+
+	connected := map[route.ID]routerPeerStatus{siteRoute.ID: {status: peer.StatusConnected}}
+	disconnected := map[route.ID]routerPeerStatus{siteRoute.ID: {status: peer.StatusConnecting}}
+	now := time.Now()
+
+	assert.False(t, w.holdDisconnectedRoute(reasonPeerUpdate, connected, now), "connected peers need no grace")
+	assert.True(t, w.disconnectedSince.IsZero())
+
+	assert.True(t, w.holdDisconnectedRoute(reasonPeerUpdate, disconnected, now))
+	assert.True(t, w.holdDisconnectedRoute(reasonPeerUpdate, disconnected, now.Add(20*time.Second)))
+
+	// reconnecting within the grace period resets it
+	assert.False(t, w.holdDisconnectedRoute(reasonPeerUpdate, connected, now.Add(25*time.Second)))
+	assert.True(t, w.disconnectedSince.IsZero())
+
+	assert.True(t, w.holdDisconnectedRoute(reasonPeerUpdate, disconnected, now.Add(40*time.Second)))
+	assert.True(t, w.holdDisconnectedRoute(reasonPeerUpdate, map[route.ID]routerPeerStatus{}, now.Add(50*time.Second)),
+		"a peer without a state counts as disconnected")
+	assert.False(t, w.holdDisconnectedRoute(reasonPeerUpdate, disconnected, now.Add(70*time.Second)), "grace period ended")
+	assert.True(t, w.disconnectedSince.IsZero())
+
+	assert.False(t, w.holdDisconnectedRoute(reasonRouteUpdate, disconnected, now), "route updates apply right away")
+
+	w.disconnectGrace = 0
+	assert.False(t, w.holdDisconnectedRoute(reasonPeerUpdate, disconnected, now), "zero disables the grace period")
+}
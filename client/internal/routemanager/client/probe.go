@@ -0,0 +1,309 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.zx2c4.com/wireguard/tun/netstack"
+
+	"github.com/netbirdio/netbird/client/iface/configurer"
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+const (
+	probeInterval = 10 * time.Second
+	probeTimeout  = 3 * time.Second
+	// maxHandshakeAge is the age of the last WireGuard handshake after which the session of a routing peer is
+	// considered dead. WireGuard renews the session every two minutes and rejects it after three.
+	maxHandshakeAge = 3 * time.Minute
+	// probeFailureThreshold is the number of consecutive failed probes after which a routing peer is demoted
+	probeFailureThreshold = 3
+
+	envDisableRouteProbes = "NB_DISABLE_ROUTE_PROBES"
+	// envRouteProbePort enables TCP probes to the port on the routing peer's tunnel IP in addition to the handshake
+	// check. A refused connection still proves that the peer is alive, but ACLs dropping the port demote the peer.
+	envRouteProbePort = "NB_ROUTE_PROBE_PORT"
+)
+
+type dialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+type wgStater interface {
+	GetStats() (map[string]configurer.WGStats, error)
+}
+
+type probeTarget struct {
+	peerKey string
+	ip      string
+	latency time.Duration
+}
+
+type probeResult struct {
+	peerKey string
+	healthy bool
+	rtt     time.Duration
+}
+
+// peerHealth tracks the liveness probe results of a routing peer
+type peerHealth struct {
+	failures  int
+	unhealthy bool
+	rtt       time.Duration
+}
+
+func routeProbesDisabled() bool {
+	return os.Getenv(envDisableRouteProbes) == "true"
+}
+
+// routeProbePort returns the port of the opt-in TCP probes, empty if they are disabled
+func routeProbePort() string {
+	val := os.Getenv(envRouteProbePort)
+	if val == "" {
+		return ""
+	}
+	if _, err := strconv.ParseUint(val, 10, 16); err != nil {
+		log.Warnf("invalid %s %q, TCP route probes are disabled: %v", envRouteProbePort, val, err)
+		return ""
+	}
+	return val
+}
+
+// probeStater returns the WireGuard stats of the interface for the handshake check, nil if it doesn't provide them
+func probeStater(wgInterface any) wgStater {
+	stater, ok := wgInterface.(wgStater)
+	if !ok {
+		return nil
+	}
+	return stater
+}
+
+// probeDialer returns a dialer that reaches peers through the tunnel, using netstack if the interface runs in netstack mode.
+func probeDialer(wgInterface any) dialFunc {
+	if nsIface, ok := wgInterface.(interface{ GetNet() *netstack.Net }); ok {
+		if nsNet := nsIface.GetNet(); nsNet != nil {
+			return nsNet.DialContext
+		}
+	}
+	dialer := &net.Dialer{}
+	return dialer.DialContext
+}
+
+// handshakeAlive reports whether the last WireGuard handshake with the peer is recent enough for its session to
+// carry traffic
+func handshakeAlive(stats map[string]configurer.WGStats, peerKey string, now time.Time) bool {
+	stat, ok := stats[peerKey]
+	if !ok || stat.LastHandshake.IsZero() {
+		return false
+	}
+	return now.Sub(stat.LastHandshake) <= maxHandshakeAge
+}
+
+// probePeer checks if the peer answers on the port of its tunnel IP and returns the round trip time of the TCP
+// handshake.
+func probePeer(ctx context.Context, dial dialFunc, ip, port string) (time.Duration, bool) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	conn, err := dial(ctx, "tcp", net.JoinHostPort(ip, port))
+	rtt := time.Since(start)
+	if err == nil {
+		if err := conn.Close(); err != nil {
+			log.Debugf("failed to close probe connection to %s: %v", ip, err)
+		}
+		return rtt, true
+	}
+
+	if peerResponded(err) {
+		return rtt, true
+	}
+
+	log.Tracef("route probe to %s failed: %v", ip, err)
+	return 0, false
+}
+
+// peerResponded reports whether a failed dial was still answered by the peer, e.g. with a refused connection.
+// Timeouts and unreachable errors mean the probe didn't make it through the tunnel.
+func peerResponded(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, syscall.EHOSTUNREACH) || errors.Is(err, syscall.ENETUNREACH) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return false
+	}
+
+	return !errors.Is(err, context.Canceled)
+}
+
+// probeTargets returns the connected routing peers of an HA group. Single routes and idle peers are not probed,
+// the former have no alternative and the latter would be woken up by the probe. Single exit nodes are probed
+// if the exit node failsafe is enabled, it falls back to the local internet connection.
+func (w *Watcher) probeTargets() []probeTarget {
+	peers := make(map[string]struct{})
+	for _, r := range w.routes {
+		peers[r.Peer] = struct{}{}
+	}
+	if len(peers) < 2 && !w.failsafeEnabled() {
+		return nil
+	}
+
+	targets := make([]probeTarget, 0, len(peers))
+	for peerKey := range peers {
+		state, err := w.statusRecorder.GetPeer(peerKey)
+		if err != nil || state.ConnStatus != peer.StatusConnected || state.IP == "" {
+			continue
+		}
+		targets = append(targets, probeTarget{peerKey: peerKey, ip: state.IP, latency: state.Latency})
+	}
+	return targets
+}
+
+// runProbes checks the WireGuard handshake of the targets and, if enabled, probes them concurrently over TCP.
+// The results are sent back to the watcher loop.
+func (w *Watcher) runProbes(targets []probeTarget) {
+	results := make([]probeResult, len(targets))
+
+	// without stats nothing is known about the handshakes, the peers are only probed over TCP if enabled
+	var stats map[string]configurer.WGStats
+	if w.wgStats != nil {
+		var err error
+		if stats, err = w.wgStats.GetStats(); err != nil {
+			log.Debugf("failed to get wireguard stats for route probes: %v", err)
+			stats = nil
+		}
+	}
+
+	now := time.Now()
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		results[i] = probeResult{peerKey: target.peerKey, healthy: true, rtt: target.latency}
+		if stats != nil && !handshakeAlive(stats, target.peerKey, now) {
+			results[i].healthy = false
+			continue
+		}
+		if w.probePort == "" {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, target probeTarget) {
+			defer wg.Done()
+			rtt, healthy := probePeer(w.ctx, w.dial, target.ip, w.probePort)
+			results[i] = probeResult{peerKey: target.peerKey, healthy: healthy, rtt: rtt}
+		}(i, target)
+	}
+	wg.Wait()
+
+	if stats == nil && w.probePort == "" {
+		results = nil
+	}
+
+	select {
+	case w.probeUpdate <- results:
+	case <-w.ctx.Done():
+	}
+}
+
+// handleProbeResults updates the peer health and reports whether any routing peer changed its health state.
+func (w *Watcher) handleProbeResults(results []probeResult) bool {
+	changed := false
+	for _, result := range results {
+		health, ok := w.peerHealth[result.peerKey]
+		if !ok {
+			health = &peerHealth{}
+			w.peerHealth[result.peerKey] = health
+		}
+
+		if result.healthy {
+			health.failures = 0
+			health.rtt = result.rtt
+			if health.unhealthy {
+				health.unhealthy = false
+				changed = true
+				w.probeEvent(result.peerKey, health)
+			}
+			continue
+		}
+
+		health.failures++
+		if !health.unhealthy && health.failures >= probeFailureThreshold {
+			health.unhealthy = true
+			changed = true
+			w.probeEvent(result.peerKey, health)
+		}
+	}
+	return changed
+}
+
+// isPeerUnhealthy reports whether the routing peer failed its recent liveness probes
+func (w *Watcher) isPeerUnhealthy(peerKey string) bool {
+	health, ok := w.peerHealth[peerKey]
+	return ok && health.unhealthy
+}
+
+// pruneHealth drops the health state of peers that are not probed anymore, e.g. because they disconnected.
+// They start over with a clean state once they are probed again.
+func (w *Watcher) pruneHealth(targets []probeTarget) {
+	probed := make(map[string]struct{}, len(targets))
+	for _, target := range targets {
+		probed[target.peerKey] = struct{}{}
+	}
+	for peerKey := range w.peerHealth {
+		if _, ok := probed[peerKey]; !ok {
+			delete(w.peerHealth, peerKey)
+		}
+	}
+}
+
+// startProbes probes the routing peers in the background unless the previous round is still running.
+func (w *Watcher) startProbes() {
+	targets := w.probeTargets()
+	w.pruneHealth(targets)
+	if len(targets) == 0 || w.probing || (w.wgStats == nil && w.probePort == "") {
+		return
+	}
+
+	w.probing = true
+	go w.runProbes(targets)
+}
+
+func (w *Watcher) probeEvent(peerKey string, health *peerHealth) {
+	meta := map[string]string{
+		"network": w.handler.String(),
+		"peer":    peerKey,
+	}
+
+	if health.unhealthy {
+		log.Warnf("routing peer %s for network [%v] failed %d liveness checks, demoting it", peerKey, w.handler, health.failures)
+		w.statusRecorder.PublishDedupEvent(
+			"route-probe-failed/"+peerKey,
+			proto.SystemEvent_WARNING,
+			proto.SystemEvent_NETWORK,
+			"Routing peer failed liveness probes",
+			"A routing peer stopped responding, traffic is moved to another peer of the group.",
+			meta,
+		)
+		return
+	}
+
+	meta["rtt"] = health.rtt.String()
+	log.Infof("routing peer %s for network [%v] passed the liveness check again (rtt %s)", peerKey, w.handler, health.rtt)
+	// the rtt differs between probes, so the key doesn't include the metadata
+	w.statusRecorder.PublishDedupEvent(
+		"route-probe-recovered/"+peerKey,
+		proto.SystemEvent_INFO,
+		proto.SystemEvent_NETWORK,
+		"Routing peer recovered",
+		"",
+		meta,
+	)
+}
@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/iface/configurer"
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/routemanager/common"
+	"github.com/netbirdio/netbird/client/internal/routemanager/static"
+	"github.com/netbirdio/netbird/route"
+)
+
+func TestPeerResponded(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "connection refused",
+			err:      &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED},
+			expected: true,
+		},
+		{
+			name:     "netstack connection refused",
+			err:      &net.OpError{Op: "connect", Err: errors.New("connection was refused")},
+			expected: true,
+		},
+		{
+			name:     "deadline exceeded",
+			err:      fmt.Errorf("dial: %w", context.DeadlineExceeded),
+			expected: false,
+		},
+		{
+			name:     "host unreachable",
+			err:      &net.OpError{Op: "dial", Err: syscall.EHOSTUNREACH},
+			expected: false,
+		},
+		{
+			name:     "canceled",
+			err:      context.Canceled,
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, peerResponded(tc.err))
+		})
+	}
+}
+
+func TestHandleProbeResults(t *testing.T) {
+	w := &Watcher{
+		statusRecorder: peer.NewRecorder("https://mgm"),
+		handler:        static.NewRoute(common.HandlerParams{Route: &route.Route{Network: netip.MustParsePrefix("0.0.0.0/0")}}),
+		peerHealth:     make(map[string]*peerHealth),
+	}
+
+	failed := []probeResult{{peerKey: "peer1"}}
+	for i := 1; i < probeFailureThreshold; i++ {
+		assert.False(t, w.handleProbeResults(failed), "peer should not be demoted before reaching the threshold")
+		assert.False(t, w.isPeerUnhealthy("peer1"))
+	}
+
+	assert.True(t, w.handleProbeResults(failed), "peer should be demoted after reaching the threshold")
+	assert.True(t, w.isPeerUnhealthy("peer1"))
+
+	assert.False(t, w.handleProbeResults(failed), "demoted peer should not change state on further failures")
+
+	assert.True(t, w.handleProbeResults([]probeResult{{peerKey: "peer1", healthy: true, rtt: time.Millisecond}}))
+	assert.False(t, w.isPeerUnhealthy("peer1"))
+	require.Contains(t, w.peerHealth, "peer1")
+	assert.Equal(t, 0, w.peerHealth["peer1"].failures)
+
+	w.pruneHealth(nil)
+	assert.Empty(t, w.peerHealth)
+}
+
+type fakeStater map[string]configurer.WGStats
+
+func (f fakeStater) GetStats() (map[string]configurer.WGStats, error) {
+	return f, nil
+}
+
+func TestHandshakeAlive(t *testing.T) {
+	now := time.Now()
+	stats := map[string]configurer.WGStats{
+		"recent": {LastHandshake: now.Add(-2 * time.Minute)},
+		"stale":  {LastHandshake: now.Add(-maxHandshakeAge - time.Second)},
+		"never":  {},
+	}
+
+	assert.True(t, handshakeAlive(stats, "recent", now))
+	assert.False(t, handshakeAlive(stats, "stale", now))
+	assert.False(t, handshakeAlive(stats, "never", now))
+	assert.False(t, handshakeAlive(stats, "unknown", now))
+}
+
+func TestRunProbesHandshake(t *testing.T) {
+	now := time.Now()
+	w := &Watcher{
+		ctx: context.Background(),
+		wgStats: fakeStater{
+			"peer1": {LastHandshake: now.Add(-30 * time.Second)},
+			"peer2": {LastHandshake: now.Add(-10 * time.Minute)},
+		},
+		dial: func(context.Context, string, string) (net.Conn, error) {
+			t.Fatal("TCP probes are opt-in")
+			return nil, nil
+		},
+		probeUpdate: make(chan []probeResult, 1),
+	}
+
+	w.runProbes([]probeTarget{
+		{peerKey: "peer1", ip: "100.64.0.1", latency: 5 * time.Millisecond},
+		{peerKey: "peer2", ip: "100.64.0.2"},
+	})
+
+	results := <-w.probeUpdate
+	assert.Equal(t, []probeResult{
+		{peerKey: "peer1", healthy: true, rtt: 5 * time.Millisecond},
+		{peerKey: "peer2"},
+	}, results)
+}
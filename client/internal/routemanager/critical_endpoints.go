@@ -0,0 +1,47 @@
+package routemanager
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+
+	nberrors "github.com/netbirdio/netbird/client/errors"
+)
+
+// DefaultCriticalEndpoints are the instance metadata services of the cloud providers. They serve the credentials of
+// the instance roles, sending them through an exit node breaks the retrieval.
+var DefaultCriticalEndpoints = []netip.Prefix{
+	// AWS, Azure, GCP, Oracle Cloud and most other providers
+	netip.MustParsePrefix("169.254.169.254/32"),
+	// AWS IPv6
+	netip.MustParsePrefix("fd00:ec2::254/128"),
+}
+
+// ParseCriticalEndpoints returns the prefixes of the configured critical endpoints, addresses or CIDRs that always
+// use the local connection. No entries return DefaultCriticalEndpoints, so a configured list overrides the defaults.
+// Invalid entries are skipped and returned as error.
+func ParseCriticalEndpoints(entries []string) ([]netip.Prefix, error) {
+	if len(entries) == 0 {
+		return DefaultCriticalEndpoints, nil
+	}
+
+	var prefixes []netip.Prefix
+	var merr *multierror.Error
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("invalid critical endpoint %q: not an address or CIDR", entry))
+			continue
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nberrors.FormatErrorOrNil(merr)
+}
@@ -0,0 +1,331 @@
+package routemanager
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/miekg/dns"
+	log "github.com/sirupsen/logrus"
+
+	nberrors "github.com/netbirdio/netbird/client/errors"
+	nbdns "github.com/netbirdio/netbird/client/internal/dns"
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/shared/management/domain"
+)
+
+const (
+	// DomainRouteDirect is the target of the domain routes that use the local connection instead of the tunnel
+	DomainRouteDirect = "direct"
+
+	// domainRouteMinLifetime is the shortest time a resolved address stays routed, connections outlive short TTLs
+	domainRouteMinLifetime = 5 * time.Minute
+	// domainRouteExpiryInterval is how often the expired addresses are removed
+	domainRouteExpiryInterval = 30 * time.Second
+)
+
+// domainRule routes the addresses a domain and its subdomains resolve to via a peer or the local connection
+type domainRule struct {
+	domain domain.Domain
+	// target is the FQDN, NetBird IP or public key of the routing peer, or DomainRouteDirect
+	target string
+}
+
+func (r domainRule) direct() bool {
+	return r.target == DomainRouteDirect
+}
+
+// domainRoute is an address resolved for a domain rule
+type domainRoute struct {
+	domain domain.Domain
+	// peerKey is the peer the address is routed to, empty for the local connection
+	peerKey string
+	expires time.Time
+}
+
+// parseDomainRules returns the rules of the configured domain routes, sorted by domain. Invalid domains are logged and
+// skipped.
+func parseDomainRules(routes map[string]string) []domainRule {
+	var rules []domainRule
+	for name, target := range routes {
+		domains, err := domain.ValidateDomains([]string{strings.TrimSpace(name)})
+		if err != nil {
+			log.Warnf("Ignoring domain route %q: %v", name, err)
+			continue
+		}
+		target = strings.TrimSpace(target)
+		if target == "" || strings.EqualFold(target, DomainRouteDirect) {
+			target = DomainRouteDirect
+		}
+		rules = append(rules, domainRule{domain: domains[0], target: target})
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].domain < rules[j].domain
+	})
+	return rules
+}
+
+// domainRuleHandler passes the queries of a domain rule on to the other handlers and routes the answered addresses
+type domainRuleHandler struct {
+	rule    domainRule
+	manager *DefaultManager
+}
+
+func (h *domainRuleHandler) String() string {
+	return fmt.Sprintf("domain route %s -> %s", h.rule.domain.SafeString(), h.rule.target)
+}
+
+// MatchSubdomains makes the rule cover the subdomains of its domain
+func (h *domainRuleHandler) MatchSubdomains() bool {
+	return true
+}
+
+func (h *domainRuleHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	resp := new(dns.Msg)
+	resp.SetRcode(r, dns.RcodeNameError)
+	// Set Zero bit to signal handler chain to continue
+	resp.MsgHdr.Zero = true
+	if err := w.WriteMsg(resp); err != nil {
+		log.Errorf("failed writing DNS continue response: %v", err)
+	}
+}
+
+func (h *domainRuleHandler) ObserveResponse(m *dns.Msg) {
+	if err := h.manager.routeDomainAnswer(h.rule, m); err != nil {
+		log.Errorf("Failed to route the addresses of %s: %v", h.rule.domain.SafeString(), err)
+	}
+}
+
+// startDomainRules registers the handlers of the domain rules with the DNS server and removes the expired addresses
+// periodically
+func (m *DefaultManager) startDomainRules() {
+	if len(m.domainRules) == 0 || m.dnsServer == nil || m.disableClientRoutes {
+		return
+	}
+
+	for _, rule := range m.domainRules {
+		m.dnsServer.RegisterHandler(domain.List{rule.domain}, &domainRuleHandler{rule: rule, manager: m}, nbdns.PriorityDomainRule)
+	}
+	log.Infof("Routing %d domain(s) by the local domain routes", len(m.domainRules))
+
+	m.shutdownWg.Add(1)
+	go func() {
+		defer m.shutdownWg.Done()
+
+		ticker := time.NewTicker(domainRouteExpiryInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-m.ctx.Done():
+				return
+			case now := <-ticker.C:
+				if err := m.expireDomainRoutes(now); err != nil {
+					log.Errorf("Failed to remove expired domain routes: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// stopDomainRules deregisters the handlers of the domain rules and removes their routes
+func (m *DefaultManager) stopDomainRules() {
+	if len(m.domainRules) == 0 || m.dnsServer == nil || m.disableClientRoutes {
+		return
+	}
+
+	for _, rule := range m.domainRules {
+		m.dnsServer.DeregisterHandler(domain.List{rule.domain}, nbdns.PriorityDomainRule)
+	}
+
+	m.domainRoutesMu.Lock()
+	defer m.domainRoutesMu.Unlock()
+
+	for prefix, route := range m.domainRoutes {
+		if err := m.removeDomainRoute(prefix, route); err != nil {
+			log.Errorf("Failed to remove domain route for %s: %v", prefix, err)
+		}
+	}
+	m.domainRoutes = nil
+}
+
+// routeDomainAnswer routes the addresses of the answer as the rule says until their TTL expired
+func (m *DefaultManager) routeDomainAnswer(rule domainRule, msg *dns.Msg) error {
+	if msg.Rcode != dns.RcodeSuccess {
+		return nil
+	}
+
+	var peerKey string
+	if !rule.direct() {
+		key, ok := findPeerKey(m.statusRecorder.GetFullStatus().Peers, rule.target)
+		if !ok {
+			return fmt.Errorf("peer %s not found", rule.target)
+		}
+		peerKey = key
+	}
+
+	now := time.Now()
+	var merr *multierror.Error
+	var directChanged bool
+
+	m.domainRoutesMu.Lock()
+	if m.domainRoutes == nil {
+		m.domainRoutes = make(map[netip.Prefix]domainRoute)
+	}
+	for _, rr := range msg.Answer {
+		var addr netip.Addr
+		switch rr := rr.(type) {
+		case *dns.A:
+			addr, _ = netip.AddrFromSlice(rr.A)
+		case *dns.AAAA:
+			addr, _ = netip.AddrFromSlice(rr.AAAA)
+		default:
+			continue
+		}
+		if !addr.IsValid() {
+			continue
+		}
+		addr = addr.Unmap()
+		prefix := netip.PrefixFrom(addr, addr.BitLen())
+
+		route := domainRoute{
+			domain:  rule.domain,
+			peerKey: peerKey,
+			expires: now.Add(max(time.Duration(rr.Header().Ttl)*time.Second, domainRouteMinLifetime)),
+		}
+
+		existing, ok := m.domainRoutes[prefix]
+		if ok && existing.peerKey == peerKey {
+			if route.expires.After(existing.expires) {
+				m.domainRoutes[prefix] = route
+			}
+			continue
+		}
+		if ok {
+			if err := m.removeDomainRoute(prefix, existing); err != nil {
+				merr = multierror.Append(merr, err)
+			}
+			directChanged = directChanged || existing.peerKey == ""
+		}
+		if err := m.addDomainRoute(prefix, route); err != nil {
+			merr = multierror.Append(merr, err)
+			delete(m.domainRoutes, prefix)
+			continue
+		}
+		m.domainRoutes[prefix] = route
+		directChanged = directChanged || peerKey == ""
+	}
+	m.domainRoutesMu.Unlock()
+
+	if directChanged {
+		if err := m.refreshDirectDomainRoutes(); err != nil {
+			merr = multierror.Append(merr, err)
+		}
+	}
+
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+// expireDomainRoutes removes the addresses whose TTL expired
+func (m *DefaultManager) expireDomainRoutes(now time.Time) error {
+	var merr *multierror.Error
+	var directChanged bool
+
+	m.domainRoutesMu.Lock()
+	for prefix, route := range m.domainRoutes {
+		if now.Before(route.expires) {
+			continue
+		}
+		if err := m.removeDomainRoute(prefix, route); err != nil {
+			merr = multierror.Append(merr, err)
+		}
+		delete(m.domainRoutes, prefix)
+		directChanged = directChanged || route.peerKey == ""
+		log.Debugf("Removed expired domain route for %s of %s", prefix, route.domain.SafeString())
+	}
+	m.domainRoutesMu.Unlock()
+
+	if directChanged {
+		if err := m.refreshDirectDomainRoutes(); err != nil {
+			merr = multierror.Append(merr, err)
+		}
+	}
+
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+// addDomainRoute routes the address to the peer of the route through the tunnel. The addresses for the local
+// connection are excluded from the VPN routes by refreshDirectDomainRoutes. The caller must hold domainRoutesMu.
+func (m *DefaultManager) addDomainRoute(prefix netip.Prefix, route domainRoute) error {
+	if route.peerKey == "" {
+		log.Debugf("Routing %s of %s via the local connection", prefix, route.domain.SafeString())
+		return nil
+	}
+
+	if _, err := m.routeRefCounter.Increment(prefix, struct{}{}); err != nil {
+		return fmt.Errorf("add route for %s: %w", prefix, err)
+	}
+	if _, err := m.allowedIPsRefCounter.Increment(prefix, route.peerKey); err != nil {
+		return fmt.Errorf("add allowed IP %s: %w", prefix, err)
+	}
+	log.Debugf("Routing %s of %s via peer %s", prefix, route.domain.SafeString(), route.peerKey)
+	return nil
+}
+
+// removeDomainRoute removes the route of the address added by addDomainRoute. The caller must hold domainRoutesMu.
+func (m *DefaultManager) removeDomainRoute(prefix netip.Prefix, route domainRoute) error {
+	if route.peerKey == "" {
+		return nil
+	}
+
+	var merr *multierror.Error
+	if _, err := m.routeRefCounter.Decrement(prefix); err != nil {
+		merr = multierror.Append(merr, fmt.Errorf("remove route for %s: %w", prefix, err))
+	}
+	if _, err := m.allowedIPsRefCounter.Decrement(prefix); err != nil {
+		merr = multierror.Append(merr, fmt.Errorf("remove allowed IP %s: %w", prefix, err))
+	}
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+// refreshDirectDomainRoutes updates the bypass routes after the addresses for the local connection changed
+func (m *DefaultManager) refreshDirectDomainRoutes() error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.ctx.Err() != nil || !m.excludesFromExitNode() {
+		return nil
+	}
+	return m.updateExitNodeExclusions(m.routeSelector.FilterSelectedExitNodes(m.clientRoutes))
+}
+
+// directDomainPrefixes returns the resolved addresses of the domain rules for the local connection
+func (m *DefaultManager) directDomainPrefixes() []netip.Prefix {
+	m.domainRoutesMu.Lock()
+	defer m.domainRoutesMu.Unlock()
+
+	var prefixes []netip.Prefix
+	for prefix, route := range m.domainRoutes {
+		if route.peerKey == "" {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+// findPeerKey returns the public key of the peer with the FQDN, short name, NetBird IP or public key
+func findPeerKey(peers []peer.State, target string) (string, bool) {
+	target = strings.TrimSuffix(strings.ToLower(target), ".")
+	for _, p := range peers {
+		fqdn := strings.TrimSuffix(strings.ToLower(p.FQDN), ".")
+		short, _, _ := strings.Cut(fqdn, ".")
+		if target == fqdn || target == short || target == p.IP || target == strings.ToLower(p.PubKey) {
+			return p.PubKey, true
+		}
+	}
+	return "", false
+}
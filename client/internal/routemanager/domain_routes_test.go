@@ -0,0 +1,100 @@
+package routemanager
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/routemanager/refcounter"
+	"github.com/netbirdio/netbird/shared/management/domain"
+)
+
+func TestParseDomainRules(t *testing.T) {
+	rules := parseDomainRules(map[string]string{
+		"Example.com":     "router.netbird.cloud",
+		"*.internal.corp": "100.64.0.2",
+		"printer.lan":     "",
+		"bank.example":    "Direct",
+		"not a domain!":   "direct",
+	})
+
+	assert.Equal(t, []domainRule{
+		{domain: "*.internal.corp", target: "100.64.0.2"},
+		{domain: "bank.example", target: DomainRouteDirect},
+		{domain: "example.com", target: "router.netbird.cloud"},
+		{domain: "printer.lan", target: DomainRouteDirect},
+	}, rules)
+}
+
+func TestRouteDomainAnswer(t *testing.T) {
+	recorder := peer.NewRecorder("")
+	require.NoError(t, recorder.AddPeer("peerkey", "router.netbird.cloud", "100.64.0.2"))
+
+	routes := map[netip.Prefix]struct{}{}
+	allowedIPs := map[netip.Prefix]string{}
+
+	m := &DefaultManager{
+		ctx:                 context.Background(),
+		statusRecorder:      recorder,
+		disableClientRoutes: true,
+		routeRefCounter: refcounter.New(
+			func(prefix netip.Prefix, _ struct{}) (struct{}, error) {
+				routes[prefix] = struct{}{}
+				return struct{}{}, nil
+			},
+			func(prefix netip.Prefix, _ struct{}) error {
+				delete(routes, prefix)
+				return nil
+			},
+		),
+		allowedIPsRefCounter: refcounter.New(
+			func(prefix netip.Prefix, peerKey string) (string, error) {
+				allowedIPs[prefix] = peerKey
+				return peerKey, nil
+			},
+			func(prefix netip.Prefix, _ string) error {
+				delete(allowedIPs, prefix)
+				return nil
+			},
+		),
+	}
+
+	rule := domainRule{domain: domain.Domain("example.com"), target: "router"}
+	msg := new(dns.Msg)
+	msg.SetQuestion("www.example.com.", dns.TypeA)
+	msg.Rcode = dns.RcodeSuccess
+	msg.Answer = []dns.RR{
+		&dns.CNAME{Hdr: dns.RR_Header{Name: "www.example.com.", Rrtype: dns.TypeCNAME, Ttl: 60}, Target: "edge.example.net."},
+		&dns.A{Hdr: dns.RR_Header{Name: "edge.example.net.", Rrtype: dns.TypeA, Ttl: 60}, A: net.ParseIP("192.0.2.10")},
+		&dns.A{Hdr: dns.RR_Header{Name: "edge.example.net.", Rrtype: dns.TypeA, Ttl: 3600}, A: net.ParseIP("192.0.2.11")},
+	}
+
+	require.NoError(t, m.routeDomainAnswer(rule, msg))
+
+	first := netip.MustParsePrefix("192.0.2.10/32")
+	second := netip.MustParsePrefix("192.0.2.11/32")
+	assert.Equal(t, map[netip.Prefix]struct{}{first: {}, second: {}}, routes)
+	assert.Equal(t, map[netip.Prefix]string{first: "peerkey", second: "peerkey"}, allowedIPs)
+
+	// answering again keeps the routes
+	require.NoError(t, m.routeDomainAnswer(rule, msg))
+	assert.Len(t, routes, 2)
+
+	// the short TTL is raised to the minimum lifetime
+	require.NoError(t, m.expireDomainRoutes(time.Now().Add(domainRouteMinLifetime+time.Second)))
+	assert.Equal(t, map[netip.Prefix]struct{}{second: {}}, routes)
+	assert.Equal(t, map[netip.Prefix]string{second: "peerkey"}, allowedIPs)
+
+	require.NoError(t, m.expireDomainRoutes(time.Now().Add(time.Hour+time.Second)))
+	assert.Empty(t, routes)
+	assert.Empty(t, allowedIPs)
+
+	assert.Error(t, m.routeDomainAnswer(domainRule{domain: "example.com", target: "unknown"}, msg))
+}
@@ -0,0 +1,105 @@
+package routemanager
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/hashicorp/go-multierror"
+
+	nberrors "github.com/netbirdio/netbird/client/errors"
+	nbnet "github.com/netbirdio/netbird/client/net"
+	"github.com/netbirdio/netbird/route"
+)
+
+// ProtectEndpoints resolves the management, signal and relay addresses again and keeps host routes via the physical
+// interface for the ones the selected client routes cover, so an exit node or a broad network can't cut the client
+// off from the NetBird services. The exit node exclusions are resolved again as well. It should be called when the
+// underlay network changed, the host routes follow the new default next hop.
+func (m *DefaultManager) ProtectEndpoints() error {
+	protects, excludes := m.protectsEndpoints(), m.excludesFromExitNode()
+	if !protects && !excludes {
+		return nil
+	}
+
+	m.mux.Lock()
+	clientRoutes := m.clientRoutes
+	m.mux.Unlock()
+
+	var addrs []netip.Addr
+	var excludedPrefixes []netip.Prefix
+	if len(clientRoutes) > 0 {
+		if protects {
+			addrs = m.resolveServiceAddrs()
+		}
+		if excludes {
+			excludedPrefixes = m.resolveExitNodeExclusions()
+		}
+	}
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	selectedRoutes := m.routeSelector.FilterSelectedExitNodes(m.clientRoutes)
+
+	var merr *multierror.Error
+	if protects {
+		if err := m.sysOps.ProtectEndpoints(coveredAddrs(addrs, selectedRoutes), m.stateManager); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("protect service endpoints: %w", err))
+		}
+	}
+	if excludes {
+		m.excludedPrefixes = excludedPrefixes
+		if err := m.updateExitNodeExclusions(selectedRoutes); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("exclude destinations from exit node: %w", err))
+		}
+	}
+
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+// protectsEndpoints reports whether the client routes can cover the NetBird services. With advanced routing the
+// traffic of the client is marked and never enters the VPN routes.
+func (m *DefaultManager) protectsEndpoints() bool {
+	return !nbnet.CustomRoutingDisabled() && !nbnet.AdvancedRouting() && !m.disableClientRoutes
+}
+
+// serviceURLs returns the URLs of the NetBird services the client must keep reaching
+func (m *DefaultManager) serviceURLs() []string {
+	urls := []string{m.statusRecorder.GetManagementState().URL, m.statusRecorder.GetSignalState().URL}
+	if m.relayMgr != nil {
+		urls = append(urls, m.relayMgr.ServerURLs()...)
+	}
+	return urls
+}
+
+func (m *DefaultManager) resolveServiceAddrs() []netip.Addr {
+	var addrs []netip.Addr
+	for _, ip := range resolveURLsToIPs(m.serviceURLs()) {
+		if addr, ok := netip.AddrFromSlice(ip); ok {
+			addrs = append(addrs, addr.Unmap())
+		}
+	}
+	return addrs
+}
+
+// coveredAddrs returns the addresses that one of the client routes with a static network would route to the VPN
+func coveredAddrs(addrs []netip.Addr, clientRoutes route.HAMap) []netip.Addr {
+	var networks []netip.Prefix
+	for _, routes := range clientRoutes {
+		if len(routes) == 0 || routes[0] == nil || routes[0].IsDynamic() || !routes[0].Network.IsValid() {
+			continue
+		}
+		networks = append(networks, routes[0].Network)
+	}
+
+	var covered []netip.Addr
+	for _, addr := range addrs {
+		for _, network := range networks {
+			if network.Contains(addr) {
+				covered = append(covered, addr)
+				break
+			}
+		}
+	}
+	return covered
+}
@@ -0,0 +1,63 @@
+package routemanager
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/route"
+	"github.com/netbirdio/netbird/shared/management/domain"
+)
+
+func TestCoveredAddrs(t *testing.T) {
+	mgmt := netip.MustParseAddr("203.0.113.10")
+	signal := netip.MustParseAddr("10.1.2.3")
+	relay := netip.MustParseAddr("2001:db8::1")
+	addrs := []netip.Addr{mgmt, signal, relay}
+
+	testCases := []struct {
+		name     string
+		routes   route.HAMap
+		expected []netip.Addr
+	}{
+		{
+			name:   "no routes",
+			routes: route.HAMap{},
+		},
+		{
+			name: "exit node covers v4 only",
+			routes: route.HAMap{
+				"exit|0.0.0.0/0": {{Network: netip.MustParsePrefix("0.0.0.0/0")}},
+			},
+			expected: []netip.Addr{mgmt, signal},
+		},
+		{
+			name: "broad network",
+			routes: route.HAMap{
+				"lan|10.0.0.0/8":       {{Network: netip.MustParsePrefix("10.0.0.0/8")}},
+				"other|192.168.0.0/16": {{Network: netip.MustParsePrefix("192.168.0.0/16")}},
+			},
+			expected: []netip.Addr{signal},
+		},
+		{
+			name: "dynamic routes are resolved separately",
+			routes: route.HAMap{
+				"dyn|example.com": {{Domains: domain.List{"example.com"}, Network: netip.MustParsePrefix("192.0.2.0/32")}},
+			},
+		},
+		{
+			name: "v6 default route",
+			routes: route.HAMap{
+				"exit|::/0": {{Network: netip.MustParsePrefix("::/0")}},
+			},
+			expected: []netip.Addr{relay},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tc.expected, coveredAddrs(addrs, tc.routes))
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package routemanager
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	nbnet "github.com/netbirdio/netbird/client/net"
+	"github.com/netbirdio/netbird/route"
+	"github.com/netbirdio/netbird/shared/management/domain"
+)
+
+// exitNodeExclusionLookupTimeout bounds the resolution of the excluded domains
+const exitNodeExclusionLookupTimeout = 5 * time.Second
+
+// exitNodeExclusions are the destinations that bypass a selected exit node
+type exitNodeExclusions struct {
+	prefixes []netip.Prefix
+	domains  domain.List
+}
+
+// parseExitNodeExclusions splits the configured exclusions into CIDRs, single addresses and domains and adds the
+// critical endpoints. Invalid entries are logged and skipped.
+func parseExitNodeExclusions(entries []string, criticalEndpoints []string) exitNodeExclusions {
+	var exclusions exitNodeExclusions
+
+	critical, err := ParseCriticalEndpoints(criticalEndpoints)
+	if err != nil {
+		log.Warnf("Ignoring critical endpoints: %v", err)
+	}
+	exclusions.prefixes = append(exclusions.prefixes, critical...)
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			exclusions.prefixes = append(exclusions.prefixes, prefix.Masked())
+			continue
+		}
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			addr = addr.Unmap()
+			exclusions.prefixes = append(exclusions.prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		// wildcard domains can't be resolved to addresses
+		domains, err := domain.ValidateDomains([]string{entry})
+		if err != nil || strings.HasPrefix(entry, "*.") {
+			log.Warnf("Ignoring exit node exclusion %q: not a CIDR, address or domain", entry)
+			continue
+		}
+		exclusions.domains = append(exclusions.domains, domains...)
+	}
+	return exclusions
+}
+
+func (e exitNodeExclusions) empty() bool {
+	return len(e.prefixes) == 0 && len(e.domains) == 0
+}
+
+// excludesFromExitNode reports whether the manager keeps bypass routes for the exit node exclusions or the domain
+// rules of the local connection
+func (m *DefaultManager) excludesFromExitNode() bool {
+	hasExclusions := !m.exitNodeExclusions.empty() || len(m.domainRules) > 0
+	return hasExclusions && !nbnet.CustomRoutingDisabled() && !m.disableClientRoutes
+}
+
+// resolveExitNodeExclusions returns the excluded CIDRs and host prefixes of the addresses the excluded domains
+// resolve to. Domains failing to resolve are skipped.
+func (m *DefaultManager) resolveExitNodeExclusions() []netip.Prefix {
+	prefixes := append([]netip.Prefix(nil), m.exitNodeExclusions.prefixes...)
+
+	for _, d := range m.exitNodeExclusions.domains {
+		ctx, cancel := context.WithTimeout(m.ctx, exitNodeExclusionLookupTimeout)
+		addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", d.PunycodeString())
+		cancel()
+		if err != nil {
+			log.Warnf("Failed to resolve exit node exclusion %s: %v", d.SafeString(), err)
+			continue
+		}
+		for _, addr := range addrs {
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+		}
+	}
+	return prefixes
+}
+
+// updateExitNodeExclusions adds the bypass routes of the excluded prefixes while one of the client routes is a
+// selected exit node and removes them otherwise. The addresses of the domain rules for the local connection bypass
+// every client route. The caller must hold the lock.
+func (m *DefaultManager) updateExitNodeExclusions(selectedRoutes route.HAMap) error {
+	var prefixes []netip.Prefix
+	if hasExitNode(selectedRoutes) {
+		prefixes = append(prefixes, m.excludedPrefixes...)
+	}
+	prefixes = append(prefixes, m.directDomainPrefixes()...)
+	return m.sysOps.ExcludeFromVPN(prefixes, m.stateManager)
+}
+
+// hasExitNode reports whether one of the routes is a default route
+func hasExitNode(routes route.HAMap) bool {
+	for _, haRoutes := range routes {
+		if len(haRoutes) > 0 && haRoutes[0] != nil && !haRoutes[0].IsDynamic() && haRoutes[0].Network.Bits() == 0 {
+			return true
+		}
+	}
+	return false
+}
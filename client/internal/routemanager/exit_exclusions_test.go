@@ -0,0 +1,99 @@
+package routemanager
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/route"
+	"github.com/netbirdio/netbird/shared/management/domain"
+)
+
+func TestParseExitNodeExclusions(t *testing.T) {
+	exclusions := parseExitNodeExclusions([]string{
+		"192.168.1.0/24",
+		" 10.1.2.3/16 ",
+		"192.0.2.10",
+		"::ffff:198.51.100.1",
+		"2001:db8::/32",
+		"vpn.example.com",
+		"",
+		"not a destination!",
+		"*.example.com",
+	}, []string{"10.0.0.2"})
+
+	assert.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.2/32"),
+		netip.MustParsePrefix("192.168.1.0/24"),
+		netip.MustParsePrefix("10.1.0.0/16"),
+		netip.MustParsePrefix("192.0.2.10/32"),
+		netip.MustParsePrefix("198.51.100.1/32"),
+		netip.MustParsePrefix("2001:db8::/32"),
+	}, exclusions.prefixes)
+	assert.Equal(t, domain.List{"vpn.example.com"}, exclusions.domains)
+
+	exclusions = parseExitNodeExclusions(nil, nil)
+	assert.Equal(t, DefaultCriticalEndpoints, exclusions.prefixes)
+	assert.Empty(t, exclusions.domains)
+}
+
+func TestParseCriticalEndpoints(t *testing.T) {
+	prefixes, err := ParseCriticalEndpoints(nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultCriticalEndpoints, prefixes)
+
+	prefixes, err = ParseCriticalEndpoints([]string{"169.254.170.2", "10.0.0.2/31", "metadata"})
+	assert.Error(t, err)
+	assert.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("169.254.170.2/32"),
+		netip.MustParsePrefix("10.0.0.2/31"),
+	}, prefixes)
+}
+
+func TestHasExitNode(t *testing.T) {
+	testCases := []struct {
+		name     string
+		routes   route.HAMap
+		expected bool
+	}{
+		{
+			name:   "no routes",
+			routes: route.HAMap{},
+		},
+		{
+			name: "network routes only",
+			routes: route.HAMap{
+				"lan|10.0.0.0/8": {{Network: netip.MustParsePrefix("10.0.0.0/8")}},
+			},
+		},
+		{
+			name: "dynamic route",
+			routes: route.HAMap{
+				"dyn|example.com": {{Domains: domain.List{"example.com"}, NetworkType: route.DomainNetwork}},
+			},
+		},
+		{
+			name: "v4 exit node",
+			routes: route.HAMap{
+				"lan|10.0.0.0/8": {{Network: netip.MustParsePrefix("10.0.0.0/8")}},
+				"exit|0.0.0.0/0": {{Network: netip.MustParsePrefix("0.0.0.0/0")}},
+			},
+			expected: true,
+		},
+		{
+			name: "v6 exit node",
+			routes: route.HAMap{
+				"exit|::/0": {{Network: netip.MustParsePrefix("::/0")}},
+			},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, hasExitNode(tc.routes))
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/netbirdio/netbird/client/iface/configurer"
 	"github.com/netbirdio/netbird/client/iface/netstack"
 	"github.com/netbirdio/netbird/client/internal/dns"
+	"github.com/netbirdio/netbird/client/internal/eventbus"
 	"github.com/netbirdio/netbird/client/internal/listener"
 	"github.com/netbirdio/netbird/client/internal/peer"
 	"github.com/netbirdio/netbird/client/internal/peerstore"
@@ -30,6 +31,7 @@ import (
 	"github.com/netbirdio/netbird/client/internal/routemanager/common"
 	"github.com/netbirdio/netbird/client/internal/routemanager/fakeip"
 	"github.com/netbirdio/netbird/client/internal/routemanager/iface"
+	"github.com/netbirdio/netbird/client/internal/routemanager/metrics"
 	"github.com/netbirdio/netbird/client/internal/routemanager/notifier"
 	"github.com/netbirdio/netbird/client/internal/routemanager/refcounter"
 	"github.com/netbirdio/netbird/client/internal/routemanager/server"
@@ -53,8 +55,12 @@ type Manager interface {
 	GetRouteSelector() *routeselector.RouteSelector
 	GetClientRoutes() route.HAMap
 	GetClientRoutesWithNetID() map[route.NetID][]*route.Route
+	ServerRoutesCount() int
+	AllowRouteFailover(peerIP netip.Addr, networks []netip.Prefix, grace time.Duration) error
+	PinExitNode(peerKey string) error
 	SetRouteChangeListener(listener listener.NetworkChangeListener)
 	InitialRouteRange() []string
+	ProtectEndpoints() error
 	SetFirewall(firewall.Manager) error
 	SetDNSForwarderPort(port uint16)
 	Stop(stateManager *statemanager.Manager)
@@ -74,6 +80,26 @@ type ManagerConfig struct {
 	PeerStore           *peerstore.Store
 	DisableClientRoutes bool
 	DisableServerRoutes bool
+	// RouteFailoverListener is called when the traffic of a client network moved to another routing peer
+	RouteFailoverListener func(newRoute *route.Route)
+	// ExitNodeFailsafe is how long an exit node may be unreachable before its default route is removed, so the
+	// traffic uses the local internet connection until it recovers. Zero disables it.
+	ExitNodeFailsafe time.Duration
+	// RouteDisconnectGrace is how long the routes of a disconnected routing peer stay installed before they fail
+	// over or are removed, so short disconnects don't make the routes flap. Zero disables it.
+	RouteDisconnectGrace time.Duration
+	// ExitNodeExclusions are the CIDRs and domains that bypass a selected exit node via the physical interface
+	ExitNodeExclusions []string
+	// CriticalEndpoints are the addresses and CIDRs of services like the cloud metadata service that bypass a
+	// selected exit node, empty uses DefaultCriticalEndpoints
+	CriticalEndpoints []string
+	// DomainRoutes route the addresses the domains and their subdomains resolve to via the routing peer with the
+	// FQDN, NetBird IP or public key, or via the local connection with DomainRouteDirect
+	DomainRoutes map[string]string
+	// Metrics records the latency of the route syscalls, nil disables it
+	Metrics *metrics.Recorder
+	// EventBus receives the route changes
+	EventBus *eventbus.Bus
 }
 
 // DefaultManager is the default instance of a route manager
@@ -106,6 +132,18 @@ type DefaultManager struct {
 	activeRoutes        map[route.HAUniqueID]client.RouteHandler
 	fakeIPManager       *fakeip.Manager
 	dnsForwarderPort    atomic.Uint32
+	onRouteFailover     func(newRoute *route.Route)
+	exitNodeFailsafe    time.Duration
+	disconnectGrace     time.Duration
+	metrics             *metrics.Recorder
+	eventBus            *eventbus.Bus
+	exitNodeExclusions  exitNodeExclusions
+	// excludedPrefixes are the exit node exclusions as of the last resolution of their domains
+	excludedPrefixes []netip.Prefix
+	domainRules      []domainRule
+	// domainRoutes are the addresses resolved for the domain rules
+	domainRoutes   map[netip.Prefix]domainRoute
+	domainRoutesMu sync.Mutex
 }
 
 func NewManager(config ManagerConfig) *DefaultManager {
@@ -134,6 +172,13 @@ func NewManager(config ManagerConfig) *DefaultManager {
 		disableClientRoutes: config.DisableClientRoutes,
 		disableServerRoutes: config.DisableServerRoutes,
 		activeRoutes:        make(map[route.HAUniqueID]client.RouteHandler),
+		onRouteFailover:     config.RouteFailoverListener,
+		exitNodeFailsafe:    config.ExitNodeFailsafe,
+		disconnectGrace:     config.RouteDisconnectGrace,
+		metrics:             config.Metrics,
+		eventBus:            config.EventBus,
+		exitNodeExclusions:  parseExitNodeExclusions(config.ExitNodeExclusions, config.CriticalEndpoints),
+		domainRules:         parseDomainRules(config.DomainRoutes),
 	}
 	dm.dnsForwarderPort.Store(uint32(nbdns.ForwarderClientPort))
 
@@ -175,10 +220,16 @@ func (m *DefaultManager) setupAndroidRoutes(config ManagerConfig) {
 func (m *DefaultManager) setupRefCounters(useNoop bool) {
 	m.routeRefCounter = refcounter.New(
 		func(prefix netip.Prefix, _ struct{}) (struct{}, error) {
-			return struct{}{}, m.sysOps.AddVPNRoute(prefix, m.wgInterface.ToInterface())
+			start := time.Now()
+			err := m.sysOps.AddVPNRoute(prefix, m.wgInterface.ToInterface())
+			m.metrics.ObserveRouteAdd(time.Since(start))
+			return struct{}{}, err
 		},
 		func(prefix netip.Prefix, _ struct{}) error {
-			return m.sysOps.RemoveVPNRoute(prefix, m.wgInterface.ToInterface())
+			start := time.Now()
+			err := m.sysOps.RemoveVPNRoute(prefix, m.wgInterface.ToInterface())
+			m.metrics.ObserveRouteRemove(time.Since(start))
+			return err
 		},
 	)
 
@@ -213,6 +264,7 @@ func (m *DefaultManager) setupRefCounters(useNoop bool) {
 // Init sets up the routing
 func (m *DefaultManager) Init() error {
 	m.routeSelector = m.initSelector()
+	m.startDomainRules()
 
 	if nbnet.CustomRoutingDisabled() || m.disableClientRoutes {
 		return nil
@@ -222,12 +274,7 @@ func (m *DefaultManager) Init() error {
 		log.Warnf("Failed cleaning up routing: %v", err)
 	}
 
-	initialAddresses := []string{m.statusRecorder.GetManagementState().URL, m.statusRecorder.GetSignalState().URL}
-	if m.relayMgr != nil {
-		initialAddresses = append(initialAddresses, m.relayMgr.ServerURLs()...)
-	}
-
-	ips := resolveURLsToIPs(initialAddresses)
+	ips := resolveURLsToIPs(m.serviceURLs())
 
 	if err := m.sysOps.SetupRouting(ips, m.stateManager, nbnet.AdvancedRouting()); err != nil {
 		return fmt.Errorf("setup routing: %w", err)
@@ -269,7 +316,7 @@ func (m *DefaultManager) SetFirewall(firewall firewall.Manager) error {
 	}
 
 	var err error
-	m.serverRouter, err = server.NewRouter(m.ctx, m.wgInterface, firewall, m.statusRecorder)
+	m.serverRouter, err = server.NewRouter(m.ctx, m.wgInterface, firewall, m.eventBus)
 	if err != nil {
 		return err
 	}
@@ -285,6 +332,7 @@ func (m *DefaultManager) SetDNSForwarderPort(port uint16) {
 func (m *DefaultManager) Stop(stateManager *statemanager.Manager) {
 	m.stop()
 	m.shutdownWg.Wait()
+	m.stopDomainRules()
 	if m.serverRouter != nil {
 		m.serverRouter.CleanUp()
 	}
@@ -360,6 +408,9 @@ func (m *DefaultManager) updateSystemRoutes(newRoutes route.HAMap) error {
 			ForwarderPort:        &m.dnsForwarderPort,
 		}
 		handler := client.HandlerFromRoute(params)
+		if m.exitNodeFailsafe > 0 && !route.IsDynamic() && route.Network.Bits() == 0 {
+			handler = client.NewSuspendableHandler(handler)
+		}
 		if err := handler.AddRoute(m.ctx); err != nil {
 			merr = multierror.Append(merr, fmt.Errorf("add route %s: %w", handler.String(), err))
 			continue
@@ -383,6 +434,16 @@ func (m *DefaultManager) UpdateRoutes(
 	default:
 	}
 
+	// resolve the services before locking, the host routes must be in place before the client routes cover them
+	var serviceAddrs []netip.Addr
+	if len(clientRoutes) > 0 && m.protectsEndpoints() {
+		serviceAddrs = m.resolveServiceAddrs()
+	}
+	var excludedPrefixes []netip.Prefix
+	if len(clientRoutes) > 0 && m.excludesFromExitNode() {
+		excludedPrefixes = m.resolveExitNodeExclusions()
+	}
+
 	m.mux.Lock()
 	defer m.mux.Unlock()
 	m.useNewDNSRoute = useNewDNSRoute
@@ -395,6 +456,19 @@ func (m *DefaultManager) UpdateRoutes(
 
 		filteredClientRoutes := m.routeSelector.FilterSelectedExitNodes(clientRoutes)
 
+		if m.protectsEndpoints() {
+			if err := m.sysOps.ProtectEndpoints(coveredAddrs(serviceAddrs, filteredClientRoutes), m.stateManager); err != nil {
+				merr = multierror.Append(merr, fmt.Errorf("protect service endpoints: %w", err))
+			}
+		}
+
+		if m.excludesFromExitNode() {
+			m.excludedPrefixes = excludedPrefixes
+			if err := m.updateExitNodeExclusions(filteredClientRoutes); err != nil {
+				merr = multierror.Append(merr, fmt.Errorf("exclude destinations from exit node: %w", err))
+			}
+		}
+
 		if err := m.updateSystemRoutes(filteredClientRoutes); err != nil {
 			merr = multierror.Append(merr, fmt.Errorf("update system routes: %w", err))
 		}
@@ -438,6 +512,29 @@ func (m *DefaultManager) GetClientRoutes() route.HAMap {
 	return maps.Clone(m.clientRoutes)
 }
 
+// ServerRoutesCount returns the number of routes this peer routes for other peers
+func (m *DefaultManager) ServerRoutesCount() int {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.serverRouter == nil {
+		return 0
+	}
+	return m.serverRouter.RoutesCount()
+}
+
+// AllowRouteFailover accepts the connections of the peer to the networks that failed over from another routing peer
+// for the grace period
+func (m *DefaultManager) AllowRouteFailover(peerIP netip.Addr, networks []netip.Prefix, grace time.Duration) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.serverRouter == nil {
+		return errors.New("server routes are disabled")
+	}
+	return m.serverRouter.AllowFailover(peerIP, networks, grace)
+}
+
 // GetClientRoutesWithNetID returns the current routes from the route map, but the keys consist of the network ID only
 func (m *DefaultManager) GetClientRoutesWithNetID() map[route.NetID][]*route.Route {
 	m.mux.Lock()
@@ -450,6 +547,45 @@ func (m *DefaultManager) GetClientRoutesWithNetID() map[route.NetID][]*route.Rou
 	return routes
 }
 
+// PinExitNode makes the given routing peer the preferred exit node. The watchers fail over to the
+// remaining exit node routes whenever the pinned peer is unreachable. An empty key restores automatic selection.
+func (m *DefaultManager) PinExitNode(peerKey string) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if peerKey != "" && !m.isExitNodePeer(peerKey) {
+		return fmt.Errorf("peer %s does not provide an exit node", peerKey)
+	}
+
+	m.routeSelector.PinExitNode(peerKey)
+
+	for id, watcher := range m.clientNetworks {
+		if m.isExitNodeRoute(m.clientRoutes[id]) {
+			watcher.TriggerRecalculation()
+		}
+	}
+
+	if err := m.stateManager.UpdateState((*SelectorState)(m.routeSelector)); err != nil {
+		log.Errorf("failed to update state: %v", err)
+	}
+
+	return nil
+}
+
+func (m *DefaultManager) isExitNodePeer(peerKey string) bool {
+	for _, routes := range m.clientRoutes {
+		if !m.isExitNodeRoute(routes) {
+			continue
+		}
+		for _, r := range routes {
+			if r.Peer == peerKey {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // TriggerSelection triggers the selection of routes, stopping deselected watchers and starting newly selected ones
 func (m *DefaultManager) TriggerSelection(networks route.HAMap) {
 	m.mux.Lock()
@@ -459,6 +595,12 @@ func (m *DefaultManager) TriggerSelection(networks route.HAMap) {
 
 	m.notifier.OnNewRoutes(networks)
 
+	if m.excludesFromExitNode() {
+		if err := m.updateExitNodeExclusions(networks); err != nil {
+			log.Errorf("failed to exclude destinations from exit node during selection: %v", err)
+		}
+	}
+
 	if err := m.updateSystemRoutes(networks); err != nil {
 		log.Errorf("failed to update system routes during selection: %v", err)
 	}
@@ -484,6 +626,11 @@ func (m *DefaultManager) TriggerSelection(networks route.HAMap) {
 			StatusRecorder:   m.statusRecorder,
 			Route:            routes[0],
 			Handler:          handler,
+			RouteSelector:    m.routeSelector,
+			OnFailover:       m.onRouteFailover,
+			ExitNodeFailsafe: m.exitNodeFailsafe,
+			DisconnectGrace:  m.disconnectGrace,
+			EventBus:         m.eventBus,
 		}
 		clientNetworkWatcher := client.NewWatcher(config)
 		m.clientNetworks[id] = clientNetworkWatcher
@@ -530,6 +677,11 @@ func (m *DefaultManager) updateClientNetworks(updateSerial uint64, networks rout
 				StatusRecorder:   m.statusRecorder,
 				Route:            routes[0],
 				Handler:          handler,
+				RouteSelector:    m.routeSelector,
+				OnFailover:       m.onRouteFailover,
+				ExitNodeFailsafe: m.exitNodeFailsafe,
+				DisconnectGrace:  m.disconnectGrace,
+				EventBus:         m.eventBus,
 			}
 			clientNetworkWatcher = client.NewWatcher(config)
 			m.clientNetworks[id] = clientNetworkWatcher
@@ -0,0 +1,191 @@
+// Package metrics records how long programming routes and firewall rules takes
+package metrics
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// defaultBounds are the upper bounds of the latency buckets, an implicit last bucket counts everything above
+var defaultBounds = []time.Duration{
+	100 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+// Bucket is the number of observations up to UpperBound. A zero UpperBound marks the overflow bucket.
+type Bucket struct {
+	UpperBound time.Duration
+	Count      uint64
+}
+
+// HistogramSnapshot is a copy of a histogram at one point in time
+type HistogramSnapshot struct {
+	Count   uint64
+	Sum     time.Duration
+	Max     time.Duration
+	Buckets []Bucket
+}
+
+// Average returns the mean observed duration
+func (s HistogramSnapshot) Average() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / time.Duration(s.Count)
+}
+
+// Histogram counts durations in fixed latency buckets
+type Histogram struct {
+	mu     sync.Mutex
+	bounds []time.Duration
+	counts []uint64
+	count  uint64
+	sum    time.Duration
+	max    time.Duration
+}
+
+// NewHistogram creates a histogram with the default latency buckets
+func NewHistogram() *Histogram {
+	return &Histogram{
+		bounds: defaultBounds,
+		counts: make([]uint64, len(defaultBounds)+1),
+	}
+}
+
+// Observe adds a duration to the histogram
+func (h *Histogram) Observe(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	i := 0
+	for i < len(h.bounds) && d > h.bounds[i] {
+		i++
+	}
+	h.counts[i]++
+	h.count++
+	h.sum += d
+	if d > h.max {
+		h.max = d
+	}
+}
+
+// Snapshot returns a copy of the histogram
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := HistogramSnapshot{
+		Count:   h.count,
+		Sum:     h.sum,
+		Max:     h.max,
+		Buckets: make([]Bucket, 0, len(h.counts)),
+	}
+	for i, count := range h.counts {
+		var bound time.Duration
+		if i < len(h.bounds) {
+			bound = h.bounds[i]
+		}
+		s.Buckets = append(s.Buckets, Bucket{UpperBound: bound, Count: count})
+	}
+	return s
+}
+
+// NetworkMapUpdate describes how long applying the routes and route firewall rules of one network map took
+type NetworkMapUpdate struct {
+	Serial           uint64
+	Time             time.Time
+	ClientRoutes     int
+	ServerRoutes     int
+	RoutesDuration   time.Duration
+	FirewallRules    int
+	FirewallDuration time.Duration
+}
+
+// Snapshot is a copy of all route programming metrics
+type Snapshot struct {
+	// OS is the operating system the syscall latencies were measured on
+	OS          string
+	RouteAdd    HistogramSnapshot
+	RouteRemove HistogramSnapshot
+	Routes      HistogramSnapshot
+	Firewall    HistogramSnapshot
+	LastUpdate  *NetworkMapUpdate
+}
+
+// Recorder collects the route programming metrics of an engine
+type Recorder struct {
+	routeAdd    *Histogram
+	routeRemove *Histogram
+	routes      *Histogram
+	firewall    *Histogram
+
+	mu         sync.Mutex
+	lastUpdate *NetworkMapUpdate
+}
+
+// NewRecorder creates an empty recorder
+func NewRecorder() *Recorder {
+	return &Recorder{
+		routeAdd:    NewHistogram(),
+		routeRemove: NewHistogram(),
+		routes:      NewHistogram(),
+		firewall:    NewHistogram(),
+	}
+}
+
+// ObserveRouteAdd records the duration of a single route add syscall
+func (r *Recorder) ObserveRouteAdd(d time.Duration) {
+	if r == nil {
+		return
+	}
+	r.routeAdd.Observe(d)
+}
+
+// ObserveRouteRemove records the duration of a single route remove syscall
+func (r *Recorder) ObserveRouteRemove(d time.Duration) {
+	if r == nil {
+		return
+	}
+	r.routeRemove.Observe(d)
+}
+
+// ObserveNetworkMap records how long applying the routes and route firewall rules of a network map took
+func (r *Recorder) ObserveNetworkMap(update NetworkMapUpdate) {
+	if r == nil {
+		return
+	}
+	r.routes.Observe(update.RoutesDuration)
+	r.firewall.Observe(update.FirewallDuration)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastUpdate = &update
+}
+
+// Snapshot returns a copy of the recorded metrics
+func (r *Recorder) Snapshot() Snapshot {
+	s := Snapshot{
+		OS:          runtime.GOOS,
+		RouteAdd:    r.routeAdd.Snapshot(),
+		RouteRemove: r.routeRemove.Snapshot(),
+		Routes:      r.routes.Snapshot(),
+		Firewall:    r.firewall.Snapshot(),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.lastUpdate != nil {
+		last := *r.lastUpdate
+		s.LastUpdate = &last
+	}
+	return s
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogram_Observe(t *testing.T) {
+	h := NewHistogram()
+	h.Observe(50 * time.Microsecond)
+	h.Observe(100 * time.Microsecond)
+	h.Observe(2 * time.Millisecond)
+	h.Observe(10 * time.Second)
+
+	s := h.Snapshot()
+	require.Len(t, s.Buckets, len(defaultBounds)+1)
+	assert.Equal(t, uint64(4), s.Count)
+	assert.Equal(t, 10*time.Second, s.Max)
+	assert.Equal(t, uint64(2), s.Buckets[0].Count, "bounds are inclusive")
+	assert.Equal(t, uint64(1), s.Buckets[3].Count)
+	assert.Equal(t, uint64(1), s.Buckets[len(s.Buckets)-1].Count)
+	assert.Zero(t, s.Buckets[len(s.Buckets)-1].UpperBound)
+	assert.Equal(t, (10*time.Second+2*time.Millisecond+150*time.Microsecond)/4, s.Average())
+}
+
+func TestRecorder_ObserveNetworkMap(t *testing.T) {
+	var nilRecorder *Recorder
+	nilRecorder.ObserveRouteAdd(time.Millisecond)
+	nilRecorder.ObserveNetworkMap(NetworkMapUpdate{})
+
+	r := NewRecorder()
+	assert.Nil(t, r.Snapshot().LastUpdate)
+
+	r.ObserveRouteAdd(time.Millisecond)
+	r.ObserveNetworkMap(NetworkMapUpdate{Serial: 1, ClientRoutes: 3, RoutesDuration: time.Millisecond, FirewallDuration: 2 * time.Millisecond})
+	r.ObserveNetworkMap(NetworkMapUpdate{Serial: 2, ClientRoutes: 5, RoutesDuration: 3 * time.Millisecond})
+
+	s := r.Snapshot()
+	require.NotNil(t, s.LastUpdate)
+	assert.Equal(t, uint64(2), s.LastUpdate.Serial)
+	assert.Equal(t, 5, s.LastUpdate.ClientRoutes)
+	assert.Equal(t, uint64(1), s.RouteAdd.Count)
+	assert.Equal(t, uint64(2), s.Routes.Count)
+	assert.Equal(t, 4*time.Millisecond, s.Routes.Sum)
+	assert.Equal(t, 2*time.Millisecond, s.Firewall.Max)
+}
@@ -2,6 +2,8 @@ package routemanager
 
 import (
 	"context"
+	"net/netip"
+	"time"
 
 	firewall "github.com/netbirdio/netbird/client/firewall/manager"
 	"github.com/netbirdio/netbird/client/iface"
@@ -19,6 +21,9 @@ type MockManager struct {
 	GetRouteSelectorFunc         func() *routeselector.RouteSelector
 	GetClientRoutesFunc          func() route.HAMap
 	GetClientRoutesWithNetIDFunc func() map[route.NetID][]*route.Route
+	ServerRoutesCountFunc        func() int
+	AllowRouteFailoverFunc       func(peerIP netip.Addr, networks []netip.Prefix, grace time.Duration) error
+	PinExitNodeFunc              func(peerKey string) error
 	StopFunc                     func(manager *statemanager.Manager)
 }
 
@@ -31,6 +36,11 @@ func (m *MockManager) InitialRouteRange() []string {
 	return nil
 }
 
+// ProtectEndpoints mock implementation of ProtectEndpoints from Manager interface
+func (m *MockManager) ProtectEndpoints() error {
+	return nil
+}
+
 // UpdateRoutes mock implementation of UpdateRoutes from Manager interface
 func (m *MockManager) UpdateRoutes(updateSerial uint64, newRoutes map[route.ID]*route.Route, clientRoutes route.HAMap, useNewDNSRoute bool) error {
 	if m.UpdateRoutesFunc != nil {
@@ -77,6 +87,14 @@ func (m *MockManager) GetClientRoutesWithNetID() map[route.NetID][]*route.Route
 	return nil
 }
 
+// PinExitNode mock implementation of PinExitNode from Manager interface
+func (m *MockManager) PinExitNode(peerKey string) error {
+	if m.PinExitNodeFunc != nil {
+		return m.PinExitNodeFunc(peerKey)
+	}
+	return nil
+}
+
 // Start mock implementation of Start from Manager interface
 func (m *MockManager) Start(ctx context.Context, iface *iface.WGIface) {
 }
@@ -94,6 +112,22 @@ func (m *MockManager) SetFirewall(firewall.Manager) error {
 func (m *MockManager) SetDNSForwarderPort(port uint16) {
 }
 
+// ServerRoutesCount mock implementation of ServerRoutesCount from Manager interface
+func (m *MockManager) ServerRoutesCount() int {
+	if m.ServerRoutesCountFunc != nil {
+		return m.ServerRoutesCountFunc()
+	}
+	return 0
+}
+
+// AllowRouteFailover mock implementation of AllowRouteFailover from Manager interface
+func (m *MockManager) AllowRouteFailover(peerIP netip.Addr, networks []netip.Prefix, grace time.Duration) error {
+	if m.AllowRouteFailoverFunc != nil {
+		return m.AllowRouteFailoverFunc(peerIP, networks, grace)
+	}
+	return nil
+}
+
 // Stop mock implementation of Stop from Manager interface
 func (m *MockManager) Stop(stateManager *statemanager.Manager) {
 	if m.StopFunc != nil {
@@ -94,6 +94,33 @@ func (rm *Counter[Key, I, O]) Get(key Key) (Ref[O], bool) {
 	return ref, ok
 }
 
+// Keys returns the keys that currently hold a reference
+func (rm *Counter[Key, I, O]) Keys() []Key {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	keys := make([]Key, 0, len(rm.refCountMap))
+	for key := range rm.refCountMap {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// UpdateOut replaces the data stored for an existing key, for resources that were recreated outside the counter.
+// It returns false if the key doesn't exist.
+func (rm *Counter[Key, I, O]) UpdateOut(key Key, out O) bool {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	ref, ok := rm.refCountMap[key]
+	if !ok {
+		return false
+	}
+	ref.Out = out
+	rm.refCountMap[key] = ref
+	return true
+}
+
 // Increment increments the reference count for the given key.
 // If this is the first reference to the key, the AddFunc is called.
 func (rm *Counter[Key, I, O]) Increment(key Key, in I) (Ref[O], error) {
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	log "github.com/sirupsen/logrus"
+
+	nberrors "github.com/netbirdio/netbird/client/errors"
+	firewall "github.com/netbirdio/netbird/client/firewall/manager"
+	"github.com/netbirdio/netbird/route"
+)
+
+// failover is a temporary rule accepting the connections of a peer that failed over from another routing peer
+type failover struct {
+	rule  firewall.Rule
+	timer *time.Timer
+}
+
+// AllowFailover accepts traffic from the networks to the peer regardless of the connection state for the grace period.
+// Without it, the connections the peer established through the previous routing peer are dropped here until the
+// peer sends a packet, which doesn't happen for connections where only the other side talks.
+// Masqueraded networks are skipped, their connections are bound to the address of the previous routing peer.
+// A repeated call for the same peer and network extends the grace period.
+func (r *Router) AllowFailover(peerIP netip.Addr, networks []netip.Prefix, grace time.Duration) error {
+	fw, ok := r.firewall.(firewall.RouteFailover)
+	if !ok {
+		return errors.New("firewall doesn't support failover rules")
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	var merr *multierror.Error
+	for _, network := range networks {
+		rt := r.routeForNetwork(network)
+		if rt == nil {
+			merr = multierror.Append(merr, fmt.Errorf("network %s is not routed by this peer", network))
+			continue
+		}
+		if rt.Masquerade {
+			log.Debugf("skipping failover rule for masqueraded network %s", network)
+			continue
+		}
+
+		key := fmt.Sprintf("%s-%s", peerIP, network.Masked())
+		if existing, ok := r.failovers[key]; ok {
+			existing.timer.Stop()
+			r.failovers[key] = r.newFailover(key, existing.rule, grace)
+			continue
+		}
+
+		rule, err := fw.AllowRouteFailover(peerIP, network)
+		if rule == nil {
+			merr = multierror.Append(merr, fmt.Errorf("allow failover for %s: %w", network, err))
+			continue
+		}
+		if err != nil {
+			// the rule was added partially, keep it so it is removed again
+			merr = multierror.Append(merr, fmt.Errorf("allow failover for %s: %w", network, err))
+		}
+
+		r.failovers[key] = r.newFailover(key, rule, grace)
+		log.Infof("accepting failed over connections of %s to %s for %s", peerIP, network, grace)
+	}
+
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+// routeForNetwork returns the static route of the network, dynamic routes are resolved per connection
+// and can't be matched with a prefix
+func (r *Router) routeForNetwork(network netip.Prefix) *route.Route {
+	for _, rt := range r.routes {
+		if !rt.IsDynamic() && rt.Network.Masked() == network.Masked() {
+			return rt
+		}
+	}
+	return nil
+}
+
+func (r *Router) newFailover(key string, rule firewall.Rule, grace time.Duration) *failover {
+	f := &failover{rule: rule}
+	f.timer = time.AfterFunc(grace, func() {
+		r.mux.Lock()
+		defer r.mux.Unlock()
+
+		// the grace period was extended in the meantime
+		if r.failovers[key] != f {
+			return
+		}
+		r.removeFailover(key, f)
+	})
+	return f
+}
+
+func (r *Router) removeFailover(key string, f *failover) {
+	delete(r.failovers, key)
+
+	fw, ok := r.firewall.(firewall.RouteFailover)
+	if !ok {
+		return
+	}
+	if err := fw.RemoveRouteFailover(f.rule); err != nil {
+		log.Errorf("failed to remove failover rule %s: %v", key, err)
+		return
+	}
+	log.Debugf("removed failover rule %s", key)
+}
+
+// removeFailovers removes all failover rules, callers must hold the lock
+func (r *Router) removeFailovers() {
+	for key, f := range r.failovers {
+		f.timer.Stop()
+		r.removeFailover(key, f)
+	}
+}
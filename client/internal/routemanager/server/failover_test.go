@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	firewall "github.com/netbirdio/netbird/client/firewall/manager"
+	nbid "github.com/netbirdio/netbird/client/internal/acl/id"
+	"github.com/netbirdio/netbird/route"
+)
+
+type failoverFirewall struct {
+	firewall.Manager
+
+	mu    sync.Mutex
+	rules map[string]struct{}
+}
+
+func (f *failoverFirewall) AllowRouteFailover(peer netip.Addr, network netip.Prefix) (firewall.Rule, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := peer.String() + "-" + network.String()
+	f.rules[id] = struct{}{}
+	return nbid.RuleID(id), nil
+}
+
+func (f *failoverFirewall) RemoveRouteFailover(rule firewall.Rule) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.rules, rule.ID())
+	return nil
+}
+
+func (f *failoverFirewall) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.rules)
+}
+
+func TestRouter_AllowFailover(t *testing.T) {
+	fw := &failoverFirewall{rules: make(map[string]struct{})}
+	router, err := NewRouter(context.Background(), nil, fw, nil)
+	require.NoError(t, err)
+
+	router.routes = map[route.ID]*route.Route{
+		"lan":  {ID: "lan", Network: netip.MustParsePrefix("192.168.1.0/24")},
+		"masq": {ID: "masq", Network: netip.MustParsePrefix("10.0.0.0/8"), Masquerade: true},
+	}
+
+	peerIP := netip.MustParseAddr("100.64.0.10")
+
+	err = router.AllowFailover(peerIP, []netip.Prefix{netip.MustParsePrefix("172.16.0.0/12")}, time.Minute)
+	assert.Error(t, err, "networks that aren't routed by the peer are rejected")
+
+	err = router.AllowFailover(peerIP, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 0, fw.count(), "masqueraded networks are skipped")
+
+	lan := []netip.Prefix{netip.MustParsePrefix("192.168.1.0/24")}
+	require.NoError(t, router.AllowFailover(peerIP, lan, 100*time.Millisecond))
+	assert.Equal(t, 1, fw.count())
+
+	// extending the grace period keeps the rule
+	require.NoError(t, router.AllowFailover(peerIP, lan, 300*time.Millisecond))
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, 1, fw.count(), "the extended grace period isn't over yet")
+
+	assert.Eventually(t, func() bool { return fw.count() == 0 }, time.Second, 10*time.Millisecond,
+		"the rule is removed after the grace period")
+}
+
+func TestRouter_RemoveFailovers(t *testing.T) {
+	fw := &failoverFirewall{rules: make(map[string]struct{})}
+	router, err := NewRouter(context.Background(), nil, fw, nil)
+	require.NoError(t, err)
+
+	router.routes = map[route.ID]*route.Route{
+		"lan": {ID: "lan", Network: netip.MustParsePrefix("192.168.1.0/24")},
+	}
+
+	lan := []netip.Prefix{netip.MustParsePrefix("192.168.1.0/24")}
+	require.NoError(t, router.AllowFailover(netip.MustParseAddr("100.64.0.10"), lan, time.Hour))
+	require.NoError(t, router.AllowFailover(netip.MustParseAddr("100.64.0.11"), lan, time.Hour))
+	assert.Equal(t, 2, fw.count())
+
+	router.mux.Lock()
+	router.removeFailovers()
+	router.mux.Unlock()
+	assert.Equal(t, 0, fw.count())
+}
@@ -9,27 +9,30 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	firewall "github.com/netbirdio/netbird/client/firewall/manager"
-	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/eventbus"
 	"github.com/netbirdio/netbird/client/internal/routemanager/iface"
 	"github.com/netbirdio/netbird/route"
 )
 
 type Router struct {
-	mux            sync.Mutex
-	ctx            context.Context
-	routes         map[route.ID]*route.Route
-	firewall       firewall.Manager
-	wgInterface    iface.WGIface
-	statusRecorder *peer.Status
+	mux         sync.Mutex
+	ctx         context.Context
+	routes      map[route.ID]*route.Route
+	firewall    firewall.Manager
+	wgInterface iface.WGIface
+	eventBus    *eventbus.Bus
+	// failovers holds the rules that accept failed over connections until their grace period ends
+	failovers map[string]*failover
 }
 
-func NewRouter(ctx context.Context, wgInterface iface.WGIface, firewall firewall.Manager, statusRecorder *peer.Status) (*Router, error) {
+func NewRouter(ctx context.Context, wgInterface iface.WGIface, firewall firewall.Manager, eventBus *eventbus.Bus) (*Router, error) {
 	return &Router{
-		ctx:            ctx,
-		routes:         make(map[route.ID]*route.Route),
-		firewall:       firewall,
-		wgInterface:    wgInterface,
-		statusRecorder: statusRecorder,
+		ctx:         ctx,
+		routes:      make(map[route.ID]*route.Route),
+		firewall:    firewall,
+		wgInterface: wgInterface,
+		eventBus:    eventBus,
+		failovers:   make(map[string]*failover),
 	}, nil
 }
 
@@ -97,7 +100,7 @@ func (r *Router) removeFromServerNetwork(route *route.Route) error {
 	}
 
 	delete(r.routes, route.ID)
-	r.statusRecorder.RemoveLocalPeerStateRoute(route.NetString())
+	r.eventBus.Publish(eventbus.RouteChanged{Network: route.NetString(), ResourceID: route.GetResourceID(), Removed: true})
 
 	return nil
 }
@@ -114,7 +117,7 @@ func (r *Router) addToServerNetwork(route *route.Route, useNewDNSRoute bool) err
 	}
 
 	r.routes[route.ID] = route
-	r.statusRecorder.AddLocalPeerStateRoute(route.NetString(), route.GetResourceID())
+	r.eventBus.Publish(eventbus.RouteChanged{Network: route.NetString(), ResourceID: route.GetResourceID()})
 
 	return nil
 }
@@ -128,9 +131,10 @@ func (r *Router) CleanUp() {
 		if err := r.firewall.RemoveNatRule(routerPair); err != nil {
 			log.Errorf("Failed to remove cleanup route: %v", err)
 		}
+		r.eventBus.Publish(eventbus.RouteChanged{Network: route.NetString(), ResourceID: route.GetResourceID(), Removed: true})
 	}
 
-	r.statusRecorder.CleanLocalPeerStateRoutes()
+	r.removeFailovers()
 }
 
 func (r *Router) RoutesCount() int {
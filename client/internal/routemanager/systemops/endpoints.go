@@ -0,0 +1,148 @@
+//go:build !android && !ios
+
+package systemops
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+
+	"github.com/hashicorp/go-multierror"
+	log "github.com/sirupsen/logrus"
+
+	nberrors "github.com/netbirdio/netbird/client/errors"
+	"github.com/netbirdio/netbird/client/internal/routemanager/vars"
+	"github.com/netbirdio/netbird/client/internal/statemanager"
+)
+
+// ProtectEndpoints keeps host routes via the physical interface for the given addresses of the NetBird services, so
+// client routes covering them, like an exit node, don't cut the client off from management, signal or the relays.
+// Addresses missing from the list lose their host routes. When the default next hop of the physical interface changed,
+// the exclusion routes pinned to the previous one are moved to the new one, the ones of the peer endpoints as well.
+func (r *SysOps) ProtectEndpoints(addrs []netip.Addr, stateManager *statemanager.Manager) error {
+	if r.refCounter == nil {
+		// the routing is separated by other means or not set up, the services don't need host routes
+		return nil
+	}
+
+	r.protectedEndpointsMu.Lock()
+	defer r.protectedEndpointsMu.Unlock()
+
+	if r.protectedEndpoints == nil {
+		r.protectedEndpoints = make(map[netip.Prefix]struct{})
+	}
+
+	previousV4, previousV6 := r.refreshDefaultNexthops()
+
+	wanted := make(map[netip.Prefix]struct{}, len(addrs))
+	for _, addr := range addrs {
+		addr = addr.Unmap()
+		wanted[netip.PrefixFrom(addr, addr.BitLen())] = struct{}{}
+	}
+
+	var merr *multierror.Error
+
+	for _, prefix := range r.refCounter.Keys() {
+		previous := previousV4
+		if prefix.Addr().Is6() {
+			previous = previousV6
+		}
+		if err := r.repinExclusion(prefix, previous); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("move exclusion route for %s: %w", prefix, err))
+		}
+	}
+
+	// add the new routes before removing the stale ones, the services must stay reachable in between
+	for prefix := range wanted {
+		if _, ok := r.protectedEndpoints[prefix]; ok {
+			continue
+		}
+
+		ref, err := r.refCounter.Increment(prefix, struct{}{})
+		if err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("add host route for %s: %w", prefix, err))
+			continue
+		}
+		// ignored prefixes, like the ones in local subnets, don't hold a reference
+		if ref.Count > 0 {
+			log.Debugf("Protecting NetBird service address %s with a host route via %s", prefix, ref.Out)
+			r.protectedEndpoints[prefix] = struct{}{}
+		}
+	}
+
+	for prefix := range r.protectedEndpoints {
+		if _, ok := wanted[prefix]; ok {
+			continue
+		}
+		if _, err := r.refCounter.Decrement(prefix); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("remove host route for %s: %w", prefix, err))
+		}
+		delete(r.protectedEndpoints, prefix)
+	}
+
+	r.updateState(stateManager)
+
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+// repinExclusion moves the exclusion route for the prefix to the current default next hop if it still uses the previous
+// one
+func (r *SysOps) repinExclusion(prefix netip.Prefix, previous Nexthop) error {
+	current := r.defaultNexthop(prefix.Addr())
+	if !current.IP.IsValid() && current.Intf == nil || current.Equal(previous) {
+		return nil
+	}
+
+	ref, ok := r.refCounter.Get(prefix)
+	if !ok || !ref.Out.Equal(previous) {
+		return nil
+	}
+
+	log.Infof("Default next hop changed from %s to %s, moving the exclusion route for %s", previous, current, prefix)
+	if err := r.removeFromRouteTable(prefix, previous); err != nil {
+		log.Debugf("Failed to remove exclusion route for %s via %s: %v", prefix, previous, err)
+	}
+	if err := r.addToRouteTable(prefix, current); err != nil {
+		return fmt.Errorf("add route to table: %w", err)
+	}
+	r.refCounter.UpdateOut(prefix, current)
+	return nil
+}
+
+// refreshDefaultNexthops looks up the default next hops of the physical interfaces and returns the previous ones.
+// A default route pointing to the VPN interface keeps the previous next hop.
+func (r *SysOps) refreshDefaultNexthops() (Nexthop, Nexthop) {
+	r.defaultNexthopMu.Lock()
+	defer r.defaultNexthopMu.Unlock()
+
+	previousV4, previousV6 := r.defaultNexthopV4, r.defaultNexthopV6
+	r.defaultNexthopV4 = r.lookupDefaultNexthop(netip.IPv4Unspecified(), previousV4)
+	r.defaultNexthopV6 = r.lookupDefaultNexthop(netip.IPv6Unspecified(), previousV6)
+
+	return previousV4, previousV6
+}
+
+func (r *SysOps) lookupDefaultNexthop(addr netip.Addr, previous Nexthop) Nexthop {
+	nexthop, err := GetNextHop(addr)
+	if err != nil {
+		if !errors.Is(err, vars.ErrRouteNotFound) {
+			log.Warnf("Failed to get default next hop for %s: %v", addr, err)
+		}
+		return previous
+	}
+	if isVPNNexthop(nexthop, r.wgInterface) {
+		return previous
+	}
+	return nexthop
+}
+
+// defaultNexthop returns the default next hop of the physical interface for the address family of addr
+func (r *SysOps) defaultNexthop(addr netip.Addr) Nexthop {
+	r.defaultNexthopMu.RLock()
+	defer r.defaultNexthopMu.RUnlock()
+
+	if addr.Is6() {
+		return r.defaultNexthopV6
+	}
+	return r.defaultNexthopV4
+}
@@ -0,0 +1,92 @@
+//go:build !android && !ios
+
+package systemops
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+
+	"github.com/hashicorp/go-multierror"
+	log "github.com/sirupsen/logrus"
+
+	nberrors "github.com/netbirdio/netbird/client/errors"
+	"github.com/netbirdio/netbird/client/internal/statemanager"
+)
+
+var errExclusionRoutingNotSetUp = errors.New("routing is not set up")
+
+// ExcludeFromVPN keeps the given prefixes routed via the physical interface while a default route points to the VPN,
+// so destinations like local printers or another VPN bypass an exit node. Prefixes missing from the list lose their
+// bypass routes, an empty list removes all of them.
+func (r *SysOps) ExcludeFromVPN(prefixes []netip.Prefix, stateManager *statemanager.Manager) error {
+	r.vpnExclusionsMu.Lock()
+	defer r.vpnExclusionsMu.Unlock()
+
+	if r.vpnExclusions == nil {
+		r.vpnExclusions = make(map[netip.Prefix]struct{})
+	}
+
+	wanted := make(map[netip.Prefix]struct{}, len(prefixes))
+	for _, prefix := range prefixes {
+		wanted[prefix.Masked()] = struct{}{}
+	}
+
+	var merr *multierror.Error
+
+	for prefix := range wanted {
+		if _, ok := r.vpnExclusions[prefix]; ok {
+			continue
+		}
+
+		tracked, err := r.addVPNExclusion(prefix)
+		if err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("add bypass route for %s: %w", prefix, err))
+			continue
+		}
+		// ignored prefixes, like the ones in local subnets, don't hold a route
+		if tracked {
+			log.Debugf("Excluding %s from the VPN with a bypass route", prefix)
+			r.vpnExclusions[prefix] = struct{}{}
+		}
+	}
+
+	for prefix := range r.vpnExclusions {
+		if _, ok := wanted[prefix]; ok {
+			continue
+		}
+		if err := r.removeVPNExclusion(prefix); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("remove bypass route for %s: %w", prefix, err))
+		}
+		delete(r.vpnExclusions, prefix)
+	}
+
+	if r.refCounter != nil {
+		r.updateState(stateManager)
+	}
+
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+// addCountedExclusion adds a bypass route via the default next hop of the physical interface and reports whether it
+// holds a reference
+func addCountedExclusion(counter *ExclusionCounter, prefix netip.Prefix) (bool, error) {
+	ref, err := counter.Increment(prefix, struct{}{})
+	if err != nil {
+		return false, err
+	}
+	return ref.Count > 0, nil
+}
+
+func removeCountedExclusion(counter *ExclusionCounter, prefix netip.Prefix) error {
+	_, err := counter.Decrement(prefix)
+	return err
+}
+
+// resetVPNExclusions forgets the bypass routes, the caller removed them with the rest of the routing setup
+func (r *SysOps) resetVPNExclusions() {
+	r.vpnExclusionsMu.Lock()
+	defer r.vpnExclusionsMu.Unlock()
+
+	r.vpnExclusions = nil
+}
@@ -0,0 +1,34 @@
+//go:build !android
+
+package systemops
+
+import (
+	"net/netip"
+
+	nbnet "github.com/netbirdio/netbird/client/net"
+)
+
+// addVPNExclusion adds a throw route to the VPN table with advanced routing, the lookup falls through to the main
+// table and its default route. The legacy routing adds a route via the default next hop instead.
+func (r *SysOps) addVPNExclusion(prefix netip.Prefix) (bool, error) {
+	if nbnet.AdvancedRouting() {
+		if err := addThrowRoute(prefix, vpnTableID); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	if r.refCounter == nil {
+		return false, errExclusionRoutingNotSetUp
+	}
+	return addCountedExclusion(r.refCounter, prefix)
+}
+
+func (r *SysOps) removeVPNExclusion(prefix netip.Prefix) error {
+	if nbnet.AdvancedRouting() {
+		return removeThrowRoute(prefix, vpnTableID)
+	}
+	if r.refCounter == nil {
+		return errExclusionRoutingNotSetUp
+	}
+	return removeCountedExclusion(r.refCounter, prefix)
+}
@@ -0,0 +1,36 @@
+//go:build !linux && !android && !ios
+
+package systemops
+
+import (
+	"net/netip"
+	"runtime"
+)
+
+func (r *SysOps) addVPNExclusion(prefix netip.Prefix) (bool, error) {
+	counter := r.exclusionCounter()
+	if counter == nil {
+		return false, errExclusionRoutingNotSetUp
+	}
+	return addCountedExclusion(counter, prefix)
+}
+
+func (r *SysOps) removeVPNExclusion(prefix netip.Prefix) error {
+	counter := r.exclusionCounter()
+	if counter == nil {
+		return errExclusionRoutingNotSetUp
+	}
+	return removeCountedExclusion(counter, prefix)
+}
+
+// exclusionCounter returns the counter of the exclusion routes. The advanced routing on Windows doesn't set one up,
+// the bypass routes get their own there.
+func (r *SysOps) exclusionCounter() *ExclusionCounter {
+	if r.refCounter != nil || runtime.GOOS != "windows" {
+		return r.refCounter
+	}
+	if r.vpnExclusionCounter == nil {
+		r.vpnExclusionCounter = r.newExclusionCounter()
+	}
+	return r.vpnExclusionCounter
+}
@@ -81,6 +81,30 @@ type SysOps struct {
 	localSubnetsCache     []*net.IPNet
 	localSubnetsCacheMu   sync.RWMutex
 	localSubnetsCacheTime time.Time
+
+	// defaultNexthopV4 and defaultNexthopV6 are the next hops of the physical default routes, used for exclusion
+	// routes whose own next hop points to the VPN interface
+	//nolint:unused // not used on mobile systems
+	defaultNexthopV4 Nexthop
+	//nolint:unused // not used on mobile systems
+	defaultNexthopV6 Nexthop
+	//nolint:unused // not used on mobile systems
+	defaultNexthopMu sync.RWMutex
+
+	// protectedEndpoints are the host routes for the NetBird services added by ProtectEndpoints
+	//nolint:unused // not used on mobile systems
+	protectedEndpoints map[netip.Prefix]struct{}
+	//nolint:unused // not used on mobile systems
+	protectedEndpointsMu sync.Mutex
+
+	// vpnExclusions are the bypass routes added by ExcludeFromVPN
+	//nolint:unused // not used on mobile systems
+	vpnExclusions map[netip.Prefix]struct{}
+	// vpnExclusionCounter holds the bypass routes when the routing doesn't set up the ref counter
+	//nolint:unused // only used on Windows
+	vpnExclusionCounter *ExclusionCounter
+	//nolint:unused // not used on mobile systems
+	vpnExclusionsMu sync.Mutex
 }
 
 func New(wgInterface wgIface, notifier *notifier.Notifier) *SysOps {
@@ -32,6 +32,14 @@ func (r *SysOps) removeFromRouteTable(netip.Prefix, Nexthop) error {
 	return nil
 }
 
+func (r *SysOps) ProtectEndpoints([]netip.Addr, *statemanager.Manager) error {
+	return nil
+}
+
+func (r *SysOps) ExcludeFromVPN([]netip.Prefix, *statemanager.Manager) error {
+	return nil
+}
+
 func EnableIPForwarding() error {
 	log.Infof("Enable IP forwarding is not implemented on %s", runtime.GOOS)
 	return nil
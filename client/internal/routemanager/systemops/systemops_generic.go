@@ -45,27 +45,24 @@ func (r *SysOps) setupRefCounter(initAddresses []net.IP, stateManager *statemana
 		log.Errorf("Unable to get initial v6 default next hop: %v", err)
 	}
 
-	refCounter := refcounter.New(
-		func(prefix netip.Prefix, _ struct{}) (Nexthop, error) {
-			initialNexthop := initialNextHopV4
-			if prefix.Addr().Is6() {
-				initialNexthop = initialNextHopV6
-			}
+	r.defaultNexthopMu.Lock()
+	r.defaultNexthopV4 = initialNextHopV4
+	r.defaultNexthopV6 = initialNextHopV6
+	r.defaultNexthopMu.Unlock()
 
-			nexthop, err := r.addRouteToNonVPNIntf(prefix, r.wgInterface, initialNexthop)
-			if errors.Is(err, vars.ErrRouteNotAllowed) || errors.Is(err, vars.ErrRouteNotFound) {
-				log.Tracef("Adding for prefix %s: %v", prefix, err)
-				// These errors are not critical, but also we should not track and try to remove the routes either.
-				return nexthop, refcounter.ErrIgnore
-			}
+	r.refCounter = r.newExclusionCounter()
 
-			return nexthop, err
-		},
-		r.removeFromRouteTable,
-	)
+	if err := r.setupHooks(initAddresses, stateManager); err != nil {
+		return fmt.Errorf("setup hooks: %w", err)
+	}
+	return nil
+}
 
+// newExclusionCounter returns a counter of routes via the default next hop of the physical interface. Routes for
+// local subnets aren't added and in netstack mode no routes are added at all.
+func (r *SysOps) newExclusionCounter() *ExclusionCounter {
 	if netstack.IsEnabled() {
-		refCounter = refcounter.New(
+		return refcounter.New(
 			func(netip.Prefix, struct{}) (Nexthop, error) {
 				return Nexthop{}, refcounter.ErrIgnore
 			},
@@ -75,12 +72,25 @@ func (r *SysOps) setupRefCounter(initAddresses []net.IP, stateManager *statemana
 		)
 	}
 
-	r.refCounter = refCounter
+	return refcounter.New(
+		func(prefix netip.Prefix, _ struct{}) (Nexthop, error) {
+			add := r.addRouteToNonVPNIntf
+			if prefix.Addr().Is4() && prefix.Addr().IsLinkLocalUnicast() {
+				// link-local services, like the cloud metadata service, can be reached via a gateway and need the
+				// route as well
+				add = r.addNonVPNRoute
+			}
+			nexthop, err := add(prefix, r.wgInterface, r.defaultNexthop(prefix.Addr()))
+			if errors.Is(err, vars.ErrRouteNotAllowed) || errors.Is(err, vars.ErrRouteNotFound) {
+				log.Tracef("Adding for prefix %s: %v", prefix, err)
+				// These errors are not critical, but also we should not track and try to remove the routes either.
+				return nexthop, refcounter.ErrIgnore
+			}
 
-	if err := r.setupHooks(initAddresses, stateManager); err != nil {
-		return fmt.Errorf("setup hooks: %w", err)
-	}
-	return nil
+			return nexthop, err
+		},
+		r.removeFromRouteTable,
+	)
 }
 
 // updateState updates state on every change so it will be persisted regularly
@@ -103,6 +113,11 @@ func (r *SysOps) cleanupRefCounter(stateManager *statemanager.Manager) error {
 		return fmt.Errorf("flush route manager: %w", err)
 	}
 
+	r.protectedEndpointsMu.Lock()
+	r.protectedEndpoints = nil
+	r.protectedEndpointsMu.Unlock()
+	r.resetVPNExclusions()
+
 	if err := stateManager.DeleteState(&ShutdownState{}); err != nil {
 		return fmt.Errorf("delete state: %w", err)
 	}
@@ -116,7 +131,11 @@ func (r *SysOps) addRouteToNonVPNIntf(prefix netip.Prefix, vpnIntf wgIface, init
 	if err := r.validateRoute(prefix); err != nil {
 		return Nexthop{}, err
 	}
+	return r.addNonVPNRoute(prefix, vpnIntf, initialNextHop)
+}
 
+// addNonVPNRoute is addRouteToNonVPNIntf without the validation of the prefix
+func (r *SysOps) addNonVPNRoute(prefix netip.Prefix, vpnIntf wgIface, initialNextHop Nexthop) (Nexthop, error) {
 	addr := prefix.Addr()
 	if addr.IsUnspecified() {
 		return Nexthop{}, vars.ErrRouteNotAllowed
@@ -136,10 +155,8 @@ func (r *SysOps) addRouteToNonVPNIntf(prefix netip.Prefix, vpnIntf wgIface, init
 	log.Debugf("Found next hop %s for prefix %s with interface %v", nexthop.IP, prefix, nexthop.Intf)
 	exitNextHop := nexthop
 
-	vpnAddr := vpnIntf.Address().IP
-
 	// if next hop is the VPN address or the interface is the VPN interface, we should use the initial values
-	if exitNextHop.IP == vpnAddr || exitNextHop.Intf != nil && exitNextHop.Intf.Name == vpnIntf.Name() {
+	if isVPNNexthop(exitNextHop, vpnIntf) {
 		log.Debugf("Route for prefix %s is pointing to the VPN interface, using initial next hop %v", prefix, initialNextHop)
 		exitNextHop = initialNextHop
 	}
@@ -152,6 +169,10 @@ func (r *SysOps) addRouteToNonVPNIntf(prefix netip.Prefix, vpnIntf wgIface, init
 	return exitNextHop, nil
 }
 
+func isVPNNexthop(nexthop Nexthop, vpnIntf wgIface) bool {
+	return nexthop.IP == vpnIntf.Address().IP || nexthop.Intf != nil && nexthop.Intf.Name == vpnIntf.Name()
+}
+
 func (r *SysOps) isPrefixInLocalSubnets(prefix netip.Prefix) (bool, *net.IPNet) {
 	r.localSubnetsCacheMu.RLock()
 	cacheAge := time.Since(r.localSubnetsCacheTime)
@@ -9,6 +9,7 @@ import (
 	"net"
 	"net/netip"
 	"os"
+	"strconv"
 	"syscall"
 
 	"github.com/hashicorp/go-multierror"
@@ -43,7 +44,7 @@ type IPRule struct {
 }
 
 const (
-	// NetbirdVPNTableID is the ID of the custom routing table used by Netbird.
+	// NetbirdVPNTableID is the default ID of the custom routing table used by Netbird.
 	NetbirdVPNTableID = 0x1BD0
 	// NetbirdVPNTableName is the name of the custom routing table used by Netbird.
 	NetbirdVPNTableName = "netbird"
@@ -53,8 +54,58 @@ const (
 
 	// ipv4ForwardingPath is the path to the file containing the IP forwarding setting.
 	ipv4ForwardingPath = "net.ipv4.ip_forward"
+
+	// defaultRulePriority is the priority of the main table rule, the netbird table rule follows right after.
+	defaultRulePriority = 105
+	// netbirdRulePriorityOffset is the distance between the main table rule and the netbird table rule.
+	netbirdRulePriorityOffset = 5
+
+	envRoutingTableID      = "NB_ROUTING_TABLE_ID"
+	envRoutingRulePriority = "NB_ROUTING_RULE_PRIORITY"
 )
 
+// vpnTableID is the routing table the VPN routes are installed into, overridable with NB_ROUTING_TABLE_ID.
+var vpnTableID = NetbirdVPNTableID
+
+// rulePriority is the priority of the first ip rule, overridable with NB_ROUTING_RULE_PRIORITY.
+var rulePriority = defaultRulePriority
+
+func init() {
+	vpnTableID = parseRoutingEnv(envRoutingTableID, NetbirdVPNTableID, isValidTableID)
+	rulePriority = parseRoutingEnv(envRoutingRulePriority, defaultRulePriority, isValidRulePriority)
+}
+
+// parseRoutingEnv reads an integer routing setting from the environment, falling back to def if it is unset or invalid.
+func parseRoutingEnv(env string, def int, valid func(int) bool) int {
+	val := os.Getenv(env)
+	if val == "" {
+		return def
+	}
+
+	parsed, err := strconv.ParseInt(val, 0, 32)
+	if err != nil || !valid(int(parsed)) {
+		log.Warnf("invalid value %q for %s, using default %d", val, env, def)
+		return def
+	}
+
+	log.Infof("using %s=%d", env, parsed)
+	return int(parsed)
+}
+
+// isValidTableID rejects the reserved tables, routes must never end up in main, local or default.
+func isValidTableID(id int) bool {
+	switch id {
+	case syscall.RT_TABLE_UNSPEC, syscall.RT_TABLE_DEFAULT, syscall.RT_TABLE_MAIN, syscall.RT_TABLE_LOCAL:
+		return false
+	}
+	return id > 0
+}
+
+// isValidRulePriority keeps both rules ahead of the main (32766) and default (32767) table rules.
+func isValidRulePriority(priority int) bool {
+	return priority > 0 && priority+netbirdRulePriorityOffset < 32766
+}
+
 var ErrTableIDExists = errors.New("ID exists with different name")
 
 const errParsePrefixMsg = "failed to parse prefix %s: %w"
@@ -77,10 +128,10 @@ type ruleParams struct {
 
 func getSetupRules() []ruleParams {
 	return []ruleParams{
-		{105, 0, syscall.RT_TABLE_MAIN, netlink.FAMILY_V4, false, 0, "rule with suppress prefixlen v4"},
-		{105, 0, syscall.RT_TABLE_MAIN, netlink.FAMILY_V6, false, 0, "rule with suppress prefixlen v6"},
-		{110, nbnet.ControlPlaneMark, NetbirdVPNTableID, netlink.FAMILY_V4, true, -1, "rule v4 netbird"},
-		{110, nbnet.ControlPlaneMark, NetbirdVPNTableID, netlink.FAMILY_V6, true, -1, "rule v6 netbird"},
+		{rulePriority, 0, syscall.RT_TABLE_MAIN, netlink.FAMILY_V4, false, 0, "rule with suppress prefixlen v4"},
+		{rulePriority, 0, syscall.RT_TABLE_MAIN, netlink.FAMILY_V6, false, 0, "rule with suppress prefixlen v6"},
+		{rulePriority + netbirdRulePriorityOffset, nbnet.ControlPlaneMark, vpnTableID, netlink.FAMILY_V4, true, -1, "rule v4 netbird"},
+		{rulePriority + netbirdRulePriorityOffset, nbnet.ControlPlaneMark, vpnTableID, netlink.FAMILY_V6, true, -1, "rule v6 netbird"},
 	}
 }
 
@@ -93,7 +144,10 @@ func getSetupRules() []ruleParams {
 //
 // Rule 2 (VPN Traffic Routing): Directs all remaining traffic to the 'NetbirdVPNTableID' custom routing table.
 // This table is where a default route or other specific routes received from the management server are configured,
-// enabling VPN connectivity.
+// enabling VPN connectivity. The main table itself is never modified.
+//
+// The table ID and the rule priorities can be changed with NB_ROUTING_TABLE_ID and NB_ROUTING_RULE_PRIORITY,
+// for setups where other tooling already claims the defaults.
 func (r *SysOps) SetupRouting(initAddresses []net.IP, stateManager *statemanager.Manager, advancedRouting bool) (err error) {
 	if !advancedRouting {
 		log.Infof("Using legacy routing setup")
@@ -137,12 +191,15 @@ func (r *SysOps) CleanupRouting(stateManager *statemanager.Manager, advancedRout
 		return r.cleanupRefCounter(stateManager)
 	}
 
+	// the bypass routes go with the flushed table
+	r.resetVPNExclusions()
+
 	var result *multierror.Error
 
-	if err := flushRoutes(NetbirdVPNTableID, netlink.FAMILY_V4); err != nil {
+	if err := flushRoutes(vpnTableID, netlink.FAMILY_V4); err != nil {
 		result = multierror.Append(result, fmt.Errorf("flush routes v4: %w", err))
 	}
-	if err := flushRoutes(NetbirdVPNTableID, netlink.FAMILY_V6); err != nil {
+	if err := flushRoutes(vpnTableID, netlink.FAMILY_V6); err != nil {
 		result = multierror.Append(result, fmt.Errorf("flush routes v6: %w", err))
 	}
 
@@ -187,11 +244,11 @@ func (r *SysOps) AddVPNRoute(prefix netip.Prefix, intf *net.Interface) error {
 
 	// TODO remove this once we have ipv6 support
 	if prefix == vars.Defaultv4 {
-		if err := addUnreachableRoute(vars.Defaultv6, NetbirdVPNTableID); err != nil {
+		if err := addUnreachableRoute(vars.Defaultv6, vpnTableID); err != nil {
 			return fmt.Errorf("add blackhole: %w", err)
 		}
 	}
-	if err := addRoute(prefix, Nexthop{netip.Addr{}, intf}, NetbirdVPNTableID); err != nil {
+	if err := addRoute(prefix, Nexthop{netip.Addr{}, intf}, vpnTableID); err != nil {
 		return fmt.Errorf("add route: %w", err)
 	}
 	return nil
@@ -208,11 +265,11 @@ func (r *SysOps) RemoveVPNRoute(prefix netip.Prefix, intf *net.Interface) error
 
 	// TODO remove this once we have ipv6 support
 	if prefix == vars.Defaultv4 {
-		if err := removeUnreachableRoute(vars.Defaultv6, NetbirdVPNTableID); err != nil {
+		if err := removeUnreachableRoute(vars.Defaultv6, vpnTableID); err != nil {
 			return fmt.Errorf("remove unreachable route: %w", err)
 		}
 	}
-	if err := removeRoute(prefix, Nexthop{netip.Addr{}, intf}, NetbirdVPNTableID); err != nil {
+	if err := removeRoute(prefix, Nexthop{netip.Addr{}, intf}, vpnTableID); err != nil {
 		return fmt.Errorf("remove route: %w", err)
 	}
 	return nil
@@ -244,7 +301,7 @@ func discoverRoutingTables() []int {
 		return []int{
 			syscall.RT_TABLE_MAIN,
 			syscall.RT_TABLE_LOCAL,
-			NetbirdVPNTableID,
+			vpnTableID,
 		}
 	}
 	return tables
@@ -309,7 +366,7 @@ func getAllRoutingTables() ([]int, error) {
 		tables = append(tables, tableID)
 	}
 
-	standardTables := []int{syscall.RT_TABLE_MAIN, syscall.RT_TABLE_LOCAL, NetbirdVPNTableID}
+	standardTables := []int{syscall.RT_TABLE_MAIN, syscall.RT_TABLE_LOCAL, vpnTableID}
 	for _, table := range standardTables {
 		if !tablesMap[table] {
 			tables = append(tables, table)
@@ -495,7 +552,7 @@ func routeTableToString(tableID int) string {
 		return "main"
 	case syscall.RT_TABLE_LOCAL:
 		return "local"
-	case NetbirdVPNTableID:
+	case vpnTableID:
 		return "netbird"
 	default:
 		return fmt.Sprintf("%d", tableID)
@@ -611,7 +668,7 @@ func ruleTableToString(table int) string {
 		return "local"
 	case syscall.RT_TABLE_DEFAULT:
 		return "default"
-	case NetbirdVPNTableID:
+	case vpnTableID:
 		return "netbird"
 	default:
 		return fmt.Sprintf("%d", table)
@@ -668,33 +725,63 @@ func addRoute(prefix netip.Prefix, nexthop Nexthop, tableID int) error {
 // ipFamily should be netlink.FAMILY_V4 for IPv4 or netlink.FAMILY_V6 for IPv6.
 // tableID specifies the routing table to which the unreachable route will be added.
 func addUnreachableRoute(prefix netip.Prefix, tableID int) error {
+	if err := addTypedRoute(prefix, syscall.RTN_UNREACHABLE, tableID); err != nil {
+		return fmt.Errorf("netlink add unreachable route: %w", err)
+	}
+	return nil
+}
+
+func removeUnreachableRoute(prefix netip.Prefix, tableID int) error {
+	if err := removeTypedRoute(prefix, syscall.RTN_UNREACHABLE, tableID); err != nil {
+		return fmt.Errorf("netlink remove unreachable route: %w", err)
+	}
+	return nil
+}
+
+// addThrowRoute adds a throw route to the routing table, the lookup of matching destinations continues with the next
+// routing rule.
+func addThrowRoute(prefix netip.Prefix, tableID int) error {
+	if err := addTypedRoute(prefix, syscall.RTN_THROW, tableID); err != nil {
+		return fmt.Errorf("netlink add throw route: %w", err)
+	}
+	return nil
+}
+
+func removeThrowRoute(prefix netip.Prefix, tableID int) error {
+	if err := removeTypedRoute(prefix, syscall.RTN_THROW, tableID); err != nil {
+		return fmt.Errorf("netlink remove throw route: %w", err)
+	}
+	return nil
+}
+
+func addTypedRoute(prefix netip.Prefix, routeType int, tableID int) error {
 	_, ipNet, err := net.ParseCIDR(prefix.String())
 	if err != nil {
 		return fmt.Errorf(errParsePrefixMsg, prefix, err)
 	}
 
 	route := &netlink.Route{
-		Type:   syscall.RTN_UNREACHABLE,
+		Type:   routeType,
 		Table:  tableID,
 		Family: getAddressFamily(prefix),
 		Dst:    ipNet,
 	}
 
 	if err := netlink.RouteAdd(route); err != nil && !isOpErr(err) {
-		return fmt.Errorf("netlink add unreachable route: %w", err)
+		return err
 	}
 
 	return nil
 }
 
-func removeUnreachableRoute(prefix netip.Prefix, tableID int) error {
+func removeTypedRoute(prefix netip.Prefix, routeType int, tableID int) error {
 	_, ipNet, err := net.ParseCIDR(prefix.String())
 	if err != nil {
 		return fmt.Errorf(errParsePrefixMsg, prefix, err)
 	}
 
 	route := &netlink.Route{
-		Type:   syscall.RTN_UNREACHABLE,
+		Type:   routeType,
 		Table:  tableID,
 		Family: getAddressFamily(prefix),
 		Dst:    ipNet,
@@ -704,11 +791,10 @@ func removeUnreachableRoute(prefix netip.Prefix, tableID int) error {
 		!errors.Is(err, syscall.ESRCH) &&
 		!errors.Is(err, syscall.ENOENT) &&
 		!isOpErr(err) {
-		return fmt.Errorf("netlink remove unreachable route: %w", err)
+		return err
 	}
 
 	return nil
-
 }
 
 // removeRoute removes a route from a specific routing table identified by tableID.
@@ -807,9 +893,9 @@ func addRoutingTableName() error {
 		}
 	}()
 
-	exists, err := entryExists(file, NetbirdVPNTableID)
+	exists, err := entryExists(file, vpnTableID)
 	if err != nil {
-		return fmt.Errorf("verify entry %d, %s: %w", NetbirdVPNTableID, NetbirdVPNTableName, err)
+		return fmt.Errorf("verify entry %d, %s: %w", vpnTableID, NetbirdVPNTableName, err)
 	}
 	if exists {
 		return nil
@@ -824,7 +910,7 @@ func addRoutingTableName() error {
 		return fmt.Errorf("open rt_tables for appending: %w", err)
 	}
 
-	if _, err := file.WriteString(fmt.Sprintf("\n%d\t%s\n", NetbirdVPNTableID, NetbirdVPNTableName)); err != nil {
+	if _, err := file.WriteString(fmt.Sprintf("\n%d\t%s\n", vpnTableID, NetbirdVPNTableName)); err != nil {
 		return fmt.Errorf("append entry to rt_tables: %w", err)
 	}
 
@@ -89,6 +89,35 @@ func TestEntryExists(t *testing.T) {
 	}
 }
 
+func TestParseRoutingEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		value    string
+		valid    func(int) bool
+		expected int
+	}{
+		{name: "Unset", env: envRoutingTableID, value: "", valid: isValidTableID, expected: NetbirdVPNTableID},
+		{name: "Decimal", env: envRoutingTableID, value: "200", valid: isValidTableID, expected: 200},
+		{name: "Hex", env: envRoutingTableID, value: "0x1BD1", valid: isValidTableID, expected: 0x1BD1},
+		{name: "MainTableRejected", env: envRoutingTableID, value: "254", valid: isValidTableID, expected: NetbirdVPNTableID},
+		{name: "Garbage", env: envRoutingTableID, value: "netbird", valid: isValidTableID, expected: NetbirdVPNTableID},
+		{name: "Priority", env: envRoutingRulePriority, value: "1000", valid: isValidRulePriority, expected: 1000},
+		{name: "PriorityBehindMain", env: envRoutingRulePriority, value: "32765", valid: isValidRulePriority, expected: defaultRulePriority},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(tc.env, tc.value)
+			def := NetbirdVPNTableID
+			if tc.env == envRoutingRulePriority {
+				def = defaultRulePriority
+			}
+			assert.Equal(t, tc.expected, parseRoutingEnv(tc.env, def, tc.valid))
+		})
+	}
+}
+
 func createAndSetupDummyInterface(t *testing.T, interfaceName, ipAddressCIDR string) string {
 	t.Helper()
 
@@ -195,6 +195,8 @@ const (
 
 func (r *SysOps) SetupRouting(initAddresses []net.IP, stateManager *statemanager.Manager, advancedRouting bool) error {
 	if advancedRouting {
+		// the bypass routes of ExcludeFromVPN need the next hop of the physical interface
+		r.refreshDefaultNexthops()
 		return nil
 	}
 
@@ -204,12 +206,30 @@ func (r *SysOps) SetupRouting(initAddresses []net.IP, stateManager *statemanager
 
 func (r *SysOps) CleanupRouting(stateManager *statemanager.Manager, advancedRouting bool) error {
 	if advancedRouting {
-		return nil
+		return r.cleanupVPNExclusionCounter()
 	}
 
 	return r.cleanupRefCounter(stateManager)
 }
 
+// cleanupVPNExclusionCounter removes the bypass routes held outside of the ref counter
+func (r *SysOps) cleanupVPNExclusionCounter() error {
+	r.vpnExclusionsMu.Lock()
+	counter := r.vpnExclusionCounter
+	r.vpnExclusionCounter = nil
+	r.vpnExclusionsMu.Unlock()
+
+	r.resetVPNExclusions()
+
+	if counter == nil {
+		return nil
+	}
+	if err := counter.Flush(); err != nil {
+		return fmt.Errorf("flush bypass routes: %w", err)
+	}
+	return nil
+}
+
 func (r *SysOps) addToRouteTable(prefix netip.Prefix, nexthop Nexthop) error {
 	log.Debugf("Adding route to %s via %s", prefix, nexthop)
 	// if we don't have an interface but a zone, extract the interface index from the zone
@@ -22,6 +22,8 @@ type RouteSelector struct {
 	deselectedRoutes map[route.NetID]struct{}
 	selectedRoutes   map[route.NetID]struct{}
 	deselectAll      bool
+	// pinnedExitNode is the public key of the routing peer preferred for exit node routes, empty means auto
+	pinnedExitNode string
 }
 
 func NewRouteSelector() *RouteSelector {
@@ -154,6 +156,23 @@ func (rs *RouteSelector) FilterSelected(routes route.HAMap) route.HAMap {
 	return filtered
 }
 
+// PinExitNode sets the routing peer that should be preferred for exit node routes.
+// An empty peer key switches back to automatic selection.
+func (rs *RouteSelector) PinExitNode(peerKey string) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.pinnedExitNode = peerKey
+}
+
+// PinnedExitNode returns the public key of the pinned exit node peer or an empty string in auto mode.
+func (rs *RouteSelector) PinnedExitNode() string {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	return rs.pinnedExitNode
+}
+
 // HasUserSelectionForRoute returns true if the user has explicitly selected or deselected this specific route
 func (rs *RouteSelector) HasUserSelectionForRoute(routeID route.NetID) bool {
 	rs.mu.RLock()
@@ -244,10 +263,12 @@ func (rs *RouteSelector) MarshalJSON() ([]byte, error) {
 		SelectedRoutes   map[route.NetID]struct{} `json:"selected_routes"`
 		DeselectedRoutes map[route.NetID]struct{} `json:"deselected_routes"`
 		DeselectAll      bool                     `json:"deselect_all"`
+		PinnedExitNode   string                   `json:"pinned_exit_node"`
 	}{
 		SelectedRoutes:   rs.selectedRoutes,
 		DeselectedRoutes: rs.deselectedRoutes,
 		DeselectAll:      rs.deselectAll,
+		PinnedExitNode:   rs.pinnedExitNode,
 	})
 }
 
@@ -262,6 +283,7 @@ func (rs *RouteSelector) UnmarshalJSON(data []byte) error {
 		rs.deselectedRoutes = map[route.NetID]struct{}{}
 		rs.selectedRoutes = map[route.NetID]struct{}{}
 		rs.deselectAll = false
+		rs.pinnedExitNode = ""
 		return nil
 	}
 
@@ -269,6 +291,7 @@ func (rs *RouteSelector) UnmarshalJSON(data []byte) error {
 		SelectedRoutes   map[route.NetID]struct{} `json:"selected_routes"`
 		DeselectedRoutes map[route.NetID]struct{} `json:"deselected_routes"`
 		DeselectAll      bool                     `json:"deselect_all"`
+		PinnedExitNode   string                   `json:"pinned_exit_node"`
 	}
 
 	if err := json.Unmarshal(data, &temp); err != nil {
@@ -278,6 +301,7 @@ func (rs *RouteSelector) UnmarshalJSON(data []byte) error {
 	rs.selectedRoutes = temp.SelectedRoutes
 	rs.deselectedRoutes = temp.DeselectedRoutes
 	rs.deselectAll = temp.DeselectAll
+	rs.pinnedExitNode = temp.PinnedExitNode
 
 	if rs.deselectedRoutes == nil {
 		rs.deselectedRoutes = map[route.NetID]struct{}{}
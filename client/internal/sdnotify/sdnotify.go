@@ -0,0 +1,39 @@
+// Package sdnotify implements the systemd service notification protocol. The notifications are sent to the socket
+// systemd passes in NOTIFY_SOCKET, they are no-ops if the process isn't run by systemd with Type=notify.
+package sdnotify
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	// StateReady tells systemd that the service finished starting up
+	StateReady = "READY=1"
+	// StateStopping tells systemd that the service is shutting down
+	StateStopping = "STOPPING=1"
+	// StateWatchdog keeps the watchdog of the service from restarting it
+	StateWatchdog = "WATCHDOG=1"
+
+	notifySocketEnv = "NOTIFY_SOCKET"
+	watchdogUSecEnv = "WATCHDOG_USEC"
+	watchdogPIDEnv  = "WATCHDOG_PID"
+)
+
+// Status returns the notification of the free-form status shown by systemctl status
+func Status(status string) string {
+	return "STATUS=" + status
+}
+
+// WatchdogInterval returns the watchdog timeout systemd set for the process, zero if the watchdog isn't enabled
+func WatchdogInterval(getenv func(string) string, pid int) time.Duration {
+	usec, err := strconv.ParseInt(getenv(watchdogUSecEnv), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	// the watchdog might be meant for another process of the service
+	if watchdogPID := getenv(watchdogPIDEnv); watchdogPID != "" && watchdogPID != strconv.Itoa(pid) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
@@ -0,0 +1,40 @@
+package sdnotify
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// Notify sends the states to systemd. It reports whether they were sent, false if the process isn't run by systemd.
+func Notify(states ...string) (bool, error) {
+	socket := os.Getenv(notifySocketEnv)
+	if socket == "" {
+		return false, nil
+	}
+	// abstract sockets are passed with a leading @
+	if strings.HasPrefix(socket, "@") {
+		socket = "\x00" + socket[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return false, fmt.Errorf("dial notify socket: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(strings.Join(states, "\n"))); err != nil {
+		return false, fmt.Errorf("write notification: %w", err)
+	}
+	return true, nil
+}
+
+// Watchdog returns the watchdog timeout of the service, zero if systemd doesn't watch the process
+func Watchdog() time.Duration {
+	if os.Getenv(notifySocketEnv) == "" {
+		return 0
+	}
+	return WatchdogInterval(os.Getenv, os.Getpid())
+}
@@ -0,0 +1,32 @@
+package sdnotify
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotify(t *testing.T) {
+	t.Setenv(notifySocketEnv, "")
+	sent, err := Notify(StateReady)
+	require.NoError(t, err)
+	assert.False(t, sent, "nothing is sent without systemd")
+
+	socket := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	t.Setenv(notifySocketEnv, socket)
+	sent, err = Notify(StateReady, Status("Connected"))
+	require.NoError(t, err)
+	assert.True(t, sent)
+
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "READY=1\nSTATUS=Connected", string(buf[:n]))
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package sdnotify
+
+import "time"
+
+// Notify is a no-op, systemd only runs on Linux
+func Notify(...string) (bool, error) {
+	return false, nil
+}
+
+// Watchdog returns zero, systemd only runs on Linux
+func Watchdog() time.Duration {
+	return 0
+}
@@ -0,0 +1,28 @@
+package sdnotify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchdogInterval(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want time.Duration
+	}{
+		{name: "disabled", env: map[string]string{}, want: 0},
+		{name: "enabled", env: map[string]string{watchdogUSecEnv: "90000000"}, want: 90 * time.Second},
+		{name: "own pid", env: map[string]string{watchdogUSecEnv: "30000000", watchdogPIDEnv: "42"}, want: 30 * time.Second},
+		{name: "other pid", env: map[string]string{watchdogUSecEnv: "30000000", watchdogPIDEnv: "7"}, want: 0},
+		{name: "invalid", env: map[string]string{watchdogUSecEnv: "soon"}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			assert.Equal(t, tt.want, WatchdogInterval(getenv, 42))
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Package secrets resolves references to secrets that are kept outside the config file.
+//
+// A reference is a config value with one of the following prefixes:
+//
+//	file:/etc/netbird/psk              the trimmed content of the file
+//	env:NB_PSK                         the value of the environment variable
+//	exec:/usr/bin/pass show nb/psk     the trimmed stdout of the command
+//	vault:secret/data/netbird#psk      the field of a secret read through the local Vault agent
+//
+// Values without a known prefix are returned as is.
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	prefixFile  = "file:"
+	prefixEnv   = "env:"
+	prefixExec  = "exec:"
+	prefixVault = "vault:"
+
+	execTimeout = 10 * time.Second
+)
+
+// IsReference reports whether the value points to an external secret
+func IsReference(value string) bool {
+	for _, prefix := range []string{prefixFile, prefixEnv, prefixExec, prefixVault} {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExecReference reports whether resolving the value runs a command
+func IsExecReference(value string) bool {
+	return strings.HasPrefix(value, prefixExec)
+}
+
+// Resolve returns the secret the value refers to, or the value itself if it isn't a reference
+func Resolve(ctx context.Context, value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, prefixFile):
+		return resolveFile(strings.TrimPrefix(value, prefixFile))
+	case strings.HasPrefix(value, prefixEnv):
+		return resolveEnv(strings.TrimPrefix(value, prefixEnv))
+	case strings.HasPrefix(value, prefixExec):
+		return resolveExec(ctx, strings.TrimPrefix(value, prefixExec))
+	case strings.HasPrefix(value, prefixVault):
+		return resolveVault(ctx, strings.TrimPrefix(value, prefixVault))
+	default:
+		return value, nil
+	}
+}
+
+func resolveFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read secret file: %w", err)
+	}
+
+	secret := strings.TrimSpace(string(data))
+	if secret == "" {
+		return "", fmt.Errorf("secret file %s is empty", path)
+	}
+	return secret, nil
+}
+
+func resolveEnv(name string) (string, error) {
+	secret, ok := os.LookupEnv(name)
+	if !ok || secret == "" {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return secret, nil
+}
+
+func resolveExec(ctx context.Context, command string) (string, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", errors.New("empty secret command")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, execTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("run secret command %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	secret := strings.TrimSpace(stdout.String())
+	if secret == "" {
+		return "", fmt.Errorf("secret command %s returned no output", args[0])
+	}
+	return secret, nil
+}
@@ -0,0 +1,90 @@
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "psk")
+	require.NoError(t, os.WriteFile(secretFile, []byte("file-secret\n"), 0600))
+	emptyFile := filepath.Join(dir, "empty")
+	require.NoError(t, os.WriteFile(emptyFile, nil, 0600))
+
+	t.Setenv("NB_TEST_SECRET", "env-secret")
+
+	testCases := []struct {
+		name     string
+		value    string
+		expected string
+		wantErr  bool
+		unixOnly bool
+	}{
+		{name: "plain value", value: "plain-secret", expected: "plain-secret"},
+		{name: "empty value", value: "", expected: ""},
+		{name: "file", value: "file:" + secretFile, expected: "file-secret"},
+		{name: "missing file", value: "file:" + filepath.Join(dir, "missing"), wantErr: true},
+		{name: "empty file", value: "file:" + emptyFile, wantErr: true},
+		{name: "env", value: "env:NB_TEST_SECRET", expected: "env-secret"},
+		{name: "missing env", value: "env:NB_TEST_SECRET_MISSING", wantErr: true},
+		{name: "exec", value: "exec:echo exec-secret", expected: "exec-secret", unixOnly: true},
+		{name: "failing exec", value: "exec:false", wantErr: true, unixOnly: true},
+		{name: "empty exec", value: "exec:", wantErr: true},
+		{name: "invalid vault reference", value: "vault:secret/netbird", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.unixOnly && runtime.GOOS == "windows" {
+				t.Skip("requires unix commands")
+			}
+
+			secret, err := Resolve(context.Background(), tc.value)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, secret)
+		})
+	}
+}
+
+func TestResolveVault(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/secret/data/netbird":
+			_, _ = w.Write([]byte(`{"data":{"data":{"psk":"kv2-secret"},"metadata":{"version":1}}}`))
+		case "/v1/kv/netbird":
+			_, _ = w.Write([]byte(`{"data":{"psk":"kv1-secret"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv(envVaultAgentAddr, srv.URL)
+
+	secret, err := Resolve(context.Background(), "vault:secret/data/netbird#psk")
+	require.NoError(t, err)
+	assert.Equal(t, "kv2-secret", secret)
+
+	secret, err = Resolve(context.Background(), "vault:kv/netbird#psk")
+	require.NoError(t, err)
+	assert.Equal(t, "kv1-secret", secret)
+
+	_, err = Resolve(context.Background(), "vault:kv/netbird#missing")
+	assert.Error(t, err)
+
+	_, err = Resolve(context.Background(), "vault:kv/unknown#psk")
+	assert.Error(t, err)
+}
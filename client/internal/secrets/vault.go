@@ -0,0 +1,92 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// envVaultAgentAddr follows the variable of the Vault CLI, e.g. unix:///var/run/vault-agent.sock or http://127.0.0.1:8100
+	envVaultAgentAddr       = "VAULT_AGENT_ADDR"
+	defaultVaultAgentSocket = "/var/run/vault-agent.sock"
+
+	vaultTimeout         = 10 * time.Second
+	maxVaultResponseSize = 1 << 20
+)
+
+// vaultResponse covers the KV v1 and v2 secret engines, v2 nests the fields in another data object
+type vaultResponse struct {
+	Data map[string]any `json:"data"`
+}
+
+// resolveVault reads a secret field through the local Vault agent. The agent is expected to authenticate the
+// requests itself (use_auto_auth_token), so no token is kept on the client. The reference has the format path#field.
+func resolveVault(ctx context.Context, ref string) (string, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || field == "" {
+		return "", fmt.Errorf("invalid vault reference %q, expected path#field", ref)
+	}
+
+	client, baseURL := vaultAgentClient()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", fmt.Errorf("create vault request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("query vault agent: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault agent returned status %d for %s", resp.StatusCode, path)
+	}
+
+	var body vaultResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxVaultResponseSize)).Decode(&body); err != nil {
+		return "", fmt.Errorf("decode vault response: %w", err)
+	}
+
+	data := body.Data
+	if nested, ok := data["data"].(map[string]any); ok {
+		data = nested
+	}
+
+	secret, ok := data[field].(string)
+	if !ok || secret == "" {
+		return "", fmt.Errorf("field %s not found in vault secret %s", field, path)
+	}
+	return secret, nil
+}
+
+// vaultAgentClient returns a client for the agent listener and the base URL to use with it
+func vaultAgentClient() (*http.Client, string) {
+	addr := os.Getenv(envVaultAgentAddr)
+	if addr == "" {
+		addr = "unix://" + defaultVaultAgentSocket
+	}
+
+	socket, isUnix := strings.CutPrefix(addr, "unix://")
+	if !isUnix {
+		return &http.Client{Timeout: vaultTimeout}, strings.TrimSuffix(addr, "/")
+	}
+
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		},
+	}
+	return &http.Client{Transport: transport, Timeout: vaultTimeout}, "http://vault-agent"
+}
@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"net/netip"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/shaping"
+	"github.com/netbirdio/netbird/route"
+)
+
+// updateTrafficShaping resolves the traffic shaping rules to the current peer and route networks and applies them.
+// The userspace firewall limits the traffic itself, otherwise tc is used on the WireGuard interface.
+func (e *Engine) updateTrafficShaping() {
+	if len(e.config.TrafficShaping) == 0 || e.wgInterface == nil {
+		return
+	}
+
+	if e.shaper == nil {
+		shaper, err := e.newShaper()
+		if err != nil {
+			log.Warnf("traffic shaping is disabled: %v", err)
+			return
+		}
+		e.shaper = shaper
+	}
+
+	if err := e.shaper.Update(e.shapingLimits()); err != nil {
+		log.Errorf("failed to update traffic shaping: %v", err)
+	}
+}
+
+func (e *Engine) newShaper() (shaping.Shaper, error) {
+	if fw, ok := e.firewall.(interface {
+		SetOutboundLimiter(func(dst netip.Addr, size int) bool)
+	}); ok {
+		limiter := shaping.NewLimiter()
+		fw.SetOutboundLimiter(limiter.Allow)
+		return limiter, nil
+	}
+
+	return shaping.NewKernelShaper(e.wgInterface.Name())
+}
+
+func (e *Engine) closeShaper() {
+	if e.shaper == nil {
+		return
+	}
+
+	if fw, ok := e.firewall.(interface {
+		SetOutboundLimiter(func(dst netip.Addr, size int) bool)
+	}); ok {
+		fw.SetOutboundLimiter(nil)
+	}
+
+	if err := e.shaper.Close(); err != nil {
+		log.Warnf("failed to remove traffic shaping: %v", err)
+	}
+	e.shaper = nil
+}
+
+func (e *Engine) shapingLimits() []shaping.Limit {
+	var peers []peer.State
+	var routes map[route.NetID][]*route.Route
+
+	var limits []shaping.Limit
+	for _, rule := range e.config.TrafficShaping {
+		if err := rule.Validate(); err != nil {
+			log.Warnf("ignoring traffic shaping rule: %v", err)
+			continue
+		}
+
+		switch {
+		case rule.Network.IsValid():
+			limits = append(limits, rule.Limit(rule.Network))
+		case rule.Peer != "":
+			if peers == nil {
+				peers = e.statusRecorder.GetFullStatus().Peers
+			}
+			prefix, ok := peerPrefix(peers, rule.Peer)
+			if !ok {
+				log.Debugf("traffic shaping rule %s: peer %s not found", rule, rule.Peer)
+				continue
+			}
+			limits = append(limits, rule.Limit(prefix))
+		case rule.Route != "":
+			if routes == nil && e.routeManager != nil {
+				routes = e.routeManager.GetClientRoutesWithNetID()
+			}
+			for _, r := range routes[route.NetID(rule.Route)] {
+				if r.IsDynamic() {
+					log.Debugf("traffic shaping rule %s: dynamic route %s is not supported", rule, r.NetID)
+					continue
+				}
+				limits = append(limits, rule.Limit(r.Network))
+			}
+		}
+	}
+	return limits
+}
+
+// peerPrefix returns the host prefix of the peer with the given public key or FQDN
+func peerPrefix(peers []peer.State, key string) (netip.Prefix, bool) {
+	fqdn := strings.TrimSuffix(key, ".")
+	for _, p := range peers {
+		if p.PubKey != key && !strings.EqualFold(strings.TrimSuffix(p.FQDN, "."), fqdn) {
+			continue
+		}
+
+		addr, err := netip.ParseAddr(p.IP)
+		if err != nil {
+			return netip.Prefix{}, false
+		}
+		return netip.PrefixFrom(addr, addr.BitLen()), true
+	}
+	return netip.Prefix{}, false
+}
@@ -0,0 +1,78 @@
+package shaping
+
+import (
+	"cmp"
+	"net/netip"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Limiter enforces the limits in userspace with a token bucket per network. It is used where the packets pass
+// through the userspace firewall. Packets exceeding the limit are dropped, which makes TCP back off.
+type Limiter struct {
+	mu      sync.RWMutex
+	buckets []bucket
+}
+
+type bucket struct {
+	limit   Limit
+	limiter *rate.Limiter
+}
+
+func NewLimiter() *Limiter {
+	return &Limiter{}
+}
+
+// Update replaces the limits. Buckets of unchanged limits are kept, so updates don't reset their state.
+func (l *Limiter) Update(limits []Limit) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	buckets := make([]bucket, 0, len(limits))
+	for _, limit := range limits {
+		idx := slices.IndexFunc(l.buckets, func(b bucket) bool { return b.limit == limit })
+		if idx >= 0 {
+			buckets = append(buckets, l.buckets[idx])
+			continue
+		}
+
+		buckets = append(buckets, bucket{
+			limit:   limit,
+			limiter: rate.NewLimiter(rate.Limit(limit.bytesPerSecond()), int(limit.burst())),
+		})
+	}
+
+	// the most specific network wins
+	slices.SortStableFunc(buckets, func(a, b bucket) int {
+		return cmp.Compare(b.limit.Prefix.Bits(), a.limit.Prefix.Bits())
+	})
+
+	l.buckets = buckets
+	return nil
+}
+
+// Close removes all limits
+func (l *Limiter) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.buckets = nil
+	return nil
+}
+
+// Allow reports whether a packet of the given size to the destination fits into the limit of its network.
+// Packets that don't match any limit are allowed.
+func (l *Limiter) Allow(dst netip.Addr, size int) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for _, b := range l.buckets {
+		if b.limit.Prefix.Contains(dst) {
+			return b.limiter.AllowN(time.Now(), size)
+		}
+	}
+	return true
+}
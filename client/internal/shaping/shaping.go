@@ -0,0 +1,104 @@
+// Package shaping caps the bandwidth of the traffic sent through the tunnel to specific peers or routed networks,
+// e.g. to keep backup traffic to an exit node from saturating the uplink.
+package shaping
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+)
+
+// defaultBurstBytes is the minimum burst of a limit. It has to fit the largest packets, including segmentation
+// offloaded ones, otherwise they could never pass.
+const defaultBurstBytes = 64 * 1024
+
+// ErrUnsupported is returned when traffic shaping isn't available on the platform
+var ErrUnsupported = errors.New("traffic shaping is not supported on this platform")
+
+// Rule caps the bandwidth of the traffic to a peer or a routed network. Exactly one of Peer, Route and Network
+// must be set.
+type Rule struct {
+	// Name is used for logging only
+	Name string
+
+	// Peer is the WireGuard public key or the FQDN of a peer
+	Peer string
+	// Route is the network ID of a client route, dynamic (domain) routes are not supported
+	Route string
+	// Network is a network reached through the tunnel
+	Network netip.Prefix
+
+	// RateKbit is the bandwidth cap in kbit/s
+	RateKbit uint64
+	// BurstBytes is the amount of traffic allowed to exceed the rate at once. Zero means default (64 KiB).
+	BurstBytes uint64
+}
+
+// Validate checks that the rule has a single target and a rate
+func (r Rule) Validate() error {
+	targets := 0
+	if r.Peer != "" {
+		targets++
+	}
+	if r.Route != "" {
+		targets++
+	}
+	if r.Network.IsValid() {
+		targets++
+	}
+
+	if targets != 1 {
+		return fmt.Errorf("shaping rule %s: exactly one of peer, route and network is required", r)
+	}
+	if r.RateKbit == 0 {
+		return fmt.Errorf("shaping rule %s: rate is required", r)
+	}
+	return nil
+}
+
+func (r Rule) String() string {
+	switch {
+	case r.Name != "":
+		return r.Name
+	case r.Peer != "":
+		return "peer " + r.Peer
+	case r.Route != "":
+		return "route " + r.Route
+	case r.Network.IsValid():
+		return r.Network.String()
+	default:
+		return "unnamed"
+	}
+}
+
+// Limit is a rule resolved to a network
+type Limit struct {
+	Prefix     netip.Prefix
+	RateKbit   uint64
+	BurstBytes uint64
+}
+
+// Limit returns the limit of the rule for the given network
+func (r Rule) Limit(prefix netip.Prefix) Limit {
+	return Limit{
+		Prefix:     prefix.Masked(),
+		RateKbit:   r.RateKbit,
+		BurstBytes: r.BurstBytes,
+	}
+}
+
+func (l Limit) bytesPerSecond() uint64 {
+	return l.RateKbit * 1000 / 8
+}
+
+func (l Limit) burst() uint64 {
+	return max(l.BurstBytes, defaultBurstBytes)
+}
+
+// Shaper enforces the limits on the outbound traffic of the tunnel
+type Shaper interface {
+	// Update replaces the enforced limits
+	Update(limits []Limit) error
+	// Close removes all limits
+	Close() error
+}
@@ -0,0 +1,68 @@
+package shaping
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRule_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr bool
+	}{
+		{name: "peer", rule: Rule{Peer: "peer-a.netbird.cloud", RateKbit: 1000}},
+		{name: "route", rule: Rule{Route: "backup", RateKbit: 1000}},
+		{name: "network", rule: Rule{Network: netip.MustParsePrefix("10.0.0.0/8"), RateKbit: 1000}},
+		{name: "no target", rule: Rule{RateKbit: 1000}, wantErr: true},
+		{name: "two targets", rule: Rule{Peer: "peer-a", Route: "backup", RateKbit: 1000}, wantErr: true},
+		{name: "no rate", rule: Rule{Route: "backup"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	l := NewLimiter()
+	require.NoError(t, l.Update([]Limit{
+		{Prefix: netip.MustParsePrefix("10.0.0.0/8"), RateKbit: 8, BurstBytes: defaultBurstBytes},
+		// a more specific network isn't limited by the broader one
+		{Prefix: netip.MustParsePrefix("10.1.0.0/16"), RateKbit: 8000000},
+	}))
+
+	limited := netip.MustParseAddr("10.2.0.1")
+	assert.True(t, l.Allow(limited, defaultBurstBytes), "burst should pass")
+	assert.False(t, l.Allow(limited, 1500), "traffic exceeding the rate should be dropped")
+
+	assert.True(t, l.Allow(netip.MustParseAddr("10.1.0.1"), 1500))
+	assert.True(t, l.Allow(netip.MustParseAddr("10.1.0.1"), 1500))
+
+	assert.True(t, l.Allow(netip.MustParseAddr("192.168.0.1"), defaultBurstBytes*2), "unmatched traffic should pass")
+}
+
+func TestLimiter_UpdateKeepsState(t *testing.T) {
+	limit := Limit{Prefix: netip.MustParsePrefix("100.64.0.10/32"), RateKbit: 8}
+	dst := netip.MustParseAddr("100.64.0.10")
+
+	l := NewLimiter()
+	require.NoError(t, l.Update([]Limit{limit}))
+	require.True(t, l.Allow(dst, defaultBurstBytes))
+
+	require.NoError(t, l.Update([]Limit{limit}))
+	assert.False(t, l.Allow(dst, 1500), "an unchanged limit should keep its bucket")
+
+	require.NoError(t, l.Close())
+	assert.True(t, l.Allow(dst, 1500))
+}
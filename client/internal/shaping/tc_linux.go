@@ -0,0 +1,199 @@
+//go:build linux && !android
+
+package shaping
+
+import (
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	ipv4DstOffset = 16
+	ipv6DstOffset = 24
+)
+
+// TC enforces the limits with an HTB qdisc on the egress of the WireGuard interface. Every limit gets its own class
+// and a u32 filter on the destination network. Unclassified traffic isn't shaped.
+type TC struct {
+	ifName string
+
+	mu     sync.Mutex
+	limits []Limit
+}
+
+// NewKernelShaper returns a shaper that uses tc on the given interface
+func NewKernelShaper(ifName string) (Shaper, error) {
+	return &TC{ifName: ifName}, nil
+}
+
+// Update replaces the qdisc of the interface if the limits changed
+func (t *TC) Update(limits []Limit) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	limits = sortLimits(limits)
+	if slices.Equal(limits, t.limits) {
+		return nil
+	}
+
+	link, err := netlink.LinkByName(t.ifName)
+	if err != nil {
+		return fmt.Errorf("get link %s: %w", t.ifName, err)
+	}
+
+	if err := deleteRootQdisc(link); err != nil {
+		return err
+	}
+	t.limits = nil
+
+	if len(limits) == 0 {
+		return nil
+	}
+
+	if err := apply(link, limits); err != nil {
+		if err := deleteRootQdisc(link); err != nil {
+			log.Warnf("failed to clean up traffic shaping on %s: %v", t.ifName, err)
+		}
+		return err
+	}
+
+	t.limits = limits
+	return nil
+}
+
+// Close removes the qdisc from the interface
+func (t *TC) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.limits == nil {
+		return nil
+	}
+	t.limits = nil
+
+	link, err := netlink.LinkByName(t.ifName)
+	if err != nil {
+		var linkNotFound netlink.LinkNotFoundError
+		if errors.As(err, &linkNotFound) {
+			return nil
+		}
+		return fmt.Errorf("get link %s: %w", t.ifName, err)
+	}
+	return deleteRootQdisc(link)
+}
+
+func apply(link netlink.Link, limits []Limit) error {
+	index := link.Attrs().Index
+
+	// the default class 0 lets unclassified traffic pass unshaped
+	qdisc := netlink.NewHtb(netlink.QdiscAttrs{
+		LinkIndex: index,
+		Handle:    netlink.MakeHandle(1, 0),
+		Parent:    netlink.HANDLE_ROOT,
+	})
+	if err := netlink.QdiscAdd(qdisc); err != nil {
+		return fmt.Errorf("add htb qdisc: %w", err)
+	}
+
+	for i, limit := range limits {
+		classID := netlink.MakeHandle(1, uint16(i+1))
+
+		class := netlink.NewHtbClass(netlink.ClassAttrs{
+			LinkIndex: index,
+			Parent:    qdisc.Handle,
+			Handle:    classID,
+		}, netlink.HtbClassAttrs{
+			Rate:   limit.RateKbit * 1000,
+			Buffer: uint32(min(limit.burst(), uint64(^uint32(0)))),
+		})
+		if err := netlink.ClassAdd(class); err != nil {
+			return fmt.Errorf("add htb class for %s: %w", limit.Prefix, err)
+		}
+
+		filter := &netlink.U32{
+			FilterAttrs: netlink.FilterAttrs{
+				LinkIndex: index,
+				Parent:    qdisc.Handle,
+				// longer prefixes are sorted first and win
+				Priority: uint16(i + 1),
+				Protocol: protocol(limit.Prefix),
+			},
+			ClassId: classID,
+			Sel:     dstSelector(limit.Prefix),
+		}
+		if err := netlink.FilterAdd(filter); err != nil {
+			return fmt.Errorf("add u32 filter for %s: %w", limit.Prefix, err)
+		}
+	}
+
+	return nil
+}
+
+func deleteRootQdisc(link netlink.Link) error {
+	qdisc := &netlink.GenericQdisc{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    netlink.MakeHandle(1, 0),
+			Parent:    netlink.HANDLE_ROOT,
+		},
+		QdiscType: "htb",
+	}
+	if err := netlink.QdiscDel(qdisc); err != nil && !errors.Is(err, unix.ENOENT) && !errors.Is(err, unix.EINVAL) {
+		return fmt.Errorf("delete root qdisc: %w", err)
+	}
+	return nil
+}
+
+func protocol(prefix netip.Prefix) uint16 {
+	if prefix.Addr().Is4() {
+		return unix.ETH_P_IP
+	}
+	return unix.ETH_P_IPV6
+}
+
+// dstSelector matches the destination address of the IP header. The keys are in host order, netlink converts them.
+func dstSelector(prefix netip.Prefix) *netlink.TcU32Sel {
+	offset := int32(ipv4DstOffset)
+	if !prefix.Addr().Is4() {
+		offset = ipv6DstOffset
+	}
+
+	addr := prefix.Addr().AsSlice()
+	bits := prefix.Bits()
+
+	sel := &netlink.TcU32Sel{Flags: netlink.TC_U32_TERMINAL}
+	for i := 0; i < len(addr); i += 4 {
+		wordBits := min(max(bits-i*8, 0), 32)
+		if wordBits == 0 && len(sel.Keys) > 0 {
+			break
+		}
+
+		var mask uint32
+		if wordBits > 0 {
+			mask = ^uint32(0) << (32 - wordBits)
+		}
+		sel.Keys = append(sel.Keys, netlink.TcU32Key{
+			Mask: mask,
+			Val:  binary.BigEndian.Uint32(addr[i:i+4]) & mask,
+			Off:  offset + int32(i),
+		})
+	}
+	return sel
+}
+
+func sortLimits(limits []Limit) []Limit {
+	sorted := slices.Clone(limits)
+	slices.SortStableFunc(sorted, func(a, b Limit) int {
+		return cmp.Compare(b.Prefix.Bits(), a.Prefix.Bits())
+	})
+	return sorted
+}
@@ -0,0 +1,51 @@
+//go:build linux && !android
+
+package shaping
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vishvananda/netlink"
+)
+
+func TestDstSelector(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		keys   []netlink.TcU32Key
+	}{
+		{
+			name:   "ipv4 host",
+			prefix: "100.64.0.10/32",
+			keys:   []netlink.TcU32Key{{Mask: 0xffffffff, Val: 0x6440000a, Off: 16}},
+		},
+		{
+			name:   "ipv4 network",
+			prefix: "10.1.0.0/16",
+			keys:   []netlink.TcU32Key{{Mask: 0xffff0000, Val: 0x0a010000, Off: 16}},
+		},
+		{
+			name:   "default route",
+			prefix: "0.0.0.0/0",
+			keys:   []netlink.TcU32Key{{Mask: 0, Val: 0, Off: 16}},
+		},
+		{
+			name:   "ipv6 network",
+			prefix: "2001:db8:1::/48",
+			keys: []netlink.TcU32Key{
+				{Mask: 0xffffffff, Val: 0x20010db8, Off: 24},
+				{Mask: 0xffff0000, Val: 0x00010000, Off: 28},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sel := dstSelector(netip.MustParsePrefix(tt.prefix))
+			assert.Equal(t, uint8(netlink.TC_U32_TERMINAL), sel.Flags)
+			assert.Equal(t, tt.keys, sel.Keys)
+		})
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !linux || android
+
+package shaping
+
+// NewKernelShaper returns ErrUnsupported, the userspace limiter is used where the firewall supports it
+func NewKernelShaper(string) (Shaper, error) {
+	return nil, ErrUnsupported
+}
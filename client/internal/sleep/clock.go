@@ -0,0 +1,112 @@
+package sleep
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ClockDetector detects a resume from suspend by comparing the clocks that keep running while the host is suspended
+// with the monotonic clock, which stops. Unlike the OS power events it works on every platform, including the
+// headless daemon, but only reports the wake up.
+type ClockDetector struct {
+	interval  time.Duration
+	threshold time.Duration
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+// NewClockDetector returns a detector that reads the clocks every interval and reports a wake up when the host was
+// suspended for longer than the threshold in between
+func NewClockDetector(interval, threshold time.Duration) *ClockDetector {
+	return &ClockDetector{
+		interval:  interval,
+		threshold: threshold,
+	}
+}
+
+// Register starts reading the clocks, the callback is called from the goroutine of the detector
+func (d *ClockDetector) Register(callback func(event EventType)) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.cancel != nil {
+		return fmt.Errorf("detector service already registered")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	go d.watch(ctx, callback)
+	return nil
+}
+
+// Deregister stops reading the clocks
+func (d *ClockDetector) Deregister() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.cancel != nil {
+		d.cancel()
+		d.cancel = nil
+	}
+	return nil
+}
+
+func (d *ClockDetector) watch(ctx context.Context, callback func(event EventType)) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	prev := readClock()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		cur := readClock()
+		slept := suspended(prev, cur)
+		prev = cur
+		if slept <= d.threshold {
+			continue
+		}
+
+		log.Infof("host resumed after being suspended for about %s", slept.Round(time.Second))
+		callback(EventTypeWakeUp)
+	}
+}
+
+// clockReading is a reading of the monotonic clock and of the clocks that keep running during a suspend
+type clockReading struct {
+	// mono carries the monotonic reading of the Go runtime, which stops during a suspend
+	mono time.Time
+	// wall is the wall clock without the monotonic reading
+	wall time.Time
+	// boot is the time since boot including the suspends, zero if the platform doesn't provide it
+	boot time.Duration
+}
+
+func readClock() clockReading {
+	now := time.Now()
+	return clockReading{
+		mono: now,
+		wall: now.Round(0),
+		boot: bootTime(),
+	}
+}
+
+// suspended returns for how long the host didn't run between the readings. The boot time is preferred because the
+// wall clock also jumps on clock adjustments.
+func suspended(prev, cur clockReading) time.Duration {
+	running := cur.mono.Sub(prev.mono)
+
+	elapsed := cur.wall.Sub(prev.wall)
+	if prev.boot > 0 && cur.boot > 0 {
+		elapsed = cur.boot - prev.boot
+	}
+	return elapsed - running
+}
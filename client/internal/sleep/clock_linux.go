@@ -0,0 +1,16 @@
+package sleep
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// bootTime returns CLOCK_BOOTTIME, which unlike CLOCK_MONOTONIC keeps running while the host is suspended
+func bootTime() time.Duration {
+	var ts unix.Timespec
+	if err := unix.ClockGettime(unix.CLOCK_BOOTTIME, &ts); err != nil {
+		return 0
+	}
+	return time.Duration(ts.Nano())
+}
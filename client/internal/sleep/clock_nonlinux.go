@@ -0,0 +1,10 @@
+//go:build !linux
+
+package sleep
+
+import "time"
+
+// bootTime isn't available on this platform, the wall clock is used instead
+func bootTime() time.Duration {
+	return 0
+}
@@ -0,0 +1,55 @@
+package sleep
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuspended(t *testing.T) {
+	start := time.Now()
+	prev := clockReading{mono: start, wall: start.Round(0), boot: time.Hour}
+
+	tests := []struct {
+		name string
+		cur  clockReading
+		want time.Duration
+	}{
+		{
+			name: "running",
+			cur:  clockReading{mono: start.Add(5 * time.Second), wall: start.Add(5 * time.Second).Round(0), boot: time.Hour + 5*time.Second},
+			want: 0,
+		},
+		{
+			name: "suspended per boot time",
+			cur:  clockReading{mono: start.Add(5 * time.Second), wall: start.Add(5 * time.Second).Round(0), boot: 2*time.Hour + 5*time.Second},
+			want: time.Hour,
+		},
+		{
+			name: "wall clock adjusted while boot time runs",
+			cur:  clockReading{mono: start.Add(5 * time.Second), wall: start.Add(time.Hour).Round(0), boot: time.Hour + 5*time.Second},
+			want: 0,
+		},
+		{
+			name: "suspended per wall clock without boot time",
+			cur:  clockReading{mono: start.Add(5 * time.Second), wall: start.Add(10 * time.Minute).Round(0)},
+			want: 10*time.Minute - 5*time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, suspended(prev, tt.cur))
+		})
+	}
+}
+
+func TestClockDetectorRegisterTwice(t *testing.T) {
+	d := NewClockDetector(time.Hour, time.Minute)
+	assert.NoError(t, d.Register(func(EventType) {}))
+	assert.Error(t, d.Register(func(EventType) {}))
+	assert.NoError(t, d.Deregister())
+	assert.NoError(t, d.Register(func(EventType) {}))
+	assert.NoError(t, d.Deregister())
+}
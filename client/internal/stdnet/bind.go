@@ -0,0 +1,87 @@
+package stdnet
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+)
+
+// SetBindInterface restricts the Net to the interface: only its addresses are discovered and the sockets are bound
+// to it, so the traffic leaves through it regardless of the routing table. It is used for the ICE sessions on the
+// secondary uplink of bonded peer connections.
+func (n *Net) SetBindInterface(name string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.bindInterface = name
+	n.interfaceFilter = func(iface string) bool {
+		return iface == name
+	}
+	return n.updateInterfaces()
+}
+
+// bindAddr returns the local address to listen on. Unspecified addresses are replaced with the address of the bound
+// interface, so the source address selects the uplink on systems without sockets bound to devices.
+func (n *Net) bindAddr(network string, addr *net.UDPAddr) (*net.UDPAddr, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.bindInterface == "" || (addr != nil && addr.IP != nil && !addr.IP.IsUnspecified()) {
+		return addr, nil
+	}
+
+	port := 0
+	if addr != nil {
+		port = addr.Port
+	}
+
+	for _, iface := range n.interfaces {
+		if iface.Name != n.bindInterface {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil, fmt.Errorf("get addresses of interface %s: %w", n.bindInterface, err)
+		}
+		for _, ifaceAddr := range addrs {
+			ip, ok := addrIP(ifaceAddr)
+			if !ok || ip.IsLinkLocalUnicast() || !networkMatches(network, ip) {
+				continue
+			}
+			return net.UDPAddrFromAddrPort(netip.AddrPortFrom(ip, uint16(port))), nil
+		}
+	}
+	return nil, fmt.Errorf("interface %s has no %s address", n.bindInterface, network)
+}
+
+func (n *Net) boundInterface() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.bindInterface
+}
+
+func addrIP(addr net.Addr) (netip.Addr, bool) {
+	var ip net.IP
+	switch a := addr.(type) {
+	case *net.IPNet:
+		ip = a.IP
+	case *net.IPAddr:
+		ip = a.IP
+	default:
+		return netip.Addr{}, false
+	}
+	parsed, ok := netip.AddrFromSlice(ip)
+	return parsed.Unmap(), ok
+}
+
+func networkMatches(network string, ip netip.Addr) bool {
+	switch network {
+	case "udp4":
+		return ip.Is4()
+	case "udp6":
+		return ip.Is6()
+	default:
+		return true
+	}
+}
@@ -0,0 +1,37 @@
+package stdnet
+
+import (
+	"fmt"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+
+	nbnet "github.com/netbirdio/netbird/client/net"
+)
+
+// bindToInterface binds the socket to the network interface with SO_BINDTODEVICE
+func bindToInterface(conn any, name string) error {
+	if pc, ok := conn.(*nbnet.PacketConn); ok {
+		conn = pc.PacketConn
+	}
+
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return fmt.Errorf("bind %T to interface %s: not a socket", conn, name)
+	}
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return fmt.Errorf("get raw socket: %w", err)
+	}
+
+	var bindErr error
+	if err := raw.Control(func(fd uintptr) {
+		bindErr = unix.BindToDevice(int(fd), name)
+	}); err != nil {
+		return fmt.Errorf("control raw socket: %w", err)
+	}
+	if bindErr != nil {
+		return fmt.Errorf("bind socket to interface %s: %w", name, bindErr)
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package stdnet
+
+// bindToInterface does nothing, the sockets are bound to the address of the interface instead
+func bindToInterface(any, string) error {
+	return nil
+}
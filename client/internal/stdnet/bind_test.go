@@ -0,0 +1,43 @@
+package stdnet
+
+import (
+	"net"
+	"testing"
+
+	"github.com/pion/transport/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNet_BindAddr(t *testing.T) {
+	wwan := transport.NewInterface(net.Interface{Name: "wwan1", Index: 3})
+	wwan.AddAddress(&net.IPNet{IP: net.ParseIP("fe80::1"), Mask: net.CIDRMask(64, 128)})
+	wwan.AddAddress(&net.IPNet{IP: net.ParseIP("10.64.0.2"), Mask: net.CIDRMask(24, 32)})
+	wwan.AddAddress(&net.IPNet{IP: net.ParseIP("2001:db8::2"), Mask: net.CIDRMask(64, 128)})
+
+	n := &Net{interfaces: []*transport.Interface{wwan}}
+
+	addr := &net.UDPAddr{Port: 1234}
+	got, err := n.bindAddr("udp4", addr)
+	require.NoError(t, err)
+	assert.Same(t, addr, got, "unbound nets listen on the given address")
+
+	n.bindInterface = "wwan1"
+
+	got, err = n.bindAddr("udp4", &net.UDPAddr{Port: 1234})
+	require.NoError(t, err)
+	assert.Equal(t, "10.64.0.2:1234", got.String())
+
+	got, err = n.bindAddr("udp6", &net.UDPAddr{IP: net.IPv6unspecified})
+	require.NoError(t, err)
+	assert.Equal(t, "[2001:db8::2]:0", got.String(), "link local addresses are skipped")
+
+	specific := &net.UDPAddr{IP: net.ParseIP("10.64.0.2"), Port: 5000}
+	got, err = n.bindAddr("udp4", specific)
+	require.NoError(t, err)
+	assert.Same(t, specific, got)
+
+	n.bindInterface = "wwan2"
+	_, err = n.bindAddr("udp4", nil)
+	assert.Error(t, err)
+}
@@ -5,16 +5,63 @@ import (
 	"net"
 
 	"github.com/pion/transport/v3"
+	log "github.com/sirupsen/logrus"
 
 	nbnet "github.com/netbirdio/netbird/client/net"
 )
 
 // ListenPacket listens for incoming packets on the given network and address.
 func (n *Net) ListenPacket(network, address string) (net.PacketConn, error) {
-	return nbnet.NewListener().ListenPacket(context.Background(), network, address)
+	if n.boundInterface() != "" {
+		locAddr, err := net.ResolveUDPAddr(network, address)
+		if err != nil {
+			return nil, err
+		}
+		if locAddr, err = n.bindAddr(network, locAddr); err != nil {
+			return nil, err
+		}
+		address = locAddr.String()
+	}
+
+	conn, err := nbnet.NewListener().ListenPacket(context.Background(), network, address)
+	if err != nil {
+		return nil, err
+	}
+	if err := n.bind(conn); err != nil {
+		return nil, err
+	}
+	return conn, nil
 }
 
 // ListenUDP acts like ListenPacket for UDP networks.
 func (n *Net) ListenUDP(network string, locAddr *net.UDPAddr) (transport.UDPConn, error) {
-	return nbnet.ListenUDP(network, locAddr)
+	locAddr, err := n.bindAddr(network, locAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := nbnet.ListenUDP(network, locAddr)
+	if err != nil {
+		return nil, err
+	}
+	if err := n.bind(conn); err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// bind binds the socket to the bound interface, the socket is closed if that fails
+func (n *Net) bind(conn net.PacketConn) error {
+	name := n.boundInterface()
+	if name == "" {
+		return nil
+	}
+
+	if err := bindToInterface(conn, name); err != nil {
+		if closeErr := conn.Close(); closeErr != nil {
+			log.Warnf("failed to close socket: %v", closeErr)
+		}
+		return err
+	}
+	return nil
 }
@@ -36,6 +36,8 @@ type Net struct {
 	// interfaceFilter should return true if the given interfaceName is allowed
 	interfaceFilter func(interfaceName string) bool
 	lastUpdate      time.Time
+	// bindInterface is the interface the sockets are bound to, empty if they aren't bound
+	bindInterface string
 
 	// mu is shared between interfaces and lastUpdate
 	mu sync.Mutex
@@ -0,0 +1,189 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pion/stun/v3"
+
+	icemaker "github.com/netbirdio/netbird/client/internal/peer/ice"
+)
+
+// turnTransportTTL is how long the transport that reached a TURN server is used before probing the transports again
+const turnTransportTTL = time.Hour
+
+type selectedTURNTransport struct {
+	transport  icemaker.TURNTransport
+	selectedAt time.Time
+}
+
+// turnTransportSelection selects the transport the TURN servers are reached over. Management announces the TURN
+// servers for UDP, on networks blocking UDP the servers are reached over TCP or TLS on port 443 instead. The
+// transports are probed in the background and the first reachable one in the order of preference is used.
+type turnTransportSelection struct {
+	transports []icemaker.TURNTransport
+	probe      func(ctx context.Context, uri *stun.URI) error
+
+	mu       sync.Mutex
+	selected map[string]selectedTURNTransport
+	probing  bool
+}
+
+func newTURNTransportSelection(transports []icemaker.TURNTransport, probe func(ctx context.Context, uri *stun.URI) error) *turnTransportSelection {
+	if len(transports) == 0 {
+		transports = icemaker.DefaultTURNTransports
+	}
+	return &turnTransportSelection{
+		transports: transports,
+		probe:      probe,
+		selected:   make(map[string]selectedTURNTransport),
+	}
+}
+
+// resolve returns the URIs of the selected transport of the TURN servers, the most preferred transport for servers
+// without a selection. It reports whether a server must be probed.
+func (t *turnTransportSelection) resolve(turns []*stun.URI) ([]*stun.URI, bool) {
+	if t == nil {
+		return turns, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	resolved := make([]*stun.URI, 0, len(turns))
+	var outdated bool
+	for _, uri := range turns {
+		variants := icemaker.TURNTransportURIs(uri, t.transports)
+		if len(variants) == 1 {
+			resolved = append(resolved, variants[0])
+			continue
+		}
+
+		selected, ok := t.selected[turnServerKey(uri)]
+		if !ok || time.Since(selected.selectedAt) > turnTransportTTL {
+			outdated = true
+		}
+		resolved = append(resolved, selectVariant(variants, selected.transport))
+	}
+	return resolved, outdated && !t.probing
+}
+
+// probeAll probes the transports of every TURN server concurrently and selects the most preferred reachable one.
+// If no transport reaches a server, the most preferred one stays in use and is probed again on the next update.
+func (t *turnTransportSelection) probeAll(ctx context.Context, turns []*stun.URI) {
+	t.mu.Lock()
+	if t.probing {
+		t.mu.Unlock()
+		return
+	}
+	t.probing = true
+	t.mu.Unlock()
+
+	defer func() {
+		t.mu.Lock()
+		t.probing = false
+		t.mu.Unlock()
+	}()
+
+	var wg sync.WaitGroup
+	for _, uri := range turns {
+		variants := icemaker.TURNTransportURIs(uri, t.transports)
+		if len(variants) == 1 {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			t.probeServer(ctx, uri, variants)
+		}()
+	}
+	wg.Wait()
+}
+
+func (t *turnTransportSelection) probeServer(ctx context.Context, uri *stun.URI, variants []*stun.URI) {
+	errs := make([]error, len(variants))
+	var wg sync.WaitGroup
+	for i, variant := range variants {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = t.probe(ctx, variant)
+		}()
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	for i, err := range errs {
+		if err != nil {
+			continue
+		}
+		transport := icemaker.TURNTransportOf(variants[i])
+		if i > 0 {
+			iceLog.Infof("TURN server %s is not reachable over %s, using %s (%s)", turnServerKey(uri),
+				icemaker.TURNTransportOf(variants[0]), transport, variants[i])
+		} else {
+			iceLog.Debugf("TURN server %s is reachable over %s", turnServerKey(uri), transport)
+		}
+
+		t.mu.Lock()
+		t.selected[turnServerKey(uri)] = selectedTURNTransport{transport: transport, selectedAt: time.Now()}
+		t.mu.Unlock()
+		return
+	}
+	iceLog.Warnf("TURN server %s is not reachable over any of the transports %v: %v", turnServerKey(uri), t.transports, errs)
+}
+
+func selectVariant(variants []*stun.URI, transport icemaker.TURNTransport) *stun.URI {
+	if transport != "" {
+		for _, variant := range variants {
+			if icemaker.TURNTransportOf(variant) == transport {
+				return variant
+			}
+		}
+	}
+	return variants[0]
+}
+
+func turnServerKey(uri *stun.URI) string {
+	return fmt.Sprintf("%s:%d", uri.Host, uri.Port)
+}
+
+// applyTURNTransports sets the TURNs to the selected transports and probes the transports in the background if
+// a selection is missing or outdated. The caller must hold syncMsgMux.
+func (e *Engine) applyTURNTransports(turns []*stun.URI) {
+	resolved, probe := e.turnTransports.resolve(turns)
+	e.TURNs = resolved
+	if !probe {
+		return
+	}
+
+	e.shutdownWg.Add(1)
+	go func() {
+		defer e.shutdownWg.Done()
+		e.turnTransports.probeAll(e.ctx, turns)
+
+		e.syncMsgMux.Lock()
+		defer e.syncMsgMux.Unlock()
+
+		if e.ctx.Err() != nil {
+			return
+		}
+		// management may have sent the TURNs again with new credentials meanwhile
+		e.TURNs, _ = e.turnTransports.resolve(e.announcedTURNs)
+		e.storeStunTurn()
+	}()
+}
+
+// storeStunTurn publishes the STUN and TURN servers to the ICE agents. The caller must hold syncMsgMux.
+func (e *Engine) storeStunTurn() {
+	var stunTurn []*stun.URI
+	stunTurn = append(stunTurn, e.STUNs...)
+	stunTurn = append(stunTurn, e.TURNs...)
+	e.stunTurn.Store(stunTurn)
+}
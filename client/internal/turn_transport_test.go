@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/pion/stun/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	icemaker "github.com/netbirdio/netbird/client/internal/peer/ice"
+)
+
+func TestTURNTransportSelection(t *testing.T) {
+	udp, err := stun.ParseURI("turn:turn.example.com:3478")
+	require.NoError(t, err)
+	tcp, err := stun.ParseURI("turn:tcp.example.com:3478?transport=tcp")
+	require.NoError(t, err)
+
+	reachable := map[icemaker.TURNTransport]bool{icemaker.TURNTransportTLS: true}
+	var mu sync.Mutex
+	var probed []string
+	selection := newTURNTransportSelection(nil, func(_ context.Context, uri *stun.URI) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if uri.Host == "turn.example.com" {
+			probed = append(probed, uri.String())
+		}
+		if reachable[icemaker.TURNTransportOf(uri)] {
+			return nil
+		}
+		return errors.New("unreachable")
+	})
+
+	resolved, probe := selection.resolve([]*stun.URI{udp, tcp})
+	assert.True(t, probe, "servers without a selection are probed")
+	assert.Equal(t, []string{"turn:turn.example.com:3478?transport=udp", "turn:tcp.example.com:3478?transport=tcp"},
+		uriStrings(resolved))
+
+	selection.probeAll(context.Background(), []*stun.URI{udp, tcp})
+	assert.ElementsMatch(t, []string{
+		"turn:turn.example.com:3478?transport=udp",
+		"turn:turn.example.com:3478?transport=tcp",
+		"turns:turn.example.com:443?transport=tcp",
+	}, probed)
+
+	resolved, probe = selection.resolve([]*stun.URI{udp, tcp})
+	assert.False(t, probe)
+	assert.Equal(t, []string{"turns:turn.example.com:443?transport=tcp", "turn:tcp.example.com:3478?transport=tcp"},
+		uriStrings(resolved))
+
+	// the most preferred reachable transport wins
+	mu.Lock()
+	reachable[icemaker.TURNTransportTCP] = true
+	mu.Unlock()
+	selection.probeAll(context.Background(), []*stun.URI{udp})
+	resolved, _ = selection.resolve([]*stun.URI{udp})
+	assert.Equal(t, []string{"turn:turn.example.com:3478?transport=tcp"}, uriStrings(resolved))
+}
+
+func TestTURNTransportSelection_SingleTransport(t *testing.T) {
+	udp, err := stun.ParseURI("turn:turn.example.com:3478")
+	require.NoError(t, err)
+
+	selection := newTURNTransportSelection([]icemaker.TURNTransport{icemaker.TURNTransportTLS}, func(context.Context, *stun.URI) error {
+		t.Fatal("a single transport is not probed")
+		return nil
+	})
+
+	resolved, probe := selection.resolve([]*stun.URI{udp})
+	assert.False(t, probe)
+	assert.Equal(t, []string{"turns:turn.example.com:443?transport=tcp"}, uriStrings(resolved))
+}
+
+func uriStrings(uris []*stun.URI) []string {
+	var strs []string
+	for _, uri := range uris {
+		strs = append(strs, uri.String())
+	}
+	return strs
+}
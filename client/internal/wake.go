@@ -0,0 +1,220 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/wol"
+	cProto "github.com/netbirdio/netbird/client/proto"
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+	signal "github.com/netbirdio/netbird/shared/signal/client"
+	sProto "github.com/netbirdio/netbird/shared/signal/proto"
+)
+
+// WakeResult describes a sent wake request
+type WakeResult struct {
+	// Target is the name of the woken machine
+	Target string
+	// Relay is the FQDN of the routing peer that sends the magic packet
+	Relay string
+	MAC   net.HardwareAddr
+}
+
+// Wake asks a routing peer on the site of the target to send a Wake-on-LAN magic packet to its local network.
+// The target is a peer FQDN, NetBird IP, public key or the name of a local Wake-on-LAN entry.
+// mac and via override the hardware address and the routing peer from the local config and management.
+func (e *Engine) Wake(target, mac, via string) (*WakeResult, error) {
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+
+	targetPeer, targetFound := findPeerState(e.statusRecorder.GetFullStatus().Peers, target)
+	wake := e.wakeTarget(target, targetPeer, targetFound)
+	if mac != "" {
+		wake.MAC = mac
+	}
+	if wake.MAC == "" {
+		return nil, fmt.Errorf("mac address of %s is unknown, set it with --mac or in the config", target)
+	}
+
+	hwAddr, err := wol.ParseMAC(wake.MAC)
+	if err != nil {
+		return nil, err
+	}
+
+	var lanAddr netip.Addr
+	if wake.LANAddress != "" {
+		if lanAddr, err = netip.ParseAddr(wake.LANAddress); err != nil {
+			return nil, fmt.Errorf("parse lan address of %s: %w", target, err)
+		}
+	}
+
+	relay, err := e.wakeRelay(via, lanAddr, targetPeer.PubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.signaler.SignalWake(relay.PubKey, hwAddr, lanAddr); err != nil {
+		return nil, fmt.Errorf("signal wake to %s: %w", relay.FQDN, err)
+	}
+	log.Infof("asked %s to wake up %s (%s)", relay.FQDN, target, hwAddr)
+
+	name := target
+	if targetFound {
+		name = targetPeer.FQDN
+	}
+	return &WakeResult{Target: name, Relay: relay.FQDN, MAC: hwAddr}, nil
+}
+
+// wakeTarget merges the management hint of the peer with the local config, the local config wins
+func (e *Engine) wakeTarget(target string, state peer.State, found bool) wol.Target {
+	var wake wol.Target
+	if found {
+		wake = e.wakeHints[state.PubKey]
+	}
+
+	keys := []string{target}
+	if found {
+		keys = append(keys, state.FQDN, state.IP)
+	}
+	for _, key := range keys {
+		local, ok := e.config.WakeOnLAN[key]
+		if !ok {
+			continue
+		}
+		if local.MAC != "" {
+			wake.MAC = local.MAC
+		}
+		if local.LANAddress != "" {
+			wake.LANAddress = local.LANAddress
+		}
+		break
+	}
+	return wake
+}
+
+// wakeRelay returns the routing peer that sends the magic packet. Without via it is a peer routing
+// a network that contains the LAN address of the machine, connected peers are preferred.
+func (e *Engine) wakeRelay(via string, lanAddr netip.Addr, targetKey string) (peer.State, error) {
+	if via != "" {
+		relay, ok := findPeerState(e.statusRecorder.GetFullStatus().Peers, via)
+		if !ok || relay.Offline {
+			return peer.State{}, fmt.Errorf("routing peer %s not found or offline", via)
+		}
+		if err := e.checkWakeSupport(relay); err != nil {
+			return peer.State{}, err
+		}
+		return relay, nil
+	}
+
+	if !lanAddr.IsValid() {
+		return peer.State{}, errors.New("lan address of the machine is unknown, select the routing peer with --via")
+	}
+
+	var candidate *peer.State
+	for _, routes := range e.routeManager.GetClientRoutes() {
+		for _, r := range routes {
+			if r.IsDynamic() || !r.Network.Contains(lanAddr) || r.Peer == targetKey {
+				continue
+			}
+			state, err := e.statusRecorder.GetPeer(r.Peer)
+			if err != nil || e.checkWakeSupport(state) != nil {
+				continue
+			}
+			if state.ConnStatus == peer.StatusConnected {
+				return state, nil
+			}
+			if candidate == nil {
+				candidate = &state
+			}
+		}
+	}
+	if candidate == nil {
+		return peer.State{}, fmt.Errorf("no routing peer found for %s, select one with --via", lanAddr)
+	}
+	return *candidate, nil
+}
+
+// checkWakeSupport returns an error if the peer announced features without Wake-on-LAN support
+func (e *Engine) checkWakeSupport(state peer.State) error {
+	conn, ok := e.peerStore.PeerConn(state.PubKey)
+	if !ok {
+		return fmt.Errorf("peer %s is not connectable", state.FQDN)
+	}
+	if features, known := conn.RemoteFeatures(); known && !features.Has(signal.FeatureWakeOnLAN) {
+		return fmt.Errorf("peer %s doesn't support Wake-on-LAN", state.FQDN)
+	}
+	return nil
+}
+
+// updateWakeHints stores the Wake-on-LAN hints that management sent for the peers
+func (e *Engine) updateWakeHints(peers []*mgmProto.RemotePeerConfig) {
+	hints := make(map[string]wol.Target)
+	for _, p := range peers {
+		cfg := p.GetWakeOnLan()
+		if cfg.GetMacAddress() == "" {
+			continue
+		}
+		hints[p.GetWgPubKey()] = wol.Target{MAC: cfg.GetMacAddress(), LANAddress: cfg.GetLanAddress()}
+	}
+	e.wakeHints = hints
+}
+
+// handleWake sends the magic packet requested by a remote peer to the local network, if this is a routing peer
+func (e *Engine) handleWake(pubKey string, wake *sProto.WakeOnLan) {
+	if e.routeManager == nil || e.routeManager.ServerRoutesCount() == 0 {
+		log.Warnf("ignoring wake request from %s, this peer doesn't route any network", pubKey)
+		return
+	}
+
+	mac := net.HardwareAddr(wake.GetMacAddress())
+	if len(mac) != 6 {
+		log.Warnf("ignoring wake request from %s with invalid mac address %s", pubKey, mac)
+		return
+	}
+
+	var lanAddr netip.Addr
+	if wake.GetLanAddress() != "" {
+		addr, err := netip.ParseAddr(wake.GetLanAddress())
+		if err != nil {
+			log.Warnf("ignoring wake request from %s with invalid lan address: %v", pubKey, err)
+			return
+		}
+		lanAddr = addr
+	}
+
+	wgIfaceName := e.wgInterface.Name()
+	go func() {
+		if err := wol.Send(mac, lanAddr, wgIfaceName); err != nil {
+			log.Errorf("failed to send wake-on-lan packet for %s requested by %s: %v", mac, pubKey, err)
+			return
+		}
+
+		log.Infof("sent wake-on-lan packet for %s requested by %s", mac, pubKey)
+		e.statusRecorder.PublishEvent(
+			cProto.SystemEvent_INFO,
+			cProto.SystemEvent_NETWORK,
+			"Sent Wake-on-LAN packet",
+			"",
+			map[string]string{"peer": pubKey, "mac": mac.String()},
+		)
+	}()
+}
+
+// findPeerState finds a peer by FQDN, short name, NetBird IP or public key
+func findPeerState(peers []peer.State, name string) (peer.State, bool) {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+	for _, p := range peers {
+		fqdn := strings.TrimSuffix(strings.ToLower(p.FQDN), ".")
+		short, _, _ := strings.Cut(fqdn, ".")
+		if name == fqdn || name == short || name == p.IP || name == strings.ToLower(p.PubKey) {
+			return p, true
+		}
+	}
+	return peer.State{}, false
+}
@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/wol"
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+func TestFindPeerState(t *testing.T) {
+	peers := []peer.State{
+		{FQDN: "desktop.netbird.cloud", IP: "100.64.0.10", PubKey: "desktopKey"},
+		{FQDN: "router.netbird.cloud.", IP: "100.64.0.1", PubKey: "routerKey"},
+	}
+
+	for _, name := range []string{"desktop.netbird.cloud", "Desktop", "100.64.0.10", "desktopkey"} {
+		p, ok := findPeerState(peers, name)
+		assert.True(t, ok, name)
+		assert.Equal(t, "desktopKey", p.PubKey, name)
+	}
+
+	p, ok := findPeerState(peers, "router.netbird.cloud")
+	assert.True(t, ok)
+	assert.Equal(t, "routerKey", p.PubKey)
+
+	_, ok = findPeerState(peers, "laptop")
+	assert.False(t, ok)
+}
+
+func TestEngine_WakeTarget(t *testing.T) {
+	desktop := peer.State{FQDN: "desktop.netbird.cloud", IP: "100.64.0.10", PubKey: "desktopKey"}
+
+	e := &Engine{config: &EngineConfig{
+		WakeOnLAN: map[string]wol.Target{
+			"100.64.0.10": {LANAddress: "192.168.1.20"},
+			"nas":         {MAC: "00:11:22:33:44:66", LANAddress: "192.168.1.30"},
+		},
+	}}
+	e.updateWakeHints([]*mgmProto.RemotePeerConfig{
+		{WgPubKey: "desktopKey", WakeOnLan: &mgmProto.WakeOnLanConfig{MacAddress: "00:11:22:33:44:55", LanAddress: "192.168.1.10"}},
+		{WgPubKey: "laptopKey"},
+	})
+	assert.Len(t, e.wakeHints, 1, "peers without hints are skipped")
+
+	assert.Equal(t, wol.Target{MAC: "00:11:22:33:44:55", LANAddress: "192.168.1.20"}, e.wakeTarget("desktop", desktop, true),
+		"the local config overrides the management hint")
+	assert.Equal(t, wol.Target{MAC: "00:11:22:33:44:66", LANAddress: "192.168.1.30"}, e.wakeTarget("nas", peer.State{}, false),
+		"machines that aren't peers come from the local config")
+	assert.Equal(t, wol.Target{}, e.wakeTarget("unknown", peer.State{}, false))
+}
@@ -0,0 +1,182 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	mgm "github.com/netbirdio/netbird/shared/management/client"
+	signal "github.com/netbirdio/netbird/shared/signal/client"
+)
+
+const (
+	// signalStreamTimeout limits how long a warm restart waits for the Signal stream of the new connection
+	signalStreamTimeout = 10 * time.Second
+	// relayReconnectTimeout limits how long a warm restart waits for the new connection to the home relay server
+	relayReconnectTimeout = 10 * time.Second
+)
+
+// enableWarmRestart makes the network monitor request a warm restart from the connect client instead of cancelling
+// the client context. It must be called before Start.
+func (e *Engine) enableWarmRestart() {
+	e.warmRestarts = make(chan struct{}, 1)
+}
+
+// warmRestartRequests returns the channel of the warm restart requests, nil if warm restarts aren't enabled
+func (e *Engine) warmRestartRequests() <-chan struct{} {
+	return e.warmRestarts
+}
+
+// requestWarmRestart asks the connect client to renew the connections of the engine to the NetBird services while
+// it keeps running. The exclusion routes are moved to the new default next hop first, so the new connections and
+// the peer endpoints are reachable through it.
+func (e *Engine) requestWarmRestart() {
+	if e.warmRestarts == nil {
+		e.triggerClientRestart()
+		return
+	}
+
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+
+	if e.ctx.Err() != nil {
+		return
+	}
+
+	if e.routeManager != nil {
+		if err := e.routeManager.ProtectEndpoints(); err != nil {
+			log.Warnf("failed to update the exclusion routes: %v", err)
+		}
+	}
+
+	log.Info("requesting a warm restart of the engine")
+	select {
+	case e.warmRestarts <- struct{}{}:
+	default:
+	}
+}
+
+// swapSession replaces the connections of the running engine to management and Signal with the ones of a new
+// session. The interface, routes, DNS, firewall and peer connections stay in place. The new Signal stream is
+// connected before the swap, so no message of the peers is lost in between. After the swap the peer connections
+// restart ICE and the network map management sends on the new Sync stream is reconciled with the applied one.
+func (e *Engine) swapSession(mgmClient mgm.Client, signalClient signal.Client) error {
+	e.syncMsgMux.Lock()
+	if e.ctx.Err() != nil {
+		e.syncMsgMux.Unlock()
+		return errors.New("engine stopped")
+	}
+	ctx, cancel := context.WithCancel(e.ctx)
+	e.syncMsgMux.Unlock()
+
+	e.receiveSignalEvents(ctx, signalClient)
+	if err := waitSignalStream(ctx, signalClient, signalStreamTimeout); err != nil {
+		cancel()
+		return err
+	}
+
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+
+	if e.ctx.Err() != nil {
+		cancel()
+		return errors.New("engine stopped")
+	}
+
+	// a signal message that is being handled finishes before the streams are replaced
+	e.peerConnMux.Lock()
+	e.sessionCancel()
+	e.sessionCtx, e.sessionCancel = ctx, cancel
+	e.signal = signalClient
+	e.signaler.SetClient(signalClient)
+	if e.srWatcher != nil {
+		e.srWatcher.SetSignalClient(signalClient)
+	}
+	e.mgmClient = mgmClient
+	e.peerConnMux.Unlock()
+
+	e.receiveManagementEvents(ctx, mgmClient)
+	e.restartPeersICE()
+
+	return nil
+}
+
+// waitSignalStream waits until the client is connected to the Signal stream
+func waitSignalStream(ctx context.Context, signalClient signal.Client, timeout time.Duration) error {
+	connected := make(chan struct{})
+	go func() {
+		signalClient.WaitStreamConnected()
+		close(connected)
+	}()
+
+	select {
+	case <-connected:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(timeout):
+		return fmt.Errorf("no Signal stream within %s", timeout)
+	}
+}
+
+// warmRestart opens a new session while the engine keeps running and swaps its connections into the engine. The
+// replaced connections are closed afterwards. The relay connections are renewed alongside, the relayed peers
+// reconnect through the new ones.
+//
+// The interface, DNS, firewall and routes aren't rebuilt. They don't depend on the network path: the interface keeps
+// its address, the exclusion routes were moved to the new next hop by requestWarmRestart and the network map is
+// reconciled over the new Sync stream. Rebuilding them would interrupt the traffic for seconds.
+func (b *engineBuilder) warmRestart(current *engineSession, engine *Engine) error {
+	start := time.Now()
+
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		reconnectRelay(current)
+	}()
+	defer func() {
+		<-relayDone
+		log.Debugf("warm restart finished in %s", time.Since(start))
+	}()
+
+	next, err := b.openSession(current)
+	if err != nil {
+		return fmt.Errorf("open session: %w", err)
+	}
+
+	// the address is part of the interface, the firewall and the routes, a changed one needs a new engine
+	if addr := next.loginResp.GetPeerConfig().GetAddress(); addr != current.engineConfig.WgAddr {
+		next.close()
+		return fmt.Errorf("the peer address changed to %s", addr)
+	}
+
+	if err := engine.swapSession(next.mgmClient, next.signalClient); err != nil {
+		next.close()
+		return fmt.Errorf("swap the connections: %w", err)
+	}
+	current.adopt(next)
+	b.reportSession(current)
+
+	log.Infof("renewed the connections of the engine in %s", time.Since(start))
+	return nil
+}
+
+// reconnectRelay renews the connections of the session to the relay servers, the previous ones are bound to the
+// network path before the change
+func reconnectRelay(session *engineSession) {
+	if session.relayManager == nil || !session.relayManager.HasRelayAddress() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(session.ctx, relayReconnectTimeout)
+	defer cancel()
+	if err := session.relayManager.Reconnect(ctx); err != nil {
+		// the reconnect guard of the relay manager keeps trying
+		log.Warnf("failed to renew the relay connections: %v", err)
+		return
+	}
+	log.Debugf("renewed the relay connections")
+}
@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/peerstore"
+	"github.com/netbirdio/netbird/client/system"
+	mgm "github.com/netbirdio/netbird/shared/management/client"
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+	signal "github.com/netbirdio/netbird/shared/signal/client"
+	sProto "github.com/netbirdio/netbird/shared/signal/proto"
+)
+
+func TestEngine_RequestWarmRestart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	e := &Engine{ctx: ctx, syncMsgMux: &sync.Mutex{}}
+	e.enableWarmRestart()
+
+	e.requestWarmRestart()
+	e.requestWarmRestart()
+
+	select {
+	case <-e.warmRestartRequests():
+	default:
+		t.Fatal("expected a warm restart request")
+	}
+	select {
+	case <-e.warmRestartRequests():
+		t.Fatal("expected the requests to be coalesced")
+	default:
+	}
+
+	cancel()
+	e.requestWarmRestart()
+	select {
+	case <-e.warmRestartRequests():
+		t.Fatal("expected no request from a stopped engine")
+	default:
+	}
+}
+
+func newSwapTestEngine(t *testing.T, ctx context.Context) *Engine {
+	t.Helper()
+	key, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+
+	signalClient := &signal.MockClient{}
+	e := &Engine{
+		ctx:        ctx,
+		syncMsgMux: &sync.Mutex{},
+		config:     &EngineConfig{WgPrivateKey: key},
+		peerStore:  peerstore.NewConnStore(),
+		signal:     signalClient,
+		signaler:   peer.NewSignaler(signalClient, key),
+		mgmClient:  &mgm.MockClient{},
+	}
+	e.sessionCtx, e.sessionCancel = context.WithCancel(ctx)
+	return e
+}
+
+func TestEngine_SwapSession(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	e := newSwapTestEngine(t, ctx)
+	replacedCtx := e.sessionCtx
+
+	receiving := make(chan struct{})
+	signalClient := &signal.MockClient{
+		ReceiveFunc: func(ctx context.Context, _ func(msg *sProto.Message) error) error {
+			close(receiving)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	syncing := make(chan struct{})
+	mgmClient := &mgm.MockClient{
+		SyncFunc: func(ctx context.Context, _ *system.Info, _ func(msg *mgmProto.SyncResponse) error) error {
+			close(syncing)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+
+	require.NoError(t, e.swapSession(mgmClient, signalClient))
+
+	for _, ch := range []chan struct{}{receiving, syncing} {
+		select {
+		case <-ch:
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected the streams of the new connections to be opened")
+		}
+	}
+	assert.Same(t, signalClient, e.signal)
+	assert.Same(t, mgmClient, e.mgmClient)
+	assert.Error(t, replacedCtx.Err(), "the streams of the replaced connections are closed")
+	assert.NoError(t, e.ctx.Err(), "the engine keeps running")
+
+	cancel()
+	e.shutdownWg.Wait()
+}
+
+func TestEngine_SwapSession_Stopped(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	e := newSwapTestEngine(t, ctx)
+	previous := e.signal
+	cancel()
+
+	assert.Error(t, e.swapSession(&mgm.MockClient{}, &signal.MockClient{}))
+	assert.Same(t, previous, e.signal)
+}
@@ -0,0 +1,110 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/netbirdio/netbird/client/iface/configurer"
+)
+
+const (
+	// DefaultWGStatsInterval is the interval of the WireGuard stats updates if none is requested
+	DefaultWGStatsInterval = time.Second
+	// MinWGStatsInterval is the shortest interval of the WireGuard stats updates
+	MinWGStatsInterval = 250 * time.Millisecond
+)
+
+// ErrEngineStopped is returned by the engine streams when the engine stops
+var ErrEngineStopped = errors.New("engine stopped")
+
+// WGPeerStats is the WireGuard transfer of a peer since the previous update
+type WGPeerStats struct {
+	PubKey        string
+	RxBytes       int64
+	TxBytes       int64
+	LastHandshake time.Time
+	// Handshake is set if a handshake with the peer completed since the previous update
+	Handshake bool
+}
+
+// WGStatsUpdate is an update of the WireGuard stats stream
+type WGStatsUpdate struct {
+	Time time.Time
+	// Elapsed is the time since the previous update, the transfer of the peers covers it
+	Elapsed time.Duration
+	Peers   []WGPeerStats
+}
+
+// StreamWGStats samples the WireGuard counters of the peers every interval and sends their growth since the
+// previous sample, until the context is done or the engine stops. The first update follows the first interval.
+// It reads the counters without holding syncMsgMux, so a stream doesn't delay the network map updates.
+func (e *Engine) StreamWGStats(ctx context.Context, interval time.Duration, send func(WGStatsUpdate) error) error {
+	if interval <= 0 {
+		interval = DefaultWGStatsInterval
+	}
+	interval = max(interval, MinWGStatsInterval)
+
+	e.syncMsgMux.Lock()
+	wgIface, engineCtx := e.wgInterface, e.ctx
+	e.syncMsgMux.Unlock()
+	if wgIface == nil || engineCtx == nil {
+		return ErrEngineStopped
+	}
+
+	last, err := wgIface.GetStats()
+	if err != nil {
+		return fmt.Errorf("get wireguard stats: %w", err)
+	}
+	lastTime := time.Now()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-engineCtx.Done():
+			return ErrEngineStopped
+		case <-ticker.C:
+		}
+
+		stats, err := wgIface.GetStats()
+		if err != nil {
+			return fmt.Errorf("get wireguard stats: %w", err)
+		}
+
+		now := time.Now()
+		update := WGStatsUpdate{
+			Time:    now,
+			Elapsed: now.Sub(lastTime),
+			Peers:   wgStatsDeltas(last, stats),
+		}
+		if err := send(update); err != nil {
+			return err
+		}
+		last, lastTime = stats, now
+	}
+}
+
+// wgStatsDeltas returns the transfer of the peers between two samples, sorted by the public key
+func wgStatsDeltas(last, current map[string]configurer.WGStats) []WGPeerStats {
+	peers := make([]WGPeerStats, 0, len(current))
+	for key, stats := range current {
+		prev := last[key]
+		peers = append(peers, WGPeerStats{
+			PubKey:        key,
+			RxBytes:       counterDelta(prev.RxBytes, stats.RxBytes),
+			TxBytes:       counterDelta(prev.TxBytes, stats.TxBytes),
+			LastHandshake: stats.LastHandshake,
+			Handshake:     !stats.LastHandshake.IsZero() && stats.LastHandshake.After(prev.LastHandshake),
+		})
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].PubKey < peers[j].PubKey
+	})
+	return peers
+}
@@ -0,0 +1,34 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/client/iface/configurer"
+)
+
+func TestWGStatsDeltas(t *testing.T) {
+	handshake := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	last := map[string]configurer.WGStats{
+		"peer-a": {TxBytes: 100, RxBytes: 200, LastHandshake: handshake},
+		"peer-b": {TxBytes: 500, RxBytes: 700, LastHandshake: handshake},
+		"peer-c": {TxBytes: 1, RxBytes: 1},
+	}
+	current := map[string]configurer.WGStats{
+		// the WireGuard counters were reset, e.g. because the peer was recreated
+		"peer-b": {TxBytes: 10, RxBytes: 20, LastHandshake: handshake.Add(2 * time.Minute)},
+		"peer-a": {TxBytes: 150, RxBytes: 300, LastHandshake: handshake},
+		"peer-c": {TxBytes: 1, RxBytes: 1},
+		"peer-d": {TxBytes: 5, RxBytes: 7, LastHandshake: handshake},
+	}
+
+	assert.Equal(t, []WGPeerStats{
+		{PubKey: "peer-a", TxBytes: 50, RxBytes: 100, LastHandshake: handshake},
+		{PubKey: "peer-b", TxBytes: 10, RxBytes: 20, LastHandshake: handshake.Add(2 * time.Minute), Handshake: true},
+		{PubKey: "peer-c"},
+		{PubKey: "peer-d", TxBytes: 5, RxBytes: 7, LastHandshake: handshake, Handshake: true},
+	}, wgStatsDeltas(last, current))
+}
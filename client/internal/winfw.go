@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"os"
+	"runtime"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/winfw"
+	sshserver "github.com/netbirdio/netbird/client/ssh/server"
+)
+
+// updateWindowsFirewall creates the Windows Defender Firewall rules for the WireGuard port of the agent and the
+// embedded SSH server when it runs, so Defender doesn't silently drop inbound connections.
+// The rules are kept across engine restarts and removed when the service is uninstalled.
+func (e *Engine) updateWindowsFirewall() {
+	if runtime.GOOS != "windows" || e.wgInterface == nil {
+		return
+	}
+
+	cfg := winfw.Config{
+		WgPort:        e.config.WgPort,
+		PublicProfile: winfw.PublicProfileByEnv(),
+	}
+
+	program, err := os.Executable()
+	if err != nil {
+		log.Warnf("failed to get executable path for windows firewall rule: %v", err)
+	} else {
+		cfg.Program = program
+	}
+
+	if e.sshServer != nil {
+		cfg.SSHPort = sshserver.InternalSSHPort
+		cfg.LocalIP = e.wgInterface.Address().IP
+	}
+
+	if cfg == e.winfwConfig {
+		return
+	}
+
+	if err := winfw.Apply(cfg); err != nil {
+		log.Warnf("failed to update windows firewall rules: %v", err)
+		return
+	}
+	e.winfwConfig = cfg
+}
@@ -0,0 +1,92 @@
+// Package winfw manages the Windows Defender Firewall rules the client needs to accept inbound connections.
+// Without them Defender silently drops inbound WireGuard handshakes and connections to embedded services.
+package winfw
+
+import (
+	"net/netip"
+	"os"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// RuleAgent allows inbound WireGuard traffic to the agent binary on the WireGuard listen port
+	RuleAgent = "NetBird Agent"
+	// RuleWireGuard is the port rule of older versions, it is only removed
+	RuleWireGuard = "NetBird WireGuard"
+	// RuleSSH allows inbound traffic to the embedded SSH server on the NetBird address
+	RuleSSH = "NetBird SSH"
+
+	// EnvPublicProfile enables the agent rule in the public profile, too
+	EnvPublicProfile = "NB_WINDOWS_FIREWALL_PUBLIC_PROFILE"
+)
+
+// ruleNames lists all rules managed by this package, used for cleanup
+var ruleNames = []string{RuleAgent, RuleWireGuard, RuleSSH}
+
+// Config describes the rules that should exist
+type Config struct {
+	// Program is the path of the agent binary, empty allows the port for any program
+	Program string
+	// WgPort is the WireGuard listen port, 0 skips the agent rule
+	WgPort int
+	// PublicProfile enables the agent rule in the public profile. Without it peers on public networks
+	// only accept connections they initiated or punched a hole for.
+	PublicProfile bool
+	// SSHPort is the port of the embedded SSH server, 0 means the server is not running
+	SSHPort uint16
+	// LocalIP is the NetBird address the SSH server listens on
+	LocalIP netip.Addr
+}
+
+type rule struct {
+	name string
+	args []string
+}
+
+// rules returns the netsh arguments of the rules matching the config
+func rules(cfg Config) []rule {
+	var rs []rule
+
+	if cfg.WgPort > 0 {
+		profile := "profile=domain,private"
+		if cfg.PublicProfile {
+			profile = "profile=any"
+		}
+		args := []string{"dir=in", "action=allow", "enable=yes", profile, "protocol=udp", "localport=" + strconv.Itoa(cfg.WgPort)}
+		if cfg.Program != "" {
+			args = append(args, "program="+cfg.Program)
+		}
+		rs = append(rs, rule{name: RuleAgent, args: args})
+	}
+
+	// Windows puts the NetBird interface into the public profile as an unidentified network, the rule is limited to
+	// the NetBird address instead
+	if cfg.SSHPort > 0 && cfg.LocalIP.IsValid() {
+		rs = append(rs, rule{
+			name: RuleSSH,
+			args: []string{
+				"dir=in", "action=allow", "enable=yes", "profile=any", "protocol=tcp",
+				"localport=" + strconv.Itoa(int(cfg.SSHPort)),
+				"localip=" + cfg.LocalIP.String(),
+			},
+		})
+	}
+
+	return rs
+}
+
+// PublicProfileByEnv returns whether the agent rule should be enabled in the public profile
+func PublicProfileByEnv() bool {
+	val := os.Getenv(EnvPublicProfile)
+	if val == "" {
+		return false
+	}
+	enabled, err := strconv.ParseBool(val)
+	if err != nil {
+		log.Warnf("failed to parse %s: %v", EnvPublicProfile, err)
+		return false
+	}
+	return enabled
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package winfw
+
+// Apply is a no-op on non-Windows platforms
+func Apply(Config) error {
+	return nil
+}
+
+// Remove is a no-op on non-Windows platforms
+func Remove() error {
+	return nil
+}
@@ -0,0 +1,85 @@
+package winfw
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ruleNamesOf(rs []rule) []string {
+	var names []string
+	for _, r := range rs {
+		names = append(names, r.name)
+	}
+	return names
+}
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      Config
+		expected []string
+	}{
+		{
+			name:     "empty config",
+			cfg:      Config{},
+			expected: nil,
+		},
+		{
+			name:     "agent",
+			cfg:      Config{Program: `C:\Program Files\NetBird\netbird.exe`, WgPort: 51820},
+			expected: []string{RuleAgent},
+		},
+		{
+			name:     "agent without port is skipped",
+			cfg:      Config{Program: `C:\Program Files\NetBird\netbird.exe`},
+			expected: nil,
+		},
+		{
+			name:     "ssh without address is skipped",
+			cfg:      Config{WgPort: 51820, SSHPort: 22022},
+			expected: []string{RuleAgent},
+		},
+		{
+			name: "ssh",
+			cfg: Config{
+				Program: `C:\Program Files\NetBird\netbird.exe`,
+				WgPort:  51820,
+				SSHPort: 22022,
+				LocalIP: netip.MustParseAddr("100.64.0.1"),
+			},
+			expected: []string{RuleAgent, RuleSSH},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ruleNamesOf(rules(tc.cfg)))
+		})
+	}
+}
+
+func TestRuleArgs(t *testing.T) {
+	rs := rules(Config{
+		Program: `C:\Program Files\NetBird\netbird.exe`,
+		WgPort:  51820,
+		SSHPort: 22022,
+		LocalIP: netip.MustParseAddr("100.64.0.1"),
+	})
+
+	assert.Contains(t, rs[0].args, `program=C:\Program Files\NetBird\netbird.exe`)
+	assert.Contains(t, rs[0].args, "protocol=udp")
+	assert.Contains(t, rs[0].args, "localport=51820")
+	assert.Contains(t, rs[0].args, "profile=domain,private")
+	assert.Contains(t, rs[1].args, "protocol=tcp")
+	assert.Contains(t, rs[1].args, "localport=22022")
+	assert.Contains(t, rs[1].args, "localip=100.64.0.1")
+}
+
+func TestRuleArgs_PublicProfile(t *testing.T) {
+	rs := rules(Config{WgPort: 51820, PublicProfile: true})
+
+	assert.Contains(t, rs[0].args, "profile=any")
+	assert.NotContains(t, rs[0].args, "profile=domain,private")
+}
@@ -0,0 +1,113 @@
+package winfw
+
+import (
+	"fmt"
+	"os/exec"
+	"slices"
+	"syscall"
+
+	"github.com/hashicorp/go-multierror"
+	log "github.com/sirupsen/logrus"
+
+	nberrors "github.com/netbirdio/netbird/client/errors"
+)
+
+// Apply creates the rules of the config and removes the managed rules that are no longer needed.
+// Existing rules are replaced, so changed ports or paths are picked up.
+func Apply(cfg Config) error {
+	if !isFirewallReachable() {
+		return nil
+	}
+
+	desired := rules(cfg)
+
+	var merr *multierror.Error
+	for _, r := range desired {
+		if ruleExists(r.name) {
+			if err := netsh("delete", "rule", "name="+r.name); err != nil {
+				merr = multierror.Append(merr, fmt.Errorf("delete rule %s: %w", r.name, err))
+				continue
+			}
+		}
+
+		args := append([]string{"add", "rule", "name=" + r.name}, r.args...)
+		if err := netsh(args...); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("add rule %s: %w", r.name, err))
+			continue
+		}
+		log.Debugf("added windows firewall rule %s", r.name)
+	}
+
+	for _, name := range ruleNames {
+		if slices.ContainsFunc(desired, func(r rule) bool { return r.name == name }) {
+			continue
+		}
+		if err := removeRule(name); err != nil {
+			merr = multierror.Append(merr, err)
+		}
+	}
+
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+// Remove deletes all rules managed by this package
+func Remove() error {
+	if !isFirewallReachable() {
+		return nil
+	}
+
+	var merr *multierror.Error
+	for _, name := range ruleNames {
+		if err := removeRule(name); err != nil {
+			merr = multierror.Append(merr, err)
+		}
+	}
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+func removeRule(name string) error {
+	if !ruleExists(name) {
+		return nil
+	}
+
+	if err := netsh("delete", "rule", "name="+name); err != nil {
+		return fmt.Errorf("delete rule %s: %w", name, err)
+	}
+	log.Debugf("removed windows firewall rule %s", name)
+	return nil
+}
+
+func ruleExists(name string) bool {
+	return netsh("show", "rule", "name="+name) == nil
+}
+
+func isFirewallReachable() bool {
+	cmd := exec.Command(getSystem32Command("netsh"), "advfirewall", "show", "allprofiles", "state")
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+
+	if _, err := cmd.Output(); err != nil {
+		log.Infof("Windows firewall is not reachable, skipping rule management: %v", err)
+		return false
+	}
+	return true
+}
+
+func netsh(args ...string) error {
+	cmd := exec.Command(getSystem32Command("netsh"), append([]string{"advfirewall", "firewall"}, args...)...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, out)
+	}
+	return nil
+}
+
+// getSystem32Command checks if a command can be found in the system path and returns it. In case it can't find it
+// in the path it will return the full path of a command assuming C:\windows\system32 as the base path.
+func getSystem32Command(command string) string {
+	if _, err := exec.LookPath(command); err == nil {
+		return command
+	}
+
+	return "C:\\windows\\system32\\" + command + ".exe"
+}
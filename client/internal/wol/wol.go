@@ -0,0 +1,185 @@
+// Package wol sends Wake-on-LAN magic packets to the local networks of the host.
+// Routing peers use it to wake up machines of their site on behalf of other peers.
+package wol
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	log "github.com/sirupsen/logrus"
+
+	nberrors "github.com/netbirdio/netbird/client/errors"
+)
+
+// Port is the discard port commonly used for magic packets
+const Port = 9
+
+// Target describes the machine to wake up
+type Target struct {
+	// MAC is the hardware address of the network card of the machine
+	MAC string `json:"mac"`
+	// LANAddress is the address of the machine in its local network. It selects the routing peer and the interface
+	// the magic packet is sent on. If empty, the packet is broadcast on all interfaces of the routing peer.
+	LANAddress string `json:"lanAddress,omitempty"`
+}
+
+// ParseMAC parses a 48 bit hardware address, the only size used by Wake-on-LAN
+func ParseMAC(s string) (net.HardwareAddr, error) {
+	mac, err := net.ParseMAC(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("parse mac address: %w", err)
+	}
+	if len(mac) != 6 {
+		return nil, fmt.Errorf("mac address %s is not 48 bits long", s)
+	}
+	return mac, nil
+}
+
+// MagicPacket builds the magic packet for the hardware address: 6 bytes of 0xff followed by 16 repetitions of the address
+func MagicPacket(mac net.HardwareAddr) ([]byte, error) {
+	if len(mac) != 6 {
+		return nil, fmt.Errorf("mac address %s is not 48 bits long", mac)
+	}
+
+	packet := make([]byte, 0, 6+16*6)
+	for i := 0; i < 6; i++ {
+		packet = append(packet, 0xff)
+	}
+	for i := 0; i < 16; i++ {
+		packet = append(packet, mac...)
+	}
+	return packet, nil
+}
+
+// Send broadcasts the magic packet for the hardware address on the local networks.
+// If lanAddr is valid only the network containing it is used. Interfaces in skipIfaces are never used.
+func Send(mac net.HardwareAddr, lanAddr netip.Addr, skipIfaces ...string) error {
+	packet, err := MagicPacket(mac)
+	if err != nil {
+		return err
+	}
+
+	networks, err := broadcastNetworks(skipIfaces)
+	if err != nil {
+		return err
+	}
+
+	var merr *multierror.Error
+	sent := 0
+	for _, n := range networks {
+		if lanAddr.IsValid() && !n.prefix.Contains(lanAddr.Unmap()) {
+			continue
+		}
+
+		if err := sendTo(packet, n.prefix.Addr(), directedBroadcast(n.prefix)); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("send on %s: %w", n.iface, err))
+			continue
+		}
+		log.Debugf("sent wake-on-lan packet for %s on %s", mac, n.iface)
+		sent++
+	}
+
+	switch {
+	case sent > 0:
+		return nil
+	case merr != nil:
+		return nberrors.FormatErrorOrNil(merr)
+	case lanAddr.IsValid():
+		return fmt.Errorf("no local network contains %s", lanAddr)
+	default:
+		return errors.New("no local network with broadcast support")
+	}
+}
+
+type broadcastNetwork struct {
+	iface  string
+	prefix netip.Prefix
+}
+
+// broadcastNetworks returns the IPv4 networks of the interfaces that are up and support broadcasts
+func broadcastNetworks(skipIfaces []string) ([]broadcastNetwork, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("list interfaces: %w", err)
+	}
+
+	var networks []broadcastNetwork
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagBroadcast == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		if isSkipped(iface.Name, skipIfaces) {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			log.Debugf("failed to get addresses of %s: %v", iface.Name, err)
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			prefix, ok := ipNetToPrefix(ipNet)
+			if !ok || !prefix.Addr().Is4() || prefix.Bits() >= 31 {
+				continue
+			}
+			networks = append(networks, broadcastNetwork{iface: iface.Name, prefix: prefix})
+		}
+	}
+	return networks, nil
+}
+
+func isSkipped(name string, skipIfaces []string) bool {
+	for _, skip := range skipIfaces {
+		if name == skip {
+			return true
+		}
+	}
+	return false
+}
+
+func ipNetToPrefix(ipNet *net.IPNet) (netip.Prefix, bool) {
+	addr, ok := netip.AddrFromSlice(ipNet.IP)
+	if !ok {
+		return netip.Prefix{}, false
+	}
+	ones, _ := ipNet.Mask.Size()
+	return netip.PrefixFrom(addr.Unmap(), ones), true
+}
+
+// directedBroadcast returns the broadcast address of an IPv4 network
+func directedBroadcast(prefix netip.Prefix) netip.Addr {
+	ip := prefix.Addr().As4()
+	hostBits := 32 - prefix.Bits()
+	for i := 3; i >= 0 && hostBits > 0; i-- {
+		bits := min(hostBits, 8)
+		ip[i] |= byte(1<<bits - 1)
+		hostBits -= bits
+	}
+	return netip.AddrFrom4(ip)
+}
+
+// sendTo sends the packet from the local address, so it leaves through the interface of the network
+func sendTo(packet []byte, local, broadcast netip.Addr) error {
+	conn, err := net.ListenUDP("udp4", net.UDPAddrFromAddrPort(netip.AddrPortFrom(local, 0)))
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Debugf("failed to close wake-on-lan socket: %v", err)
+		}
+	}()
+
+	if _, err := conn.WriteToUDPAddrPort(packet, netip.AddrPortFrom(broadcast, Port)); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package wol
+
+import (
+	"bytes"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMagicPacket(t *testing.T) {
+	mac, err := ParseMAC("00:11:22:33:44:55")
+	require.NoError(t, err)
+
+	packet, err := MagicPacket(mac)
+	require.NoError(t, err)
+	require.Len(t, packet, 102)
+
+	assert.Equal(t, bytes.Repeat([]byte{0xff}, 6), packet[:6])
+	for i := 0; i < 16; i++ {
+		offset := 6 + i*6
+		assert.Equal(t, []byte(mac), packet[offset:offset+6])
+	}
+}
+
+func TestParseMAC(t *testing.T) {
+	_, err := ParseMAC("00-11-22-33-44-55")
+	assert.NoError(t, err)
+
+	_, err = ParseMAC("00:11:22:33:44:55:66:77")
+	assert.Error(t, err, "EUI-64 isn't supported")
+
+	_, err = ParseMAC("not a mac")
+	assert.Error(t, err)
+}
+
+func TestDirectedBroadcast(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   string
+	}{
+		{prefix: "192.168.1.10/24", want: "192.168.1.255"},
+		{prefix: "10.0.0.1/8", want: "10.255.255.255"},
+		{prefix: "172.16.5.4/20", want: "172.16.15.255"},
+		{prefix: "192.168.1.129/25", want: "192.168.1.255"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			assert.Equal(t, netip.MustParseAddr(tt.want), directedBroadcast(netip.MustParsePrefix(tt.prefix)))
+		})
+	}
+}
@@ -0,0 +1,234 @@
+// Package proxy routes the connections to the management, signal and relay servers through an HTTP, HTTPS or SOCKS5
+// proxy, for networks that only allow outbound connections through a mandatory proxy.
+//
+// The proxy is the one set with SetURL, otherwise the one of the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment
+// variables.
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+	xproxy "golang.org/x/net/proxy"
+)
+
+// DialFunc dials a connection, the connection to the proxy is dialed with it
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+var (
+	mu       sync.RWMutex
+	explicit *url.URL
+	fromEnv  = sync.OnceValue(func() func(*url.URL) (*url.URL, error) {
+		return httpproxy.FromEnvironment().ProxyFunc()
+	})
+)
+
+// SetURL sets the proxy used for all servers, an empty URL uses the proxy of the environment again. The schemes
+// http, https, socks5 and socks5h are supported, credentials are taken from the user info.
+func SetURL(rawURL string) error {
+	var proxyURL *url.URL
+	if rawURL != "" {
+		var err error
+		proxyURL, err = Parse(rawURL)
+		if err != nil {
+			return err
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	explicit = proxyURL
+	return nil
+}
+
+// Parse parses and validates a proxy URL
+func Parse(rawURL string) (*url.URL, error) {
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse proxy URL: %w", err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q, supported schemes are: http, https, socks5, socks5h", proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("proxy URL %q has no host", rawURL)
+	}
+	return proxyURL, nil
+}
+
+// URLFor returns the proxy for the target URL, nil if the target is connected directly
+func URLFor(target *url.URL) (*url.URL, error) {
+	mu.RLock()
+	proxyURL := explicit
+	mu.RUnlock()
+
+	if proxyURL != nil {
+		return proxyURL, nil
+	}
+	return fromEnv()(target)
+}
+
+// Enabled reports whether the connections to the address go through a proxy
+func Enabled(addr string, secure bool) bool {
+	proxyURL, err := URLFor(targetURL(addr, secure))
+	return err == nil && proxyURL != nil
+}
+
+// HTTPProxy returns the proxy of the request, for http.Transport.Proxy
+func HTTPProxy(req *http.Request) (*url.URL, error) {
+	return URLFor(req.URL)
+}
+
+// DialContext dials the TCP address through the proxy, or directly with dial if it doesn't go through a proxy. Secure
+// selects the proxy of HTTPS_PROXY instead of HTTP_PROXY, like for https URLs.
+func DialContext(ctx context.Context, dial DialFunc, network, addr string, secure bool) (net.Conn, error) {
+	proxyURL, err := URLFor(targetURL(addr, secure))
+	if err != nil {
+		return nil, fmt.Errorf("get proxy for %s: %w", addr, err)
+	}
+	if proxyURL == nil {
+		return dial(ctx, network, addr)
+	}
+
+	switch proxyURL.Scheme {
+	case "socks5", "socks5h":
+		return dialSOCKS5(ctx, dial, proxyURL, network, addr)
+	default:
+		return dialConnect(ctx, dial, proxyURL, addr)
+	}
+}
+
+func targetURL(addr string, secure bool) *url.URL {
+	if secure {
+		return &url.URL{Scheme: "https", Host: addr}
+	}
+	return &url.URL{Scheme: "http", Host: addr}
+}
+
+// dialConnect tunnels the connection through an HTTP or HTTPS proxy with the CONNECT method
+func dialConnect(ctx context.Context, dial DialFunc, proxyURL *url.URL, addr string) (_ net.Conn, err error) {
+	conn, err := dial(ctx, "tcp", hostPort(proxyURL))
+	if err != nil {
+		return nil, fmt.Errorf("dial proxy %s: %w", proxyURL.Host, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = conn.Close()
+		}
+	}()
+
+	if proxyURL.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return nil, fmt.Errorf("proxy tls handshake: %w", err)
+		}
+		conn = tlsConn
+	}
+
+	// the deadline of the context bounds the CONNECT exchange, the tunnel itself has no deadline
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, fmt.Errorf("set deadline: %w", err)
+		}
+		defer func() {
+			if clearErr := conn.SetDeadline(time.Time{}); clearErr != nil && err == nil {
+				err = fmt.Errorf("clear deadline: %w", clearErr)
+			}
+		}()
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if user := proxyURL.User; user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := req.Write(conn); err != nil {
+		return nil, fmt.Errorf("write CONNECT request: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return nil, fmt.Errorf("read CONNECT response: %w", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("proxy %s refused the connection to %s: %s", proxyURL.Host, addr, resp.Status)
+	}
+
+	if reader.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, reader: reader}, nil
+	}
+	return conn, nil
+}
+
+func dialSOCKS5(ctx context.Context, dial DialFunc, proxyURL *url.URL, network, addr string) (net.Conn, error) {
+	var auth *xproxy.Auth
+	if user := proxyURL.User; user != nil {
+		password, _ := user.Password()
+		auth = &xproxy.Auth{User: user.Username(), Password: password}
+	}
+
+	dialer, err := xproxy.SOCKS5("tcp", hostPort(proxyURL), auth, forwardDialer(dial))
+	if err != nil {
+		return nil, fmt.Errorf("create socks5 dialer: %w", err)
+	}
+	conn, err := dialer.(xproxy.ContextDialer).DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s through socks5 proxy %s: %w", addr, proxyURL.Host, err)
+	}
+	return conn, nil
+}
+
+func hostPort(proxyURL *url.URL) string {
+	if proxyURL.Port() != "" {
+		return proxyURL.Host
+	}
+	switch proxyURL.Scheme {
+	case "https":
+		return net.JoinHostPort(proxyURL.Hostname(), "443")
+	case "socks5", "socks5h":
+		return net.JoinHostPort(proxyURL.Hostname(), "1080")
+	default:
+		return net.JoinHostPort(proxyURL.Hostname(), "80")
+	}
+}
+
+// forwardDialer dials the connections to the SOCKS5 proxy
+type forwardDialer DialFunc
+
+func (d forwardDialer) Dial(network, addr string) (net.Conn, error) {
+	return d(context.Background(), network, addr)
+}
+
+func (d forwardDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return d(ctx, network, addr)
+}
+
+// bufferedConn returns the data the proxy sent right after the CONNECT response before reading from the connection
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
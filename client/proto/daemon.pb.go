@@ -138,6 +138,61 @@ func (OSLifecycleRequest_CycleType) EnumDescriptor() ([]byte, []int) {
 	return file_daemon_proto_rawDescGZIP(), []int{1, 0}
 }
 
+type PeerListOptions_SortField int32
+
+const (
+	PeerListOptions_IP            PeerListOptions_SortField = 0
+	PeerListOptions_FQDN          PeerListOptions_SortField = 1
+	PeerListOptions_STATUS        PeerListOptions_SortField = 2
+	PeerListOptions_LATENCY       PeerListOptions_SortField = 3
+	PeerListOptions_STATUS_UPDATE PeerListOptions_SortField = 4
+)
+
+// Enum value maps for PeerListOptions_SortField.
+var (
+	PeerListOptions_SortField_name = map[int32]string{
+		0: "IP",
+		1: "FQDN",
+		2: "STATUS",
+		3: "LATENCY",
+		4: "STATUS_UPDATE",
+	}
+	PeerListOptions_SortField_value = map[string]int32{
+		"IP":            0,
+		"FQDN":          1,
+		"STATUS":        2,
+		"LATENCY":       3,
+		"STATUS_UPDATE": 4,
+	}
+)
+
+func (x PeerListOptions_SortField) Enum() *PeerListOptions_SortField {
+	p := new(PeerListOptions_SortField)
+	*p = x
+	return p
+}
+
+func (x PeerListOptions_SortField) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PeerListOptions_SortField) Descriptor() protoreflect.EnumDescriptor {
+	return file_daemon_proto_enumTypes[2].Descriptor()
+}
+
+func (PeerListOptions_SortField) Type() protoreflect.EnumType {
+	return &file_daemon_proto_enumTypes[2]
+}
+
+func (x PeerListOptions_SortField) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PeerListOptions_SortField.Descriptor instead.
+func (PeerListOptions_SortField) EnumDescriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{10, 0}
+}
+
 type SystemEvent_Severity int32
 
 const (
@@ -174,11 +229,11 @@ func (x SystemEvent_Severity) String() string {
 }
 
 func (SystemEvent_Severity) Descriptor() protoreflect.EnumDescriptor {
-	return file_daemon_proto_enumTypes[2].Descriptor()
+	return file_daemon_proto_enumTypes[3].Descriptor()
 }
 
 func (SystemEvent_Severity) Type() protoreflect.EnumType {
-	return &file_daemon_proto_enumTypes[2]
+	return &file_daemon_proto_enumTypes[3]
 }
 
 func (x SystemEvent_Severity) Number() protoreflect.EnumNumber {
@@ -187,7 +242,7 @@ func (x SystemEvent_Severity) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use SystemEvent_Severity.Descriptor instead.
 func (SystemEvent_Severity) EnumDescriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{53, 0}
+	return file_daemon_proto_rawDescGZIP(), []int{130, 0}
 }
 
 type SystemEvent_Category int32
@@ -229,11 +284,11 @@ func (x SystemEvent_Category) String() string {
 }
 
 func (SystemEvent_Category) Descriptor() protoreflect.EnumDescriptor {
-	return file_daemon_proto_enumTypes[3].Descriptor()
+	return file_daemon_proto_enumTypes[4].Descriptor()
 }
 
 func (SystemEvent_Category) Type() protoreflect.EnumType {
-	return &file_daemon_proto_enumTypes[3]
+	return &file_daemon_proto_enumTypes[4]
 }
 
 func (x SystemEvent_Category) Number() protoreflect.EnumNumber {
@@ -242,7 +297,7 @@ func (x SystemEvent_Category) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use SystemEvent_Category.Descriptor instead.
 func (SystemEvent_Category) EnumDescriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{53, 1}
+	return file_daemon_proto_rawDescGZIP(), []int{130, 1}
 }
 
 type EmptyRequest struct {
@@ -417,6 +472,12 @@ type LoginRequest struct {
 	EnableSSHRemotePortForwarding *bool   `protobuf:"varint,37,opt,name=enableSSHRemotePortForwarding,proto3,oneof" json:"enableSSHRemotePortForwarding,omitempty"`
 	DisableSSHAuth                *bool   `protobuf:"varint,38,opt,name=disableSSHAuth,proto3,oneof" json:"disableSSHAuth,omitempty"`
 	SshJWTCacheTTL                *int32  `protobuf:"varint,39,opt,name=sshJWTCacheTTL,proto3,oneof" json:"sshJWTCacheTTL,omitempty"`
+	KillSwitch                    *bool   `protobuf:"varint,40,opt,name=kill_switch,json=killSwitch,proto3,oneof" json:"kill_switch,omitempty"`
+	Socks5ProxyAddress            *string `protobuf:"bytes,41,opt,name=socks5ProxyAddress,proto3,oneof" json:"socks5ProxyAddress,omitempty"`
+	HttpProxyAddress              *string `protobuf:"bytes,42,opt,name=httpProxyAddress,proto3,oneof" json:"httpProxyAddress,omitempty"`
+	IceMulticastDNS               *bool   `protobuf:"varint,43,opt,name=iceMulticastDNS,proto3,oneof" json:"iceMulticastDNS,omitempty"`
+	IcePolicy                     *string `protobuf:"bytes,44,opt,name=icePolicy,proto3,oneof" json:"icePolicy,omitempty"`
+	FirewallBackend               *string `protobuf:"bytes,45,opt,name=firewallBackend,proto3,oneof" json:"firewallBackend,omitempty"`
 	unknownFields                 protoimpl.UnknownFields
 	sizeCache                     protoimpl.SizeCache
 }
@@ -725,6 +786,48 @@ func (x *LoginRequest) GetSshJWTCacheTTL() int32 {
 	return 0
 }
 
+func (x *LoginRequest) GetKillSwitch() bool {
+	if x != nil && x.KillSwitch != nil {
+		return *x.KillSwitch
+	}
+	return false
+}
+
+func (x *LoginRequest) GetSocks5ProxyAddress() string {
+	if x != nil && x.Socks5ProxyAddress != nil {
+		return *x.Socks5ProxyAddress
+	}
+	return ""
+}
+
+func (x *LoginRequest) GetHttpProxyAddress() string {
+	if x != nil && x.HttpProxyAddress != nil {
+		return *x.HttpProxyAddress
+	}
+	return ""
+}
+
+func (x *LoginRequest) GetIceMulticastDNS() bool {
+	if x != nil && x.IceMulticastDNS != nil {
+		return *x.IceMulticastDNS
+	}
+	return false
+}
+
+func (x *LoginRequest) GetIcePolicy() string {
+	if x != nil && x.IcePolicy != nil {
+		return *x.IcePolicy
+	}
+	return ""
+}
+
+func (x *LoginRequest) GetFirewallBackend() string {
+	if x != nil && x.FirewallBackend != nil {
+		return *x.FirewallBackend
+	}
+	return ""
+}
+
 type LoginResponse struct {
 	state                   protoimpl.MessageState `protogen:"open.v1"`
 	NeedsSSOLogin           bool                   `protobuf:"varint,1,opt,name=needsSSOLogin,proto3" json:"needsSSOLogin,omitempty"`
@@ -990,9 +1093,11 @@ type StatusRequest struct {
 	GetFullPeerStatus bool                   `protobuf:"varint,1,opt,name=getFullPeerStatus,proto3" json:"getFullPeerStatus,omitempty"`
 	ShouldRunProbes   bool                   `protobuf:"varint,2,opt,name=shouldRunProbes,proto3" json:"shouldRunProbes,omitempty"`
 	// the UI do not using this yet, but CLIs could use it to wait until the status is ready
-	WaitForReady  *bool `protobuf:"varint,3,opt,name=waitForReady,proto3,oneof" json:"waitForReady,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	WaitForReady *bool `protobuf:"varint,3,opt,name=waitForReady,proto3,oneof" json:"waitForReady,omitempty"`
+	// filters, sorts and paginates the peers of the full status, all peers are returned if unset
+	PeerListOptions *PeerListOptions `protobuf:"bytes,4,opt,name=peerListOptions,proto3" json:"peerListOptions,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *StatusRequest) Reset() {
@@ -1046,6 +1151,128 @@ func (x *StatusRequest) GetWaitForReady() bool {
 	return false
 }
 
+func (x *StatusRequest) GetPeerListOptions() *PeerListOptions {
+	if x != nil {
+		return x.PeerListOptions
+	}
+	return nil
+}
+
+type PeerListOptions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// returns the peers with one of the connection statuses (idle, connecting, connected), case insensitive
+	Statuses []string `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	// returns the peers whose FQDN starts with one of the names
+	Names []string `protobuf:"bytes,2,rep,name=names,proto3" json:"names,omitempty"`
+	// returns the peers with one of the IPs
+	Ips []string `protobuf:"bytes,3,rep,name=ips,proto3" json:"ips,omitempty"`
+	// returns the connected peers with the connection type (P2P or Relayed), case insensitive
+	ConnectionType string `protobuf:"bytes,4,opt,name=connectionType,proto3" json:"connectionType,omitempty"`
+	// returns the peers whose FQDN, IP or public key contains the text, case insensitive
+	Search     string                    `protobuf:"bytes,5,opt,name=search,proto3" json:"search,omitempty"`
+	SortBy     PeerListOptions_SortField `protobuf:"varint,6,opt,name=sortBy,proto3,enum=daemon.PeerListOptions_SortField" json:"sortBy,omitempty"`
+	Descending bool                      `protobuf:"varint,7,opt,name=descending,proto3" json:"descending,omitempty"`
+	// number of matching peers to skip
+	Offset uint32 `protobuf:"varint,8,opt,name=offset,proto3" json:"offset,omitempty"`
+	// maximum number of peers to return, zero returns all matching peers
+	Limit         uint32 `protobuf:"varint,9,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PeerListOptions) Reset() {
+	*x = PeerListOptions{}
+	mi := &file_daemon_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerListOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerListOptions) ProtoMessage() {}
+
+func (x *PeerListOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerListOptions.ProtoReflect.Descriptor instead.
+func (*PeerListOptions) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PeerListOptions) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *PeerListOptions) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+func (x *PeerListOptions) GetIps() []string {
+	if x != nil {
+		return x.Ips
+	}
+	return nil
+}
+
+func (x *PeerListOptions) GetConnectionType() string {
+	if x != nil {
+		return x.ConnectionType
+	}
+	return ""
+}
+
+func (x *PeerListOptions) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *PeerListOptions) GetSortBy() PeerListOptions_SortField {
+	if x != nil {
+		return x.SortBy
+	}
+	return PeerListOptions_IP
+}
+
+func (x *PeerListOptions) GetDescending() bool {
+	if x != nil {
+		return x.Descending
+	}
+	return false
+}
+
+func (x *PeerListOptions) GetOffset() uint32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *PeerListOptions) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
 type StatusResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// status of the server.
@@ -1059,7 +1286,7 @@ type StatusResponse struct {
 
 func (x *StatusResponse) Reset() {
 	*x = StatusResponse{}
-	mi := &file_daemon_proto_msgTypes[10]
+	mi := &file_daemon_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1071,7 +1298,7 @@ func (x *StatusResponse) String() string {
 func (*StatusResponse) ProtoMessage() {}
 
 func (x *StatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[10]
+	mi := &file_daemon_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1084,7 +1311,7 @@ func (x *StatusResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
 func (*StatusResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{10}
+	return file_daemon_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *StatusResponse) GetStatus() string {
@@ -1116,7 +1343,7 @@ type DownRequest struct {
 
 func (x *DownRequest) Reset() {
 	*x = DownRequest{}
-	mi := &file_daemon_proto_msgTypes[11]
+	mi := &file_daemon_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1128,7 +1355,7 @@ func (x *DownRequest) String() string {
 func (*DownRequest) ProtoMessage() {}
 
 func (x *DownRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[11]
+	mi := &file_daemon_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1141,7 +1368,7 @@ func (x *DownRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DownRequest.ProtoReflect.Descriptor instead.
 func (*DownRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{11}
+	return file_daemon_proto_rawDescGZIP(), []int{12}
 }
 
 type DownResponse struct {
@@ -1152,7 +1379,7 @@ type DownResponse struct {
 
 func (x *DownResponse) Reset() {
 	*x = DownResponse{}
-	mi := &file_daemon_proto_msgTypes[12]
+	mi := &file_daemon_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1164,7 +1391,7 @@ func (x *DownResponse) String() string {
 func (*DownResponse) ProtoMessage() {}
 
 func (x *DownResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[12]
+	mi := &file_daemon_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1177,7 +1404,7 @@ func (x *DownResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DownResponse.ProtoReflect.Descriptor instead.
 func (*DownResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{12}
+	return file_daemon_proto_rawDescGZIP(), []int{13}
 }
 
 type GetConfigRequest struct {
@@ -1190,7 +1417,7 @@ type GetConfigRequest struct {
 
 func (x *GetConfigRequest) Reset() {
 	*x = GetConfigRequest{}
-	mi := &file_daemon_proto_msgTypes[13]
+	mi := &file_daemon_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1202,7 +1429,7 @@ func (x *GetConfigRequest) String() string {
 func (*GetConfigRequest) ProtoMessage() {}
 
 func (x *GetConfigRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[13]
+	mi := &file_daemon_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1215,7 +1442,7 @@ func (x *GetConfigRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetConfigRequest.ProtoReflect.Descriptor instead.
 func (*GetConfigRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{13}
+	return file_daemon_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *GetConfigRequest) GetProfileName() string {
@@ -1265,13 +1492,19 @@ type GetConfigResponse struct {
 	EnableSSHRemotePortForwarding bool   `protobuf:"varint,23,opt,name=enableSSHRemotePortForwarding,proto3" json:"enableSSHRemotePortForwarding,omitempty"`
 	DisableSSHAuth                bool   `protobuf:"varint,25,opt,name=disableSSHAuth,proto3" json:"disableSSHAuth,omitempty"`
 	SshJWTCacheTTL                int32  `protobuf:"varint,26,opt,name=sshJWTCacheTTL,proto3" json:"sshJWTCacheTTL,omitempty"`
+	KillSwitch                    bool   `protobuf:"varint,27,opt,name=kill_switch,json=killSwitch,proto3" json:"kill_switch,omitempty"`
+	Socks5ProxyAddress            string `protobuf:"bytes,28,opt,name=socks5ProxyAddress,proto3" json:"socks5ProxyAddress,omitempty"`
+	HttpProxyAddress              string `protobuf:"bytes,29,opt,name=httpProxyAddress,proto3" json:"httpProxyAddress,omitempty"`
+	IceMulticastDNS               bool   `protobuf:"varint,30,opt,name=iceMulticastDNS,proto3" json:"iceMulticastDNS,omitempty"`
+	IcePolicy                     string `protobuf:"bytes,31,opt,name=icePolicy,proto3" json:"icePolicy,omitempty"`
+	FirewallBackend               string `protobuf:"bytes,32,opt,name=firewallBackend,proto3" json:"firewallBackend,omitempty"`
 	unknownFields                 protoimpl.UnknownFields
 	sizeCache                     protoimpl.SizeCache
 }
 
 func (x *GetConfigResponse) Reset() {
 	*x = GetConfigResponse{}
-	mi := &file_daemon_proto_msgTypes[14]
+	mi := &file_daemon_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1283,7 +1516,7 @@ func (x *GetConfigResponse) String() string {
 func (*GetConfigResponse) ProtoMessage() {}
 
 func (x *GetConfigResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[14]
+	mi := &file_daemon_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1296,7 +1529,7 @@ func (x *GetConfigResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetConfigResponse.ProtoReflect.Descriptor instead.
 func (*GetConfigResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{14}
+	return file_daemon_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *GetConfigResponse) GetManagementUrl() string {
@@ -1481,6 +1714,48 @@ func (x *GetConfigResponse) GetSshJWTCacheTTL() int32 {
 	return 0
 }
 
+func (x *GetConfigResponse) GetKillSwitch() bool {
+	if x != nil {
+		return x.KillSwitch
+	}
+	return false
+}
+
+func (x *GetConfigResponse) GetSocks5ProxyAddress() string {
+	if x != nil {
+		return x.Socks5ProxyAddress
+	}
+	return ""
+}
+
+func (x *GetConfigResponse) GetHttpProxyAddress() string {
+	if x != nil {
+		return x.HttpProxyAddress
+	}
+	return ""
+}
+
+func (x *GetConfigResponse) GetIceMulticastDNS() bool {
+	if x != nil {
+		return x.IceMulticastDNS
+	}
+	return false
+}
+
+func (x *GetConfigResponse) GetIcePolicy() string {
+	if x != nil {
+		return x.IcePolicy
+	}
+	return ""
+}
+
+func (x *GetConfigResponse) GetFirewallBackend() string {
+	if x != nil {
+		return x.FirewallBackend
+	}
+	return ""
+}
+
 // PeerState contains the latest state of a peer
 type PeerState struct {
 	state                      protoimpl.MessageState `protogen:"open.v1"`
@@ -1502,13 +1777,27 @@ type PeerState struct {
 	Latency                    *durationpb.Duration   `protobuf:"bytes,17,opt,name=latency,proto3" json:"latency,omitempty"`
 	RelayAddress               string                 `protobuf:"bytes,18,opt,name=relayAddress,proto3" json:"relayAddress,omitempty"`
 	SshHostKey                 []byte                 `protobuf:"bytes,19,opt,name=sshHostKey,proto3" json:"sshHostKey,omitempty"`
-	unknownFields              protoimpl.UnknownFields
-	sizeCache                  protoimpl.SizeCache
+	// maintenance is true if the peer announced that it is about to go down
+	Maintenance bool `protobuf:"varint,20,opt,name=maintenance,proto3" json:"maintenance,omitempty"`
+	// allowedIps are the addresses management assigned to the peer
+	AllowedIps []string `protobuf:"bytes,21,rep,name=allowedIps,proto3" json:"allowedIps,omitempty"`
+	// offline is true if management reported the peer as offline or login expired
+	Offline bool `protobuf:"varint,22,opt,name=offline,proto3" json:"offline,omitempty"`
+	// totalBytesRx and totalBytesTx are the transfer counters accumulated across restarts
+	TotalBytesRx  int64                  `protobuf:"varint,23,opt,name=totalBytesRx,proto3" json:"totalBytesRx,omitempty"`
+	TotalBytesTx  int64                  `protobuf:"varint,24,opt,name=totalBytesTx,proto3" json:"totalBytesTx,omitempty"`
+	LastConnected *timestamppb.Timestamp `protobuf:"bytes,25,opt,name=lastConnected,proto3" json:"lastConnected,omitempty"`
+	// offlineReason tells why management doesn't let an offline peer connect, empty if the peer is just offline
+	OfflineReason string `protobuf:"bytes,26,opt,name=offlineReason,proto3" json:"offlineReason,omitempty"`
+	// networkTransfers break the transfer down per routed network of the peer, they are taken from the flow counters
+	NetworkTransfers []*NetworkTransfer `protobuf:"bytes,27,rep,name=networkTransfers,proto3" json:"networkTransfers,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *PeerState) Reset() {
 	*x = PeerState{}
-	mi := &file_daemon_proto_msgTypes[15]
+	mi := &file_daemon_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1520,7 +1809,7 @@ func (x *PeerState) String() string {
 func (*PeerState) ProtoMessage() {}
 
 func (x *PeerState) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[15]
+	mi := &file_daemon_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1533,7 +1822,7 @@ func (x *PeerState) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PeerState.ProtoReflect.Descriptor instead.
 func (*PeerState) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{15}
+	return file_daemon_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *PeerState) GetIP() string {
@@ -1662,53 +1951,174 @@ func (x *PeerState) GetSshHostKey() []byte {
 	return nil
 }
 
-// LocalPeerState contains the latest state of the local peer
-type LocalPeerState struct {
-	state               protoimpl.MessageState `protogen:"open.v1"`
-	IP                  string                 `protobuf:"bytes,1,opt,name=IP,proto3" json:"IP,omitempty"`
-	PubKey              string                 `protobuf:"bytes,2,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
-	KernelInterface     bool                   `protobuf:"varint,3,opt,name=kernelInterface,proto3" json:"kernelInterface,omitempty"`
-	Fqdn                string                 `protobuf:"bytes,4,opt,name=fqdn,proto3" json:"fqdn,omitempty"`
-	RosenpassEnabled    bool                   `protobuf:"varint,5,opt,name=rosenpassEnabled,proto3" json:"rosenpassEnabled,omitempty"`
-	RosenpassPermissive bool                   `protobuf:"varint,6,opt,name=rosenpassPermissive,proto3" json:"rosenpassPermissive,omitempty"`
-	Networks            []string               `protobuf:"bytes,7,rep,name=networks,proto3" json:"networks,omitempty"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+func (x *PeerState) GetMaintenance() bool {
+	if x != nil {
+		return x.Maintenance
+	}
+	return false
 }
 
-func (x *LocalPeerState) Reset() {
-	*x = LocalPeerState{}
-	mi := &file_daemon_proto_msgTypes[16]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *PeerState) GetAllowedIps() []string {
+	if x != nil {
+		return x.AllowedIps
+	}
+	return nil
 }
 
-func (x *LocalPeerState) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *PeerState) GetOffline() bool {
+	if x != nil {
+		return x.Offline
+	}
+	return false
 }
 
-func (*LocalPeerState) ProtoMessage() {}
+func (x *PeerState) GetTotalBytesRx() int64 {
+	if x != nil {
+		return x.TotalBytesRx
+	}
+	return 0
+}
 
-func (x *LocalPeerState) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[16]
+func (x *PeerState) GetTotalBytesTx() int64 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.TotalBytesTx
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use LocalPeerState.ProtoReflect.Descriptor instead.
-func (*LocalPeerState) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{16}
+func (x *PeerState) GetLastConnected() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastConnected
+	}
+	return nil
 }
 
-func (x *LocalPeerState) GetIP() string {
+func (x *PeerState) GetOfflineReason() string {
 	if x != nil {
-		return x.IP
+		return x.OfflineReason
+	}
+	return ""
+}
+
+func (x *PeerState) GetNetworkTransfers() []*NetworkTransfer {
+	if x != nil {
+		return x.NetworkTransfers
+	}
+	return nil
+}
+
+// NetworkTransfer holds the transfer counters of a routed network
+type NetworkTransfer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Network       string                 `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	BytesRx       int64                  `protobuf:"varint,2,opt,name=bytesRx,proto3" json:"bytesRx,omitempty"`
+	BytesTx       int64                  `protobuf:"varint,3,opt,name=bytesTx,proto3" json:"bytesTx,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkTransfer) Reset() {
+	*x = NetworkTransfer{}
+	mi := &file_daemon_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkTransfer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkTransfer) ProtoMessage() {}
+
+func (x *NetworkTransfer) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkTransfer.ProtoReflect.Descriptor instead.
+func (*NetworkTransfer) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *NetworkTransfer) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *NetworkTransfer) GetBytesRx() int64 {
+	if x != nil {
+		return x.BytesRx
+	}
+	return 0
+}
+
+func (x *NetworkTransfer) GetBytesTx() int64 {
+	if x != nil {
+		return x.BytesTx
+	}
+	return 0
+}
+
+// LocalPeerState contains the latest state of the local peer
+type LocalPeerState struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	IP                  string                 `protobuf:"bytes,1,opt,name=IP,proto3" json:"IP,omitempty"`
+	PubKey              string                 `protobuf:"bytes,2,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
+	KernelInterface     bool                   `protobuf:"varint,3,opt,name=kernelInterface,proto3" json:"kernelInterface,omitempty"`
+	Fqdn                string                 `protobuf:"bytes,4,opt,name=fqdn,proto3" json:"fqdn,omitempty"`
+	RosenpassEnabled    bool                   `protobuf:"varint,5,opt,name=rosenpassEnabled,proto3" json:"rosenpassEnabled,omitempty"`
+	RosenpassPermissive bool                   `protobuf:"varint,6,opt,name=rosenpassPermissive,proto3" json:"rosenpassPermissive,omitempty"`
+	Networks            []string               `protobuf:"bytes,7,rep,name=networks,proto3" json:"networks,omitempty"`
+	// roles are the roles the peer derived from its local state, e.g. routing-peer or exit-node
+	Roles []string `protobuf:"bytes,8,rep,name=roles,proto3" json:"roles,omitempty"`
+	// networkTransfers break the transfer down per network routed by the local peer
+	NetworkTransfers []*NetworkTransfer `protobuf:"bytes,9,rep,name=networkTransfers,proto3" json:"networkTransfers,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *LocalPeerState) Reset() {
+	*x = LocalPeerState{}
+	mi := &file_daemon_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocalPeerState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocalPeerState) ProtoMessage() {}
+
+func (x *LocalPeerState) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocalPeerState.ProtoReflect.Descriptor instead.
+func (*LocalPeerState) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *LocalPeerState) GetIP() string {
+	if x != nil {
+		return x.IP
 	}
 	return ""
 }
@@ -1755,19 +2165,39 @@ func (x *LocalPeerState) GetNetworks() []string {
 	return nil
 }
 
+func (x *LocalPeerState) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *LocalPeerState) GetNetworkTransfers() []*NetworkTransfer {
+	if x != nil {
+		return x.NetworkTransfers
+	}
+	return nil
+}
+
 // SignalState contains the latest state of a signal connection
 type SignalState struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	URL           string                 `protobuf:"bytes,1,opt,name=URL,proto3" json:"URL,omitempty"`
-	Connected     bool                   `protobuf:"varint,2,opt,name=connected,proto3" json:"connected,omitempty"`
-	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	URL       string                 `protobuf:"bytes,1,opt,name=URL,proto3" json:"URL,omitempty"`
+	Connected bool                   `protobuf:"varint,2,opt,name=connected,proto3" json:"connected,omitempty"`
+	Error     string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	// reconnects counts how often the signal stream was connected again after it had been disconnected
+	Reconnects           int32                  `protobuf:"varint,4,opt,name=reconnects,proto3" json:"reconnects,omitempty"`
+	LastDisconnect       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=lastDisconnect,proto3" json:"lastDisconnect,omitempty"`
+	LastDisconnectReason string                 `protobuf:"bytes,6,opt,name=lastDisconnectReason,proto3" json:"lastDisconnectReason,omitempty"`
+	// latency is the round trip time of the last message sent through signal
+	Latency       *durationpb.Duration `protobuf:"bytes,7,opt,name=latency,proto3" json:"latency,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SignalState) Reset() {
 	*x = SignalState{}
-	mi := &file_daemon_proto_msgTypes[17]
+	mi := &file_daemon_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1779,7 +2209,7 @@ func (x *SignalState) String() string {
 func (*SignalState) ProtoMessage() {}
 
 func (x *SignalState) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[17]
+	mi := &file_daemon_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1792,7 +2222,7 @@ func (x *SignalState) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SignalState.ProtoReflect.Descriptor instead.
 func (*SignalState) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{17}
+	return file_daemon_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *SignalState) GetURL() string {
@@ -1816,6 +2246,95 @@ func (x *SignalState) GetError() string {
 	return ""
 }
 
+func (x *SignalState) GetReconnects() int32 {
+	if x != nil {
+		return x.Reconnects
+	}
+	return 0
+}
+
+func (x *SignalState) GetLastDisconnect() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastDisconnect
+	}
+	return nil
+}
+
+func (x *SignalState) GetLastDisconnectReason() string {
+	if x != nil {
+		return x.LastDisconnectReason
+	}
+	return ""
+}
+
+func (x *SignalState) GetLatency() *durationpb.Duration {
+	if x != nil {
+		return x.Latency
+	}
+	return nil
+}
+
+// FlowState contains the latest state of the connection to the flow receiver, the URL is empty if flow logging is disabled
+type FlowState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	URL           string                 `protobuf:"bytes,1,opt,name=URL,proto3" json:"URL,omitempty"`
+	Connected     bool                   `protobuf:"varint,2,opt,name=connected,proto3" json:"connected,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlowState) Reset() {
+	*x = FlowState{}
+	mi := &file_daemon_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlowState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlowState) ProtoMessage() {}
+
+func (x *FlowState) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlowState.ProtoReflect.Descriptor instead.
+func (*FlowState) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *FlowState) GetURL() string {
+	if x != nil {
+		return x.URL
+	}
+	return ""
+}
+
+func (x *FlowState) GetConnected() bool {
+	if x != nil {
+		return x.Connected
+	}
+	return false
+}
+
+func (x *FlowState) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 // ManagementState contains the latest state of a management connection
 type ManagementState struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1828,7 +2347,7 @@ type ManagementState struct {
 
 func (x *ManagementState) Reset() {
 	*x = ManagementState{}
-	mi := &file_daemon_proto_msgTypes[18]
+	mi := &file_daemon_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1840,7 +2359,7 @@ func (x *ManagementState) String() string {
 func (*ManagementState) ProtoMessage() {}
 
 func (x *ManagementState) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[18]
+	mi := &file_daemon_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1853,7 +2372,7 @@ func (x *ManagementState) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ManagementState.ProtoReflect.Descriptor instead.
 func (*ManagementState) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{18}
+	return file_daemon_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *ManagementState) GetURL() string {
@@ -1889,7 +2408,7 @@ type RelayState struct {
 
 func (x *RelayState) Reset() {
 	*x = RelayState{}
-	mi := &file_daemon_proto_msgTypes[19]
+	mi := &file_daemon_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1901,7 +2420,7 @@ func (x *RelayState) String() string {
 func (*RelayState) ProtoMessage() {}
 
 func (x *RelayState) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[19]
+	mi := &file_daemon_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1914,7 +2433,7 @@ func (x *RelayState) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RelayState.ProtoReflect.Descriptor instead.
 func (*RelayState) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{19}
+	return file_daemon_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *RelayState) GetURI() string {
@@ -1950,7 +2469,7 @@ type NSGroupState struct {
 
 func (x *NSGroupState) Reset() {
 	*x = NSGroupState{}
-	mi := &file_daemon_proto_msgTypes[20]
+	mi := &file_daemon_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1962,7 +2481,7 @@ func (x *NSGroupState) String() string {
 func (*NSGroupState) ProtoMessage() {}
 
 func (x *NSGroupState) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[20]
+	mi := &file_daemon_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1975,7 +2494,7 @@ func (x *NSGroupState) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NSGroupState.ProtoReflect.Descriptor instead.
 func (*NSGroupState) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{20}
+	return file_daemon_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *NSGroupState) GetServers() []string {
@@ -2020,7 +2539,7 @@ type SSHSessionInfo struct {
 
 func (x *SSHSessionInfo) Reset() {
 	*x = SSHSessionInfo{}
-	mi := &file_daemon_proto_msgTypes[21]
+	mi := &file_daemon_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2032,7 +2551,7 @@ func (x *SSHSessionInfo) String() string {
 func (*SSHSessionInfo) ProtoMessage() {}
 
 func (x *SSHSessionInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[21]
+	mi := &file_daemon_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2045,7 +2564,7 @@ func (x *SSHSessionInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SSHSessionInfo.ProtoReflect.Descriptor instead.
 func (*SSHSessionInfo) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{21}
+	return file_daemon_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *SSHSessionInfo) GetUsername() string {
@@ -2094,7 +2613,7 @@ type SSHServerState struct {
 
 func (x *SSHServerState) Reset() {
 	*x = SSHServerState{}
-	mi := &file_daemon_proto_msgTypes[22]
+	mi := &file_daemon_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2106,7 +2625,7 @@ func (x *SSHServerState) String() string {
 func (*SSHServerState) ProtoMessage() {}
 
 func (x *SSHServerState) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[22]
+	mi := &file_daemon_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2119,7 +2638,7 @@ func (x *SSHServerState) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SSHServerState.ProtoReflect.Descriptor instead.
 func (*SSHServerState) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{22}
+	return file_daemon_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *SSHServerState) GetEnabled() bool {
@@ -2149,13 +2668,23 @@ type FullStatus struct {
 	Events                  []*SystemEvent         `protobuf:"bytes,7,rep,name=events,proto3" json:"events,omitempty"`
 	LazyConnectionEnabled   bool                   `protobuf:"varint,9,opt,name=lazyConnectionEnabled,proto3" json:"lazyConnectionEnabled,omitempty"`
 	SshServerState          *SSHServerState        `protobuf:"bytes,10,opt,name=sshServerState,proto3" json:"sshServerState,omitempty"`
-	unknownFields           protoimpl.UnknownFields
-	sizeCache               protoimpl.SizeCache
+	MaintenanceEnabled      bool                   `protobuf:"varint,11,opt,name=maintenanceEnabled,proto3" json:"maintenanceEnabled,omitempty"`
+	// number of peers matching the peer list options before pagination
+	TotalPeers      int32                 `protobuf:"varint,12,opt,name=totalPeers,proto3" json:"totalPeers,omitempty"`
+	FirewallSets    []*FirewallSetState   `protobuf:"bytes,13,rep,name=firewallSets,proto3" json:"firewallSets,omitempty"`
+	FirewallBackend *FirewallBackendState `protobuf:"bytes,14,opt,name=firewallBackend,proto3" json:"firewallBackend,omitempty"`
+	FlowState       *FlowState            `protobuf:"bytes,15,opt,name=flowState,proto3" json:"flowState,omitempty"`
+	// netstackFallback is why the client fell back to netstack mode, empty if it did not
+	NetstackFallback string             `protobuf:"bytes,16,opt,name=netstackFallback,proto3" json:"netstackFallback,omitempty"`
+	DnsListener      *DNSListenerState  `protobuf:"bytes,17,opt,name=dnsListener,proto3" json:"dnsListener,omitempty"`
+	PeerResources    *PeerResourceState `protobuf:"bytes,18,opt,name=peerResources,proto3" json:"peerResources,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *FullStatus) Reset() {
 	*x = FullStatus{}
-	mi := &file_daemon_proto_msgTypes[23]
+	mi := &file_daemon_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2167,7 +2696,7 @@ func (x *FullStatus) String() string {
 func (*FullStatus) ProtoMessage() {}
 
 func (x *FullStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[23]
+	mi := &file_daemon_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2180,7 +2709,7 @@ func (x *FullStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FullStatus.ProtoReflect.Descriptor instead.
 func (*FullStatus) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{23}
+	return file_daemon_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *FullStatus) GetManagementState() *ManagementState {
@@ -2253,28 +2782,3988 @@ func (x *FullStatus) GetSshServerState() *SSHServerState {
 	return nil
 }
 
-// Networks
-type ListNetworksRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *FullStatus) GetMaintenanceEnabled() bool {
+	if x != nil {
+		return x.MaintenanceEnabled
+	}
+	return false
 }
 
-func (x *ListNetworksRequest) Reset() {
-	*x = ListNetworksRequest{}
-	mi := &file_daemon_proto_msgTypes[24]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *FullStatus) GetTotalPeers() int32 {
+	if x != nil {
+		return x.TotalPeers
+	}
+	return 0
 }
 
-func (x *ListNetworksRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *FullStatus) GetFirewallSets() []*FirewallSetState {
+	if x != nil {
+		return x.FirewallSets
+	}
+	return nil
 }
 
-func (*ListNetworksRequest) ProtoMessage() {}
-
-func (x *ListNetworksRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[24]
+func (x *FullStatus) GetFirewallBackend() *FirewallBackendState {
+	if x != nil {
+		return x.FirewallBackend
+	}
+	return nil
+}
+
+func (x *FullStatus) GetFlowState() *FlowState {
+	if x != nil {
+		return x.FlowState
+	}
+	return nil
+}
+
+func (x *FullStatus) GetNetstackFallback() string {
+	if x != nil {
+		return x.NetstackFallback
+	}
+	return ""
+}
+
+func (x *FullStatus) GetDnsListener() *DNSListenerState {
+	if x != nil {
+		return x.DnsListener
+	}
+	return nil
+}
+
+func (x *FullStatus) GetPeerResources() *PeerResourceState {
+	if x != nil {
+		return x.PeerResources
+	}
+	return nil
+}
+
+// PeerResourceState is the resource usage of the peer connections with the limits, a zero limit is unlimited
+type PeerResourceState struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Goroutines     int64                  `protobuf:"varint,1,opt,name=goroutines,proto3" json:"goroutines,omitempty"`
+	IceAgents      int64                  `protobuf:"varint,2,opt,name=iceAgents,proto3" json:"iceAgents,omitempty"`
+	BufferBytes    int64                  `protobuf:"varint,3,opt,name=bufferBytes,proto3" json:"bufferBytes,omitempty"`
+	MaxGoroutines  int64                  `protobuf:"varint,4,opt,name=maxGoroutines,proto3" json:"maxGoroutines,omitempty"`
+	MaxIceAgents   int64                  `protobuf:"varint,5,opt,name=maxIceAgents,proto3" json:"maxIceAgents,omitempty"`
+	MaxBufferBytes int64                  `protobuf:"varint,6,opt,name=maxBufferBytes,proto3" json:"maxBufferBytes,omitempty"`
+	// peers is the number of open peer connections
+	Peers int32 `protobuf:"varint,7,opt,name=peers,proto3" json:"peers,omitempty"`
+	// deniedActivations is the number of lazy connection activations denied because of the limits
+	DeniedActivations int64 `protobuf:"varint,8,opt,name=deniedActivations,proto3" json:"deniedActivations,omitempty"`
+	// deniedIceAgents is the number of ICE attempts skipped because of the limits
+	DeniedIceAgents int64 `protobuf:"varint,9,opt,name=deniedIceAgents,proto3" json:"deniedIceAgents,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PeerResourceState) Reset() {
+	*x = PeerResourceState{}
+	mi := &file_daemon_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerResourceState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerResourceState) ProtoMessage() {}
+
+func (x *PeerResourceState) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerResourceState.ProtoReflect.Descriptor instead.
+func (*PeerResourceState) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *PeerResourceState) GetGoroutines() int64 {
+	if x != nil {
+		return x.Goroutines
+	}
+	return 0
+}
+
+func (x *PeerResourceState) GetIceAgents() int64 {
+	if x != nil {
+		return x.IceAgents
+	}
+	return 0
+}
+
+func (x *PeerResourceState) GetBufferBytes() int64 {
+	if x != nil {
+		return x.BufferBytes
+	}
+	return 0
+}
+
+func (x *PeerResourceState) GetMaxGoroutines() int64 {
+	if x != nil {
+		return x.MaxGoroutines
+	}
+	return 0
+}
+
+func (x *PeerResourceState) GetMaxIceAgents() int64 {
+	if x != nil {
+		return x.MaxIceAgents
+	}
+	return 0
+}
+
+func (x *PeerResourceState) GetMaxBufferBytes() int64 {
+	if x != nil {
+		return x.MaxBufferBytes
+	}
+	return 0
+}
+
+func (x *PeerResourceState) GetPeers() int32 {
+	if x != nil {
+		return x.Peers
+	}
+	return 0
+}
+
+func (x *PeerResourceState) GetDeniedActivations() int64 {
+	if x != nil {
+		return x.DeniedActivations
+	}
+	return 0
+}
+
+func (x *PeerResourceState) GetDeniedIceAgents() int64 {
+	if x != nil {
+		return x.DeniedIceAgents
+	}
+	return 0
+}
+
+// DNSListenerState are the addresses the local DNS resolver listens on
+type DNSListenerState struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Addresses []string               `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	// fallbacks describe the configured addresses that couldn't be bound
+	Fallbacks     []string `protobuf:"bytes,2,rep,name=fallbacks,proto3" json:"fallbacks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DNSListenerState) Reset() {
+	*x = DNSListenerState{}
+	mi := &file_daemon_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DNSListenerState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DNSListenerState) ProtoMessage() {}
+
+func (x *DNSListenerState) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DNSListenerState.ProtoReflect.Descriptor instead.
+func (*DNSListenerState) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *DNSListenerState) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *DNSListenerState) GetFallbacks() []string {
+	if x != nil {
+		return x.Fallbacks
+	}
+	return nil
+}
+
+// FirewallBackendState tells which firewall backend the client uses and why it was chosen
+type FirewallBackendState struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Backend string                 `protobuf:"bytes,1,opt,name=backend,proto3" json:"backend,omitempty"`
+	// native is the kernel firewall the userspace firewall uses for the routed traffic
+	Native        string `protobuf:"bytes,2,opt,name=native,proto3" json:"native,omitempty"`
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FirewallBackendState) Reset() {
+	*x = FirewallBackendState{}
+	mi := &file_daemon_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FirewallBackendState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FirewallBackendState) ProtoMessage() {}
+
+func (x *FirewallBackendState) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FirewallBackendState.ProtoReflect.Descriptor instead.
+func (*FirewallBackendState) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *FirewallBackendState) GetBackend() string {
+	if x != nil {
+		return x.Backend
+	}
+	return ""
+}
+
+func (x *FirewallBackendState) GetNative() string {
+	if x != nil {
+		return x.Native
+	}
+	return ""
+}
+
+func (x *FirewallBackendState) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// FirewallSetState is the number of peer addresses or route networks a firewall set holds
+type FirewallSetState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Elements      int32                  `protobuf:"varint,2,opt,name=elements,proto3" json:"elements,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FirewallSetState) Reset() {
+	*x = FirewallSetState{}
+	mi := &file_daemon_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FirewallSetState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FirewallSetState) ProtoMessage() {}
+
+func (x *FirewallSetState) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FirewallSetState.ProtoReflect.Descriptor instead.
+func (*FirewallSetState) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *FirewallSetState) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FirewallSetState) GetElements() int32 {
+	if x != nil {
+		return x.Elements
+	}
+	return 0
+}
+
+// Networks
+type ListNetworksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNetworksRequest) Reset() {
+	*x = ListNetworksRequest{}
+	mi := &file_daemon_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNetworksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNetworksRequest) ProtoMessage() {}
+
+func (x *ListNetworksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNetworksRequest.ProtoReflect.Descriptor instead.
+func (*ListNetworksRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{31}
+}
+
+type ListNetworksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Routes        []*Network             `protobuf:"bytes,1,rep,name=routes,proto3" json:"routes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNetworksResponse) Reset() {
+	*x = ListNetworksResponse{}
+	mi := &file_daemon_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNetworksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNetworksResponse) ProtoMessage() {}
+
+func (x *ListNetworksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNetworksResponse.ProtoReflect.Descriptor instead.
+func (*ListNetworksResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ListNetworksResponse) GetRoutes() []*Network {
+	if x != nil {
+		return x.Routes
+	}
+	return nil
+}
+
+type SelectNetworksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NetworkIDs    []string               `protobuf:"bytes,1,rep,name=networkIDs,proto3" json:"networkIDs,omitempty"`
+	Append        bool                   `protobuf:"varint,2,opt,name=append,proto3" json:"append,omitempty"`
+	All           bool                   `protobuf:"varint,3,opt,name=all,proto3" json:"all,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelectNetworksRequest) Reset() {
+	*x = SelectNetworksRequest{}
+	mi := &file_daemon_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelectNetworksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelectNetworksRequest) ProtoMessage() {}
+
+func (x *SelectNetworksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelectNetworksRequest.ProtoReflect.Descriptor instead.
+func (*SelectNetworksRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *SelectNetworksRequest) GetNetworkIDs() []string {
+	if x != nil {
+		return x.NetworkIDs
+	}
+	return nil
+}
+
+func (x *SelectNetworksRequest) GetAppend() bool {
+	if x != nil {
+		return x.Append
+	}
+	return false
+}
+
+func (x *SelectNetworksRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+type SelectNetworksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelectNetworksResponse) Reset() {
+	*x = SelectNetworksResponse{}
+	mi := &file_daemon_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelectNetworksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelectNetworksResponse) ProtoMessage() {}
+
+func (x *SelectNetworksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelectNetworksResponse.ProtoReflect.Descriptor instead.
+func (*SelectNetworksResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{34}
+}
+
+type SetExitNodeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// peer is the public key, FQDN or IP of the routing peer to pin, empty or "auto" enables automatic selection
+	Peer          string `protobuf:"bytes,1,opt,name=peer,proto3" json:"peer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetExitNodeRequest) Reset() {
+	*x = SetExitNodeRequest{}
+	mi := &file_daemon_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetExitNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetExitNodeRequest) ProtoMessage() {}
+
+func (x *SetExitNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetExitNodeRequest.ProtoReflect.Descriptor instead.
+func (*SetExitNodeRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *SetExitNodeRequest) GetPeer() string {
+	if x != nil {
+		return x.Peer
+	}
+	return ""
+}
+
+type SetExitNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetExitNodeResponse) Reset() {
+	*x = SetExitNodeResponse{}
+	mi := &file_daemon_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetExitNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetExitNodeResponse) ProtoMessage() {}
+
+func (x *SetExitNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetExitNodeResponse.ProtoReflect.Descriptor instead.
+func (*SetExitNodeResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{36}
+}
+
+type SetMaintenanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Enabled       bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetMaintenanceRequest) Reset() {
+	*x = SetMaintenanceRequest{}
+	mi := &file_daemon_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMaintenanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMaintenanceRequest) ProtoMessage() {}
+
+func (x *SetMaintenanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMaintenanceRequest.ProtoReflect.Descriptor instead.
+func (*SetMaintenanceRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *SetMaintenanceRequest) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+type SetMaintenanceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetMaintenanceResponse) Reset() {
+	*x = SetMaintenanceResponse{}
+	mi := &file_daemon_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMaintenanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMaintenanceResponse) ProtoMessage() {}
+
+func (x *SetMaintenanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMaintenanceResponse.ProtoReflect.Descriptor instead.
+func (*SetMaintenanceResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{38}
+}
+
+type GetExitNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetExitNodeRequest) Reset() {
+	*x = GetExitNodeRequest{}
+	mi := &file_daemon_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExitNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExitNodeRequest) ProtoMessage() {}
+
+func (x *GetExitNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExitNodeRequest.ProtoReflect.Descriptor instead.
+func (*GetExitNodeRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{39}
+}
+
+type GetExitNodeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// pinnedPeer is the public key of the pinned exit node, empty in auto mode
+	PinnedPeer     string `protobuf:"bytes,1,opt,name=pinnedPeer,proto3" json:"pinnedPeer,omitempty"`
+	PinnedPeerFqdn string `protobuf:"bytes,2,opt,name=pinnedPeerFqdn,proto3" json:"pinnedPeerFqdn,omitempty"`
+	// activePeer is the public key of the peer currently serving the exit node route
+	ActivePeer     string `protobuf:"bytes,3,opt,name=activePeer,proto3" json:"activePeer,omitempty"`
+	ActivePeerFqdn string `protobuf:"bytes,4,opt,name=activePeerFqdn,proto3" json:"activePeerFqdn,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetExitNodeResponse) Reset() {
+	*x = GetExitNodeResponse{}
+	mi := &file_daemon_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExitNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExitNodeResponse) ProtoMessage() {}
+
+func (x *GetExitNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExitNodeResponse.ProtoReflect.Descriptor instead.
+func (*GetExitNodeResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *GetExitNodeResponse) GetPinnedPeer() string {
+	if x != nil {
+		return x.PinnedPeer
+	}
+	return ""
+}
+
+func (x *GetExitNodeResponse) GetPinnedPeerFqdn() string {
+	if x != nil {
+		return x.PinnedPeerFqdn
+	}
+	return ""
+}
+
+func (x *GetExitNodeResponse) GetActivePeer() string {
+	if x != nil {
+		return x.ActivePeer
+	}
+	return ""
+}
+
+func (x *GetExitNodeResponse) GetActivePeerFqdn() string {
+	if x != nil {
+		return x.ActivePeerFqdn
+	}
+	return ""
+}
+
+type IPList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ips           []string               `protobuf:"bytes,1,rep,name=ips,proto3" json:"ips,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IPList) Reset() {
+	*x = IPList{}
+	mi := &file_daemon_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IPList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IPList) ProtoMessage() {}
+
+func (x *IPList) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IPList.ProtoReflect.Descriptor instead.
+func (*IPList) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *IPList) GetIps() []string {
+	if x != nil {
+		return x.Ips
+	}
+	return nil
+}
+
+type Network struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Range         string                 `protobuf:"bytes,2,opt,name=range,proto3" json:"range,omitempty"`
+	Selected      bool                   `protobuf:"varint,3,opt,name=selected,proto3" json:"selected,omitempty"`
+	Domains       []string               `protobuf:"bytes,4,rep,name=domains,proto3" json:"domains,omitempty"`
+	ResolvedIPs   map[string]*IPList     `protobuf:"bytes,5,rep,name=resolvedIPs,proto3" json:"resolvedIPs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Network) Reset() {
+	*x = Network{}
+	mi := &file_daemon_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Network) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Network) ProtoMessage() {}
+
+func (x *Network) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Network.ProtoReflect.Descriptor instead.
+func (*Network) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *Network) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *Network) GetRange() string {
+	if x != nil {
+		return x.Range
+	}
+	return ""
+}
+
+func (x *Network) GetSelected() bool {
+	if x != nil {
+		return x.Selected
+	}
+	return false
+}
+
+func (x *Network) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *Network) GetResolvedIPs() map[string]*IPList {
+	if x != nil {
+		return x.ResolvedIPs
+	}
+	return nil
+}
+
+// ForwardingRules
+type PortInfo struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to PortSelection:
+	//
+	//	*PortInfo_Port
+	//	*PortInfo_Range_
+	PortSelection isPortInfo_PortSelection `protobuf_oneof:"portSelection"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PortInfo) Reset() {
+	*x = PortInfo{}
+	mi := &file_daemon_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PortInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PortInfo) ProtoMessage() {}
+
+func (x *PortInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PortInfo.ProtoReflect.Descriptor instead.
+func (*PortInfo) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *PortInfo) GetPortSelection() isPortInfo_PortSelection {
+	if x != nil {
+		return x.PortSelection
+	}
+	return nil
+}
+
+func (x *PortInfo) GetPort() uint32 {
+	if x != nil {
+		if x, ok := x.PortSelection.(*PortInfo_Port); ok {
+			return x.Port
+		}
+	}
+	return 0
+}
+
+func (x *PortInfo) GetRange() *PortInfo_Range {
+	if x != nil {
+		if x, ok := x.PortSelection.(*PortInfo_Range_); ok {
+			return x.Range
+		}
+	}
+	return nil
+}
+
+type isPortInfo_PortSelection interface {
+	isPortInfo_PortSelection()
+}
+
+type PortInfo_Port struct {
+	Port uint32 `protobuf:"varint,1,opt,name=port,proto3,oneof"`
+}
+
+type PortInfo_Range_ struct {
+	Range *PortInfo_Range `protobuf:"bytes,2,opt,name=range,proto3,oneof"`
+}
+
+func (*PortInfo_Port) isPortInfo_PortSelection() {}
+
+func (*PortInfo_Range_) isPortInfo_PortSelection() {}
+
+type ForwardingRule struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Protocol           string                 `protobuf:"bytes,1,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	DestinationPort    *PortInfo              `protobuf:"bytes,2,opt,name=destinationPort,proto3" json:"destinationPort,omitempty"`
+	TranslatedAddress  string                 `protobuf:"bytes,3,opt,name=translatedAddress,proto3" json:"translatedAddress,omitempty"`
+	TranslatedHostname string                 `protobuf:"bytes,4,opt,name=translatedHostname,proto3" json:"translatedHostname,omitempty"`
+	TranslatedPort     *PortInfo              `protobuf:"bytes,5,opt,name=translatedPort,proto3" json:"translatedPort,omitempty"`
+	// health of the translated address: up, down or unknown. Empty if the rule has no health check.
+	Health          string                 `protobuf:"bytes,6,opt,name=health,proto3" json:"health,omitempty"`
+	HealthError     string                 `protobuf:"bytes,7,opt,name=healthError,proto3" json:"healthError,omitempty"`
+	LastHealthCheck *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=lastHealthCheck,proto3" json:"lastHealthCheck,omitempty"`
+	// draining rules are removed but kept in place for the established connections until drainDeadline
+	Draining      bool                   `protobuf:"varint,9,opt,name=draining,proto3" json:"draining,omitempty"`
+	DrainDeadline *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=drainDeadline,proto3" json:"drainDeadline,omitempty"`
+	// translatedAddresses are the additional translated addresses, the connections are balanced round-robin across
+	// translatedAddress and them
+	TranslatedAddresses []string `protobuf:"bytes,11,rep,name=translatedAddresses,proto3" json:"translatedAddresses,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ForwardingRule) Reset() {
+	*x = ForwardingRule{}
+	mi := &file_daemon_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForwardingRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardingRule) ProtoMessage() {}
+
+func (x *ForwardingRule) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardingRule.ProtoReflect.Descriptor instead.
+func (*ForwardingRule) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ForwardingRule) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *ForwardingRule) GetDestinationPort() *PortInfo {
+	if x != nil {
+		return x.DestinationPort
+	}
+	return nil
+}
+
+func (x *ForwardingRule) GetTranslatedAddress() string {
+	if x != nil {
+		return x.TranslatedAddress
+	}
+	return ""
+}
+
+func (x *ForwardingRule) GetTranslatedHostname() string {
+	if x != nil {
+		return x.TranslatedHostname
+	}
+	return ""
+}
+
+func (x *ForwardingRule) GetTranslatedPort() *PortInfo {
+	if x != nil {
+		return x.TranslatedPort
+	}
+	return nil
+}
+
+func (x *ForwardingRule) GetHealth() string {
+	if x != nil {
+		return x.Health
+	}
+	return ""
+}
+
+func (x *ForwardingRule) GetHealthError() string {
+	if x != nil {
+		return x.HealthError
+	}
+	return ""
+}
+
+func (x *ForwardingRule) GetLastHealthCheck() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastHealthCheck
+	}
+	return nil
+}
+
+func (x *ForwardingRule) GetDraining() bool {
+	if x != nil {
+		return x.Draining
+	}
+	return false
+}
+
+func (x *ForwardingRule) GetDrainDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DrainDeadline
+	}
+	return nil
+}
+
+func (x *ForwardingRule) GetTranslatedAddresses() []string {
+	if x != nil {
+		return x.TranslatedAddresses
+	}
+	return nil
+}
+
+type ForwardingRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*ForwardingRule      `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForwardingRulesResponse) Reset() {
+	*x = ForwardingRulesResponse{}
+	mi := &file_daemon_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForwardingRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardingRulesResponse) ProtoMessage() {}
+
+func (x *ForwardingRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardingRulesResponse.ProtoReflect.Descriptor instead.
+func (*ForwardingRulesResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ForwardingRulesResponse) GetRules() []*ForwardingRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// DebugBundler
+type DebugBundleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Anonymize     bool                   `protobuf:"varint,1,opt,name=anonymize,proto3" json:"anonymize,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	SystemInfo    bool                   `protobuf:"varint,3,opt,name=systemInfo,proto3" json:"systemInfo,omitempty"`
+	UploadURL     string                 `protobuf:"bytes,4,opt,name=uploadURL,proto3" json:"uploadURL,omitempty"`
+	LogFileCount  uint32                 `protobuf:"varint,5,opt,name=logFileCount,proto3" json:"logFileCount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DebugBundleRequest) Reset() {
+	*x = DebugBundleRequest{}
+	mi := &file_daemon_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DebugBundleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebugBundleRequest) ProtoMessage() {}
+
+func (x *DebugBundleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebugBundleRequest.ProtoReflect.Descriptor instead.
+func (*DebugBundleRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *DebugBundleRequest) GetAnonymize() bool {
+	if x != nil {
+		return x.Anonymize
+	}
+	return false
+}
+
+func (x *DebugBundleRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *DebugBundleRequest) GetSystemInfo() bool {
+	if x != nil {
+		return x.SystemInfo
+	}
+	return false
+}
+
+func (x *DebugBundleRequest) GetUploadURL() string {
+	if x != nil {
+		return x.UploadURL
+	}
+	return ""
+}
+
+func (x *DebugBundleRequest) GetLogFileCount() uint32 {
+	if x != nil {
+		return x.LogFileCount
+	}
+	return 0
+}
+
+type DebugBundleResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Path                string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	UploadedKey         string                 `protobuf:"bytes,2,opt,name=uploadedKey,proto3" json:"uploadedKey,omitempty"`
+	UploadFailureReason string                 `protobuf:"bytes,3,opt,name=uploadFailureReason,proto3" json:"uploadFailureReason,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *DebugBundleResponse) Reset() {
+	*x = DebugBundleResponse{}
+	mi := &file_daemon_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DebugBundleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebugBundleResponse) ProtoMessage() {}
+
+func (x *DebugBundleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebugBundleResponse.ProtoReflect.Descriptor instead.
+func (*DebugBundleResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *DebugBundleResponse) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DebugBundleResponse) GetUploadedKey() string {
+	if x != nil {
+		return x.UploadedKey
+	}
+	return ""
+}
+
+func (x *DebugBundleResponse) GetUploadFailureReason() string {
+	if x != nil {
+		return x.UploadFailureReason
+	}
+	return ""
+}
+
+type GetLogLevelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLogLevelRequest) Reset() {
+	*x = GetLogLevelRequest{}
+	mi := &file_daemon_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLogLevelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLogLevelRequest) ProtoMessage() {}
+
+func (x *GetLogLevelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLogLevelRequest.ProtoReflect.Descriptor instead.
+func (*GetLogLevelRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{48}
+}
+
+type GetLogLevelResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Level LogLevel               `protobuf:"varint,1,opt,name=level,proto3,enum=daemon.LogLevel" json:"level,omitempty"`
+	// subsystemLevels are the subsystem levels overriding the global level
+	SubsystemLevels map[string]LogLevel `protobuf:"bytes,2,rep,name=subsystemLevels,proto3" json:"subsystemLevels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value,enum=daemon.LogLevel"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetLogLevelResponse) Reset() {
+	*x = GetLogLevelResponse{}
+	mi := &file_daemon_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLogLevelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLogLevelResponse) ProtoMessage() {}
+
+func (x *GetLogLevelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLogLevelResponse.ProtoReflect.Descriptor instead.
+func (*GetLogLevelResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *GetLogLevelResponse) GetLevel() LogLevel {
+	if x != nil {
+		return x.Level
+	}
+	return LogLevel_UNKNOWN
+}
+
+func (x *GetLogLevelResponse) GetSubsystemLevels() map[string]LogLevel {
+	if x != nil {
+		return x.SubsystemLevels
+	}
+	return nil
+}
+
+type SetLogLevelRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Level LogLevel               `protobuf:"varint,1,opt,name=level,proto3,enum=daemon.LogLevel" json:"level,omitempty"`
+	// subsystem sets the level of a single subsystem (dns, routes, firewall, ice, relay) instead of the global level
+	Subsystem string `protobuf:"bytes,2,opt,name=subsystem,proto3" json:"subsystem,omitempty"`
+	// resetSubsystem makes the subsystem follow the global level again
+	ResetSubsystem bool `protobuf:"varint,3,opt,name=resetSubsystem,proto3" json:"resetSubsystem,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SetLogLevelRequest) Reset() {
+	*x = SetLogLevelRequest{}
+	mi := &file_daemon_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLogLevelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLogLevelRequest) ProtoMessage() {}
+
+func (x *SetLogLevelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLogLevelRequest.ProtoReflect.Descriptor instead.
+func (*SetLogLevelRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *SetLogLevelRequest) GetLevel() LogLevel {
+	if x != nil {
+		return x.Level
+	}
+	return LogLevel_UNKNOWN
+}
+
+func (x *SetLogLevelRequest) GetSubsystem() string {
+	if x != nil {
+		return x.Subsystem
+	}
+	return ""
+}
+
+func (x *SetLogLevelRequest) GetResetSubsystem() bool {
+	if x != nil {
+		return x.ResetSubsystem
+	}
+	return false
+}
+
+type SetLogLevelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLogLevelResponse) Reset() {
+	*x = SetLogLevelResponse{}
+	mi := &file_daemon_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLogLevelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLogLevelResponse) ProtoMessage() {}
+
+func (x *SetLogLevelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLogLevelResponse.ProtoReflect.Descriptor instead.
+func (*SetLogLevelResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{51}
+}
+
+// State represents a daemon state entry
+type State struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *State) Reset() {
+	*x = State{}
+	mi := &file_daemon_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *State) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*State) ProtoMessage() {}
+
+func (x *State) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use State.ProtoReflect.Descriptor instead.
+func (*State) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *State) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// ListStatesRequest is empty as it requires no parameters
+type ListStatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStatesRequest) Reset() {
+	*x = ListStatesRequest{}
+	mi := &file_daemon_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStatesRequest) ProtoMessage() {}
+
+func (x *ListStatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStatesRequest.ProtoReflect.Descriptor instead.
+func (*ListStatesRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{53}
+}
+
+// ListStatesResponse contains a list of states
+type ListStatesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	States        []*State               `protobuf:"bytes,1,rep,name=states,proto3" json:"states,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStatesResponse) Reset() {
+	*x = ListStatesResponse{}
+	mi := &file_daemon_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStatesResponse) ProtoMessage() {}
+
+func (x *ListStatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStatesResponse.ProtoReflect.Descriptor instead.
+func (*ListStatesResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ListStatesResponse) GetStates() []*State {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+// CleanStateRequest for cleaning states
+type CleanStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StateName     string                 `protobuf:"bytes,1,opt,name=state_name,json=stateName,proto3" json:"state_name,omitempty"`
+	All           bool                   `protobuf:"varint,2,opt,name=all,proto3" json:"all,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CleanStateRequest) Reset() {
+	*x = CleanStateRequest{}
+	mi := &file_daemon_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CleanStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CleanStateRequest) ProtoMessage() {}
+
+func (x *CleanStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CleanStateRequest.ProtoReflect.Descriptor instead.
+func (*CleanStateRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *CleanStateRequest) GetStateName() string {
+	if x != nil {
+		return x.StateName
+	}
+	return ""
+}
+
+func (x *CleanStateRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+// CleanStateResponse contains the result of the clean operation
+type CleanStateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CleanedStates int32                  `protobuf:"varint,1,opt,name=cleaned_states,json=cleanedStates,proto3" json:"cleaned_states,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CleanStateResponse) Reset() {
+	*x = CleanStateResponse{}
+	mi := &file_daemon_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CleanStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CleanStateResponse) ProtoMessage() {}
+
+func (x *CleanStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CleanStateResponse.ProtoReflect.Descriptor instead.
+func (*CleanStateResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *CleanStateResponse) GetCleanedStates() int32 {
+	if x != nil {
+		return x.CleanedStates
+	}
+	return 0
+}
+
+// DeleteStateRequest for deleting states
+type DeleteStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StateName     string                 `protobuf:"bytes,1,opt,name=state_name,json=stateName,proto3" json:"state_name,omitempty"`
+	All           bool                   `protobuf:"varint,2,opt,name=all,proto3" json:"all,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteStateRequest) Reset() {
+	*x = DeleteStateRequest{}
+	mi := &file_daemon_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteStateRequest) ProtoMessage() {}
+
+func (x *DeleteStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteStateRequest.ProtoReflect.Descriptor instead.
+func (*DeleteStateRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *DeleteStateRequest) GetStateName() string {
+	if x != nil {
+		return x.StateName
+	}
+	return ""
+}
+
+func (x *DeleteStateRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+// DeleteStateResponse contains the result of the delete operation
+type DeleteStateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeletedStates int32                  `protobuf:"varint,1,opt,name=deleted_states,json=deletedStates,proto3" json:"deleted_states,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteStateResponse) Reset() {
+	*x = DeleteStateResponse{}
+	mi := &file_daemon_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteStateResponse) ProtoMessage() {}
+
+func (x *DeleteStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteStateResponse.ProtoReflect.Descriptor instead.
+func (*DeleteStateResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *DeleteStateResponse) GetDeletedStates() int32 {
+	if x != nil {
+		return x.DeletedStates
+	}
+	return 0
+}
+
+type SetSyncResponsePersistenceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Enabled       bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetSyncResponsePersistenceRequest) Reset() {
+	*x = SetSyncResponsePersistenceRequest{}
+	mi := &file_daemon_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetSyncResponsePersistenceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetSyncResponsePersistenceRequest) ProtoMessage() {}
+
+func (x *SetSyncResponsePersistenceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetSyncResponsePersistenceRequest.ProtoReflect.Descriptor instead.
+func (*SetSyncResponsePersistenceRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *SetSyncResponsePersistenceRequest) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+type SetSyncResponsePersistenceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetSyncResponsePersistenceResponse) Reset() {
+	*x = SetSyncResponsePersistenceResponse{}
+	mi := &file_daemon_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetSyncResponsePersistenceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetSyncResponsePersistenceResponse) ProtoMessage() {}
+
+func (x *SetSyncResponsePersistenceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetSyncResponsePersistenceResponse.ProtoReflect.Descriptor instead.
+func (*SetSyncResponsePersistenceResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{60}
+}
+
+type GetNetworkMapRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// peer filters remote peers and peer firewall rules by public key, FQDN or IP
+	Peer string `protobuf:"bytes,1,opt,name=peer,proto3" json:"peer,omitempty"`
+	// route filters routes and route firewall rules by ID, network ID, network or domain
+	Route         string `protobuf:"bytes,2,opt,name=route,proto3" json:"route,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNetworkMapRequest) Reset() {
+	*x = GetNetworkMapRequest{}
+	mi := &file_daemon_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNetworkMapRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNetworkMapRequest) ProtoMessage() {}
+
+func (x *GetNetworkMapRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNetworkMapRequest.ProtoReflect.Descriptor instead.
+func (*GetNetworkMapRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *GetNetworkMapRequest) GetPeer() string {
+	if x != nil {
+		return x.Peer
+	}
+	return ""
+}
+
+func (x *GetNetworkMapRequest) GetRoute() string {
+	if x != nil {
+		return x.Route
+	}
+	return ""
+}
+
+type GetNetworkMapResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Serial uint64                 `protobuf:"varint,1,opt,name=serial,proto3" json:"serial,omitempty"`
+	// networkMap is the filtered network map encoded as JSON
+	NetworkMap string `protobuf:"bytes,2,opt,name=networkMap,proto3" json:"networkMap,omitempty"`
+	// routes are the decoded routes encoded as JSON
+	Routes string `protobuf:"bytes,3,opt,name=routes,proto3" json:"routes,omitempty"`
+	// dnsConfig is the decoded DNS configuration encoded as JSON
+	DnsConfig string `protobuf:"bytes,4,opt,name=dnsConfig,proto3" json:"dnsConfig,omitempty"`
+	// firewallRules are the peer and route firewall rules encoded as JSON
+	FirewallRules string `protobuf:"bytes,5,opt,name=firewallRules,proto3" json:"firewallRules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNetworkMapResponse) Reset() {
+	*x = GetNetworkMapResponse{}
+	mi := &file_daemon_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNetworkMapResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNetworkMapResponse) ProtoMessage() {}
+
+func (x *GetNetworkMapResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNetworkMapResponse.ProtoReflect.Descriptor instead.
+func (*GetNetworkMapResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *GetNetworkMapResponse) GetSerial() uint64 {
+	if x != nil {
+		return x.Serial
+	}
+	return 0
+}
+
+func (x *GetNetworkMapResponse) GetNetworkMap() string {
+	if x != nil {
+		return x.NetworkMap
+	}
+	return ""
+}
+
+func (x *GetNetworkMapResponse) GetRoutes() string {
+	if x != nil {
+		return x.Routes
+	}
+	return ""
+}
+
+func (x *GetNetworkMapResponse) GetDnsConfig() string {
+	if x != nil {
+		return x.DnsConfig
+	}
+	return ""
+}
+
+func (x *GetNetworkMapResponse) GetFirewallRules() string {
+	if x != nil {
+		return x.FirewallRules
+	}
+	return ""
+}
+
+type PortForward struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// listenAddress is the address the local listener is bound to, it identifies the forward
+	ListenAddress string `protobuf:"bytes,1,opt,name=listenAddress,proto3" json:"listenAddress,omitempty"`
+	// targetAddress is the peer address connections are forwarded to
+	TargetAddress     string `protobuf:"bytes,2,opt,name=targetAddress,proto3" json:"targetAddress,omitempty"`
+	ActiveConnections uint64 `protobuf:"varint,3,opt,name=activeConnections,proto3" json:"activeConnections,omitempty"`
+	TotalConnections  uint64 `protobuf:"varint,4,opt,name=totalConnections,proto3" json:"totalConnections,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *PortForward) Reset() {
+	*x = PortForward{}
+	mi := &file_daemon_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PortForward) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PortForward) ProtoMessage() {}
+
+func (x *PortForward) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PortForward.ProtoReflect.Descriptor instead.
+func (*PortForward) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *PortForward) GetListenAddress() string {
+	if x != nil {
+		return x.ListenAddress
+	}
+	return ""
+}
+
+func (x *PortForward) GetTargetAddress() string {
+	if x != nil {
+		return x.TargetAddress
+	}
+	return ""
+}
+
+func (x *PortForward) GetActiveConnections() uint64 {
+	if x != nil {
+		return x.ActiveConnections
+	}
+	return 0
+}
+
+func (x *PortForward) GetTotalConnections() uint64 {
+	if x != nil {
+		return x.TotalConnections
+	}
+	return 0
+}
+
+type AddPortForwardRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// listenAddress is a host:port or a port, which is bound to localhost
+	ListenAddress string `protobuf:"bytes,1,opt,name=listenAddress,proto3" json:"listenAddress,omitempty"`
+	// targetAddress is the peer IP or FQDN and port
+	TargetAddress string `protobuf:"bytes,2,opt,name=targetAddress,proto3" json:"targetAddress,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddPortForwardRequest) Reset() {
+	*x = AddPortForwardRequest{}
+	mi := &file_daemon_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddPortForwardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddPortForwardRequest) ProtoMessage() {}
+
+func (x *AddPortForwardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddPortForwardRequest.ProtoReflect.Descriptor instead.
+func (*AddPortForwardRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *AddPortForwardRequest) GetListenAddress() string {
+	if x != nil {
+		return x.ListenAddress
+	}
+	return ""
+}
+
+func (x *AddPortForwardRequest) GetTargetAddress() string {
+	if x != nil {
+		return x.TargetAddress
+	}
+	return ""
+}
+
+type AddPortForwardResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Forward       *PortForward           `protobuf:"bytes,1,opt,name=forward,proto3" json:"forward,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddPortForwardResponse) Reset() {
+	*x = AddPortForwardResponse{}
+	mi := &file_daemon_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddPortForwardResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddPortForwardResponse) ProtoMessage() {}
+
+func (x *AddPortForwardResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddPortForwardResponse.ProtoReflect.Descriptor instead.
+func (*AddPortForwardResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *AddPortForwardResponse) GetForward() *PortForward {
+	if x != nil {
+		return x.Forward
+	}
+	return nil
+}
+
+type RemovePortForwardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ListenAddress string                 `protobuf:"bytes,1,opt,name=listenAddress,proto3" json:"listenAddress,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemovePortForwardRequest) Reset() {
+	*x = RemovePortForwardRequest{}
+	mi := &file_daemon_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePortForwardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePortForwardRequest) ProtoMessage() {}
+
+func (x *RemovePortForwardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePortForwardRequest.ProtoReflect.Descriptor instead.
+func (*RemovePortForwardRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *RemovePortForwardRequest) GetListenAddress() string {
+	if x != nil {
+		return x.ListenAddress
+	}
+	return ""
+}
+
+type RemovePortForwardResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemovePortForwardResponse) Reset() {
+	*x = RemovePortForwardResponse{}
+	mi := &file_daemon_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePortForwardResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePortForwardResponse) ProtoMessage() {}
+
+func (x *RemovePortForwardResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePortForwardResponse.ProtoReflect.Descriptor instead.
+func (*RemovePortForwardResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{67}
+}
+
+type ListPortForwardsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPortForwardsRequest) Reset() {
+	*x = ListPortForwardsRequest{}
+	mi := &file_daemon_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPortForwardsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPortForwardsRequest) ProtoMessage() {}
+
+func (x *ListPortForwardsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPortForwardsRequest.ProtoReflect.Descriptor instead.
+func (*ListPortForwardsRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{68}
+}
+
+type ListPortForwardsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Forwards      []*PortForward         `protobuf:"bytes,1,rep,name=forwards,proto3" json:"forwards,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPortForwardsResponse) Reset() {
+	*x = ListPortForwardsResponse{}
+	mi := &file_daemon_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPortForwardsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPortForwardsResponse) ProtoMessage() {}
+
+func (x *ListPortForwardsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPortForwardsResponse.ProtoReflect.Descriptor instead.
+func (*ListPortForwardsResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *ListPortForwardsResponse) GetForwards() []*PortForward {
+	if x != nil {
+		return x.Forwards
+	}
+	return nil
+}
+
+type DiagnoseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiagnoseRequest) Reset() {
+	*x = DiagnoseRequest{}
+	mi := &file_daemon_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiagnoseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiagnoseRequest) ProtoMessage() {}
+
+func (x *DiagnoseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiagnoseRequest.ProtoReflect.Descriptor instead.
+func (*DiagnoseRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{70}
+}
+
+type DiagnosedIssue struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// severity is warning or error
+	Severity      string   `protobuf:"bytes,2,opt,name=severity,proto3" json:"severity,omitempty"`
+	Title         string   `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Details       string   `protobuf:"bytes,4,opt,name=details,proto3" json:"details,omitempty"`
+	Remediation   []string `protobuf:"bytes,5,rep,name=remediation,proto3" json:"remediation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiagnosedIssue) Reset() {
+	*x = DiagnosedIssue{}
+	mi := &file_daemon_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiagnosedIssue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiagnosedIssue) ProtoMessage() {}
+
+func (x *DiagnosedIssue) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiagnosedIssue.ProtoReflect.Descriptor instead.
+func (*DiagnosedIssue) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *DiagnosedIssue) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DiagnosedIssue) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *DiagnosedIssue) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *DiagnosedIssue) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+func (x *DiagnosedIssue) GetRemediation() []string {
+	if x != nil {
+		return x.Remediation
+	}
+	return nil
+}
+
+type DiagnoseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Issues        []*DiagnosedIssue      `protobuf:"bytes,1,rep,name=issues,proto3" json:"issues,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiagnoseResponse) Reset() {
+	*x = DiagnoseResponse{}
+	mi := &file_daemon_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiagnoseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiagnoseResponse) ProtoMessage() {}
+
+func (x *DiagnoseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiagnoseResponse.ProtoReflect.Descriptor instead.
+func (*DiagnoseResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *DiagnoseResponse) GetIssues() []*DiagnosedIssue {
+	if x != nil {
+		return x.Issues
+	}
+	return nil
+}
+
+type WakeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// target is a peer FQDN, NetBird IP, public key or the name of a local Wake-on-LAN entry
+	Target string `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	// macAddress overrides the hardware address from the config and management
+	MacAddress string `protobuf:"bytes,2,opt,name=macAddress,proto3" json:"macAddress,omitempty"`
+	// via selects the routing peer that sends the magic packet
+	Via           string `protobuf:"bytes,3,opt,name=via,proto3" json:"via,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WakeRequest) Reset() {
+	*x = WakeRequest{}
+	mi := &file_daemon_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WakeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WakeRequest) ProtoMessage() {}
+
+func (x *WakeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WakeRequest.ProtoReflect.Descriptor instead.
+func (*WakeRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *WakeRequest) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *WakeRequest) GetMacAddress() string {
+	if x != nil {
+		return x.MacAddress
+	}
+	return ""
+}
+
+func (x *WakeRequest) GetVia() string {
+	if x != nil {
+		return x.Via
+	}
+	return ""
+}
+
+type WakeResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Target string                 `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	// relay is the FQDN of the routing peer that sends the magic packet
+	Relay         string `protobuf:"bytes,2,opt,name=relay,proto3" json:"relay,omitempty"`
+	MacAddress    string `protobuf:"bytes,3,opt,name=macAddress,proto3" json:"macAddress,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WakeResponse) Reset() {
+	*x = WakeResponse{}
+	mi := &file_daemon_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WakeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WakeResponse) ProtoMessage() {}
+
+func (x *WakeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WakeResponse.ProtoReflect.Descriptor instead.
+func (*WakeResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *WakeResponse) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *WakeResponse) GetRelay() string {
+	if x != nil {
+		return x.Relay
+	}
+	return ""
+}
+
+func (x *WakeResponse) GetMacAddress() string {
+	if x != nil {
+		return x.MacAddress
+	}
+	return ""
+}
+
+type GetDNSCacheStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDNSCacheStatsRequest) Reset() {
+	*x = GetDNSCacheStatsRequest{}
+	mi := &file_daemon_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDNSCacheStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDNSCacheStatsRequest) ProtoMessage() {}
+
+func (x *GetDNSCacheStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDNSCacheStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetDNSCacheStatsRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{75}
+}
+
+type GetDNSCacheStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       uint32                 `protobuf:"varint,1,opt,name=entries,proto3" json:"entries,omitempty"`
+	Hits          uint64                 `protobuf:"varint,2,opt,name=hits,proto3" json:"hits,omitempty"`
+	Misses        uint64                 `protobuf:"varint,3,opt,name=misses,proto3" json:"misses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDNSCacheStatsResponse) Reset() {
+	*x = GetDNSCacheStatsResponse{}
+	mi := &file_daemon_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDNSCacheStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDNSCacheStatsResponse) ProtoMessage() {}
+
+func (x *GetDNSCacheStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDNSCacheStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetDNSCacheStatsResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *GetDNSCacheStatsResponse) GetEntries() uint32 {
+	if x != nil {
+		return x.Entries
+	}
+	return 0
+}
+
+func (x *GetDNSCacheStatsResponse) GetHits() uint64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+func (x *GetDNSCacheStatsResponse) GetMisses() uint64 {
+	if x != nil {
+		return x.Misses
+	}
+	return 0
+}
+
+type FlushDNSCacheRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlushDNSCacheRequest) Reset() {
+	*x = FlushDNSCacheRequest{}
+	mi := &file_daemon_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlushDNSCacheRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlushDNSCacheRequest) ProtoMessage() {}
+
+func (x *FlushDNSCacheRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlushDNSCacheRequest.ProtoReflect.Descriptor instead.
+func (*FlushDNSCacheRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{77}
+}
+
+type FlushDNSCacheResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// flushed is the number of dropped answers
+	Flushed       uint32 `protobuf:"varint,1,opt,name=flushed,proto3" json:"flushed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlushDNSCacheResponse) Reset() {
+	*x = FlushDNSCacheResponse{}
+	mi := &file_daemon_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlushDNSCacheResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlushDNSCacheResponse) ProtoMessage() {}
+
+func (x *FlushDNSCacheResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlushDNSCacheResponse.ProtoReflect.Descriptor instead.
+func (*FlushDNSCacheResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *FlushDNSCacheResponse) GetFlushed() uint32 {
+	if x != nil {
+		return x.Flushed
+	}
+	return 0
+}
+
+type GetRouteRuleStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRouteRuleStatsRequest) Reset() {
+	*x = GetRouteRuleStatsRequest{}
+	mi := &file_daemon_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRouteRuleStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRouteRuleStatsRequest) ProtoMessage() {}
+
+func (x *GetRouteRuleStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRouteRuleStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetRouteRuleStatsRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{79}
+}
+
+type RouteRuleStats struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	RuleID   string                 `protobuf:"bytes,1,opt,name=ruleID,proto3" json:"ruleID,omitempty"`
+	PolicyID []byte                 `protobuf:"bytes,2,opt,name=policyID,proto3" json:"policyID,omitempty"`
+	// action is accept, drop or audit
+	Action        string `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	Packets       uint64 `protobuf:"varint,4,opt,name=packets,proto3" json:"packets,omitempty"`
+	Bytes         uint64 `protobuf:"varint,5,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RouteRuleStats) Reset() {
+	*x = RouteRuleStats{}
+	mi := &file_daemon_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RouteRuleStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RouteRuleStats) ProtoMessage() {}
+
+func (x *RouteRuleStats) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RouteRuleStats.ProtoReflect.Descriptor instead.
+func (*RouteRuleStats) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *RouteRuleStats) GetRuleID() string {
+	if x != nil {
+		return x.RuleID
+	}
+	return ""
+}
+
+func (x *RouteRuleStats) GetPolicyID() []byte {
+	if x != nil {
+		return x.PolicyID
+	}
+	return nil
+}
+
+func (x *RouteRuleStats) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *RouteRuleStats) GetPackets() uint64 {
+	if x != nil {
+		return x.Packets
+	}
+	return 0
+}
+
+func (x *RouteRuleStats) GetBytes() uint64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+type GetRouteRuleStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*RouteRuleStats      `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRouteRuleStatsResponse) Reset() {
+	*x = GetRouteRuleStatsResponse{}
+	mi := &file_daemon_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRouteRuleStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRouteRuleStatsResponse) ProtoMessage() {}
+
+func (x *GetRouteRuleStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRouteRuleStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetRouteRuleStatsResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *GetRouteRuleStatsResponse) GetRules() []*RouteRuleStats {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type GetRouteMetricsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRouteMetricsRequest) Reset() {
+	*x = GetRouteMetricsRequest{}
+	mi := &file_daemon_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRouteMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRouteMetricsRequest) ProtoMessage() {}
+
+func (x *GetRouteMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRouteMetricsRequest.ProtoReflect.Descriptor instead.
+func (*GetRouteMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{82}
+}
+
+type LatencyBucket struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// upperBound is unset for the bucket counting everything above the last bound
+	UpperBound    *durationpb.Duration `protobuf:"bytes,1,opt,name=upperBound,proto3" json:"upperBound,omitempty"`
+	Count         uint64               `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LatencyBucket) Reset() {
+	*x = LatencyBucket{}
+	mi := &file_daemon_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LatencyBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LatencyBucket) ProtoMessage() {}
+
+func (x *LatencyBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LatencyBucket.ProtoReflect.Descriptor instead.
+func (*LatencyBucket) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *LatencyBucket) GetUpperBound() *durationpb.Duration {
+	if x != nil {
+		return x.UpperBound
+	}
+	return nil
+}
+
+func (x *LatencyBucket) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type LatencyHistogram struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         uint64                 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Sum           *durationpb.Duration   `protobuf:"bytes,2,opt,name=sum,proto3" json:"sum,omitempty"`
+	Max           *durationpb.Duration   `protobuf:"bytes,3,opt,name=max,proto3" json:"max,omitempty"`
+	Buckets       []*LatencyBucket       `protobuf:"bytes,4,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LatencyHistogram) Reset() {
+	*x = LatencyHistogram{}
+	mi := &file_daemon_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LatencyHistogram) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LatencyHistogram) ProtoMessage() {}
+
+func (x *LatencyHistogram) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LatencyHistogram.ProtoReflect.Descriptor instead.
+func (*LatencyHistogram) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *LatencyHistogram) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *LatencyHistogram) GetSum() *durationpb.Duration {
+	if x != nil {
+		return x.Sum
+	}
+	return nil
+}
+
+func (x *LatencyHistogram) GetMax() *durationpb.Duration {
+	if x != nil {
+		return x.Max
+	}
+	return nil
+}
+
+func (x *LatencyHistogram) GetBuckets() []*LatencyBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+type NetworkMapRouteUpdate struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Serial           uint64                 `protobuf:"varint,1,opt,name=serial,proto3" json:"serial,omitempty"`
+	Time             *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	ClientRoutes     int32                  `protobuf:"varint,3,opt,name=clientRoutes,proto3" json:"clientRoutes,omitempty"`
+	ServerRoutes     int32                  `protobuf:"varint,4,opt,name=serverRoutes,proto3" json:"serverRoutes,omitempty"`
+	RoutesDuration   *durationpb.Duration   `protobuf:"bytes,5,opt,name=routesDuration,proto3" json:"routesDuration,omitempty"`
+	FirewallRules    int32                  `protobuf:"varint,6,opt,name=firewallRules,proto3" json:"firewallRules,omitempty"`
+	FirewallDuration *durationpb.Duration   `protobuf:"bytes,7,opt,name=firewallDuration,proto3" json:"firewallDuration,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *NetworkMapRouteUpdate) Reset() {
+	*x = NetworkMapRouteUpdate{}
+	mi := &file_daemon_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkMapRouteUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkMapRouteUpdate) ProtoMessage() {}
+
+func (x *NetworkMapRouteUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkMapRouteUpdate.ProtoReflect.Descriptor instead.
+func (*NetworkMapRouteUpdate) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *NetworkMapRouteUpdate) GetSerial() uint64 {
+	if x != nil {
+		return x.Serial
+	}
+	return 0
+}
+
+func (x *NetworkMapRouteUpdate) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *NetworkMapRouteUpdate) GetClientRoutes() int32 {
+	if x != nil {
+		return x.ClientRoutes
+	}
+	return 0
+}
+
+func (x *NetworkMapRouteUpdate) GetServerRoutes() int32 {
+	if x != nil {
+		return x.ServerRoutes
+	}
+	return 0
+}
+
+func (x *NetworkMapRouteUpdate) GetRoutesDuration() *durationpb.Duration {
+	if x != nil {
+		return x.RoutesDuration
+	}
+	return nil
+}
+
+func (x *NetworkMapRouteUpdate) GetFirewallRules() int32 {
+	if x != nil {
+		return x.FirewallRules
+	}
+	return 0
+}
+
+func (x *NetworkMapRouteUpdate) GetFirewallDuration() *durationpb.Duration {
+	if x != nil {
+		return x.FirewallDuration
+	}
+	return nil
+}
+
+type GetRouteMetricsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// os is the operating system the route syscall latencies were measured on
+	Os            string                 `protobuf:"bytes,1,opt,name=os,proto3" json:"os,omitempty"`
+	RouteAdd      *LatencyHistogram      `protobuf:"bytes,2,opt,name=routeAdd,proto3" json:"routeAdd,omitempty"`
+	RouteRemove   *LatencyHistogram      `protobuf:"bytes,3,opt,name=routeRemove,proto3" json:"routeRemove,omitempty"`
+	Routes        *LatencyHistogram      `protobuf:"bytes,4,opt,name=routes,proto3" json:"routes,omitempty"`
+	Firewall      *LatencyHistogram      `protobuf:"bytes,5,opt,name=firewall,proto3" json:"firewall,omitempty"`
+	LastUpdate    *NetworkMapRouteUpdate `protobuf:"bytes,6,opt,name=lastUpdate,proto3" json:"lastUpdate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRouteMetricsResponse) Reset() {
+	*x = GetRouteMetricsResponse{}
+	mi := &file_daemon_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRouteMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRouteMetricsResponse) ProtoMessage() {}
+
+func (x *GetRouteMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRouteMetricsResponse.ProtoReflect.Descriptor instead.
+func (*GetRouteMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *GetRouteMetricsResponse) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *GetRouteMetricsResponse) GetRouteAdd() *LatencyHistogram {
+	if x != nil {
+		return x.RouteAdd
+	}
+	return nil
+}
+
+func (x *GetRouteMetricsResponse) GetRouteRemove() *LatencyHistogram {
+	if x != nil {
+		return x.RouteRemove
+	}
+	return nil
+}
+
+func (x *GetRouteMetricsResponse) GetRoutes() *LatencyHistogram {
+	if x != nil {
+		return x.Routes
+	}
+	return nil
+}
+
+func (x *GetRouteMetricsResponse) GetFirewall() *LatencyHistogram {
+	if x != nil {
+		return x.Firewall
+	}
+	return nil
+}
+
+func (x *GetRouteMetricsResponse) GetLastUpdate() *NetworkMapRouteUpdate {
+	if x != nil {
+		return x.LastUpdate
+	}
+	return nil
+}
+
+type ReconnectPeerRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// peer is a peer FQDN, NetBird IP or public key
+	Peer          string `protobuf:"bytes,1,opt,name=peer,proto3" json:"peer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReconnectPeerRequest) Reset() {
+	*x = ReconnectPeerRequest{}
+	mi := &file_daemon_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReconnectPeerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconnectPeerRequest) ProtoMessage() {}
+
+func (x *ReconnectPeerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconnectPeerRequest.ProtoReflect.Descriptor instead.
+func (*ReconnectPeerRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *ReconnectPeerRequest) GetPeer() string {
+	if x != nil {
+		return x.Peer
+	}
+	return ""
+}
+
+type ReconnectPeerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Fqdn          string                 `protobuf:"bytes,1,opt,name=fqdn,proto3" json:"fqdn,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReconnectPeerResponse) Reset() {
+	*x = ReconnectPeerResponse{}
+	mi := &file_daemon_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReconnectPeerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconnectPeerResponse) ProtoMessage() {}
+
+func (x *ReconnectPeerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconnectPeerResponse.ProtoReflect.Descriptor instead.
+func (*ReconnectPeerResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *ReconnectPeerResponse) GetFqdn() string {
+	if x != nil {
+		return x.Fqdn
+	}
+	return ""
+}
+
+type ForceRelayPeerRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// peer is a peer FQDN, NetBird IP or public key
+	Peer string `protobuf:"bytes,1,opt,name=peer,proto3" json:"peer,omitempty"`
+	// enabled keeps the connection on the relay, false releases it to ICE again
+	Enabled       bool `protobuf:"varint,2,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForceRelayPeerRequest) Reset() {
+	*x = ForceRelayPeerRequest{}
+	mi := &file_daemon_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForceRelayPeerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceRelayPeerRequest) ProtoMessage() {}
+
+func (x *ForceRelayPeerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForceRelayPeerRequest.ProtoReflect.Descriptor instead.
+func (*ForceRelayPeerRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *ForceRelayPeerRequest) GetPeer() string {
+	if x != nil {
+		return x.Peer
+	}
+	return ""
+}
+
+func (x *ForceRelayPeerRequest) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+type ForceRelayPeerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Fqdn          string                 `protobuf:"bytes,1,opt,name=fqdn,proto3" json:"fqdn,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForceRelayPeerResponse) Reset() {
+	*x = ForceRelayPeerResponse{}
+	mi := &file_daemon_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForceRelayPeerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceRelayPeerResponse) ProtoMessage() {}
+
+func (x *ForceRelayPeerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForceRelayPeerResponse.ProtoReflect.Descriptor instead.
+func (*ForceRelayPeerResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *ForceRelayPeerResponse) GetFqdn() string {
+	if x != nil {
+		return x.Fqdn
+	}
+	return ""
+}
+
+type GetPeerCandidatesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// peer is a peer FQDN, NetBird IP or public key
+	Peer          string `protobuf:"bytes,1,opt,name=peer,proto3" json:"peer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPeerCandidatesRequest) Reset() {
+	*x = GetPeerCandidatesRequest{}
+	mi := &file_daemon_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPeerCandidatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPeerCandidatesRequest) ProtoMessage() {}
+
+func (x *GetPeerCandidatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[91]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPeerCandidatesRequest.ProtoReflect.Descriptor instead.
+func (*GetPeerCandidatesRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *GetPeerCandidatesRequest) GetPeer() string {
+	if x != nil {
+		return x.Peer
+	}
+	return ""
+}
+
+// CandidatePair is an ICE candidate pair a peer connection selected
+type CandidatePair struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	LocalType      string                 `protobuf:"bytes,1,opt,name=localType,proto3" json:"localType,omitempty"`
+	LocalEndpoint  string                 `protobuf:"bytes,2,opt,name=localEndpoint,proto3" json:"localEndpoint,omitempty"`
+	RemoteType     string                 `protobuf:"bytes,3,opt,name=remoteType,proto3" json:"remoteType,omitempty"`
+	RemoteEndpoint string                 `protobuf:"bytes,4,opt,name=remoteEndpoint,proto3" json:"remoteEndpoint,omitempty"`
+	// relayed is set if the pair goes through a TURN server
+	Relayed    bool                   `protobuf:"varint,5,opt,name=relayed,proto3" json:"relayed,omitempty"`
+	SelectedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=selectedAt,proto3" json:"selectedAt,omitempty"`
+	// closedAt is when the pair stopped being used, unset for the current pair
+	ClosedAt      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=closedAt,proto3" json:"closedAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CandidatePair) Reset() {
+	*x = CandidatePair{}
+	mi := &file_daemon_proto_msgTypes[92]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CandidatePair) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CandidatePair) ProtoMessage() {}
+
+func (x *CandidatePair) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[92]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CandidatePair.ProtoReflect.Descriptor instead.
+func (*CandidatePair) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *CandidatePair) GetLocalType() string {
+	if x != nil {
+		return x.LocalType
+	}
+	return ""
+}
+
+func (x *CandidatePair) GetLocalEndpoint() string {
+	if x != nil {
+		return x.LocalEndpoint
+	}
+	return ""
+}
+
+func (x *CandidatePair) GetRemoteType() string {
+	if x != nil {
+		return x.RemoteType
+	}
+	return ""
+}
+
+func (x *CandidatePair) GetRemoteEndpoint() string {
+	if x != nil {
+		return x.RemoteEndpoint
+	}
+	return ""
+}
+
+func (x *CandidatePair) GetRelayed() bool {
+	if x != nil {
+		return x.Relayed
+	}
+	return false
+}
+
+func (x *CandidatePair) GetSelectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SelectedAt
+	}
+	return nil
+}
+
+func (x *CandidatePair) GetClosedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ClosedAt
+	}
+	return nil
+}
+
+type GetPeerCandidatesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Fqdn  string                 `protobuf:"bytes,1,opt,name=fqdn,proto3" json:"fqdn,omitempty"`
+	// current is the pair the connection uses, unset if ICE isn't connected
+	Current *CandidatePair `protobuf:"bytes,2,opt,name=current,proto3" json:"current,omitempty"`
+	// history are the previously selected pairs, the most recent last
+	History []*CandidatePair `protobuf:"bytes,3,rep,name=history,proto3" json:"history,omitempty"`
+	// relayForced is set if the connection is kept on the relay
+	RelayForced   bool `protobuf:"varint,4,opt,name=relayForced,proto3" json:"relayForced,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPeerCandidatesResponse) Reset() {
+	*x = GetPeerCandidatesResponse{}
+	mi := &file_daemon_proto_msgTypes[93]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPeerCandidatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPeerCandidatesResponse) ProtoMessage() {}
+
+func (x *GetPeerCandidatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[93]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPeerCandidatesResponse.ProtoReflect.Descriptor instead.
+func (*GetPeerCandidatesResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *GetPeerCandidatesResponse) GetFqdn() string {
+	if x != nil {
+		return x.Fqdn
+	}
+	return ""
+}
+
+func (x *GetPeerCandidatesResponse) GetCurrent() *CandidatePair {
+	if x != nil {
+		return x.Current
+	}
+	return nil
+}
+
+func (x *GetPeerCandidatesResponse) GetHistory() []*CandidatePair {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+func (x *GetPeerCandidatesResponse) GetRelayForced() bool {
+	if x != nil {
+		return x.RelayForced
+	}
+	return false
+}
+
+type GetRelayCandidatesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// refresh measures the round trip times again instead of returning the latest measurement
+	Refresh       bool `protobuf:"varint,1,opt,name=refresh,proto3" json:"refresh,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRelayCandidatesRequest) Reset() {
+	*x = GetRelayCandidatesRequest{}
+	mi := &file_daemon_proto_msgTypes[94]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRelayCandidatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRelayCandidatesRequest) ProtoMessage() {}
+
+func (x *GetRelayCandidatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[94]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRelayCandidatesRequest.ProtoReflect.Descriptor instead.
+func (*GetRelayCandidatesRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{94}
+}
+
+func (x *GetRelayCandidatesRequest) GetRefresh() bool {
+	if x != nil {
+		return x.Refresh
+	}
+	return false
+}
+
+// RelayCandidate is a relay server the client may use as its home relay server
+type RelayCandidate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Url   string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// rtt is the round trip time to the server, unset if it wasn't measured or the measurement failed
+	Rtt        *durationpb.Duration   `protobuf:"bytes,2,opt,name=rtt,proto3" json:"rtt,omitempty"`
+	Error      string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	MeasuredAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=measuredAt,proto3" json:"measuredAt,omitempty"`
+	// home is set for the server the client is connected to
+	Home bool `protobuf:"varint,5,opt,name=home,proto3" json:"home,omitempty"`
+	// preferred is set for the server the client selected, the pinned or the lowest latency one
+	Preferred     bool `protobuf:"varint,6,opt,name=preferred,proto3" json:"preferred,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RelayCandidate) Reset() {
+	*x = RelayCandidate{}
+	mi := &file_daemon_proto_msgTypes[95]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RelayCandidate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RelayCandidate) ProtoMessage() {}
+
+func (x *RelayCandidate) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[95]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RelayCandidate.ProtoReflect.Descriptor instead.
+func (*RelayCandidate) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{95}
+}
+
+func (x *RelayCandidate) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *RelayCandidate) GetRtt() *durationpb.Duration {
+	if x != nil {
+		return x.Rtt
+	}
+	return nil
+}
+
+func (x *RelayCandidate) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *RelayCandidate) GetMeasuredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MeasuredAt
+	}
+	return nil
+}
+
+func (x *RelayCandidate) GetHome() bool {
+	if x != nil {
+		return x.Home
+	}
+	return false
+}
+
+func (x *RelayCandidate) GetPreferred() bool {
+	if x != nil {
+		return x.Preferred
+	}
+	return false
+}
+
+type GetRelayCandidatesResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Candidates []*RelayCandidate      `protobuf:"bytes,1,rep,name=candidates,proto3" json:"candidates,omitempty"`
+	// pinned is the relay server pinned as the home relay server, empty if it is selected by latency
+	Pinned        string `protobuf:"bytes,2,opt,name=pinned,proto3" json:"pinned,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRelayCandidatesResponse) Reset() {
+	*x = GetRelayCandidatesResponse{}
+	mi := &file_daemon_proto_msgTypes[96]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRelayCandidatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRelayCandidatesResponse) ProtoMessage() {}
+
+func (x *GetRelayCandidatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[96]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRelayCandidatesResponse.ProtoReflect.Descriptor instead.
+func (*GetRelayCandidatesResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{96}
+}
+
+func (x *GetRelayCandidatesResponse) GetCandidates() []*RelayCandidate {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
+func (x *GetRelayCandidatesResponse) GetPinned() string {
+	if x != nil {
+		return x.Pinned
+	}
+	return ""
+}
+
+type SetPreferredRelayRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// url is the relay server to pin, empty to select the one with the lowest latency again
+	Url           string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPreferredRelayRequest) Reset() {
+	*x = SetPreferredRelayRequest{}
+	mi := &file_daemon_proto_msgTypes[97]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPreferredRelayRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPreferredRelayRequest) ProtoMessage() {}
+
+func (x *SetPreferredRelayRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[97]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPreferredRelayRequest.ProtoReflect.Descriptor instead.
+func (*SetPreferredRelayRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{97}
+}
+
+func (x *SetPreferredRelayRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type SetPreferredRelayResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPreferredRelayResponse) Reset() {
+	*x = SetPreferredRelayResponse{}
+	mi := &file_daemon_proto_msgTypes[98]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPreferredRelayResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPreferredRelayResponse) ProtoMessage() {}
+
+func (x *SetPreferredRelayResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[98]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPreferredRelayResponse.ProtoReflect.Descriptor instead.
+func (*SetPreferredRelayResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{98}
+}
+
+type SubscribeWGStatsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// interval between the updates, 1 second if unset, at least 250 milliseconds
+	Interval      *durationpb.Duration `protobuf:"bytes,1,opt,name=interval,proto3" json:"interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeWGStatsRequest) Reset() {
+	*x = SubscribeWGStatsRequest{}
+	mi := &file_daemon_proto_msgTypes[99]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeWGStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeWGStatsRequest) ProtoMessage() {}
+
+func (x *SubscribeWGStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[99]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2285,33 +6774,46 @@ func (x *ListNetworksRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListNetworksRequest.ProtoReflect.Descriptor instead.
-func (*ListNetworksRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use SubscribeWGStatsRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeWGStatsRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{99}
 }
 
-type ListNetworksResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Routes        []*Network             `protobuf:"bytes,1,rep,name=routes,proto3" json:"routes,omitempty"`
+func (x *SubscribeWGStatsRequest) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+type WGPeerStats struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	PubKey string                 `protobuf:"bytes,1,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
+	// rxBytes and txBytes are the bytes received from and sent to the peer since the previous update
+	RxBytes       int64                  `protobuf:"varint,2,opt,name=rxBytes,proto3" json:"rxBytes,omitempty"`
+	TxBytes       int64                  `protobuf:"varint,3,opt,name=txBytes,proto3" json:"txBytes,omitempty"`
+	LastHandshake *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=lastHandshake,proto3" json:"lastHandshake,omitempty"`
+	// handshake is set if a handshake with the peer completed since the previous update
+	Handshake     bool `protobuf:"varint,5,opt,name=handshake,proto3" json:"handshake,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListNetworksResponse) Reset() {
-	*x = ListNetworksResponse{}
-	mi := &file_daemon_proto_msgTypes[25]
+func (x *WGPeerStats) Reset() {
+	*x = WGPeerStats{}
+	mi := &file_daemon_proto_msgTypes[100]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListNetworksResponse) String() string {
+func (x *WGPeerStats) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListNetworksResponse) ProtoMessage() {}
+func (*WGPeerStats) ProtoMessage() {}
 
-func (x *ListNetworksResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[25]
+func (x *WGPeerStats) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[100]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2322,42 +6824,71 @@ func (x *ListNetworksResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListNetworksResponse.ProtoReflect.Descriptor instead.
-func (*ListNetworksResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{25}
+// Deprecated: Use WGPeerStats.ProtoReflect.Descriptor instead.
+func (*WGPeerStats) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{100}
 }
 
-func (x *ListNetworksResponse) GetRoutes() []*Network {
+func (x *WGPeerStats) GetPubKey() string {
 	if x != nil {
-		return x.Routes
+		return x.PubKey
+	}
+	return ""
+}
+
+func (x *WGPeerStats) GetRxBytes() int64 {
+	if x != nil {
+		return x.RxBytes
+	}
+	return 0
+}
+
+func (x *WGPeerStats) GetTxBytes() int64 {
+	if x != nil {
+		return x.TxBytes
+	}
+	return 0
+}
+
+func (x *WGPeerStats) GetLastHandshake() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastHandshake
 	}
 	return nil
 }
 
-type SelectNetworksRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	NetworkIDs    []string               `protobuf:"bytes,1,rep,name=networkIDs,proto3" json:"networkIDs,omitempty"`
-	Append        bool                   `protobuf:"varint,2,opt,name=append,proto3" json:"append,omitempty"`
-	All           bool                   `protobuf:"varint,3,opt,name=all,proto3" json:"all,omitempty"`
+func (x *WGPeerStats) GetHandshake() bool {
+	if x != nil {
+		return x.Handshake
+	}
+	return false
+}
+
+type WGStatsUpdate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Time  *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	// elapsed is the time since the previous update, the transfer of the peers covers it
+	Elapsed       *durationpb.Duration `protobuf:"bytes,2,opt,name=elapsed,proto3" json:"elapsed,omitempty"`
+	Peers         []*WGPeerStats       `protobuf:"bytes,3,rep,name=peers,proto3" json:"peers,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SelectNetworksRequest) Reset() {
-	*x = SelectNetworksRequest{}
-	mi := &file_daemon_proto_msgTypes[26]
+func (x *WGStatsUpdate) Reset() {
+	*x = WGStatsUpdate{}
+	mi := &file_daemon_proto_msgTypes[101]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SelectNetworksRequest) String() string {
+func (x *WGStatsUpdate) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SelectNetworksRequest) ProtoMessage() {}
+func (*WGStatsUpdate) ProtoMessage() {}
 
-func (x *SelectNetworksRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[26]
+func (x *WGStatsUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[101]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2368,53 +6899,53 @@ func (x *SelectNetworksRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SelectNetworksRequest.ProtoReflect.Descriptor instead.
-func (*SelectNetworksRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{26}
+// Deprecated: Use WGStatsUpdate.ProtoReflect.Descriptor instead.
+func (*WGStatsUpdate) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{101}
 }
 
-func (x *SelectNetworksRequest) GetNetworkIDs() []string {
+func (x *WGStatsUpdate) GetTime() *timestamppb.Timestamp {
 	if x != nil {
-		return x.NetworkIDs
+		return x.Time
 	}
 	return nil
 }
 
-func (x *SelectNetworksRequest) GetAppend() bool {
+func (x *WGStatsUpdate) GetElapsed() *durationpb.Duration {
 	if x != nil {
-		return x.Append
+		return x.Elapsed
 	}
-	return false
+	return nil
 }
 
-func (x *SelectNetworksRequest) GetAll() bool {
+func (x *WGStatsUpdate) GetPeers() []*WGPeerStats {
 	if x != nil {
-		return x.All
+		return x.Peers
 	}
-	return false
+	return nil
 }
 
-type SelectNetworksResponse struct {
+type GetDNSForwarderStatsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SelectNetworksResponse) Reset() {
-	*x = SelectNetworksResponse{}
-	mi := &file_daemon_proto_msgTypes[27]
+func (x *GetDNSForwarderStatsRequest) Reset() {
+	*x = GetDNSForwarderStatsRequest{}
+	mi := &file_daemon_proto_msgTypes[102]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SelectNetworksResponse) String() string {
+func (x *GetDNSForwarderStatsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SelectNetworksResponse) ProtoMessage() {}
+func (*GetDNSForwarderStatsRequest) ProtoMessage() {}
 
-func (x *SelectNetworksResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[27]
+func (x *GetDNSForwarderStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[102]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2425,33 +6956,143 @@ func (x *SelectNetworksResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SelectNetworksResponse.ProtoReflect.Descriptor instead.
-func (*SelectNetworksResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{27}
+// Deprecated: Use GetDNSForwarderStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetDNSForwarderStatsRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{102}
+}
+
+type GetDNSForwarderStatsResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	CacheEntries uint32                 `protobuf:"varint,1,opt,name=cacheEntries,proto3" json:"cacheEntries,omitempty"`
+	// cacheHits are the queries answered from fresh cache entries, negativeHits the NXDOMAIN and NODATA ones of them
+	CacheHits    uint64 `protobuf:"varint,2,opt,name=cacheHits,proto3" json:"cacheHits,omitempty"`
+	NegativeHits uint64 `protobuf:"varint,3,opt,name=negativeHits,proto3" json:"negativeHits,omitempty"`
+	CacheMisses  uint64 `protobuf:"varint,4,opt,name=cacheMisses,proto3" json:"cacheMisses,omitempty"`
+	// staleAnswers are the queries answered from expired cache entries because the upstream failed
+	StaleAnswers     uint64 `protobuf:"varint,5,opt,name=staleAnswers,proto3" json:"staleAnswers,omitempty"`
+	UpstreamQueries  uint64 `protobuf:"varint,6,opt,name=upstreamQueries,proto3" json:"upstreamQueries,omitempty"`
+	UpstreamFailures uint64 `protobuf:"varint,7,opt,name=upstreamFailures,proto3" json:"upstreamFailures,omitempty"`
+	// upstreamLatency is the average duration of the upstream queries
+	UpstreamLatency    *durationpb.Duration `protobuf:"bytes,8,opt,name=upstreamLatency,proto3" json:"upstreamLatency,omitempty"`
+	UpstreamLatencyMax *durationpb.Duration `protobuf:"bytes,9,opt,name=upstreamLatencyMax,proto3" json:"upstreamLatencyMax,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
-type IPList struct {
+func (x *GetDNSForwarderStatsResponse) Reset() {
+	*x = GetDNSForwarderStatsResponse{}
+	mi := &file_daemon_proto_msgTypes[103]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDNSForwarderStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDNSForwarderStatsResponse) ProtoMessage() {}
+
+func (x *GetDNSForwarderStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[103]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDNSForwarderStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetDNSForwarderStatsResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{103}
+}
+
+func (x *GetDNSForwarderStatsResponse) GetCacheEntries() uint32 {
+	if x != nil {
+		return x.CacheEntries
+	}
+	return 0
+}
+
+func (x *GetDNSForwarderStatsResponse) GetCacheHits() uint64 {
+	if x != nil {
+		return x.CacheHits
+	}
+	return 0
+}
+
+func (x *GetDNSForwarderStatsResponse) GetNegativeHits() uint64 {
+	if x != nil {
+		return x.NegativeHits
+	}
+	return 0
+}
+
+func (x *GetDNSForwarderStatsResponse) GetCacheMisses() uint64 {
+	if x != nil {
+		return x.CacheMisses
+	}
+	return 0
+}
+
+func (x *GetDNSForwarderStatsResponse) GetStaleAnswers() uint64 {
+	if x != nil {
+		return x.StaleAnswers
+	}
+	return 0
+}
+
+func (x *GetDNSForwarderStatsResponse) GetUpstreamQueries() uint64 {
+	if x != nil {
+		return x.UpstreamQueries
+	}
+	return 0
+}
+
+func (x *GetDNSForwarderStatsResponse) GetUpstreamFailures() uint64 {
+	if x != nil {
+		return x.UpstreamFailures
+	}
+	return 0
+}
+
+func (x *GetDNSForwarderStatsResponse) GetUpstreamLatency() *durationpb.Duration {
+	if x != nil {
+		return x.UpstreamLatency
+	}
+	return nil
+}
+
+func (x *GetDNSForwarderStatsResponse) GetUpstreamLatencyMax() *durationpb.Duration {
+	if x != nil {
+		return x.UpstreamLatencyMax
+	}
+	return nil
+}
+
+type GetEffectiveConfigRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ips           []string               `protobuf:"bytes,1,rep,name=ips,proto3" json:"ips,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *IPList) Reset() {
-	*x = IPList{}
-	mi := &file_daemon_proto_msgTypes[28]
+func (x *GetEffectiveConfigRequest) Reset() {
+	*x = GetEffectiveConfigRequest{}
+	mi := &file_daemon_proto_msgTypes[104]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *IPList) String() string {
+func (x *GetEffectiveConfigRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*IPList) ProtoMessage() {}
+func (*GetEffectiveConfigRequest) ProtoMessage() {}
 
-func (x *IPList) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[28]
+func (x *GetEffectiveConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[104]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2462,44 +7103,164 @@ func (x *IPList) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use IPList.ProtoReflect.Descriptor instead.
-func (*IPList) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{28}
+// Deprecated: Use GetEffectiveConfigRequest.ProtoReflect.Descriptor instead.
+func (*GetEffectiveConfigRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{104}
 }
 
-func (x *IPList) GetIps() []string {
+// EffectiveSetting is a value the running engine uses
+type EffectiveSetting struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// source is where the value came from: default, config or management
+	Source        string `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EffectiveSetting) Reset() {
+	*x = EffectiveSetting{}
+	mi := &file_daemon_proto_msgTypes[105]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EffectiveSetting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EffectiveSetting) ProtoMessage() {}
+
+func (x *EffectiveSetting) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[105]
 	if x != nil {
-		return x.Ips
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EffectiveSetting.ProtoReflect.Descriptor instead.
+func (*EffectiveSetting) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{105}
+}
+
+func (x *EffectiveSetting) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EffectiveSetting) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *EffectiveSetting) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type GetEffectiveConfigResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Settings []*EffectiveSetting    `protobuf:"bytes,1,rep,name=settings,proto3" json:"settings,omitempty"`
+	// features are the feature flags management can turn on for the peer
+	Features      []*EffectiveSetting `protobuf:"bytes,2,rep,name=features,proto3" json:"features,omitempty"`
+	Version       string              `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEffectiveConfigResponse) Reset() {
+	*x = GetEffectiveConfigResponse{}
+	mi := &file_daemon_proto_msgTypes[106]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEffectiveConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEffectiveConfigResponse) ProtoMessage() {}
+
+func (x *GetEffectiveConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[106]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEffectiveConfigResponse.ProtoReflect.Descriptor instead.
+func (*GetEffectiveConfigResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{106}
+}
+
+func (x *GetEffectiveConfigResponse) GetSettings() []*EffectiveSetting {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *GetEffectiveConfigResponse) GetFeatures() []*EffectiveSetting {
+	if x != nil {
+		return x.Features
 	}
 	return nil
 }
 
-type Network struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ID            string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	Range         string                 `protobuf:"bytes,2,opt,name=range,proto3" json:"range,omitempty"`
-	Selected      bool                   `protobuf:"varint,3,opt,name=selected,proto3" json:"selected,omitempty"`
-	Domains       []string               `protobuf:"bytes,4,rep,name=domains,proto3" json:"domains,omitempty"`
-	ResolvedIPs   map[string]*IPList     `protobuf:"bytes,5,rep,name=resolvedIPs,proto3" json:"resolvedIPs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+func (x *GetEffectiveConfigResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+// QueryFlowsRequest filters the flows, unset fields match all flows
+type QueryFlowsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// peer is the FQDN, NetBird IP or public key of a peer, or any IP address
+	Peer string `protobuf:"bytes,1,opt,name=peer,proto3" json:"peer,omitempty"`
+	Port uint32 `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	// protocol is tcp, udp, icmp, sctp or the protocol number
+	Protocol      string                 `protobuf:"bytes,3,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Since         *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=since,proto3" json:"since,omitempty"`
+	Until         *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=until,proto3" json:"until,omitempty"`
+	Limit         uint32                 `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Network) Reset() {
-	*x = Network{}
-	mi := &file_daemon_proto_msgTypes[29]
+func (x *QueryFlowsRequest) Reset() {
+	*x = QueryFlowsRequest{}
+	mi := &file_daemon_proto_msgTypes[107]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Network) String() string {
+func (x *QueryFlowsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Network) ProtoMessage() {}
+func (*QueryFlowsRequest) ProtoMessage() {}
 
-func (x *Network) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[29]
+func (x *QueryFlowsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[107]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2510,73 +7271,92 @@ func (x *Network) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Network.ProtoReflect.Descriptor instead.
-func (*Network) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{29}
+// Deprecated: Use QueryFlowsRequest.ProtoReflect.Descriptor instead.
+func (*QueryFlowsRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{107}
 }
 
-func (x *Network) GetID() string {
+func (x *QueryFlowsRequest) GetPeer() string {
 	if x != nil {
-		return x.ID
+		return x.Peer
 	}
 	return ""
 }
 
-func (x *Network) GetRange() string {
+func (x *QueryFlowsRequest) GetPort() uint32 {
 	if x != nil {
-		return x.Range
+		return x.Port
 	}
-	return ""
+	return 0
 }
 
-func (x *Network) GetSelected() bool {
+func (x *QueryFlowsRequest) GetProtocol() string {
 	if x != nil {
-		return x.Selected
+		return x.Protocol
 	}
-	return false
+	return ""
 }
 
-func (x *Network) GetDomains() []string {
+func (x *QueryFlowsRequest) GetSince() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Domains
+		return x.Since
 	}
 	return nil
 }
 
-func (x *Network) GetResolvedIPs() map[string]*IPList {
+func (x *QueryFlowsRequest) GetUntil() *timestamppb.Timestamp {
 	if x != nil {
-		return x.ResolvedIPs
+		return x.Until
 	}
 	return nil
 }
 
-// ForwardingRules
-type PortInfo struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Types that are valid to be assigned to PortSelection:
-	//
-	//	*PortInfo_Port
-	//	*PortInfo_Range_
-	PortSelection isPortInfo_PortSelection `protobuf_oneof:"portSelection"`
+func (x *QueryFlowsRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type FlowRecord struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	FlowId    string                 `protobuf:"bytes,2,opt,name=flowId,proto3" json:"flowId,omitempty"`
+	// type is start, end, drop or audit
+	Type       string `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Direction  string `protobuf:"bytes,4,opt,name=direction,proto3" json:"direction,omitempty"`
+	Protocol   string `protobuf:"bytes,5,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	SourceIp   string `protobuf:"bytes,6,opt,name=sourceIp,proto3" json:"sourceIp,omitempty"`
+	SourcePort uint32 `protobuf:"varint,7,opt,name=sourcePort,proto3" json:"sourcePort,omitempty"`
+	DestIp     string `protobuf:"bytes,8,opt,name=destIp,proto3" json:"destIp,omitempty"`
+	DestPort   uint32 `protobuf:"varint,9,opt,name=destPort,proto3" json:"destPort,omitempty"`
+	IcmpType   uint32 `protobuf:"varint,10,opt,name=icmpType,proto3" json:"icmpType,omitempty"`
+	IcmpCode   uint32 `protobuf:"varint,11,opt,name=icmpCode,proto3" json:"icmpCode,omitempty"`
+	RxPackets  uint64 `protobuf:"varint,12,opt,name=rxPackets,proto3" json:"rxPackets,omitempty"`
+	TxPackets  uint64 `protobuf:"varint,13,opt,name=txPackets,proto3" json:"txPackets,omitempty"`
+	RxBytes    uint64 `protobuf:"varint,14,opt,name=rxBytes,proto3" json:"rxBytes,omitempty"`
+	TxBytes    uint64 `protobuf:"varint,15,opt,name=txBytes,proto3" json:"txBytes,omitempty"`
+	// dnsQuery is the query name of the events of the DNS query log
+	DnsQuery      string `protobuf:"bytes,16,opt,name=dnsQuery,proto3" json:"dnsQuery,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PortInfo) Reset() {
-	*x = PortInfo{}
-	mi := &file_daemon_proto_msgTypes[30]
+func (x *FlowRecord) Reset() {
+	*x = FlowRecord{}
+	mi := &file_daemon_proto_msgTypes[108]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PortInfo) String() string {
+func (x *FlowRecord) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PortInfo) ProtoMessage() {}
+func (*FlowRecord) ProtoMessage() {}
 
-func (x *PortInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[30]
+func (x *FlowRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[108]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2587,150 +7367,145 @@ func (x *PortInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PortInfo.ProtoReflect.Descriptor instead.
-func (*PortInfo) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{30}
+// Deprecated: Use FlowRecord.ProtoReflect.Descriptor instead.
+func (*FlowRecord) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{108}
 }
 
-func (x *PortInfo) GetPortSelection() isPortInfo_PortSelection {
+func (x *FlowRecord) GetTimestamp() *timestamppb.Timestamp {
 	if x != nil {
-		return x.PortSelection
+		return x.Timestamp
 	}
 	return nil
 }
 
-func (x *PortInfo) GetPort() uint32 {
+func (x *FlowRecord) GetFlowId() string {
 	if x != nil {
-		if x, ok := x.PortSelection.(*PortInfo_Port); ok {
-			return x.Port
-		}
+		return x.FlowId
 	}
-	return 0
+	return ""
 }
 
-func (x *PortInfo) GetRange() *PortInfo_Range {
+func (x *FlowRecord) GetType() string {
 	if x != nil {
-		if x, ok := x.PortSelection.(*PortInfo_Range_); ok {
-			return x.Range
-		}
+		return x.Type
 	}
-	return nil
+	return ""
 }
 
-type isPortInfo_PortSelection interface {
-	isPortInfo_PortSelection()
+func (x *FlowRecord) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
 }
 
-type PortInfo_Port struct {
-	Port uint32 `protobuf:"varint,1,opt,name=port,proto3,oneof"`
+func (x *FlowRecord) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
 }
 
-type PortInfo_Range_ struct {
-	Range *PortInfo_Range `protobuf:"bytes,2,opt,name=range,proto3,oneof"`
+func (x *FlowRecord) GetSourceIp() string {
+	if x != nil {
+		return x.SourceIp
+	}
+	return ""
 }
 
-func (*PortInfo_Port) isPortInfo_PortSelection() {}
-
-func (*PortInfo_Range_) isPortInfo_PortSelection() {}
-
-type ForwardingRule struct {
-	state              protoimpl.MessageState `protogen:"open.v1"`
-	Protocol           string                 `protobuf:"bytes,1,opt,name=protocol,proto3" json:"protocol,omitempty"`
-	DestinationPort    *PortInfo              `protobuf:"bytes,2,opt,name=destinationPort,proto3" json:"destinationPort,omitempty"`
-	TranslatedAddress  string                 `protobuf:"bytes,3,opt,name=translatedAddress,proto3" json:"translatedAddress,omitempty"`
-	TranslatedHostname string                 `protobuf:"bytes,4,opt,name=translatedHostname,proto3" json:"translatedHostname,omitempty"`
-	TranslatedPort     *PortInfo              `protobuf:"bytes,5,opt,name=translatedPort,proto3" json:"translatedPort,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+func (x *FlowRecord) GetSourcePort() uint32 {
+	if x != nil {
+		return x.SourcePort
+	}
+	return 0
 }
 
-func (x *ForwardingRule) Reset() {
-	*x = ForwardingRule{}
-	mi := &file_daemon_proto_msgTypes[31]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *FlowRecord) GetDestIp() string {
+	if x != nil {
+		return x.DestIp
+	}
+	return ""
 }
 
-func (x *ForwardingRule) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *FlowRecord) GetDestPort() uint32 {
+	if x != nil {
+		return x.DestPort
+	}
+	return 0
 }
 
-func (*ForwardingRule) ProtoMessage() {}
-
-func (x *ForwardingRule) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[31]
+func (x *FlowRecord) GetIcmpType() uint32 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.IcmpType
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use ForwardingRule.ProtoReflect.Descriptor instead.
-func (*ForwardingRule) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{31}
+func (x *FlowRecord) GetIcmpCode() uint32 {
+	if x != nil {
+		return x.IcmpCode
+	}
+	return 0
 }
 
-func (x *ForwardingRule) GetProtocol() string {
+func (x *FlowRecord) GetRxPackets() uint64 {
 	if x != nil {
-		return x.Protocol
+		return x.RxPackets
 	}
-	return ""
+	return 0
 }
 
-func (x *ForwardingRule) GetDestinationPort() *PortInfo {
+func (x *FlowRecord) GetTxPackets() uint64 {
 	if x != nil {
-		return x.DestinationPort
+		return x.TxPackets
 	}
-	return nil
+	return 0
 }
 
-func (x *ForwardingRule) GetTranslatedAddress() string {
+func (x *FlowRecord) GetRxBytes() uint64 {
 	if x != nil {
-		return x.TranslatedAddress
+		return x.RxBytes
 	}
-	return ""
+	return 0
 }
 
-func (x *ForwardingRule) GetTranslatedHostname() string {
+func (x *FlowRecord) GetTxBytes() uint64 {
 	if x != nil {
-		return x.TranslatedHostname
+		return x.TxBytes
 	}
-	return ""
+	return 0
 }
 
-func (x *ForwardingRule) GetTranslatedPort() *PortInfo {
+func (x *FlowRecord) GetDnsQuery() string {
 	if x != nil {
-		return x.TranslatedPort
+		return x.DnsQuery
 	}
-	return nil
+	return ""
 }
 
-type ForwardingRulesResponse struct {
+type QueryFlowsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Rules         []*ForwardingRule      `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	Flows         []*FlowRecord          `protobuf:"bytes,1,rep,name=flows,proto3" json:"flows,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ForwardingRulesResponse) Reset() {
-	*x = ForwardingRulesResponse{}
-	mi := &file_daemon_proto_msgTypes[32]
+func (x *QueryFlowsResponse) Reset() {
+	*x = QueryFlowsResponse{}
+	mi := &file_daemon_proto_msgTypes[109]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ForwardingRulesResponse) String() string {
+func (x *QueryFlowsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ForwardingRulesResponse) ProtoMessage() {}
+func (*QueryFlowsResponse) ProtoMessage() {}
 
-func (x *ForwardingRulesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[32]
+func (x *QueryFlowsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[109]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2741,45 +7516,50 @@ func (x *ForwardingRulesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ForwardingRulesResponse.ProtoReflect.Descriptor instead.
-func (*ForwardingRulesResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{32}
+// Deprecated: Use QueryFlowsResponse.ProtoReflect.Descriptor instead.
+func (*QueryFlowsResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{109}
 }
 
-func (x *ForwardingRulesResponse) GetRules() []*ForwardingRule {
+func (x *QueryFlowsResponse) GetFlows() []*FlowRecord {
 	if x != nil {
-		return x.Rules
+		return x.Flows
 	}
 	return nil
 }
 
-// DebugBundler
-type DebugBundleRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Anonymize     bool                   `protobuf:"varint,1,opt,name=anonymize,proto3" json:"anonymize,omitempty"`
-	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	SystemInfo    bool                   `protobuf:"varint,3,opt,name=systemInfo,proto3" json:"systemInfo,omitempty"`
-	UploadURL     string                 `protobuf:"bytes,4,opt,name=uploadURL,proto3" json:"uploadURL,omitempty"`
-	LogFileCount  uint32                 `protobuf:"varint,5,opt,name=logFileCount,proto3" json:"logFileCount,omitempty"`
+type InboundPrompt struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PeerIp   string                 `protobuf:"bytes,2,opt,name=peerIp,proto3" json:"peerIp,omitempty"`
+	PeerFqdn string                 `protobuf:"bytes,3,opt,name=peerFqdn,proto3" json:"peerFqdn,omitempty"`
+	// protocol is tcp or udp
+	Protocol string                 `protobuf:"bytes,4,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Port     uint32                 `protobuf:"varint,5,opt,name=port,proto3" json:"port,omitempty"`
+	Created  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created,proto3" json:"created,omitempty"`
+	// decision is pending, allowed or denied
+	Decision string `protobuf:"bytes,7,opt,name=decision,proto3" json:"decision,omitempty"`
+	// expires is when the decision ends or the unanswered prompt is dropped
+	Expires       *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=expires,proto3" json:"expires,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DebugBundleRequest) Reset() {
-	*x = DebugBundleRequest{}
-	mi := &file_daemon_proto_msgTypes[33]
+func (x *InboundPrompt) Reset() {
+	*x = InboundPrompt{}
+	mi := &file_daemon_proto_msgTypes[110]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DebugBundleRequest) String() string {
+func (x *InboundPrompt) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DebugBundleRequest) ProtoMessage() {}
+func (*InboundPrompt) ProtoMessage() {}
 
-func (x *DebugBundleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[33]
+func (x *InboundPrompt) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[110]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2790,70 +7570,88 @@ func (x *DebugBundleRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DebugBundleRequest.ProtoReflect.Descriptor instead.
-func (*DebugBundleRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{33}
+// Deprecated: Use InboundPrompt.ProtoReflect.Descriptor instead.
+func (*InboundPrompt) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{110}
 }
 
-func (x *DebugBundleRequest) GetAnonymize() bool {
+func (x *InboundPrompt) GetId() string {
 	if x != nil {
-		return x.Anonymize
+		return x.Id
 	}
-	return false
+	return ""
 }
 
-func (x *DebugBundleRequest) GetStatus() string {
+func (x *InboundPrompt) GetPeerIp() string {
 	if x != nil {
-		return x.Status
+		return x.PeerIp
 	}
 	return ""
 }
 
-func (x *DebugBundleRequest) GetSystemInfo() bool {
+func (x *InboundPrompt) GetPeerFqdn() string {
 	if x != nil {
-		return x.SystemInfo
+		return x.PeerFqdn
 	}
-	return false
+	return ""
 }
 
-func (x *DebugBundleRequest) GetUploadURL() string {
+func (x *InboundPrompt) GetProtocol() string {
 	if x != nil {
-		return x.UploadURL
+		return x.Protocol
 	}
 	return ""
 }
 
-func (x *DebugBundleRequest) GetLogFileCount() uint32 {
+func (x *InboundPrompt) GetPort() uint32 {
 	if x != nil {
-		return x.LogFileCount
+		return x.Port
 	}
 	return 0
 }
 
-type DebugBundleResponse struct {
-	state               protoimpl.MessageState `protogen:"open.v1"`
-	Path                string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	UploadedKey         string                 `protobuf:"bytes,2,opt,name=uploadedKey,proto3" json:"uploadedKey,omitempty"`
-	UploadFailureReason string                 `protobuf:"bytes,3,opt,name=uploadFailureReason,proto3" json:"uploadFailureReason,omitempty"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+func (x *InboundPrompt) GetCreated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Created
+	}
+	return nil
 }
 
-func (x *DebugBundleResponse) Reset() {
-	*x = DebugBundleResponse{}
-	mi := &file_daemon_proto_msgTypes[34]
+func (x *InboundPrompt) GetDecision() string {
+	if x != nil {
+		return x.Decision
+	}
+	return ""
+}
+
+func (x *InboundPrompt) GetExpires() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Expires
+	}
+	return nil
+}
+
+type ListInboundPromptsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInboundPromptsRequest) Reset() {
+	*x = ListInboundPromptsRequest{}
+	mi := &file_daemon_proto_msgTypes[111]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DebugBundleResponse) String() string {
+func (x *ListInboundPromptsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DebugBundleResponse) ProtoMessage() {}
+func (*ListInboundPromptsRequest) ProtoMessage() {}
 
-func (x *DebugBundleResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[34]
+func (x *ListInboundPromptsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[111]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2861,56 +7659,36 @@ func (x *DebugBundleResponse) ProtoReflect() protoreflect.Message {
 		}
 		return ms
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use DebugBundleResponse.ProtoReflect.Descriptor instead.
-func (*DebugBundleResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{34}
-}
-
-func (x *DebugBundleResponse) GetPath() string {
-	if x != nil {
-		return x.Path
-	}
-	return ""
-}
-
-func (x *DebugBundleResponse) GetUploadedKey() string {
-	if x != nil {
-		return x.UploadedKey
-	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *DebugBundleResponse) GetUploadFailureReason() string {
-	if x != nil {
-		return x.UploadFailureReason
-	}
-	return ""
+// Deprecated: Use ListInboundPromptsRequest.ProtoReflect.Descriptor instead.
+func (*ListInboundPromptsRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{111}
 }
 
-type GetLogLevelRequest struct {
+type ListInboundPromptsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prompts       []*InboundPrompt       `protobuf:"bytes,1,rep,name=prompts,proto3" json:"prompts,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetLogLevelRequest) Reset() {
-	*x = GetLogLevelRequest{}
-	mi := &file_daemon_proto_msgTypes[35]
+func (x *ListInboundPromptsResponse) Reset() {
+	*x = ListInboundPromptsResponse{}
+	mi := &file_daemon_proto_msgTypes[112]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetLogLevelRequest) String() string {
+func (x *ListInboundPromptsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetLogLevelRequest) ProtoMessage() {}
+func (*ListInboundPromptsResponse) ProtoMessage() {}
 
-func (x *GetLogLevelRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[35]
+func (x *ListInboundPromptsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[112]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2921,33 +7699,43 @@ func (x *GetLogLevelRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetLogLevelRequest.ProtoReflect.Descriptor instead.
-func (*GetLogLevelRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{35}
+// Deprecated: Use ListInboundPromptsResponse.ProtoReflect.Descriptor instead.
+func (*ListInboundPromptsResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{112}
 }
 
-type GetLogLevelResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Level         LogLevel               `protobuf:"varint,1,opt,name=level,proto3,enum=daemon.LogLevel" json:"level,omitempty"`
+func (x *ListInboundPromptsResponse) GetPrompts() []*InboundPrompt {
+	if x != nil {
+		return x.Prompts
+	}
+	return nil
+}
+
+type RespondInboundPromptRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Allow bool                   `protobuf:"varint,2,opt,name=allow,proto3" json:"allow,omitempty"`
+	// duration of the decision, one hour if unset
+	Duration      *durationpb.Duration `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetLogLevelResponse) Reset() {
-	*x = GetLogLevelResponse{}
-	mi := &file_daemon_proto_msgTypes[36]
+func (x *RespondInboundPromptRequest) Reset() {
+	*x = RespondInboundPromptRequest{}
+	mi := &file_daemon_proto_msgTypes[113]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetLogLevelResponse) String() string {
+func (x *RespondInboundPromptRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetLogLevelResponse) ProtoMessage() {}
+func (*RespondInboundPromptRequest) ProtoMessage() {}
 
-func (x *GetLogLevelResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[36]
+func (x *RespondInboundPromptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[113]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2958,40 +7746,54 @@ func (x *GetLogLevelResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetLogLevelResponse.ProtoReflect.Descriptor instead.
-func (*GetLogLevelResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{36}
+// Deprecated: Use RespondInboundPromptRequest.ProtoReflect.Descriptor instead.
+func (*RespondInboundPromptRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{113}
 }
 
-func (x *GetLogLevelResponse) GetLevel() LogLevel {
+func (x *RespondInboundPromptRequest) GetId() string {
 	if x != nil {
-		return x.Level
+		return x.Id
 	}
-	return LogLevel_UNKNOWN
+	return ""
 }
 
-type SetLogLevelRequest struct {
+func (x *RespondInboundPromptRequest) GetAllow() bool {
+	if x != nil {
+		return x.Allow
+	}
+	return false
+}
+
+func (x *RespondInboundPromptRequest) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+type RespondInboundPromptResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Level         LogLevel               `protobuf:"varint,1,opt,name=level,proto3,enum=daemon.LogLevel" json:"level,omitempty"`
+	Prompt        *InboundPrompt         `protobuf:"bytes,1,opt,name=prompt,proto3" json:"prompt,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetLogLevelRequest) Reset() {
-	*x = SetLogLevelRequest{}
-	mi := &file_daemon_proto_msgTypes[37]
+func (x *RespondInboundPromptResponse) Reset() {
+	*x = RespondInboundPromptResponse{}
+	mi := &file_daemon_proto_msgTypes[114]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetLogLevelRequest) String() string {
+func (x *RespondInboundPromptResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetLogLevelRequest) ProtoMessage() {}
+func (*RespondInboundPromptResponse) ProtoMessage() {}
 
-func (x *SetLogLevelRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[37]
+func (x *RespondInboundPromptResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[114]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3002,39 +7804,44 @@ func (x *SetLogLevelRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetLogLevelRequest.ProtoReflect.Descriptor instead.
-func (*SetLogLevelRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{37}
+// Deprecated: Use RespondInboundPromptResponse.ProtoReflect.Descriptor instead.
+func (*RespondInboundPromptResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{114}
 }
 
-func (x *SetLogLevelRequest) GetLevel() LogLevel {
+func (x *RespondInboundPromptResponse) GetPrompt() *InboundPrompt {
 	if x != nil {
-		return x.Level
+		return x.Prompt
 	}
-	return LogLevel_UNKNOWN
+	return nil
 }
 
-type SetLogLevelResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+// ProbePeersRequest selects the peers to probe, all connected peers if none are given
+type ProbePeersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// peers are peer FQDNs, NetBird IPs or public keys
+	Peers []string `protobuf:"bytes,1,rep,name=peers,proto3" json:"peers,omitempty"`
+	// count is the number of echo probes sent to each peer
+	Count         uint32 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetLogLevelResponse) Reset() {
-	*x = SetLogLevelResponse{}
-	mi := &file_daemon_proto_msgTypes[38]
+func (x *ProbePeersRequest) Reset() {
+	*x = ProbePeersRequest{}
+	mi := &file_daemon_proto_msgTypes[115]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetLogLevelResponse) String() string {
+func (x *ProbePeersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetLogLevelResponse) ProtoMessage() {}
+func (*ProbePeersRequest) ProtoMessage() {}
 
-func (x *SetLogLevelResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[38]
+func (x *ProbePeersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[115]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3045,34 +7852,56 @@ func (x *SetLogLevelResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetLogLevelResponse.ProtoReflect.Descriptor instead.
-func (*SetLogLevelResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{38}
+// Deprecated: Use ProbePeersRequest.ProtoReflect.Descriptor instead.
+func (*ProbePeersRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{115}
 }
 
-// State represents a daemon state entry
-type State struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+func (x *ProbePeersRequest) GetPeers() []string {
+	if x != nil {
+		return x.Peers
+	}
+	return nil
+}
+
+func (x *ProbePeersRequest) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type PeerProbe struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	PubKey   string                 `protobuf:"bytes,1,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
+	Fqdn     string                 `protobuf:"bytes,2,opt,name=fqdn,proto3" json:"fqdn,omitempty"`
+	Ip       string                 `protobuf:"bytes,3,opt,name=ip,proto3" json:"ip,omitempty"`
+	Sent     uint32                 `protobuf:"varint,4,opt,name=sent,proto3" json:"sent,omitempty"`
+	Received uint32                 `protobuf:"varint,5,opt,name=received,proto3" json:"received,omitempty"`
+	RttMin   *durationpb.Duration   `protobuf:"bytes,6,opt,name=rttMin,proto3" json:"rttMin,omitempty"`
+	RttAvg   *durationpb.Duration   `protobuf:"bytes,7,opt,name=rttAvg,proto3" json:"rttAvg,omitempty"`
+	RttMax   *durationpb.Duration   `protobuf:"bytes,8,opt,name=rttMax,proto3" json:"rttMax,omitempty"`
+	// error is why the peer wasn't probed
+	Error         string `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *State) Reset() {
-	*x = State{}
-	mi := &file_daemon_proto_msgTypes[39]
+func (x *PeerProbe) Reset() {
+	*x = PeerProbe{}
+	mi := &file_daemon_proto_msgTypes[116]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *State) String() string {
+func (x *PeerProbe) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*State) ProtoMessage() {}
+func (*PeerProbe) ProtoMessage() {}
 
-func (x *State) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[39]
+func (x *PeerProbe) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[116]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3083,40 +7912,96 @@ func (x *State) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use State.ProtoReflect.Descriptor instead.
-func (*State) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{39}
+// Deprecated: Use PeerProbe.ProtoReflect.Descriptor instead.
+func (*PeerProbe) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{116}
 }
 
-func (x *State) GetName() string {
+func (x *PeerProbe) GetPubKey() string {
 	if x != nil {
-		return x.Name
+		return x.PubKey
 	}
 	return ""
 }
 
-// ListStatesRequest is empty as it requires no parameters
-type ListStatesRequest struct {
+func (x *PeerProbe) GetFqdn() string {
+	if x != nil {
+		return x.Fqdn
+	}
+	return ""
+}
+
+func (x *PeerProbe) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *PeerProbe) GetSent() uint32 {
+	if x != nil {
+		return x.Sent
+	}
+	return 0
+}
+
+func (x *PeerProbe) GetReceived() uint32 {
+	if x != nil {
+		return x.Received
+	}
+	return 0
+}
+
+func (x *PeerProbe) GetRttMin() *durationpb.Duration {
+	if x != nil {
+		return x.RttMin
+	}
+	return nil
+}
+
+func (x *PeerProbe) GetRttAvg() *durationpb.Duration {
+	if x != nil {
+		return x.RttAvg
+	}
+	return nil
+}
+
+func (x *PeerProbe) GetRttMax() *durationpb.Duration {
+	if x != nil {
+		return x.RttMax
+	}
+	return nil
+}
+
+func (x *PeerProbe) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ProbePeersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Peers         []*PeerProbe           `protobuf:"bytes,1,rep,name=peers,proto3" json:"peers,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListStatesRequest) Reset() {
-	*x = ListStatesRequest{}
-	mi := &file_daemon_proto_msgTypes[40]
+func (x *ProbePeersResponse) Reset() {
+	*x = ProbePeersResponse{}
+	mi := &file_daemon_proto_msgTypes[117]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListStatesRequest) String() string {
+func (x *ProbePeersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListStatesRequest) ProtoMessage() {}
+func (*ProbePeersResponse) ProtoMessage() {}
 
-func (x *ListStatesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[40]
+func (x *ProbePeersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[117]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3127,34 +8012,45 @@ func (x *ListStatesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListStatesRequest.ProtoReflect.Descriptor instead.
-func (*ListStatesRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{40}
+// Deprecated: Use ProbePeersResponse.ProtoReflect.Descriptor instead.
+func (*ProbePeersResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{117}
 }
 
-// ListStatesResponse contains a list of states
-type ListStatesResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	States        []*State               `protobuf:"bytes,1,rep,name=states,proto3" json:"states,omitempty"`
+func (x *ProbePeersResponse) GetPeers() []*PeerProbe {
+	if x != nil {
+		return x.Peers
+	}
+	return nil
+}
+
+// APIToken is a token of the monitoring API, only its hash is stored
+type APIToken struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// scopes are the read permissions of the token: status:read, events:read
+	Scopes        []string               `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListStatesResponse) Reset() {
-	*x = ListStatesResponse{}
-	mi := &file_daemon_proto_msgTypes[41]
+func (x *APIToken) Reset() {
+	*x = APIToken{}
+	mi := &file_daemon_proto_msgTypes[118]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListStatesResponse) String() string {
+func (x *APIToken) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListStatesResponse) ProtoMessage() {}
+func (*APIToken) ProtoMessage() {}
 
-func (x *ListStatesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[41]
+func (x *APIToken) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[118]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3165,42 +8061,62 @@ func (x *ListStatesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListStatesResponse.ProtoReflect.Descriptor instead.
-func (*ListStatesResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{41}
+// Deprecated: Use APIToken.ProtoReflect.Descriptor instead.
+func (*APIToken) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{118}
 }
 
-func (x *ListStatesResponse) GetStates() []*State {
+func (x *APIToken) GetId() string {
 	if x != nil {
-		return x.States
+		return x.Id
+	}
+	return ""
+}
+
+func (x *APIToken) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *APIToken) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
 	}
 	return nil
 }
 
-// CleanStateRequest for cleaning states
-type CleanStateRequest struct {
+func (x *APIToken) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CreateAPITokenRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	StateName     string                 `protobuf:"bytes,1,opt,name=state_name,json=stateName,proto3" json:"state_name,omitempty"`
-	All           bool                   `protobuf:"varint,2,opt,name=all,proto3" json:"all,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes        []string               `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CleanStateRequest) Reset() {
-	*x = CleanStateRequest{}
-	mi := &file_daemon_proto_msgTypes[42]
+func (x *CreateAPITokenRequest) Reset() {
+	*x = CreateAPITokenRequest{}
+	mi := &file_daemon_proto_msgTypes[119]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CleanStateRequest) String() string {
+func (x *CreateAPITokenRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CleanStateRequest) ProtoMessage() {}
+func (*CreateAPITokenRequest) ProtoMessage() {}
 
-func (x *CleanStateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[42]
+func (x *CreateAPITokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[119]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3211,48 +8127,49 @@ func (x *CleanStateRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CleanStateRequest.ProtoReflect.Descriptor instead.
-func (*CleanStateRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{42}
+// Deprecated: Use CreateAPITokenRequest.ProtoReflect.Descriptor instead.
+func (*CreateAPITokenRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{119}
 }
 
-func (x *CleanStateRequest) GetStateName() string {
+func (x *CreateAPITokenRequest) GetName() string {
 	if x != nil {
-		return x.StateName
+		return x.Name
 	}
 	return ""
 }
 
-func (x *CleanStateRequest) GetAll() bool {
+func (x *CreateAPITokenRequest) GetScopes() []string {
 	if x != nil {
-		return x.All
+		return x.Scopes
 	}
-	return false
+	return nil
 }
 
-// CleanStateResponse contains the result of the clean operation
-type CleanStateResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	CleanedStates int32                  `protobuf:"varint,1,opt,name=cleaned_states,json=cleanedStates,proto3" json:"cleaned_states,omitempty"`
+type CreateAPITokenResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Token *APIToken              `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// secret is the token to present to the monitoring API
+	Secret        string `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CleanStateResponse) Reset() {
-	*x = CleanStateResponse{}
-	mi := &file_daemon_proto_msgTypes[43]
+func (x *CreateAPITokenResponse) Reset() {
+	*x = CreateAPITokenResponse{}
+	mi := &file_daemon_proto_msgTypes[120]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CleanStateResponse) String() string {
+func (x *CreateAPITokenResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CleanStateResponse) ProtoMessage() {}
+func (*CreateAPITokenResponse) ProtoMessage() {}
 
-func (x *CleanStateResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[43]
+func (x *CreateAPITokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[120]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3263,42 +8180,46 @@ func (x *CleanStateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CleanStateResponse.ProtoReflect.Descriptor instead.
-func (*CleanStateResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{43}
+// Deprecated: Use CreateAPITokenResponse.ProtoReflect.Descriptor instead.
+func (*CreateAPITokenResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{120}
 }
 
-func (x *CleanStateResponse) GetCleanedStates() int32 {
+func (x *CreateAPITokenResponse) GetToken() *APIToken {
 	if x != nil {
-		return x.CleanedStates
+		return x.Token
 	}
-	return 0
+	return nil
 }
 
-// DeleteStateRequest for deleting states
-type DeleteStateRequest struct {
+func (x *CreateAPITokenResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type ListAPITokensRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	StateName     string                 `protobuf:"bytes,1,opt,name=state_name,json=stateName,proto3" json:"state_name,omitempty"`
-	All           bool                   `protobuf:"varint,2,opt,name=all,proto3" json:"all,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
-
-func (x *DeleteStateRequest) Reset() {
-	*x = DeleteStateRequest{}
-	mi := &file_daemon_proto_msgTypes[44]
+
+func (x *ListAPITokensRequest) Reset() {
+	*x = ListAPITokensRequest{}
+	mi := &file_daemon_proto_msgTypes[121]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteStateRequest) String() string {
+func (x *ListAPITokensRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteStateRequest) ProtoMessage() {}
+func (*ListAPITokensRequest) ProtoMessage() {}
 
-func (x *DeleteStateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[44]
+func (x *ListAPITokensRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[121]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3309,48 +8230,33 @@ func (x *DeleteStateRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteStateRequest.ProtoReflect.Descriptor instead.
-func (*DeleteStateRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{44}
-}
-
-func (x *DeleteStateRequest) GetStateName() string {
-	if x != nil {
-		return x.StateName
-	}
-	return ""
-}
-
-func (x *DeleteStateRequest) GetAll() bool {
-	if x != nil {
-		return x.All
-	}
-	return false
+// Deprecated: Use ListAPITokensRequest.ProtoReflect.Descriptor instead.
+func (*ListAPITokensRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{121}
 }
 
-// DeleteStateResponse contains the result of the delete operation
-type DeleteStateResponse struct {
+type ListAPITokensResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	DeletedStates int32                  `protobuf:"varint,1,opt,name=deleted_states,json=deletedStates,proto3" json:"deleted_states,omitempty"`
+	Tokens        []*APIToken            `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteStateResponse) Reset() {
-	*x = DeleteStateResponse{}
-	mi := &file_daemon_proto_msgTypes[45]
+func (x *ListAPITokensResponse) Reset() {
+	*x = ListAPITokensResponse{}
+	mi := &file_daemon_proto_msgTypes[122]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteStateResponse) String() string {
+func (x *ListAPITokensResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteStateResponse) ProtoMessage() {}
+func (*ListAPITokensResponse) ProtoMessage() {}
 
-func (x *DeleteStateResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[45]
+func (x *ListAPITokensResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[122]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3361,40 +8267,41 @@ func (x *DeleteStateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteStateResponse.ProtoReflect.Descriptor instead.
-func (*DeleteStateResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{45}
+// Deprecated: Use ListAPITokensResponse.ProtoReflect.Descriptor instead.
+func (*ListAPITokensResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{122}
 }
 
-func (x *DeleteStateResponse) GetDeletedStates() int32 {
+func (x *ListAPITokensResponse) GetTokens() []*APIToken {
 	if x != nil {
-		return x.DeletedStates
+		return x.Tokens
 	}
-	return 0
+	return nil
 }
 
-type SetSyncResponsePersistenceRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Enabled       bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+type RevokeAPITokenRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// token is the ID or the name of the token
+	Token         string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetSyncResponsePersistenceRequest) Reset() {
-	*x = SetSyncResponsePersistenceRequest{}
-	mi := &file_daemon_proto_msgTypes[46]
+func (x *RevokeAPITokenRequest) Reset() {
+	*x = RevokeAPITokenRequest{}
+	mi := &file_daemon_proto_msgTypes[123]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetSyncResponsePersistenceRequest) String() string {
+func (x *RevokeAPITokenRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetSyncResponsePersistenceRequest) ProtoMessage() {}
+func (*RevokeAPITokenRequest) ProtoMessage() {}
 
-func (x *SetSyncResponsePersistenceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[46]
+func (x *RevokeAPITokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[123]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3405,39 +8312,40 @@ func (x *SetSyncResponsePersistenceRequest) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetSyncResponsePersistenceRequest.ProtoReflect.Descriptor instead.
-func (*SetSyncResponsePersistenceRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{46}
+// Deprecated: Use RevokeAPITokenRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAPITokenRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{123}
 }
 
-func (x *SetSyncResponsePersistenceRequest) GetEnabled() bool {
+func (x *RevokeAPITokenRequest) GetToken() string {
 	if x != nil {
-		return x.Enabled
+		return x.Token
 	}
-	return false
+	return ""
 }
 
-type SetSyncResponsePersistenceResponse struct {
+type RevokeAPITokenResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         *APIToken              `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetSyncResponsePersistenceResponse) Reset() {
-	*x = SetSyncResponsePersistenceResponse{}
-	mi := &file_daemon_proto_msgTypes[47]
+func (x *RevokeAPITokenResponse) Reset() {
+	*x = RevokeAPITokenResponse{}
+	mi := &file_daemon_proto_msgTypes[124]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetSyncResponsePersistenceResponse) String() string {
+func (x *RevokeAPITokenResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetSyncResponsePersistenceResponse) ProtoMessage() {}
+func (*RevokeAPITokenResponse) ProtoMessage() {}
 
-func (x *SetSyncResponsePersistenceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[47]
+func (x *RevokeAPITokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[124]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3448,9 +8356,16 @@ func (x *SetSyncResponsePersistenceResponse) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetSyncResponsePersistenceResponse.ProtoReflect.Descriptor instead.
-func (*SetSyncResponsePersistenceResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{47}
+// Deprecated: Use RevokeAPITokenResponse.ProtoReflect.Descriptor instead.
+func (*RevokeAPITokenResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{124}
+}
+
+func (x *RevokeAPITokenResponse) GetToken() *APIToken {
+	if x != nil {
+		return x.Token
+	}
+	return nil
 }
 
 type TCPFlags struct {
@@ -3467,7 +8382,7 @@ type TCPFlags struct {
 
 func (x *TCPFlags) Reset() {
 	*x = TCPFlags{}
-	mi := &file_daemon_proto_msgTypes[48]
+	mi := &file_daemon_proto_msgTypes[125]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3479,7 +8394,7 @@ func (x *TCPFlags) String() string {
 func (*TCPFlags) ProtoMessage() {}
 
 func (x *TCPFlags) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[48]
+	mi := &file_daemon_proto_msgTypes[125]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3492,7 +8407,7 @@ func (x *TCPFlags) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TCPFlags.ProtoReflect.Descriptor instead.
 func (*TCPFlags) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{48}
+	return file_daemon_proto_rawDescGZIP(), []int{125}
 }
 
 func (x *TCPFlags) GetSyn() bool {
@@ -3554,7 +8469,7 @@ type TracePacketRequest struct {
 
 func (x *TracePacketRequest) Reset() {
 	*x = TracePacketRequest{}
-	mi := &file_daemon_proto_msgTypes[49]
+	mi := &file_daemon_proto_msgTypes[126]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3566,7 +8481,7 @@ func (x *TracePacketRequest) String() string {
 func (*TracePacketRequest) ProtoMessage() {}
 
 func (x *TracePacketRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[49]
+	mi := &file_daemon_proto_msgTypes[126]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3579,7 +8494,7 @@ func (x *TracePacketRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TracePacketRequest.ProtoReflect.Descriptor instead.
 func (*TracePacketRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{49}
+	return file_daemon_proto_rawDescGZIP(), []int{126}
 }
 
 func (x *TracePacketRequest) GetSourceIp() string {
@@ -3657,7 +8572,7 @@ type TraceStage struct {
 
 func (x *TraceStage) Reset() {
 	*x = TraceStage{}
-	mi := &file_daemon_proto_msgTypes[50]
+	mi := &file_daemon_proto_msgTypes[127]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3669,7 +8584,7 @@ func (x *TraceStage) String() string {
 func (*TraceStage) ProtoMessage() {}
 
 func (x *TraceStage) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[50]
+	mi := &file_daemon_proto_msgTypes[127]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3682,7 +8597,7 @@ func (x *TraceStage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TraceStage.ProtoReflect.Descriptor instead.
 func (*TraceStage) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{50}
+	return file_daemon_proto_rawDescGZIP(), []int{127}
 }
 
 func (x *TraceStage) GetName() string {
@@ -3723,7 +8638,7 @@ type TracePacketResponse struct {
 
 func (x *TracePacketResponse) Reset() {
 	*x = TracePacketResponse{}
-	mi := &file_daemon_proto_msgTypes[51]
+	mi := &file_daemon_proto_msgTypes[128]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3735,7 +8650,7 @@ func (x *TracePacketResponse) String() string {
 func (*TracePacketResponse) ProtoMessage() {}
 
 func (x *TracePacketResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[51]
+	mi := &file_daemon_proto_msgTypes[128]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3748,7 +8663,7 @@ func (x *TracePacketResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TracePacketResponse.ProtoReflect.Descriptor instead.
 func (*TracePacketResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{51}
+	return file_daemon_proto_rawDescGZIP(), []int{128}
 }
 
 func (x *TracePacketResponse) GetStages() []*TraceStage {
@@ -3766,14 +8681,18 @@ func (x *TracePacketResponse) GetFinalDisposition() bool {
 }
 
 type SubscribeRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// minSeverity skips events below the given severity
+	MinSeverity SystemEvent_Severity `protobuf:"varint,1,opt,name=minSeverity,proto3,enum=daemon.SystemEvent_Severity" json:"minSeverity,omitempty"`
+	// categories limits the subscription to the given categories, all categories are sent if empty
+	Categories    []SystemEvent_Category `protobuf:"varint,2,rep,packed,name=categories,proto3,enum=daemon.SystemEvent_Category" json:"categories,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SubscribeRequest) Reset() {
 	*x = SubscribeRequest{}
-	mi := &file_daemon_proto_msgTypes[52]
+	mi := &file_daemon_proto_msgTypes[129]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3785,7 +8704,7 @@ func (x *SubscribeRequest) String() string {
 func (*SubscribeRequest) ProtoMessage() {}
 
 func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[52]
+	mi := &file_daemon_proto_msgTypes[129]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3798,25 +8717,43 @@ func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
 func (*SubscribeRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{52}
+	return file_daemon_proto_rawDescGZIP(), []int{129}
+}
+
+func (x *SubscribeRequest) GetMinSeverity() SystemEvent_Severity {
+	if x != nil {
+		return x.MinSeverity
+	}
+	return SystemEvent_INFO
+}
+
+func (x *SubscribeRequest) GetCategories() []SystemEvent_Category {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
 }
 
 type SystemEvent struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Severity      SystemEvent_Severity   `protobuf:"varint,2,opt,name=severity,proto3,enum=daemon.SystemEvent_Severity" json:"severity,omitempty"`
-	Category      SystemEvent_Category   `protobuf:"varint,3,opt,name=category,proto3,enum=daemon.SystemEvent_Category" json:"category,omitempty"`
-	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
-	UserMessage   string                 `protobuf:"bytes,5,opt,name=userMessage,proto3" json:"userMessage,omitempty"`
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Metadata      map[string]string      `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Severity    SystemEvent_Severity   `protobuf:"varint,2,opt,name=severity,proto3,enum=daemon.SystemEvent_Severity" json:"severity,omitempty"`
+	Category    SystemEvent_Category   `protobuf:"varint,3,opt,name=category,proto3,enum=daemon.SystemEvent_Category" json:"category,omitempty"`
+	Message     string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	UserMessage string                 `protobuf:"bytes,5,opt,name=userMessage,proto3" json:"userMessage,omitempty"`
+	Timestamp   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Metadata    map[string]string      `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// dedupKey identifies repeated occurrences of the same event
+	DedupKey string `protobuf:"bytes,8,opt,name=dedupKey,proto3" json:"dedupKey,omitempty"`
+	// suppressed is the number of events with the same dedupKey that were dropped since the previous one was sent
+	Suppressed    uint32 `protobuf:"varint,9,opt,name=suppressed,proto3" json:"suppressed,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SystemEvent) Reset() {
 	*x = SystemEvent{}
-	mi := &file_daemon_proto_msgTypes[53]
+	mi := &file_daemon_proto_msgTypes[130]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3828,7 +8765,7 @@ func (x *SystemEvent) String() string {
 func (*SystemEvent) ProtoMessage() {}
 
 func (x *SystemEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[53]
+	mi := &file_daemon_proto_msgTypes[130]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3841,7 +8778,7 @@ func (x *SystemEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SystemEvent.ProtoReflect.Descriptor instead.
 func (*SystemEvent) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{53}
+	return file_daemon_proto_rawDescGZIP(), []int{130}
 }
 
 func (x *SystemEvent) GetId() string {
@@ -3893,6 +8830,20 @@ func (x *SystemEvent) GetMetadata() map[string]string {
 	return nil
 }
 
+func (x *SystemEvent) GetDedupKey() string {
+	if x != nil {
+		return x.DedupKey
+	}
+	return ""
+}
+
+func (x *SystemEvent) GetSuppressed() uint32 {
+	if x != nil {
+		return x.Suppressed
+	}
+	return 0
+}
+
 type GetEventsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -3901,7 +8852,7 @@ type GetEventsRequest struct {
 
 func (x *GetEventsRequest) Reset() {
 	*x = GetEventsRequest{}
-	mi := &file_daemon_proto_msgTypes[54]
+	mi := &file_daemon_proto_msgTypes[131]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3913,7 +8864,7 @@ func (x *GetEventsRequest) String() string {
 func (*GetEventsRequest) ProtoMessage() {}
 
 func (x *GetEventsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[54]
+	mi := &file_daemon_proto_msgTypes[131]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3926,7 +8877,7 @@ func (x *GetEventsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetEventsRequest.ProtoReflect.Descriptor instead.
 func (*GetEventsRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{54}
+	return file_daemon_proto_rawDescGZIP(), []int{131}
 }
 
 type GetEventsResponse struct {
@@ -3938,7 +8889,7 @@ type GetEventsResponse struct {
 
 func (x *GetEventsResponse) Reset() {
 	*x = GetEventsResponse{}
-	mi := &file_daemon_proto_msgTypes[55]
+	mi := &file_daemon_proto_msgTypes[132]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3950,7 +8901,7 @@ func (x *GetEventsResponse) String() string {
 func (*GetEventsResponse) ProtoMessage() {}
 
 func (x *GetEventsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[55]
+	mi := &file_daemon_proto_msgTypes[132]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3963,7 +8914,7 @@ func (x *GetEventsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetEventsResponse.ProtoReflect.Descriptor instead.
 func (*GetEventsResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{55}
+	return file_daemon_proto_rawDescGZIP(), []int{132}
 }
 
 func (x *GetEventsResponse) GetEvents() []*SystemEvent {
@@ -3983,7 +8934,7 @@ type SwitchProfileRequest struct {
 
 func (x *SwitchProfileRequest) Reset() {
 	*x = SwitchProfileRequest{}
-	mi := &file_daemon_proto_msgTypes[56]
+	mi := &file_daemon_proto_msgTypes[133]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3995,7 +8946,7 @@ func (x *SwitchProfileRequest) String() string {
 func (*SwitchProfileRequest) ProtoMessage() {}
 
 func (x *SwitchProfileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[56]
+	mi := &file_daemon_proto_msgTypes[133]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4008,7 +8959,7 @@ func (x *SwitchProfileRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SwitchProfileRequest.ProtoReflect.Descriptor instead.
 func (*SwitchProfileRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{56}
+	return file_daemon_proto_rawDescGZIP(), []int{133}
 }
 
 func (x *SwitchProfileRequest) GetProfileName() string {
@@ -4033,7 +8984,7 @@ type SwitchProfileResponse struct {
 
 func (x *SwitchProfileResponse) Reset() {
 	*x = SwitchProfileResponse{}
-	mi := &file_daemon_proto_msgTypes[57]
+	mi := &file_daemon_proto_msgTypes[134]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4045,7 +8996,7 @@ func (x *SwitchProfileResponse) String() string {
 func (*SwitchProfileResponse) ProtoMessage() {}
 
 func (x *SwitchProfileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[57]
+	mi := &file_daemon_proto_msgTypes[134]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4058,7 +9009,7 @@ func (x *SwitchProfileResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SwitchProfileResponse.ProtoReflect.Descriptor instead.
 func (*SwitchProfileResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{57}
+	return file_daemon_proto_rawDescGZIP(), []int{134}
 }
 
 type SetConfigRequest struct {
@@ -4100,13 +9051,24 @@ type SetConfigRequest struct {
 	EnableSSHRemotePortForwarding *bool                `protobuf:"varint,32,opt,name=enableSSHRemotePortForwarding,proto3,oneof" json:"enableSSHRemotePortForwarding,omitempty"`
 	DisableSSHAuth                *bool                `protobuf:"varint,33,opt,name=disableSSHAuth,proto3,oneof" json:"disableSSHAuth,omitempty"`
 	SshJWTCacheTTL                *int32               `protobuf:"varint,34,opt,name=sshJWTCacheTTL,proto3,oneof" json:"sshJWTCacheTTL,omitempty"`
-	unknownFields                 protoimpl.UnknownFields
-	sizeCache                     protoimpl.SizeCache
+	KillSwitch                    *bool                `protobuf:"varint,35,opt,name=kill_switch,json=killSwitch,proto3,oneof" json:"kill_switch,omitempty"`
+	// socks5ProxyAddress and httpProxyAddress expose the tunnel through local proxies in netstack mode.
+	// An empty address disables the proxy.
+	Socks5ProxyAddress *string `protobuf:"bytes,36,opt,name=socks5ProxyAddress,proto3,oneof" json:"socks5ProxyAddress,omitempty"`
+	HttpProxyAddress   *string `protobuf:"bytes,37,opt,name=httpProxyAddress,proto3,oneof" json:"httpProxyAddress,omitempty"`
+	// iceMulticastDNS hides the host candidate IPs from peers behind random mDNS names
+	IceMulticastDNS *bool `protobuf:"varint,38,opt,name=iceMulticastDNS,proto3,oneof" json:"iceMulticastDNS,omitempty"`
+	// icePolicy restricts the ICE candidate types: all, relay-only, no-relay or host-only
+	IcePolicy *string `protobuf:"bytes,39,opt,name=icePolicy,proto3,oneof" json:"icePolicy,omitempty"`
+	// firewallBackend selects the firewall implementation: auto, iptables, nftables, userspace or none
+	FirewallBackend *string `protobuf:"bytes,40,opt,name=firewallBackend,proto3,oneof" json:"firewallBackend,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *SetConfigRequest) Reset() {
 	*x = SetConfigRequest{}
-	mi := &file_daemon_proto_msgTypes[58]
+	mi := &file_daemon_proto_msgTypes[135]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4118,7 +9080,7 @@ func (x *SetConfigRequest) String() string {
 func (*SetConfigRequest) ProtoMessage() {}
 
 func (x *SetConfigRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[58]
+	mi := &file_daemon_proto_msgTypes[135]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4131,7 +9093,7 @@ func (x *SetConfigRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetConfigRequest.ProtoReflect.Descriptor instead.
 func (*SetConfigRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{58}
+	return file_daemon_proto_rawDescGZIP(), []int{135}
 }
 
 func (x *SetConfigRequest) GetUsername() string {
@@ -4372,6 +9334,48 @@ func (x *SetConfigRequest) GetSshJWTCacheTTL() int32 {
 	return 0
 }
 
+func (x *SetConfigRequest) GetKillSwitch() bool {
+	if x != nil && x.KillSwitch != nil {
+		return *x.KillSwitch
+	}
+	return false
+}
+
+func (x *SetConfigRequest) GetSocks5ProxyAddress() string {
+	if x != nil && x.Socks5ProxyAddress != nil {
+		return *x.Socks5ProxyAddress
+	}
+	return ""
+}
+
+func (x *SetConfigRequest) GetHttpProxyAddress() string {
+	if x != nil && x.HttpProxyAddress != nil {
+		return *x.HttpProxyAddress
+	}
+	return ""
+}
+
+func (x *SetConfigRequest) GetIceMulticastDNS() bool {
+	if x != nil && x.IceMulticastDNS != nil {
+		return *x.IceMulticastDNS
+	}
+	return false
+}
+
+func (x *SetConfigRequest) GetIcePolicy() string {
+	if x != nil && x.IcePolicy != nil {
+		return *x.IcePolicy
+	}
+	return ""
+}
+
+func (x *SetConfigRequest) GetFirewallBackend() string {
+	if x != nil && x.FirewallBackend != nil {
+		return *x.FirewallBackend
+	}
+	return ""
+}
+
 type SetConfigResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -4380,7 +9384,7 @@ type SetConfigResponse struct {
 
 func (x *SetConfigResponse) Reset() {
 	*x = SetConfigResponse{}
-	mi := &file_daemon_proto_msgTypes[59]
+	mi := &file_daemon_proto_msgTypes[136]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4392,7 +9396,7 @@ func (x *SetConfigResponse) String() string {
 func (*SetConfigResponse) ProtoMessage() {}
 
 func (x *SetConfigResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[59]
+	mi := &file_daemon_proto_msgTypes[136]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4405,7 +9409,7 @@ func (x *SetConfigResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetConfigResponse.ProtoReflect.Descriptor instead.
 func (*SetConfigResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{59}
+	return file_daemon_proto_rawDescGZIP(), []int{136}
 }
 
 type AddProfileRequest struct {
@@ -4418,7 +9422,7 @@ type AddProfileRequest struct {
 
 func (x *AddProfileRequest) Reset() {
 	*x = AddProfileRequest{}
-	mi := &file_daemon_proto_msgTypes[60]
+	mi := &file_daemon_proto_msgTypes[137]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4430,7 +9434,7 @@ func (x *AddProfileRequest) String() string {
 func (*AddProfileRequest) ProtoMessage() {}
 
 func (x *AddProfileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[60]
+	mi := &file_daemon_proto_msgTypes[137]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4443,7 +9447,7 @@ func (x *AddProfileRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddProfileRequest.ProtoReflect.Descriptor instead.
 func (*AddProfileRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{60}
+	return file_daemon_proto_rawDescGZIP(), []int{137}
 }
 
 func (x *AddProfileRequest) GetUsername() string {
@@ -4468,7 +9472,7 @@ type AddProfileResponse struct {
 
 func (x *AddProfileResponse) Reset() {
 	*x = AddProfileResponse{}
-	mi := &file_daemon_proto_msgTypes[61]
+	mi := &file_daemon_proto_msgTypes[138]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4480,7 +9484,7 @@ func (x *AddProfileResponse) String() string {
 func (*AddProfileResponse) ProtoMessage() {}
 
 func (x *AddProfileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[61]
+	mi := &file_daemon_proto_msgTypes[138]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4493,7 +9497,7 @@ func (x *AddProfileResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddProfileResponse.ProtoReflect.Descriptor instead.
 func (*AddProfileResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{61}
+	return file_daemon_proto_rawDescGZIP(), []int{138}
 }
 
 type RemoveProfileRequest struct {
@@ -4506,7 +9510,7 @@ type RemoveProfileRequest struct {
 
 func (x *RemoveProfileRequest) Reset() {
 	*x = RemoveProfileRequest{}
-	mi := &file_daemon_proto_msgTypes[62]
+	mi := &file_daemon_proto_msgTypes[139]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4518,7 +9522,7 @@ func (x *RemoveProfileRequest) String() string {
 func (*RemoveProfileRequest) ProtoMessage() {}
 
 func (x *RemoveProfileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[62]
+	mi := &file_daemon_proto_msgTypes[139]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4531,7 +9535,7 @@ func (x *RemoveProfileRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveProfileRequest.ProtoReflect.Descriptor instead.
 func (*RemoveProfileRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{62}
+	return file_daemon_proto_rawDescGZIP(), []int{139}
 }
 
 func (x *RemoveProfileRequest) GetUsername() string {
@@ -4556,7 +9560,7 @@ type RemoveProfileResponse struct {
 
 func (x *RemoveProfileResponse) Reset() {
 	*x = RemoveProfileResponse{}
-	mi := &file_daemon_proto_msgTypes[63]
+	mi := &file_daemon_proto_msgTypes[140]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4568,7 +9572,7 @@ func (x *RemoveProfileResponse) String() string {
 func (*RemoveProfileResponse) ProtoMessage() {}
 
 func (x *RemoveProfileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[63]
+	mi := &file_daemon_proto_msgTypes[140]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4581,7 +9585,7 @@ func (x *RemoveProfileResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveProfileResponse.ProtoReflect.Descriptor instead.
 func (*RemoveProfileResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{63}
+	return file_daemon_proto_rawDescGZIP(), []int{140}
 }
 
 type ListProfilesRequest struct {
@@ -4593,7 +9597,7 @@ type ListProfilesRequest struct {
 
 func (x *ListProfilesRequest) Reset() {
 	*x = ListProfilesRequest{}
-	mi := &file_daemon_proto_msgTypes[64]
+	mi := &file_daemon_proto_msgTypes[141]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4605,7 +9609,7 @@ func (x *ListProfilesRequest) String() string {
 func (*ListProfilesRequest) ProtoMessage() {}
 
 func (x *ListProfilesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[64]
+	mi := &file_daemon_proto_msgTypes[141]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4618,7 +9622,7 @@ func (x *ListProfilesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListProfilesRequest.ProtoReflect.Descriptor instead.
 func (*ListProfilesRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{64}
+	return file_daemon_proto_rawDescGZIP(), []int{141}
 }
 
 func (x *ListProfilesRequest) GetUsername() string {
@@ -4637,7 +9641,7 @@ type ListProfilesResponse struct {
 
 func (x *ListProfilesResponse) Reset() {
 	*x = ListProfilesResponse{}
-	mi := &file_daemon_proto_msgTypes[65]
+	mi := &file_daemon_proto_msgTypes[142]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4649,7 +9653,7 @@ func (x *ListProfilesResponse) String() string {
 func (*ListProfilesResponse) ProtoMessage() {}
 
 func (x *ListProfilesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[65]
+	mi := &file_daemon_proto_msgTypes[142]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4662,7 +9666,7 @@ func (x *ListProfilesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListProfilesResponse.ProtoReflect.Descriptor instead.
 func (*ListProfilesResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{65}
+	return file_daemon_proto_rawDescGZIP(), []int{142}
 }
 
 func (x *ListProfilesResponse) GetProfiles() []*Profile {
@@ -4682,7 +9686,7 @@ type Profile struct {
 
 func (x *Profile) Reset() {
 	*x = Profile{}
-	mi := &file_daemon_proto_msgTypes[66]
+	mi := &file_daemon_proto_msgTypes[143]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4694,7 +9698,7 @@ func (x *Profile) String() string {
 func (*Profile) ProtoMessage() {}
 
 func (x *Profile) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[66]
+	mi := &file_daemon_proto_msgTypes[143]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4707,7 +9711,7 @@ func (x *Profile) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Profile.ProtoReflect.Descriptor instead.
 func (*Profile) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{66}
+	return file_daemon_proto_rawDescGZIP(), []int{143}
 }
 
 func (x *Profile) GetName() string {
@@ -4732,7 +9736,7 @@ type GetActiveProfileRequest struct {
 
 func (x *GetActiveProfileRequest) Reset() {
 	*x = GetActiveProfileRequest{}
-	mi := &file_daemon_proto_msgTypes[67]
+	mi := &file_daemon_proto_msgTypes[144]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4744,7 +9748,7 @@ func (x *GetActiveProfileRequest) String() string {
 func (*GetActiveProfileRequest) ProtoMessage() {}
 
 func (x *GetActiveProfileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[67]
+	mi := &file_daemon_proto_msgTypes[144]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4757,7 +9761,7 @@ func (x *GetActiveProfileRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetActiveProfileRequest.ProtoReflect.Descriptor instead.
 func (*GetActiveProfileRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{67}
+	return file_daemon_proto_rawDescGZIP(), []int{144}
 }
 
 type GetActiveProfileResponse struct {
@@ -4770,7 +9774,7 @@ type GetActiveProfileResponse struct {
 
 func (x *GetActiveProfileResponse) Reset() {
 	*x = GetActiveProfileResponse{}
-	mi := &file_daemon_proto_msgTypes[68]
+	mi := &file_daemon_proto_msgTypes[145]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4782,7 +9786,7 @@ func (x *GetActiveProfileResponse) String() string {
 func (*GetActiveProfileResponse) ProtoMessage() {}
 
 func (x *GetActiveProfileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[68]
+	mi := &file_daemon_proto_msgTypes[145]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4795,7 +9799,7 @@ func (x *GetActiveProfileResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetActiveProfileResponse.ProtoReflect.Descriptor instead.
 func (*GetActiveProfileResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{68}
+	return file_daemon_proto_rawDescGZIP(), []int{145}
 }
 
 func (x *GetActiveProfileResponse) GetProfileName() string {
@@ -4822,7 +9826,7 @@ type LogoutRequest struct {
 
 func (x *LogoutRequest) Reset() {
 	*x = LogoutRequest{}
-	mi := &file_daemon_proto_msgTypes[69]
+	mi := &file_daemon_proto_msgTypes[146]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4834,7 +9838,7 @@ func (x *LogoutRequest) String() string {
 func (*LogoutRequest) ProtoMessage() {}
 
 func (x *LogoutRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[69]
+	mi := &file_daemon_proto_msgTypes[146]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4847,7 +9851,7 @@ func (x *LogoutRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogoutRequest.ProtoReflect.Descriptor instead.
 func (*LogoutRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{69}
+	return file_daemon_proto_rawDescGZIP(), []int{146}
 }
 
 func (x *LogoutRequest) GetProfileName() string {
@@ -4872,7 +9876,7 @@ type LogoutResponse struct {
 
 func (x *LogoutResponse) Reset() {
 	*x = LogoutResponse{}
-	mi := &file_daemon_proto_msgTypes[70]
+	mi := &file_daemon_proto_msgTypes[147]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4884,7 +9888,7 @@ func (x *LogoutResponse) String() string {
 func (*LogoutResponse) ProtoMessage() {}
 
 func (x *LogoutResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[70]
+	mi := &file_daemon_proto_msgTypes[147]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4897,7 +9901,7 @@ func (x *LogoutResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogoutResponse.ProtoReflect.Descriptor instead.
 func (*LogoutResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{70}
+	return file_daemon_proto_rawDescGZIP(), []int{147}
 }
 
 type GetFeaturesRequest struct {
@@ -4908,7 +9912,7 @@ type GetFeaturesRequest struct {
 
 func (x *GetFeaturesRequest) Reset() {
 	*x = GetFeaturesRequest{}
-	mi := &file_daemon_proto_msgTypes[71]
+	mi := &file_daemon_proto_msgTypes[148]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4920,7 +9924,7 @@ func (x *GetFeaturesRequest) String() string {
 func (*GetFeaturesRequest) ProtoMessage() {}
 
 func (x *GetFeaturesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[71]
+	mi := &file_daemon_proto_msgTypes[148]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4933,7 +9937,7 @@ func (x *GetFeaturesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetFeaturesRequest.ProtoReflect.Descriptor instead.
 func (*GetFeaturesRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{71}
+	return file_daemon_proto_rawDescGZIP(), []int{148}
 }
 
 type GetFeaturesResponse struct {
@@ -4946,7 +9950,7 @@ type GetFeaturesResponse struct {
 
 func (x *GetFeaturesResponse) Reset() {
 	*x = GetFeaturesResponse{}
-	mi := &file_daemon_proto_msgTypes[72]
+	mi := &file_daemon_proto_msgTypes[149]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4958,7 +9962,7 @@ func (x *GetFeaturesResponse) String() string {
 func (*GetFeaturesResponse) ProtoMessage() {}
 
 func (x *GetFeaturesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[72]
+	mi := &file_daemon_proto_msgTypes[149]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4971,7 +9975,7 @@ func (x *GetFeaturesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetFeaturesResponse.ProtoReflect.Descriptor instead.
 func (*GetFeaturesResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{72}
+	return file_daemon_proto_rawDescGZIP(), []int{149}
 }
 
 func (x *GetFeaturesResponse) GetDisableProfiles() bool {
@@ -4999,7 +10003,7 @@ type GetPeerSSHHostKeyRequest struct {
 
 func (x *GetPeerSSHHostKeyRequest) Reset() {
 	*x = GetPeerSSHHostKeyRequest{}
-	mi := &file_daemon_proto_msgTypes[73]
+	mi := &file_daemon_proto_msgTypes[150]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5011,7 +10015,7 @@ func (x *GetPeerSSHHostKeyRequest) String() string {
 func (*GetPeerSSHHostKeyRequest) ProtoMessage() {}
 
 func (x *GetPeerSSHHostKeyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[73]
+	mi := &file_daemon_proto_msgTypes[150]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5024,7 +10028,7 @@ func (x *GetPeerSSHHostKeyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPeerSSHHostKeyRequest.ProtoReflect.Descriptor instead.
 func (*GetPeerSSHHostKeyRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{73}
+	return file_daemon_proto_rawDescGZIP(), []int{150}
 }
 
 func (x *GetPeerSSHHostKeyRequest) GetPeerAddress() string {
@@ -5051,7 +10055,7 @@ type GetPeerSSHHostKeyResponse struct {
 
 func (x *GetPeerSSHHostKeyResponse) Reset() {
 	*x = GetPeerSSHHostKeyResponse{}
-	mi := &file_daemon_proto_msgTypes[74]
+	mi := &file_daemon_proto_msgTypes[151]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5063,7 +10067,7 @@ func (x *GetPeerSSHHostKeyResponse) String() string {
 func (*GetPeerSSHHostKeyResponse) ProtoMessage() {}
 
 func (x *GetPeerSSHHostKeyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[74]
+	mi := &file_daemon_proto_msgTypes[151]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5076,7 +10080,7 @@ func (x *GetPeerSSHHostKeyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPeerSSHHostKeyResponse.ProtoReflect.Descriptor instead.
 func (*GetPeerSSHHostKeyResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{74}
+	return file_daemon_proto_rawDescGZIP(), []int{151}
 }
 
 func (x *GetPeerSSHHostKeyResponse) GetSshHostKey() []byte {
@@ -5118,7 +10122,7 @@ type RequestJWTAuthRequest struct {
 
 func (x *RequestJWTAuthRequest) Reset() {
 	*x = RequestJWTAuthRequest{}
-	mi := &file_daemon_proto_msgTypes[75]
+	mi := &file_daemon_proto_msgTypes[152]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5130,7 +10134,7 @@ func (x *RequestJWTAuthRequest) String() string {
 func (*RequestJWTAuthRequest) ProtoMessage() {}
 
 func (x *RequestJWTAuthRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[75]
+	mi := &file_daemon_proto_msgTypes[152]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5143,7 +10147,7 @@ func (x *RequestJWTAuthRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RequestJWTAuthRequest.ProtoReflect.Descriptor instead.
 func (*RequestJWTAuthRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{75}
+	return file_daemon_proto_rawDescGZIP(), []int{152}
 }
 
 func (x *RequestJWTAuthRequest) GetHint() string {
@@ -5176,7 +10180,7 @@ type RequestJWTAuthResponse struct {
 
 func (x *RequestJWTAuthResponse) Reset() {
 	*x = RequestJWTAuthResponse{}
-	mi := &file_daemon_proto_msgTypes[76]
+	mi := &file_daemon_proto_msgTypes[153]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5188,7 +10192,7 @@ func (x *RequestJWTAuthResponse) String() string {
 func (*RequestJWTAuthResponse) ProtoMessage() {}
 
 func (x *RequestJWTAuthResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[76]
+	mi := &file_daemon_proto_msgTypes[153]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5201,7 +10205,7 @@ func (x *RequestJWTAuthResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RequestJWTAuthResponse.ProtoReflect.Descriptor instead.
 func (*RequestJWTAuthResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{76}
+	return file_daemon_proto_rawDescGZIP(), []int{153}
 }
 
 func (x *RequestJWTAuthResponse) GetVerificationURI() string {
@@ -5266,7 +10270,7 @@ type WaitJWTTokenRequest struct {
 
 func (x *WaitJWTTokenRequest) Reset() {
 	*x = WaitJWTTokenRequest{}
-	mi := &file_daemon_proto_msgTypes[77]
+	mi := &file_daemon_proto_msgTypes[154]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5278,7 +10282,7 @@ func (x *WaitJWTTokenRequest) String() string {
 func (*WaitJWTTokenRequest) ProtoMessage() {}
 
 func (x *WaitJWTTokenRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[77]
+	mi := &file_daemon_proto_msgTypes[154]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5291,7 +10295,7 @@ func (x *WaitJWTTokenRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WaitJWTTokenRequest.ProtoReflect.Descriptor instead.
 func (*WaitJWTTokenRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{77}
+	return file_daemon_proto_rawDescGZIP(), []int{154}
 }
 
 func (x *WaitJWTTokenRequest) GetDeviceCode() string {
@@ -5323,7 +10327,7 @@ type WaitJWTTokenResponse struct {
 
 func (x *WaitJWTTokenResponse) Reset() {
 	*x = WaitJWTTokenResponse{}
-	mi := &file_daemon_proto_msgTypes[78]
+	mi := &file_daemon_proto_msgTypes[155]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5335,7 +10339,7 @@ func (x *WaitJWTTokenResponse) String() string {
 func (*WaitJWTTokenResponse) ProtoMessage() {}
 
 func (x *WaitJWTTokenResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[78]
+	mi := &file_daemon_proto_msgTypes[155]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5348,7 +10352,7 @@ func (x *WaitJWTTokenResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WaitJWTTokenResponse.ProtoReflect.Descriptor instead.
 func (*WaitJWTTokenResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{78}
+	return file_daemon_proto_rawDescGZIP(), []int{155}
 }
 
 func (x *WaitJWTTokenResponse) GetToken() string {
@@ -5380,7 +10384,7 @@ type InstallerResultRequest struct {
 
 func (x *InstallerResultRequest) Reset() {
 	*x = InstallerResultRequest{}
-	mi := &file_daemon_proto_msgTypes[79]
+	mi := &file_daemon_proto_msgTypes[156]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5392,7 +10396,7 @@ func (x *InstallerResultRequest) String() string {
 func (*InstallerResultRequest) ProtoMessage() {}
 
 func (x *InstallerResultRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[79]
+	mi := &file_daemon_proto_msgTypes[156]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5405,7 +10409,7 @@ func (x *InstallerResultRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InstallerResultRequest.ProtoReflect.Descriptor instead.
 func (*InstallerResultRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{79}
+	return file_daemon_proto_rawDescGZIP(), []int{156}
 }
 
 type InstallerResultResponse struct {
@@ -5418,7 +10422,7 @@ type InstallerResultResponse struct {
 
 func (x *InstallerResultResponse) Reset() {
 	*x = InstallerResultResponse{}
-	mi := &file_daemon_proto_msgTypes[80]
+	mi := &file_daemon_proto_msgTypes[157]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5430,7 +10434,7 @@ func (x *InstallerResultResponse) String() string {
 func (*InstallerResultResponse) ProtoMessage() {}
 
 func (x *InstallerResultResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[80]
+	mi := &file_daemon_proto_msgTypes[157]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5443,7 +10447,7 @@ func (x *InstallerResultResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InstallerResultResponse.ProtoReflect.Descriptor instead.
 func (*InstallerResultResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{80}
+	return file_daemon_proto_rawDescGZIP(), []int{157}
 }
 
 func (x *InstallerResultResponse) GetSuccess() bool {
@@ -5470,7 +10474,7 @@ type PortInfo_Range struct {
 
 func (x *PortInfo_Range) Reset() {
 	*x = PortInfo_Range{}
-	mi := &file_daemon_proto_msgTypes[82]
+	mi := &file_daemon_proto_msgTypes[159]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5482,7 +10486,7 @@ func (x *PortInfo_Range) String() string {
 func (*PortInfo_Range) ProtoMessage() {}
 
 func (x *PortInfo_Range) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_msgTypes[82]
+	mi := &file_daemon_proto_msgTypes[159]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5495,7 +10499,7 @@ func (x *PortInfo_Range) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PortInfo_Range.ProtoReflect.Descriptor instead.
 func (*PortInfo_Range) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_rawDescGZIP(), []int{30, 0}
+	return file_daemon_proto_rawDescGZIP(), []int{43, 0}
 }
 
 func (x *PortInfo_Range) GetStart() uint32 {
@@ -5525,7 +10529,7 @@ const file_daemon_proto_rawDesc = "" +
 	"\x05SLEEP\x10\x01\x12\n" +
 	"\n" +
 	"\x06WAKEUP\x10\x02\"\x15\n" +
-	"\x13OSLifecycleResponse\"\xb6\x12\n" +
+	"\x13OSLifecycleResponse\"\xb5\x15\n" +
 	"\fLoginRequest\x12\x1a\n" +
 	"\bsetupKey\x18\x01 \x01(\tR\bsetupKey\x12&\n" +
 	"\fpreSharedKey\x18\x02 \x01(\tB\x02\x18\x01R\fpreSharedKey\x12$\n" +
@@ -5569,7 +10573,14 @@ const file_daemon_proto_rawDesc = "" +
 	"\x1cenableSSHLocalPortForwarding\x18$ \x01(\bH\x17R\x1cenableSSHLocalPortForwarding\x88\x01\x01\x12I\n" +
 	"\x1denableSSHRemotePortForwarding\x18% \x01(\bH\x18R\x1denableSSHRemotePortForwarding\x88\x01\x01\x12+\n" +
 	"\x0edisableSSHAuth\x18& \x01(\bH\x19R\x0edisableSSHAuth\x88\x01\x01\x12+\n" +
-	"\x0esshJWTCacheTTL\x18' \x01(\x05H\x1aR\x0esshJWTCacheTTL\x88\x01\x01B\x13\n" +
+	"\x0esshJWTCacheTTL\x18' \x01(\x05H\x1aR\x0esshJWTCacheTTL\x88\x01\x01\x12$\n" +
+	"\vkill_switch\x18( \x01(\bH\x1bR\n" +
+	"killSwitch\x88\x01\x01\x123\n" +
+	"\x12socks5ProxyAddress\x18) \x01(\tH\x1cR\x12socks5ProxyAddress\x88\x01\x01\x12/\n" +
+	"\x10httpProxyAddress\x18* \x01(\tH\x1dR\x10httpProxyAddress\x88\x01\x01\x12-\n" +
+	"\x0ficeMulticastDNS\x18+ \x01(\bH\x1eR\x0ficeMulticastDNS\x88\x01\x01\x12!\n" +
+	"\ticePolicy\x18, \x01(\tH\x1fR\ticePolicy\x88\x01\x01\x12-\n" +
+	"\x0ffirewallBackend\x18- \x01(\tH R\x0ffirewallBackend\x88\x01\x01B\x13\n" +
 	"\x11_rosenpassEnabledB\x10\n" +
 	"\x0e_interfaceNameB\x10\n" +
 	"\x0e_wireguardPortB\x17\n" +
@@ -5596,7 +10607,14 @@ const file_daemon_proto_rawDesc = "" +
 	"\x1d_enableSSHLocalPortForwardingB \n" +
 	"\x1e_enableSSHRemotePortForwardingB\x11\n" +
 	"\x0f_disableSSHAuthB\x11\n" +
-	"\x0f_sshJWTCacheTTL\"\xb5\x01\n" +
+	"\x0f_sshJWTCacheTTLB\x0e\n" +
+	"\f_kill_switchB\x15\n" +
+	"\x13_socks5ProxyAddressB\x13\n" +
+	"\x11_httpProxyAddressB\x12\n" +
+	"\x10_iceMulticastDNSB\f\n" +
+	"\n" +
+	"_icePolicyB\x12\n" +
+	"\x10_firewallBackend\"\xb5\x01\n" +
 	"\rLoginResponse\x12$\n" +
 	"\rneedsSSOLogin\x18\x01 \x01(\bR\rneedsSSOLogin\x12\x1a\n" +
 	"\buserCode\x18\x02 \x01(\tR\buserCode\x12(\n" +
@@ -5617,12 +10635,32 @@ const file_daemon_proto_rawDesc = "" +
 	"\t_usernameB\r\n" +
 	"\v_autoUpdate\"\f\n" +
 	"\n" +
-	"UpResponse\"\xa1\x01\n" +
+	"UpResponse\"\xe4\x01\n" +
 	"\rStatusRequest\x12,\n" +
 	"\x11getFullPeerStatus\x18\x01 \x01(\bR\x11getFullPeerStatus\x12(\n" +
 	"\x0fshouldRunProbes\x18\x02 \x01(\bR\x0fshouldRunProbes\x12'\n" +
-	"\fwaitForReady\x18\x03 \x01(\bH\x00R\fwaitForReady\x88\x01\x01B\x0f\n" +
-	"\r_waitForReady\"\x82\x01\n" +
+	"\fwaitForReady\x18\x03 \x01(\bH\x00R\fwaitForReady\x88\x01\x01\x12A\n" +
+	"\x0fpeerListOptions\x18\x04 \x01(\v2\x17.daemon.PeerListOptionsR\x0fpeerListOptionsB\x0f\n" +
+	"\r_waitForReady\"\xe9\x02\n" +
+	"\x0fPeerListOptions\x12\x1a\n" +
+	"\bstatuses\x18\x01 \x03(\tR\bstatuses\x12\x14\n" +
+	"\x05names\x18\x02 \x03(\tR\x05names\x12\x10\n" +
+	"\x03ips\x18\x03 \x03(\tR\x03ips\x12&\n" +
+	"\x0econnectionType\x18\x04 \x01(\tR\x0econnectionType\x12\x16\n" +
+	"\x06search\x18\x05 \x01(\tR\x06search\x129\n" +
+	"\x06sortBy\x18\x06 \x01(\x0e2!.daemon.PeerListOptions.SortFieldR\x06sortBy\x12\x1e\n" +
+	"\n" +
+	"descending\x18\a \x01(\bR\n" +
+	"descending\x12\x16\n" +
+	"\x06offset\x18\b \x01(\rR\x06offset\x12\x14\n" +
+	"\x05limit\x18\t \x01(\rR\x05limit\"I\n" +
+	"\tSortField\x12\x06\n" +
+	"\x02IP\x10\x00\x12\b\n" +
+	"\x04FQDN\x10\x01\x12\n" +
+	"\n" +
+	"\x06STATUS\x10\x02\x12\v\n" +
+	"\aLATENCY\x10\x03\x12\x11\n" +
+	"\rSTATUS_UPDATE\x10\x04\"\x82\x01\n" +
 	"\x0eStatusResponse\x12\x16\n" +
 	"\x06status\x18\x01 \x01(\tR\x06status\x122\n" +
 	"\n" +
@@ -5633,7 +10671,8 @@ const file_daemon_proto_rawDesc = "" +
 	"\fDownResponse\"P\n" +
 	"\x10GetConfigRequest\x12 \n" +
 	"\vprofileName\x18\x01 \x01(\tR\vprofileName\x12\x1a\n" +
-	"\busername\x18\x02 \x01(\tR\busername\"\xdb\b\n" +
+	"\busername\x18\x02 \x01(\tR\busername\"\xca\n" +
+	"\n" +
 	"\x11GetConfigResponse\x12$\n" +
 	"\rmanagementUrl\x18\x01 \x01(\tR\rmanagementUrl\x12\x1e\n" +
 	"\n" +
@@ -5664,7 +10703,14 @@ const file_daemon_proto_rawDesc = "" +
 	"\x1cenableSSHLocalPortForwarding\x18\x16 \x01(\bR\x1cenableSSHLocalPortForwarding\x12D\n" +
 	"\x1denableSSHRemotePortForwarding\x18\x17 \x01(\bR\x1denableSSHRemotePortForwarding\x12&\n" +
 	"\x0edisableSSHAuth\x18\x19 \x01(\bR\x0edisableSSHAuth\x12&\n" +
-	"\x0esshJWTCacheTTL\x18\x1a \x01(\x05R\x0esshJWTCacheTTL\"\xfe\x05\n" +
+	"\x0esshJWTCacheTTL\x18\x1a \x01(\x05R\x0esshJWTCacheTTL\x12\x1f\n" +
+	"\vkill_switch\x18\x1b \x01(\bR\n" +
+	"killSwitch\x12.\n" +
+	"\x12socks5ProxyAddress\x18\x1c \x01(\tR\x12socks5ProxyAddress\x12*\n" +
+	"\x10httpProxyAddress\x18\x1d \x01(\tR\x10httpProxyAddress\x12(\n" +
+	"\x0ficeMulticastDNS\x18\x1e \x01(\bR\x0ficeMulticastDNS\x12\x1c\n" +
+	"\ticePolicy\x18\x1f \x01(\tR\ticePolicy\x12(\n" +
+	"\x0ffirewallBackend\x18  \x01(\tR\x0ffirewallBackend\"\xcf\b\n" +
 	"\tPeerState\x12\x0e\n" +
 	"\x02IP\x18\x01 \x01(\tR\x02IP\x12\x16\n" +
 	"\x06pubKey\x18\x02 \x01(\tR\x06pubKey\x12\x1e\n" +
@@ -5688,7 +10734,21 @@ const file_daemon_proto_rawDesc = "" +
 	"\frelayAddress\x18\x12 \x01(\tR\frelayAddress\x12\x1e\n" +
 	"\n" +
 	"sshHostKey\x18\x13 \x01(\fR\n" +
-	"sshHostKey\"\xf0\x01\n" +
+	"sshHostKey\x12 \n" +
+	"\vmaintenance\x18\x14 \x01(\bR\vmaintenance\x12\x1e\n" +
+	"\n" +
+	"allowedIps\x18\x15 \x03(\tR\n" +
+	"allowedIps\x12\x18\n" +
+	"\aoffline\x18\x16 \x01(\bR\aoffline\x12\"\n" +
+	"\ftotalBytesRx\x18\x17 \x01(\x03R\ftotalBytesRx\x12\"\n" +
+	"\ftotalBytesTx\x18\x18 \x01(\x03R\ftotalBytesTx\x12@\n" +
+	"\rlastConnected\x18\x19 \x01(\v2\x1a.google.protobuf.TimestampR\rlastConnected\x12$\n" +
+	"\rofflineReason\x18\x1a \x01(\tR\rofflineReason\x12C\n" +
+	"\x10networkTransfers\x18\x1b \x03(\v2\x17.daemon.NetworkTransferR\x10networkTransfers\"_\n" +
+	"\x0fNetworkTransfer\x12\x18\n" +
+	"\anetwork\x18\x01 \x01(\tR\anetwork\x12\x18\n" +
+	"\abytesRx\x18\x02 \x01(\x03R\abytesRx\x12\x18\n" +
+	"\abytesTx\x18\x03 \x01(\x03R\abytesTx\"\xcb\x02\n" +
 	"\x0eLocalPeerState\x12\x0e\n" +
 	"\x02IP\x18\x01 \x01(\tR\x02IP\x12\x16\n" +
 	"\x06pubKey\x18\x02 \x01(\tR\x06pubKey\x12(\n" +
@@ -5696,10 +10756,22 @@ const file_daemon_proto_rawDesc = "" +
 	"\x04fqdn\x18\x04 \x01(\tR\x04fqdn\x12*\n" +
 	"\x10rosenpassEnabled\x18\x05 \x01(\bR\x10rosenpassEnabled\x120\n" +
 	"\x13rosenpassPermissive\x18\x06 \x01(\bR\x13rosenpassPermissive\x12\x1a\n" +
-	"\bnetworks\x18\a \x03(\tR\bnetworks\"S\n" +
+	"\bnetworks\x18\a \x03(\tR\bnetworks\x12\x14\n" +
+	"\x05roles\x18\b \x03(\tR\x05roles\x12C\n" +
+	"\x10networkTransfers\x18\t \x03(\v2\x17.daemon.NetworkTransferR\x10networkTransfers\"\xa0\x02\n" +
 	"\vSignalState\x12\x10\n" +
 	"\x03URL\x18\x01 \x01(\tR\x03URL\x12\x1c\n" +
 	"\tconnected\x18\x02 \x01(\bR\tconnected\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x1e\n" +
+	"\n" +
+	"reconnects\x18\x04 \x01(\x05R\n" +
+	"reconnects\x12B\n" +
+	"\x0elastDisconnect\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x0elastDisconnect\x122\n" +
+	"\x14lastDisconnectReason\x18\x06 \x01(\tR\x14lastDisconnectReason\x123\n" +
+	"\alatency\x18\a \x01(\v2\x19.google.protobuf.DurationR\alatency\"Q\n" +
+	"\tFlowState\x12\x10\n" +
+	"\x03URL\x18\x01 \x01(\tR\x03URL\x12\x1c\n" +
+	"\tconnected\x18\x02 \x01(\bR\tconnected\x12\x14\n" +
 	"\x05error\x18\x03 \x01(\tR\x05error\"W\n" +
 	"\x0fManagementState\x12\x10\n" +
 	"\x03URL\x18\x01 \x01(\tR\x03URL\x12\x1c\n" +
@@ -5723,7 +10795,7 @@ const file_daemon_proto_rawDesc = "" +
 	"\fportForwards\x18\x05 \x03(\tR\fportForwards\"^\n" +
 	"\x0eSSHServerState\x12\x18\n" +
 	"\aenabled\x18\x01 \x01(\bR\aenabled\x122\n" +
-	"\bsessions\x18\x02 \x03(\v2\x16.daemon.SSHSessionInfoR\bsessions\"\xaf\x04\n" +
+	"\bsessions\x18\x02 \x03(\v2\x16.daemon.SSHSessionInfoR\bsessions\"\xdf\a\n" +
 	"\n" +
 	"FullStatus\x12A\n" +
 	"\x0fmanagementState\x18\x01 \x01(\v2\x17.daemon.ManagementStateR\x0fmanagementState\x125\n" +
@@ -5737,7 +10809,39 @@ const file_daemon_proto_rawDesc = "" +
 	"\x06events\x18\a \x03(\v2\x13.daemon.SystemEventR\x06events\x124\n" +
 	"\x15lazyConnectionEnabled\x18\t \x01(\bR\x15lazyConnectionEnabled\x12>\n" +
 	"\x0esshServerState\x18\n" +
-	" \x01(\v2\x16.daemon.SSHServerStateR\x0esshServerState\"\x15\n" +
+	" \x01(\v2\x16.daemon.SSHServerStateR\x0esshServerState\x12.\n" +
+	"\x12maintenanceEnabled\x18\v \x01(\bR\x12maintenanceEnabled\x12\x1e\n" +
+	"\n" +
+	"totalPeers\x18\f \x01(\x05R\n" +
+	"totalPeers\x12<\n" +
+	"\ffirewallSets\x18\r \x03(\v2\x18.daemon.FirewallSetStateR\ffirewallSets\x12F\n" +
+	"\x0ffirewallBackend\x18\x0e \x01(\v2\x1c.daemon.FirewallBackendStateR\x0ffirewallBackend\x12/\n" +
+	"\tflowState\x18\x0f \x01(\v2\x11.daemon.FlowStateR\tflowState\x12*\n" +
+	"\x10netstackFallback\x18\x10 \x01(\tR\x10netstackFallback\x12:\n" +
+	"\vdnsListener\x18\x11 \x01(\v2\x18.daemon.DNSListenerStateR\vdnsListener\x12?\n" +
+	"\rpeerResources\x18\x12 \x01(\v2\x19.daemon.PeerResourceStateR\rpeerResources\"\xd3\x02\n" +
+	"\x11PeerResourceState\x12\x1e\n" +
+	"\n" +
+	"goroutines\x18\x01 \x01(\x03R\n" +
+	"goroutines\x12\x1c\n" +
+	"\ticeAgents\x18\x02 \x01(\x03R\ticeAgents\x12 \n" +
+	"\vbufferBytes\x18\x03 \x01(\x03R\vbufferBytes\x12$\n" +
+	"\rmaxGoroutines\x18\x04 \x01(\x03R\rmaxGoroutines\x12\"\n" +
+	"\fmaxIceAgents\x18\x05 \x01(\x03R\fmaxIceAgents\x12&\n" +
+	"\x0emaxBufferBytes\x18\x06 \x01(\x03R\x0emaxBufferBytes\x12\x14\n" +
+	"\x05peers\x18\a \x01(\x05R\x05peers\x12,\n" +
+	"\x11deniedActivations\x18\b \x01(\x03R\x11deniedActivations\x12(\n" +
+	"\x0fdeniedIceAgents\x18\t \x01(\x03R\x0fdeniedIceAgents\"N\n" +
+	"\x10DNSListenerState\x12\x1c\n" +
+	"\taddresses\x18\x01 \x03(\tR\taddresses\x12\x1c\n" +
+	"\tfallbacks\x18\x02 \x03(\tR\tfallbacks\"`\n" +
+	"\x14FirewallBackendState\x12\x18\n" +
+	"\abackend\x18\x01 \x01(\tR\abackend\x12\x16\n" +
+	"\x06native\x18\x02 \x01(\tR\x06native\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"B\n" +
+	"\x10FirewallSetState\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\belements\x18\x02 \x01(\x05R\belements\"\x15\n" +
 	"\x13ListNetworksRequest\"?\n" +
 	"\x14ListNetworksResponse\x12'\n" +
 	"\x06routes\x18\x01 \x03(\v2\x0f.daemon.NetworkR\x06routes\"a\n" +
@@ -5747,7 +10851,23 @@ const file_daemon_proto_rawDesc = "" +
 	"networkIDs\x12\x16\n" +
 	"\x06append\x18\x02 \x01(\bR\x06append\x12\x10\n" +
 	"\x03all\x18\x03 \x01(\bR\x03all\"\x18\n" +
-	"\x16SelectNetworksResponse\"\x1a\n" +
+	"\x16SelectNetworksResponse\"(\n" +
+	"\x12SetExitNodeRequest\x12\x12\n" +
+	"\x04peer\x18\x01 \x01(\tR\x04peer\"\x15\n" +
+	"\x13SetExitNodeResponse\"1\n" +
+	"\x15SetMaintenanceRequest\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\"\x18\n" +
+	"\x16SetMaintenanceResponse\"\x14\n" +
+	"\x12GetExitNodeRequest\"\xa5\x01\n" +
+	"\x13GetExitNodeResponse\x12\x1e\n" +
+	"\n" +
+	"pinnedPeer\x18\x01 \x01(\tR\n" +
+	"pinnedPeer\x12&\n" +
+	"\x0epinnedPeerFqdn\x18\x02 \x01(\tR\x0epinnedPeerFqdn\x12\x1e\n" +
+	"\n" +
+	"activePeer\x18\x03 \x01(\tR\n" +
+	"activePeer\x12&\n" +
+	"\x0eactivePeerFqdn\x18\x04 \x01(\tR\x0eactivePeerFqdn\"\x1a\n" +
 	"\x06IPList\x12\x10\n" +
 	"\x03ips\x18\x01 \x03(\tR\x03ips\"\xf9\x01\n" +
 	"\aNetwork\x12\x0e\n" +
@@ -5765,13 +10885,20 @@ const file_daemon_proto_rawDesc = "" +
 	"\x05Range\x12\x14\n" +
 	"\x05start\x18\x01 \x01(\rR\x05start\x12\x10\n" +
 	"\x03end\x18\x02 \x01(\rR\x03endB\x0f\n" +
-	"\rportSelection\"\x80\x02\n" +
+	"\rportSelection\"\x90\x04\n" +
 	"\x0eForwardingRule\x12\x1a\n" +
 	"\bprotocol\x18\x01 \x01(\tR\bprotocol\x12:\n" +
 	"\x0fdestinationPort\x18\x02 \x01(\v2\x10.daemon.PortInfoR\x0fdestinationPort\x12,\n" +
 	"\x11translatedAddress\x18\x03 \x01(\tR\x11translatedAddress\x12.\n" +
 	"\x12translatedHostname\x18\x04 \x01(\tR\x12translatedHostname\x128\n" +
-	"\x0etranslatedPort\x18\x05 \x01(\v2\x10.daemon.PortInfoR\x0etranslatedPort\"G\n" +
+	"\x0etranslatedPort\x18\x05 \x01(\v2\x10.daemon.PortInfoR\x0etranslatedPort\x12\x16\n" +
+	"\x06health\x18\x06 \x01(\tR\x06health\x12 \n" +
+	"\vhealthError\x18\a \x01(\tR\vhealthError\x12D\n" +
+	"\x0flastHealthCheck\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x0flastHealthCheck\x12\x1a\n" +
+	"\bdraining\x18\t \x01(\bR\bdraining\x12@\n" +
+	"\rdrainDeadline\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\rdrainDeadline\x120\n" +
+	"\x13translatedAddresses\x18\v \x03(\tR\x13translatedAddresses\"G\n" +
 	"\x17ForwardingRulesResponse\x12,\n" +
 	"\x05rules\x18\x01 \x03(\v2\x16.daemon.ForwardingRuleR\x05rules\"\xac\x01\n" +
 	"\x12DebugBundleRequest\x12\x1c\n" +
@@ -5786,11 +10913,17 @@ const file_daemon_proto_rawDesc = "" +
 	"\x04path\x18\x01 \x01(\tR\x04path\x12 \n" +
 	"\vuploadedKey\x18\x02 \x01(\tR\vuploadedKey\x120\n" +
 	"\x13uploadFailureReason\x18\x03 \x01(\tR\x13uploadFailureReason\"\x14\n" +
-	"\x12GetLogLevelRequest\"=\n" +
+	"\x12GetLogLevelRequest\"\xef\x01\n" +
 	"\x13GetLogLevelResponse\x12&\n" +
-	"\x05level\x18\x01 \x01(\x0e2\x10.daemon.LogLevelR\x05level\"<\n" +
+	"\x05level\x18\x01 \x01(\x0e2\x10.daemon.LogLevelR\x05level\x12Z\n" +
+	"\x0fsubsystemLevels\x18\x02 \x03(\v20.daemon.GetLogLevelResponse.SubsystemLevelsEntryR\x0fsubsystemLevels\x1aT\n" +
+	"\x14SubsystemLevelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12&\n" +
+	"\x05value\x18\x02 \x01(\x0e2\x10.daemon.LogLevelR\x05value:\x028\x01\"\x82\x01\n" +
 	"\x12SetLogLevelRequest\x12&\n" +
-	"\x05level\x18\x01 \x01(\x0e2\x10.daemon.LogLevelR\x05level\"\x15\n" +
+	"\x05level\x18\x01 \x01(\x0e2\x10.daemon.LogLevelR\x05level\x12\x1c\n" +
+	"\tsubsystem\x18\x02 \x01(\tR\tsubsystem\x12&\n" +
+	"\x0eresetSubsystem\x18\x03 \x01(\bR\x0eresetSubsystem\"\x15\n" +
 	"\x13SetLogLevelResponse\"\x1b\n" +
 	"\x05State\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\"\x13\n" +
@@ -5811,7 +10944,260 @@ const file_daemon_proto_rawDesc = "" +
 	"\x0edeleted_states\x18\x01 \x01(\x05R\rdeletedStates\"=\n" +
 	"!SetSyncResponsePersistenceRequest\x12\x18\n" +
 	"\aenabled\x18\x01 \x01(\bR\aenabled\"$\n" +
-	"\"SetSyncResponsePersistenceResponse\"v\n" +
+	"\"SetSyncResponsePersistenceResponse\"@\n" +
+	"\x14GetNetworkMapRequest\x12\x12\n" +
+	"\x04peer\x18\x01 \x01(\tR\x04peer\x12\x14\n" +
+	"\x05route\x18\x02 \x01(\tR\x05route\"\xab\x01\n" +
+	"\x15GetNetworkMapResponse\x12\x16\n" +
+	"\x06serial\x18\x01 \x01(\x04R\x06serial\x12\x1e\n" +
+	"\n" +
+	"networkMap\x18\x02 \x01(\tR\n" +
+	"networkMap\x12\x16\n" +
+	"\x06routes\x18\x03 \x01(\tR\x06routes\x12\x1c\n" +
+	"\tdnsConfig\x18\x04 \x01(\tR\tdnsConfig\x12$\n" +
+	"\rfirewallRules\x18\x05 \x01(\tR\rfirewallRules\"\xb3\x01\n" +
+	"\vPortForward\x12$\n" +
+	"\rlistenAddress\x18\x01 \x01(\tR\rlistenAddress\x12$\n" +
+	"\rtargetAddress\x18\x02 \x01(\tR\rtargetAddress\x12,\n" +
+	"\x11activeConnections\x18\x03 \x01(\x04R\x11activeConnections\x12*\n" +
+	"\x10totalConnections\x18\x04 \x01(\x04R\x10totalConnections\"c\n" +
+	"\x15AddPortForwardRequest\x12$\n" +
+	"\rlistenAddress\x18\x01 \x01(\tR\rlistenAddress\x12$\n" +
+	"\rtargetAddress\x18\x02 \x01(\tR\rtargetAddress\"G\n" +
+	"\x16AddPortForwardResponse\x12-\n" +
+	"\aforward\x18\x01 \x01(\v2\x13.daemon.PortForwardR\aforward\"@\n" +
+	"\x18RemovePortForwardRequest\x12$\n" +
+	"\rlistenAddress\x18\x01 \x01(\tR\rlistenAddress\"\x1b\n" +
+	"\x19RemovePortForwardResponse\"\x19\n" +
+	"\x17ListPortForwardsRequest\"K\n" +
+	"\x18ListPortForwardsResponse\x12/\n" +
+	"\bforwards\x18\x01 \x03(\v2\x13.daemon.PortForwardR\bforwards\"\x11\n" +
+	"\x0fDiagnoseRequest\"\x8e\x01\n" +
+	"\x0eDiagnosedIssue\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bseverity\x18\x02 \x01(\tR\bseverity\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x18\n" +
+	"\adetails\x18\x04 \x01(\tR\adetails\x12 \n" +
+	"\vremediation\x18\x05 \x03(\tR\vremediation\"B\n" +
+	"\x10DiagnoseResponse\x12.\n" +
+	"\x06issues\x18\x01 \x03(\v2\x16.daemon.DiagnosedIssueR\x06issues\"W\n" +
+	"\vWakeRequest\x12\x16\n" +
+	"\x06target\x18\x01 \x01(\tR\x06target\x12\x1e\n" +
+	"\n" +
+	"macAddress\x18\x02 \x01(\tR\n" +
+	"macAddress\x12\x10\n" +
+	"\x03via\x18\x03 \x01(\tR\x03via\"\\\n" +
+	"\fWakeResponse\x12\x16\n" +
+	"\x06target\x18\x01 \x01(\tR\x06target\x12\x14\n" +
+	"\x05relay\x18\x02 \x01(\tR\x05relay\x12\x1e\n" +
+	"\n" +
+	"macAddress\x18\x03 \x01(\tR\n" +
+	"macAddress\"\x19\n" +
+	"\x17GetDNSCacheStatsRequest\"`\n" +
+	"\x18GetDNSCacheStatsResponse\x12\x18\n" +
+	"\aentries\x18\x01 \x01(\rR\aentries\x12\x12\n" +
+	"\x04hits\x18\x02 \x01(\x04R\x04hits\x12\x16\n" +
+	"\x06misses\x18\x03 \x01(\x04R\x06misses\"\x16\n" +
+	"\x14FlushDNSCacheRequest\"1\n" +
+	"\x15FlushDNSCacheResponse\x12\x18\n" +
+	"\aflushed\x18\x01 \x01(\rR\aflushed\"\x1a\n" +
+	"\x18GetRouteRuleStatsRequest\"\x8c\x01\n" +
+	"\x0eRouteRuleStats\x12\x16\n" +
+	"\x06ruleID\x18\x01 \x01(\tR\x06ruleID\x12\x1a\n" +
+	"\bpolicyID\x18\x02 \x01(\fR\bpolicyID\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x18\n" +
+	"\apackets\x18\x04 \x01(\x04R\apackets\x12\x14\n" +
+	"\x05bytes\x18\x05 \x01(\x04R\x05bytes\"I\n" +
+	"\x19GetRouteRuleStatsResponse\x12,\n" +
+	"\x05rules\x18\x01 \x03(\v2\x16.daemon.RouteRuleStatsR\x05rules\"\x18\n" +
+	"\x16GetRouteMetricsRequest\"`\n" +
+	"\rLatencyBucket\x129\n" +
+	"\n" +
+	"upperBound\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"upperBound\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x04R\x05count\"\xb3\x01\n" +
+	"\x10LatencyHistogram\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x04R\x05count\x12+\n" +
+	"\x03sum\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x03sum\x12+\n" +
+	"\x03max\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x03max\x12/\n" +
+	"\abuckets\x18\x04 \x03(\v2\x15.daemon.LatencyBucketR\abuckets\"\xd7\x02\n" +
+	"\x15NetworkMapRouteUpdate\x12\x16\n" +
+	"\x06serial\x18\x01 \x01(\x04R\x06serial\x12.\n" +
+	"\x04time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\"\n" +
+	"\fclientRoutes\x18\x03 \x01(\x05R\fclientRoutes\x12\"\n" +
+	"\fserverRoutes\x18\x04 \x01(\x05R\fserverRoutes\x12A\n" +
+	"\x0eroutesDuration\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\x0eroutesDuration\x12$\n" +
+	"\rfirewallRules\x18\x06 \x01(\x05R\rfirewallRules\x12E\n" +
+	"\x10firewallDuration\x18\a \x01(\v2\x19.google.protobuf.DurationR\x10firewallDuration\"\xc2\x02\n" +
+	"\x17GetRouteMetricsResponse\x12\x0e\n" +
+	"\x02os\x18\x01 \x01(\tR\x02os\x124\n" +
+	"\brouteAdd\x18\x02 \x01(\v2\x18.daemon.LatencyHistogramR\brouteAdd\x12:\n" +
+	"\vrouteRemove\x18\x03 \x01(\v2\x18.daemon.LatencyHistogramR\vrouteRemove\x120\n" +
+	"\x06routes\x18\x04 \x01(\v2\x18.daemon.LatencyHistogramR\x06routes\x124\n" +
+	"\bfirewall\x18\x05 \x01(\v2\x18.daemon.LatencyHistogramR\bfirewall\x12=\n" +
+	"\n" +
+	"lastUpdate\x18\x06 \x01(\v2\x1d.daemon.NetworkMapRouteUpdateR\n" +
+	"lastUpdate\"*\n" +
+	"\x14ReconnectPeerRequest\x12\x12\n" +
+	"\x04peer\x18\x01 \x01(\tR\x04peer\"+\n" +
+	"\x15ReconnectPeerResponse\x12\x12\n" +
+	"\x04fqdn\x18\x01 \x01(\tR\x04fqdn\"E\n" +
+	"\x15ForceRelayPeerRequest\x12\x12\n" +
+	"\x04peer\x18\x01 \x01(\tR\x04peer\x12\x18\n" +
+	"\aenabled\x18\x02 \x01(\bR\aenabled\",\n" +
+	"\x16ForceRelayPeerResponse\x12\x12\n" +
+	"\x04fqdn\x18\x01 \x01(\tR\x04fqdn\".\n" +
+	"\x18GetPeerCandidatesRequest\x12\x12\n" +
+	"\x04peer\x18\x01 \x01(\tR\x04peer\"\xa9\x02\n" +
+	"\rCandidatePair\x12\x1c\n" +
+	"\tlocalType\x18\x01 \x01(\tR\tlocalType\x12$\n" +
+	"\rlocalEndpoint\x18\x02 \x01(\tR\rlocalEndpoint\x12\x1e\n" +
+	"\n" +
+	"remoteType\x18\x03 \x01(\tR\n" +
+	"remoteType\x12&\n" +
+	"\x0eremoteEndpoint\x18\x04 \x01(\tR\x0eremoteEndpoint\x12\x18\n" +
+	"\arelayed\x18\x05 \x01(\bR\arelayed\x12:\n" +
+	"\n" +
+	"selectedAt\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"selectedAt\x126\n" +
+	"\bclosedAt\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\bclosedAt\"\xb3\x01\n" +
+	"\x19GetPeerCandidatesResponse\x12\x12\n" +
+	"\x04fqdn\x18\x01 \x01(\tR\x04fqdn\x12/\n" +
+	"\acurrent\x18\x02 \x01(\v2\x15.daemon.CandidatePairR\acurrent\x12/\n" +
+	"\ahistory\x18\x03 \x03(\v2\x15.daemon.CandidatePairR\ahistory\x12 \n" +
+	"\vrelayForced\x18\x04 \x01(\bR\vrelayForced\"5\n" +
+	"\x19GetRelayCandidatesRequest\x12\x18\n" +
+	"\arefresh\x18\x01 \x01(\bR\arefresh\"\xd3\x01\n" +
+	"\x0eRelayCandidate\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12+\n" +
+	"\x03rtt\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x03rtt\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12:\n" +
+	"\n" +
+	"measuredAt\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"measuredAt\x12\x12\n" +
+	"\x04home\x18\x05 \x01(\bR\x04home\x12\x1c\n" +
+	"\tpreferred\x18\x06 \x01(\bR\tpreferred\"l\n" +
+	"\x1aGetRelayCandidatesResponse\x126\n" +
+	"\n" +
+	"candidates\x18\x01 \x03(\v2\x16.daemon.RelayCandidateR\n" +
+	"candidates\x12\x16\n" +
+	"\x06pinned\x18\x02 \x01(\tR\x06pinned\",\n" +
+	"\x18SetPreferredRelayRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\"\x1b\n" +
+	"\x19SetPreferredRelayResponse\"P\n" +
+	"\x17SubscribeWGStatsRequest\x125\n" +
+	"\binterval\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\binterval\"\xb9\x01\n" +
+	"\vWGPeerStats\x12\x16\n" +
+	"\x06pubKey\x18\x01 \x01(\tR\x06pubKey\x12\x18\n" +
+	"\arxBytes\x18\x02 \x01(\x03R\arxBytes\x12\x18\n" +
+	"\atxBytes\x18\x03 \x01(\x03R\atxBytes\x12@\n" +
+	"\rlastHandshake\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\rlastHandshake\x12\x1c\n" +
+	"\thandshake\x18\x05 \x01(\bR\thandshake\"\x9f\x01\n" +
+	"\rWGStatsUpdate\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x123\n" +
+	"\aelapsed\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\aelapsed\x12)\n" +
+	"\x05peers\x18\x03 \x03(\v2\x13.daemon.WGPeerStatsR\x05peers\"\x1d\n" +
+	"\x1bGetDNSForwarderStatsRequest\"\xb0\x03\n" +
+	"\x1cGetDNSForwarderStatsResponse\x12\"\n" +
+	"\fcacheEntries\x18\x01 \x01(\rR\fcacheEntries\x12\x1c\n" +
+	"\tcacheHits\x18\x02 \x01(\x04R\tcacheHits\x12\"\n" +
+	"\fnegativeHits\x18\x03 \x01(\x04R\fnegativeHits\x12 \n" +
+	"\vcacheMisses\x18\x04 \x01(\x04R\vcacheMisses\x12\"\n" +
+	"\fstaleAnswers\x18\x05 \x01(\x04R\fstaleAnswers\x12(\n" +
+	"\x0fupstreamQueries\x18\x06 \x01(\x04R\x0fupstreamQueries\x12*\n" +
+	"\x10upstreamFailures\x18\a \x01(\x04R\x10upstreamFailures\x12C\n" +
+	"\x0fupstreamLatency\x18\b \x01(\v2\x19.google.protobuf.DurationR\x0fupstreamLatency\x12I\n" +
+	"\x12upstreamLatencyMax\x18\t \x01(\v2\x19.google.protobuf.DurationR\x12upstreamLatencyMax\"\x1b\n" +
+	"\x19GetEffectiveConfigRequest\"T\n" +
+	"\x10EffectiveSetting\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\"\xa2\x01\n" +
+	"\x1aGetEffectiveConfigResponse\x124\n" +
+	"\bsettings\x18\x01 \x03(\v2\x18.daemon.EffectiveSettingR\bsettings\x124\n" +
+	"\bfeatures\x18\x02 \x03(\v2\x18.daemon.EffectiveSettingR\bfeatures\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\"\xd1\x01\n" +
+	"\x11QueryFlowsRequest\x12\x12\n" +
+	"\x04peer\x18\x01 \x01(\tR\x04peer\x12\x12\n" +
+	"\x04port\x18\x02 \x01(\rR\x04port\x12\x1a\n" +
+	"\bprotocol\x18\x03 \x01(\tR\bprotocol\x120\n" +
+	"\x05since\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x120\n" +
+	"\x05until\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x05until\x12\x14\n" +
+	"\x05limit\x18\x06 \x01(\rR\x05limit\"\xe0\x03\n" +
+	"\n" +
+	"FlowRecord\x128\n" +
+	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x16\n" +
+	"\x06flowId\x18\x02 \x01(\tR\x06flowId\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x1c\n" +
+	"\tdirection\x18\x04 \x01(\tR\tdirection\x12\x1a\n" +
+	"\bprotocol\x18\x05 \x01(\tR\bprotocol\x12\x1a\n" +
+	"\bsourceIp\x18\x06 \x01(\tR\bsourceIp\x12\x1e\n" +
+	"\n" +
+	"sourcePort\x18\a \x01(\rR\n" +
+	"sourcePort\x12\x16\n" +
+	"\x06destIp\x18\b \x01(\tR\x06destIp\x12\x1a\n" +
+	"\bdestPort\x18\t \x01(\rR\bdestPort\x12\x1a\n" +
+	"\bicmpType\x18\n" +
+	" \x01(\rR\bicmpType\x12\x1a\n" +
+	"\bicmpCode\x18\v \x01(\rR\bicmpCode\x12\x1c\n" +
+	"\trxPackets\x18\f \x01(\x04R\trxPackets\x12\x1c\n" +
+	"\ttxPackets\x18\r \x01(\x04R\ttxPackets\x12\x18\n" +
+	"\arxBytes\x18\x0e \x01(\x04R\arxBytes\x12\x18\n" +
+	"\atxBytes\x18\x0f \x01(\x04R\atxBytes\x12\x1a\n" +
+	"\bdnsQuery\x18\x10 \x01(\tR\bdnsQuery\">\n" +
+	"\x12QueryFlowsResponse\x12(\n" +
+	"\x05flows\x18\x01 \x03(\v2\x12.daemon.FlowRecordR\x05flows\"\x8b\x02\n" +
+	"\rInboundPrompt\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06peerIp\x18\x02 \x01(\tR\x06peerIp\x12\x1a\n" +
+	"\bpeerFqdn\x18\x03 \x01(\tR\bpeerFqdn\x12\x1a\n" +
+	"\bprotocol\x18\x04 \x01(\tR\bprotocol\x12\x12\n" +
+	"\x04port\x18\x05 \x01(\rR\x04port\x124\n" +
+	"\acreated\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\acreated\x12\x1a\n" +
+	"\bdecision\x18\a \x01(\tR\bdecision\x124\n" +
+	"\aexpires\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\aexpires\"\x1b\n" +
+	"\x19ListInboundPromptsRequest\"M\n" +
+	"\x1aListInboundPromptsResponse\x12/\n" +
+	"\aprompts\x18\x01 \x03(\v2\x15.daemon.InboundPromptR\aprompts\"z\n" +
+	"\x1bRespondInboundPromptRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05allow\x18\x02 \x01(\bR\x05allow\x125\n" +
+	"\bduration\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\bduration\"M\n" +
+	"\x1cRespondInboundPromptResponse\x12-\n" +
+	"\x06prompt\x18\x01 \x01(\v2\x15.daemon.InboundPromptR\x06prompt\"?\n" +
+	"\x11ProbePeersRequest\x12\x14\n" +
+	"\x05peers\x18\x01 \x03(\tR\x05peers\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\rR\x05count\"\xa6\x02\n" +
+	"\tPeerProbe\x12\x16\n" +
+	"\x06pubKey\x18\x01 \x01(\tR\x06pubKey\x12\x12\n" +
+	"\x04fqdn\x18\x02 \x01(\tR\x04fqdn\x12\x0e\n" +
+	"\x02ip\x18\x03 \x01(\tR\x02ip\x12\x12\n" +
+	"\x04sent\x18\x04 \x01(\rR\x04sent\x12\x1a\n" +
+	"\breceived\x18\x05 \x01(\rR\breceived\x121\n" +
+	"\x06rttMin\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\x06rttMin\x121\n" +
+	"\x06rttAvg\x18\a \x01(\v2\x19.google.protobuf.DurationR\x06rttAvg\x121\n" +
+	"\x06rttMax\x18\b \x01(\v2\x19.google.protobuf.DurationR\x06rttMax\x12\x14\n" +
+	"\x05error\x18\t \x01(\tR\x05error\"=\n" +
+	"\x12ProbePeersResponse\x12'\n" +
+	"\x05peers\x18\x01 \x03(\v2\x11.daemon.PeerProbeR\x05peers\"\x80\x01\n" +
+	"\bAPIToken\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06scopes\x18\x03 \x03(\tR\x06scopes\x128\n" +
+	"\tcreatedAt\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"C\n" +
+	"\x15CreateAPITokenRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06scopes\x18\x02 \x03(\tR\x06scopes\"X\n" +
+	"\x16CreateAPITokenResponse\x12&\n" +
+	"\x05token\x18\x01 \x01(\v2\x10.daemon.APITokenR\x05token\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\"\x16\n" +
+	"\x14ListAPITokensRequest\"A\n" +
+	"\x15ListAPITokensResponse\x12(\n" +
+	"\x06tokens\x18\x01 \x03(\v2\x10.daemon.APITokenR\x06tokens\"-\n" +
+	"\x15RevokeAPITokenRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"@\n" +
+	"\x16RevokeAPITokenResponse\x12&\n" +
+	"\x05token\x18\x01 \x01(\v2\x10.daemon.APITokenR\x05token\"v\n" +
 	"\bTCPFlags\x12\x10\n" +
 	"\x03syn\x18\x01 \x01(\bR\x03syn\x12\x10\n" +
 	"\x03ack\x18\x02 \x01(\bR\x03ack\x12\x10\n" +
@@ -5845,8 +11231,12 @@ const file_daemon_proto_rawDesc = "" +
 	"\x13_forwarding_details\"n\n" +
 	"\x13TracePacketResponse\x12*\n" +
 	"\x06stages\x18\x01 \x03(\v2\x12.daemon.TraceStageR\x06stages\x12+\n" +
-	"\x11final_disposition\x18\x02 \x01(\bR\x10finalDisposition\"\x12\n" +
-	"\x10SubscribeRequest\"\x93\x04\n" +
+	"\x11final_disposition\x18\x02 \x01(\bR\x10finalDisposition\"\x90\x01\n" +
+	"\x10SubscribeRequest\x12>\n" +
+	"\vminSeverity\x18\x01 \x01(\x0e2\x1c.daemon.SystemEvent.SeverityR\vminSeverity\x12<\n" +
+	"\n" +
+	"categories\x18\x02 \x03(\x0e2\x1c.daemon.SystemEvent.CategoryR\n" +
+	"categories\"\xcf\x04\n" +
 	"\vSystemEvent\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x128\n" +
 	"\bseverity\x18\x02 \x01(\x0e2\x1c.daemon.SystemEvent.SeverityR\bseverity\x128\n" +
@@ -5854,7 +11244,11 @@ const file_daemon_proto_rawDesc = "" +
 	"\amessage\x18\x04 \x01(\tR\amessage\x12 \n" +
 	"\vuserMessage\x18\x05 \x01(\tR\vuserMessage\x128\n" +
 	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12=\n" +
-	"\bmetadata\x18\a \x03(\v2!.daemon.SystemEvent.MetadataEntryR\bmetadata\x1a;\n" +
+	"\bmetadata\x18\a \x03(\v2!.daemon.SystemEvent.MetadataEntryR\bmetadata\x12\x1a\n" +
+	"\bdedupKey\x18\b \x01(\tR\bdedupKey\x12\x1e\n" +
+	"\n" +
+	"suppressed\x18\t \x01(\rR\n" +
+	"suppressed\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\":\n" +
@@ -5878,7 +11272,7 @@ const file_daemon_proto_rawDesc = "" +
 	"\busername\x18\x02 \x01(\tH\x01R\busername\x88\x01\x01B\x0e\n" +
 	"\f_profileNameB\v\n" +
 	"\t_username\"\x17\n" +
-	"\x15SwitchProfileResponse\"\xdf\x10\n" +
+	"\x15SwitchProfileResponse\"\xde\x13\n" +
 	"\x10SetConfigRequest\x12\x1a\n" +
 	"\busername\x18\x01 \x01(\tR\busername\x12 \n" +
 	"\vprofileName\x18\x02 \x01(\tR\vprofileName\x12$\n" +
@@ -5917,7 +11311,14 @@ const file_daemon_proto_rawDesc = "" +
 	"\x1cenableSSHLocalPortForwarding\x18\x1f \x01(\bH\x14R\x1cenableSSHLocalPortForwarding\x88\x01\x01\x12I\n" +
 	"\x1denableSSHRemotePortForwarding\x18  \x01(\bH\x15R\x1denableSSHRemotePortForwarding\x88\x01\x01\x12+\n" +
 	"\x0edisableSSHAuth\x18! \x01(\bH\x16R\x0edisableSSHAuth\x88\x01\x01\x12+\n" +
-	"\x0esshJWTCacheTTL\x18\" \x01(\x05H\x17R\x0esshJWTCacheTTL\x88\x01\x01B\x13\n" +
+	"\x0esshJWTCacheTTL\x18\" \x01(\x05H\x17R\x0esshJWTCacheTTL\x88\x01\x01\x12$\n" +
+	"\vkill_switch\x18# \x01(\bH\x18R\n" +
+	"killSwitch\x88\x01\x01\x123\n" +
+	"\x12socks5ProxyAddress\x18$ \x01(\tH\x19R\x12socks5ProxyAddress\x88\x01\x01\x12/\n" +
+	"\x10httpProxyAddress\x18% \x01(\tH\x1aR\x10httpProxyAddress\x88\x01\x01\x12-\n" +
+	"\x0ficeMulticastDNS\x18& \x01(\bH\x1bR\x0ficeMulticastDNS\x88\x01\x01\x12!\n" +
+	"\ticePolicy\x18' \x01(\tH\x1cR\ticePolicy\x88\x01\x01\x12-\n" +
+	"\x0ffirewallBackend\x18( \x01(\tH\x1dR\x0ffirewallBackend\x88\x01\x01B\x13\n" +
 	"\x11_rosenpassEnabledB\x10\n" +
 	"\x0e_interfaceNameB\x10\n" +
 	"\x0e_wireguardPortB\x17\n" +
@@ -5941,7 +11342,14 @@ const file_daemon_proto_rawDesc = "" +
 	"\x1d_enableSSHLocalPortForwardingB \n" +
 	"\x1e_enableSSHRemotePortForwardingB\x11\n" +
 	"\x0f_disableSSHAuthB\x11\n" +
-	"\x0f_sshJWTCacheTTL\"\x13\n" +
+	"\x0f_sshJWTCacheTTLB\x0e\n" +
+	"\f_kill_switchB\x15\n" +
+	"\x13_socks5ProxyAddressB\x13\n" +
+	"\x11_httpProxyAddressB\x12\n" +
+	"\x10_iceMulticastDNSB\f\n" +
+	"\n" +
+	"_icePolicyB\x12\n" +
+	"\x10_firewallBackend\"\x13\n" +
 	"\x11SetConfigResponse\"Q\n" +
 	"\x11AddProfileRequest\x12\x1a\n" +
 	"\busername\x18\x01 \x01(\tR\busername\x12 \n" +
@@ -6015,7 +11423,7 @@ const file_daemon_proto_rawDesc = "" +
 	"\x04WARN\x10\x04\x12\b\n" +
 	"\x04INFO\x10\x05\x12\t\n" +
 	"\x05DEBUG\x10\x06\x12\t\n" +
-	"\x05TRACE\x10\a2\xb4\x13\n" +
+	"\x05TRACE\x10\a2\xda%\n" +
 	"\rDaemonService\x126\n" +
 	"\x05Login\x12\x14.daemon.LoginRequest\x1a\x15.daemon.LoginResponse\"\x00\x12K\n" +
 	"\fWaitSSOLogin\x12\x1b.daemon.WaitSSOLoginRequest\x1a\x1c.daemon.WaitSSOLoginResponse\"\x00\x12-\n" +
@@ -6025,7 +11433,10 @@ const file_daemon_proto_rawDesc = "" +
 	"\tGetConfig\x12\x18.daemon.GetConfigRequest\x1a\x19.daemon.GetConfigResponse\"\x00\x12K\n" +
 	"\fListNetworks\x12\x1b.daemon.ListNetworksRequest\x1a\x1c.daemon.ListNetworksResponse\"\x00\x12Q\n" +
 	"\x0eSelectNetworks\x12\x1d.daemon.SelectNetworksRequest\x1a\x1e.daemon.SelectNetworksResponse\"\x00\x12S\n" +
-	"\x10DeselectNetworks\x12\x1d.daemon.SelectNetworksRequest\x1a\x1e.daemon.SelectNetworksResponse\"\x00\x12J\n" +
+	"\x10DeselectNetworks\x12\x1d.daemon.SelectNetworksRequest\x1a\x1e.daemon.SelectNetworksResponse\"\x00\x12H\n" +
+	"\vSetExitNode\x12\x1a.daemon.SetExitNodeRequest\x1a\x1b.daemon.SetExitNodeResponse\"\x00\x12H\n" +
+	"\vGetExitNode\x12\x1a.daemon.GetExitNodeRequest\x1a\x1b.daemon.GetExitNodeResponse\"\x00\x12Q\n" +
+	"\x0eSetMaintenance\x12\x1d.daemon.SetMaintenanceRequest\x1a\x1e.daemon.SetMaintenanceResponse\"\x00\x12J\n" +
 	"\x0fForwardingRules\x12\x14.daemon.EmptyRequest\x1a\x1f.daemon.ForwardingRulesResponse\"\x00\x12H\n" +
 	"\vDebugBundle\x12\x1a.daemon.DebugBundleRequest\x1a\x1b.daemon.DebugBundleResponse\"\x00\x12H\n" +
 	"\vGetLogLevel\x12\x1a.daemon.GetLogLevelRequest\x1a\x1b.daemon.GetLogLevelResponse\"\x00\x12H\n" +
@@ -6052,7 +11463,34 @@ const file_daemon_proto_rawDesc = "" +
 	"\x0eRequestJWTAuth\x12\x1d.daemon.RequestJWTAuthRequest\x1a\x1e.daemon.RequestJWTAuthResponse\"\x00\x12K\n" +
 	"\fWaitJWTToken\x12\x1b.daemon.WaitJWTTokenRequest\x1a\x1c.daemon.WaitJWTTokenResponse\"\x00\x12N\n" +
 	"\x11NotifyOSLifecycle\x12\x1a.daemon.OSLifecycleRequest\x1a\x1b.daemon.OSLifecycleResponse\"\x00\x12W\n" +
-	"\x12GetInstallerResult\x12\x1e.daemon.InstallerResultRequest\x1a\x1f.daemon.InstallerResultResponse\"\x00B\bZ\x06/protob\x06proto3"
+	"\x12GetInstallerResult\x12\x1e.daemon.InstallerResultRequest\x1a\x1f.daemon.InstallerResultResponse\"\x00\x12N\n" +
+	"\rGetNetworkMap\x12\x1c.daemon.GetNetworkMapRequest\x1a\x1d.daemon.GetNetworkMapResponse\"\x00\x12Q\n" +
+	"\x0eAddPortForward\x12\x1d.daemon.AddPortForwardRequest\x1a\x1e.daemon.AddPortForwardResponse\"\x00\x12Z\n" +
+	"\x11RemovePortForward\x12 .daemon.RemovePortForwardRequest\x1a!.daemon.RemovePortForwardResponse\"\x00\x12W\n" +
+	"\x10ListPortForwards\x12\x1f.daemon.ListPortForwardsRequest\x1a .daemon.ListPortForwardsResponse\"\x00\x12?\n" +
+	"\bDiagnose\x12\x17.daemon.DiagnoseRequest\x1a\x18.daemon.DiagnoseResponse\"\x00\x123\n" +
+	"\x04Wake\x12\x13.daemon.WakeRequest\x1a\x14.daemon.WakeResponse\"\x00\x12W\n" +
+	"\x10GetDNSCacheStats\x12\x1f.daemon.GetDNSCacheStatsRequest\x1a .daemon.GetDNSCacheStatsResponse\"\x00\x12N\n" +
+	"\rFlushDNSCache\x12\x1c.daemon.FlushDNSCacheRequest\x1a\x1d.daemon.FlushDNSCacheResponse\"\x00\x12Z\n" +
+	"\x11GetRouteRuleStats\x12 .daemon.GetRouteRuleStatsRequest\x1a!.daemon.GetRouteRuleStatsResponse\"\x00\x12T\n" +
+	"\x0fGetRouteMetrics\x12\x1e.daemon.GetRouteMetricsRequest\x1a\x1f.daemon.GetRouteMetricsResponse\"\x00\x12N\n" +
+	"\rReconnectPeer\x12\x1c.daemon.ReconnectPeerRequest\x1a\x1d.daemon.ReconnectPeerResponse\"\x00\x12Q\n" +
+	"\x0eForceRelayPeer\x12\x1d.daemon.ForceRelayPeerRequest\x1a\x1e.daemon.ForceRelayPeerResponse\"\x00\x12Z\n" +
+	"\x11GetPeerCandidates\x12 .daemon.GetPeerCandidatesRequest\x1a!.daemon.GetPeerCandidatesResponse\"\x00\x12]\n" +
+	"\x12GetRelayCandidates\x12!.daemon.GetRelayCandidatesRequest\x1a\".daemon.GetRelayCandidatesResponse\"\x00\x12Z\n" +
+	"\x11SetPreferredRelay\x12 .daemon.SetPreferredRelayRequest\x1a!.daemon.SetPreferredRelayResponse\"\x00\x12c\n" +
+	"\x14GetDNSForwarderStats\x12#.daemon.GetDNSForwarderStatsRequest\x1a$.daemon.GetDNSForwarderStatsResponse\"\x00\x12E\n" +
+	"\n" +
+	"ProbePeers\x12\x19.daemon.ProbePeersRequest\x1a\x1a.daemon.ProbePeersResponse\"\x00\x12Q\n" +
+	"\x0eCreateAPIToken\x12\x1d.daemon.CreateAPITokenRequest\x1a\x1e.daemon.CreateAPITokenResponse\"\x00\x12N\n" +
+	"\rListAPITokens\x12\x1c.daemon.ListAPITokensRequest\x1a\x1d.daemon.ListAPITokensResponse\"\x00\x12Q\n" +
+	"\x0eRevokeAPIToken\x12\x1d.daemon.RevokeAPITokenRequest\x1a\x1e.daemon.RevokeAPITokenResponse\"\x00\x12]\n" +
+	"\x12GetEffectiveConfig\x12!.daemon.GetEffectiveConfigRequest\x1a\".daemon.GetEffectiveConfigResponse\"\x00\x12E\n" +
+	"\n" +
+	"QueryFlows\x12\x19.daemon.QueryFlowsRequest\x1a\x1a.daemon.QueryFlowsResponse\"\x00\x12]\n" +
+	"\x12ListInboundPrompts\x12!.daemon.ListInboundPromptsRequest\x1a\".daemon.ListInboundPromptsResponse\"\x00\x12c\n" +
+	"\x14RespondInboundPrompt\x12#.daemon.RespondInboundPromptRequest\x1a$.daemon.RespondInboundPromptResponse\"\x00\x12N\n" +
+	"\x10SubscribeWGStats\x12\x1f.daemon.SubscribeWGStatsRequest\x1a\x15.daemon.WGStatsUpdate\"\x000\x01B\bZ\x06/protob\x06proto3"
 
 var (
 	file_daemon_proto_rawDescOnce sync.Once
@@ -6066,206 +11504,408 @@ func file_daemon_proto_rawDescGZIP() []byte {
 	return file_daemon_proto_rawDescData
 }
 
-var file_daemon_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_daemon_proto_msgTypes = make([]protoimpl.MessageInfo, 84)
+var file_daemon_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_daemon_proto_msgTypes = make([]protoimpl.MessageInfo, 162)
 var file_daemon_proto_goTypes = []any{
 	(LogLevel)(0),                              // 0: daemon.LogLevel
 	(OSLifecycleRequest_CycleType)(0),          // 1: daemon.OSLifecycleRequest.CycleType
-	(SystemEvent_Severity)(0),                  // 2: daemon.SystemEvent.Severity
-	(SystemEvent_Category)(0),                  // 3: daemon.SystemEvent.Category
-	(*EmptyRequest)(nil),                       // 4: daemon.EmptyRequest
-	(*OSLifecycleRequest)(nil),                 // 5: daemon.OSLifecycleRequest
-	(*OSLifecycleResponse)(nil),                // 6: daemon.OSLifecycleResponse
-	(*LoginRequest)(nil),                       // 7: daemon.LoginRequest
-	(*LoginResponse)(nil),                      // 8: daemon.LoginResponse
-	(*WaitSSOLoginRequest)(nil),                // 9: daemon.WaitSSOLoginRequest
-	(*WaitSSOLoginResponse)(nil),               // 10: daemon.WaitSSOLoginResponse
-	(*UpRequest)(nil),                          // 11: daemon.UpRequest
-	(*UpResponse)(nil),                         // 12: daemon.UpResponse
-	(*StatusRequest)(nil),                      // 13: daemon.StatusRequest
-	(*StatusResponse)(nil),                     // 14: daemon.StatusResponse
-	(*DownRequest)(nil),                        // 15: daemon.DownRequest
-	(*DownResponse)(nil),                       // 16: daemon.DownResponse
-	(*GetConfigRequest)(nil),                   // 17: daemon.GetConfigRequest
-	(*GetConfigResponse)(nil),                  // 18: daemon.GetConfigResponse
-	(*PeerState)(nil),                          // 19: daemon.PeerState
-	(*LocalPeerState)(nil),                     // 20: daemon.LocalPeerState
-	(*SignalState)(nil),                        // 21: daemon.SignalState
-	(*ManagementState)(nil),                    // 22: daemon.ManagementState
-	(*RelayState)(nil),                         // 23: daemon.RelayState
-	(*NSGroupState)(nil),                       // 24: daemon.NSGroupState
-	(*SSHSessionInfo)(nil),                     // 25: daemon.SSHSessionInfo
-	(*SSHServerState)(nil),                     // 26: daemon.SSHServerState
-	(*FullStatus)(nil),                         // 27: daemon.FullStatus
-	(*ListNetworksRequest)(nil),                // 28: daemon.ListNetworksRequest
-	(*ListNetworksResponse)(nil),               // 29: daemon.ListNetworksResponse
-	(*SelectNetworksRequest)(nil),              // 30: daemon.SelectNetworksRequest
-	(*SelectNetworksResponse)(nil),             // 31: daemon.SelectNetworksResponse
-	(*IPList)(nil),                             // 32: daemon.IPList
-	(*Network)(nil),                            // 33: daemon.Network
-	(*PortInfo)(nil),                           // 34: daemon.PortInfo
-	(*ForwardingRule)(nil),                     // 35: daemon.ForwardingRule
-	(*ForwardingRulesResponse)(nil),            // 36: daemon.ForwardingRulesResponse
-	(*DebugBundleRequest)(nil),                 // 37: daemon.DebugBundleRequest
-	(*DebugBundleResponse)(nil),                // 38: daemon.DebugBundleResponse
-	(*GetLogLevelRequest)(nil),                 // 39: daemon.GetLogLevelRequest
-	(*GetLogLevelResponse)(nil),                // 40: daemon.GetLogLevelResponse
-	(*SetLogLevelRequest)(nil),                 // 41: daemon.SetLogLevelRequest
-	(*SetLogLevelResponse)(nil),                // 42: daemon.SetLogLevelResponse
-	(*State)(nil),                              // 43: daemon.State
-	(*ListStatesRequest)(nil),                  // 44: daemon.ListStatesRequest
-	(*ListStatesResponse)(nil),                 // 45: daemon.ListStatesResponse
-	(*CleanStateRequest)(nil),                  // 46: daemon.CleanStateRequest
-	(*CleanStateResponse)(nil),                 // 47: daemon.CleanStateResponse
-	(*DeleteStateRequest)(nil),                 // 48: daemon.DeleteStateRequest
-	(*DeleteStateResponse)(nil),                // 49: daemon.DeleteStateResponse
-	(*SetSyncResponsePersistenceRequest)(nil),  // 50: daemon.SetSyncResponsePersistenceRequest
-	(*SetSyncResponsePersistenceResponse)(nil), // 51: daemon.SetSyncResponsePersistenceResponse
-	(*TCPFlags)(nil),                           // 52: daemon.TCPFlags
-	(*TracePacketRequest)(nil),                 // 53: daemon.TracePacketRequest
-	(*TraceStage)(nil),                         // 54: daemon.TraceStage
-	(*TracePacketResponse)(nil),                // 55: daemon.TracePacketResponse
-	(*SubscribeRequest)(nil),                   // 56: daemon.SubscribeRequest
-	(*SystemEvent)(nil),                        // 57: daemon.SystemEvent
-	(*GetEventsRequest)(nil),                   // 58: daemon.GetEventsRequest
-	(*GetEventsResponse)(nil),                  // 59: daemon.GetEventsResponse
-	(*SwitchProfileRequest)(nil),               // 60: daemon.SwitchProfileRequest
-	(*SwitchProfileResponse)(nil),              // 61: daemon.SwitchProfileResponse
-	(*SetConfigRequest)(nil),                   // 62: daemon.SetConfigRequest
-	(*SetConfigResponse)(nil),                  // 63: daemon.SetConfigResponse
-	(*AddProfileRequest)(nil),                  // 64: daemon.AddProfileRequest
-	(*AddProfileResponse)(nil),                 // 65: daemon.AddProfileResponse
-	(*RemoveProfileRequest)(nil),               // 66: daemon.RemoveProfileRequest
-	(*RemoveProfileResponse)(nil),              // 67: daemon.RemoveProfileResponse
-	(*ListProfilesRequest)(nil),                // 68: daemon.ListProfilesRequest
-	(*ListProfilesResponse)(nil),               // 69: daemon.ListProfilesResponse
-	(*Profile)(nil),                            // 70: daemon.Profile
-	(*GetActiveProfileRequest)(nil),            // 71: daemon.GetActiveProfileRequest
-	(*GetActiveProfileResponse)(nil),           // 72: daemon.GetActiveProfileResponse
-	(*LogoutRequest)(nil),                      // 73: daemon.LogoutRequest
-	(*LogoutResponse)(nil),                     // 74: daemon.LogoutResponse
-	(*GetFeaturesRequest)(nil),                 // 75: daemon.GetFeaturesRequest
-	(*GetFeaturesResponse)(nil),                // 76: daemon.GetFeaturesResponse
-	(*GetPeerSSHHostKeyRequest)(nil),           // 77: daemon.GetPeerSSHHostKeyRequest
-	(*GetPeerSSHHostKeyResponse)(nil),          // 78: daemon.GetPeerSSHHostKeyResponse
-	(*RequestJWTAuthRequest)(nil),              // 79: daemon.RequestJWTAuthRequest
-	(*RequestJWTAuthResponse)(nil),             // 80: daemon.RequestJWTAuthResponse
-	(*WaitJWTTokenRequest)(nil),                // 81: daemon.WaitJWTTokenRequest
-	(*WaitJWTTokenResponse)(nil),               // 82: daemon.WaitJWTTokenResponse
-	(*InstallerResultRequest)(nil),             // 83: daemon.InstallerResultRequest
-	(*InstallerResultResponse)(nil),            // 84: daemon.InstallerResultResponse
-	nil,                                        // 85: daemon.Network.ResolvedIPsEntry
-	(*PortInfo_Range)(nil),                     // 86: daemon.PortInfo.Range
-	nil,                                        // 87: daemon.SystemEvent.MetadataEntry
-	(*durationpb.Duration)(nil),                // 88: google.protobuf.Duration
-	(*timestamppb.Timestamp)(nil),              // 89: google.protobuf.Timestamp
+	(PeerListOptions_SortField)(0),             // 2: daemon.PeerListOptions.SortField
+	(SystemEvent_Severity)(0),                  // 3: daemon.SystemEvent.Severity
+	(SystemEvent_Category)(0),                  // 4: daemon.SystemEvent.Category
+	(*EmptyRequest)(nil),                       // 5: daemon.EmptyRequest
+	(*OSLifecycleRequest)(nil),                 // 6: daemon.OSLifecycleRequest
+	(*OSLifecycleResponse)(nil),                // 7: daemon.OSLifecycleResponse
+	(*LoginRequest)(nil),                       // 8: daemon.LoginRequest
+	(*LoginResponse)(nil),                      // 9: daemon.LoginResponse
+	(*WaitSSOLoginRequest)(nil),                // 10: daemon.WaitSSOLoginRequest
+	(*WaitSSOLoginResponse)(nil),               // 11: daemon.WaitSSOLoginResponse
+	(*UpRequest)(nil),                          // 12: daemon.UpRequest
+	(*UpResponse)(nil),                         // 13: daemon.UpResponse
+	(*StatusRequest)(nil),                      // 14: daemon.StatusRequest
+	(*PeerListOptions)(nil),                    // 15: daemon.PeerListOptions
+	(*StatusResponse)(nil),                     // 16: daemon.StatusResponse
+	(*DownRequest)(nil),                        // 17: daemon.DownRequest
+	(*DownResponse)(nil),                       // 18: daemon.DownResponse
+	(*GetConfigRequest)(nil),                   // 19: daemon.GetConfigRequest
+	(*GetConfigResponse)(nil),                  // 20: daemon.GetConfigResponse
+	(*PeerState)(nil),                          // 21: daemon.PeerState
+	(*NetworkTransfer)(nil),                    // 22: daemon.NetworkTransfer
+	(*LocalPeerState)(nil),                     // 23: daemon.LocalPeerState
+	(*SignalState)(nil),                        // 24: daemon.SignalState
+	(*FlowState)(nil),                          // 25: daemon.FlowState
+	(*ManagementState)(nil),                    // 26: daemon.ManagementState
+	(*RelayState)(nil),                         // 27: daemon.RelayState
+	(*NSGroupState)(nil),                       // 28: daemon.NSGroupState
+	(*SSHSessionInfo)(nil),                     // 29: daemon.SSHSessionInfo
+	(*SSHServerState)(nil),                     // 30: daemon.SSHServerState
+	(*FullStatus)(nil),                         // 31: daemon.FullStatus
+	(*PeerResourceState)(nil),                  // 32: daemon.PeerResourceState
+	(*DNSListenerState)(nil),                   // 33: daemon.DNSListenerState
+	(*FirewallBackendState)(nil),               // 34: daemon.FirewallBackendState
+	(*FirewallSetState)(nil),                   // 35: daemon.FirewallSetState
+	(*ListNetworksRequest)(nil),                // 36: daemon.ListNetworksRequest
+	(*ListNetworksResponse)(nil),               // 37: daemon.ListNetworksResponse
+	(*SelectNetworksRequest)(nil),              // 38: daemon.SelectNetworksRequest
+	(*SelectNetworksResponse)(nil),             // 39: daemon.SelectNetworksResponse
+	(*SetExitNodeRequest)(nil),                 // 40: daemon.SetExitNodeRequest
+	(*SetExitNodeResponse)(nil),                // 41: daemon.SetExitNodeResponse
+	(*SetMaintenanceRequest)(nil),              // 42: daemon.SetMaintenanceRequest
+	(*SetMaintenanceResponse)(nil),             // 43: daemon.SetMaintenanceResponse
+	(*GetExitNodeRequest)(nil),                 // 44: daemon.GetExitNodeRequest
+	(*GetExitNodeResponse)(nil),                // 45: daemon.GetExitNodeResponse
+	(*IPList)(nil),                             // 46: daemon.IPList
+	(*Network)(nil),                            // 47: daemon.Network
+	(*PortInfo)(nil),                           // 48: daemon.PortInfo
+	(*ForwardingRule)(nil),                     // 49: daemon.ForwardingRule
+	(*ForwardingRulesResponse)(nil),            // 50: daemon.ForwardingRulesResponse
+	(*DebugBundleRequest)(nil),                 // 51: daemon.DebugBundleRequest
+	(*DebugBundleResponse)(nil),                // 52: daemon.DebugBundleResponse
+	(*GetLogLevelRequest)(nil),                 // 53: daemon.GetLogLevelRequest
+	(*GetLogLevelResponse)(nil),                // 54: daemon.GetLogLevelResponse
+	(*SetLogLevelRequest)(nil),                 // 55: daemon.SetLogLevelRequest
+	(*SetLogLevelResponse)(nil),                // 56: daemon.SetLogLevelResponse
+	(*State)(nil),                              // 57: daemon.State
+	(*ListStatesRequest)(nil),                  // 58: daemon.ListStatesRequest
+	(*ListStatesResponse)(nil),                 // 59: daemon.ListStatesResponse
+	(*CleanStateRequest)(nil),                  // 60: daemon.CleanStateRequest
+	(*CleanStateResponse)(nil),                 // 61: daemon.CleanStateResponse
+	(*DeleteStateRequest)(nil),                 // 62: daemon.DeleteStateRequest
+	(*DeleteStateResponse)(nil),                // 63: daemon.DeleteStateResponse
+	(*SetSyncResponsePersistenceRequest)(nil),  // 64: daemon.SetSyncResponsePersistenceRequest
+	(*SetSyncResponsePersistenceResponse)(nil), // 65: daemon.SetSyncResponsePersistenceResponse
+	(*GetNetworkMapRequest)(nil),               // 66: daemon.GetNetworkMapRequest
+	(*GetNetworkMapResponse)(nil),              // 67: daemon.GetNetworkMapResponse
+	(*PortForward)(nil),                        // 68: daemon.PortForward
+	(*AddPortForwardRequest)(nil),              // 69: daemon.AddPortForwardRequest
+	(*AddPortForwardResponse)(nil),             // 70: daemon.AddPortForwardResponse
+	(*RemovePortForwardRequest)(nil),           // 71: daemon.RemovePortForwardRequest
+	(*RemovePortForwardResponse)(nil),          // 72: daemon.RemovePortForwardResponse
+	(*ListPortForwardsRequest)(nil),            // 73: daemon.ListPortForwardsRequest
+	(*ListPortForwardsResponse)(nil),           // 74: daemon.ListPortForwardsResponse
+	(*DiagnoseRequest)(nil),                    // 75: daemon.DiagnoseRequest
+	(*DiagnosedIssue)(nil),                     // 76: daemon.DiagnosedIssue
+	(*DiagnoseResponse)(nil),                   // 77: daemon.DiagnoseResponse
+	(*WakeRequest)(nil),                        // 78: daemon.WakeRequest
+	(*WakeResponse)(nil),                       // 79: daemon.WakeResponse
+	(*GetDNSCacheStatsRequest)(nil),            // 80: daemon.GetDNSCacheStatsRequest
+	(*GetDNSCacheStatsResponse)(nil),           // 81: daemon.GetDNSCacheStatsResponse
+	(*FlushDNSCacheRequest)(nil),               // 82: daemon.FlushDNSCacheRequest
+	(*FlushDNSCacheResponse)(nil),              // 83: daemon.FlushDNSCacheResponse
+	(*GetRouteRuleStatsRequest)(nil),           // 84: daemon.GetRouteRuleStatsRequest
+	(*RouteRuleStats)(nil),                     // 85: daemon.RouteRuleStats
+	(*GetRouteRuleStatsResponse)(nil),          // 86: daemon.GetRouteRuleStatsResponse
+	(*GetRouteMetricsRequest)(nil),             // 87: daemon.GetRouteMetricsRequest
+	(*LatencyBucket)(nil),                      // 88: daemon.LatencyBucket
+	(*LatencyHistogram)(nil),                   // 89: daemon.LatencyHistogram
+	(*NetworkMapRouteUpdate)(nil),              // 90: daemon.NetworkMapRouteUpdate
+	(*GetRouteMetricsResponse)(nil),            // 91: daemon.GetRouteMetricsResponse
+	(*ReconnectPeerRequest)(nil),               // 92: daemon.ReconnectPeerRequest
+	(*ReconnectPeerResponse)(nil),              // 93: daemon.ReconnectPeerResponse
+	(*ForceRelayPeerRequest)(nil),              // 94: daemon.ForceRelayPeerRequest
+	(*ForceRelayPeerResponse)(nil),             // 95: daemon.ForceRelayPeerResponse
+	(*GetPeerCandidatesRequest)(nil),           // 96: daemon.GetPeerCandidatesRequest
+	(*CandidatePair)(nil),                      // 97: daemon.CandidatePair
+	(*GetPeerCandidatesResponse)(nil),          // 98: daemon.GetPeerCandidatesResponse
+	(*GetRelayCandidatesRequest)(nil),          // 99: daemon.GetRelayCandidatesRequest
+	(*RelayCandidate)(nil),                     // 100: daemon.RelayCandidate
+	(*GetRelayCandidatesResponse)(nil),         // 101: daemon.GetRelayCandidatesResponse
+	(*SetPreferredRelayRequest)(nil),           // 102: daemon.SetPreferredRelayRequest
+	(*SetPreferredRelayResponse)(nil),          // 103: daemon.SetPreferredRelayResponse
+	(*SubscribeWGStatsRequest)(nil),            // 104: daemon.SubscribeWGStatsRequest
+	(*WGPeerStats)(nil),                        // 105: daemon.WGPeerStats
+	(*WGStatsUpdate)(nil),                      // 106: daemon.WGStatsUpdate
+	(*GetDNSForwarderStatsRequest)(nil),        // 107: daemon.GetDNSForwarderStatsRequest
+	(*GetDNSForwarderStatsResponse)(nil),       // 108: daemon.GetDNSForwarderStatsResponse
+	(*GetEffectiveConfigRequest)(nil),          // 109: daemon.GetEffectiveConfigRequest
+	(*EffectiveSetting)(nil),                   // 110: daemon.EffectiveSetting
+	(*GetEffectiveConfigResponse)(nil),         // 111: daemon.GetEffectiveConfigResponse
+	(*QueryFlowsRequest)(nil),                  // 112: daemon.QueryFlowsRequest
+	(*FlowRecord)(nil),                         // 113: daemon.FlowRecord
+	(*QueryFlowsResponse)(nil),                 // 114: daemon.QueryFlowsResponse
+	(*InboundPrompt)(nil),                      // 115: daemon.InboundPrompt
+	(*ListInboundPromptsRequest)(nil),          // 116: daemon.ListInboundPromptsRequest
+	(*ListInboundPromptsResponse)(nil),         // 117: daemon.ListInboundPromptsResponse
+	(*RespondInboundPromptRequest)(nil),        // 118: daemon.RespondInboundPromptRequest
+	(*RespondInboundPromptResponse)(nil),       // 119: daemon.RespondInboundPromptResponse
+	(*ProbePeersRequest)(nil),                  // 120: daemon.ProbePeersRequest
+	(*PeerProbe)(nil),                          // 121: daemon.PeerProbe
+	(*ProbePeersResponse)(nil),                 // 122: daemon.ProbePeersResponse
+	(*APIToken)(nil),                           // 123: daemon.APIToken
+	(*CreateAPITokenRequest)(nil),              // 124: daemon.CreateAPITokenRequest
+	(*CreateAPITokenResponse)(nil),             // 125: daemon.CreateAPITokenResponse
+	(*ListAPITokensRequest)(nil),               // 126: daemon.ListAPITokensRequest
+	(*ListAPITokensResponse)(nil),              // 127: daemon.ListAPITokensResponse
+	(*RevokeAPITokenRequest)(nil),              // 128: daemon.RevokeAPITokenRequest
+	(*RevokeAPITokenResponse)(nil),             // 129: daemon.RevokeAPITokenResponse
+	(*TCPFlags)(nil),                           // 130: daemon.TCPFlags
+	(*TracePacketRequest)(nil),                 // 131: daemon.TracePacketRequest
+	(*TraceStage)(nil),                         // 132: daemon.TraceStage
+	(*TracePacketResponse)(nil),                // 133: daemon.TracePacketResponse
+	(*SubscribeRequest)(nil),                   // 134: daemon.SubscribeRequest
+	(*SystemEvent)(nil),                        // 135: daemon.SystemEvent
+	(*GetEventsRequest)(nil),                   // 136: daemon.GetEventsRequest
+	(*GetEventsResponse)(nil),                  // 137: daemon.GetEventsResponse
+	(*SwitchProfileRequest)(nil),               // 138: daemon.SwitchProfileRequest
+	(*SwitchProfileResponse)(nil),              // 139: daemon.SwitchProfileResponse
+	(*SetConfigRequest)(nil),                   // 140: daemon.SetConfigRequest
+	(*SetConfigResponse)(nil),                  // 141: daemon.SetConfigResponse
+	(*AddProfileRequest)(nil),                  // 142: daemon.AddProfileRequest
+	(*AddProfileResponse)(nil),                 // 143: daemon.AddProfileResponse
+	(*RemoveProfileRequest)(nil),               // 144: daemon.RemoveProfileRequest
+	(*RemoveProfileResponse)(nil),              // 145: daemon.RemoveProfileResponse
+	(*ListProfilesRequest)(nil),                // 146: daemon.ListProfilesRequest
+	(*ListProfilesResponse)(nil),               // 147: daemon.ListProfilesResponse
+	(*Profile)(nil),                            // 148: daemon.Profile
+	(*GetActiveProfileRequest)(nil),            // 149: daemon.GetActiveProfileRequest
+	(*GetActiveProfileResponse)(nil),           // 150: daemon.GetActiveProfileResponse
+	(*LogoutRequest)(nil),                      // 151: daemon.LogoutRequest
+	(*LogoutResponse)(nil),                     // 152: daemon.LogoutResponse
+	(*GetFeaturesRequest)(nil),                 // 153: daemon.GetFeaturesRequest
+	(*GetFeaturesResponse)(nil),                // 154: daemon.GetFeaturesResponse
+	(*GetPeerSSHHostKeyRequest)(nil),           // 155: daemon.GetPeerSSHHostKeyRequest
+	(*GetPeerSSHHostKeyResponse)(nil),          // 156: daemon.GetPeerSSHHostKeyResponse
+	(*RequestJWTAuthRequest)(nil),              // 157: daemon.RequestJWTAuthRequest
+	(*RequestJWTAuthResponse)(nil),             // 158: daemon.RequestJWTAuthResponse
+	(*WaitJWTTokenRequest)(nil),                // 159: daemon.WaitJWTTokenRequest
+	(*WaitJWTTokenResponse)(nil),               // 160: daemon.WaitJWTTokenResponse
+	(*InstallerResultRequest)(nil),             // 161: daemon.InstallerResultRequest
+	(*InstallerResultResponse)(nil),            // 162: daemon.InstallerResultResponse
+	nil,                                        // 163: daemon.Network.ResolvedIPsEntry
+	(*PortInfo_Range)(nil),                     // 164: daemon.PortInfo.Range
+	nil,                                        // 165: daemon.GetLogLevelResponse.SubsystemLevelsEntry
+	nil,                                        // 166: daemon.SystemEvent.MetadataEntry
+	(*durationpb.Duration)(nil),                // 167: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),              // 168: google.protobuf.Timestamp
 }
 var file_daemon_proto_depIdxs = []int32{
-	1,  // 0: daemon.OSLifecycleRequest.type:type_name -> daemon.OSLifecycleRequest.CycleType
-	88, // 1: daemon.LoginRequest.dnsRouteInterval:type_name -> google.protobuf.Duration
-	27, // 2: daemon.StatusResponse.fullStatus:type_name -> daemon.FullStatus
-	89, // 3: daemon.PeerState.connStatusUpdate:type_name -> google.protobuf.Timestamp
-	89, // 4: daemon.PeerState.lastWireguardHandshake:type_name -> google.protobuf.Timestamp
-	88, // 5: daemon.PeerState.latency:type_name -> google.protobuf.Duration
-	25, // 6: daemon.SSHServerState.sessions:type_name -> daemon.SSHSessionInfo
-	22, // 7: daemon.FullStatus.managementState:type_name -> daemon.ManagementState
-	21, // 8: daemon.FullStatus.signalState:type_name -> daemon.SignalState
-	20, // 9: daemon.FullStatus.localPeerState:type_name -> daemon.LocalPeerState
-	19, // 10: daemon.FullStatus.peers:type_name -> daemon.PeerState
-	23, // 11: daemon.FullStatus.relays:type_name -> daemon.RelayState
-	24, // 12: daemon.FullStatus.dns_servers:type_name -> daemon.NSGroupState
-	57, // 13: daemon.FullStatus.events:type_name -> daemon.SystemEvent
-	26, // 14: daemon.FullStatus.sshServerState:type_name -> daemon.SSHServerState
-	33, // 15: daemon.ListNetworksResponse.routes:type_name -> daemon.Network
-	85, // 16: daemon.Network.resolvedIPs:type_name -> daemon.Network.ResolvedIPsEntry
-	86, // 17: daemon.PortInfo.range:type_name -> daemon.PortInfo.Range
-	34, // 18: daemon.ForwardingRule.destinationPort:type_name -> daemon.PortInfo
-	34, // 19: daemon.ForwardingRule.translatedPort:type_name -> daemon.PortInfo
-	35, // 20: daemon.ForwardingRulesResponse.rules:type_name -> daemon.ForwardingRule
-	0,  // 21: daemon.GetLogLevelResponse.level:type_name -> daemon.LogLevel
-	0,  // 22: daemon.SetLogLevelRequest.level:type_name -> daemon.LogLevel
-	43, // 23: daemon.ListStatesResponse.states:type_name -> daemon.State
-	52, // 24: daemon.TracePacketRequest.tcp_flags:type_name -> daemon.TCPFlags
-	54, // 25: daemon.TracePacketResponse.stages:type_name -> daemon.TraceStage
-	2,  // 26: daemon.SystemEvent.severity:type_name -> daemon.SystemEvent.Severity
-	3,  // 27: daemon.SystemEvent.category:type_name -> daemon.SystemEvent.Category
-	89, // 28: daemon.SystemEvent.timestamp:type_name -> google.protobuf.Timestamp
-	87, // 29: daemon.SystemEvent.metadata:type_name -> daemon.SystemEvent.MetadataEntry
-	57, // 30: daemon.GetEventsResponse.events:type_name -> daemon.SystemEvent
-	88, // 31: daemon.SetConfigRequest.dnsRouteInterval:type_name -> google.protobuf.Duration
-	70, // 32: daemon.ListProfilesResponse.profiles:type_name -> daemon.Profile
-	32, // 33: daemon.Network.ResolvedIPsEntry.value:type_name -> daemon.IPList
-	7,  // 34: daemon.DaemonService.Login:input_type -> daemon.LoginRequest
-	9,  // 35: daemon.DaemonService.WaitSSOLogin:input_type -> daemon.WaitSSOLoginRequest
-	11, // 36: daemon.DaemonService.Up:input_type -> daemon.UpRequest
-	13, // 37: daemon.DaemonService.Status:input_type -> daemon.StatusRequest
-	15, // 38: daemon.DaemonService.Down:input_type -> daemon.DownRequest
-	17, // 39: daemon.DaemonService.GetConfig:input_type -> daemon.GetConfigRequest
-	28, // 40: daemon.DaemonService.ListNetworks:input_type -> daemon.ListNetworksRequest
-	30, // 41: daemon.DaemonService.SelectNetworks:input_type -> daemon.SelectNetworksRequest
-	30, // 42: daemon.DaemonService.DeselectNetworks:input_type -> daemon.SelectNetworksRequest
-	4,  // 43: daemon.DaemonService.ForwardingRules:input_type -> daemon.EmptyRequest
-	37, // 44: daemon.DaemonService.DebugBundle:input_type -> daemon.DebugBundleRequest
-	39, // 45: daemon.DaemonService.GetLogLevel:input_type -> daemon.GetLogLevelRequest
-	41, // 46: daemon.DaemonService.SetLogLevel:input_type -> daemon.SetLogLevelRequest
-	44, // 47: daemon.DaemonService.ListStates:input_type -> daemon.ListStatesRequest
-	46, // 48: daemon.DaemonService.CleanState:input_type -> daemon.CleanStateRequest
-	48, // 49: daemon.DaemonService.DeleteState:input_type -> daemon.DeleteStateRequest
-	50, // 50: daemon.DaemonService.SetSyncResponsePersistence:input_type -> daemon.SetSyncResponsePersistenceRequest
-	53, // 51: daemon.DaemonService.TracePacket:input_type -> daemon.TracePacketRequest
-	56, // 52: daemon.DaemonService.SubscribeEvents:input_type -> daemon.SubscribeRequest
-	58, // 53: daemon.DaemonService.GetEvents:input_type -> daemon.GetEventsRequest
-	60, // 54: daemon.DaemonService.SwitchProfile:input_type -> daemon.SwitchProfileRequest
-	62, // 55: daemon.DaemonService.SetConfig:input_type -> daemon.SetConfigRequest
-	64, // 56: daemon.DaemonService.AddProfile:input_type -> daemon.AddProfileRequest
-	66, // 57: daemon.DaemonService.RemoveProfile:input_type -> daemon.RemoveProfileRequest
-	68, // 58: daemon.DaemonService.ListProfiles:input_type -> daemon.ListProfilesRequest
-	71, // 59: daemon.DaemonService.GetActiveProfile:input_type -> daemon.GetActiveProfileRequest
-	73, // 60: daemon.DaemonService.Logout:input_type -> daemon.LogoutRequest
-	75, // 61: daemon.DaemonService.GetFeatures:input_type -> daemon.GetFeaturesRequest
-	77, // 62: daemon.DaemonService.GetPeerSSHHostKey:input_type -> daemon.GetPeerSSHHostKeyRequest
-	79, // 63: daemon.DaemonService.RequestJWTAuth:input_type -> daemon.RequestJWTAuthRequest
-	81, // 64: daemon.DaemonService.WaitJWTToken:input_type -> daemon.WaitJWTTokenRequest
-	5,  // 65: daemon.DaemonService.NotifyOSLifecycle:input_type -> daemon.OSLifecycleRequest
-	83, // 66: daemon.DaemonService.GetInstallerResult:input_type -> daemon.InstallerResultRequest
-	8,  // 67: daemon.DaemonService.Login:output_type -> daemon.LoginResponse
-	10, // 68: daemon.DaemonService.WaitSSOLogin:output_type -> daemon.WaitSSOLoginResponse
-	12, // 69: daemon.DaemonService.Up:output_type -> daemon.UpResponse
-	14, // 70: daemon.DaemonService.Status:output_type -> daemon.StatusResponse
-	16, // 71: daemon.DaemonService.Down:output_type -> daemon.DownResponse
-	18, // 72: daemon.DaemonService.GetConfig:output_type -> daemon.GetConfigResponse
-	29, // 73: daemon.DaemonService.ListNetworks:output_type -> daemon.ListNetworksResponse
-	31, // 74: daemon.DaemonService.SelectNetworks:output_type -> daemon.SelectNetworksResponse
-	31, // 75: daemon.DaemonService.DeselectNetworks:output_type -> daemon.SelectNetworksResponse
-	36, // 76: daemon.DaemonService.ForwardingRules:output_type -> daemon.ForwardingRulesResponse
-	38, // 77: daemon.DaemonService.DebugBundle:output_type -> daemon.DebugBundleResponse
-	40, // 78: daemon.DaemonService.GetLogLevel:output_type -> daemon.GetLogLevelResponse
-	42, // 79: daemon.DaemonService.SetLogLevel:output_type -> daemon.SetLogLevelResponse
-	45, // 80: daemon.DaemonService.ListStates:output_type -> daemon.ListStatesResponse
-	47, // 81: daemon.DaemonService.CleanState:output_type -> daemon.CleanStateResponse
-	49, // 82: daemon.DaemonService.DeleteState:output_type -> daemon.DeleteStateResponse
-	51, // 83: daemon.DaemonService.SetSyncResponsePersistence:output_type -> daemon.SetSyncResponsePersistenceResponse
-	55, // 84: daemon.DaemonService.TracePacket:output_type -> daemon.TracePacketResponse
-	57, // 85: daemon.DaemonService.SubscribeEvents:output_type -> daemon.SystemEvent
-	59, // 86: daemon.DaemonService.GetEvents:output_type -> daemon.GetEventsResponse
-	61, // 87: daemon.DaemonService.SwitchProfile:output_type -> daemon.SwitchProfileResponse
-	63, // 88: daemon.DaemonService.SetConfig:output_type -> daemon.SetConfigResponse
-	65, // 89: daemon.DaemonService.AddProfile:output_type -> daemon.AddProfileResponse
-	67, // 90: daemon.DaemonService.RemoveProfile:output_type -> daemon.RemoveProfileResponse
-	69, // 91: daemon.DaemonService.ListProfiles:output_type -> daemon.ListProfilesResponse
-	72, // 92: daemon.DaemonService.GetActiveProfile:output_type -> daemon.GetActiveProfileResponse
-	74, // 93: daemon.DaemonService.Logout:output_type -> daemon.LogoutResponse
-	76, // 94: daemon.DaemonService.GetFeatures:output_type -> daemon.GetFeaturesResponse
-	78, // 95: daemon.DaemonService.GetPeerSSHHostKey:output_type -> daemon.GetPeerSSHHostKeyResponse
-	80, // 96: daemon.DaemonService.RequestJWTAuth:output_type -> daemon.RequestJWTAuthResponse
-	82, // 97: daemon.DaemonService.WaitJWTToken:output_type -> daemon.WaitJWTTokenResponse
-	6,  // 98: daemon.DaemonService.NotifyOSLifecycle:output_type -> daemon.OSLifecycleResponse
-	84, // 99: daemon.DaemonService.GetInstallerResult:output_type -> daemon.InstallerResultResponse
-	67, // [67:100] is the sub-list for method output_type
-	34, // [34:67] is the sub-list for method input_type
-	34, // [34:34] is the sub-list for extension type_name
-	34, // [34:34] is the sub-list for extension extendee
-	0,  // [0:34] is the sub-list for field type_name
+	1,   // 0: daemon.OSLifecycleRequest.type:type_name -> daemon.OSLifecycleRequest.CycleType
+	167, // 1: daemon.LoginRequest.dnsRouteInterval:type_name -> google.protobuf.Duration
+	15,  // 2: daemon.StatusRequest.peerListOptions:type_name -> daemon.PeerListOptions
+	2,   // 3: daemon.PeerListOptions.sortBy:type_name -> daemon.PeerListOptions.SortField
+	31,  // 4: daemon.StatusResponse.fullStatus:type_name -> daemon.FullStatus
+	168, // 5: daemon.PeerState.connStatusUpdate:type_name -> google.protobuf.Timestamp
+	168, // 6: daemon.PeerState.lastWireguardHandshake:type_name -> google.protobuf.Timestamp
+	167, // 7: daemon.PeerState.latency:type_name -> google.protobuf.Duration
+	168, // 8: daemon.PeerState.lastConnected:type_name -> google.protobuf.Timestamp
+	22,  // 9: daemon.PeerState.networkTransfers:type_name -> daemon.NetworkTransfer
+	22,  // 10: daemon.LocalPeerState.networkTransfers:type_name -> daemon.NetworkTransfer
+	168, // 11: daemon.SignalState.lastDisconnect:type_name -> google.protobuf.Timestamp
+	167, // 12: daemon.SignalState.latency:type_name -> google.protobuf.Duration
+	29,  // 13: daemon.SSHServerState.sessions:type_name -> daemon.SSHSessionInfo
+	26,  // 14: daemon.FullStatus.managementState:type_name -> daemon.ManagementState
+	24,  // 15: daemon.FullStatus.signalState:type_name -> daemon.SignalState
+	23,  // 16: daemon.FullStatus.localPeerState:type_name -> daemon.LocalPeerState
+	21,  // 17: daemon.FullStatus.peers:type_name -> daemon.PeerState
+	27,  // 18: daemon.FullStatus.relays:type_name -> daemon.RelayState
+	28,  // 19: daemon.FullStatus.dns_servers:type_name -> daemon.NSGroupState
+	135, // 20: daemon.FullStatus.events:type_name -> daemon.SystemEvent
+	30,  // 21: daemon.FullStatus.sshServerState:type_name -> daemon.SSHServerState
+	35,  // 22: daemon.FullStatus.firewallSets:type_name -> daemon.FirewallSetState
+	34,  // 23: daemon.FullStatus.firewallBackend:type_name -> daemon.FirewallBackendState
+	25,  // 24: daemon.FullStatus.flowState:type_name -> daemon.FlowState
+	33,  // 25: daemon.FullStatus.dnsListener:type_name -> daemon.DNSListenerState
+	32,  // 26: daemon.FullStatus.peerResources:type_name -> daemon.PeerResourceState
+	47,  // 27: daemon.ListNetworksResponse.routes:type_name -> daemon.Network
+	163, // 28: daemon.Network.resolvedIPs:type_name -> daemon.Network.ResolvedIPsEntry
+	164, // 29: daemon.PortInfo.range:type_name -> daemon.PortInfo.Range
+	48,  // 30: daemon.ForwardingRule.destinationPort:type_name -> daemon.PortInfo
+	48,  // 31: daemon.ForwardingRule.translatedPort:type_name -> daemon.PortInfo
+	168, // 32: daemon.ForwardingRule.lastHealthCheck:type_name -> google.protobuf.Timestamp
+	168, // 33: daemon.ForwardingRule.drainDeadline:type_name -> google.protobuf.Timestamp
+	49,  // 34: daemon.ForwardingRulesResponse.rules:type_name -> daemon.ForwardingRule
+	0,   // 35: daemon.GetLogLevelResponse.level:type_name -> daemon.LogLevel
+	165, // 36: daemon.GetLogLevelResponse.subsystemLevels:type_name -> daemon.GetLogLevelResponse.SubsystemLevelsEntry
+	0,   // 37: daemon.SetLogLevelRequest.level:type_name -> daemon.LogLevel
+	57,  // 38: daemon.ListStatesResponse.states:type_name -> daemon.State
+	68,  // 39: daemon.AddPortForwardResponse.forward:type_name -> daemon.PortForward
+	68,  // 40: daemon.ListPortForwardsResponse.forwards:type_name -> daemon.PortForward
+	76,  // 41: daemon.DiagnoseResponse.issues:type_name -> daemon.DiagnosedIssue
+	85,  // 42: daemon.GetRouteRuleStatsResponse.rules:type_name -> daemon.RouteRuleStats
+	167, // 43: daemon.LatencyBucket.upperBound:type_name -> google.protobuf.Duration
+	167, // 44: daemon.LatencyHistogram.sum:type_name -> google.protobuf.Duration
+	167, // 45: daemon.LatencyHistogram.max:type_name -> google.protobuf.Duration
+	88,  // 46: daemon.LatencyHistogram.buckets:type_name -> daemon.LatencyBucket
+	168, // 47: daemon.NetworkMapRouteUpdate.time:type_name -> google.protobuf.Timestamp
+	167, // 48: daemon.NetworkMapRouteUpdate.routesDuration:type_name -> google.protobuf.Duration
+	167, // 49: daemon.NetworkMapRouteUpdate.firewallDuration:type_name -> google.protobuf.Duration
+	89,  // 50: daemon.GetRouteMetricsResponse.routeAdd:type_name -> daemon.LatencyHistogram
+	89,  // 51: daemon.GetRouteMetricsResponse.routeRemove:type_name -> daemon.LatencyHistogram
+	89,  // 52: daemon.GetRouteMetricsResponse.routes:type_name -> daemon.LatencyHistogram
+	89,  // 53: daemon.GetRouteMetricsResponse.firewall:type_name -> daemon.LatencyHistogram
+	90,  // 54: daemon.GetRouteMetricsResponse.lastUpdate:type_name -> daemon.NetworkMapRouteUpdate
+	168, // 55: daemon.CandidatePair.selectedAt:type_name -> google.protobuf.Timestamp
+	168, // 56: daemon.CandidatePair.closedAt:type_name -> google.protobuf.Timestamp
+	97,  // 57: daemon.GetPeerCandidatesResponse.current:type_name -> daemon.CandidatePair
+	97,  // 58: daemon.GetPeerCandidatesResponse.history:type_name -> daemon.CandidatePair
+	167, // 59: daemon.RelayCandidate.rtt:type_name -> google.protobuf.Duration
+	168, // 60: daemon.RelayCandidate.measuredAt:type_name -> google.protobuf.Timestamp
+	100, // 61: daemon.GetRelayCandidatesResponse.candidates:type_name -> daemon.RelayCandidate
+	167, // 62: daemon.SubscribeWGStatsRequest.interval:type_name -> google.protobuf.Duration
+	168, // 63: daemon.WGPeerStats.lastHandshake:type_name -> google.protobuf.Timestamp
+	168, // 64: daemon.WGStatsUpdate.time:type_name -> google.protobuf.Timestamp
+	167, // 65: daemon.WGStatsUpdate.elapsed:type_name -> google.protobuf.Duration
+	105, // 66: daemon.WGStatsUpdate.peers:type_name -> daemon.WGPeerStats
+	167, // 67: daemon.GetDNSForwarderStatsResponse.upstreamLatency:type_name -> google.protobuf.Duration
+	167, // 68: daemon.GetDNSForwarderStatsResponse.upstreamLatencyMax:type_name -> google.protobuf.Duration
+	110, // 69: daemon.GetEffectiveConfigResponse.settings:type_name -> daemon.EffectiveSetting
+	110, // 70: daemon.GetEffectiveConfigResponse.features:type_name -> daemon.EffectiveSetting
+	168, // 71: daemon.QueryFlowsRequest.since:type_name -> google.protobuf.Timestamp
+	168, // 72: daemon.QueryFlowsRequest.until:type_name -> google.protobuf.Timestamp
+	168, // 73: daemon.FlowRecord.timestamp:type_name -> google.protobuf.Timestamp
+	113, // 74: daemon.QueryFlowsResponse.flows:type_name -> daemon.FlowRecord
+	168, // 75: daemon.InboundPrompt.created:type_name -> google.protobuf.Timestamp
+	168, // 76: daemon.InboundPrompt.expires:type_name -> google.protobuf.Timestamp
+	115, // 77: daemon.ListInboundPromptsResponse.prompts:type_name -> daemon.InboundPrompt
+	167, // 78: daemon.RespondInboundPromptRequest.duration:type_name -> google.protobuf.Duration
+	115, // 79: daemon.RespondInboundPromptResponse.prompt:type_name -> daemon.InboundPrompt
+	167, // 80: daemon.PeerProbe.rttMin:type_name -> google.protobuf.Duration
+	167, // 81: daemon.PeerProbe.rttAvg:type_name -> google.protobuf.Duration
+	167, // 82: daemon.PeerProbe.rttMax:type_name -> google.protobuf.Duration
+	121, // 83: daemon.ProbePeersResponse.peers:type_name -> daemon.PeerProbe
+	168, // 84: daemon.APIToken.createdAt:type_name -> google.protobuf.Timestamp
+	123, // 85: daemon.CreateAPITokenResponse.token:type_name -> daemon.APIToken
+	123, // 86: daemon.ListAPITokensResponse.tokens:type_name -> daemon.APIToken
+	123, // 87: daemon.RevokeAPITokenResponse.token:type_name -> daemon.APIToken
+	130, // 88: daemon.TracePacketRequest.tcp_flags:type_name -> daemon.TCPFlags
+	132, // 89: daemon.TracePacketResponse.stages:type_name -> daemon.TraceStage
+	3,   // 90: daemon.SubscribeRequest.minSeverity:type_name -> daemon.SystemEvent.Severity
+	4,   // 91: daemon.SubscribeRequest.categories:type_name -> daemon.SystemEvent.Category
+	3,   // 92: daemon.SystemEvent.severity:type_name -> daemon.SystemEvent.Severity
+	4,   // 93: daemon.SystemEvent.category:type_name -> daemon.SystemEvent.Category
+	168, // 94: daemon.SystemEvent.timestamp:type_name -> google.protobuf.Timestamp
+	166, // 95: daemon.SystemEvent.metadata:type_name -> daemon.SystemEvent.MetadataEntry
+	135, // 96: daemon.GetEventsResponse.events:type_name -> daemon.SystemEvent
+	167, // 97: daemon.SetConfigRequest.dnsRouteInterval:type_name -> google.protobuf.Duration
+	148, // 98: daemon.ListProfilesResponse.profiles:type_name -> daemon.Profile
+	46,  // 99: daemon.Network.ResolvedIPsEntry.value:type_name -> daemon.IPList
+	0,   // 100: daemon.GetLogLevelResponse.SubsystemLevelsEntry.value:type_name -> daemon.LogLevel
+	8,   // 101: daemon.DaemonService.Login:input_type -> daemon.LoginRequest
+	10,  // 102: daemon.DaemonService.WaitSSOLogin:input_type -> daemon.WaitSSOLoginRequest
+	12,  // 103: daemon.DaemonService.Up:input_type -> daemon.UpRequest
+	14,  // 104: daemon.DaemonService.Status:input_type -> daemon.StatusRequest
+	17,  // 105: daemon.DaemonService.Down:input_type -> daemon.DownRequest
+	19,  // 106: daemon.DaemonService.GetConfig:input_type -> daemon.GetConfigRequest
+	36,  // 107: daemon.DaemonService.ListNetworks:input_type -> daemon.ListNetworksRequest
+	38,  // 108: daemon.DaemonService.SelectNetworks:input_type -> daemon.SelectNetworksRequest
+	38,  // 109: daemon.DaemonService.DeselectNetworks:input_type -> daemon.SelectNetworksRequest
+	40,  // 110: daemon.DaemonService.SetExitNode:input_type -> daemon.SetExitNodeRequest
+	44,  // 111: daemon.DaemonService.GetExitNode:input_type -> daemon.GetExitNodeRequest
+	42,  // 112: daemon.DaemonService.SetMaintenance:input_type -> daemon.SetMaintenanceRequest
+	5,   // 113: daemon.DaemonService.ForwardingRules:input_type -> daemon.EmptyRequest
+	51,  // 114: daemon.DaemonService.DebugBundle:input_type -> daemon.DebugBundleRequest
+	53,  // 115: daemon.DaemonService.GetLogLevel:input_type -> daemon.GetLogLevelRequest
+	55,  // 116: daemon.DaemonService.SetLogLevel:input_type -> daemon.SetLogLevelRequest
+	58,  // 117: daemon.DaemonService.ListStates:input_type -> daemon.ListStatesRequest
+	60,  // 118: daemon.DaemonService.CleanState:input_type -> daemon.CleanStateRequest
+	62,  // 119: daemon.DaemonService.DeleteState:input_type -> daemon.DeleteStateRequest
+	64,  // 120: daemon.DaemonService.SetSyncResponsePersistence:input_type -> daemon.SetSyncResponsePersistenceRequest
+	131, // 121: daemon.DaemonService.TracePacket:input_type -> daemon.TracePacketRequest
+	134, // 122: daemon.DaemonService.SubscribeEvents:input_type -> daemon.SubscribeRequest
+	136, // 123: daemon.DaemonService.GetEvents:input_type -> daemon.GetEventsRequest
+	138, // 124: daemon.DaemonService.SwitchProfile:input_type -> daemon.SwitchProfileRequest
+	140, // 125: daemon.DaemonService.SetConfig:input_type -> daemon.SetConfigRequest
+	142, // 126: daemon.DaemonService.AddProfile:input_type -> daemon.AddProfileRequest
+	144, // 127: daemon.DaemonService.RemoveProfile:input_type -> daemon.RemoveProfileRequest
+	146, // 128: daemon.DaemonService.ListProfiles:input_type -> daemon.ListProfilesRequest
+	149, // 129: daemon.DaemonService.GetActiveProfile:input_type -> daemon.GetActiveProfileRequest
+	151, // 130: daemon.DaemonService.Logout:input_type -> daemon.LogoutRequest
+	153, // 131: daemon.DaemonService.GetFeatures:input_type -> daemon.GetFeaturesRequest
+	155, // 132: daemon.DaemonService.GetPeerSSHHostKey:input_type -> daemon.GetPeerSSHHostKeyRequest
+	157, // 133: daemon.DaemonService.RequestJWTAuth:input_type -> daemon.RequestJWTAuthRequest
+	159, // 134: daemon.DaemonService.WaitJWTToken:input_type -> daemon.WaitJWTTokenRequest
+	6,   // 135: daemon.DaemonService.NotifyOSLifecycle:input_type -> daemon.OSLifecycleRequest
+	161, // 136: daemon.DaemonService.GetInstallerResult:input_type -> daemon.InstallerResultRequest
+	66,  // 137: daemon.DaemonService.GetNetworkMap:input_type -> daemon.GetNetworkMapRequest
+	69,  // 138: daemon.DaemonService.AddPortForward:input_type -> daemon.AddPortForwardRequest
+	71,  // 139: daemon.DaemonService.RemovePortForward:input_type -> daemon.RemovePortForwardRequest
+	73,  // 140: daemon.DaemonService.ListPortForwards:input_type -> daemon.ListPortForwardsRequest
+	75,  // 141: daemon.DaemonService.Diagnose:input_type -> daemon.DiagnoseRequest
+	78,  // 142: daemon.DaemonService.Wake:input_type -> daemon.WakeRequest
+	80,  // 143: daemon.DaemonService.GetDNSCacheStats:input_type -> daemon.GetDNSCacheStatsRequest
+	82,  // 144: daemon.DaemonService.FlushDNSCache:input_type -> daemon.FlushDNSCacheRequest
+	84,  // 145: daemon.DaemonService.GetRouteRuleStats:input_type -> daemon.GetRouteRuleStatsRequest
+	87,  // 146: daemon.DaemonService.GetRouteMetrics:input_type -> daemon.GetRouteMetricsRequest
+	92,  // 147: daemon.DaemonService.ReconnectPeer:input_type -> daemon.ReconnectPeerRequest
+	94,  // 148: daemon.DaemonService.ForceRelayPeer:input_type -> daemon.ForceRelayPeerRequest
+	96,  // 149: daemon.DaemonService.GetPeerCandidates:input_type -> daemon.GetPeerCandidatesRequest
+	99,  // 150: daemon.DaemonService.GetRelayCandidates:input_type -> daemon.GetRelayCandidatesRequest
+	102, // 151: daemon.DaemonService.SetPreferredRelay:input_type -> daemon.SetPreferredRelayRequest
+	107, // 152: daemon.DaemonService.GetDNSForwarderStats:input_type -> daemon.GetDNSForwarderStatsRequest
+	120, // 153: daemon.DaemonService.ProbePeers:input_type -> daemon.ProbePeersRequest
+	124, // 154: daemon.DaemonService.CreateAPIToken:input_type -> daemon.CreateAPITokenRequest
+	126, // 155: daemon.DaemonService.ListAPITokens:input_type -> daemon.ListAPITokensRequest
+	128, // 156: daemon.DaemonService.RevokeAPIToken:input_type -> daemon.RevokeAPITokenRequest
+	109, // 157: daemon.DaemonService.GetEffectiveConfig:input_type -> daemon.GetEffectiveConfigRequest
+	112, // 158: daemon.DaemonService.QueryFlows:input_type -> daemon.QueryFlowsRequest
+	116, // 159: daemon.DaemonService.ListInboundPrompts:input_type -> daemon.ListInboundPromptsRequest
+	118, // 160: daemon.DaemonService.RespondInboundPrompt:input_type -> daemon.RespondInboundPromptRequest
+	104, // 161: daemon.DaemonService.SubscribeWGStats:input_type -> daemon.SubscribeWGStatsRequest
+	9,   // 162: daemon.DaemonService.Login:output_type -> daemon.LoginResponse
+	11,  // 163: daemon.DaemonService.WaitSSOLogin:output_type -> daemon.WaitSSOLoginResponse
+	13,  // 164: daemon.DaemonService.Up:output_type -> daemon.UpResponse
+	16,  // 165: daemon.DaemonService.Status:output_type -> daemon.StatusResponse
+	18,  // 166: daemon.DaemonService.Down:output_type -> daemon.DownResponse
+	20,  // 167: daemon.DaemonService.GetConfig:output_type -> daemon.GetConfigResponse
+	37,  // 168: daemon.DaemonService.ListNetworks:output_type -> daemon.ListNetworksResponse
+	39,  // 169: daemon.DaemonService.SelectNetworks:output_type -> daemon.SelectNetworksResponse
+	39,  // 170: daemon.DaemonService.DeselectNetworks:output_type -> daemon.SelectNetworksResponse
+	41,  // 171: daemon.DaemonService.SetExitNode:output_type -> daemon.SetExitNodeResponse
+	45,  // 172: daemon.DaemonService.GetExitNode:output_type -> daemon.GetExitNodeResponse
+	43,  // 173: daemon.DaemonService.SetMaintenance:output_type -> daemon.SetMaintenanceResponse
+	50,  // 174: daemon.DaemonService.ForwardingRules:output_type -> daemon.ForwardingRulesResponse
+	52,  // 175: daemon.DaemonService.DebugBundle:output_type -> daemon.DebugBundleResponse
+	54,  // 176: daemon.DaemonService.GetLogLevel:output_type -> daemon.GetLogLevelResponse
+	56,  // 177: daemon.DaemonService.SetLogLevel:output_type -> daemon.SetLogLevelResponse
+	59,  // 178: daemon.DaemonService.ListStates:output_type -> daemon.ListStatesResponse
+	61,  // 179: daemon.DaemonService.CleanState:output_type -> daemon.CleanStateResponse
+	63,  // 180: daemon.DaemonService.DeleteState:output_type -> daemon.DeleteStateResponse
+	65,  // 181: daemon.DaemonService.SetSyncResponsePersistence:output_type -> daemon.SetSyncResponsePersistenceResponse
+	133, // 182: daemon.DaemonService.TracePacket:output_type -> daemon.TracePacketResponse
+	135, // 183: daemon.DaemonService.SubscribeEvents:output_type -> daemon.SystemEvent
+	137, // 184: daemon.DaemonService.GetEvents:output_type -> daemon.GetEventsResponse
+	139, // 185: daemon.DaemonService.SwitchProfile:output_type -> daemon.SwitchProfileResponse
+	141, // 186: daemon.DaemonService.SetConfig:output_type -> daemon.SetConfigResponse
+	143, // 187: daemon.DaemonService.AddProfile:output_type -> daemon.AddProfileResponse
+	145, // 188: daemon.DaemonService.RemoveProfile:output_type -> daemon.RemoveProfileResponse
+	147, // 189: daemon.DaemonService.ListProfiles:output_type -> daemon.ListProfilesResponse
+	150, // 190: daemon.DaemonService.GetActiveProfile:output_type -> daemon.GetActiveProfileResponse
+	152, // 191: daemon.DaemonService.Logout:output_type -> daemon.LogoutResponse
+	154, // 192: daemon.DaemonService.GetFeatures:output_type -> daemon.GetFeaturesResponse
+	156, // 193: daemon.DaemonService.GetPeerSSHHostKey:output_type -> daemon.GetPeerSSHHostKeyResponse
+	158, // 194: daemon.DaemonService.RequestJWTAuth:output_type -> daemon.RequestJWTAuthResponse
+	160, // 195: daemon.DaemonService.WaitJWTToken:output_type -> daemon.WaitJWTTokenResponse
+	7,   // 196: daemon.DaemonService.NotifyOSLifecycle:output_type -> daemon.OSLifecycleResponse
+	162, // 197: daemon.DaemonService.GetInstallerResult:output_type -> daemon.InstallerResultResponse
+	67,  // 198: daemon.DaemonService.GetNetworkMap:output_type -> daemon.GetNetworkMapResponse
+	70,  // 199: daemon.DaemonService.AddPortForward:output_type -> daemon.AddPortForwardResponse
+	72,  // 200: daemon.DaemonService.RemovePortForward:output_type -> daemon.RemovePortForwardResponse
+	74,  // 201: daemon.DaemonService.ListPortForwards:output_type -> daemon.ListPortForwardsResponse
+	77,  // 202: daemon.DaemonService.Diagnose:output_type -> daemon.DiagnoseResponse
+	79,  // 203: daemon.DaemonService.Wake:output_type -> daemon.WakeResponse
+	81,  // 204: daemon.DaemonService.GetDNSCacheStats:output_type -> daemon.GetDNSCacheStatsResponse
+	83,  // 205: daemon.DaemonService.FlushDNSCache:output_type -> daemon.FlushDNSCacheResponse
+	86,  // 206: daemon.DaemonService.GetRouteRuleStats:output_type -> daemon.GetRouteRuleStatsResponse
+	91,  // 207: daemon.DaemonService.GetRouteMetrics:output_type -> daemon.GetRouteMetricsResponse
+	93,  // 208: daemon.DaemonService.ReconnectPeer:output_type -> daemon.ReconnectPeerResponse
+	95,  // 209: daemon.DaemonService.ForceRelayPeer:output_type -> daemon.ForceRelayPeerResponse
+	98,  // 210: daemon.DaemonService.GetPeerCandidates:output_type -> daemon.GetPeerCandidatesResponse
+	101, // 211: daemon.DaemonService.GetRelayCandidates:output_type -> daemon.GetRelayCandidatesResponse
+	103, // 212: daemon.DaemonService.SetPreferredRelay:output_type -> daemon.SetPreferredRelayResponse
+	108, // 213: daemon.DaemonService.GetDNSForwarderStats:output_type -> daemon.GetDNSForwarderStatsResponse
+	122, // 214: daemon.DaemonService.ProbePeers:output_type -> daemon.ProbePeersResponse
+	125, // 215: daemon.DaemonService.CreateAPIToken:output_type -> daemon.CreateAPITokenResponse
+	127, // 216: daemon.DaemonService.ListAPITokens:output_type -> daemon.ListAPITokensResponse
+	129, // 217: daemon.DaemonService.RevokeAPIToken:output_type -> daemon.RevokeAPITokenResponse
+	111, // 218: daemon.DaemonService.GetEffectiveConfig:output_type -> daemon.GetEffectiveConfigResponse
+	114, // 219: daemon.DaemonService.QueryFlows:output_type -> daemon.QueryFlowsResponse
+	117, // 220: daemon.DaemonService.ListInboundPrompts:output_type -> daemon.ListInboundPromptsResponse
+	119, // 221: daemon.DaemonService.RespondInboundPrompt:output_type -> daemon.RespondInboundPromptResponse
+	106, // 222: daemon.DaemonService.SubscribeWGStats:output_type -> daemon.WGStatsUpdate
+	162, // [162:223] is the sub-list for method output_type
+	101, // [101:162] is the sub-list for method input_type
+	101, // [101:101] is the sub-list for extension type_name
+	101, // [101:101] is the sub-list for extension extendee
+	0,   // [0:101] is the sub-list for field type_name
 }
 
 func init() { file_daemon_proto_init() }
@@ -6276,23 +11916,23 @@ func file_daemon_proto_init() {
 	file_daemon_proto_msgTypes[3].OneofWrappers = []any{}
 	file_daemon_proto_msgTypes[7].OneofWrappers = []any{}
 	file_daemon_proto_msgTypes[9].OneofWrappers = []any{}
-	file_daemon_proto_msgTypes[30].OneofWrappers = []any{
+	file_daemon_proto_msgTypes[43].OneofWrappers = []any{
 		(*PortInfo_Port)(nil),
 		(*PortInfo_Range_)(nil),
 	}
-	file_daemon_proto_msgTypes[49].OneofWrappers = []any{}
-	file_daemon_proto_msgTypes[50].OneofWrappers = []any{}
-	file_daemon_proto_msgTypes[56].OneofWrappers = []any{}
-	file_daemon_proto_msgTypes[58].OneofWrappers = []any{}
-	file_daemon_proto_msgTypes[69].OneofWrappers = []any{}
-	file_daemon_proto_msgTypes[75].OneofWrappers = []any{}
+	file_daemon_proto_msgTypes[126].OneofWrappers = []any{}
+	file_daemon_proto_msgTypes[127].OneofWrappers = []any{}
+	file_daemon_proto_msgTypes[133].OneofWrappers = []any{}
+	file_daemon_proto_msgTypes[135].OneofWrappers = []any{}
+	file_daemon_proto_msgTypes[146].OneofWrappers = []any{}
+	file_daemon_proto_msgTypes[152].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_daemon_proto_rawDesc), len(file_daemon_proto_rawDesc)),
-			NumEnums:      4,
-			NumMessages:   84,
+			NumEnums:      5,
+			NumMessages:   162,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -37,6 +37,12 @@ type DaemonServiceClient interface {
 	SelectNetworks(ctx context.Context, in *SelectNetworksRequest, opts ...grpc.CallOption) (*SelectNetworksResponse, error)
 	// Deselect specific routes
 	DeselectNetworks(ctx context.Context, in *SelectNetworksRequest, opts ...grpc.CallOption) (*SelectNetworksResponse, error)
+	// SetExitNode pins a preferred exit node peer or switches back to automatic selection
+	SetExitNode(ctx context.Context, in *SetExitNodeRequest, opts ...grpc.CallOption) (*SetExitNodeResponse, error)
+	// GetExitNode returns the exit node selection policy and the active exit node
+	GetExitNode(ctx context.Context, in *GetExitNodeRequest, opts ...grpc.CallOption) (*GetExitNodeResponse, error)
+	// SetMaintenance announces to the connected peers that this peer is about to go down or is back in service
+	SetMaintenance(ctx context.Context, in *SetMaintenanceRequest, opts ...grpc.CallOption) (*SetMaintenanceResponse, error)
 	ForwardingRules(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*ForwardingRulesResponse, error)
 	// DebugBundle creates a debug bundle
 	DebugBundle(ctx context.Context, in *DebugBundleRequest, opts ...grpc.CallOption) (*DebugBundleResponse, error)
@@ -72,6 +78,56 @@ type DaemonServiceClient interface {
 	WaitJWTToken(ctx context.Context, in *WaitJWTTokenRequest, opts ...grpc.CallOption) (*WaitJWTTokenResponse, error)
 	NotifyOSLifecycle(ctx context.Context, in *OSLifecycleRequest, opts ...grpc.CallOption) (*OSLifecycleResponse, error)
 	GetInstallerResult(ctx context.Context, in *InstallerResultRequest, opts ...grpc.CallOption) (*InstallerResultResponse, error)
+	// GetNetworkMap returns the persisted network map, filtered by peer or route, for troubleshooting
+	GetNetworkMap(ctx context.Context, in *GetNetworkMapRequest, opts ...grpc.CallOption) (*GetNetworkMapResponse, error)
+	// AddPortForward creates a local listener forwarding connections to a peer address through the netstack
+	AddPortForward(ctx context.Context, in *AddPortForwardRequest, opts ...grpc.CallOption) (*AddPortForwardResponse, error)
+	// RemovePortForward closes the listener of a port forward
+	RemovePortForward(ctx context.Context, in *RemovePortForwardRequest, opts ...grpc.CallOption) (*RemovePortForwardResponse, error)
+	// ListPortForwards returns the active port forwards
+	ListPortForwards(ctx context.Context, in *ListPortForwardsRequest, opts ...grpc.CallOption) (*ListPortForwardsResponse, error)
+	// Diagnose checks the client and the host for well-known failure patterns
+	Diagnose(ctx context.Context, in *DiagnoseRequest, opts ...grpc.CallOption) (*DiagnoseResponse, error)
+	// Wake asks a routing peer to send a Wake-on-LAN magic packet to a machine of its local network
+	Wake(ctx context.Context, in *WakeRequest, opts ...grpc.CallOption) (*WakeResponse, error)
+	// GetDNSCacheStats returns the counters of the cache of upstream DNS answers
+	GetDNSCacheStats(ctx context.Context, in *GetDNSCacheStatsRequest, opts ...grpc.CallOption) (*GetDNSCacheStatsResponse, error)
+	// FlushDNSCache drops the cached upstream DNS answers
+	FlushDNSCache(ctx context.Context, in *FlushDNSCacheRequest, opts ...grpc.CallOption) (*FlushDNSCacheResponse, error)
+	// GetRouteRuleStats returns the counters of the route firewall rules
+	GetRouteRuleStats(ctx context.Context, in *GetRouteRuleStatsRequest, opts ...grpc.CallOption) (*GetRouteRuleStatsResponse, error)
+	// GetRouteMetrics returns how long programming the routes and route firewall rules takes
+	GetRouteMetrics(ctx context.Context, in *GetRouteMetricsRequest, opts ...grpc.CallOption) (*GetRouteMetricsResponse, error)
+	// ReconnectPeer tears down the connection to a peer and connects again
+	ReconnectPeer(ctx context.Context, in *ReconnectPeerRequest, opts ...grpc.CallOption) (*ReconnectPeerResponse, error)
+	// ForceRelayPeer keeps the connection to a peer on the relay, or releases it to ICE again
+	ForceRelayPeer(ctx context.Context, in *ForceRelayPeerRequest, opts ...grpc.CallOption) (*ForceRelayPeerResponse, error)
+	// GetPeerCandidates returns the selected ICE candidate pair of the connection to a peer and the previous ones
+	GetPeerCandidates(ctx context.Context, in *GetPeerCandidatesRequest, opts ...grpc.CallOption) (*GetPeerCandidatesResponse, error)
+	// GetRelayCandidates returns the relay servers with their measured round trip time
+	GetRelayCandidates(ctx context.Context, in *GetRelayCandidatesRequest, opts ...grpc.CallOption) (*GetRelayCandidatesResponse, error)
+	// SetPreferredRelay pins the home relay server, an empty url selects the one with the lowest latency again
+	SetPreferredRelay(ctx context.Context, in *SetPreferredRelayRequest, opts ...grpc.CallOption) (*SetPreferredRelayResponse, error)
+	// GetDNSForwarderStats returns the counters of the cache and the upstream queries of the DNS forwarder of the domain routes
+	GetDNSForwarderStats(ctx context.Context, in *GetDNSForwarderStatsRequest, opts ...grpc.CallOption) (*GetDNSForwarderStatsResponse, error)
+	// ProbePeers sends echo probes through the tunnel to the peers and returns their round trip times and loss
+	ProbePeers(ctx context.Context, in *ProbePeersRequest, opts ...grpc.CallOption) (*ProbePeersResponse, error)
+	// CreateAPIToken creates a token of the monitoring API, the token is only returned once
+	CreateAPIToken(ctx context.Context, in *CreateAPITokenRequest, opts ...grpc.CallOption) (*CreateAPITokenResponse, error)
+	// ListAPITokens lists the tokens of the monitoring API
+	ListAPITokens(ctx context.Context, in *ListAPITokensRequest, opts ...grpc.CallOption) (*ListAPITokensResponse, error)
+	// RevokeAPIToken deletes a token of the monitoring API
+	RevokeAPIToken(ctx context.Context, in *RevokeAPITokenRequest, opts ...grpc.CallOption) (*RevokeAPITokenResponse, error)
+	// GetEffectiveConfig returns the configuration the running engine uses and where each value came from
+	GetEffectiveConfig(ctx context.Context, in *GetEffectiveConfigRequest, opts ...grpc.CallOption) (*GetEffectiveConfigResponse, error)
+	// QueryFlows returns the recently recorded traffic flows, newest first
+	QueryFlows(ctx context.Context, in *QueryFlowsRequest, opts ...grpc.CallOption) (*QueryFlowsResponse, error)
+	// ListInboundPrompts returns the prompts for the first inbound connections of the peers, newest first
+	ListInboundPrompts(ctx context.Context, in *ListInboundPromptsRequest, opts ...grpc.CallOption) (*ListInboundPromptsResponse, error)
+	// RespondInboundPrompt allows or denies the inbound connections of a prompt for a while
+	RespondInboundPrompt(ctx context.Context, in *RespondInboundPromptRequest, opts ...grpc.CallOption) (*RespondInboundPromptResponse, error)
+	// SubscribeWGStats streams the WireGuard transfer of the peers since the previous update and their handshakes
+	SubscribeWGStats(ctx context.Context, in *SubscribeWGStatsRequest, opts ...grpc.CallOption) (DaemonService_SubscribeWGStatsClient, error)
 }
 
 type daemonServiceClient struct {
@@ -163,6 +219,33 @@ func (c *daemonServiceClient) DeselectNetworks(ctx context.Context, in *SelectNe
 	return out, nil
 }
 
+func (c *daemonServiceClient) SetExitNode(ctx context.Context, in *SetExitNodeRequest, opts ...grpc.CallOption) (*SetExitNodeResponse, error) {
+	out := new(SetExitNodeResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/SetExitNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) GetExitNode(ctx context.Context, in *GetExitNodeRequest, opts ...grpc.CallOption) (*GetExitNodeResponse, error) {
+	out := new(GetExitNodeResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/GetExitNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) SetMaintenance(ctx context.Context, in *SetMaintenanceRequest, opts ...grpc.CallOption) (*SetMaintenanceResponse, error) {
+	out := new(SetMaintenanceResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/SetMaintenance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *daemonServiceClient) ForwardingRules(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*ForwardingRulesResponse, error) {
 	out := new(ForwardingRulesResponse)
 	err := c.cc.Invoke(ctx, "/daemon.DaemonService/ForwardingRules", in, out, opts...)
@@ -402,6 +485,254 @@ func (c *daemonServiceClient) GetInstallerResult(ctx context.Context, in *Instal
 	return out, nil
 }
 
+func (c *daemonServiceClient) GetNetworkMap(ctx context.Context, in *GetNetworkMapRequest, opts ...grpc.CallOption) (*GetNetworkMapResponse, error) {
+	out := new(GetNetworkMapResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/GetNetworkMap", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) AddPortForward(ctx context.Context, in *AddPortForwardRequest, opts ...grpc.CallOption) (*AddPortForwardResponse, error) {
+	out := new(AddPortForwardResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/AddPortForward", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) RemovePortForward(ctx context.Context, in *RemovePortForwardRequest, opts ...grpc.CallOption) (*RemovePortForwardResponse, error) {
+	out := new(RemovePortForwardResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/RemovePortForward", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) ListPortForwards(ctx context.Context, in *ListPortForwardsRequest, opts ...grpc.CallOption) (*ListPortForwardsResponse, error) {
+	out := new(ListPortForwardsResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/ListPortForwards", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) Diagnose(ctx context.Context, in *DiagnoseRequest, opts ...grpc.CallOption) (*DiagnoseResponse, error) {
+	out := new(DiagnoseResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/Diagnose", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) Wake(ctx context.Context, in *WakeRequest, opts ...grpc.CallOption) (*WakeResponse, error) {
+	out := new(WakeResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/Wake", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) GetDNSCacheStats(ctx context.Context, in *GetDNSCacheStatsRequest, opts ...grpc.CallOption) (*GetDNSCacheStatsResponse, error) {
+	out := new(GetDNSCacheStatsResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/GetDNSCacheStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) FlushDNSCache(ctx context.Context, in *FlushDNSCacheRequest, opts ...grpc.CallOption) (*FlushDNSCacheResponse, error) {
+	out := new(FlushDNSCacheResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/FlushDNSCache", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) GetRouteRuleStats(ctx context.Context, in *GetRouteRuleStatsRequest, opts ...grpc.CallOption) (*GetRouteRuleStatsResponse, error) {
+	out := new(GetRouteRuleStatsResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/GetRouteRuleStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) GetRouteMetrics(ctx context.Context, in *GetRouteMetricsRequest, opts ...grpc.CallOption) (*GetRouteMetricsResponse, error) {
+	out := new(GetRouteMetricsResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/GetRouteMetrics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) ReconnectPeer(ctx context.Context, in *ReconnectPeerRequest, opts ...grpc.CallOption) (*ReconnectPeerResponse, error) {
+	out := new(ReconnectPeerResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/ReconnectPeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) ForceRelayPeer(ctx context.Context, in *ForceRelayPeerRequest, opts ...grpc.CallOption) (*ForceRelayPeerResponse, error) {
+	out := new(ForceRelayPeerResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/ForceRelayPeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) GetPeerCandidates(ctx context.Context, in *GetPeerCandidatesRequest, opts ...grpc.CallOption) (*GetPeerCandidatesResponse, error) {
+	out := new(GetPeerCandidatesResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/GetPeerCandidates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) GetRelayCandidates(ctx context.Context, in *GetRelayCandidatesRequest, opts ...grpc.CallOption) (*GetRelayCandidatesResponse, error) {
+	out := new(GetRelayCandidatesResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/GetRelayCandidates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) SetPreferredRelay(ctx context.Context, in *SetPreferredRelayRequest, opts ...grpc.CallOption) (*SetPreferredRelayResponse, error) {
+	out := new(SetPreferredRelayResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/SetPreferredRelay", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) GetDNSForwarderStats(ctx context.Context, in *GetDNSForwarderStatsRequest, opts ...grpc.CallOption) (*GetDNSForwarderStatsResponse, error) {
+	out := new(GetDNSForwarderStatsResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/GetDNSForwarderStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) ProbePeers(ctx context.Context, in *ProbePeersRequest, opts ...grpc.CallOption) (*ProbePeersResponse, error) {
+	out := new(ProbePeersResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/ProbePeers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) CreateAPIToken(ctx context.Context, in *CreateAPITokenRequest, opts ...grpc.CallOption) (*CreateAPITokenResponse, error) {
+	out := new(CreateAPITokenResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/CreateAPIToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) ListAPITokens(ctx context.Context, in *ListAPITokensRequest, opts ...grpc.CallOption) (*ListAPITokensResponse, error) {
+	out := new(ListAPITokensResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/ListAPITokens", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) RevokeAPIToken(ctx context.Context, in *RevokeAPITokenRequest, opts ...grpc.CallOption) (*RevokeAPITokenResponse, error) {
+	out := new(RevokeAPITokenResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/RevokeAPIToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) GetEffectiveConfig(ctx context.Context, in *GetEffectiveConfigRequest, opts ...grpc.CallOption) (*GetEffectiveConfigResponse, error) {
+	out := new(GetEffectiveConfigResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/GetEffectiveConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) QueryFlows(ctx context.Context, in *QueryFlowsRequest, opts ...grpc.CallOption) (*QueryFlowsResponse, error) {
+	out := new(QueryFlowsResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/QueryFlows", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) ListInboundPrompts(ctx context.Context, in *ListInboundPromptsRequest, opts ...grpc.CallOption) (*ListInboundPromptsResponse, error) {
+	out := new(ListInboundPromptsResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/ListInboundPrompts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) RespondInboundPrompt(ctx context.Context, in *RespondInboundPromptRequest, opts ...grpc.CallOption) (*RespondInboundPromptResponse, error) {
+	out := new(RespondInboundPromptResponse)
+	err := c.cc.Invoke(ctx, "/daemon.DaemonService/RespondInboundPrompt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) SubscribeWGStats(ctx context.Context, in *SubscribeWGStatsRequest, opts ...grpc.CallOption) (DaemonService_SubscribeWGStatsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DaemonService_ServiceDesc.Streams[1], "/daemon.DaemonService/SubscribeWGStats", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &daemonServiceSubscribeWGStatsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DaemonService_SubscribeWGStatsClient interface {
+	Recv() (*WGStatsUpdate, error)
+	grpc.ClientStream
+}
+
+type daemonServiceSubscribeWGStatsClient struct {
+	grpc.ClientStream
+}
+
+func (x *daemonServiceSubscribeWGStatsClient) Recv() (*WGStatsUpdate, error) {
+	m := new(WGStatsUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // DaemonServiceServer is the server API for DaemonService service.
 // All implementations must embed UnimplementedDaemonServiceServer
 // for forward compatibility
@@ -425,6 +756,12 @@ type DaemonServiceServer interface {
 	SelectNetworks(context.Context, *SelectNetworksRequest) (*SelectNetworksResponse, error)
 	// Deselect specific routes
 	DeselectNetworks(context.Context, *SelectNetworksRequest) (*SelectNetworksResponse, error)
+	// SetExitNode pins a preferred exit node peer or switches back to automatic selection
+	SetExitNode(context.Context, *SetExitNodeRequest) (*SetExitNodeResponse, error)
+	// GetExitNode returns the exit node selection policy and the active exit node
+	GetExitNode(context.Context, *GetExitNodeRequest) (*GetExitNodeResponse, error)
+	// SetMaintenance announces to the connected peers that this peer is about to go down or is back in service
+	SetMaintenance(context.Context, *SetMaintenanceRequest) (*SetMaintenanceResponse, error)
 	ForwardingRules(context.Context, *EmptyRequest) (*ForwardingRulesResponse, error)
 	// DebugBundle creates a debug bundle
 	DebugBundle(context.Context, *DebugBundleRequest) (*DebugBundleResponse, error)
@@ -460,6 +797,56 @@ type DaemonServiceServer interface {
 	WaitJWTToken(context.Context, *WaitJWTTokenRequest) (*WaitJWTTokenResponse, error)
 	NotifyOSLifecycle(context.Context, *OSLifecycleRequest) (*OSLifecycleResponse, error)
 	GetInstallerResult(context.Context, *InstallerResultRequest) (*InstallerResultResponse, error)
+	// GetNetworkMap returns the persisted network map, filtered by peer or route, for troubleshooting
+	GetNetworkMap(context.Context, *GetNetworkMapRequest) (*GetNetworkMapResponse, error)
+	// AddPortForward creates a local listener forwarding connections to a peer address through the netstack
+	AddPortForward(context.Context, *AddPortForwardRequest) (*AddPortForwardResponse, error)
+	// RemovePortForward closes the listener of a port forward
+	RemovePortForward(context.Context, *RemovePortForwardRequest) (*RemovePortForwardResponse, error)
+	// ListPortForwards returns the active port forwards
+	ListPortForwards(context.Context, *ListPortForwardsRequest) (*ListPortForwardsResponse, error)
+	// Diagnose checks the client and the host for well-known failure patterns
+	Diagnose(context.Context, *DiagnoseRequest) (*DiagnoseResponse, error)
+	// Wake asks a routing peer to send a Wake-on-LAN magic packet to a machine of its local network
+	Wake(context.Context, *WakeRequest) (*WakeResponse, error)
+	// GetDNSCacheStats returns the counters of the cache of upstream DNS answers
+	GetDNSCacheStats(context.Context, *GetDNSCacheStatsRequest) (*GetDNSCacheStatsResponse, error)
+	// FlushDNSCache drops the cached upstream DNS answers
+	FlushDNSCache(context.Context, *FlushDNSCacheRequest) (*FlushDNSCacheResponse, error)
+	// GetRouteRuleStats returns the counters of the route firewall rules
+	GetRouteRuleStats(context.Context, *GetRouteRuleStatsRequest) (*GetRouteRuleStatsResponse, error)
+	// GetRouteMetrics returns how long programming the routes and route firewall rules takes
+	GetRouteMetrics(context.Context, *GetRouteMetricsRequest) (*GetRouteMetricsResponse, error)
+	// ReconnectPeer tears down the connection to a peer and connects again
+	ReconnectPeer(context.Context, *ReconnectPeerRequest) (*ReconnectPeerResponse, error)
+	// ForceRelayPeer keeps the connection to a peer on the relay, or releases it to ICE again
+	ForceRelayPeer(context.Context, *ForceRelayPeerRequest) (*ForceRelayPeerResponse, error)
+	// GetPeerCandidates returns the selected ICE candidate pair of the connection to a peer and the previous ones
+	GetPeerCandidates(context.Context, *GetPeerCandidatesRequest) (*GetPeerCandidatesResponse, error)
+	// GetRelayCandidates returns the relay servers with their measured round trip time
+	GetRelayCandidates(context.Context, *GetRelayCandidatesRequest) (*GetRelayCandidatesResponse, error)
+	// SetPreferredRelay pins the home relay server, an empty url selects the one with the lowest latency again
+	SetPreferredRelay(context.Context, *SetPreferredRelayRequest) (*SetPreferredRelayResponse, error)
+	// GetDNSForwarderStats returns the counters of the cache and the upstream queries of the DNS forwarder of the domain routes
+	GetDNSForwarderStats(context.Context, *GetDNSForwarderStatsRequest) (*GetDNSForwarderStatsResponse, error)
+	// ProbePeers sends echo probes through the tunnel to the peers and returns their round trip times and loss
+	ProbePeers(context.Context, *ProbePeersRequest) (*ProbePeersResponse, error)
+	// CreateAPIToken creates a token of the monitoring API, the token is only returned once
+	CreateAPIToken(context.Context, *CreateAPITokenRequest) (*CreateAPITokenResponse, error)
+	// ListAPITokens lists the tokens of the monitoring API
+	ListAPITokens(context.Context, *ListAPITokensRequest) (*ListAPITokensResponse, error)
+	// RevokeAPIToken deletes a token of the monitoring API
+	RevokeAPIToken(context.Context, *RevokeAPITokenRequest) (*RevokeAPITokenResponse, error)
+	// GetEffectiveConfig returns the configuration the running engine uses and where each value came from
+	GetEffectiveConfig(context.Context, *GetEffectiveConfigRequest) (*GetEffectiveConfigResponse, error)
+	// QueryFlows returns the recently recorded traffic flows, newest first
+	QueryFlows(context.Context, *QueryFlowsRequest) (*QueryFlowsResponse, error)
+	// ListInboundPrompts returns the prompts for the first inbound connections of the peers, newest first
+	ListInboundPrompts(context.Context, *ListInboundPromptsRequest) (*ListInboundPromptsResponse, error)
+	// RespondInboundPrompt allows or denies the inbound connections of a prompt for a while
+	RespondInboundPrompt(context.Context, *RespondInboundPromptRequest) (*RespondInboundPromptResponse, error)
+	// SubscribeWGStats streams the WireGuard transfer of the peers since the previous update and their handshakes
+	SubscribeWGStats(*SubscribeWGStatsRequest, DaemonService_SubscribeWGStatsServer) error
 	mustEmbedUnimplementedDaemonServiceServer()
 }
 
@@ -494,6 +881,15 @@ func (UnimplementedDaemonServiceServer) SelectNetworks(context.Context, *SelectN
 func (UnimplementedDaemonServiceServer) DeselectNetworks(context.Context, *SelectNetworksRequest) (*SelectNetworksResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeselectNetworks not implemented")
 }
+func (UnimplementedDaemonServiceServer) SetExitNode(context.Context, *SetExitNodeRequest) (*SetExitNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetExitNode not implemented")
+}
+func (UnimplementedDaemonServiceServer) GetExitNode(context.Context, *GetExitNodeRequest) (*GetExitNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetExitNode not implemented")
+}
+func (UnimplementedDaemonServiceServer) SetMaintenance(context.Context, *SetMaintenanceRequest) (*SetMaintenanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMaintenance not implemented")
+}
 func (UnimplementedDaemonServiceServer) ForwardingRules(context.Context, *EmptyRequest) (*ForwardingRulesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ForwardingRules not implemented")
 }
@@ -566,6 +962,81 @@ func (UnimplementedDaemonServiceServer) NotifyOSLifecycle(context.Context, *OSLi
 func (UnimplementedDaemonServiceServer) GetInstallerResult(context.Context, *InstallerResultRequest) (*InstallerResultResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetInstallerResult not implemented")
 }
+func (UnimplementedDaemonServiceServer) GetNetworkMap(context.Context, *GetNetworkMapRequest) (*GetNetworkMapResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNetworkMap not implemented")
+}
+func (UnimplementedDaemonServiceServer) AddPortForward(context.Context, *AddPortForwardRequest) (*AddPortForwardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddPortForward not implemented")
+}
+func (UnimplementedDaemonServiceServer) RemovePortForward(context.Context, *RemovePortForwardRequest) (*RemovePortForwardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemovePortForward not implemented")
+}
+func (UnimplementedDaemonServiceServer) ListPortForwards(context.Context, *ListPortForwardsRequest) (*ListPortForwardsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPortForwards not implemented")
+}
+func (UnimplementedDaemonServiceServer) Diagnose(context.Context, *DiagnoseRequest) (*DiagnoseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Diagnose not implemented")
+}
+func (UnimplementedDaemonServiceServer) Wake(context.Context, *WakeRequest) (*WakeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Wake not implemented")
+}
+func (UnimplementedDaemonServiceServer) GetDNSCacheStats(context.Context, *GetDNSCacheStatsRequest) (*GetDNSCacheStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDNSCacheStats not implemented")
+}
+func (UnimplementedDaemonServiceServer) FlushDNSCache(context.Context, *FlushDNSCacheRequest) (*FlushDNSCacheResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FlushDNSCache not implemented")
+}
+func (UnimplementedDaemonServiceServer) GetRouteRuleStats(context.Context, *GetRouteRuleStatsRequest) (*GetRouteRuleStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRouteRuleStats not implemented")
+}
+func (UnimplementedDaemonServiceServer) GetRouteMetrics(context.Context, *GetRouteMetricsRequest) (*GetRouteMetricsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRouteMetrics not implemented")
+}
+func (UnimplementedDaemonServiceServer) ReconnectPeer(context.Context, *ReconnectPeerRequest) (*ReconnectPeerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReconnectPeer not implemented")
+}
+func (UnimplementedDaemonServiceServer) ForceRelayPeer(context.Context, *ForceRelayPeerRequest) (*ForceRelayPeerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForceRelayPeer not implemented")
+}
+func (UnimplementedDaemonServiceServer) GetPeerCandidates(context.Context, *GetPeerCandidatesRequest) (*GetPeerCandidatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeerCandidates not implemented")
+}
+func (UnimplementedDaemonServiceServer) GetRelayCandidates(context.Context, *GetRelayCandidatesRequest) (*GetRelayCandidatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRelayCandidates not implemented")
+}
+func (UnimplementedDaemonServiceServer) SetPreferredRelay(context.Context, *SetPreferredRelayRequest) (*SetPreferredRelayResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPreferredRelay not implemented")
+}
+func (UnimplementedDaemonServiceServer) GetDNSForwarderStats(context.Context, *GetDNSForwarderStatsRequest) (*GetDNSForwarderStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDNSForwarderStats not implemented")
+}
+func (UnimplementedDaemonServiceServer) ProbePeers(context.Context, *ProbePeersRequest) (*ProbePeersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProbePeers not implemented")
+}
+func (UnimplementedDaemonServiceServer) CreateAPIToken(context.Context, *CreateAPITokenRequest) (*CreateAPITokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAPIToken not implemented")
+}
+func (UnimplementedDaemonServiceServer) ListAPITokens(context.Context, *ListAPITokensRequest) (*ListAPITokensResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAPITokens not implemented")
+}
+func (UnimplementedDaemonServiceServer) RevokeAPIToken(context.Context, *RevokeAPITokenRequest) (*RevokeAPITokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAPIToken not implemented")
+}
+func (UnimplementedDaemonServiceServer) GetEffectiveConfig(context.Context, *GetEffectiveConfigRequest) (*GetEffectiveConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEffectiveConfig not implemented")
+}
+func (UnimplementedDaemonServiceServer) QueryFlows(context.Context, *QueryFlowsRequest) (*QueryFlowsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryFlows not implemented")
+}
+func (UnimplementedDaemonServiceServer) ListInboundPrompts(context.Context, *ListInboundPromptsRequest) (*ListInboundPromptsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInboundPrompts not implemented")
+}
+func (UnimplementedDaemonServiceServer) RespondInboundPrompt(context.Context, *RespondInboundPromptRequest) (*RespondInboundPromptResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RespondInboundPrompt not implemented")
+}
+func (UnimplementedDaemonServiceServer) SubscribeWGStats(*SubscribeWGStatsRequest, DaemonService_SubscribeWGStatsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeWGStats not implemented")
+}
 func (UnimplementedDaemonServiceServer) mustEmbedUnimplementedDaemonServiceServer() {}
 
 // UnsafeDaemonServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -741,6 +1212,60 @@ func _DaemonService_DeselectNetworks_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DaemonService_SetExitNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetExitNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).SetExitNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/SetExitNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).SetExitNode(ctx, req.(*SetExitNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_GetExitNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExitNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).GetExitNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/GetExitNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).GetExitNode(ctx, req.(*GetExitNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_SetMaintenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMaintenanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).SetMaintenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/SetMaintenance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).SetMaintenance(ctx, req.(*SetMaintenanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _DaemonService_ForwardingRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(EmptyRequest)
 	if err := dec(in); err != nil {
@@ -1176,9 +1701,462 @@ func _DaemonService_GetInstallerResult_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
-// DaemonService_ServiceDesc is the grpc.ServiceDesc for DaemonService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
+func _DaemonService_GetNetworkMap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNetworkMapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).GetNetworkMap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/GetNetworkMap",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).GetNetworkMap(ctx, req.(*GetNetworkMapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_AddPortForward_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddPortForwardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).AddPortForward(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/AddPortForward",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).AddPortForward(ctx, req.(*AddPortForwardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_RemovePortForward_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemovePortForwardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).RemovePortForward(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/RemovePortForward",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).RemovePortForward(ctx, req.(*RemovePortForwardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_ListPortForwards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPortForwardsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).ListPortForwards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/ListPortForwards",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).ListPortForwards(ctx, req.(*ListPortForwardsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_Diagnose_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiagnoseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).Diagnose(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/Diagnose",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).Diagnose(ctx, req.(*DiagnoseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_Wake_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).Wake(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/Wake",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).Wake(ctx, req.(*WakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_GetDNSCacheStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDNSCacheStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).GetDNSCacheStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/GetDNSCacheStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).GetDNSCacheStats(ctx, req.(*GetDNSCacheStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_FlushDNSCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FlushDNSCacheRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).FlushDNSCache(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/FlushDNSCache",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).FlushDNSCache(ctx, req.(*FlushDNSCacheRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_GetRouteRuleStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRouteRuleStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).GetRouteRuleStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/GetRouteRuleStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).GetRouteRuleStats(ctx, req.(*GetRouteRuleStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_GetRouteMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRouteMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).GetRouteMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/GetRouteMetrics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).GetRouteMetrics(ctx, req.(*GetRouteMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_ReconnectPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReconnectPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).ReconnectPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/ReconnectPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).ReconnectPeer(ctx, req.(*ReconnectPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_ForceRelayPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForceRelayPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).ForceRelayPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/ForceRelayPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).ForceRelayPeer(ctx, req.(*ForceRelayPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_GetPeerCandidates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPeerCandidatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).GetPeerCandidates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/GetPeerCandidates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).GetPeerCandidates(ctx, req.(*GetPeerCandidatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_GetRelayCandidates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRelayCandidatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).GetRelayCandidates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/GetRelayCandidates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).GetRelayCandidates(ctx, req.(*GetRelayCandidatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_SetPreferredRelay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPreferredRelayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).SetPreferredRelay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/SetPreferredRelay",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).SetPreferredRelay(ctx, req.(*SetPreferredRelayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_GetDNSForwarderStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDNSForwarderStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).GetDNSForwarderStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/GetDNSForwarderStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).GetDNSForwarderStats(ctx, req.(*GetDNSForwarderStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_ProbePeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProbePeersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).ProbePeers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/ProbePeers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).ProbePeers(ctx, req.(*ProbePeersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_CreateAPIToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAPITokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).CreateAPIToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/CreateAPIToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).CreateAPIToken(ctx, req.(*CreateAPITokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_ListAPITokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAPITokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).ListAPITokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/ListAPITokens",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).ListAPITokens(ctx, req.(*ListAPITokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_RevokeAPIToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAPITokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).RevokeAPIToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/RevokeAPIToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).RevokeAPIToken(ctx, req.(*RevokeAPITokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_GetEffectiveConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEffectiveConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).GetEffectiveConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/GetEffectiveConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).GetEffectiveConfig(ctx, req.(*GetEffectiveConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_QueryFlows_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryFlowsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).QueryFlows(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/QueryFlows",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).QueryFlows(ctx, req.(*QueryFlowsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_ListInboundPrompts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInboundPromptsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).ListInboundPrompts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/ListInboundPrompts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).ListInboundPrompts(ctx, req.(*ListInboundPromptsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_RespondInboundPrompt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RespondInboundPromptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).RespondInboundPrompt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemon.DaemonService/RespondInboundPrompt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).RespondInboundPrompt(ctx, req.(*RespondInboundPromptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_SubscribeWGStats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeWGStatsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DaemonServiceServer).SubscribeWGStats(m, &daemonServiceSubscribeWGStatsServer{stream})
+}
+
+type DaemonService_SubscribeWGStatsServer interface {
+	Send(*WGStatsUpdate) error
+	grpc.ServerStream
+}
+
+type daemonServiceSubscribeWGStatsServer struct {
+	grpc.ServerStream
+}
+
+func (x *daemonServiceSubscribeWGStatsServer) Send(m *WGStatsUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// DaemonService_ServiceDesc is the grpc.ServiceDesc for DaemonService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
 var DaemonService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "daemon.DaemonService",
 	HandlerType: (*DaemonServiceServer)(nil),
@@ -1219,6 +2197,18 @@ var DaemonService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeselectNetworks",
 			Handler:    _DaemonService_DeselectNetworks_Handler,
 		},
+		{
+			MethodName: "SetExitNode",
+			Handler:    _DaemonService_SetExitNode_Handler,
+		},
+		{
+			MethodName: "GetExitNode",
+			Handler:    _DaemonService_GetExitNode_Handler,
+		},
+		{
+			MethodName: "SetMaintenance",
+			Handler:    _DaemonService_SetMaintenance_Handler,
+		},
 		{
 			MethodName: "ForwardingRules",
 			Handler:    _DaemonService_ForwardingRules_Handler,
@@ -1311,6 +2301,102 @@ var DaemonService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetInstallerResult",
 			Handler:    _DaemonService_GetInstallerResult_Handler,
 		},
+		{
+			MethodName: "GetNetworkMap",
+			Handler:    _DaemonService_GetNetworkMap_Handler,
+		},
+		{
+			MethodName: "AddPortForward",
+			Handler:    _DaemonService_AddPortForward_Handler,
+		},
+		{
+			MethodName: "RemovePortForward",
+			Handler:    _DaemonService_RemovePortForward_Handler,
+		},
+		{
+			MethodName: "ListPortForwards",
+			Handler:    _DaemonService_ListPortForwards_Handler,
+		},
+		{
+			MethodName: "Diagnose",
+			Handler:    _DaemonService_Diagnose_Handler,
+		},
+		{
+			MethodName: "Wake",
+			Handler:    _DaemonService_Wake_Handler,
+		},
+		{
+			MethodName: "GetDNSCacheStats",
+			Handler:    _DaemonService_GetDNSCacheStats_Handler,
+		},
+		{
+			MethodName: "FlushDNSCache",
+			Handler:    _DaemonService_FlushDNSCache_Handler,
+		},
+		{
+			MethodName: "GetRouteRuleStats",
+			Handler:    _DaemonService_GetRouteRuleStats_Handler,
+		},
+		{
+			MethodName: "GetRouteMetrics",
+			Handler:    _DaemonService_GetRouteMetrics_Handler,
+		},
+		{
+			MethodName: "ReconnectPeer",
+			Handler:    _DaemonService_ReconnectPeer_Handler,
+		},
+		{
+			MethodName: "ForceRelayPeer",
+			Handler:    _DaemonService_ForceRelayPeer_Handler,
+		},
+		{
+			MethodName: "GetPeerCandidates",
+			Handler:    _DaemonService_GetPeerCandidates_Handler,
+		},
+		{
+			MethodName: "GetRelayCandidates",
+			Handler:    _DaemonService_GetRelayCandidates_Handler,
+		},
+		{
+			MethodName: "SetPreferredRelay",
+			Handler:    _DaemonService_SetPreferredRelay_Handler,
+		},
+		{
+			MethodName: "GetDNSForwarderStats",
+			Handler:    _DaemonService_GetDNSForwarderStats_Handler,
+		},
+		{
+			MethodName: "ProbePeers",
+			Handler:    _DaemonService_ProbePeers_Handler,
+		},
+		{
+			MethodName: "CreateAPIToken",
+			Handler:    _DaemonService_CreateAPIToken_Handler,
+		},
+		{
+			MethodName: "ListAPITokens",
+			Handler:    _DaemonService_ListAPITokens_Handler,
+		},
+		{
+			MethodName: "RevokeAPIToken",
+			Handler:    _DaemonService_RevokeAPIToken_Handler,
+		},
+		{
+			MethodName: "GetEffectiveConfig",
+			Handler:    _DaemonService_GetEffectiveConfig_Handler,
+		},
+		{
+			MethodName: "QueryFlows",
+			Handler:    _DaemonService_QueryFlows_Handler,
+		},
+		{
+			MethodName: "ListInboundPrompts",
+			Handler:    _DaemonService_ListInboundPrompts_Handler,
+		},
+		{
+			MethodName: "RespondInboundPrompt",
+			Handler:    _DaemonService_RespondInboundPrompt_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1318,6 +2404,11 @@ var DaemonService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _DaemonService_SubscribeEvents_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeWGStats",
+			Handler:       _DaemonService_SubscribeWGStats_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "daemon.proto",
 }
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	gstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/netbirdio/netbird/client/internal/apitoken"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// apiTokensFile is the file of the monitoring API tokens in the config directory
+const apiTokensFile = "api_tokens.json"
+
+// monitoringScopes are the methods of the monitoring API and the scope they require, other methods are denied
+var monitoringScopes = map[string]apitoken.Scope{
+	"/daemon.DaemonService/Status":               apitoken.ScopeStatusRead,
+	"/daemon.DaemonService/GetFeatures":          apitoken.ScopeStatusRead,
+	"/daemon.DaemonService/ListNetworks":         apitoken.ScopeStatusRead,
+	"/daemon.DaemonService/ForwardingRules":      apitoken.ScopeStatusRead,
+	"/daemon.DaemonService/GetRouteMetrics":      apitoken.ScopeStatusRead,
+	"/daemon.DaemonService/GetRouteRuleStats":    apitoken.ScopeStatusRead,
+	"/daemon.DaemonService/GetDNSCacheStats":     apitoken.ScopeStatusRead,
+	"/daemon.DaemonService/GetDNSForwarderStats": apitoken.ScopeStatusRead,
+	"/daemon.DaemonService/GetPeerCandidates":    apitoken.ScopeStatusRead,
+	"/daemon.DaemonService/GetRelayCandidates":   apitoken.ScopeStatusRead,
+	"/daemon.DaemonService/SubscribeWGStats":     apitoken.ScopeStatusRead,
+	"/daemon.DaemonService/GetEvents":            apitoken.ScopeEventsRead,
+	"/daemon.DaemonService/SubscribeEvents":      apitoken.ScopeEventsRead,
+}
+
+// CreateAPIToken creates a token of the monitoring API, the token is only returned once
+func (s *Server) CreateAPIToken(ctx context.Context, req *proto.CreateAPITokenRequest) (*proto.CreateAPITokenResponse, error) {
+	var scopes []apitoken.Scope
+	for _, name := range req.GetScopes() {
+		scope, err := apitoken.ParseScope(name)
+		if err != nil {
+			return nil, gstatus.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		scopes = append(scopes, scope)
+	}
+
+	secret, token, err := s.apiTokens.Create(ctx, req.GetName(), scopes)
+	if err != nil {
+		return nil, gstatus.Errorf(codes.Internal, "create API token: %v", err)
+	}
+	log.Infof("created API token %s (%s) with the scopes %v", token.ID, token.Name, token.Scopes)
+
+	return &proto.CreateAPITokenResponse{Token: toProtoAPIToken(token), Secret: secret}, nil
+}
+
+// ListAPITokens lists the tokens of the monitoring API
+func (s *Server) ListAPITokens(context.Context, *proto.ListAPITokensRequest) (*proto.ListAPITokensResponse, error) {
+	tokens, err := s.apiTokens.List()
+	if err != nil {
+		return nil, gstatus.Errorf(codes.Internal, "list API tokens: %v", err)
+	}
+
+	resp := &proto.ListAPITokensResponse{}
+	for _, token := range tokens {
+		resp.Tokens = append(resp.Tokens, toProtoAPIToken(token))
+	}
+	return resp, nil
+}
+
+// RevokeAPIToken deletes a token of the monitoring API
+func (s *Server) RevokeAPIToken(ctx context.Context, req *proto.RevokeAPITokenRequest) (*proto.RevokeAPITokenResponse, error) {
+	if req.GetToken() == "" {
+		return nil, gstatus.Errorf(codes.InvalidArgument, "token is required")
+	}
+
+	token, err := s.apiTokens.Revoke(ctx, req.GetToken())
+	if errors.Is(err, apitoken.ErrNotFound) {
+		return nil, gstatus.Errorf(codes.NotFound, "%v", err)
+	}
+	if err != nil {
+		return nil, gstatus.Errorf(codes.Internal, "revoke API token: %v", err)
+	}
+	log.Infof("revoked API token %s (%s)", token.ID, token.Name)
+
+	return &proto.RevokeAPITokenResponse{Token: toProtoAPIToken(token)}, nil
+}
+
+func toProtoAPIToken(token apitoken.Token) *proto.APIToken {
+	pbToken := &proto.APIToken{
+		Id:        token.ID,
+		Name:      token.Name,
+		CreatedAt: timestamppb.New(token.CreatedAt),
+	}
+	for _, scope := range token.Scopes {
+		pbToken.Scopes = append(pbToken.Scopes, string(scope))
+	}
+	return pbToken
+}
+
+// MonitoringServerOptions returns the options of the gRPC server of the monitoring API. Every request has to present
+// a token with the scope of the method as bearer token in the authorization metadata.
+func (s *Server) MonitoringServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if err := s.authorizeMonitoring(ctx, info.FullMethod); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := s.authorizeMonitoring(ss.Context(), info.FullMethod); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	}
+}
+
+// authorizeMonitoring checks that the request presents a token with the scope of the method
+func (s *Server) authorizeMonitoring(ctx context.Context, method string) error {
+	scope, ok := monitoringScopes[method]
+	if !ok {
+		return gstatus.Errorf(codes.PermissionDenied, "%s is not part of the monitoring API", method)
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	var presented string
+	for _, value := range md.Get("authorization") {
+		if token, ok := strings.CutPrefix(value, "Bearer "); ok {
+			presented = strings.TrimSpace(token)
+			break
+		}
+	}
+	if presented == "" {
+		return gstatus.Error(codes.Unauthenticated, "API token is required")
+	}
+
+	token, err := s.apiTokens.Authenticate(presented)
+	if errors.Is(err, apitoken.ErrInvalidToken) {
+		return gstatus.Error(codes.Unauthenticated, "invalid API token")
+	}
+	if err != nil {
+		log.Errorf("failed to authenticate API token: %v", err)
+		return gstatus.Error(codes.Internal, "failed to authenticate API token")
+	}
+
+	if !token.HasScope(scope) {
+		return gstatus.Errorf(codes.PermissionDenied, "API token %s lacks the %s scope", token.ID, scope)
+	}
+	return nil
+}
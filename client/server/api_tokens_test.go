@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	gstatus "google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/internal/apitoken"
+)
+
+func TestAuthorizeMonitoring(t *testing.T) {
+	s := &Server{apiTokens: apitoken.NewStore(filepath.Join(t.TempDir(), apiTokensFile))}
+	statusToken, _, err := s.apiTokens.Create(context.Background(), "status", []apitoken.Scope{apitoken.ScopeStatusRead})
+	require.NoError(t, err)
+
+	withToken := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		method string
+		code   codes.Code
+	}{
+		{name: "scope granted", ctx: withToken(statusToken), method: "/daemon.DaemonService/Status", code: codes.OK},
+		{name: "scope missing", ctx: withToken(statusToken), method: "/daemon.DaemonService/SubscribeEvents", code: codes.PermissionDenied},
+		{name: "not a monitoring method", ctx: withToken(statusToken), method: "/daemon.DaemonService/Down", code: codes.PermissionDenied},
+		{name: "no token", ctx: context.Background(), method: "/daemon.DaemonService/Status", code: codes.Unauthenticated},
+		{name: "invalid token", ctx: withToken("nbt_00000000_secret"), method: "/daemon.DaemonService/Status", code: codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.authorizeMonitoring(tt.ctx, tt.method)
+			assert.Equal(t, tt.code, gstatus.Code(err))
+		})
+	}
+}
@@ -13,8 +13,12 @@ import (
 	"os"
 
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
 
+	"github.com/netbirdio/netbird/client/internal"
 	"github.com/netbirdio/netbird/client/internal/debug"
+	"github.com/netbirdio/netbird/client/internal/logging"
 	"github.com/netbirdio/netbird/client/proto"
 	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
 	"github.com/netbirdio/netbird/upload-server/types"
@@ -38,6 +42,7 @@ func (s *Server) DebugBundle(_ context.Context, req *proto.DebugBundleRequest) (
 			StatusRecorder: s.statusRecorder,
 			SyncResponse:   syncResponse,
 			LogFile:        s.logFile,
+			EventHistory:   s.eventHistory,
 		},
 		debug.BundleConfig{
 			Anonymize:         req.GetAnonymize(),
@@ -157,8 +162,13 @@ func (s *Server) GetLogLevel(_ context.Context, _ *proto.GetLogLevelRequest) (*p
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	level := ParseLogLevel(log.GetLevel().String())
-	return &proto.GetLogLevelResponse{Level: level}, nil
+	subsystemLevels := make(map[string]proto.LogLevel)
+	for subsystem, level := range logging.SubsystemLevels() {
+		subsystemLevels[string(subsystem)] = ParseLogLevel(level.String())
+	}
+
+	level := ParseLogLevel(logging.Level().String())
+	return &proto.GetLogLevelResponse{Level: level, SubsystemLevels: subsystemLevels}, nil
 }
 
 // SetLogLevel sets the logging level for the server.
@@ -166,12 +176,16 @@ func (s *Server) SetLogLevel(_ context.Context, req *proto.SetLogLevelRequest) (
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
+	if req.GetSubsystem() != "" {
+		return s.setSubsystemLogLevel(req)
+	}
+
 	level, err := log.ParseLevel(req.Level.String())
 	if err != nil {
 		return nil, fmt.Errorf("invalid log level: %w", err)
 	}
 
-	log.SetLevel(level)
+	logging.SetLevel(level)
 
 	if s.connectClient == nil {
 		return nil, fmt.Errorf("connect client not initialized")
@@ -186,13 +200,41 @@ func (s *Server) SetLogLevel(_ context.Context, req *proto.SetLogLevelRequest) (
 		return nil, fmt.Errorf("firewall manager not initialized")
 	}
 
-	fwManager.SetLogLevel(level)
+	fwManager.SetLogLevel(logging.SubsystemLevel(logging.Firewall))
 
 	log.Infof("Log level set to %s", level.String())
 
 	return &proto.SetLogLevelResponse{}, nil
 }
 
+func (s *Server) setSubsystemLogLevel(req *proto.SetLogLevelRequest) (*proto.SetLogLevelResponse, error) {
+	subsystem, err := logging.ParseSubsystem(req.GetSubsystem())
+	if err != nil {
+		return nil, gstatus.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if req.GetResetSubsystem() {
+		logging.ResetSubsystemLevel(subsystem)
+		log.Infof("Log level of %s reset to the global level", subsystem)
+	} else {
+		level, err := log.ParseLevel(req.Level.String())
+		if err != nil {
+			return nil, gstatus.Errorf(codes.InvalidArgument, "invalid log level: %v", err)
+		}
+		logging.SetSubsystemLevel(subsystem, level)
+		log.Infof("Log level of %s set to %s", subsystem, level)
+	}
+
+	// the userspace firewall has its own logger
+	if subsystem == logging.Firewall && s.connectClient != nil {
+		if engine := s.connectClient.Engine(); engine != nil && engine.GetFirewallManager() != nil {
+			engine.GetFirewallManager().SetLogLevel(logging.SubsystemLevel(logging.Firewall))
+		}
+	}
+
+	return &proto.SetLogLevelResponse{}, nil
+}
+
 // SetSyncResponsePersistence sets the sync response persistence for the server.
 func (s *Server) SetSyncResponsePersistence(_ context.Context, req *proto.SetSyncResponsePersistenceRequest) (*proto.SetSyncResponsePersistenceResponse, error) {
 	s.mutex.Lock()
@@ -215,3 +257,37 @@ func (s *Server) getLatestSyncResponse() (*mgmProto.SyncResponse, error) {
 
 	return cClient.GetLatestSyncResponse()
 }
+
+// GetNetworkMap returns the persisted network map as JSON, filtered by peer or route
+func (s *Server) GetNetworkMap(_ context.Context, req *proto.GetNetworkMapRequest) (*proto.GetNetworkMapResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.persistSyncResponse {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "sync response persistence is disabled, enable it with 'netbird debug persistence on'")
+	}
+
+	syncResponse, err := s.getLatestSyncResponse()
+	if err != nil {
+		return nil, gstatus.Errorf(codes.Unavailable, "get latest sync response: %v", err)
+	}
+	if syncResponse.GetNetworkMap() == nil {
+		return nil, gstatus.Errorf(codes.NotFound, "no network map received yet")
+	}
+
+	inspection, err := internal.InspectNetworkMap(syncResponse.GetNetworkMap(), internal.NetworkMapFilter{
+		Peer:  req.GetPeer(),
+		Route: req.GetRoute(),
+	})
+	if err != nil {
+		return nil, gstatus.Errorf(codes.Internal, "inspect network map: %v", err)
+	}
+
+	return &proto.GetNetworkMapResponse{
+		Serial:        inspection.Serial,
+		NetworkMap:    inspection.NetworkMap,
+		Routes:        inspection.Routes,
+		DnsConfig:     inspection.DNSConfig,
+		FirewallRules: inspection.FirewallRules,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/netbirdio/netbird/client/internal"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// GetDNSCacheStats returns the counters of the cache of upstream DNS answers
+func (s *Server) GetDNSCacheStats(_ context.Context, _ *proto.GetDNSCacheStatsRequest) (*proto.GetDNSCacheStatsResponse, error) {
+	engine, err := s.runningEngine()
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := engine.DNSCacheStats()
+	if err != nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "get DNS cache stats: %v", err)
+	}
+
+	return &proto.GetDNSCacheStatsResponse{
+		Entries: uint32(stats.Entries),
+		Hits:    stats.Hits,
+		Misses:  stats.Misses,
+	}, nil
+}
+
+// FlushDNSCache drops the cached upstream DNS answers
+func (s *Server) FlushDNSCache(_ context.Context, _ *proto.FlushDNSCacheRequest) (*proto.FlushDNSCacheResponse, error) {
+	engine, err := s.runningEngine()
+	if err != nil {
+		return nil, err
+	}
+
+	flushed, err := engine.FlushDNSCache()
+	if err != nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "flush DNS cache: %v", err)
+	}
+
+	return &proto.FlushDNSCacheResponse{Flushed: uint32(flushed)}, nil
+}
+
+// GetDNSForwarderStats returns the counters of the cache and the upstream queries of the DNS forwarder
+func (s *Server) GetDNSForwarderStats(_ context.Context, _ *proto.GetDNSForwarderStatsRequest) (*proto.GetDNSForwarderStatsResponse, error) {
+	engine, err := s.runningEngine()
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := engine.DNSForwarderStats()
+	if err != nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "get DNS forwarder stats: %v", err)
+	}
+
+	return &proto.GetDNSForwarderStatsResponse{
+		CacheEntries:       uint32(stats.CacheEntries),
+		CacheHits:          stats.CacheHits,
+		NegativeHits:       stats.NegativeHits,
+		CacheMisses:        stats.CacheMisses,
+		StaleAnswers:       stats.StaleAnswers,
+		UpstreamQueries:    stats.UpstreamQueries,
+		UpstreamFailures:   stats.UpstreamFailures,
+		UpstreamLatency:    durationpb.New(stats.UpstreamLatency),
+		UpstreamLatencyMax: durationpb.New(stats.UpstreamLatencyMax),
+	}, nil
+}
+
+func (s *Server) runningEngine() (*internal.Engine, error) {
+	s.mutex.Lock()
+	connectClient := s.connectClient
+	s.mutex.Unlock()
+
+	if connectClient == nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "client is not running")
+	}
+
+	engine := connectClient.Engine()
+	if engine == nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "engine is not running")
+	}
+	return engine, nil
+}
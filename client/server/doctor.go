@@ -0,0 +1,40 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// Diagnose checks the running engine and the host for well-known failure patterns
+func (s *Server) Diagnose(context.Context, *proto.DiagnoseRequest) (*proto.DiagnoseResponse, error) {
+	s.mutex.Lock()
+	connectClient := s.connectClient
+	s.mutex.Unlock()
+
+	if connectClient == nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "client is not running")
+	}
+
+	engine := connectClient.Engine()
+	if engine == nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "engine is not running")
+	}
+
+	issues := engine.Diagnose()
+
+	resp := &proto.DiagnoseResponse{Issues: make([]*proto.DiagnosedIssue, 0, len(issues))}
+	for _, issue := range issues {
+		resp.Issues = append(resp.Issues, &proto.DiagnosedIssue{
+			Id:          issue.ID,
+			Severity:    string(issue.Severity),
+			Title:       issue.Title,
+			Details:     issue.Details,
+			Remediation: issue.Remediation,
+		})
+	}
+	return resp, nil
+}
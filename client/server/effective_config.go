@@ -0,0 +1,36 @@
+package server
+
+import (
+	"context"
+
+	"github.com/netbirdio/netbird/client/internal"
+	"github.com/netbirdio/netbird/client/proto"
+	"github.com/netbirdio/netbird/version"
+)
+
+// GetEffectiveConfig returns the configuration the running engine uses and where each value came from
+func (s *Server) GetEffectiveConfig(_ context.Context, _ *proto.GetEffectiveConfigRequest) (*proto.GetEffectiveConfigResponse, error) {
+	engine, err := s.runningEngine()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := engine.EffectiveConfig()
+	return &proto.GetEffectiveConfigResponse{
+		Settings: toProtoEffectiveSettings(cfg.Settings),
+		Features: toProtoEffectiveSettings(cfg.Features),
+		Version:  version.NetbirdVersion(),
+	}, nil
+}
+
+func toProtoEffectiveSettings(settings []internal.EffectiveSetting) []*proto.EffectiveSetting {
+	pbSettings := make([]*proto.EffectiveSetting, 0, len(settings))
+	for _, setting := range settings {
+		pbSettings = append(pbSettings, &proto.EffectiveSetting{
+			Name:   setting.Name,
+			Value:  setting.Value,
+			Source: string(setting.Source),
+		})
+	}
+	return pbSettings
+}
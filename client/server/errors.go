@@ -0,0 +1,19 @@
+package server
+
+import (
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/internal/daemonerr"
+)
+
+// loginError returns the error of a login that management rejected with the reason of the rejection. Other errors
+// are returned as they are.
+func loginError(err error) error {
+	st, ok := gstatus.FromError(err)
+	if !ok || (st.Code() != codes.InvalidArgument && st.Code() != codes.PermissionDenied) {
+		return err
+	}
+
+	return daemonerr.Status(st.Code(), daemonerr.ReasonAuthRequired, st.Message())
+}
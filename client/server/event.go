@@ -5,11 +5,15 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/netbirdio/netbird/client/internal/peer"
 	"github.com/netbirdio/netbird/client/proto"
 )
 
 func (s *Server) SubscribeEvents(req *proto.SubscribeRequest, stream proto.DaemonService_SubscribeEventsServer) error {
-	subscription := s.statusRecorder.SubscribeToEvents()
+	subscription := s.statusRecorder.SubscribeToEvents(peer.EventFilter{
+		MinSeverity: req.GetMinSeverity(),
+		Categories:  req.GetCategories(),
+	})
 	defer func() {
 		s.statusRecorder.UnsubscribeFromEvents(subscription)
 		log.Debug("client unsubscribed from events")
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// QueryFlows returns the recently recorded traffic flows, newest first
+func (s *Server) QueryFlows(_ context.Context, req *proto.QueryFlowsRequest) (*proto.QueryFlowsResponse, error) {
+	engine, err := s.runningEngine()
+	if err != nil {
+		return nil, err
+	}
+
+	query := nftypes.EventQuery{
+		Limit: int(req.GetLimit()),
+	}
+	if req.GetPort() > 0xffff {
+		return nil, gstatus.Errorf(codes.InvalidArgument, "invalid port %d", req.GetPort())
+	}
+	query.Port = uint16(req.GetPort())
+	if req.GetSince() != nil {
+		query.Since = req.GetSince().AsTime()
+	}
+	if req.GetUntil() != nil {
+		query.Until = req.GetUntil().AsTime()
+	}
+
+	if query.Protocol, err = parseFlowProtocol(req.GetProtocol()); err != nil {
+		return nil, gstatus.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if req.GetPeer() != "" {
+		addr, err := resolveFlowPeer(s.statusRecorder.GetFullStatus().Peers, req.GetPeer())
+		if err != nil {
+			return nil, gstatus.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		query.Addrs = []netip.Addr{addr}
+	}
+
+	events, err := engine.QueryFlows(query)
+	if err != nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "query flows: %v", err)
+	}
+
+	flows := make([]*proto.FlowRecord, 0, len(events))
+	for _, event := range events {
+		flows = append(flows, toProtoFlowRecord(event))
+	}
+	return &proto.QueryFlowsResponse{Flows: flows}, nil
+}
+
+// parseFlowProtocol parses the protocol name or number, empty matches all protocols
+func parseFlowProtocol(protocol string) (nftypes.Protocol, error) {
+	switch strings.ToLower(protocol) {
+	case "", "all":
+		return nftypes.ProtocolUnknown, nil
+	case "tcp":
+		return nftypes.TCP, nil
+	case "udp":
+		return nftypes.UDP, nil
+	case "icmp":
+		return nftypes.ICMP, nil
+	case "sctp":
+		return nftypes.SCTP, nil
+	}
+
+	number, err := strconv.ParseUint(protocol, 10, 8)
+	if err != nil {
+		return nftypes.ProtocolUnknown, fmt.Errorf("invalid protocol %q", protocol)
+	}
+	return nftypes.Protocol(number), nil
+}
+
+// resolveFlowPeer returns the NetBird IP of the peer with the FQDN, NetBird IP or public key, or the address itself
+func resolveFlowPeer(peers []peer.State, target string) (netip.Addr, error) {
+	if addr, err := netip.ParseAddr(target); err == nil {
+		return addr.Unmap(), nil
+	}
+
+	target = strings.TrimSuffix(strings.ToLower(target), ".")
+	for _, p := range peers {
+		fqdn := strings.TrimSuffix(strings.ToLower(p.FQDN), ".")
+		short, _, _ := strings.Cut(fqdn, ".")
+		if target != fqdn && target != short && target != strings.ToLower(p.PubKey) {
+			continue
+		}
+		addr, err := netip.ParseAddr(p.IP)
+		if err != nil {
+			return netip.Addr{}, fmt.Errorf("peer %s has no valid IP: %w", target, err)
+		}
+		return addr, nil
+	}
+	return netip.Addr{}, fmt.Errorf("peer %s not found", target)
+}
+
+func toProtoFlowRecord(event *nftypes.Event) *proto.FlowRecord {
+	record := &proto.FlowRecord{
+		Timestamp:  timestamppb.New(event.Timestamp),
+		FlowId:     event.FlowID.String(),
+		Type:       flowTypeName(event.Type),
+		Direction:  event.Direction.String(),
+		Protocol:   event.Protocol.String(),
+		SourceIp:   event.SourceIP.String(),
+		SourcePort: uint32(event.SourcePort),
+		DestIp:     event.DestIP.String(),
+		DestPort:   uint32(event.DestPort),
+		IcmpType:   uint32(event.ICMPType),
+		IcmpCode:   uint32(event.ICMPCode),
+		RxPackets:  event.RxPackets,
+		TxPackets:  event.TxPackets,
+		RxBytes:    event.RxBytes,
+		TxBytes:    event.TxBytes,
+	}
+	if event.DNSQuery != nil {
+		record.DnsQuery = event.DNSQuery.Name
+	}
+	return record
+}
+
+func flowTypeName(t nftypes.Type) string {
+	switch t {
+	case nftypes.TypeStart:
+		return "start"
+	case nftypes.TypeEnd:
+		return "end"
+	case nftypes.TypeDrop:
+		return "drop"
+	case nftypes.TypeAudit:
+		return "audit"
+	default:
+		return "unknown"
+	}
+}
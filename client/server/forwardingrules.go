@@ -3,6 +3,8 @@ package server
 import (
 	"context"
 
+	"google.golang.org/protobuf/types/known/timestamppb"
+
 	firewall "github.com/netbirdio/netbird/client/firewall/manager"
 	"github.com/netbirdio/netbird/client/proto"
 )
@@ -11,7 +13,7 @@ func (s *Server) ForwardingRules(context.Context, *proto.EmptyRequest) (*proto.F
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	rules := s.statusRecorder.ForwardingRules()
+	rules := s.statusRecorder.ForwardingRuleStates()
 	responseRules := make([]*proto.ForwardingRule, 0, len(rules))
 	for _, rule := range rules {
 		respRule := &proto.ForwardingRule{
@@ -20,6 +22,20 @@ func (s *Server) ForwardingRules(context.Context, *proto.EmptyRequest) (*proto.F
 			TranslatedAddress:  rule.TranslatedAddress.String(),
 			TranslatedHostname: s.hostNameByTranslateAddress(rule.TranslatedAddress.String()),
 			TranslatedPort:     portToProto(rule.TranslatedPort),
+			Draining:           rule.Draining,
+		}
+		for _, addr := range rule.TranslatedAddresses {
+			respRule.TranslatedAddresses = append(respRule.TranslatedAddresses, addr.String())
+		}
+		if rule.Draining {
+			respRule.DrainDeadline = timestamppb.New(rule.DrainDeadline)
+		}
+		if rule.HealthCheck.Type != firewall.HealthCheckNone && !rule.Draining {
+			respRule.Health = rule.Health.Status.String()
+			respRule.HealthError = rule.Health.LastError
+			if !rule.Health.LastCheck.IsZero() {
+				respRule.LastHealthCheck = timestamppb.New(rule.Health.LastCheck)
+			}
 		}
 		responseRules = append(responseRules, respRule)
 
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/netbirdio/netbird/client/internal/inboundprompt"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// ListInboundPrompts returns the prompts for the first inbound connections of the peers, newest first
+func (s *Server) ListInboundPrompts(_ context.Context, _ *proto.ListInboundPromptsRequest) (*proto.ListInboundPromptsResponse, error) {
+	engine, err := s.runningEngine()
+	if err != nil {
+		return nil, err
+	}
+
+	prompts, err := engine.InboundPrompts()
+	if err != nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+
+	resp := &proto.ListInboundPromptsResponse{Prompts: make([]*proto.InboundPrompt, 0, len(prompts))}
+	for _, prompt := range prompts {
+		resp.Prompts = append(resp.Prompts, toProtoInboundPrompt(prompt))
+	}
+	return resp, nil
+}
+
+// RespondInboundPrompt allows or denies the inbound connections of a prompt for a while
+func (s *Server) RespondInboundPrompt(_ context.Context, req *proto.RespondInboundPromptRequest) (*proto.RespondInboundPromptResponse, error) {
+	if req.GetId() == "" {
+		return nil, gstatus.Errorf(codes.InvalidArgument, "prompt id is required")
+	}
+	if req.GetDuration() != nil && req.GetDuration().AsDuration() < 0 {
+		return nil, gstatus.Errorf(codes.InvalidArgument, "invalid duration %s", req.GetDuration().AsDuration())
+	}
+
+	engine, err := s.runningEngine()
+	if err != nil {
+		return nil, err
+	}
+
+	prompt, err := engine.RespondInboundPrompt(req.GetId(), req.GetAllow(), req.GetDuration().AsDuration())
+	switch {
+	case errors.Is(err, inboundprompt.ErrPromptNotFound):
+		return nil, gstatus.Errorf(codes.NotFound, "inbound prompt %s not found", req.GetId())
+	case err != nil:
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "respond to inbound prompt: %v", err)
+	}
+
+	return &proto.RespondInboundPromptResponse{Prompt: toProtoInboundPrompt(prompt)}, nil
+}
+
+func toProtoInboundPrompt(prompt inboundprompt.Prompt) *proto.InboundPrompt {
+	return &proto.InboundPrompt{
+		Id:       prompt.ID,
+		PeerIp:   prompt.PeerIP.String(),
+		PeerFqdn: prompt.PeerFQDN,
+		Protocol: strings.ToLower(prompt.Protocol.String()),
+		Port:     uint32(prompt.Port),
+		Created:  timestamppb.New(prompt.Created),
+		Decision: prompt.Decision.String(),
+		Expires:  timestamppb.New(prompt.Expires),
+	}
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// SetMaintenance enables or disables the maintenance mode and announces it to the connected peers.
+// The mode is kept for the lifetime of the daemon, so it survives reconnects of the engine.
+func (s *Server) SetMaintenance(_ context.Context, req *proto.SetMaintenanceRequest) (*proto.SetMaintenanceResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	enabled := req.GetEnabled()
+	s.statusRecorder.UpdateMaintenance(enabled)
+
+	if s.connectClient != nil {
+		if engine := s.connectClient.Engine(); engine != nil {
+			if err := engine.SetMaintenance(enabled); err != nil {
+				return nil, fmt.Errorf("announce maintenance mode: %w", err)
+			}
+		}
+	}
+
+	message := "Maintenance mode disabled"
+	if enabled {
+		message = "Maintenance mode enabled"
+	}
+	s.statusRecorder.PublishEvent(proto.SystemEvent_INFO, proto.SystemEvent_SYSTEM, message, "", nil)
+
+	return &proto.SetMaintenanceResponse{}, nil
+}
@@ -10,6 +10,8 @@ import (
 
 	"golang.org/x/exp/maps"
 
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/routemanager/vars"
 	"github.com/netbirdio/netbird/client/proto"
 	"github.com/netbirdio/netbird/route"
 	"github.com/netbirdio/netbird/shared/management/domain"
@@ -205,6 +207,108 @@ func (s *Server) DeselectNetworks(_ context.Context, req *proto.SelectNetworksRe
 	return &proto.SelectNetworksResponse{}, nil
 }
 
+// SetExitNode pins the preferred exit node peer or restores automatic exit node selection.
+func (s *Server) SetExitNode(_ context.Context, req *proto.SetExitNodeRequest) (*proto.SetExitNodeResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.connectClient == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	engine := s.connectClient.Engine()
+	if engine == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	routeManager := engine.GetRouteManager()
+	if routeManager == nil {
+		return nil, fmt.Errorf("no route manager")
+	}
+
+	var peerKey string
+	if p := req.GetPeer(); p != "" && p != "auto" {
+		state, ok := findPeer(s.statusRecorder.GetFullStatus().Peers, p)
+		if !ok {
+			return nil, fmt.Errorf("peer %s not found", p)
+		}
+		peerKey = state.PubKey
+	}
+
+	if err := routeManager.PinExitNode(peerKey); err != nil {
+		return nil, fmt.Errorf("pin exit node: %w", err)
+	}
+
+	mode := "auto"
+	if peerKey != "" {
+		mode = "pinned"
+	}
+	s.statusRecorder.PublishEvent(
+		proto.SystemEvent_INFO,
+		proto.SystemEvent_SYSTEM,
+		"Exit node selection changed",
+		"",
+		map[string]string{
+			"mode": mode,
+			"peer": peerKey,
+		},
+	)
+
+	return &proto.SetExitNodeResponse{}, nil
+}
+
+// GetExitNode returns the pinned exit node, if any, and the peer currently serving the exit node route.
+func (s *Server) GetExitNode(context.Context, *proto.GetExitNodeRequest) (*proto.GetExitNodeResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.connectClient == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	engine := s.connectClient.Engine()
+	if engine == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	routeManager := engine.GetRouteManager()
+	if routeManager == nil {
+		return nil, fmt.Errorf("no route manager")
+	}
+
+	peers := s.statusRecorder.GetFullStatus().Peers
+	resp := &proto.GetExitNodeResponse{
+		PinnedPeer: routeManager.GetRouteSelector().PinnedExitNode(),
+	}
+	if state, ok := findPeer(peers, resp.PinnedPeer); ok {
+		resp.PinnedPeerFqdn = state.FQDN
+	}
+
+	for _, state := range peers {
+		if _, ok := state.GetRoutes()[vars.ExitNodeCIDR]; ok {
+			resp.ActivePeer = state.PubKey
+			resp.ActivePeerFqdn = state.FQDN
+			break
+		}
+	}
+
+	return resp, nil
+}
+
+// findPeer looks up a peer by public key, FQDN or IP.
+func findPeer(peers []peer.State, id string) (peer.State, bool) {
+	if id == "" {
+		return peer.State{}, false
+	}
+
+	for _, state := range peers {
+		if state.PubKey == id || state.IP == id || strings.TrimSuffix(state.FQDN, ".") == strings.TrimSuffix(id, ".") {
+			return state, true
+		}
+	}
+	return peer.State{}, false
+}
+
 func toNetIDs(routes []string) []route.NetID {
 	var netIDs []route.NetID
 	for _, rt := range routes {
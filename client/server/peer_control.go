@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// ReconnectPeer tears down the connection to a peer and connects again
+func (s *Server) ReconnectPeer(_ context.Context, req *proto.ReconnectPeerRequest) (*proto.ReconnectPeerResponse, error) {
+	if req.GetPeer() == "" {
+		return nil, gstatus.Errorf(codes.InvalidArgument, "peer is required")
+	}
+
+	engine, err := s.runningEngine()
+	if err != nil {
+		return nil, err
+	}
+
+	fqdn, err := engine.ReconnectPeer(req.GetPeer())
+	if err != nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	return &proto.ReconnectPeerResponse{Fqdn: fqdn}, nil
+}
+
+// ForceRelayPeer keeps the connection to a peer on the relay, or releases it to ICE again
+func (s *Server) ForceRelayPeer(_ context.Context, req *proto.ForceRelayPeerRequest) (*proto.ForceRelayPeerResponse, error) {
+	if req.GetPeer() == "" {
+		return nil, gstatus.Errorf(codes.InvalidArgument, "peer is required")
+	}
+
+	engine, err := s.runningEngine()
+	if err != nil {
+		return nil, err
+	}
+
+	fqdn, err := engine.ForceRelayPeer(req.GetPeer(), req.GetEnabled())
+	if err != nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	return &proto.ForceRelayPeerResponse{Fqdn: fqdn}, nil
+}
+
+// GetPeerCandidates returns the selected ICE candidate pair of the connection to a peer and the previous ones
+func (s *Server) GetPeerCandidates(_ context.Context, req *proto.GetPeerCandidatesRequest) (*proto.GetPeerCandidatesResponse, error) {
+	if req.GetPeer() == "" {
+		return nil, gstatus.Errorf(codes.InvalidArgument, "peer is required")
+	}
+
+	engine, err := s.runningEngine()
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := engine.GetPeerCandidates(req.GetPeer())
+	if err != nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+
+	resp := &proto.GetPeerCandidatesResponse{
+		Fqdn:        candidates.FQDN,
+		RelayForced: candidates.RelayForced,
+	}
+	if candidates.Current != nil {
+		resp.Current = toProtoCandidatePair(*candidates.Current)
+	}
+	for _, pair := range candidates.History {
+		resp.History = append(resp.History, toProtoCandidatePair(pair))
+	}
+	return resp, nil
+}
+
+func toProtoCandidatePair(pair peer.CandidatePair) *proto.CandidatePair {
+	pbPair := &proto.CandidatePair{
+		LocalType:      pair.LocalType,
+		LocalEndpoint:  pair.LocalEndpoint,
+		RemoteType:     pair.RemoteType,
+		RemoteEndpoint: pair.RemoteEndpoint,
+		Relayed:        pair.Relayed,
+		SelectedAt:     timestamppb.New(pair.SelectedAt),
+	}
+	if !pair.ClosedAt.IsZero() {
+		pbPair.ClosedAt = timestamppb.New(pair.ClosedAt)
+	}
+	return pbPair
+}
+
+// ProbePeers sends echo probes through the tunnel to the peers and returns their round trip times and loss
+func (s *Server) ProbePeers(ctx context.Context, req *proto.ProbePeersRequest) (*proto.ProbePeersResponse, error) {
+	engine, err := s.runningEngine()
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := engine.ProbePeers(ctx, req.GetPeers(), int(req.GetCount()))
+	if err != nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+
+	resp := &proto.ProbePeersResponse{}
+	for _, result := range results {
+		resp.Peers = append(resp.Peers, &proto.PeerProbe{
+			PubKey:   result.PubKey,
+			Fqdn:     result.FQDN,
+			Ip:       result.IP,
+			Sent:     uint32(result.Sent),
+			Received: uint32(result.Received),
+			RttMin:   durationpb.New(result.RTTMin),
+			RttAvg:   durationpb.New(result.RTTAvg),
+			RttMax:   durationpb.New(result.RTTMax),
+			Error:    result.Error,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"cmp"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var connStatusOrder = map[string]int{
+	peer.StatusConnected.String():  0,
+	peer.StatusConnecting.String(): 1,
+	peer.StatusIdle.String():       2,
+}
+
+// applyPeerListOptions filters, sorts and paginates the peers. It returns the selected page and the number of peers
+// matching the filters. Without options the peers are returned unchanged.
+func applyPeerListOptions(peers []*proto.PeerState, opts *proto.PeerListOptions) ([]*proto.PeerState, int) {
+	if opts == nil {
+		return peers, len(peers)
+	}
+
+	matching := make([]*proto.PeerState, 0, len(peers))
+	for _, p := range peers {
+		if matchesPeerListOptions(p, opts) {
+			matching = append(matching, p)
+		}
+	}
+
+	slices.SortStableFunc(matching, func(a, b *proto.PeerState) int {
+		c := comparePeers(a, b, opts.GetSortBy())
+		if c == 0 {
+			// keep pages stable between requests
+			c = strings.Compare(a.GetPubKey(), b.GetPubKey())
+		}
+		if opts.GetDescending() {
+			return -c
+		}
+		return c
+	})
+
+	total := len(matching)
+
+	offset := min(int(opts.GetOffset()), total)
+	end := total
+	if limit := int(opts.GetLimit()); limit > 0 {
+		end = min(offset+limit, total)
+	}
+
+	return matching[offset:end], total
+}
+
+func matchesPeerListOptions(p *proto.PeerState, opts *proto.PeerListOptions) bool {
+	if statuses := opts.GetStatuses(); len(statuses) > 0 &&
+		!slices.ContainsFunc(statuses, func(s string) bool { return strings.EqualFold(s, p.GetConnStatus()) }) {
+		return false
+	}
+
+	if names := opts.GetNames(); len(names) > 0 &&
+		!slices.ContainsFunc(names, func(n string) bool { return strings.HasPrefix(p.GetFqdn(), strings.ToLower(n)) }) {
+		return false
+	}
+
+	if ips := opts.GetIps(); len(ips) > 0 && !slices.Contains(ips, p.GetIP()) {
+		return false
+	}
+
+	if connType := opts.GetConnectionType(); connType != "" && !strings.EqualFold(connType, peerConnectionType(p)) {
+		return false
+	}
+
+	if search := strings.ToLower(opts.GetSearch()); search != "" &&
+		!strings.Contains(strings.ToLower(p.GetFqdn()), search) &&
+		!strings.Contains(p.GetIP(), search) &&
+		!strings.Contains(strings.ToLower(p.GetPubKey()), search) {
+		return false
+	}
+
+	return true
+}
+
+func comparePeers(a, b *proto.PeerState, field proto.PeerListOptions_SortField) int {
+	switch field {
+	case proto.PeerListOptions_FQDN:
+		return strings.Compare(a.GetFqdn(), b.GetFqdn())
+	case proto.PeerListOptions_STATUS:
+		return cmp.Compare(connStatusOrder[a.GetConnStatus()], connStatusOrder[b.GetConnStatus()])
+	case proto.PeerListOptions_LATENCY:
+		return cmp.Compare(a.GetLatency().AsDuration(), b.GetLatency().AsDuration())
+	case proto.PeerListOptions_STATUS_UPDATE:
+		return a.GetConnStatusUpdate().AsTime().Compare(b.GetConnStatusUpdate().AsTime())
+	default:
+		aAddr, _ := netip.ParseAddr(a.GetIP())
+		bAddr, _ := netip.ParseAddr(b.GetIP())
+		return aAddr.Compare(bAddr)
+	}
+}
+
+// peerConnectionType returns P2P or Relayed for connected peers and "-" otherwise, matching the status output
+func peerConnectionType(p *proto.PeerState) string {
+	if p.GetConnStatus() != peer.StatusConnected.String() {
+		return "-"
+	}
+	if p.GetRelayed() {
+		return "Relayed"
+	}
+	return "P2P"
+}
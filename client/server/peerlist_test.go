@@ -0,0 +1,112 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+func testPeers() []*proto.PeerState {
+	return []*proto.PeerState{
+		{PubKey: "c", IP: "100.64.0.10", Fqdn: "charlie.netbird.cloud", ConnStatus: "Idle"},
+		{PubKey: "a", IP: "100.64.0.2", Fqdn: "alpha.netbird.cloud", ConnStatus: "Connected", Latency: durationpb.New(30 * time.Millisecond)},
+		{PubKey: "b", IP: "100.64.0.3", Fqdn: "bravo.netbird.cloud", ConnStatus: "Connected", Relayed: true, Latency: durationpb.New(10 * time.Millisecond)},
+		{PubKey: "d", IP: "100.64.0.4", Fqdn: "delta.netbird.cloud", ConnStatus: "Connecting"},
+	}
+}
+
+func pubKeys(peers []*proto.PeerState) []string {
+	keys := make([]string, 0, len(peers))
+	for _, p := range peers {
+		keys = append(keys, p.GetPubKey())
+	}
+	return keys
+}
+
+func TestApplyPeerListOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          *proto.PeerListOptions
+		expected      []string
+		expectedTotal int
+	}{
+		{
+			name:          "no options",
+			opts:          nil,
+			expected:      []string{"c", "a", "b", "d"},
+			expectedTotal: 4,
+		},
+		{
+			name:          "sorted by ip",
+			opts:          &proto.PeerListOptions{},
+			expected:      []string{"a", "b", "d", "c"},
+			expectedTotal: 4,
+		},
+		{
+			name:          "status filter",
+			opts:          &proto.PeerListOptions{Statuses: []string{"connected"}},
+			expected:      []string{"a", "b"},
+			expectedTotal: 2,
+		},
+		{
+			name:          "connection type filter",
+			opts:          &proto.PeerListOptions{ConnectionType: "relayed"},
+			expected:      []string{"b"},
+			expectedTotal: 1,
+		},
+		{
+			name:          "name prefix filter",
+			opts:          &proto.PeerListOptions{Names: []string{"Delta", "alpha"}},
+			expected:      []string{"a", "d"},
+			expectedTotal: 2,
+		},
+		{
+			name:          "ip filter",
+			opts:          &proto.PeerListOptions{Ips: []string{"100.64.0.10"}},
+			expected:      []string{"c"},
+			expectedTotal: 1,
+		},
+		{
+			name:          "search",
+			opts:          &proto.PeerListOptions{Search: "RAV"},
+			expected:      []string{"b"},
+			expectedTotal: 1,
+		},
+		{
+			name:          "sorted by latency descending",
+			opts:          &proto.PeerListOptions{Statuses: []string{"connected"}, SortBy: proto.PeerListOptions_LATENCY, Descending: true},
+			expected:      []string{"a", "b"},
+			expectedTotal: 2,
+		},
+		{
+			name:          "sorted by status",
+			opts:          &proto.PeerListOptions{SortBy: proto.PeerListOptions_STATUS},
+			expected:      []string{"a", "b", "d", "c"},
+			expectedTotal: 4,
+		},
+		{
+			name:          "page",
+			opts:          &proto.PeerListOptions{SortBy: proto.PeerListOptions_FQDN, Offset: 1, Limit: 2},
+			expected:      []string{"b", "c"},
+			expectedTotal: 4,
+		},
+		{
+			name:          "offset beyond the end",
+			opts:          &proto.PeerListOptions{Offset: 10, Limit: 2},
+			expected:      []string{},
+			expectedTotal: 4,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			peers, total := applyPeerListOptions(testPeers(), tc.opts)
+			assert.Equal(t, tc.expected, pubKeys(peers))
+			assert.Equal(t, tc.expectedTotal, total)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/internal/portforward"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// AddPortForward creates a local listener forwarding connections to a peer through the netstack.
+// The forward outlives engine restarts, connections fail while the engine is down.
+func (s *Server) AddPortForward(_ context.Context, req *proto.AddPortForwardRequest) (*proto.AddPortForwardResponse, error) {
+	if req.GetListenAddress() == "" || req.GetTargetAddress() == "" {
+		return nil, gstatus.Errorf(codes.InvalidArgument, "listen and target address are required")
+	}
+
+	if _, err := s.engineDialer(); err != nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "port forwarding requires a running client in netstack mode: %v", err)
+	}
+
+	info, err := s.portForwards.Add(req.GetListenAddress(), req.GetTargetAddress())
+	if err != nil {
+		return nil, gstatus.Errorf(codes.InvalidArgument, "add port forward: %v", err)
+	}
+
+	return &proto.AddPortForwardResponse{Forward: toProtoPortForward(info)}, nil
+}
+
+// RemovePortForward closes the listener of a port forward
+func (s *Server) RemovePortForward(_ context.Context, req *proto.RemovePortForwardRequest) (*proto.RemovePortForwardResponse, error) {
+	if err := s.portForwards.Remove(req.GetListenAddress()); err != nil {
+		if errors.Is(err, portforward.ErrNotFound) {
+			return nil, gstatus.Errorf(codes.NotFound, "no port forward listens on %s", req.GetListenAddress())
+		}
+		return nil, gstatus.Errorf(codes.Internal, "remove port forward: %v", err)
+	}
+
+	return &proto.RemovePortForwardResponse{}, nil
+}
+
+// ListPortForwards returns the active port forwards
+func (s *Server) ListPortForwards(context.Context, *proto.ListPortForwardsRequest) (*proto.ListPortForwardsResponse, error) {
+	infos := s.portForwards.List()
+
+	forwards := make([]*proto.PortForward, 0, len(infos))
+	for _, info := range infos {
+		forwards = append(forwards, toProtoPortForward(info))
+	}
+
+	return &proto.ListPortForwardsResponse{Forwards: forwards}, nil
+}
+
+// engineDialer returns the netstack of the running engine
+func (s *Server) engineDialer() (portforward.Dialer, error) {
+	s.mutex.Lock()
+	connectClient := s.connectClient
+	s.mutex.Unlock()
+
+	if connectClient == nil {
+		return nil, errors.New("client is not running")
+	}
+
+	engine := connectClient.Engine()
+	if engine == nil {
+		return nil, errors.New("engine is not running")
+	}
+
+	nsNet, err := engine.GetNet()
+	if err != nil {
+		return nil, err
+	}
+	return nsNet, nil
+}
+
+func toProtoPortForward(info portforward.Info) *proto.PortForward {
+	return &proto.PortForward{
+		ListenAddress:     info.ListenAddress,
+		TargetAddress:     info.TargetAddress,
+		ActiveConnections: info.ActiveConnections,
+		TotalConnections:  info.TotalConnections,
+	}
+}
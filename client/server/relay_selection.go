@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/netbirdio/netbird/client/internal"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// GetRelayCandidates returns the relay servers with their measured round trip time
+func (s *Server) GetRelayCandidates(_ context.Context, req *proto.GetRelayCandidatesRequest) (*proto.GetRelayCandidatesResponse, error) {
+	engine, err := s.runningEngine()
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, pinned, err := engine.GetRelayCandidates(req.GetRefresh())
+	if err != nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+
+	resp := &proto.GetRelayCandidatesResponse{Pinned: pinned}
+	for _, candidate := range candidates {
+		resp.Candidates = append(resp.Candidates, toProtoRelayCandidate(candidate))
+	}
+	return resp, nil
+}
+
+// SetPreferredRelay pins the home relay server, an empty url selects the one with the lowest latency again
+func (s *Server) SetPreferredRelay(_ context.Context, req *proto.SetPreferredRelayRequest) (*proto.SetPreferredRelayResponse, error) {
+	engine, err := s.runningEngine()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := engine.SetPreferredRelay(req.GetUrl()); err != nil {
+		if errors.Is(err, internal.ErrUnknownRelay) {
+			return nil, gstatus.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	return &proto.SetPreferredRelayResponse{}, nil
+}
+
+func toProtoRelayCandidate(candidate internal.RelayCandidate) *proto.RelayCandidate {
+	pbCandidate := &proto.RelayCandidate{
+		Url:       candidate.URL,
+		Home:      candidate.Home,
+		Preferred: candidate.Preferred,
+	}
+	if candidate.RTT > 0 {
+		pbCandidate.Rtt = durationpb.New(candidate.RTT)
+	}
+	if candidate.Err != nil {
+		pbCandidate.Error = candidate.Err.Error()
+	}
+	if !candidate.MeasuredAt.IsZero() {
+		pbCandidate.MeasuredAt = timestamppb.New(candidate.MeasuredAt)
+	}
+	return pbCandidate
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/netbirdio/netbird/client/internal/routemanager/metrics"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// GetRouteMetrics returns how long programming the routes and route firewall rules takes
+func (s *Server) GetRouteMetrics(_ context.Context, _ *proto.GetRouteMetricsRequest) (*proto.GetRouteMetricsResponse, error) {
+	engine, err := s.runningEngine()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := engine.RouteMetrics()
+	if err != nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "get route metrics: %v", err)
+	}
+
+	resp := &proto.GetRouteMetricsResponse{
+		Os:          snapshot.OS,
+		RouteAdd:    toProtoHistogram(snapshot.RouteAdd),
+		RouteRemove: toProtoHistogram(snapshot.RouteRemove),
+		Routes:      toProtoHistogram(snapshot.Routes),
+		Firewall:    toProtoHistogram(snapshot.Firewall),
+	}
+	if last := snapshot.LastUpdate; last != nil {
+		resp.LastUpdate = &proto.NetworkMapRouteUpdate{
+			Serial:           last.Serial,
+			Time:             timestamppb.New(last.Time),
+			ClientRoutes:     int32(last.ClientRoutes),
+			ServerRoutes:     int32(last.ServerRoutes),
+			RoutesDuration:   durationpb.New(last.RoutesDuration),
+			FirewallRules:    int32(last.FirewallRules),
+			FirewallDuration: durationpb.New(last.FirewallDuration),
+		}
+	}
+	return resp, nil
+}
+
+func toProtoHistogram(h metrics.HistogramSnapshot) *proto.LatencyHistogram {
+	ph := &proto.LatencyHistogram{
+		Count: h.Count,
+		Sum:   durationpb.New(h.Sum),
+		Max:   durationpb.New(h.Max),
+	}
+	for _, bucket := range h.Buckets {
+		pb := &proto.LatencyBucket{Count: bucket.Count}
+		if bucket.UpperBound > 0 {
+			pb.UpperBound = durationpb.New(bucket.UpperBound)
+		}
+		ph.Buckets = append(ph.Buckets, pb)
+	}
+	return ph
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// GetRouteRuleStats returns the counters of the route firewall rules
+func (s *Server) GetRouteRuleStats(_ context.Context, _ *proto.GetRouteRuleStatsRequest) (*proto.GetRouteRuleStatsResponse, error) {
+	engine, err := s.runningEngine()
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := engine.RouteRuleStats()
+	if err != nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "get route rule stats: %v", err)
+	}
+
+	resp := &proto.GetRouteRuleStatsResponse{}
+	for _, rule := range stats {
+		resp.Rules = append(resp.Rules, &proto.RouteRuleStats{
+			RuleID:   rule.RuleID,
+			PolicyID: rule.PolicyID,
+			Action:   rule.Action.String(),
+			Packets:  rule.Packets,
+			Bytes:    rule.Bytes,
+		})
+	}
+	return resp, nil
+}
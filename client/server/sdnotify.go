@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal"
+	"github.com/netbirdio/netbird/client/internal/sdnotify"
+)
+
+// serviceReadyPollInterval is how often the readiness of the daemon is checked until it is reported
+const serviceReadyPollInterval = time.Second
+
+// NotifyServiceManager reports to systemd once the daemon is ready and feeds the watchdog of the service while the
+// engine health probes complete. It is a no-op if the daemon isn't run by systemd with Type=notify.
+func (s *Server) NotifyServiceManager(ctx context.Context) {
+	go s.notifyServiceReady(ctx)
+
+	if timeout := sdnotify.Watchdog(); timeout > 0 {
+		log.Infof("systemd watchdog enabled with a timeout of %s", timeout)
+		go s.feedServiceWatchdog(ctx, timeout)
+	}
+}
+
+func (s *Server) notifyServiceReady(ctx context.Context) {
+	ticker := time.NewTicker(serviceReadyPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if status, ready := s.serviceReadiness(); ready {
+			if _, err := sdnotify.Notify(sdnotify.StateReady, sdnotify.Status(status)); err != nil {
+				log.Warnf("failed to notify systemd about the readiness: %v", err)
+			}
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// serviceReadiness reports whether the daemon finished starting up. It is ready once the engine applied the first
+// network map, with the interface and DNS set up, or if it doesn't connect until a login or an up command.
+func (s *Server) serviceReadiness() (string, bool) {
+	status, state := s.serviceStatus()
+	switch state {
+	case internal.StatusNeedsLogin, internal.StatusLoginFailed, internal.StatusSessionExpired:
+		return status, true
+	case internal.StatusIdle:
+		s.mutex.Lock()
+		running := s.clientRunning
+		s.mutex.Unlock()
+		if !running {
+			return status, true
+		}
+	}
+
+	engine, err := s.runningEngine()
+	if err != nil || !engine.NetworkMapApplied() {
+		return "", false
+	}
+	return "Connected", true
+}
+
+// serviceStatus returns the status of the daemon shown by systemctl status
+func (s *Server) serviceStatus() (string, internal.StatusType) {
+	state, _ := internal.CtxGetState(s.rootCtx).Status()
+	switch state {
+	case internal.StatusNeedsLogin, internal.StatusLoginFailed, internal.StatusSessionExpired:
+		return "Waiting for login", state
+	case internal.StatusIdle:
+		return "Disconnected", state
+	default:
+		return string(state), state
+	}
+}
+
+// feedServiceWatchdog pings the watchdog whenever the engine health probes complete, a wedged engine stops the pings
+// and systemd restarts the daemon. Unreachable NetBird services only change the status, a restart doesn't fix them.
+func (s *Server) feedServiceWatchdog(ctx context.Context, timeout time.Duration) {
+	interval := timeout / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// pending is the result of a probe that didn't complete in time, no other probe is started until it does
+	var pending chan bool
+	lastStatus := ""
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		engine, err := s.runningEngine()
+		if err != nil {
+			pending = nil
+			status, _ := s.serviceStatus()
+			lastStatus = pingServiceWatchdog(status, lastStatus)
+			continue
+		}
+
+		if pending == nil {
+			pending = make(chan bool, 1)
+			go func(result chan<- bool) {
+				result <- engine.RunHealthProbes(false)
+			}(pending)
+		}
+
+		var healthy bool
+		select {
+		case healthy = <-pending:
+			pending = nil
+		case <-time.After(interval):
+			log.Warnf("engine health probes didn't complete within %s, not pinging the systemd watchdog", interval)
+			continue
+		}
+
+		status, _ := s.serviceStatus()
+		if !healthy {
+			status += ", NetBird services are unreachable"
+		}
+		lastStatus = pingServiceWatchdog(status, lastStatus)
+	}
+}
+
+// pingServiceWatchdog pings the watchdog, with the status if it changed, and returns the status
+func pingServiceWatchdog(status, lastStatus string) string {
+	states := []string{sdnotify.StateWatchdog}
+	if status != lastStatus {
+		states = append(states, sdnotify.Status(status))
+	}
+	if _, err := sdnotify.Notify(states...); err != nil {
+		log.Debugf("failed to ping the systemd watchdog: %v", err)
+	}
+	return status
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/netip"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -23,8 +27,13 @@ import (
 	gstatus "google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/netbirdio/netbird/client/internal/apitoken"
 	"github.com/netbirdio/netbird/client/internal/auth"
+	"github.com/netbirdio/netbird/client/internal/daemonerr"
+	"github.com/netbirdio/netbird/client/internal/eventbus"
+	"github.com/netbirdio/netbird/client/internal/portforward"
 	"github.com/netbirdio/netbird/client/internal/profilemanager"
+	"github.com/netbirdio/netbird/client/internal/secrets"
 	"github.com/netbirdio/netbird/client/system"
 	mgm "github.com/netbirdio/netbird/shared/management/client"
 	"github.com/netbirdio/netbird/shared/management/domain"
@@ -49,6 +58,9 @@ const (
 	// JWT token cache TTL for the client daemon (disabled by default)
 	defaultJWTCacheTTL = 0
 
+	// eventHistorySize is the number of engine events the debug bundle includes
+	eventHistorySize = 200
+
 	errRestoreResidualState   = "failed to restore residual state: %v"
 	errProfilesDisabled       = "profiles are disabled, you cannot use this feature without profiles enabled"
 	errUpdateSettingsDisabled = "update settings are disabled, you cannot use this feature without update settings enabled"
@@ -75,6 +87,8 @@ type Server struct {
 	connectClient *internal.ConnectClient
 
 	statusRecorder *peer.Status
+	// eventHistory keeps the latest engine events of all connections for the debug bundle
+	eventHistory   *eventbus.History
 	sessionWatcher *internal.SessionWatcher
 
 	lastProbe           time.Time
@@ -89,6 +103,11 @@ type Server struct {
 	sleepTriggeredDown atomic.Bool
 
 	jwtCache *jwtCache
+
+	portForwards *portforward.Manager
+
+	// apiTokens are the tokens of the monitoring API
+	apiTokens *apitoken.Store
 }
 
 type oauthAuthFlow struct {
@@ -100,16 +119,20 @@ type oauthAuthFlow struct {
 
 // New server instance constructor.
 func New(ctx context.Context, logFile string, configFile string, profilesDisabled bool, updateSettingsDisabled bool) *Server {
-	return &Server{
+	s := &Server{
 		rootCtx:                ctx,
 		logFile:                logFile,
 		persistSyncResponse:    true,
 		statusRecorder:         peer.NewRecorder(""),
+		eventHistory:           eventbus.NewHistory(eventHistorySize),
 		profileManager:         profilemanager.NewServiceManager(configFile),
 		profilesDisabled:       profilesDisabled,
 		updateSettingsDisabled: updateSettingsDisabled,
 		jwtCache:               newJWTCache(),
+		apiTokens:              apitoken.NewStore(filepath.Join(profilemanager.DefaultConfigPathDir, apiTokensFile)),
 	}
+	s.portForwards = portforward.NewManager(ctx, s.engineDialer)
+	return s
 }
 
 func (s *Server) Start() error {
@@ -263,7 +286,7 @@ func (s *Server) loginAttempt(ctx context.Context, setupKey, jwtToken string) (i
 			log.Errorf("failed login: %v", err)
 			status = internal.StatusLoginFailed
 		}
-		return status, err
+		return status, loginError(err)
 	}
 	return "", nil
 }
@@ -310,6 +333,10 @@ func (s *Server) SetConfig(callerCtx context.Context, msg *proto.SetConfigReques
 	}
 
 	if msg.OptionalPreSharedKey != nil {
+		// the daemon runs privileged, commands must only be configured in the config file
+		if secrets.IsExecReference(*msg.OptionalPreSharedKey) {
+			return nil, gstatus.Errorf(codes.InvalidArgument, "exec secret references are not accepted by the daemon, set them in the config file")
+		}
 		if *msg.OptionalPreSharedKey != "" {
 			config.PreSharedKey = msg.OptionalPreSharedKey
 		}
@@ -353,7 +380,13 @@ func (s *Server) SetConfig(callerCtx context.Context, msg *proto.SetConfigReques
 	config.BlockLANAccess = msg.BlockLanAccess
 	config.DisableNotifications = msg.DisableNotifications
 	config.LazyConnectionEnabled = msg.LazyConnectionEnabled
+	config.ICEMulticastDNS = msg.IceMulticastDNS
+	config.ICEPolicy = msg.IcePolicy
 	config.BlockInbound = msg.BlockInbound
+	config.KillSwitch = msg.KillSwitch
+	config.FirewallBackend = msg.FirewallBackend
+	config.SOCKS5ProxyAddress = msg.Socks5ProxyAddress
+	config.HTTPProxyAddress = msg.HttpProxyAddress
 	config.EnableSSHRoot = msg.EnableSSHRoot
 	config.EnableSSHSFTP = msg.EnableSSHSFTP
 	config.EnableSSHLocalPortForwarding = msg.EnableSSHLocalPortForwarding
@@ -621,7 +654,7 @@ func (s *Server) Up(callerCtx context.Context, msg *proto.UpRequest) (*proto.UpR
 		status, err := state.Status()
 		if err != nil {
 			s.mutex.Unlock()
-			return nil, err
+			return nil, loginError(err)
 		}
 		if status == internal.StatusNeedsLogin {
 			s.actCancel()
@@ -643,11 +676,11 @@ func (s *Server) Up(callerCtx context.Context, msg *proto.UpRequest) (*proto.UpR
 	// not in the progress or already successfully established connection.
 	status, err := state.Status()
 	if err != nil {
-		return nil, err
+		return nil, loginError(err)
 	}
 
 	if status != internal.StatusIdle {
-		return nil, fmt.Errorf("up already in progress: current status %s", status)
+		return nil, daemonerr.Status(codes.FailedPrecondition, daemonerr.ReasonEngineStarting, fmt.Sprintf("up already in progress: current status %s", status))
 	}
 
 	// it should be nil here, but in case it isn't we cancel it.
@@ -663,7 +696,7 @@ func (s *Server) Up(callerCtx context.Context, msg *proto.UpRequest) (*proto.UpR
 	s.actCancel = cancel
 
 	if s.config == nil {
-		return nil, fmt.Errorf("config is not defined, please call login command first")
+		return nil, daemonerr.Status(codes.FailedPrecondition, daemonerr.ReasonAuthRequired, "config is not defined, please call login command first")
 	}
 
 	activeProf, err := s.profileManager.GetActiveProfileState()
@@ -726,7 +759,7 @@ func (s *Server) waitForUp(callerCtx context.Context) (*proto.UpResponse, error)
 		return nil, callerCtx.Err()
 	case <-timeoutCtx.Done():
 		log.Debug("up is timed out, stopping the wait for engine to become ready")
-		return nil, timeoutCtx.Err()
+		return nil, daemonerr.Status(codes.DeadlineExceeded, daemonerr.ReasonEngineStarting, "engine didn't become ready in time")
 	}
 }
 
@@ -881,7 +914,7 @@ func (s *Server) handleActiveProfileLogout(ctx context.Context) (*proto.LogoutRe
 
 		config, _, err := s.getConfig(activeProf)
 		if err != nil {
-			return nil, gstatus.Errorf(codes.FailedPrecondition, "not logged in")
+			return nil, daemonerr.Status(codes.FailedPrecondition, daemonerr.ReasonAuthRequired, "not logged in")
 		}
 		s.config = config
 	}
@@ -1070,6 +1103,10 @@ func (s *Server) Status(
 		pbFullStatus := toProtoFullStatus(fullStatus)
 		pbFullStatus.Events = s.statusRecorder.GetEventHistory()
 
+		peers, total := applyPeerListOptions(pbFullStatus.GetPeers(), msg.GetPeerListOptions())
+		pbFullStatus.Peers = peers
+		pbFullStatus.TotalPeers = int32(total)
+
 		pbFullStatus.SshServerState = s.getSSHServerState()
 
 		statusResponse.FullStatus = pbFullStatus
@@ -1406,7 +1443,13 @@ func (s *Server) GetConfig(ctx context.Context, req *proto.GetConfigRequest) (*p
 		RosenpassEnabled:              cfg.RosenpassEnabled,
 		RosenpassPermissive:           cfg.RosenpassPermissive,
 		LazyConnectionEnabled:         cfg.LazyConnectionEnabled,
+		IceMulticastDNS:               cfg.ICEMulticastDNS,
+		IcePolicy:                     cfg.ICEPolicy,
 		BlockInbound:                  cfg.BlockInbound,
+		KillSwitch:                    cfg.KillSwitch,
+		FirewallBackend:               cfg.FirewallBackend,
+		Socks5ProxyAddress:            cfg.SOCKS5ProxyAddress,
+		HttpProxyAddress:              cfg.HTTPProxyAddress,
 		DisableNotifications:          disableNotifications,
 		NetworkMonitor:                networkMonitor,
 		DisableDns:                    disableDNS,
@@ -1526,6 +1569,7 @@ func (s *Server) connect(ctx context.Context, config *profilemanager.Config, sta
 	log.Tracef("running client connection")
 	s.connectClient = internal.NewConnectClient(ctx, config, statusRecorder, doInitialAutoUpdate)
 	s.connectClient.SetSyncResponsePersistence(s.persistSyncResponse)
+	s.connectClient.EventBus().Handle(s.eventHistory.Record)
 	if err := s.connectClient.Run(runningChan); err != nil {
 		return err
 	}
@@ -1619,6 +1663,22 @@ func toProtoFullStatus(fullStatus peer.FullStatus) *proto.FullStatus {
 	if err := fullStatus.SignalState.Error; err != nil {
 		pbFullStatus.SignalState.Error = err.Error()
 	}
+	pbFullStatus.SignalState.Reconnects = int32(fullStatus.SignalState.Reconnects)
+	if !fullStatus.SignalState.LastDisconnect.IsZero() {
+		pbFullStatus.SignalState.LastDisconnect = timestamppb.New(fullStatus.SignalState.LastDisconnect)
+	}
+	pbFullStatus.SignalState.LastDisconnectReason = fullStatus.SignalState.LastDisconnectReason
+	if fullStatus.SignalState.Latency > 0 {
+		pbFullStatus.SignalState.Latency = durationpb.New(fullStatus.SignalState.Latency)
+	}
+
+	pbFullStatus.FlowState = &proto.FlowState{
+		URL:       fullStatus.FlowState.URL,
+		Connected: fullStatus.FlowState.Connected,
+	}
+	if err := fullStatus.FlowState.Error; err != nil {
+		pbFullStatus.FlowState.Error = err.Error()
+	}
 
 	pbFullStatus.LocalPeerState.IP = fullStatus.LocalPeerState.IP
 	pbFullStatus.LocalPeerState.PubKey = fullStatus.LocalPeerState.PubKey
@@ -1627,8 +1687,44 @@ func toProtoFullStatus(fullStatus peer.FullStatus) *proto.FullStatus {
 	pbFullStatus.LocalPeerState.RosenpassPermissive = fullStatus.RosenpassState.Permissive
 	pbFullStatus.LocalPeerState.RosenpassEnabled = fullStatus.RosenpassState.Enabled
 	pbFullStatus.LocalPeerState.Networks = maps.Keys(fullStatus.LocalPeerState.Routes)
+	pbFullStatus.LocalPeerState.Roles = fullStatus.LocalPeerState.Roles
+	pbFullStatus.LocalPeerState.NetworkTransfers = toNetworkTransfers(fullStatus.LocalPeerState.Routes, fullStatus.RouteTransfers)
 	pbFullStatus.NumberOfForwardingRules = int32(fullStatus.NumOfForwardingRules)
 	pbFullStatus.LazyConnectionEnabled = fullStatus.LazyConnectionEnabled
+	pbFullStatus.MaintenanceEnabled = fullStatus.MaintenanceEnabled
+	pbFullStatus.NetstackFallback = fullStatus.NetstackFallback
+	if listener := fullStatus.DNSListener; len(listener.Addresses) > 0 || len(listener.Fallbacks) > 0 {
+		pbFullStatus.DnsListener = &proto.DNSListenerState{Fallbacks: listener.Fallbacks}
+		for _, addr := range listener.Addresses {
+			pbFullStatus.DnsListener.Addresses = append(pbFullStatus.DnsListener.Addresses, addr.String())
+		}
+	}
+	if res := fullStatus.Resources; res != nil {
+		pbFullStatus.PeerResources = &proto.PeerResourceState{
+			Goroutines:        int64(res.Goroutines),
+			IceAgents:         int64(res.ICEAgents),
+			BufferBytes:       res.BufferBytes,
+			MaxGoroutines:     int64(res.Limits.MaxGoroutines),
+			MaxIceAgents:      int64(res.Limits.MaxICEAgents),
+			MaxBufferBytes:    res.Limits.MaxBufferBytes,
+			Peers:             int32(res.Peers),
+			DeniedActivations: res.DeniedActivations,
+			DeniedIceAgents:   res.DeniedICEAgents,
+		}
+	}
+
+	pbFullStatus.FirewallBackend = &proto.FirewallBackendState{
+		Backend: fullStatus.FirewallBackend.Backend,
+		Native:  fullStatus.FirewallBackend.Native,
+		Reason:  fullStatus.FirewallBackend.Reason,
+	}
+
+	for _, set := range fullStatus.FirewallSets {
+		pbFullStatus.FirewallSets = append(pbFullStatus.FirewallSets, &proto.FirewallSetState{
+			Name:     set.Name,
+			Elements: int32(set.Elements),
+		})
+	}
 
 	for _, peerState := range fullStatus.Peers {
 		pbPeerState := &proto.PeerState{
@@ -1650,6 +1746,16 @@ func toProtoFullStatus(fullStatus peer.FullStatus) *proto.FullStatus {
 			Networks:                   maps.Keys(peerState.GetRoutes()),
 			Latency:                    durationpb.New(peerState.Latency),
 			SshHostKey:                 peerState.SSHHostKey,
+			Maintenance:                peerState.Maintenance,
+			AllowedIps:                 prefixesToStrings(peerState.AllowedIPs),
+			Offline:                    peerState.Offline,
+			OfflineReason:              string(peerState.OfflineReason),
+			TotalBytesRx:               peerState.Lifetime.BytesRx,
+			TotalBytesTx:               peerState.Lifetime.BytesTx,
+			NetworkTransfers:           toNetworkTransfers(peerState.GetRoutes(), fullStatus.RouteTransfers),
+		}
+		if !peerState.Lifetime.LastConnected.IsZero() {
+			pbPeerState.LastConnected = timestamppb.New(peerState.Lifetime.LastConnected)
 		}
 		pbFullStatus.Peers = append(pbFullStatus.Peers, pbPeerState)
 	}
@@ -1688,6 +1794,37 @@ func toProtoFullStatus(fullStatus peer.FullStatus) *proto.FullStatus {
 	return &pbFullStatus
 }
 
+// toNetworkTransfers returns the transfer counters of the given routed networks, sorted by network
+func toNetworkTransfers(networks map[string]struct{}, transfers map[string]peer.RouteTransfer) []*proto.NetworkTransfer {
+	var result []*proto.NetworkTransfer
+	for network := range networks {
+		transfer, ok := transfers[network]
+		if !ok {
+			continue
+		}
+		result = append(result, &proto.NetworkTransfer{
+			Network: network,
+			BytesRx: transfer.BytesRx,
+			BytesTx: transfer.BytesTx,
+		})
+	}
+	slices.SortFunc(result, func(a, b *proto.NetworkTransfer) int {
+		return strings.Compare(a.Network, b.Network)
+	})
+	return result
+}
+
+func prefixesToStrings(prefixes []netip.Prefix) []string {
+	if len(prefixes) == 0 {
+		return nil
+	}
+	result := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		result = append(result, prefix.String())
+	}
+	return result
+}
+
 // sendTerminalNotification sends a terminal notification message
 // to inform the user that the NetBird connection session has expired.
 func sendTerminalNotification() error {
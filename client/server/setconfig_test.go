@@ -70,7 +70,13 @@ func TestSetConfig_AllFieldsSaved(t *testing.T) {
 	blockLANAccess := true
 	disableNotifications := true
 	lazyConnectionEnabled := true
+	iceMulticastDNS := true
+	icePolicy := "relay-only"
+	firewallBackend := "nftables"
 	blockInbound := true
+	killSwitch := true
+	socks5ProxyAddress := "127.0.0.1:1080"
+	httpProxyAddress := "127.0.0.1:3128"
 	mtu := int64(1280)
 	sshJWTCacheTTL := int32(300)
 
@@ -94,7 +100,13 @@ func TestSetConfig_AllFieldsSaved(t *testing.T) {
 		BlockLanAccess:        &blockLANAccess,
 		DisableNotifications:  &disableNotifications,
 		LazyConnectionEnabled: &lazyConnectionEnabled,
+		IceMulticastDNS:       &iceMulticastDNS,
+		IcePolicy:             &icePolicy,
+		FirewallBackend:       &firewallBackend,
 		BlockInbound:          &blockInbound,
+		KillSwitch:            &killSwitch,
+		Socks5ProxyAddress:    &socks5ProxyAddress,
+		HttpProxyAddress:      &httpProxyAddress,
 		NatExternalIPs:        []string{"1.2.3.4", "5.6.7.8"},
 		CleanNATExternalIPs:   false,
 		CustomDNSAddress:      []byte("1.1.1.1:53"),
@@ -139,7 +151,13 @@ func TestSetConfig_AllFieldsSaved(t *testing.T) {
 	require.NotNil(t, cfg.DisableNotifications)
 	require.Equal(t, disableNotifications, *cfg.DisableNotifications)
 	require.Equal(t, lazyConnectionEnabled, cfg.LazyConnectionEnabled)
+	require.Equal(t, iceMulticastDNS, cfg.ICEMulticastDNS)
+	require.Equal(t, icePolicy, cfg.ICEPolicy)
+	require.Equal(t, firewallBackend, cfg.FirewallBackend)
 	require.Equal(t, blockInbound, cfg.BlockInbound)
+	require.Equal(t, killSwitch, cfg.KillSwitch)
+	require.Equal(t, socks5ProxyAddress, cfg.SOCKS5ProxyAddress)
+	require.Equal(t, httpProxyAddress, cfg.HTTPProxyAddress)
 	require.Equal(t, []string{"1.2.3.4", "5.6.7.8"}, cfg.NATExternalIPs)
 	require.Equal(t, "1.1.1.1:53", cfg.CustomDNSAddress)
 	// IFaceBlackList contains defaults + extras
@@ -188,7 +206,13 @@ func verifyAllFieldsCovered(t *testing.T, req *proto.SetConfigRequest) {
 		"BlockLanAccess":                true,
 		"DisableNotifications":          true,
 		"LazyConnectionEnabled":         true,
+		"IceMulticastDNS":               true,
+		"IcePolicy":                     true,
+		"FirewallBackend":               true,
 		"BlockInbound":                  true,
+		"KillSwitch":                    true,
+		"Socks5ProxyAddress":            true,
+		"HttpProxyAddress":              true,
 		"NatExternalIPs":                true,
 		"CustomDNSAddress":              true,
 		"ExtraIFaceBlacklist":           true,
@@ -247,7 +271,13 @@ func TestCLIFlags_MappedToSetConfig(t *testing.T) {
 		"disable-firewall":                  "DisableFirewall",
 		"block-lan-access":                  "BlockLanAccess",
 		"block-inbound":                     "BlockInbound",
+		"kill-switch":                       "KillSwitch",
+		"socks5-proxy-address":              "Socks5ProxyAddress",
+		"http-proxy-address":                "HttpProxyAddress",
 		"enable-lazy-connection":            "LazyConnectionEnabled",
+		"enable-ice-mdns":                   "IceMulticastDNS",
+		"ice-policy":                        "IcePolicy",
+		"firewall-backend":                  "FirewallBackend",
 		"external-ip-map":                   "NatExternalIPs",
 		"dns-resolver-address":              "CustomDNSAddress",
 		"extra-iface-blacklist":             "ExtraIFaceBlacklist",
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// Wake asks a routing peer on the site of the target to send a Wake-on-LAN magic packet
+func (s *Server) Wake(_ context.Context, req *proto.WakeRequest) (*proto.WakeResponse, error) {
+	if req.GetTarget() == "" {
+		return nil, gstatus.Errorf(codes.InvalidArgument, "target is required")
+	}
+
+	s.mutex.Lock()
+	connectClient := s.connectClient
+	s.mutex.Unlock()
+
+	if connectClient == nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "client is not running")
+	}
+
+	engine := connectClient.Engine()
+	if engine == nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "engine is not running")
+	}
+
+	result, err := engine.Wake(req.GetTarget(), req.GetMacAddress(), req.GetVia())
+	if err != nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "wake %s: %v", req.GetTarget(), err)
+	}
+
+	return &proto.WakeResponse{
+		Target:     result.Target,
+		Relay:      result.Relay,
+		MacAddress: result.MAC.String(),
+	}, nil
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"errors"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/netbirdio/netbird/client/internal"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// SubscribeWGStats streams the WireGuard transfer of the peers since the previous update and their handshakes
+func (s *Server) SubscribeWGStats(req *proto.SubscribeWGStatsRequest, stream proto.DaemonService_SubscribeWGStatsServer) error {
+	engine, err := s.runningEngine()
+	if err != nil {
+		return err
+	}
+
+	log.Debug("client subscribed to wireguard stats")
+	defer log.Debug("client unsubscribed from wireguard stats")
+
+	err = engine.StreamWGStats(stream.Context(), req.GetInterval().AsDuration(), func(update internal.WGStatsUpdate) error {
+		return stream.Send(toProtoWGStatsUpdate(update))
+	})
+	if errors.Is(err, internal.ErrEngineStopped) {
+		return gstatus.Errorf(codes.Unavailable, "engine stopped")
+	}
+	return err
+}
+
+func toProtoWGStatsUpdate(update internal.WGStatsUpdate) *proto.WGStatsUpdate {
+	peers := make([]*proto.WGPeerStats, 0, len(update.Peers))
+	for _, p := range update.Peers {
+		peerStats := &proto.WGPeerStats{
+			PubKey:    p.PubKey,
+			RxBytes:   p.RxBytes,
+			TxBytes:   p.TxBytes,
+			Handshake: p.Handshake,
+		}
+		if !p.LastHandshake.IsZero() {
+			peerStats.LastHandshake = timestamppb.New(p.LastHandshake)
+		}
+		peers = append(peers, peerStats)
+	}
+
+	return &proto.WGStatsUpdate{
+		Time:    timestamppb.New(update.Time),
+		Elapsed: durationpb.New(update.Elapsed),
+		Peers:   peers,
+	}
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/anmitsu/go-shlex"
+	"github.com/gliderlabs/ssh"
+)
+
+// shellControlChars may chain further commands to an allowed command, arguments containing them never match a
+// wildcard entry of the command allowlist
+const shellControlChars = ";&|`$<>()\n\r\\"
+
+var ErrUserNotAllowed = errors.New("user is not allowed by the SSH policy")
+
+// UserNotAllowedError represents an error when the SSH policy doesn't allow logins as a user
+type UserNotAllowedError struct {
+	Username string
+}
+
+func (e *UserNotAllowedError) Error() string {
+	return fmt.Sprintf("login as %s is not allowed by the SSH policy", e.Username)
+}
+
+func (e *UserNotAllowedError) Is(target error) bool {
+	return target == ErrUserNotAllowed
+}
+
+// Policy restricts what clients may do on the SSH server, on top of the authentication.
+// The zero value allows everything and records nothing.
+type Policy struct {
+	// AllowedUsers are the local users clients may log in as. Empty allows all users.
+	AllowedUsers []string
+	// AllowedCommands are the command lines clients may execute. An entry ending with " *" allows the command with
+	// any arguments that don't contain shell control characters. Interactive shells are denied if set.
+	// Empty allows all commands and interactive shells.
+	AllowedCommands []string
+	// AllowedPeers are the NetBird peer networks allowed to connect. Empty allows all peers.
+	AllowedPeers []netip.Prefix
+	// RecordSessions records the shell and command sessions, see RecordingConfig
+	RecordSessions bool
+	// PeerRules map the peers to the local users they may log in as, the first rule matching a peer applies.
+	// Peers no rule matches are rejected. Empty applies the settings above to all peers.
+	PeerRules []PeerRule
+}
+
+// PeerRule grants the peers of networks, e.g. the NetBird IPs of a peer group, access as local users. The rule
+// narrows the policy, the users and commands must be allowed by both.
+type PeerRule struct {
+	// Peers are the NetBird IPs or networks of the peers the rule applies to
+	Peers []netip.Prefix
+	// Users are the local users the peers may log in as. Empty allows the users of the policy.
+	Users []string
+	// ForceCommand runs instead of the command or the shell the peers request, like ForceCommand of OpenSSH.
+	// The requested command is passed in SSH_ORIGINAL_COMMAND. SFTP is denied if set.
+	ForceCommand string
+	// DenyShell denies interactive shells, commands stay allowed
+	DenyShell bool
+}
+
+// matches reports whether the rule applies to the peer
+func (r PeerRule) matches(addr netip.Addr) bool {
+	return slices.ContainsFunc(r.Peers, func(prefix netip.Prefix) bool {
+		return prefix.Contains(addr)
+	})
+}
+
+// UpdatePolicy replaces the SSH policy. Active sessions keep running, new connections and sessions use the new
+// policy. A nil policy allows everything.
+func (s *Server) UpdatePolicy(policy *Policy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if policy == nil {
+		policy = &Policy{}
+	}
+	s.policy = *policy
+}
+
+func (s *Server) getPolicy() Policy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.policy
+}
+
+// userAllowed reports whether the policy allows logins as the user
+func (p Policy) userAllowed(username string) bool {
+	return len(p.AllowedUsers) == 0 || containsUser(p.AllowedUsers, username)
+}
+
+// peerUserAllowed reports whether the policy allows the peer to log in as the user
+func (p Policy) peerUserAllowed(addr netip.Addr, username string) bool {
+	if !p.userAllowed(username) {
+		return false
+	}
+	rule, ok := p.peerRule(addr)
+	if !ok {
+		return false
+	}
+	return len(rule.Users) == 0 || containsUser(rule.Users, username)
+}
+
+// peerRule returns the rule applying to the peer. It reports false if no rule matches while rules are set.
+func (p Policy) peerRule(addr netip.Addr) (PeerRule, bool) {
+	if len(p.PeerRules) == 0 {
+		return PeerRule{}, true
+	}
+	for _, rule := range p.PeerRules {
+		if rule.matches(addr) {
+			return rule, true
+		}
+	}
+	return PeerRule{}, false
+}
+
+// containsUser reports whether the users contain the username, case-insensitive on Windows
+func containsUser(users []string, username string) bool {
+	if isPlatformUnix() {
+		return slices.Contains(users, username)
+	}
+	return slices.ContainsFunc(users, func(user string) bool {
+		return strings.EqualFold(user, username)
+	})
+}
+
+// peerAllowed reports whether the policy allows connections from the peer
+func (p Policy) peerAllowed(addr netip.Addr) bool {
+	if _, ok := p.peerRule(addr); !ok {
+		return false
+	}
+	if len(p.AllowedPeers) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(p.AllowedPeers, func(prefix netip.Prefix) bool {
+		return prefix.Contains(addr)
+	})
+}
+
+// commandAllowed reports whether the policy allows the command line, an empty command is an interactive shell
+func (p Policy) commandAllowed(command string) bool {
+	if len(p.AllowedCommands) == 0 {
+		return true
+	}
+
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return false
+	}
+
+	for _, allowed := range p.AllowedCommands {
+		prefix, wildcard := strings.CutSuffix(allowed, " *")
+		if !wildcard {
+			if command == allowed {
+				return true
+			}
+			continue
+		}
+
+		if command == prefix {
+			return true
+		}
+		args, ok := strings.CutPrefix(command, prefix+" ")
+		if ok && !strings.ContainsAny(args, shellControlChars) {
+			return true
+		}
+	}
+	return false
+}
+
+// forcedCommandSession runs the forced command of a peer rule instead of the requested command or shell
+type forcedCommandSession struct {
+	ssh.Session
+	command  string
+	original string
+}
+
+func newForcedCommandSession(session ssh.Session, command string) ssh.Session {
+	return &forcedCommandSession{
+		Session:  session,
+		command:  command,
+		original: session.RawCommand(),
+	}
+}
+
+func (f *forcedCommandSession) RawCommand() string {
+	return f.command
+}
+
+func (f *forcedCommandSession) Command() []string {
+	args, err := shlex.Split(f.command, true)
+	if err != nil {
+		return []string{f.command}
+	}
+	return args
+}
+
+// originalCommand returns the command the client requested if a forced command replaced it
+func originalCommand(session ssh.Session) (string, bool) {
+	for {
+		switch sess := session.(type) {
+		case *forcedCommandSession:
+			return sess.original, sess.original != ""
+		case *recordedSession:
+			session = sess.Session
+		default:
+			return "", false
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/netip"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gliderlabs/ssh"
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cryptossh "golang.org/x/crypto/ssh"
+
+	nbssh "github.com/netbirdio/netbird/client/ssh"
+)
+
+func TestPolicy_CommandAllowed(t *testing.T) {
+	policy := Policy{AllowedCommands: []string{"uptime", "systemctl status *"}}
+
+	tests := []struct {
+		command string
+		allowed bool
+	}{
+		{command: "uptime", allowed: true},
+		{command: " uptime ", allowed: true},
+		{command: "systemctl status", allowed: true},
+		{command: "systemctl status nginx", allowed: true},
+		{command: "", allowed: false},
+		{command: "uptime -p", allowed: false},
+		{command: "systemctl restart nginx", allowed: false},
+		{command: "systemctl statusx", allowed: false},
+		{command: "systemctl status nginx; rm -rf /", allowed: false},
+		{command: "systemctl status $(id)", allowed: false},
+		{command: "systemctl status nginx | sh", allowed: false},
+		{command: "systemctl status nginx\nid", allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			assert.Equal(t, tt.allowed, policy.commandAllowed(tt.command))
+		})
+	}
+
+	assert.True(t, Policy{}.commandAllowed(""), "empty allowlist should allow interactive shells")
+	assert.True(t, Policy{}.commandAllowed("rm -rf /tmp/x"), "empty allowlist should allow all commands")
+}
+
+func TestPolicy_UserAllowed(t *testing.T) {
+	assert.True(t, Policy{}.userAllowed("root"))
+
+	policy := Policy{AllowedUsers: []string{"deploy"}}
+	assert.True(t, policy.userAllowed("deploy"))
+	assert.False(t, policy.userAllowed("root"))
+}
+
+func TestPolicy_PeerAllowed(t *testing.T) {
+	assert.True(t, Policy{}.peerAllowed(netip.MustParseAddr("100.64.0.1")))
+
+	policy := Policy{AllowedPeers: []netip.Prefix{
+		netip.MustParsePrefix("100.64.0.10/32"),
+		netip.MustParsePrefix("100.64.1.0/24"),
+	}}
+	assert.True(t, policy.peerAllowed(netip.MustParseAddr("100.64.0.10")))
+	assert.True(t, policy.peerAllowed(netip.MustParseAddr("100.64.1.20")))
+	assert.False(t, policy.peerAllowed(netip.MustParseAddr("100.64.0.11")))
+
+	invalid := Policy{AllowedPeers: []netip.Prefix{{}}}
+	assert.False(t, invalid.peerAllowed(netip.MustParseAddr("100.64.0.10")), "invalid entries should match no peer")
+}
+
+func TestPolicy_PeerRules(t *testing.T) {
+	policy := Policy{
+		AllowedUsers: []string{"deploy", "backup", "admin"},
+		PeerRules: []PeerRule{
+			{Peers: []netip.Prefix{netip.MustParsePrefix("100.64.0.10/32")}, Users: []string{"admin"}},
+			{Peers: []netip.Prefix{netip.MustParsePrefix("100.64.1.0/24")}, Users: []string{"deploy", "root"}},
+			{Peers: []netip.Prefix{netip.MustParsePrefix("100.64.0.0/16")}},
+		},
+	}
+
+	admin := netip.MustParseAddr("100.64.0.10")
+	ci := netip.MustParseAddr("100.64.1.20")
+	other := netip.MustParseAddr("100.64.2.1")
+	outside := netip.MustParseAddr("100.65.0.1")
+
+	assert.True(t, policy.peerUserAllowed(admin, "admin"))
+	assert.False(t, policy.peerUserAllowed(admin, "deploy"), "the first matching rule should apply")
+	assert.True(t, policy.peerUserAllowed(ci, "deploy"))
+	assert.False(t, policy.peerUserAllowed(ci, "root"), "the users must be allowed by the policy as well")
+	assert.True(t, policy.peerUserAllowed(other, "backup"), "a rule without users should allow the users of the policy")
+	assert.False(t, policy.peerUserAllowed(other, "root"))
+
+	assert.True(t, policy.peerAllowed(other))
+	assert.False(t, policy.peerAllowed(outside), "peers no rule matches should be rejected")
+	assert.False(t, policy.peerUserAllowed(outside, "deploy"))
+	assert.False(t, policy.peerUserAllowed(netip.Addr{}, "deploy"), "unknown peers should be rejected")
+
+	assert.True(t, Policy{}.peerUserAllowed(netip.Addr{}, "root"), "no rules should allow all peers")
+}
+
+func TestForcedCommandSession(t *testing.T) {
+	session := newForcedCommandSession(&testSession{rawCommand: "rsync --server ."}, "/usr/local/bin/backup --verbose 'a b'")
+
+	assert.Equal(t, "/usr/local/bin/backup --verbose 'a b'", session.RawCommand())
+	assert.Equal(t, []string{"/usr/local/bin/backup", "--verbose", "a b"}, session.Command())
+
+	env := prepareSSHEnv(&recordedSession{Session: session})
+	assert.Contains(t, env, "SSH_ORIGINAL_COMMAND=rsync --server .", "the original command should be found through the recording")
+
+	_, ok := originalCommand(newForcedCommandSession(&testSession{}, "uptime"))
+	assert.False(t, ok, "shells have no original command")
+	_, ok = originalCommand(&testSession{rawCommand: "uptime"})
+	assert.False(t, ok)
+}
+
+type testSession struct {
+	ssh.Session
+	rawCommand string
+}
+
+func (s *testSession) RawCommand() string {
+	return s.rawCommand
+}
+
+func (s *testSession) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(100, 64, 0, 10), Port: 40000}
+}
+
+func (s *testSession) LocalAddr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(100, 64, 0, 1), Port: 22022}
+}
+
+func TestServer_PolicyDeniesUser(t *testing.T) {
+	currentUser, err := user.Current()
+	require.NoError(t, err)
+
+	server := New(&Config{})
+	server.SetAllowRootLogin(true)
+	server.UpdatePolicy(&Policy{AllowedUsers: []string{"nb-policy-test-user"}})
+
+	result := server.CheckPrivileges(PrivilegeCheckRequest{
+		RequestedUsername:         currentUser.Username,
+		FeatureSupportsUserSwitch: true,
+		FeatureName:               FeatureSSHLogin,
+	})
+	assert.False(t, result.Allowed)
+	assert.ErrorIs(t, result.Error, ErrUserNotAllowed)
+
+	server.UpdatePolicy(nil)
+	result = server.CheckPrivileges(PrivilegeCheckRequest{
+		RequestedUsername:         currentUser.Username,
+		FeatureSupportsUserSwitch: true,
+		FeatureName:               FeatureSSHLogin,
+	})
+	assert.True(t, result.Allowed)
+}
+
+func TestServer_SessionRecording(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test command needs a Unix shell")
+	}
+
+	hostKey, err := nbssh.GeneratePrivateKey(nbssh.ED25519)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var events []SessionEvent
+	recordingDir := t.TempDir()
+
+	server := New(&Config{
+		HostKeyPEM: hostKey,
+		Recording: &RecordingConfig{
+			Dir: recordingDir,
+			Events: func(event SessionEvent) {
+				mu.Lock()
+				defer mu.Unlock()
+				events = append(events, event)
+			},
+		},
+	})
+	server.SetAllowRootLogin(true)
+	server.UpdatePolicy(&Policy{
+		AllowedCommands: []string{"echo *"},
+		RecordSessions:  true,
+	})
+
+	serverAddr := StartTestServer(t, server)
+	defer func() {
+		require.NoError(t, server.Stop())
+	}()
+
+	currentUser, err := user.Current()
+	require.NoError(t, err)
+
+	client, err := cryptossh.Dial("tcp", serverAddr, &cryptossh.ClientConfig{
+		User:            currentUser.Username,
+		HostKeyCallback: cryptossh.InsecureIgnoreHostKey(), // #nosec G106 - test only
+		Timeout:         3 * time.Second,
+	})
+	require.NoError(t, err)
+	defer func() {
+		if err := client.Close(); err != nil {
+			t.Logf("close client: %v", err)
+		}
+	}()
+
+	t.Run("denied command", func(t *testing.T) {
+		session, err := client.NewSession()
+		require.NoError(t, err)
+		defer session.Close()
+
+		_, err = session.CombinedOutput("id")
+		var exitErr *cryptossh.ExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, 1, exitErr.ExitStatus())
+	})
+
+	t.Run("recorded command", func(t *testing.T) {
+		session, err := client.NewSession()
+		require.NoError(t, err)
+		defer session.Close()
+
+		output, err := session.Output("echo recorded-output")
+		require.NoError(t, err)
+		assert.Equal(t, "recorded-output\n", string(output))
+
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(events) == 2
+		}, 5*time.Second, 50*time.Millisecond)
+
+		mu.Lock()
+		start, end := events[0], events[1]
+		mu.Unlock()
+		assert.False(t, start.End)
+		assert.True(t, end.End)
+		assert.Equal(t, start.ID, end.ID)
+		assert.Equal(t, "echo recorded-output", start.Command)
+		assert.Equal(t, currentUser.Username, start.Username)
+		require.NotEmpty(t, start.File)
+		assert.Equal(t, recordingDir, filepath.Dir(start.File))
+
+		file, err := os.Open(start.File)
+		require.NoError(t, err)
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		require.True(t, scanner.Scan())
+		var header castHeader
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &header))
+		assert.Equal(t, 2, header.Version)
+		assert.Equal(t, "echo recorded-output", header.Command)
+
+		var recorded strings.Builder
+		for scanner.Scan() {
+			var event []any
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+			require.Len(t, event, 3)
+			assert.Equal(t, "o", event[1])
+			recorded.WriteString(event[2].(string))
+		}
+		// the login shell may print to stderr, which is recorded as well
+		assert.Contains(t, recorded.String(), "recorded-output\n")
+	})
+}
+
+func TestServer_PeerRuleForcedCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test command needs a Unix shell")
+	}
+
+	hostKey, err := nbssh.GeneratePrivateKey(nbssh.ED25519)
+	require.NoError(t, err)
+
+	server := New(&Config{HostKeyPEM: hostKey})
+	server.SetAllowRootLogin(true)
+	server.UpdatePolicy(&Policy{PeerRules: []PeerRule{{
+		Peers:        []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")},
+		ForceCommand: "echo forced",
+	}}})
+
+	serverAddr := StartTestServer(t, server)
+	defer func() {
+		require.NoError(t, server.Stop())
+	}()
+
+	currentUser, err := user.Current()
+	require.NoError(t, err)
+
+	client, err := cryptossh.Dial("tcp", serverAddr, &cryptossh.ClientConfig{
+		User:            currentUser.Username,
+		HostKeyCallback: cryptossh.InsecureIgnoreHostKey(), // #nosec G106 - test only
+		Timeout:         3 * time.Second,
+	})
+	require.NoError(t, err)
+	defer func() {
+		if err := client.Close(); err != nil {
+			t.Logf("close client: %v", err)
+		}
+	}()
+
+	session, err := client.NewSession()
+	require.NoError(t, err)
+	defer session.Close()
+
+	output, err := session.Output("id")
+	require.NoError(t, err)
+	assert.Contains(t, string(output), "forced\n")
+
+	server.SetAllowSFTP(true)
+	_, err = sftp.NewClient(client)
+	assert.Error(t, err, "SFTP should be denied with a forced command")
+}
@@ -50,12 +50,9 @@ func (s *Server) SetAllowRemotePortForwarding(allow bool) {
 
 // configurePortForwarding sets up port forwarding callbacks
 func (s *Server) configurePortForwarding(server *ssh.Server) {
-	allowLocal := s.allowLocalPortForwarding
-	allowRemote := s.allowRemotePortForwarding
-
 	server.LocalPortForwardingCallback = func(ctx ssh.Context, dstHost string, dstPort uint32) bool {
 		logger := s.getRequestLogger(ctx)
-		if !allowLocal {
+		if !s.isLocalPortForwardingAllowed() {
 			logger.Warnf("local port forwarding denied for %s:%d: disabled", dstHost, dstPort)
 			return false
 		}
@@ -70,7 +67,7 @@ func (s *Server) configurePortForwarding(server *ssh.Server) {
 
 	server.ReversePortForwardingCallback = func(ctx ssh.Context, bindHost string, bindPort uint32) bool {
 		logger := s.getRequestLogger(ctx)
-		if !allowRemote {
+		if !s.isRemotePortForwardingAllowed() {
 			logger.Warnf("remote port forwarding denied for %s:%d: disabled", bindHost, bindPort)
 			return false
 		}
@@ -83,7 +80,8 @@ func (s *Server) configurePortForwarding(server *ssh.Server) {
 		return true
 	}
 
-	log.Debugf("SSH server configured with local_forwarding=%v, remote_forwarding=%v", allowLocal, allowRemote)
+	log.Debugf("SSH server configured with local_forwarding=%v, remote_forwarding=%v",
+		s.allowLocalPortForwarding, s.allowRemotePortForwarding)
 }
 
 // checkPortForwardingPrivileges validates privilege requirements for port forwarding operations.
@@ -102,6 +100,7 @@ func (s *Server) checkPortForwardingPrivileges(ctx ssh.Context, forwardType stri
 		RequestedUsername:         ctx.User(),
 		FeatureSupportsUserSwitch: true,
 		FeatureName:               forwardType + " port forwarding",
+		RemoteAddr:                ctx.RemoteAddr(),
 	})
 
 	if !result.Allowed {
@@ -264,6 +263,13 @@ func (s *Server) getRequestLogger(ctx ssh.Context) *log.Entry {
 	return log.WithField("session", fmt.Sprintf("%s@%s", ctx.User(), remoteAddr))
 }
 
+// isLocalPortForwardingAllowed checks if local port forwarding is enabled
+func (s *Server) isLocalPortForwardingAllowed() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.allowLocalPortForwarding
+}
+
 // isRemotePortForwardingAllowed checks if remote port forwarding is enabled
 func (s *Server) isRemotePortForwardingAllowed() bool {
 	s.mu.RLock()
@@ -0,0 +1,226 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gliderlabs/ssh"
+	log "github.com/sirupsen/logrus"
+)
+
+// RecordingConfig configures where the sessions are recorded to if the policy enables the recording
+type RecordingConfig struct {
+	// Dir stores the output of every recorded session in an asciicast v2 file. Empty disables the file recording.
+	Dir string
+	// Events receives the start and the end of every recorded session. Nil disables the session events.
+	Events func(SessionEvent)
+}
+
+// SessionEvent is the start or the end of a recorded session
+type SessionEvent struct {
+	// ID identifies the session, the start and the end event share it
+	ID string
+	// End is false for the start and true for the end of the session
+	End         bool
+	Username    string
+	JWTUsername string
+	RemoteAddr  netip.AddrPort
+	LocalAddr   netip.AddrPort
+	// Command is the executed command line, empty for interactive shells
+	Command string
+	// File is the asciicast file of the session, empty if sessions aren't recorded to files
+	File string
+	// Duration is set for the end of the session
+	Duration time.Duration
+}
+
+// castHeader is the header line of an asciicast v2 file
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Command   string            `json:"command,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// recordedSession records the output of a session. The input isn't recorded to keep typed secrets out of the
+// recordings.
+type recordedSession struct {
+	ssh.Session
+
+	mu     sync.Mutex
+	file   *os.File
+	start  time.Time
+	event  SessionEvent
+	events func(SessionEvent)
+	logger *log.Entry
+}
+
+// recordSession wraps the session to record it if the policy enables the recording, the returned function ends
+// the recording
+func (s *Server) recordSession(logger *log.Entry, session ssh.Session, key sessionKey, jwtUsername string) (ssh.Session, func()) {
+	s.mu.RLock()
+	recording := s.recording
+	enabled := s.policy.RecordSessions
+	s.mu.RUnlock()
+
+	if !enabled || recording == nil || (recording.Dir == "" && recording.Events == nil) {
+		return session, func() {}
+	}
+
+	r := &recordedSession{
+		Session: session,
+		start:   time.Now(),
+		events:  recording.Events,
+		logger:  logger,
+		event: SessionEvent{
+			ID:          string(key),
+			Username:    session.User(),
+			JWTUsername: jwtUsername,
+			RemoteAddr:  addrPortOf(session.RemoteAddr()),
+			LocalAddr:   addrPortOf(session.LocalAddr()),
+			Command:     session.RawCommand(),
+		},
+	}
+
+	if recording.Dir != "" {
+		file, err := createRecordingFile(recording.Dir, session.User(), r.start)
+		if err != nil {
+			logger.Warnf("failed to record session: %v", err)
+		} else {
+			r.file = file
+			r.event.File = file.Name()
+			r.writeHeader()
+			logger.Infof("recording session to %s", file.Name())
+		}
+	}
+
+	if r.events != nil {
+		r.events(r.event)
+	}
+
+	return r, r.close
+}
+
+func createRecordingFile(dir, username string, start time.Time) (*os.File, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create recording directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%s.cast", start.UTC().Format("20060102T150405.000000000Z"), filepath.Base(username))
+	file, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("create recording file: %w", err)
+	}
+	return file, nil
+}
+
+func (r *recordedSession) writeHeader() {
+	header := castHeader{
+		Version:   2,
+		Width:     80,
+		Height:    24,
+		Timestamp: r.start.Unix(),
+		Command:   r.event.Command,
+	}
+	if ptyReq, _, isPty := r.Session.Pty(); isPty {
+		header.Width = ptyReq.Window.Width
+		header.Height = ptyReq.Window.Height
+		header.Env = map[string]string{"TERM": ptyReq.Term}
+	}
+
+	line, err := json.Marshal(header)
+	if err != nil {
+		r.logger.Debugf("marshal recording header: %v", err)
+		return
+	}
+	r.writeLine(line)
+}
+
+// Write records the output before it is sent to the client
+func (r *recordedSession) Write(p []byte) (int, error) {
+	n, err := r.Session.Write(p)
+	r.record(p[:n])
+	return n, err
+}
+
+// Stderr records the error output before it is sent to the client
+func (r *recordedSession) Stderr() io.ReadWriter {
+	return &recordedStderr{ReadWriter: r.Session.Stderr(), session: r}
+}
+
+func (r *recordedSession) record(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+
+	line, err := json.Marshal([]any{time.Since(r.start).Seconds(), "o", string(data)})
+	if err != nil {
+		r.logger.Debugf("marshal recording event: %v", err)
+		return
+	}
+	r.writeLine(line)
+}
+
+func (r *recordedSession) writeLine(line []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return
+	}
+	if _, err := r.file.Write(append(line, '\n')); err != nil {
+		r.logger.Warnf("failed to write session recording, stopping the recording: %v", err)
+		r.closeFile()
+	}
+}
+
+func (r *recordedSession) close() {
+	r.mu.Lock()
+	r.closeFile()
+	r.mu.Unlock()
+
+	if r.events != nil {
+		event := r.event
+		event.End = true
+		event.Duration = time.Since(r.start)
+		r.events(event)
+	}
+}
+
+func (r *recordedSession) closeFile() {
+	if r.file == nil {
+		return
+	}
+	if err := r.file.Close(); err != nil {
+		r.logger.Debugf("close session recording: %v", err)
+	}
+	r.file = nil
+}
+
+type recordedStderr struct {
+	io.ReadWriter
+	session *recordedSession
+}
+
+func (s *recordedStderr) Write(p []byte) (int, error) {
+	n, err := s.ReadWriter.Write(p)
+	s.session.record(p[:n])
+	return n, err
+}
+
+func addrPortOf(addr net.Addr) netip.AddrPort {
+	if tcpAddr, ok := addr.(*net.TCPAddr); ok {
+		addrPort := tcpAddr.AddrPort()
+		return netip.AddrPortFrom(addrPort.Addr().Unmap(), addrPort.Port())
+	}
+	return netip.AddrPort{}
+}
@@ -170,6 +170,11 @@ type Server struct {
 
 	authorizer *sshauth.Authorizer
 
+	// policy restricts users, commands and peers and enables the session recording
+	policy Policy
+	// recording is where recorded sessions go, nil disables the recording
+	recording *RecordingConfig
+
 	suSupportsPty    bool
 	loginIsUtilLinux bool
 }
@@ -188,6 +193,10 @@ type Config struct {
 
 	// HostKey is the SSH server host key in PEM format
 	HostKeyPEM []byte
+
+	// Recording configures where sessions are recorded to if the policy enables the recording.
+	// If nil, sessions are never recorded.
+	Recording *RecordingConfig
 }
 
 // SessionInfo contains information about an active SSH session
@@ -212,6 +221,7 @@ func New(config *Config) *Server {
 		jwtEnabled:             config.JWT != nil,
 		jwtConfig:              config.JWT,
 		authorizer:             sshauth.NewAuthorizer(), // Initialize with empty config
+		recording:              config.Recording,
 	}
 
 	return s
@@ -773,6 +783,11 @@ func (s *Server) connectionValidator(_ ssh.Context, conn net.Conn) net.Conn {
 		return nil
 	}
 
+	if !s.getPolicy().peerAllowed(remoteIP) {
+		log.Warnf("SSH connection rejected from NetBird peer %s: not allowed by the SSH policy", remoteIP)
+		return nil
+	}
+
 	log.Infof("SSH connection from NetBird peer %s allowed", tcpAddr)
 	return &trackedConn{
 		Conn:       conn,
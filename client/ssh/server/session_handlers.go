@@ -53,15 +53,36 @@ func (s *Server) sessionHandler(session ssh.Session) {
 		logger.Infof("SSH session closed after %v", duration)
 	}()
 
-	privilegeResult, err := s.userPrivilegeCheck(session.User())
+	privilegeResult, err := s.userPrivilegeCheck(session.User(), session.RemoteAddr())
 	if err != nil {
 		s.handlePrivError(logger, session, err)
 		return
 	}
 
+	policy := s.getPolicy()
+	rule, _ := policy.peerRule(addrPortOf(session.RemoteAddr()).Addr())
+	if rule.ForceCommand != "" {
+		logger.Infof("running the command forced by the SSH policy instead of %s", safeLogCommand(session.Command()))
+		session = newForcedCommandSession(session, rule.ForceCommand)
+	}
+
 	ptyReq, winCh, isPty := session.Pty()
 	hasCommand := len(session.Command()) > 0
 
+	if isPty || hasCommand {
+		shellDenied := !hasCommand && rule.DenyShell
+		if shellDenied || !policy.commandAllowed(session.RawCommand()) {
+			s.handleCommandDenied(logger, session)
+			return
+		}
+	}
+
+	if isPty || hasCommand {
+		recorded, stopRecording := s.recordSession(logger, session, sessionKey, jwtUsername)
+		defer stopRecording()
+		session = recorded
+	}
+
 	switch {
 	case isPty && hasCommand:
 		// ssh -t <host> <cmd> - Pty command execution
@@ -78,6 +99,18 @@ func (s *Server) sessionHandler(session ssh.Session) {
 	}
 }
 
+// handleCommandDenied rejects a command or an interactive shell the SSH policy doesn't allow
+func (s *Server) handleCommandDenied(logger *log.Entry, session ssh.Session) {
+	logger.Warnf("rejected %s: not allowed by the SSH policy", safeLogCommand(session.Command()))
+
+	if _, err := io.WriteString(session.Stderr(), "command is not allowed on this SSH server\n"); err != nil {
+		logger.Debugf(errWriteSession, err)
+	}
+	if err := session.Exit(1); err != nil {
+		logSessionExitError(logger, err)
+	}
+}
+
 // handleNonInteractiveSession handles sessions that have no PTY and no command.
 // These are typically used for port forwarding (ssh -L/-R) or tunneling (ssh -N).
 func (s *Server) handleNonInteractiveSession(logger *log.Entry, session ssh.Session) {
@@ -169,6 +202,9 @@ func (s *Server) buildUserLookupErrorMessage(err error) string {
 		}
 		return "privileged user access is disabled on this SSH server\n"
 
+	case errors.Is(err, ErrUserNotAllowed):
+		return "login as this user is not allowed on this SSH server\n"
+
 	case errors.Is(err, ErrPrivilegeRequired):
 		return "Windows user switching failed - NetBird must run with elevated privileges for user switching\n"
 
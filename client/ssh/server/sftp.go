@@ -42,10 +42,19 @@ func (s *Server) sftpSubsystemHandler(sess ssh.Session) {
 		return
 	}
 
+	if rule, _ := s.getPolicy().peerRule(addrPortOf(sess.RemoteAddr()).Addr()); rule.ForceCommand != "" {
+		logger.Warn("SFTP subsystem request denied: the SSH policy forces a command")
+		if err := sess.Exit(1); err != nil {
+			logger.Debugf("SFTP session exit: %v", err)
+		}
+		return
+	}
+
 	result := s.CheckPrivileges(PrivilegeCheckRequest{
 		RequestedUsername:         sess.User(),
 		FeatureSupportsUserSwitch: true,
 		FeatureName:               FeatureSFTP,
+		RemoteAddr:                sess.RemoteAddr(),
 	})
 
 	if !result.Allowed {
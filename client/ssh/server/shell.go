@@ -171,10 +171,15 @@ func prepareSSHEnv(session ssh.Session) []string {
 		localPort = strconv.Itoa(InternalSSHPort)
 	}
 
-	return []string{
+	env := []string{
 		// SSH_CLIENT format: "client_ip client_port server_port"
 		fmt.Sprintf("SSH_CLIENT=%s %s %s", remoteHost, remotePort, localPort),
 		// SSH_CONNECTION format: "client_ip client_port server_ip server_port"
 		fmt.Sprintf("SSH_CONNECTION=%s %s %s %s", remoteHost, remotePort, localHost, localPort),
 	}
+	// the forced command of the SSH policy gets the requested command like with OpenSSH
+	if command, ok := originalCommand(session); ok {
+		env = append(env, "SSH_ORIGINAL_COMMAND="+command)
+	}
+	return env
 }
@@ -3,6 +3,7 @@ package server
 import (
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"os/user"
 	"runtime"
@@ -44,6 +45,8 @@ type PrivilegeCheckRequest struct {
 	RequestedUsername         string
 	FeatureSupportsUserSwitch bool // Does this feature/operation support user switching?
 	FeatureName               string
+	// RemoteAddr is the address of the peer, the peer rules of the SSH policy apply to it
+	RemoteAddr net.Addr
 }
 
 // PrivilegeCheckResult represents the result of a privilege check
@@ -73,6 +76,17 @@ func (s *Server) CheckPrivileges(req PrivilegeCheckRequest) PrivilegeCheckResult
 		return PrivilegeCheckResult{Allowed: false, Error: err}
 	}
 
+	username := req.RequestedUsername
+	if username == "" {
+		username = context.currentUser.Username
+	}
+	if !s.getPolicy().peerUserAllowed(addrPortOf(req.RemoteAddr).Addr(), username) {
+		return PrivilegeCheckResult{
+			Allowed: false,
+			Error:   &UserNotAllowedError{Username: username},
+		}
+	}
+
 	// Handle empty username case - but still check root access controls
 	if req.RequestedUsername == "" {
 		if isPrivilegedUsername(context.currentUser.Username) && !context.allowRoot {
@@ -276,11 +290,12 @@ func (s *Server) userNameLookup(username string) (*user.User, error) {
 }
 
 // userPrivilegeCheck performs user lookup with full privilege check result
-func (s *Server) userPrivilegeCheck(username string) (PrivilegeCheckResult, error) {
+func (s *Server) userPrivilegeCheck(username string, remoteAddr net.Addr) (PrivilegeCheckResult, error) {
 	result := s.CheckPrivileges(PrivilegeCheckRequest{
 		RequestedUsername:         username,
 		FeatureSupportsUserSwitch: true,
 		FeatureName:               FeatureSSHLogin,
+		RemoteAddr:                remoteAddr,
 	})
 
 	if !result.Allowed {
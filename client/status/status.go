@@ -21,22 +21,54 @@ import (
 	"github.com/netbirdio/netbird/version"
 )
 
+const (
+	expectedStatusOnline  = "online"
+	expectedStatusOffline = "offline"
+	expectedStatusBlocked = "blocked"
+)
+
 type PeerStateDetailOutput struct {
-	FQDN                   string           `json:"fqdn" yaml:"fqdn"`
-	IP                     string           `json:"netbirdIp" yaml:"netbirdIp"`
-	PubKey                 string           `json:"publicKey" yaml:"publicKey"`
-	Status                 string           `json:"status" yaml:"status"`
-	LastStatusUpdate       time.Time        `json:"lastStatusUpdate" yaml:"lastStatusUpdate"`
-	ConnType               string           `json:"connectionType" yaml:"connectionType"`
-	IceCandidateType       IceCandidateType `json:"iceCandidateType" yaml:"iceCandidateType"`
-	IceCandidateEndpoint   IceCandidateType `json:"iceCandidateEndpoint" yaml:"iceCandidateEndpoint"`
-	RelayAddress           string           `json:"relayAddress" yaml:"relayAddress"`
-	LastWireguardHandshake time.Time        `json:"lastWireguardHandshake" yaml:"lastWireguardHandshake"`
-	TransferReceived       int64            `json:"transferReceived" yaml:"transferReceived"`
-	TransferSent           int64            `json:"transferSent" yaml:"transferSent"`
-	Latency                time.Duration    `json:"latency" yaml:"latency"`
-	RosenpassEnabled       bool             `json:"quantumResistance" yaml:"quantumResistance"`
-	Networks               []string         `json:"networks" yaml:"networks"`
+	FQDN                   string                  `json:"fqdn" yaml:"fqdn"`
+	IP                     string                  `json:"netbirdIp" yaml:"netbirdIp"`
+	PubKey                 string                  `json:"publicKey" yaml:"publicKey"`
+	Status                 string                  `json:"status" yaml:"status"`
+	LastStatusUpdate       time.Time               `json:"lastStatusUpdate" yaml:"lastStatusUpdate"`
+	ConnType               string                  `json:"connectionType" yaml:"connectionType"`
+	IceCandidateType       IceCandidateType        `json:"iceCandidateType" yaml:"iceCandidateType"`
+	IceCandidateEndpoint   IceCandidateType        `json:"iceCandidateEndpoint" yaml:"iceCandidateEndpoint"`
+	RelayAddress           string                  `json:"relayAddress" yaml:"relayAddress"`
+	LastWireguardHandshake time.Time               `json:"lastWireguardHandshake" yaml:"lastWireguardHandshake"`
+	TransferReceived       int64                   `json:"transferReceived" yaml:"transferReceived"`
+	TransferSent           int64                   `json:"transferSent" yaml:"transferSent"`
+	TotalTransferReceived  int64                   `json:"totalTransferReceived" yaml:"totalTransferReceived"`
+	TotalTransferSent      int64                   `json:"totalTransferSent" yaml:"totalTransferSent"`
+	LastConnected          time.Time               `json:"lastConnected" yaml:"lastConnected"`
+	Latency                time.Duration           `json:"latency" yaml:"latency"`
+	RosenpassEnabled       bool                    `json:"quantumResistance" yaml:"quantumResistance"`
+	Networks               []string                `json:"networks" yaml:"networks"`
+	Maintenance            bool                    `json:"maintenance" yaml:"maintenance"`
+	AllowedIPs             []string                `json:"allowedIps" yaml:"allowedIps"`
+	ExpectedStatus         string                  `json:"expectedStatus" yaml:"expectedStatus"`
+	OfflineReason          string                  `json:"offlineReason,omitempty" yaml:"offlineReason,omitempty"`
+	NetworkTransfers       []NetworkTransferOutput `json:"networkTransfers,omitempty" yaml:"networkTransfers,omitempty"`
+	TunnelProbe            *PeerProbeOutput        `json:"tunnelProbe,omitempty" yaml:"tunnelProbe,omitempty"`
+}
+
+// PeerProbeOutput is the round trip time and loss of the echo probes sent to a peer through the tunnel
+type PeerProbeOutput struct {
+	Sent     int           `json:"sent" yaml:"sent"`
+	Received int           `json:"received" yaml:"received"`
+	RTTMin   time.Duration `json:"rttMin" yaml:"rttMin"`
+	RTTAvg   time.Duration `json:"rttAvg" yaml:"rttAvg"`
+	RTTMax   time.Duration `json:"rttMax" yaml:"rttMax"`
+	Error    string        `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// NetworkTransferOutput is the transfer of the flows to and from a routed network
+type NetworkTransferOutput struct {
+	Network          string `json:"network" yaml:"network"`
+	TransferReceived int64  `json:"transferReceived" yaml:"transferReceived"`
+	TransferSent     int64  `json:"transferSent" yaml:"transferSent"`
 }
 
 type PeersStateOutput struct {
@@ -46,6 +78,17 @@ type PeersStateOutput struct {
 }
 
 type SignalStateOutput struct {
+	URL                  string        `json:"url" yaml:"url"`
+	Connected            bool          `json:"connected" yaml:"connected"`
+	Error                string        `json:"error" yaml:"error"`
+	Reconnects           int           `json:"reconnects,omitempty" yaml:"reconnects,omitempty"`
+	LastDisconnect       *time.Time    `json:"lastDisconnect,omitempty" yaml:"lastDisconnect,omitempty"`
+	LastDisconnectReason string        `json:"lastDisconnectReason,omitempty" yaml:"lastDisconnectReason,omitempty"`
+	Latency              time.Duration `json:"latency,omitempty" yaml:"latency,omitempty"`
+}
+
+// FlowStateOutput is the state of the connection to the flow receiver
+type FlowStateOutput struct {
 	URL       string `json:"url" yaml:"url"`
 	Connected bool   `json:"connected" yaml:"connected"`
 	Error     string `json:"error" yaml:"error"`
@@ -100,6 +143,7 @@ type OutputOverview struct {
 	DaemonVersion           string                     `json:"daemonVersion" yaml:"daemonVersion"`
 	ManagementState         ManagementStateOutput      `json:"management" yaml:"management"`
 	SignalState             SignalStateOutput          `json:"signal" yaml:"signal"`
+	FlowState               *FlowStateOutput           `json:"flow,omitempty" yaml:"flow,omitempty"`
 	Relays                  RelayStateOutput           `json:"relays" yaml:"relays"`
 	IP                      string                     `json:"netbirdIp" yaml:"netbirdIp"`
 	PubKey                  string                     `json:"publicKey" yaml:"publicKey"`
@@ -108,12 +152,52 @@ type OutputOverview struct {
 	RosenpassEnabled        bool                       `json:"quantumResistance" yaml:"quantumResistance"`
 	RosenpassPermissive     bool                       `json:"quantumResistancePermissive" yaml:"quantumResistancePermissive"`
 	Networks                []string                   `json:"networks" yaml:"networks"`
+	Roles                   []string                   `json:"roles,omitempty" yaml:"roles,omitempty"`
+	NetworkTransfers        []NetworkTransferOutput    `json:"networkTransfers,omitempty" yaml:"networkTransfers,omitempty"`
 	NumberOfForwardingRules int                        `json:"forwardingRules" yaml:"forwardingRules"`
 	NSServerGroups          []NsServerGroupStateOutput `json:"dnsServers" yaml:"dnsServers"`
 	Events                  []SystemEventOutput        `json:"events" yaml:"events"`
 	LazyConnectionEnabled   bool                       `json:"lazyConnectionEnabled" yaml:"lazyConnectionEnabled"`
 	ProfileName             string                     `json:"profileName" yaml:"profileName"`
 	SSHServerState          SSHServerStateOutput       `json:"sshServer" yaml:"sshServer"`
+	MaintenanceEnabled      bool                       `json:"maintenanceEnabled" yaml:"maintenanceEnabled"`
+	FirewallSets            []FirewallSetOutput        `json:"firewallSets,omitempty" yaml:"firewallSets,omitempty"`
+	FirewallBackend         *FirewallBackendOutput     `json:"firewallBackend,omitempty" yaml:"firewallBackend,omitempty"`
+	NetstackFallback        string                     `json:"netstackFallback,omitempty" yaml:"netstackFallback,omitempty"`
+	DNSListener             *DNSListenerOutput         `json:"dnsListener,omitempty" yaml:"dnsListener,omitempty"`
+	PeerResources           *PeerResourcesOutput       `json:"peerResources,omitempty" yaml:"peerResources,omitempty"`
+}
+
+// PeerResourcesOutput is the resource usage of the peer connections, a zero limit is unlimited
+type PeerResourcesOutput struct {
+	Goroutines        int64 `json:"goroutines" yaml:"goroutines"`
+	ICEAgents         int64 `json:"iceAgents" yaml:"iceAgents"`
+	BufferBytes       int64 `json:"bufferBytes" yaml:"bufferBytes"`
+	MaxGoroutines     int64 `json:"maxGoroutines" yaml:"maxGoroutines"`
+	MaxICEAgents      int64 `json:"maxIceAgents" yaml:"maxIceAgents"`
+	MaxBufferBytes    int64 `json:"maxBufferBytes" yaml:"maxBufferBytes"`
+	Peers             int   `json:"peers" yaml:"peers"`
+	DeniedActivations int64 `json:"deniedActivations" yaml:"deniedActivations"`
+	DeniedICEAgents   int64 `json:"deniedIceAgents" yaml:"deniedIceAgents"`
+}
+
+// DNSListenerOutput are the addresses the local DNS resolver listens on
+type DNSListenerOutput struct {
+	Addresses []string `json:"addresses" yaml:"addresses"`
+	Fallbacks []string `json:"fallbacks,omitempty" yaml:"fallbacks,omitempty"`
+}
+
+// FirewallBackendOutput tells which firewall backend the client uses and why it was chosen
+type FirewallBackendOutput struct {
+	Backend string `json:"backend" yaml:"backend"`
+	Native  string `json:"native,omitempty" yaml:"native,omitempty"`
+	Reason  string `json:"reason" yaml:"reason"`
+}
+
+// FirewallSetOutput is the number of peer addresses or route networks a firewall set holds
+type FirewallSetOutput struct {
+	Name     string `json:"name" yaml:"name"`
+	Elements int    `json:"elements" yaml:"elements"`
 }
 
 func ConvertToStatusOutputOverview(resp *proto.StatusResponse, anon bool, statusFilter string, prefixNamesFilter []string, prefixNamesFilterMap map[string]struct{}, ipsFilter map[string]struct{}, connectionTypeFilter string, profName string) OutputOverview {
@@ -128,9 +212,16 @@ func ConvertToStatusOutputOverview(resp *proto.StatusResponse, anon bool, status
 
 	signalState := pbFullStatus.GetSignalState()
 	signalOverview := SignalStateOutput{
-		URL:       signalState.GetURL(),
-		Connected: signalState.GetConnected(),
-		Error:     signalState.Error,
+		URL:                  signalState.GetURL(),
+		Connected:            signalState.GetConnected(),
+		Error:                signalState.Error,
+		Reconnects:           int(signalState.GetReconnects()),
+		LastDisconnectReason: signalState.GetLastDisconnectReason(),
+		Latency:              signalState.GetLatency().AsDuration(),
+	}
+	if signalState.GetLastDisconnect() != nil {
+		lastDisconnect := signalState.GetLastDisconnect().AsTime().Local()
+		signalOverview.LastDisconnect = &lastDisconnect
 	}
 
 	relayOverview := mapRelays(pbFullStatus.GetRelays())
@@ -143,6 +234,7 @@ func ConvertToStatusOutputOverview(resp *proto.StatusResponse, anon bool, status
 		DaemonVersion:           resp.GetDaemonVersion(),
 		ManagementState:         managementOverview,
 		SignalState:             signalOverview,
+		FlowState:               mapFlowState(pbFullStatus.GetFlowState()),
 		Relays:                  relayOverview,
 		IP:                      pbFullStatus.GetLocalPeerState().GetIP(),
 		PubKey:                  pbFullStatus.GetLocalPeerState().GetPubKey(),
@@ -151,12 +243,20 @@ func ConvertToStatusOutputOverview(resp *proto.StatusResponse, anon bool, status
 		RosenpassEnabled:        pbFullStatus.GetLocalPeerState().GetRosenpassEnabled(),
 		RosenpassPermissive:     pbFullStatus.GetLocalPeerState().GetRosenpassPermissive(),
 		Networks:                pbFullStatus.GetLocalPeerState().GetNetworks(),
+		Roles:                   pbFullStatus.GetLocalPeerState().GetRoles(),
+		NetworkTransfers:        mapNetworkTransfers(pbFullStatus.GetLocalPeerState().GetNetworkTransfers()),
 		NumberOfForwardingRules: int(pbFullStatus.GetNumberOfForwardingRules()),
 		NSServerGroups:          mapNSGroups(pbFullStatus.GetDnsServers()),
 		Events:                  mapEvents(pbFullStatus.GetEvents()),
 		LazyConnectionEnabled:   pbFullStatus.GetLazyConnectionEnabled(),
 		ProfileName:             profName,
 		SSHServerState:          sshServerOverview,
+		MaintenanceEnabled:      pbFullStatus.GetMaintenanceEnabled(),
+		FirewallSets:            mapFirewallSets(pbFullStatus.GetFirewallSets()),
+		FirewallBackend:         mapFirewallBackend(pbFullStatus.GetFirewallBackend()),
+		NetstackFallback:        pbFullStatus.GetNetstackFallback(),
+		DNSListener:             mapDNSListener(pbFullStatus.GetDnsListener()),
+		PeerResources:           mapPeerResources(pbFullStatus.GetPeerResources()),
 	}
 
 	if anon {
@@ -277,6 +377,11 @@ func mapPeers(
 			transferSent = pbPeerState.GetBytesTx()
 		}
 
+		lastConnected := time.Time{}
+		if pbPeerState.GetLastConnected() != nil {
+			lastConnected = pbPeerState.GetLastConnected().AsTime().Local()
+		}
+
 		timeLocal := pbPeerState.GetConnStatusUpdate().AsTime().Local()
 		peerState := PeerStateDetailOutput{
 			IP:               pbPeerState.GetIP(),
@@ -297,9 +402,17 @@ func mapPeers(
 			LastWireguardHandshake: lastHandshake,
 			TransferReceived:       transferReceived,
 			TransferSent:           transferSent,
+			TotalTransferReceived:  pbPeerState.GetTotalBytesRx(),
+			TotalTransferSent:      pbPeerState.GetTotalBytesTx(),
+			LastConnected:          lastConnected,
 			Latency:                pbPeerState.GetLatency().AsDuration(),
 			RosenpassEnabled:       pbPeerState.GetRosenpassEnabled(),
 			Networks:               pbPeerState.GetNetworks(),
+			Maintenance:            pbPeerState.GetMaintenance(),
+			AllowedIPs:             pbPeerState.GetAllowedIps(),
+			ExpectedStatus:         expectedPeerStatus(pbPeerState),
+			OfflineReason:          pbPeerState.GetOfflineReason(),
+			NetworkTransfers:       mapNetworkTransfers(pbPeerState.GetNetworkTransfers()),
 		}
 
 		peersStateDetail = append(peersStateDetail, peerState)
@@ -315,6 +428,20 @@ func mapPeers(
 	return peersOverview
 }
 
+// expectedPeerStatus returns the state management reported for the peer,
+// which may differ from the local connection status. Peers that management keeps away
+// for a reason, e.g. a failed posture check, are blocked rather than offline.
+func expectedPeerStatus(pbPeerState *proto.PeerState) string {
+	switch {
+	case pbPeerState.GetOffline() && pbPeerState.GetOfflineReason() != "":
+		return expectedStatusBlocked
+	case pbPeerState.GetOffline():
+		return expectedStatusOffline
+	default:
+		return expectedStatusOnline
+	}
+}
+
 func sortPeersByIP(peersStateDetail []PeerStateDetailOutput) {
 	if len(peersStateDetail) > 0 {
 		sort.SliceStable(peersStateDetail, func(i, j int) bool {
@@ -342,31 +469,8 @@ func ParseToYAML(overview OutputOverview) (string, error) {
 }
 
 func ParseGeneralSummary(overview OutputOverview, showURL bool, showRelays bool, showNameServers bool, showSSHSessions bool) string {
-	var managementConnString string
-	if overview.ManagementState.Connected {
-		managementConnString = "Connected"
-		if showURL {
-			managementConnString = fmt.Sprintf("%s to %s", managementConnString, overview.ManagementState.URL)
-		}
-	} else {
-		managementConnString = "Disconnected"
-		if overview.ManagementState.Error != "" {
-			managementConnString = fmt.Sprintf("%s, reason: %s", managementConnString, overview.ManagementState.Error)
-		}
-	}
-
-	var signalConnString string
-	if overview.SignalState.Connected {
-		signalConnString = "Connected"
-		if showURL {
-			signalConnString = fmt.Sprintf("%s to %s", signalConnString, overview.SignalState.URL)
-		}
-	} else {
-		signalConnString = "Disconnected"
-		if overview.SignalState.Error != "" {
-			signalConnString = fmt.Sprintf("%s, reason: %s", signalConnString, overview.SignalState.Error)
-		}
-	}
+	managementConnString := parseConnState(overview.ManagementState.Connected, overview.ManagementState.URL, overview.ManagementState.Error, showURL)
+	signalConnString := parseConnState(overview.SignalState.Connected, overview.SignalState.URL, overview.SignalState.Error, showURL)
 
 	interfaceTypeString := "Userspace"
 	interfaceIP := overview.IP
@@ -379,21 +483,7 @@ func ParseGeneralSummary(overview OutputOverview, showURL bool, showRelays bool,
 
 	var relaysString string
 	if showRelays {
-		for _, relay := range overview.Relays.Details {
-			available := "Available"
-			reason := ""
-
-			if !relay.Available {
-				if relay.Error == probeRelay.ErrCheckInProgress.Error() {
-					available = "Checking..."
-				} else {
-					available = "Unavailable"
-					reason = fmt.Sprintf(", reason: %s", relay.Error)
-				}
-			}
-
-			relaysString += fmt.Sprintf("\n  [%s] is %s%s", relay.URI, available, reason)
-		}
+		relaysString = parseRelayDetails(overview.Relays)
 	} else {
 		relaysString = fmt.Sprintf("%d/%d Available", overview.Relays.Available, overview.Relays.Total)
 	}
@@ -529,9 +619,236 @@ func ParseGeneralSummary(overview OutputOverview, showURL bool, showRelays bool,
 		overview.NumberOfForwardingRules,
 		peersCountString,
 	)
+
+	// the fallback changes how the peers are reached, so it leads the summary
+	if overview.NetstackFallback != "" {
+		summary = fmt.Sprintf("WARNING: running in netstack mode (%s)\n", overview.NetstackFallback) + summary
+	}
+
+	if len(overview.Roles) > 0 {
+		summary += fmt.Sprintf("Roles: %s\n", strings.Join(overview.Roles, ", "))
+	}
+	if len(overview.NetworkTransfers) > 0 {
+		summary += "Network transfer (received/sent):\n" + parseNetworkTransfers(overview.NetworkTransfers, "  ")
+	}
+	if overview.MaintenanceEnabled {
+		summary += "Maintenance mode: enabled\n"
+	}
+	if backend := overview.FirewallBackend; backend != nil {
+		name := backend.Backend
+		if backend.Native != "" {
+			name = fmt.Sprintf("%s with %s", backend.Backend, backend.Native)
+		}
+		summary += fmt.Sprintf("Firewall: %s (%s)\n", name, backend.Reason)
+	}
+	if len(overview.FirewallSets) > 0 {
+		elements := 0
+		for _, set := range overview.FirewallSets {
+			elements += set.Elements
+		}
+		summary += fmt.Sprintf("Firewall sets: %d (%d elements)\n", len(overview.FirewallSets), elements)
+	}
+	if listener := overview.DNSListener; listener != nil {
+		summary += fmt.Sprintf("DNS listener: %s\n", strings.Join(listener.Addresses, ", "))
+		for _, fallback := range listener.Fallbacks {
+			summary += fmt.Sprintf("  WARNING: %s\n", fallback)
+		}
+	}
+	if res := overview.PeerResources; res != nil {
+		summary += "Peer resources: " + parsePeerResources(res)
+	}
 	return summary
 }
 
+// parsePeerResources describes the resource usage of the peer connections with the configured limits
+func parsePeerResources(res *PeerResourcesOutput) string {
+	withLimit := func(used, limit int64, unit string) string {
+		if limit == 0 {
+			return fmt.Sprintf("%d %s", used, unit)
+		}
+		return fmt.Sprintf("%d/%d %s", used, limit, unit)
+	}
+
+	buffers := toIEC(res.BufferBytes)
+	if res.MaxBufferBytes > 0 {
+		buffers += "/" + toIEC(res.MaxBufferBytes)
+	}
+
+	out := fmt.Sprintf("%d peers, %s, %s, %s buffers\n",
+		res.Peers,
+		withLimit(res.Goroutines, res.MaxGoroutines, "goroutines"),
+		withLimit(res.ICEAgents, res.MaxICEAgents, "ICE agents"),
+		buffers,
+	)
+	if res.DeniedActivations > 0 || res.DeniedICEAgents > 0 {
+		out += fmt.Sprintf("  WARNING: limits reached, %d activations denied, %d connections kept on relay\n", res.DeniedActivations, res.DeniedICEAgents)
+	}
+	return out
+}
+
+// parseConnState describes the connection to a control plane service
+func parseConnState(connected bool, url, connErr string, showURL bool) string {
+	if connected {
+		if showURL {
+			return fmt.Sprintf("Connected to %s", url)
+		}
+		return "Connected"
+	}
+	if connErr != "" {
+		return fmt.Sprintf("Disconnected, reason: %s", connErr)
+	}
+	return "Disconnected"
+}
+
+func parseRelayDetails(relays RelayStateOutput) string {
+	var relaysString string
+	for _, relay := range relays.Details {
+		available := "Available"
+		reason := ""
+
+		if !relay.Available {
+			if relay.Error == probeRelay.ErrCheckInProgress.Error() {
+				available = "Checking..."
+			} else {
+				available = "Unavailable"
+				reason = fmt.Sprintf(", reason: %s", relay.Error)
+			}
+		}
+
+		relaysString += fmt.Sprintf("\n  [%s] is %s%s", relay.URI, available, reason)
+	}
+	return relaysString
+}
+
+// ParseControlPlaneSummary describes the health of the management, signal, relay and flow connections
+func ParseControlPlaneSummary(overview OutputOverview) string {
+	management := overview.ManagementState
+	signal := overview.SignalState
+
+	signalString := parseEndpointState(signal.Connected, signal.URL, signal.Error)
+	if signal.Latency > 0 {
+		signalString += fmt.Sprintf("\n  Message latency: %s", signal.Latency.Round(time.Millisecond))
+	}
+	signalString += fmt.Sprintf("\n  Reconnects: %d", signal.Reconnects)
+	if signal.LastDisconnect != nil {
+		signalString += fmt.Sprintf("\n  Last disconnect: %s", timeAgo(*signal.LastDisconnect))
+		if signal.LastDisconnectReason != "" {
+			signalString += fmt.Sprintf(", reason: %s", signal.LastDisconnectReason)
+		}
+	}
+
+	relaysString := fmt.Sprintf("%d/%d Available", overview.Relays.Available, overview.Relays.Total)
+	relaysString += parseRelayDetails(overview.Relays)
+
+	flowString := "Disabled"
+	if flow := overview.FlowState; flow != nil {
+		flowString = parseEndpointState(flow.Connected, flow.URL, flow.Error)
+	}
+
+	return fmt.Sprintf(
+		"Management: %s\n"+
+			"Signal: %s\n"+
+			"Relays: %s\n"+
+			"Flow: %s\n",
+		parseEndpointState(management.Connected, management.URL, management.Error),
+		signalString,
+		relaysString,
+		flowString,
+	)
+}
+
+// parseEndpointState describes the connection to a control plane service including its address
+func parseEndpointState(connected bool, url, connErr string) string {
+	if connected {
+		return fmt.Sprintf("Connected to %s", url)
+	}
+	if connErr != "" {
+		return fmt.Sprintf("Disconnected from %s, reason: %s", url, connErr)
+	}
+	return fmt.Sprintf("Disconnected from %s", url)
+}
+
+func mapFlowState(flow *proto.FlowState) *FlowStateOutput {
+	if flow.GetURL() == "" {
+		return nil
+	}
+	return &FlowStateOutput{
+		URL:       flow.GetURL(),
+		Connected: flow.GetConnected(),
+		Error:     flow.GetError(),
+	}
+}
+
+func mapFirewallBackend(backend *proto.FirewallBackendState) *FirewallBackendOutput {
+	if backend.GetBackend() == "" {
+		return nil
+	}
+	return &FirewallBackendOutput{
+		Backend: backend.GetBackend(),
+		Native:  backend.GetNative(),
+		Reason:  backend.GetReason(),
+	}
+}
+
+func mapDNSListener(listener *proto.DNSListenerState) *DNSListenerOutput {
+	if len(listener.GetAddresses()) == 0 && len(listener.GetFallbacks()) == 0 {
+		return nil
+	}
+	return &DNSListenerOutput{
+		Addresses: listener.GetAddresses(),
+		Fallbacks: listener.GetFallbacks(),
+	}
+}
+
+func mapPeerResources(res *proto.PeerResourceState) *PeerResourcesOutput {
+	if res == nil {
+		return nil
+	}
+	return &PeerResourcesOutput{
+		Goroutines:        res.GetGoroutines(),
+		ICEAgents:         res.GetIceAgents(),
+		BufferBytes:       res.GetBufferBytes(),
+		MaxGoroutines:     res.GetMaxGoroutines(),
+		MaxICEAgents:      res.GetMaxIceAgents(),
+		MaxBufferBytes:    res.GetMaxBufferBytes(),
+		Peers:             int(res.GetPeers()),
+		DeniedActivations: res.GetDeniedActivations(),
+		DeniedICEAgents:   res.GetDeniedIceAgents(),
+	}
+}
+
+func mapFirewallSets(sets []*proto.FirewallSetState) []FirewallSetOutput {
+	var output []FirewallSetOutput
+	for _, set := range sets {
+		output = append(output, FirewallSetOutput{
+			Name:     set.GetName(),
+			Elements: int(set.GetElements()),
+		})
+	}
+	return output
+}
+
+func mapNetworkTransfers(transfers []*proto.NetworkTransfer) []NetworkTransferOutput {
+	var output []NetworkTransferOutput
+	for _, transfer := range transfers {
+		output = append(output, NetworkTransferOutput{
+			Network:          transfer.GetNetwork(),
+			TransferReceived: transfer.GetBytesRx(),
+			TransferSent:     transfer.GetBytesTx(),
+		})
+	}
+	return output
+}
+
+// parseNetworkTransfers lists the transfer per routed network, one network per line with the given indent
+func parseNetworkTransfers(transfers []NetworkTransferOutput, indent string) string {
+	var output string
+	for _, transfer := range transfers {
+		output += fmt.Sprintf("%s%s: %s/%s\n", indent, transfer.Network, toIEC(transfer.TransferReceived), toIEC(transfer.TransferSent))
+	}
+	return output
+}
+
 func ParseToFullDetailSummary(overview OutputOverview) string {
 	parsedPeersString := parsePeers(overview.Peers, overview.RosenpassEnabled, overview.RosenpassPermissive)
 	parsedEventsString := parseEvents(overview.Events)
@@ -599,9 +916,26 @@ func parsePeers(peers PeersStateOutput, rosenpassEnabled, rosenpassPermissive bo
 			networks = strings.Join(peerState.Networks, ", ")
 		}
 
+		allowedIPs := "-"
+		if len(peerState.AllowedIPs) > 0 {
+			allowedIPs = strings.Join(peerState.AllowedIPs, ", ")
+		}
+
+		status := peerState.Status
+		if peerState.Maintenance {
+			status += " (maintenance)"
+		}
+		switch peerState.ExpectedStatus {
+		case expectedStatusOffline:
+			status += " (peer is offline)"
+		case expectedStatusBlocked:
+			status += fmt.Sprintf(" (blocked by management: %s)", peerState.OfflineReason)
+		}
+
 		peerString := fmt.Sprintf(
 			"\n %s:\n"+
 				"  NetBird IP: %s\n"+
+				"  Allowed IPs: %s\n"+
 				"  Public key: %s\n"+
 				"  Status: %s\n"+
 				"  -- detail --\n"+
@@ -612,13 +946,16 @@ func parsePeers(peers PeersStateOutput, rosenpassEnabled, rosenpassPermissive bo
 				"  Last connection update: %s\n"+
 				"  Last WireGuard handshake: %s\n"+
 				"  Transfer status (received/sent) %s/%s\n"+
+				"  Total transfer (received/sent) %s/%s\n"+
+				"  Last connected: %s\n"+
 				"  Quantum resistance: %s\n"+
 				"  Networks: %s\n"+
 				"  Latency: %s\n",
 			domain.Domain(peerState.FQDN).SafeString(),
 			peerState.IP,
+			allowedIPs,
 			peerState.PubKey,
-			peerState.Status,
+			status,
 			peerState.ConnType,
 			localICE,
 			remoteICE,
@@ -629,16 +966,64 @@ func parsePeers(peers PeersStateOutput, rosenpassEnabled, rosenpassPermissive bo
 			timeAgo(peerState.LastWireguardHandshake),
 			toIEC(peerState.TransferReceived),
 			toIEC(peerState.TransferSent),
+			toIEC(peerState.TotalTransferReceived),
+			toIEC(peerState.TotalTransferSent),
+			timeAgo(peerState.LastConnected),
 			rosenpassEnabledStatus,
 			networks,
 			peerState.Latency.String(),
 		)
+		if len(peerState.NetworkTransfers) > 0 {
+			peerString += "  Network transfer (received/sent):\n" + parseNetworkTransfers(peerState.NetworkTransfers, "    ")
+		}
+		if probe := peerState.TunnelProbe; probe != nil {
+			peerString += "  Tunnel latency: " + parsePeerProbe(*probe) + "\n"
+		}
 
 		peersString += peerString
 	}
 	return peersString
 }
 
+// parsePeerProbe describes the round trip times and loss of the echo probes to a peer
+func parsePeerProbe(probe PeerProbeOutput) string {
+	if probe.Error != "" {
+		return "not probed (" + probe.Error + ")"
+	}
+	loss := 0.0
+	if probe.Sent > 0 {
+		loss = float64(probe.Sent-probe.Received) / float64(probe.Sent) * 100
+	}
+	if probe.Received == 0 {
+		return fmt.Sprintf("no reply, %d probes lost", probe.Sent)
+	}
+	return fmt.Sprintf("%s avg (min/max %s/%s), %.0f%% loss",
+		probe.RTTAvg.Round(100*time.Microsecond), probe.RTTMin.Round(100*time.Microsecond), probe.RTTMax.Round(100*time.Microsecond), loss)
+}
+
+// AttachPeerProbes adds the results of the echo probes to the peers of the overview
+func AttachPeerProbes(overview *OutputOverview, probes []*proto.PeerProbe) {
+	byKey := make(map[string]*proto.PeerProbe, len(probes))
+	for _, probe := range probes {
+		byKey[probe.GetPubKey()] = probe
+	}
+
+	for i, details := range overview.Peers.Details {
+		probe, ok := byKey[details.PubKey]
+		if !ok {
+			continue
+		}
+		overview.Peers.Details[i].TunnelProbe = &PeerProbeOutput{
+			Sent:     int(probe.GetSent()),
+			Received: int(probe.GetReceived()),
+			RTTMin:   probe.GetRttMin().AsDuration(),
+			RTTAvg:   probe.GetRttAvg().AsDuration(),
+			RTTMax:   probe.GetRttMax().AsDuration(),
+			Error:    probe.GetError(),
+		}
+	}
+}
+
 func skipDetailByFilters(peerState *proto.PeerState, peerStatus string, statusFilter string, prefixNamesFilter []string, prefixNamesFilterMap map[string]struct{}, ipsFilter map[string]struct{}, connectionTypeFilter, connType string) bool {
 	statusEval := false
 	ipEval := false
@@ -780,6 +1165,14 @@ func anonymizePeerDetail(a *anonymize.Anonymizer, peer *PeerStateDetailOutput) {
 	for i, route := range peer.Networks {
 		peer.Networks[i] = a.AnonymizeRoute(route)
 	}
+
+	for i, prefix := range peer.AllowedIPs {
+		peer.AllowedIPs[i] = a.AnonymizeRoute(prefix)
+	}
+
+	for i, transfer := range peer.NetworkTransfers {
+		peer.NetworkTransfers[i].Network = a.AnonymizeRoute(transfer.Network)
+	}
 }
 
 func anonymizeOverview(a *anonymize.Anonymizer, overview *OutputOverview) {
@@ -793,8 +1186,22 @@ func anonymizeOverview(a *anonymize.Anonymizer, overview *OutputOverview) {
 	overview.ManagementState.Error = a.AnonymizeString(overview.ManagementState.Error)
 	overview.SignalState.URL = a.AnonymizeURI(overview.SignalState.URL)
 	overview.SignalState.Error = a.AnonymizeString(overview.SignalState.Error)
+	overview.SignalState.LastDisconnectReason = a.AnonymizeString(overview.SignalState.LastDisconnectReason)
+	if overview.FlowState != nil {
+		overview.FlowState.URL = a.AnonymizeURI(overview.FlowState.URL)
+		overview.FlowState.Error = a.AnonymizeString(overview.FlowState.Error)
+	}
 
 	overview.IP = a.AnonymizeIPString(overview.IP)
+	overview.NetstackFallback = a.AnonymizeString(overview.NetstackFallback)
+	if overview.DNSListener != nil {
+		for i, addr := range overview.DNSListener.Addresses {
+			overview.DNSListener.Addresses[i] = a.AnonymizeString(addr)
+		}
+		for i, fallback := range overview.DNSListener.Fallbacks {
+			overview.DNSListener.Fallbacks[i] = a.AnonymizeString(fallback)
+		}
+	}
 	for i, detail := range overview.Relays.Details {
 		detail.URI = a.AnonymizeURI(detail.URI)
 		detail.Error = a.AnonymizeString(detail.Error)
@@ -817,6 +1224,10 @@ func anonymizeOverview(a *anonymize.Anonymizer, overview *OutputOverview) {
 		overview.Networks[i] = a.AnonymizeRoute(route)
 	}
 
+	for i, transfer := range overview.NetworkTransfers {
+		overview.NetworkTransfers[i].Network = a.AnonymizeRoute(transfer.Network)
+	}
+
 	overview.FQDN = a.AnonymizeDomain(overview.FQDN)
 
 	for i, event := range overview.Events {
@@ -44,10 +44,14 @@ var resp = &proto.StatusResponse{
 				LastWireguardHandshake:     timestamppb.New(time.Date(2001, time.Month(1), 1, 1, 1, 2, 0, time.UTC)),
 				BytesRx:                    200,
 				BytesTx:                    100,
+				TotalBytesRx:               4096,
+				TotalBytesTx:               2048,
+				LastConnected:              timestamppb.New(time.Date(2001, time.Month(1), 1, 1, 1, 2, 0, time.UTC)),
 				Networks: []string{
 					"10.1.0.0/24",
 				},
-				Latency: durationpb.New(time.Duration(10000000)),
+				Latency:    durationpb.New(time.Duration(10000000)),
+				AllowedIps: []string{"192.168.178.101/32"},
 			},
 			{
 				IP:                         "192.168.178.102",
@@ -146,10 +150,15 @@ var overview = OutputOverview{
 				LastWireguardHandshake: time.Date(2001, 1, 1, 1, 1, 2, 0, time.UTC),
 				TransferReceived:       200,
 				TransferSent:           100,
+				TotalTransferReceived:  4096,
+				TotalTransferSent:      2048,
+				LastConnected:          time.Date(2001, 1, 1, 1, 1, 2, 0, time.UTC),
 				Networks: []string{
 					"10.1.0.0/24",
 				},
-				Latency: time.Duration(10000000),
+				Latency:        time.Duration(10000000),
+				AllowedIPs:     []string{"192.168.178.101/32"},
+				ExpectedStatus: "online",
 			},
 			{
 				IP:               "192.168.178.102",
@@ -170,6 +179,7 @@ var overview = OutputOverview{
 				TransferReceived:       2000,
 				TransferSent:           1000,
 				Latency:                time.Duration(10000000),
+				ExpectedStatus:         "online",
 			},
 		},
 	},
@@ -296,11 +306,19 @@ func TestParsingToJSON(t *testing.T) {
                 "lastWireguardHandshake": "2001-01-01T01:01:02Z",
                 "transferReceived": 200,
                 "transferSent": 100,
+                "totalTransferReceived": 4096,
+                "totalTransferSent": 2048,
+                "lastConnected": "2001-01-01T01:01:02Z",
 				"latency": 10000000,
                 "quantumResistance": false,
                 "networks": [
                   "10.1.0.0/24"
-                ]
+                ],
+                "maintenance": false,
+                "allowedIps": [
+                  "192.168.178.101/32"
+                ],
+                "expectedStatus": "online"
               },
               {
                 "fqdn": "peer-2.awesome-domain.com",
@@ -321,9 +339,15 @@ func TestParsingToJSON(t *testing.T) {
                 "lastWireguardHandshake": "2002-02-02T02:02:03Z",
                 "transferReceived": 2000,
                 "transferSent": 1000,
+                "totalTransferReceived": 0,
+                "totalTransferSent": 0,
+                "lastConnected": "0001-01-01T00:00:00Z",
 				"latency": 10000000,
                 "quantumResistance": false,
-                "networks": null
+                "networks": null,
+                "maintenance": false,
+                "allowedIps": null,
+                "expectedStatus": "online"
               }
             ]
           },
@@ -393,7 +417,8 @@ func TestParsingToJSON(t *testing.T) {
 		  "sshServer":{
 		    "enabled":false,
 			"sessions":[]
-		  }
+		  },
+          "maintenanceEnabled": false
         }`
 	// @formatter:on
 
@@ -427,10 +452,17 @@ func TestParsingToYAML(t *testing.T) {
           lastWireguardHandshake: 2001-01-01T01:01:02Z
           transferReceived: 200
           transferSent: 100
+          totalTransferReceived: 4096
+          totalTransferSent: 2048
+          lastConnected: 2001-01-01T01:01:02Z
           latency: 10ms
           quantumResistance: false
           networks:
             - 10.1.0.0/24
+          maintenance: false
+          allowedIps:
+            - 192.168.178.101/32
+          expectedStatus: online
         - fqdn: peer-2.awesome-domain.com
           netbirdIp: 192.168.178.102
           publicKey: Pubkey2
@@ -447,9 +479,15 @@ func TestParsingToYAML(t *testing.T) {
           lastWireguardHandshake: 2002-02-02T02:02:03Z
           transferReceived: 2000
           transferSent: 1000
+          totalTransferReceived: 0
+          totalTransferSent: 0
+          lastConnected: 0001-01-01T00:00:00Z
           latency: 10ms
           quantumResistance: false
           networks: []
+          maintenance: false
+          allowedIps: []
+          expectedStatus: online
 cliVersion: development
 daemonVersion: 0.14.1
 management:
@@ -499,6 +537,7 @@ profileName: ""
 sshServer:
     enabled: false
     sessions: []
+maintenanceEnabled: false
 `
 
 	assert.Equal(t, expectedYAML, yaml)
@@ -517,6 +556,7 @@ func TestParsingToDetail(t *testing.T) {
 		`Peers detail:
  peer-1.awesome-domain.com:
   NetBird IP: 192.168.178.101
+  Allowed IPs: 192.168.178.101/32
   Public key: Pubkey1
   Status: Connected
   -- detail --
@@ -527,12 +567,15 @@ func TestParsingToDetail(t *testing.T) {
   Last connection update: %s
   Last WireGuard handshake: %s
   Transfer status (received/sent) 200 B/100 B
+  Total transfer (received/sent) 4.0 KiB/2.0 KiB
+  Last connected: %s
   Quantum resistance: false
   Networks: 10.1.0.0/24
   Latency: 10ms
 
  peer-2.awesome-domain.com:
   NetBird IP: 192.168.178.102
+  Allowed IPs: -
   Public key: Pubkey2
   Status: Connected
   -- detail --
@@ -543,6 +586,8 @@ func TestParsingToDetail(t *testing.T) {
   Last connection update: %s
   Last WireGuard handshake: %s
   Transfer status (received/sent) 2.0 KiB/1000 B
+  Total transfer (received/sent) 0 B/0 B
+  Last connected: -
   Quantum resistance: false
   Networks: -
   Latency: 10ms
@@ -569,7 +614,7 @@ SSH Server: Disabled
 Networks: 10.10.0.0/24
 Forwarding rules: 0
 Peers count: 2/2 Connected
-`, lastConnectionUpdate1, lastHandshake1, lastConnectionUpdate2, lastHandshake2, runtime.GOOS, runtime.GOARCH, overview.CliVersion)
+`, lastConnectionUpdate1, lastHandshake1, lastHandshake1, lastConnectionUpdate2, lastHandshake2, runtime.GOOS, runtime.GOARCH, overview.CliVersion)
 
 	assert.Equal(t, expectedDetail, detail)
 }
@@ -599,6 +644,35 @@ Peers count: 2/2 Connected
 	assert.Equal(t, expectedString, shortVersion)
 }
 
+func TestParsingToControlPlane(t *testing.T) {
+	controlPlane := overview
+	controlPlane.SignalState.Reconnects = 2
+	controlPlane.SignalState.Latency = 23400 * time.Microsecond
+	lastDisconnect := time.Now().Add(-5 * time.Minute)
+	controlPlane.SignalState.LastDisconnect = &lastDisconnect
+	controlPlane.SignalState.LastDisconnectReason = "stream closed"
+	controlPlane.FlowState = &FlowStateOutput{
+		URL:   "https://my-awesome-flow.com:443",
+		Error: "unavailable",
+	}
+
+	expectedString := `Management: Connected to my-awesome-management.com:443
+Signal: Connected to my-awesome-signal.com:443
+  Message latency: 23ms
+  Reconnects: 2
+  Last disconnect: 5 minutes ago, reason: stream closed
+Relays: 1/2 Available
+  [stun:my-awesome-stun.com:3478] is Available
+  [turns:my-awesome-turn.com:443?transport=tcp] is Unavailable, reason: context: deadline exceeded
+Flow: Disconnected from https://my-awesome-flow.com:443, reason: unavailable
+`
+
+	assert.Equal(t, expectedString, ParseControlPlaneSummary(controlPlane))
+
+	controlPlane.FlowState = nil
+	assert.Contains(t, ParseControlPlaneSummary(controlPlane), "Flow: Disabled\n")
+}
+
 func TestTimeAgo(t *testing.T) {
 	now := time.Now()
 
@@ -626,3 +700,59 @@ func TestTimeAgo(t *testing.T) {
 		})
 	}
 }
+
+func TestExpectedPeerStatus(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    *proto.PeerState
+		expected string
+	}{
+		{"Online", &proto.PeerState{}, "online"},
+		{"Offline", &proto.PeerState{Offline: true}, "offline"},
+		{"Blocked", &proto.PeerState{Offline: true, OfflineReason: "posture check failed"}, "blocked"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, expectedPeerStatus(tc.input))
+		})
+	}
+}
+
+func TestMapNetworkTransfers(t *testing.T) {
+	transfers := mapNetworkTransfers([]*proto.NetworkTransfer{
+		{Network: "10.0.0.0/16", BytesRx: 1024, BytesTx: 2048},
+		{Network: "192.168.1.0/24", BytesRx: 10, BytesTx: 0},
+	})
+	assert.Equal(t, []NetworkTransferOutput{
+		{Network: "10.0.0.0/16", TransferReceived: 1024, TransferSent: 2048},
+		{Network: "192.168.1.0/24", TransferReceived: 10},
+	}, transfers)
+
+	expected := "  10.0.0.0/16: 1.0 KiB/2.0 KiB\n" +
+		"  192.168.1.0/24: 10 B/0 B\n"
+	assert.Equal(t, expected, parseNetworkTransfers(transfers, "  "))
+}
+
+func TestAttachPeerProbes(t *testing.T) {
+	overview := OutputOverview{Peers: PeersStateOutput{Details: []PeerStateDetailOutput{
+		{PubKey: "key-a"}, {PubKey: "key-b"}, {PubKey: "key-c"},
+	}}}
+	AttachPeerProbes(&overview, []*proto.PeerProbe{
+		{
+			PubKey: "key-a", Sent: 4, Received: 3,
+			RttMin: durationpb.New(10 * time.Millisecond),
+			RttAvg: durationpb.New(12 * time.Millisecond),
+			RttMax: durationpb.New(15 * time.Millisecond),
+		},
+		{PubKey: "key-b", Error: "peer is not connected"},
+	})
+
+	require.NotNil(t, overview.Peers.Details[0].TunnelProbe)
+	assert.Equal(t, "12ms avg (min/max 10ms/15ms), 25% loss", parsePeerProbe(*overview.Peers.Details[0].TunnelProbe))
+	require.NotNil(t, overview.Peers.Details[1].TunnelProbe)
+	assert.Equal(t, "not probed (peer is not connected)", parsePeerProbe(*overview.Peers.Details[1].TunnelProbe))
+	assert.Nil(t, overview.Peers.Details[2].TunnelProbe)
+
+	assert.Equal(t, "no reply, 3 probes lost", parsePeerProbe(PeerProbeOutput{Sent: 3}))
+}
@@ -78,6 +78,11 @@ type Info struct {
 	EnableSSHLocalPortForwarding  bool
 	EnableSSHRemotePortForwarding bool
 	DisableSSHAuth                bool
+
+	Roles Roles
+
+	// Inventory is only set if the inventory reporting is enabled
+	Inventory *Inventory
 }
 
 func (i *Info) SetFlags(
@@ -0,0 +1,180 @@
+package system
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultInventoryInterval is how long a collected inventory is reused before it is collected again
+	DefaultInventoryInterval = 12 * time.Hour
+
+	// maxInventoryPackages bounds the size of the inventory in the meta sent to management
+	maxInventoryPackages = 2000
+	maxInventoryFieldLen = 128
+)
+
+// DiskEncryption is the encryption status of the system disk
+type DiskEncryption string
+
+const (
+	DiskEncryptionUnknown DiskEncryption = ""
+	DiskEncrypted         DiskEncryption = "encrypted"
+	DiskUnencrypted       DiskEncryption = "unencrypted"
+)
+
+// Package is an installed software package
+type Package struct {
+	Name    string
+	Version string
+}
+
+// Inventory is the software inventory of the device, reported to management for the posture checks
+type Inventory struct {
+	// Packages are the installed packages sorted by name, at most maxInventoryPackages
+	Packages []Package
+	// PackagesTruncated is set if more packages are installed than reported
+	PackagesTruncated bool
+	// PatchLevel is the patch level of the operating system, e.g. the latest hotfix on Windows, the build on macOS or
+	// the running kernel release on Linux
+	PatchLevel string
+	// LastUpdate is when the operating system or its packages were last updated, zero if unknown
+	LastUpdate     time.Time
+	DiskEncryption DiskEncryption
+	CollectedAt    time.Time
+}
+
+// InventoryCollector collects the inventory of the device and caches it for the interval, collecting the package
+// list may take a while
+type InventoryCollector struct {
+	interval time.Duration
+	collect  func(ctx context.Context) Inventory
+
+	mu     sync.Mutex
+	cached *Inventory
+}
+
+// NewInventoryCollector creates a collector that reuses the inventory for the interval, zero means
+// DefaultInventoryInterval
+func NewInventoryCollector(interval time.Duration) *InventoryCollector {
+	if interval <= 0 {
+		interval = DefaultInventoryInterval
+	}
+	return &InventoryCollector{
+		interval: interval,
+		collect:  collectInventory,
+	}
+}
+
+// Interval returns how long the inventory is reused
+func (c *InventoryCollector) Interval() time.Duration {
+	return c.interval
+}
+
+// Get returns the cached inventory, it is collected if the cache is empty or older than the interval
+func (c *InventoryCollector) Get(ctx context.Context) *Inventory {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cached != nil && time.Since(c.cached.CollectedAt) < c.interval {
+		return c.cached
+	}
+
+	inventory := c.collect(ctx)
+	inventory.CollectedAt = time.Now()
+	limitInventory(&inventory)
+	c.cached = &inventory
+	return c.cached
+}
+
+// Cached returns the cached inventory without collecting it, nil if it was not collected yet
+func (c *InventoryCollector) Cached() *Inventory {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cached
+}
+
+// Refresh collects the inventory again and reports whether it changed since the last collection
+func (c *InventoryCollector) Refresh(ctx context.Context) bool {
+	if c == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	previous := c.cached
+	c.cached = nil
+	c.mu.Unlock()
+
+	current := c.Get(ctx)
+	return previous == nil || !previous.equal(current)
+}
+
+// equal compares the inventories without the collection time
+func (i *Inventory) equal(other *Inventory) bool {
+	return slices.Equal(i.Packages, other.Packages) &&
+		i.PackagesTruncated == other.PackagesTruncated &&
+		i.PatchLevel == other.PatchLevel &&
+		i.LastUpdate.Equal(other.LastUpdate) &&
+		i.DiskEncryption == other.DiskEncryption
+}
+
+// limitInventory sorts and deduplicates the packages and bounds the size of the inventory
+func limitInventory(inventory *Inventory) {
+	for i, pkg := range inventory.Packages {
+		inventory.Packages[i] = Package{
+			Name:    truncateField(pkg.Name),
+			Version: truncateField(pkg.Version),
+		}
+	}
+	inventory.Packages = slices.DeleteFunc(inventory.Packages, func(pkg Package) bool {
+		return pkg.Name == ""
+	})
+
+	slices.SortFunc(inventory.Packages, func(a, b Package) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Version, b.Version)
+	})
+	inventory.Packages = slices.Compact(inventory.Packages)
+
+	if len(inventory.Packages) > maxInventoryPackages {
+		inventory.Packages = inventory.Packages[:maxInventoryPackages]
+		inventory.PackagesTruncated = true
+	}
+	inventory.PatchLevel = truncateField(inventory.PatchLevel)
+}
+
+// truncateField bounds the length of a field, the result stays valid UTF-8 as protobuf requires
+func truncateField(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > maxInventoryFieldLen {
+		s = s[:maxInventoryFieldLen]
+	}
+	return strings.ToValidUTF8(s, "")
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,84 @@
+//go:build !ios
+
+package system
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const installHistoryFile = "/Library/Receipts/InstallHistory.plist"
+
+var (
+	plistBundleName    = regexp.MustCompile(`<key>CFBundleName</key>\s*<string>([^<]*)</string>`)
+	plistBundleVersion = regexp.MustCompile(`<key>CFBundleShortVersionString</key>\s*<string>([^<]*)</string>`)
+)
+
+func collectInventory(ctx context.Context) Inventory {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	inventory := Inventory{
+		Packages:   readApplications("/Applications"),
+		LastUpdate: modTime(installHistoryFile),
+	}
+
+	if out, err := exec.CommandContext(ctx, "sw_vers", "-buildVersion").Output(); err != nil {
+		log.Debugf("failed to read the macOS build: %v", err)
+	} else {
+		inventory.PatchLevel = strings.TrimSpace(string(out))
+	}
+
+	inventory.DiskEncryption = fileVaultStatus(ctx)
+	return inventory
+}
+
+// readApplications lists the application bundles of the directory. Bundles with a binary Info.plist are listed
+// without a version.
+func readApplications(dir string) []Package {
+	bundles, err := filepath.Glob(filepath.Join(dir, "*.app"))
+	if err != nil {
+		return nil
+	}
+
+	packages := make([]Package, 0, len(bundles))
+	for _, bundle := range bundles {
+		pkg := Package{Name: strings.TrimSuffix(filepath.Base(bundle), ".app")}
+
+		plist, err := os.ReadFile(filepath.Join(bundle, "Contents", "Info.plist"))
+		if err == nil {
+			if m := plistBundleName.FindSubmatch(plist); m != nil && len(m[1]) > 0 {
+				pkg.Name = string(m[1])
+			}
+			if m := plistBundleVersion.FindSubmatch(plist); m != nil {
+				pkg.Version = string(m[1])
+			}
+		}
+		packages = append(packages, pkg)
+	}
+	return packages
+}
+
+func fileVaultStatus(ctx context.Context) DiskEncryption {
+	out, err := exec.CommandContext(ctx, "fdesetup", "status").Output()
+	if err != nil {
+		log.Debugf("failed to read the FileVault status: %v", err)
+		return DiskEncryptionUnknown
+	}
+
+	switch {
+	case strings.Contains(string(out), "FileVault is On"):
+		return DiskEncrypted
+	case strings.Contains(string(out), "FileVault is Off"):
+		return DiskUnencrypted
+	default:
+		return DiskEncryptionUnknown
+	}
+}
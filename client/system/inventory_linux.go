@@ -0,0 +1,168 @@
+//go:build !android
+
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	dpkgStatusFile   = "/var/lib/dpkg/status"
+	apkInstalledFile = "/lib/apk/db/installed"
+	rpmDBDir         = "/var/lib/rpm"
+)
+
+func collectInventory(ctx context.Context) Inventory {
+	var inventory Inventory
+
+	switch {
+	case fileExists(dpkgStatusFile):
+		inventory.Packages = readDpkgPackages(dpkgStatusFile)
+		inventory.LastUpdate = modTime(dpkgStatusFile)
+	case fileExists(apkInstalledFile):
+		inventory.Packages = readApkPackages(apkInstalledFile)
+		inventory.LastUpdate = modTime(apkInstalledFile)
+	case fileExists(rpmDBDir):
+		inventory.Packages = queryRpmPackages(ctx)
+		inventory.LastUpdate = modTime(rpmDBDir)
+	}
+
+	var uname unix.Utsname
+	if err := unix.Uname(&uname); err != nil {
+		log.Debugf("failed to read the kernel release: %v", err)
+	} else {
+		inventory.PatchLevel = unix.ByteSliceToString(uname.Release[:])
+	}
+
+	inventory.DiskEncryption = dmCryptStatus("/sys/block")
+	return inventory
+}
+
+// readDpkgPackages reads the installed packages from the dpkg status file
+func readDpkgPackages(path string) []Package {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Debugf("failed to open %s: %v", path, err)
+		return nil
+	}
+	defer file.Close()
+
+	var (
+		packages  []Package
+		pkg       Package
+		installed bool
+	)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if installed {
+				packages = append(packages, pkg)
+			}
+			pkg, installed = Package{}, false
+		case strings.HasPrefix(line, "Package: "):
+			pkg.Name = strings.TrimPrefix(line, "Package: ")
+		case strings.HasPrefix(line, "Version: "):
+			pkg.Version = strings.TrimPrefix(line, "Version: ")
+		case strings.HasPrefix(line, "Status: "):
+			installed = strings.HasSuffix(line, " installed")
+		}
+	}
+	if installed {
+		packages = append(packages, pkg)
+	}
+	if err := scanner.Err(); err != nil {
+		log.Debugf("failed to read %s: %v", path, err)
+	}
+	return packages
+}
+
+// readApkPackages reads the installed packages from the apk database
+func readApkPackages(path string) []Package {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Debugf("failed to open %s: %v", path, err)
+		return nil
+	}
+	defer file.Close()
+
+	var (
+		packages []Package
+		pkg      Package
+	)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if pkg.Name != "" {
+				packages = append(packages, pkg)
+			}
+			pkg = Package{}
+		case strings.HasPrefix(line, "P:"):
+			pkg.Name = strings.TrimPrefix(line, "P:")
+		case strings.HasPrefix(line, "V:"):
+			pkg.Version = strings.TrimPrefix(line, "V:")
+		}
+	}
+	if pkg.Name != "" {
+		packages = append(packages, pkg)
+	}
+	if err := scanner.Err(); err != nil {
+		log.Debugf("failed to read %s: %v", path, err)
+	}
+	return packages
+}
+
+// queryRpmPackages lists the installed packages with rpm, its database format differs between the distributions
+func queryRpmPackages(ctx context.Context) []Package {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "rpm", "-qa", "--queryformat", `%{NAME}\t%{VERSION}-%{RELEASE}\n`).Output()
+	if err != nil {
+		log.Debugf("failed to list the rpm packages: %v", err)
+		return nil
+	}
+
+	var packages []Package
+	for _, line := range bytes.Split(out, []byte("\n")) {
+		name, version, ok := strings.Cut(string(line), "\t")
+		if !ok {
+			continue
+		}
+		packages = append(packages, Package{Name: name, Version: version})
+	}
+	return packages
+}
+
+// dmCryptStatus reports the disk as encrypted if any of the block devices is a dm-crypt mapping
+func dmCryptStatus(sysBlock string) DiskEncryption {
+	uuids, err := filepath.Glob(filepath.Join(sysBlock, "dm-*", "dm", "uuid"))
+	if err != nil {
+		return DiskEncryptionUnknown
+	}
+
+	for _, path := range uuids {
+		uuid, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if strings.HasPrefix(string(uuid), "CRYPT-") {
+			return DiskEncrypted
+		}
+	}
+	return DiskUnencrypted
+}
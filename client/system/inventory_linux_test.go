@@ -0,0 +1,70 @@
+//go:build !android
+
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadDpkgPackages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status")
+	status := `Package: curl
+Status: install ok installed
+Version: 8.5.0-2ubuntu10
+
+Package: removed
+Status: deinstall ok config-files
+Version: 1.0
+
+Package: zsh
+Status: install ok installed
+Priority: optional
+Version: 5.9-6
+`
+	require.NoError(t, os.WriteFile(path, []byte(status), 0o600))
+
+	assert.Equal(t, []Package{
+		{Name: "curl", Version: "8.5.0-2ubuntu10"},
+		{Name: "zsh", Version: "5.9-6"},
+	}, readDpkgPackages(path))
+}
+
+func TestReadApkPackages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "installed")
+	installed := `C:Q1abc=
+P:musl
+V:1.2.5-r0
+A:x86_64
+
+P:busybox
+V:1.36.1-r29
+`
+	require.NoError(t, os.WriteFile(path, []byte(installed), 0o600))
+
+	assert.Equal(t, []Package{
+		{Name: "musl", Version: "1.2.5-r0"},
+		{Name: "busybox", Version: "1.36.1-r29"},
+	}, readApkPackages(path))
+}
+
+func TestDmCryptStatus(t *testing.T) {
+	sysBlock := t.TempDir()
+	assert.Equal(t, DiskUnencrypted, dmCryptStatus(sysBlock))
+
+	writeUUID := func(dev, uuid string) {
+		dir := filepath.Join(sysBlock, dev, "dm")
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "uuid"), []byte(uuid+"\n"), 0o600))
+	}
+
+	writeUUID("dm-0", "LVM-abc")
+	assert.Equal(t, DiskUnencrypted, dmCryptStatus(sysBlock))
+
+	writeUUID("dm-1", "CRYPT-LUKS2-abc-root")
+	assert.Equal(t, DiskEncrypted, dmCryptStatus(sysBlock))
+}
@@ -0,0 +1,10 @@
+//go:build android || ios || (!linux && !darwin && !windows)
+
+package system
+
+import "context"
+
+// collectInventory is not supported on this platform, the inventory only carries the collection time
+func collectInventory(context.Context) Inventory {
+	return Inventory{}
+}
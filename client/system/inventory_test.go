@@ -0,0 +1,77 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitInventory(t *testing.T) {
+	inventory := Inventory{
+		Packages: []Package{
+			{Name: "zsh", Version: "5.9"},
+			{Name: " curl ", Version: "8.5.0"},
+			{Name: "curl", Version: "8.5.0"},
+			{Name: "", Version: "1.0"},
+			{Name: strings.Repeat("a", maxInventoryFieldLen+10), Version: "1.0"},
+		},
+		PatchLevel: "6.8.0-45-generic\n",
+	}
+	limitInventory(&inventory)
+
+	assert.Equal(t, []Package{
+		{Name: strings.Repeat("a", maxInventoryFieldLen), Version: "1.0"},
+		{Name: "curl", Version: "8.5.0"},
+		{Name: "zsh", Version: "5.9"},
+	}, inventory.Packages)
+	assert.False(t, inventory.PackagesTruncated)
+	assert.Equal(t, "6.8.0-45-generic", inventory.PatchLevel)
+
+	inventory = Inventory{}
+	for i := 0; i < maxInventoryPackages+1; i++ {
+		inventory.Packages = append(inventory.Packages, Package{Name: fmt.Sprintf("pkg-%05d", i)})
+	}
+	limitInventory(&inventory)
+
+	assert.Len(t, inventory.Packages, maxInventoryPackages)
+	assert.True(t, inventory.PackagesTruncated)
+}
+
+func TestTruncateField_ValidUTF8(t *testing.T) {
+	field := truncateField(strings.Repeat("a", maxInventoryFieldLen-1) + "é")
+	assert.Equal(t, strings.Repeat("a", maxInventoryFieldLen-1), field)
+}
+
+func TestInventoryCollector(t *testing.T) {
+	var collected int
+	patchLevel := "KB5001"
+	collector := NewInventoryCollector(time.Hour)
+	collector.collect = func(context.Context) Inventory {
+		collected++
+		return Inventory{PatchLevel: patchLevel}
+	}
+
+	var nilCollector *InventoryCollector
+	assert.Nil(t, nilCollector.Get(t.Context()))
+	assert.Nil(t, collector.Cached())
+
+	first := collector.Get(t.Context())
+	require.NotNil(t, first)
+	assert.Equal(t, "KB5001", first.PatchLevel)
+	assert.False(t, first.CollectedAt.IsZero())
+	assert.Same(t, first, collector.Get(t.Context()), "the inventory is cached for the interval")
+	assert.Same(t, first, collector.Cached())
+	assert.Equal(t, 1, collected)
+
+	assert.False(t, collector.Refresh(t.Context()), "the inventory didn't change")
+	assert.Equal(t, 2, collected)
+
+	patchLevel = "KB5002"
+	assert.True(t, collector.Refresh(t.Context()))
+	assert.Equal(t, "KB5002", collector.Cached().PatchLevel)
+}
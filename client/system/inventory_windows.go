@@ -0,0 +1,123 @@
+package system
+
+import (
+	"context"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/yusufpapurcu/wmi"
+	"golang.org/x/sys/windows/registry"
+)
+
+const bitLockerNamespace = `root\CIMV2\Security\MicrosoftVolumeEncryption`
+
+var uninstallKeys = []string{
+	`SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall`,
+	`SOFTWARE\WOW6432Node\Microsoft\Windows\CurrentVersion\Uninstall`,
+}
+
+type Win32_QuickFixEngineering struct {
+	HotFixID    string
+	InstalledOn string
+}
+
+type Win32_EncryptableVolume struct {
+	DriveLetter      string
+	ProtectionStatus uint32
+}
+
+func collectInventory(context.Context) Inventory {
+	var inventory Inventory
+	for _, key := range uninstallKeys {
+		inventory.Packages = append(inventory.Packages, readUninstallKey(key)...)
+	}
+	inventory.PatchLevel, inventory.LastUpdate = latestHotFix()
+	inventory.DiskEncryption = bitLockerStatus()
+	return inventory
+}
+
+// readUninstallKey lists the installed programs registered for the Programs and Features control panel
+func readUninstallKey(path string) []Package {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, path, registry.ENUMERATE_SUB_KEYS)
+	if err != nil {
+		log.Debugf("failed to open %s: %v", path, err)
+		return nil
+	}
+	defer k.Close()
+
+	names, err := k.ReadSubKeyNames(-1)
+	if err != nil {
+		log.Debugf("failed to read %s: %v", path, err)
+		return nil
+	}
+
+	packages := make([]Package, 0, len(names))
+	for _, name := range names {
+		sub, err := registry.OpenKey(k, name, registry.QUERY_VALUE)
+		if err != nil {
+			continue
+		}
+		displayName, _, _ := sub.GetStringValue("DisplayName")
+		displayVersion, _, _ := sub.GetStringValue("DisplayVersion")
+		_ = sub.Close()
+
+		if displayName != "" {
+			packages = append(packages, Package{Name: displayName, Version: displayVersion})
+		}
+	}
+	return packages
+}
+
+// latestHotFix returns the most recently installed update and when it was installed
+func latestHotFix() (string, time.Time) {
+	var dst []Win32_QuickFixEngineering
+	query := wmi.CreateQuery(&dst, "")
+	if err := wmi.Query(query, &dst); err != nil {
+		log.Debugf("failed to query the installed updates: %v", err)
+		return "", time.Time{}
+	}
+
+	var (
+		latestID string
+		latest   time.Time
+	)
+	for _, fix := range dst {
+		installedOn, err := time.Parse("1/2/2006", fix.InstalledOn)
+		if err != nil {
+			continue
+		}
+		if installedOn.After(latest) {
+			latestID, latest = fix.HotFixID, installedOn
+		}
+	}
+	return latestID, latest
+}
+
+// bitLockerStatus reports whether BitLocker protects the system drive
+func bitLockerStatus() DiskEncryption {
+	var dst []Win32_EncryptableVolume
+	query := wmi.CreateQuery(&dst, "")
+	if err := wmi.QueryNamespace(query, &dst, bitLockerNamespace); err != nil {
+		log.Debugf("failed to query the BitLocker status: %v", err)
+		return DiskEncryptionUnknown
+	}
+
+	systemDrive := os.Getenv("SystemDrive")
+	if systemDrive == "" {
+		systemDrive = "C:"
+	}
+	for _, volume := range dst {
+		if volume.DriveLetter != systemDrive {
+			continue
+		}
+		// 1 is protection on, 0 protection off and 2 unknown
+		switch volume.ProtectionStatus {
+		case 1:
+			return DiskEncrypted
+		case 0:
+			return DiskUnencrypted
+		}
+	}
+	return DiskEncryptionUnknown
+}
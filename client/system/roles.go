@@ -0,0 +1,64 @@
+package system
+
+import (
+	"os"
+	"strings"
+)
+
+// Role names reported in the local status
+const (
+	RoleRoutingPeer    = "routing-peer"
+	RoleExitNode       = "exit-node"
+	RoleIngressGateway = "ingress-gateway"
+	RoleEphemeralCI    = "ephemeral-ci"
+)
+
+// ciEnvVars are set by CI systems in the environment of their jobs
+var ciEnvVars = []string{
+	"GITHUB_ACTIONS",
+	"GITLAB_CI",
+	"BUILDKITE",
+	"CIRCLECI",
+	"JENKINS_URL",
+	"TF_BUILD",
+	"TEAMCITY_VERSION",
+	"BITBUCKET_BUILD_NUMBER",
+	"DRONE",
+	"WOODPECKER",
+}
+
+// Roles are the roles of the peer derived from its local state
+type Roles struct {
+	RoutingPeer    bool
+	ExitNode       bool
+	IngressGateway bool
+	EphemeralCI    bool
+}
+
+// Names returns the names of the roles the peer has
+func (r Roles) Names() []string {
+	var names []string
+	if r.RoutingPeer {
+		names = append(names, RoleRoutingPeer)
+	}
+	if r.ExitNode {
+		names = append(names, RoleExitNode)
+	}
+	if r.IngressGateway {
+		names = append(names, RoleIngressGateway)
+	}
+	if r.EphemeralCI {
+		names = append(names, RoleEphemeralCI)
+	}
+	return names
+}
+
+// IsCIRunner reports whether the process runs in a CI job
+func IsCIRunner() bool {
+	for _, name := range ciEnvVars {
+		if os.Getenv(name) != "" {
+			return true
+		}
+	}
+	return strings.EqualFold(os.Getenv("CI"), "true")
+}
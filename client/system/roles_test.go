@@ -0,0 +1,30 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoles_Names(t *testing.T) {
+	assert.Empty(t, Roles{}.Names())
+	assert.Equal(t, []string{RoleRoutingPeer, RoleExitNode}, Roles{RoutingPeer: true, ExitNode: true}.Names())
+	assert.Equal(t, []string{RoleIngressGateway, RoleEphemeralCI}, Roles{IngressGateway: true, EphemeralCI: true}.Names())
+}
+
+func TestIsCIRunner(t *testing.T) {
+	for _, name := range append(ciEnvVars, "CI") {
+		t.Setenv(name, "")
+	}
+	assert.False(t, IsCIRunner())
+
+	t.Setenv("CI", "false")
+	assert.False(t, IsCIRunner())
+
+	t.Setenv("CI", "true")
+	assert.True(t, IsCIRunner())
+
+	t.Setenv("CI", "")
+	t.Setenv("GITLAB_CI", "true")
+	assert.True(t, IsCIRunner())
+}
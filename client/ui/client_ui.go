@@ -1114,6 +1114,7 @@ func (s *serviceClient) onTrayReady() {
 			s.updateExitNodes()
 		}
 	})
+	s.eventManager.AddHandler(s.handleInboundPrompt)
 	s.eventManager.AddHandler(func(event *proto.SystemEvent) {
 		// todo use new Category
 		if windowAction, ok := event.Metadata["progress_window"]; ok {
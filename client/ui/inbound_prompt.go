@@ -0,0 +1,112 @@
+//go:build !(linux && 386)
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// inboundPromptDurations are the decisions offered by the prompt window
+var inboundPromptDurations = []struct {
+	label    string
+	duration time.Duration
+}{
+	{"1 hour", time.Hour},
+	{"8 hours", 8 * time.Hour},
+	{"24 hours", 24 * time.Hour},
+}
+
+// handleInboundPrompt shows a window to allow or deny the first inbound connection of a peer
+func (s *serviceClient) handleInboundPrompt(event *proto.SystemEvent) {
+	if event.Metadata[peer.EventTypeKey] != peer.EventInboundPrompt {
+		return
+	}
+
+	id := event.Metadata["prompt_id"]
+	if id == "" {
+		return
+	}
+
+	// the events are handled outside of the UI goroutine
+	fyne.Do(func() {
+		s.showInboundPrompt(id, event)
+	})
+}
+
+// showInboundPrompt builds and shows the prompt window, it must run on the UI goroutine
+func (s *serviceClient) showInboundPrompt(id string, event *proto.SystemEvent) {
+	w := s.app.NewWindow("NetBird Inbound Connection")
+	w.SetIcon(fyne.NewStaticResource("netbird.png", iconAbout))
+
+	label := widget.NewLabel(fmt.Sprintf("%s connects to port %s of this device.", event.Metadata["peer"], event.Metadata["target"]))
+	label.Wrapping = fyne.TextWrapWord
+
+	durationSelect := widget.NewSelect(nil, nil)
+	for _, d := range inboundPromptDurations {
+		durationSelect.Options = append(durationSelect.Options, d.label)
+	}
+	durationSelect.SetSelectedIndex(0)
+
+	respond := func(allow bool) {
+		duration := inboundPromptDurations[durationSelect.SelectedIndex()].duration
+		// the daemon call blocks, the window is updated back on the UI goroutine
+		go func() {
+			err := s.respondInboundPrompt(id, allow, duration)
+			fyne.Do(func() {
+				if err != nil {
+					log.Errorf("failed to respond to inbound prompt %s: %v", id, err)
+					label.SetText(fmt.Sprintf("Failed to respond: %v", err))
+					return
+				}
+				w.Close()
+			})
+		}()
+	}
+
+	allowBtn := widget.NewButtonWithIcon("Allow", theme.ConfirmIcon(), func() {
+		respond(true)
+	})
+	denyBtn := widget.NewButtonWithIcon("Deny", theme.CancelIcon(), func() {
+		respond(false)
+	})
+	denyBtn.Importance = widget.DangerImportance
+
+	w.SetContent(container.NewVBox(
+		label,
+		widget.NewLabel("The connections pass until you answer. Remember the decision for:"),
+		durationSelect,
+		container.NewGridWithColumns(2, allowBtn, denyBtn),
+	))
+	w.Resize(fyne.NewSize(420, 180))
+	w.Show()
+}
+
+func (s *serviceClient) respondInboundPrompt(id string, allow bool, duration time.Duration) error {
+	conn, err := s.getSrvClient(defaultFailTimeout)
+	if err != nil {
+		return fmt.Errorf("get client: %w", err)
+	}
+
+	_, err = conn.RespondInboundPrompt(s.ctx, &proto.RespondInboundPromptRequest{
+		Id:       id,
+		Allow:    allow,
+		Duration: durationpb.New(duration),
+	})
+	if err != nil {
+		return errors.New(status.Convert(err).Message())
+	}
+	return nil
+}
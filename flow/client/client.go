@@ -26,11 +26,20 @@ import (
 	"github.com/netbirdio/netbird/util/wsproxy"
 )
 
+// ConnStateNotifier is notified when the event stream to the flow receiver connects or disconnects
+type ConnStateNotifier interface {
+	MarkFlowDisconnected(error)
+	MarkFlowConnected()
+}
+
 type GRPCClient struct {
 	realClient proto.FlowServiceClient
 	clientConn *grpc.ClientConn
 	stream     proto.FlowService_EventsClient
 	streamMu   sync.Mutex
+
+	connStateCallback     ConnStateNotifier
+	connStateCallbackLock sync.RWMutex
 }
 
 func NewClient(addr, payload, signature string, interval time.Duration) (*GRPCClient, error) {
@@ -88,6 +97,13 @@ func (c *GRPCClient) Close() error {
 	return nil
 }
 
+// SetConnStateListener sets the ConnStateNotifier
+func (c *GRPCClient) SetConnStateListener(notifier ConnStateNotifier) {
+	c.connStateCallbackLock.Lock()
+	defer c.connStateCallbackLock.Unlock()
+	c.connStateCallback = notifier
+}
+
 func (c *GRPCClient) Receive(ctx context.Context, interval time.Duration, msgHandler func(msg *proto.FlowEventAck) error) error {
 	backOff := defaultBackoff(ctx, interval)
 	operation := func() error {
@@ -95,6 +111,7 @@ func (c *GRPCClient) Receive(ctx context.Context, interval time.Duration, msgHan
 			if s, ok := status.FromError(err); ok && s.Code() == codes.Canceled {
 				return fmt.Errorf("receive: %w: %w", err, context.Canceled)
 			}
+			c.notifyDisconnected(err)
 			log.Errorf("receive failed: %v", err)
 			return fmt.Errorf("receive: %w", err)
 		}
@@ -130,6 +147,7 @@ func (c *GRPCClient) establishStreamAndReceive(ctx context.Context, msgHandler f
 	c.streamMu.Lock()
 	c.stream = stream
 	c.streamMu.Unlock()
+	c.notifyConnected()
 
 	return c.receive(stream, msgHandler)
 }
@@ -193,3 +211,23 @@ func (c *GRPCClient) Send(event *proto.FlowEvent) error {
 
 	return nil
 }
+
+func (c *GRPCClient) notifyDisconnected(err error) {
+	c.connStateCallbackLock.RLock()
+	defer c.connStateCallbackLock.RUnlock()
+
+	if c.connStateCallback == nil {
+		return
+	}
+	c.connStateCallback.MarkFlowDisconnected(err)
+}
+
+func (c *GRPCClient) notifyConnected() {
+	c.connStateCallbackLock.RLock()
+	defer c.connStateCallbackLock.RUnlock()
+
+	if c.connStateCallback == nil {
+		return
+	}
+	c.connStateCallback.MarkFlowConnected()
+}
@@ -137,7 +137,12 @@ func ToSyncResponse(ctx context.Context, config *nbconfig.Config, httpConfig *nb
 	response.RemotePeersIsEmpty = len(remotePeers) == 0
 	response.NetworkMap.RemotePeersIsEmpty = response.RemotePeersIsEmpty
 
-	response.NetworkMap.OfflinePeers = appendRemotePeerConfig(nil, networkMap.OfflinePeers, dnsName)
+	// the network map only holds peers with an expired login as offline peers
+	offlinePeers := appendRemotePeerConfig(nil, networkMap.OfflinePeers, dnsName)
+	for _, offlinePeer := range offlinePeers {
+		offlinePeer.OfflineReason = proto.RemotePeerConfig_LOGIN_EXPIRED
+	}
+	response.NetworkMap.OfflinePeers = offlinePeers
 
 	firewallRules := toProtocolFirewallRules(networkMap.FirewallRules)
 	response.NetworkMap.FirewallRules = firewallRules
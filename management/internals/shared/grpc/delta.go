@@ -0,0 +1,50 @@
+package grpc
+
+import (
+	"slices"
+
+	"github.com/netbirdio/netbird/shared/management/networkmap"
+	"github.com/netbirdio/netbird/shared/management/proto"
+)
+
+// networkMapDeltas turns the network maps sent on a Sync stream into deltas against the previously sent map if
+// the peer supports them. It is used by the stream's goroutine only.
+type networkMapDeltas struct {
+	enabled bool
+	last    *proto.NetworkMap
+}
+
+func newNetworkMapDeltas(capabilities []proto.SyncRequest_Capability) *networkMapDeltas {
+	return &networkMapDeltas{enabled: slices.Contains(capabilities, proto.SyncRequest_NETWORK_MAP_DELTA)}
+}
+
+// encode returns the response to send. The first map of the stream and maps that can't be expressed as a delta
+// are sent in full. The deprecated top level peer fields are left out of deltas, peers that support deltas
+// don't read them.
+func (d *networkMapDeltas) encode(resp *proto.SyncResponse) *proto.SyncResponse {
+	if !d.enabled || resp.GetNetworkMap() == nil {
+		return resp
+	}
+
+	prev := d.last
+	d.last = resp.GetNetworkMap()
+
+	delta := networkmap.Delta(prev, resp.GetNetworkMap())
+	if delta == nil {
+		return resp
+	}
+
+	return &proto.SyncResponse{
+		NetbirdConfig: resp.GetNetbirdConfig(),
+		NetworkMap:    delta,
+		Checks:        resp.GetChecks(),
+		ResumeToken:   resp.GetResumeToken(),
+	}
+}
+
+// resume sets the network map the peer kept when its session was resumed as the base of the following deltas
+func (d *networkMapDeltas) resume(nm *proto.NetworkMap) {
+	if d.enabled {
+		d.last = nm
+	}
+}
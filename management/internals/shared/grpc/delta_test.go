@@ -0,0 +1,48 @@
+package grpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/shared/management/proto"
+)
+
+func TestNetworkMapDeltas(t *testing.T) {
+	first := &proto.SyncResponse{
+		RemotePeers: []*proto.RemotePeerConfig{{WgPubKey: "peer-a"}},
+		NetworkMap: &proto.NetworkMap{
+			Serial:      1,
+			RemotePeers: []*proto.RemotePeerConfig{{WgPubKey: "peer-a"}},
+		},
+	}
+	second := &proto.SyncResponse{
+		RemotePeers: []*proto.RemotePeerConfig{{WgPubKey: "peer-a"}, {WgPubKey: "peer-b"}},
+		NetworkMap: &proto.NetworkMap{
+			Serial:      2,
+			RemotePeers: []*proto.RemotePeerConfig{{WgPubKey: "peer-a"}, {WgPubKey: "peer-b"}},
+		},
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		deltas := newNetworkMapDeltas(nil)
+		assert.Same(t, first, deltas.encode(first))
+		assert.Same(t, second, deltas.encode(second))
+	})
+
+	t.Run("supported", func(t *testing.T) {
+		deltas := newNetworkMapDeltas([]proto.SyncRequest_Capability{proto.SyncRequest_NETWORK_MAP_DELTA})
+		assert.Same(t, first, deltas.encode(first), "the first map of the stream is sent in full")
+
+		config := &proto.SyncResponse{NetbirdConfig: &proto.NetbirdConfig{}}
+		assert.Same(t, config, deltas.encode(config), "updates without a map are sent as is")
+
+		resp := deltas.encode(second)
+		require.True(t, resp.GetNetworkMap().GetDelta())
+		assert.Equal(t, uint64(1), resp.GetNetworkMap().GetBaseSerial())
+		require.Len(t, resp.GetNetworkMap().GetRemotePeers(), 1)
+		assert.Equal(t, "peer-b", resp.GetNetworkMap().GetRemotePeers()[0].GetWgPubKey())
+		assert.Empty(t, resp.GetRemotePeers(), "deprecated peer fields are left out of deltas")
+	})
+}
@@ -0,0 +1,124 @@
+package grpc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+
+	"github.com/netbirdio/netbird/shared/management/networkmap"
+	"github.com/netbirdio/netbird/shared/management/proto"
+)
+
+const resumeTokenTTL = 12 * time.Hour
+
+// resumeTokens issues and validates the resume tokens of the Sync streams. A token binds the peer key to the digest
+// of the last network map sent to the peer. The tokens are signed with a key generated at startup, a restarted
+// management rejects the tokens it didn't issue and the peers receive the full network map.
+type resumeTokens struct {
+	key []byte
+	ttl time.Duration
+	now func() time.Time
+}
+
+func newResumeTokens() (*resumeTokens, error) {
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generate resume token key: %w", err)
+	}
+	return &resumeTokens{key: key, ttl: resumeTokenTTL, now: time.Now}, nil
+}
+
+// issue returns a token for the peer holding the network map with the digest. The token is the expiry, the digest
+// and the signature of both and the peer key.
+func (r *resumeTokens) issue(peerKey string, digest []byte) string {
+	//nolint:gosec
+	expiry := binary.BigEndian.AppendUint64(nil, uint64(r.now().Add(r.ttl).Unix()))
+	token := append(expiry, digest...)
+	token = append(token, r.sign(peerKey, expiry, digest)...)
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+// valid reports whether the token was issued to the peer for the network map with the digest and hasn't expired
+func (r *resumeTokens) valid(token, peerKey string, digest []byte) bool {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(data) != 8+2*sha256.Size {
+		return false
+	}
+	expiry, tokenDigest, signature := data[:8], data[8:8+sha256.Size], data[8+sha256.Size:]
+
+	if !hmac.Equal(signature, r.sign(peerKey, expiry, tokenDigest)) {
+		return false
+	}
+	//nolint:gosec
+	if r.now().Unix() > int64(binary.BigEndian.Uint64(expiry)) {
+		return false
+	}
+	return hmac.Equal(tokenDigest, digest)
+}
+
+func (r *resumeTokens) sign(peerKey string, expiry, digest []byte) []byte {
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(peerKey))
+	mac.Write(expiry)
+	mac.Write(digest)
+	return mac.Sum(nil)
+}
+
+// initialSyncResponse returns the first response of a Sync stream. If the resume token of the peer is valid for the
+// current network map, the map is left out and the peer keeps the map it holds. The response carries a new token.
+func (s *Server) initialSyncResponse(ctx context.Context, peerKey wgtypes.Key, resumeToken string, resp *proto.SyncResponse, deltas *networkMapDeltas) *proto.SyncResponse {
+	digest, err := networkmap.Digest(resp.GetNetworkMap())
+	if err != nil {
+		log.WithContext(ctx).Debugf("not issuing a resume token to peer %s: %v", peerKey.String(), err)
+		return deltas.encode(resp)
+	}
+	token := s.resumeTokens.issue(peerKey.String(), digest)
+
+	if resumeToken == "" || !s.resumeTokens.valid(resumeToken, peerKey.String(), digest) {
+		return deltas.encode(withResumeToken(resp, token))
+	}
+
+	log.WithContext(ctx).Debugf("resumed the Sync session of peer %s at network map serial %d", peerKey.String(), resp.GetNetworkMap().GetSerial())
+	deltas.resume(resp.GetNetworkMap())
+	return &proto.SyncResponse{
+		NetbirdConfig: resp.GetNetbirdConfig(),
+		Checks:        resp.GetChecks(),
+		ResumeToken:   token,
+		Resumed:       true,
+	}
+}
+
+// updateWithResumeToken returns the update with a resume token for its network map. Updates without a map are
+// returned as is, the peer's token for the previous map stays valid.
+func (s *Server) updateWithResumeToken(ctx context.Context, peerKey wgtypes.Key, resp *proto.SyncResponse) *proto.SyncResponse {
+	if resp.GetNetworkMap() == nil {
+		return resp
+	}
+	digest, err := networkmap.Digest(resp.GetNetworkMap())
+	if err != nil {
+		log.WithContext(ctx).Debugf("not issuing a resume token to peer %s: %v", peerKey.String(), err)
+		return resp
+	}
+	return withResumeToken(resp, s.resumeTokens.issue(peerKey.String(), digest))
+}
+
+// withResumeToken returns the response with the resume token set, the response itself isn't modified
+func withResumeToken(resp *proto.SyncResponse, token string) *proto.SyncResponse {
+	return &proto.SyncResponse{
+		NetbirdConfig:      resp.GetNetbirdConfig(),
+		PeerConfig:         resp.GetPeerConfig(),
+		RemotePeers:        resp.GetRemotePeers(),
+		RemotePeersIsEmpty: resp.GetRemotePeersIsEmpty(),
+		NetworkMap:         resp.GetNetworkMap(),
+		Checks:             resp.GetChecks(),
+		ResumeToken:        token,
+	}
+}
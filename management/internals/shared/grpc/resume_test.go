@@ -0,0 +1,83 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+
+	"github.com/netbirdio/netbird/shared/management/networkmap"
+	"github.com/netbirdio/netbird/shared/management/proto"
+)
+
+func TestResumeTokens(t *testing.T) {
+	tokens, err := newResumeTokens()
+	require.NoError(t, err)
+
+	digest, err := networkmap.Digest(&proto.NetworkMap{Serial: 1})
+	require.NoError(t, err)
+	otherDigest, err := networkmap.Digest(&proto.NetworkMap{Serial: 2})
+	require.NoError(t, err)
+
+	token := tokens.issue("peer-a", digest)
+	assert.True(t, tokens.valid(token, "peer-a", digest))
+	assert.False(t, tokens.valid(token, "peer-b", digest), "tokens are bound to the peer")
+	assert.False(t, tokens.valid(token, "peer-a", otherDigest), "tokens are bound to the network map")
+	assert.False(t, tokens.valid("garbage", "peer-a", digest))
+
+	restarted, err := newResumeTokens()
+	require.NoError(t, err)
+	assert.False(t, restarted.valid(token, "peer-a", digest), "tokens of another management instance are rejected")
+
+	tokens.now = func() time.Time { return time.Now().Add(resumeTokenTTL + time.Minute) }
+	assert.False(t, tokens.valid(token, "peer-a", digest), "expired tokens are rejected")
+}
+
+func TestServer_InitialSyncResponse(t *testing.T) {
+	tokens, err := newResumeTokens()
+	require.NoError(t, err)
+	s := &Server{resumeTokens: tokens}
+	peerKey, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+	ctx := context.Background()
+	capabilities := []proto.SyncRequest_Capability{proto.SyncRequest_NETWORK_MAP_DELTA}
+
+	first := &proto.SyncResponse{
+		NetbirdConfig: &proto.NetbirdConfig{},
+		NetworkMap: &proto.NetworkMap{
+			Serial:      1,
+			RemotePeers: []*proto.RemotePeerConfig{{WgPubKey: "peer-a"}},
+		},
+	}
+
+	resp := s.initialSyncResponse(ctx, peerKey.PublicKey(), "", first, newNetworkMapDeltas(capabilities))
+	require.NotNil(t, resp.GetNetworkMap())
+	assert.False(t, resp.GetResumed())
+	require.NotEmpty(t, resp.GetResumeToken())
+	assert.Empty(t, first.GetResumeToken(), "the response of the network map controller isn't modified")
+
+	deltas := newNetworkMapDeltas(capabilities)
+	resumed := s.initialSyncResponse(ctx, peerKey.PublicKey(), resp.GetResumeToken(), first, deltas)
+	assert.True(t, resumed.GetResumed())
+	assert.Nil(t, resumed.GetNetworkMap(), "the peer keeps its network map")
+	assert.NotNil(t, resumed.GetNetbirdConfig())
+	assert.NotEmpty(t, resumed.GetResumeToken())
+
+	second := &proto.SyncResponse{
+		NetworkMap: &proto.NetworkMap{
+			Serial:      2,
+			RemotePeers: []*proto.RemotePeerConfig{{WgPubKey: "peer-a"}, {WgPubKey: "peer-b"}},
+		},
+	}
+	update := deltas.encode(s.updateWithResumeToken(ctx, peerKey.PublicKey(), second))
+	assert.True(t, update.GetNetworkMap().GetDelta(), "the kept network map is the base of the deltas")
+	assert.Equal(t, uint64(1), update.GetNetworkMap().GetBaseSerial())
+	assert.NotEmpty(t, update.GetResumeToken())
+
+	changed := s.initialSyncResponse(ctx, peerKey.PublicKey(), resp.GetResumeToken(), second, newNetworkMapDeltas(capabilities))
+	assert.False(t, changed.GetResumed(), "a changed network map is sent in full")
+	assert.Equal(t, uint64(2), changed.GetNetworkMap().GetSerial())
+}
@@ -22,6 +22,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/netbirdio/netbird/management/internals/controllers/network_map"
 	nbconfig "github.com/netbirdio/netbird/management/internals/server/config"
@@ -75,6 +76,8 @@ type Server struct {
 
 	syncSem atomic.Int32
 	syncLim int32
+
+	resumeTokens *resumeTokens
 }
 
 // NewServer creates a new Management server
@@ -113,6 +116,11 @@ func NewServer(
 		}
 	}
 
+	resumeTokens, err := newResumeTokens()
+	if err != nil {
+		return nil, err
+	}
+
 	return &Server{
 		accountManager:           accountManager,
 		settingsManager:          settingsManager,
@@ -129,6 +137,8 @@ func NewServer(
 		loginFilter: newLoginFilter(),
 
 		syncLim: syncLim,
+
+		resumeTokens: resumeTokens,
 	}, nil
 }
 
@@ -258,7 +268,9 @@ func (s *Server) Sync(req *proto.EncryptedMessage, srv proto.ManagementService_S
 		return mapError(ctx, err)
 	}
 
-	err = s.sendInitialSync(ctx, peerKey, peer, netMap, postureChecks, srv, dnsFwdPort)
+	deltas := newNetworkMapDeltas(syncReq.GetCapabilities())
+
+	err = s.sendInitialSync(ctx, peerKey, peer, netMap, postureChecks, srv, dnsFwdPort, deltas, syncReq.GetResumeToken())
 	if err != nil {
 		log.WithContext(ctx).Debugf("error while sending initial sync for %s: %v", peerKey.String(), err)
 		s.syncSem.Add(-1)
@@ -286,11 +298,11 @@ func (s *Server) Sync(req *proto.EncryptedMessage, srv proto.ManagementService_S
 
 	s.syncSem.Add(-1)
 
-	return s.handleUpdates(ctx, accountID, peerKey, peer, updates, srv)
+	return s.handleUpdates(ctx, accountID, peerKey, peer, updates, srv, deltas)
 }
 
 // handleUpdates sends updates to the connected peer until the updates channel is closed.
-func (s *Server) handleUpdates(ctx context.Context, accountID string, peerKey wgtypes.Key, peer *nbpeer.Peer, updates chan *network_map.UpdateMessage, srv proto.ManagementService_SyncServer) error {
+func (s *Server) handleUpdates(ctx context.Context, accountID string, peerKey wgtypes.Key, peer *nbpeer.Peer, updates chan *network_map.UpdateMessage, srv proto.ManagementService_SyncServer, deltas *networkMapDeltas) error {
 	log.WithContext(ctx).Tracef("starting to handle updates for peer %s", peerKey.String())
 	for {
 		select {
@@ -307,7 +319,7 @@ func (s *Server) handleUpdates(ctx context.Context, accountID string, peerKey wg
 			}
 			log.WithContext(ctx).Debugf("received an update for peer %s", peerKey.String())
 
-			if err := s.sendUpdate(ctx, accountID, peerKey, peer, update, srv); err != nil {
+			if err := s.sendUpdate(ctx, accountID, peerKey, peer, update, srv, deltas); err != nil {
 				log.WithContext(ctx).Debugf("error while sending an update to peer %s: %v", peerKey.String(), err)
 				return err
 			}
@@ -324,14 +336,15 @@ func (s *Server) handleUpdates(ctx context.Context, accountID string, peerKey wg
 
 // sendUpdate encrypts the update message using the peer key and the server's wireguard key,
 // then sends the encrypted message to the connected peer via the sync server.
-func (s *Server) sendUpdate(ctx context.Context, accountID string, peerKey wgtypes.Key, peer *nbpeer.Peer, update *network_map.UpdateMessage, srv proto.ManagementService_SyncServer) error {
+// Network maps are sent as deltas if the peer supports them.
+func (s *Server) sendUpdate(ctx context.Context, accountID string, peerKey wgtypes.Key, peer *nbpeer.Peer, update *network_map.UpdateMessage, srv proto.ManagementService_SyncServer, deltas *networkMapDeltas) error {
 	key, err := s.secretsManager.GetWGKey()
 	if err != nil {
 		s.cancelPeerRoutines(ctx, accountID, peer)
 		return status.Errorf(codes.Internal, "failed processing update message")
 	}
 
-	encryptedResp, err := encryption.EncryptMessage(peerKey, key, update.Update)
+	encryptedResp, err := encryption.EncryptMessage(peerKey, key, withServerTime(deltas.encode(s.updateWithResumeToken(ctx, peerKey, update.Update))))
 	if err != nil {
 		s.cancelPeerRoutines(ctx, accountID, peer)
 		return status.Errorf(codes.Internal, "failed processing update message")
@@ -348,6 +361,22 @@ func (s *Server) sendUpdate(ctx context.Context, accountID string, peerKey wgtyp
 	return nil
 }
 
+// withServerTime returns the response stamped with the current time, peers check their clock against it before
+// enforcing rule schedules. The response itself isn't modified, updates are shared between peers.
+func withServerTime(resp *proto.SyncResponse) *proto.SyncResponse {
+	return &proto.SyncResponse{
+		NetbirdConfig:      resp.GetNetbirdConfig(),
+		PeerConfig:         resp.GetPeerConfig(),
+		RemotePeers:        resp.GetRemotePeers(),
+		RemotePeersIsEmpty: resp.GetRemotePeersIsEmpty(),
+		NetworkMap:         resp.GetNetworkMap(),
+		Checks:             resp.GetChecks(),
+		ResumeToken:        resp.GetResumeToken(),
+		Resumed:            resp.GetResumed(),
+		ServerTime:         timestamppb.Now(),
+	}
+}
+
 func (s *Server) cancelPeerRoutines(ctx context.Context, accountID string, peer *nbpeer.Peer) {
 	unlock := s.acquirePeerLockByUID(ctx, peer.Key)
 	defer unlock()
@@ -501,7 +530,45 @@ func extractPeerMeta(ctx context.Context, meta *proto.PeerSystemMeta) nbpeer.Pee
 			LazyConnectionEnabled: meta.GetFlags().GetLazyConnectionEnabled(),
 		},
 		Files: files,
+		Roles: nbpeer.Roles{
+			RoutingPeer:    meta.GetRoles().GetRoutingPeer(),
+			ExitNode:       meta.GetRoles().GetExitNode(),
+			IngressGateway: meta.GetRoles().GetIngressGateway(),
+			EphemeralCI:    meta.GetRoles().GetEphemeralCI(),
+		},
+		Inventory: extractInventory(meta.GetInventory()),
+	}
+}
+
+func extractInventory(inventory *proto.Inventory) *nbpeer.Inventory {
+	if inventory == nil {
+		return nil
+	}
+
+	packages := make([]nbpeer.Package, 0, len(inventory.GetPackages()))
+	for _, pkg := range inventory.GetPackages() {
+		packages = append(packages, nbpeer.Package{
+			Name:    pkg.GetName(),
+			Version: pkg.GetVersion(),
+		})
+	}
+
+	return &nbpeer.Inventory{
+		Packages:          packages,
+		PackagesTruncated: inventory.GetPackagesTruncated(),
+		PatchLevel:        inventory.GetPatchLevel(),
+		LastUpdate:        timestampToTime(inventory.GetLastUpdate()),
+		DiskEncryption:    inventory.GetDiskEncryption(),
+		CollectedAt:       timestampToTime(inventory.GetCollectedAt()),
+	}
+}
+
+// timestampToTime converts an unset timestamp to the zero time instead of the Unix epoch
+func timestampToTime(ts *timestamp.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
 	}
+	return ts.AsTime()
 }
 
 func (s *Server) parseRequest(ctx context.Context, req *proto.EncryptedMessage, parsed pb.Message) (wgtypes.Key, error) {
@@ -687,8 +754,9 @@ func (s *Server) IsHealthy(ctx context.Context, req *proto.Empty) (*proto.Empty,
 	return &proto.Empty{}, nil
 }
 
-// sendInitialSync sends initial proto.SyncResponse to the peer requesting synchronization
-func (s *Server) sendInitialSync(ctx context.Context, peerKey wgtypes.Key, peer *nbpeer.Peer, networkMap *types.NetworkMap, postureChecks []*posture.Checks, srv proto.ManagementService_SyncServer, dnsFwdPort int64) error {
+// sendInitialSync sends initial proto.SyncResponse to the peer requesting synchronization. The network map is left
+// out if the peer resumes its session with a valid resume token.
+func (s *Server) sendInitialSync(ctx context.Context, peerKey wgtypes.Key, peer *nbpeer.Peer, networkMap *types.NetworkMap, postureChecks []*posture.Checks, srv proto.ManagementService_SyncServer, dnsFwdPort int64, deltas *networkMapDeltas, resumeToken string) error {
 	var err error
 
 	var turnToken *Token
@@ -724,7 +792,8 @@ func (s *Server) sendInitialSync(ctx context.Context, peerKey wgtypes.Key, peer
 		return status.Errorf(codes.Internal, "failed getting server key")
 	}
 
-	encryptedResp, err := encryption.EncryptMessage(peerKey, key, plainResp)
+	// the first map of the stream is always sent in full, it's the base of the following deltas
+	encryptedResp, err := encryption.EncryptMessage(peerKey, key, withServerTime(s.initialSyncResponse(ctx, peerKey, resumeToken, plainResp, deltas)))
 	if err != nil {
 		return status.Errorf(codes.Internal, "error handling request")
 	}
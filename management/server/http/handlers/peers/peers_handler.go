@@ -198,6 +198,11 @@ func (h *Handler) GetAllPeers(w http.ResponseWriter, r *http.Request) {
 
 	nameFilter := r.URL.Query().Get("name")
 	ipFilter := r.URL.Query().Get("ip")
+	roleFilter := r.URL.Query().Get("role")
+	if roleFilter != "" && !isValidPeerRole(roleFilter) {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid peer role %s", roleFilter), w)
+		return
+	}
 
 	accountID, userID := userAuth.AccountId, userAuth.UserId
 
@@ -207,6 +212,10 @@ func (h *Handler) GetAllPeers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if roleFilter != "" {
+		peers = filterPeersByRole(peers, roleFilter)
+	}
+
 	settings, err := h.accountManager.GetAccountSettings(r.Context(), accountID, activity.SystemInitiator)
 	if err != nil {
 		util.WriteError(r.Context(), err, w)
@@ -464,6 +473,7 @@ func toSinglePeerResponse(peer *nbpeer.Peer, groupsInfo []api.GroupMinimum, dnsD
 			RosenpassPermissive:   &peer.Meta.Flags.RosenpassPermissive,
 			ServerSshAllowed:      &peer.Meta.Flags.ServerSSHAllowed,
 		},
+		LocalRoles: toPeerLocalRoles(peer.Meta.Roles),
 	}
 
 	if !approved {
@@ -518,6 +528,36 @@ func toPeerListItemResponse(peer *nbpeer.Peer, groupsInfo []api.GroupMinimum, dn
 			RosenpassPermissive:   &peer.Meta.Flags.RosenpassPermissive,
 			ServerSshAllowed:      &peer.Meta.Flags.ServerSSHAllowed,
 		},
+		LocalRoles: toPeerLocalRoles(peer.Meta.Roles),
+	}
+}
+
+func toPeerLocalRoles(roles nbpeer.Roles) *api.PeerLocalRoles {
+	return &api.PeerLocalRoles{
+		RoutingPeer:    &roles.RoutingPeer,
+		ExitNode:       &roles.ExitNode,
+		IngressGateway: &roles.IngressGateway,
+		EphemeralCi:    &roles.EphemeralCI,
+	}
+}
+
+func filterPeersByRole(peers []*nbpeer.Peer, role string) []*nbpeer.Peer {
+	filtered := make([]*nbpeer.Peer, 0, len(peers))
+	for _, peer := range peers {
+		if peer.Meta.Roles.Has(role) {
+			filtered = append(filtered, peer)
+		}
+	}
+	return filtered
+}
+
+func isValidPeerRole(role string) bool {
+	switch api.GetApiPeersParamsRole(role) {
+	case api.GetApiPeersParamsRoleRoutingPeer, api.GetApiPeersParamsRoleExitNode,
+		api.GetApiPeersParamsRoleIngressGateway, api.GetApiPeersParamsRoleEphemeralCi:
+		return true
+	default:
+		return false
 	}
 }
 
@@ -345,6 +345,66 @@ func TestGetPeers(t *testing.T) {
 	}
 }
 
+func TestGetPeers_RoleFilter(t *testing.T) {
+	routingPeer := &nbpeer.Peer{
+		ID:     testPeerID,
+		Key:    "key",
+		IP:     net.ParseIP("100.64.0.1"),
+		Status: &nbpeer.PeerStatus{Connected: true},
+		Name:   "RoutingPeer",
+		Meta:   nbpeer.PeerSystemMeta{Roles: nbpeer.Roles{RoutingPeer: true, ExitNode: true}},
+	}
+	client := &nbpeer.Peer{
+		ID:     noUpdateChannelTestPeerID,
+		Key:    "key1",
+		IP:     net.ParseIP("100.64.0.2"),
+		Status: &nbpeer.PeerStatus{},
+		Name:   "Client",
+	}
+
+	p := initTestMetaData(t, routingPeer, client)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/peers", p.GetAllPeers).Methods("GET")
+
+	tt := []struct {
+		query          string
+		expectedStatus int
+		expectedPeers  []string
+	}{
+		{query: "", expectedStatus: http.StatusOK, expectedPeers: []string{"RoutingPeer", "Client"}},
+		{query: "?role=exit-node", expectedStatus: http.StatusOK, expectedPeers: []string{"RoutingPeer"}},
+		{query: "?role=ingress-gateway", expectedStatus: http.StatusOK, expectedPeers: []string{}},
+		{query: "?role=gateway", expectedStatus: http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.query, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/peers"+tc.query, nil)
+			req = nbcontext.SetUserAuthInRequest(req, auth.UserAuth{
+				UserId:    "admin_user",
+				Domain:    "hotmail.com",
+				AccountId: "test_id",
+			})
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var respBody []*api.PeerBatch
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &respBody))
+			names := make([]string, 0, len(respBody))
+			for _, peer := range respBody {
+				names = append(names, peer.Name)
+				require.NotNil(t, peer.LocalRoles)
+			}
+			assert.ElementsMatch(t, tc.expectedPeers, names)
+		})
+	}
+}
+
 func TestGetAccessiblePeers(t *testing.T) {
 	peer1 := &nbpeer.Peer{
 		ID:                     "peer1",
@@ -111,6 +111,67 @@ type Flags struct {
 	LazyConnectionEnabled bool
 }
 
+// Roles are the roles a peer derives from its local state and reports with its meta
+type Roles struct {
+	RoutingPeer    bool
+	ExitNode       bool
+	IngressGateway bool
+	EphemeralCI    bool
+}
+
+// Has reports whether the peer has the role, named like in the peer's local status, e.g. routing-peer
+func (r Roles) Has(role string) bool {
+	switch role {
+	case RoleRoutingPeer:
+		return r.RoutingPeer
+	case RoleExitNode:
+		return r.ExitNode
+	case RoleIngressGateway:
+		return r.IngressGateway
+	case RoleEphemeralCI:
+		return r.EphemeralCI
+	default:
+		return false
+	}
+}
+
+// Role names of Roles.Has
+const (
+	RoleRoutingPeer    = "routing-peer"
+	RoleExitNode       = "exit-node"
+	RoleIngressGateway = "ingress-gateway"
+	RoleEphemeralCI    = "ephemeral-ci"
+)
+
+// Inventory is the software inventory a peer reports if it has the inventory reporting enabled
+type Inventory struct {
+	Packages          []Package
+	PackagesTruncated bool
+	PatchLevel        string
+	LastUpdate        time.Time
+	DiskEncryption    string
+	CollectedAt       time.Time
+}
+
+// Package is an installed software package of the Inventory
+type Package struct {
+	Name    string
+	Version string
+}
+
+// isEqual compares the inventories without the collection time, so a peer collecting an unchanged inventory
+// doesn't update the stored meta
+func (i *Inventory) isEqual(other *Inventory) bool {
+	if i == nil || other == nil {
+		return i == other
+	}
+	return slices.Equal(i.Packages, other.Packages) &&
+		i.PackagesTruncated == other.PackagesTruncated &&
+		i.PatchLevel == other.PatchLevel &&
+		i.LastUpdate.Equal(other.LastUpdate) &&
+		i.DiskEncryption == other.DiskEncryption
+}
+
 // PeerSystemMeta is a metadata of a Peer machine system
 type PeerSystemMeta struct { //nolint:revive
 	Hostname           string
@@ -130,6 +191,8 @@ type PeerSystemMeta struct { //nolint:revive
 	Environment        Environment `gorm:"serializer:json"`
 	Flags              Flags       `gorm:"serializer:json"`
 	Files              []File      `gorm:"serializer:json"`
+	Roles              Roles       `gorm:"serializer:json"`
+	Inventory          *Inventory  `gorm:"serializer:json"`
 }
 
 func (p PeerSystemMeta) isEqual(other PeerSystemMeta) bool {
@@ -174,7 +237,9 @@ func (p PeerSystemMeta) isEqual(other PeerSystemMeta) bool {
 		p.SystemManufacturer == other.SystemManufacturer &&
 		p.Environment.Cloud == other.Environment.Cloud &&
 		p.Environment.Platform == other.Environment.Platform &&
-		p.Flags.isEqual(other.Flags)
+		p.Flags.isEqual(other.Flags) &&
+		p.Roles == other.Roles &&
+		p.Inventory.isEqual(other.Inventory)
 }
 
 func (p PeerSystemMeta) isEmpty() bool {
@@ -308,6 +308,8 @@ func TestClient_Sync(t *testing.T) {
 	}
 
 	ch := make(chan *mgmtProto.SyncResponse, 1)
+	resume := NewSessionResume()
+	client.SetSessionResume(resume)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -340,9 +342,41 @@ func TestClient_Sync(t *testing.T) {
 		if resp.GetRemotePeers()[0].GetWgPubKey() != remoteKey.PublicKey().String() {
 			t.Errorf("expecting RemotePeer public key %s got %s", remoteKey.PublicKey().String(), resp.GetRemotePeers()[0].GetWgPubKey())
 		}
+		if resp.GetResumeToken() == "" {
+			t.Error("expecting a resume token got none")
+		}
 	case <-time.After(3 * time.Second):
 		t.Error("timeout waiting for test to finish")
 	}
+
+	// a new client sharing the session store resumes the session with the unchanged network map
+	require.Eventually(t, resume.Resumable, 3*time.Second, 10*time.Millisecond)
+	cancel()
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	resumingClient, clientErr := NewClient(ctx, listener.Addr().String(), testKey, false)
+	require.NoError(t, clientErr)
+	resumingClient.SetSessionResume(resume)
+
+	go func() {
+		_ = resumingClient.Sync(ctx, info, func(msg *mgmtProto.SyncResponse) error {
+			ch <- msg
+			return nil
+		})
+	}()
+
+	select {
+	case resp := <-ch:
+		if !resp.GetResumed() {
+			t.Error("expecting the session to be resumed")
+		}
+		if resp.GetNetworkMap() != nil {
+			t.Error("expecting no network map in a resumed session")
+		}
+	case <-time.After(3 * time.Second):
+		t.Error("timeout waiting for the resumed session")
+	}
 }
 
 func Test_SystemMetaDataFromClient(t *testing.T) {
@@ -16,6 +16,7 @@ import (
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	nbgrpc "github.com/netbirdio/netbird/client/grpc"
 	"github.com/netbirdio/netbird/client/system"
@@ -32,6 +33,10 @@ const (
 	errMsgNoMgmtConnection = "no connection to management"
 )
 
+// ErrNetworkMapResync is returned by Sync message handlers that can't apply a network map delta. The Sync stream is
+// reconnected, management starts every stream with a full network map.
+var ErrNetworkMapResync = errors.New("network map resync required")
+
 // ConnStateNotifier is a wrapper interface of the status recorders
 type ConnStateNotifier interface {
 	MarkManagementDisconnected(error)
@@ -45,6 +50,9 @@ type GrpcClient struct {
 	conn                  *grpc.ClientConn
 	connStateCallback     ConnStateNotifier
 	connStateCallbackLock sync.RWMutex
+
+	// resume keeps the session the Sync stream is resumed with after a reconnect
+	resume *SessionResume
 }
 
 // NewClient creates a new client to Management service
@@ -74,6 +82,7 @@ func NewClient(ctx context.Context, addr string, ourPrivateKey wgtypes.Key, tlsE
 		ctx:                   ctx,
 		conn:                  conn,
 		connStateCallbackLock: sync.RWMutex{},
+		resume:                NewSessionResume(),
 	}, nil
 }
 
@@ -89,6 +98,12 @@ func (c *GrpcClient) SetConnStateListener(notifier ConnStateNotifier) {
 	c.connStateCallback = notifier
 }
 
+// SetSessionResume sets the store of the resumable Sync session, a store shared with the previous client resumes its
+// session. It must be called before Sync.
+func (c *GrpcClient) SetSessionResume(resume *SessionResume) {
+	c.resume = resume
+}
+
 // defaultBackoff is a basic backoff mechanism for general issues
 func defaultBackoff(ctx context.Context) backoff.BackOff {
 	return backoff.WithContext(&backoff.ExponentialBackOff{
@@ -124,13 +139,17 @@ func (c *GrpcClient) Sync(ctx context.Context, sysInfo *system.Info, msgHandler
 			return fmt.Errorf("connection to management is not ready and in %s state", connState)
 		}
 
-		serverPubKey, err := c.GetServerPublicKey()
-		if err != nil {
-			log.Debugf(errMsgMgmtPublicKey, err)
-			return err
+		serverPubKey, resumeToken := c.resume.state()
+		if serverPubKey == nil {
+			var err error
+			serverPubKey, err = c.GetServerPublicKey()
+			if err != nil {
+				log.Debugf(errMsgMgmtPublicKey, err)
+				return err
+			}
 		}
 
-		return c.handleStream(ctx, *serverPubKey, sysInfo, msgHandler, backOff)
+		return c.handleStream(ctx, *serverPubKey, sysInfo, resumeToken, msgHandler, backOff)
 	}
 
 	err := backoff.Retry(operation, backOff)
@@ -141,14 +160,16 @@ func (c *GrpcClient) Sync(ctx context.Context, sysInfo *system.Info, msgHandler
 	return err
 }
 
-func (c *GrpcClient) handleStream(ctx context.Context, serverPubKey wgtypes.Key, sysInfo *system.Info,
+func (c *GrpcClient) handleStream(ctx context.Context, serverPubKey wgtypes.Key, sysInfo *system.Info, resumeToken string,
 	msgHandler func(msg *proto.SyncResponse) error, backOff backoff.BackOff) error {
 	ctx, cancelStream := context.WithCancel(ctx)
 	defer cancelStream()
 
-	stream, err := c.connectToStream(ctx, serverPubKey, sysInfo)
+	stream, err := c.connectToStream(ctx, serverPubKey, sysInfo, resumeToken)
 	if err != nil {
 		log.Debugf("failed to open Management Service stream: %s", err)
+		// the server key might have changed, the next attempt starts a new session
+		c.resume.Reset()
 		if s, ok := gstatus.FromError(err); ok && s.Code() == codes.PermissionDenied {
 			return backoff.Permanent(err) // unrecoverable error, propagate to the upper layer
 		}
@@ -191,7 +212,7 @@ func (c *GrpcClient) GetNetworkMap(sysInfo *system.Info) (*proto.NetworkMap, err
 
 	ctx, cancelStream := context.WithCancel(c.ctx)
 	defer cancelStream()
-	stream, err := c.connectToStream(ctx, *serverPubKey, sysInfo)
+	stream, err := c.connectToStream(ctx, *serverPubKey, sysInfo, "")
 	if err != nil {
 		log.Debugf("failed to open Management Service stream: %s", err)
 		return nil, err
@@ -224,8 +245,12 @@ func (c *GrpcClient) GetNetworkMap(sysInfo *system.Info) (*proto.NetworkMap, err
 	return decryptedResp.GetNetworkMap(), nil
 }
 
-func (c *GrpcClient) connectToStream(ctx context.Context, serverPubKey wgtypes.Key, sysInfo *system.Info) (proto.ManagementService_SyncClient, error) {
-	req := &proto.SyncRequest{Meta: infoToMetaData(sysInfo)}
+func (c *GrpcClient) connectToStream(ctx context.Context, serverPubKey wgtypes.Key, sysInfo *system.Info, resumeToken string) (proto.ManagementService_SyncClient, error) {
+	req := &proto.SyncRequest{
+		Meta:         infoToMetaData(sysInfo),
+		Capabilities: []proto.SyncRequest_Capability{proto.SyncRequest_NETWORK_MAP_DELTA},
+		ResumeToken:  resumeToken,
+	}
 
 	myPrivateKey := c.key
 	myPublicKey := myPrivateKey.PublicKey()
@@ -260,11 +285,31 @@ func (c *GrpcClient) receiveEvents(stream proto.ManagementService_SyncClient, se
 		err = encryption.DecryptMessage(serverPubKey, c.key, update.Body, decryptedResp)
 		if err != nil {
 			log.Errorf("failed decrypting update message from Management Service: %s", err)
+			c.resume.Reset()
 			return err
 		}
 
+		if decryptedResp.GetResumed() {
+			log.Infof("resumed the Management Service session, the network map didn't change")
+		}
+
 		if err := msgHandler(decryptedResp); err != nil {
+			if stream.Context().Err() != nil {
+				// the stream was closed, the session is kept for the client resuming it
+				return err
+			}
+			// the token is only valid for a network map the handler applied
+			c.resume.Reset()
+			if errors.Is(err, ErrNetworkMapResync) {
+				log.Warnf("reconnecting to the Management Service sync stream for a full network map: %v", err)
+				return err
+			}
 			log.Errorf("failed handling an update message received from Management Service: %v", err.Error())
+			continue
+		}
+
+		if decryptedResp.GetResumeToken() != "" {
+			c.resume.set(serverPubKey, decryptedResp.GetResumeToken())
 		}
 	}
 }
@@ -585,5 +630,42 @@ func infoToMetaData(info *system.Info) *proto.PeerSystemMeta {
 
 			LazyConnectionEnabled: info.LazyConnectionEnabled,
 		},
+
+		Roles: &proto.PeerRoles{
+			RoutingPeer:    info.Roles.RoutingPeer,
+			ExitNode:       info.Roles.ExitNode,
+			IngressGateway: info.Roles.IngressGateway,
+			EphemeralCI:    info.Roles.EphemeralCI,
+		},
+
+		Inventory: inventoryToProto(info.Inventory),
+	}
+}
+
+func inventoryToProto(inventory *system.Inventory) *proto.Inventory {
+	if inventory == nil {
+		return nil
+	}
+
+	packages := make([]*proto.Package, 0, len(inventory.Packages))
+	for _, pkg := range inventory.Packages {
+		packages = append(packages, &proto.Package{
+			Name:    pkg.Name,
+			Version: pkg.Version,
+		})
+	}
+
+	var lastUpdate *timestamppb.Timestamp
+	if !inventory.LastUpdate.IsZero() {
+		lastUpdate = timestamppb.New(inventory.LastUpdate)
+	}
+
+	return &proto.Inventory{
+		Packages:          packages,
+		PackagesTruncated: inventory.PackagesTruncated,
+		PatchLevel:        inventory.PatchLevel,
+		LastUpdate:        lastUpdate,
+		DiskEncryption:    string(inventory.DiskEncryption),
+		CollectedAt:       timestamppb.New(inventory.CollectedAt),
 	}
 }
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"sync"
+
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+// SessionResume keeps the resume token of the last SyncResponse applied by the peer and the server key it was
+// received with. It outlives the clients, a Sync stream of a new client resumes the session of the previous one.
+type SessionResume struct {
+	mu        sync.Mutex
+	serverKey *wgtypes.Key
+	token     string
+}
+
+// NewSessionResume returns a store without a session to resume
+func NewSessionResume() *SessionResume {
+	return &SessionResume{}
+}
+
+// Resumable reports whether there is a session to resume
+func (r *SessionResume) Resumable() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.token != ""
+}
+
+// Reset drops the session, the next Sync stream receives the full network map. It is called when the network map
+// the token was issued for is gone, e.g. for a new engine.
+func (r *SessionResume) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.serverKey = nil
+	r.token = ""
+}
+
+// state returns the server key and the token to resume the Sync session with, the key is nil if there is no
+// session to resume
+func (r *SessionResume) state() (*wgtypes.Key, string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.serverKey, r.token
+}
+
+func (r *SessionResume) set(serverPubKey wgtypes.Key, token string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.serverKey = &serverPubKey
+	r.token = token
+}
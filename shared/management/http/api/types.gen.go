@@ -214,6 +214,14 @@ const (
 	GetApiEventsNetworkTrafficParamsDirectionINGRESS          GetApiEventsNetworkTrafficParamsDirection = "INGRESS"
 )
 
+// Defines values for GetApiPeersParamsRole.
+const (
+	GetApiPeersParamsRoleEphemeralCi    GetApiPeersParamsRole = "ephemeral-ci"
+	GetApiPeersParamsRoleExitNode       GetApiPeersParamsRole = "exit-node"
+	GetApiPeersParamsRoleIngressGateway GetApiPeersParamsRole = "ingress-gateway"
+	GetApiPeersParamsRoleRoutingPeer    GetApiPeersParamsRole = "routing-peer"
+)
+
 // AccessiblePeer defines model for AccessiblePeer.
 type AccessiblePeer struct {
 	// CityName Commonly used English name of the city
@@ -1141,6 +1149,7 @@ type Peer struct {
 	// LastSeen Last time peer connected to Netbird's management service
 	LastSeen   time.Time       `json:"last_seen"`
 	LocalFlags *PeerLocalFlags `json:"local_flags,omitempty"`
+	LocalRoles *PeerLocalRoles `json:"local_roles,omitempty"`
 
 	// LoginExpirationEnabled Indicates whether peer login expiration has been enabled or not
 	LoginExpirationEnabled bool `json:"login_expiration_enabled"`
@@ -1232,6 +1241,7 @@ type PeerBatch struct {
 	// LastSeen Last time peer connected to Netbird's management service
 	LastSeen   time.Time       `json:"last_seen"`
 	LocalFlags *PeerLocalFlags `json:"local_flags,omitempty"`
+	LocalRoles *PeerLocalRoles `json:"local_roles,omitempty"`
 
 	// LoginExpirationEnabled Indicates whether peer login expiration has been enabled or not
 	LoginExpirationEnabled bool `json:"login_expiration_enabled"`
@@ -1294,6 +1304,21 @@ type PeerLocalFlags struct {
 	ServerSshAllowed *bool `json:"server_ssh_allowed,omitempty"`
 }
 
+// PeerLocalRoles Roles the peer derived from its local state and reported to the management service
+type PeerLocalRoles struct {
+	// EphemeralCi Indicates whether the peer runs in a CI job
+	EphemeralCi *bool `json:"ephemeral_ci,omitempty"`
+
+	// ExitNode Indicates whether the peer routes a default route as an exit node
+	ExitNode *bool `json:"exit_node,omitempty"`
+
+	// IngressGateway Indicates whether the peer has ingress port forwarding rules
+	IngressGateway *bool `json:"ingress_gateway,omitempty"`
+
+	// RoutingPeer Indicates whether the peer routes networks for other peers
+	RoutingPeer *bool `json:"routing_peer,omitempty"`
+}
+
 // PeerMinimum defines model for PeerMinimum.
 type PeerMinimum struct {
 	// Id Peer ID
@@ -2057,8 +2082,14 @@ type GetApiPeersParams struct {
 
 	// Ip Filter peers by IP address
 	Ip *string `form:"ip,omitempty" json:"ip,omitempty"`
+
+	// Role Filter peers by a role they reported
+	Role *GetApiPeersParamsRole `form:"role,omitempty" json:"role,omitempty"`
 }
 
+// GetApiPeersParamsRole defines parameters for GetApiPeers.
+type GetApiPeersParamsRole string
+
 // GetApiPeersPeerIdIngressPortsParams defines parameters for GetApiPeersPeerIdIngressPorts.
 type GetApiPeersPeerIdIngressPortsParams struct {
 	// Name Filters ingress port allocations by name
@@ -0,0 +1,214 @@
+// Package networkmap computes and applies the incremental network map updates of the management Sync stream.
+package networkmap
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	pb "google.golang.org/protobuf/proto"
+
+	"github.com/netbirdio/netbird/shared/management/proto"
+)
+
+// ErrBaseMismatch is returned if a delta doesn't apply to the network map the peer holds
+var ErrBaseMismatch = errors.New("network map delta doesn't apply to the current network map")
+
+type peerEntry struct {
+	config  *proto.RemotePeerConfig
+	offline bool
+}
+
+// Delta returns the changes from prev to next as a delta network map. It returns nil if the change can't be
+// expressed as a delta, the full map has to be sent then. Computing it compares every peer and route, which costs
+// about as much as marshaling the full map. The savings are the bandwidth and the client side work: the client
+// applies only the peers, routes and rules the delta touches.
+func Delta(prev, next *proto.NetworkMap) *proto.NetworkMap {
+	if prev == nil || next == nil || prev.GetDelta() || next.GetDelta() {
+		return nil
+	}
+
+	prevPeers, ok := peersByKey(prev)
+	if !ok {
+		return nil
+	}
+	nextPeers, ok := peersByKey(next)
+	if !ok {
+		return nil
+	}
+	prevRoutes, ok := routesByID(prev.GetRoutes())
+	if !ok {
+		return nil
+	}
+	nextRoutes, ok := routesByID(next.GetRoutes())
+	if !ok {
+		return nil
+	}
+
+	delta := &proto.NetworkMap{
+		Serial:            next.GetSerial(),
+		PeerConfig:        next.GetPeerConfig(),
+		DNSConfig:         next.GetDNSConfig(),
+		ForwardingRules:   next.GetForwardingRules(),
+		SshAuth:           next.GetSshAuth(),
+		InboundExceptions: next.GetInboundExceptions(),
+		Delta:             true,
+		BaseSerial:        prev.GetSerial(),
+	}
+
+	for _, peer := range next.GetRemotePeers() {
+		if old, ok := prevPeers[peer.GetWgPubKey()]; !ok || old.offline || !pb.Equal(old.config, peer) {
+			delta.RemotePeers = append(delta.RemotePeers, peer)
+		}
+	}
+	for _, peer := range next.GetOfflinePeers() {
+		if old, ok := prevPeers[peer.GetWgPubKey()]; !ok || !old.offline || !pb.Equal(old.config, peer) {
+			delta.OfflinePeers = append(delta.OfflinePeers, peer)
+		}
+	}
+	for key := range prevPeers {
+		if _, ok := nextPeers[key]; !ok {
+			delta.RemovedPeers = append(delta.RemovedPeers, key)
+		}
+	}
+	slices.Sort(delta.RemovedPeers)
+
+	for _, route := range next.GetRoutes() {
+		if old, ok := prevRoutes[route.GetID()]; !ok || !pb.Equal(old, route) {
+			delta.Routes = append(delta.Routes, route)
+		}
+	}
+	for id := range prevRoutes {
+		if _, ok := nextRoutes[id]; !ok {
+			delta.RemovedRoutes = append(delta.RemovedRoutes, id)
+		}
+	}
+	slices.Sort(delta.RemovedRoutes)
+
+	if !slices.EqualFunc(prev.GetFirewallRules(), next.GetFirewallRules(), equal[*proto.FirewallRule]) {
+		// an emptied list can only be told apart from an unchanged one by the flag
+		if len(next.GetFirewallRules()) == 0 && !next.GetFirewallRulesIsEmpty() {
+			return nil
+		}
+		delta.FirewallRules = next.GetFirewallRules()
+		delta.FirewallRulesIsEmpty = next.GetFirewallRulesIsEmpty()
+	}
+
+	if !slices.EqualFunc(prev.GetRoutesFirewallRules(), next.GetRoutesFirewallRules(), equal[*proto.RouteFirewallRule]) {
+		if len(next.GetRoutesFirewallRules()) == 0 && !next.GetRoutesFirewallRulesIsEmpty() {
+			return nil
+		}
+		delta.RoutesFirewallRules = next.GetRoutesFirewallRules()
+		delta.RoutesFirewallRulesIsEmpty = next.GetRoutesFirewallRulesIsEmpty()
+	}
+
+	return delta
+}
+
+// ApplyDelta returns the full network map the delta describes when applied to base. Full maps are returned as is.
+// The returned map shares the unchanged entries with base.
+func ApplyDelta(base, delta *proto.NetworkMap) (*proto.NetworkMap, error) {
+	if !delta.GetDelta() {
+		return delta, nil
+	}
+	if base == nil {
+		return nil, fmt.Errorf("%w: no base map for serial %d", ErrBaseMismatch, delta.GetBaseSerial())
+	}
+	if base.GetSerial() != delta.GetBaseSerial() {
+		return nil, fmt.Errorf("%w: delta is based on serial %d, current serial is %d",
+			ErrBaseMismatch, delta.GetBaseSerial(), base.GetSerial())
+	}
+
+	merged := &proto.NetworkMap{
+		Serial:                     delta.GetSerial(),
+		PeerConfig:                 delta.GetPeerConfig(),
+		DNSConfig:                  delta.GetDNSConfig(),
+		ForwardingRules:            delta.GetForwardingRules(),
+		SshAuth:                    delta.GetSshAuth(),
+		InboundExceptions:          delta.GetInboundExceptions(),
+		FirewallRules:              base.GetFirewallRules(),
+		FirewallRulesIsEmpty:       base.GetFirewallRulesIsEmpty(),
+		RoutesFirewallRules:        base.GetRoutesFirewallRules(),
+		RoutesFirewallRulesIsEmpty: base.GetRoutesFirewallRulesIsEmpty(),
+	}
+
+	replacedPeers := make(map[string]struct{}, len(delta.GetRemovedPeers())+len(delta.GetRemotePeers())+len(delta.GetOfflinePeers()))
+	for _, key := range delta.GetRemovedPeers() {
+		replacedPeers[key] = struct{}{}
+	}
+	for _, peer := range slices.Concat(delta.GetRemotePeers(), delta.GetOfflinePeers()) {
+		replacedPeers[peer.GetWgPubKey()] = struct{}{}
+	}
+	merged.RemotePeers = mergePeers(base.GetRemotePeers(), delta.GetRemotePeers(), replacedPeers)
+	merged.OfflinePeers = mergePeers(base.GetOfflinePeers(), delta.GetOfflinePeers(), replacedPeers)
+	merged.RemotePeersIsEmpty = len(merged.RemotePeers) == 0
+
+	replacedRoutes := make(map[string]struct{}, len(delta.GetRemovedRoutes())+len(delta.GetRoutes()))
+	for _, id := range delta.GetRemovedRoutes() {
+		replacedRoutes[id] = struct{}{}
+	}
+	for _, route := range delta.GetRoutes() {
+		replacedRoutes[route.GetID()] = struct{}{}
+	}
+	for _, route := range base.GetRoutes() {
+		if _, ok := replacedRoutes[route.GetID()]; !ok {
+			merged.Routes = append(merged.Routes, route)
+		}
+	}
+	merged.Routes = append(merged.Routes, delta.GetRoutes()...)
+
+	if len(delta.GetFirewallRules()) > 0 || delta.GetFirewallRulesIsEmpty() {
+		merged.FirewallRules = delta.GetFirewallRules()
+		merged.FirewallRulesIsEmpty = delta.GetFirewallRulesIsEmpty()
+	}
+	if len(delta.GetRoutesFirewallRules()) > 0 || delta.GetRoutesFirewallRulesIsEmpty() {
+		merged.RoutesFirewallRules = delta.GetRoutesFirewallRules()
+		merged.RoutesFirewallRulesIsEmpty = delta.GetRoutesFirewallRulesIsEmpty()
+	}
+
+	return merged, nil
+}
+
+func mergePeers(base, changed []*proto.RemotePeerConfig, replaced map[string]struct{}) []*proto.RemotePeerConfig {
+	merged := make([]*proto.RemotePeerConfig, 0, len(base)+len(changed))
+	for _, peer := range base {
+		if _, ok := replaced[peer.GetWgPubKey()]; !ok {
+			merged = append(merged, peer)
+		}
+	}
+	return append(merged, changed...)
+}
+
+// peersByKey indexes the remote and offline peers of the map, it fails on duplicate keys
+func peersByKey(nm *proto.NetworkMap) (map[string]peerEntry, bool) {
+	peers := make(map[string]peerEntry, len(nm.GetRemotePeers())+len(nm.GetOfflinePeers()))
+	for _, peer := range nm.GetRemotePeers() {
+		if _, ok := peers[peer.GetWgPubKey()]; ok {
+			return nil, false
+		}
+		peers[peer.GetWgPubKey()] = peerEntry{config: peer}
+	}
+	for _, peer := range nm.GetOfflinePeers() {
+		if _, ok := peers[peer.GetWgPubKey()]; ok {
+			return nil, false
+		}
+		peers[peer.GetWgPubKey()] = peerEntry{config: peer, offline: true}
+	}
+	return peers, true
+}
+
+// routesByID indexes the routes by ID, it fails on duplicate IDs
+func routesByID(routes []*proto.Route) (map[string]*proto.Route, bool) {
+	byID := make(map[string]*proto.Route, len(routes))
+	for _, route := range routes {
+		if _, ok := byID[route.GetID()]; ok {
+			return nil, false
+		}
+		byID[route.GetID()] = route
+	}
+	return byID, true
+}
+
+func equal[T pb.Message](a, b T) bool {
+	return pb.Equal(a, b)
+}
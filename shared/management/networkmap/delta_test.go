@@ -0,0 +1,130 @@
+package networkmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pb "google.golang.org/protobuf/proto"
+
+	"github.com/netbirdio/netbird/shared/management/proto"
+)
+
+func remotePeer(key, ip string) *proto.RemotePeerConfig {
+	return &proto.RemotePeerConfig{WgPubKey: key, AllowedIps: []string{ip}}
+}
+
+func baseNetworkMap() *proto.NetworkMap {
+	return &proto.NetworkMap{
+		Serial:     1,
+		PeerConfig: &proto.PeerConfig{Address: "100.64.0.1/16"},
+		RemotePeers: []*proto.RemotePeerConfig{
+			remotePeer("peer-a", "100.64.0.2/32"),
+			remotePeer("peer-b", "100.64.0.3/32"),
+			remotePeer("peer-c", "100.64.0.4/32"),
+		},
+		OfflinePeers: []*proto.RemotePeerConfig{remotePeer("peer-d", "100.64.0.5/32")},
+		Routes: []*proto.Route{
+			{ID: "route-1", Network: "10.0.0.0/24", Peer: "peer-a"},
+			{ID: "route-2", Network: "10.0.1.0/24", Peer: "peer-b"},
+		},
+		FirewallRules: []*proto.FirewallRule{{PeerIP: "100.64.0.2", Port: "22"}},
+	}
+}
+
+func TestDelta(t *testing.T) {
+	prev := baseNetworkMap()
+
+	next := baseNetworkMap()
+	next.Serial = 2
+	next.RemotePeers = []*proto.RemotePeerConfig{
+		remotePeer("peer-a", "100.64.0.2/32"),
+		remotePeer("peer-b", "100.64.0.30/32"),
+		remotePeer("peer-d", "100.64.0.5/32"),
+		remotePeer("peer-e", "100.64.0.6/32"),
+	}
+	next.OfflinePeers = nil
+	next.Routes = []*proto.Route{
+		{ID: "route-1", Network: "10.0.0.0/24", Peer: "peer-a"},
+		{ID: "route-3", Network: "10.0.2.0/24", Peer: "peer-e"},
+	}
+
+	delta := Delta(prev, next)
+	require.NotNil(t, delta)
+	assert.True(t, delta.GetDelta())
+	assert.Equal(t, uint64(1), delta.GetBaseSerial())
+	assert.Equal(t, uint64(2), delta.GetSerial())
+
+	var changed []string
+	for _, peer := range delta.GetRemotePeers() {
+		changed = append(changed, peer.GetWgPubKey())
+	}
+	assert.ElementsMatch(t, []string{"peer-b", "peer-d", "peer-e"}, changed, "unchanged peers are left out")
+	assert.Equal(t, []string{"peer-c"}, delta.GetRemovedPeers())
+	require.Len(t, delta.GetRoutes(), 1)
+	assert.Equal(t, "route-3", delta.GetRoutes()[0].GetID())
+	assert.Equal(t, []string{"route-2"}, delta.GetRemovedRoutes())
+	assert.Empty(t, delta.GetFirewallRules(), "unchanged rules are left out")
+
+	merged, err := ApplyDelta(prev, delta)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, next.GetRemotePeers(), merged.GetRemotePeers())
+	assert.Empty(t, merged.GetOfflinePeers())
+	assert.ElementsMatch(t, next.GetRoutes(), merged.GetRoutes())
+	assert.True(t, pb.Equal(next.GetPeerConfig(), merged.GetPeerConfig()))
+	assert.Equal(t, prev.GetFirewallRules(), merged.GetFirewallRules())
+	assert.Equal(t, uint64(2), merged.GetSerial())
+	assert.False(t, merged.GetDelta())
+}
+
+func TestDelta_FirewallRules(t *testing.T) {
+	prev := baseNetworkMap()
+
+	emptied := baseNetworkMap()
+	emptied.Serial = 2
+	emptied.FirewallRules = nil
+	emptied.FirewallRulesIsEmpty = true
+
+	delta := Delta(prev, emptied)
+	require.NotNil(t, delta)
+	merged, err := ApplyDelta(prev, delta)
+	require.NoError(t, err)
+	assert.Empty(t, merged.GetFirewallRules())
+	assert.True(t, merged.GetFirewallRulesIsEmpty())
+
+	emptied.FirewallRulesIsEmpty = false
+	assert.Nil(t, Delta(prev, emptied), "an emptied list without the flag needs a full map")
+}
+
+func TestDelta_Unsupported(t *testing.T) {
+	prev := baseNetworkMap()
+	assert.Nil(t, Delta(nil, prev))
+
+	duplicate := baseNetworkMap()
+	duplicate.OfflinePeers = append(duplicate.OfflinePeers, remotePeer("peer-a", "100.64.0.2/32"))
+	assert.Nil(t, Delta(prev, duplicate))
+
+	delta := Delta(prev, baseNetworkMap())
+	require.NotNil(t, delta)
+	assert.Nil(t, Delta(prev, delta), "deltas can't be diffed")
+}
+
+func TestApplyDelta_BaseMismatch(t *testing.T) {
+	prev := baseNetworkMap()
+	next := baseNetworkMap()
+	next.Serial = 3
+	delta := Delta(prev, next)
+	require.NotNil(t, delta)
+
+	other := baseNetworkMap()
+	other.Serial = 2
+	_, err := ApplyDelta(other, delta)
+	assert.ErrorIs(t, err, ErrBaseMismatch)
+
+	_, err = ApplyDelta(nil, delta)
+	assert.ErrorIs(t, err, ErrBaseMismatch)
+
+	full, err := ApplyDelta(nil, next)
+	require.NoError(t, err)
+	assert.Same(t, next, full, "full maps are returned as is")
+}
@@ -0,0 +1,41 @@
+package networkmap
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"strings"
+
+	pb "google.golang.org/protobuf/proto"
+
+	"github.com/netbirdio/netbird/shared/management/proto"
+)
+
+// Digest returns a SHA-256 digest of the network map. Maps with the same serial and content have the same digest,
+// regardless of the order of their peers and routes.
+func Digest(nm *proto.NetworkMap) ([]byte, error) {
+	if nm == nil {
+		return nil, fmt.Errorf("no network map")
+	}
+	if nm.GetDelta() {
+		return nil, fmt.Errorf("network map %d is a delta", nm.GetSerial())
+	}
+
+	sorted := pb.Clone(nm).(*proto.NetworkMap)
+	slices.SortFunc(sorted.RemotePeers, comparePeers)
+	slices.SortFunc(sorted.OfflinePeers, comparePeers)
+	slices.SortFunc(sorted.Routes, func(a, b *proto.Route) int {
+		return strings.Compare(a.GetID(), b.GetID())
+	})
+
+	data, err := pb.MarshalOptions{Deterministic: true}.Marshal(sorted)
+	if err != nil {
+		return nil, fmt.Errorf("marshal network map: %w", err)
+	}
+	digest := sha256.Sum256(data)
+	return digest[:], nil
+}
+
+func comparePeers(a, b *proto.RemotePeerConfig) int {
+	return strings.Compare(a.GetWgPubKey(), b.GetWgPubKey())
+}
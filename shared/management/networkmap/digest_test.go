@@ -0,0 +1,40 @@
+package networkmap
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/shared/management/proto"
+)
+
+func TestDigest(t *testing.T) {
+	digest, err := Digest(baseNetworkMap())
+	require.NoError(t, err)
+
+	reordered := baseNetworkMap()
+	slices.Reverse(reordered.RemotePeers)
+	slices.Reverse(reordered.Routes)
+	got, err := Digest(reordered)
+	require.NoError(t, err)
+	assert.Equal(t, digest, got, "the order of peers and routes doesn't change the digest")
+
+	changed := baseNetworkMap()
+	changed.RemotePeers[1].AllowedIps = []string{"100.64.0.30/32"}
+	got, err = Digest(changed)
+	require.NoError(t, err)
+	assert.NotEqual(t, digest, got)
+
+	bumped := baseNetworkMap()
+	bumped.Serial = 2
+	got, err = Digest(bumped)
+	require.NoError(t, err)
+	assert.NotEqual(t, digest, got, "the serial is the base of the following deltas")
+
+	_, err = Digest(&proto.NetworkMap{Serial: 2, Delta: true, BaseSerial: 1})
+	assert.Error(t, err)
+	_, err = Digest(nil)
+	assert.Error(t, err)
+}
@@ -3,7 +3,9 @@ package hmac
 import (
 	"encoding/base64"
 	"fmt"
+	"strconv"
 	"sync"
+	"time"
 
 	v2 "github.com/netbirdio/netbird/shared/relay/auth/hmac/v2"
 )
@@ -11,8 +13,9 @@ import (
 // TokenStore is a simple in-memory store for token
 // With this can update the token in thread safe way
 type TokenStore struct {
-	mu    sync.Mutex
-	token []byte
+	mu        sync.Mutex
+	token     []byte
+	expiresAt time.Time
 }
 
 func (a *TokenStore) UpdateToken(token *Token) error {
@@ -34,6 +37,12 @@ func (a *TokenStore) UpdateToken(token *Token) error {
 	}
 
 	a.token = tok.Marshal()
+
+	// the payload is the unix timestamp the token expires at
+	a.expiresAt = time.Time{}
+	if ts, err := strconv.ParseInt(token.Payload, 10, 64); err == nil {
+		a.expiresAt = time.Unix(ts, 0)
+	}
 	return nil
 }
 
@@ -42,3 +51,10 @@ func (a *TokenStore) TokenBinary() []byte {
 	defer a.mu.Unlock()
 	return a.token
 }
+
+// ExpiresAt returns the expiry of the stored token, false if there is no token or its expiry is unknown
+func (a *TokenStore) ExpiresAt() (time.Time, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.expiresAt, !a.expiresAt.IsZero()
+}
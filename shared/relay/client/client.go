@@ -11,6 +11,7 @@ import (
 
 	auth "github.com/netbirdio/netbird/shared/relay/auth/hmac"
 	"github.com/netbirdio/netbird/shared/relay/client/dialer"
+	relayNet "github.com/netbirdio/netbird/shared/relay/client/dialer/net"
 	"github.com/netbirdio/netbird/shared/relay/healthcheck"
 	"github.com/netbirdio/netbird/shared/relay/messages"
 )
@@ -501,6 +502,14 @@ func (c *Client) writeTo(connReference *Conn, dstID messages.PeerID, payload []b
 	return len(payload), err
 }
 
+func (c *Client) pathStats() (relayNet.PathStats, bool) {
+	statsConn, ok := c.relayConn.(relayNet.PathStatsConn)
+	if !ok {
+		return relayNet.PathStats{}, false
+	}
+	return statsConn.PathStats(), true
+}
+
 func (c *Client) listenForStopEvents(ctx context.Context, hc *healthcheck.Receiver, conn net.Conn, internalStopFlag *internalStopFlag) {
 	for {
 		select {
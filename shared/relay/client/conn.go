@@ -4,6 +4,7 @@ import (
 	"net"
 	"time"
 
+	relayNet "github.com/netbirdio/netbird/shared/relay/client/dialer/net"
 	"github.com/netbirdio/netbird/shared/relay/messages"
 )
 
@@ -46,6 +47,12 @@ func (c *Conn) Read(b []byte) (n int, err error) {
 	return n, nil
 }
 
+// PathStats returns the measurements of the network path to the relay server. It returns false when the transport
+// doesn't measure the path, like WebSocket.
+func (c *Conn) PathStats() (relayNet.PathStats, bool) {
+	return c.client.pathStats()
+}
+
 func (c *Conn) Close() error {
 	return c.client.closeConn(c, c.dstID)
 }
@@ -0,0 +1,19 @@
+package net
+
+import "time"
+
+// PathStats are the measurements of the network path to the relay server
+type PathStats struct {
+	// SmoothedRTT is the moving average of the round trip time
+	SmoothedRTT time.Duration
+	// MinRTT is the lowest round trip time observed, the delay of the path without queuing
+	MinRTT time.Duration
+	// PacketsSent and PacketsLost are counted since the connection was established
+	PacketsSent uint64
+	PacketsLost uint64
+}
+
+// PathStatsConn is implemented by the relay server connections that measure their network path
+type PathStatsConn interface {
+	PathStats() PathStats
+}
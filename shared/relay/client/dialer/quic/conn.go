@@ -84,6 +84,17 @@ func (c *Conn) SetDeadline(t time.Time) error {
 	return nil
 }
 
+// PathStats returns the RTT and loss measured by the QUIC connection
+func (c *Conn) PathStats() netErr.PathStats {
+	stats := c.session.ConnectionStats()
+	return netErr.PathStats{
+		SmoothedRTT: stats.SmoothedRTT,
+		MinRTT:      stats.MinRTT,
+		PacketsSent: stats.PacketsSent,
+		PacketsLost: stats.PacketsLost,
+	}
+}
+
 func (c *Conn) Close() error {
 	return c.session.CloseWithError(0, "normal closure")
 }
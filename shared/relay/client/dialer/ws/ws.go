@@ -15,6 +15,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	nbnet "github.com/netbirdio/netbird/client/net"
+	"github.com/netbirdio/netbird/client/net/proxy"
 	"github.com/netbirdio/netbird/shared/relay"
 	"github.com/netbirdio/netbird/util/embeddedroots"
 )
@@ -74,6 +75,7 @@ func httpClientNbDialer() *http.Client {
 	}
 
 	customTransport := &http.Transport{
+		Proxy: proxy.HTTPProxy,
 		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
 			return customDialer.DialContext(ctx, network, addr)
 		},
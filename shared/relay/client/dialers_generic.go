@@ -3,7 +3,11 @@
 package client
 
 import (
+	"net"
+	"net/url"
+
 	"github.com/netbirdio/netbird/client/iface"
+	"github.com/netbirdio/netbird/client/net/proxy"
 	"github.com/netbirdio/netbird/shared/relay/client/dialer"
 	"github.com/netbirdio/netbird/shared/relay/client/dialer/quic"
 	"github.com/netbirdio/netbird/shared/relay/client/dialer/ws"
@@ -15,5 +19,27 @@ func (c *Client) getDialers() []dialer.DialeFn {
 		c.log.Infof("MTU %d exceeds default (%d), forcing WebSocket transport to avoid DATAGRAM frame size issues", c.mtu, iface.DefaultMTU)
 		return []dialer.DialeFn{ws.Dialer{}}
 	}
+	if c.viaProxy() {
+		c.log.Infof("connecting to the relay server through a proxy, using WebSocket transport")
+		return []dialer.DialeFn{ws.Dialer{}}
+	}
 	return []dialer.DialeFn{quic.Dialer{}, ws.Dialer{}}
 }
+
+// viaProxy reports whether the WebSocket connection to the relay server goes through a proxy, QUIC can't pass it
+func (c *Client) viaProxy() bool {
+	serverURL, err := url.Parse(c.connectionURL)
+	if err != nil {
+		return false
+	}
+	secure := serverURL.Scheme == "rels"
+	host := serverURL.Host
+	if serverURL.Port() == "" {
+		if secure {
+			host = net.JoinHostPort(serverURL.Hostname(), "443")
+		} else {
+			host = net.JoinHostPort(serverURL.Hostname(), "80")
+		}
+	}
+	return proxy.Enabled(host, secure)
+}
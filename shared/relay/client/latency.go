@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	nbnet "github.com/netbirdio/netbird/client/net"
+)
+
+// MeasureRTT measures the round trip time to the relay server as the time the TCP handshake takes. The connection
+// bypasses the tunnel, like the relay connection itself.
+func MeasureRTT(ctx context.Context, serverURL string) (time.Duration, error) {
+	address, err := relayHostPort(serverURL)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	conn, err := nbnet.NewDialer().DialContext(ctx, "tcp", address)
+	if err != nil {
+		return 0, fmt.Errorf("dial %s: %w", address, err)
+	}
+	rtt := time.Since(start)
+	_ = conn.Close()
+	return rtt, nil
+}
+
+// relayHostPort returns the host and port of a rel:// or rels:// URL
+func relayHostPort(serverURL string) (string, error) {
+	var host, defaultPort string
+	switch {
+	case strings.HasPrefix(serverURL, "rels://"):
+		host, defaultPort = strings.TrimPrefix(serverURL, "rels://"), "443"
+	case strings.HasPrefix(serverURL, "rel://"):
+		host, defaultPort = strings.TrimPrefix(serverURL, "rel://"), "80"
+	default:
+		return "", fmt.Errorf("unsupported scheme: %s", serverURL)
+	}
+	host, _, _ = strings.Cut(host, "/")
+
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host, nil
+	}
+	return net.JoinHostPort(strings.Trim(host, "[]"), defaultPort), nil
+}
@@ -0,0 +1,31 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayHostPort(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "rels://relay.example.com", want: "relay.example.com:443"},
+		{url: "rels://relay.example.com:8443", want: "relay.example.com:8443"},
+		{url: "rel://relay.example.com/relay", want: "relay.example.com:80"},
+		{url: "rel://[2001:db8::1]", want: "[2001:db8::1]:80"},
+		{url: "rels://[2001:db8::1]:443", want: "[2001:db8::1]:443"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got, err := relayHostPort(tt.url)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := relayHostPort("https://relay.example.com")
+	assert.Error(t, err)
+}
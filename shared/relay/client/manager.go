@@ -207,6 +207,69 @@ func (m *Manager) ServerURLs() []string {
 	return m.serverPicker.ServerURLs.Load().([]string)
 }
 
+// HomeServerURL returns the URL of the relay server the home Relay client connects to
+func (m *Manager) HomeServerURL() (string, error) {
+	m.relayClientMu.RLock()
+	defer m.relayClientMu.RUnlock()
+
+	if m.relayClient == nil {
+		return "", ErrRelayClientNotConnected
+	}
+	return m.relayClient.connectionURL, nil
+}
+
+// SetPreferredServer makes the manager connect to the server first whenever it picks a home relay server. An empty
+// URL clears the preference.
+func (m *Manager) SetPreferredServer(serverURL string) {
+	m.serverPicker.PreferredURL.Store(serverURL)
+}
+
+// SwitchServer connects to the relay server and makes it the home relay server. The connections through the previous
+// home server are closed, their peers reconnect through the new one.
+func (m *Manager) SwitchServer(ctx context.Context, serverURL string) error {
+	if !m.running {
+		return fmt.Errorf("manager is not serving")
+	}
+
+	client := NewClient(serverURL, m.tokenStore, m.peerID, m.mtu)
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect to %s: %w", serverURL, err)
+	}
+
+	m.relayClientMu.Lock()
+	previous := m.relayClient
+	m.relayClient = client
+	m.relayClient.SetOnDisconnectListener(m.onServerDisconnected)
+	m.relayClientMu.Unlock()
+
+	if previous != nil {
+		previous.SetOnDisconnectListener(nil)
+		if err := previous.Close(); err != nil {
+			log.Debugf("failed to close the previous home Relay client: %v", err)
+		}
+		m.notifyOnDisconnectListeners(previous.connectionURL)
+	}
+
+	log.Infof("switched the home Relay server to %s", serverURL)
+	m.onServerConnected()
+	return nil
+}
+
+// Reconnect renews the connection to the home relay server and closes the ones to the foreign relay servers, e.g.
+// after a network change left them on a gone network path. The peers of the closed connections are notified and
+// reconnect through new ones, the foreign servers are connected again on demand.
+func (m *Manager) Reconnect(ctx context.Context) error {
+	serverURL, err := m.HomeServerURL()
+	if err != nil {
+		return err
+	}
+	if err := m.SwitchServer(ctx, serverURL); err != nil {
+		return err
+	}
+	m.closeForeignRelays()
+	return nil
+}
+
 // HasRelayAddress returns true if the manager is serving. With this method can check if the peer can communicate with
 // Relay service.
 func (m *Manager) HasRelayAddress() bool {
@@ -223,6 +286,11 @@ func (m *Manager) UpdateToken(token *relayAuth.Token) error {
 	return m.tokenStore.UpdateToken(token)
 }
 
+// TokenExpiresAt returns the expiry of the relay auth token, false if no token was received yet.
+func (m *Manager) TokenExpiresAt() (time.Time, bool) {
+	return m.tokenStore.ExpiresAt()
+}
+
 func (m *Manager) openConnVia(ctx context.Context, serverAddress, peerKey string) (net.Conn, error) {
 	// check if already has a connection to the desired relay server
 	m.relayClientsMutex.RLock()
@@ -258,6 +326,49 @@ func (m *Manager) openConnVia(ctx context.Context, serverAddress, peerKey string
 	m.relayClients[serverAddress] = rt
 	m.relayClientsMutex.Unlock()
 
+	relayClient, err := m.connectForeign(rt, serverAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := relayClient.OpenConn(ctx, peerKey)
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// Preconnect connects to a foreign relay server before a peer connection is opened through it, so the peer
+// connection doesn't wait for the relay handshake. The connection is cleaned up if it stays unused.
+func (m *Manager) Preconnect(serverAddress string) error {
+	m.relayClientMu.RLock()
+	if m.relayClient == nil {
+		m.relayClientMu.RUnlock()
+		return ErrRelayClientNotConnected
+	}
+	foreign, err := m.isForeignServer(serverAddress)
+	m.relayClientMu.RUnlock()
+	if err != nil || !foreign {
+		return err
+	}
+
+	m.relayClientsMutex.Lock()
+	if _, ok := m.relayClients[serverAddress]; ok {
+		m.relayClientsMutex.Unlock()
+		return nil
+	}
+	rt := NewRelayTrack()
+	rt.Lock()
+	m.relayClients[serverAddress] = rt
+	m.relayClientsMutex.Unlock()
+
+	_, err = m.connectForeign(rt, serverAddress)
+	return err
+}
+
+// connectForeign connects the relay client of the locked track and unlocks it. The track is deleted if the
+// connection fails.
+func (m *Manager) connectForeign(rt *RelayTrack, serverAddress string) (*Client, error) {
 	relayClient := NewClient(serverAddress, m.tokenStore, m.peerID, m.mtu)
 	err := relayClient.Connect(m.ctx)
 	if err != nil {
@@ -272,12 +383,7 @@ func (m *Manager) openConnVia(ctx context.Context, serverAddress, peerKey string
 	relayClient.SetOnDisconnectListener(m.onServerDisconnected)
 	rt.relayClient = relayClient
 	rt.Unlock()
-
-	conn, err := relayClient.OpenConn(ctx, peerKey)
-	if err != nil {
-		return nil, err
-	}
-	return conn, nil
+	return relayClient, nil
 }
 
 func (m *Manager) onServerConnected() {
@@ -378,6 +484,25 @@ func (m *Manager) cleanUpUnusedRelays() {
 	}
 }
 
+func (m *Manager) closeForeignRelays() {
+	m.relayClientsMutex.Lock()
+	defer m.relayClientsMutex.Unlock()
+
+	for addr, rt := range m.relayClients {
+		rt.Lock()
+		if rt.relayClient != nil {
+			rt.relayClient.SetOnDisconnectListener(nil)
+			go func(client *Client) {
+				_ = client.Close()
+			}(rt.relayClient)
+		}
+		delete(m.relayClients, addr)
+		rt.Unlock()
+		m.notifyOnDisconnectListeners(addr)
+		log.Debugf("closed the foreign relay server connection: %s", addr)
+	}
+}
+
 func (m *Manager) addListener(serverAddress string, onClosedListener OnServerCloseListener) {
 	m.listenerLock.Lock()
 	defer m.listenerLock.Unlock()
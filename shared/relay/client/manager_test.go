@@ -460,6 +460,131 @@ func TestNotifierDoubleAdd(t *testing.T) {
 
 }
 
+func TestSwitchServer(t *testing.T) {
+	ctx := context.Background()
+
+	var urls []string
+	for _, address := range []string{"localhost:52601", "localhost:52602"} {
+		lstCfg := server.ListenerConfig{Address: address}
+		srv, err := server.NewServer(newManagerTestServerConfig(lstCfg.Address))
+		if err != nil {
+			t.Fatalf("failed to create server: %s", err)
+		}
+		errChan := make(chan error, 1)
+		go func() {
+			if err := srv.Listen(lstCfg); err != nil {
+				errChan <- err
+			}
+		}()
+		defer func() {
+			if err := srv.Shutdown(ctx); err != nil {
+				t.Errorf("failed to close server: %s", err)
+			}
+		}()
+		if err := waitForServerToStart(errChan); err != nil {
+			t.Fatalf("failed to start server: %s", err)
+		}
+		urls = append(urls, toURL(lstCfg)...)
+	}
+
+	mCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	mgr := NewManager(mCtx, urls[:1], "alice", iface.DefaultMTU)
+	if err := mgr.SwitchServer(ctx, urls[1]); err == nil {
+		t.Fatalf("expected error when switching before serving")
+	}
+	if err := mgr.Serve(); err != nil {
+		t.Fatalf("failed to serve manager: %s", err)
+	}
+
+	homeAddr, err := mgr.RelayInstanceAddress()
+	if err != nil {
+		t.Fatalf("failed to get relay address: %s", err)
+	}
+	closed := make(chan struct{}, 1)
+	if err := mgr.AddCloseListener(homeAddr, func() { closed <- struct{}{} }); err != nil {
+		t.Fatalf("failed to add close listener: %s", err)
+	}
+
+	mgr.UpdateServerURLs(urls)
+	if err := mgr.SwitchServer(ctx, urls[1]); err != nil {
+		t.Fatalf("failed to switch server: %s", err)
+	}
+
+	home, err := mgr.HomeServerURL()
+	if err != nil {
+		t.Fatalf("failed to get home server: %s", err)
+	}
+	if home != urls[1] {
+		t.Errorf("expected home server %s, got %s", urls[1], home)
+	}
+	if !mgr.Ready() {
+		t.Errorf("expected the manager to be ready after the switch")
+	}
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Errorf("close listener of the previous home server was not called")
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	ctx := context.Background()
+
+	lstCfg := server.ListenerConfig{Address: "localhost:52603"}
+	srv, err := server.NewServer(newManagerTestServerConfig(lstCfg.Address))
+	if err != nil {
+		t.Fatalf("failed to create server: %s", err)
+	}
+	errChan := make(chan error, 1)
+	go func() {
+		if err := srv.Listen(lstCfg); err != nil {
+			errChan <- err
+		}
+	}()
+	defer func() {
+		if err := srv.Shutdown(ctx); err != nil {
+			t.Errorf("failed to close server: %s", err)
+		}
+	}()
+	if err := waitForServerToStart(errChan); err != nil {
+		t.Fatalf("failed to start server: %s", err)
+	}
+
+	mCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	mgr := NewManager(mCtx, toURL(lstCfg), "alice", iface.DefaultMTU)
+	if err := mgr.Reconnect(ctx); err == nil {
+		t.Fatalf("expected error when reconnecting before serving")
+	}
+	if err := mgr.Serve(); err != nil {
+		t.Fatalf("failed to serve manager: %s", err)
+	}
+
+	homeAddr, err := mgr.RelayInstanceAddress()
+	if err != nil {
+		t.Fatalf("failed to get relay address: %s", err)
+	}
+	closed := make(chan struct{}, 1)
+	if err := mgr.AddCloseListener(homeAddr, func() { closed <- struct{}{} }); err != nil {
+		t.Fatalf("failed to add close listener: %s", err)
+	}
+
+	if err := mgr.Reconnect(ctx); err != nil {
+		t.Fatalf("failed to reconnect: %s", err)
+	}
+	if !mgr.Ready() {
+		t.Errorf("expected the manager to be ready after the reconnect")
+	}
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Errorf("close listener of the previous connection was not called")
+	}
+}
+
 func toURL(address server.ListenerConfig) []string {
 	return []string{"rel://" + address.Address}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sync/atomic"
 	"time"
 
@@ -13,8 +14,9 @@ import (
 )
 
 const (
-	maxConcurrentServers     = 7
-	defaultConnectionTimeout = 30 * time.Second
+	maxConcurrentServers       = 7
+	defaultConnectionTimeout   = 30 * time.Second
+	preferredConnectionTimeout = 10 * time.Second
 )
 
 type connResult struct {
@@ -29,12 +31,18 @@ type ServerPicker struct {
 	PeerID            string
 	MTU               uint16
 	ConnectionTimeout time.Duration
+	// PreferredURL is connected first if it is one of the ServerURLs, the others are only tried if it fails
+	PreferredURL atomic.Value
 }
 
 func (sp *ServerPicker) PickServer(parentCtx context.Context) (*Client, error) {
 	ctx, cancel := context.WithTimeout(parentCtx, sp.ConnectionTimeout)
 	defer cancel()
 
+	if client, ok := sp.pickPreferred(ctx); ok {
+		return client, nil
+	}
+
 	totalServers := len(sp.ServerURLs.Load().([]string))
 
 	connResultChan := make(chan connResult, totalServers)
@@ -67,6 +75,24 @@ func (sp *ServerPicker) PickServer(parentCtx context.Context) (*Client, error) {
 	}
 }
 
+func (sp *ServerPicker) pickPreferred(parentCtx context.Context) (*Client, bool) {
+	preferred, _ := sp.PreferredURL.Load().(string)
+	if preferred == "" || !slices.Contains(sp.ServerURLs.Load().([]string), preferred) {
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(parentCtx, preferredConnectionTimeout)
+	defer cancel()
+
+	relayClient := NewClient(preferred, sp.TokenStore, sp.PeerID, sp.MTU)
+	if err := relayClient.Connect(ctx); err != nil {
+		log.Warnf("failed to connect to the preferred Relay server %s, trying the others: %v", preferred, err)
+		return nil, false
+	}
+	log.Infof("chosen preferred home Relay server: %s", preferred)
+	return relayClient, true
+}
+
 func (sp *ServerPicker) startConnection(ctx context.Context, resultChan chan connResult, url string) {
 	log.Infof("try to connecting to relay server: %s", url)
 	relayClient := NewClient(url, sp.TokenStore, sp.PeerID, sp.MTU)
@@ -20,9 +20,25 @@ type Status string
 const StreamConnected Status = "Connected"
 const StreamDisconnected Status = "Disconnected"
 
+// Feature IDs announced in the featuresSupported field of offers and answers. Peers negotiate per connection
+// behavior on them instead of comparing agent versions. IDs must stay below 64 and must never be reused.
 const (
 	// DirectCheck indicates support to direct mode checks
 	DirectCheck uint32 = 1
+	// FeatureRosenpass indicates that the peer runs Rosenpass for this connection
+	FeatureRosenpass uint32 = 2
+	// FeatureLazyConnection indicates that the peer can be connected lazily and handles GO_IDLE messages
+	FeatureLazyConnection uint32 = 3
+	// FeatureFlowSampling indicates that the peer samples traffic flows, reserved
+	FeatureFlowSampling uint32 = 4
+	// FeatureMultiPath indicates that the peer can bond a connection over two uplinks with an ICE session on each
+	FeatureMultiPath uint32 = 5
+	// FeatureTCPFallback indicates that the peer can fall back to TCP transports, reserved
+	FeatureTCPFallback uint32 = 6
+	// FeatureWakeOnLAN indicates that the peer handles WAKE messages and relays them to its local network
+	FeatureWakeOnLAN uint32 = 7
+	// FeatureRouteFailover indicates that the peer handles ROUTE_FAILOVER messages as a routing peer
+	FeatureRouteFailover uint32 = 8
 )
 
 type Client interface {
@@ -164,6 +164,40 @@ var _ = Describe("GrpcClient", func() {
 			})
 		})
 
+		Context("with a resume token of a disconnected session", func() {
+			It("should receive the messages sent while disconnected", func() {
+
+				client := createRawSignalClient(addr)
+				md := metadata.New(map[string]string{sigProto.HeaderId: "peer"})
+				streamCtx, cancelStream := context.WithCancel(metadata.NewOutgoingContext(context.Background(), md))
+				stream, err := client.ConnectStream(streamCtx)
+				Expect(err).To(BeNil())
+				header, err := stream.Header()
+				Expect(err).To(BeNil())
+				Expect(header.Get(sigProto.HeaderResumed)).To(BeEmpty())
+				tokens := header.Get(sigProto.HeaderResumeToken)
+				Expect(tokens).To(HaveLen(1))
+				cancelStream()
+
+				msg := &sigProto.EncryptedMessage{Key: "other", RemoteKey: "peer", Body: []byte("offer")}
+				Eventually(func() error {
+					_, err := client.Send(context.Background(), msg)
+					return err
+				}, 5*time.Second).Should(Succeed())
+
+				md.Set(sigProto.HeaderResumeToken, tokens[0])
+				stream, err = client.ConnectStream(metadata.NewOutgoingContext(context.Background(), md))
+				Expect(err).To(BeNil())
+				header, err = stream.Header()
+				Expect(err).To(BeNil())
+				Expect(header.Get(sigProto.HeaderResumed)).NotTo(BeEmpty())
+
+				received, err := stream.Recv()
+				Expect(err).To(BeNil())
+				Expect(received.GetBody()).To(Equal([]byte("offer")))
+			})
+		})
+
 	})
 
 })
@@ -29,6 +29,12 @@ type ConnStateNotifier interface {
 	MarkSignalConnected()
 }
 
+// LatencyNotifier is implemented by a ConnStateNotifier that also tracks the round trip time of the messages sent
+// through signal
+type LatencyNotifier interface {
+	UpdateSignalLatency(time.Duration)
+}
+
 // GrpcClient Wraps the Signal Exchange Service gRpc client
 type GrpcClient struct {
 	key        wgtypes.Key
@@ -47,6 +53,10 @@ type GrpcClient struct {
 
 	onReconnectedListenerFn func()
 
+	// resume keeps the token of the current session, sent on reconnect to receive the messages held while
+	// disconnected
+	resume *SessionResume
+
 	decryptionWorker       *Worker
 	decryptionWorkerCancel context.CancelFunc
 	decryptionWg           sync.WaitGroup
@@ -81,9 +91,16 @@ func NewClient(ctx context.Context, addr string, key wgtypes.Key, tlsEnabled boo
 		mux:                   sync.Mutex{},
 		status:                StreamDisconnected,
 		connStateCallbackLock: sync.RWMutex{},
+		resume:                NewSessionResume(),
 	}, nil
 }
 
+// SetSessionResume sets the store of the session token, a store shared with the previous client resumes its
+// session. It must be called before Receive.
+func (c *GrpcClient) SetSessionResume(resume *SessionResume) {
+	c.resume = resume
+}
+
 func (c *GrpcClient) StreamConnected() bool {
 	return c.status == StreamConnected
 }
@@ -225,6 +242,9 @@ func (c *GrpcClient) connect(ctx context.Context, key string) (proto.SignalExcha
 
 	// add key fingerprint to the request header to be identified on the server side
 	md := metadata.New(map[string]string{proto.HeaderId: key})
+	if token := c.resume.get(); token != "" {
+		md.Set(proto.HeaderResumeToken, token)
+	}
 	metaCtx := metadata.NewOutgoingContext(ctx, md)
 	stream, err := c.realClient.ConnectStream(metaCtx, grpc.WaitForReady(true))
 	c.stream = stream
@@ -241,6 +261,16 @@ func (c *GrpcClient) connect(ctx context.Context, key string) (proto.SignalExcha
 		return nil, fmt.Errorf("didn't receive a registration header from the Signal server whille connecting to the streams")
 	}
 
+	if len(header.Get(proto.HeaderResumed)) > 0 {
+		log.Infof("resumed the Signal Service session")
+	}
+	// servers without session support don't return a token
+	var token string
+	if tokens := header.Get(proto.HeaderResumeToken); len(tokens) > 0 {
+		token = tokens[0]
+	}
+	c.resume.set(token)
+
 	return stream, nil
 }
 
@@ -380,7 +410,9 @@ func (c *GrpcClient) Send(msg *proto.Message) error {
 		}
 		ctx, cancel := context.WithTimeout(c.ctx, attemptTimeout)
 
+		start := time.Now()
 		_, err = c.realClient.Send(ctx, encryptedMessage)
+		latency := time.Since(start)
 
 		cancel()
 
@@ -389,6 +421,7 @@ func (c *GrpcClient) Send(msg *proto.Message) error {
 		}
 
 		if err == nil {
+			c.notifyLatency(latency)
 			return nil
 		}
 	}
@@ -454,6 +487,15 @@ func (c *GrpcClient) notifyDisconnected(err error) {
 	c.connStateCallback.MarkSignalDisconnected(err)
 }
 
+func (c *GrpcClient) notifyLatency(latency time.Duration) {
+	c.connStateCallbackLock.RLock()
+	defer c.connStateCallbackLock.RUnlock()
+
+	if notifier, ok := c.connStateCallback.(LatencyNotifier); ok {
+		notifier.UpdateSignalLatency(latency)
+	}
+}
+
 func (c *GrpcClient) notifyConnected() {
 	c.connStateCallbackLock.RLock()
 	defer c.connStateCallbackLock.RUnlock()
@@ -0,0 +1,27 @@
+package client
+
+import "sync"
+
+// SessionResume keeps the token of the Signal session. It outlives the clients, the stream of a new client resumes
+// the session of the previous one and receives the messages held for the peer while it was disconnected.
+type SessionResume struct {
+	mu    sync.Mutex
+	token string
+}
+
+// NewSessionResume returns a store without a session to resume
+func NewSessionResume() *SessionResume {
+	return &SessionResume{}
+}
+
+func (r *SessionResume) get() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.token
+}
+
+func (r *SessionResume) set(token string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.token = token
+}
@@ -3,3 +3,10 @@ package proto
 // protocol constants, field names that can be used by both client and server
 const HeaderId = "x-wiretrustee-peer-id"
 const HeaderRegistered = "x-wiretrustee-peer-registered"
+
+// HeaderResumeToken carries the token of the peer's session, the server returns a new one on every connect and the
+// client sends the previous one to resume its session after a reconnect
+const HeaderResumeToken = "x-netbird-resume-token"
+
+// HeaderResumed is set by the server if the session was resumed and the messages held for the peer are delivered
+const HeaderResumed = "x-netbird-resumed"
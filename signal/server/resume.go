@@ -0,0 +1,139 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/shared/signal/proto"
+)
+
+const (
+	// resumeGracePeriod is how long the messages to a disconnected peer are held for it to resume its session
+	resumeGracePeriod = 30 * time.Second
+	// maxHeldMessages limits the messages held for a disconnected peer, the later ones are dropped
+	maxHeldMessages = 64
+)
+
+// session is the resumable session of a peer. While the peer is disconnected the messages to it are held, a
+// reconnect with the session's token receives them.
+type session struct {
+	token  string
+	parked bool
+	held   []*proto.EncryptedMessage
+}
+
+// sessionShards is the number of independently locked shards of the sessions, the streams of different peers
+// rarely wait for each other
+const sessionShards = 32
+
+// sessions keeps the resumable sessions of the peers by peer ID
+type sessions struct {
+	shards [sessionShards]sessionShard
+	// parked counts the parked sessions, hold doesn't lock while no peer is parked
+	parked atomic.Int64
+	grace  time.Duration
+}
+
+type sessionShard struct {
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+func newSessions() *sessions {
+	s := &sessions{grace: resumeGracePeriod}
+	for i := range s.shards {
+		s.shards[i].sessions = make(map[string]*session)
+	}
+	return s
+}
+
+func (s *sessions) shard(peerID string) *sessionShard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(peerID))
+	return &s.shards[h.Sum32()%sessionShards]
+}
+
+// open starts a new session for the peer and returns its token. If the token of the peer's previous session is
+// given and the session is still held, the messages held for the peer are returned and resumed is true.
+func (s *sessions) open(peerID, resumeToken string) (token string, held []*proto.EncryptedMessage, resumed bool, err error) {
+	token, err = newSessionToken()
+	if err != nil {
+		return "", nil, false, err
+	}
+
+	shard := s.shard(peerID)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	if prev, ok := shard.sessions[peerID]; ok {
+		if prev.parked {
+			s.parked.Add(-1)
+		}
+		if resumeToken != "" && prev.token == resumeToken {
+			held, resumed = prev.held, true
+		}
+	}
+	shard.sessions[peerID] = &session{token: token}
+	return token, held, resumed, nil
+}
+
+// park holds the messages to the peer after its stream with the session token closed. The session ends after the
+// grace period. A session replaced by a newer stream of the peer isn't parked.
+func (s *sessions) park(peerID, token string) {
+	shard := s.shard(peerID)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	sess, ok := shard.sessions[peerID]
+	if !ok || sess.token != token || sess.parked {
+		return
+	}
+	sess.parked = true
+	s.parked.Add(1)
+
+	time.AfterFunc(s.grace, func() {
+		shard.mu.Lock()
+		defer shard.mu.Unlock()
+		if shard.sessions[peerID] == sess {
+			delete(shard.sessions, peerID)
+			s.parked.Add(-1)
+			if len(sess.held) > 0 {
+				log.Debugf("dropped %d messages held for peer [%s], the session wasn't resumed", len(sess.held), peerID)
+			}
+		}
+	})
+}
+
+// hold keeps the message if its destination peer is disconnected and may resume its session. It reports whether
+// the message was held. While no peer is parked it returns without locking.
+func (s *sessions) hold(msg *proto.EncryptedMessage) bool {
+	if s.parked.Load() == 0 {
+		return false
+	}
+
+	shard := s.shard(msg.RemoteKey)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	sess, ok := shard.sessions[msg.RemoteKey]
+	if !ok || !sess.parked || len(sess.held) >= maxHeldMessages {
+		return false
+	}
+	sess.held = append(sess.held, msg)
+	return true
+}
+
+func newSessionToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("generate session token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/shared/signal/proto"
+)
+
+func TestSessions_Resume(t *testing.T) {
+	s := newSessions()
+	msg := &proto.EncryptedMessage{Key: "peer-b", RemoteKey: "peer-a"}
+
+	token, held, resumed, err := s.open("peer-a", "")
+	require.NoError(t, err)
+	assert.False(t, resumed)
+	assert.Empty(t, held)
+	assert.False(t, s.hold(msg), "messages to connected peers aren't held")
+
+	s.park("peer-a", token)
+	assert.True(t, s.hold(msg))
+
+	_, held, resumed, err = s.open("peer-a", "unknown")
+	require.NoError(t, err)
+	assert.False(t, resumed, "a wrong token starts a new session")
+	assert.Empty(t, held)
+}
+
+func TestSessions_HeldMessages(t *testing.T) {
+	s := newSessions()
+	msg := &proto.EncryptedMessage{Key: "peer-b", RemoteKey: "peer-a"}
+
+	token, _, _, err := s.open("peer-a", "")
+	require.NoError(t, err)
+	s.park("peer-a", token)
+	for i := 0; i < maxHeldMessages; i++ {
+		require.True(t, s.hold(msg))
+	}
+	assert.False(t, s.hold(msg), "the held messages are limited")
+
+	newToken, held, resumed, err := s.open("peer-a", token)
+	require.NoError(t, err)
+	assert.True(t, resumed)
+	assert.Len(t, held, maxHeldMessages)
+	assert.NotEqual(t, token, newToken)
+
+	s.park("peer-a", token)
+	assert.False(t, s.hold(msg), "a replaced session isn't parked")
+}
+
+func TestSessions_GracePeriod(t *testing.T) {
+	s := newSessions()
+	s.grace = 10 * time.Millisecond
+
+	token, _, _, err := s.open("peer-a", "")
+	require.NoError(t, err)
+	s.park("peer-a", token)
+
+	assert.Eventually(t, func() bool {
+		return !s.hold(&proto.EncryptedMessage{RemoteKey: "peer-a"})
+	}, time.Second, 10*time.Millisecond)
+
+	_, _, resumed, err := s.open("peer-a", token)
+	require.NoError(t, err)
+	assert.False(t, resumed, "the session ended with the grace period")
+}
+
+func TestSessions_ParkedCount(t *testing.T) {
+	s := newSessions()
+	msg := &proto.EncryptedMessage{Key: "peer-b", RemoteKey: "peer-a"}
+
+	token, _, _, err := s.open("peer-a", "")
+	require.NoError(t, err)
+	assert.Zero(t, s.parked.Load())
+
+	s.park("peer-a", token)
+	s.park("peer-a", token)
+	assert.EqualValues(t, 1, s.parked.Load(), "a session is parked once")
+
+	_, _, resumed, err := s.open("peer-a", token)
+	require.NoError(t, err)
+	assert.True(t, resumed)
+	assert.Zero(t, s.parked.Load(), "a resumed session isn't parked")
+	assert.False(t, s.hold(msg))
+}
+
+func TestSessions_Shards(t *testing.T) {
+	s := newSessions()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(peerID string) {
+			defer wg.Done()
+			token, _, _, err := s.open(peerID, "")
+			assert.NoError(t, err)
+			s.park(peerID, token)
+			assert.True(t, s.hold(&proto.EncryptedMessage{RemoteKey: peerID}))
+		}(fmt.Sprintf("peer-%d", i))
+	}
+	wg.Wait()
+
+	assert.EqualValues(t, 100, s.parked.Load())
+	var sessions int
+	for i := range s.shards {
+		sessions += len(s.shards[i].sessions)
+	}
+	assert.Equal(t, 100, sessions)
+}
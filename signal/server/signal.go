@@ -52,6 +52,7 @@ var (
 // Server an instance of a Signal server
 type Server struct {
 	registry *peer.Registry
+	sessions *sessions
 	proto.UnimplementedSignalExchangeServer
 	dispatcher *dispatcher.Dispatcher
 	metrics    *metrics.AppMetrics
@@ -83,6 +84,7 @@ func NewServer(ctx context.Context, meter metric.Meter) (*Server, error) {
 	s := &Server{
 		dispatcher:    d,
 		registry:      peer.NewRegistry(appMetrics),
+		sessions:      newSessions(),
 		metrics:       appMetrics,
 		successHeader: metadata.Pairs(proto.HeaderRegistered, "1"),
 		sendTimeout:   sTimeout,
@@ -100,10 +102,16 @@ func (s *Server) Send(ctx context.Context, msg *proto.EncryptedMessage) (*proto.
 		return &proto.EncryptedMessage{}, nil
 	}
 
+	if s.sessions.hold(msg) {
+		log.Tracef("holding a message from peer [%s] to disconnected peer [%s] until it resumes its session", msg.Key, msg.RemoteKey)
+		return &proto.EncryptedMessage{}, nil
+	}
+
 	return s.dispatcher.SendMessage(ctx, msg)
 }
 
-// ConnectStream connects to the exchange stream
+// ConnectStream connects to the exchange stream. A peer reconnecting with the token of its previous session resumes
+// it and receives the messages held for it while it was disconnected.
 func (s *Server) ConnectStream(stream proto.SignalExchange_ConnectStreamServer) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	p, err := s.RegisterPeer(stream, cancel)
@@ -111,10 +119,29 @@ func (s *Server) ConnectStream(stream proto.SignalExchange_ConnectStreamServer)
 		return err
 	}
 
-	defer s.DeregisterPeer(p)
+	var resumeToken string
+	if values := metadata.ValueFromIncomingContext(stream.Context(), proto.HeaderResumeToken); len(values) > 0 {
+		resumeToken = values[0]
+	}
+	token, held, resumed, err := s.sessions.open(p.Id, resumeToken)
+	if err != nil {
+		s.DeregisterPeer(p)
+		log.Errorf("error while opening the session of peer [%s] %v", p.Id, err)
+		return status.Errorf(codes.Internal, "error while opening the session")
+	}
+
+	defer func() {
+		s.DeregisterPeer(p)
+		s.sessions.park(p.Id, token)
+	}()
+
+	header := metadata.Join(s.successHeader, metadata.Pairs(proto.HeaderResumeToken, token))
+	if resumed {
+		header.Set(proto.HeaderResumed, "1")
+	}
 
 	// needed to confirm that the peer has been registered so that the client can proceed
-	err = stream.SendHeader(s.successHeader)
+	err = stream.SendHeader(header)
 	if err != nil {
 		s.metrics.RegistrationFailures.Add(stream.Context(), 1, metric.WithAttributes(attribute.String(labelError, labelErrorFailedHeader)))
 		return err
@@ -122,6 +149,16 @@ func (s *Server) ConnectStream(stream proto.SignalExchange_ConnectStreamServer)
 
 	log.Debugf("peer connected [%s] [streamID %d] ", p.Id, p.StreamID)
 
+	if resumed {
+		log.Debugf("peer resumed its session [%s], delivering %d held messages", p.Id, len(held))
+	}
+	for _, msg := range held {
+		if err := stream.Send(msg); err != nil {
+			log.Debugf("failed delivering a held message to peer [%s]: %v", p.Id, err)
+			return err
+		}
+	}
+
 	select {
 	case <-stream.Context().Done():
 		log.Debugf("peer stream closing [%s] [streamID %d] ", p.Id, p.StreamID)
@@ -165,6 +202,11 @@ func (s *Server) forwardMessageToPeer(ctx context.Context, msg *proto.EncryptedM
 	// lookup the target peer where the message is going to
 	dstPeer, found := s.registry.Get(msg.RemoteKey)
 
+	if !found && s.sessions.hold(msg) {
+		log.Tracef("holding a message from peer [%s] to disconnected peer [%s] until it resumes its session", msg.Key, msg.RemoteKey)
+		return
+	}
+
 	if !found {
 		s.metrics.GetRegistrationDelay.Record(ctx, float64(time.Since(getRegistrationStart).Nanoseconds())/1e6, metric.WithAttributes(attribute.String(labelType, labelTypeStream), attribute.String(labelRegistrationStatus, labelRegistrationNotFound)))
 		s.metrics.MessageForwardFailures.Add(ctx, 1, metric.WithAttributes(attribute.String(labelType, labelTypeNotConnected)))
@@ -28,15 +28,20 @@ var (
 	}
 )
 
-// InitLog parses and sets log-level input
+// InitLog parses and sets log-level input, the format is taken from the NB_LOG_FORMAT environment variable
 func InitLog(logLevel string, logs ...string) error {
+	return InitLogWithFormat(logLevel, os.Getenv("NB_LOG_FORMAT"), logs...)
+}
+
+// InitLogWithFormat parses and sets log-level input and the log format, text or json. Syslog output always uses
+// the syslog format.
+func InitLogWithFormat(logLevel, logFmt string, logs ...string) error {
 	level, err := log.ParseLevel(logLevel)
 	if err != nil {
 		log.Errorf("Failed parsing log-level %s: %s", logLevel, err)
 		return err
 	}
 	var writers []io.Writer
-	logFmt := os.Getenv("NB_LOG_FORMAT")
 
 	for _, logPath := range logs {
 		switch logPath {